@@ -7,7 +7,10 @@
 //	activity.Record(ctx context.Context, msg string, a ...interface{})
 // to record entries, and
 //  activity.Warnf(ctx context.Context, msg string, a ...interface{})
-// to warn about errors. The context contains the Journal ID.
+// to warn about errors. The context contains the Journal ID. Verbose
+// entries that are only useful when debugging a plugin should use
+//  activity.Debugf(ctx context.Context, msg string, a ...interface{})
+// These are only recorded if the plugin's level was raised via SetPluginLevel.
 package activity
 
 import (
@@ -26,8 +29,13 @@ import (
 // KeyType is used to type keys for looking up context values.
 type KeyType int
 
-// JournalKey is used to identify a Journal in a context.
-const JournalKey KeyType = iota
+const (
+	// JournalKey is used to identify a Journal in a context.
+	JournalKey KeyType = iota
+	// PluginKey is used to identify the name of the plugin that activity is
+	// recorded on behalf of. See SetPluginLevel.
+	PluginKey
+)
 
 // Enforce a limit on cache size to avoid running out of file descriptors. It'll be rare that we
 // have dozens of processes running simultaneously.
@@ -41,10 +49,12 @@ var journalDir = func() string {
 }()
 var expires = 30 * time.Second
 
-// CloseAll ensures open journals are flushed to disk and closed.
-// Use when the application is shutting down.
+// CloseAll ensures open journals are flushed to disk and closed, and that the
+// journal sinks have sent their remaining entries. Use when the application is
+// shutting down.
 func CloseAll() {
 	recorderCache.Flush()
+	closeSinks()
 }
 
 // Dir gets the directory where journals are stored.
@@ -64,12 +74,29 @@ func GetJournal(ctx context.Context) Journal {
 
 var deadLetterOfficeJournal = Journal{ID: "dead-letter-office"}
 
+// PinJournal keeps the journal in the context open until the context is done.
+// Long-running operations like streams and execs use it so that the recorder
+// cache's limit doesn't close their journal while they're still recording to it.
+func PinJournal(ctx context.Context) {
+	journal, ok := ctx.Value(JournalKey).(Journal)
+	if !ok || journal.ID == "" {
+		return
+	}
+	if _, err := journal.getRecorder(); err != nil {
+		return
+	}
+	recorderCache.Pin(ctx, "", journal.ID)
+}
+
 // Record writes a new entry to the journal identified by the ID at `activity.JournalKey` in
 // the provided context. It also writes to the server logs at the debug level. If no ID
 // is registered, the entry is written to the server logs at the info level. If the
 // ID is an empty string - which can happen when the JournalID header is missing from an
 // API call - it uses the ID 'dead-letter-office'.
 func Record(ctx context.Context, msg string, a ...interface{}) {
+	if !enabled(ctx, log.InfoLevel) {
+		return
+	}
 	journal, ok := ctx.Value(JournalKey).(Journal)
 	if !ok {
 		log.Infof(msg, a...)
@@ -89,6 +116,9 @@ func Record(ctx context.Context, msg string, a ...interface{}) {
 // provided context at WARN level. It also writes to the server logs at the same level. Use Warnf
 // in plugin `Init` methods for issues during setup that will result in degraded behavior.
 func Warnf(ctx context.Context, msg string, a ...interface{}) {
+	if !enabled(ctx, log.WarnLevel) {
+		return
+	}
 	journal, ok := ctx.Value(JournalKey).(Journal)
 	if !ok {
 		log.Warnf(msg, a...)
@@ -104,6 +134,28 @@ func Warnf(ctx context.Context, msg string, a ...interface{}) {
 	journal.Warnf(msg, a...)
 }
 
+// Debugf writes a new entry to the journal identified by the ID at `activity.JournalKey` in the
+// provided context at DEBUG level. It also writes to the server logs at the same level. Debug
+// entries are only recorded for plugins whose level was raised to debug via SetPluginLevel.
+func Debugf(ctx context.Context, msg string, a ...interface{}) {
+	if !enabled(ctx, log.DebugLevel) {
+		return
+	}
+	journal, ok := ctx.Value(JournalKey).(Journal)
+	if !ok {
+		log.Debugf(msg, a...)
+		return
+	}
+
+	if journal.ID == "" {
+		journal = deadLetterOfficeJournal
+	} else {
+		journal.addToHistory()
+	}
+
+	journal.Debugf(msg, a...)
+}
+
 // SubmitMethodInvocation submits a method invocation event to Google Analytics.
 // It then records the invocation to the journal identified by the ID at `activity.JournalKey`
 // in the provided context.
@@ -105,9 +105,12 @@ func (j Journal) getRecorder() (recorder, error) {
 
 		l := &log.Logger{
 			Out:       f,
+			Hooks:     make(log.LevelHooks),
 			Level:     log.TraceLevel,
 			Formatter: &log.TextFormatter{TimestampFormat: time.RFC3339Nano},
 		}
+		// Ship the journal's entries to the journal sinks, if any are configured.
+		l.AddHook(sinkHook{journal: j})
 		recorder.logger = l
 		return recorder, nil
 	})
@@ -127,6 +130,18 @@ func (j Journal) Warnf(msg string, a ...interface{}) {
 	}
 }
 
+// Debugf writes a new entry to the journal at DEBUG level. It also logs to the shell at the
+// same level. See Record for details on how journals are stored.
+func (j Journal) Debugf(msg string, a ...interface{}) {
+	log.Debugf(msg, a...)
+
+	if logger, err := j.getLogger(); err != nil {
+		log.Warnf("Error creating journal's logger %v: %v", j.ID, err)
+	} else {
+		logger.Debugf(msg, a...)
+	}
+}
+
 // Record writes a new entry to the journal. It creates a new file for the journal if needed, then
 // appends the message to that journal. Journals are stored in the user's cache directory under
 // `wash/activity/ID.log`.
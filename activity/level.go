@@ -0,0 +1,73 @@
+package activity
+
+import (
+	"context"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// DefaultLevel is the journal level of plugins that don't have their own level.
+// It includes Record and Warnf entries, but not Debugf entries.
+const DefaultLevel = log.InfoLevel
+
+var pluginLevels = struct {
+	mux    sync.RWMutex
+	levels map[string]log.Level
+}{
+	levels: make(map[string]log.Level),
+}
+
+// SetPluginLevel sets the journal level of the named plugin. Entries recorded on
+// behalf of the plugin that are less severe than the level are dropped. The plugin
+// is identified by the PluginKey context value.
+func SetPluginLevel(plugin string, level log.Level) {
+	pluginLevels.mux.Lock()
+	defer pluginLevels.mux.Unlock()
+	pluginLevels.levels[plugin] = level
+}
+
+// ResetPluginLevel resets the named plugin's journal level to DefaultLevel.
+func ResetPluginLevel(plugin string) {
+	pluginLevels.mux.Lock()
+	defer pluginLevels.mux.Unlock()
+	delete(pluginLevels.levels, plugin)
+}
+
+// PluginLevel returns the named plugin's journal level.
+func PluginLevel(plugin string) log.Level {
+	pluginLevels.mux.RLock()
+	defer pluginLevels.mux.RUnlock()
+	if level, ok := pluginLevels.levels[plugin]; ok {
+		return level
+	}
+	return DefaultLevel
+}
+
+// PluginLevels returns the journal levels of the plugins whose level was set via
+// SetPluginLevel.
+func PluginLevels() map[string]log.Level {
+	pluginLevels.mux.RLock()
+	defer pluginLevels.mux.RUnlock()
+	levels := make(map[string]log.Level, len(pluginLevels.levels))
+	for plugin, level := range pluginLevels.levels {
+		levels[plugin] = level
+	}
+	return levels
+}
+
+// WithPlugin returns a copy of ctx whose entries are recorded on behalf of the
+// named plugin.
+func WithPlugin(ctx context.Context, plugin string) context.Context {
+	return context.WithValue(ctx, PluginKey, plugin)
+}
+
+// enabled returns true if entries at the given level should be recorded for the
+// context's plugin.
+func enabled(ctx context.Context, level log.Level) bool {
+	plugin, ok := ctx.Value(PluginKey).(string)
+	if !ok {
+		return level <= DefaultLevel
+	}
+	return level <= PluginLevel(plugin)
+}
@@ -0,0 +1,47 @@
+package activity
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPluginLevel(t *testing.T) {
+	defer ResetPluginLevel("foo")
+
+	assert.Equal(t, DefaultLevel, PluginLevel("foo"))
+	assert.NotContains(t, PluginLevels(), "foo")
+
+	SetPluginLevel("foo", log.DebugLevel)
+	assert.Equal(t, log.DebugLevel, PluginLevel("foo"))
+	assert.Equal(t, map[string]log.Level{"foo": log.DebugLevel}, PluginLevels())
+	assert.Equal(t, DefaultLevel, PluginLevel("bar"))
+
+	ResetPluginLevel("foo")
+	assert.Equal(t, DefaultLevel, PluginLevel("foo"))
+}
+
+func TestDebugfRespectsPluginLevel(t *testing.T) {
+	defer CloseAll()
+	defer ResetPluginLevel("foo")
+
+	ctx := context.WithValue(context.Background(), JournalKey, Journal{ID: "level"})
+	ctx = WithPlugin(ctx, "foo")
+
+	Debugf(ctx, "dropped debug")
+	SetPluginLevel("foo", log.DebugLevel)
+	Debugf(ctx, "recorded debug")
+	SetPluginLevel("foo", log.WarnLevel)
+	Record(ctx, "dropped info")
+	Warnf(ctx, "recorded warning")
+
+	bits, err := ioutil.ReadFile(filepath.Join(Dir(), "level.log"))
+	if assert.Nil(t, err) {
+		assert.NotContains(t, string(bits), "dropped")
+		assert.Regexp(t, "(?s)recorded debug.*recorded warning", string(bits))
+	}
+}
@@ -0,0 +1,67 @@
+package activity
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Panic describes a panic that Wash recovered from.
+type Panic struct {
+	Time  time.Time `json:"time"`
+	Op    string    `json:"op"`
+	Value string    `json:"value"`
+	Stack string    `json:"stack"`
+}
+
+// maxRecentPanics is the number of panics that are kept for diagnostics.
+const maxRecentPanics = 50
+
+var recentPanics struct {
+	mux  sync.Mutex
+	list []Panic
+}
+
+// RecordPanic records a panic that was recovered from while performing op, and
+// returns an error describing it. Callers return the error so that the panic only
+// fails the current operation instead of crashing the daemon. The panic's written
+// to the server logs and the context's journal, and is kept for diagnostics (see
+// RecentPanics).
+//
+// RecordPanic must be called by the deferred function that recovered the panic so
+// that the recorded stack trace includes the panic's origin.
+func RecordPanic(ctx context.Context, op string, value interface{}) error {
+	p := Panic{
+		Time:  time.Now(),
+		Op:    op,
+		Value: fmt.Sprint(value),
+		Stack: string(debug.Stack()),
+	}
+
+	recentPanics.mux.Lock()
+	recentPanics.list = append(recentPanics.list, p)
+	if len(recentPanics.list) > maxRecentPanics {
+		recentPanics.list = recentPanics.list[len(recentPanics.list)-maxRecentPanics:]
+	}
+	recentPanics.mux.Unlock()
+
+	log.Errorf("%v panicked: %v\n%v", p.Op, p.Value, p.Stack)
+	if _, ok := ctx.Value(JournalKey).(Journal); ok {
+		Warnf(ctx, "%v panicked: %v\n%v", p.Op, p.Value, p.Stack)
+	}
+	return fmt.Errorf("%v panicked: %v", p.Op, p.Value)
+}
+
+// RecentPanics returns the panics that were most recently recorded by RecordPanic,
+// oldest first.
+func RecentPanics() []Panic {
+	recentPanics.mux.Lock()
+	defer recentPanics.mux.Unlock()
+	panics := make([]Panic, len(recentPanics.list))
+	copy(panics, recentPanics.list)
+	return panics
+}
@@ -0,0 +1,39 @@
+package activity
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordPanic(t *testing.T) {
+	err := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = RecordPanic(context.Background(), "List on /foo", r)
+			}
+		}()
+		panic("boom")
+	}()
+	assert.EqualError(t, err, "List on /foo panicked: boom")
+
+	panics := RecentPanics()
+	if assert.NotEmpty(t, panics) {
+		p := panics[len(panics)-1]
+		assert.Equal(t, "List on /foo", p.Op)
+		assert.Equal(t, "boom", p.Value)
+		assert.Contains(t, p.Stack, "TestRecordPanic")
+	}
+}
+
+func TestRecentPanicsIsBounded(t *testing.T) {
+	for i := 0; i < maxRecentPanics+5; i++ {
+		_ = RecordPanic(context.Background(), "op", i)
+	}
+	panics := RecentPanics()
+	assert.Len(t, panics, maxRecentPanics)
+	assert.Equal(t, "5", panics[0].Value)
+	assert.Equal(t, fmt.Sprint(maxRecentPanics+4), panics[len(panics)-1].Value)
+}
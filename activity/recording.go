@@ -0,0 +1,192 @@
+package activity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// RecordingOptions configures session recording. Recordings are stored in the
+// recordings directory of the journal directory.
+type RecordingOptions struct {
+	// Enabled turns on session recording.
+	Enabled bool
+	// MaxAge is how long recordings are retained. Zero retains them forever.
+	MaxAge time.Duration
+	// MaxCount is the maximum number of retained recordings. Zero retains all of
+	// them.
+	MaxCount int
+}
+
+var recordingOpts struct {
+	mux sync.Mutex
+	RecordingOptions
+}
+
+// recordingExt is the extension of asciinema's asciicast files.
+const recordingExt = ".cast"
+
+// The dimensions of the recorded terminal. The remote TTY's size isn't known, so
+// these are the conventional defaults.
+const (
+	recordingWidth  = 80
+	recordingHeight = 24
+)
+
+// SetRecordingOptions configures session recording. It also prunes the existing
+// recordings according to the new retention controls.
+func SetRecordingOptions(opts RecordingOptions) {
+	recordingOpts.mux.Lock()
+	recordingOpts.RecordingOptions = opts
+	recordingOpts.mux.Unlock()
+	pruneRecordings()
+}
+
+// RecordingEnabled returns true if sessions should be recorded.
+func RecordingEnabled() bool {
+	recordingOpts.mux.Lock()
+	defer recordingOpts.mux.Unlock()
+	return recordingOpts.Enabled
+}
+
+// RecordingsDir returns the directory where recordings are stored.
+func RecordingsDir() string {
+	return filepath.Join(Dir(), "recordings")
+}
+
+// Recording records a session's output in asciinema's asciicast v2 format, so it
+// can be reviewed with `asciinema play`. The recording's associated with the
+// context's journal.
+type Recording struct {
+	mux   sync.Mutex
+	file  *os.File
+	start time.Time
+	path  string
+}
+
+type recordingHeader struct {
+	Version   int    `json:"version"`
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+	Timestamp int64  `json:"timestamp"`
+	Command   string `json:"command,omitempty"`
+	Title     string `json:"title,omitempty"`
+}
+
+// NewRecording starts recording a session. The title identifies what the session
+// was started on, and command is the session's command line.
+func NewRecording(ctx context.Context, title string, command string) (*Recording, error) {
+	if err := os.MkdirAll(RecordingsDir(), 0750); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	name := "unknown"
+	if journal, ok := ctx.Value(JournalKey).(Journal); ok && journal.ID != "" {
+		name = journal.ID
+	}
+	path := filepath.Join(RecordingsDir(), fmt.Sprintf("%v-%v%v", name, start.UnixNano(), recordingExt))
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0640)
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := json.Marshal(recordingHeader{
+		Version:   2,
+		Width:     recordingWidth,
+		Height:    recordingHeight,
+		Timestamp: start.Unix(),
+		Command:   command,
+		Title:     title,
+	})
+	if err == nil {
+		_, err = fmt.Fprintf(file, "%s\n", header)
+	}
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	Record(ctx, "Recording session on %v to %v", title, path)
+	pruneRecordings()
+	return &Recording{file: file, start: start, path: path}, nil
+}
+
+// Path returns the recording's path.
+func (r *Recording) Path() string {
+	return r.path
+}
+
+// Output records output that was printed at the given time.
+func (r *Recording) Output(t time.Time, data string) error {
+	elapsed := t.Sub(r.start).Seconds()
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	event, err := json.Marshal([]interface{}{elapsed, "o", data})
+	if err != nil {
+		return err
+	}
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	_, err = fmt.Fprintf(r.file, "%s\n", event)
+	return err
+}
+
+// Close finishes the recording.
+func (r *Recording) Close() error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	return r.file.Close()
+}
+
+// pruneRecordings removes the recordings that are older than the configured
+// MaxAge, then the oldest recordings beyond the configured MaxCount.
+func pruneRecordings() {
+	recordingOpts.mux.Lock()
+	opts := recordingOpts.RecordingOptions
+	recordingOpts.mux.Unlock()
+	if opts.MaxAge <= 0 && opts.MaxCount <= 0 {
+		return
+	}
+
+	files, err := ioutil.ReadDir(RecordingsDir())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Warnf("Unable to prune recordings: %v", err)
+		}
+		return
+	}
+	var recordings []os.FileInfo
+	for _, file := range files {
+		if !file.IsDir() && strings.HasSuffix(file.Name(), recordingExt) {
+			recordings = append(recordings, file)
+		}
+	}
+	// Newest first
+	sort.Slice(recordings, func(i, j int) bool {
+		return recordings[i].ModTime().After(recordings[j].ModTime())
+	})
+
+	for i, recording := range recordings {
+		expired := opts.MaxAge > 0 && time.Since(recording.ModTime()) > opts.MaxAge
+		excess := opts.MaxCount > 0 && i >= opts.MaxCount
+		if !expired && !excess {
+			continue
+		}
+		path := filepath.Join(RecordingsDir(), recording.Name())
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			log.Warnf("Unable to remove recording %v: %v", path, err)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package activity
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecording(t *testing.T) {
+	defer os.RemoveAll(RecordingsDir())
+	ctx := context.WithValue(context.Background(), JournalKey, Journal{ID: "rec"})
+
+	recording, err := NewRecording(ctx, "/docker/containers/foo", "bash -l")
+	require.NoError(t, err)
+	assert.Contains(t, recording.Path(), "rec-")
+	assert.NoError(t, recording.Output(recording.start.Add(1500*time.Millisecond), "hello\r\n"))
+	assert.NoError(t, recording.Close())
+
+	bits, err := ioutil.ReadFile(recording.Path())
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(bits)), "\n")
+	if assert.Len(t, lines, 2) {
+		var header recordingHeader
+		if assert.NoError(t, json.Unmarshal([]byte(lines[0]), &header)) {
+			assert.Equal(t, 2, header.Version)
+			assert.Equal(t, "bash -l", header.Command)
+			assert.Equal(t, "/docker/containers/foo", header.Title)
+		}
+		assert.Equal(t, `[1.5,"o","hello\r\n"]`, lines[1])
+	}
+}
+
+func TestPruneRecordings(t *testing.T) {
+	defer os.RemoveAll(RecordingsDir())
+	defer SetRecordingOptions(RecordingOptions{})
+	ctx := context.Background()
+
+	var paths []string
+	for i := 0; i < 3; i++ {
+		recording, err := NewRecording(ctx, "foo", "sh")
+		require.NoError(t, err)
+		require.NoError(t, recording.Close())
+		// Make the recordings progressively newer
+		mtime := time.Now().Add(time.Duration(i-3) * time.Hour)
+		require.NoError(t, os.Chtimes(recording.Path(), mtime, mtime))
+		paths = append(paths, recording.Path())
+	}
+
+	SetRecordingOptions(RecordingOptions{Enabled: true, MaxAge: 150 * time.Minute})
+	assert.NoFileExists(t, paths[0])
+	assert.FileExists(t, paths[1])
+	assert.FileExists(t, paths[2])
+
+	SetRecordingOptions(RecordingOptions{Enabled: true, MaxCount: 1})
+	assert.NoFileExists(t, paths[1])
+	assert.FileExists(t, paths[2])
+}
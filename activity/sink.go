@@ -0,0 +1,205 @@
+package activity
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Entry is a journal entry that's shipped to the journal sinks. It includes the
+// journal's history info so that a sink's entries can be grouped by command.
+type Entry struct {
+	Time        time.Time `json:"time"`
+	Level       string    `json:"level"`
+	Message     string    `json:"message"`
+	JournalID   string    `json:"journal_id"`
+	Description string    `json:"description,omitempty"`
+	Start       time.Time `json:"start"`
+}
+
+// Sink ships batches of journal entries to a central store, like OpenSearch or
+// S3, so that wash usage across a team can be audited and searched in one place.
+type Sink interface {
+	Send(ctx context.Context, entries []Entry) error
+}
+
+// SinkOptions configures how entries are batched before they're sent to a
+// sink.
+type SinkOptions struct {
+	// BatchSize is the maximum number of entries in a batch. Defaults to
+	// DefaultSinkBatchSize.
+	BatchSize int
+	// FlushInterval is how often a partial batch is sent. Defaults to
+	// DefaultSinkFlushInterval.
+	FlushInterval time.Duration
+}
+
+// The default SinkOptions.
+const (
+	DefaultSinkBatchSize     = 100
+	DefaultSinkFlushInterval = 10 * time.Second
+)
+
+// sinkTimeout bounds how long sending a batch can take.
+var sinkTimeout = 30 * time.Second
+
+// sinkQueueBatches is how many batches of entries can be queued for a sink
+// before new entries are dropped. Entries are dropped instead of blocking so
+// that a slow sink never slows down the operations that are being recorded.
+const sinkQueueBatches = 10
+
+var sinks struct {
+	mux  sync.RWMutex
+	list []*sinkBatcher
+}
+
+type sinkBatcher struct {
+	name    string
+	sink    Sink
+	opts    SinkOptions
+	entries chan Entry
+	stopCh  chan struct{}
+	doneCh  chan struct{}
+	// dropped is the number of entries that were dropped since the last warning
+	dropped int
+	dropMux sync.Mutex
+}
+
+// AddSink starts shipping journal entries to the sink. The name identifies the
+// sink in the server logs. Entries are sent in batches once there's a full
+// batch or the flush interval elapses, and failed batches are logged and
+// discarded. Use CloseAll to send the remaining entries.
+func AddSink(name string, sink Sink, opts SinkOptions) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultSinkBatchSize
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = DefaultSinkFlushInterval
+	}
+	b := &sinkBatcher{
+		name:    name,
+		sink:    sink,
+		opts:    opts,
+		entries: make(chan Entry, opts.BatchSize*sinkQueueBatches),
+		stopCh:  make(chan struct{}),
+		doneCh:  make(chan struct{}),
+	}
+	go b.run()
+
+	sinks.mux.Lock()
+	sinks.list = append(sinks.list, b)
+	sinks.mux.Unlock()
+}
+
+// closeSinks sends the sinks' remaining entries, then stops them.
+func closeSinks() {
+	sinks.mux.Lock()
+	list := sinks.list
+	sinks.list = nil
+	sinks.mux.Unlock()
+
+	for _, b := range list {
+		close(b.stopCh)
+	}
+	for _, b := range list {
+		<-b.doneCh
+	}
+}
+
+// ship queues the entry for each sink.
+func ship(entry Entry) {
+	sinks.mux.RLock()
+	defer sinks.mux.RUnlock()
+	for _, b := range sinks.list {
+		select {
+		case b.entries <- entry:
+		default:
+			b.dropMux.Lock()
+			b.dropped++
+			b.dropMux.Unlock()
+		}
+	}
+}
+
+func (b *sinkBatcher) run() {
+	defer close(b.doneCh)
+	ticker := time.NewTicker(b.opts.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]Entry, 0, b.opts.BatchSize)
+	for {
+		select {
+		case entry := <-b.entries:
+			batch = append(batch, entry)
+			if len(batch) >= b.opts.BatchSize {
+				batch = b.flush(batch)
+			}
+		case <-ticker.C:
+			batch = b.flush(batch)
+		case <-b.stopCh:
+			// Drain the queued entries so that nothing recorded before the
+			// shutdown is lost.
+			for {
+				select {
+				case entry := <-b.entries:
+					batch = append(batch, entry)
+					if len(batch) >= b.opts.BatchSize {
+						batch = b.flush(batch)
+					}
+				default:
+					b.flush(batch)
+					return
+				}
+			}
+		}
+	}
+}
+
+// flush sends the batch and returns an empty batch that reuses its storage.
+func (b *sinkBatcher) flush(batch []Entry) []Entry {
+	b.dropMux.Lock()
+	dropped := b.dropped
+	b.dropped = 0
+	b.dropMux.Unlock()
+	if dropped > 0 {
+		// Journal entries aren't shipped from the server logs, so logging here
+		// can't recurse.
+		log.Warnf("Journal sink %v is falling behind, dropped %v entries", b.name, dropped)
+	}
+	if len(batch) == 0 {
+		return batch
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sinkTimeout)
+	defer cancel()
+	if err := b.sink.Send(ctx, batch); err != nil {
+		log.Warnf("Failed to send %v journal entries to sink %v: %v", len(batch), b.name, err)
+	}
+	return batch[:0]
+}
+
+// sinkHook is a logrus hook that ships a journal's entries to the journal
+// sinks and the subscribers.
+type sinkHook struct {
+	journal Journal
+}
+
+func (h sinkHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+func (h sinkHook) Fire(e *log.Entry) error {
+	entry := Entry{
+		Time:        e.Time,
+		Level:       e.Level.String(),
+		Message:     e.Message,
+		JournalID:   h.journal.ID,
+		Description: h.journal.Description,
+		Start:       h.journal.start,
+	}
+	ship(entry)
+	publish(entry)
+	return nil
+}
@@ -0,0 +1,80 @@
+package activity
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockSink struct {
+	mux     sync.Mutex
+	batches [][]Entry
+}
+
+func (s *mockSink) Send(ctx context.Context, entries []Entry) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	// The batch's storage is reused, so copy it.
+	s.batches = append(s.batches, append([]Entry(nil), entries...))
+	return nil
+}
+
+func (s *mockSink) sent() [][]Entry {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.batches
+}
+
+func TestSinkShipsJournalEntriesInBatches(t *testing.T) {
+	history = initHistory()
+	defer func() {
+		history = initHistory()
+		CloseAll()
+	}()
+
+	sink := &mockSink{}
+	AddSink("mock", sink, SinkOptions{BatchSize: 2, FlushInterval: time.Hour})
+
+	journal := NewJournal("sinktest", "wash ls")
+	ctx := context.WithValue(context.Background(), JournalKey, journal)
+	for i := 0; i < 3; i++ {
+		Record(ctx, "entry %v", i)
+	}
+	Warnf(ctx, "uh oh")
+
+	// The first batch is sent once it's full, the rest when the sinks are closed.
+	CloseAll()
+	batches := sink.sent()
+	require.Len(t, batches, 2)
+	assert.Len(t, batches[0], 2)
+	assert.Len(t, batches[1], 2)
+
+	var messages []string
+	for _, batch := range batches {
+		for _, entry := range batch {
+			messages = append(messages, fmt.Sprintf("%v: %v", entry.Level, entry.Message))
+			assert.Equal(t, "sinktest", entry.JournalID)
+			assert.Equal(t, "wash ls", entry.Description)
+			assert.Equal(t, journal.Start(), entry.Start)
+		}
+	}
+	assert.Equal(t, []string{"info: entry 0", "info: entry 1", "info: entry 2", "warning: uh oh"}, messages)
+}
+
+func TestSinkFlushesPartialBatches(t *testing.T) {
+	defer CloseAll()
+
+	sink := &mockSink{}
+	AddSink("mock", sink, SinkOptions{BatchSize: 100, FlushInterval: 10 * time.Millisecond})
+
+	ctx := context.WithValue(context.Background(), JournalKey, Journal{ID: "sinkflush"})
+	Record(ctx, "hello")
+	assert.Eventually(t, func() bool {
+		return len(sink.sent()) == 1
+	}, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,101 @@
+// Package sinks implements the journal sinks that ship wash's activity to a
+// central store, so that team-wide wash usage can be audited and searched. Each
+// sink is configured by an entry in the journal_sinks config.
+package sinks
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/puppetlabs/wash/activity"
+)
+
+// Config is a journal sink's config.
+type Config struct {
+	// Type is the sink's type. It's one of opensearch, s3 or http.
+	Type string
+	// Name identifies the sink in the server logs. Defaults to Type.
+	Name string
+
+	// URL is the OpenSearch cluster's or HTTP endpoint's URL.
+	URL string
+	// Index is the OpenSearch index that entries are added to.
+	Index    string
+	Username string
+	Password string
+	// Headers are added to the HTTP sink's requests, e.g. an Authorization
+	// header.
+	Headers map[string]string
+
+	// Bucket is the S3 bucket that batches are uploaded to. Each batch is an
+	// object under Prefix.
+	Bucket  string
+	Prefix  string
+	Region  string
+	Profile string
+
+	BatchSize     int           `mapstructure:"batch_size"`
+	FlushInterval time.Duration `mapstructure:"flush_interval"`
+}
+
+// DefaultIndex is the OpenSearch index that's used if none is configured.
+const DefaultIndex = "wash-activity"
+
+// New returns the configured sink.
+func New(config Config) (activity.Sink, error) {
+	switch config.Type {
+	case "opensearch":
+		u, err := parseURL(config)
+		if err != nil {
+			return nil, err
+		}
+		index := config.Index
+		if index == "" {
+			index = DefaultIndex
+		}
+		return newOpenSearchSink(u, index, config.Username, config.Password), nil
+	case "s3":
+		if config.Bucket == "" {
+			return nil, fmt.Errorf("journal_sinks: the %v sink must specify a bucket", config.SinkName())
+		}
+		return newS3Sink(config.Bucket, config.Prefix, config.Region, config.Profile)
+	case "http":
+		u, err := parseURL(config)
+		if err != nil {
+			return nil, err
+		}
+		return newHTTPSink(u, config.Username, config.Password, config.Headers), nil
+	case "":
+		return nil, fmt.Errorf("journal_sinks: every sink must specify a type of opensearch, s3 or http")
+	default:
+		return nil, fmt.Errorf("journal_sinks: unknown sink type %v, must be one of opensearch, s3 or http", config.Type)
+	}
+}
+
+// SinkName returns the name that identifies the sink.
+func (c Config) SinkName() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return c.Type
+}
+
+// Options returns the sink's batching options.
+func (c Config) Options() activity.SinkOptions {
+	return activity.SinkOptions{
+		BatchSize:     c.BatchSize,
+		FlushInterval: c.FlushInterval,
+	}
+}
+
+func parseURL(config Config) (*url.URL, error) {
+	if config.URL == "" {
+		return nil, fmt.Errorf("journal_sinks: the %v sink must specify a url", config.SinkName())
+	}
+	u, err := url.Parse(config.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("journal_sinks: the %v sink's url %v must be an http or https URL", config.SinkName(), config.URL)
+	}
+	return u, nil
+}
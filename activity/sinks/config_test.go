@@ -0,0 +1,37 @@
+package sinks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewErrors(t *testing.T) {
+	_, err := New(Config{})
+	assert.EqualError(t, err, "journal_sinks: every sink must specify a type of opensearch, s3 or http")
+
+	_, err = New(Config{Type: "kafka"})
+	assert.EqualError(t, err, "journal_sinks: unknown sink type kafka, must be one of opensearch, s3 or http")
+
+	_, err = New(Config{Type: "opensearch"})
+	assert.EqualError(t, err, "journal_sinks: the opensearch sink must specify a url")
+
+	_, err = New(Config{Type: "http", Name: "audit", URL: "collector:8080"})
+	assert.EqualError(t, err, "journal_sinks: the audit sink's url collector:8080 must be an http or https URL")
+
+	_, err = New(Config{Type: "s3"})
+	assert.EqualError(t, err, "journal_sinks: the s3 sink must specify a bucket")
+}
+
+func TestNew(t *testing.T) {
+	sink, err := New(Config{Type: "opensearch", URL: "https://search:9200/prefix"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "https://search:9200/prefix/_bulk", sink.(*openSearchSink).bulkURL)
+		assert.Equal(t, DefaultIndex, sink.(*openSearchSink).index)
+	}
+
+	sink, err = New(Config{Type: "http", URL: "http://collector:8080/ingest"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "http://collector:8080/ingest", sink.(*httpSink).url.String())
+	}
+}
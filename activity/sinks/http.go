@@ -0,0 +1,83 @@
+package sinks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// maxErrorBodySize bounds how much of an error response's body is included in
+// the returned error.
+const maxErrorBodySize = 512
+
+// httpSink POSTs each batch to an HTTP endpoint as a JSON array of entries.
+type httpSink struct {
+	client   *http.Client
+	url      *url.URL
+	username string
+	password string
+	headers  map[string]string
+}
+
+func newHTTPSink(u *url.URL, username, password string, headers map[string]string) *httpSink {
+	return &httpSink{
+		client:   plugin.HTTPClient(),
+		url:      u,
+		username: username,
+		password: password,
+		headers:  headers,
+	}
+}
+
+func (s *httpSink) Send(ctx context.Context, entries []activity.Entry) error {
+	body, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	_, err = post(ctx, s.client, s.url.String(), "application/json", bytes.NewReader(body), s.username, s.password, s.headers)
+	return err
+}
+
+// post sends a POST request and returns the response's body. Non-2xx responses
+// are returned as errors.
+func post(ctx context.Context, client *http.Client, url string, contentType string, body io.Reader, username, password string, headers map[string]string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodPost, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", contentType)
+	if username != "" {
+		req.SetBasicAuth(username, password)
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg := strings.TrimSpace(string(respBody))
+		if len(msg) > maxErrorBodySize {
+			msg = msg[:maxErrorBodySize] + "..."
+		}
+		return nil, fmt.Errorf("%v: %v", resp.Status, msg)
+	}
+	return respBody, nil
+}
@@ -0,0 +1,36 @@
+package sinks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/puppetlabs/wash/activity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPSinkSend(t *testing.T) {
+	var entries []activity.Entry
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&entries))
+		if len(entries) > 1 {
+			http.Error(w, "too many entries", http.StatusRequestEntityTooLarge)
+		}
+	}))
+	defer server.Close()
+
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	sink := newHTTPSink(u, "", "", map[string]string{"Authorization": "Bearer token"})
+	err = sink.Send(context.Background(), []activity.Entry{{Message: "hello", JournalID: "1"}})
+	require.NoError(t, err)
+	assert.Equal(t, []activity.Entry{{Message: "hello", JournalID: "1"}}, entries)
+
+	err = sink.Send(context.Background(), make([]activity.Entry, 2))
+	assert.EqualError(t, err, "413 Request Entity Too Large: too many entries")
+}
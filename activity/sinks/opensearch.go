@@ -0,0 +1,98 @@
+package sinks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// openSearchSink indexes each batch in an OpenSearch (or Elasticsearch) index
+// via the bulk API.
+type openSearchSink struct {
+	client   *http.Client
+	bulkURL  string
+	index    string
+	username string
+	password string
+}
+
+func newOpenSearchSink(u *url.URL, index, username, password string) *openSearchSink {
+	bulkURL := *u
+	bulkURL.Path = path.Join("/", u.Path, "_bulk")
+	return &openSearchSink{
+		client:   plugin.HTTPClient(),
+		bulkURL:  bulkURL.String(),
+		index:    index,
+		username: username,
+		password: password,
+	}
+}
+
+type bulkAction struct {
+	Index struct {
+		Index string `json:"_index"`
+	} `json:"index"`
+}
+
+type bulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		Status int `json:"status"`
+		Error  struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+	} `json:"items"`
+}
+
+func (s *openSearchSink) Send(ctx context.Context, entries []activity.Entry) error {
+	var action bulkAction
+	action.Index.Index = s.index
+
+	// The bulk API's body is newline-delimited JSON, with each document
+	// preceded by its action.
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for _, entry := range entries {
+		if err := enc.Encode(action); err != nil {
+			return err
+		}
+		if err := enc.Encode(entry); err != nil {
+			return err
+		}
+	}
+
+	respBody, err := post(ctx, s.client, s.bulkURL, "application/x-ndjson", &body, s.username, s.password, nil)
+	if err != nil {
+		return err
+	}
+	var resp bulkResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return fmt.Errorf("unexpected bulk API response: %v", err)
+	}
+	if !resp.Errors {
+		return nil
+	}
+	// The request succeeded, but some of the entries weren't indexed. Report
+	// the first failure since they typically all fail for the same reason.
+	failed := 0
+	var reason string
+	for _, item := range resp.Items {
+		for _, result := range item {
+			if result.Status >= 300 {
+				failed++
+				if reason == "" {
+					reason = fmt.Sprintf("%v: %v", result.Error.Type, result.Error.Reason)
+				}
+			}
+		}
+	}
+	return fmt.Errorf("failed to index %v of %v entries: %v", failed, len(entries), reason)
+}
@@ -0,0 +1,69 @@
+package sinks
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/puppetlabs/wash/activity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenSearchSinkSend(t *testing.T) {
+	var lines []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/_bulk", r.URL.Path)
+		assert.Equal(t, "application/x-ndjson", r.Header.Get("Content-Type"))
+		username, password, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "wash", username)
+		assert.Equal(t, "secret", password)
+
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			var line map[string]interface{}
+			assert.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+			lines = append(lines, line)
+		}
+		_, err := w.Write([]byte(`{"errors":false,"items":[]}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	sink := newOpenSearchSink(u, "audit", "wash", "secret")
+	err = sink.Send(context.Background(), []activity.Entry{
+		{Time: time.Now(), Level: "info", Message: "Listing 2 VMs", JournalID: "1"},
+		{Time: time.Now(), Level: "warning", Message: "uh oh", JournalID: "1"},
+	})
+	require.NoError(t, err)
+
+	if assert.Len(t, lines, 4) {
+		assert.Equal(t, map[string]interface{}{"index": map[string]interface{}{"_index": "audit"}}, lines[0])
+		assert.Equal(t, "Listing 2 VMs", lines[1]["message"])
+		assert.Equal(t, "uh oh", lines[3]["message"])
+	}
+}
+
+func TestOpenSearchSinkSendReportsFailedItems(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(`{"errors":true,"items":[
+			{"index":{"status":201}},
+			{"index":{"status":400,"error":{"type":"mapper_parsing_exception","reason":"failed to parse field [time]"}}}
+		]}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	err = newOpenSearchSink(u, "audit", "", "").Send(context.Background(), make([]activity.Entry, 2))
+	assert.EqualError(t, err, "failed to index 1 of 2 entries: mapper_parsing_exception: failed to parse field [time]")
+}
@@ -0,0 +1,80 @@
+package sinks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// s3Sink uploads each batch to an S3 bucket as a newline-delimited JSON object.
+// Objects are keyed by date so that they can be queried with tools like Athena.
+type s3Sink struct {
+	client   *s3.S3
+	bucket   string
+	prefix   string
+	hostname string
+}
+
+func newS3Sink(bucket, prefix, region, profile string) (*s3Sink, error) {
+	// SharedConfigEnable loads the profile from the ~/.aws/credentials and
+	// ~/.aws/config files, like the AWS plugin.
+	opts := session.Options{
+		Profile:           profile,
+		SharedConfigState: session.SharedConfigEnable,
+		Config:            aws.Config{HTTPClient: plugin.HTTPClient()},
+	}
+	if region != "" {
+		opts.Config.Region = aws.String(region)
+	}
+	sess, err := session.NewSessionWithOptions(opts)
+	if err != nil {
+		return nil, fmt.Errorf("journal_sinks: failed to create the AWS session for the s3 sink: %v", err)
+	}
+
+	// The hostname keeps different machines' objects from colliding.
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return &s3Sink{
+		client:   s3.New(sess),
+		bucket:   bucket,
+		prefix:   prefix,
+		hostname: hostname,
+	}, nil
+}
+
+func (s *s3Sink) Send(ctx context.Context, entries []activity.Entry) error {
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for _, entry := range entries {
+		if err := enc.Encode(entry); err != nil {
+			return err
+		}
+	}
+
+	_, err := s.client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(s.key(time.Now())),
+		Body:        bytes.NewReader(body.Bytes()),
+		ContentType: aws.String("application/x-ndjson"),
+	})
+	return err
+}
+
+// key returns the key of the object that a batch sent at t is uploaded to.
+func (s *s3Sink) key(t time.Time) string {
+	t = t.UTC()
+	name := fmt.Sprintf("%v-%v-%v.ndjson", s.hostname, os.Getpid(), t.UnixNano())
+	return s.prefix + path.Join(t.Format("2006/01/02"), name)
+}
@@ -0,0 +1,53 @@
+package activity
+
+import "sync"
+
+// subscriberBuffer is how many entries can be queued for a subscriber before
+// new entries are dropped. Like the sinks, subscribers never slow down the
+// operations that are being recorded.
+const subscriberBuffer = 256
+
+var subscribers struct {
+	mux  sync.RWMutex
+	next int
+	list map[int]chan Entry
+}
+
+// Subscribe returns a channel that receives the journal entries that are
+// recorded from now on, across all journals, and a function that ends the
+// subscription and closes the channel. Entries are dropped if the subscriber
+// falls too far behind.
+func Subscribe() (<-chan Entry, func()) {
+	ch := make(chan Entry, subscriberBuffer)
+
+	subscribers.mux.Lock()
+	if subscribers.list == nil {
+		subscribers.list = make(map[int]chan Entry)
+	}
+	id := subscribers.next
+	subscribers.next++
+	subscribers.list[id] = ch
+	subscribers.mux.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			subscribers.mux.Lock()
+			delete(subscribers.list, id)
+			subscribers.mux.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// publish sends the entry to each subscriber.
+func publish(entry Entry) {
+	subscribers.mux.RLock()
+	defer subscribers.mux.RUnlock()
+	for _, ch := range subscribers.list {
+		select {
+		case ch <- entry:
+		default:
+		}
+	}
+}
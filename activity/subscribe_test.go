@@ -0,0 +1,54 @@
+package activity
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscribeReceivesRecordedEntries(t *testing.T) {
+	history = initHistory()
+	defer func() {
+		history = initHistory()
+		CloseAll()
+	}()
+
+	entries, unsubscribe := Subscribe()
+	journal := NewJournal("subscribetest", "wash ls")
+	ctx := context.WithValue(context.Background(), JournalKey, journal)
+	Record(ctx, "hello")
+	Warnf(ctx, "uh oh")
+
+	for _, expected := range []string{"info: hello", "warning: uh oh"} {
+		select {
+		case entry := <-entries:
+			assert.Equal(t, expected, entry.Level+": "+entry.Message)
+			assert.Equal(t, "subscribetest", entry.JournalID)
+			assert.Equal(t, "wash ls", entry.Description)
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out waiting for %q", expected)
+		}
+	}
+
+	unsubscribe()
+	// Unsubscribing closes the channel and can be repeated.
+	_, ok := <-entries
+	require.False(t, ok)
+	unsubscribe()
+	Record(ctx, "after")
+}
+
+func TestSubscribeDropsEntriesWhenFull(t *testing.T) {
+	defer CloseAll()
+
+	entries, unsubscribe := Subscribe()
+	defer unsubscribe()
+	ctx := context.WithValue(context.Background(), JournalKey, Journal{ID: "subscribefull"})
+	for i := 0; i < subscriberBuffer+10; i++ {
+		Record(ctx, "entry %v", i)
+	}
+	assert.Len(t, entries, subscriberBuffer)
+}
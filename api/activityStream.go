@@ -0,0 +1,105 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/puppetlabs/wash/activity"
+	apitypes "github.com/puppetlabs/wash/api/types"
+	log "github.com/sirupsen/logrus"
+)
+
+// swagger:parameters streamActivity
+//nolint:deadcode,unused
+type activityStreamParams struct {
+	// only stream the entries of the journal with this ID
+	//
+	// in: query
+	Journal string
+	// only stream entries that are at least as severe as this level,
+	// e.g. "warn"
+	//
+	// in: query
+	Level string
+}
+
+// activityKeepAlive is how often a comment is sent while no entries are being
+// recorded, so that proxies don't close an idle stream.
+var activityKeepAlive = 30 * time.Second
+
+// swagger:route GET /activity/stream activity streamActivity
+//
+// Stream journal entries
+//
+// Streams the entries that are recorded in the journals from now on as
+// server-sent events until the client disconnects. Each event's type is
+// "entry", and its data is a JournalEntry. Entries are dropped if the client
+// falls too far behind.
+//
+//     Produces:
+//     - application/json
+//     - text/event-stream
+//
+//     Schemes: http
+//
+//     Responses:
+//       200: JournalEntry
+//       400: errorResp
+//       500: errorResp
+var activityStreamHandler = handler{logOnly: true, fn: func(w http.ResponseWriter, r *http.Request) *errorResponse {
+	journalID := r.URL.Query().Get("journal")
+	level := log.TraceLevel
+	if lvl := r.URL.Query().Get("level"); lvl != "" {
+		var err error
+		if level, err = log.ParseLevel(lvl); err != nil {
+			return badRequestResponse(err.Error())
+		}
+	}
+
+	f, ok := w.(flushableWriter)
+	if !ok {
+		return unknownErrorResponse(fmt.Errorf("Cannot stream activity, response handler does not support flushing"))
+	}
+
+	entries, unsubscribe := activity.Subscribe()
+	defer unsubscribe()
+
+	// Do an initial flush to send the header.
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	f.Flush()
+
+	sw := &streamableResponseWriter{f}
+	keepAlive := time.NewTicker(activityKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		var err error
+		select {
+		case <-r.Context().Done():
+			return nil
+		case <-keepAlive.C:
+			_, err = fmt.Fprint(sw, ": keep-alive\n\n")
+		case entry := <-entries:
+			if journalID != "" && entry.JournalID != journalID {
+				continue
+			}
+			if entryLevel, parseErr := log.ParseLevel(entry.Level); parseErr == nil && entryLevel > level {
+				continue
+			}
+			data, marshalErr := json.Marshal(apitypes.JournalEntry(entry))
+			if marshalErr != nil {
+				log.Warnf("API: Could not marshal journal entry %v: %v", entry, marshalErr)
+				continue
+			}
+			_, err = fmt.Fprintf(sw, "event: entry\ndata: %s\n\n", data)
+		}
+		if err != nil {
+			// Common when the caller closes the connection.
+			log.Printf("API: Streaming activity errored: %v", err)
+			return nil
+		}
+	}
+}}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/puppetlabs/wash/activity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActivityStreamHandler(t *testing.T) {
+	dir, err := ioutil.TempDir("", "activityStream")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	oldDir := activity.Dir()
+	activity.SetDir(dir)
+	defer activity.SetDir(oldDir)
+	defer activity.CloseAll()
+
+	server := httptest.NewServer(activityStreamHandler)
+	defer server.Close()
+	resp, err := http.Get(server.URL + "/activity/stream?journal=streamed&level=warn")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	streamed := context.WithValue(context.Background(), activity.JournalKey, activity.NewJournal("streamed", "wash ls"))
+	other := context.WithValue(context.Background(), activity.JournalKey, activity.NewJournal("other", "wash find"))
+	activity.Record(streamed, "too verbose")
+	activity.Warnf(other, "other journal")
+	activity.Warnf(streamed, "uh oh")
+
+	scanner := bufio.NewScanner(resp.Body)
+	require.True(t, scanner.Scan())
+	assert.Equal(t, "event: entry", scanner.Text())
+	require.True(t, scanner.Scan())
+	data := scanner.Text()
+	assert.True(t, strings.HasPrefix(data, "data: {"), data)
+	assert.Contains(t, data, `"level":"warning","message":"uh oh","journal_id":"streamed","description":"wash ls"`)
+}
+
+func TestActivityStreamHandler_InvalidLevel(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/activity/stream?level=loud", nil)
+	w := httptest.NewRecorder()
+	activityStreamHandler.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
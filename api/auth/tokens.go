@@ -0,0 +1,214 @@
+// Package auth implements the bearer tokens that authenticate clients of the
+// API's TCP listener. Tokens are stored in a JSON file. Only their hashes are
+// stored, so a token's secret is only known when it's created.
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// AllPlugins is the plugin name that grants access to every plugin.
+const AllPlugins = "*"
+
+const secretPrefix = "wash_"
+
+// Token is a stored bearer token. Read and Write are the names of the plugins
+// that the token can read from and write to. Write access implies read access.
+type Token struct {
+	Name    string    `json:"name"`
+	Hash    string    `json:"hash"`
+	Read    []string  `json:"read,omitempty"`
+	Write   []string  `json:"write,omitempty"`
+	Created time.Time `json:"created"`
+}
+
+// Allows returns true if the token can access the plugin. Write access is
+// required if write is true.
+func (t Token) Allows(plugin string, write bool) bool {
+	if contains(t.Write, plugin) {
+		return true
+	}
+	return !write && contains(t.Read, plugin)
+}
+
+func contains(plugins []string, plugin string) bool {
+	for _, p := range plugins {
+		if p == AllPlugins || p == plugin {
+			return true
+		}
+	}
+	return false
+}
+
+// Store is a file of tokens. The daemon's store rereads the file when it
+// changes, so tokens can be created and revoked while it's running.
+type Store struct {
+	path    string
+	mux     sync.Mutex
+	modTime time.Time
+	size    int64
+	tokens  []Token
+}
+
+// NewStore returns the store of the tokens in the file at path. The file's
+// created when the first token is.
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// Path returns the path of the store's file.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// load rereads the file if it's changed. It must be called with s.mux held.
+func (s *Store) load() error {
+	info, err := os.Stat(s.path)
+	if os.IsNotExist(err) {
+		s.tokens, s.modTime, s.size = nil, time.Time{}, 0
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if s.tokens != nil && info.ModTime().Equal(s.modTime) && info.Size() == s.size {
+		return nil
+	}
+
+	content, err := ioutil.ReadFile(s.path)
+	if err != nil {
+		return err
+	}
+	tokens := []Token{}
+	if err := json.Unmarshal(content, &tokens); err != nil {
+		return fmt.Errorf("could not decode the tokens in %v: %v", s.path, err)
+	}
+	s.tokens, s.modTime, s.size = tokens, info.ModTime(), info.Size()
+	return nil
+}
+
+// save writes the tokens. It must be called with s.mux held.
+func (s *Store) save(tokens []Token) error {
+	content, err := json.MarshalIndent(tokens, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0750); err != nil {
+		return err
+	}
+	// Write to a temporary file and rename it so that the daemon never reads
+	// a partially written file.
+	tmp := s.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, content, 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return err
+	}
+	s.tokens = nil
+	return nil
+}
+
+// List returns the tokens, sorted by name.
+func (s *Store) List() ([]Token, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	tokens := append([]Token{}, s.tokens...)
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].Name < tokens[j].Name
+	})
+	return tokens, nil
+}
+
+// Create creates a token with the given access, and returns its secret.
+func (s *Store) Create(name string, read []string, write []string) (string, error) {
+	if name == "" {
+		return "", fmt.Errorf("the token's name can't be empty")
+	}
+	if len(read) == 0 && len(write) == 0 {
+		return "", fmt.Errorf("the token must be able to read or write at least one plugin")
+	}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if err := s.load(); err != nil {
+		return "", err
+	}
+	for _, t := range s.tokens {
+		if t.Name == name {
+			return "", fmt.Errorf("the %v token already exists", name)
+		}
+	}
+
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", fmt.Errorf("could not generate the token: %v", err)
+	}
+	secret := secretPrefix + base64.RawURLEncoding.EncodeToString(bytes)
+	token := Token{
+		Name:    name,
+		Hash:    hash(secret),
+		Read:    read,
+		Write:   write,
+		Created: time.Now().UTC(),
+	}
+	if err := s.save(append(s.tokens[:len(s.tokens):len(s.tokens)], token)); err != nil {
+		return "", fmt.Errorf("could not save the token: %v", err)
+	}
+	return secret, nil
+}
+
+// Revoke deletes the named token.
+func (s *Store) Revoke(name string) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if err := s.load(); err != nil {
+		return err
+	}
+	tokens := []Token{}
+	for _, t := range s.tokens {
+		if t.Name != name {
+			tokens = append(tokens, t)
+		}
+	}
+	if len(tokens) == len(s.tokens) {
+		return fmt.Errorf("the %v token does not exist", name)
+	}
+	return s.save(tokens)
+}
+
+// Authenticate returns the token whose secret is secret. It returns false if
+// there isn't one.
+func (s *Store) Authenticate(secret string) (Token, bool, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if err := s.load(); err != nil {
+		return Token{}, false, err
+	}
+	h := []byte(hash(secret))
+	for _, t := range s.tokens {
+		if subtle.ConstantTimeCompare(h, []byte(t.Hash)) == 1 {
+			return t, true, nil
+		}
+	}
+	return Token{}, false, nil
+}
+
+func hash(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
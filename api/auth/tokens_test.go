@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStore(t *testing.T) (*Store, func()) {
+	dir, err := ioutil.TempDir("", "tokens")
+	require.NoError(t, err)
+	return NewStore(filepath.Join(dir, "wash", "tokens.json")), func() { os.RemoveAll(dir) }
+}
+
+func TestCreateAndAuthenticate(t *testing.T) {
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	secret, err := store.Create("ci", []string{"aws"}, []string{"docker"})
+	require.NoError(t, err)
+	assert.Regexp(t, "^wash_", secret)
+
+	token, ok, err := store.Authenticate(secret)
+	if assert.NoError(t, err) && assert.True(t, ok) {
+		assert.Equal(t, "ci", token.Name)
+		assert.NotContains(t, token.Hash, secret)
+	}
+	_, ok, err = store.Authenticate(secret + "x")
+	if assert.NoError(t, err) {
+		assert.False(t, ok)
+	}
+
+	// The secret isn't stored.
+	content, err := ioutil.ReadFile(store.Path())
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), secret)
+
+	_, err = store.Create("ci", []string{"aws"}, nil)
+	assert.EqualError(t, err, "the ci token already exists")
+	_, err = store.Create("none", nil, nil)
+	assert.EqualError(t, err, "the token must be able to read or write at least one plugin")
+}
+
+func TestRevoke(t *testing.T) {
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	secret, err := store.Create("ci", []string{"*"}, nil)
+	require.NoError(t, err)
+	_, err = store.Create("admin", nil, []string{"*"})
+	require.NoError(t, err)
+
+	// Another store, like the daemon's, sees the revocation.
+	daemonStore := NewStore(store.Path())
+	_, ok, err := daemonStore.Authenticate(secret)
+	if assert.NoError(t, err) {
+		assert.True(t, ok)
+	}
+
+	require.NoError(t, store.Revoke("ci"))
+	_, ok, err = daemonStore.Authenticate(secret)
+	if assert.NoError(t, err) {
+		assert.False(t, ok)
+	}
+	tokens, err := daemonStore.List()
+	if assert.NoError(t, err) && assert.Len(t, tokens, 1) {
+		assert.Equal(t, "admin", tokens[0].Name)
+	}
+
+	assert.EqualError(t, store.Revoke("ci"), "the ci token does not exist")
+}
+
+func TestAllows(t *testing.T) {
+	token := Token{Read: []string{"aws"}, Write: []string{"docker"}}
+	assert.True(t, token.Allows("aws", false))
+	assert.False(t, token.Allows("aws", true))
+	assert.True(t, token.Allows("docker", false))
+	assert.True(t, token.Allows("docker", true))
+	assert.False(t, token.Allows("gcp", false))
+	assert.False(t, token.Allows(AllPlugins, false))
+
+	token = Token{Read: []string{AllPlugins}}
+	assert.True(t, token.Allows("gcp", false))
+	assert.True(t, token.Allows(AllPlugins, false))
+	assert.False(t, token.Allows("gcp", true))
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sync"
+
+	"github.com/puppetlabs/wash/activity"
+	apitypes "github.com/puppetlabs/wash/api/types"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// batchConcurrency is the number of a batch's paths that are resolved at once.
+const batchConcurrency = 10
+
+// swagger:parameters batchInfo
+//nolint:deadcode,unused
+type batchBody struct {
+	// in: body
+	Body apitypes.BatchBody
+}
+
+// swagger:response
+//nolint:deadcode,unused
+type batchResults struct {
+	// in: body
+	Results []apitypes.BatchResult
+}
+
+// swagger:route POST /fs/batch batch batchInfo
+//
+// Get the info of multiple entries
+//
+// Returns the Entry objects of up to 1000 paths in one round trip, in the
+// order of the paths. Each entry's full metadata is also returned if metadata
+// is true. A path whose entry can't be found, or whose metadata can't be
+// fetched, has an error object instead, so one bad path doesn't fail the
+// batch.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Schemes: http
+//
+//     Responses:
+//       200: batchResults
+//       400: errorResp
+//       500: errorResp
+var batchHandler = handler{fn: func(w http.ResponseWriter, r *http.Request) *errorResponse {
+	ctx := r.Context()
+	if r.Body == nil {
+		return badRequestResponse("Please send a JSON request body")
+	}
+	var body apitypes.BatchBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return badRequestResponse(err.Error())
+	}
+	if len(body.Paths) > apitypes.MaxBatchPaths {
+		return badRequestResponse(fmt.Sprintf("A batch can have at most %v paths, not %v", apitypes.MaxBatchPaths, len(body.Paths)))
+	}
+
+	results := make([]apitypes.BatchResult, len(body.Paths))
+	sem := make(chan struct{}, batchConcurrency)
+	var wg sync.WaitGroup
+	for i, path := range body.Paths {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, path string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			result := apitypes.BatchResult{Path: path}
+			if !filepath.IsAbs(path) {
+				result.Error = relativePathResponse(path).body
+				results[i] = result
+				return
+			}
+			entry, _, errResp := getEntryFromPath(ctx, path)
+			if errResp != nil {
+				result.Error = errResp.body
+				results[i] = result
+				return
+			}
+			if body.Metadata {
+				metadata, err := plugin.MetadataWithCacheInfo(ctx, entry)
+				if err != nil {
+					result.Error = unknownErrorResponse(err).body
+					results[i] = result
+					return
+				}
+				result.Metadata = metadata
+			}
+			apiEntry := apitypes.NewEntry(entry)
+			apiEntry.Path = path
+			result.Entry = &apiEntry
+			results[i] = result
+		}(i, path)
+	}
+	wg.Wait()
+	activity.Record(ctx, "API: Batch %v paths", len(body.Paths))
+
+	if err := json.NewEncoder(w).Encode(results); err != nil {
+		return unknownErrorResponse(fmt.Errorf("Could not marshal the batch results: %v", err))
+	}
+	return nil
+}}
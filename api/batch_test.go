@@ -0,0 +1,68 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	apitypes "github.com/puppetlabs/wash/api/types"
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func batchRequest(t *testing.T, body string) *httptest.ResponseRecorder {
+	plugin.SetTestCache(newMockCache())
+	defer plugin.UnsetTestCache()
+
+	root := &mockRoot{EntryBase: plugin.NewEntry("mine")}
+	root.SetTestID("/mine")
+	root.On("List", mock.Anything).Return([]plugin.Entry{&listTestsEntry{EntryBase: plugin.NewEntry("a")}}, nil)
+
+	reg := plugin.NewRegistry()
+	require.NoError(t, reg.RegisterPlugin(root, map[string]interface{}{}))
+	ctx := context.WithValue(context.Background(), pluginRegistryKey, reg)
+	ctx = context.WithValue(ctx, mountpointKey, "/mnt")
+
+	r := httptest.NewRequest(http.MethodPost, "/fs/batch", strings.NewReader(body)).WithContext(ctx)
+	w := httptest.NewRecorder()
+	batchHandler.ServeHTTP(w, r)
+	return w
+}
+
+func TestBatchHandler(t *testing.T) {
+	w := batchRequest(t, `{"paths": ["/mnt/mine/a", "mine/a", "/mnt/mine/b", "/mnt/mine"], "metadata": true}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var results []apitypes.BatchResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &results))
+	require.Len(t, results, 4)
+
+	if assert.NotNil(t, results[0].Entry) {
+		assert.Equal(t, "/mnt/mine/a", results[0].Entry.Path)
+	}
+	assert.Nil(t, results[0].Error)
+
+	if assert.NotNil(t, results[1].Error) {
+		assert.Equal(t, apitypes.RelativePath, results[1].Error.Kind)
+	}
+	if assert.NotNil(t, results[2].Error) {
+		assert.Equal(t, apitypes.EntryNotFound, results[2].Error.Kind)
+	}
+	assert.Nil(t, results[2].Entry)
+
+	if assert.NotNil(t, results[3].Entry) {
+		assert.Equal(t, "mine", results[3].Entry.Name)
+	}
+}
+
+func TestBatchHandler_ReturnsErrorForTooManyPaths(t *testing.T) {
+	paths, err := json.Marshal(make([]string, apitypes.MaxBatchPaths+1))
+	require.NoError(t, err)
+	w := batchRequest(t, `{"paths": `+string(paths)+`}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "A batch can have at most 1000 paths, not 1001")
+}
@@ -6,8 +6,11 @@ package client
 // an API, it makes sense to include this code in an api/client/ directory.
 
 import (
+	"bufio"
 	"bytes"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -17,10 +20,15 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/Benchkram/errz"
+	"github.com/gorilla/websocket"
 	"github.com/puppetlabs/wash/activity"
 	"github.com/puppetlabs/wash/analytics"
 	apitypes "github.com/puppetlabs/wash/api/types"
@@ -30,9 +38,31 @@ import (
 type Client interface {
 	Info(path string) (apitypes.Entry, error)
 	List(path string) ([]apitypes.Entry, error)
+	// ListPage lists up to limit entries, starting after the page whose cursor
+	// is cursor. An empty cursor starts at the first page. The returned cursor
+	// is empty on the last page.
+	ListPage(path string, limit int, cursor string) ([]apitypes.Entry, string, error)
+	// ListStream calls fn on each entry as it's received. It stops if fn
+	// returns an error.
+	ListStream(path string, fn func(apitypes.Entry) error) error
 	Metadata(path string) (map[string]interface{}, error)
-	Stream(path string) (io.ReadCloser, error)
+	// Batch returns the info of each path in one request, in the order of the
+	// paths. The results include each entry's full metadata if metadata is
+	// true. Batches of more than apitypes.MaxBatchPaths paths are split into
+	// multiple requests.
+	Batch(paths []string, metadata bool) ([]apitypes.BatchResult, error)
+	// A negative size reads the rest of the content.
+	Read(path string, size int64, offset int64) ([]byte, error)
+	Mountpoint() (string, error)
+	// A zero since only streams new updates.
+	Stream(path string, since time.Duration) (io.ReadCloser, error)
+	// The events channel is closed once the watch ends. Close the returned
+	// closer to stop watching.
+	Watch(path string) (<-chan apitypes.EntryEvent, io.Closer, error)
 	Exec(path string, command string, args []string, opts apitypes.ExecOptions) (<-chan apitypes.ExecPacket, error)
+	// ExecSession is Exec for interactive commands, whose stdin is written
+	// while they're running.
+	ExecSession(path string, command string, args []string, opts apitypes.ExecOptions) (ExecSession, error)
 	History(bool) (chan apitypes.Activity, error)
 	ActivityJournal(index int, follow bool) (io.ReadCloser, error)
 	Clear(path string) ([]string, error)
@@ -41,11 +71,42 @@ type Client interface {
 	Screenview(name string, params analytics.Params) error
 	Delete(path string) (bool, error)
 	Signal(path string, signal string) error
+	Rename(path string, dest string) error
+	// A "nil" query waits for the entry to exist.
+	Wait(path string, query interface{}, opts apitypes.WaitOptions) (apitypes.WaitResult, error)
+	Copy(src string, dest string) (int64, error)
+	Write(path string, content io.Reader) error
+	Create(path string, name string, dir bool, mode os.FileMode) (apitypes.Entry, error)
+	JournalLevels() (map[string]string, error)
+	// An empty level resets the plugin to the default level.
+	SetJournalLevel(plugin string, level string) error
+	Diagnostics() (apitypes.Diagnostics, error)
+	Stats() (apitypes.Stats, error)
+	Trash() ([]apitypes.TrashedEntry, error)
+	RestoreFromTrash(id string) error
+	PurgeFromTrash(id string) (bool, error)
+	InstallPlugin(body apitypes.PluginInstallBody) error
+	EnablePlugin(name string) error
+	DisablePlugin(name string) error
+	ReloadPlugin(name string) error
 }
 
-// A domainSocketClient is a wash API client.
+// A domainSocketClient is a wash API client. Despite its name, it's also used
+// for the remote daemons that are reached over TLS.
 type domainSocketClient struct {
 	*http.Client
+	baseURL string
+	// dialer dials the websockets of exec sessions.
+	dialer *websocket.Dialer
+	// token is the bearer token that authenticates the client to a remote
+	// daemon.
+	token string
+	// thin is true if the client's a thin client of a remote daemon, which
+	// resolves paths against the daemon's mountpoint instead of the local
+	// filesystem.
+	thin       bool
+	mux        sync.Mutex
+	mountpoint string
 }
 
 var domainSocketBaseURL = "http://localhost"
@@ -53,14 +114,108 @@ var domainSocketBaseURL = "http://localhost"
 // ForUNIXSocket returns a client suitable for making wash API calls over a UNIX
 // domain socket.
 func ForUNIXSocket(pathToSocket string) Client {
+	dial := func(_ context.Context, _, _ string) (net.Conn, error) {
+		return net.Dial("unix", pathToSocket)
+	}
 	return &domainSocketClient{
-		&http.Client{
+		Client: &http.Client{
 			Transport: &http.Transport{
-				DialContext: func(_ context.Context, _, _ string) (net.Conn, error) {
-					return net.Dial("unix", pathToSocket)
-				},
+				DialContext: dial,
 			},
-		}}
+		},
+		baseURL: domainSocketBaseURL,
+		dialer:  &websocket.Dialer{NetDialContext: dial},
+	}
+}
+
+// ForTLS returns a client suitable for making wash API calls to a remote daemon
+// that listens at address, which is host:port. Remote daemons require either a
+// client certificate, which tlsConfig should include, or a bearer token. An
+// empty token isn't sent.
+func ForTLS(address string, tlsConfig *tls.Config, token string) Client {
+	return &domainSocketClient{
+		Client: &http.Client{
+			Transport: &http.Transport{
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: tlsConfig,
+			},
+		},
+		baseURL: "https://" + address,
+		dialer: &websocket.Dialer{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsConfig,
+		},
+		token: token,
+	}
+}
+
+// NewTLSConfig returns the TLS config of a client of a remote daemon. The
+// client certificate is optional, since clients can authenticate with a
+// bearer token instead. The system's CAs verify the daemon's certificate if
+// caBundle is empty.
+func NewTLSConfig(caBundle string, cert string, key string) (*tls.Config, error) {
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+	if cert != "" {
+		pair, err := tls.LoadX509KeyPair(cert, key)
+		if err != nil {
+			return nil, fmt.Errorf("could not load the client certificate: %v", err)
+		}
+		cfg.Certificates = []tls.Certificate{pair}
+	}
+	if caBundle != "" {
+		pem, err := ioutil.ReadFile(caBundle)
+		if err != nil {
+			return nil, fmt.Errorf("could not read the CA bundle: %v", err)
+		}
+		cfg.RootCAs = x509.NewCertPool()
+		if !cfg.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("the CA bundle %v does not contain any PEM certificates", caBundle)
+		}
+	}
+	return cfg, nil
+}
+
+// ForThinClient returns a thin client of the remote daemon that listens at
+// address. It's like ForTLS, except that paths are relative to the daemon's
+// mountpoint because the client doesn't mount the daemon's filesystem. So
+// "aws/demo" and "/aws/demo" both refer to the daemon's aws/demo entry.
+func ForThinClient(address string, tlsConfig *tls.Config, token string) Client {
+	c := ForTLS(address, tlsConfig, token).(*domainSocketClient)
+	c.thin = true
+	return c
+}
+
+// absPath returns the absolute path of p. A thin client's paths are resolved
+// against the remote daemon's mountpoint, which is only fetched once.
+func (c *domainSocketClient) absPath(p string) (string, error) {
+	if !c.thin {
+		return filepath.Abs(p)
+	}
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if c.mountpoint == "" {
+		mountpoint, err := c.Mountpoint()
+		if err != nil {
+			return "", fmt.Errorf("could not get the remote daemon's mountpoint: %v", err)
+		}
+		c.mountpoint = mountpoint
+	}
+	if p == c.mountpoint || strings.HasPrefix(p, c.mountpoint+"/") {
+		return path.Clean(p), nil
+	}
+	return path.Join(c.mountpoint, p), nil
+}
+
+// setHeaders sets the headers that are sent with each request.
+func (c *domainSocketClient) setHeaders(header http.Header) {
+	journal := activity.JournalForPID(os.Getpid())
+	header.Set(apitypes.JournalIDHeader, journal.ID)
+	header.Set(apitypes.JournalDescHeader, journal.Description)
+	if c.token != "" {
+		header.Set("Authorization", "Bearer "+c.token)
+	}
 }
 
 func unmarshalErrorResp(resp *http.Response) error {
@@ -77,19 +232,29 @@ func unmarshalErrorResp(resp *http.Response) error {
 }
 
 func (c *domainSocketClient) doRequest(method, endpoint string, params url.Values, body io.Reader) (io.ReadCloser, error) {
+	resp, err := c.doRequestForResponse(method, endpoint, params, body)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// doRequestForResponse is like doRequest, except that it returns the whole
+// response so that its headers can be read.
+func (c *domainSocketClient) doRequestForResponse(method, endpoint string, params url.Values, body io.Reader) (*http.Response, error) {
 	// Do common parameter munging.
 	if paths, ok := params["path"]; ok {
 		if len(paths) != 1 {
 			panic("path parameter should have a single element")
 		}
-		path, err := filepath.Abs(paths[0])
+		path, err := c.absPath(paths[0])
 		if err != nil {
 			return nil, fmt.Errorf("could not calculate the absolute path of %v: %v", path, err)
 		}
 		params["path"] = []string{path}
 	}
 
-	req, err := http.NewRequest(method, domainSocketBaseURL, body)
+	req, err := http.NewRequest(method, c.baseURL, body)
 	if err != nil {
 		return nil, err
 	}
@@ -97,16 +262,14 @@ func (c *domainSocketClient) doRequest(method, endpoint string, params url.Value
 	req.URL.Path = endpoint
 	req.URL.RawQuery = params.Encode()
 
-	journal := activity.JournalForPID(os.Getpid())
-	req.Header.Set(apitypes.JournalIDHeader, journal.ID)
-	req.Header.Set(apitypes.JournalDescHeader, journal.Description)
+	c.setHeaders(req.Header)
 	resp, err := c.Do(req)
 	if err != nil {
 		return nil, err
 	}
 
 	if resp.StatusCode == http.StatusOK {
-		return resp.Body, nil
+		return resp, nil
 	}
 
 	return nil, unmarshalErrorResp(resp)
@@ -155,6 +318,83 @@ func (c *domainSocketClient) List(path string) ([]apitypes.Entry, error) {
 	return ls, nil
 }
 
+// ListPage lists a page of the resources located at "path".
+func (c *domainSocketClient) ListPage(path string, limit int, cursor string) ([]apitypes.Entry, string, error) {
+	params := url.Values{"path": []string{path}, "limit": []string{strconv.Itoa(limit)}}
+	if cursor != "" {
+		params.Set("cursor", cursor)
+	}
+	resp, err := c.doRequestForResponse(http.MethodGet, "/fs/list", params, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	defer func() { errz.Log(resp.Body.Close()) }()
+
+	var ls []apitypes.Entry
+	if err := json.NewDecoder(resp.Body).Decode(&ls); err != nil {
+		return nil, "", fmt.Errorf("Non-JSON body at /fs/list: %v", err)
+	}
+	return ls, resp.Header.Get(apitypes.ListNextCursorHeader), nil
+}
+
+// ListStream streams the resources located at "path".
+func (c *domainSocketClient) ListStream(path string, fn func(apitypes.Entry) error) error {
+	params := url.Values{"path": []string{path}, "stream": []string{"true"}}
+	respBody, err := c.doRequest(http.MethodGet, "/fs/list", params, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { errz.Log(respBody.Close()) }()
+
+	decoder := json.NewDecoder(respBody)
+	for {
+		var entry apitypes.Entry
+		if err := decoder.Decode(&entry); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("Non-JSON body at /fs/list: %v", err)
+		}
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+}
+
+// Batch gets the info of the resources located at "paths".
+func (c *domainSocketClient) Batch(paths []string, metadata bool) ([]apitypes.BatchResult, error) {
+	results := make([]apitypes.BatchResult, 0, len(paths))
+	for start := 0; start < len(paths); start += apitypes.MaxBatchPaths {
+		end := start + apitypes.MaxBatchPaths
+		if end > len(paths) {
+			end = len(paths)
+		}
+		body := apitypes.BatchBody{Metadata: metadata}
+		for _, path := range paths[start:end] {
+			absPath, err := c.absPath(path)
+			if err != nil {
+				return nil, fmt.Errorf("could not calculate the absolute path of %v: %v", path, err)
+			}
+			body.Paths = append(body.Paths, absPath)
+		}
+		jsonBody, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+
+		var batch []apitypes.BatchResult
+		if err := c.doRequestAndParseJSONBody(http.MethodPost, "/fs/batch", url.Values{}, bytes.NewReader(jsonBody), &batch); err != nil {
+			return nil, err
+		}
+		// Return the caller's paths so that they can be matched up with
+		// the results.
+		for i := range batch {
+			batch[i].Path = paths[start+i]
+		}
+		results = append(results, batch...)
+	}
+	return results, nil
+}
+
 // Metadata gets the metadata of the resource located at "path".
 func (c *domainSocketClient) Metadata(path string) (map[string]interface{}, error) {
 	var metadata map[string]interface{}
@@ -165,9 +405,70 @@ func (c *domainSocketClient) Metadata(path string) (map[string]interface{}, erro
 	return metadata, nil
 }
 
-// Stream updates for the resource located at "path".
-func (c *domainSocketClient) Stream(path string) (io.ReadCloser, error) {
-	respBody, err := c.doRequest(http.MethodGet, "/fs/stream", url.Values{"path": []string{path}}, nil)
+// Read reads up to size bytes of the content of the resource located at "path",
+// starting at offset. A negative size reads the rest of the content.
+func (c *domainSocketClient) Read(path string, size int64, offset int64) ([]byte, error) {
+	params := url.Values{"path": []string{path}}
+	if size >= 0 {
+		params.Set("size", strconv.FormatInt(size, 10))
+	}
+	if offset > 0 {
+		params.Set("offset", strconv.FormatInt(offset, 10))
+	}
+	respBody, err := c.doRequest(http.MethodGet, "/fs/read", params, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { errz.Log(respBody.Close()) }()
+	return ioutil.ReadAll(respBody)
+}
+
+// Mountpoint returns the daemon's mountpoint.
+func (c *domainSocketClient) Mountpoint() (string, error) {
+	var mountpoint string
+	if err := c.getRequest("/fs/mountpoint", url.Values{}, &mountpoint); err != nil {
+		return "", err
+	}
+	return mountpoint, nil
+}
+
+// Watch watches the children of the resource located at "path".
+func (c *domainSocketClient) Watch(path string) (<-chan apitypes.EntryEvent, io.Closer, error) {
+	respBody, err := c.doRequest(http.MethodGet, "/fs/watch", url.Values{"path": []string{path}}, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	events := make(chan apitypes.EntryEvent)
+	go func() {
+		defer close(events)
+		// Events are separated by a blank line. Only their data lines are
+		// needed since the data includes the event's type.
+		scanner := bufio.NewScanner(respBody)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if !strings.HasPrefix(line, "data: ") {
+				continue
+			}
+			var event apitypes.EntryEvent
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event); err != nil {
+				log.Println(err)
+				continue
+			}
+			events <- event
+		}
+	}()
+	return events, respBody, nil
+}
+
+// Stream updates for the resource located at "path". If since is non-zero, then
+// the updates made since that long ago are streamed first.
+func (c *domainSocketClient) Stream(path string, since time.Duration) (io.ReadCloser, error) {
+	params := url.Values{"path": []string{path}}
+	if since > 0 {
+		params.Set("since", since.String())
+	}
+	respBody, err := c.doRequest(http.MethodGet, "/fs/stream", params, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -316,3 +617,185 @@ func (c *domainSocketClient) Signal(path string, signal string) error {
 	_, err = c.doRequest(http.MethodPost, "/fs/signal", url.Values{"path": []string{path}}, bytes.NewReader(jsonBody))
 	return err
 }
+
+// Rename renames or moves the entry at "path" to "dest".
+func (c *domainSocketClient) Rename(path string, dest string) error {
+	dest, err := c.absPath(dest)
+	if err != nil {
+		return fmt.Errorf("could not calculate the absolute path of %v: %v", dest, err)
+	}
+	jsonBody, err := json.Marshal(apitypes.RenameBody{Destination: dest})
+	if err != nil {
+		return err
+	}
+	respBody, err := c.doRequest(http.MethodPost, "/fs/rename", url.Values{"path": []string{path}}, bytes.NewReader(jsonBody))
+	if err != nil {
+		return err
+	}
+	return respBody.Close()
+}
+
+// Wait blocks until the entry at "path" satisfies the given RQL query or the
+// timeout elapses. The result's Entry is set if the query was satisfied.
+func (c *domainSocketClient) Wait(path string, query interface{}, opts apitypes.WaitOptions) (apitypes.WaitResult, error) {
+	params := url.Values{"path": []string{path}}
+	if opts.Timeout > 0 {
+		params.Set("timeout", opts.Timeout.String())
+	}
+	if opts.Interval > 0 {
+		params.Set("interval", opts.Interval.String())
+	}
+	if opts.Fullmeta {
+		params.Set("fullmeta", "true")
+	}
+
+	var body io.Reader
+	if query != nil {
+		jsonBody, err := json.Marshal(query)
+		if err != nil {
+			return apitypes.WaitResult{}, err
+		}
+		body = bytes.NewReader(jsonBody)
+	}
+
+	var result apitypes.WaitResult
+	err := c.doRequestAndParseJSONBody(http.MethodPost, "/fs/wait", params, body, &result)
+	return result, err
+}
+
+// Copy copies the content of the entry at "src" to the entry at "dest" and
+// returns the number of copied bytes.
+func (c *domainSocketClient) Copy(src string, dest string) (int64, error) {
+	dest, err := c.absPath(dest)
+	if err != nil {
+		return 0, fmt.Errorf("could not calculate the absolute path of %v: %v", dest, err)
+	}
+	jsonBody, err := json.Marshal(apitypes.CopyBody{Destination: dest})
+	if err != nil {
+		return 0, err
+	}
+
+	var copied int64
+	err = c.doRequestAndParseJSONBody(http.MethodPost, "/fs/copy", url.Values{"path": []string{src}}, bytes.NewReader(jsonBody), &copied)
+	return copied, err
+}
+
+// Write replaces the content of the entry at "path" with the content read from
+// "content".
+func (c *domainSocketClient) Write(path string, content io.Reader) error {
+	respBody, err := c.doRequest(http.MethodPut, "/fs/write", url.Values{"path": []string{path}}, content)
+	if err != nil {
+		return err
+	}
+	return respBody.Close()
+}
+
+// Create creates a new child named "name" of the entry at "path". It creates a
+// directory if "dir" is true, or an empty file with "mode" otherwise.
+func (c *domainSocketClient) Create(path string, name string, dir bool, mode os.FileMode) (apitypes.Entry, error) {
+	var e apitypes.Entry
+	jsonBody, err := json.Marshal(apitypes.CreateBody{Name: name, Dir: dir, Mode: uint32(mode.Perm())})
+	if err != nil {
+		return e, err
+	}
+	err = c.doRequestAndParseJSONBody(http.MethodPost, "/fs/create", url.Values{"path": []string{path}}, bytes.NewReader(jsonBody), &e)
+	return e, err
+}
+
+// JournalLevels returns the journal level of each loaded plugin.
+func (c *domainSocketClient) JournalLevels() (map[string]string, error) {
+	var levels map[string]string
+	if err := c.getRequest("/journal/levels", url.Values{}, &levels); err != nil {
+		return nil, err
+	}
+	return levels, nil
+}
+
+// SetJournalLevel sets the plugin's journal level.
+func (c *domainSocketClient) SetJournalLevel(plugin string, level string) error {
+	jsonBody, err := json.Marshal(apitypes.JournalLevelBody{Plugin: plugin, Level: level})
+	if err != nil {
+		return err
+	}
+
+	_, err = c.doRequest(http.MethodPut, "/journal/levels", url.Values{}, bytes.NewReader(jsonBody))
+	return err
+}
+
+// Diagnostics returns the server's diagnostics.
+func (c *domainSocketClient) Diagnostics() (apitypes.Diagnostics, error) {
+	var diagnostics apitypes.Diagnostics
+	err := c.getRequest("/diagnostics", url.Values{}, &diagnostics)
+	return diagnostics, err
+}
+
+// Stats returns the server's performance stats.
+func (c *domainSocketClient) Stats() (apitypes.Stats, error) {
+	var stats apitypes.Stats
+	err := c.getRequest("/stats", url.Values{}, &stats)
+	return stats, err
+}
+
+// Trash returns the entries that were moved to the trash.
+func (c *domainSocketClient) Trash() ([]apitypes.TrashedEntry, error) {
+	var entries []apitypes.TrashedEntry
+	err := c.getRequest("/trash", url.Values{}, &entries)
+	return entries, err
+}
+
+// RestoreFromTrash restores the trashed entry with the given ID.
+func (c *domainSocketClient) RestoreFromTrash(id string) error {
+	respBody, err := c.doRequest(http.MethodPost, "/trash/"+id+"/restore", url.Values{}, nil)
+	if err != nil {
+		return err
+	}
+	errz.Log(respBody.Close())
+	return nil
+}
+
+// PurgeFromTrash deletes the trashed entry with the given ID now instead of
+// when its retention period expires.
+func (c *domainSocketClient) PurgeFromTrash(id string) (bool, error) {
+	var deleted bool
+	err := c.doRequestAndParseJSONBody(http.MethodDelete, "/trash/"+id, url.Values{}, nil, &deleted)
+	return deleted, err
+}
+
+// InstallPlugin installs an external plugin.
+func (c *domainSocketClient) InstallPlugin(body apitypes.PluginInstallBody) error {
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	respBody, err := c.doRequest(http.MethodPost, "/plugins", url.Values{}, bytes.NewReader(jsonBody))
+	if err != nil {
+		return err
+	}
+	errz.Log(respBody.Close())
+	return nil
+}
+
+// EnablePlugin enables a disabled plugin.
+func (c *domainSocketClient) EnablePlugin(name string) error {
+	return c.pluginAction(name, "enable")
+}
+
+// DisablePlugin disables a plugin.
+func (c *domainSocketClient) DisablePlugin(name string) error {
+	return c.pluginAction(name, "disable")
+}
+
+// ReloadPlugin re-initializes a plugin.
+func (c *domainSocketClient) ReloadPlugin(name string) error {
+	return c.pluginAction(name, "reload")
+}
+
+func (c *domainSocketClient) pluginAction(name string, action string) error {
+	respBody, err := c.doRequest(http.MethodPost, "/plugins/"+url.PathEscape(name)+"/"+action, url.Values{}, nil)
+	if err != nil {
+		return err
+	}
+	errz.Log(respBody.Close())
+	return nil
+}
@@ -0,0 +1,114 @@
+package client
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/Benchkram/errz"
+	"github.com/gorilla/websocket"
+	apitypes "github.com/puppetlabs/wash/api/types"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// ExecSession is an interactive command that was started by
+// Client#ExecSession. Writing to it writes to the command's stdin.
+type ExecSession interface {
+	// Packets returns the command's output and exit code. The channel's
+	// closed once the command's finished.
+	Packets() <-chan apitypes.ExecPacket
+	Write(p []byte) (int, error)
+	// CloseStdin closes the command's stdin.
+	CloseStdin() error
+	// Resize resizes the command's TTY.
+	Resize(rows uint16, cols uint16) error
+	// Close ends the session. The command's stopped if it's still running.
+	Close() error
+}
+
+type execSession struct {
+	conn    *websocket.Conn
+	packets chan apitypes.ExecPacket
+	// Websocket connections support one concurrent writer.
+	mux sync.Mutex
+}
+
+// ExecSession starts an interactive command on the resource located at
+// "path". Use the session to write its stdin and to read its output.
+func (c *domainSocketClient) ExecSession(path string, command string, args []string, opts apitypes.ExecOptions) (ExecSession, error) {
+	path, err := c.absPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not calculate the absolute path of %v: %v", path, err)
+	}
+	u, err := url.Parse(strings.Replace(c.baseURL, "http", "ws", 1))
+	if err != nil {
+		return nil, err
+	}
+	u.Path = "/fs/exec/session"
+	u.RawQuery = url.Values{"path": []string{path}}.Encode()
+
+	header := http.Header{}
+	c.setHeaders(header)
+	conn, resp, err := c.dialer.Dial(u.String(), header)
+	if err != nil {
+		if err == websocket.ErrBadHandshake && resp != nil {
+			return nil, unmarshalErrorResp(resp)
+		}
+		return nil, err
+	}
+
+	session := &execSession{conn: conn, packets: make(chan apitypes.ExecPacket, 1)}
+	if err := session.send(apitypes.ExecBody{Cmd: command, Args: args, Opts: opts}); err != nil {
+		errz.Log(conn.Close())
+		return nil, err
+	}
+	go session.readPackets()
+	return session, nil
+}
+
+func (s *execSession) readPackets() {
+	defer close(s.packets)
+	for {
+		var pkt apitypes.ExecPacket
+		if err := s.conn.ReadJSON(&pkt); err != nil {
+			if !websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+				log.Println(err)
+			}
+			return
+		}
+		s.packets <- pkt
+	}
+}
+
+func (s *execSession) send(msg interface{}) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.conn.WriteJSON(msg)
+}
+
+func (s *execSession) Packets() <-chan apitypes.ExecPacket {
+	return s.packets
+}
+
+func (s *execSession) Write(p []byte) (int, error) {
+	if err := s.send(apitypes.ExecInput{TypeField: apitypes.StdinInput, Data: string(p)}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (s *execSession) CloseStdin() error {
+	return s.send(apitypes.ExecInput{TypeField: apitypes.StdinEOFInput})
+}
+
+func (s *execSession) Resize(rows uint16, cols uint16) error {
+	size := plugin.WindowSize{Rows: rows, Cols: cols}
+	return s.send(apitypes.ExecInput{TypeField: apitypes.ResizeInput, Size: &size})
+}
+
+func (s *execSession) Close() error {
+	return s.conn.Close()
+}
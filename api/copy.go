@@ -0,0 +1,76 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+
+	"github.com/puppetlabs/wash/activity"
+	apitypes "github.com/puppetlabs/wash/api/types"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// swagger:route POST /fs/copy copy copyEntry
+//
+// Copies the content of the entry at the specified path to a destination entry.
+//
+// The content is piped from the source to the destination in bounded chunks when
+// both entries support it, so copying large entries doesn't require buffering
+// their entire content. On success, returns the number of copied bytes.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Schemes: http
+//
+//     Responses:
+//       200:
+//       400: errorResp
+//       404: errorResp
+//       500: errorResp
+var copyHandler = handler{fn: func(w http.ResponseWriter, r *http.Request) *errorResponse {
+	ctx := r.Context()
+	src, path, errResp := getEntryFromRequest(r)
+	if errResp != nil {
+		return errResp
+	}
+	if !plugin.ReadAction().IsSupportedOn(src) {
+		return unsupportedActionResponse(path, plugin.ReadAction())
+	}
+
+	if r.Body == nil {
+		return badActionRequestResponse(path, plugin.WriteAction(), "Please send a JSON request body")
+	}
+	var body apitypes.CopyBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return badActionRequestResponse(path, plugin.WriteAction(), err.Error())
+	}
+	if body.Destination == "" {
+		return badActionRequestResponse(path, plugin.WriteAction(), "Please specify a destination")
+	}
+	if !filepath.IsAbs(body.Destination) {
+		return relativePathResponse(body.Destination)
+	}
+
+	dst, dstPath, errResp := getEntryFromPath(ctx, body.Destination)
+	if errResp != nil {
+		return errResp
+	}
+	if !plugin.WriteAction().IsSupportedOn(dst) {
+		return unsupportedActionResponse(dstPath, plugin.WriteAction())
+	}
+
+	copied, err := plugin.Copy(ctx, src, dst.(plugin.Writable))
+	if err != nil {
+		return erroredActionResponse(dstPath, plugin.WriteAction(), err.Error())
+	}
+	activity.Record(ctx, "API: Copy %v %v: %v bytes", path, dstPath, copied)
+	if err := json.NewEncoder(w).Encode(copied); err != nil {
+		return unknownErrorResponse(fmt.Errorf("Could not marshal copy's result for %v: %v", path, err))
+	}
+	return nil
+}}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/puppetlabs/wash/activity"
+	apitypes "github.com/puppetlabs/wash/api/types"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// swagger:route POST /fs/create create createEntry
+//
+// Creates a new child of the entry at the specified path.
+//
+// Creates an empty file with the request body's mode, or a directory if the
+// request body's dir is true. Only
+// entries whose plugin supports creating children can create them. On success,
+// returns an Entry object describing the new child.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Schemes: http
+//
+//     Responses:
+//       200: Entry
+//       400: errorResp
+//       404: errorResp
+//       500: errorResp
+var createHandler = handler{fn: func(w http.ResponseWriter, r *http.Request) *errorResponse {
+	ctx := r.Context()
+	entry, path, errResp := getEntryFromRequest(r)
+	if errResp != nil {
+		return errResp
+	}
+
+	if r.Body == nil {
+		return badRequestResponse("Please send a JSON request body")
+	}
+	var body apitypes.CreateBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return badRequestResponse(err.Error())
+	}
+	if body.Name == "" || strings.Contains(body.Name, "/") {
+		return badRequestResponse("Please specify a name that doesn't contain a '/'")
+	}
+
+	var child plugin.Entry
+	if body.Dir {
+		parent, ok := entry.(plugin.DirCreatable)
+		if !ok {
+			return badRequestResponse(fmt.Sprintf("%v does not support creating directories", path))
+		}
+		dir, err := plugin.CreateDir(ctx, parent, body.Name)
+		if err != nil {
+			return unknownErrorResponse(fmt.Errorf("Could not create directory %v in %v: %v", body.Name, path, err))
+		}
+		child = dir
+	} else {
+		parent, ok := entry.(plugin.Creatable)
+		if !ok {
+			return badRequestResponse(fmt.Sprintf("%v does not support creating files", path))
+		}
+		mode := os.FileMode(0644)
+		if body.Mode != 0 {
+			if os.FileMode(body.Mode)&^os.ModePerm != 0 {
+				return badRequestResponse(fmt.Sprintf("The mode %o isn't a file's permissions", body.Mode))
+			}
+			mode = os.FileMode(body.Mode)
+		}
+		file, err := plugin.Create(ctx, parent, body.Name, mode)
+		if err != nil {
+			return unknownErrorResponse(fmt.Errorf("Could not create file %v in %v: %v", body.Name, path, err))
+		}
+		// Created files only exist once they're written to.
+		if err := plugin.WriteStream(ctx, file, strings.NewReader(""), 0); err != nil {
+			return unknownErrorResponse(fmt.Errorf("Could not create file %v in %v: %v", body.Name, path, err))
+		}
+		child = file
+	}
+
+	apiEntry := apitypes.NewEntry(child)
+	apiEntry.Path = strings.TrimRight(path, "/") + "/" + apiEntry.CName
+	activity.Record(ctx, "API: Create %v in %v", apiEntry.CName, path)
+	if err := json.NewEncoder(w).Encode(&apiEntry); err != nil {
+		return unknownErrorResponse(fmt.Errorf("Could not marshal %v: %v", apiEntry.Path, err))
+	}
+	return nil
+}}
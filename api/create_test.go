@@ -0,0 +1,104 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	apitypes "github.com/puppetlabs/wash/api/types"
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type createTestsRoot struct {
+	mockRoot
+	written map[string]string
+	modes   map[string]os.FileMode
+}
+
+func (r *createTestsRoot) List(ctx context.Context) ([]plugin.Entry, error) {
+	return nil, nil
+}
+
+func (r *createTestsRoot) Create(ctx context.Context, name string, mode os.FileMode) (plugin.Writable, error) {
+	r.modes[name] = mode
+	return &createTestsFile{EntryBase: plugin.NewEntry(name), root: r}, nil
+}
+
+func (r *createTestsRoot) CreateDir(ctx context.Context, name string) (plugin.Parent, error) {
+	return &createTestsRoot{mockRoot: mockRoot{EntryBase: plugin.NewEntry(name)}}, nil
+}
+
+type createTestsFile struct {
+	plugin.EntryBase
+	root *createTestsRoot
+}
+
+func (f *createTestsFile) Schema() *plugin.EntrySchema {
+	return nil
+}
+
+func (f *createTestsFile) Write(ctx context.Context, b []byte) error {
+	f.root.written[f.Name()] = string(b)
+	return nil
+}
+
+func createRequest(t *testing.T, plug plugin.Root, body string) *httptest.ResponseRecorder {
+	plugin.SetTestCache(newMockCache())
+	defer plugin.UnsetTestCache()
+
+	reg := plugin.NewRegistry()
+	require.NoError(t, reg.RegisterPlugin(plug, map[string]interface{}{}))
+	ctx := context.WithValue(context.Background(), pluginRegistryKey, reg)
+	ctx = context.WithValue(ctx, mountpointKey, "/mnt")
+
+	r := httptest.NewRequest(http.MethodPost, "/fs/create?path=/mnt/mine", strings.NewReader(body)).WithContext(ctx)
+	w := httptest.NewRecorder()
+	createHandler.ServeHTTP(w, r)
+	return w
+}
+
+func TestCreateHandler(t *testing.T) {
+	plug := &createTestsRoot{mockRoot: mockRoot{EntryBase: plugin.NewEntry("mine")}, written: make(map[string]string), modes: make(map[string]os.FileMode)}
+	plug.SetTestID("/mine")
+
+	w := createRequest(t, plug, `{"name": "notes.txt"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var entry apitypes.Entry
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &entry))
+	assert.Equal(t, "/mnt/mine/notes.txt", entry.Path)
+	// Files are created by writing empty content to them.
+	assert.Equal(t, map[string]string{"notes.txt": ""}, plug.written)
+	// Files are 0644 unless the request has a mode.
+	assert.Equal(t, os.FileMode(0644), plug.modes["notes.txt"])
+
+	w = createRequest(t, plug, `{"name": "secret.txt", "mode": 384}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, os.FileMode(0600), plug.modes["secret.txt"])
+
+	w = createRequest(t, plug, `{"name": "setuid", "mode": 2541}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = createRequest(t, plug, `{"name": "logs", "dir": true}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &entry))
+	assert.Equal(t, "/mnt/mine/logs", entry.Path)
+	assert.Contains(t, entry.Actions, plugin.ListAction().Name)
+
+	w = createRequest(t, plug, `{"name": "a/b"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCreateHandlerUnsupported(t *testing.T) {
+	plug := &mockRoot{EntryBase: plugin.NewEntry("mine")}
+	plug.SetTestID("/mine")
+
+	w := createRequest(t, plug, `{"name": "logs", "dir": true}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "/mnt/mine does not support creating directories")
+}
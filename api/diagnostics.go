@@ -0,0 +1,52 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime/pprof"
+	"sort"
+
+	"github.com/puppetlabs/wash/activity"
+	apitypes "github.com/puppetlabs/wash/api/types"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// swagger:route GET /diagnostics diagnostics getDiagnostics
+//
+// Get diagnostics
+//
+// Get the server's loaded plugins, cache usage, recently recovered panics and
+// goroutine dump. Used to generate diagnostic bundles for bug reports.
+//
+//     Produces:
+//     - application/json
+//
+//     Schemes: http
+//
+//     Responses:
+//       200: Diagnostics
+//       500: errorResp
+var diagnosticsHandler = handler{logOnly: true, fn: func(w http.ResponseWriter, r *http.Request) *errorResponse {
+	registry := r.Context().Value(pluginRegistryKey).(*plugin.Registry)
+	diagnostics := apitypes.Diagnostics{Panics: activity.RecentPanics()}
+	for name := range registry.Plugins() {
+		diagnostics.Plugins = append(diagnostics.Plugins, name)
+	}
+	sort.Strings(diagnostics.Plugins)
+	if stats, ok := plugin.CacheStats(); ok {
+		diagnostics.Cache = &stats
+	}
+
+	var goroutines bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&goroutines, 2); err != nil {
+		return unknownErrorResponse(fmt.Errorf("Could not dump goroutines: %v", err))
+	}
+	diagnostics.Goroutines = goroutines.String()
+
+	if err := json.NewEncoder(w).Encode(diagnostics); err != nil {
+		return unknownErrorResponse(fmt.Errorf("Could not marshal diagnostics: %v", err))
+	}
+	return nil
+}}
@@ -165,6 +165,14 @@ func invalidIntParam(name, value string) *errorResponse {
 	)}
 }
 
+func invalidDurationParam(name, value string) *errorResponse {
+	return &errorResponse{http.StatusBadRequest, newErrorObj(
+		apitypes.InvalidDuration,
+		fmt.Sprintf("Invalid duration value '%v' given for %v parameter", value, name),
+		apitypes.ErrorFields{"value": value},
+	)}
+}
+
 func invalidPathsResponse() *errorResponse {
 	return &errorResponse{http.StatusBadRequest, newErrorObj(
 		apitypes.InvalidPaths,
@@ -189,25 +197,6 @@ func erroredActionResponse(path string, a plugin.Action, reason string) *errorRe
 	return &errorResponse{statusCode, body}
 }
 
-func duplicateCNameResponse(e plugin.DuplicateCNameErr) *errorResponse {
-	fields := apitypes.ErrorFields{
-		"parent_id":                   e.ParentID,
-		"first_child_name":            e.FirstChildName,
-		"first_child_slash_replacer":  e.FirstChildSlashReplacer,
-		"second_child_name":           e.SecondChildName,
-		"second_child_slash_replacer": e.SecondChildSlashReplacer,
-		"cname":                       e.CName,
-	}
-
-	body := newErrorObj(
-		apitypes.DuplicateCName,
-		e.Error(),
-		fields,
-	)
-
-	return &errorResponse{http.StatusInternalServerError, body}
-}
-
 func relativePathResponse(path string) *errorResponse {
 	fields := apitypes.ErrorFields{
 		"path": path,
@@ -229,3 +218,59 @@ func nonWashPathResponse(path string) *errorResponse {
 		apitypes.ErrorFields{"path": path},
 	)}
 }
+
+func webhookNotFoundResponse(id string) *errorResponse {
+	return &errorResponse{http.StatusNotFound, newErrorObj(
+		apitypes.WebhookNotFound,
+		fmt.Sprintf("Webhook %v does not exist", id),
+		apitypes.ErrorFields{"id": id},
+	)}
+}
+
+func trashedEntryNotFoundResponse(id string) *errorResponse {
+	return &errorResponse{http.StatusNotFound, newErrorObj(
+		apitypes.TrashedEntryNotFound,
+		fmt.Sprintf("Trashed entry %v does not exist", id),
+		apitypes.ErrorFields{"id": id},
+	)}
+}
+
+func idempotencyKeyReusedResponse(key string) *errorResponse {
+	return &errorResponse{http.StatusUnprocessableEntity, newErrorObj(
+		apitypes.IdempotencyKeyReused,
+		fmt.Sprintf("Idempotency key %v was already used for a different request", key),
+		apitypes.ErrorFields{"key": key},
+	)}
+}
+
+func duplicateRequestResponse(key string) *errorResponse {
+	return &errorResponse{http.StatusConflict, newErrorObj(
+		apitypes.DuplicateRequest,
+		fmt.Sprintf("The request with idempotency key %v already succeeded, but its response was too large to replay", key),
+		apitypes.ErrorFields{"key": key},
+	)}
+}
+
+func unauthorizedResponse(reason string) *errorResponse {
+	return &errorResponse{http.StatusUnauthorized, newErrorObj(
+		apitypes.Unauthorized,
+		fmt.Sprintf("Unauthorized: %v", reason),
+		apitypes.ErrorFields{},
+	)}
+}
+
+func forbiddenResponse(token string, plugin string, write bool) *errorResponse {
+	access := "read"
+	if write {
+		access = "write"
+	}
+	msg := fmt.Sprintf("The %v token can't %v the %v plugin", token, access, plugin)
+	if plugin == "*" {
+		msg = fmt.Sprintf("The %v token can't %v every plugin, which is required to access Wash's root", token, access)
+	}
+	return &errorResponse{http.StatusForbidden, newErrorObj(
+		apitypes.Forbidden,
+		msg,
+		apitypes.ErrorFields{"token": token, "plugin": plugin, "access": access},
+	)}
+}
@@ -87,7 +87,7 @@ var execHandler = handler{fn: func(w http.ResponseWriter, r *http.Request) *erro
 	}
 
 	activity.Record(ctx, "API: Exec %v %+v", path, body)
-	opts := plugin.ExecOptions{}
+	opts := plugin.ExecOptions{Tty: body.Opts.Tty}
 	if body.Opts.Input != "" {
 		opts.Stdin = strings.NewReader(body.Opts.Input)
 	}
@@ -0,0 +1,162 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/puppetlabs/wash/activity"
+	apitypes "github.com/puppetlabs/wash/api/types"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// The default CheckOrigin rejects cross-origin requests, which keeps web pages
+// from starting sessions. Requests from Wash's client don't set an Origin.
+var execSessionUpgrader = websocket.Upgrader{}
+
+// swagger:parameters executeInteractiveCommand
+//nolint:deadcode,unused
+type execSessionParams struct {
+	params
+}
+
+// swagger:route GET /fs/exec/session exec executeInteractiveCommand
+//
+// Execute an interactive command on a remote system
+//
+// Executes a command on the remote system described by the supplied path
+// over a websocket, so that its stdin can be written while it's running.
+// The client first sends the command as an ExecBody, then sends ExecInput
+// messages. The server sends the same packets as /fs/exec.
+//
+//     Schemes: http
+//
+//     Responses:
+//       101: execResponse
+//       400: errorResp
+//       404: errorResp
+//       500: errorResp
+var execSessionHandler = handler{fn: func(w http.ResponseWriter, r *http.Request) *errorResponse {
+	entry, path, errResp := getEntryFromRequest(r)
+	if errResp != nil {
+		return errResp
+	}
+
+	if !plugin.ExecAction().IsSupportedOn(entry) {
+		return unsupportedActionResponse(path, plugin.ExecAction())
+	}
+
+	conn, err := execSessionUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade already replied with an error.
+		activity.Record(r.Context(), "API: Could not start an exec session on %v: %v", path, err)
+		return nil
+	}
+	defer conn.Close()
+
+	// The request's context isn't cancelled when the client disconnects since
+	// the connection's been hijacked, so the session cancels it instead.
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	var body apitypes.ExecBody
+	if err := conn.ReadJSON(&body); err != nil {
+		sendSessionPacket(ctx, conn, &apitypes.ExecPacket{
+			TypeField: apitypes.Exitcode,
+			Timestamp: time.Now(),
+			Err:       badActionRequestResponse(path, plugin.ExecAction(), err.Error()).body,
+		})
+		return nil
+	}
+
+	activity.Record(ctx, "API: Exec session %v %+v", path, body)
+	stdinR, stdinW := io.Pipe()
+	resizeCh := make(chan plugin.WindowSize, 1)
+	opts := plugin.ExecOptions{Stdin: stdinR, Tty: body.Opts.Tty, Resize: resizeCh}
+	if body.Opts.Input != "" {
+		opts.Stdin = io.MultiReader(strings.NewReader(body.Opts.Input), stdinR)
+	}
+	cmd, err := plugin.ExecWithAnalytics(ctx, entry.(plugin.Execable), body.Cmd, body.Args, opts)
+	if err != nil {
+		sendSessionPacket(ctx, conn, &apitypes.ExecPacket{
+			TypeField: apitypes.Exitcode,
+			Timestamp: time.Now(),
+			Err:       erroredActionResponse(path, plugin.ExecAction(), err.Error()).body,
+		})
+		return nil
+	}
+
+	// Forward the client's input until it disconnects.
+	go func() {
+		defer cancel()
+		defer stdinW.Close()
+		for {
+			var input apitypes.ExecInput
+			if err := conn.ReadJSON(&input); err != nil {
+				activity.Record(ctx, "API: Exec session %v input closed: %v", path, err)
+				return
+			}
+			switch input.TypeField {
+			case apitypes.StdinInput:
+				if _, err := stdinW.Write([]byte(input.Data)); err != nil {
+					activity.Record(ctx, "API: Exec session %v could not write stdin: %v", path, err)
+				}
+			case apitypes.StdinEOFInput:
+				activity.Record(ctx, "API: Exec session %v closed stdin: %v", path, stdinW.Close())
+			case apitypes.ResizeInput:
+				if input.Size != nil {
+					// Only the latest size matters, so replace a size that the
+					// executor hasn't received yet.
+					select {
+					case <-resizeCh:
+					default:
+					}
+					resizeCh <- *input.Size
+				}
+			default:
+				activity.Record(ctx, "API: Exec session %v ignored input of type %v", path, input.TypeField)
+			}
+		}
+	}()
+
+	for chunk := range cmd.OutputCh() {
+		packet := apitypes.ExecPacket{TypeField: chunk.StreamID, Timestamp: chunk.Timestamp}
+		if err := chunk.Err; err != nil {
+			packet.Err = newStreamingErrorObj(chunk.StreamID, err.Error())
+		} else {
+			packet.Data = chunk.Data
+		}
+		sendSessionPacket(ctx, conn, &packet)
+	}
+
+	packet := apitypes.ExecPacket{TypeField: apitypes.Exitcode, Timestamp: time.Now()}
+	exitCode, err := cmd.ExitCode()
+	if err != nil {
+		packet.Err = newUnknownErrorObj(fmt.Errorf("could not get the exit code: %v", err))
+	} else {
+		packet.Data = exitCode
+	}
+	sendSessionPacket(ctx, conn, &packet)
+
+	closeMsg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+	if err := conn.WriteControl(websocket.CloseMessage, closeMsg, time.Now().Add(time.Second)); err != nil {
+		activity.Record(ctx, "API: Exec session %v could not send the close message: %v", path, err)
+	}
+	return nil
+}}
+
+// sendSessionPacket is sendPacket for exec sessions.
+func sendSessionPacket(ctx context.Context, conn *websocket.Conn, p *apitypes.ExecPacket) {
+	select {
+	case <-ctx.Done():
+		// The client's gone, so there's no-one to send the packet to.
+	default:
+		if err := conn.WriteJSON(p); err != nil {
+			activity.Record(ctx, "Error sending the packet from %v: %v", p.TypeField, err)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	apitypes "github.com/puppetlabs/wash/api/types"
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// execSessionTestsRoot echoes stdin until it's closed.
+type execSessionTestsRoot struct {
+	mockRoot
+	resized chan plugin.WindowSize
+}
+
+func (r *execSessionTestsRoot) Exec(ctx context.Context, cmd string, args []string, opts plugin.ExecOptions) (plugin.ExecCommand, error) {
+	execCmd := plugin.NewExecCommand(ctx)
+	execCmd.SetResizeFunc(opts.Resize, func(size plugin.WindowSize) error {
+		r.resized <- size
+		return nil
+	})
+	go func() {
+		_, err := io.Copy(execCmd.Stdout(), opts.Stdin)
+		execCmd.CloseStreamsWithError(err)
+		execCmd.SetExitCode(0)
+	}()
+	return execCmd, nil
+}
+
+func TestExecSessionHandler(t *testing.T) {
+	plugin.SetTestCache(newMockCache())
+	defer plugin.UnsetTestCache()
+
+	plug := &execSessionTestsRoot{mockRoot: mockRoot{EntryBase: plugin.NewEntry("mine")}, resized: make(chan plugin.WindowSize, 1)}
+	plug.SetTestID("/mine")
+	reg := plugin.NewRegistry()
+	require.NoError(t, reg.RegisterPlugin(plug, map[string]interface{}{}))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), pluginRegistryKey, reg)
+		ctx = context.WithValue(ctx, mountpointKey, "/mnt")
+		execSessionHandler.ServeHTTP(w, r.WithContext(ctx))
+	}))
+	defer server.Close()
+
+	url := strings.Replace(server.URL, "http", "ws", 1) + "/fs/exec/session?path=/mnt/mine"
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.NoError(t, conn.WriteJSON(apitypes.ExecBody{Cmd: "cat", Opts: apitypes.ExecOptions{Tty: true}}))
+	require.NoError(t, conn.WriteJSON(apitypes.ExecInput{TypeField: apitypes.ResizeInput, Size: &plugin.WindowSize{Rows: 24, Cols: 80}}))
+	select {
+	case size := <-plug.resized:
+		assert.Equal(t, plugin.WindowSize{Rows: 24, Cols: 80}, size)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the TTY was not resized")
+	}
+
+	require.NoError(t, conn.WriteJSON(apitypes.ExecInput{TypeField: apitypes.StdinInput, Data: "hello"}))
+	var pkt apitypes.ExecPacket
+	require.NoError(t, conn.ReadJSON(&pkt))
+	assert.Equal(t, apitypes.Stdout, pkt.TypeField)
+	assert.Equal(t, "hello", pkt.Data)
+
+	require.NoError(t, conn.WriteJSON(apitypes.ExecInput{TypeField: apitypes.StdinEOFInput}))
+	require.NoError(t, conn.ReadJSON(&pkt))
+	assert.Equal(t, apitypes.Exitcode, pkt.TypeField)
+	assert.Equal(t, float64(0), pkt.Data)
+
+	err = conn.ReadJSON(&pkt)
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseNormalClosure), "unexpected error: %v", err)
+}
@@ -1,10 +1,13 @@
 package api
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/puppetlabs/wash/activity"
 	"github.com/puppetlabs/wash/api/rql"
@@ -17,15 +20,41 @@ import (
 //nolint:deadcode,unused
 type findParams struct {
 	params
-	rql.Options
+	// the minimum depth of the returned entries. The path is at depth 0.
+	//
+	// in: query
+	Mindepth int
+	// the maximum depth of the returned entries
+	//
+	// in: query
+	Maxdepth int
+	// if true, then meta primaries act on the entries' full metadata, which is
+	// also returned
+	//
+	// in: query
+	Fullmeta bool
+	// the maximum number of bytes of each entry's content that content
+	// primaries read. Defaults to 1 MiB.
+	//
+	// in: query
+	Contentmaxsize int64
+	// the RQL query, or a FindBody that wraps the query with sort, limit,
+	// offset and fields clauses. Defaults to matching every entry.
+	//
+	// in: body
+	Query interface{}
 }
 
-// swagger:route GET /fs/find find findQuery
+// swagger:route POST /fs/find find findQuery
 //
 // Find entries using RQL
 //
 // Recursively descends the given path, returning all children that satisfy
-// the given RQL query.
+// the given RQL query. If the body's a FindBody, then the children are sorted,
+// paginated and projected as it specifies, and the Total-Count header is set
+// to the number of children that satisfied the query. If the FindBody has an
+// aggregate clause, then the response is a list of AggregateResult objects
+// instead.
 //
 //     Consumes:
 //     - application/json
@@ -63,17 +92,51 @@ var findHandler = handler{fn: func(w http.ResponseWriter, r *http.Request) *erro
 	if errResp != nil {
 		return errResp
 	}
-	var rawQuery interface{}
-	if err := json.NewDecoder(r.Body).Decode(&rawQuery); err != nil {
-		if err != io.EOF {
-			return badRequestResponse(fmt.Sprintf("could not decode the RQL query: %v", err))
-		}
-		rawQuery = true
+	contentMaxSize, hasContentMaxSize, errResp := getInt64Param(r.URL, "contentmaxsize")
+	if errResp != nil {
+		return errResp
+	}
+	if contentMaxSize < 0 {
+		return badRequestResponse(fmt.Sprintf("contentmaxsize must be non-negative, got %v", contentMaxSize))
+	}
+	body, isFindBody, errResp := getFindBody(r)
+	if errResp != nil {
+		return errResp
 	}
 	query := ast.Query()
-	if err := query.Unmarshal(rawQuery); err != nil {
+	if err := query.Unmarshal(body.Query); err != nil {
 		return badRequestResponse(fmt.Sprintf("could not decode the RQL query: %v", err))
 	}
+	sortKeys := make([]rql.SortKey, len(body.Sort))
+	for i, str := range body.Sort {
+		key, err := rql.ParseSortKey(str)
+		if err != nil {
+			return badRequestResponse(fmt.Sprintf("invalid sort key: %v", err))
+		}
+		sortKeys[i] = key
+	}
+	var aggregation *rql.Aggregation
+	if body.Aggregate != nil {
+		if len(body.Sort) > 0 || body.Limit > 0 || body.Offset > 0 || len(body.Fields) > 0 {
+			return badRequestResponse("aggregate can't be combined with sort, limit, offset or fields")
+		}
+		a, err := rql.NewAggregation(body.Aggregate.Function, body.Aggregate.Field, body.Aggregate.GroupBy)
+		if err != nil {
+			return badRequestResponse(fmt.Sprintf("invalid aggregate: %v", err))
+		}
+		aggregation = &a
+	}
+	fields := make([]rql.Field, len(body.Fields))
+	for i, str := range body.Fields {
+		field, err := rql.ParseField(str)
+		if err == nil && !strings.HasPrefix(str, "meta.") {
+			err = fmt.Errorf("%q is not a metadata field like meta.<key>", str)
+		}
+		if err != nil {
+			return badRequestResponse(fmt.Sprintf("invalid field: %v", err))
+		}
+		fields[i] = field
+	}
 
 	opts := rql.NewOptions()
 	opts.Fullmeta = fullMeta
@@ -83,17 +146,42 @@ var findHandler = handler{fn: func(w http.ResponseWriter, r *http.Request) *erro
 	if hasMaxDepth {
 		opts.Maxdepth = maxDepth
 	}
+	if hasContentMaxSize {
+		opts.ContentMaxSize = contentMaxSize
+	}
 
 	rqlEntries, err := rql.Find(ctx, entry, query, opts)
 	if err != nil {
 		return unknownErrorResponse(err)
 	}
 
+	if isFindBody {
+		w.Header().Set(apitypes.FindTotalCountHeader, strconv.Itoa(len(rqlEntries)))
+	}
+	if aggregation != nil {
+		var results []apitypes.AggregateResult
+		for _, group := range rql.Aggregate(rqlEntries, *aggregation) {
+			results = append(results, apitypes.AggregateResult{Group: group.Key, Count: group.Count, Value: group.Value})
+		}
+		activity.Record(ctx, "API: Find %v aggregated %v items into %v groups", path, len(rqlEntries), len(results))
+		if err := json.NewEncoder(w).Encode(results); err != nil {
+			return unknownErrorResponse(fmt.Errorf("Could not marshal the aggregated find results for %v: %v", path, err))
+		}
+		return nil
+	}
+	if len(sortKeys) > 0 {
+		rql.Sort(rqlEntries, sortKeys)
+	}
+	rqlEntries = paginate(rqlEntries, body.Offset, body.Limit)
+
 	result := []apitypes.Entry{}
 	for _, rqlEntry := range rqlEntries {
 		apiEntry := rqlEntry.Entry
 		// Make sure all paths are absolute paths
 		apiEntry.Path = path + "/" + apiEntry.Path
+		if len(fields) > 0 {
+			apiEntry.Metadata = rql.Project(apiEntry.Metadata, fields)
+		}
 		result = append(result, apiEntry)
 	}
 
@@ -105,3 +193,54 @@ var findHandler = handler{fn: func(w http.ResponseWriter, r *http.Request) *erro
 	}
 	return nil
 }}
+
+// getFindBody decodes the find endpoint's body, which is either a bare RQL
+// query or a FindBody. It returns true if it's a FindBody.
+func getFindBody(r *http.Request) (apitypes.FindBody, bool, *errorResponse) {
+	var raw json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&raw); err != nil {
+		if err != io.EOF {
+			return apitypes.FindBody{}, false, badRequestResponse(fmt.Sprintf("could not decode the RQL query: %v", err))
+		}
+		return apitypes.FindBody{Query: true}, false, nil
+	}
+
+	// RQL queries are arrays or booleans, so an object is a FindBody.
+	if !bytes.HasPrefix(bytes.TrimSpace(raw), []byte("{")) {
+		var query interface{}
+		if err := json.Unmarshal(raw, &query); err != nil {
+			return apitypes.FindBody{}, false, badRequestResponse(fmt.Sprintf("could not decode the RQL query: %v", err))
+		}
+		return apitypes.FindBody{Query: query}, false, nil
+	}
+
+	var body apitypes.FindBody
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&body); err != nil {
+		return apitypes.FindBody{}, false, badRequestResponse(fmt.Sprintf("could not decode the find body: %v", err))
+	}
+	if body.Query == nil {
+		body.Query = true
+	}
+	if body.Limit < 0 {
+		return apitypes.FindBody{}, false, badRequestResponse(fmt.Sprintf("limit must be non-negative, got %v", body.Limit))
+	}
+	if body.Offset < 0 {
+		return apitypes.FindBody{}, false, badRequestResponse(fmt.Sprintf("offset must be non-negative, got %v", body.Offset))
+	}
+	return body, true, nil
+}
+
+// paginate returns the entries after the first offset entries, up to limit
+// entries. A zero limit means no limit.
+func paginate(entries []rql.Entry, offset int, limit int) []rql.Entry {
+	if offset >= len(entries) {
+		return nil
+	}
+	entries = entries[offset:]
+	if limit > 0 && limit < len(entries) {
+		entries = entries[:limit]
+	}
+	return entries
+}
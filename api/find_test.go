@@ -0,0 +1,70 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/puppetlabs/wash/api/rql"
+	apitypes "github.com/puppetlabs/wash/api/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func findBodyRequest(body string) *http.Request {
+	return httptest.NewRequest(http.MethodPost, "http://example.com/fs/find", strings.NewReader(body))
+}
+
+func TestGetFindBody(t *testing.T) {
+	body, isFindBody, errResp := getFindBody(findBodyRequest(""))
+	require.Nil(t, errResp)
+	assert.False(t, isFindBody)
+	assert.Equal(t, apitypes.FindBody{Query: true}, body)
+
+	body, isFindBody, errResp = getFindBody(findBodyRequest(`["name", ["glob", "*.log"]]`))
+	require.Nil(t, errResp)
+	assert.False(t, isFindBody)
+	assert.Equal(t, []interface{}{"name", []interface{}{"glob", "*.log"}}, body.Query)
+
+	body, isFindBody, errResp = getFindBody(findBodyRequest(` {"sort": ["-size"], "limit": 10, "offset": 20, "fields": ["meta.tags"]}`))
+	require.Nil(t, errResp)
+	assert.True(t, isFindBody)
+	assert.Equal(t, apitypes.FindBody{Query: true, Sort: []string{"-size"}, Limit: 10, Offset: 20, Fields: []string{"meta.tags"}}, body)
+
+	body, _, errResp = getFindBody(findBodyRequest(`{"query": ["kind", ["glob", "*object"]], "aggregate": {"function": "sum", "field": "size", "group_by": "meta.Bucket"}}`))
+	require.Nil(t, errResp)
+	assert.Equal(t, &apitypes.FindAggregate{Function: "sum", Field: "size", GroupBy: "meta.Bucket"}, body.Aggregate)
+}
+
+func TestGetFindBody_Errors(t *testing.T) {
+	for body, msg := range map[string]string{
+		`[`:                   "could not decode the RQL query",
+		`{"order": ["name"]}`: "could not decode the find body.*unknown field",
+		`{"limit": -1}`:       "limit must be non-negative",
+		`{"offset": -1}`:      "offset must be non-negative",
+	} {
+		_, _, errResp := getFindBody(findBodyRequest(body))
+		if assert.NotNil(t, errResp, body) {
+			assert.Equal(t, http.StatusBadRequest, errResp.statusCode)
+			assert.Regexp(t, msg, errResp.body.Msg)
+		}
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	entries := make([]rql.Entry, 5)
+	for i := range entries {
+		entries[i].Path = string(rune('a' + i))
+	}
+	names := func(entries []rql.Entry) (ps []string) {
+		for _, e := range entries {
+			ps = append(ps, e.Path)
+		}
+		return
+	}
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, names(paginate(entries, 0, 0)))
+	assert.Equal(t, []string{"b", "c"}, names(paginate(entries, 1, 2)))
+	assert.Equal(t, []string{"d", "e"}, names(paginate(entries, 3, 10)))
+	assert.Empty(t, paginate(entries, 5, 1))
+}
@@ -5,6 +5,8 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/fsnotify/fsnotify"
+	"github.com/puppetlabs/wash/activity"
 	"github.com/puppetlabs/wash/plugin"
 )
 
@@ -37,6 +39,72 @@ func (d *dir) List(ctx context.Context) ([]plugin.Entry, error) {
 	return entries, nil
 }
 
+// Watch uses fsnotify to watch the directory's files.
+func (d *dir) Watch(ctx context.Context) (<-chan plugin.EntryEvent, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := watcher.Add(d.path); err != nil {
+		_ = watcher.Close()
+		return nil, err
+	}
+
+	events := make(chan plugin.EntryEvent)
+	go func() {
+		defer close(events)
+		defer watcher.Close()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case err := <-watcher.Errors:
+				activity.Warnf(ctx, "Error watching %v: %v", d.path, err)
+			case fsEvent, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				event, ok := newEntryEvent(ctx, fsEvent)
+				if !ok {
+					continue
+				}
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return events, nil
+}
+
+func newEntryEvent(ctx context.Context, fsEvent fsnotify.Event) (plugin.EntryEvent, bool) {
+	var event plugin.EntryEvent
+	switch {
+	case fsEvent.Op&(fsnotify.Remove|fsnotify.Rename) != 0:
+		// A renamed file's new name is reported as a separate create event.
+		event.Type = plugin.EntryDeleted
+		event.Name = filepath.Base(fsEvent.Name)
+		return event, true
+	case fsEvent.Op&fsnotify.Create != 0:
+		event.Type = plugin.EntryCreated
+	case fsEvent.Op&(fsnotify.Write|fsnotify.Chmod) != 0:
+		event.Type = plugin.EntryModified
+	default:
+		return event, false
+	}
+	entry, err := NewEntry(ctx, fsEvent.Name)
+	if err != nil {
+		// The file was removed before it could be stat'ed. Its remove
+		// event will follow.
+		activity.Record(ctx, "Could not stat %v: %v", fsEvent.Name, err)
+		return event, false
+	}
+	event.Entry = entry
+	return event, true
+}
+
 func (d *dir) ChildSchemas() []*plugin.EntrySchema {
 	return []*plugin.EntrySchema{
 		(&dir{}).Schema(),
@@ -48,4 +116,4 @@ func (d *dir) Schema() *plugin.EntrySchema {
 	return plugin.NewEntrySchema(d, "dir")
 }
 
-var _ = plugin.Parent(&dir{})
+var _ = plugin.Watchable(&dir{})
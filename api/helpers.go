@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	apifs "github.com/puppetlabs/wash/api/fs"
 	apitypes "github.com/puppetlabs/wash/api/types"
@@ -19,10 +20,6 @@ func findEntry(ctx context.Context, root plugin.Entry, segments []string) (plugi
 	path := strings.Join(segments, "/")
 	curEntry, err := plugin.FindEntry(ctx, root, segments)
 	if err != nil {
-		if cnameErr, ok := err.(plugin.DuplicateCNameErr); ok {
-			return nil, duplicateCNameResponse(cnameErr)
-		}
-
 		return nil, entryNotFoundResponse(path, err.Error())
 	}
 	return curEntry, nil
@@ -68,21 +65,23 @@ func getEntryFromRequest(r *http.Request) (plugin.Entry, string, *errorResponse)
 	if errResp != nil {
 		return nil, "", errResp
 	}
+	return getEntryFromPath(r.Context(), path)
+}
 
-	ctx := r.Context()
+// getEntryFromPath returns the entry at the given absolute path. It's used
+// by requests that operate on more than one entry.
+func getEntryFromPath(ctx context.Context, path string) (plugin.Entry, string, *errorResponse) {
 	trimmedPath, errResp := toWashPath(ctx, path)
 	if errResp != nil {
 		if errResp.body.Kind != apitypes.NonWashPath {
 			panic("Unexpected error from getWashPathFromFullPath")
 		}
 
-		// Local file/directory, so convert it to a Wash entry
-		//
-		// TODO: The code here means that the Wash server cannot be
-		// mounted on a remote machine. This is not an immediate issue,
-		// but it does mean that we'll need to re-evaluate this code once
-		// we get to the point where supporting remote Wash servers is
-		// desirable.
+		// Local file/directory, so convert it to a Wash entry. Remote
+		// daemons don't have access to the server's local files.
+		if isRemoteRequest(ctx) {
+			return nil, "", errResp
+		}
 		e, err := apifs.NewEntry(ctx, path)
 		if err != nil {
 			if os.IsNotExist(err) {
@@ -100,6 +99,9 @@ func getEntryFromRequest(r *http.Request) (plugin.Entry, string, *errorResponse)
 	registry := ctx.Value(pluginRegistryKey).(*plugin.Registry)
 	if trimmedPath == "" {
 		// Return the registry
+		if errResp := authorize(ctx, ""); errResp != nil {
+			return nil, "", errResp
+		}
 		return registry, path, nil
 	}
 
@@ -107,6 +109,9 @@ func getEntryFromRequest(r *http.Request) (plugin.Entry, string, *errorResponse)
 	segments := strings.Split(trimmedPath, "/")
 	pluginName := segments[0]
 	segments = segments[1:]
+	if errResp := authorize(ctx, pluginName); errResp != nil {
+		return nil, "", errResp
+	}
 
 	root, ok := registry.Plugins()[pluginName]
 	if !ok {
@@ -145,3 +150,29 @@ func getIntParam(u *url.URL, key string) (int, bool, *errorResponse) {
 	}
 	return 0, false, nil
 }
+
+// return is (n, found, err). Negative values are invalid.
+func getInt64Param(u *url.URL, key string) (int64, bool, *errorResponse) {
+	val := u.Query().Get(key)
+	if val != "" {
+		n, err := strconv.ParseInt(val, 10, 64)
+		if err != nil || n < 0 {
+			return 0, false, invalidIntParam(key, val)
+		}
+		return n, true, nil
+	}
+	return 0, false, nil
+}
+
+// return is (duration, found, err)
+func getDurationParam(u *url.URL, key string) (time.Duration, bool, *errorResponse) {
+	val := u.Query().Get(key)
+	if val != "" {
+		d, err := time.ParseDuration(val)
+		if err != nil || d < 0 {
+			return 0, false, invalidDurationParam(key, val)
+		}
+		return d, true, nil
+	}
+	return 0, false, nil
+}
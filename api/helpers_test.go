@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/puppetlabs/wash/plugin"
 	"github.com/stretchr/testify/mock"
@@ -172,6 +173,26 @@ func (suite *HelpersTestSuite) TestGetIntParam() {
 	}
 }
 
+func (suite *HelpersTestSuite) TestGetDurationParam() {
+	var u url.URL
+	u.RawQuery = ""
+	_, found, err := getDurationParam(&u, "param")
+	suite.Nil(err)
+	suite.False(found)
+
+	u.RawQuery = "param=1m30s"
+	val, found, err := getDurationParam(&u, "param")
+	suite.Nil(err)
+	suite.True(found)
+	suite.Equal(90*time.Second, val)
+
+	for _, query := range []string{"param=foo", "param=10", "param=-1s"} {
+		u.RawQuery = query
+		_, _, err = getDurationParam(&u, "param")
+		suite.Regexp(".*duration.*", err)
+	}
+}
+
 func TestHelpers(t *testing.T) {
 	suite.Run(t, new(HelpersTestSuite))
 }
@@ -0,0 +1,279 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/puppetlabs/wash/activity"
+	apitypes "github.com/puppetlabs/wash/api/types"
+)
+
+// IdempotencyWindow is how long the response to a request with an
+// Idempotency-Key header is remembered.
+const IdempotencyWindow = 10 * time.Minute
+
+// maxReplayedResponseSize is the largest response that's remembered so that it
+// can be replayed. A retry of a request whose response was larger isn't run
+// again; it fails instead.
+const maxReplayedResponseSize = 1024 * 1024
+
+// swagger:parameters deleteEntry executeCommand signalEntry copyEntry purgeTrashedEntry writeEntry
+//nolint:deadcode,unused
+type idempotencyKeyParam struct {
+	// deduplicates retries of the request. A retry with the same key within 10
+	// minutes of a successful request replays its response instead of running
+	// the request again.
+	//
+	// in: header
+	IdempotencyKey string `json:"Idempotency-Key"`
+}
+
+// idempotencyKeys remembers the responses to the requests that had an
+// Idempotency-Key header. Only successful responses are remembered, so a
+// request that failed can be retried with the same key.
+type idempotencyKeys struct {
+	mux      sync.Mutex
+	requests map[string]*idempotentRequest
+}
+
+type idempotentRequest struct {
+	// fingerprint identifies the request's method, URL and body so that a key
+	// that's reused for a different request is rejected.
+	fingerprint string
+	// done is closed once the request's finished.
+	done     chan struct{}
+	response *recordedResponse
+	expires  time.Time
+}
+
+func newIdempotencyKeys() *idempotencyKeys {
+	return &idempotencyKeys{
+		requests: make(map[string]*idempotentRequest),
+	}
+}
+
+// idempotent deduplicates the requests to h that have the same Idempotency-Key
+// header. Concurrent retries wait for the original request to finish.
+func idempotent(h handler) handler {
+	return handler{logOnly: h.logOnly, fn: func(w http.ResponseWriter, r *http.Request) *errorResponse {
+		key := r.Header.Get(apitypes.IdempotencyKeyHeader)
+		if key == "" {
+			return h.call(w, r)
+		}
+
+		fingerprint, err := fingerprintRequest(r)
+		if err != nil {
+			return badRequestResponse(fmt.Sprintf("Could not read the request body: %v", err))
+		}
+		if r.Body != nil {
+			// The server only closes the original body, so close its
+			// replacement, which may be a spooled temporary file.
+			defer r.Body.Close()
+		}
+
+		// Remote clients' keys are kept apart so that a client can't replay
+		// another client's response, which skips the handler's authorization,
+		// or make its requests fail by reusing its keys.
+		scopedKey := key
+		if identity, ok := remoteClient(r.Context()); ok {
+			scopedKey = identity + "/" + key
+		}
+
+		keys := r.Context().Value(idempotencyKeysKey).(*idempotencyKeys)
+		for {
+			req, isNew := keys.start(scopedKey, fingerprint)
+			if req.fingerprint != fingerprint {
+				return idempotencyKeyReusedResponse(key)
+			}
+			if isNew {
+				return keys.serve(w, r, scopedKey, req, h)
+			}
+
+			<-req.done
+			if req.response == nil {
+				// The original request failed, so it was forgotten. Try again.
+				continue
+			}
+			activity.Record(r.Context(), "API: Replaying the response for idempotency key %v", key)
+			if req.response.truncated {
+				return duplicateRequestResponse(key)
+			}
+			req.response.replay(w)
+			return nil
+		}
+	}}
+}
+
+// start returns the request with the given key. If there isn't one, then it
+// starts a new request and returns true.
+func (keys *idempotencyKeys) start(key string, fingerprint string) (*idempotentRequest, bool) {
+	keys.mux.Lock()
+	defer keys.mux.Unlock()
+
+	now := time.Now()
+	for k, req := range keys.requests {
+		if !req.expires.IsZero() && now.After(req.expires) {
+			delete(keys.requests, k)
+		}
+	}
+	if req, ok := keys.requests[key]; ok {
+		return req, false
+	}
+	req := &idempotentRequest{fingerprint: fingerprint, done: make(chan struct{})}
+	keys.requests[key] = req
+	return req, true
+}
+
+// serve runs the request and records its response. Failed requests are
+// forgotten.
+func (keys *idempotencyKeys) serve(w http.ResponseWriter, r *http.Request, key string, req *idempotentRequest, h handler) (err *errorResponse) {
+	recorder := &responseRecorder{ResponseWriter: w}
+	defer func() {
+		keys.mux.Lock()
+		if err == nil && recorder.succeeded() {
+			req.response = &recordedResponse{
+				status:      recorder.status,
+				contentType: w.Header().Get("Content-Type"),
+				body:        recorder.body.Bytes(),
+				truncated:   recorder.truncated,
+			}
+			req.expires = time.Now().Add(IdempotencyWindow)
+		} else {
+			delete(keys.requests, key)
+		}
+		keys.mux.Unlock()
+		close(req.done)
+	}()
+	return h.call(recorder, r)
+}
+
+// maxBufferedBodySize is the largest request body that's fingerprinted in
+// memory. Larger bodies, like a big file's content sent to /fs/write, are
+// spooled to a temporary file instead.
+var maxBufferedBodySize int64 = 1024 * 1024
+
+// fingerprintRequest hashes the request's method, URL and body. The body's
+// replaced so that the handler can still read it.
+func fingerprintRequest(r *http.Request) (string, error) {
+	hash := sha256.New()
+	_, _ = io.WriteString(hash, r.Method+" "+r.URL.String()+"\n")
+	if r.Body != nil {
+		var buf bytes.Buffer
+		n, err := io.CopyN(io.MultiWriter(&buf, hash), r.Body, maxBufferedBodySize+1)
+		if err != nil && err != io.EOF {
+			return "", err
+		}
+		if n <= maxBufferedBodySize {
+			r.Body = ioutil.NopCloser(&buf)
+		} else {
+			spooled, err := spoolBody(&buf, r.Body, hash)
+			if err != nil {
+				return "", err
+			}
+			r.Body = spooled
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// spoolBody writes the body to a temporary file, hashing it as it's written,
+// and returns the file so that it can be read again. The file's removed once
+// it's closed. head is the part of the body that's already been read.
+func spoolBody(head io.Reader, body io.Reader, hash io.Writer) (io.ReadCloser, error) {
+	f, err := ioutil.TempFile("", "wash-request-body")
+	if err != nil {
+		return nil, err
+	}
+	spooled := &spooledBody{File: f}
+	if _, err := io.Copy(io.MultiWriter(f, hash), io.MultiReader(head, body)); err != nil {
+		spooled.Close()
+		return nil, err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		spooled.Close()
+		return nil, err
+	}
+	return spooled, nil
+}
+
+type spooledBody struct {
+	*os.File
+}
+
+func (b *spooledBody) Close() error {
+	err := b.File.Close()
+	if rmErr := os.Remove(b.Name()); err == nil {
+		err = rmErr
+	}
+	return err
+}
+
+// responseRecorder records a response as it's written. It supports flushing so
+// that streamed responses like exec's are still streamed.
+type responseRecorder struct {
+	http.ResponseWriter
+	status    int
+	body      bytes.Buffer
+	truncated bool
+}
+
+func (rr *responseRecorder) WriteHeader(statusCode int) {
+	if rr.status == 0 {
+		rr.status = statusCode
+	}
+	rr.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (rr *responseRecorder) Write(b []byte) (int, error) {
+	if rr.status == 0 {
+		rr.status = http.StatusOK
+	}
+	if !rr.truncated {
+		if rr.body.Len()+len(b) > maxReplayedResponseSize {
+			rr.truncated = true
+			rr.body.Reset()
+		} else {
+			rr.body.Write(b)
+		}
+	}
+	return rr.ResponseWriter.Write(b)
+}
+
+func (rr *responseRecorder) Flush() {
+	if f, ok := rr.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (rr *responseRecorder) succeeded() bool {
+	// Handlers that don't write anything succeed with a 200.
+	return rr.status == 0 || (rr.status >= 200 && rr.status < 300)
+}
+
+type recordedResponse struct {
+	status      int
+	contentType string
+	body        []byte
+	truncated   bool
+}
+
+func (resp *recordedResponse) replay(w http.ResponseWriter) {
+	if resp.contentType != "" {
+		w.Header().Set("Content-Type", resp.contentType)
+	}
+	w.Header().Set(apitypes.IdempotentReplayedHeader, "true")
+	status := resp.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.WriteHeader(status)
+	_, _ = w.Write(resp.body)
+}
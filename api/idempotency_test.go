@@ -0,0 +1,104 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	apitypes "github.com/puppetlabs/wash/api/types"
+	"github.com/stretchr/testify/suite"
+)
+
+type IdempotencyTestSuite struct {
+	suite.Suite
+	keys  *idempotencyKeys
+	calls int
+}
+
+func (suite *IdempotencyTestSuite) SetupTest() {
+	suite.keys = newIdempotencyKeys()
+	suite.calls = 0
+}
+
+// handler returns a handler that fails with the given status, or that succeeds
+// with the number of times it's been called if the status is 0.
+func (suite *IdempotencyTestSuite) handler(status int) handler {
+	return idempotent(handler{logOnly: true, fn: func(w http.ResponseWriter, r *http.Request) *errorResponse {
+		suite.calls++
+		if status != 0 {
+			return &errorResponse{status, newErrorObj(apitypes.UnknownError, "failed", nil)}
+		}
+		fmt.Fprintf(w, "%v", suite.calls)
+		return nil
+	}})
+}
+
+func (suite *IdempotencyTestSuite) serve(h handler, key string, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodPost, "/fs/exec?path=/foo", strings.NewReader(body))
+	if key != "" {
+		r.Header.Set(apitypes.IdempotencyKeyHeader, key)
+	}
+	r = r.WithContext(context.WithValue(r.Context(), idempotencyKeysKey, suite.keys))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func (suite *IdempotencyTestSuite) TestWithoutKey_RunsEveryRequest() {
+	h := suite.handler(0)
+	suite.Equal("1", suite.serve(h, "", "{}").Body.String())
+	suite.Equal("2", suite.serve(h, "", "{}").Body.String())
+}
+
+func (suite *IdempotencyTestSuite) TestRetry_ReplaysResponse() {
+	h := suite.handler(0)
+	suite.Equal("1", suite.serve(h, "a", "{}").Body.String())
+
+	w := suite.serve(h, "a", "{}")
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Equal("1", w.Body.String())
+	suite.Equal("true", w.Header().Get(apitypes.IdempotentReplayedHeader))
+	suite.Equal(1, suite.calls)
+
+	suite.Equal("2", suite.serve(h, "b", "{}").Body.String())
+}
+
+func (suite *IdempotencyTestSuite) TestReusedKeyForDifferentRequest_Fails() {
+	h := suite.handler(0)
+	suite.serve(h, "a", "{}")
+
+	w := suite.serve(h, "a", `{"cmd": "rm"}`)
+	suite.Equal(http.StatusUnprocessableEntity, w.Code)
+	suite.Contains(w.Body.String(), apitypes.IdempotencyKeyReused)
+	suite.Equal(1, suite.calls)
+}
+
+func (suite *IdempotencyTestSuite) TestFailedRequest_CanBeRetried() {
+	suite.Equal(http.StatusInternalServerError, suite.serve(suite.handler(http.StatusInternalServerError), "a", "{}").Code)
+	suite.Equal("2", suite.serve(suite.handler(0), "a", "{}").Body.String())
+}
+
+func (suite *IdempotencyTestSuite) TestLargeBody_IsSpooled() {
+	defer func(size int64) { maxBufferedBodySize = size }(maxBufferedBodySize)
+	maxBufferedBodySize = 4
+
+	h := idempotent(handler{logOnly: true, fn: func(w http.ResponseWriter, r *http.Request) *errorResponse {
+		suite.calls++
+		body, err := ioutil.ReadAll(r.Body)
+		suite.NoError(err)
+		fmt.Fprintf(w, "%v %s", suite.calls, body)
+		return nil
+	}})
+	suite.Equal("1 hello world", suite.serve(h, "a", "hello world").Body.String())
+	suite.Equal("1 hello world", suite.serve(h, "a", "hello world").Body.String())
+	suite.Equal(http.StatusUnprocessableEntity, suite.serve(h, "a", "hello there").Code)
+	suite.Equal("2 hey", suite.serve(h, "b", "hey").Body.String())
+}
+
+func TestIdempotency(t *testing.T) {
+	suite.Run(t, new(IdempotencyTestSuite))
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/puppetlabs/wash/activity"
+	apitypes "github.com/puppetlabs/wash/api/types"
+	"github.com/puppetlabs/wash/plugin"
+	log "github.com/sirupsen/logrus"
+)
+
+// swagger:route GET /journal/levels journal getJournalLevels
+//
+// Get the plugins' journal levels
+//
+// Get the journal level of every loaded plugin. Entries that a plugin records
+// below its level are dropped from the journal.
+//
+//     Produces:
+//     - application/json
+//
+//     Schemes: http
+//
+//     Responses:
+//       200: JournalLevelsResponse
+//       500: errorResp
+var journalLevelsHandler = handler{logOnly: true, fn: func(w http.ResponseWriter, r *http.Request) *errorResponse {
+	registry := r.Context().Value(pluginRegistryKey).(*plugin.Registry)
+	levels := make(map[string]string)
+	for name := range registry.Plugins() {
+		levels[name] = activity.PluginLevel(name).String()
+	}
+
+	if err := json.NewEncoder(w).Encode(levels); err != nil {
+		return unknownErrorResponse(fmt.Errorf("Could not marshal journal levels: %v", err))
+	}
+	return nil
+}}
+
+// swagger:route PUT /journal/levels journal setJournalLevel
+//
+// Set a plugin's journal level
+//
+// Raises or lowers the journal level of the specified plugin. The change takes
+// effect immediately, so it can be used to debug a single plugin without
+// restarting the server.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Schemes: http
+//
+//     Responses:
+//       200:
+//       400: errorResp
+//       404: errorResp
+//       500: errorResp
+var setJournalLevelHandler = handler{fn: func(w http.ResponseWriter, r *http.Request) *errorResponse {
+	ctx := r.Context()
+	if r.Body == nil {
+		return badRequestResponse("Please send a JSON request body")
+	}
+
+	var body apitypes.JournalLevelBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return badRequestResponse(err.Error())
+	}
+
+	registry := ctx.Value(pluginRegistryKey).(*plugin.Registry)
+	if _, ok := registry.Plugins()[body.Plugin]; !ok {
+		return pluginDoesNotExistResponse(body.Plugin)
+	}
+
+	if body.Level == "" {
+		activity.ResetPluginLevel(body.Plugin)
+	} else {
+		level, err := log.ParseLevel(body.Level)
+		if err != nil {
+			return badRequestResponse(err.Error())
+		}
+		activity.SetPluginLevel(body.Plugin, level)
+	}
+
+	activity.Record(ctx, "API: Journal level %v %v", body.Plugin, activity.PluginLevel(body.Plugin))
+	return nil
+}}
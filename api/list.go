@@ -1,6 +1,8 @@
 package api
 
 import (
+	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -18,15 +20,40 @@ type entryList struct {
 	Entries []apitypes.Entry
 }
 
+// swagger:parameters listEntries
+//nolint:deadcode,unused
+type listParams struct {
+	// the maximum number of entries to return. The rest of the entries are
+	// listed by passing the response's Next-Cursor header as the cursor.
+	// Defaults to every entry.
+	//
+	// in: query
+	Limit int
+	// the cursor of the page to return. Defaults to the first page.
+	//
+	// in: query
+	Cursor string
+	// stream the entries as newline-delimited JSON (application/x-ndjson)
+	// instead of as a JSON array. Defaults to false.
+	//
+	// in: query
+	Stream bool
+}
+
 // swagger:route GET /fs/list list listEntries
 //
 // Lists children of a path
 //
 // Returns a list of Entry objects describing children of the given path.
-// The "metadata" key is set to the partial metadata.
+// The "metadata" key is set to the partial metadata. Entries are sorted by
+// name. Large listings can be paginated with the limit and cursor parameters,
+// and streamed with the stream parameter. Paginating only reduces the size of
+// each response: each page still lists the whole directory, which is usually
+// cached, and only the page's entries are described.
 //
 //     Produces:
 //     - application/json
+//     - application/x-ndjson
 //
 //     Schemes: http
 //
@@ -42,6 +69,25 @@ var listHandler = handler{fn: func(w http.ResponseWriter, r *http.Request) *erro
 		return errResp
 	}
 
+	limit, _, errResp := getIntParam(r.URL, "limit")
+	if errResp != nil {
+		return errResp
+	}
+	if limit < 0 {
+		return invalidIntParam("limit", r.URL.Query().Get("limit"))
+	}
+	var after listCursor
+	if cursor := r.URL.Query().Get("cursor"); cursor != "" {
+		var err error
+		if after, err = decodeListCursor(cursor); err != nil {
+			return badRequestResponse(fmt.Sprintf("Invalid cursor %v", cursor))
+		}
+	}
+	stream, errResp := getBoolParam(r.URL, "stream")
+	if errResp != nil {
+		return errResp
+	}
+
 	if !plugin.ListAction().IsSupportedOn(entry) {
 		return unsupportedActionResponse(path, plugin.ListAction())
 	}
@@ -49,27 +95,108 @@ var listHandler = handler{fn: func(w http.ResponseWriter, r *http.Request) *erro
 	parent := entry.(plugin.Parent)
 	entries, err := plugin.ListWithAnalytics(ctx, parent)
 	if err != nil {
-		if cnameErr, ok := err.(plugin.DuplicateCNameErr); ok {
-			return duplicateCNameResponse(cnameErr)
-		}
-
 		return erroredActionResponse(path, plugin.ListAction(), err.Error())
 	}
 
-	result := make([]apitypes.Entry, 0, entries.Len())
-	entries.Range(func(_ string, entry plugin.Entry) bool {
-		apiEntry := apitypes.NewEntry(entry)
-		apiEntry.Path = path + "/" + apiEntry.CName
-		result = append(result, apiEntry)
+	page := make([]listedEntry, 0, entries.Len())
+	entries.Range(func(cname string, entry plugin.Entry) bool {
+		page = append(page, listedEntry{cursor: listCursor{name: plugin.Name(entry), cname: cname}, entry: entry})
 		return true
 	})
-	// Sort entries so they have a deterministic order.
-	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	// Sort entries so they have a deterministic order. Names aren't unique, so
+	// ties are broken by cname to give pages a total order.
+	sort.Slice(page, func(i, j int) bool { return page[i].cursor.less(page[j].cursor) })
+
+	// The page is found before its entries are described because describing
+	// an entry computes its attributes and metadata.
+	if after != (listCursor{}) {
+		start := sort.Search(len(page), func(i int) bool { return after.less(page[i].cursor) })
+		page = page[start:]
+	}
+	if limit > 0 && len(page) > limit {
+		page = page[:limit]
+		w.Header().Set(apitypes.ListNextCursorHeader, page[limit-1].cursor.encode())
+	}
+	result := make([]apitypes.Entry, len(page))
+	for i, listed := range page {
+		result[i] = apitypes.NewEntry(listed.entry)
+		result[i].Path = path + "/" + result[i].CName
+	}
 	activity.Record(ctx, "API: List %v %v items", path, len(result))
 
+	if stream {
+		return streamEntries(ctx, w, path, result)
+	}
 	jsonEncoder := json.NewEncoder(w)
 	if err = jsonEncoder.Encode(result); err != nil {
 		return unknownErrorResponse(fmt.Errorf("Could not marshal list results for %v: %v", path, err))
 	}
 	return nil
 }}
+
+// streamEntries writes an entry on each line, flushing periodically so that
+// clients can start processing a large listing before it's all been encoded.
+func streamEntries(ctx context.Context, w http.ResponseWriter, path string, entries []apitypes.Entry) *errorResponse {
+	const flushEvery = 100
+	f, ok := w.(flushableWriter)
+	if !ok {
+		return unknownErrorResponse(fmt.Errorf("Cannot stream list results, response handler does not support flushing"))
+	}
+	w.Header().Set("Content-Type", apitypes.NDJSONContentType)
+	jsonEncoder := json.NewEncoder(f)
+	for i, entry := range entries {
+		if err := jsonEncoder.Encode(entry); err != nil {
+			// The status has been sent, so the client can only tell that the
+			// stream was cut short.
+			activity.Warnf(ctx, "API: Could not stream list results for %v: %v", path, err)
+			return nil
+		}
+		if (i+1)%flushEvery == 0 {
+			f.Flush()
+		}
+	}
+	return nil
+}
+
+// listCursor is the position of a page in a listing. It's the name and cname
+// of the previous page's last entry.
+type listCursor struct {
+	name  string
+	cname string
+}
+
+// listedEntry is an entry in a listing and its position.
+type listedEntry struct {
+	cursor listCursor
+	entry  plugin.Entry
+}
+
+func (c listCursor) less(other listCursor) bool {
+	if c.name != other.name {
+		return c.name < other.name
+	}
+	return c.cname < other.cname
+}
+
+// encode returns the cursor as an opaque string. It's the base64 of a JSON
+// array of the name and cname so that names can contain any character.
+func (c listCursor) encode() string {
+	// Marshalling strings can't fail.
+	b, _ := json.Marshal([]string{c.name, c.cname})
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeListCursor(s string) (listCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return listCursor{}, err
+	}
+	var parts []string
+	if err := json.Unmarshal(b, &parts); err != nil {
+		return listCursor{}, err
+	}
+	if len(parts) != 2 {
+		return listCursor{}, fmt.Errorf("the cursor has %v parts instead of 2", len(parts))
+	}
+	return listCursor{name: parts[0], cname: parts[1]}, nil
+}
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	apitypes "github.com/puppetlabs/wash/api/types"
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type listTestsEntry struct {
+	plugin.EntryBase
+}
+
+func (e *listTestsEntry) Schema() *plugin.EntrySchema {
+	return nil
+}
+
+func listRequest(t *testing.T, params url.Values) *httptest.ResponseRecorder {
+	plugin.SetTestCache(newMockCache())
+	defer plugin.UnsetTestCache()
+
+	root := &mockRoot{EntryBase: plugin.NewEntry("mine")}
+	root.SetTestID("/mine")
+	var entries []plugin.Entry
+	for _, name := range []string{"d", "b", "e", "a", "c"} {
+		entries = append(entries, &listTestsEntry{EntryBase: plugin.NewEntry(name)})
+	}
+	root.On("List", mock.Anything).Return(entries, nil)
+
+	reg := plugin.NewRegistry()
+	require.NoError(t, reg.RegisterPlugin(root, map[string]interface{}{}))
+	ctx := context.WithValue(context.Background(), pluginRegistryKey, reg)
+	ctx = context.WithValue(ctx, mountpointKey, "/mnt")
+
+	params.Set("path", "/mnt/mine")
+	r := httptest.NewRequest(http.MethodGet, "/fs/list?"+params.Encode(), nil).WithContext(ctx)
+	w := httptest.NewRecorder()
+	listHandler.ServeHTTP(w, r)
+	return w
+}
+
+func names(entries []apitypes.Entry) []string {
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name)
+	}
+	return names
+}
+
+func TestListHandler_Paginates(t *testing.T) {
+	var pages [][]string
+	cursor := ""
+	for i := 0; i < 5; i++ {
+		w := listRequest(t, url.Values{"limit": []string{"2"}, "cursor": []string{cursor}})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var entries []apitypes.Entry
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &entries))
+		pages = append(pages, names(entries))
+		if cursor = w.Header().Get(apitypes.ListNextCursorHeader); cursor == "" {
+			break
+		}
+	}
+	assert.Equal(t, [][]string{{"a", "b"}, {"c", "d"}, {"e"}}, pages)
+
+	w := listRequest(t, url.Values{})
+	var entries []apitypes.Entry
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &entries))
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, names(entries))
+	assert.Empty(t, w.Header().Get(apitypes.ListNextCursorHeader))
+}
+
+func TestListHandler_ReturnsErrorForInvalidParams(t *testing.T) {
+	w := listRequest(t, url.Values{"limit": []string{"-1"}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = listRequest(t, url.Values{"cursor": []string{"!!"}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "Invalid cursor !!")
+}
+
+func TestListCursor_RoundTrips(t *testing.T) {
+	for _, c := range []listCursor{
+		{name: "a", cname: "a"},
+		{name: "a\x00b", cname: "a#b"},
+		{name: "", cname: "\x00"},
+		{name: "a/b", cname: "a%2Fb"},
+	} {
+		decoded, err := decodeListCursor(c.encode())
+		if assert.NoError(t, err) {
+			assert.Equal(t, c, decoded)
+		}
+	}
+
+	_, err := decodeListCursor(base64.RawURLEncoding.EncodeToString([]byte(`["a"]`)))
+	assert.Error(t, err)
+}
+
+func TestListHandler_Streams(t *testing.T) {
+	w := listRequest(t, url.Values{"stream": []string{"true"}, "limit": []string{"3"}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, apitypes.NDJSONContentType, w.Header().Get("Content-Type"))
+	assert.NotEmpty(t, w.Header().Get(apitypes.ListNextCursorHeader))
+
+	var streamed []string
+	scanner := bufio.NewScanner(w.Body)
+	for scanner.Scan() {
+		var entry apitypes.Entry
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		streamed = append(streamed, entry.Name)
+	}
+	assert.Equal(t, []string{"a", "b", "c"}, streamed)
+}
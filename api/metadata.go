@@ -37,7 +37,7 @@ var metadataHandler = handler{fn: func(w http.ResponseWriter, r *http.Request) *
 		return errResp
 	}
 
-	metadata, err := plugin.Metadata(ctx, entry)
+	metadata, err := plugin.MetadataWithCacheInfo(ctx, entry)
 
 	if err != nil {
 		return unknownErrorResponse(err)
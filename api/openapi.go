@@ -0,0 +1,31 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/puppetlabs/wash/api/openapi"
+)
+
+// swagger:route GET /swagger.json openapi getOpenAPI
+//
+// The API's OpenAPI document
+//
+// Returns the OpenAPI 3 document that describes the API's endpoints, so that
+// tools can generate clients for it.
+//
+//     Produces:
+//     - application/json
+//
+//     Schemes: http
+//
+//     Responses:
+//       200:
+var openapiHandler = handler{logOnly: true, fn: func(w http.ResponseWriter, r *http.Request) *errorResponse {
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := io.WriteString(w, openapi.JSON); err != nil {
+		return unknownErrorResponse(fmt.Errorf("Could not write the OpenAPI document: %v", err))
+	}
+	return nil
+}}
@@ -0,0 +1,35 @@
+//go:build ignore
+// +build ignore
+
+// gen generates specJSON.go from the api package's annotations.
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/puppetlabs/wash/api/openapi"
+)
+
+func main() {
+	spec, err := openapi.Generate("..")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "could not generate the OpenAPI document: %v\n", err)
+		os.Exit(1)
+	}
+	// Backticks can't be in a raw string, so they're concatenated.
+	literal := "`" + strings.Replace(string(spec), "`", "` + \"`\" + `", -1) + "`"
+	src := fmt.Sprintf(`// Code generated by gen.go from the api package. DO NOT EDIT.
+
+package openapi
+
+// JSON is the OpenAPI document of the API.
+const JSON = %v
+`, literal)
+	if err := ioutil.WriteFile("specJSON.go", []byte(src), 0644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
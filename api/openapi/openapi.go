@@ -0,0 +1,513 @@
+// Package openapi generates the OpenAPI 3 document of Wash's HTTP API from
+// the swagger annotations in the api package, so that the daemon can serve it
+// at /swagger.json. Request and response bodies are described by reflecting
+// on the Go types that the API encodes.
+package openapi
+
+//go:generate go run gen.go
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Generate returns the OpenAPI document of the API whose source is in apiDir.
+// The response types in apiDir/types are also read.
+func Generate(apiDir string) ([]byte, error) {
+	g := &generator{
+		paths:      make(map[string]map[string]*operation),
+		params:     make(map[string][]interface{}),
+		bodies:     make(map[string]interface{}),
+		responses:  make(map[string]interface{}),
+		schemas:    make(map[string]interface{}),
+		schemaRefs: make(map[reflect.Type]string),
+	}
+	for _, dir := range []struct {
+		path string
+		pkg  string
+	}{{apiDir, "api"}, {filepath.Join(apiDir, "types"), "apitypes"}} {
+		if err := g.parseDir(dir.path, dir.pkg); err != nil {
+			return nil, err
+		}
+	}
+	doc, err := g.document()
+	if err != nil {
+		return nil, err
+	}
+	content, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(content, '\n'), nil
+}
+
+type operation struct {
+	method      string
+	path        string
+	id          string
+	tags        []string
+	summary     string
+	description string
+	consumes    []string
+	produces    []string
+	responses   map[string]string
+}
+
+type generator struct {
+	paths map[string]map[string]*operation
+	// params and bodies are an operation's parameters and request body
+	params map[string][]interface{}
+	bodies map[string]interface{}
+	// responses are the response objects, keyed by their name
+	responses map[string]interface{}
+	// schemas are the named schemas of the Go types. schemaRefs maps a type
+	// to its schema's name.
+	schemas    map[string]interface{}
+	schemaRefs map[reflect.Type]string
+	// typeSpecs are the types of the package that's being parsed
+	typeSpecs map[string]*ast.TypeSpec
+}
+
+func (g *generator) parseDir(dir string, pkg string) error {
+	fset := token.NewFileSet()
+	matches, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(matches)
+	files := make(map[string]*ast.File)
+	var names []string
+	// Embedded parameter structs can be declared in any of the package's
+	// files, so they're all parsed first.
+	g.typeSpecs = make(map[string]*ast.TypeSpec)
+	for _, path := range matches {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+		files[path] = f
+		names = append(names, path)
+		for _, decl := range f.Decls {
+			if decl, ok := decl.(*ast.GenDecl); ok && decl.Tok == token.TYPE {
+				for _, spec := range decl.Specs {
+					spec := spec.(*ast.TypeSpec)
+					g.typeSpecs[spec.Name.Name] = spec
+				}
+			}
+		}
+	}
+
+	for _, path := range names {
+		f := files[path]
+		for _, cg := range f.Comments {
+			if err := g.parseRoute(cg.Text()); err != nil {
+				return fmt.Errorf("%v: %v", filepath.Base(path), err)
+			}
+		}
+		for _, decl := range f.Decls {
+			decl, ok := decl.(*ast.GenDecl)
+			if !ok || decl.Tok != token.TYPE || decl.Doc == nil || len(decl.Specs) != 1 {
+				continue
+			}
+			spec := decl.Specs[0].(*ast.TypeSpec)
+			if err := g.parseType(pkg, spec, decl.Doc.Text()); err != nil {
+				return fmt.Errorf("%v: %v: %v", filepath.Base(path), spec.Name.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+const ndjson = "application/x-ndjson"
+
+var sectionRegex = regexp.MustCompile(`^(Consumes|Produces|Schemes|Responses):`)
+
+// parseRoute parses a swagger:route annotation, which is formatted like
+//
+//	swagger:route METHOD PATH [TAGS...] ID
+//
+//	Summary
+//
+//	Description
+//
+//	    Produces:
+//	    - application/json
+//
+//	    Responses:
+//	      200: responseName
+func (g *generator) parseRoute(text string) error {
+	lines := strings.Split(text, "\n")
+	if len(lines) == 0 || !strings.HasPrefix(lines[0], "swagger:route ") {
+		return nil
+	}
+	fields := strings.Fields(lines[0])[1:]
+	if len(fields) < 2 {
+		return fmt.Errorf("malformed annotation %q", lines[0])
+	}
+	op := &operation{method: strings.ToLower(fields[0]), path: fields[1], responses: make(map[string]string)}
+	if rest := fields[2:]; len(rest) > 0 {
+		op.tags, op.id = rest[:len(rest)-1], rest[len(rest)-1]
+	}
+
+	var paragraphs []string
+	var paragraph []string
+	section := ""
+	for _, line := range lines[1:] {
+		trimmed := strings.TrimSpace(line)
+		if m := sectionRegex.FindStringSubmatch(trimmed); m != nil {
+			section = m[1]
+			continue
+		}
+		switch {
+		case section == "" && trimmed == "":
+			if len(paragraph) > 0 {
+				paragraphs = append(paragraphs, strings.Join(paragraph, " "))
+				paragraph = nil
+			}
+		case section == "":
+			paragraph = append(paragraph, trimmed)
+		case trimmed == "":
+			section = ""
+		case section == "Consumes" || section == "Produces":
+			mediaType := strings.TrimSpace(strings.TrimPrefix(trimmed, "-"))
+			if section == "Consumes" {
+				op.consumes = append(op.consumes, mediaType)
+			} else {
+				op.produces = append(op.produces, mediaType)
+			}
+		case section == "Responses":
+			parts := strings.SplitN(trimmed, ":", 2)
+			if len(parts) != 2 {
+				return fmt.Errorf("%v: malformed response %q", op.path, trimmed)
+			}
+			op.responses[parts[0]] = strings.TrimSpace(parts[1])
+		}
+	}
+	if len(paragraph) > 0 {
+		paragraphs = append(paragraphs, strings.Join(paragraph, " "))
+	}
+	if len(paragraphs) > 0 {
+		op.summary = paragraphs[0]
+		op.description = strings.Join(paragraphs[1:], "\n\n")
+	}
+
+	if g.paths[op.path] == nil {
+		g.paths[op.path] = make(map[string]*operation)
+	}
+	g.paths[op.path][op.method] = op
+	return nil
+}
+
+func (g *generator) parseType(pkg string, spec *ast.TypeSpec, doc string) error {
+	for _, line := range strings.Split(doc, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "swagger:parameters":
+			return g.parseParameters(pkg, spec, fields[1:])
+		case "swagger:response":
+			return g.parseResponse(pkg, spec)
+		}
+	}
+	return nil
+}
+
+func (g *generator) parseParameters(pkg string, spec *ast.TypeSpec, ids []string) error {
+	params, body, err := g.paramsOf(pkg, spec.Type)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		g.params[id] = append(g.params[id], params...)
+		if body != nil {
+			g.bodies[id] = body
+		}
+	}
+	return nil
+}
+
+// paramsOf returns the parameters and the request body of a swagger:parameters
+// struct. Embedded structs are the api package's shared parameter structs.
+func (g *generator) paramsOf(pkg string, expr ast.Expr) ([]interface{}, interface{}, error) {
+	st, ok := expr.(*ast.StructType)
+	if !ok {
+		return nil, nil, fmt.Errorf("swagger:parameters must annotate a struct")
+	}
+	var params []interface{}
+	var body interface{}
+	for _, field := range st.Fields.List {
+		if len(field.Names) == 0 {
+			ident, ok := field.Type.(*ast.Ident)
+			if !ok || g.typeSpecs[ident.Name] == nil {
+				return nil, nil, fmt.Errorf("embedded fields must be structs in the same package")
+			}
+			embedded, embeddedBody, err := g.paramsOf(pkg, g.typeSpecs[ident.Name].Type)
+			if err != nil {
+				return nil, nil, err
+			}
+			params = append(params, embedded...)
+			if embeddedBody != nil {
+				body = embeddedBody
+			}
+			continue
+		}
+		in, description := parseFieldDoc(field.Doc)
+		schema, err := g.schemaOfExpr(pkg, field.Type)
+		if err != nil {
+			return nil, nil, err
+		}
+		if in == "body" {
+			body = schema
+			continue
+		}
+		if in == "" {
+			return nil, nil, fmt.Errorf("%v is missing an in: annotation", field.Names[0].Name)
+		}
+		param := map[string]interface{}{
+			"name":   paramName(field),
+			"in":     in,
+			"schema": schema,
+		}
+		if description != "" {
+			param["description"] = description
+		}
+		if in == "path" {
+			param["required"] = true
+		}
+		params = append(params, param)
+	}
+	return params, body, nil
+}
+
+// paramName returns the field's json tag, or its lowercased name.
+func paramName(field *ast.Field) string {
+	if field.Tag != nil {
+		tag := reflect.StructTag(strings.Trim(field.Tag.Value, "`"))
+		if name := strings.Split(tag.Get("json"), ",")[0]; name != "" {
+			return name
+		}
+	}
+	return strings.ToLower(field.Names[0].Name)
+}
+
+func parseFieldDoc(doc *ast.CommentGroup) (in string, description string) {
+	if doc == nil {
+		return "", ""
+	}
+	var lines []string
+	for _, line := range strings.Split(doc.Text(), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "in:") {
+			in = strings.TrimSpace(strings.TrimPrefix(line, "in:"))
+		} else if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return in, strings.Join(lines, " ")
+}
+
+// parseResponse parses a swagger:response struct. Its body is its "in: body"
+// or Body field, or its only field. Structs without one are their own body.
+func (g *generator) parseResponse(pkg string, spec *ast.TypeSpec) error {
+	name := spec.Name.Name
+	var schema interface{}
+	if st, ok := spec.Type.(*ast.StructType); ok {
+		for _, field := range st.Fields.List {
+			in, _ := parseFieldDoc(field.Doc)
+			isBody := in == "body" || (len(field.Names) == 1 && field.Names[0].Name == "Body")
+			// A struct with a single field, like entryMetadata, wraps its body.
+			isBody = isBody || (len(st.Fields.List) == 1 && len(field.Names) == 1 && in == "")
+			if isBody {
+				var err error
+				if schema, err = g.schemaOfExpr(pkg, field.Type); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	if schema == nil {
+		var err error
+		if schema, err = g.schemaOfExpr(pkg, spec.Name); err != nil {
+			return err
+		}
+	}
+	g.responses[name] = schema
+	return nil
+}
+
+// schemaOfExpr returns the schema of a Go type expression. Named types are
+// looked up in the registry of types.
+func (g *generator) schemaOfExpr(pkg string, expr ast.Expr) (interface{}, error) {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		if schema, ok := basicSchema(expr.Name); ok {
+			return schema, nil
+		}
+		return g.schemaOfName(pkg + "." + expr.Name)
+	case *ast.SelectorExpr:
+		return g.schemaOfName(expr.X.(*ast.Ident).Name + "." + expr.Sel.Name)
+	case *ast.StarExpr:
+		return g.schemaOfExpr(pkg, expr.X)
+	case *ast.ArrayType:
+		items, err := g.schemaOfExpr(pkg, expr.Elt)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"type": "array", "items": items}, nil
+	case *ast.MapType:
+		values, err := g.schemaOfExpr(pkg, expr.Value)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"type": "object", "additionalProperties": values}, nil
+	case *ast.InterfaceType:
+		return map[string]interface{}{}, nil
+	case *ast.StructType:
+		// Anonymous structs that embed a type, like errorResp's body.
+		if len(expr.Fields.List) == 1 && len(expr.Fields.List[0].Names) == 0 {
+			return g.schemaOfExpr(pkg, expr.Fields.List[0].Type)
+		}
+	}
+	return nil, fmt.Errorf("unsupported type %T", expr)
+}
+
+func (g *generator) schemaOfName(name string) (interface{}, error) {
+	t, ok := types[name]
+	if !ok {
+		return nil, fmt.Errorf("%v isn't in the openapi package's registry of types", name)
+	}
+	return g.schemaOf(t), nil
+}
+
+func basicSchema(name string) (map[string]interface{}, bool) {
+	switch name {
+	case "string":
+		return map[string]interface{}{"type": "string"}, true
+	case "bool":
+		return map[string]interface{}{"type": "boolean"}, true
+	case "int", "int32", "uint", "uint16", "uint32":
+		return map[string]interface{}{"type": "integer"}, true
+	case "int64", "uint64":
+		return map[string]interface{}{"type": "integer", "format": "int64"}, true
+	case "float64":
+		return map[string]interface{}{"type": "number"}, true
+	}
+	return nil, false
+}
+
+func (g *generator) document() (map[string]interface{}, error) {
+	paths := make(map[string]interface{})
+	for path, ops := range g.paths {
+		item := make(map[string]interface{})
+		for method, op := range ops {
+			o, err := g.operationObject(op)
+			if err != nil {
+				return nil, err
+			}
+			item[method] = o
+		}
+		paths[path] = item
+	}
+	responses := make(map[string]interface{})
+	for name, schema := range g.responses {
+		responses[name] = map[string]interface{}{
+			"description": name,
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{"schema": schema},
+			},
+		}
+	}
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":       "Wash API",
+			"version":     "1",
+			"description": "The API of the Wash daemon. It's served on the daemon's UNIX socket, and the /fs endpoints are also served on the api.listen TCP address.",
+		},
+		"servers": []interface{}{map[string]interface{}{"url": "http://localhost"}},
+		"paths":   paths,
+		"components": map[string]interface{}{
+			"schemas":   g.schemas,
+			"responses": responses,
+		},
+	}, nil
+}
+
+func (g *generator) operationObject(op *operation) (map[string]interface{}, error) {
+	o := map[string]interface{}{}
+	if op.id != "" {
+		o["operationId"] = op.id
+	}
+	if len(op.tags) > 0 {
+		o["tags"] = op.tags
+	}
+	if op.summary != "" {
+		o["summary"] = op.summary
+	}
+	if op.description != "" {
+		o["description"] = op.description
+	}
+	if params := g.params[op.id]; len(params) > 0 && op.id != "" {
+		o["parameters"] = params
+	}
+	if body, ok := g.bodies[op.id]; ok && op.id != "" {
+		content := make(map[string]interface{})
+		consumes := op.consumes
+		if len(consumes) == 0 {
+			consumes = []string{"application/json"}
+		}
+		for _, mediaType := range consumes {
+			content[mediaType] = map[string]interface{}{"schema": body}
+		}
+		o["requestBody"] = map[string]interface{}{"content": content}
+	}
+
+	responses := make(map[string]interface{})
+	for code, name := range op.responses {
+		if name == "" {
+			responses[code] = map[string]interface{}{"description": code}
+			continue
+		}
+		schema, ok := g.responses[name]
+		if !ok {
+			return nil, fmt.Errorf("%v %v: unknown response %v", op.method, op.path, name)
+		}
+		// Errors are always JSON error objects.
+		if strings.HasPrefix(code, "4") || strings.HasPrefix(code, "5") {
+			responses[code] = map[string]interface{}{"$ref": "#/components/responses/" + name}
+			continue
+		}
+		produces := op.produces
+		if len(produces) == 0 {
+			produces = []string{"application/json"}
+		}
+		content := make(map[string]interface{})
+		for _, mediaType := range produces {
+			mediaSchema := schema
+			if array, ok := schema.(map[string]interface{}); ok && mediaType == ndjson && array["type"] == "array" {
+				// Each line is one of the array's items.
+				mediaSchema = array["items"]
+			}
+			content[mediaType] = map[string]interface{}{"schema": mediaSchema}
+		}
+		responses[code] = map[string]interface{}{"description": name, "content": content}
+	}
+	if len(responses) == 0 {
+		responses["200"] = map[string]interface{}{"description": "200"}
+	}
+	o["responses"] = responses
+	return o, nil
+}
@@ -0,0 +1,48 @@
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONIsUpToDate(t *testing.T) {
+	spec, err := Generate("..")
+	require.NoError(t, err)
+	assert.Equal(t, string(spec), JSON, "specJSON.go is out of date. Run go generate")
+}
+
+func TestJSON(t *testing.T) {
+	var doc struct {
+		OpenAPI string                                       `json:"openapi"`
+		Paths   map[string]map[string]map[string]interface{} `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(JSON), &doc))
+	assert.Equal(t, "3.0.3", doc.OpenAPI)
+
+	list := doc.Paths["/fs/list"]["get"]
+	assert.Equal(t, "listEntries", list["operationId"])
+	var params []string
+	for _, param := range list["parameters"].([]interface{}) {
+		params = append(params, param.(map[string]interface{})["name"].(string))
+	}
+	assert.ElementsMatch(t, []string{"path", "limit", "cursor", "stream"}, params)
+
+	exec := doc.Paths["/fs/exec"]["post"]
+	assert.Contains(t, exec, "requestBody")
+	assert.Contains(t, doc.Paths["/plugins/{name}/enable"]["post"]["parameters"], map[string]interface{}{
+		"name":        "name",
+		"in":          "path",
+		"required":    true,
+		"schema":      map[string]interface{}{"type": "string"},
+		"description": "the plugin's name",
+	})
+}
+
+func TestParseRoute_ReturnsErrorIfMalformed(t *testing.T) {
+	g := &generator{paths: make(map[string]map[string]*operation)}
+	assert.EqualError(t, g.parseRoute("swagger:route GET"), `malformed annotation "swagger:route GET"`)
+	assert.EqualError(t, g.parseRoute("swagger:route GET /fs/info info entryInfo\n\n    Responses:\n      200 entry"), `/fs/info: malformed response "200 entry"`)
+}
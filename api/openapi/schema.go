@@ -0,0 +1,146 @@
+package openapi
+
+import (
+	"encoding/json"
+	"io"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+
+	apitypes "github.com/puppetlabs/wash/api/types"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// types are the named types that the annotations refer to. The generator
+// returns an error for types that are missing, so new request and response
+// types must be added here.
+var types = map[string]reflect.Type{
+	"apitypes.Activity":              reflect.TypeOf(apitypes.Activity{}),
+	"apitypes.AggregateResult":       reflect.TypeOf(apitypes.AggregateResult{}),
+	"apitypes.BatchBody":             reflect.TypeOf(apitypes.BatchBody{}),
+	"apitypes.BatchResult":           reflect.TypeOf(apitypes.BatchResult{}),
+	"apitypes.Diagnostics":           reflect.TypeOf(apitypes.Diagnostics{}),
+	"apitypes.Entry":                 reflect.TypeOf(apitypes.Entry{}),
+	"apitypes.EntryEvent":            reflect.TypeOf(apitypes.EntryEvent{}),
+	"apitypes.EntrySchema":           reflect.TypeOf(apitypes.EntrySchema{}),
+	"apitypes.ErrorObj":              reflect.TypeOf(apitypes.ErrorObj{}),
+	"apitypes.ExecBody":              reflect.TypeOf(apitypes.ExecBody{}),
+	"apitypes.ExecPacket":            reflect.TypeOf(apitypes.ExecPacket{}),
+	"apitypes.HistoryResponse":       reflect.TypeOf(apitypes.HistoryResponse{}),
+	"apitypes.JournalEntry":          reflect.TypeOf(apitypes.JournalEntry{}),
+	"apitypes.JournalLevelsResponse": reflect.TypeOf(apitypes.JournalLevelsResponse{}),
+	"apitypes.PluginInstallBody":     reflect.TypeOf(apitypes.PluginInstallBody{}),
+	"apitypes.Stats":                 reflect.TypeOf(apitypes.Stats{}),
+	"apitypes.TrashedEntry":          reflect.TypeOf(apitypes.TrashedEntry{}),
+	"apitypes.WaitResult":            reflect.TypeOf(apitypes.WaitResult{}),
+	"apitypes.Webhook":               reflect.TypeOf(apitypes.Webhook{}),
+	"io.Reader":                      readerType,
+	"plugin.JSONObject":              reflect.TypeOf(plugin.JSONObject{}),
+}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	durationType  = reflect.TypeOf(time.Duration(0))
+	readerType    = reflect.TypeOf((*io.Reader)(nil)).Elem()
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// schemaOf returns the schema of the type's JSON encoding. Named structs are
+// added to the document's schemas and referenced.
+func (g *generator) schemaOf(t reflect.Type) interface{} {
+	switch t {
+	case timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case durationType:
+		return map[string]interface{}{"type": "integer", "format": "int64", "description": "nanoseconds"}
+	case readerType:
+		return map[string]interface{}{"type": "string", "format": "binary"}
+	}
+	if t.Kind() == reflect.Ptr {
+		return g.schemaOf(t.Elem())
+	}
+	if t.Implements(marshalerType) || reflect.PtrTo(t).Implements(marshalerType) {
+		// The type's fields don't describe its custom encoding.
+		return map[string]interface{}{}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		return g.ref(t)
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "format": "byte"}
+		}
+		return map[string]interface{}{"type": "array", "items": g.schemaOf(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": g.schemaOf(t.Elem())}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int64, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	}
+	return map[string]interface{}{}
+}
+
+// ref returns a reference to the named struct's schema. The schema's named
+// after the type, or after its package and the type if another package's type
+// has the same name.
+func (g *generator) ref(t reflect.Type) interface{} {
+	name, ok := g.schemaRefs[t]
+	if !ok {
+		name = t.Name()
+		if _, taken := g.schemas[name]; taken {
+			name = strings.Title(path.Base(t.PkgPath())) + t.Name()
+		}
+		g.schemaRefs[t] = name
+		// Reserve the name before building the schema, since the type can
+		// refer to itself.
+		g.schemas[name] = nil
+		g.schemas[name] = g.structSchema(t)
+	}
+	return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+}
+
+func (g *generator) structSchema(t reflect.Type) map[string]interface{} {
+	properties := make(map[string]interface{})
+	g.addProperties(t, properties)
+	return map[string]interface{}{"type": "object", "properties": properties}
+}
+
+// addProperties adds the struct's fields, and those of its embedded structs,
+// to properties.
+func (g *generator) addProperties(t reflect.Type, properties map[string]interface{}) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			g.addProperties(fieldType, properties)
+			continue
+		}
+		if field.PkgPath != "" {
+			// Unexported fields aren't encoded.
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = g.schemaOf(field.Type)
+	}
+}
@@ -0,0 +1,2230 @@
+// Code generated by gen.go from the api package. DO NOT EDIT.
+
+package openapi
+
+// JSON is the OpenAPI document of the API.
+const JSON = `{
+  "components": {
+    "responses": {
+      "AggregateResult": {
+        "content": {
+          "application/json": {
+            "schema": {
+              "$ref": "#/components/schemas/AggregateResult"
+            }
+          }
+        },
+        "description": "AggregateResult"
+      },
+      "Diagnostics": {
+        "content": {
+          "application/json": {
+            "schema": {
+              "$ref": "#/components/schemas/Diagnostics"
+            }
+          }
+        },
+        "description": "Diagnostics"
+      },
+      "Entry": {
+        "content": {
+          "application/json": {
+            "schema": {
+              "$ref": "#/components/schemas/Entry"
+            }
+          }
+        },
+        "description": "Entry"
+      },
+      "EntryEvent": {
+        "content": {
+          "application/json": {
+            "schema": {
+              "$ref": "#/components/schemas/EntryEvent"
+            }
+          }
+        },
+        "description": "EntryEvent"
+      },
+      "EntrySchema": {
+        "content": {
+          "application/json": {
+            "schema": {}
+          }
+        },
+        "description": "EntrySchema"
+      },
+      "ExecPacket": {
+        "content": {
+          "application/json": {
+            "schema": {
+              "$ref": "#/components/schemas/ExecPacket"
+            }
+          }
+        },
+        "description": "ExecPacket"
+      },
+      "HistoryResponse": {
+        "content": {
+          "application/json": {
+            "schema": {
+              "items": {
+                "$ref": "#/components/schemas/Activity"
+              },
+              "type": "array"
+            }
+          }
+        },
+        "description": "HistoryResponse"
+      },
+      "JournalEntry": {
+        "content": {
+          "application/json": {
+            "schema": {
+              "$ref": "#/components/schemas/JournalEntry"
+            }
+          }
+        },
+        "description": "JournalEntry"
+      },
+      "JournalLevelsResponse": {
+        "content": {
+          "application/json": {
+            "schema": {
+              "additionalProperties": {
+                "type": "string"
+              },
+              "type": "object"
+            }
+          }
+        },
+        "description": "JournalLevelsResponse"
+      },
+      "Stats": {
+        "content": {
+          "application/json": {
+            "schema": {
+              "$ref": "#/components/schemas/TypesStats"
+            }
+          }
+        },
+        "description": "Stats"
+      },
+      "TrashedEntry": {
+        "content": {
+          "application/json": {
+            "schema": {
+              "$ref": "#/components/schemas/TrashedEntry"
+            }
+          }
+        },
+        "description": "TrashedEntry"
+      },
+      "Webhook": {
+        "content": {
+          "application/json": {
+            "schema": {
+              "$ref": "#/components/schemas/Webhook"
+            }
+          }
+        },
+        "description": "Webhook"
+      },
+      "batchResults": {
+        "content": {
+          "application/json": {
+            "schema": {
+              "items": {
+                "$ref": "#/components/schemas/BatchResult"
+              },
+              "type": "array"
+            }
+          }
+        },
+        "description": "batchResults"
+      },
+      "entryList": {
+        "content": {
+          "application/json": {
+            "schema": {
+              "items": {
+                "$ref": "#/components/schemas/Entry"
+              },
+              "type": "array"
+            }
+          }
+        },
+        "description": "entryList"
+      },
+      "entryMetadata": {
+        "content": {
+          "application/json": {
+            "schema": {
+              "additionalProperties": {},
+              "type": "object"
+            }
+          }
+        },
+        "description": "entryMetadata"
+      },
+      "errorResp": {
+        "content": {
+          "application/json": {
+            "schema": {
+              "$ref": "#/components/schemas/ErrorObj"
+            }
+          }
+        },
+        "description": "errorResp"
+      },
+      "execResponse": {
+        "content": {
+          "application/json": {
+            "schema": {
+              "items": {
+                "$ref": "#/components/schemas/ExecPacket"
+              },
+              "type": "array"
+            }
+          }
+        },
+        "description": "execResponse"
+      },
+      "octetResponse": {
+        "content": {
+          "application/json": {
+            "schema": {
+              "format": "binary",
+              "type": "string"
+            }
+          }
+        },
+        "description": "octetResponse"
+      },
+      "schemaResponse": {
+        "content": {
+          "application/json": {
+            "schema": {
+              "additionalProperties": {},
+              "type": "object"
+            }
+          }
+        },
+        "description": "schemaResponse"
+      },
+      "trashList": {
+        "content": {
+          "application/json": {
+            "schema": {
+              "items": {
+                "$ref": "#/components/schemas/TrashedEntry"
+              },
+              "type": "array"
+            }
+          }
+        },
+        "description": "trashList"
+      },
+      "waitResult": {
+        "content": {
+          "application/json": {
+            "schema": {
+              "$ref": "#/components/schemas/WaitResult"
+            }
+          }
+        },
+        "description": "waitResult"
+      },
+      "webhookList": {
+        "content": {
+          "application/json": {
+            "schema": {
+              "items": {
+                "$ref": "#/components/schemas/Webhook"
+              },
+              "type": "array"
+            }
+          }
+        },
+        "description": "webhookList"
+      }
+    },
+    "schemas": {
+      "Activity": {
+        "properties": {
+          "description": {
+            "type": "string"
+          },
+          "start": {
+            "format": "date-time",
+            "type": "string"
+          }
+        },
+        "type": "object"
+      },
+      "AggregateResult": {
+        "properties": {
+          "count": {
+            "type": "integer"
+          },
+          "group": {},
+          "value": {}
+        },
+        "type": "object"
+      },
+      "BatchBody": {
+        "properties": {
+          "metadata": {
+            "type": "boolean"
+          },
+          "paths": {
+            "items": {
+              "type": "string"
+            },
+            "type": "array"
+          }
+        },
+        "type": "object"
+      },
+      "BatchResult": {
+        "properties": {
+          "entry": {
+            "$ref": "#/components/schemas/Entry"
+          },
+          "error": {
+            "$ref": "#/components/schemas/ErrorObj"
+          },
+          "metadata": {
+            "additionalProperties": {},
+            "type": "object"
+          },
+          "path": {
+            "type": "string"
+          }
+        },
+        "type": "object"
+      },
+      "Diagnostics": {
+        "properties": {
+          "cache": {
+            "$ref": "#/components/schemas/Stats"
+          },
+          "goroutines": {
+            "type": "string"
+          },
+          "panics": {
+            "items": {
+              "$ref": "#/components/schemas/Panic"
+            },
+            "type": "array"
+          },
+          "plugins": {
+            "items": {
+              "type": "string"
+            },
+            "type": "array"
+          }
+        },
+        "type": "object"
+      },
+      "Entry": {
+        "properties": {
+          "actions": {
+            "items": {
+              "type": "string"
+            },
+            "type": "array"
+          },
+          "attributes": {},
+          "cname": {
+            "type": "string"
+          },
+          "metadata": {
+            "additionalProperties": {},
+            "type": "object"
+          },
+          "name": {
+            "type": "string"
+          },
+          "path": {
+            "type": "string"
+          },
+          "target": {
+            "type": "string"
+          },
+          "type_id": {
+            "type": "string"
+          }
+        },
+        "type": "object"
+      },
+      "EntryEvent": {
+        "properties": {
+          "entry": {
+            "$ref": "#/components/schemas/Entry"
+          },
+          "time": {
+            "format": "date-time",
+            "type": "string"
+          },
+          "type": {
+            "type": "string"
+          }
+        },
+        "type": "object"
+      },
+      "ErrorObj": {
+        "properties": {
+          "fields": {
+            "additionalProperties": {},
+            "type": "object"
+          },
+          "kind": {
+            "type": "string"
+          },
+          "msg": {
+            "type": "string"
+          }
+        },
+        "type": "object"
+      },
+      "ExecBody": {
+        "properties": {
+          "args": {
+            "items": {
+              "type": "string"
+            },
+            "type": "array"
+          },
+          "cmd": {
+            "type": "string"
+          },
+          "opts": {
+            "$ref": "#/components/schemas/ExecOptions"
+          }
+        },
+        "type": "object"
+      },
+      "ExecOptions": {
+        "properties": {
+          "input": {
+            "type": "string"
+          },
+          "tty": {
+            "type": "boolean"
+          }
+        },
+        "type": "object"
+      },
+      "ExecPacket": {
+        "properties": {
+          "data": {},
+          "error": {
+            "$ref": "#/components/schemas/ErrorObj"
+          },
+          "timestamp": {
+            "format": "date-time",
+            "type": "string"
+          },
+          "type": {
+            "type": "string"
+          }
+        },
+        "type": "object"
+      },
+      "Histogram": {
+        "properties": {
+          "average_latency": {
+            "description": "nanoseconds",
+            "format": "int64",
+            "type": "integer"
+          },
+          "buckets": {
+            "items": {
+              "format": "int64",
+              "type": "integer"
+            },
+            "type": "array"
+          },
+          "count": {
+            "format": "int64",
+            "type": "integer"
+          },
+          "errors": {
+            "format": "int64",
+            "type": "integer"
+          },
+          "max_latency": {
+            "description": "nanoseconds",
+            "format": "int64",
+            "type": "integer"
+          },
+          "name": {
+            "type": "string"
+          },
+          "slower": {
+            "format": "int64",
+            "type": "integer"
+          }
+        },
+        "type": "object"
+      },
+      "JournalEntry": {
+        "properties": {
+          "description": {
+            "type": "string"
+          },
+          "journal_id": {
+            "type": "string"
+          },
+          "level": {
+            "type": "string"
+          },
+          "message": {
+            "type": "string"
+          },
+          "start": {
+            "format": "date-time",
+            "type": "string"
+          },
+          "time": {
+            "format": "date-time",
+            "type": "string"
+          }
+        },
+        "type": "object"
+      },
+      "MemoryStats": {
+        "properties": {
+          "alloc": {
+            "format": "int64",
+            "type": "integer"
+          },
+          "num_gc": {
+            "type": "integer"
+          },
+          "sys": {
+            "format": "int64",
+            "type": "integer"
+          }
+        },
+        "type": "object"
+      },
+      "MethodStats": {
+        "properties": {
+          "average_latency": {
+            "description": "nanoseconds",
+            "format": "int64",
+            "type": "integer"
+          },
+          "count": {
+            "format": "int64",
+            "type": "integer"
+          },
+          "errors": {
+            "format": "int64",
+            "type": "integer"
+          },
+          "max_latency": {
+            "description": "nanoseconds",
+            "format": "int64",
+            "type": "integer"
+          },
+          "method": {
+            "type": "string"
+          }
+        },
+        "type": "object"
+      },
+      "Operation": {
+        "properties": {
+          "entry": {
+            "type": "string"
+          },
+          "method": {
+            "type": "string"
+          },
+          "plugin": {
+            "type": "string"
+          },
+          "started": {
+            "format": "date-time",
+            "type": "string"
+          }
+        },
+        "type": "object"
+      },
+      "Panic": {
+        "properties": {
+          "op": {
+            "type": "string"
+          },
+          "stack": {
+            "type": "string"
+          },
+          "time": {
+            "format": "date-time",
+            "type": "string"
+          },
+          "value": {
+            "type": "string"
+          }
+        },
+        "type": "object"
+      },
+      "PluginInstallBody": {
+        "properties": {
+          "config": {
+            "additionalProperties": {},
+            "type": "object"
+          },
+          "protocol": {
+            "type": "string"
+          },
+          "script": {
+            "type": "string"
+          }
+        },
+        "type": "object"
+      },
+      "PluginStats": {
+        "properties": {
+          "methods": {
+            "items": {
+              "$ref": "#/components/schemas/MethodStats"
+            },
+            "type": "array"
+          },
+          "plugin": {
+            "type": "string"
+          }
+        },
+        "type": "object"
+      },
+      "Stats": {
+        "properties": {
+          "hits": {
+            "format": "int64",
+            "type": "integer"
+          },
+          "items": {
+            "type": "integer"
+          },
+          "misses": {
+            "format": "int64",
+            "type": "integer"
+          }
+        },
+        "type": "object"
+      },
+      "TrashedEntry": {
+        "properties": {
+          "id": {
+            "type": "string"
+          },
+          "path": {
+            "type": "string"
+          },
+          "purge_at": {
+            "format": "date-time",
+            "type": "string"
+          },
+          "trashed_at": {
+            "format": "date-time",
+            "type": "string"
+          }
+        },
+        "type": "object"
+      },
+      "TypesStats": {
+        "properties": {
+          "active": {
+            "items": {
+              "$ref": "#/components/schemas/Operation"
+            },
+            "type": "array"
+          },
+          "cache": {
+            "$ref": "#/components/schemas/Stats"
+          },
+          "fuse_ops": {
+            "items": {
+              "$ref": "#/components/schemas/Histogram"
+            },
+            "type": "array"
+          },
+          "goroutines": {
+            "type": "integer"
+          },
+          "memory": {
+            "$ref": "#/components/schemas/MemoryStats"
+          },
+          "plugins": {
+            "items": {
+              "$ref": "#/components/schemas/PluginStats"
+            },
+            "type": "array"
+          },
+          "start_time": {
+            "format": "date-time",
+            "type": "string"
+          },
+          "uptime": {
+            "description": "nanoseconds",
+            "format": "int64",
+            "type": "integer"
+          }
+        },
+        "type": "object"
+      },
+      "WaitResult": {
+        "properties": {
+          "entry": {
+            "$ref": "#/components/schemas/Entry"
+          },
+          "satisfied": {
+            "type": "boolean"
+          }
+        },
+        "type": "object"
+      },
+      "Webhook": {
+        "properties": {
+          "fullmeta": {
+            "type": "boolean"
+          },
+          "id": {
+            "type": "string"
+          },
+          "interval": {
+            "type": "string"
+          },
+          "last_error": {
+            "type": "string"
+          },
+          "maxdepth": {
+            "type": "integer"
+          },
+          "path": {
+            "type": "string"
+          },
+          "query": {},
+          "url": {
+            "type": "string"
+          }
+        },
+        "type": "object"
+      }
+    }
+  },
+  "info": {
+    "description": "The API of the Wash daemon. It's served on the daemon's UNIX socket, and the /fs endpoints are also served on the api.listen TCP address.",
+    "title": "Wash API",
+    "version": "1"
+  },
+  "openapi": "3.0.3",
+  "paths": {
+    "/activity/stream": {
+      "get": {
+        "description": "Streams the entries that are recorded in the journals from now on as server-sent events until the client disconnects. Each event's type is \"entry\", and its data is a JournalEntry. Entries are dropped if the client falls too far behind.",
+        "operationId": "streamActivity",
+        "parameters": [
+          {
+            "description": "only stream the entries of the journal with this ID",
+            "in": "query",
+            "name": "journal",
+            "schema": {
+              "type": "string"
+            }
+          },
+          {
+            "description": "only stream entries that are at least as severe as this level, e.g. \"warn\"",
+            "in": "query",
+            "name": "level",
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/JournalEntry"
+                }
+              },
+              "text/event-stream": {
+                "schema": {
+                  "$ref": "#/components/schemas/JournalEntry"
+                }
+              }
+            },
+            "description": "JournalEntry"
+          },
+          "400": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "500": {
+            "$ref": "#/components/responses/errorResp"
+          }
+        },
+        "summary": "Stream journal entries",
+        "tags": [
+          "activity"
+        ]
+      }
+    },
+    "/analytics/screenview": {
+      "post": {
+        "responses": {
+          "200": {
+            "description": "200"
+          },
+          "404": {
+            "$ref": "#/components/responses/errorResp"
+          }
+        },
+        "summary": "Submits a screenview to Google Analytics"
+      }
+    },
+    "/cache": {
+      "delete": {
+        "description": "Removes the specified entry and its children from the cache.",
+        "operationId": "cacheDelete",
+        "parameters": [
+          {
+            "description": "uniquely identifies an entry",
+            "in": "query",
+            "name": "path",
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "200"
+          },
+          "500": {
+            "$ref": "#/components/responses/errorResp"
+          }
+        },
+        "summary": "Remove items from the cache",
+        "tags": [
+          "cache"
+        ]
+      }
+    },
+    "/diagnostics": {
+      "get": {
+        "description": "Get the server's loaded plugins, cache usage, recently recovered panics and goroutine dump. Used to generate diagnostic bundles for bug reports.",
+        "operationId": "getDiagnostics",
+        "responses": {
+          "200": {
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Diagnostics"
+                }
+              }
+            },
+            "description": "Diagnostics"
+          },
+          "500": {
+            "$ref": "#/components/responses/errorResp"
+          }
+        },
+        "summary": "Get diagnostics",
+        "tags": [
+          "diagnostics"
+        ]
+      }
+    },
+    "/fs/batch": {
+      "post": {
+        "description": "Returns the Entry objects of up to 1000 paths in one round trip, in the order of the paths. Each entry's full metadata is also returned if metadata is true. A path whose entry can't be found, or whose metadata can't be fetched, has an error object instead, so one bad path doesn't fail the batch.",
+        "operationId": "batchInfo",
+        "requestBody": {
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/BatchBody"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "content": {
+              "application/json": {
+                "schema": {
+                  "items": {
+                    "$ref": "#/components/schemas/BatchResult"
+                  },
+                  "type": "array"
+                }
+              }
+            },
+            "description": "batchResults"
+          },
+          "400": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "500": {
+            "$ref": "#/components/responses/errorResp"
+          }
+        },
+        "summary": "Get the info of multiple entries",
+        "tags": [
+          "batch"
+        ]
+      }
+    },
+    "/fs/copy": {
+      "post": {
+        "description": "The content is piped from the source to the destination in bounded chunks when both entries support it, so copying large entries doesn't require buffering their entire content. On success, returns the number of copied bytes.",
+        "operationId": "copyEntry",
+        "parameters": [
+          {
+            "description": "deduplicates retries of the request. A retry with the same key within 10 minutes of a successful request replays its response instead of running the request again.",
+            "in": "header",
+            "name": "Idempotency-Key",
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "200"
+          },
+          "400": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "404": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "500": {
+            "$ref": "#/components/responses/errorResp"
+          }
+        },
+        "summary": "Copies the content of the entry at the specified path to a destination entry.",
+        "tags": [
+          "copy"
+        ]
+      }
+    },
+    "/fs/create": {
+      "post": {
+        "description": "Creates an empty file with the request body's mode, or a directory if the request body's dir is true. Only entries whose plugin supports creating children can create them. On success, returns an Entry object describing the new child.",
+        "operationId": "createEntry",
+        "responses": {
+          "200": {
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Entry"
+                }
+              }
+            },
+            "description": "Entry"
+          },
+          "400": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "404": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "500": {
+            "$ref": "#/components/responses/errorResp"
+          }
+        },
+        "summary": "Creates a new child of the entry at the specified path.",
+        "tags": [
+          "create"
+        ]
+      }
+    },
+    "/fs/delete": {
+      "delete": {
+        "description": "On success, returns a boolean that describes whether the delete was applied immediately or is pending.",
+        "operationId": "deleteEntry",
+        "parameters": [
+          {
+            "description": "deduplicates retries of the request. A retry with the same key within 10 minutes of a successful request replays its response instead of running the request again.",
+            "in": "header",
+            "name": "Idempotency-Key",
+            "schema": {
+              "type": "string"
+            }
+          },
+          {
+            "description": "uniquely identifies an entry",
+            "in": "query",
+            "name": "path",
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "200"
+          },
+          "400": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "404": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "500": {
+            "$ref": "#/components/responses/errorResp"
+          }
+        },
+        "summary": "Deletes the entry at the specified path.",
+        "tags": [
+          "delete"
+        ]
+      }
+    },
+    "/fs/exec": {
+      "post": {
+        "description": "Executes a command on the remote system described by the supplied path.",
+        "operationId": "executeCommand",
+        "parameters": [
+          {
+            "description": "uniquely identifies an entry",
+            "in": "query",
+            "name": "path",
+            "schema": {
+              "type": "string"
+            }
+          },
+          {
+            "description": "deduplicates retries of the request. A retry with the same key within 10 minutes of a successful request replays its response instead of running the request again.",
+            "in": "header",
+            "name": "Idempotency-Key",
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "requestBody": {
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/ExecBody"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "content": {
+              "application/json": {
+                "schema": {
+                  "items": {
+                    "$ref": "#/components/schemas/ExecPacket"
+                  },
+                  "type": "array"
+                }
+              }
+            },
+            "description": "execResponse"
+          },
+          "400": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "404": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "500": {
+            "$ref": "#/components/responses/errorResp"
+          }
+        },
+        "summary": "Execute a command on a remote system",
+        "tags": [
+          "exec"
+        ]
+      }
+    },
+    "/fs/exec/session": {
+      "get": {
+        "description": "Executes a command on the remote system described by the supplied path over a websocket, so that its stdin can be written while it's running. The client first sends the command as an ExecBody, then sends ExecInput messages. The server sends the same packets as /fs/exec.",
+        "operationId": "executeInteractiveCommand",
+        "parameters": [
+          {
+            "description": "uniquely identifies an entry",
+            "in": "query",
+            "name": "path",
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "responses": {
+          "101": {
+            "content": {
+              "application/json": {
+                "schema": {
+                  "items": {
+                    "$ref": "#/components/schemas/ExecPacket"
+                  },
+                  "type": "array"
+                }
+              }
+            },
+            "description": "execResponse"
+          },
+          "400": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "404": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "500": {
+            "$ref": "#/components/responses/errorResp"
+          }
+        },
+        "summary": "Execute an interactive command on a remote system",
+        "tags": [
+          "exec"
+        ]
+      }
+    },
+    "/fs/find": {
+      "post": {
+        "description": "Recursively descends the given path, returning all children that satisfy the given RQL query. If the body's a FindBody, then the children are sorted, paginated and projected as it specifies, and the Total-Count header is set to the number of children that satisfied the query. If the FindBody has an aggregate clause, then the response is a list of AggregateResult objects instead.",
+        "operationId": "findQuery",
+        "parameters": [
+          {
+            "description": "uniquely identifies an entry",
+            "in": "query",
+            "name": "path",
+            "schema": {
+              "type": "string"
+            }
+          },
+          {
+            "description": "the minimum depth of the returned entries. The path is at depth 0.",
+            "in": "query",
+            "name": "mindepth",
+            "schema": {
+              "type": "integer"
+            }
+          },
+          {
+            "description": "the maximum depth of the returned entries",
+            "in": "query",
+            "name": "maxdepth",
+            "schema": {
+              "type": "integer"
+            }
+          },
+          {
+            "description": "if true, then meta primaries act on the entries' full metadata, which is also returned",
+            "in": "query",
+            "name": "fullmeta",
+            "schema": {
+              "type": "boolean"
+            }
+          },
+          {
+            "description": "the maximum number of bytes of each entry's content that content primaries read. Defaults to 1 MiB.",
+            "in": "query",
+            "name": "contentmaxsize",
+            "schema": {
+              "format": "int64",
+              "type": "integer"
+            }
+          }
+        ],
+        "requestBody": {
+          "content": {
+            "application/json": {
+              "schema": {}
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "content": {
+              "application/json": {
+                "schema": {
+                  "items": {
+                    "$ref": "#/components/schemas/Entry"
+                  },
+                  "type": "array"
+                }
+              }
+            },
+            "description": "entryList"
+          },
+          "400": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "404": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "500": {
+            "$ref": "#/components/responses/errorResp"
+          }
+        },
+        "summary": "Find entries using RQL",
+        "tags": [
+          "find"
+        ]
+      }
+    },
+    "/fs/info": {
+      "get": {
+        "description": "Returns an Entry object describing the given path.",
+        "operationId": "entryInfo",
+        "parameters": [
+          {
+            "description": "uniquely identifies an entry",
+            "in": "query",
+            "name": "path",
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Entry"
+                }
+              }
+            },
+            "description": "Entry"
+          },
+          "400": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "404": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "500": {
+            "$ref": "#/components/responses/errorResp"
+          }
+        },
+        "summary": "Info about entry at path",
+        "tags": [
+          "info"
+        ]
+      }
+    },
+    "/fs/list": {
+      "get": {
+        "description": "Returns a list of Entry objects describing children of the given path. The \"metadata\" key is set to the partial metadata. Entries are sorted by name. Large listings can be paginated with the limit and cursor parameters, and streamed with the stream parameter. Paginating only reduces the size of each response: each page still lists the whole directory, which is usually cached, and only the page's entries are described.",
+        "operationId": "listEntries",
+        "parameters": [
+          {
+            "description": "the maximum number of entries to return. The rest of the entries are listed by passing the response's Next-Cursor header as the cursor. Defaults to every entry.",
+            "in": "query",
+            "name": "limit",
+            "schema": {
+              "type": "integer"
+            }
+          },
+          {
+            "description": "the cursor of the page to return. Defaults to the first page.",
+            "in": "query",
+            "name": "cursor",
+            "schema": {
+              "type": "string"
+            }
+          },
+          {
+            "description": "stream the entries as newline-delimited JSON (application/x-ndjson) instead of as a JSON array. Defaults to false.",
+            "in": "query",
+            "name": "stream",
+            "schema": {
+              "type": "boolean"
+            }
+          },
+          {
+            "description": "uniquely identifies an entry",
+            "in": "query",
+            "name": "path",
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "content": {
+              "application/json": {
+                "schema": {
+                  "items": {
+                    "$ref": "#/components/schemas/Entry"
+                  },
+                  "type": "array"
+                }
+              },
+              "application/x-ndjson": {
+                "schema": {
+                  "$ref": "#/components/schemas/Entry"
+                }
+              }
+            },
+            "description": "entryList"
+          },
+          "400": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "404": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "500": {
+            "$ref": "#/components/responses/errorResp"
+          }
+        },
+        "summary": "Lists children of a path",
+        "tags": [
+          "list"
+        ]
+      }
+    },
+    "/fs/metadata": {
+      "get": {
+        "description": "Get metadata about the specified entry.",
+        "operationId": "getMetadata",
+        "parameters": [
+          {
+            "description": "uniquely identifies an entry",
+            "in": "query",
+            "name": "path",
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "content": {
+              "application/json": {
+                "schema": {
+                  "additionalProperties": {},
+                  "type": "object"
+                }
+              }
+            },
+            "description": "entryMetadata"
+          },
+          "404": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "500": {
+            "$ref": "#/components/responses/errorResp"
+          }
+        },
+        "summary": "Get metadata",
+        "tags": [
+          "metadata"
+        ]
+      }
+    },
+    "/fs/mountpoint": {
+      "get": {
+        "description": "Returns the server's mountpoint. Entry paths start with it, so remote daemons use it to find the server's plugins.",
+        "operationId": "getMountpoint",
+        "responses": {
+          "200": {
+            "description": "200"
+          },
+          "500": {
+            "$ref": "#/components/responses/errorResp"
+          }
+        },
+        "summary": "The server's mountpoint",
+        "tags": [
+          "mountpoint"
+        ]
+      }
+    },
+    "/fs/read": {
+      "get": {
+        "description": "Read the specified entry's content, or the requested range of it. Daemons that mount a remote Wash server use it since they can't read the remote server's files.",
+        "operationId": "readContent",
+        "parameters": [
+          {
+            "description": "the number of bytes to read. Defaults to the rest of the content.",
+            "in": "query",
+            "name": "size",
+            "schema": {
+              "format": "int64",
+              "type": "integer"
+            }
+          },
+          {
+            "description": "the offset to start reading from. Defaults to 0.",
+            "in": "query",
+            "name": "offset",
+            "schema": {
+              "format": "int64",
+              "type": "integer"
+            }
+          },
+          {
+            "description": "uniquely identifies an entry",
+            "in": "query",
+            "name": "path",
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "content": {
+              "application/json": {
+                "schema": {
+                  "format": "binary",
+                  "type": "string"
+                }
+              },
+              "application/octet-stream": {
+                "schema": {
+                  "format": "binary",
+                  "type": "string"
+                }
+              }
+            },
+            "description": "octetResponse"
+          },
+          "400": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "404": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "500": {
+            "$ref": "#/components/responses/errorResp"
+          }
+        },
+        "summary": "Read content",
+        "tags": [
+          "read"
+        ]
+      }
+    },
+    "/fs/rename": {
+      "post": {
+        "description": "The entry's moved to the request body's destination, whose parent must be in the same plugin as the entry. Like mv, an existing entry at the destination is replaced.",
+        "operationId": "renameEntry",
+        "responses": {
+          "200": {
+            "description": "200"
+          },
+          "400": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "404": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "500": {
+            "$ref": "#/components/responses/errorResp"
+          }
+        },
+        "summary": "Renames or moves the entry at the specified path.",
+        "tags": [
+          "rename"
+        ]
+      }
+    },
+    "/fs/schema": {
+      "get": {
+        "description": "Returns a map of Type IDs to EntrySchema objects describing the plugin schema starting at the given path. The first key in the map corresponds to the path's schema.",
+        "operationId": "entrySchema",
+        "parameters": [
+          {
+            "description": "uniquely identifies an entry",
+            "in": "query",
+            "name": "path",
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "content": {
+              "application/json": {
+                "schema": {
+                  "additionalProperties": {},
+                  "type": "object"
+                }
+              }
+            },
+            "description": "schemaResponse"
+          },
+          "400": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "404": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "500": {
+            "$ref": "#/components/responses/errorResp"
+          }
+        },
+        "summary": "Schema for an entry at path",
+        "tags": [
+          "schema"
+        ]
+      }
+    },
+    "/fs/signal": {
+      "post": {
+        "operationId": "signalEntry",
+        "parameters": [
+          {
+            "description": "deduplicates retries of the request. A retry with the same key within 10 minutes of a successful request replays its response instead of running the request again.",
+            "in": "header",
+            "name": "Idempotency-Key",
+            "schema": {
+              "type": "string"
+            }
+          },
+          {
+            "description": "uniquely identifies an entry",
+            "in": "query",
+            "name": "path",
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "200"
+          },
+          "400": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "404": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "500": {
+            "$ref": "#/components/responses/errorResp"
+          }
+        },
+        "summary": "Sends a signal to the entry at the specified path.",
+        "tags": [
+          "signal"
+        ]
+      }
+    },
+    "/fs/stream": {
+      "get": {
+        "description": "Get a stream of new updates to the specified entry. If since is set, then the stream also includes the updates made since that long ago (for entries that support it, like logs).",
+        "operationId": "streamUpdates",
+        "parameters": [
+          {
+            "description": "uniquely identifies an entry",
+            "in": "query",
+            "name": "path",
+            "schema": {
+              "type": "string"
+            }
+          },
+          {
+            "description": "also stream the updates made since this long ago, e.g. \"10m\"",
+            "in": "query",
+            "name": "since",
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "content": {
+              "application/json": {
+                "schema": {
+                  "format": "binary",
+                  "type": "string"
+                }
+              },
+              "application/octet-stream": {
+                "schema": {
+                  "format": "binary",
+                  "type": "string"
+                }
+              }
+            },
+            "description": "octetResponse"
+          },
+          "400": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "404": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "500": {
+            "$ref": "#/components/responses/errorResp"
+          }
+        },
+        "summary": "Stream updates",
+        "tags": [
+          "stream"
+        ]
+      }
+    },
+    "/fs/wait": {
+      "post": {
+        "description": "Blocks until the entry at the given path exists and satisfies the given RQL query, then returns the entry. An empty query waits for the entry to exist. If the timeout elapses first, then the result isn't satisfied. The entry's own cached data is cleared before each check. Its attributes and partial metadata are refreshed when its parent's cached listing expires.",
+        "operationId": "waitForCondition",
+        "parameters": [
+          {
+            "description": "uniquely identifies an entry",
+            "in": "query",
+            "name": "path",
+            "schema": {
+              "type": "string"
+            }
+          },
+          {
+            "description": "the maximum amount of time to wait, e.g. \"30s\" (defaults to 5m)",
+            "in": "query",
+            "name": "timeout",
+            "schema": {
+              "type": "string"
+            }
+          },
+          {
+            "description": "the amount of time between condition checks, e.g. \"5s\" (defaults to 1s)",
+            "in": "query",
+            "name": "interval",
+            "schema": {
+              "type": "string"
+            }
+          },
+          {
+            "description": "if true, then meta primaries act on the entry's full metadata",
+            "in": "query",
+            "name": "fullmeta",
+            "schema": {
+              "type": "boolean"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/WaitResult"
+                }
+              }
+            },
+            "description": "waitResult"
+          },
+          "400": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "404": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "500": {
+            "$ref": "#/components/responses/errorResp"
+          }
+        },
+        "summary": "Wait for an entry to satisfy an RQL query",
+        "tags": [
+          "wait"
+        ]
+      }
+    },
+    "/fs/watch": {
+      "get": {
+        "description": "Streams the changes to the specified entry's children as server-sent events until the client disconnects. Each event's type is \"created\", \"modified\" or \"deleted\", and its data is an EntryEvent.",
+        "operationId": "watchEntry",
+        "responses": {
+          "200": {
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/EntryEvent"
+                }
+              },
+              "text/event-stream": {
+                "schema": {
+                  "$ref": "#/components/schemas/EntryEvent"
+                }
+              }
+            },
+            "description": "EntryEvent"
+          },
+          "400": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "404": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "500": {
+            "$ref": "#/components/responses/errorResp"
+          }
+        },
+        "summary": "Watch an entry's children",
+        "tags": [
+          "watch"
+        ]
+      }
+    },
+    "/fs/write": {
+      "put": {
+        "description": "The body is piped to entries that can consume their content from a stream, so writing large content doesn't require buffering it. Otherwise, the entire body is buffered before it's written. The entry's cached data is cleared once the write succeeds.",
+        "operationId": "writeEntry",
+        "parameters": [
+          {
+            "description": "deduplicates retries of the request. A retry with the same key within 10 minutes of a successful request replays its response instead of running the request again.",
+            "in": "header",
+            "name": "Idempotency-Key",
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "200"
+          },
+          "400": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "404": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "500": {
+            "$ref": "#/components/responses/errorResp"
+          }
+        },
+        "summary": "Replaces the content of the entry at the specified path with the request body.",
+        "tags": [
+          "write"
+        ]
+      }
+    },
+    "/history": {
+      "get": {
+        "description": "Get a list of commands that have been run via 'wash' and when they were run.",
+        "operationId": "retrieveHistory",
+        "parameters": [
+          {
+            "description": "stream updates when true",
+            "in": "query",
+            "name": "follow",
+            "schema": {
+              "type": "boolean"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "content": {
+              "application/json": {
+                "schema": {
+                  "items": {
+                    "$ref": "#/components/schemas/Activity"
+                  },
+                  "type": "array"
+                }
+              }
+            },
+            "description": "HistoryResponse"
+          },
+          "400": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "404": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "500": {
+            "$ref": "#/components/responses/errorResp"
+          }
+        },
+        "summary": "Get command history",
+        "tags": [
+          "history"
+        ]
+      }
+    },
+    "/history/{id}": {
+      "get": {
+        "description": "Get the logs related to a particular command run via 'wash', requested by index within its activity history.",
+        "operationId": "getJournal",
+        "parameters": [
+          {
+            "description": "stream updates when true",
+            "in": "query",
+            "name": "follow",
+            "schema": {
+              "type": "boolean"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "content": {
+              "application/json": {
+                "schema": {
+                  "format": "binary",
+                  "type": "string"
+                }
+              },
+              "application/octet-stream": {
+                "schema": {
+                  "format": "binary",
+                  "type": "string"
+                }
+              }
+            },
+            "description": "octetResponse"
+          },
+          "400": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "404": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "500": {
+            "$ref": "#/components/responses/errorResp"
+          }
+        },
+        "summary": "Get logs for a particular entry in history",
+        "tags": [
+          "journal"
+        ]
+      }
+    },
+    "/journal/levels": {
+      "get": {
+        "description": "Get the journal level of every loaded plugin. Entries that a plugin records below its level are dropped from the journal.",
+        "operationId": "getJournalLevels",
+        "responses": {
+          "200": {
+            "content": {
+              "application/json": {
+                "schema": {
+                  "additionalProperties": {
+                    "type": "string"
+                  },
+                  "type": "object"
+                }
+              }
+            },
+            "description": "JournalLevelsResponse"
+          },
+          "500": {
+            "$ref": "#/components/responses/errorResp"
+          }
+        },
+        "summary": "Get the plugins' journal levels",
+        "tags": [
+          "journal"
+        ]
+      },
+      "put": {
+        "description": "Raises or lowers the journal level of the specified plugin. The change takes effect immediately, so it can be used to debug a single plugin without restarting the server.",
+        "operationId": "setJournalLevel",
+        "responses": {
+          "200": {
+            "description": "200"
+          },
+          "400": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "404": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "500": {
+            "$ref": "#/components/responses/errorResp"
+          }
+        },
+        "summary": "Set a plugin's journal level",
+        "tags": [
+          "journal"
+        ]
+      }
+    },
+    "/plugins": {
+      "post": {
+        "description": "Loads the external plugin's script and adds the plugin to Wash's root, without restarting the server. The plugin's registered even if its init fails, so that it can be reloaded once it's fixed.",
+        "operationId": "installPlugin",
+        "requestBody": {
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/PluginInstallBody"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "200"
+          },
+          "400": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "500": {
+            "$ref": "#/components/responses/errorResp"
+          }
+        },
+        "summary": "Install an external plugin",
+        "tags": [
+          "plugins"
+        ]
+      }
+    },
+    "/plugins/{name}/disable": {
+      "post": {
+        "description": "Removes the plugin from Wash's root and clears its cached data. External plugins that run as a daemon are stopped.",
+        "operationId": "disablePlugin",
+        "parameters": [
+          {
+            "description": "the plugin's name",
+            "in": "path",
+            "name": "name",
+            "required": true,
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "200"
+          },
+          "400": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "404": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "500": {
+            "$ref": "#/components/responses/errorResp"
+          }
+        },
+        "summary": "Disable a plugin",
+        "tags": [
+          "plugins"
+        ]
+      }
+    },
+    "/plugins/{name}/enable": {
+      "post": {
+        "description": "Initializes the plugin with its config and adds it back to Wash's root.",
+        "operationId": "enablePlugin",
+        "parameters": [
+          {
+            "description": "the plugin's name",
+            "in": "path",
+            "name": "name",
+            "required": true,
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "200"
+          },
+          "400": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "404": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "500": {
+            "$ref": "#/components/responses/errorResp"
+          }
+        },
+        "summary": "Enable a disabled plugin",
+        "tags": [
+          "plugins"
+        ]
+      }
+    },
+    "/plugins/{name}/reload": {
+      "post": {
+        "description": "Re-initializes the plugin with its config and clears its cached data, so an external plugin's updated script is used without restarting the server. External plugins that run as a daemon are relaunched.",
+        "operationId": "reloadPlugin",
+        "parameters": [
+          {
+            "description": "the plugin's name",
+            "in": "path",
+            "name": "name",
+            "required": true,
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "200"
+          },
+          "404": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "500": {
+            "$ref": "#/components/responses/errorResp"
+          }
+        },
+        "summary": "Reload a plugin",
+        "tags": [
+          "plugins"
+        ]
+      }
+    },
+    "/stats": {
+      "get": {
+        "description": "Get the server's uptime, memory usage, cache usage, the stats of each plugin's method calls, the operations that are in progress and, if FUSE op logging is on, the FUSE operations' latency histograms.",
+        "operationId": "getStats",
+        "responses": {
+          "200": {
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/TypesStats"
+                }
+              }
+            },
+            "description": "Stats"
+          },
+          "500": {
+            "$ref": "#/components/responses/errorResp"
+          }
+        },
+        "summary": "Get stats",
+        "tags": [
+          "stats"
+        ]
+      }
+    },
+    "/swagger.json": {
+      "get": {
+        "description": "Returns the OpenAPI 3 document that describes the API's endpoints, so that tools can generate clients for it.",
+        "operationId": "getOpenAPI",
+        "responses": {
+          "200": {
+            "description": "200"
+          }
+        },
+        "summary": "The API's OpenAPI document",
+        "tags": [
+          "openapi"
+        ]
+      }
+    },
+    "/trash": {
+      "get": {
+        "description": "Lists the entries that were moved to the trash by a delete, ordered by when they were trashed. The list's empty if the trash isn't enabled.",
+        "operationId": "listTrash",
+        "responses": {
+          "200": {
+            "content": {
+              "application/json": {
+                "schema": {
+                  "items": {
+                    "$ref": "#/components/schemas/TrashedEntry"
+                  },
+                  "type": "array"
+                }
+              }
+            },
+            "description": "trashList"
+          },
+          "500": {
+            "$ref": "#/components/responses/errorResp"
+          }
+        },
+        "summary": "List the trashed entries",
+        "tags": [
+          "trash"
+        ]
+      }
+    },
+    "/trash/{id}": {
+      "delete": {
+        "description": "Deletes the trashed entry now instead of when its retention period expires.\n\nOn success, returns a boolean that describes whether the delete was applied immediately or is pending.",
+        "operationId": "purgeTrashedEntry",
+        "parameters": [
+          {
+            "description": "deduplicates retries of the request. A retry with the same key within 10 minutes of a successful request replays its response instead of running the request again.",
+            "in": "header",
+            "name": "Idempotency-Key",
+            "schema": {
+              "type": "string"
+            }
+          },
+          {
+            "description": "the trashed entry's ID",
+            "in": "path",
+            "name": "id",
+            "required": true,
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "200"
+          },
+          "404": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "500": {
+            "$ref": "#/components/responses/errorResp"
+          }
+        },
+        "summary": "Purge a trashed entry",
+        "tags": [
+          "trash"
+        ]
+      }
+    },
+    "/trash/{id}/restore": {
+      "post": {
+        "description": "Takes the entry out of the trash so that it's listed again and isn't deleted.",
+        "operationId": "restoreTrashedEntry",
+        "parameters": [
+          {
+            "description": "the trashed entry's ID",
+            "in": "path",
+            "name": "id",
+            "required": true,
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "200"
+          },
+          "404": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "500": {
+            "$ref": "#/components/responses/errorResp"
+          }
+        },
+        "summary": "Restore a trashed entry",
+        "tags": [
+          "trash"
+        ]
+      }
+    },
+    "/webhooks": {
+      "get": {
+        "operationId": "listWebhooks",
+        "responses": {
+          "200": {
+            "content": {
+              "application/json": {
+                "schema": {
+                  "items": {
+                    "$ref": "#/components/schemas/Webhook"
+                  },
+                  "type": "array"
+                }
+              }
+            },
+            "description": "webhookList"
+          },
+          "500": {
+            "$ref": "#/components/responses/errorResp"
+          }
+        },
+        "summary": "List the registered webhooks",
+        "tags": [
+          "webhooks"
+        ]
+      },
+      "post": {
+        "description": "Registers a webhook that watches the entries under the given path that satisfy the given RQL query. The entries are checked periodically, and an event is POSTed to the webhook's URL whenever an entry starts satisfying the query (created), stops satisfying it (deleted), or has its attributes change (modified). The first check only records the entries, so registering a webhook doesn't notify it of the existing entries.",
+        "operationId": "addWebhook",
+        "requestBody": {
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/Webhook"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Webhook"
+                }
+              }
+            },
+            "description": "Webhook"
+          },
+          "400": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "500": {
+            "$ref": "#/components/responses/errorResp"
+          }
+        },
+        "summary": "Register a webhook",
+        "tags": [
+          "webhooks"
+        ]
+      }
+    },
+    "/webhooks/{id}": {
+      "delete": {
+        "description": "Stops watching the webhook's entries.",
+        "operationId": "deleteWebhook",
+        "parameters": [
+          {
+            "description": "the webhook's ID",
+            "in": "path",
+            "name": "id",
+            "required": true,
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "200"
+          },
+          "404": {
+            "$ref": "#/components/responses/errorResp"
+          },
+          "500": {
+            "$ref": "#/components/responses/errorResp"
+          }
+        },
+        "summary": "Delete a webhook",
+        "tags": [
+          "webhooks"
+        ]
+      }
+    }
+  },
+  "servers": [
+    {
+      "url": "http://localhost"
+    }
+  ]
+}
+`
@@ -0,0 +1,151 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/puppetlabs/wash/activity"
+	apitypes "github.com/puppetlabs/wash/api/types"
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/puppetlabs/wash/plugin/external"
+)
+
+// swagger:parameters installPlugin
+//nolint:deadcode,unused
+type pluginInstallBody struct {
+	// in: body
+	Plugin apitypes.PluginInstallBody
+}
+
+// swagger:parameters enablePlugin disablePlugin reloadPlugin
+//nolint:deadcode,unused
+type pluginNameParam struct {
+	// the plugin's name
+	//
+	// in: path
+	Name string
+}
+
+// swagger:route POST /plugins plugins installPlugin
+//
+// Install an external plugin
+//
+// Loads the external plugin's script and adds the plugin to Wash's root,
+// without restarting the server. The plugin's registered even if its init
+// fails, so that it can be reloaded once it's fixed.
+//
+//     Consumes:
+//     - application/json
+//
+//     Schemes: http
+//
+//     Responses:
+//       200:
+//       400: errorResp
+//       500: errorResp
+var installPluginHandler = handler{fn: func(w http.ResponseWriter, r *http.Request) *errorResponse {
+	ctx := r.Context()
+	if r.Body == nil {
+		return badRequestResponse("Please send a JSON request body")
+	}
+
+	var body apitypes.PluginInstallBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return badRequestResponse(err.Error())
+	}
+	if body.Script == "" {
+		return badRequestResponse("the plugin's script is required")
+	}
+
+	spec := external.PluginSpec{Script: body.Script, Protocol: body.Protocol}
+	root, err := spec.Load()
+	if err != nil {
+		return badRequestResponse(fmt.Sprintf("%v failed to load: %v", body.Script, err))
+	}
+	registry := ctx.Value(pluginRegistryKey).(*plugin.Registry)
+	if registry.HasPlugin(spec.Name()) {
+		return badRequestResponse(fmt.Sprintf("the %v plugin's already been registered", spec.Name()))
+	}
+	if err := registry.InstallPlugin(root, body.Config); err != nil {
+		if !registry.HasPlugin(spec.Name()) {
+			return badRequestResponse(err.Error())
+		}
+		return unknownErrorResponse(fmt.Errorf("the %v plugin was installed, but it failed to initialize: %v", spec.Name(), err))
+	}
+
+	activity.Record(ctx, "API: Plugin %v installed from %v", spec.Name(), body.Script)
+	return nil
+}}
+
+// swagger:route POST /plugins/{name}/enable plugins enablePlugin
+//
+// Enable a disabled plugin
+//
+// Initializes the plugin with its config and adds it back to Wash's root.
+//
+//     Schemes: http
+//
+//     Responses:
+//       200:
+//       400: errorResp
+//       404: errorResp
+//       500: errorResp
+var enablePluginHandler = pluginActionHandler("enabled", "disabled", (*plugin.Registry).EnablePlugin)
+
+// swagger:route POST /plugins/{name}/disable plugins disablePlugin
+//
+// Disable a plugin
+//
+// Removes the plugin from Wash's root and clears its cached data. External
+// plugins that run as a daemon are stopped.
+//
+//     Schemes: http
+//
+//     Responses:
+//       200:
+//       400: errorResp
+//       404: errorResp
+//       500: errorResp
+var disablePluginHandler = pluginActionHandler("disabled", "enabled", (*plugin.Registry).DisablePlugin)
+
+// swagger:route POST /plugins/{name}/reload plugins reloadPlugin
+//
+// Reload a plugin
+//
+// Re-initializes the plugin with its config and clears its cached data, so
+// an external plugin's updated script is used without restarting the server.
+// External plugins that run as a daemon are relaunched.
+//
+//     Schemes: http
+//
+//     Responses:
+//       200:
+//       404: errorResp
+//       500: errorResp
+var reloadPluginHandler = pluginActionHandler("reloaded", "any", (*plugin.Registry).ReloadPlugin)
+
+// pluginActionHandler returns a handler that calls action on the plugin
+// that's named in the request's path. The request's rejected if the plugin
+// isn't in the state that the action requires, which is either enabled,
+// disabled or any.
+func pluginActionHandler(verb string, requiredState string, action func(*plugin.Registry, string) error) handler {
+	return handler{fn: func(w http.ResponseWriter, r *http.Request) *errorResponse {
+		ctx := r.Context()
+		name := mux.Vars(r)["name"]
+		registry := ctx.Value(pluginRegistryKey).(*plugin.Registry)
+		if !registry.HasPlugin(name) {
+			return pluginDoesNotExistResponse(name)
+		}
+		_, enabled := registry.Plugins()[name]
+		if (requiredState == "enabled" && !enabled) || (requiredState == "disabled" && enabled) {
+			return badRequestResponse(fmt.Sprintf("the %v plugin is not %v", name, requiredState))
+		}
+		if err := action(registry, name); err != nil {
+			return unknownErrorResponse(err)
+		}
+		activity.Record(ctx, "API: Plugin %v %v", name, verb)
+		return nil
+	}}
+}
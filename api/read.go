@@ -0,0 +1,84 @@
+package api
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// swagger:parameters readContent
+//nolint:deadcode,unused
+type readParams struct {
+	// the number of bytes to read. Defaults to the rest of the content.
+	//
+	// in: query
+	Size int64
+	// the offset to start reading from. Defaults to 0.
+	//
+	// in: query
+	Offset int64
+}
+
+// swagger:route GET /fs/read read readContent
+//
+// Read content
+//
+// Read the specified entry's content, or the requested range of it. Daemons
+// that mount a remote Wash server use it since they can't read the remote
+// server's files.
+//
+//     Produces:
+//     - application/json
+//     - application/octet-stream
+//
+//     Schemes: http
+//
+//     Responses:
+//       200: octetResponse
+//       400: errorResp
+//       404: errorResp
+//       500: errorResp
+var readHandler = handler{fn: func(w http.ResponseWriter, r *http.Request) *errorResponse {
+	ctx := r.Context()
+	entry, path, errResp := getEntryFromRequest(r)
+	if errResp != nil {
+		return errResp
+	}
+
+	if !plugin.ReadAction().IsSupportedOn(entry) {
+		return unsupportedActionResponse(path, plugin.ReadAction())
+	}
+
+	offset, _, errResp := getInt64Param(r.URL, "offset")
+	if errResp != nil {
+		return errResp
+	}
+	size, hasSize, errResp := getInt64Param(r.URL, "size")
+	if errResp != nil {
+		return errResp
+	}
+	if !hasSize {
+		contentSize, err := plugin.Size(ctx, entry)
+		if err != nil {
+			return erroredActionResponse(path, plugin.ReadAction(), err.Error())
+		}
+		size = int64(contentSize) - offset
+		if size < 0 {
+			size = 0
+		}
+	}
+
+	data, err := plugin.Read(ctx, entry, size, offset)
+	if err != nil && err != io.EOF {
+		return erroredActionResponse(path, plugin.ReadAction(), err.Error())
+	}
+
+	activity.Record(ctx, "API: Read %v: %v bytes at offset %v", path, len(data), offset)
+	w.Header().Set("Content-Type", "application/octet-stream")
+	if _, err := w.Write(data); err != nil {
+		activity.Warnf(ctx, "API: Failed writing the content of %v: %v", path, err)
+	}
+	return nil
+}}
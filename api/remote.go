@@ -0,0 +1,233 @@
+package api
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/api/auth"
+	apitypes "github.com/puppetlabs/wash/api/types"
+	log "github.com/sirupsen/logrus"
+)
+
+// RemoteOptions configures the API's TCP listener, which lets other Wash daemons
+// and API clients reach this one. The listener requires TLS. Its clients
+// authenticate with either a client certificate or a bearer token. Clients
+// with a certificate have the same access to the plugins as the local socket,
+// while a token's access is limited to the plugins that it was granted.
+type RemoteOptions struct {
+	// Address is the host:port that the listener binds to. An empty address
+	// disables the listener.
+	Address string
+	// CertFile and KeyFile are the server's certificate and key.
+	CertFile string
+	KeyFile  string
+	// ClientCAFile is a PEM file of the CAs that sign the clients'
+	// certificates. Client certificates aren't accepted if it's empty.
+	ClientCAFile string
+	// TokensFile is the file of the bearer tokens that are created by
+	// wash token create.
+	TokensFile string
+}
+
+func (o RemoteOptions) listen() (net.Listener, error) {
+	if o.CertFile == "" || o.KeyFile == "" {
+		return nil, fmt.Errorf("api.listen requires the api.tls_cert and api.tls_key configs")
+	}
+	cert, err := tls.LoadX509KeyPair(o.CertFile, o.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not load the API's certificate: %v", err)
+	}
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if o.ClientCAFile != "" {
+		pem, err := ioutil.ReadFile(o.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("api.tls_client_ca config: %v", err)
+		}
+		cfg.ClientCAs = x509.NewCertPool()
+		if !cfg.ClientCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("api.tls_client_ca config: %v does not contain any PEM-encoded certificates", o.ClientCAFile)
+		}
+		// Clients that use a token don't need a certificate.
+		cfg.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+	return tls.Listen("tcp", o.Address, cfg)
+}
+
+// remoteHandler serves the requests of remote daemons and other remote
+// clients. They can only use the /fs endpoints and read the OpenAPI document,
+// and their paths can only refer to entries because the daemon's local files
+// aren't theirs to access.
+//
+// Clients without a verified certificate must send a token in the
+// Authorization header. The token's checked against the plugins that the
+// request accesses when its paths are resolved.
+func remoteHandler(next http.Handler, tokens *auth.Store) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/fs/") && r.URL.Path != "/swagger.json" {
+			http.NotFound(w, r)
+			return
+		}
+		var ctx context.Context
+		if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+			cn := r.TLS.VerifiedChains[0][0].Subject.CommonName
+			ctx = context.WithValue(r.Context(), remoteKey, clientIdentity("cert", cn))
+		} else {
+			token, errResp := authenticate(r, tokens)
+			if errResp != nil {
+				log.Infof("API: Rejected %v %v from %v: %v", r.Method, r.URL.Path, r.RemoteAddr, errResp.body.Msg)
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(errResp.statusCode)
+				fmt.Fprintln(w, errResp.Error())
+				return
+			}
+			ctx = context.WithValue(r.Context(), remoteKey, clientIdentity("token", token.Name))
+			ctx = context.WithValue(ctx, authKey, remoteAuth{token: token, write: isWriteRequest(r)})
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func authenticate(r *http.Request, tokens *auth.Store) (auth.Token, *errorResponse) {
+	header := r.Header.Get("Authorization")
+	if header == "" {
+		return auth.Token{}, unauthorizedResponse("the request requires a client certificate or a bearer token")
+	}
+	const prefix = "Bearer "
+	if !strings.HasPrefix(header, prefix) {
+		return auth.Token{}, unauthorizedResponse("the Authorization header must contain a bearer token")
+	}
+	token, ok, err := tokens.Authenticate(strings.TrimPrefix(header, prefix))
+	if err != nil {
+		return auth.Token{}, unknownErrorResponse(fmt.Errorf("could not read the tokens: %v", err))
+	}
+	if !ok {
+		return auth.Token{}, unauthorizedResponse("the bearer token is invalid or was revoked")
+	}
+	return token, nil
+}
+
+// isWriteRequest returns true if the request changes the entries that it
+// accesses. Exec sessions are writes, even though they're GET requests, while
+// finds, waits and batches are reads even though they're POST requests.
+func isWriteRequest(r *http.Request) bool {
+	switch r.URL.Path {
+	case "/fs/find", "/fs/wait", "/fs/batch":
+		return false
+	case "/fs/exec/session":
+		return true
+	}
+	return r.Method != http.MethodGet && r.Method != http.MethodHead
+}
+
+// remoteAuth is the access of a request that was authenticated by a token.
+type remoteAuth struct {
+	token auth.Token
+	write bool
+}
+
+// authorize returns an error if the request can't access the plugin. An empty
+// plugin is Wash's root, which gives access to every plugin. Requests that
+// were authenticated by a certificate can access every plugin.
+func authorize(ctx context.Context, plugin string) *errorResponse {
+	a, ok := ctx.Value(authKey).(remoteAuth)
+	if !ok {
+		return nil
+	}
+	if plugin == "" {
+		plugin = auth.AllPlugins
+	}
+	if a.token.Allows(plugin, a.write) {
+		return nil
+	}
+	return forbiddenResponse(a.token.Name, plugin, a.write)
+}
+
+func isRemoteRequest(ctx context.Context) bool {
+	_, ok := ctx.Value(remoteKey).(string)
+	return ok
+}
+
+// clientIdentity identifies a remote client by how it authenticated and its
+// certificate's common name or token's name. Each client gets its own
+// journals and cache namespace, so the identity's restricted to the characters
+// that are valid in both.
+func clientIdentity(kind string, name string) string {
+	if name == "" {
+		return kind
+	}
+	return kind + "-" + sanitizeIdentity(name)
+}
+
+// sanitizeIdentity escapes the bytes that aren't letters, digits or '.' as _XX,
+// where XX is the byte's hex code. '_' is escaped too so that different names
+// can't have the same escape, and so is '-' so that the names that are joined
+// with it can be told apart.
+func sanitizeIdentity(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9') || c == '.' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "_%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// remoteClient returns the identity of the request's remote client. It returns
+// false for requests from the local socket.
+func remoteClient(ctx context.Context) (string, bool) {
+	identity, ok := ctx.Value(remoteKey).(string)
+	return identity, ok
+}
+
+// remoteJournal returns the journal of a remote client's request. The journal's
+// prefixed with the client's identity so that clients can't write to each
+// other's journals, or to files outside the journal directory.
+func remoteJournal(identity string, r *http.Request) activity.Journal {
+	id := r.Header.Get(apitypes.JournalIDHeader)
+	if id != "" {
+		id = identity + "-" + sanitizeIdentity(id)
+	}
+	desc := r.Header.Get(apitypes.JournalDescHeader)
+	if desc != "" {
+		desc = fmt.Sprintf("%v (%v at %v)", desc, identity, r.RemoteAddr)
+	}
+	return activity.NewJournal(id, desc)
+}
+
+// swagger:route GET /fs/mountpoint mountpoint getMountpoint
+//
+// The server's mountpoint
+//
+// Returns the server's mountpoint. Entry paths start with it, so remote
+// daemons use it to find the server's plugins.
+//
+//     Produces:
+//     - application/json
+//
+//     Schemes: http
+//
+//     Responses:
+//       200:
+//       500: errorResp
+var mountpointHandler = handler{fn: func(w http.ResponseWriter, r *http.Request) *errorResponse {
+	mountpoint := r.Context().Value(mountpointKey).(string)
+	if err := json.NewEncoder(w).Encode(mountpoint); err != nil {
+		return unknownErrorResponse(fmt.Errorf("Could not marshal the mountpoint: %v", err))
+	}
+	return nil
+}}
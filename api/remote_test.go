@@ -0,0 +1,180 @@
+package api
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/puppetlabs/wash/api/auth"
+	apitypes "github.com/puppetlabs/wash/api/types"
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoteHandler(t *testing.T) {
+	dir, err := ioutil.TempDir("", "remote")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	tokens := auth.NewStore(filepath.Join(dir, "tokens.json"))
+	secret, err := tokens.Create("ci", []string{"aws"}, nil)
+	require.NoError(t, err)
+
+	var ctx context.Context
+	handler := remoteHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx = r.Context()
+	}), tokens)
+	serve := func(method string, path string, token string, cert bool) int {
+		ctx = nil
+		r := httptest.NewRequest(method, path, nil)
+		if token != "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		if cert {
+			cert := &x509.Certificate{Subject: pkix.Name{CommonName: "laptop.example.com"}}
+			r.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodGet, "/history", secret, false))
+	assert.Equal(t, http.StatusOK, serve(http.MethodGet, "/swagger.json", secret, false))
+	assert.Equal(t, http.StatusUnauthorized, serve(http.MethodGet, "/fs/list", "", false))
+	assert.Equal(t, http.StatusUnauthorized, serve(http.MethodGet, "/fs/list", secret+"x", false))
+
+	if assert.Equal(t, http.StatusOK, serve(http.MethodGet, "/fs/list", "", true)) {
+		assert.True(t, isRemoteRequest(ctx))
+		identity, _ := remoteClient(ctx)
+		assert.Equal(t, "cert-laptop.example.com", identity)
+		assert.Nil(t, ctx.Value(authKey))
+	}
+	if assert.Equal(t, http.StatusOK, serve(http.MethodGet, "/fs/list", secret, false)) {
+		identity, _ := remoteClient(ctx)
+		assert.Equal(t, "token-ci", identity)
+		a := ctx.Value(authKey).(remoteAuth)
+		assert.Equal(t, "ci", a.token.Name)
+		assert.False(t, a.write)
+	}
+	if assert.Equal(t, http.StatusOK, serve(http.MethodPost, "/fs/find", secret, false)) {
+		assert.False(t, ctx.Value(authKey).(remoteAuth).write)
+	}
+	if assert.Equal(t, http.StatusOK, serve(http.MethodPost, "/fs/signal", secret, false)) {
+		assert.True(t, ctx.Value(authKey).(remoteAuth).write)
+	}
+	if assert.Equal(t, http.StatusOK, serve(http.MethodGet, "/fs/exec/session", secret, false)) {
+		assert.True(t, ctx.Value(authKey).(remoteAuth).write)
+	}
+}
+
+func TestRemoteJournal(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/fs/list", nil)
+	r.RemoteAddr = "10.0.0.2:51234"
+	r.Header.Set(apitypes.JournalIDHeader, "../../1234")
+	r.Header.Set(apitypes.JournalDescHeader, "ls")
+	journal := remoteJournal(clientIdentity("token", "alice smith"), r)
+	assert.Equal(t, "token-alice_20smith-.._2F.._2F1234", journal.ID)
+	assert.Equal(t, "ls (token-alice_20smith at 10.0.0.2:51234)", journal.Description)
+
+	// Requests without a journal stay without one.
+	r.Header.Del(apitypes.JournalIDHeader)
+	assert.Equal(t, "", remoteJournal("cert", r).ID)
+}
+
+func TestSanitizeIdentity(t *testing.T) {
+	assert.Equal(t, "laptop.example.com", sanitizeIdentity("laptop.example.com"))
+	assert.Equal(t, "alice_20smith", sanitizeIdentity("alice smith"))
+	assert.Equal(t, "_C3_A9", sanitizeIdentity("é"))
+
+	// Names that used to map to the same identity don't anymore.
+	assert.NotEqual(t, sanitizeIdentity("a b"), sanitizeIdentity("a_b"))
+	assert.NotEqual(t, sanitizeIdentity("a_20b"), sanitizeIdentity("a b"))
+	assert.NotEqual(
+		t,
+		clientIdentity("token", "a-b")+"-"+sanitizeIdentity("c"),
+		clientIdentity("token", "a")+"-"+sanitizeIdentity("b-c"),
+	)
+}
+
+func TestIdempotencyKeys_AreScopedToRemoteClients(t *testing.T) {
+	keys := newIdempotencyKeys()
+	calls := 0
+	h := idempotent(handler{logOnly: true, fn: func(w http.ResponseWriter, r *http.Request) *errorResponse {
+		calls++
+		fmt.Fprintf(w, "%v", calls)
+		return nil
+	}})
+	serve := func(identity string, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/fs/exec?path=/mnt/aws", strings.NewReader(body))
+		r.Header.Set(apitypes.IdempotencyKeyHeader, "a")
+		ctx := context.WithValue(r.Context(), idempotencyKeysKey, keys)
+		if identity != "" {
+			ctx = context.WithValue(ctx, remoteKey, identity)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r.WithContext(ctx))
+		return w
+	}
+
+	assert.Equal(t, "1", serve("token-alice", "{}").Body.String())
+	// Another client's request with the same key runs instead of replaying
+	// alice's response.
+	w := serve("token-bob", "{}")
+	assert.Equal(t, "2", w.Body.String())
+	assert.Empty(t, w.Header().Get(apitypes.IdempotentReplayedHeader))
+	// Reusing alice's key for a different request doesn't fail other clients.
+	assert.Equal(t, http.StatusOK, serve("cert-laptop", `{"cmd": "rm"}`).Code)
+	// Local requests don't share keys with remote clients either.
+	assert.Equal(t, "4", serve("", "{}").Body.String())
+
+	w = serve("token-alice", "{}")
+	assert.Equal(t, "1", w.Body.String())
+	assert.Equal(t, "true", w.Header().Get(apitypes.IdempotentReplayedHeader))
+	assert.Equal(t, 4, calls)
+}
+
+func TestGetEntryFromPath_AuthorizesTokens(t *testing.T) {
+	reg := plugin.NewRegistry()
+	for _, name := range []string{"aws", "gcp"} {
+		root := &mockRoot{EntryBase: plugin.NewEntry(name)}
+		root.SetTestID("/" + name)
+		require.NoError(t, reg.RegisterPlugin(root, map[string]interface{}{}))
+	}
+	ctx := context.WithValue(context.Background(), pluginRegistryKey, reg)
+	ctx = context.WithValue(ctx, mountpointKey, "/mnt")
+	token := auth.Token{Name: "ci", Read: []string{"aws"}}
+
+	readCtx := context.WithValue(ctx, authKey, remoteAuth{token: token})
+	_, _, errResp := getEntryFromPath(readCtx, "/mnt/aws")
+	assert.Nil(t, errResp)
+	_, _, errResp = getEntryFromPath(readCtx, "/mnt/gcp")
+	if assert.NotNil(t, errResp) {
+		assert.Equal(t, http.StatusForbidden, errResp.statusCode)
+		assert.Equal(t, apitypes.Forbidden, errResp.body.Kind)
+		assert.Equal(t, "The ci token can't read the gcp plugin", errResp.body.Msg)
+	}
+	_, _, errResp = getEntryFromPath(readCtx, "/mnt")
+	if assert.NotNil(t, errResp) {
+		assert.Equal(t, http.StatusForbidden, errResp.statusCode)
+	}
+
+	writeCtx := context.WithValue(ctx, authKey, remoteAuth{token: token, write: true})
+	_, _, errResp = getEntryFromPath(writeCtx, "/mnt/aws")
+	if assert.NotNil(t, errResp) {
+		assert.Equal(t, "The ci token can't write the aws plugin", errResp.body.Msg)
+	}
+
+	// Requests without a token, like those from the socket, aren't limited.
+	_, _, errResp = getEntryFromPath(ctx, "/mnt")
+	assert.Nil(t, errResp)
+}
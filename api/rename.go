@@ -0,0 +1,77 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+
+	"github.com/puppetlabs/wash/activity"
+	apitypes "github.com/puppetlabs/wash/api/types"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// swagger:route POST /fs/rename rename renameEntry
+//
+// Renames or moves the entry at the specified path.
+//
+// The entry's moved to the request body's destination, whose parent must be in
+// the same plugin as the entry. Like mv, an existing entry at the destination is
+// replaced.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Schemes: http
+//
+//     Responses:
+//       200:
+//       400: errorResp
+//       404: errorResp
+//       500: errorResp
+var renameHandler = handler{fn: func(w http.ResponseWriter, r *http.Request) *errorResponse {
+	ctx := r.Context()
+	entry, path, errResp := getEntryFromRequest(r)
+	if errResp != nil {
+		return errResp
+	}
+	if !plugin.RenameAction().IsSupportedOn(entry) {
+		return unsupportedActionResponse(path, plugin.RenameAction())
+	}
+
+	if r.Body == nil {
+		return badActionRequestResponse(path, plugin.RenameAction(), "Please send a JSON request body")
+	}
+	var body apitypes.RenameBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return badActionRequestResponse(path, plugin.RenameAction(), err.Error())
+	}
+	if body.Destination == "" {
+		return badActionRequestResponse(path, plugin.RenameAction(), "Please specify a destination")
+	}
+	if !filepath.IsAbs(body.Destination) {
+		return relativePathResponse(body.Destination)
+	}
+
+	dst := filepath.Clean(body.Destination)
+	newParent, newParentPath, errResp := getEntryFromPath(ctx, filepath.Dir(dst))
+	if errResp != nil {
+		return errResp
+	}
+	if !plugin.ListAction().IsSupportedOn(newParent) {
+		return badActionRequestResponse(path, plugin.RenameAction(), fmt.Sprintf("%v is not a directory", newParentPath))
+	}
+
+	err := plugin.RenameWithAnalytics(ctx, entry.(plugin.Renamable), newParent.(plugin.Parent), filepath.Base(dst))
+	if err != nil {
+		if plugin.IsInvalidInputErr(err) {
+			return badActionRequestResponse(path, plugin.RenameAction(), err.Error())
+		}
+		return erroredActionResponse(path, plugin.RenameAction(), err.Error())
+	}
+	activity.Record(ctx, "API: Rename %v %v", path, body.Destination)
+	return nil
+}}
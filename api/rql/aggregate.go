@@ -0,0 +1,153 @@
+package rql
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// The aggregation functions
+const (
+	AggregateCount = "count"
+	AggregateSum   = "sum"
+	AggregateMin   = "min"
+	AggregateMax   = "max"
+)
+
+// Aggregation computes a value over the entries that satisfied a query, like
+// their count or the sum of their sizes, optionally for each group of entries
+// that share a field's value.
+type Aggregation struct {
+	Function string
+	// Field is the aggregated field. It's empty for count.
+	Field Field
+	// GroupBy is the field whose values group the entries. The entries are
+	// aggregated as a single group if it's empty.
+	GroupBy Field
+}
+
+// NewAggregation validates and returns an aggregation. The function is one of
+// "count", "sum", "min" or "max". The field is required by every function
+// except for count, which doesn't take one. The groupBy field is optional.
+func NewAggregation(function string, field string, groupBy string) (Aggregation, error) {
+	a := Aggregation{Function: function}
+	switch function {
+	case AggregateCount:
+		if field != "" {
+			return Aggregation{}, fmt.Errorf("count doesn't take a field")
+		}
+	case AggregateSum, AggregateMin, AggregateMax:
+		if field == "" {
+			return Aggregation{}, fmt.Errorf("%v requires a field", function)
+		}
+		f, err := ParseField(field)
+		if err != nil {
+			return Aggregation{}, err
+		}
+		a.Field = f
+	default:
+		return Aggregation{}, fmt.Errorf("%q is not a valid aggregation function, expected count, sum, min or max", function)
+	}
+	if groupBy != "" {
+		f, err := ParseField(groupBy)
+		if err != nil {
+			return Aggregation{}, fmt.Errorf("invalid group by field: %w", err)
+		}
+		a.GroupBy = f
+	}
+	return a, nil
+}
+
+// Group is an aggregation's result for a group of entries.
+type Group struct {
+	// Key is the group's value of the GroupBy field. It's nil for the entries
+	// that don't have the field, and for ungrouped aggregations.
+	Key interface{}
+	// Count is the number of entries in the group.
+	Count int
+	// Value is the aggregated value. Sum adds up the field's numeric values,
+	// so it's a float64. Min and max return the least and greatest of the
+	// field's values, or nil if none of the group's entries have the field.
+	// Count's value is the group's Count.
+	Value interface{}
+}
+
+// Aggregate computes the aggregation's groups. Groups are ordered by their
+// key, and the group of entries without a key comes last. An ungrouped
+// aggregation has a single group, even if there aren't any entries.
+func Aggregate(entries []Entry, a Aggregation) []Group {
+	var groups []*Group
+	index := make(map[string]*Group)
+	if a.GroupBy == "" {
+		g := &Group{}
+		groups = append(groups, g)
+		index[""] = g
+	}
+	for _, e := range entries {
+		id := ""
+		var key interface{}
+		if a.GroupBy != "" {
+			if v, ok := a.GroupBy.Value(e); ok {
+				key = v
+				// Keys can be objects or arrays, which can't be map keys, so
+				// groups are identified by their key's JSON.
+				b, err := json.Marshal(v)
+				if err != nil {
+					b = []byte(fmt.Sprintf("%#v", v))
+				}
+				id = "v" + string(b)
+			}
+		}
+		g, ok := index[id]
+		if !ok {
+			g = &Group{Key: key}
+			groups = append(groups, g)
+			index[id] = g
+		}
+		g.add(e, a)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		ki, kj := groups[i].Key, groups[j].Key
+		if (ki == nil) != (kj == nil) {
+			return kj == nil
+		}
+		return compareValues(ki, kj) < 0
+	})
+	result := make([]Group, len(groups))
+	for i, g := range groups {
+		result[i] = *g
+		if a.Function == AggregateCount {
+			result[i].Value = g.Count
+		} else if a.Function == AggregateSum && g.Value == nil {
+			result[i].Value = float64(0)
+		}
+	}
+	return result
+}
+
+func (g *Group) add(e Entry, a Aggregation) {
+	g.Count++
+	if a.Function == AggregateCount {
+		return
+	}
+	v, ok := a.Field.Value(e)
+	if !ok {
+		return
+	}
+	switch a.Function {
+	case AggregateSum:
+		if n, ok := v.(float64); ok {
+			sum, _ := g.Value.(float64)
+			g.Value = sum + n
+		}
+	case AggregateMin:
+		if g.Value == nil || compareValues(v, g.Value) < 0 {
+			g.Value = v
+		}
+	case AggregateMax:
+		if g.Value == nil || compareValues(v, g.Value) > 0 {
+			g.Value = v
+		}
+	}
+}
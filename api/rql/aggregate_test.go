@@ -0,0 +1,63 @@
+package rql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAggregation(t *testing.T) {
+	a, err := NewAggregation("sum", "size", "meta.bucket")
+	require.NoError(t, err)
+	assert.Equal(t, Aggregation{Function: "sum", Field: "size", GroupBy: "meta.bucket"}, a)
+
+	_, err = NewAggregation("count", "", "")
+	assert.NoError(t, err)
+	_, err = NewAggregation("count", "size", "")
+	assert.Regexp(t, "count.*field", err)
+	_, err = NewAggregation("max", "", "")
+	assert.Regexp(t, "max requires a field", err)
+	_, err = NewAggregation("avg", "size", "")
+	assert.Regexp(t, "avg.*not a valid aggregation function", err)
+	_, err = NewAggregation("sum", "bogus", "")
+	assert.Regexp(t, "bogus.*not a valid field", err)
+	_, err = NewAggregation("sum", "size", "bogus")
+	assert.Regexp(t, "group by.*bogus", err)
+}
+
+func TestAggregate(t *testing.T) {
+	bucket := func(name string) plugin.JSONObject {
+		return plugin.JSONObject{"bucket": name}
+	}
+	entries := []Entry{
+		newSortTestEntry("a", 10, 300, bucket("logs")),
+		newSortTestEntry("b", 30, 100, bucket("backups")),
+		newSortTestEntry("c", 5, 200, bucket("logs")),
+		newSortTestEntry("d", 0, 0, nil),
+	}
+	aggregate := func(function string, field string, groupBy string) []Group {
+		a, err := NewAggregation(function, field, groupBy)
+		require.NoError(t, err)
+		return Aggregate(entries, a)
+	}
+
+	assert.Equal(t, []Group{{Count: 4, Value: 4}}, aggregate("count", "", ""))
+	assert.Equal(t, []Group{{Count: 4, Value: float64(45)}}, aggregate("sum", "size", ""))
+	assert.Equal(t, []Group{
+		{Key: "backups", Count: 1, Value: float64(30)},
+		{Key: "logs", Count: 2, Value: float64(15)},
+		{Key: nil, Count: 1, Value: float64(0)},
+	}, aggregate("sum", "size", "meta.bucket"))
+	assert.Equal(t, []Group{
+		{Key: "backups", Count: 1, Value: time.Unix(100, 0)},
+		{Key: "logs", Count: 2, Value: time.Unix(200, 0)},
+		{Key: nil, Count: 1, Value: nil},
+	}, aggregate("min", "mtime", "meta.bucket"))
+	assert.Equal(t, []Group{{Count: 4, Value: "d"}}, aggregate("max", "name", ""))
+
+	// Ungrouped aggregations of no entries have a single group
+	assert.Equal(t, []Group{{Count: 0, Value: 0}}, Aggregate(nil, Aggregation{Function: "count"}))
+}
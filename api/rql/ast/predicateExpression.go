@@ -44,3 +44,8 @@ func NPE_TimePredicate() rql.TimePredicate {
 func NPE_UnsignedNumericPredicate() rql.NumericPredicate {
 	return predicate.NPE_UnsignedNumericPredicate()
 }
+
+// NPE_ValuePredicate returns a node representing NPE ValuePredicate
+func NPE_ValuePredicate() rql.ValuePredicate {
+	return predicate.NPE_ValuePredicate()
+}
@@ -20,8 +20,33 @@ func Primary() rql.ASTNode {
 		primary.Mtime(NPE_TimePredicate()),
 		primary.Size(NPE_UnsignedNumericPredicate()),
 		primary.Meta(PE_Object()),
+		primary.Attr(NPE_ValuePredicate()),
+		primary.Stream(NPE_StringPredicate()),
+		primary.Content(NPE_StringPredicate()),
 		primary.Boolean(true),
 	)
 	nt.SetMatchErrMsg("expected a primary")
 	return nt
 }
+
+// PrimaryNames returns the names of the primaries that take a predicate,
+// i.e. all of the primaries except for the boolean primaries. Shell
+// completion uses it.
+func PrimaryNames() []string {
+	return []string{
+		"action",
+		"name",
+		"cname",
+		"path",
+		"kind",
+		"atime",
+		"crtime",
+		"ctime",
+		"mtime",
+		"size",
+		"meta",
+		"attr",
+		"stream",
+		"content",
+	}
+}
@@ -0,0 +1,63 @@
+package rql
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// contentSampler reads the lines of an entry's content for the content
+// primary. Like the streamSampler, it's created by the walker, which has the
+// request's context, and it reads the content at most once.
+type contentSampler struct {
+	ctx     context.Context
+	entry   plugin.Entry
+	maxSize int64
+	read    bool
+	lines   []string
+}
+
+func newContentSampler(ctx context.Context, entry plugin.Entry, maxSize int64) *contentSampler {
+	return &contentSampler{
+		ctx:     ctx,
+		entry:   entry,
+		maxSize: maxSize,
+	}
+}
+
+func (s *contentSampler) sample() []string {
+	if s.read {
+		return s.lines
+	}
+	s.read = true
+	lines, err := s.readLines()
+	if err != nil {
+		activity.Warnf(s.ctx, "RQL: could not read the content of %v: %v", plugin.ID(s.entry), err)
+	}
+	s.lines = lines
+	return lines
+}
+
+// readLines returns the lines in the first maxSize bytes of the entry's
+// content.
+func (s *contentSampler) readLines() ([]string, error) {
+	if s.maxSize <= 0 {
+		return nil, nil
+	}
+	data, err := plugin.Read(s.ctx, s.entry, s.maxSize, 0)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	// A line can be as long as the content that's read.
+	scanner.Buffer(make([]byte, 0, 64*1024), int(s.maxSize)+1)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines, scanner.Err()
+}
@@ -0,0 +1,73 @@
+package rql
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/puppetlabs/wash/datastore"
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/stretchr/testify/suite"
+)
+
+type ContentSampleTestSuite struct {
+	suite.Suite
+}
+
+func (s *ContentSampleTestSuite) SetupTest() {
+	plugin.SetTestCache(datastore.NewMemCache())
+}
+
+func (s *ContentSampleTestSuite) TearDownTest() {
+	plugin.UnsetTestCache()
+}
+
+func (s *ContentSampleTestSuite) TestSample() {
+	e := newMockReadableEntry("foo\nERROR: timeout\n", nil)
+	sampler := newContentSampler(context.Background(), e, DefaultContentMaxSize)
+	s.Equal([]string{"foo", "ERROR: timeout"}, sampler.sample())
+
+	// The sample is cached
+	s.Equal([]string{"foo", "ERROR: timeout"}, sampler.sample())
+	s.Equal(1, e.reads)
+}
+
+func (s *ContentSampleTestSuite) TestSample_StopsAtMaxSize() {
+	e := newMockReadableEntry("foo\nbar\nbaz\n", nil)
+	s.Equal([]string{"foo", "ba"}, newContentSampler(context.Background(), e, 6).sample())
+}
+
+func (s *ContentSampleTestSuite) TestSample_ReadErrors() {
+	e := newMockReadableEntry("", fmt.Errorf("failed to read"))
+	s.Empty(newContentSampler(context.Background(), e, DefaultContentMaxSize).sample())
+}
+
+func TestContentSample(t *testing.T) {
+	suite.Run(t, new(ContentSampleTestSuite))
+}
+
+type mockReadableEntry struct {
+	plugin.EntryBase
+	content string
+	err     error
+	reads   int
+}
+
+func newMockReadableEntry(content string, err error) *mockReadableEntry {
+	e := &mockReadableEntry{
+		EntryBase: plugin.NewEntry("foo"),
+		content:   content,
+		err:       err,
+	}
+	e.SetTestID("/foo")
+	return e
+}
+
+func (e *mockReadableEntry) Schema() *plugin.EntrySchema {
+	return nil
+}
+
+func (e *mockReadableEntry) Read(ctx context.Context) ([]byte, error) {
+	e.reads++
+	return []byte(e.content), e.err
+}
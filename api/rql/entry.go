@@ -1,6 +1,8 @@
 package rql
 
 import (
+	"time"
+
 	"github.com/getlantern/deepcopy"
 	apitypes "github.com/puppetlabs/wash/api/types"
 	"github.com/puppetlabs/wash/plugin"
@@ -31,6 +33,8 @@ type Entry struct {
 	apitypes.Entry
 	Schema      *EntrySchema
 	pluginEntry plugin.Entry
+	sampler     *streamSampler
+	content     *contentSampler
 }
 
 func newEntry(parent *Entry, pluginEntry plugin.Entry) Entry {
@@ -53,3 +57,21 @@ func newEntry(parent *Entry, pluginEntry plugin.Entry) Entry {
 func (e Entry) SchemaKnown() bool {
 	return e.Schema != nil
 }
+
+// StreamSample returns a bounded sample of the lines that the entry streamed
+// in the last window. It returns nil if the entry isn't streamable.
+func (e Entry) StreamSample(window time.Duration) []string {
+	if e.sampler == nil {
+		return nil
+	}
+	return e.sampler.sample(window)
+}
+
+// ContentLines returns the lines in a bounded prefix of the entry's content.
+// It returns nil if the entry isn't readable.
+func (e Entry) ContentLines() []string {
+	if e.content == nil {
+		return nil
+	}
+	return e.content.sample()
+}
@@ -0,0 +1,157 @@
+package rql
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// metaFieldPrefix is the prefix of fields that refer to an entry's metadata,
+// e.g. "meta.tags.owner".
+const metaFieldPrefix = "meta."
+
+// Field refers to one of an entry's values. It's one of "name", "cname",
+// "path", "size", "atime", "crtime", "ctime", "mtime", or a metadata path like
+// "meta.tags.owner", whose keys are separated by dots.
+type Field string
+
+// ParseField validates the given field.
+func ParseField(str string) (Field, error) {
+	switch str {
+	case "name", "cname", "path", "size", "atime", "crtime", "ctime", "mtime":
+		return Field(str), nil
+	}
+	if strings.HasPrefix(str, metaFieldPrefix) && len(str) > len(metaFieldPrefix) {
+		return Field(str), nil
+	}
+	return "", fmt.Errorf("%q is not a valid field, expected name, cname, path, size, atime, crtime, ctime, mtime or meta.<key>[.<key>...]", str)
+}
+
+// metaPath returns the field's metadata keys, or nil if it isn't a metadata
+// field.
+func (f Field) metaPath() []string {
+	if !strings.HasPrefix(string(f), metaFieldPrefix) {
+		return nil
+	}
+	return strings.Split(strings.TrimPrefix(string(f), metaFieldPrefix), ".")
+}
+
+// Value returns the entry's value of the field. It returns false if the entry
+// doesn't have the value, like an entry without a size or a missing metadata
+// key. Sizes are float64s like metadata numbers, and times are time.Times.
+func (f Field) Value(e Entry) (interface{}, bool) {
+	switch f {
+	case "name":
+		return e.Name, true
+	case "cname":
+		return e.CName, true
+	case "path":
+		return e.Path, true
+	case "size":
+		return float64(e.Attributes.Size()), e.Attributes.HasSize()
+	case "atime":
+		return e.Attributes.Atime(), e.Attributes.HasAtime()
+	case "crtime":
+		return e.Attributes.Crtime(), e.Attributes.HasCrtime()
+	case "ctime":
+		return e.Attributes.Ctime(), e.Attributes.HasCtime()
+	case "mtime":
+		return e.Attributes.Mtime(), e.Attributes.HasMtime()
+	}
+	return metaValue(e.Metadata, f.metaPath())
+}
+
+func metaValue(metadata plugin.JSONObject, path []string) (interface{}, bool) {
+	var v interface{} = metadata
+	for _, key := range path {
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if v, ok = obj[key]; !ok {
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// Project returns the subset of the metadata that's selected by the given
+// metadata fields, keeping their nesting. Fields that aren't in the metadata
+// are skipped.
+func Project(metadata plugin.JSONObject, fields []Field) plugin.JSONObject {
+	projected := plugin.JSONObject{}
+	for _, field := range fields {
+		path := field.metaPath()
+		if path == nil {
+			continue
+		}
+		v, ok := metaValue(metadata, path)
+		if !ok {
+			continue
+		}
+		obj := projected
+		for _, key := range path[:len(path)-1] {
+			child, ok := obj[key].(map[string]interface{})
+			if !ok {
+				child = make(map[string]interface{})
+				obj[key] = child
+			}
+			obj = child
+		}
+		obj[path[len(path)-1]] = v
+	}
+	return projected
+}
+
+// compareValues orders field values. Numbers, strings, times and booleans are
+// compared with values of the same type. Otherwise, values are ordered by
+// their type so that the order is still total.
+func compareValues(a, b interface{}) int {
+	ra, rb := valueRank(a), valueRank(b)
+	if ra != rb {
+		return ra - rb
+	}
+	switch at := a.(type) {
+	case float64:
+		bt := b.(float64)
+		if at < bt {
+			return -1
+		} else if at > bt {
+			return 1
+		}
+	case string:
+		return strings.Compare(at, b.(string))
+	case time.Time:
+		bt := b.(time.Time)
+		if at.Before(bt) {
+			return -1
+		} else if at.After(bt) {
+			return 1
+		}
+	case bool:
+		bt := b.(bool)
+		if !at && bt {
+			return -1
+		} else if at && !bt {
+			return 1
+		}
+	}
+	return 0
+}
+
+func valueRank(v interface{}) int {
+	switch v.(type) {
+	case float64:
+		return 0
+	case string:
+		return 1
+	case time.Time:
+		return 2
+	case bool:
+		return 3
+	default:
+		return 4
+	}
+}
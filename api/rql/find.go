@@ -18,3 +18,20 @@ import (
 func Find(ctx context.Context, start plugin.Entry, query Query, options Options) ([]Entry, error) {
 	return newWalker(query, options).Walk(ctx, start)
 }
+
+// Eval evaluates the query on the entry itself, unlike Find, which evaluates it
+// on the entry's descendants. It returns the entry and true if the entry
+// satisfies the query. The returned entry's path is "", like Find's start path.
+func Eval(ctx context.Context, entry plugin.Entry, query Query, options Options) (Entry, bool, error) {
+	e := newEntry(nil, entry)
+	s, err := plugin.Schema(entry)
+	if err != nil {
+		return Entry{}, false, err
+	}
+	if s != nil {
+		e.Schema = newEntrySchema(s)
+	}
+	options.Mindepth = 0
+	satisfied, err := (&walkerImpl{q: query, opts: options}).visit(ctx, &e, 0)
+	return e, satisfied, err
+}
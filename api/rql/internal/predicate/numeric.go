@@ -57,7 +57,12 @@ func (p *numeric) Unmarshal(input interface{}) error {
 	case string:
 		n, err = decimal.NewFromString(t)
 		if err != nil {
-			return fmt.Errorf("failed to parse %v as a number: %w", t, err)
+			// Unsigned numbers are sizes, so they can also have a unit
+			// suffix like "100M".
+			var ok bool
+			if n, ok = parseSize(t); !p.unsigned || !ok {
+				return fmt.Errorf("failed to parse %v as a number: %w", t, err)
+			}
 		}
 	default:
 		return fmt.Errorf("%v is not a valid number", t)
@@ -33,6 +33,23 @@ func (s *NumericTestSuite) TestUnmarshalErrors_Unsigned() {
 	s.UMETC(s.A("<", "-10"), "unsigned.*number", false)
 }
 
+func (s *NumericTestSuite) TestEvalNumeric_UnsignedSizes() {
+	s.NodeConstructor = func() rql.ASTNode {
+		return UnsignedNumeric("", s.N("0"))
+	}
+	ast := s.A(">", "1k")
+	s.ENFTC(ast, s.N("1024"))
+	s.ENTTC(ast, s.N("1025"))
+	ast = s.A("=", "100M")
+	s.ENTTC(ast, s.N("104857600"))
+	ast = s.A("=", "3c")
+	s.ENTTC(ast, s.N("3"))
+
+	// Signed numbers aren't sizes.
+	s.NodeConstructor = s.DefaultNodeConstructor
+	s.UMETC(s.A("<", "1k"), "parse.*1k.*number", false)
+}
+
 func (s *NumericTestSuite) TestEvalNumeric() {
 	// Test LT, and also test
 	ast := s.A("<", "1")
@@ -37,6 +37,15 @@ func (s *SizeTestSuite) TestEvalEntry() {
 	s.EETTC(ast, e)
 }
 
+func (s *SizeTestSuite) TestEvalEntry_Units() {
+	ast := s.A("size", s.A(">", "100M"))
+	e := rql.Entry{}
+	e.Attributes.SetSize(uint64(100 << 20))
+	s.EEFTC(ast, e)
+	e.Attributes.SetSize(uint64(100<<20 + 1))
+	s.EETTC(ast, e)
+}
+
 func (s *SizeTestSuite) TestExpression_Atom() {
 	s.NodeConstructor = func() rql.ASTNode {
 		return expression.New("size", true, func() rql.ASTNode {
@@ -23,9 +23,24 @@ func Time(op ComparisonOp, t time.Time) rql.TimePredicate {
 type tm struct {
 	op ComparisonOp
 	t  time.Time
+	// age is set if the time was specified as a duration like "2h". The
+	// compared time's computed from the duration when the predicate's
+	// evaluated, so that a parsed query that's evaluated repeatedly (e.g. by
+	// /fs/wait) doesn't compare against a stale time. ageStr and ageOp are
+	// kept so that the predicate marshals back to the duration.
+	age    time.Duration
+	ageStr string
+	ageOp  ComparisonOp
 }
 
+// now returns the reference time of durations. It's a variable so that the
+// tests can stub it.
+var now = time.Now
+
 func (p *tm) Marshal() interface{} {
+	if p.ageStr != "" {
+		return []interface{}{string(p.ageOp), p.ageStr}
+	}
 	return []interface{}{string(p.op), p.t}
 }
 
@@ -48,27 +63,55 @@ func (p *tm) Unmarshal(input interface{}) error {
 	if !comparisonOpMap[op] {
 		return fmt.Errorf("%v is not a valid comparison op", op)
 	}
+	if str, ok := array[1].(string); ok {
+		if d, ok := parseDuration(str); ok {
+			// A duration is compared with the time's age, so e.g. ["<", "2h"]
+			// is true for times less than 2 hours ago, i.e. after now - 2h.
+			p.op = flippedOps[op]
+			p.age = d
+			p.ageStr = str
+			p.ageOp = op
+			return nil
+		}
+	}
 	t, err := munge.ToTime(array[1])
 	if err != nil {
 		return err
 	}
 	p.op = op
 	p.t = t
+	p.age = 0
+	p.ageStr = ""
+	p.ageOp = ""
 	return nil
 }
 
+// flippedOps maps a comparison of ages to the equivalent comparison of times.
+var flippedOps = map[ComparisonOp]ComparisonOp{
+	LT:   GT,
+	LTE:  GTE,
+	GT:   LT,
+	GTE:  LTE,
+	EQL:  EQL,
+	NEQL: NEQL,
+}
+
 func (p *tm) EvalTime(t time.Time) bool {
+	ref := p.t
+	if p.ageStr != "" {
+		ref = now().Add(-p.age)
+	}
 	switch p.op {
 	case LT:
-		return t.Before(p.t)
+		return t.Before(ref)
 	case LTE:
-		return t.Before(p.t) || t.Equal(p.t)
+		return t.Before(ref) || t.Equal(ref)
 	case GT:
-		return t.After(p.t)
+		return t.After(ref)
 	case GTE:
-		return t.After(p.t) || t.Equal(p.t)
+		return t.After(ref) || t.Equal(ref)
 	case EQL:
-		return t.Equal(p.t)
+		return t.Equal(ref)
 	default:
 		// We should never hit this code path
 		panic(fmt.Sprintf("p.op (%v) is not a valid comparison operator", p.op))
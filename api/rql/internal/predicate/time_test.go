@@ -58,6 +58,44 @@ func (s *TimeTestSuite) TestEvalTime() {
 	s.ETTTC(ast, s.TM(2000))
 }
 
+func (s *TimeTestSuite) TestEvalTime_Durations() {
+	now = func() time.Time { return s.TM(100000) }
+	defer func() { now = time.Now }()
+
+	// Durations are compared with the time's age.
+	ast := s.A("<", "2h")
+	s.ETFTC(ast, s.TM(100000-7200), s.TM(0))
+	s.ETTTC(ast, s.TM(100000-7199), s.TM(100000))
+
+	ast = s.A(">=", "1d")
+	s.ETFTC(ast, s.TM(100000-86399))
+	s.ETTTC(ast, s.TM(100000-86400), s.TM(0))
+
+	ast = s.A("=", "1h30m")
+	s.ETTTC(ast, s.TM(100000-5400))
+
+	// The duration's preserved when marshalling.
+	node := s.NodeConstructor()
+	s.NoError(node.Unmarshal(s.A("<", "2h")))
+	s.Equal(s.A("<", "2h"), node.Marshal())
+}
+
+func (s *TimeTestSuite) TestEvalTime_DurationsAreRelativeToTheEvaluation() {
+	current := s.TM(100000)
+	now = func() time.Time { return current }
+	defer func() { now = time.Now }()
+
+	p := s.NodeConstructor().(rql.TimePredicate)
+	s.NoError(p.Unmarshal(s.A("<", "5m")))
+	s.True(p.EvalTime(s.TM(100000 - 60)))
+
+	// Advancing the clock ages the same time out of the window without
+	// re-parsing the predicate.
+	current = s.TM(100000 + 300)
+	s.False(p.EvalTime(s.TM(100000 - 60)))
+	s.True(p.EvalTime(s.TM(100000 + 250)))
+}
+
 func (s *TimeTestSuite) TestExpression_AtomAndNot() {
 	s.NodeConstructor = func() rql.ASTNode {
 		return expression.New("time", true, func() rql.ASTNode {
@@ -0,0 +1,68 @@
+package predicate
+
+import (
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// The size and duration units match the ones accepted by `wash find`'s
+// -size and time primaries, so that its queries translate directly to RQL.
+
+var bytesOf = map[byte]int64{
+	'c': 1,
+	'k': 1 << 10,
+	'M': 1 << 20,
+	'G': 1 << 30,
+	'T': 1 << 40,
+	'P': 1 << 50,
+}
+
+var sizeRegex = regexp.MustCompile(`^\d+[ckMGTP]$`)
+
+// parseSize parses a size with a unit suffix, e.g. "100M". The units are
+// c (bytes), k (kibibytes), M (mebibytes), G (gibibytes), T (tebibytes) and
+// P (pebibytes). It returns false if str isn't a size.
+func parseSize(str string) (decimal.Decimal, bool) {
+	if !sizeRegex.MatchString(str) {
+		return decimal.Decimal{}, false
+	}
+	endIx := len(str) - 1
+	n, err := decimal.NewFromString(str[:endIx])
+	if err != nil {
+		return decimal.Decimal{}, false
+	}
+	return n.Mul(decimal.NewFromInt(bytesOf[str[endIx]])), true
+}
+
+var durationOf = map[byte]time.Duration{
+	's': time.Second,
+	'm': time.Minute,
+	'h': time.Hour,
+	'd': 24 * time.Hour,
+	'w': 7 * 24 * time.Hour,
+}
+
+var durationChunkRegex = regexp.MustCompile(`\d+[smhdw]`)
+var durationRegex = regexp.MustCompile("^(" + durationChunkRegex.String() + ")+$")
+
+// parseDuration parses a duration like "2h" or "1d12h". The units are
+// s (seconds), m (minutes), h (hours), d (days) and w (weeks). It returns
+// false if str isn't a duration.
+func parseDuration(str string) (time.Duration, bool) {
+	if !durationRegex.MatchString(str) {
+		return 0, false
+	}
+	var d time.Duration
+	for _, chunk := range durationChunkRegex.FindAllString(str, -1) {
+		endIx := len(chunk) - 1
+		n, err := strconv.ParseInt(chunk[:endIx], 10, 64)
+		if err != nil {
+			return 0, false
+		}
+		d += time.Duration(n) * durationOf[chunk[endIx]]
+	}
+	return d, true
+}
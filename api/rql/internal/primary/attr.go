@@ -0,0 +1,68 @@
+package primary
+
+import (
+	"fmt"
+
+	"github.com/puppetlabs/wash/api/rql"
+	"github.com/puppetlabs/wash/api/rql/internal/errz"
+	"github.com/puppetlabs/wash/api/rql/internal/matcher"
+)
+
+// Attr constructs a predicate on one of the entry's custom attributes.
+// Custom attributes are declared in the entry's schema.
+func Attr(p rql.ValuePredicate) rql.Primary {
+	return &attr{
+		p: p,
+	}
+}
+
+type attr struct {
+	name string
+	p    rql.ValuePredicate
+}
+
+func (p *attr) Marshal() interface{} {
+	return []interface{}{"attr", p.name, p.p.Marshal()}
+}
+
+func (p *attr) Unmarshal(input interface{}) error {
+	errMsgPrefix := "attr: must be formatted as [\"attr\", <name>, NPE ValuePredicate]"
+	if !matcher.Array(matcher.Value("attr"))(input) {
+		return errz.MatchErrorf(errMsgPrefix)
+	}
+	array := input.([]interface{})
+	if len(array) > 3 {
+		return fmt.Errorf(errMsgPrefix)
+	}
+	if len(array) < 2 {
+		return fmt.Errorf("%v (missing the name)", errMsgPrefix)
+	}
+	name, ok := array[1].(string)
+	if !ok || len(name) <= 0 {
+		return fmt.Errorf("%v (the name must be a non-empty string)", errMsgPrefix)
+	}
+	if len(array) < 3 {
+		return fmt.Errorf("%v (missing NPE ValuePredicate)", errMsgPrefix)
+	}
+	if err := p.p.Unmarshal(array[2]); err != nil {
+		return fmt.Errorf("attr: error unmarshalling the NPE ValuePredicate: %w", err)
+	}
+	p.name = name
+	return nil
+}
+
+func (p *attr) IsPrimary() bool {
+	return true
+}
+
+func (p *attr) EvalEntry(e rql.Entry) bool {
+	return e.Attributes.HasCustom(p.name) && p.p.EvalValue(e.Attributes.Custom(p.name))
+}
+
+func (p *attr) EvalEntrySchema(s *rql.EntrySchema) bool {
+	schema := s.GetCustomAttribute(p.name)
+	return schema != nil && p.p.EvalValueSchema(schema.Type().JSONSchema())
+}
+
+var _ = rql.EntryPredicate(&attr{})
+var _ = rql.EntrySchemaPredicate(&attr{})
@@ -0,0 +1,102 @@
+package primary
+
+import (
+	"testing"
+
+	"github.com/puppetlabs/wash/api/rql"
+	"github.com/puppetlabs/wash/api/rql/ast/asttest"
+	"github.com/puppetlabs/wash/api/rql/internal/predicate"
+	"github.com/puppetlabs/wash/api/rql/internal/predicate/expression"
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/stretchr/testify/suite"
+)
+
+type AttrTestSuite struct {
+	asttest.Suite
+}
+
+func (s *AttrTestSuite) TestMarshal() {
+	p := Attr(predicate.NPE_ValuePredicate())
+	input := s.A("attr", "region", s.A("string", s.A("=", "us-west-1")))
+	s.MUM(p, input)
+	s.MTC(p, input)
+}
+
+func (s *AttrTestSuite) TestUnmarshalErrors() {
+	s.UMETC("foo", `attr.*formatted.*"attr".*<name>.*NPE ValuePredicate`, true)
+	s.UMETC(s.A("foo", "restarts", true), `attr.*formatted.*"attr".*<name>.*NPE ValuePredicate`, true)
+	s.UMETC(s.A("attr", "restarts", true, "bar"), `attr.*formatted.*"attr".*<name>.*NPE ValuePredicate`, false)
+	s.UMETC(s.A("attr"), `attr.*formatted.*missing.*name`, false)
+	s.UMETC(s.A("attr", 5, true), `attr.*formatted.*name.*non-empty string`, false)
+	s.UMETC(s.A("attr", "", true), `attr.*formatted.*name.*non-empty string`, false)
+	s.UMETC(s.A("attr", "restarts"), `attr.*formatted.*missing.*NPE ValuePredicate`, false)
+	s.UMETC(s.A("attr", "restarts", s.A("number")), `attr.*NPE ValuePredicate`, false)
+}
+
+func (s *AttrTestSuite) TestEvalEntry() {
+	ast := s.A("attr", "restarts", s.A("number", s.A(">", float64(5))))
+	e := rql.Entry{}
+	s.EEFTC(ast, e)
+	e.Attributes.SetCustom("region", "us-west-1")
+	s.EEFTC(ast, e)
+	e.Attributes.SetCustom("restarts", 5)
+	s.EEFTC(ast, e)
+	e.Attributes.SetCustom("restarts", 6)
+	s.EETTC(ast, e)
+}
+
+func (s *AttrTestSuite) TestEvalEntrySchema() {
+	ast := s.A("attr", "restarts", s.A("number", s.A(">", float64(5))))
+	schema := &rql.EntrySchema{}
+	s.EESFTC(ast, schema)
+
+	region := plugin.CustomAttributeSchema{}
+	region.SetName("region").SetType(plugin.StringAttribute)
+	schema.SetCustomAttributes([]plugin.CustomAttributeSchema{region})
+	s.EESFTC(ast, schema)
+
+	restarts := plugin.CustomAttributeSchema{}
+	restarts.SetName("restarts").SetType(plugin.NumberAttribute)
+	schema.SetCustomAttributes([]plugin.CustomAttributeSchema{region, restarts})
+	s.EESTTC(ast, schema)
+}
+
+func (s *AttrTestSuite) TestExpression_Atom() {
+	s.NodeConstructor = func() rql.ASTNode {
+		return expression.New("attr", false, func() rql.ASTNode {
+			return Attr(predicate.NPE_ValuePredicate())
+		})
+	}
+
+	ast := s.A("attr", "region", s.A("string", s.A("=", "us-west-1")))
+	e := rql.Entry{}
+	s.EEFTC(ast, e)
+	e.Attributes.SetCustom("region", "us-east-1")
+	s.EEFTC(ast, e)
+	e.Attributes.SetCustom("region", "us-west-1")
+	s.EETTC(ast, e)
+
+	schema := &rql.EntrySchema{}
+	s.EESFTC(ast, schema)
+	region := plugin.CustomAttributeSchema{}
+	region.SetName("region").SetType(plugin.StringAttribute)
+	schema.SetCustomAttributes([]plugin.CustomAttributeSchema{region})
+	s.EESTTC(ast, schema)
+
+	s.AssertNotImplemented(
+		ast,
+		asttest.ValuePredicateC,
+		asttest.StringPredicateC,
+		asttest.NumericPredicateC,
+		asttest.TimePredicateC,
+		asttest.ActionPredicateC,
+	)
+}
+
+func TestAttr(t *testing.T) {
+	s := new(AttrTestSuite)
+	s.DefaultNodeConstructor = func() rql.ASTNode {
+		return Attr(predicate.NPE_ValuePredicate())
+	}
+	suite.Run(t, s)
+}
@@ -0,0 +1,70 @@
+package primary
+
+import (
+	"fmt"
+
+	"github.com/puppetlabs/wash/api/rql"
+	"github.com/puppetlabs/wash/api/rql/internal/errz"
+	"github.com/puppetlabs/wash/api/rql/internal/matcher"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// Content constructs a predicate on the lines of an entry's content, like
+// grep. It's true if any of the lines satisfy p. Only a bounded prefix of the
+// content is read, see rql.Options#ContentMaxSize.
+func Content(p rql.StringPredicate) rql.Primary {
+	return &content{
+		p: p,
+	}
+}
+
+type content struct {
+	p rql.StringPredicate
+}
+
+func (p *content) Marshal() interface{} {
+	return []interface{}{"content", p.p.Marshal()}
+}
+
+func (p *content) Unmarshal(input interface{}) error {
+	errMsgPrefix := "content: must be formatted as [\"content\", NPE StringPredicate]"
+	if !matcher.Array(matcher.Value("content"))(input) {
+		return errz.MatchErrorf(errMsgPrefix)
+	}
+	array := input.([]interface{})
+	if len(array) > 2 {
+		return fmt.Errorf(errMsgPrefix)
+	}
+	if len(array) < 2 {
+		return fmt.Errorf("%v (missing NPE StringPredicate)", errMsgPrefix)
+	}
+	if err := p.p.Unmarshal(array[1]); err != nil {
+		return fmt.Errorf("content: error unmarshalling the NPE StringPredicate: %w", err)
+	}
+	return nil
+}
+
+func (p *content) IsPrimary() bool {
+	return true
+}
+
+func (p *content) EvalEntry(e rql.Entry) bool {
+	for _, line := range e.ContentLines() {
+		if p.p.EvalString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *content) EvalEntrySchema(s *rql.EntrySchema) bool {
+	for _, action := range s.Actions() {
+		if action == plugin.ReadAction().Name {
+			return true
+		}
+	}
+	return false
+}
+
+var _ = rql.EntryPredicate(&content{})
+var _ = rql.EntrySchemaPredicate(&content{})
@@ -0,0 +1,52 @@
+package primary
+
+import (
+	"testing"
+
+	"github.com/puppetlabs/wash/api/rql"
+	"github.com/puppetlabs/wash/api/rql/ast/asttest"
+	"github.com/puppetlabs/wash/api/rql/internal/predicate"
+	"github.com/stretchr/testify/suite"
+)
+
+type ContentTestSuite struct {
+	asttest.Suite
+}
+
+func (s *ContentTestSuite) TestMarshal() {
+	p := Content(predicate.NPE_StringPredicate())
+	input := s.A("content", s.A("regex", "ERROR.*timeout"))
+	s.MUM(p, input)
+	s.MTC(p, input)
+}
+
+func (s *ContentTestSuite) TestUnmarshalErrors() {
+	s.UMETC("foo", `content.*formatted.*"content".*NPE StringPredicate`, true)
+	s.UMETC(s.A("foo", s.A("glob", "foo")), `content.*formatted.*"content".*NPE StringPredicate`, true)
+	s.UMETC(s.A("content", s.A("glob", "foo"), "bar"), `content.*formatted.*"content".*NPE StringPredicate`, false)
+	s.UMETC(s.A("content"), `content.*formatted.*missing.*NPE StringPredicate`, false)
+	s.UMETC(s.A("content", s.A("regex", "[")), `content.*NPE StringPredicate.*invalid.*regex`, false)
+}
+
+func (s *ContentTestSuite) TestEvalEntry_NotReadable() {
+	// Entries that weren't visited by the walker don't have any content.
+	ast := s.A("content", s.A("glob", "*"))
+	s.EEFTC(ast, rql.Entry{})
+}
+
+func (s *ContentTestSuite) TestEvalEntrySchema() {
+	ast := s.A("content", s.A("glob", "*ERROR*"))
+	schema := &rql.EntrySchema{}
+	schema.SetActions([]string{"list", "stream"})
+	s.EESFTC(ast, schema)
+	schema.SetActions([]string{"read", "stream"})
+	s.EESTTC(ast, schema)
+}
+
+func TestContent(t *testing.T) {
+	s := new(ContentTestSuite)
+	s.DefaultNodeConstructor = func() rql.ASTNode {
+		return Content(predicate.NPE_StringPredicate())
+	}
+	suite.Run(t, s)
+}
@@ -24,7 +24,9 @@ func (s *KindTestSuite) TestUnmarshal() {
 	s.UMETC(s.A("kind", "foo", "bar"), `kind.*formatted.*"kind".*NPE StringPredicate`, false)
 	s.UMETC(s.A("kind"), `kind.*formatted.*"kind".*NPE StringPredicate.*missing.*NPE StringPredicate`, false)
 	s.UMETC(s.A("kind", s.A("glob", "[")), "kind.*NPE StringPredicate.*glob", false)
+	s.UMETC(s.A("kind", s.A("regex", "[")), "kind.*NPE StringPredicate.*invalid.*regex", false)
 }
+
 func (s *KindTestSuite) TestEvalEntrySchema() {
 	ast := s.A("kind", s.A("glob", "foo"))
 	schema := &rql.EntrySchema{}
@@ -34,6 +36,15 @@ func (s *KindTestSuite) TestEvalEntrySchema() {
 	s.EESTTC(ast, schema)
 }
 
+func (s *KindTestSuite) TestEvalEntrySchema_Regex() {
+	ast := s.A("kind", s.A("regex", "^docker/containers/[^/]+$"))
+	schema := &rql.EntrySchema{}
+	schema.SetPath("docker/containers/container/fs")
+	s.EESFTC(ast, schema)
+	schema.SetPath("docker/containers/container")
+	s.EESTTC(ast, schema)
+}
+
 func (s *KindTestSuite) TestExpression_Atom() {
 	s.NodeConstructor = func() rql.ASTNode {
 		return expression.New("kind", false, func() rql.ASTNode {
@@ -24,6 +24,7 @@ func (s *NameTestSuite) TestUnmarshal() {
 	s.UMETC(s.A("name", "foo", "bar"), `name.*formatted.*"name".*NPE StringPredicate`, false)
 	s.UMETC(s.A("name"), `name.*formatted.*"name".*NPE StringPredicate.*missing.*NPE StringPredicate`, false)
 	s.UMETC(s.A("name", s.A("glob", "[")), "name.*NPE StringPredicate.*glob", false)
+	s.UMETC(s.A("name", s.A("regex", "[")), "name.*NPE StringPredicate.*invalid.*regex", false)
 }
 
 func (s *NameTestSuite) TestEvalEntry() {
@@ -35,6 +36,15 @@ func (s *NameTestSuite) TestEvalEntry() {
 	s.EETTC(ast, e)
 }
 
+func (s *NameTestSuite) TestEvalEntry_Regex() {
+	ast := s.A("name", s.A("regex", "^fo+$"))
+	e := rql.Entry{}
+	e.Name = "foobar"
+	s.EEFTC(ast, e)
+	e.Name = "fooo"
+	s.EETTC(ast, e)
+}
+
 func (s *NameTestSuite) TestExpression_Atom() {
 	s.NodeConstructor = func() rql.ASTNode {
 		return expression.New("name", false, func() rql.ASTNode {
@@ -24,6 +24,7 @@ func (s *PathTestSuite) TestUnmarshal() {
 	s.UMETC(s.A("path", "foo", "bar"), `path.*formatted.*"path".*NPE StringPredicate`, false)
 	s.UMETC(s.A("path"), `path.*formatted.*"path".*NPE StringPredicate.*missing.*NPE StringPredicate`, false)
 	s.UMETC(s.A("path", s.A("glob", "[")), "path.*NPE StringPredicate.*glob", false)
+	s.UMETC(s.A("path", s.A("regex", "[")), "path.*NPE StringPredicate.*invalid.*regex", false)
 }
 
 func (s *PathTestSuite) TestEvalEntry() {
@@ -35,6 +36,15 @@ func (s *PathTestSuite) TestEvalEntry() {
 	s.EETTC(ast, e)
 }
 
+func (s *PathTestSuite) TestEvalEntry_Regex() {
+	ast := s.A("path", s.A("regex", "^fo+$"))
+	e := rql.Entry{}
+	e.Path = "foobar"
+	s.EEFTC(ast, e)
+	e.Path = "fooo"
+	s.EETTC(ast, e)
+}
+
 func (s *PathTestSuite) TestExpression_Atom() {
 	s.NodeConstructor = func() rql.ASTNode {
 		return expression.New("path", false, func() rql.ASTNode {
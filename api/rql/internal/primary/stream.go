@@ -0,0 +1,84 @@
+package primary
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/puppetlabs/wash/api/rql"
+	"github.com/puppetlabs/wash/api/rql/internal/errz"
+	"github.com/puppetlabs/wash/api/rql/internal/matcher"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// Stream constructs a predicate on the lines that an entry streamed within a
+// recent time window, like a container log's lines from the last 10 minutes.
+// It's true if any of the lines satisfy p.
+func Stream(p rql.StringPredicate) rql.Primary {
+	return &stream{
+		p: p,
+	}
+}
+
+type stream struct {
+	window time.Duration
+	p      rql.StringPredicate
+}
+
+func (p *stream) Marshal() interface{} {
+	return []interface{}{"stream", p.window.String(), p.p.Marshal()}
+}
+
+func (p *stream) Unmarshal(input interface{}) error {
+	errMsgPrefix := "stream: must be formatted as [\"stream\", <window>, NPE StringPredicate]"
+	if !matcher.Array(matcher.Value("stream"))(input) {
+		return errz.MatchErrorf(errMsgPrefix)
+	}
+	array := input.([]interface{})
+	if len(array) > 3 {
+		return fmt.Errorf(errMsgPrefix)
+	}
+	if len(array) < 2 {
+		return fmt.Errorf("%v (missing the window)", errMsgPrefix)
+	}
+	windowStr, ok := array[1].(string)
+	if !ok {
+		return fmt.Errorf("%v (the window must be a duration string like \"10m\")", errMsgPrefix)
+	}
+	window, err := time.ParseDuration(windowStr)
+	if err != nil || window <= 0 {
+		return fmt.Errorf("%v (the window must be a positive duration like \"10m\")", errMsgPrefix)
+	}
+	if len(array) < 3 {
+		return fmt.Errorf("%v (missing NPE StringPredicate)", errMsgPrefix)
+	}
+	if err := p.p.Unmarshal(array[2]); err != nil {
+		return fmt.Errorf("stream: error unmarshalling the NPE StringPredicate: %w", err)
+	}
+	p.window = window
+	return nil
+}
+
+func (p *stream) IsPrimary() bool {
+	return true
+}
+
+func (p *stream) EvalEntry(e rql.Entry) bool {
+	for _, line := range e.StreamSample(p.window) {
+		if p.p.EvalString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *stream) EvalEntrySchema(s *rql.EntrySchema) bool {
+	for _, action := range s.Actions() {
+		if action == plugin.StreamAction().Name {
+			return true
+		}
+	}
+	return false
+}
+
+var _ = rql.EntryPredicate(&stream{})
+var _ = rql.EntrySchemaPredicate(&stream{})
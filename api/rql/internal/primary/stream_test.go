@@ -0,0 +1,56 @@
+package primary
+
+import (
+	"testing"
+
+	"github.com/puppetlabs/wash/api/rql"
+	"github.com/puppetlabs/wash/api/rql/ast/asttest"
+	"github.com/puppetlabs/wash/api/rql/internal/predicate"
+	"github.com/stretchr/testify/suite"
+)
+
+type StreamTestSuite struct {
+	asttest.Suite
+}
+
+func (s *StreamTestSuite) TestMarshal() {
+	p := Stream(predicate.NPE_StringPredicate())
+	input := s.A("stream", "10m0s", s.A("glob", "*ERROR*"))
+	s.MUM(p, input)
+	s.MTC(p, input)
+}
+
+func (s *StreamTestSuite) TestUnmarshalErrors() {
+	s.UMETC("foo", `stream.*formatted.*"stream".*<window>.*NPE StringPredicate`, true)
+	s.UMETC(s.A("foo", "10m", true), `stream.*formatted.*"stream".*<window>.*NPE StringPredicate`, true)
+	s.UMETC(s.A("stream", "10m", s.A("glob", "foo"), "bar"), `stream.*formatted.*"stream".*<window>.*NPE StringPredicate`, false)
+	s.UMETC(s.A("stream"), `stream.*formatted.*missing.*window`, false)
+	s.UMETC(s.A("stream", 10, s.A("glob", "foo")), `stream.*formatted.*window.*duration`, false)
+	s.UMETC(s.A("stream", "foo", s.A("glob", "foo")), `stream.*formatted.*window.*positive duration`, false)
+	s.UMETC(s.A("stream", "-10m", s.A("glob", "foo")), `stream.*formatted.*window.*positive duration`, false)
+	s.UMETC(s.A("stream", "10m"), `stream.*formatted.*missing.*NPE StringPredicate`, false)
+	s.UMETC(s.A("stream", "10m", s.A("glob")), `stream.*NPE StringPredicate`, false)
+}
+
+func (s *StreamTestSuite) TestEvalEntry_NotStreamable() {
+	// Entries that weren't visited by the walker don't have a stream sample.
+	ast := s.A("stream", "10m", s.A("glob", "*"))
+	s.EEFTC(ast, rql.Entry{})
+}
+
+func (s *StreamTestSuite) TestEvalEntrySchema() {
+	ast := s.A("stream", "10m", s.A("glob", "*ERROR*"))
+	schema := &rql.EntrySchema{}
+	schema.SetActions([]string{"read"})
+	s.EESFTC(ast, schema)
+	schema.SetActions([]string{"read", "stream"})
+	s.EESTTC(ast, schema)
+}
+
+func TestStream(t *testing.T) {
+	s := new(StreamTestSuite)
+	s.DefaultNodeConstructor = func() rql.ASTNode {
+		return Stream(predicate.NPE_StringPredicate())
+	}
+	suite.Run(t, s)
+}
@@ -21,17 +21,25 @@ type Options struct {
 	// where N is the number of visited entries. Using the partial metadata (unsetting Fullmeta)
 	// does not result in any extra request.
 	Fullmeta bool
+	// ContentMaxSize is the maximum number of bytes of an entry's content that the
+	// content primary reads. Content past it isn't matched. Reading content requires
+	// an extra request for each entry that the content primary's evaluated on.
+	ContentMaxSize int64
 }
 
 // DefaultMaxdepth is the default value of the maxdepth option.
 // It is set to the max value of a 32-bit integer.
 const DefaultMaxdepth = 1<<31 - 1
 
+// DefaultContentMaxSize is the default value of the ContentMaxSize option (1 MiB).
+const DefaultContentMaxSize = 1 << 20
+
 // NewOptions creates a new Options object
 func NewOptions() Options {
 	return Options{
-		Mindepth: 0,
-		Maxdepth: DefaultMaxdepth,
-		Fullmeta: false,
+		Mindepth:       0,
+		Maxdepth:       DefaultMaxdepth,
+		Fullmeta:       false,
+		ContentMaxSize: DefaultContentMaxSize,
 	}
 }
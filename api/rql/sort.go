@@ -0,0 +1,55 @@
+package rql
+
+import (
+	"sort"
+	"strings"
+)
+
+// SortKey orders entries by one of their fields.
+type SortKey struct {
+	Field      Field
+	Descending bool
+}
+
+// ParseSortKey parses a sort key. It's a field, optionally prefixed with "-"
+// to sort in descending order, e.g. "-size" or "meta.tags.owner".
+func ParseSortKey(str string) (SortKey, error) {
+	key := SortKey{}
+	if strings.HasPrefix(str, "-") {
+		key.Descending = true
+		str = str[1:]
+	}
+	field, err := ParseField(str)
+	if err != nil {
+		return SortKey{}, err
+	}
+	key.Field = field
+	return key, nil
+}
+
+// Sort sorts the entries by the given keys. Later keys break the ties of
+// earlier keys, and the entries' paths break the remaining ties. Entries that
+// don't have a key's value come after the entries that do, regardless of the
+// key's direction.
+func Sort(entries []Entry, keys []SortKey) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		for _, key := range keys {
+			a, aOK := key.Field.Value(entries[i])
+			b, bOK := key.Field.Value(entries[j])
+			if aOK != bOK {
+				return aOK
+			}
+			if !aOK {
+				continue
+			}
+			c := compareValues(a, b)
+			if key.Descending {
+				c = -c
+			}
+			if c != 0 {
+				return c < 0
+			}
+		}
+		return entries[i].Path < entries[j].Path
+	})
+}
@@ -0,0 +1,88 @@
+package rql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSortTestEntry(path string, size uint64, mtime int64, meta plugin.JSONObject) Entry {
+	e := Entry{}
+	e.Path = path
+	e.Name = path
+	if size > 0 {
+		e.Attributes.SetSize(size)
+	}
+	if mtime > 0 {
+		e.Attributes.SetMtime(time.Unix(mtime, 0))
+	}
+	e.Metadata = meta
+	return e
+}
+
+func paths(entries []Entry) []string {
+	var ps []string
+	for _, e := range entries {
+		ps = append(ps, e.Path)
+	}
+	return ps
+}
+
+func TestParseSortKey(t *testing.T) {
+	key, err := ParseSortKey("-size")
+	require.NoError(t, err)
+	assert.Equal(t, SortKey{Field: "size", Descending: true}, key)
+
+	key, err = ParseSortKey("meta.tags.owner")
+	require.NoError(t, err)
+	assert.Equal(t, SortKey{Field: "meta.tags.owner"}, key)
+
+	_, err = ParseSortKey("bogus")
+	assert.Regexp(t, "bogus.*not a valid field", err)
+	_, err = ParseSortKey("meta.")
+	assert.Regexp(t, "not a valid field", err)
+}
+
+func TestSort(t *testing.T) {
+	entries := []Entry{
+		newSortTestEntry("c", 10, 300, plugin.JSONObject{"tags": map[string]interface{}{"owner": "bob"}}),
+		newSortTestEntry("a", 30, 100, plugin.JSONObject{"tags": map[string]interface{}{"owner": "alice"}}),
+		newSortTestEntry("d", 0, 0, nil),
+		newSortTestEntry("b", 10, 200, plugin.JSONObject{"tags": map[string]interface{}{"owner": "alice"}}),
+	}
+
+	Sort(entries, []SortKey{{Field: "name"}})
+	assert.Equal(t, []string{"a", "b", "c", "d"}, paths(entries))
+
+	// Entries without a size come last, and ties are broken by the next key
+	// and then by the path.
+	Sort(entries, []SortKey{{Field: "size", Descending: true}, {Field: "mtime"}})
+	assert.Equal(t, []string{"a", "b", "c", "d"}, paths(entries))
+	Sort(entries, []SortKey{{Field: "size"}})
+	assert.Equal(t, []string{"b", "c", "a", "d"}, paths(entries))
+
+	Sort(entries, []SortKey{{Field: "mtime", Descending: true}})
+	assert.Equal(t, []string{"c", "b", "a", "d"}, paths(entries))
+
+	Sort(entries, []SortKey{{Field: "meta.tags.owner"}, {Field: "mtime", Descending: true}})
+	assert.Equal(t, []string{"b", "a", "c", "d"}, paths(entries))
+}
+
+func TestProject(t *testing.T) {
+	meta := plugin.JSONObject{
+		"region": "us-west-1",
+		"tags":   map[string]interface{}{"owner": "alice", "team": "infra"},
+		"state":  map[string]interface{}{"name": "running"},
+	}
+	fields := []Field{"meta.region", "meta.tags.owner", "meta.missing", "meta.region.nested"}
+	assert.Equal(t, plugin.JSONObject{
+		"region": "us-west-1",
+		"tags":   map[string]interface{}{"owner": "alice"},
+	}, Project(meta, fields))
+
+	// The original metadata is unchanged
+	assert.Len(t, meta["tags"], 2)
+}
@@ -0,0 +1,106 @@
+package rql
+
+import (
+	"bufio"
+	"context"
+	"time"
+
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// The stream primary's sampling bounds. Sampling an entry's stream stops once
+// no updates arrive for streamSampleIdleTimeout, or once streamSampleTimeout
+// elapses, whichever's first. Only the most recent streamSampleMaxLines lines
+// are kept. These bound how long a query can spend on each entry since streams
+// like logs are followed and so never end on their own. They're variables so
+// that the tests can mock them.
+var (
+	streamSampleIdleTimeout = 1 * time.Second
+	streamSampleTimeout     = 10 * time.Second
+	streamSampleMaxLines    = 1000
+)
+
+// streamSampler samples an entry's recent stream updates. It's created by the
+// walker, which has the request's context, and caches each window's sample so
+// that an entry's stream is read at most once per window.
+type streamSampler struct {
+	ctx     context.Context
+	entry   plugin.Streamable
+	samples map[time.Duration][]string
+}
+
+func newStreamSampler(ctx context.Context, entry plugin.Streamable) *streamSampler {
+	return &streamSampler{
+		ctx:     ctx,
+		entry:   entry,
+		samples: make(map[time.Duration][]string),
+	}
+}
+
+func (s *streamSampler) sample(window time.Duration) []string {
+	if lines, ok := s.samples[window]; ok {
+		return lines
+	}
+	lines, err := s.readLines(window)
+	if err != nil {
+		activity.Warnf(s.ctx, "RQL: could not sample the updates streamed by %v in the last %v: %v", plugin.ID(s.entry), window, err)
+	}
+	s.samples[window] = lines
+	return lines
+}
+
+// readLines returns the lines streamed by the entry since window ago. Entries
+// that don't support StreamSince only stream new updates, so their sample only
+// includes the updates that arrive while it's taken.
+func (s *streamSampler) readLines(window time.Duration) ([]string, error) {
+	ctx, cancel := context.WithTimeout(s.ctx, streamSampleTimeout)
+	defer cancel()
+	ctx = plugin.WithStreamSince(ctx, time.Now().Add(-window))
+	rdr, err := plugin.Stream(ctx, s.entry)
+	if err != nil {
+		return nil, err
+	}
+	defer rdr.Close()
+
+	linesCh := make(chan string)
+	errCh := make(chan error, 1)
+	go func() {
+		scanner := bufio.NewScanner(rdr)
+		for scanner.Scan() {
+			select {
+			case linesCh <- scanner.Text():
+			case <-ctx.Done():
+				return
+			}
+		}
+		errCh <- scanner.Err()
+	}()
+
+	var lines []string
+	idle := time.NewTimer(streamSampleIdleTimeout)
+	defer idle.Stop()
+	for {
+		select {
+		case line := <-linesCh:
+			lines = append(lines, line)
+			if len(lines) > streamSampleMaxLines {
+				lines = lines[1:]
+			}
+			if !idle.Stop() {
+				<-idle.C
+			}
+			idle.Reset(streamSampleIdleTimeout)
+		case err := <-errCh:
+			return lines, err
+		case <-idle.C:
+			return lines, nil
+		case <-ctx.Done():
+			if s.ctx.Err() != nil {
+				return lines, s.ctx.Err()
+			}
+			// The sample's timeout elapsed
+			return lines, nil
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package rql
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/stretchr/testify/suite"
+)
+
+type StreamSampleTestSuite struct {
+	suite.Suite
+	idleTimeout time.Duration
+	maxLines    int
+}
+
+func (s *StreamSampleTestSuite) SetupTest() {
+	s.idleTimeout, s.maxLines = streamSampleIdleTimeout, streamSampleMaxLines
+	streamSampleIdleTimeout = 50 * time.Millisecond
+}
+
+func (s *StreamSampleTestSuite) TearDownTest() {
+	streamSampleIdleTimeout, streamSampleMaxLines = s.idleTimeout, s.maxLines
+}
+
+func (s *StreamSampleTestSuite) TestSample_EndedStream() {
+	e := newMockStreamableEntry(func(ctx context.Context) (io.ReadCloser, error) {
+		since, ok := plugin.StreamSince(ctx)
+		s.True(ok)
+		s.WithinDuration(time.Now().Add(-10*time.Minute), since, time.Minute)
+		return ioutil.NopCloser(strings.NewReader("foo\nERROR: bar\n")), nil
+	})
+	sampler := newStreamSampler(context.Background(), e)
+	s.Equal([]string{"foo", "ERROR: bar"}, sampler.sample(10*time.Minute))
+
+	// The sample is cached
+	s.Equal([]string{"foo", "ERROR: bar"}, sampler.sample(10*time.Minute))
+	s.Equal(1, e.streams)
+}
+
+func (s *StreamSampleTestSuite) TestSample_FollowedStream() {
+	r, w := io.Pipe()
+	defer w.Close()
+	e := newMockStreamableEntry(func(ctx context.Context) (io.ReadCloser, error) {
+		go func() {
+			_, _ = w.Write([]byte("foo\nbar\n"))
+		}()
+		return r, nil
+	})
+	// Following the stream stops once it's idle
+	lines := newStreamSampler(context.Background(), e).sample(time.Minute)
+	s.Equal([]string{"foo", "bar"}, lines)
+}
+
+func (s *StreamSampleTestSuite) TestSample_KeepsTheMostRecentLines() {
+	streamSampleMaxLines = 2
+	e := newMockStreamableEntry(func(ctx context.Context) (io.ReadCloser, error) {
+		return ioutil.NopCloser(strings.NewReader("1\n2\n3\n")), nil
+	})
+	s.Equal([]string{"2", "3"}, newStreamSampler(context.Background(), e).sample(time.Minute))
+}
+
+func (s *StreamSampleTestSuite) TestSample_StreamErrors() {
+	e := newMockStreamableEntry(func(ctx context.Context) (io.ReadCloser, error) {
+		return nil, fmt.Errorf("failed to stream")
+	})
+	s.Empty(newStreamSampler(context.Background(), e).sample(time.Minute))
+}
+
+func TestStreamSample(t *testing.T) {
+	suite.Run(t, new(StreamSampleTestSuite))
+}
+
+type mockStreamableEntry struct {
+	plugin.EntryBase
+	stream  func(context.Context) (io.ReadCloser, error)
+	streams int
+}
+
+func newMockStreamableEntry(stream func(context.Context) (io.ReadCloser, error)) *mockStreamableEntry {
+	e := &mockStreamableEntry{
+		EntryBase: plugin.NewEntry("foo"),
+		stream:    stream,
+	}
+	e.SetTestID("/foo")
+	return e
+}
+
+func (e *mockStreamableEntry) Schema() *plugin.EntrySchema {
+	return nil
+}
+
+func (e *mockStreamableEntry) Stream(ctx context.Context) (io.ReadCloser, error) {
+	e.streams++
+	return e.stream(ctx)
+}
@@ -86,8 +86,14 @@ func (w *walkerImpl) walk(ctx context.Context, e *Entry, depth int) ([]Entry, er
 				children = append(children, child)
 				return true
 			})
-			// Sort the children by cname to ensure consistent ordering
+			// Sort the children by cname to ensure consistent ordering. The
+			// children that are expensive to list are walked last so that the
+			// cheap subtrees' results aren't held up by them.
 			sort.Slice(children, func(i, j int) bool {
+				iExpensive, jExpensive := isExpensiveToList(children[i]), isExpensiveToList(children[j])
+				if iExpensive != jExpensive {
+					return jExpensive
+				}
 				return children[i].CName < children[j].CName
 			})
 			// Now walk the children
@@ -104,6 +110,12 @@ func (w *walkerImpl) walk(ctx context.Context, e *Entry, depth int) ([]Entry, er
 	return entries, nil
 }
 
+// isExpensiveToList returns true if e's schema says that its children are
+// expensive to list.
+func isExpensiveToList(e Entry) bool {
+	return e.SchemaKnown() && e.Schema.ListCost().IsExpensive()
+}
+
 func (w *walkerImpl) visit(ctx context.Context, e *Entry, depth int) (bool, error) {
 	if depth < w.opts.Mindepth {
 		return false, nil
@@ -121,5 +133,14 @@ func (w *walkerImpl) visit(ctx context.Context, e *Entry, depth int) (bool, erro
 		}
 		e.Metadata = meta
 	}
+	if s, ok := e.pluginEntry.(plugin.Streamable); ok && e.Supports(plugin.StreamAction()) {
+		// The stream primary samples the entry's stream lazily, so this
+		// doesn't stream anything unless the query needs it.
+		e.sampler = newStreamSampler(ctx, s)
+	}
+	if e.Supports(plugin.ReadAction()) {
+		// Likewise, the content primary reads the entry's content lazily.
+		e.content = newContentSampler(ctx, e.pluginEntry, w.opts.ContentMaxSize)
+	}
 	return w.q.EvalEntry((*e)), nil
 }
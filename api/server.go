@@ -12,6 +12,7 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/puppetlabs/wash/activity"
 	"github.com/puppetlabs/wash/analytics"
+	"github.com/puppetlabs/wash/api/auth"
 	apitypes "github.com/puppetlabs/wash/api/types"
 	"github.com/puppetlabs/wash/plugin"
 
@@ -23,9 +24,13 @@ type key int
 const (
 	pluginRegistryKey key = iota
 	mountpointKey
+	webhooksKey
+	idempotencyKeysKey
+	remoteKey
+	authKey
 )
 
-// swagger:parameters cacheDelete listEntries entryInfo getMetadata readContent streamUpdates deleteEntry signalEntry entrySchema
+// swagger:parameters cacheDelete listEntries entryInfo getMetadata readContent deleteEntry signalEntry entrySchema
 //nolint:deadcode,unused
 type params struct {
 	// uniquely identifies an entry
@@ -55,7 +60,7 @@ func (handle handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 	record("API: %v %v", r.Method, r.URL)
 
-	if err := handle.fn(w, r); err != nil {
+	if err := handle.call(w, r); err != nil {
 		record("API: %v %v: %v", r.Method, r.URL, err)
 		w.WriteHeader(err.statusCode)
 
@@ -73,7 +78,20 @@ func (handle handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// StartAPI starts the api. It returns three values:
+// call invokes the handler's function. If the function panics, then the panic
+// fails only the current request instead of crashing the daemon.
+func (handle handler) call(w http.ResponseWriter, r *http.Request) (err *errorResponse) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			op := fmt.Sprintf("API: %v %v", r.Method, r.URL)
+			err = unknownErrorResponse(activity.RecordPanic(r.Context(), op, rec))
+		}
+	}()
+	return handle.fn(w, r)
+}
+
+// StartAPI starts the api. It listens at socketPath, and also at remote's address
+// if it's set. It returns three values:
 //   1. A channel to initiate the shutdown (stopCh). stopCh accepts a Context object
 //      that is used to cancel a stalled shutdown.
 //
@@ -84,6 +102,7 @@ func StartAPI(
 	registry *plugin.Registry,
 	mountpoint string,
 	socketPath string,
+	remote RemoteOptions,
 	analyticsClient analytics.Client,
 ) (chan<- context.Context, <-chan struct{}, error) {
 	log.Infof("API: Listening at %s", socketPath)
@@ -105,6 +124,20 @@ func StartAPI(
 	if err != nil {
 		return nil, nil, err
 	}
+	var remoteServer net.Listener
+	if remote.Address != "" {
+		log.Infof("API: Listening for remote daemons at %s", remote.Address)
+		if remoteServer, err = remote.listen(); err != nil {
+			server.Close()
+			return nil, nil, err
+		}
+	}
+
+	webhooksCtx := context.WithValue(context.Background(), pluginRegistryKey, registry)
+	webhooksCtx = context.WithValue(webhooksCtx, mountpointKey, mountpoint)
+	webhooksCtx = context.WithValue(webhooksCtx, analytics.ClientKey, analyticsClient)
+	hooks := newWebhooks(webhooksCtx)
+	keys := newIdempotencyKeys()
 
 	prepareContextMiddleWare := func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -114,8 +147,16 @@ func StartAPI(
 				r.Header.Get(apitypes.JournalIDHeader),
 				r.Header.Get(apitypes.JournalDescHeader),
 			)
+			if identity, ok := remoteClient(newctx); ok {
+				// Remote clients share the daemon, so their activity and cached
+				// results are kept apart.
+				journal = remoteJournal(identity, r)
+				newctx = plugin.WithCacheNamespace(newctx, identity)
+			}
 			newctx = context.WithValue(newctx, activity.JournalKey, journal)
 			newctx = context.WithValue(newctx, analytics.ClientKey, analyticsClient)
+			newctx = context.WithValue(newctx, webhooksKey, hooks)
+			newctx = context.WithValue(newctx, idempotencyKeysKey, keys)
 
 			// Call the next handler, which can be another middleware in the chain, or the final handler.
 			next.ServeHTTP(w, r.WithContext(newctx))
@@ -124,23 +165,50 @@ func StartAPI(
 
 	r := mux.NewRouter()
 
+	r.Handle("/swagger.json", openapiHandler).Methods(http.MethodGet)
 	r.Handle("/analytics/screenview", screenviewHandler).Methods(http.MethodPost)
 	r.Handle("/fs/info", infoHandler).Methods(http.MethodGet)
+	r.Handle("/fs/batch", batchHandler).Methods(http.MethodPost)
 	r.Handle("/fs/list", listHandler).Methods(http.MethodGet)
 	r.Handle("/fs/find", findHandler).Methods(http.MethodPost)
 	r.Handle("/fs/metadata", metadataHandler).Methods(http.MethodGet)
+	r.Handle("/fs/mountpoint", mountpointHandler).Methods(http.MethodGet)
+	r.Handle("/fs/read", readHandler).Methods(http.MethodGet)
 	r.Handle("/fs/stream", streamHandler).Methods(http.MethodGet)
-	r.Handle("/fs/exec", execHandler).Methods(http.MethodPost)
+	r.Handle("/fs/watch", watchHandler).Methods(http.MethodGet)
+	r.Handle("/fs/exec", idempotent(execHandler)).Methods(http.MethodPost)
+	r.Handle("/fs/exec/session", execSessionHandler).Methods(http.MethodGet)
 	r.Handle("/fs/schema", schemaHandler).Methods(http.MethodGet)
-	r.Handle("/fs/delete", deleteHandler).Methods(http.MethodDelete)
-	r.Handle("/fs/signal", signalHandler).Methods(http.MethodPost)
+	r.Handle("/fs/create", idempotent(createHandler)).Methods(http.MethodPost)
+	r.Handle("/fs/delete", idempotent(deleteHandler)).Methods(http.MethodDelete)
+	r.Handle("/fs/signal", idempotent(signalHandler)).Methods(http.MethodPost)
+	r.Handle("/fs/rename", idempotent(renameHandler)).Methods(http.MethodPost)
+	r.Handle("/fs/copy", idempotent(copyHandler)).Methods(http.MethodPost)
+	r.Handle("/fs/wait", waitHandler).Methods(http.MethodPost)
+	r.Handle("/fs/write", idempotent(writeHandler)).Methods(http.MethodPut)
 	r.Handle("/cache", cacheHandler).Methods(http.MethodDelete)
+	r.Handle("/activity/stream", activityStreamHandler).Methods(http.MethodGet)
 	r.Handle("/history", historyHandler).Methods(http.MethodGet)
 	r.Handle("/history/{index:[0-9]+}", historyEntryHandler).Methods(http.MethodGet)
+	r.Handle("/journal/levels", journalLevelsHandler).Methods(http.MethodGet)
+	r.Handle("/journal/levels", setJournalLevelHandler).Methods(http.MethodPut)
+	r.Handle("/diagnostics", diagnosticsHandler).Methods(http.MethodGet)
+	r.Handle("/stats", statsHandler).Methods(http.MethodGet)
+	r.Handle("/webhooks", listWebhooksHandler).Methods(http.MethodGet)
+	r.Handle("/webhooks", addWebhookHandler).Methods(http.MethodPost)
+	r.Handle("/webhooks/{id:[0-9]+}", deleteWebhookHandler).Methods(http.MethodDelete)
+	r.Handle("/plugins", installPluginHandler).Methods(http.MethodPost)
+	r.Handle("/plugins/{name}/enable", enablePluginHandler).Methods(http.MethodPost)
+	r.Handle("/plugins/{name}/disable", disablePluginHandler).Methods(http.MethodPost)
+	r.Handle("/plugins/{name}/reload", reloadPluginHandler).Methods(http.MethodPost)
+	r.Handle("/trash", listTrashHandler).Methods(http.MethodGet)
+	r.Handle("/trash/{id:[0-9]+}/restore", restoreTrashedEntryHandler).Methods(http.MethodPost)
+	r.Handle("/trash/{id:[0-9]+}", idempotent(purgeTrashedEntryHandler)).Methods(http.MethodDelete)
 
 	r.Use(prepareContextMiddleWare)
 
 	httpServer := http.Server{Handler: r}
+	remoteHTTPServer := http.Server{Handler: remoteHandler(r, auth.NewStore(remote.TokensFile))}
 
 	// Start the server
 	serverStoppedCh := make(chan struct{})
@@ -152,12 +220,26 @@ func StartAPI(
 
 		log.Infof("API: Server was shut down")
 	}()
+	if remoteServer != nil {
+		go func() {
+			err := remoteHTTPServer.Serve(remoteServer)
+			if err != nil && err != http.ErrServerClosed {
+				log.Warnf("API: %v", err)
+			}
+		}()
+	}
 
 	stopCh := make(chan context.Context)
 	go func() {
 		ctx := <-stopCh
 
 		log.Infof("API: Shutting down the server")
+		hooks.stop()
+		if remoteServer != nil {
+			if err := remoteHTTPServer.Shutdown(ctx); err != nil {
+				log.Warnf("API: Remote listener shutdown failed: %v", err)
+			}
+		}
 		err := httpServer.Shutdown(ctx)
 		if err != nil {
 			log.Warnf("API: Shutdown failed: %v", err)
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime"
+	"time"
+
+	apitypes "github.com/puppetlabs/wash/api/types"
+	"github.com/puppetlabs/wash/metrics"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// swagger:route GET /stats stats getStats
+//
+// Get stats
+//
+// Get the server's uptime, memory usage, cache usage, the stats of each
+// plugin's method calls, the operations that are in progress and, if FUSE op
+// logging is on, the FUSE operations' latency histograms.
+//
+//     Produces:
+//     - application/json
+//
+//     Schemes: http
+//
+//     Responses:
+//       200: Stats
+//       500: errorResp
+var statsHandler = handler{logOnly: true, fn: func(w http.ResponseWriter, r *http.Request) *errorResponse {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	stats := apitypes.Stats{
+		StartTime: metrics.StartTime,
+		Uptime:    time.Since(metrics.StartTime),
+		Memory: apitypes.MemoryStats{
+			Alloc: mem.Alloc,
+			Sys:   mem.Sys,
+			NumGC: mem.NumGC,
+		},
+		Goroutines: runtime.NumGoroutine(),
+		Plugins:    metrics.Plugins(),
+		Active:     metrics.Active(),
+		FuseOps:    metrics.Histograms(),
+	}
+	if cacheStats, ok := plugin.CacheStats(); ok {
+		stats.Cache = &cacheStats
+	}
+
+	if err := json.NewEncoder(w).Encode(stats); err != nil {
+		return unknownErrorResponse(fmt.Errorf("Could not marshal stats: %v", err))
+	}
+	return nil
+}}
@@ -4,16 +4,29 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"time"
 
 	"github.com/puppetlabs/wash/activity"
 	"github.com/puppetlabs/wash/plugin"
 )
 
+// swagger:parameters streamUpdates
+//nolint:deadcode,unused
+type streamParams struct {
+	params
+	// also stream the updates made since this long ago, e.g. "10m"
+	//
+	// in: query
+	Since string
+}
+
 // swagger:route GET /fs/stream stream streamUpdates
 //
 // Stream updates
 //
-// Get a stream of new updates to the specified entry.
+// Get a stream of new updates to the specified entry. If since is set, then
+// the stream also includes the updates made since that long ago (for entries
+// that support it, like logs).
 //
 //     Produces:
 //     - application/json
@@ -23,6 +36,7 @@ import (
 //
 //     Responses:
 //       200: octetResponse
+//       400: errorResp
 //       404: errorResp
 //       500: errorResp
 var streamHandler = handler{fn: func(w http.ResponseWriter, r *http.Request) *errorResponse {
@@ -40,7 +54,15 @@ var streamHandler = handler{fn: func(w http.ResponseWriter, r *http.Request) *er
 		return unknownErrorResponse(fmt.Errorf("Cannot stream %v, response handler does not support flushing", path))
 	}
 
+	since, hasSince, errResp := getDurationParam(r.URL, "since")
+	if errResp != nil {
+		return errResp
+	}
+
 	ctx := r.Context()
+	if hasSince {
+		ctx = plugin.WithStreamSince(ctx, time.Now().Add(-since))
+	}
 	rdr, err := plugin.StreamWithAnalytics(ctx, entry.(plugin.Streamable))
 
 	if err != nil {
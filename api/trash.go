@@ -0,0 +1,121 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/puppetlabs/wash/activity"
+	apitypes "github.com/puppetlabs/wash/api/types"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// swagger:parameters restoreTrashedEntry purgeTrashedEntry
+//nolint:deadcode,unused
+type trashIDParam struct {
+	// the trashed entry's ID
+	//
+	// in: path
+	ID string
+}
+
+// swagger:response
+//nolint:deadcode,unused
+type trashList struct {
+	// in: body
+	Entries []apitypes.TrashedEntry
+}
+
+// swagger:route GET /trash trash listTrash
+//
+// List the trashed entries
+//
+// Lists the entries that were moved to the trash by a delete, ordered by when
+// they were trashed. The list's empty if the trash isn't enabled.
+//
+//     Produces:
+//     - application/json
+//
+//     Schemes: http
+//
+//     Responses:
+//       200: trashList
+//       500: errorResp
+var listTrashHandler = handler{logOnly: true, fn: func(w http.ResponseWriter, r *http.Request) *errorResponse {
+	mountpoint := r.Context().Value(mountpointKey).(string)
+	trashed := plugin.Trash()
+	entries := make([]apitypes.TrashedEntry, len(trashed))
+	for i, t := range trashed {
+		entries[i] = apitypes.TrashedEntry{
+			ID:        t.ID,
+			Path:      mountpoint + t.EntryID,
+			TrashedAt: t.TrashedAt,
+			PurgeAt:   t.PurgeAt,
+		}
+	}
+	if err := json.NewEncoder(w).Encode(entries); err != nil {
+		return unknownErrorResponse(fmt.Errorf("Could not marshal the trash: %v", err))
+	}
+	return nil
+}}
+
+// swagger:route POST /trash/{id}/restore trash restoreTrashedEntry
+//
+// Restore a trashed entry
+//
+// Takes the entry out of the trash so that it's listed again and isn't deleted.
+//
+//     Schemes: http
+//
+//     Responses:
+//       200:
+//       404: errorResp
+//       500: errorResp
+var restoreTrashedEntryHandler = handler{fn: func(w http.ResponseWriter, r *http.Request) *errorResponse {
+	ctx := r.Context()
+	id := mux.Vars(r)["id"]
+	if err := plugin.RestoreFromTrash(id); err != nil {
+		if plugin.IsTrashedEntryNotFoundErr(err) {
+			return trashedEntryNotFoundResponse(id)
+		}
+		return unknownErrorResponse(err)
+	}
+	activity.Record(ctx, "API: Trashed entry %v restored", id)
+	return nil
+}}
+
+// swagger:route DELETE /trash/{id} trash purgeTrashedEntry
+//
+// Purge a trashed entry
+//
+// Deletes the trashed entry now instead of when its retention period expires.
+//
+// On success, returns a boolean that describes whether the delete was applied immediately
+// or is pending.
+//
+//     Produces:
+//     - application/json
+//
+//     Schemes: http
+//
+//     Responses:
+//       200:
+//       404: errorResp
+//       500: errorResp
+var purgeTrashedEntryHandler = handler{fn: func(w http.ResponseWriter, r *http.Request) *errorResponse {
+	ctx := r.Context()
+	id := mux.Vars(r)["id"]
+	deleted, err := plugin.PurgeFromTrash(ctx, id)
+	if err != nil {
+		if plugin.IsTrashedEntryNotFoundErr(err) {
+			return trashedEntryNotFoundResponse(id)
+		}
+		return unknownErrorResponse(fmt.Errorf("Could not delete trashed entry %v: %v", id, err))
+	}
+	activity.Record(ctx, "API: Trashed entry %v purged %v", id, deleted)
+	if err := json.NewEncoder(w).Encode(deleted); err != nil {
+		return unknownErrorResponse(fmt.Errorf("Could not marshal the purge's result for %v: %v", id, err))
+	}
+	return nil
+}}
@@ -0,0 +1,23 @@
+package apitypes
+
+// BatchBody encapsulates the payload for a POST request to /fs/batch.
+type BatchBody struct {
+	// Paths are the entries' paths
+	Paths []string `json:"paths"`
+	// Metadata requests each entry's full metadata, like /fs/metadata. The
+	// entry's partial metadata is returned otherwise.
+	Metadata bool `json:"metadata"`
+}
+
+// BatchResult is the result of one of a batch's paths. Error is set instead
+// of Entry and Metadata if the path's entry couldn't be found, or if its
+// metadata couldn't be fetched.
+type BatchResult struct {
+	Path     string                 `json:"path"`
+	Entry    *Entry                 `json:"entry,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	Error    *ErrorObj              `json:"error,omitempty"`
+}
+
+// MaxBatchPaths is the maximum number of paths in a batch.
+const MaxBatchPaths = 1000
@@ -0,0 +1,7 @@
+package apitypes
+
+// CopyBody encapsulates the payload for a call to the copy endpoint
+type CopyBody struct {
+	// Absolute path of the entry that the content's copied to
+	Destination string `json:"destination"`
+}
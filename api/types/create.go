@@ -0,0 +1,12 @@
+package apitypes
+
+// CreateBody encapsulates the payload for a call to the create endpoint
+type CreateBody struct {
+	// Name of the new child
+	Name string `json:"name"`
+	// Dir creates a directory instead of an empty file
+	Dir bool `json:"dir"`
+	// Mode is the new file's permissions, like 0644. It defaults to 0644, and
+	// it's ignored for directories.
+	Mode uint32 `json:"mode,omitempty"`
+}
@@ -0,0 +1,21 @@
+package apitypes
+
+import (
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/datastore"
+)
+
+// Diagnostics describes the state of the running server. It's used to generate
+// diagnostic bundles for bug reports.
+//
+// swagger:response
+type Diagnostics struct {
+	// The loaded plugins
+	Plugins []string `json:"plugins"`
+	// The cache's usage. It's omitted if the cache doesn't track its usage.
+	Cache *datastore.Stats `json:"cache,omitempty"`
+	// The panics that the server most recently recovered from
+	Panics []activity.Panic `json:"panics"`
+	// A dump of the server's goroutines
+	Goroutines string `json:"goroutines"`
+}
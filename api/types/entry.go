@@ -13,10 +13,13 @@ type Entry struct {
 	CName      string                 `json:"cname"`
 	Attributes plugin.EntryAttributes `json:"attributes"`
 	Metadata   plugin.JSONObject      `json:"metadata"`
+	// Target is a symlink's target, relative to the symlink's parent. It's
+	// empty for other entries.
+	Target string `json:"target,omitempty"`
 }
 
 func NewEntry(e plugin.Entry) Entry {
-	return Entry{
+	entry := Entry{
 		TypeID:     plugin.TypeID(e),
 		Name:       plugin.Name(e),
 		CName:      plugin.CName(e),
@@ -24,6 +27,10 @@ func NewEntry(e plugin.Entry) Entry {
 		Attributes: plugin.Attributes(e),
 		Metadata:   plugin.PartialMetadata(e),
 	}
+	if link, ok := e.(*plugin.Symlink); ok {
+		entry.Target = link.Target()
+	}
+	return entry
 }
 
 // Supports returns true if e supports the given action, false
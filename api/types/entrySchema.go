@@ -13,6 +13,9 @@ import (
 // SignalSchema represents a signal's schema
 type SignalSchema = plugin.SignalSchema
 
+// CustomAttributeSchema represents a custom attribute's schema
+type CustomAttributeSchema = plugin.CustomAttributeSchema
+
 // EntrySchema describes an entry's schema, which is what's returned by
 // the /fs/schema endpoint.
 //
@@ -226,6 +229,48 @@ func (s *EntrySchema) SetSignals(signals []SignalSchema) *EntrySchema {
 	return s
 }
 
+// CustomAttributes returns the entry's custom attributes
+func (s *EntrySchema) CustomAttributes() []CustomAttributeSchema {
+	return s.EntrySchema.CustomAttributes
+}
+
+// GetCustomAttribute returns the schema of the named custom attribute,
+// or nil if the entry doesn't have it.
+func (s *EntrySchema) GetCustomAttribute(name string) *CustomAttributeSchema {
+	for i := range s.EntrySchema.CustomAttributes {
+		if s.EntrySchema.CustomAttributes[i].Name() == name {
+			return &s.EntrySchema.CustomAttributes[i]
+		}
+	}
+	return nil
+}
+
+// SetCustomAttributes sets the entry's custom attributes. This should only
+// be called by the tests.
+func (s *EntrySchema) SetCustomAttributes(attributes []CustomAttributeSchema) *EntrySchema {
+	s.EntrySchema.CustomAttributes = attributes
+	return s
+}
+
+// ConsoleURL returns the template of the URL of the entry's page in its
+// backend's web console, or "" if the entry doesn't have one.
+func (s *EntrySchema) ConsoleURL() string {
+	return s.EntrySchema.ConsoleURL
+}
+
+// ListCost returns how expensive it is to list the entry's children, or "" if
+// the entry's plugin didn't say.
+func (s *EntrySchema) ListCost() plugin.ListCost {
+	return s.EntrySchema.ListCost
+}
+
+// SetListCost sets the entry's list cost. This should only be called by the
+// tests.
+func (s *EntrySchema) SetListCost(cost plugin.ListCost) *EntrySchema {
+	s.EntrySchema.ListCost = cost
+	return s
+}
+
 // Singleton returns true if the entry's a singleton, false otherwise.
 func (s *EntrySchema) Singleton() bool {
 	return s.EntrySchema.Singleton
@@ -27,20 +27,26 @@ func (e *ErrorObj) Error() string {
 
 // Define error kinds returned by the API
 const (
-	UnsupportedAction  = "puppetlabs.wash/unsupported-action"
-	UnknownError       = "puppetlabs.wash/unknown-error"
-	StreamingError     = "puppetlabs.wash/streaming-error"
-	EntryNotFound      = "puppetlabs.wash/entry-not-found"
-	PluginDoesNotExist = "puppetlabs.wash/plugin-does-not-exist"
-	BadRequest         = "puppetlabs.wash/bad-request"
-	BadActionRequest   = "puppetlabs.wash/bad-action-request"
-	JournalUnavailable = "puppetlabs.wash/journal-unavailable"
-	ErroredAction      = "puppetlabs.wash/errored-action"
-	DuplicateCName     = "puppetlabs.wash/duplicate-cname"
-	RelativePath       = "puppetlabs.wash/relative-path"
-	InvalidPaths       = "puppetlabs.wash/invalid-paths"
-	OutOfBounds        = "puppetlabs.wash/out-of-bounds"
-	NonWashPath        = "puppetlabs.wash/non-wash-path"
-	InvalidBool        = "puppetlabs.wash/invalid-bool"
-	InvalidInt         = "puppetlabs.wash/invalid-int"
+	UnsupportedAction    = "puppetlabs.wash/unsupported-action"
+	UnknownError         = "puppetlabs.wash/unknown-error"
+	StreamingError       = "puppetlabs.wash/streaming-error"
+	EntryNotFound        = "puppetlabs.wash/entry-not-found"
+	PluginDoesNotExist   = "puppetlabs.wash/plugin-does-not-exist"
+	BadRequest           = "puppetlabs.wash/bad-request"
+	BadActionRequest     = "puppetlabs.wash/bad-action-request"
+	JournalUnavailable   = "puppetlabs.wash/journal-unavailable"
+	ErroredAction        = "puppetlabs.wash/errored-action"
+	RelativePath         = "puppetlabs.wash/relative-path"
+	InvalidPaths         = "puppetlabs.wash/invalid-paths"
+	OutOfBounds          = "puppetlabs.wash/out-of-bounds"
+	NonWashPath          = "puppetlabs.wash/non-wash-path"
+	InvalidBool          = "puppetlabs.wash/invalid-bool"
+	InvalidInt           = "puppetlabs.wash/invalid-int"
+	InvalidDuration      = "puppetlabs.wash/invalid-duration"
+	WebhookNotFound      = "puppetlabs.wash/webhook-not-found"
+	TrashedEntryNotFound = "puppetlabs.wash/trashed-entry-not-found"
+	IdempotencyKeyReused = "puppetlabs.wash/idempotency-key-reused"
+	DuplicateRequest     = "puppetlabs.wash/duplicate-request"
+	Unauthorized         = "puppetlabs.wash/unauthorized"
+	Forbidden            = "puppetlabs.wash/forbidden"
 )
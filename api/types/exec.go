@@ -12,6 +12,8 @@ import (
 type ExecOptions struct {
 	// Input to pass on stdin when executing the command
 	Input string `json:"input"`
+	// Allocate a TTY for the command, e.g. for an interactive session
+	Tty bool `json:"tty"`
 }
 
 // ExecBody encapsulates the payload for a call to a plugin's Exec function
@@ -42,3 +44,23 @@ type ExecPacket struct {
 	Data      interface{}           `json:"data"`
 	Err       *ErrorObj             `json:"error"`
 }
+
+// ExecInputType identifies the type of an ExecInput message.
+type ExecInputType = string
+
+// Enumerates the input types of an interactive exec session.
+const (
+	StdinInput    ExecInputType = "stdin"
+	StdinEOFInput ExecInputType = "stdin_eof"
+	ResizeInput   ExecInputType = "resize"
+)
+
+// ExecInput is a message that's sent to an interactive exec session after
+// its ExecBody. Stdin messages write Data to the command's stdin, the
+// stdin_eof message closes the command's stdin, and resize messages resize
+// the command's TTY to Size.
+type ExecInput struct {
+	TypeField ExecInputType      `json:"type"`
+	Data      string             `json:"data,omitempty"`
+	Size      *plugin.WindowSize `json:"size,omitempty"`
+}
@@ -0,0 +1,57 @@
+package apitypes
+
+// FindTotalCountHeader is the name of the HTTP Header that contains the number
+// of entries that satisfied a find query before its limit and offset were
+// applied.
+const FindTotalCountHeader = "Total-Count"
+
+// FindBody is the find endpoint's extended request body. It wraps an RQL query
+// with clauses that shape the returned entries. A bare RQL query is still
+// accepted as the body; it's equivalent to a FindBody with only a Query.
+type FindBody struct {
+	// The RQL query. Defaults to matching every entry.
+	Query interface{} `json:"query,omitempty"`
+	// The fields to sort the entries by, e.g. "name", "-size", "mtime" or
+	// "meta.tags.owner". A "-" prefix sorts in descending order. Later fields
+	// break the ties of earlier fields. Entries are returned in traversal
+	// order if it's empty.
+	Sort []string `json:"sort,omitempty"`
+	// The maximum number of entries to return. Zero means no limit.
+	Limit int `json:"limit,omitempty"`
+	// The number of entries to skip, after sorting
+	Offset int `json:"offset,omitempty"`
+	// The metadata fields to return, e.g. "meta.tags.owner". If set, each
+	// entry's metadata only includes these fields.
+	Fields []string `json:"fields,omitempty"`
+	// If set, then the response is the aggregation's results instead of the
+	// entries. It can't be combined with the other clauses.
+	Aggregate *FindAggregate `json:"aggregate,omitempty"`
+}
+
+// FindAggregate describes an aggregation of the entries that satisfied a find
+// query.
+type FindAggregate struct {
+	// One of "count", "sum", "min" or "max"
+	Function string `json:"function"`
+	// The aggregated field, e.g. "size" or "meta.ContentLength". It's
+	// required by every function except for count.
+	Field string `json:"field,omitempty"`
+	// The field whose values group the entries, e.g. "meta.Bucket". The
+	// entries are aggregated as a single group if it's unset.
+	GroupBy string `json:"group_by,omitempty"`
+}
+
+// AggregateResult is an aggregation's result for a group of entries.
+//
+// swagger:response
+type AggregateResult struct {
+	// The group's value of the group_by field. It's null for the entries
+	// that don't have the field, and for ungrouped aggregations.
+	Group interface{} `json:"group"`
+	// The number of entries in the group
+	Count int `json:"count"`
+	// The aggregated value. It's the count for count, a number for sum, and
+	// the least or greatest of the field's values for min and max, or null if
+	// none of the group's entries have the field.
+	Value interface{} `json:"value"`
+}
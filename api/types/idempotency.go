@@ -0,0 +1,10 @@
+package apitypes
+
+// IdempotencyKeyHeader is the name of the HTTP Header used to deduplicate retries
+// of a delete, exec, signal or copy request. A retry with the same key replays
+// the original request's response instead of running it again.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayedHeader is set to "true" on a response that's a replay of an
+// earlier request's response.
+const IdempotentReplayedHeader = "Idempotent-Replayed"
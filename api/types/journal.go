@@ -22,3 +22,38 @@ type HistoryResponse struct {
 	// in: body
 	Activities []Activity
 }
+
+// JournalLevelsResponse describes the result returned by the `/journal/levels`
+// endpoint. It maps a plugin's name to its journal level.
+//
+// swagger:response
+type JournalLevelsResponse struct {
+	// in: body
+	Levels map[string]string
+}
+
+// JournalLevelBody encapsulates the payload for setting a plugin's journal level.
+type JournalLevelBody struct {
+	// Name of the plugin
+	Plugin string `json:"plugin"`
+	// The plugin's new level, e.g. "debug" or "warn". An empty level resets the
+	// plugin to the default level.
+	Level string `json:"level"`
+}
+
+// JournalEntry describes an entry that was recorded in a journal. It's sent as
+// the data of the server-sent events of the `/activity/stream` endpoint.
+//
+// swagger:response
+type JournalEntry struct {
+	Time    time.Time `json:"time"`
+	Level   string    `json:"level"`
+	Message string    `json:"message"`
+	// The ID of the entry's journal
+	JournalID string `json:"journal_id"`
+	// The description of the activity that the journal's associated with,
+	// e.g. the wash command that was run
+	Description string `json:"description,omitempty"`
+	// When the journal's activity started
+	Start time.Time `json:"start"`
+}
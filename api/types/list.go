@@ -0,0 +1,9 @@
+package apitypes
+
+// ListNextCursorHeader is the name of the HTTP Header that contains the cursor
+// of a paginated listing's next page. It isn't set on the last page.
+const ListNextCursorHeader = "Next-Cursor"
+
+// NDJSONContentType is the content type of a streamed listing, which has an
+// Entry object on each line.
+const NDJSONContentType = "application/x-ndjson"
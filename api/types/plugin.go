@@ -0,0 +1,12 @@
+package apitypes
+
+// PluginInstallBody encapsulates the payload for installing an external
+// plugin while the server's running.
+type PluginInstallBody struct {
+	// Absolute path to the plugin's script
+	Script string `json:"script"`
+	// How the script's invoked, either fork (the default) or jsonrpc
+	Protocol string `json:"protocol,omitempty"`
+	// The plugin's config, which is passed to its init method
+	Config map[string]interface{} `json:"config,omitempty"`
+}
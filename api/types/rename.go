@@ -0,0 +1,7 @@
+package apitypes
+
+// RenameBody encapsulates the payload for a call to the rename endpoint
+type RenameBody struct {
+	// Absolute path that the entry's renamed to. Its parent must already exist.
+	Destination string `json:"destination"`
+}
@@ -0,0 +1,41 @@
+package apitypes
+
+import (
+	"time"
+
+	"github.com/puppetlabs/wash/datastore"
+	"github.com/puppetlabs/wash/metrics"
+)
+
+// Stats describes the performance of the running server.
+//
+// swagger:response
+type Stats struct {
+	// The time at which the server started
+	StartTime time.Time `json:"start_time"`
+	// The server's uptime in nanoseconds
+	Uptime time.Duration `json:"uptime"`
+	// The server's memory usage
+	Memory MemoryStats `json:"memory"`
+	// The number of running goroutines
+	Goroutines int `json:"goroutines"`
+	// The cache's usage. It's omitted if the cache doesn't track its usage.
+	Cache *datastore.Stats `json:"cache,omitempty"`
+	// The stats of each plugin's method calls
+	Plugins []metrics.PluginStats `json:"plugins"`
+	// The operations that are in progress, oldest first
+	Active []metrics.Operation `json:"active"`
+	// The latency histograms of FUSE operations. They're only recorded when the
+	// server's started with FUSE op logging.
+	FuseOps []metrics.Histogram `json:"fuse_ops,omitempty"`
+}
+
+// MemoryStats describes the server's memory usage in bytes.
+type MemoryStats struct {
+	// The bytes allocated by live objects
+	Alloc uint64 `json:"alloc"`
+	// The bytes obtained from the OS
+	Sys uint64 `json:"sys"`
+	// The number of completed garbage collections
+	NumGC uint32 `json:"num_gc"`
+}
@@ -0,0 +1,16 @@
+package apitypes
+
+import "time"
+
+// TrashedEntry describes an entry that was moved to the trash by a delete. It's
+// deleted at PurgeAt unless it's restored first.
+//
+// swagger:response
+type TrashedEntry struct {
+	// Identifies the entry in the trash
+	ID string `json:"id"`
+	// The entry's absolute path
+	Path      string    `json:"path"`
+	TrashedAt time.Time `json:"trashed_at"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
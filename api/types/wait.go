@@ -0,0 +1,22 @@
+package apitypes
+
+import "time"
+
+// WaitOptions are options that can be passed as part of a Wait call.
+type WaitOptions struct {
+	// The maximum amount of time to wait. Zero means the server's default.
+	Timeout time.Duration
+	// The amount of time between condition checks. Zero means the server's
+	// default.
+	Interval time.Duration
+	// If true, then meta primaries act on the entry's full metadata
+	Fullmeta bool
+}
+
+// WaitResult is the result of a wait. Satisfied is false if the timeout
+// elapsed before the entry satisfied the condition. Entry is only set if it
+// was satisfied.
+type WaitResult struct {
+	Satisfied bool   `json:"satisfied"`
+	Entry     *Entry `json:"entry,omitempty"`
+}
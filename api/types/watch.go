@@ -0,0 +1,16 @@
+package apitypes
+
+import "time"
+
+// EntryEvent describes a change to one of a watched entry's children. It's
+// sent as the data of a server-sent event whose type is the event's type.
+//
+// swagger:response
+type EntryEvent struct {
+	// One of "created", "modified" or "deleted"
+	Type string `json:"type"`
+	// The child's state. Deleted children that were reported by name only
+	// have their Name, CName and Path set.
+	Entry Entry     `json:"entry"`
+	Time  time.Time `json:"time"`
+}
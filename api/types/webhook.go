@@ -0,0 +1,54 @@
+package apitypes
+
+import "time"
+
+// Webhook describes a webhook that's registered with the `/webhooks` endpoint.
+// The server periodically checks the entries under Path that satisfy Query, and
+// POSTs a WebhookEvent to URL for each change.
+//
+// swagger:response
+type Webhook struct {
+	// Assigned by the server
+	ID string `json:"id"`
+	// The http(s) URL that's notified of the events
+	URL string `json:"url"`
+	// The absolute path of the watched entry
+	Path string `json:"path"`
+	// The RQL query that filters the watched entries. A missing query watches
+	// every entry under Path.
+	Query interface{} `json:"query,omitempty"`
+	// The amount of time between checks, e.g. "30s" (defaults to 30s)
+	Interval string `json:"interval,omitempty"`
+	// The maximum depth of the watched entries. A missing maxdepth watches every
+	// entry under Path.
+	Maxdepth *int `json:"maxdepth,omitempty"`
+	// If true, then meta primaries act on the entry's full metadata
+	Fullmeta bool `json:"fullmeta,omitempty"`
+	// Set by the server if the last check or notification failed
+	LastError string `json:"last_error,omitempty"`
+}
+
+// Webhook event types
+const (
+	// WebhookEntryCreated means that the entry started satisfying the query,
+	// e.g. because it was created.
+	WebhookEntryCreated = "created"
+	// WebhookEntryModified means that the entry's attributes changed, e.g.
+	// because a file's content was modified.
+	WebhookEntryModified = "modified"
+	// WebhookEntryDeleted means that the entry stopped satisfying the query,
+	// e.g. because it was deleted.
+	WebhookEntryDeleted = "deleted"
+)
+
+// WebhookEvent is the payload that's POSTed to a webhook's URL.
+type WebhookEvent struct {
+	// The webhook's ID
+	Webhook string `json:"webhook"`
+	// One of "created", "modified" or "deleted"
+	Type string `json:"type"`
+	// The entry's state when the event was observed. Deleted entries are
+	// reported with their last observed state.
+	Entry Entry     `json:"entry"`
+	Time  time.Time `json:"time"`
+}
@@ -0,0 +1,175 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/api/rql"
+	"github.com/puppetlabs/wash/api/rql/ast"
+	apitypes "github.com/puppetlabs/wash/api/types"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// DefaultWaitTimeout is the default timeout of a wait request.
+const DefaultWaitTimeout = 5 * time.Minute
+
+// DefaultWaitInterval is the default amount of time between consecutive
+// condition checks.
+const DefaultWaitInterval = 1 * time.Second
+
+// MinWaitInterval is the minimum amount of time between consecutive condition
+// checks. It avoids hammering the plugin APIs.
+const MinWaitInterval = 100 * time.Millisecond
+
+// swagger:parameters waitForCondition
+//nolint:deadcode,unused
+type waitParams struct {
+	params
+	// the maximum amount of time to wait, e.g. "30s" (defaults to 5m)
+	//
+	// in: query
+	Timeout string
+	// the amount of time between condition checks, e.g. "5s" (defaults to 1s)
+	//
+	// in: query
+	Interval string
+	// if true, then meta primaries act on the entry's full metadata
+	//
+	// in: query
+	Fullmeta bool
+}
+
+// swagger:response
+//nolint:deadcode,unused
+type waitResult struct {
+	// in: body
+	Result apitypes.WaitResult
+}
+
+// swagger:route POST /fs/wait wait waitForCondition
+//
+// Wait for an entry to satisfy an RQL query
+//
+// Blocks until the entry at the given path exists and satisfies the given RQL
+// query, then returns the entry. An empty query waits for the entry to exist.
+// If the timeout elapses first, then the result isn't satisfied. The entry's
+// own cached data is cleared before each check. Its attributes and partial
+// metadata are refreshed when its parent's cached listing expires.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Schemes: http
+//
+//     Responses:
+//       200: waitResult
+//       400: errorResp
+//       404: errorResp
+//       500: errorResp
+var waitHandler = handler{fn: func(w http.ResponseWriter, r *http.Request) *errorResponse {
+	ctx := r.Context()
+	path, errResp := getPathFromRequest(r)
+	if errResp != nil {
+		return errResp
+	}
+	timeout, hasTimeout, errResp := getDurationParam(r.URL, "timeout")
+	if errResp != nil {
+		return errResp
+	}
+	if !hasTimeout {
+		timeout = DefaultWaitTimeout
+	}
+	interval, hasInterval, errResp := getDurationParam(r.URL, "interval")
+	if errResp != nil {
+		return errResp
+	}
+	if !hasInterval {
+		interval = DefaultWaitInterval
+	} else if interval < MinWaitInterval {
+		interval = MinWaitInterval
+	}
+	fullMeta, errResp := getBoolParam(r.URL, "fullmeta")
+	if errResp != nil {
+		return errResp
+	}
+
+	var rawQuery interface{}
+	if err := json.NewDecoder(r.Body).Decode(&rawQuery); err != nil {
+		if err != io.EOF {
+			return badRequestResponse(fmt.Sprintf("could not decode the RQL query: %v", err))
+		}
+		rawQuery = true
+	}
+	query := ast.Query()
+	if err := query.Unmarshal(rawQuery); err != nil {
+		return badRequestResponse(fmt.Sprintf("could not decode the RQL query: %v", err))
+	}
+
+	opts := rql.NewOptions()
+	opts.Fullmeta = fullMeta
+
+	// washPath is empty if path is a regular file/dir.
+	washPath, errResp := toWashPath(ctx, path)
+	if errResp != nil {
+		washPath = ""
+	}
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	for checks := 1; ; checks++ {
+		if washPath != "" {
+			plugin.ClearOpsFor(washPath)
+		}
+		apiEntry, satisfied, errResp := checkWaitCondition(r, query, opts)
+		if errResp != nil {
+			return errResp
+		}
+		if satisfied {
+			activity.Record(ctx, "API: Wait %v satisfied after %v checks", path, checks)
+			apiEntry.Path = path
+			return writeWaitResult(w, path, apitypes.WaitResult{Satisfied: true, Entry: &apiEntry})
+		}
+
+		select {
+		case <-ctx.Done():
+			return unknownErrorResponse(ctx.Err())
+		case <-deadline.C:
+			activity.Record(ctx, "API: Wait %v timed out after %v checks", path, checks)
+			return writeWaitResult(w, path, apitypes.WaitResult{Satisfied: false})
+		case <-time.After(interval):
+		}
+	}
+}}
+
+func writeWaitResult(w http.ResponseWriter, path string, result apitypes.WaitResult) *errorResponse {
+	if err := json.NewEncoder(w).Encode(&result); err != nil {
+		return unknownErrorResponse(fmt.Errorf("Could not marshal the wait result for %v: %v", path, err))
+	}
+	return nil
+}
+
+// checkWaitCondition returns true if the requested entry exists and satisfies the
+// query. Errors are returned as-is, except for "not found" errors since the wait
+// could be for the entry to exist.
+func checkWaitCondition(r *http.Request, query rql.Query, opts rql.Options) (apitypes.Entry, bool, *errorResponse) {
+	entry, _, errResp := getEntryFromRequest(r)
+	if errResp != nil {
+		if errResp.body.Kind == apitypes.EntryNotFound {
+			return apitypes.Entry{}, false, nil
+		}
+		return apitypes.Entry{}, false, errResp
+	}
+
+	rqlEntry, satisfied, err := rql.Eval(r.Context(), entry, query, opts)
+	if err != nil {
+		return apitypes.Entry{}, false, unknownErrorResponse(err)
+	}
+	return rqlEntry.Entry, satisfied, nil
+}
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	apitypes "github.com/puppetlabs/wash/api/types"
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func waitRequest(t *testing.T, path string, query string) (*httptest.ResponseRecorder, *mockRoot) {
+	plugin.SetTestCache(newMockCache())
+	defer plugin.UnsetTestCache()
+
+	root := &mockRoot{EntryBase: plugin.NewEntry("mine")}
+	root.SetTestID("/mine")
+	root.On("List", mock.Anything).Return([]plugin.Entry{&listTestsEntry{EntryBase: plugin.NewEntry("a")}}, nil)
+
+	reg := plugin.NewRegistry()
+	require.NoError(t, reg.RegisterPlugin(root, map[string]interface{}{}))
+	ctx := context.WithValue(context.Background(), pluginRegistryKey, reg)
+	ctx = context.WithValue(ctx, mountpointKey, "/mnt")
+
+	params := url.Values{"path": []string{path}, "timeout": []string{"300ms"}, "interval": []string{"100ms"}}
+	r := httptest.NewRequest(http.MethodPost, "/fs/wait?"+params.Encode(), strings.NewReader(query)).WithContext(ctx)
+	w := httptest.NewRecorder()
+	waitHandler.ServeHTTP(w, r)
+	return w, root
+}
+
+func TestWaitHandler_Satisfied(t *testing.T) {
+	w, _ := waitRequest(t, "/mnt/mine/a", `["name", ["glob", "a"]]`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var result apitypes.WaitResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.True(t, result.Satisfied)
+	if assert.NotNil(t, result.Entry) {
+		assert.Equal(t, "/mnt/mine/a", result.Entry.Path)
+	}
+}
+
+func TestWaitHandler_Timeout(t *testing.T) {
+	w, root := waitRequest(t, "/mnt/mine/a", `["name", ["glob", "b"]]`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var result apitypes.WaitResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.False(t, result.Satisfied)
+	assert.Nil(t, result.Entry)
+	// Each check only clears the entry's own cached data, so the parent's
+	// cached listing is reused.
+	root.AssertNumberOfCalls(t, "List", 1)
+
+	// Waiting for an entry that doesn't exist also times out.
+	w, _ = waitRequest(t, "/mnt/mine/b", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.JSONEq(t, `{"satisfied": false}`, w.Body.String())
+}
+
+func TestWaitHandler_BadQuery(t *testing.T) {
+	w, _ := waitRequest(t, "/mnt/mine/a", `["bogus"]`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "could not decode the RQL query")
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/puppetlabs/wash/activity"
+	apitypes "github.com/puppetlabs/wash/api/types"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// swagger:route GET /fs/watch watch watchEntry
+//
+// Watch an entry's children
+//
+// Streams the changes to the specified entry's children as server-sent
+// events until the client disconnects. Each event's type is "created",
+// "modified" or "deleted", and its data is an EntryEvent.
+//
+//     Produces:
+//     - application/json
+//     - text/event-stream
+//
+//     Schemes: http
+//
+//     Responses:
+//       200: EntryEvent
+//       400: errorResp
+//       404: errorResp
+//       500: errorResp
+var watchHandler = handler{fn: func(w http.ResponseWriter, r *http.Request) *errorResponse {
+	entry, path, errResp := getEntryFromRequest(r)
+	if errResp != nil {
+		return errResp
+	}
+
+	watchable, ok := entry.(plugin.Watchable)
+	if !ok || !plugin.WatchAction().IsSupportedOn(entry) {
+		return unsupportedActionResponse(path, plugin.WatchAction())
+	}
+
+	f, ok := w.(flushableWriter)
+	if !ok {
+		return unknownErrorResponse(fmt.Errorf("Cannot watch %v, response handler does not support flushing", path))
+	}
+
+	ctx := r.Context()
+	events, err := plugin.Watch(ctx, watchable)
+	if err != nil {
+		return erroredActionResponse(path, plugin.WatchAction(), err.Error())
+	}
+	activity.Record(ctx, "API: Watching %v", path)
+
+	// Do an initial flush to send the header.
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	f.Flush()
+
+	sw := &streamableResponseWriter{f}
+	for event := range events {
+		data, err := json.Marshal(newAPIEntryEvent(path, event))
+		if err != nil {
+			activity.Record(ctx, "API: Could not marshal the %v event of %v: %v", event.Type, path, err)
+			continue
+		}
+		if _, err := fmt.Fprintf(sw, "event: %v\ndata: %s\n\n", event.Type, data); err != nil {
+			// Common when the caller closes the connection.
+			activity.Record(ctx, "API: Watching %v errored: %v", path, err)
+			break
+		}
+	}
+	activity.Record(ctx, "API: Stopped watching %v", path)
+	return nil
+}}
+
+func newAPIEntryEvent(parentPath string, event plugin.EntryEvent) apitypes.EntryEvent {
+	var entry apitypes.Entry
+	if event.Entry != nil {
+		entry = apitypes.NewEntry(event.Entry)
+	} else {
+		entry = apitypes.Entry{Name: event.Name, CName: plugin.EscapeName(event.Name, 0)}
+	}
+	entry.Path = parentPath + "/" + entry.CName
+	return apitypes.EntryEvent{Type: event.Type, Entry: entry, Time: event.Time}
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// watchTestsRoot sends a created and a deleted event, then stops.
+type watchTestsRoot struct {
+	mockRoot
+}
+
+func (r *watchTestsRoot) Watch(ctx context.Context) (<-chan plugin.EntryEvent, error) {
+	events := make(chan plugin.EntryEvent, 2)
+	t := time.Date(2020, 2, 3, 10, 15, 4, 0, time.UTC)
+	events <- plugin.EntryEvent{Type: plugin.EntryCreated, Entry: newMockEntry("foo"), Time: t}
+	events <- plugin.EntryEvent{Type: plugin.EntryDeleted, Name: "bar", Time: t}
+	close(events)
+	return events, nil
+}
+
+func TestWatchHandler(t *testing.T) {
+	plugin.SetTestCache(newMockCache())
+	defer plugin.UnsetTestCache()
+
+	plug := &watchTestsRoot{mockRoot: mockRoot{EntryBase: plugin.NewEntry("mine")}}
+	plug.SetTestID("/mine")
+	reg := plugin.NewRegistry()
+	require.NoError(t, reg.RegisterPlugin(plug, map[string]interface{}{}))
+
+	ctx := context.WithValue(context.Background(), pluginRegistryKey, reg)
+	ctx = context.WithValue(ctx, mountpointKey, "/mnt")
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/fs/watch?path=/mnt/mine", nil).WithContext(ctx)
+	w := httptest.NewRecorder()
+	watchHandler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "event: created\ndata: {\"type\":\"created\",\"entry\":{")
+	assert.Contains(t, w.Body.String(), "\"path\":\"/mnt/mine/foo\"")
+	assert.Contains(t, w.Body.String(), "event: deleted\ndata: {\"type\":\"deleted\",\"entry\":{")
+	assert.Contains(t, w.Body.String(), "\"path\":\"/mnt/mine/bar\"")
+}
+
+func TestWatchHandler_Unsupported(t *testing.T) {
+	plugin.SetTestCache(newMockCache())
+	defer plugin.UnsetTestCache()
+
+	plug := &mockRoot{EntryBase: plugin.NewEntry("mine")}
+	plug.SetTestID("/mine")
+	reg := plugin.NewRegistry()
+	require.NoError(t, reg.RegisterPlugin(plug, map[string]interface{}{}))
+
+	ctx := context.WithValue(context.Background(), pluginRegistryKey, reg)
+	ctx = context.WithValue(ctx, mountpointKey, "/mnt")
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/fs/watch?path=/mnt/mine", nil).WithContext(ctx)
+	w := httptest.NewRecorder()
+	watchHandler.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,433 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/api/rql"
+	"github.com/puppetlabs/wash/api/rql/ast"
+	apitypes "github.com/puppetlabs/wash/api/types"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// DefaultWebhookInterval is the default amount of time between consecutive
+// checks of a webhook's entries.
+const DefaultWebhookInterval = 30 * time.Second
+
+// MinWebhookInterval is the minimum amount of time between consecutive checks
+// of a webhook's entries. Each check clears the watched entries' cached data,
+// so this avoids hammering the plugin APIs.
+const MinWebhookInterval = 5 * time.Second
+
+// webhookNotifyTimeout is the maximum amount of time to wait for a webhook's
+// URL to respond to an event.
+const webhookNotifyTimeout = 10 * time.Second
+
+// swagger:parameters addWebhook
+//nolint:deadcode,unused
+type webhookBody struct {
+	// in: body
+	Webhook apitypes.Webhook
+}
+
+// swagger:parameters deleteWebhook
+//nolint:deadcode,unused
+type webhookIDParam struct {
+	// the webhook's ID
+	//
+	// in: path
+	ID string
+}
+
+// swagger:response
+//nolint:deadcode,unused
+type webhookList struct {
+	// in: body
+	Webhooks []apitypes.Webhook
+}
+
+// swagger:route GET /webhooks webhooks listWebhooks
+//
+// List the registered webhooks
+//
+//     Produces:
+//     - application/json
+//
+//     Schemes: http
+//
+//     Responses:
+//       200: webhookList
+//       500: errorResp
+var listWebhooksHandler = handler{logOnly: true, fn: func(w http.ResponseWriter, r *http.Request) *errorResponse {
+	hooks := r.Context().Value(webhooksKey).(*webhooks)
+	if err := json.NewEncoder(w).Encode(hooks.list()); err != nil {
+		return unknownErrorResponse(fmt.Errorf("Could not marshal the webhooks: %v", err))
+	}
+	return nil
+}}
+
+// swagger:route POST /webhooks webhooks addWebhook
+//
+// Register a webhook
+//
+// Registers a webhook that watches the entries under the given path that
+// satisfy the given RQL query. The entries are checked periodically, and an
+// event is POSTed to the webhook's URL whenever an entry starts satisfying the
+// query (created), stops satisfying it (deleted), or has its attributes change
+// (modified). The first check only records the entries, so registering a
+// webhook doesn't notify it of the existing entries.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Schemes: http
+//
+//     Responses:
+//       200: Webhook
+//       400: errorResp
+//       500: errorResp
+var addWebhookHandler = handler{fn: func(w http.ResponseWriter, r *http.Request) *errorResponse {
+	ctx := r.Context()
+	if r.Body == nil {
+		return badRequestResponse("Please send a JSON request body")
+	}
+
+	var body apitypes.Webhook
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return badRequestResponse(err.Error())
+	}
+	hook, errResp := newWebhook(body)
+	if errResp != nil {
+		return errResp
+	}
+
+	hooks := ctx.Value(webhooksKey).(*webhooks)
+	hooks.add(hook)
+	activity.Record(ctx, "API: Webhook %v added for %v", hook.ID, hook.Path)
+
+	if err := json.NewEncoder(w).Encode(hook.status()); err != nil {
+		return unknownErrorResponse(fmt.Errorf("Could not marshal webhook %v: %v", hook.ID, err))
+	}
+	return nil
+}}
+
+// swagger:route DELETE /webhooks/{id} webhooks deleteWebhook
+//
+// Delete a webhook
+//
+// Stops watching the webhook's entries.
+//
+//     Produces:
+//     - application/json
+//
+//     Schemes: http
+//
+//     Responses:
+//       200:
+//       404: errorResp
+//       500: errorResp
+var deleteWebhookHandler = handler{fn: func(w http.ResponseWriter, r *http.Request) *errorResponse {
+	ctx := r.Context()
+	id := mux.Vars(r)["id"]
+	hooks := ctx.Value(webhooksKey).(*webhooks)
+	if !hooks.delete(id) {
+		return webhookNotFoundResponse(id)
+	}
+	activity.Record(ctx, "API: Webhook %v deleted", id)
+	return nil
+}}
+
+// webhooks are the registered webhooks. Each webhook is checked by its own
+// goroutine, which runs until the webhook is deleted or the server's shut down.
+type webhooks struct {
+	mux    sync.Mutex
+	nextID int
+	hooks  map[string]*webhook
+	// ctx is the parent of each webhook's context. It includes the plugin
+	// registry and the mountpoint so that entries can be looked up outside
+	// of a request.
+	ctx    context.Context
+	cancel context.CancelFunc
+	client *http.Client
+}
+
+func newWebhooks(ctx context.Context) *webhooks {
+	ctx, cancel := context.WithCancel(ctx)
+	return &webhooks{
+		nextID: 1,
+		hooks:  make(map[string]*webhook),
+		ctx:    ctx,
+		cancel: cancel,
+		client: &http.Client{Timeout: webhookNotifyTimeout},
+	}
+}
+
+func (hs *webhooks) add(hook *webhook) {
+	hs.mux.Lock()
+	defer hs.mux.Unlock()
+
+	hook.ID = strconv.Itoa(hs.nextID)
+	hs.nextID++
+	hs.hooks[hook.ID] = hook
+
+	journal := activity.NewJournal("webhook-"+hook.ID, fmt.Sprintf("Webhook %v for %v", hook.URL, hook.Path))
+	ctx, cancel := context.WithCancel(context.WithValue(hs.ctx, activity.JournalKey, journal))
+	hook.cancel = cancel
+	go hook.watch(ctx, hs.client)
+}
+
+func (hs *webhooks) delete(id string) bool {
+	hs.mux.Lock()
+	defer hs.mux.Unlock()
+
+	hook, ok := hs.hooks[id]
+	if !ok {
+		return false
+	}
+	hook.cancel()
+	delete(hs.hooks, id)
+	return true
+}
+
+func (hs *webhooks) list() []apitypes.Webhook {
+	hs.mux.Lock()
+	defer hs.mux.Unlock()
+
+	list := make([]apitypes.Webhook, 0, len(hs.hooks))
+	for _, hook := range hs.hooks {
+		list = append(list, hook.status())
+	}
+	sort.Slice(list, func(i, j int) bool {
+		a, _ := strconv.Atoi(list[i].ID)
+		b, _ := strconv.Atoi(list[j].ID)
+		return a < b
+	})
+	return list
+}
+
+// stop stops checking every webhook.
+func (hs *webhooks) stop() {
+	hs.cancel()
+}
+
+type webhook struct {
+	apitypes.Webhook
+	query    rql.Query
+	opts     rql.Options
+	interval time.Duration
+	cancel   context.CancelFunc
+	mux      sync.Mutex
+}
+
+// newWebhook validates the registered webhook.
+func newWebhook(body apitypes.Webhook) (*webhook, *errorResponse) {
+	u, err := url.Parse(body.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, badRequestResponse(fmt.Sprintf("The webhook's url must be an http(s) URL, not %q", body.URL))
+	}
+	if body.Path == "" {
+		return nil, badRequestResponse("The webhook must include a path")
+	}
+	if !filepath.IsAbs(body.Path) {
+		return nil, relativePathResponse(body.Path)
+	}
+
+	hook := &webhook{
+		Webhook:  body,
+		query:    ast.Query(),
+		opts:     rql.NewOptions(),
+		interval: DefaultWebhookInterval,
+	}
+	hook.ID = ""
+	hook.LastError = ""
+
+	rawQuery := body.Query
+	if rawQuery == nil {
+		rawQuery = true
+	}
+	if err := hook.query.Unmarshal(rawQuery); err != nil {
+		return nil, badRequestResponse(fmt.Sprintf("could not decode the RQL query: %v", err))
+	}
+	if body.Maxdepth != nil {
+		if *body.Maxdepth < 0 {
+			return nil, badRequestResponse(fmt.Sprintf("The webhook's maxdepth must be non-negative, not %v", *body.Maxdepth))
+		}
+		hook.opts.Maxdepth = *body.Maxdepth
+	}
+	hook.opts.Fullmeta = body.Fullmeta
+
+	if body.Interval != "" {
+		interval, err := time.ParseDuration(body.Interval)
+		if err != nil {
+			return nil, invalidDurationParam("interval", body.Interval)
+		}
+		if interval < MinWebhookInterval {
+			interval = MinWebhookInterval
+		}
+		hook.interval = interval
+	}
+	hook.Interval = hook.interval.String()
+	return hook, nil
+}
+
+func (hook *webhook) status() apitypes.Webhook {
+	hook.mux.Lock()
+	defer hook.mux.Unlock()
+	return hook.Webhook
+}
+
+func (hook *webhook) setLastError(err error) {
+	hook.mux.Lock()
+	defer hook.mux.Unlock()
+	if err == nil {
+		hook.LastError = ""
+	} else {
+		hook.LastError = err.Error()
+	}
+}
+
+// watch checks the webhook's entries every interval and notifies the webhook's
+// URL of any changes. A failed check is retried at the next interval without
+// reporting any events so that an API outage doesn't look like the entries
+// were deleted.
+func (hook *webhook) watch(ctx context.Context, client *http.Client) {
+	var matches map[string]apitypes.Entry
+	for {
+		current, err := hook.check(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			activity.Warnf(ctx, "Webhook %v: could not check %v: %v", hook.ID, hook.Path, err)
+		} else if matches != nil {
+			err = hook.notify(ctx, client, diffWebhookMatches(hook.ID, matches, current, time.Now()))
+		}
+		if current != nil {
+			matches = current
+		}
+		hook.setLastError(err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(hook.interval):
+		}
+	}
+}
+
+// check returns the entries that currently satisfy the webhook's query, keyed
+// by their path. A missing path means that there aren't any entries.
+func (hook *webhook) check(ctx context.Context) (map[string]apitypes.Entry, error) {
+	if washPath, errResp := toWashPath(ctx, hook.Path); errResp == nil {
+		plugin.ClearCacheFor(washPath, true)
+	}
+	entry, _, errResp := getEntryFromPath(ctx, hook.Path)
+	if errResp != nil {
+		if errResp.body.Kind == apitypes.EntryNotFound {
+			return map[string]apitypes.Entry{}, nil
+		}
+		return nil, errResp
+	}
+
+	entries, err := rql.Find(ctx, entry, hook.query, hook.opts)
+	if err != nil {
+		return nil, err
+	}
+	matches := make(map[string]apitypes.Entry, len(entries))
+	for _, rqlEntry := range entries {
+		apiEntry := rqlEntry.Entry
+		if apiEntry.Path == "" {
+			apiEntry.Path = hook.Path
+		} else {
+			apiEntry.Path = hook.Path + "/" + apiEntry.Path
+		}
+		matches[apiEntry.Path] = apiEntry
+	}
+	return matches, nil
+}
+
+// notify POSTs each event to the webhook's URL. It returns the last error so
+// that one unreachable notification doesn't drop the remaining events.
+func (hook *webhook) notify(ctx context.Context, client *http.Client, events []apitypes.WebhookEvent) error {
+	var lastErr error
+	for _, event := range events {
+		if err := hook.post(ctx, client, event); err != nil {
+			activity.Warnf(ctx, "Webhook %v: could not notify %v that %v was %v: %v", hook.ID, hook.URL, event.Entry.Path, event.Type, err)
+			lastErr = err
+			continue
+		}
+		activity.Record(ctx, "Webhook %v: notified %v that %v was %v", hook.ID, hook.URL, event.Entry.Path, event.Type)
+	}
+	return lastErr
+}
+
+func (hook *webhook) post(ctx context.Context, client *http.Client, event apitypes.WebhookEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("the webhook responded with %v", resp.Status)
+	}
+	return nil
+}
+
+// diffWebhookMatches returns the events that turn the old matches into the new
+// ones, sorted by path. Entries are considered modified if any of their
+// attributes besides atime changed, since atime's usually the time of the check.
+func diffWebhookMatches(id string, old map[string]apitypes.Entry, current map[string]apitypes.Entry, now time.Time) []apitypes.WebhookEvent {
+	var events []apitypes.WebhookEvent
+	for path, entry := range current {
+		oldEntry, ok := old[path]
+		if !ok {
+			events = append(events, apitypes.WebhookEvent{Webhook: id, Type: apitypes.WebhookEntryCreated, Entry: entry, Time: now})
+		} else if attributesFingerprint(oldEntry) != attributesFingerprint(entry) {
+			events = append(events, apitypes.WebhookEvent{Webhook: id, Type: apitypes.WebhookEntryModified, Entry: entry, Time: now})
+		}
+	}
+	for path, entry := range old {
+		if _, ok := current[path]; !ok {
+			events = append(events, apitypes.WebhookEvent{Webhook: id, Type: apitypes.WebhookEntryDeleted, Entry: entry, Time: now})
+		}
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Entry.Path < events[j].Entry.Path
+	})
+	return events
+}
+
+func attributesFingerprint(e apitypes.Entry) string {
+	attributes := e.Attributes.ToMap()
+	delete(attributes, "atime")
+	fingerprint, err := json.Marshal(attributes)
+	if err != nil {
+		// This shouldn't happen since the attributes were already marshalled
+		// by the plugin.
+		return ""
+	}
+	return string(fingerprint)
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	apitypes "github.com/puppetlabs/wash/api/types"
+	"github.com/stretchr/testify/suite"
+)
+
+type WebhookTestSuite struct {
+	suite.Suite
+}
+
+func (suite *WebhookTestSuite) TestNewWebhook() {
+	_, errResp := newWebhook(apitypes.Webhook{URL: "ftp://example.com", Path: "/mnt/docker"})
+	if suite.NotNil(errResp) {
+		suite.Equal(apitypes.BadRequest, errResp.body.Kind)
+	}
+	_, errResp = newWebhook(apitypes.Webhook{URL: "http://example.com"})
+	if suite.NotNil(errResp) {
+		suite.Equal(apitypes.BadRequest, errResp.body.Kind)
+	}
+	_, errResp = newWebhook(apitypes.Webhook{URL: "http://example.com", Path: "docker"})
+	if suite.NotNil(errResp) {
+		suite.Equal(apitypes.RelativePath, errResp.body.Kind)
+	}
+	_, errResp = newWebhook(apitypes.Webhook{URL: "http://example.com", Path: "/mnt/docker", Query: []interface{}{"foo"}})
+	if suite.NotNil(errResp) {
+		suite.Equal(apitypes.BadRequest, errResp.body.Kind)
+	}
+	_, errResp = newWebhook(apitypes.Webhook{URL: "http://example.com", Path: "/mnt/docker", Interval: "foo"})
+	if suite.NotNil(errResp) {
+		suite.Equal(apitypes.InvalidDuration, errResp.body.Kind)
+	}
+
+	maxdepth := 1
+	hook, errResp := newWebhook(apitypes.Webhook{
+		ID:       "5",
+		URL:      "https://example.com/hook",
+		Path:     "/mnt/docker/containers",
+		Query:    []interface{}{"name", []interface{}{"glob", "web*"}},
+		Interval: "1s",
+		Maxdepth: &maxdepth,
+	})
+	if suite.Nil(errResp) {
+		suite.Equal("", hook.ID)
+		suite.Equal(MinWebhookInterval, hook.interval)
+		suite.Equal(MinWebhookInterval.String(), hook.Interval)
+		suite.Equal(1, hook.opts.Maxdepth)
+	}
+
+	hook, errResp = newWebhook(apitypes.Webhook{URL: "http://example.com", Path: "/mnt/docker"})
+	if suite.Nil(errResp) {
+		suite.Equal(DefaultWebhookInterval, hook.interval)
+	}
+}
+
+func (suite *WebhookTestSuite) TestDiffWebhookMatches() {
+	now := time.Now()
+	newEntry := func(path string, size uint64) apitypes.Entry {
+		e := apitypes.Entry{Path: path}
+		e.Attributes.SetSize(size).SetAtime(time.Now())
+		return e
+	}
+
+	old := map[string]apitypes.Entry{
+		"/mnt/foo": newEntry("/mnt/foo", 1),
+		"/mnt/bar": newEntry("/mnt/bar", 1),
+		"/mnt/baz": newEntry("/mnt/baz", 1),
+	}
+	current := map[string]apitypes.Entry{
+		"/mnt/foo":  newEntry("/mnt/foo", 1),
+		"/mnt/bar":  newEntry("/mnt/bar", 2),
+		"/mnt/quux": newEntry("/mnt/quux", 1),
+	}
+	events := diffWebhookMatches("1", old, current, now)
+	suite.Equal([]apitypes.WebhookEvent{
+		{Webhook: "1", Type: apitypes.WebhookEntryModified, Entry: current["/mnt/bar"], Time: now},
+		{Webhook: "1", Type: apitypes.WebhookEntryDeleted, Entry: old["/mnt/baz"], Time: now},
+		{Webhook: "1", Type: apitypes.WebhookEntryCreated, Entry: current["/mnt/quux"], Time: now},
+	}, events)
+
+	suite.Empty(diffWebhookMatches("1", current, current, now))
+}
+
+func TestWebhook(t *testing.T) {
+	suite.Run(t, new(WebhookTestSuite))
+}
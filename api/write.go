@@ -0,0 +1,55 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// swagger:route PUT /fs/write write writeEntry
+//
+// Replaces the content of the entry at the specified path with the request body.
+//
+// The body is piped to entries that can consume their content from a stream, so
+// writing large content doesn't require buffering it. Otherwise, the entire body
+// is buffered before it's written. The entry's cached data is cleared once the
+// write succeeds.
+//
+//     Consumes:
+//     - application/octet-stream
+//
+//     Produces:
+//     - application/json
+//
+//     Schemes: http
+//
+//     Responses:
+//       200:
+//       400: errorResp
+//       404: errorResp
+//       500: errorResp
+var writeHandler = handler{fn: func(w http.ResponseWriter, r *http.Request) *errorResponse {
+	ctx := r.Context()
+	entry, path, errResp := getEntryFromRequest(r)
+	if errResp != nil {
+		return errResp
+	}
+
+	if !plugin.WriteAction().IsSupportedOn(entry) {
+		return unsupportedActionResponse(path, plugin.WriteAction())
+	}
+
+	if r.Body == nil {
+		return badActionRequestResponse(path, plugin.WriteAction(), "Please send the new content as the request body")
+	}
+
+	// ContentLength is -1 if the body's size is unknown, which matches
+	// StreamWritable's convention.
+	if err := plugin.WriteStream(ctx, entry.(plugin.Writable), r.Body, r.ContentLength); err != nil {
+		return erroredActionResponse(path, plugin.WriteAction(), err.Error())
+	}
+
+	activity.Record(ctx, "API: Write %v: %v bytes", path, r.ContentLength)
+	return nil
+}}
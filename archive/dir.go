@@ -0,0 +1,118 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// ListTTL is the TTL used for an archive's listing. Listing an archive
+// requires reading and decompressing all of its content, so we cache it
+// for a while.
+var ListTTL = 5 * time.Minute
+
+// Dir represents the decompressed content of a readable entry.
+type Dir struct {
+	plugin.EntryBase
+	source plugin.Entry
+}
+
+// NewDir creates a new Dir entry with the given name. The Dir's children are
+// generated by reading and decompressing source, which must be readable.
+func NewDir(name string, source plugin.Entry) *Dir {
+	if !plugin.ReadAction().IsSupportedOn(source) {
+		panic("archive.NewDir called with a non-readable source")
+	}
+
+	d := &Dir{
+		EntryBase: plugin.NewEntry(name),
+	}
+	d.source = source
+	d.SetTTLOf(plugin.ListOp, ListTTL)
+	d.Attributes().SetMode(os.ModeDir | 0550)
+	if attr := plugin.Attributes(source); attr.HasMtime() {
+		d.Attributes().SetMtime(attr.Mtime())
+	}
+	return d
+}
+
+// AddDirs returns the entries with a sibling Dir for each readable entry whose
+// name has an archive extension, e.g. "app.log.gz.contents" for "app.log.gz".
+// Plugins use it to let users browse their compressed files' content. A Dir
+// isn't added if one of the entries already has its name.
+func AddDirs(entries []plugin.Entry) []plugin.Entry {
+	names := make(map[string]struct{}, len(entries))
+	for _, entry := range entries {
+		names[plugin.Name(entry)] = struct{}{}
+	}
+	for _, entry := range entries {
+		name := plugin.Name(entry)
+		if !HasArchiveExtension(name) || !plugin.ReadAction().IsSupportedOn(entry) {
+			continue
+		}
+		dirName := DirName(name)
+		if _, ok := names[dirName]; ok {
+			continue
+		}
+		entries = append(entries, NewDir(dirName, entry))
+	}
+	return entries
+}
+
+// ChildSchemas returns the Dir entry's child schemas
+func (d *Dir) ChildSchemas() []*plugin.EntrySchema {
+	return ChildSchemas()
+}
+
+// Schema returns the Dir entry's schema
+func (d *Dir) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(d, "archive").
+		SetDescription(archiveDescription)
+}
+
+// List reads and decompresses the source entry, returning its content.
+func (d *Dir) List(ctx context.Context) ([]plugin.Entry, error) {
+	size, err := plugin.Size(ctx, d.source)
+	if err != nil {
+		return nil, err
+	}
+	if int64(size) > MaxSize {
+		return nil, fmt.Errorf("%v is too large to browse (%v bytes, the maximum is %v)", plugin.Name(d.source), size, MaxSize)
+	}
+
+	activity.Record(ctx, "Reading %v bytes from %v to browse its content", size, plugin.ID(d.source))
+	content, err := plugin.Read(ctx, d.source, int64(size), 0)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	root, err := extract(plugin.Name(d.source), content)
+	if err != nil {
+		return nil, err
+	}
+	return root.entries(), nil
+}
+
+// ChildSchemas returns the child schemas of an archive's directories
+func ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&dir{}).Schema(),
+		(&file{}).Schema(),
+	}
+}
+
+const archiveDescription = `
+This is the decompressed content of an archive. Wash detects gzip, tar
+(including .tar.gz/.tgz) and zip content from the archive's leading bytes.
+Plain gzip streams are presented as a single decompressed file, while tar
+and zip archives are presented as a hierarchy of their members.
+
+Listing an archive reads and decompresses the entire archive, so the result
+is cached for a while. Archive members are read-only.
+`
@@ -0,0 +1,83 @@
+package archive
+
+import (
+	"context"
+
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// dir represents a directory within an archive. Its children are known
+// as soon as the archive's extracted, so they are prefetched.
+type dir struct {
+	plugin.EntryBase
+	node *node
+}
+
+func newDir(name string, n *node) *dir {
+	d := &dir{
+		EntryBase: plugin.NewEntry(name),
+	}
+	d.node = n
+	d.SetAttributes(n.attr)
+	d.Prefetched()
+	d.DisableCachingFor(plugin.ListOp)
+	return d
+}
+
+func (d *dir) ChildSchemas() []*plugin.EntrySchema {
+	return ChildSchemas()
+}
+
+func (d *dir) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(d, "dir").SetDescription(dirDescription)
+}
+
+func (d *dir) List(context.Context) ([]plugin.Entry, error) {
+	return d.node.entries(), nil
+}
+
+// file represents a decompressed file within an archive.
+type file struct {
+	plugin.EntryBase
+	content []byte
+}
+
+func newFile(name string, n *node) *file {
+	f := &file{
+		EntryBase: plugin.NewEntry(name),
+	}
+	f.content = n.content
+	f.SetAttributes(n.attr)
+	f.Prefetched()
+	f.DisableCachingFor(plugin.ReadOp)
+	return f
+}
+
+func (f *file) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(f, "file").SetDescription(fileDescription)
+}
+
+func (f *file) Read(context.Context) ([]byte, error) {
+	return f.content, nil
+}
+
+// entries returns the entries representing n's children
+func (n *node) entries() []plugin.Entry {
+	entries := make([]plugin.Entry, 0, len(n.children))
+	for name, child := range n.children {
+		if child.children != nil {
+			entries = append(entries, newDir(name, child))
+		} else {
+			entries = append(entries, newFile(name, child))
+		}
+	}
+	return entries
+}
+
+const dirDescription = `
+This is a directory within an archive.
+`
+
+const fileDescription = `
+This is a decompressed file within an archive.
+`
@@ -0,0 +1,221 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// MaxSize is the maximum total size of the decompressed content that Wash
+// will hold in memory for a single archive.
+var MaxSize int64 = 512 * 1024 * 1024
+
+// node represents a decompressed file or a directory within an archive.
+type node struct {
+	attr     plugin.EntryAttributes
+	content  []byte
+	children map[string]*node
+}
+
+func newDirNode(mtime time.Time) *node {
+	n := &node{children: make(map[string]*node)}
+	n.attr.SetMode(os.ModeDir | 0550)
+	if !mtime.IsZero() {
+		n.attr.SetMtime(mtime)
+	}
+	return n
+}
+
+// add adds a file with the given path to the tree rooted at n, creating any
+// intermediate directories along the way. It returns an error if the path
+// conflicts with a member that was already added, i.e. if one of its parents
+// is a file or if it names both a file and a directory.
+func (n *node) add(p string, file *node) error {
+	trimmed := strings.Trim(path.Clean("/"+p), "/")
+	if trimmed == "" {
+		return nil
+	}
+	segments := strings.Split(trimmed, "/")
+	parent := n
+	for i, segment := range segments[:len(segments)-1] {
+		child, ok := parent.children[segment]
+		if !ok {
+			child = newDirNode(time.Time{})
+			parent.children[segment] = child
+		} else if child.children == nil {
+			return fmt.Errorf("%v is a file, so %v can't be in it", strings.Join(segments[:i+1], "/"), trimmed)
+		}
+		parent = child
+	}
+	basename := segments[len(segments)-1]
+	existing, ok := parent.children[basename]
+	switch {
+	case !ok:
+		parent.children[basename] = file
+	case existing.children != nil && file.children != nil:
+		// Explicit directory headers can appear after their children, so preserve
+		// any children that were already added.
+		existing.attr = file.attr
+	case existing.children == nil && file.children == nil:
+		// Appending to a tar archive adds a newer version of a member instead of
+		// replacing it, so the last version wins.
+		parent.children[basename] = file
+	default:
+		return fmt.Errorf("%v is both a file and a directory", trimmed)
+	}
+	return nil
+}
+
+// tarHeaderSize is the size of a tar header block, which is enough to detect
+// whether a gzip stream contains a tar archive.
+const tarHeaderSize = 512
+
+// extract decompresses content and returns a tree representing it. name is
+// used to name the decompressed file when content is a plain gzip stream.
+func extract(name string, content []byte) (*node, error) {
+	root := newDirNode(time.Time{})
+	x := &extraction{}
+	switch Detect(content) {
+	case Gzip:
+		gz, err := gzip.NewReader(bytes.NewReader(content))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read gzip stream: %w", err)
+		}
+		defer gz.Close()
+		// A gzip'd tar archive's members are extracted while the stream is
+		// decompressed so that the tar archive itself isn't held in memory.
+		r := bufio.NewReaderSize(gz, tarHeaderSize)
+		if header, _ := r.Peek(tarHeaderSize); Detect(header) == Tar {
+			return root, x.extractTar(root, r)
+		}
+		data, err := x.readAll(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress gzip stream: %w", err)
+		}
+		file := newFileNode(data, gz.ModTime, 0440)
+		return root, root.add(gunzippedName(name, gz.Name), file)
+	case Tar:
+		return root, x.extractTar(root, bytes.NewReader(content))
+	case Zip:
+		return root, x.extractZip(root, content)
+	default:
+		return nil, fmt.Errorf("%v is not a gzip, tar or zip archive", name)
+	}
+}
+
+// extraction tracks the size of the content that's been decompressed from an
+// archive. The total size is limited, not just each member's, so that an
+// archive with many large members can't exhaust the daemon's memory.
+type extraction struct {
+	size int64
+}
+
+func (x *extraction) extractTar(root *node, content io.Reader) error {
+	r := tar.NewReader(content)
+	for {
+		hdr, err := r.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read tar header: %w", err)
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := root.add(hdr.Name, newDirNode(hdr.ModTime)); err != nil {
+				return err
+			}
+		case tar.TypeReg, tar.TypeRegA:
+			data, err := x.readAll(r)
+			if err != nil {
+				return fmt.Errorf("failed to read %v: %w", hdr.Name, err)
+			}
+			if err := root.add(hdr.Name, newFileNode(data, hdr.ModTime, hdr.FileInfo().Mode().Perm())); err != nil {
+				return err
+			}
+		default:
+			// Skip links, devices and other special files. They don't have any content
+			// that's worth browsing.
+		}
+	}
+}
+
+func (x *extraction) extractZip(root *node, content []byte) error {
+	r, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return fmt.Errorf("failed to read zip archive: %w", err)
+	}
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() {
+			if err := root.add(f.Name, newDirNode(f.Modified)); err != nil {
+				return err
+			}
+			continue
+		}
+		rdr, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("failed to open %v: %w", f.Name, err)
+		}
+		data, err := x.readAll(rdr)
+		rdr.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read %v: %w", f.Name, err)
+		}
+		if err := root.add(f.Name, newFileNode(data, f.Modified, f.Mode().Perm())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readAll reads r, returning an error once the archive's decompressed content
+// exceeds MaxSize bytes. This guards against decompression bombs.
+func (x *extraction) readAll(r io.Reader) ([]byte, error) {
+	data, err := ioutil.ReadAll(io.LimitReader(r, MaxSize-x.size+1))
+	if err != nil {
+		return nil, err
+	}
+	x.size += int64(len(data))
+	if x.size > MaxSize {
+		return nil, fmt.Errorf("decompressed content exceeds the maximum size of %v bytes", MaxSize)
+	}
+	return data, nil
+}
+
+func newFileNode(content []byte, mtime time.Time, perm os.FileMode) *node {
+	n := &node{content: content}
+	n.attr.SetMode(perm).SetSize(uint64(len(content)))
+	if !mtime.IsZero() {
+		n.attr.SetMtime(mtime)
+	}
+	return n
+}
+
+// gunzippedName returns the name of the decompressed file. It prefers the
+// name recorded in the gzip header, falling back to name without its
+// extension.
+func gunzippedName(name string, headerName string) string {
+	if headerName != "" {
+		return path.Base(headerName)
+	}
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".tgz"):
+		return name[:len(name)-len(".tgz")] + ".tar"
+	case strings.HasSuffix(lower, ".gz"):
+		return name[:len(name)-len(".gz")]
+	default:
+		return name
+	}
+}
@@ -0,0 +1,165 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func gzipBytes(t *testing.T, name string, content []byte) []byte {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	w.Name = name
+	_, err := w.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func tarBytes(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	w := tar.NewWriter(&buf)
+	for name, content := range files {
+		require.NoError(t, w.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0644,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+			Format:   tar.FormatPAX,
+		}))
+		_, err := w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func zipBytes(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestDetect(t *testing.T) {
+	assert.Equal(t, Gzip, Detect(gzipBytes(t, "", []byte("hello"))))
+	assert.Equal(t, Tar, Detect(tarBytes(t, map[string]string{"a": "b"})))
+	assert.Equal(t, Zip, Detect(zipBytes(t, map[string]string{"a": "b"})))
+	assert.Equal(t, Unknown, Detect([]byte("hello")))
+	assert.Equal(t, Unknown, Detect(nil))
+}
+
+func TestHasArchiveExtension(t *testing.T) {
+	for _, name := range []string{"a.gz", "a.log.GZ", "a.tgz", "a.tar", "a.tar.gz", "a.zip"} {
+		assert.True(t, HasArchiveExtension(name), name)
+	}
+	for _, name := range []string{"a", "a.log", "gz", "a.gzip"} {
+		assert.False(t, HasArchiveExtension(name), name)
+	}
+}
+
+func TestExtract_Gzip(t *testing.T) {
+	root, err := extract("app.log.gz", gzipBytes(t, "", []byte("hello")))
+	require.NoError(t, err)
+	require.Contains(t, root.children, "app.log")
+	f := root.children["app.log"]
+	assert.Equal(t, []byte("hello"), f.content)
+	assert.Equal(t, uint64(5), f.attr.Size())
+
+	// Prefer the name in the gzip header
+	root, err = extract("foo.gz", gzipBytes(t, "dir/bar.txt", []byte("hello")))
+	require.NoError(t, err)
+	assert.Contains(t, root.children, "bar.txt")
+}
+
+func TestExtract_TarGz(t *testing.T) {
+	content := gzipBytes(t, "", tarBytes(t, map[string]string{
+		"a.txt":     "a",
+		"sub/b.txt": "bb",
+	}))
+	root, err := extract("foo.tgz", content)
+	require.NoError(t, err)
+
+	require.Contains(t, root.children, "a.txt")
+	assert.Equal(t, []byte("a"), root.children["a.txt"].content)
+	require.Contains(t, root.children, "sub")
+	sub := root.children["sub"]
+	assert.True(t, sub.attr.Mode().IsDir())
+	require.Contains(t, sub.children, "b.txt")
+	assert.Equal(t, []byte("bb"), sub.children["b.txt"].content)
+}
+
+func TestExtract_Zip(t *testing.T) {
+	root, err := extract("foo.zip", zipBytes(t, map[string]string{
+		"a.txt":       "a",
+		"sub/":        "",
+		"sub/b/c.txt": "ccc",
+	}))
+	require.NoError(t, err)
+
+	require.Contains(t, root.children, "a.txt")
+	require.Contains(t, root.children, "sub")
+	b := root.children["sub"].children["b"]
+	require.NotNil(t, b)
+	assert.Equal(t, []byte("ccc"), b.children["c.txt"].content)
+	assert.Equal(t, uint64(3), b.children["c.txt"].attr.Size())
+}
+
+func TestExtract_Unknown(t *testing.T) {
+	_, err := extract("foo.gz", []byte("not compressed"))
+	assert.Error(t, err)
+}
+
+func TestExtract_MaxSize(t *testing.T) {
+	defer func(orig int64) { MaxSize = orig }(MaxSize)
+	MaxSize = 4
+	_, err := extract("foo.gz", gzipBytes(t, "", []byte("too large")))
+	assert.Error(t, err)
+}
+
+func TestExtract_MaxSizeIsCumulative(t *testing.T) {
+	defer func(orig int64) { MaxSize = orig }(MaxSize)
+	MaxSize = 4
+	// Each member fits, but together they don't.
+	files := map[string]string{"a.txt": "aaa", "b.txt": "bbb"}
+	_, err := extract("foo.tar", tarBytes(t, files))
+	assert.Error(t, err)
+	_, err = extract("foo.tgz", gzipBytes(t, "", tarBytes(t, files)))
+	assert.Error(t, err)
+	_, err = extract("foo.zip", zipBytes(t, files))
+	assert.Error(t, err)
+
+	_, err = extract("foo.tar", tarBytes(t, map[string]string{"a.txt": "aa", "b.txt": "bb"}))
+	assert.NoError(t, err)
+}
+
+func TestNodeAdd_Conflicts(t *testing.T) {
+	root := newDirNode(time.Time{})
+	require.NoError(t, root.add("sub/a.txt", newFileNode([]byte("a"), time.Time{}, 0644)))
+	// An explicit directory header after its children keeps them.
+	require.NoError(t, root.add("sub/", newDirNode(time.Time{})))
+	assert.Contains(t, root.children["sub"].children, "a.txt")
+	// A newer version of a file replaces it.
+	require.NoError(t, root.add("sub/a.txt", newFileNode([]byte("aa"), time.Time{}, 0644)))
+	assert.Equal(t, []byte("aa"), root.children["sub"].children["a.txt"].content)
+
+	// A file can't replace a directory, or vice versa.
+	assert.Error(t, root.add("sub", newFileNode([]byte("b"), time.Time{}, 0644)))
+	assert.Error(t, root.add("sub/a.txt/", newDirNode(time.Time{})))
+	// A file can't be a parent directory.
+	assert.Error(t, root.add("sub/a.txt/c.txt", newFileNode([]byte("c"), time.Time{}, 0644)))
+	assert.Equal(t, []byte("aa"), root.children["sub"].children["a.txt"].content)
+	assert.Len(t, root.children["sub"].children, 1)
+}
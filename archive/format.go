@@ -0,0 +1,86 @@
+// Package archive provides helpers for browsing compressed and archived content.
+//
+// Plugins can opt into archive browsing by returning an archive.Dir next to any
+// readable entry whose content could be a gzip, tar or zip file (see HasArchiveExtension).
+// The archive.Dir lists the decompressed content as a file, or the archive's members as
+// a hierarchy of files and directories. This lets users grep compressed logs through the
+// mount without having to download and extract them first.
+package archive
+
+import (
+	"bytes"
+	"strings"
+)
+
+// Format represents a supported compression or archive format.
+type Format int
+
+// Enumerates the supported formats.
+const (
+	Unknown Format = iota
+	Gzip
+	Tar
+	Zip
+)
+
+func (f Format) String() string {
+	switch f {
+	case Gzip:
+		return "gzip"
+	case Tar:
+		return "tar"
+	case Zip:
+		return "zip"
+	default:
+		return "unknown"
+	}
+}
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zipMagic  = []byte("PK\x03\x04")
+	// The "ustar" magic starts at offset 257 of a tar header. It is followed by
+	// either "\x0000" (POSIX) or "  \x00" (GNU).
+	tarMagic       = []byte("ustar")
+	tarMagicOffset = 257
+)
+
+// Detect returns the format of the given content by inspecting its leading
+// bytes. It returns Unknown if the content isn't in a supported format.
+func Detect(content []byte) Format {
+	switch {
+	case bytes.HasPrefix(content, gzipMagic):
+		return Gzip
+	case bytes.HasPrefix(content, zipMagic):
+		return Zip
+	case len(content) >= tarMagicOffset+len(tarMagic) &&
+		bytes.Equal(content[tarMagicOffset:tarMagicOffset+len(tarMagic)], tarMagic):
+		return Tar
+	default:
+		return Unknown
+	}
+}
+
+var archiveExtensions = []string{".gz", ".tgz", ".tar", ".zip"}
+
+// HasArchiveExtension returns true if name ends with a well-known gzip, tar
+// or zip extension. Plugins should use it to decide whether an entry is worth
+// wrapping in an archive.Dir, since detecting the actual format requires
+// reading the entry's content.
+func HasArchiveExtension(name string) bool {
+	lower := strings.ToLower(name)
+	for _, ext := range archiveExtensions {
+		if strings.HasSuffix(lower, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// DirName returns the name of the archive.Dir for an entry with the given
+// name.
+func DirName(name string) string {
+	return name + dirSuffix
+}
+
+const dirSuffix = ".contents"
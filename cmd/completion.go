@@ -0,0 +1,294 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/puppetlabs/wash/api/client"
+	"github.com/puppetlabs/wash/api/rql/ast"
+	apitypes "github.com/puppetlabs/wash/api/types"
+	"github.com/puppetlabs/wash/cmd/internal/find/primary"
+	"github.com/puppetlabs/wash/cmd/internal/find/types"
+	"github.com/puppetlabs/wash/cmd/internal/shell"
+	cmdutil "github.com/puppetlabs/wash/cmd/util"
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func completionCommand() *cobra.Command {
+	completionCmd := &cobra.Command{
+		Use:   "completion [bash|zsh]",
+		Short: "Prints or installs Wash's shell completion script",
+		Long: `Prints the completion script for the given shell, which defaults to $SHELL. Besides
+paths, the script completes subcommands, flags, 'find' primaries, action names,
+kinds and signals. Kinds and signals are completed from the schemas of the entries
+in the current directory, so they're only completed inside the Wash shell (which
+already loads the script).
+
+With --install, the script is written to Wash's config directory and sourced by
+~/.bashrc or ~/.zshrc.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: toRunE(completionMain),
+	}
+	completionCmd.Flags().Bool("install", false, "Install the completion script instead of printing it")
+	return completionCmd
+}
+
+func completionMain(cmd *cobra.Command, args []string) exitCode {
+	sh := filepath.Base(os.Getenv("SHELL"))
+	if len(args) > 0 {
+		sh = args[0]
+	}
+	script, err := shell.Completion(sh, []string{"wash"})
+	if err != nil {
+		cmdutil.ErrPrintf("%v\n", err)
+		return exitCode{1}
+	}
+
+	install, err := cmd.Flags().GetBool("install")
+	if err != nil {
+		panic(err.Error())
+	}
+	if !install {
+		cmdutil.Print(script)
+		return exitCode{0}
+	}
+
+	scriptPath, rcPath, err := installCompletion(sh, script)
+	if err != nil {
+		cmdutil.ErrPrintf("Failed to install the completion script: %v\n", err)
+		return exitCode{1}
+	}
+	cmdutil.Printf("Installed the completion script to %v and sourced it in %v. Restart your shell to use it.\n", scriptPath, rcPath)
+	return exitCode{0}
+}
+
+// installCompletion writes the completion script to Wash's config directory and
+// sources it in the shell's rc file if it isn't sourced already.
+func installCompletion(sh string, script string) (string, string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", "", err
+	}
+	scriptPath := filepath.Join(configDir, "wash", "completion."+sh)
+	if err := os.MkdirAll(filepath.Dir(scriptPath), 0750); err != nil {
+		return "", "", err
+	}
+	if err := ioutil.WriteFile(scriptPath, []byte(script), 0644); err != nil {
+		return "", "", err
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", "", err
+	}
+	rcPath := filepath.Join(homeDir, ".bashrc")
+	if sh == "zsh" {
+		if zdotdir := os.Getenv("ZDOTDIR"); zdotdir != "" {
+			homeDir = zdotdir
+		}
+		rcPath = filepath.Join(homeDir, ".zshrc")
+	}
+	rc, err := ioutil.ReadFile(rcPath)
+	if err != nil && !os.IsNotExist(err) {
+		return "", "", err
+	}
+	sourceLine := fmt.Sprintf("[ -s '%v' ] && source '%v'", scriptPath, scriptPath)
+	if strings.Contains(string(rc), sourceLine) {
+		return scriptPath, rcPath, nil
+	}
+	f, err := os.OpenFile(rcPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return "", "", err
+	}
+	defer f.Close()
+	_, err = f.WriteString("\n# Wash completion\n" + sourceLine + "\n")
+	return scriptPath, rcPath, err
+}
+
+// completeCommand is called by the completion script. Its arguments are the
+// words of the command line up to and including the word that's being completed.
+func completeCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:                "__complete <word>...",
+		Hidden:             true,
+		DisableFlagParsing: true,
+		Args:               cobra.MinimumNArgs(1),
+		RunE:               toRunE(completeMain),
+	}
+}
+
+func completeMain(cmd *cobra.Command, args []string) exitCode {
+	for _, candidate := range complete(cmd.Root(), cmdutil.NewClient(), args) {
+		cmdutil.Println(candidate)
+	}
+	return exitCode{0}
+}
+
+// complete returns the candidates for the last word. An empty list means that the
+// completion script should fall back to completing paths.
+func complete(root *cobra.Command, conn client.Client, words []string) []string {
+	if words[0] == "wash" {
+		words = words[1:]
+		if len(words) <= 1 {
+			var names []string
+			for _, subcommand := range root.Commands() {
+				if !subcommand.Hidden {
+					names = append(names, subcommand.Name())
+				}
+			}
+			return withPrefix(names, lastWord(words))
+		}
+	}
+	subcommand, _, err := root.Find(words[:1])
+	if err != nil || subcommand == root {
+		return nil
+	}
+
+	args, cur := words[1:len(words)-1], words[len(words)-1]
+	var prev string
+	if len(args) > 0 {
+		prev = args[len(args)-1]
+	}
+	switch subcommand.Name() {
+	case "find":
+		return completeFind(conn, args, prev, cur)
+	case "signal":
+		if len(args) == 0 {
+			return withPrefix(signalNames(conn, "."), cur)
+		}
+	case "wait":
+		if prev == "--query" {
+			return completeRQLPrimary(cur)
+		}
+	}
+	if strings.HasPrefix(cur, "-") {
+		var flags []string
+		subcommand.Flags().VisitAll(func(f *pflag.Flag) {
+			flags = append(flags, "--"+f.Name)
+			if f.Shorthand != "" {
+				flags = append(flags, "-"+f.Shorthand)
+			}
+		})
+		return withPrefix(flags, cur)
+	}
+	return nil
+}
+
+// completeFind completes `wash find`'s options, primaries and their arguments.
+func completeFind(conn client.Client, args []string, prev string, cur string) []string {
+	switch prev {
+	case "-action":
+		var actions []string
+		for action := range plugin.Actions() {
+			actions = append(actions, action)
+		}
+		return withPrefix(actions, cur)
+	case "-kind", "-k":
+		path := "."
+		if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+			path = args[0]
+		}
+		return withPrefix(kinds(conn, path), cur)
+	}
+	if !strings.HasPrefix(cur, "-") {
+		return nil
+	}
+	tokens := primary.Tokens()
+	opts := types.NewOptions()
+	opts.FlagSet().VisitAll(func(f *flag.Flag) {
+		tokens = append(tokens, "-"+f.Name)
+	})
+	return withPrefix(tokens, cur)
+}
+
+// completeRQLPrimary completes the primary at the start of an RQL query, e.g.
+// '["na completes to '["name"
+func completeRQLPrimary(cur string) []string {
+	ix := strings.Index(cur, `["`)
+	if ix < 0 || strings.Trim(cur[:ix], `'`) != "" {
+		return nil
+	}
+	var candidates []string
+	for _, name := range ast.PrimaryNames() {
+		candidates = append(candidates, cur[:ix]+`["`+name+`"`)
+	}
+	return withPrefix(candidates, cur)
+}
+
+// kinds returns the kinds of the entries under path, i.e. the paths in its
+// stree relative to its root
+func kinds(conn client.Client, path string) []string {
+	schema, err := conn.Schema(path)
+	if err != nil || schema == nil {
+		return nil
+	}
+	var kinds []string
+	for kind := range schema.ToMap() {
+		segments := strings.SplitN(kind, "/", 2)
+		if len(segments) > 1 {
+			kinds = append(kinds, segments[1])
+		}
+	}
+	return kinds
+}
+
+// signalNames returns the signals that are supported by path and the entries
+// under it
+func signalNames(conn client.Client, path string) []string {
+	schema, err := conn.Schema(path)
+	if err != nil || schema == nil {
+		return nil
+	}
+	signals := make(map[string]struct{})
+	visited := make(map[string]struct{})
+	var visit func(*apitypes.EntrySchema)
+	visit = func(s *apitypes.EntrySchema) {
+		if _, ok := visited[s.TypeID()]; ok {
+			return
+		}
+		visited[s.TypeID()] = struct{}{}
+		for _, signal := range s.Signals() {
+			if !signal.IsGroup() {
+				signals[signal.Name()] = struct{}{}
+			}
+		}
+		for _, child := range s.Children() {
+			visit(child)
+		}
+	}
+	visit(schema)
+	names := make([]string, 0, len(signals))
+	for name := range signals {
+		names = append(names, name)
+	}
+	return names
+}
+
+// withPrefix returns the sorted, unique candidates that start with prefix
+func withPrefix(candidates []string, prefix string) []string {
+	seen := make(map[string]struct{})
+	var matches []string
+	for _, candidate := range candidates {
+		if _, ok := seen[candidate]; ok || !strings.HasPrefix(candidate, prefix) {
+			continue
+		}
+		seen[candidate] = struct{}{}
+		matches = append(matches, candidate)
+	}
+	sort.Strings(matches)
+	return matches
+}
+
+func lastWord(words []string) string {
+	if len(words) == 0 {
+		return ""
+	}
+	return words[len(words)-1]
+}
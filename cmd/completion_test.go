@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"testing"
+
+	apitypes "github.com/puppetlabs/wash/api/types"
+	"github.com/puppetlabs/wash/cmd/internal/cmdtest"
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/stretchr/testify/suite"
+)
+
+type CompletionTestSuite struct {
+	cmdtest.Suite
+}
+
+func (s *CompletionTestSuite) complete(words ...string) []string {
+	return complete(rootCommand(), s.Client, words)
+}
+
+func (s *CompletionTestSuite) TestCompleteSubcommands() {
+	s.Equal([]string{"find"}, s.complete("wash", "fi"))
+	s.NotContains(s.complete("wash", ""), "__complete")
+	s.Nil(s.complete("ls", "fo"))
+}
+
+func (s *CompletionTestSuite) TestCompleteFlags() {
+	s.Equal([]string{"--follow"}, s.complete("wash", "tail", "--fo"))
+	s.Equal([]string{"--follow"}, s.complete("tail", "--fo"))
+}
+
+func (s *CompletionTestSuite) TestCompleteFind() {
+	s.Equal([]string{"-name"}, s.complete("find", ".", "-na"))
+	s.Contains(s.complete("find", "-"), "-maxdepth")
+	s.Equal([]string{"read", "rename"}, s.complete("find", "-action", "r"))
+	s.Nil(s.complete("find", "-name", "fo"))
+
+	root := s.schema("docker", nil)
+	containers := s.schema("docker/containers", nil)
+	container := s.schema("docker/containers/container", nil)
+	root.SetChildren([]*apitypes.EntrySchema{containers})
+	containers.SetChildren([]*apitypes.EntrySchema{container})
+	s.Client.On("Schema", "docker").Return(root, nil)
+	s.Equal(
+		[]string{"containers", "containers/container"},
+		s.complete("wash", "find", "docker", "-kind", "con"),
+	)
+
+	s.Client.On("Schema", "foo").Return((*apitypes.EntrySchema)(nil), fmt.Errorf("failed"))
+	s.Nil(s.complete("find", "foo", "-k", ""))
+}
+
+func (s *CompletionTestSuite) TestCompleteSignals() {
+	start := plugin.SignalSchema{}
+	start.SetName("start")
+	stop := plugin.SignalSchema{}
+	stop.SetName("stop")
+	root := s.schema("docker", nil)
+	container := s.schema("docker/container", []apitypes.SignalSchema{start, stop})
+	root.SetChildren([]*apitypes.EntrySchema{container})
+	s.Client.On("Schema", ".").Return(root, nil)
+
+	s.Equal([]string{"start", "stop"}, s.complete("signal", "st"))
+	s.Nil(s.complete("signal", "start", "fo"))
+}
+
+func (s *CompletionTestSuite) TestCompleteRQLPrimaries() {
+	s.Equal([]string{`'["name"`}, s.complete("wait", "--query", `'["na`))
+	s.Equal([]string{`["meta"`}, s.complete("wait", "--query", `["me`))
+	s.Nil(s.complete("wait", "--query", "foo"))
+}
+
+func (s *CompletionTestSuite) schema(path string, signals []apitypes.SignalSchema) *apitypes.EntrySchema {
+	schema := &apitypes.EntrySchema{}
+	schema.SetPath(path).SetTypeID(path).SetSignals(signals)
+	return schema
+}
+
+func TestCompletion(t *testing.T) {
+	suite.Run(t, new(CompletionTestSuite))
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	cmdutil "github.com/puppetlabs/wash/cmd/util"
+)
+
+func cpCommand() *cobra.Command {
+	cpCmd := &cobra.Command{
+		Use:   "cp <source> <destination>",
+		Short: "Copies the content of the source entry to the destination entry",
+		Long: `Copies the content of the source entry to the destination entry. The source must support
+the read action and the destination must support the write action. The copy's done by the
+Wash daemon, which pipes the content between the entries in bounded chunks when the plugins
+support it. This makes it suitable for copying large (multi-GB) entries across plugins.`,
+		Args: cobra.ExactArgs(2),
+		RunE: toRunE(cpMain),
+	}
+	cpCmd.Flags().BoolP("verbose", "v", false, "Print the number of copied bytes")
+	return cpCmd
+}
+
+func cpMain(cmd *cobra.Command, args []string) exitCode {
+	src, dest := args[0], args[1]
+	verbose, err := cmd.Flags().GetBool("verbose")
+	if err != nil {
+		panic(err.Error())
+	}
+
+	conn := cmdutil.NewClient()
+	copied, err := conn.Copy(src, dest)
+	if err != nil {
+		cmdutil.ErrPrintf("%v: %v\n", src, err)
+		return exitCode{1}
+	}
+	if verbose {
+		cmdutil.Printf("copied %v bytes from %v to %v\n", copied, src, dest)
+	}
+	return exitCode{0}
+}
@@ -15,7 +15,8 @@ func deleteCommand() *cobra.Command {
 		Use:   "delete <path> [<path>]",
 		Short: "Deletes the entries at the specified paths",
 		Long: `Deletes the entries at the specified paths, prompting the user for confirmation
-before deleting each entry.`,
+before deleting each entry. If the trash is enabled, the entries are moved to the
+trash instead; see 'wash trash'.`,
 		Args: cobra.MinimumNArgs(1),
 		RunE: toRunE(deleteMain),
 	}
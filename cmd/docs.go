@@ -73,6 +73,14 @@ func docsMain(cmd *cobra.Command, args []string) exitCode {
 		addSection(docs, stringifySupportedAttributes(path, entry))
 	}
 
+	// Print the custom attributes (if there are any). This part is printed as
+	//   CUSTOM ATTRIBUTES
+	//     * <attribute> (<type>) -- <value>
+	//         <description>
+	if schema != nil && len(schema.CustomAttributes()) > 0 {
+		addSection(docs, stringifyCustomAttributes(entry, schema.CustomAttributes()))
+	}
+
 	// Print the supported actions. This part is printed as
 	//   SUPPORTED ACTIONS
 	//     * <action>
@@ -127,6 +135,10 @@ func stringifySupportedAttributes(path string, entry apitypes.Entry) string {
 		supportedAttributes.WriteString(strings.Join(lines, "\n"))
 	} else {
 		for attr, value := range entry.Attributes.ToMap() {
+			if attr == "custom" {
+				// Custom attributes are printed in their own section
+				continue
+			}
 			supportedAttributes.WriteString(fmt.Sprintf("* %v", attr))
 			var fullAttrName string
 			switch attr {
@@ -138,6 +150,10 @@ func stringifySupportedAttributes(path string, entry apitypes.Entry) string {
 				fullAttrName = "change time"
 			case "crtime":
 				fullAttrName = "creation time"
+			case "uid":
+				fullAttrName = "owner's user ID"
+			case "gid":
+				fullAttrName = "owner's group ID"
 			}
 			if len(fullAttrName) > 0 {
 				supportedAttributes.WriteString(fmt.Sprintf(" (%v)", fullAttrName))
@@ -160,6 +176,25 @@ func stringifySupportedAttributes(path string, entry apitypes.Entry) string {
 	return supportedAttributes.String()
 }
 
+func stringifyCustomAttributes(entry apitypes.Entry, attributes []apitypes.CustomAttributeSchema) string {
+	var customAttributes strings.Builder
+	customAttributes.WriteString("CUSTOM ATTRIBUTES\n")
+	for _, attr := range attributes {
+		customAttributes.WriteString(fmt.Sprintf("* %v (%v)", attr.Name(), attr.Type()))
+		if entry.Attributes.HasCustom(attr.Name()) {
+			customAttributes.WriteString(fmt.Sprintf(" -- %v", entry.Attributes.Custom(attr.Name())))
+		}
+		customAttributes.WriteString("\n")
+		lines := strings.Split(strings.Trim(attr.Description(), "\n"), "\n")
+		for _, line := range lines {
+			customAttributes.WriteString(fmt.Sprintf("    %v\n", line))
+		}
+	}
+	customAttributes.WriteString("\n")
+	customAttributes.WriteString("You can filter on custom attributes with the RQL's 'attr' primary.")
+	return customAttributes.String()
+}
+
 func stringifySupportedActions(path string, entry apitypes.Entry) string {
 	path = shellquote.Join(path)
 	var supportedActions strings.Builder
@@ -222,6 +257,11 @@ func stringifySupportedActions(path string, entry apitypes.Entry) string {
 			actionDescriptionLines = []string{
 				fmt.Sprintf("- delete %s", path),
 			}
+		case plugin.RenameAction().Name:
+			actionDescriptionLines = []string{
+				fmt.Sprintf("- mv %s <new_path>", path),
+				fmt.Sprintf("    <new_path> must be in the same plugin"),
+			}
 		case plugin.SignalAction().Name:
 			actionDescriptionLines = []string{
 				fmt.Sprintf("- signal <signal> %s", path),
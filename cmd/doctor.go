@@ -0,0 +1,322 @@
+package cmd
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v2"
+
+	"github.com/puppetlabs/wash/activity"
+	apitypes "github.com/puppetlabs/wash/api/types"
+	"github.com/puppetlabs/wash/cmd/internal/config"
+	cmdutil "github.com/puppetlabs/wash/cmd/util"
+	"github.com/puppetlabs/wash/cmd/version"
+)
+
+// The bundle includes the tail of the server's log file and the most recently
+// modified journals. These limits keep the bundle small enough to attach to a
+// bug report.
+const (
+	maxBundledLogSize     = 10 * 1024 * 1024
+	maxBundledJournalSize = 1024 * 1024
+	maxBundledJournals    = 20
+)
+
+// secretKeyRegex matches config keys whose values are redacted from the bundle.
+var secretKeyRegex = regexp.MustCompile(`(?i)(password|passwd|token|secret|key|credential)`)
+
+const redacted = "REDACTED"
+
+func doctorCommand() *cobra.Command {
+	doctorCmd := &cobra.Command{
+		Use:   "doctor [--bundle <path>]",
+		Short: "Reports on the health of the Wash daemon",
+		Long: `Prints the daemon's loaded plugins, cache usage and the panics that it recently
+recovered from. A panic only fails the operation that triggered it, so the daemon
+keeps running after one.
+
+With --bundle, writes a diagnostic bundle (a .tar.gz file) that can be attached to
+a bug report. The bundle contains the daemon's diagnostics and goroutine dump, the
+config file with secrets redacted, the tail of the server's log file and the most
+recent activity journals. The bundle's still written if the daemon isn't running,
+in which case it omits the daemon's diagnostics.`,
+		Args: cobra.NoArgs,
+		RunE: toRunE(doctorMain),
+	}
+	doctorCmd.Flags().String("bundle", "", "Write a diagnostic bundle to the given path")
+	doctorCmd.Flags().String("config-file", config.DefaultFile(), "The config file to include in the bundle")
+	doctorCmd.Flags().String("logfile", "", "The server log file to include in the bundle. Defaults to the config file's logfile")
+	return doctorCmd
+}
+
+func doctorMain(cmd *cobra.Command, args []string) exitCode {
+	bundlePath, err := cmd.Flags().GetString("bundle")
+	if err != nil {
+		panic(err.Error())
+	}
+
+	diagnostics, diagErr := cmdutil.NewClient().Diagnostics()
+	if bundlePath == "" {
+		if diagErr != nil {
+			cmdutil.ErrPrintf("Unable to get the daemon's diagnostics: %v\n", diagErr)
+			return exitCode{1}
+		}
+		cmdutil.Print(formatDiagnostics(diagnostics))
+		return exitCode{0}
+	}
+
+	configFile, err := cmd.Flags().GetString("config-file")
+	if err != nil {
+		panic(err.Error())
+	}
+	logFile, err := cmd.Flags().GetString("logfile")
+	if err != nil {
+		panic(err.Error())
+	}
+	if logFile == "" {
+		if err := config.ReadFrom(configFile); err != nil {
+			cmdutil.ErrPrintf("Unable to find the server's log file: %v\n", err)
+		}
+		logFile = viper.GetString("logfile")
+	}
+	if configFile == config.DefaultFile() {
+		configFile = config.DefaultFileAbsPath()
+	}
+
+	if diagErr != nil {
+		cmdutil.ErrPrintf("Unable to get the daemon's diagnostics, omitting them from the bundle: %v\n", diagErr)
+	}
+	bundle := diagnosticBundle{
+		diagnostics: diagnostics,
+		diagErr:     diagErr,
+		configFile:  configFile,
+		logFile:     logFile,
+		journalDir:  activity.Dir(),
+	}
+	if err := bundle.writeTo(bundlePath); err != nil {
+		cmdutil.ErrPrintf("Unable to write the diagnostic bundle: %v\n", err)
+		return exitCode{1}
+	}
+	cmdutil.Printf("Wrote the diagnostic bundle to %v\n", bundlePath)
+	return exitCode{0}
+}
+
+func formatDiagnostics(diagnostics apitypes.Diagnostics) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Plugins: %v\n", strings.Join(diagnostics.Plugins, ", "))
+	if diagnostics.Cache != nil {
+		fmt.Fprintf(
+			&b,
+			"Cache: %v items, %v hits, %v misses\n",
+			diagnostics.Cache.Items,
+			diagnostics.Cache.Hits,
+			diagnostics.Cache.Misses,
+		)
+	}
+	fmt.Fprintf(&b, "Recent panics: %v\n", len(diagnostics.Panics))
+	for _, p := range diagnostics.Panics {
+		fmt.Fprintf(&b, "  %v %v: %v\n", p.Time.Format(time.RFC3339), p.Op, p.Value)
+	}
+	return b.String()
+}
+
+// diagnosticBundle describes what's included in a diagnostic bundle.
+type diagnosticBundle struct {
+	diagnostics apitypes.Diagnostics
+	// diagErr is set if the daemon's diagnostics couldn't be retrieved.
+	diagErr    error
+	configFile string
+	logFile    string
+	journalDir string
+}
+
+// writeTo writes the bundle as a gzipped tarball to the given path.
+func (b diagnosticBundle) writeTo(path string) (err error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	return b.write(f)
+}
+
+func (b diagnosticBundle) write(w io.Writer) error {
+	gw := gzip.NewWriter(w)
+	tw := &bundleWriter{tw: tar.NewWriter(gw), modTime: time.Now()}
+
+	tw.add("version.txt", []byte(fmt.Sprintf(
+		"wash %v\n%v %v/%v\n",
+		version.BuildVersion,
+		runtime.Version(),
+		runtime.GOOS,
+		runtime.GOARCH,
+	)))
+
+	if b.diagErr != nil {
+		tw.add("diagnostics-error.txt", []byte(b.diagErr.Error()+"\n"))
+	} else {
+		diagnostics := b.diagnostics
+		tw.add("goroutines.txt", []byte(diagnostics.Goroutines))
+		diagnostics.Goroutines = ""
+		if data, err := json.MarshalIndent(diagnostics, "", "  "); err != nil {
+			tw.add("diagnostics-error.txt", []byte(err.Error()+"\n"))
+		} else {
+			tw.add("diagnostics.json", data)
+		}
+	}
+
+	if content, err := ioutil.ReadFile(b.configFile); err == nil {
+		if content, err = redactConfig(content); err != nil {
+			tw.add("config-error.txt", []byte(fmt.Sprintf("Unable to redact %v: %v\n", b.configFile, err)))
+		} else {
+			tw.add("wash.yaml", content)
+		}
+	} else if !os.IsNotExist(err) {
+		tw.add("config-error.txt", []byte(err.Error()+"\n"))
+	}
+
+	if b.logFile != "" {
+		tw.addFile("wash.log", b.logFile, maxBundledLogSize)
+	}
+
+	for _, journal := range recentJournals(b.journalDir, maxBundledJournals) {
+		tw.addFile("journals/"+filepath.Base(journal), journal, maxBundledJournalSize)
+	}
+
+	if err := tw.close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// bundleWriter adds files to a tarball. It records the first error so that the
+// bundle's contents can be added without checking for errors after each file.
+type bundleWriter struct {
+	tw      *tar.Writer
+	modTime time.Time
+	err     error
+}
+
+func (w *bundleWriter) add(name string, data []byte) {
+	if w.err != nil {
+		return
+	}
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    0640,
+		Size:    int64(len(data)),
+		ModTime: w.modTime,
+	}
+	if w.err = w.tw.WriteHeader(hdr); w.err != nil {
+		return
+	}
+	_, w.err = w.tw.Write(data)
+}
+
+// addFile adds the last maxSize bytes of the file at path. Files that can't be
+// read are noted in the bundle instead.
+func (w *bundleWriter) addFile(name string, path string, maxSize int64) {
+	data, err := readTail(path, maxSize)
+	if err != nil {
+		w.add(name+".error.txt", []byte(err.Error()+"\n"))
+		return
+	}
+	w.add(name, data)
+}
+
+func (w *bundleWriter) close() error {
+	if w.err != nil {
+		return w.err
+	}
+	return w.tw.Close()
+}
+
+func readTail(path string, maxSize int64) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() > maxSize {
+		if _, err := f.Seek(info.Size()-maxSize, io.SeekStart); err != nil {
+			return nil, err
+		}
+	}
+	return ioutil.ReadAll(io.LimitReader(f, maxSize))
+}
+
+// recentJournals returns the paths of the n most recently modified journals in
+// dir.
+func recentJournals(dir string, n int) []string {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var journals []os.FileInfo
+	for _, file := range files {
+		if !file.IsDir() && filepath.Ext(file.Name()) == ".log" {
+			journals = append(journals, file)
+		}
+	}
+	sort.Slice(journals, func(i, j int) bool {
+		return journals[i].ModTime().After(journals[j].ModTime())
+	})
+	if len(journals) > n {
+		journals = journals[:n]
+	}
+	paths := make([]string, len(journals))
+	for i, journal := range journals {
+		paths[i] = filepath.Join(dir, journal.Name())
+	}
+	return paths
+}
+
+// redactConfig replaces the values of secret-like keys in the given YAML config
+// so that the config can be shared.
+func redactConfig(content []byte) ([]byte, error) {
+	var cfg interface{}
+	if err := yaml.Unmarshal(content, &cfg); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(redactValue(cfg))
+}
+
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		for key, val := range v {
+			if secretKeyRegex.MatchString(fmt.Sprint(key)) {
+				v[key] = redacted
+			} else {
+				v[key] = redactValue(val)
+			}
+		}
+	case []interface{}:
+		for i, val := range v {
+			v[i] = redactValue(val)
+		}
+	}
+	return value
+}
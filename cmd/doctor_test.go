@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	apitypes "github.com/puppetlabs/wash/api/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactConfig(t *testing.T) {
+	content := []byte(`
+plugins: [aws]
+aws:
+  profiles: [dev]
+  secret_access_key: hunter2
+external-plugins:
+  - script: /foo
+    api_token: abc
+    password: xyz
+`)
+	redactedContent, err := redactConfig(content)
+	require.NoError(t, err)
+	assert.Equal(t, `aws:
+  profiles:
+  - dev
+  secret_access_key: REDACTED
+external-plugins:
+- api_token: REDACTED
+  password: REDACTED
+  script: /foo
+plugins:
+- aws
+`, string(redactedContent))
+
+	_, err = redactConfig([]byte("foo: ["))
+	assert.Error(t, err)
+}
+
+func TestDiagnosticBundle(t *testing.T) {
+	dir, err := ioutil.TempDir("", "doctor")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	configFile := filepath.Join(dir, "wash.yaml")
+	require.NoError(t, ioutil.WriteFile(configFile, []byte("token: abc\n"), 0600))
+	logFile := filepath.Join(dir, "wash.log")
+	require.NoError(t, ioutil.WriteFile(logFile, []byte("a log\n"), 0600))
+	journalDir := filepath.Join(dir, "activity")
+	require.NoError(t, os.Mkdir(journalDir, 0750))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(journalDir, "1.log"), []byte("a journal\n"), 0600))
+
+	bundle := diagnosticBundle{
+		diagnostics: apitypes.Diagnostics{Plugins: []string{"docker"}, Goroutines: "goroutine 1"},
+		configFile:  configFile,
+		logFile:     logFile,
+		journalDir:  journalDir,
+	}
+	var buf bytes.Buffer
+	require.NoError(t, bundle.write(&buf))
+	files := readBundle(t, &buf)
+	assert.Contains(t, files, "version.txt")
+	assert.Equal(t, "goroutine 1", files["goroutines.txt"])
+	assert.Contains(t, files["diagnostics.json"], `"docker"`)
+	assert.NotContains(t, files["diagnostics.json"], "goroutine 1")
+	assert.Equal(t, "token: REDACTED\n", files["wash.yaml"])
+	assert.Equal(t, "a log\n", files["wash.log"])
+	assert.Equal(t, "a journal\n", files["journals/1.log"])
+
+	// The bundle's still written if the daemon's unavailable.
+	bundle.diagErr = fmt.Errorf("connection refused")
+	buf.Reset()
+	require.NoError(t, bundle.write(&buf))
+	files = readBundle(t, &buf)
+	assert.Equal(t, "connection refused\n", files["diagnostics-error.txt"])
+	assert.NotContains(t, files, "diagnostics.json")
+	assert.Contains(t, files, "wash.yaml")
+}
+
+func readBundle(t *testing.T, r io.Reader) map[string]string {
+	gr, err := gzip.NewReader(r)
+	require.NoError(t, err)
+	tr := tar.NewReader(gr)
+	files := make(map[string]string)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return files
+		}
+		require.NoError(t, err)
+		data, err := ioutil.ReadAll(tr)
+		require.NoError(t, err)
+		files[hdr.Name] = string(data)
+	}
+}
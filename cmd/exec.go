@@ -1,31 +1,68 @@
 package cmd
 
 import (
+	"bufio"
+	"bytes"
 	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
 
+	"github.com/Benchkram/errz"
+	"github.com/puppetlabs/wash/api/client"
 	apitypes "github.com/puppetlabs/wash/api/types"
 	cmdutil "github.com/puppetlabs/wash/cmd/util"
 	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh/terminal"
 )
 
 func execCommand() *cobra.Command {
 	use, aliases := generateShellAlias("exec")
 	execCmd := &cobra.Command{
-		Use:     use + " <path> <command> [<arg>...]",
+		Use:     use + " [-t <path>]... [--targets-from <file>] [--output-dir <dir>] [--junit <file>] <path> <command> [<arg>...]",
 		Aliases: aliases,
 		Short:   "Executes the given command on the indicated target",
 		Long: `For a Wash resource (specified by <path>) that implements the ability to execute a command, run the
 specified command and arguments. The results will be forwarded from the target on stdout, stderr,
-and exit code.`,
+and exit code.
+
+To run the command on several targets, specify them with -t or --targets-from instead of <path>.
+The command runs on up to --parallel targets at once. Each target's output is printed under a
+header once the command finishes on it, or written to <dir>/<path>/{stdout,stderr,exit_code} with
+--output-dir. --junit writes a JUnit XML summary of each target's exit code and duration (and its
+output) so that fleet commands can be reported by CI systems. With several targets, exec exits
+with 1 if the command failed on any of them.
+
+Use -i to run an interactive command on a single target, e.g. a shell. Stdin is sent to the
+command as it's typed instead of being ignored. If stdin is a terminal, then the command runs
+in a TTY that's resized with the terminal. exec -it <path> <command> also works, since -t can
+name the single target.`,
 		Example: `exec docker/containers/example_1 printenv USER
-  print the USER environment variable from a Docker container instance`,
-		Args: cobra.MinimumNArgs(2),
+  print the USER environment variable from a Docker container instance
+
+exec -i docker/containers/example_1 sh
+  start an interactive shell in a Docker container
+
+exec -t docker/containers/example_1 -t docker/containers/example_2 --output-dir out uname -a
+  write each container's uname to out/docker/containers/<name>/stdout
+
+find kubernetes -kind '*container' | exec --targets-from - --junit report.xml uptime
+  run uptime in all Kubernetes containers and write a JUnit summary to report.xml`,
+		Args: cobra.MinimumNArgs(1),
 		RunE: toRunE(execMain),
 	}
 
 	// Don't interpret any flags after the first positional argument. Those should
 	// instead get interpreted by this command as normal args, not flags.
 	execCmd.Flags().SetInterspersed(false)
+	execCmd.Flags().StringArrayP("target", "t", nil, "Run the command on this target. Can be repeated")
+	execCmd.Flags().String("targets-from", "", "Run the command on the targets listed in this file, one per line (- for stdin)")
+	execCmd.Flags().String("output-dir", "", "Write each target's stdout, stderr and exit code to files in this directory")
+	execCmd.Flags().String("junit", "", "Write a JUnit XML summary of each target's result to this file")
+	execCmd.Flags().IntP("parallel", "p", 10, "Number of targets to run the command on in parallel")
+	execCmd.Flags().BoolP("interactive", "i", false, "Send stdin to the command as it's typed. If stdin is a terminal, then a TTY is allocated")
 
 	return execCmd
 }
@@ -70,14 +107,270 @@ func printPackets(pkts <-chan apitypes.ExecPacket) (int, error) {
 }
 
 func execMain(cmd *cobra.Command, args []string) exitCode {
-	var path string
-	var command string
-	var commandArgs []string
+	targets, err := execTargets(cmd)
+	if err != nil {
+		cmdutil.ErrPrintf("%v\n", err)
+		return exitCode{1}
+	}
+	outputDir, err := cmd.Flags().GetString("output-dir")
+	if err != nil {
+		panic(err.Error())
+	}
+	junit, err := cmd.Flags().GetString("junit")
+	if err != nil {
+		panic(err.Error())
+	}
+	parallel, err := cmd.Flags().GetInt("parallel")
+	if err != nil {
+		panic(err.Error())
+	}
+	interactive, err := cmd.Flags().GetBool("interactive")
+	if err != nil {
+		panic(err.Error())
+	}
+	if parallel <= 0 {
+		cmdutil.ErrPrintf("--parallel must be positive\n")
+		return exitCode{1}
+	}
+
+	if interactive {
+		return execInteractiveMain(cmd, targets, args, outputDir, junit)
+	}
+	if len(targets) == 0 {
+		if len(args) < 2 {
+			cmdutil.ErrPrintf("requires a <path> and a <command>\n")
+			return exitCode{1}
+		}
+		if outputDir == "" && junit == "" {
+			return execOnTarget(args[0], args[1], args[2:])
+		}
+		targets, args = args[:1], args[1:]
+	}
+
+	conn := cmdutil.NewClient()
+	results := execOnTargets(conn, targets, args[0], args[1:], parallel)
+	if outputDir != "" {
+		err = writeExecOutputs(outputDir, results)
+	} else {
+		printExecOutputs(results)
+	}
+	if err != nil {
+		cmdutil.ErrPrintf("%v\n", err)
+		return exitCode{1}
+	}
+	if junit != "" {
+		if err := writeJUnitSummary(junit, strings.Join(args, " "), results); err != nil {
+			cmdutil.ErrPrintf("%v\n", err)
+			return exitCode{1}
+		}
+	}
+
+	for _, result := range results {
+		if result.failed() {
+			return exitCode{1}
+		}
+	}
+	return exitCode{0}
+}
+
+// execInteractiveMain runs an interactive command on a single target, which
+// is either the first argument or the -t target. The latter means that
+// exec -it <path> <command> works like it does with Docker.
+func execInteractiveMain(cmd *cobra.Command, targets []string, args []string, outputDir string, junit string) exitCode {
+	if len(targets) > 1 {
+		cmdutil.ErrPrintf("-i only supports a single target\n")
+		return exitCode{1}
+	}
+	if outputDir != "" || junit != "" {
+		cmdutil.ErrPrintf("-i cannot be used with --output-dir or --junit\n")
+		return exitCode{1}
+	}
+	if targetsFrom, _ := cmd.Flags().GetString("targets-from"); targetsFrom != "" {
+		// Stdin's sent to the command, so it can't also list the targets.
+		cmdutil.ErrPrintf("-i cannot be used with --targets-from\n")
+		return exitCode{1}
+	}
+	if len(targets) == 0 {
+		if len(args) < 2 {
+			cmdutil.ErrPrintf("requires a <path> and a <command>\n")
+			return exitCode{1}
+		}
+		targets, args = args[:1], args[1:]
+	}
+	return execInteractively(cmdutil.NewClient(), targets[0], args[0], args[1:], os.Stdin)
+}
+
+// execInteractively runs the command on path, sending stdin to it as it's
+// read. If stdin is a terminal, then the command runs in a TTY. The terminal's
+// put in raw mode so that keys like Ctrl-C are sent to the command, and its
+// resizes are forwarded to the TTY.
+func execInteractively(conn client.Client, path string, command string, args []string, stdin *os.File) exitCode {
+	fd := int(stdin.Fd())
+	tty := terminal.IsTerminal(fd)
+	session, err := conn.ExecSession(path, command, args, apitypes.ExecOptions{Tty: tty})
+	if err != nil {
+		cmdutil.ErrPrintf("%v\n", err)
+		return exitCode{1}
+	}
+	defer func() { errz.Log(session.Close()) }()
+
+	if tty {
+		state, err := terminal.MakeRaw(fd)
+		if err != nil {
+			cmdutil.ErrPrintf("could not put the terminal in raw mode: %v\n", err)
+			return exitCode{1}
+		}
+		defer func() { errz.Log(terminal.Restore(fd, state)) }()
+
+		resize := func() {
+			cols, rows, err := terminal.GetSize(fd)
+			if err == nil {
+				err = session.Resize(uint16(rows), uint16(cols))
+			}
+			errz.Log(err)
+		}
+		resize()
+		winch := make(chan os.Signal, 1)
+		notifyResize(winch)
+		defer signal.Stop(winch)
+		go func() {
+			for range winch {
+				resize()
+			}
+		}()
+	}
+
+	go func() {
+		// The session's closed once the command finishes, so only EOF's
+		// forwarded.
+		if _, err := io.Copy(session, stdin); err == nil {
+			errz.Log(session.CloseStdin())
+		}
+	}()
+
+	code, err := printPackets(session.Packets())
+	if err != nil {
+		return exitCode{1}
+	}
+	return exitCode{code}
+}
 
-	path = args[0]
-	command = args[1]
-	commandArgs = args[2:]
+// execTargets returns the targets specified with -t and --targets-from.
+func execTargets(cmd *cobra.Command) ([]string, error) {
+	targets, err := cmd.Flags().GetStringArray("target")
+	if err != nil {
+		panic(err.Error())
+	}
+	targetsFrom, err := cmd.Flags().GetString("targets-from")
+	if err != nil {
+		panic(err.Error())
+	}
+	if targetsFrom == "" {
+		return targets, nil
+	}
+
+	var rdr io.Reader = os.Stdin
+	if targetsFrom != "-" {
+		f, err := os.Open(targetsFrom)
+		if err != nil {
+			return nil, fmt.Errorf("could not read the targets: %v", err)
+		}
+		defer f.Close()
+		rdr = f
+	}
+	scanner := bufio.NewScanner(rdr)
+	for scanner.Scan() {
+		if target := strings.TrimSpace(scanner.Text()); target != "" {
+			targets = append(targets, target)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not read the targets: %v", err)
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("%v does not list any targets", targetsFrom)
+	}
+	return targets, nil
+}
+
+// execResult is the result of running the command on one of several targets.
+type execResult struct {
+	target   string
+	stdout   bytes.Buffer
+	stderr   bytes.Buffer
+	exitCode int
+	err      error
+	duration time.Duration
+}
+
+func (r *execResult) failed() bool {
+	return r.err != nil || r.exitCode != 0
+}
+
+// execOnTargets runs the command on each target, returning the results in the
+// targets' order.
+func execOnTargets(conn client.Client, targets []string, command string, args []string, parallel int) []*execResult {
+	results := make([]*execResult, len(targets))
+	pool := cmdutil.NewPool(parallel)
+	for i, target := range targets {
+		i, target := i, target
+		pool.Submit(func() {
+			defer pool.Done()
+			results[i] = execOn(conn, target, command, args)
+		})
+	}
+	pool.Finish()
+	return results
+}
+
+func execOn(conn client.Client, target string, command string, args []string) *execResult {
+	result := &execResult{target: target}
+	start := time.Now()
+	defer func() { result.duration = time.Since(start) }()
+
+	ch, err := conn.Exec(target, command, args, apitypes.ExecOptions{})
+	if err != nil {
+		result.err = err
+		return result
+	}
+	for pkt := range ch {
+		if pkt.Err != nil {
+			if result.err == nil {
+				result.err = pkt.Err
+			}
+			continue
+		}
+		switch pkt.TypeField {
+		case apitypes.Exitcode:
+			result.exitCode = int(pkt.Data.(float64))
+		case apitypes.Stdout:
+			fmt.Fprint(&result.stdout, pkt.Data)
+		case apitypes.Stderr:
+			fmt.Fprint(&result.stderr, pkt.Data)
+		}
+	}
+	return result
+}
+
+// printExecOutputs prints each target's output under a header, like tail does
+// for several files.
+func printExecOutputs(results []*execResult) {
+	for i, result := range results {
+		if i > 0 {
+			cmdutil.Println()
+		}
+		cmdutil.Printf("==> %v <==\n", result.target)
+		cmdutil.Print(result.stdout.String())
+		fmt.Fprint(cmdutil.Stderr, result.stderr.String())
+		if result.err != nil {
+			cmdutil.ErrPrintf("%v: %v\n", result.target, result.err)
+		} else if result.exitCode != 0 {
+			cmdutil.ErrPrintf("%v: exited with %v\n", result.target, result.exitCode)
+		}
+	}
+}
 
+func execOnTarget(path string, command string, commandArgs []string) exitCode {
 	conn := cmdutil.NewClient()
 
 	ch, err := conn.Exec(path, command, commandArgs, apitypes.ExecOptions{})
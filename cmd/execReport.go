@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	cmdutil "github.com/puppetlabs/wash/cmd/util"
+)
+
+// execOutputDir returns the directory of the target's output files. The
+// target's cleaned so that its directory is always inside dir, even for
+// targets like ../foo.
+func execOutputDir(dir string, target string) string {
+	return filepath.Join(dir, strings.TrimPrefix(filepath.Clean("/"+target), "/"))
+}
+
+// writeExecOutputs writes each target's stdout, stderr and exit code to
+// <dir>/<target>/{stdout,stderr,exit_code}. If the command couldn't be run on
+// a target, then its error's written to <dir>/<target>/error instead of its
+// exit code. A line summarizing each target's result is printed.
+func writeExecOutputs(dir string, results []*execResult) error {
+	for _, result := range results {
+		targetDir := execOutputDir(dir, result.target)
+		if err := os.MkdirAll(targetDir, 0750); err != nil {
+			return fmt.Errorf("could not write %v's output: %v", result.target, err)
+		}
+		files := map[string]string{
+			"stdout": result.stdout.String(),
+			"stderr": result.stderr.String(),
+		}
+		if result.err != nil {
+			files["error"] = result.err.Error() + "\n"
+		} else {
+			files["exit_code"] = strconv.Itoa(result.exitCode) + "\n"
+		}
+		for name, content := range files {
+			if err := ioutil.WriteFile(filepath.Join(targetDir, name), []byte(content), 0640); err != nil {
+				return fmt.Errorf("could not write %v's output: %v", result.target, err)
+			}
+		}
+
+		if result.err != nil {
+			cmdutil.ErrPrintf("%v: errored after %v: %v\n", result.target, result.duration, result.err)
+		} else if result.exitCode != 0 {
+			cmdutil.ErrPrintf("%v: exited with %v after %v\n", result.target, result.exitCode, result.duration)
+		} else {
+			cmdutil.Printf("%v: exited with 0 after %v\n", result.target, result.duration)
+		}
+	}
+	return nil
+}
+
+// The JUnit XML format. Each target is a test case that fails if the command
+// exited with a non-zero exit code and errors if the command couldn't be run.
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Errors   int             `xml:"errors,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Error     *junitFailure `xml:"error,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+	SystemErr string        `xml:"system-err,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+}
+
+// writeJUnitSummary writes a JUnit XML summary of the results to path.
+func writeJUnitSummary(path string, command string, results []*execResult) error {
+	suite := junitTestSuite{
+		Name:  "wash exec " + command,
+		Tests: len(results),
+	}
+	var total float64
+	for _, result := range results {
+		testCase := junitTestCase{
+			Name:      result.target,
+			ClassName: "wash.exec",
+			Time:      formatJUnitTime(result.duration.Seconds()),
+			SystemOut: result.stdout.String(),
+			SystemErr: result.stderr.String(),
+		}
+		if result.err != nil {
+			suite.Errors++
+			testCase.Error = &junitFailure{Message: result.err.Error()}
+		} else if result.exitCode != 0 {
+			suite.Failures++
+			testCase.Failure = &junitFailure{Message: fmt.Sprintf("exited with %v", result.exitCode)}
+		}
+		total += result.duration.Seconds()
+		suite.Cases = append(suite.Cases, testCase)
+	}
+	suite.Time = formatJUnitTime(total)
+
+	content, err := xml.MarshalIndent(junitTestSuites{Suites: []junitTestSuite{suite}}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not write the JUnit summary: %v", err)
+	}
+	content = append([]byte(xml.Header), append(content, '\n')...)
+	if err := ioutil.WriteFile(path, content, 0640); err != nil {
+		return fmt.Errorf("could not write the JUnit summary: %v", err)
+	}
+	return nil
+}
+
+func formatJUnitTime(seconds float64) string {
+	return strconv.FormatFloat(seconds, 'f', 3, 64)
+}
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	apitypes "github.com/puppetlabs/wash/api/types"
+	"github.com/puppetlabs/wash/cmd/internal/cmdtest"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/suite"
+)
+
+type ExecTestSuite struct {
+	cmdtest.Suite
+	dir string
+}
+
+func (s *ExecTestSuite) SetupTest() {
+	s.Suite.SetupTest()
+	dir, err := ioutil.TempDir("", "wash-exec-test")
+	if err != nil {
+		s.FailNow(err.Error())
+	}
+	s.dir = dir
+}
+
+func (s *ExecTestSuite) TearDownTest() {
+	os.RemoveAll(s.dir)
+	s.Suite.TearDownTest()
+}
+
+func (s *ExecTestSuite) mockExec(target string, stdout string, exitCode int) {
+	ch := make(chan apitypes.ExecPacket, 2)
+	ch <- apitypes.ExecPacket{TypeField: apitypes.Stdout, Data: stdout}
+	ch <- apitypes.ExecPacket{TypeField: apitypes.Exitcode, Data: float64(exitCode)}
+	close(ch)
+	s.Client.On("Exec", target, "uname", []string{"-a"}, apitypes.ExecOptions{}).Return((<-chan apitypes.ExecPacket)(ch), nil)
+}
+
+func (s *ExecTestSuite) runExec(args ...string) int {
+	cmd := execCommand()
+	cmd.SetArgs(args)
+	var exit int
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		exit = execMain(cmd, args).value
+		return nil
+	}
+	s.NoError(cmd.Execute())
+	return exit
+}
+
+func (s *ExecTestSuite) TestExec_MultipleTargets() {
+	s.mockExec("a", "linux a\n", 0)
+	s.mockExec("b", "linux b\n", 0)
+	s.Equal(0, s.runExec("-t", "a", "-t", "b", "uname", "-a"))
+	s.Equal("==> a <==\nlinux a\n\n==> b <==\nlinux b\n", s.Stdout())
+}
+
+func (s *ExecTestSuite) TestExec_OutputDirAndJUnit() {
+	s.mockExec("docker/containers/a", "linux a\n", 0)
+	s.mockExec("docker/containers/b", "", 2)
+	s.Client.On("Exec", "../c", "uname", []string{"-a"}, apitypes.ExecOptions{}).Return((<-chan apitypes.ExecPacket)(nil), fmt.Errorf("c is not execable"))
+
+	junit := filepath.Join(s.dir, "report.xml")
+	exit := s.runExec(
+		"-t", "docker/containers/a", "-t", "docker/containers/b", "-t", "../c",
+		"--output-dir", s.dir, "--junit", junit,
+		"uname", "-a",
+	)
+	s.Equal(1, exit)
+
+	s.assertFile("linux a\n", "docker/containers/a/stdout")
+	s.assertFile("0\n", "docker/containers/a/exit_code")
+	s.assertFile("", "docker/containers/b/stdout")
+	s.assertFile("2\n", "docker/containers/b/exit_code")
+	// ../c's output is written inside the output directory
+	s.assertFile("c is not execable\n", "c/error")
+	s.Regexp("docker/containers/a: exited with 0", s.Stdout())
+	s.Regexp("docker/containers/b: exited with 2", s.Stderr())
+	s.Regexp("../c: errored after .*: c is not execable", s.Stderr())
+
+	report, err := ioutil.ReadFile(junit)
+	if s.NoError(err) {
+		s.Regexp(`<testsuite name="wash exec uname -a" tests="3" failures="1" errors="1"`, string(report))
+		s.Regexp(`<testcase name="docker/containers/a" classname="wash.exec" time="[0-9.]+">\s*<system-out>linux a`, string(report))
+		s.Regexp(`<testcase name="docker/containers/b".*>\s*<failure message="exited with 2">`, string(report))
+		s.Regexp(`<testcase name="../c".*>\s*<error message="c is not execable">`, string(report))
+	}
+}
+
+// fakeExecSession echoes stdin to stdout, and exits once stdin's closed.
+type fakeExecSession struct {
+	packets chan apitypes.ExecPacket
+}
+
+func (f *fakeExecSession) Packets() <-chan apitypes.ExecPacket {
+	return f.packets
+}
+
+func (f *fakeExecSession) Write(p []byte) (int, error) {
+	f.packets <- apitypes.ExecPacket{TypeField: apitypes.Stdout, Data: string(p)}
+	return len(p), nil
+}
+
+func (f *fakeExecSession) CloseStdin() error {
+	f.packets <- apitypes.ExecPacket{TypeField: apitypes.Exitcode, Data: float64(3)}
+	close(f.packets)
+	return nil
+}
+
+func (f *fakeExecSession) Resize(uint16, uint16) error {
+	return nil
+}
+
+func (f *fakeExecSession) Close() error {
+	return nil
+}
+
+func (s *ExecTestSuite) TestExec_Interactive() {
+	stdin := filepath.Join(s.dir, "stdin")
+	if err := ioutil.WriteFile(stdin, []byte("hello\n"), 0644); err != nil {
+		s.FailNow(err.Error())
+	}
+	f, err := os.Open(stdin)
+	if err != nil {
+		s.FailNow(err.Error())
+	}
+	defer f.Close()
+
+	session := &fakeExecSession{packets: make(chan apitypes.ExecPacket, 2)}
+	s.Client.On("ExecSession", "a", "cat", []string{}, apitypes.ExecOptions{}).Return(session, nil)
+	s.Equal(3, execInteractively(s.Client, "a", "cat", []string{}, f).value)
+	s.Equal("hello\n", s.Stdout())
+}
+
+func (s *ExecTestSuite) TestExec_InteractiveRequiresOneTarget() {
+	s.Equal(1, s.runExec("-i", "-t", "a", "-t", "b", "sh"))
+	s.Regexp("-i only supports a single target", s.Stderr())
+}
+
+func (s *ExecTestSuite) TestExec_InteractiveWithTargetFlag() {
+	s.Equal(1, s.runExec("-it", "a", "-t", "b", "sh"))
+	s.Regexp("-i only supports a single target", s.Stderr())
+
+	s.Equal(1, s.runExec("-it", "a", "--junit", "report.xml", "sh"))
+	s.Regexp("-i cannot be used with --output-dir or --junit", s.Stderr())
+}
+
+func (s *ExecTestSuite) assertFile(expected string, path string) {
+	content, err := ioutil.ReadFile(filepath.Join(s.dir, path))
+	if s.NoError(err) {
+		s.Equal(expected, string(content))
+	}
+}
+
+func TestExec(t *testing.T) {
+	suite.Run(t, new(ExecTestSuite))
+}
@@ -1,8 +1,6 @@
 package cmd
 
 import (
-	"sync"
-
 	"github.com/emirpasic/gods/maps/linkedhashmap"
 	cmdutil "github.com/puppetlabs/wash/cmd/util"
 	"github.com/spf13/cobra"
@@ -43,34 +41,28 @@ func infoMain(cmd *cobra.Command, args []string) exitCode {
 	// displayed.
 	infoMap := infoResultMap{}
 
-	// Fetch the data
+	// Fetch the data in a single batch
+	results, err := conn.Batch(paths, false)
+	if err != nil {
+		cmdutil.ErrPrintf("%v\n", err)
+		return exitCode{1}
+	}
 	ec := 0
-	var infoMapMux sync.Mutex
-	var wg sync.WaitGroup
-	for _, path := range paths {
-		wg.Add(1)
-		go func(path string) {
-			defer wg.Done()
-
-			entry, err := conn.Info(path)
-			if err != nil {
-				ec = 1
-				cmdutil.SafeErrPrintf("%v: %v\n", path, err)
-				return
-			}
-
-			entryMap := orderedMap{linkedhashmap.New()}
-			entryMap.Put("Name", entry.Name)
-			entryMap.Put("CName", entry.CName)
-			entryMap.Put("Actions", entry.Actions)
-			entryMap.Put("Attributes", entry.Attributes.ToMap())
-
-			infoMapMux.Lock()
-			infoMap[path] = entryMap
-			infoMapMux.Unlock()
-		}(path)
+	for _, result := range results {
+		if result.Error != nil {
+			ec = 1
+			cmdutil.ErrPrintf("%v: %v\n", result.Path, result.Error)
+			continue
+		}
+		entry := result.Entry
+
+		entryMap := orderedMap{linkedhashmap.New()}
+		entryMap.Put("Name", entry.Name)
+		entryMap.Put("CName", entry.CName)
+		entryMap.Put("Actions", entry.Actions)
+		entryMap.Put("Attributes", entry.Attributes.ToMap())
+		infoMap[result.Path] = entryMap
 	}
-	wg.Wait()
 
 	// Marshal the results
 	var result interface{} = infoMap
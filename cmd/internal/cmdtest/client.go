@@ -2,10 +2,13 @@ package cmdtest
 
 import (
 	"io"
+	"os"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 
 	"github.com/puppetlabs/wash/analytics"
+	"github.com/puppetlabs/wash/api/client"
 	apitypes "github.com/puppetlabs/wash/api/types"
 )
 
@@ -26,15 +29,57 @@ func (c *MockClient) List(path string) ([]apitypes.Entry, error) {
 	return args.Get(0).([]apitypes.Entry), args.Error(1)
 }
 
+// ListPage mocks Client#ListPage
+func (c *MockClient) ListPage(path string, limit int, cursor string) ([]apitypes.Entry, string, error) {
+	args := c.Called(path, limit, cursor)
+	return args.Get(0).([]apitypes.Entry), args.String(1), args.Error(2)
+}
+
+// ListStream mocks Client#ListStream
+func (c *MockClient) ListStream(path string, fn func(apitypes.Entry) error) error {
+	args := c.Called(path, fn)
+	return args.Error(0)
+}
+
 // Metadata mocks Client#Metadata
 func (c *MockClient) Metadata(path string) (map[string]interface{}, error) {
 	args := c.Called(path)
 	return args.Get(0).(map[string]interface{}), args.Error(1)
 }
 
-// Stream mocks Client#Stream
-func (c *MockClient) Stream(path string) (io.ReadCloser, error) {
+// Batch mocks Client#Batch
+func (c *MockClient) Batch(paths []string, metadata bool) ([]apitypes.BatchResult, error) {
+	args := c.Called(paths, metadata)
+	return args.Get(0).([]apitypes.BatchResult), args.Error(1)
+}
+
+// Read mocks Client#Read
+func (c *MockClient) Read(path string, size int64, offset int64) ([]byte, error) {
+	args := c.Called(path, size, offset)
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+// Mountpoint mocks Client#Mountpoint
+func (c *MockClient) Mountpoint() (string, error) {
+	args := c.Called()
+	return args.String(0), args.Error(1)
+}
+
+// ExecSession mocks Client#ExecSession
+func (c *MockClient) ExecSession(path string, command string, args []string, opts apitypes.ExecOptions) (client.ExecSession, error) {
+	margs := c.Called(path, command, args, opts)
+	return margs.Get(0).(client.ExecSession), margs.Error(1)
+}
+
+// Watch mocks Client#Watch
+func (c *MockClient) Watch(path string) (<-chan apitypes.EntryEvent, io.Closer, error) {
 	args := c.Called(path)
+	return args.Get(0).(<-chan apitypes.EntryEvent), args.Get(1).(io.Closer), args.Error(2)
+}
+
+// Stream mocks Client#Stream
+func (c *MockClient) Stream(path string, since time.Duration) (io.ReadCloser, error) {
+	args := c.Called(path, since)
 	return args.Get(0).(io.ReadCloser), args.Error(1)
 }
 
@@ -85,3 +130,99 @@ func (c *MockClient) Signal(path string, signal string) error {
 	args := c.Called(path, signal)
 	return args.Error(0)
 }
+
+// Wait mocks Client#Wait
+func (c *MockClient) Wait(path string, query interface{}, opts apitypes.WaitOptions) (apitypes.WaitResult, error) {
+	args := c.Called(path, query, opts)
+	return args.Get(0).(apitypes.WaitResult), args.Error(1)
+}
+
+// Rename mocks Client#Rename
+func (c *MockClient) Rename(path string, dest string) error {
+	args := c.Called(path, dest)
+	return args.Error(0)
+}
+
+// Copy mocks Client#Copy
+func (c *MockClient) Copy(src string, dest string) (int64, error) {
+	args := c.Called(src, dest)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// Write mocks Client#Write
+func (c *MockClient) Write(path string, content io.Reader) error {
+	args := c.Called(path, content)
+	return args.Error(0)
+}
+
+// Create mocks Client#Create
+func (c *MockClient) Create(path string, name string, dir bool, mode os.FileMode) (apitypes.Entry, error) {
+	args := c.Called(path, name, dir, mode)
+	return args.Get(0).(apitypes.Entry), args.Error(1)
+}
+
+// JournalLevels mocks Client#JournalLevels
+func (c *MockClient) JournalLevels() (map[string]string, error) {
+	args := c.Called()
+	return args.Get(0).(map[string]string), args.Error(1)
+}
+
+// SetJournalLevel mocks Client#SetJournalLevel
+func (c *MockClient) SetJournalLevel(plugin string, level string) error {
+	args := c.Called(plugin, level)
+	return args.Error(0)
+}
+
+// Diagnostics mocks Client#Diagnostics
+func (c *MockClient) Diagnostics() (apitypes.Diagnostics, error) {
+	args := c.Called()
+	return args.Get(0).(apitypes.Diagnostics), args.Error(1)
+}
+
+// Stats mocks Client#Stats
+func (c *MockClient) Stats() (apitypes.Stats, error) {
+	args := c.Called()
+	return args.Get(0).(apitypes.Stats), args.Error(1)
+}
+
+// Trash mocks Client#Trash
+func (c *MockClient) Trash() ([]apitypes.TrashedEntry, error) {
+	args := c.Called()
+	return args.Get(0).([]apitypes.TrashedEntry), args.Error(1)
+}
+
+// RestoreFromTrash mocks Client#RestoreFromTrash
+func (c *MockClient) RestoreFromTrash(id string) error {
+	args := c.Called(id)
+	return args.Error(0)
+}
+
+// PurgeFromTrash mocks Client#PurgeFromTrash
+func (c *MockClient) PurgeFromTrash(id string) (bool, error) {
+	args := c.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+
+// InstallPlugin mocks Client#InstallPlugin
+func (c *MockClient) InstallPlugin(body apitypes.PluginInstallBody) error {
+	args := c.Called(body)
+	return args.Error(0)
+}
+
+// EnablePlugin mocks Client#EnablePlugin
+func (c *MockClient) EnablePlugin(name string) error {
+	args := c.Called(name)
+	return args.Error(0)
+}
+
+// DisablePlugin mocks Client#DisablePlugin
+func (c *MockClient) DisablePlugin(name string) error {
+	args := c.Called(name)
+	return args.Error(0)
+}
+
+// ReloadPlugin mocks Client#ReloadPlugin
+func (c *MockClient) ReloadPlugin(name string) error {
+	args := c.Called(name)
+	return args.Error(0)
+}
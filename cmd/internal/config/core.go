@@ -17,6 +17,7 @@ import (
 const (
 	SocketKey   = "socket"
 	EmbeddedKey = "embedded"
+	RemoteKey   = "remote"
 )
 
 // Socket is the path to the Wash server's UNIX
@@ -24,6 +25,11 @@ const (
 var Socket string
 var Embedded bool
 
+// Remote is the host:port of the remote daemon that the
+// wash subcommands are thin clients of. It's empty if
+// they're clients of the local daemon.
+var Remote string
+
 // Init initializes the config package. It loads Wash's defaults and
 // sets up viper
 func Init() error {
@@ -51,6 +57,7 @@ func Init() error {
 	// Load the shared config
 	Socket = viper.GetString(SocketKey)
 	Embedded = viper.GetBool(EmbeddedKey)
+	Remote = viper.GetString(RemoteKey)
 
 	return nil
 }
@@ -73,6 +80,12 @@ func DefaultFileAbsPath() string {
 	return defaultFileAbs
 }
 
+// DefaultTokensFile returns the default path of the file of the API's bearer
+// tokens, which is next to the default config file.
+func DefaultTokensFile() string {
+	return filepath.Join(filepath.Dir(DefaultFileAbsPath()), "tokens.json")
+}
+
 // ReadFrom reads the config from the specified file.
 // If file == DefaultFile(), then ReadFrom wil not return
 // an error if file does not exist.
@@ -0,0 +1,81 @@
+package find
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/puppetlabs/wash/api/rql"
+	"github.com/puppetlabs/wash/cmd/internal/find/types"
+	cmdutil "github.com/puppetlabs/wash/cmd/util"
+)
+
+// parseAggregation parses the -aggregate and -groupby options. The aggregation
+// is formatted as <function>[:<field>], e.g. "count" or "sum:size".
+func parseAggregation(opts types.Options) (rql.Aggregation, error) {
+	if opts.Aggregate == "" {
+		return rql.Aggregation{}, fmt.Errorf("-%v requires -%v", types.GroupbyFlag, types.AggregateFlag)
+	}
+	if opts.Checkpoint != "" {
+		return rql.Aggregation{}, fmt.Errorf("-%v can't be combined with -%v", types.AggregateFlag, types.CheckpointFlag)
+	}
+	segments := strings.SplitN(opts.Aggregate, ":", 2)
+	function, field := segments[0], ""
+	if len(segments) > 1 {
+		field = segments[1]
+	}
+	a, err := rql.NewAggregation(function, field, opts.Groupby)
+	if err != nil {
+		return rql.Aggregation{}, fmt.Errorf("-%v %v: %v", types.AggregateFlag, opts.Aggregate, err)
+	}
+	return a, nil
+}
+
+// printAggregation prints the aggregation of the entries. Ungrouped
+// aggregations print only their value so that they're easy to use in
+// scripts. Grouped aggregations print a table of each group's value.
+func printAggregation(entries []types.Entry, a rql.Aggregation) {
+	rqlEntries := make([]rql.Entry, len(entries))
+	for i, e := range entries {
+		rqlEntries[i] = rql.Entry{Entry: e.Entry}
+		rqlEntries[i].Path = e.NormalizedPath
+	}
+	groups := rql.Aggregate(rqlEntries, a)
+	if a.GroupBy == "" {
+		cmdutil.Println(formatAggregateValue(groups[0].Value))
+		return
+	}
+
+	rows := make([][]string, len(groups))
+	for i, g := range groups {
+		rows[i] = []string{formatAggregateValue(g.Key), formatAggregateValue(g.Value)}
+	}
+	headers := []cmdutil.ColumnHeader{
+		{ShortName: "group", FullName: "GROUP"},
+		{ShortName: a.Function, FullName: strings.ToUpper(a.Function)},
+	}
+	cmdutil.Print(cmdutil.NewTableWithHeaders(headers, rows).Format())
+}
+
+func formatAggregateValue(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return "-"
+	case string:
+		return t
+	case int:
+		return strconv.Itoa(t)
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case time.Time:
+		return t.Format(time.RFC3339)
+	default:
+		b, err := json.Marshal(t)
+		if err != nil {
+			return fmt.Sprintf("%v", t)
+		}
+		return string(b)
+	}
+}
@@ -0,0 +1,183 @@
+package find
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+
+	cmdutil "github.com/puppetlabs/wash/cmd/util"
+)
+
+// checkpoint records a walk's progress in a file so that an interrupted walk
+// can resume where it left off. The file's a list of JSON records, one per
+// line, so that it's valid up to the last complete record when find's
+// interrupted. Its first record is find's arguments, so that a checkpoint
+// isn't resumed by a different walk.
+//
+// A nil checkpoint records nothing.
+type checkpoint struct {
+	path    string
+	file    *os.File
+	visited map[string]bool
+	done    map[string]bool
+	results map[string]bool
+	// resultsList is the results in the order that they were found.
+	resultsList []string
+}
+
+type checkpointRecord struct {
+	Type string   `json:"type"`
+	Path string   `json:"path,omitempty"`
+	Args []string `json:"args,omitempty"`
+}
+
+const (
+	argsRecord = "args"
+	// visitedRecord means that the entry at path was visited.
+	visitedRecord = "visited"
+	// resultRecord means that the entry at path satisfied the expression.
+	// Its path is the printed path.
+	resultRecord = "result"
+	// doneRecord means that the entry at path and all its descendants were
+	// successfully visited.
+	doneRecord = "done"
+)
+
+// openCheckpoint opens the checkpoint file at path. If the file exists, then
+// it loads the progress of the walk that created it. Otherwise it creates the
+// file.
+func openCheckpoint(path string, args []string) (*checkpoint, error) {
+	c := &checkpoint{
+		path:    path,
+		visited: make(map[string]bool),
+		done:    make(map[string]bool),
+		results: make(map[string]bool),
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("could not read the checkpoint file %v: %v", path, err)
+	}
+	// validLen is the length of the file's complete records. An interrupted
+	// write leaves an incomplete record at the end, which is truncated.
+	validLen := 0
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if validLen+len(line) >= len(content) {
+			// The last line wasn't terminated, so it's incomplete.
+			break
+		}
+		var record checkpointRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			return nil, fmt.Errorf("the checkpoint file %v is corrupt: %v", path, err)
+		}
+		if validLen == 0 {
+			if record.Type != argsRecord || !reflect.DeepEqual(record.Args, args) {
+				return nil, fmt.Errorf("the checkpoint file %v was created by a different find command: %v", path, record.Args)
+			}
+		}
+		c.load(record)
+		validLen += len(line) + 1
+	}
+
+	c.file, err = os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("could not open the checkpoint file %v: %v", path, err)
+	}
+	if err := c.file.Truncate(int64(validLen)); err != nil {
+		_ = c.file.Close()
+		return nil, fmt.Errorf("could not open the checkpoint file %v: %v", path, err)
+	}
+	if _, err := c.file.Seek(int64(validLen), 0); err != nil {
+		_ = c.file.Close()
+		return nil, fmt.Errorf("could not open the checkpoint file %v: %v", path, err)
+	}
+	if validLen == 0 {
+		c.record(checkpointRecord{Type: argsRecord, Args: args})
+	}
+	return c, nil
+}
+
+func (c *checkpoint) load(record checkpointRecord) {
+	switch record.Type {
+	case visitedRecord:
+		c.visited[record.Path] = true
+	case doneRecord:
+		c.done[record.Path] = true
+	case resultRecord:
+		if !c.results[record.Path] {
+			c.results[record.Path] = true
+			c.resultsList = append(c.resultsList, record.Path)
+		}
+	}
+}
+
+func (c *checkpoint) record(record checkpointRecord) {
+	if c == nil || c.file == nil {
+		return
+	}
+	line, err := json.Marshal(record)
+	if err == nil {
+		_, err = c.file.Write(append(line, '\n'))
+	}
+	if err != nil {
+		// Keep walking, but stop checkpointing so that the file's not left
+		// with gaps.
+		cmdutil.ErrPrintf("could not write to the checkpoint file %v, so it will not be updated: %v\n", c.path, err)
+		_ = c.file.Close()
+		c.file = nil
+		return
+	}
+	c.load(record)
+}
+
+// isDone returns true if the entry at path and its descendants were already
+// visited.
+func (c *checkpoint) isDone(path string) bool {
+	return c != nil && c.done[path]
+}
+
+// isVisited returns true if the entry at path was already visited.
+func (c *checkpoint) isVisited(path string) bool {
+	return c != nil && (c.visited[path] || c.done[path])
+}
+
+// isResult returns true if path was already printed as a result.
+func (c *checkpoint) isResult(path string) bool {
+	return c != nil && c.results[path]
+}
+
+func (c *checkpoint) markVisited(path string) {
+	c.record(checkpointRecord{Type: visitedRecord, Path: path})
+}
+
+func (c *checkpoint) markDone(path string) {
+	c.record(checkpointRecord{Type: doneRecord, Path: path})
+}
+
+func (c *checkpoint) addResult(path string) {
+	c.record(checkpointRecord{Type: resultRecord, Path: path})
+}
+
+// close closes the checkpoint file. If remove is true, then the file's
+// removed since the walk's finished.
+func (c *checkpoint) close(remove bool) {
+	if c == nil || c.file == nil {
+		return
+	}
+	if err := c.file.Close(); err != nil {
+		cmdutil.ErrPrintf("could not close the checkpoint file %v: %v\n", c.path, err)
+	}
+	c.file = nil
+	if remove {
+		if err := os.Remove(c.path); err != nil {
+			cmdutil.ErrPrintf("could not remove the checkpoint file %v: %v\n", c.path, err)
+		}
+	}
+}
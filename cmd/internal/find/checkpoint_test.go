@@ -0,0 +1,69 @@
+package find
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenCheckpoint(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wash-find-checkpoint")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "checkpoint")
+	args := []string{"-checkpoint", path, ".", "-name", "foo"}
+
+	// A new checkpoint records find's arguments.
+	cp, err := openCheckpoint(path, args)
+	require.NoError(t, err)
+	cp.addResult("./foo")
+	cp.markVisited("/foo")
+	cp.close(false)
+
+	// An interrupted write leaves an incomplete record, which is dropped.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"type":"done","pa`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	cp, err = openCheckpoint(path, args)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"./foo"}, cp.resultsList)
+	assert.True(t, cp.isVisited("/foo"))
+	assert.False(t, cp.isDone("/foo"))
+	cp.markDone("/foo")
+	cp.close(false)
+
+	content, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, `{"type":"args","args":["-checkpoint","`+path+`",".","-name","foo"]}
+{"type":"result","path":"./foo"}
+{"type":"visited","path":"/foo"}
+{"type":"done","path":"/foo"}
+`, string(content))
+
+	// A different walk can't resume the checkpoint.
+	_, err = openCheckpoint(path, []string{"."})
+	assert.Regexp(t, "created by a different find command", err)
+
+	// Finished walks remove their checkpoint.
+	cp, err = openCheckpoint(path, args)
+	require.NoError(t, err)
+	cp.close(true)
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestCheckpoint_Nil(t *testing.T) {
+	var cp *checkpoint
+	cp.markDone("/foo")
+	assert.False(t, cp.isDone("/foo"))
+	assert.False(t, cp.isVisited("/foo"))
+	assert.False(t, cp.isResult("./foo"))
+	cp.close(true)
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/puppetlabs/wash/api/rql"
 	"github.com/puppetlabs/wash/cmd/internal/find/params"
 	"github.com/puppetlabs/wash/cmd/internal/find/parser"
 	"github.com/puppetlabs/wash/cmd/internal/find/primary"
@@ -42,15 +43,45 @@ func Main(args []string) int {
 		)
 	}
 
+	var aggregation *rql.Aggregation
+	if opts.Aggregate != "" || opts.Groupby != "" {
+		a, err := parseAggregation(*opts)
+		if err != nil {
+			cmdutil.ErrPrintf("find: %v\n", err)
+			return 1
+		}
+		aggregation = &a
+	}
+
+	var cp *checkpoint
+	if opts.Checkpoint != "" {
+		cp, err = openCheckpoint(opts.Checkpoint, args)
+		if err != nil {
+			cmdutil.ErrPrintf("find: %v\n", err)
+			return 1
+		}
+		// Print the results of the interrupted walk so that the output's
+		// complete.
+		for _, path := range cp.resultsList {
+			cmdutil.Printf("%v\n", path)
+		}
+	}
+
 	// Do the walk
 	conn := cmdutil.NewClient()
-	walker := newWalker(result, conn)
+	walker := newWalker(result, conn, cp)
 	exitCode := 0
 	for _, path := range result.Paths {
 		if !walker.Walk(path) {
 			exitCode = 1
 		}
 	}
+	// Keep the checkpoint if the walk failed so that rerunning it only
+	// retries the failed parts.
+	cp.close(exitCode == 0)
+	if aggregation != nil {
+		printAggregation(walker.Matches(), *aggregation)
+	}
 	return exitCode
 }
 
@@ -1,6 +1,7 @@
 package find
 
 import (
+	"strconv"
 	"strings"
 	"testing"
 
@@ -16,7 +17,7 @@ import (
 
 type MainTestSuite struct {
 	*cmdtest.Suite
-	oldNewWalker func(r parser.Result, conn client.Client) walker
+	oldNewWalker func(r parser.Result, conn client.Client, cp *checkpoint) walker
 	walker       *mockWalker
 }
 
@@ -24,8 +25,8 @@ func (s *MainTestSuite) SetupTest() {
 	s.Suite.SetupTest()
 	s.oldNewWalker = newWalker
 	s.walker = &mockWalker{}
-	newWalker = func(r parser.Result, conn client.Client) walker {
-		s.walker.walkerImpl = s.oldNewWalker(r, conn).(*walkerImpl)
+	newWalker = func(r parser.Result, conn client.Client, cp *checkpoint) walker {
+		s.walker.walkerImpl = s.oldNewWalker(r, conn, cp).(*walkerImpl)
 		return s.walker
 	}
 }
@@ -85,6 +86,43 @@ func (s *MainTestSuite) TestMain_MultiplePaths_UnsuccessfulWalk() {
 	s.walker.AssertCalled(s.T(), "Walk", "bar")
 }
 
+func (s *MainTestSuite) TestMain_AggregateErrors() {
+	s.Equal(1, Main([]string{"-groupby", "meta.Bucket"}))
+	s.Regexp("find: -groupby requires -aggregate", s.Stderr())
+}
+
+func (s *MainTestSuite) TestMain_AggregateInvalid() {
+	s.Equal(1, Main([]string{"-aggregate", "avg:size"}))
+	s.Regexp("find: -aggregate avg:size:.*not a valid aggregation function", s.Stderr())
+}
+
+func (s *MainTestSuite) TestMain_AggregateWithCheckpoint() {
+	s.Equal(1, Main([]string{"-aggregate", "count", "-checkpoint", "foo"}))
+	s.Regexp("find: -aggregate can't be combined with -checkpoint", s.Stderr())
+}
+
+func (s *MainTestSuite) TestMain_Aggregate() {
+	s.walker.On("Walk", ".").Return(true).Run(func(mock.Arguments) {
+		for i, bucket := range []string{"logs", "backups", "logs"} {
+			e := types.Entry{NormalizedPath: bucket + strconv.Itoa(i)}
+			e.Attributes.SetSize(uint64(10 * (i + 1)))
+			e.Metadata = map[string]interface{}{"Bucket": bucket}
+			s.walker.matches = append(s.walker.matches, e)
+		}
+	})
+	s.Equal(0, Main([]string{"-aggregate", "sum:size", "-groupby", "meta.Bucket"}))
+	s.Regexp(`(?m)^backups\s+20$`, s.Stdout())
+	s.Regexp(`(?m)^logs\s+40$`, s.Stdout())
+}
+
+func (s *MainTestSuite) TestMain_AggregateUngrouped() {
+	s.walker.On("Walk", ".").Return(true).Run(func(mock.Arguments) {
+		s.walker.matches = append(s.walker.matches, types.Entry{}, types.Entry{})
+	})
+	s.Equal(0, Main([]string{"-aggregate", "count"}))
+	s.Equal("2\n", s.Stdout())
+}
+
 func (s *MainTestSuite) TestPrintHelp_NoValue() {
 	helpOpt := types.HelpOption{
 		HasValue: false,
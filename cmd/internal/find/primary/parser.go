@@ -2,6 +2,7 @@ package primary
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/puppetlabs/wash/cmd/internal/find/parser/errz"
@@ -44,6 +45,17 @@ func Table() *cmdutil.Table {
 	return cmdutil.NewTable(rows...)
 }
 
+// Tokens returns the tokens of all of `wash find`'s available primaries,
+// e.g. "-name" and "-k"
+func Tokens() []string {
+	tokens := make([]string, 0, len(Parser.primaryMap))
+	for token := range Parser.primaryMap {
+		tokens = append(tokens, token)
+	}
+	sort.Strings(tokens)
+	return tokens
+}
+
 // Parser parses `wash find` primaries.
 var Parser = &parser{
 	primaryMap:   make(map[string]*Primary),
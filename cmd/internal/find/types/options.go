@@ -14,8 +14,15 @@ type Options struct {
 	Mindepth uint
 	Daystart bool
 	Fullmeta bool
-	Help     HelpOption
-	setFlags map[string]struct{}
+	// Checkpoint is the path of the file that records the walk's progress.
+	Checkpoint string
+	// Aggregate is the aggregation that's printed instead of the satisfying
+	// entries, formatted as <function>[:<field>], e.g. "count" or "sum:size".
+	Aggregate string
+	// Groupby is the field that groups the aggregated entries.
+	Groupby string
+	Help       HelpOption
+	setFlags   map[string]struct{}
 }
 
 // DefaultMaxdepth is the default value of the maxdepth option.
@@ -47,6 +54,12 @@ const (
 	DaystartFlag = "daystart"
 	// FullmetaFlag is the name of the fullmeta option's flag
 	FullmetaFlag = "fullmeta"
+	// CheckpointFlag is the name of the checkpoint option's flag
+	CheckpointFlag = "checkpoint"
+	// AggregateFlag is the name of the aggregate option's flag
+	AggregateFlag = "aggregate"
+	// GroupbyFlag is the name of the groupby option's flag
+	GroupbyFlag = "groupby"
 )
 
 // IsSet returns true if the flag was set, false otherwise.
@@ -71,6 +84,9 @@ func (opts *Options) FlagSet() *flag.FlagSet {
 	fs.IntVar(&opts.Maxdepth, MaxdepthFlag, opts.Maxdepth, "")
 	fs.BoolVar(&opts.Daystart, DaystartFlag, opts.Daystart, "")
 	fs.BoolVar(&opts.Fullmeta, FullmetaFlag, opts.Fullmeta, "")
+	fs.StringVar(&opts.Checkpoint, CheckpointFlag, opts.Checkpoint, "")
+	fs.StringVar(&opts.Aggregate, AggregateFlag, opts.Aggregate, "")
+	fs.StringVar(&opts.Groupby, GroupbyFlag, opts.Groupby, "")
 	return fs
 }
 
@@ -78,15 +94,18 @@ func (opts *Options) FlagSet() *flag.FlagSet {
 // options
 func OptionsTable() *cmdutil.Table {
 	return cmdutil.NewTable(
-		[]string{"Flags:",                 ""},
-		[]string{"      -depth",           "Visit the children first before the parent (default false)"},
-		[]string{"      -mindepth depth",  "Do not print entries at levels less than depth (default 0)"},
-		[]string{"      -maxdepth depth",  "Do not print entries at levels greater than depth (default infinity)"},
-		[]string{"      -daystart",        "Set the reference time to the start of the current day (default false)"},
-		[]string{"      -fullmeta",        "Use the entry's full metadata in meta primary predicates (default false)"},
-		[]string{"  -h, -help",            "Print this usage"},
-		[]string{"  -h, -help <primary>",  "Print a detailed description of the specified primary (e.g. \"-help meta\")"},
-		[]string{"  -h, -help syntax",     "Print a detailed description of find's expression syntax"},
+		[]string{"Flags:",                     ""},
+		[]string{"      -depth",               "Visit the children first before the parent (default false)"},
+		[]string{"      -mindepth depth",      "Do not print entries at levels less than depth (default 0)"},
+		[]string{"      -maxdepth depth",      "Do not print entries at levels greater than depth (default infinity)"},
+		[]string{"      -daystart",            "Set the reference time to the start of the current day (default false)"},
+		[]string{"      -fullmeta",            "Use the entry's full metadata in meta primary predicates (default false)"},
+		[]string{"      -checkpoint file",     "Record the walk's progress in file, and resume the walk if file exists"},
+		[]string{"      -aggregate f[:field]", "Print the f (count, sum, min or max) of the entries' field instead of the entries"},
+		[]string{"      -groupby field",       "Print -aggregate's result for each value of field, e.g. meta.Bucket"},
+		[]string{"  -h, -help",                "Print this usage"},
+		[]string{"  -h, -help <primary>",      "Print a detailed description of the specified primary (e.g. \"-help meta\")"},
+		[]string{"  -h, -help syntax",         "Print a detailed description of find's expression syntax"},
 	)
 }
 
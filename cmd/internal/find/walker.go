@@ -1,6 +1,8 @@
 package find
 
 import (
+	"sort"
+
 	"github.com/puppetlabs/wash/api/client"
 	"github.com/puppetlabs/wash/cmd/internal/find/parser"
 	"github.com/puppetlabs/wash/cmd/internal/find/primary"
@@ -13,20 +15,27 @@ type walker interface {
 	// Returns true if the walk is successful (i.e. does not
 	// have any errors), false otherwise.
 	Walk(path string) bool
+	// Returns the satisfying entries if the walk's aggregating them instead
+	// of printing them.
+	Matches() []types.Entry
 }
 
 type walkerImpl struct {
-	p    types.EntryPredicate
-	opts types.Options
-	conn client.Client
+	p          types.EntryPredicate
+	opts       types.Options
+	conn       client.Client
+	checkpoint *checkpoint
+	matches    []types.Entry
 }
 
-// Make this a variable so that other tests can mock it
-var newWalker = func(r parser.Result, conn client.Client) walker {
+// Make this a variable so that other tests can mock it. The checkpoint can be
+// nil.
+var newWalker = func(r parser.Result, conn client.Client, cp *checkpoint) walker {
 	return &walkerImpl{
-		p:    r.Predicate,
-		opts: r.Options,
-		conn: conn,
+		p:          r.Predicate,
+		opts:       r.Options,
+		conn:       conn,
+		checkpoint: cp,
 	}
 }
 
@@ -53,16 +62,36 @@ func (w *walkerImpl) Walk(path string) bool {
 	return w.walk(e, 0)
 }
 
-func (w *walkerImpl) walk(e types.Entry, depth uint) bool {
+func (w *walkerImpl) walk(e types.Entry, depth uint) (successful bool) {
+	if w.checkpoint.isDone(e.Path) {
+		// An earlier walk visited e and its descendants.
+		return true
+	}
+	defer func() {
+		if successful {
+			w.checkpoint.markDone(e.Path)
+		}
+	}()
+
 	// If the Depth option is set, then we visit e after visiting its children.
 	// Otherwise, we visit e first.
-	successful := true
+	successful = true
 	check := func(result bool) {
 		// Use "&&" to short-circuit if successful is false
 		successful = successful && result
 	}
+	visit := func() {
+		if w.checkpoint.isVisited(e.Path) {
+			return
+		}
+		visited := w.visit(e, depth)
+		if visited {
+			w.checkpoint.markVisited(e.Path)
+		}
+		check(visited)
+	}
 	if !w.opts.Depth {
-		check(w.visit(e, depth))
+		visit()
 	}
 	childDepth := depth + 1
 	if int(childDepth) <= w.opts.Maxdepth && e.Supports(plugin.ListAction()) {
@@ -77,17 +106,24 @@ func (w *walkerImpl) walk(e types.Entry, depth uint) bool {
 			cmdutil.ErrPrintf("could not get children of %v: %v\n", e.NormalizedPath, err)
 			successful = false
 		} else {
-			for _, child := range children {
-				if e.SchemaKnown {
+			if e.SchemaKnown {
+				for i := range children {
 					// Note that e.Schema != nil here
-					child.SetSchema(e.Schema.GetChild(child.TypeID))
+					children[i].SetSchema(e.Schema.GetChild(children[i].TypeID))
 				}
+			}
+			// Walk the children that are expensive to list last so that the
+			// cheap subtrees' results are printed sooner.
+			sort.SliceStable(children, func(i, j int) bool {
+				return !isExpensiveToList(children[i]) && isExpensiveToList(children[j])
+			})
+			for _, child := range children {
 				check(w.walk(child, childDepth))
 			}
 		}
 	}
 	if w.opts.Depth {
-		check(w.visit(e, depth))
+		visit()
 	}
 	return successful
 }
@@ -123,8 +159,25 @@ func (w *walkerImpl) visit(e types.Entry, depth uint) bool {
 			e.Metadata = meta
 		}
 	}
-	if w.p.P(e) {
+	if w.opts.Aggregate != "" {
+		if w.p.P(e) {
+			w.matches = append(w.matches, e)
+		}
+		return true
+	}
+	if w.p.P(e) && !w.checkpoint.isResult(e.NormalizedPath) {
 		cmdutil.Printf("%v\n", e.NormalizedPath)
+		w.checkpoint.addResult(e.NormalizedPath)
 	}
 	return true
 }
+
+func (w *walkerImpl) Matches() []types.Entry {
+	return w.matches
+}
+
+// isExpensiveToList returns true if e's schema says that its children are
+// expensive to list.
+func isExpensiveToList(e types.Entry) bool {
+	return e.Schema != nil && e.Schema.ListCost().IsExpensive()
+}
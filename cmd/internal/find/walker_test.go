@@ -2,6 +2,8 @@ package find
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -32,6 +34,7 @@ func (s *WalkerTestSuite) SetupTest() {
 			}),
 		},
 		s.Suite.Client,
+		nil,
 	).(*walkerImpl)
 }
 
@@ -127,6 +130,42 @@ func (s *WalkerTestSuite) TestWalk_WithSchema_HappyCase() {
 	)
 }
 
+func (s *WalkerTestSuite) TestWalk_ExpensiveSubtreesLast() {
+	schema := (&apitypes.EntrySchema{}).SetPath(".").SetTypeID("root").SetChildren([]*apitypes.EntrySchema{
+		(&apitypes.EntrySchema{}).SetPath("./slow").SetTypeID("slow").SetListCost(plugin.ExpensiveList).SetChildren([]*apitypes.EntrySchema{
+			(&apitypes.EntrySchema{}).SetPath("./slow/file").SetTypeID("file"),
+		}),
+		(&apitypes.EntrySchema{}).SetPath("./fast").SetTypeID("fast").SetListCost(plugin.CheapList).SetChildren([]*apitypes.EntrySchema{
+			(&apitypes.EntrySchema{}).SetPath("./fast/file").SetTypeID("file"),
+		}),
+	})
+	s.setupMocksForWalk(schema, map[string][]apitypes.Entry{
+		".": []apitypes.Entry{
+			s.toEntry("./a", true, "slow"),
+			s.toEntry("./b", true, "fast"),
+			s.toEntry("./c", true, "slow"),
+			s.toEntry("./d", true, "fast"),
+		},
+		"./a": []apitypes.Entry{s.toEntry("./a/1", false, "file")},
+		"./b": []apitypes.Entry{s.toEntry("./b/1", false, "file")},
+		"./c": []apitypes.Entry{s.toEntry("./c/1", false, "file")},
+		"./d": []apitypes.Entry{s.toEntry("./d/1", false, "file")},
+	})
+
+	s.True(s.walker.Walk("."))
+	s.assertPrintedTree(
+		".",
+		"./b",
+		"./b/1",
+		"./d",
+		"./d/1",
+		"./a",
+		"./a/1",
+		"./c",
+		"./c/1",
+	)
+}
+
 func (s *WalkerTestSuite) TestWalk_MaxdepthSet() {
 	s.setupDefaultMocksForWalk()
 	s.walker.opts.Maxdepth = 2
@@ -166,6 +205,41 @@ func (s *WalkerTestSuite) TestWalk_DepthSet() {
 	)
 }
 
+func (s *WalkerTestSuite) TestWalk_Checkpoint() {
+	dir, err := ioutil.TempDir("", "wash-find-checkpoint")
+	if !s.NoError(err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "checkpoint")
+
+	// The interrupted walk visited the root and all of ./foo/bar.
+	s.NoError(ioutil.WriteFile(path, []byte(`{"type":"args","args":["."]}
+{"type":"result","path":"."}
+{"type":"visited","path":"/"}
+{"type":"done","path":"/foo/bar"}
+`), 0600))
+	s.walker.checkpoint, err = openCheckpoint(path, []string{"."})
+	if !s.NoError(err) {
+		return
+	}
+	s.setupMocksForWalk(nil, map[string][]apitypes.Entry{
+		".": []apitypes.Entry{s.toEntry("./foo", true, "")},
+		"./foo": []apitypes.Entry{
+			s.toEntry("./foo/bar", true, ""),
+			s.toEntry("./foo/baz", false, ""),
+		},
+	})
+
+	s.True(s.walker.Walk("."))
+	s.assertPrintedTree(
+		"./foo",
+		"./foo/baz",
+	)
+	s.True(s.walker.checkpoint.isDone("/"))
+	s.True(s.walker.checkpoint.isResult("./foo/baz"))
+}
+
 func (s *WalkerTestSuite) TestWalk_ListErrors() {
 	s.setupDefaultMocksForWalk()
 	err := fmt.Errorf("failed to list")
@@ -204,6 +278,14 @@ func (s *WalkerTestSuite) TestVisit_MindepthSet() {
 	s.assertNotPrintedEntry(e)
 }
 
+func (s *WalkerTestSuite) TestVisit_AggregateSet_CollectsEntry() {
+	s.walker.opts.Aggregate = "count"
+	e := newMockEntryForVisit()
+	s.True(s.walker.visit(e, 0))
+	s.assertNotPrintedEntry(e)
+	s.Equal([]types.Entry{e}, s.walker.Matches())
+}
+
 func (s *WalkerTestSuite) TestVisit_NilSchema_DoesNotVisit() {
 	e := newMockEntryForVisit()
 	e.SetSchema(nil)
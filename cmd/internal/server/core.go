@@ -11,33 +11,87 @@ import (
 
 	"github.com/Benchkram/errz"
 	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/activity/sinks"
 	"github.com/puppetlabs/wash/analytics"
 	"github.com/puppetlabs/wash/api"
+	"github.com/puppetlabs/wash/api/auth"
 	"github.com/puppetlabs/wash/fuse"
+	"github.com/puppetlabs/wash/nfs"
+	"github.com/puppetlabs/wash/ninep"
 	"github.com/puppetlabs/wash/plugin"
 	"github.com/puppetlabs/wash/plugin/aws"
+	"github.com/puppetlabs/wash/plugin/azure"
+	"github.com/puppetlabs/wash/plugin/bigquery"
+	"github.com/puppetlabs/wash/plugin/certificates"
+	"github.com/puppetlabs/wash/plugin/consul"
 	"github.com/puppetlabs/wash/plugin/docker"
+	"github.com/puppetlabs/wash/plugin/etcd"
 	"github.com/puppetlabs/wash/plugin/gcp"
+	"github.com/puppetlabs/wash/plugin/github"
+	"github.com/puppetlabs/wash/plugin/hosts"
 	"github.com/puppetlabs/wash/plugin/kubernetes"
+	"github.com/puppetlabs/wash/plugin/libvirt"
+	"github.com/puppetlabs/wash/plugin/nomad"
+	"github.com/puppetlabs/wash/plugin/openstack"
+	"github.com/puppetlabs/wash/plugin/prometheus"
+	"github.com/puppetlabs/wash/plugin/proxmox"
+	"github.com/puppetlabs/wash/plugin/remote"
+	"github.com/puppetlabs/wash/plugin/systemd"
+	"github.com/puppetlabs/wash/plugin/vault"
+	"github.com/puppetlabs/wash/plugin/vsphere"
 
 	log "github.com/sirupsen/logrus"
 )
 
 // InternalPlugins lists the plugins enabled by default in Wash.
 var InternalPlugins = map[string]plugin.Root{
-	"aws":        &aws.Root{},
-	"docker":     &docker.Root{},
-	"gcp":        &gcp.Root{},
-	"kubernetes": &kubernetes.Root{},
+	"aws":          &aws.Root{},
+	"azure":        &azure.Root{},
+	"bigquery":     &bigquery.Root{},
+	"certificates": &certificates.Root{},
+	"consul":       &consul.Root{},
+	"docker":       &docker.Root{},
+	"etcd":         &etcd.Root{},
+	"gcp":          &gcp.Root{},
+	"github":       &github.Root{},
+	"hosts":        &hosts.Root{},
+	"kubernetes":   &kubernetes.Root{},
+	"libvirt":      &libvirt.Root{},
+	"nomad":        &nomad.Root{},
+	"openstack":    &openstack.Root{},
+	"prometheus":   &prometheus.Root{},
+	"proxmox":      &proxmox.Root{},
+	"remote":       &remote.Root{},
+	"systemd":      &systemd.Root{},
+	"vault":        &vault.Root{},
+	"vsphere":      &vsphere.Root{},
 }
 
 // Opts exposes additional configuration for server operation.
 type Opts struct {
 	CPUProfilePath string
-	LogFile        string
+	// FuseOpLogging records every FUSE operation's latency. See fuse.SetOpLogging.
+	FuseOpLogging bool
+	// Fuse configures the kernel's caching of the FUSE filesystem.
+	Fuse fuse.Options
+	// MountProtocol is FUSE or NFS. It defaults to FUSE.
+	MountProtocol string
+	LogFile       string
 	// LogLevel can be "warn", "info", "debug", or "trace".
 	LogLevel     string
 	PluginConfig map[string]map[string]interface{}
+	Recordings   activity.RecordingOptions
+	// JournalSinks ship the journals' entries to central stores.
+	JournalSinks []sinks.Config
+	Trash        plugin.TrashOptions
+	// HTTP configures the HTTP client that's shared by the core plugins.
+	HTTP plugin.HTTPOptions
+	// Remote configures the API's TCP listener for remote daemons. See api.RemoteOptions.
+	Remote api.RemoteOptions
+	// NinePListen is the address that the filesystem's exported at over 9P. The
+	// export's off when it's empty. Its clients attach with Remote's tokens.
+	// See ninep.Serve.
+	NinePListen string
 }
 
 // SetupLogging configures log level and output file according to configured options.
@@ -61,6 +115,14 @@ func (o Opts) SetupLogging() (*os.File, error) {
 	return nil, nil
 }
 
+// The protocols that the filesystem can be served with
+const (
+	FUSE = "fuse"
+	// NFS serves a read-only filesystem over NFS v3 for systems where FUSE
+	// isn't available.
+	NFS = "nfs"
+)
+
 type controlChannels struct {
 	stopCh    chan<- context.Context
 	stoppedCh <-chan struct{}
@@ -68,12 +130,14 @@ type controlChannels struct {
 
 // Server encapsulates a running wash server with both Socket and FUSE servers.
 type Server struct {
-	mountpoint       string
-	socket           string
-	opts             Opts
-	logFH            *os.File
-	api              controlChannels
+	mountpoint string
+	socket     string
+	opts       Opts
+	logFH      *os.File
+	api        controlChannels
+	// fuse is the FUSE or NFS server, depending on the MountProtocol option.
 	fuse             controlChannels
+	ninep            controlChannels
 	plugins          map[string]plugin.Root
 	analyticsClient  analytics.Client
 	forVerifyInstall bool
@@ -114,12 +178,28 @@ func (s *Server) Start() (bool, error) {
 
 	successfullyLoadedPlugins := true
 	if !s.forVerifyInstall {
+		// The plugins create their API clients in Init, so the shared HTTP
+		// client's configured first.
+		if err := plugin.SetHTTPOptions(s.opts.HTTP); err != nil {
+			return false, err
+		}
 		successfullyLoadedPlugins = s.loadPlugins(registry)
 		if len(registry.Plugins()) == 0 {
 			return successfullyLoadedPlugins, fmt.Errorf("no plugins loaded. If you're planning on using Wash just for its external plugins, then go to https://puppetlabs.github.io/wash/docs/external-plugins")
 		}
 
 		plugin.InitCache()
+		activity.SetRecordingOptions(s.opts.Recordings)
+		for _, config := range s.opts.JournalSinks {
+			sink, err := sinks.New(config)
+			if err != nil {
+				return successfullyLoadedPlugins, err
+			}
+			activity.AddSink(config.SinkName(), sink, config.Options())
+		}
+		plugin.SetTrashOptions(s.opts.Trash)
+		fuse.SetOpLogging(s.opts.FuseOpLogging)
+		fuse.SetOptions(s.opts.Fuse)
 
 		analyticsConfig, err := analytics.GetConfig()
 		if err != nil {
@@ -132,6 +212,7 @@ func (s *Server) Start() (bool, error) {
 		registry,
 		s.mountpoint,
 		s.socket,
+		s.opts.Remote,
 		s.analyticsClient,
 	)
 	if err != nil {
@@ -139,7 +220,11 @@ func (s *Server) Start() (bool, error) {
 	}
 	s.api = controlChannels{stopCh: apiServerStopCh, stoppedCh: apiServerStoppedCh}
 
-	fuseServerStopCh, fuseServerStoppedCh, err := fuse.ServeFuseFS(
+	serveFS := fuse.ServeFuseFS
+	if s.opts.MountProtocol == NFS {
+		serveFS = nfs.ServeNFS
+	}
+	fuseServerStopCh, fuseServerStoppedCh, err := serveFS(
 		registry,
 		s.mountpoint,
 		s.analyticsClient,
@@ -150,6 +235,21 @@ func (s *Server) Start() (bool, error) {
 	}
 	s.fuse = controlChannels{stopCh: fuseServerStopCh, stoppedCh: fuseServerStoppedCh}
 
+	if s.opts.NinePListen != "" {
+		ninepServerStopCh, ninepServerStoppedCh, err := ninep.Serve(
+			registry,
+			s.opts.NinePListen,
+			auth.NewStore(s.opts.Remote.TokensFile),
+			s.analyticsClient,
+		)
+		if err != nil {
+			s.stopAPIServer()
+			s.stopFUSEServer()
+			return successfullyLoadedPlugins, fmt.Errorf("could not export the filesystem over 9P: %v", err)
+		}
+		s.ninep = controlChannels{stopCh: ninepServerStopCh, stoppedCh: ninepServerStoppedCh}
+	}
+
 	if !s.forVerifyInstall {
 		if s.opts.CPUProfilePath != "" {
 			f, err := os.Create(s.opts.CPUProfilePath)
@@ -186,6 +286,15 @@ func (s *Server) stopFUSEServer() {
 	<-s.fuse.stoppedCh
 }
 
+func (s *Server) stop9PServer() {
+	// The 9P server's only started if it's configured.
+	if s.ninep.stopCh == nil {
+		return
+	}
+	close(s.ninep.stopCh)
+	<-s.ninep.stoppedCh
+}
+
 func (s *Server) shutdown() {
 	if s.forVerifyInstall {
 		return
@@ -235,6 +344,7 @@ func (s *Server) Wait(sigCh chan os.Signal) {
 		// This code-path is possible if the API server prematurely shuts down
 		s.stopFUSEServer()
 	}
+	s.stop9PServer()
 	s.shutdown()
 }
 
@@ -242,6 +352,7 @@ func (s *Server) Wait(sigCh chan os.Signal) {
 func (s *Server) Stop() {
 	s.stopAPIServer()
 	s.stopFUSEServer()
+	s.stop9PServer()
 	s.shutdown()
 }
 
@@ -48,10 +48,10 @@ func (b bash) Command(subcommands []string, rundir string) (*exec.Cmd, error) {
 	// Re-add aliases in case .bashrc overrode them.
 	content += common
 
-	// Configure prompt and override `cd`
+	// Configure prompt, override `cd` and complete wash and the subcommands
 	content += preparePrompt(`\e[0;36m`, `\e[0;32m`, `\e[m`, "export PS1") + `
 export PROMPT_COMMAND=prompter
-` + overrideCd() + `
+` + overrideCd() + bashCompletion(append([]string{"wash"}, subcommands...)) + `
 [[ -s ~/.washrc ]] && source ~/.washrc
 `
 	if err := ioutil.WriteFile(rcpath, []byte(content), 0644); err != nil {
@@ -0,0 +1,49 @@
+package shell
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Completion returns the completion script for the named shell (bash or zsh). The
+// script completes the given commands, which are 'wash' and, in the Wash shell, the
+// subcommand aliases. It gets its candidates from 'wash __complete', and falls back
+// to the shell's filename completion if there aren't any.
+func Completion(sh string, commands []string) (string, error) {
+	switch sh {
+	case "bash":
+		return bashCompletion(commands), nil
+	case "zsh":
+		return zshCompletion(commands), nil
+	default:
+		return "", fmt.Errorf("completion is not supported for %v, only for bash and zsh", sh)
+	}
+}
+
+func bashCompletion(commands []string) string {
+	return `
+function _wash_complete() {
+	local IFS=$'\n'
+	COMPREPLY=($(wash __complete "${COMP_WORDS[@]:0:$((COMP_CWORD+1))}" 2>/dev/null))
+}
+complete -o default -F _wash_complete ` + strings.Join(commands, " ") + `
+`
+}
+
+func zshCompletion(commands []string) string {
+	return `
+function _wash_complete() {
+	local -a candidates
+	candidates=("${(@f)$(wash __complete "${(@)words[1,$CURRENT]}" 2>/dev/null)}")
+	if [[ -n "${candidates[1]}" ]]; then
+		compadd -- "${candidates[@]}"
+	else
+		_files
+	fi
+}
+if ! (( $+functions[compdef] )); then
+	autoload -Uz compinit && compinit
+fi
+compdef _wash_complete ` + strings.Join(commands, " ") + `
+`
+}
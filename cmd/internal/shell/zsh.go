@@ -58,11 +58,11 @@ fi
 	// Re-add aliases in case .zprofile or .zshrc overrode them.
 	content += common
 
-	// Configure prompt and override `cd`
+	// Configure prompt, override `cd` and complete wash and the subcommands
 	content += preparePrompt("%F{cyan}", "%F{green}", "%f", "PROMPT") + `
 autoload -Uz add-zsh-hook
 add-zsh-hook precmd prompter
-` + overrideCd() + `
+` + overrideCd() + zshCompletion(append([]string{"wash"}, subcommands...)) + `
 if [[ -s ~/.washrc ]]; then source ~/.washrc; fi
 `
 	if err := ioutil.WriteFile(filepath.Join(rundir, ".zshrc"), []byte(content), 0644); err != nil {
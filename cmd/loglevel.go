@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	cmdutil "github.com/puppetlabs/wash/cmd/util"
+)
+
+func loglevelCommand() *cobra.Command {
+	loglevelCmd := &cobra.Command{
+		Use:   "loglevel [<plugin> [<level>]]",
+		Short: "Prints or sets the journal level of the loaded plugins",
+		Long: `Controls how much of each plugin's activity is written to the journal, which can be
+viewed with 'wash history'. With no arguments, prints the journal level of every loaded
+plugin. With a plugin, prints that plugin's level. With a plugin and a level, sets the
+plugin's level. The change takes effect immediately, so a single plugin can be debugged
+without restarting the server, e.g.
+  wash loglevel aws debug
+
+Valid levels are trace, debug, info, warn, error, fatal and panic. Use "default" to
+reset the plugin to the default level (info).`,
+		Args: cobra.MaximumNArgs(2),
+		RunE: toRunE(loglevelMain),
+	}
+	return loglevelCmd
+}
+
+func loglevelMain(cmd *cobra.Command, args []string) exitCode {
+	conn := cmdutil.NewClient()
+
+	if len(args) == 2 {
+		plugin, level := args[0], args[1]
+		if level == "default" {
+			level = ""
+		}
+		if err := conn.SetJournalLevel(plugin, level); err != nil {
+			cmdutil.ErrPrintf("%v: %v\n", plugin, err)
+			return exitCode{1}
+		}
+		return exitCode{0}
+	}
+
+	levels, err := conn.JournalLevels()
+	if err != nil {
+		cmdutil.ErrPrintf("%v\n", err)
+		return exitCode{1}
+	}
+
+	if len(args) == 1 {
+		plugin := args[0]
+		level, ok := levels[plugin]
+		if !ok {
+			cmdutil.ErrPrintf("%v: plugin does not exist\n", plugin)
+			return exitCode{1}
+		}
+		cmdutil.Println(level)
+		return exitCode{0}
+	}
+
+	plugins := make([]string, 0, len(levels))
+	for plugin := range levels {
+		plugins = append(plugins, plugin)
+	}
+	sort.Strings(plugins)
+	table := make([][]string, len(plugins))
+	for i, plugin := range plugins {
+		table[i] = []string{plugin, levels[plugin]}
+	}
+	cmdutil.Print(cmdutil.NewTableWithHeaders([]cmdutil.ColumnHeader{
+		{ShortName: "plugin", FullName: "PLUGIN"},
+		{ShortName: "level", FullName: "LEVEL"},
+	}, table).Format())
+	return exitCode{0}
+}
@@ -34,7 +34,9 @@ func formatTime(t time.Time) string {
 
 func cname(entry apitypes.Entry) string {
 	cname := entry.CName
-	if entry.Supports(plugin.ListAction()) {
+	if entry.Target != "" {
+		cname += "@"
+	} else if entry.Supports(plugin.ListAction()) {
 		cname += "/"
 	}
 	return cname
@@ -96,22 +98,30 @@ func lsMain(cmd *cobra.Command, args []string) exitCode {
 	conn := cmdutil.NewClient()
 	items := make([]lsItem, len(paths))
 
-	// Fetch the required data
+	// Fetch the required data. The entries are fetched in a single batch,
+	// then the directories are listed concurrently.
+	results, err := conn.Batch(paths, false)
 	var wg sync.WaitGroup
 	for ix, path := range paths {
+		items[ix].path = path
+		if err != nil {
+			items[ix].err = err
+			continue
+		}
+		if results[ix].Error != nil {
+			items[ix].err = results[ix].Error
+			continue
+		}
+		items[ix].entry = *results[ix].Entry
+		if items[ix].Type() != dirItem {
+			continue
+		}
+
 		wg.Add(1)
-		go func(ix int, path string) {
+		go func(item *lsItem) {
 			defer wg.Done()
-
-			var item lsItem
-			item.path = path
-			item.entry, item.err = conn.Info(path)
-			if item.err == nil && item.Type() == dirItem {
-				item.children, item.err = conn.List(path)
-			}
-
-			items[ix] = item
-		}(ix, path)
+			item.children, item.err = conn.List(item.path)
+		}(&items[ix])
 	}
 	wg.Wait()
 
@@ -1,8 +1,6 @@
 package cmd
 
 import (
-	"sync"
-
 	cmdutil "github.com/puppetlabs/wash/cmd/util"
 	"github.com/spf13/cobra"
 )
@@ -14,7 +12,11 @@ func metaCommand() *cobra.Command {
 		Long: `Prints the metadata of the given entries. By default, meta prints the
 full metadata as returned by the metadata endpoint. Specify the
 --partial flag to instead print the partial metadata, a (possibly)
-reduced set of metadata that's returned when entries are enumerated.`,
+reduced set of metadata that's returned when entries are enumerated.
+
+The full metadata includes a wash.cache section that describes whether
+the entry's actions are served from Wash's cache, how old their cached
+results are, and how long their last refresh took.`,
 		Args: cobra.MinimumNArgs(1),
 		RunE: toRunE(metaMain),
 	}
@@ -43,41 +45,29 @@ func metaMain(cmd *cobra.Command, args []string) exitCode {
 	conn := cmdutil.NewClient()
 	metadataMap := make(map[string]map[string]interface{})
 
-	// Fetch the data.
+	// Fetch the data in a single batch. The partial metadata is part of
+	// each entry.
+	results, err := conn.Batch(paths, !showPartialMetadata)
+	if err != nil {
+		cmdutil.ErrPrintf("%v\n", err)
+		return exitCode{1}
+	}
 	ec := 0
-	var metadataMapMux sync.Mutex
-	var wg sync.WaitGroup
-	for _, path := range paths {
-		wg.Add(1)
-		go func(path string) {
-			defer wg.Done()
-
-			var metadata map[string]interface{}
-
-			if showPartialMetadata {
-				e, err := conn.Info(path)
-				if err != nil {
-					ec = 1
-					cmdutil.SafeErrPrintf("%v: %v\n", path, err)
-					return
-				}
-				metadata = e.Metadata
-			} else {
-				var err error
-				metadata, err = conn.Metadata(path)
-				if err != nil {
-					ec = 1
-					cmdutil.SafeErrPrintf("%v: %v\n", path, err)
-					return
-				}
-			}
-
-			metadataMapMux.Lock()
-			metadataMap[path] = metadata
-			metadataMapMux.Unlock()
-		}(path)
+	for _, result := range results {
+		if result.Error != nil {
+			ec = 1
+			cmdutil.ErrPrintf("%v: %v\n", result.Path, result.Error)
+			continue
+		}
+		if showPartialMetadata {
+			metadataMap[result.Path] = result.Entry.Metadata
+		} else if result.Metadata != nil {
+			metadataMap[result.Path] = result.Metadata
+		} else {
+			// Empty metadata is omitted from the result.
+			metadataMap[result.Path] = map[string]interface{}{}
+		}
 	}
-	wg.Wait()
 
 	// Marshal the results
 	var result interface{} = metadataMap
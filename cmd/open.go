@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	apitypes "github.com/puppetlabs/wash/api/types"
+	cmdutil "github.com/puppetlabs/wash/cmd/util"
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/spf13/cobra"
+)
+
+func openCommand() *cobra.Command {
+	openCmd := &cobra.Command{
+		Use:   "open <path>",
+		Short: "Opens the entry's page in its web console",
+		Long: `Opens the entry's page in its backend's web console, like the AWS console for
+an EC2 instance. The URL's generated from the console URL template in the
+entry's schema, so only entries whose plugin provides one can be opened. Use
+'docs <path>' to see whether an entry has one.
+
+The URL's opened with the browser in the BROWSER environment variable, or with
+the system's default browser if it isn't set.`,
+		Args: cobra.ExactArgs(1),
+		RunE: toRunE(openMain),
+	}
+	openCmd.Flags().BoolP("print", "p", false, "Print the URL instead of opening it")
+	return openCmd
+}
+
+func openMain(cmd *cobra.Command, args []string) exitCode {
+	path := args[0]
+	print, err := cmd.Flags().GetBool("print")
+	if err != nil {
+		panic(err.Error())
+	}
+
+	conn := cmdutil.NewClient()
+	entry, err := conn.Info(path)
+	if err != nil {
+		cmdutil.ErrPrintf("%v\n", err)
+		return exitCode{1}
+	}
+	schema, err := conn.Schema(path)
+	if err != nil {
+		cmdutil.ErrPrintf("failed to get the schema: %v\n", err)
+		return exitCode{1}
+	}
+	if schema == nil || schema.ConsoleURL() == "" {
+		cmdutil.ErrPrintf("%v does not have a web console page\n", path)
+		return exitCode{1}
+	}
+	metadata, err := conn.Metadata(path)
+	if err != nil {
+		cmdutil.ErrPrintf("failed to get the metadata: %v\n", err)
+		return exitCode{1}
+	}
+
+	url, err := renderConsoleURL(schema.ConsoleURL(), entry, metadata)
+	if err != nil {
+		cmdutil.ErrPrintf("failed to generate the URL for %v: %v\n", path, err)
+		return exitCode{1}
+	}
+	if print {
+		cmdutil.Println(url)
+		return exitCode{0}
+	}
+	if err := openURL(url); err != nil {
+		cmdutil.ErrPrintf("failed to open %v: %v\n", url, err)
+		return exitCode{1}
+	}
+	return exitCode{0}
+}
+
+// renderConsoleURL executes the entry's console URL template.
+func renderConsoleURL(template string, entry apitypes.Entry, metadata map[string]interface{}) (string, error) {
+	tmpl, err := plugin.ParseConsoleURL(template)
+	if err != nil {
+		return "", err
+	}
+	custom := entry.Attributes.CustomAttributes()
+	if custom == nil {
+		custom = make(map[string]interface{})
+	}
+	var url strings.Builder
+	err = tmpl.Execute(&url, plugin.ConsoleURLData{
+		Name:       entry.Name,
+		CName:      entry.CName,
+		Path:       entry.Path,
+		Attributes: custom,
+		Metadata:   metadata,
+	})
+	if err != nil {
+		return "", err
+	}
+	return url.String(), nil
+}
+
+// openURL opens the URL in the user's browser.
+func openURL(url string) error {
+	var cmd *exec.Cmd
+	if browser := os.Getenv("BROWSER"); browser != "" {
+		cmd = exec.Command(browser, url)
+	} else {
+		switch runtime.GOOS {
+		case "darwin":
+			cmd = exec.Command("open", url)
+		case "windows":
+			cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+		default:
+			cmd = exec.Command("xdg-open", url)
+		}
+	}
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%v: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"testing"
+
+	apitypes "github.com/puppetlabs/wash/api/types"
+	"github.com/stretchr/testify/suite"
+)
+
+type OpenTestSuite struct {
+	suite.Suite
+}
+
+func (suite *OpenTestSuite) TestRenderConsoleURL() {
+	entry := apitypes.Entry{Name: "web_i-0123", CName: "web_i-0123", Path: "/mnt/aws/prod/web_i-0123"}
+	entry.Attributes.SetCustom("region", "us-west-2")
+	metadata := map[string]interface{}{"InstanceId": "i-0123"}
+
+	url, err := renderConsoleURL(
+		"https://console.aws.amazon.com/ec2/home?region={{.Attributes.region}}#InstanceDetails:instanceId={{.Metadata.InstanceId}}",
+		entry,
+		metadata,
+	)
+	if suite.NoError(err) {
+		suite.Equal("https://console.aws.amazon.com/ec2/home?region=us-west-2#InstanceDetails:instanceId=i-0123", url)
+	}
+
+	url, err = renderConsoleURL("https://example.com/{{.Name}}", entry, nil)
+	if suite.NoError(err) {
+		suite.Equal("https://example.com/web_i-0123", url)
+	}
+}
+
+func (suite *OpenTestSuite) TestRenderConsoleURL_MissingKey() {
+	_, err := renderConsoleURL("https://example.com/{{.Attributes.zone}}", apitypes.Entry{}, nil)
+	suite.Regexp("map has no entry for key \"zone\"", err)
+
+	_, err = renderConsoleURL("https://example.com/{{.Metadata.id}}", apitypes.Entry{}, map[string]interface{}{})
+	suite.Regexp("map has no entry for key \"id\"", err)
+}
+
+func TestOpen(t *testing.T) {
+	suite.Run(t, new(OpenTestSuite))
+}
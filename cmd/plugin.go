@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	apitypes "github.com/puppetlabs/wash/api/types"
+	"github.com/puppetlabs/wash/cmd/internal/config"
+	cmdutil "github.com/puppetlabs/wash/cmd/util"
+	"github.com/puppetlabs/wash/plugin/external"
+	"github.com/puppetlabs/wash/plugin/external/scaffold"
+)
+
+func pluginCommand() *cobra.Command {
+	pluginCmd := &cobra.Command{
+		Use:   "plugin",
+		Short: "Installs, enables, disables or reloads plugins while the daemon's running",
+		Long: `Manages the daemon's plugins without restarting it or unmounting the filesystem.
+Changes only last until the daemon's stopped. To keep an installed plugin, also
+add it to the 'external-plugins' key in your config file.
+
+Use 'wash plugin scaffold' to start writing a new external plugin.`,
+	}
+
+	installCmd := &cobra.Command{
+		Use:   "install <script>",
+		Short: "Installs an external plugin",
+		Long: `Loads the external plugin's script and adds the plugin to Wash's root. The
+plugin's config is read from the key that's named after the plugin in the config
+file, like it is when the daemon starts.`,
+		Args: cobra.ExactArgs(1),
+		RunE: toRunE(pluginInstallMain),
+	}
+	installCmd.Flags().String("protocol", "fork", "How the script's invoked, either fork or jsonrpc")
+	installCmd.Flags().String("config-file", config.DefaultFile(), "The config file to read the plugin's config from")
+	pluginCmd.AddCommand(installCmd)
+
+	pluginCmd.AddCommand(&cobra.Command{
+		Use:   "enable <plugin>...",
+		Short: "Enables disabled plugins",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  toRunE(pluginActionMain("enable")),
+	})
+	pluginCmd.AddCommand(&cobra.Command{
+		Use:   "disable <plugin>...",
+		Short: "Disables plugins, removing them from Wash's root and clearing their cached data",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  toRunE(pluginActionMain("disable")),
+	})
+	pluginCmd.AddCommand(&cobra.Command{
+		Use:   "reload <plugin>...",
+		Short: "Re-initializes plugins and clears their cached data",
+		Long: `Re-initializes the plugins with their config and clears their cached data, so
+that an external plugin's updated script is picked up. External plugins that run
+as a daemon are relaunched.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: toRunE(pluginActionMain("reload")),
+	})
+
+	scaffoldCmd := &cobra.Command{
+		Use:   "scaffold <name>",
+		Short: "Generates a skeleton external plugin",
+		Long: `Writes a skeleton external plugin script named <name> to the output directory.
+The skeleton implements the external plugin protocol's calling conventions, and
+has a stub for each of the protocol's methods. Its root lists a single readable
+entry, so it can be installed with 'wash plugin install' right away.`,
+		Args: cobra.ExactArgs(1),
+		RunE: toRunE(pluginScaffoldMain),
+	}
+	scaffoldCmd.Flags().String("language", "python", "The script's language, one of "+strings.Join(scaffold.Languages(), ", "))
+	scaffoldCmd.Flags().String("output-dir", ".", "The directory to write the script to")
+	pluginCmd.AddCommand(scaffoldCmd)
+	return pluginCmd
+}
+
+func pluginInstallMain(cmd *cobra.Command, args []string) exitCode {
+	script, err := filepath.Abs(args[0])
+	if err != nil {
+		cmdutil.ErrPrintf("Could not compute the absolute path of %v: %v\n", args[0], err)
+		return exitCode{1}
+	}
+	protocol, err := cmd.Flags().GetString("protocol")
+	if err != nil {
+		panic(err.Error())
+	}
+	configFile, err := cmd.Flags().GetString("config-file")
+	if err != nil {
+		panic(err.Error())
+	}
+	if err := config.ReadFrom(configFile); err != nil {
+		cmdutil.ErrPrintf("%v\n", err)
+		return exitCode{1}
+	}
+
+	name := external.PluginSpec{Script: script}.Name()
+	conn := cmdutil.NewClient()
+	err = conn.InstallPlugin(apitypes.PluginInstallBody{
+		Script:   script,
+		Protocol: protocol,
+		Config:   viper.GetStringMap(name),
+	})
+	if err != nil {
+		cmdutil.ErrPrintf("%v: %v\n", name, err)
+		return exitCode{1}
+	}
+	cmdutil.Printf("%v has been installed\n", name)
+	return exitCode{0}
+}
+
+func pluginActionMain(action string) func(*cobra.Command, []string) exitCode {
+	return func(cmd *cobra.Command, args []string) exitCode {
+		conn := cmdutil.NewClient()
+		fn, verb := conn.EnablePlugin, "enabled"
+		switch action {
+		case "disable":
+			fn, verb = conn.DisablePlugin, "disabled"
+		case "reload":
+			fn, verb = conn.ReloadPlugin, "reloaded"
+		}
+
+		ec := 0
+		for _, name := range args {
+			if err := fn(name); err != nil {
+				ec = 1
+				cmdutil.ErrPrintf("%v: %v\n", name, err)
+			} else {
+				cmdutil.Printf("%v has been %v\n", name, verb)
+			}
+		}
+		return exitCode{ec}
+	}
+}
+
+func pluginScaffoldMain(cmd *cobra.Command, args []string) exitCode {
+	language, err := cmd.Flags().GetString("language")
+	if err != nil {
+		panic(err.Error())
+	}
+	outputDir, err := cmd.Flags().GetString("output-dir")
+	if err != nil {
+		panic(err.Error())
+	}
+
+	name, content, err := scaffold.Skeleton(language, args[0])
+	if err != nil {
+		cmdutil.ErrPrintf("%v\n", err)
+		return exitCode{1}
+	}
+	path := filepath.Join(outputDir, name)
+	// O_EXCL avoids overwriting an existing plugin.
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0755)
+	if err != nil {
+		cmdutil.ErrPrintf("Could not create the script: %v\n", err)
+		return exitCode{1}
+	}
+	_, err = f.Write(content)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		cmdutil.ErrPrintf("Could not write the script: %v\n", err)
+		return exitCode{1}
+	}
+	cmdutil.Printf("Wrote %v. Install it with wash plugin install %v\n", path, path)
+	return exitCode{0}
+}
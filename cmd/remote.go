@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/puppetlabs/wash/api/client"
+	"github.com/puppetlabs/wash/cmd/internal/config"
+	cmdutil "github.com/puppetlabs/wash/cmd/util"
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// useRemoteClient makes the subcommands thin clients of the remote daemon
+// if --remote or WASH_REMOTE is set.
+func useRemoteClient(cmd *cobra.Command, args []string) error {
+	if config.Remote == "" {
+		return nil
+	}
+	c, err := newRemoteClient(config.Remote)
+	if err != nil {
+		cmdutil.ErrPrintf("%v\n", err)
+		return exitCode{1}
+	}
+	cmdutil.NewClient = func() client.Client {
+		return c
+	}
+	return nil
+}
+
+// newRemoteClient returns a thin client of the remote daemon at address. The
+// client's certificate or token is read from the client key of the default
+// config file, or from the WASH_CLIENT_* environment variables.
+func newRemoteClient(address string) (client.Client, error) {
+	if err := config.ReadFrom(config.DefaultFile()); err != nil {
+		return nil, err
+	}
+	tlsConfig, err := client.NewTLSConfig(
+		viper.GetString("client.ca_bundle"),
+		viper.GetString("client.cert"),
+		viper.GetString("client.key"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("client config: %v", err)
+	}
+	token := viper.GetString("client.token")
+	if token == "" && len(tlsConfig.Certificates) == 0 {
+		return nil, fmt.Errorf("--remote requires either the client.cert and client.key configs, or the client.token config")
+	}
+	return client.ForThinClient(strings.TrimPrefix(address, "tcp://"), tlsConfig, token), nil
+}
+
+// remoteShellMain starts the shell as a thin client of the remote daemon. The
+// daemon's filesystem isn't mounted, so the shell starts in the current
+// directory and the wash subcommands' paths are relative to the daemon's
+// mountpoint.
+func remoteShellMain(cmd *cobra.Command, execfile string, cachedir string) exitCode {
+	if _, err := cmdutil.NewClient().Mountpoint(); err != nil {
+		cmdutil.ErrPrintf("Unable to reach the remote daemon at %v: %v\n", config.Remote, err)
+		return exitCode{1}
+	}
+
+	rundir, err := ioutil.TempDir(cachedir, "run")
+	if err != nil {
+		cmdutil.ErrPrintf("Error creating temporary run location in %v: %v\n", cachedir, err)
+		return exitCode{1}
+	}
+	defer os.RemoveAll(rundir)
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		cmdutil.ErrPrintf("Unable to get the current directory: %v\n", err)
+		return exitCode{1}
+	}
+	if plugin.IsInteractive() {
+		cmdutil.Printf("Welcome to Wash! Connected to %v. Try 'docs .'\n", config.Remote)
+	}
+	return runShell(cmd, execfile, rundir, []string{"WASH_REMOTE=" + config.Remote}, cwd)
+}
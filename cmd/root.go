@@ -47,6 +47,10 @@ func registerInvocationToGA(cmd *cobra.Command, socketPath string) <-chan struct
 			// Analytics for these is sent by the server during its startup.
 			return
 		}
+		if config.Remote != "" {
+			// Remote daemons don't serve the analytics endpoint.
+			return
+		}
 		// Errors are reported in the server logs so no need to expose them
 		// to the user
 		_ = cmdutil.NewClient().Screenview(name, analytics.Params{})
@@ -150,7 +154,11 @@ then starts your system shell with shortcuts configured for wash subcommands.`,
 		// Omit validate because it's meant to be run independently to test a plugin and should not be
 		// part of normal shell interaction.
 		addCommand(rootCmd, validateCommand())
+		// The Wash shell already loads the completion script
+		addCommand(rootCmd, completionCommand())
 	}
+	rootCmd.PersistentFlags().StringVar(&config.Remote, "remote", config.Remote, "Run as a thin client of the remote daemon at this host:port. See the client config")
+	rootCmd.PersistentPreRunE = useRemoteClient
 	rootCmd = ensureGARegistration(rootCmd)
 
 	addCommand(rootCmd, metaCommand())
@@ -166,6 +174,17 @@ then starts your system shell with shortcuts configured for wash subcommands.`,
 	addCommand(rootCmd, docsCommand())
 	addCommand(rootCmd, deleteCommand())
 	addCommand(rootCmd, signalCommand())
+	addCommand(rootCmd, waitCommand())
+	addCommand(rootCmd, cpCommand())
+	addCommand(rootCmd, loglevelCommand())
+	addCommand(rootCmd, doctorCommand())
+	addCommand(rootCmd, statsCommand())
+	addCommand(rootCmd, trashCommand())
+	addCommand(rootCmd, pluginCommand())
+	addCommand(rootCmd, openCommand())
+	addCommand(rootCmd, tokenCommand())
+	// __complete is hidden and called on every tab, so it isn't registered to GA
+	rootCmd.AddCommand(completeCommand())
 
 	return rootCmd
 }
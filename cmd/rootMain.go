@@ -7,8 +7,7 @@ import (
 	"path/filepath"
 	"strings"
 
-	"golang.org/x/sys/unix"
-
+	"github.com/puppetlabs/wash/cmd/internal/config"
 	"github.com/puppetlabs/wash/cmd/internal/server"
 	"github.com/puppetlabs/wash/cmd/internal/shell"
 	cmdutil "github.com/puppetlabs/wash/cmd/util"
@@ -58,7 +57,7 @@ func rootMain(cmd *cobra.Command, args []string) exitCode {
 	// work with shells (zsh) that try to use their parent's process group, which would no longer be
 	// in the same session. By forking, we keep the original process group in its original session so
 	// the child shell can still modify it.
-	if plugin.IsInteractive() && os.Getpid() == unix.Getpgrp() {
+	if plugin.IsInteractive() && isProcessGroupLeader() {
 		comm := exec.Command(os.Args[0], os.Args[1:]...)
 		comm.Stdin = os.Stdin
 		comm.Stdout = os.Stdout
@@ -78,6 +77,10 @@ func rootMain(cmd *cobra.Command, args []string) exitCode {
 		return exitCode{1}
 	}
 
+	if config.Remote != "" {
+		return remoteShellMain(cmd, execfile, cachedir)
+	}
+
 	// Mountpath is not cleaned up correctly if removed as part of deleting rundir, so it's placed
 	// in a separate location. The server has reported that it's completely done by the time we
 	// delete rundir, so I'm not sure why it doesn't clean up correctly. Alternatively, adding a
@@ -129,6 +132,13 @@ func rootMain(cmd *cobra.Command, args []string) exitCode {
 		cmdutil.Println("Welcome to Wash! Try 'docs .'")
 	}
 
+	env := []string{"WASH_SOCKET=" + socketpath, "W=" + mountpath}
+	return runShell(cmd, execfile, rundir, env, mountpath)
+}
+
+// runShell runs the shell with the wash subcommands in rundir, and with the
+// given environment variables and working directory.
+func runShell(cmd *cobra.Command, execfile string, rundir string, env []string, dir string) exitCode {
 	if !symlinkWash(rundir) {
 		return exitCode{1}
 	}
@@ -158,12 +168,9 @@ func rootMain(cmd *cobra.Command, args []string) exitCode {
 	if comm.Env == nil {
 		comm.Env = os.Environ()
 	}
-	comm.Env = append(comm.Env,
-		"WASH_SOCKET="+socketpath,
-		"W="+mountpath,
-		"PATH="+rundir+string(os.PathListSeparator)+os.Getenv("PATH"),
-	)
-	comm.Dir = mountpath
+	comm.Env = append(comm.Env, env...)
+	comm.Env = append(comm.Env, "PATH="+rundir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	comm.Dir = dir
 
 	if startErr := comm.Start(); startErr != nil {
 		cmdutil.ErrPrintf("%v\n", startErr)
@@ -171,9 +178,10 @@ func rootMain(cmd *cobra.Command, args []string) exitCode {
 	}
 
 	// If interactive (when we might prompt the user for input, such as security tokens), create a
-	// new session. If not interactive, calling setsid is pointless and might fail.
-	if plugin.IsInteractive() {
-		if _, err := unix.Setsid(); err != nil {
+	// new session. If not interactive, calling setsid is pointless and might fail. A thin client
+	// doesn't run a daemon, so it has nothing to move.
+	if plugin.IsInteractive() && config.Remote == "" {
+		if err := newSession(); err != nil {
 			cmdutil.ErrPrintf("Error moving Wash daemon to new session: %v", err)
 
 			if err := comm.Process.Kill(); err != nil {
@@ -226,8 +234,9 @@ func flattenSubcommands(subcommands []*cobra.Command) []string {
 			panic("all subcommands should have non-empty usage")
 		}
 		name := tokens[0]
-		// Specifically skip server as undocumented when running in wash shell.
-		if name == "server" {
+		// Specifically skip server as undocumented when running in wash shell. Hidden
+		// commands like __complete are only meant to be called by wash.
+		if name == "server" || subcommand.Hidden {
 			continue
 		}
 
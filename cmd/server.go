@@ -10,10 +10,14 @@ import (
 	"time"
 
 	"github.com/Benchkram/errz"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/activity/sinks"
+	"github.com/puppetlabs/wash/api"
 	apifs "github.com/puppetlabs/wash/api/fs"
 	"github.com/puppetlabs/wash/cmd/internal/config"
 	"github.com/puppetlabs/wash/cmd/internal/server"
 	cmdutil "github.com/puppetlabs/wash/cmd/util"
+	"github.com/puppetlabs/wash/fuse"
 	"github.com/puppetlabs/wash/plugin"
 	"github.com/puppetlabs/wash/plugin/external"
 	"gopkg.in/yaml.v2"
@@ -75,6 +79,13 @@ func addServerArgs(cmd *cobra.Command, defaultLogLevel string) {
 	cmd.Flags().String("loglevel", defaultLogLevel, "Set the logging level")
 	cmd.Flags().String("logfile", "", "Set the log file's location. Defaults to stdout")
 	cmd.Flags().String("cpuprofile", "", "Write cpu profile to file")
+	cmd.Flags().Bool("fuse-oplog", false, "Record every FUSE operation's latency in the journal and in histograms that are reported by 'wash stats'")
+	cmd.Flags().Duration("fuse-attr-timeout", fuse.DefaultCacheOptions.AttrTimeout, "How long the kernel caches an entry's attributes")
+	cmd.Flags().Duration("fuse-entry-timeout", fuse.DefaultCacheOptions.EntryTimeout, "How long the kernel caches a directory's lookups")
+	cmd.Flags().Bool("fuse-direct-io", fuse.DefaultCacheOptions.DirectIO, "Bypass the kernel's page cache when reading files")
+	cmd.Flags().String("mount-protocol", server.FUSE, "Serve the filesystem with fuse, or with nfs on systems where FUSE isn't available. The NFS filesystem is read-only")
+	cmd.Flags().String("9p-listen", "", "Also export the filesystem over 9P2000.L at this TCP address, or at unix:<path>. Addresses without a host listen on 127.0.0.1. The export is read-only, and remote clients must attach with a token from 'wash token create'")
+	cmd.Flags().String("listen", "", "Also serve the API's /fs endpoints to remote clients at this TCP address, like tcp://0.0.0.0:8443. See the api.listen config")
 	cmd.Flags().String("config-file", config.DefaultFile(), "Set the config file's location")
 }
 
@@ -83,6 +94,13 @@ func bindServerArgs(cmd *cobra.Command, args []string) {
 	errz.Fatal(viper.BindPFlag("loglevel", cmd.Flags().Lookup("loglevel")))
 	errz.Fatal(viper.BindPFlag("logfile", cmd.Flags().Lookup("logfile")))
 	errz.Fatal(viper.BindPFlag("cpuprofile", cmd.Flags().Lookup("cpuprofile")))
+	errz.Fatal(viper.BindPFlag("fuse_oplog", cmd.Flags().Lookup("fuse-oplog")))
+	errz.Fatal(viper.BindPFlag("fuse.attr_timeout", cmd.Flags().Lookup("fuse-attr-timeout")))
+	errz.Fatal(viper.BindPFlag("fuse.entry_timeout", cmd.Flags().Lookup("fuse-entry-timeout")))
+	errz.Fatal(viper.BindPFlag("fuse.direct_io", cmd.Flags().Lookup("fuse-direct-io")))
+	errz.Fatal(viper.BindPFlag("mount_protocol", cmd.Flags().Lookup("mount-protocol")))
+	errz.Fatal(viper.BindPFlag("9p.listen", cmd.Flags().Lookup("9p-listen")))
+	errz.Fatal(viper.BindPFlag("api.listen", cmd.Flags().Lookup("listen")))
 }
 
 // serverOptsFor returns map of plugins and server.Opts for the given command.
@@ -166,6 +184,21 @@ func serverOptsFor(cmd *cobra.Command) (map[string]plugin.Root, server.Opts, err
 		plugins[name] = intPlugin
 	}
 
+	var journalSinks []sinks.Config
+	if err := viper.UnmarshalKey("journal_sinks", &journalSinks); err != nil {
+		return nil, server.Opts{}, fmt.Errorf("failed to unmarshal the journal_sinks key: %v", err)
+	}
+
+	fuseOpts, err := fuseOptions()
+	if err != nil {
+		return nil, server.Opts{}, err
+	}
+
+	mountProtocol := viper.GetString("mount_protocol")
+	if mountProtocol != server.FUSE && mountProtocol != server.NFS {
+		return nil, server.Opts{}, fmt.Errorf("mount_protocol must be %v or %v, not %v", server.FUSE, server.NFS, mountProtocol)
+	}
+
 	pluginConfig := make(map[string]map[string]interface{})
 	for name := range plugins {
 		pluginConfig[name] = viper.GetStringMap(name)
@@ -177,15 +210,95 @@ func serverOptsFor(cmd *cobra.Command) (map[string]plugin.Root, server.Opts, err
 		pluginConfig["local"] = map[string]interface{}{"basepath": localfsPath}
 	}
 
+	listenAddress, err := apiListenAddress()
+	if err != nil {
+		return nil, server.Opts{}, err
+	}
+
 	// Return the options
 	return plugins, server.Opts{
 		CPUProfilePath: viper.GetString("cpuprofile"),
+		FuseOpLogging:  viper.GetBool("fuse_oplog"),
+		Fuse:           fuseOpts,
+		MountProtocol:  mountProtocol,
 		LogFile:        viper.GetString("logfile"),
 		LogLevel:       viper.GetString("loglevel"),
 		PluginConfig:   pluginConfig,
+		Recordings: activity.RecordingOptions{
+			Enabled:  viper.GetBool("recordings.enabled"),
+			MaxAge:   viper.GetDuration("recordings.max_age"),
+			MaxCount: viper.GetInt("recordings.max_count"),
+		},
+		JournalSinks: journalSinks,
+		Trash: plugin.TrashOptions{
+			Enabled:   viper.GetBool("trash.enabled"),
+			Retention: viper.GetDuration("trash.retention"),
+			Plugins:   viper.GetStringSlice("trash.plugins"),
+		},
+		HTTP: plugin.HTTPOptions{
+			Proxy:         viper.GetString("http.proxy"),
+			CABundle:      viper.GetString("http.ca_bundle"),
+			MinTLSVersion: viper.GetString("http.min_tls_version"),
+		},
+		Remote: api.RemoteOptions{
+			Address:      listenAddress,
+			CertFile:     viper.GetString("api.tls_cert"),
+			KeyFile:      viper.GetString("api.tls_key"),
+			ClientCAFile: viper.GetString("api.tls_client_ca"),
+			TokensFile:   tokensFile(),
+		},
+		NinePListen: viper.GetString("9p.listen"),
 	}, nil
 }
 
+// apiListenAddress returns the host:port of the api.listen config. The address
+// can start with tcp://.
+func apiListenAddress() (string, error) {
+	address := viper.GetString("api.listen")
+	if i := strings.Index(address, "://"); i >= 0 {
+		if scheme := address[:i]; scheme != "tcp" {
+			return "", fmt.Errorf("api.listen config: the %v scheme isn't supported. Use tcp://<host>:<port>", scheme)
+		}
+		address = address[i+len("://"):]
+	}
+	return address, nil
+}
+
+// fuseOptions returns the FUSE server's options. Each plugin in the
+// fuse.plugins key uses the top-level fuse options for the keys that it
+// doesn't set.
+func fuseOptions() (fuse.Options, error) {
+	readCacheOptions := func(key string, defaults fuse.CacheOptions) (fuse.CacheOptions, error) {
+		opts := defaults
+		if viper.IsSet(key + ".attr_timeout") {
+			opts.AttrTimeout = viper.GetDuration(key + ".attr_timeout")
+		}
+		if viper.IsSet(key + ".entry_timeout") {
+			opts.EntryTimeout = viper.GetDuration(key + ".entry_timeout")
+		}
+		if viper.IsSet(key + ".direct_io") {
+			opts.DirectIO = viper.GetBool(key + ".direct_io")
+		}
+		if opts.AttrTimeout < 0 || opts.EntryTimeout < 0 {
+			return fuse.CacheOptions{}, fmt.Errorf("%v.attr_timeout and %v.entry_timeout must not be negative", key, key)
+		}
+		return opts, nil
+	}
+
+	defaults, err := readCacheOptions("fuse", fuse.DefaultCacheOptions)
+	if err != nil {
+		return fuse.Options{}, err
+	}
+	opts := fuse.Options{CacheOptions: defaults, Plugins: make(map[string]fuse.CacheOptions)}
+	for name := range viper.GetStringMap("fuse.plugins") {
+		opts.Plugins[name], err = readCacheOptions("fuse.plugins."+name, defaults)
+		if err != nil {
+			return fuse.Options{}, err
+		}
+	}
+	return opts, nil
+}
+
 func promptEnabledPlugins() (map[string]plugin.Root, error) {
 	// Prompt them for the list of enabled plugins. This should look something
 	// like
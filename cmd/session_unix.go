@@ -0,0 +1,29 @@
+//go:build !windows
+// +build !windows
+
+package cmd
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// notifyResize relays the terminal's resizes to ch.
+func notifyResize(ch chan<- os.Signal) {
+	signal.Notify(ch, syscall.SIGWINCH)
+}
+
+// isProcessGroupLeader returns whether this process leads its process group.
+func isProcessGroupLeader() bool {
+	return os.Getpid() == unix.Getpgrp()
+}
+
+// newSession moves this process to a new session so that it no longer has a
+// controlling terminal.
+func newSession() error {
+	_, err := unix.Setsid()
+	return err
+}
@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"os"
+)
+
+// notifyResize does nothing on Windows, where console resizes aren't signals.
+// Remote TTYs keep the size that they started with.
+func notifyResize(ch chan<- os.Signal) {}
+
+// isProcessGroupLeader returns false since Windows consoles don't have process
+// groups that shells take over.
+func isProcessGroupLeader() bool {
+	return false
+}
+
+// newSession does nothing on Windows, where processes don't have controlling
+// terminals.
+func newSession() error {
+	return nil
+}
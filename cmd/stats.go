@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	apitypes "github.com/puppetlabs/wash/api/types"
+	cmdutil "github.com/puppetlabs/wash/cmd/util"
+	"github.com/puppetlabs/wash/metrics"
+)
+
+func statsCommand() *cobra.Command {
+	statsCmd := &cobra.Command{
+		Use:   "stats [-o table|json|yaml]",
+		Short: "Summarizes the performance of the Wash daemon and its plugins",
+		Long: `Prints the daemon's uptime, memory usage and cache hit ratio, the number of
+calls to each plugin's methods with their errors and latencies, and the
+operations that are in progress (like open streams). The method stats count the
+calls that were made since the daemon started, including the calls that were
+served by the cache. If the daemon was started with --fuse-oplog, it also
+prints a latency histogram of each FUSE operation.`,
+		Args: cobra.NoArgs,
+		RunE: toRunE(statsMain),
+	}
+	statsCmd.Flags().StringP("output", "o", "table", "Set the output format (table, json or yaml)")
+	return statsCmd
+}
+
+func statsMain(cmd *cobra.Command, args []string) exitCode {
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		panic(err.Error())
+	}
+	var marshaller cmdutil.Marshaller
+	if output != "table" {
+		if output != "json" && output != "yaml" {
+			cmdutil.ErrPrintf("output must be table, json or yaml, not %v\n", output)
+			return exitCode{1}
+		}
+		if marshaller, err = cmdutil.NewMarshaller(output); err != nil {
+			cmdutil.ErrPrintf("%v\n", err)
+			return exitCode{1}
+		}
+	}
+
+	stats, err := cmdutil.NewClient().Stats()
+	if err != nil {
+		cmdutil.ErrPrintf("Unable to get the daemon's stats: %v\n", err)
+		return exitCode{1}
+	}
+
+	if marshaller == nil {
+		cmdutil.Print(formatDaemonStats(stats, time.Now()))
+		return exitCode{0}
+	}
+	marshalled, err := marshaller.Marshal(stats)
+	if err != nil {
+		cmdutil.ErrPrintf("Unable to marshal the daemon's stats: %v\n", err)
+		return exitCode{1}
+	}
+	cmdutil.Println(marshalled)
+	return exitCode{0}
+}
+
+func formatDaemonStats(stats apitypes.Stats, now time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Uptime: %v\n", cmdutil.FormatDuration(stats.Uptime))
+	fmt.Fprintf(
+		&b,
+		"Memory: %v allocated, %v obtained from the OS, %v GCs, %v goroutines\n",
+		formatBytes(stats.Memory.Alloc),
+		formatBytes(stats.Memory.Sys),
+		stats.Memory.NumGC,
+		stats.Goroutines,
+	)
+	if stats.Cache != nil {
+		fmt.Fprintf(
+			&b,
+			"Cache: %v items, %v hits, %v misses (%v hit ratio)\n",
+			stats.Cache.Items,
+			stats.Cache.Hits,
+			stats.Cache.Misses,
+			formatHitRatio(stats.Cache.Hits, stats.Cache.Misses),
+		)
+	}
+
+	b.WriteString("\nPlugin calls:\n")
+	if len(stats.Plugins) == 0 {
+		b.WriteString("  none\n")
+	} else {
+		var rows [][]string
+		for _, plugin := range stats.Plugins {
+			for _, method := range plugin.Methods {
+				rows = append(rows, []string{
+					plugin.Plugin,
+					method.Method,
+					strconv.FormatUint(method.Count, 10),
+					strconv.FormatUint(method.Errors, 10),
+					formatLatency(method.AverageLatency),
+					formatLatency(method.MaxLatency),
+				})
+			}
+		}
+		b.WriteString(cmdutil.NewTableWithHeaders([]cmdutil.ColumnHeader{
+			{ShortName: "plugin", FullName: "PLUGIN"},
+			{ShortName: "method", FullName: "METHOD"},
+			{ShortName: "count", FullName: "COUNT"},
+			{ShortName: "errors", FullName: "ERRORS"},
+			{ShortName: "avg", FullName: "AVG"},
+			{ShortName: "max", FullName: "MAX"},
+		}, rows).Format())
+	}
+
+	// FUSE ops are only recorded when the daemon's started with --fuse-oplog.
+	if len(stats.FuseOps) > 0 {
+		b.WriteString("\nFUSE ops:\n")
+		b.WriteString(formatFuseOps(stats.FuseOps))
+	}
+
+	b.WriteString("\nActive operations:\n")
+	if len(stats.Active) == 0 {
+		b.WriteString("  none\n")
+	} else {
+		rows := make([][]string, len(stats.Active))
+		for i, op := range stats.Active {
+			rows[i] = []string{
+				op.Plugin,
+				op.Method,
+				cmdutil.FormatDuration(now.Sub(op.Started)),
+				op.Entry,
+			}
+		}
+		b.WriteString(cmdutil.NewTableWithHeaders([]cmdutil.ColumnHeader{
+			{ShortName: "plugin", FullName: "PLUGIN"},
+			{ShortName: "method", FullName: "METHOD"},
+			{ShortName: "running", FullName: "RUNNING"},
+			{ShortName: "entry", FullName: "ENTRY"},
+		}, rows).Format())
+	}
+	return b.String()
+}
+
+// formatFuseOps formats the histograms as a table with a column for each
+// latency bucket.
+func formatFuseOps(histograms []metrics.Histogram) string {
+	headers := []cmdutil.ColumnHeader{
+		{ShortName: "op", FullName: "OP"},
+		{ShortName: "count", FullName: "COUNT"},
+		{ShortName: "errors", FullName: "ERRORS"},
+		{ShortName: "avg", FullName: "AVG"},
+		{ShortName: "max", FullName: "MAX"},
+	}
+	for _, bound := range metrics.LatencyBuckets {
+		headers = append(headers, cmdutil.ColumnHeader{ShortName: "<=" + bound.String(), FullName: "<=" + bound.String()})
+	}
+	last := metrics.LatencyBuckets[len(metrics.LatencyBuckets)-1]
+	headers = append(headers, cmdutil.ColumnHeader{ShortName: ">" + last.String(), FullName: ">" + last.String()})
+
+	rows := make([][]string, len(histograms))
+	for i, h := range histograms {
+		row := []string{
+			strings.TrimPrefix(h.Name, "FUSE "),
+			strconv.FormatUint(h.Count, 10),
+			strconv.FormatUint(h.Errors, 10),
+			formatLatency(h.AverageLatency),
+			formatLatency(h.MaxLatency),
+		}
+		for _, n := range h.Buckets {
+			row = append(row, strconv.FormatUint(n, 10))
+		}
+		rows[i] = append(row, strconv.FormatUint(h.Slower, 10))
+	}
+	return cmdutil.NewTableWithHeaders(headers, rows).Format()
+}
+
+func formatHitRatio(hits uint64, misses uint64) string {
+	if hits+misses == 0 {
+		return "n/a"
+	}
+	return fmt.Sprintf("%.1f%%", 100*float64(hits)/float64(hits+misses))
+}
+
+// formatLatency rounds the latency so that it's readable. Plugin calls usually
+// take milliseconds, which FormatDuration doesn't show.
+func formatLatency(latency time.Duration) string {
+	return latency.Round(10 * time.Microsecond).String()
+}
+
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%v B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	apitypes "github.com/puppetlabs/wash/api/types"
+	"github.com/puppetlabs/wash/datastore"
+	"github.com/puppetlabs/wash/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatDaemonStats(t *testing.T) {
+	now := time.Now()
+	stats := apitypes.Stats{
+		Uptime:     90 * time.Second,
+		Memory:     apitypes.MemoryStats{Alloc: 3 * 1024 * 1024, Sys: 512, NumGC: 4},
+		Goroutines: 12,
+		Cache:      &datastore.Stats{Items: 5, Hits: 3, Misses: 1},
+		Plugins: []metrics.PluginStats{
+			{Plugin: "docker", Methods: []metrics.MethodStats{
+				{Method: "List", Count: 4, Errors: 1, AverageLatency: 1234567 * time.Nanosecond, MaxLatency: 2 * time.Millisecond},
+			}},
+		},
+		Active: []metrics.Operation{
+			{Plugin: "docker", Method: "Stream", Entry: "/docker/containers/foo/log", Started: now.Add(-5 * time.Second)},
+		},
+	}
+	formatted := formatDaemonStats(stats, now)
+	assert.Contains(t, formatted, "Uptime: 01:30.00\n")
+	assert.Contains(t, formatted, "Memory: 3.0 MiB allocated, 512 B obtained from the OS, 4 GCs, 12 goroutines\n")
+	assert.Contains(t, formatted, "Cache: 5 items, 3 hits, 1 misses (75.0% hit ratio)\n")
+	assert.Regexp(t, `docker\s+List\s+4\s+1\s+1.23ms\s+2ms`, formatted)
+	assert.Regexp(t, `docker\s+Stream\s+00:05.00\s+/docker/containers/foo/log`, formatted)
+
+	formatted = formatDaemonStats(apitypes.Stats{}, now)
+	assert.Contains(t, formatted, "Plugin calls:\n  none\n")
+	assert.Contains(t, formatted, "Active operations:\n  none\n")
+	assert.NotContains(t, formatted, "Cache:")
+	assert.NotContains(t, formatted, "FUSE ops:")
+
+	buckets := make([]uint64, len(metrics.LatencyBuckets))
+	buckets[0], buckets[2] = 2, 1
+	formatted = formatDaemonStats(apitypes.Stats{FuseOps: []metrics.Histogram{
+		{Name: "FUSE Lookup", Count: 4, Errors: 1, AverageLatency: 2 * time.Second, MaxLatency: 8 * time.Second, Buckets: buckets, Slower: 1},
+	}}, now)
+	assert.Regexp(t, `OP\s+COUNT\s+ERRORS\s+AVG\s+MAX\s+<=1ms\s+<=5ms.*<=5s\s+>5s`, formatted)
+	assert.Regexp(t, `Lookup\s+4\s+1\s+2s\s+8s\s+2\s+0\s+1\s+0\s+0\s+0\s+0\s+0\s+1`, formatted)
+}
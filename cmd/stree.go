@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/xlab/treeprint"
 
@@ -19,9 +21,13 @@ hierarchy. Non-singleton types are bracketed with "[]".
 
 If a subdirectory is listed in 'stree' but not visible in your directory then you are
 likely lacking permissions to enumerate that type of resource. View the 'whistory' entry
-for listing the directory to see why it's not included.`,
+for listing the directory to see why it's not included.
+
+Use '--actions' to also display each type's supported actions, e.g. to see which entries
+can be renamed with 'mv' or deleted.`,
 		RunE: toRunE(streeMain),
 	}
+	streeCmd.Flags().BoolP("actions", "a", false, "Display each type's supported actions")
 	return streeCmd
 }
 
@@ -30,6 +36,10 @@ func streeMain(cmd *cobra.Command, args []string) exitCode {
 	if len(paths) == 0 {
 		paths = []string{"."}
 	}
+	showActions, err := cmd.Flags().GetBool("actions")
+	if err != nil {
+		panic(err.Error())
+	}
 	conn := cmdutil.NewClient()
 	schemas := make(map[string]*apitypes.EntrySchema)
 	for _, path := range paths {
@@ -46,18 +56,23 @@ func streeMain(cmd *cobra.Command, args []string) exitCode {
 	}
 	for path, schema := range schemas {
 		stree := treeprint.New()
-		fill(stree, schema, make(map[string]bool))
+		fill(stree, schema, showActions, make(map[string]bool))
 		stree.SetValue(path)
 		cmdutil.Print(stree.String())
 	}
 	return exitCode{0}
 }
 
-func fill(stree treeprint.Tree, schema *apitypes.EntrySchema, visited map[string]bool) treeprint.Tree {
+func fill(stree treeprint.Tree, schema *apitypes.EntrySchema, showActions bool, visited map[string]bool) treeprint.Tree {
 	value := schema.Label()
 	if !schema.Singleton() {
 		value = fmt.Sprintf("[%v]", value)
 	}
+	if showActions {
+		actions := append([]string{}, schema.Actions()...)
+		sort.Strings(actions)
+		value = fmt.Sprintf("%v (%v)", value, strings.Join(actions, ", "))
+	}
 	stree.SetValue(value)
 	if visited[schema.Path()] {
 		return stree
@@ -68,7 +83,7 @@ func fill(stree treeprint.Tree, schema *apitypes.EntrySchema, visited map[string
 		// set a stub value. Note that the value will be reset to the
 		// correct value in the recursive call, so this is OK.
 		subtree := stree.AddBranch("foo")
-		fill(subtree, child, visited)
+		fill(subtree, child, showActions, visited)
 	}
 	return stree
 }
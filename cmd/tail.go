@@ -18,13 +18,18 @@ import (
 
 func tailCommand() *cobra.Command {
 	tailCmd := &cobra.Command{
-		Use:   "tail -f [<file>...]",
+		Use:   "tail -f [--since <duration>] [<file>...]",
 		Short: "Displays new output of files or resources with the stream action",
 		Long: `Output any new updates to files and/or resources (that support the stream action). Mimics
-'tail -f' for remote logs, and calls '/usr/bin/tail' if '-f' is omitted.`,
+'tail -f' for remote logs, and calls '/usr/bin/tail' if '-f' is omitted.
+
+With --since, resources also output the updates made since that long ago (e.g.
+'--since 10m') before following new ones, if they support it. Local files ignore
+--since.`,
 		RunE: toRunE(tailMain),
 	}
 	tailCmd.Flags().BoolP("follow", "f", false, "Follow new output")
+	tailCmd.Flags().Duration("since", 0, "With -f, also output the updates made since this long ago")
 	return tailCmd
 }
 
@@ -79,8 +84,8 @@ func (w *lineWriter) Finish() {
 
 // Streams output via API to aggregator channel.
 // Returns nil if streaming's not supported on this path.
-func tailStream(conn client.Client, agg chan line, path string) io.Closer {
-	stream, err := conn.Stream(path)
+func tailStream(conn client.Client, agg chan line, path string, since time.Duration) io.Closer {
+	stream, err := conn.Stream(path, since)
 	if err != nil {
 		if errObj, ok := err.(*apitypes.ErrorObj); ok {
 			if errObj.Kind == apitypes.UnsupportedAction {
@@ -153,8 +158,16 @@ func tailMain(cmd *cobra.Command, args []string) exitCode {
 	if err != nil {
 		panic(err.Error())
 	}
+	since, err := cmd.Flags().GetDuration("since")
+	if err != nil {
+		panic(err.Error())
+	}
 
 	if !follow {
+		if since != 0 {
+			cmdutil.ErrPrintf("--since can only be used with -f\n")
+			return exitCode{1}
+		}
 		// Defer to `/usr/bin/tail`
 		comm := exec.Command("/usr/bin/tail", args...)
 		comm.Stdin = os.Stdin
@@ -180,7 +193,7 @@ func tailMain(cmd *cobra.Command, args []string) exitCode {
 
 	// Try streaming as a resource, then as a file if that failed for predictable reasons
 	for _, path := range args {
-		if closer := tailStream(conn, agg, path); closer != nil {
+		if closer := tailStream(conn, agg, path, since); closer != nil {
 			defer func() { errz.Log(closer.Close()) }()
 			continue
 		}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/puppetlabs/wash/api/auth"
+	"github.com/puppetlabs/wash/cmd/internal/config"
+	cmdutil "github.com/puppetlabs/wash/cmd/util"
+)
+
+func tokenCommand() *cobra.Command {
+	tokenCmd := &cobra.Command{
+		Use:   "token",
+		Short: "Creates, lists or revokes the API's bearer tokens",
+		Long: `Manages the bearer tokens that authenticate clients of the API's TCP listener
+(see the 'api' option in the config). Each token can only read from or write to
+the plugins that it's granted. Tokens are stored in the file at the
+'api.tokens_file' option, and the daemon picks up changes to it while it's
+running.`,
+	}
+	tokenCmd.PersistentFlags().String("config-file", config.DefaultFile(), "The config file to read the api.tokens_file option from")
+
+	createCmd := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Creates a token and prints its secret",
+		Long: `Creates a token that can read from the plugins in --read and write to the
+plugins in --write. Write access includes read access, and '*' grants access to
+every plugin, which is also required to access Wash's root. The token's secret
+is only printed once, so store it somewhere safe.`,
+		Args: cobra.ExactArgs(1),
+		RunE: toRunE(tokenCreateMain),
+	}
+	createCmd.Flags().StringSlice("read", nil, "The plugins that the token can read from")
+	createCmd.Flags().StringSlice("write", nil, "The plugins that the token can read from and write to")
+	tokenCmd.AddCommand(createCmd)
+
+	tokenCmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "Lists the tokens and their access",
+		Args:  cobra.NoArgs,
+		RunE:  toRunE(tokenListMain),
+	})
+	tokenCmd.AddCommand(&cobra.Command{
+		Use:   "revoke <name>...",
+		Short: "Revokes tokens",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  toRunE(tokenRevokeMain),
+	})
+	return tokenCmd
+}
+
+// tokensFile returns the path of the API's tokens file from the config.
+func tokensFile() string {
+	if file := viper.GetString("api.tokens_file"); file != "" {
+		return file
+	}
+	return config.DefaultTokensFile()
+}
+
+func tokenStore(cmd *cobra.Command) (*auth.Store, bool) {
+	configFile, err := cmd.Flags().GetString("config-file")
+	if err != nil {
+		panic(err.Error())
+	}
+	if err := config.ReadFrom(configFile); err != nil {
+		cmdutil.ErrPrintf("%v\n", err)
+		return nil, false
+	}
+	return auth.NewStore(tokensFile()), true
+}
+
+func tokenCreateMain(cmd *cobra.Command, args []string) exitCode {
+	read, err := cmd.Flags().GetStringSlice("read")
+	if err != nil {
+		panic(err.Error())
+	}
+	write, err := cmd.Flags().GetStringSlice("write")
+	if err != nil {
+		panic(err.Error())
+	}
+	store, ok := tokenStore(cmd)
+	if !ok {
+		return exitCode{1}
+	}
+
+	secret, err := store.Create(args[0], read, write)
+	if err != nil {
+		cmdutil.ErrPrintf("%v\n", err)
+		return exitCode{1}
+	}
+	cmdutil.Printf("%v\n", secret)
+	return exitCode{0}
+}
+
+func tokenListMain(cmd *cobra.Command, args []string) exitCode {
+	store, ok := tokenStore(cmd)
+	if !ok {
+		return exitCode{1}
+	}
+	tokens, err := store.List()
+	if err != nil {
+		cmdutil.ErrPrintf("%v\n", err)
+		return exitCode{1}
+	}
+
+	table := make([][]string, len(tokens))
+	for i, token := range tokens {
+		table[i] = []string{
+			token.Name,
+			strings.Join(token.Read, ","),
+			strings.Join(token.Write, ","),
+			token.Created.Local().Format(time.RFC3339),
+		}
+	}
+	cmdutil.Print(cmdutil.NewTableWithHeaders([]cmdutil.ColumnHeader{
+		{ShortName: "name", FullName: "NAME"},
+		{ShortName: "read", FullName: "READ"},
+		{ShortName: "write", FullName: "WRITE"},
+		{ShortName: "created", FullName: "CREATED"},
+	}, table).Format())
+	return exitCode{0}
+}
+
+func tokenRevokeMain(cmd *cobra.Command, args []string) exitCode {
+	store, ok := tokenStore(cmd)
+	if !ok {
+		return exitCode{1}
+	}
+	ec := 0
+	for _, name := range args {
+		if err := store.Revoke(name); err != nil {
+			ec = 1
+			cmdutil.ErrPrintf("%v\n", err)
+		} else {
+			cmdutil.Printf("%v has been revoked\n", name)
+		}
+	}
+	return exitCode{ec}
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	cmdutil "github.com/puppetlabs/wash/cmd/util"
+)
+
+func trashCommand() *cobra.Command {
+	trashCmd := &cobra.Command{
+		Use:   "trash [restore <id> [<id>] | purge [<id>]]",
+		Short: "Lists, restores or purges the entries in the trash",
+		Long: `When the trash is enabled (see the 'trash' option in the config), 'wash delete'
+moves entries to the trash instead of deleting them. Trashed entries are hidden
+from their parent's listing, and they're deleted once their retention period
+expires.
+
+With no arguments, lists the trashed entries with their IDs. 'restore' takes the
+entries with the given IDs out of the trash. 'purge' deletes the entries with the
+given IDs now, or every trashed entry if no IDs are given. The trash is kept in
+memory, so stopping the daemon restores its entries.`,
+		Args: cobra.ArbitraryArgs,
+		RunE: toRunE(trashMain),
+	}
+	return trashCmd
+}
+
+func trashMain(cmd *cobra.Command, args []string) exitCode {
+	conn := cmdutil.NewClient()
+
+	if len(args) == 0 {
+		entries, err := conn.Trash()
+		if err != nil {
+			cmdutil.ErrPrintf("%v\n", err)
+			return exitCode{1}
+		}
+		table := make([][]string, len(entries))
+		for i, entry := range entries {
+			table[i] = []string{entry.ID, entry.PurgeAt.Local().Format(time.RFC3339), entry.Path}
+		}
+		cmdutil.Print(cmdutil.NewTableWithHeaders([]cmdutil.ColumnHeader{
+			{ShortName: "id", FullName: "ID"},
+			{ShortName: "purge_at", FullName: "PURGE AT"},
+			{ShortName: "path", FullName: "PATH"},
+		}, table).Format())
+		return exitCode{0}
+	}
+
+	action, ids := args[0], args[1:]
+	switch action {
+	case "restore":
+		if len(ids) == 0 {
+			cmdutil.ErrPrintf("restore requires the IDs of the entries to restore\n")
+			return exitCode{1}
+		}
+		ec := 0
+		for _, id := range ids {
+			if err := conn.RestoreFromTrash(id); err != nil {
+				ec = 1
+				cmdutil.ErrPrintf("%v: %v\n", id, err)
+			} else {
+				cmdutil.Printf("%v has been restored\n", id)
+			}
+		}
+		return exitCode{ec}
+	case "purge":
+		if len(ids) == 0 {
+			entries, err := conn.Trash()
+			if err != nil {
+				cmdutil.ErrPrintf("%v\n", err)
+				return exitCode{1}
+			}
+			for _, entry := range entries {
+				ids = append(ids, entry.ID)
+			}
+		}
+		ec := 0
+		for _, id := range ids {
+			deleted, err := conn.PurgeFromTrash(id)
+			if err != nil {
+				ec = 1
+				cmdutil.ErrPrintf("%v: %v\n", id, err)
+			} else if deleted {
+				cmdutil.Printf("%v has been deleted\n", id)
+			} else {
+				cmdutil.Printf("%v has been marked for deletion and will eventually be deleted\n", id)
+			}
+		}
+		return exitCode{ec}
+	default:
+		cmdutil.ErrPrintf("unknown trash action %v, expected restore or purge\n", action)
+		return exitCode{1}
+	}
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	apitypes "github.com/puppetlabs/wash/api/types"
+	cmdutil "github.com/puppetlabs/wash/cmd/util"
+)
+
+// waitTimeoutExitCode is wait's exit code when the condition isn't satisfied
+// before the timeout. It matches timeout(1)'s exit code.
+const waitTimeoutExitCode = 124
+
+// readyQuery matches entries whose metadata reports a running state. It covers
+// the common shapes of state metadata, e.g. Docker containers (State or
+// State.Status), EC2 instances (State.Name) and Kubernetes pods (Status.Phase).
+var readyQuery = func() interface{} {
+	running := []interface{}{"string", []interface{}{"regex", "(?i)^running$"}}
+	key := func(k string, p interface{}) interface{} {
+		return []interface{}{"object", []interface{}{[]interface{}{"key", k}, p}}
+	}
+	meta := func(p interface{}) interface{} {
+		return []interface{}{"meta", p}
+	}
+	return []interface{}{"OR",
+		[]interface{}{"OR",
+			meta(key("state", running)),
+			meta(key("state", key("status", running))),
+		},
+		[]interface{}{"OR",
+			meta(key("state", key("name", running))),
+			meta(key("status", key("phase", running))),
+		},
+	}
+}()
+
+func waitCommand() *cobra.Command {
+	waitCmd := &cobra.Command{
+		Use:   "wait <path> [--exists | --ready | --query <rql>]",
+		Short: "Waits for the entry at the specified path to satisfy a condition",
+		Long: `Waits until the entry at the specified path satisfies a condition, then exits. The
+condition is one of:
+  --exists   the entry exists (the default)
+  --ready    the entry's metadata reports a running state
+  --query    the entry satisfies the given RQL query, specified as its JSON AST
+
+Exits with 0 when the condition is satisfied, 124 if the timeout elapsed first, or
+1 on any other error. This makes wait suitable for orchestration scripts, e.g.
+  wash wait --ready docker/containers/web && wash exec docker/containers/web ...`,
+		Args: cobra.ExactArgs(1),
+		RunE: toRunE(waitMain),
+	}
+	waitCmd.Flags().Bool("exists", false, "Wait for the entry to exist")
+	waitCmd.Flags().Bool("ready", false, "Wait for the entry's metadata to report a running state")
+	waitCmd.Flags().StringP("query", "q", "", "Wait for the entry to satisfy the given RQL query")
+	waitCmd.Flags().Duration("timeout", 5*time.Minute, "The maximum amount of time to wait")
+	waitCmd.Flags().Duration("interval", time.Second, "The amount of time between condition checks")
+	waitCmd.Flags().Bool("fullmeta", false, "Use the entry's full metadata in meta primaries")
+	return waitCmd
+}
+
+func waitMain(cmd *cobra.Command, args []string) exitCode {
+	path := args[0]
+	query, err := waitCondition(cmd)
+	if err != nil {
+		cmdutil.ErrPrintf("%v\n", err)
+		return exitCode{1}
+	}
+
+	var opts apitypes.WaitOptions
+	if opts.Timeout, err = cmd.Flags().GetDuration("timeout"); err != nil {
+		panic(err.Error())
+	}
+	if opts.Interval, err = cmd.Flags().GetDuration("interval"); err != nil {
+		panic(err.Error())
+	}
+	if opts.Fullmeta, err = cmd.Flags().GetBool("fullmeta"); err != nil {
+		panic(err.Error())
+	}
+
+	conn := cmdutil.NewClient()
+	result, err := conn.Wait(path, query, opts)
+	if err != nil {
+		cmdutil.ErrPrintf("%v: %v\n", path, err)
+		return exitCode{1}
+	}
+	if !result.Satisfied {
+		cmdutil.ErrPrintf("%v: timed out after %v waiting for the condition\n", path, opts.Timeout)
+		return exitCode{waitTimeoutExitCode}
+	}
+	return exitCode{0}
+}
+
+// waitCondition returns the RQL query corresponding to the specified condition.
+// A nil query waits for the entry to exist.
+func waitCondition(cmd *cobra.Command) (interface{}, error) {
+	exists, err := cmd.Flags().GetBool("exists")
+	if err != nil {
+		panic(err.Error())
+	}
+	ready, err := cmd.Flags().GetBool("ready")
+	if err != nil {
+		panic(err.Error())
+	}
+	rawQuery, err := cmd.Flags().GetString("query")
+	if err != nil {
+		panic(err.Error())
+	}
+
+	conditions := 0
+	for _, set := range []bool{exists, ready, rawQuery != ""} {
+		if set {
+			conditions++
+		}
+	}
+	if conditions > 1 {
+		return nil, fmt.Errorf("only one of --exists, --ready, or --query can be specified")
+	}
+
+	switch {
+	case ready:
+		return readyQuery, nil
+	case rawQuery != "":
+		var query interface{}
+		if err := json.Unmarshal([]byte(rawQuery), &query); err != nil {
+			return nil, fmt.Errorf("could not parse the query as JSON: %v", err)
+		}
+		return query, nil
+	default:
+		return nil, nil
+	}
+}
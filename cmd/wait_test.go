@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/puppetlabs/wash/api/rql/ast"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadyQueryIsValidRQL(t *testing.T) {
+	assert.NoError(t, ast.Query().Unmarshal(readyQuery))
+}
+
+func TestWaitCondition(t *testing.T) {
+	cmd := waitCommand()
+	query, err := waitCondition(cmd)
+	require.NoError(t, err)
+	assert.Nil(t, query)
+
+	cmd = waitCommand()
+	require.NoError(t, cmd.Flags().Set("query", `["name", ["glob", "foo*"]]`))
+	query, err = waitCondition(cmd)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"name", []interface{}{"glob", "foo*"}}, query)
+
+	cmd = waitCommand()
+	require.NoError(t, cmd.Flags().Set("query", `["name"`))
+	_, err = waitCondition(cmd)
+	assert.Regexp(t, "could not parse the query", err)
+
+	cmd = waitCommand()
+	require.NoError(t, cmd.Flags().Set("ready", "true"))
+	require.NoError(t, cmd.Flags().Set("exists", "true"))
+	_, err = waitCondition(cmd)
+	assert.Regexp(t, "only one of", err)
+}
@@ -2,9 +2,11 @@
 package datastore
 
 import (
+	"context"
 	"math"
 	"regexp"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	// TODO: Once https://github.com/patrickmn/go-cache/pull/75
@@ -14,6 +16,9 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+// noExpiration is the TTL of items that never expire.
+const noExpiration time.Duration = -1
+
 // Cache is an interface for a cache.
 type Cache interface {
 	GetOrUpdate(category, key string, ttl time.Duration, resetTTLOnHit bool, generateValue func() (interface{}, error)) (interface{}, error)
@@ -25,6 +30,10 @@ type Cache interface {
 // MemCache is an in-memory cache. It supports concurrent get/set, as well as the ability
 // to get-or-update cached data in a single transaction to avoid redundant update activity.
 type MemCache struct {
+	// hits and misses are updated atomically, so they're first to guarantee 64-bit
+	// alignment.
+	hits   uint64
+	misses uint64
 	// Use a write lock when deleting entries to avoid concurrent map read/write on the underlying
 	// map used by go-cache. This happened sometimes when evicting an entry at the same time that
 	// it's being used again. The scenario became more common when we started evicting cache items
@@ -34,6 +43,27 @@ type MemCache struct {
 	locks       sync.Map
 	hasEviction bool
 	limit       int
+	// pins maps the keys of pinned items to their pin. It's guarded by pinsMux.
+	pinsMux sync.Mutex
+	pins    map[string]*pin
+	// refreshes maps the keys of cached items to when they were generated. It's
+	// guarded by refreshesMux.
+	refreshesMux sync.Mutex
+	refreshes    map[string]refresh
+	onEvicted    func(string, interface{})
+}
+
+// refresh records when an item was generated and how long it took.
+type refresh struct {
+	start    time.Time
+	duration time.Duration
+}
+
+// pin tracks an item's active pins. remaining is the item's TTL when it was
+// pinned, so the time that it's pinned for doesn't count towards its TTL.
+type pin struct {
+	count     int
+	remaining time.Duration
 }
 
 var _ = Cache(&MemCache{})
@@ -42,10 +72,23 @@ var _ = Cache(&MemCache{})
 func NewMemCache() *MemCache {
 	// The TTLs will be passed-in individually in the GetOrUpdate
 	// method so we don't need to specify a default expiration
-	cache := cache.New(cache.NoExpiration, 1*time.Minute)
-	return &MemCache{
-		instance:    cache,
+	instance := cache.New(cache.NoExpiration, 1*time.Minute)
+	mem := &MemCache{
+		instance:    instance,
 		hasEviction: false,
+		pins:        make(map[string]*pin),
+		refreshes:   make(map[string]refresh),
+	}
+	instance.OnEvicted(mem.evicted)
+	return mem
+}
+
+func (cache *MemCache) evicted(key string, value interface{}) {
+	cache.refreshesMux.Lock()
+	delete(cache.refreshes, key)
+	cache.refreshesMux.Unlock()
+	if cache.onEvicted != nil {
+		cache.onEvicted(key, value)
 	}
 }
 
@@ -62,7 +105,7 @@ func (cache *MemCache) lockForKey(category, key string) *locksutil.LockEntry {
 // WithEvicted adds an eviction function that's called on each object as it's evicted to facilitate
 // cleanup.
 func (cache *MemCache) WithEvicted(f func(string, interface{})) *MemCache {
-	cache.instance.OnEvicted(f)
+	cache.onEvicted = f
 	cache.hasEviction = true
 	return cache
 }
@@ -74,6 +117,50 @@ func (cache *MemCache) Limit(n int) *MemCache {
 	return cache
 }
 
+// Stats describes a cache's usage.
+type Stats struct {
+	Items  int    `json:"items"`
+	Hits   uint64 `json:"hits"`
+	Misses uint64 `json:"misses"`
+}
+
+// Stats returns the cache's usage. Hits and misses are only counted by GetOrUpdate.
+func (cache *MemCache) Stats() Stats {
+	return Stats{
+		Items:  cache.instance.ItemCount(),
+		Hits:   atomic.LoadUint64(&cache.hits),
+		Misses: atomic.LoadUint64(&cache.misses),
+	}
+}
+
+// ItemInfo describes a cached item.
+type ItemInfo struct {
+	// Refreshed is when the item was generated, and RefreshDuration is how long
+	// it took.
+	Refreshed       time.Time
+	RefreshDuration time.Duration
+	// Expires is zero if the item doesn't expire.
+	Expires time.Time
+}
+
+// Info describes the item stored at the given key. It returns false if the item
+// isn't cached.
+func (cache *MemCache) Info(category, key string) (ItemInfo, bool) {
+	key = formKey(category, key)
+	_, expires, found := cache.instance.GetWithExpiration(key)
+	if !found {
+		return ItemInfo{}, false
+	}
+	cache.refreshesMux.Lock()
+	r, ok := cache.refreshes[key]
+	cache.refreshesMux.Unlock()
+	if !ok {
+		// The item was stored by something other than GetOrUpdate.
+		return ItemInfo{}, false
+	}
+	return ItemInfo{Refreshed: r.start, RefreshDuration: r.duration, Expires: expires}, true
+}
+
 func formKey(category, key string) string {
 	return category + "::" + key
 }
@@ -113,7 +200,8 @@ func (cache *MemCache) GetOrUpdate(category, key string, ttl time.Duration, rese
 	value, found := cache.instance.Get(key)
 	if found {
 		log.Tracef("Cache hit on %v", key)
-		if resetTTLOnHit {
+		atomic.AddUint64(&cache.hits, 1)
+		if resetTTLOnHit && !cache.resetPinnedTTL(key, ttl) {
 			// Update last-access time
 			cache.instance.Set(key, value, ttl)
 		}
@@ -125,6 +213,7 @@ func (cache *MemCache) GetOrUpdate(category, key string, ttl time.Duration, rese
 
 	// Cache misses should be rarer, so print them as debug messages.
 	log.Debugf("Cache miss on %v", key)
+	atomic.AddUint64(&cache.misses, 1)
 
 	if cache.limit > 0 && cache.instance.ItemCount() >= cache.limit {
 		// Retain write lock when deleting items to avoid concurrent map read/write.
@@ -135,7 +224,11 @@ func (cache *MemCache) GetOrUpdate(category, key string, ttl time.Duration, rese
 		cache.mux.RLock()
 	}
 
+	start := time.Now()
 	value, err := generateValue()
+	cache.refreshesMux.Lock()
+	cache.refreshes[key] = refresh{start: start, duration: time.Since(start)}
+	cache.refreshesMux.Unlock()
 	// Cache error responses as well. These are often authentication or availability failures
 	// and we don't want to continually query the API on failures.
 	if err != nil {
@@ -151,7 +244,12 @@ func (cache *MemCache) deleteClosestToExpiration() {
 	var candidate string
 	now := time.Now().UnixNano()
 	lowest := int64(math.MaxInt64)
+	cache.pinsMux.Lock()
+	defer cache.pinsMux.Unlock()
 	for k, it := range cache.instance.Items() {
+		if _, ok := cache.pins[k]; ok {
+			continue
+		}
 		remaining := it.Expiration - now
 		if remaining < lowest {
 			lowest = remaining
@@ -159,7 +257,9 @@ func (cache *MemCache) deleteClosestToExpiration() {
 		}
 	}
 	if candidate == "" {
-		panic("should have found a candidate")
+		// Every item's pinned, so the cache can't stay within its limit.
+		log.Debugf("Exceeding the cache limit of %v because all items are pinned", cache.limit)
+		return
 	}
 	cache.instance.Delete(candidate)
 }
@@ -181,6 +281,14 @@ func (cache *MemCache) Flush() {
 		cache.instance.DeleteExpired()
 	}
 	cache.instance.Flush()
+
+	cache.refreshesMux.Lock()
+	cache.refreshes = make(map[string]refresh)
+	cache.refreshesMux.Unlock()
+
+	cache.pinsMux.Lock()
+	cache.pins = make(map[string]*pin)
+	cache.pinsMux.Unlock()
 }
 
 // Delete removes entries from the cache that match the provided regexp.
@@ -195,6 +303,9 @@ func (cache *MemCache) Delete(matcher *regexp.Regexp) []string {
 		if matcher.MatchString(k) {
 			log.Debugf("Deleting cache entry %v", k)
 			cache.instance.Delete(k)
+			cache.pinsMux.Lock()
+			delete(cache.pins, k)
+			cache.pinsMux.Unlock()
 			deleted = append(deleted, k)
 		} else {
 			log.Debugf("Skipping %v", k)
@@ -202,3 +313,94 @@ func (cache *MemCache) Delete(matcher *regexp.Regexp) []string {
 	}
 	return deleted
 }
+
+// Pin pins the item stored at the given key until ctx is done. Pinned items
+// don't expire and aren't evicted, so state that's used by a long-running
+// operation (like an exec's SSH connection) isn't closed while the operation's
+// running. Pins are counted, and the item's TTL resumes when its last pin is
+// released. Explicitly deleting the item still removes it.
+//
+// Pin returns false if the item isn't cached or if ctx can't be cancelled,
+// since its pin would never be released.
+func (cache *MemCache) Pin(ctx context.Context, category, key string) bool {
+	if ctx.Done() == nil {
+		return false
+	}
+
+	cache.mux.RLock()
+	defer cache.mux.RUnlock()
+
+	l := cache.lockForKey(category, key)
+	l.Lock()
+	defer l.Unlock()
+
+	fullKey := formKey(category, key)
+	value, expiration, found := cache.instance.GetWithExpiration(fullKey)
+	if !found {
+		return false
+	}
+
+	cache.pinsMux.Lock()
+	p, ok := cache.pins[fullKey]
+	if !ok {
+		p = &pin{remaining: noExpiration}
+		if !expiration.IsZero() {
+			p.remaining = time.Until(expiration)
+		}
+		cache.pins[fullKey] = p
+		cache.instance.Set(fullKey, value, noExpiration)
+	}
+	p.count++
+	cache.pinsMux.Unlock()
+	log.Tracef("Pinned %v", fullKey)
+
+	go func() {
+		<-ctx.Done()
+		cache.unpin(category, key)
+	}()
+	return true
+}
+
+func (cache *MemCache) unpin(category, key string) {
+	cache.mux.RLock()
+	defer cache.mux.RUnlock()
+
+	l := cache.lockForKey(category, key)
+	l.Lock()
+	defer l.Unlock()
+
+	fullKey := formKey(category, key)
+	cache.pinsMux.Lock()
+	defer cache.pinsMux.Unlock()
+	p, ok := cache.pins[fullKey]
+	if !ok {
+		// The item was deleted while it was pinned
+		return
+	}
+	p.count--
+	if p.count > 0 {
+		return
+	}
+	delete(cache.pins, fullKey)
+	if value, found := cache.instance.Get(fullKey); found {
+		remaining := p.remaining
+		if remaining == 0 {
+			// Zero means the cache's default expiration, which is to never expire.
+			remaining = time.Nanosecond
+		}
+		cache.instance.Set(fullKey, value, remaining)
+	}
+	log.Tracef("Unpinned %v", fullKey)
+}
+
+// resetPinnedTTL resets the TTL that a pinned item resumes with once it's
+// unpinned. It returns false if the item isn't pinned.
+func (cache *MemCache) resetPinnedTTL(key string, ttl time.Duration) bool {
+	cache.pinsMux.Lock()
+	defer cache.pinsMux.Unlock()
+	p, ok := cache.pins[key]
+	if ok {
+		p.remaining = ttl
+	}
+	return ok
+}
@@ -1,6 +1,7 @@
 package datastore
 
 import (
+	"context"
 	"errors"
 	"regexp"
 	"testing"
@@ -143,6 +144,96 @@ func (suite *MemCacheTestSuite) TestDelete() {
 	suite.NotNil(suite.mem.instance.Get("another entry"))
 }
 
+func (suite *MemCacheTestSuite) TestStats() {
+	generate := func() (interface{}, error) { return "value", nil }
+	_, err := suite.mem.GetOrUpdate("cat", "an entry", time.Second, false, generate)
+	suite.NoError(err)
+	_, err = suite.mem.GetOrUpdate("cat", "an entry", time.Second, false, generate)
+	suite.NoError(err)
+	_, err = suite.mem.GetOrUpdate("cat", "another entry", time.Second, false, generate)
+	suite.NoError(err)
+
+	suite.Equal(Stats{Items: 2, Hits: 1, Misses: 2}, suite.mem.Stats())
+}
+
+func (suite *MemCacheTestSuite) TestInfo() {
+	_, ok := suite.mem.Info("cat", "an entry")
+	suite.False(ok)
+
+	start := time.Now()
+	_, err := suite.mem.GetOrUpdate("cat", "an entry", time.Minute, false, func() (interface{}, error) {
+		time.Sleep(10 * time.Millisecond)
+		return "value", nil
+	})
+	suite.NoError(err)
+	info, ok := suite.mem.Info("cat", "an entry")
+	if suite.True(ok) {
+		suite.False(info.Refreshed.Before(start))
+		suite.True(info.RefreshDuration >= 10*time.Millisecond)
+		suite.WithinDuration(start.Add(time.Minute), info.Expires, time.Second)
+	}
+
+	suite.mem.Delete(regexp.MustCompile("cat::an entry"))
+	_, ok = suite.mem.Info("cat", "an entry")
+	suite.False(ok)
+}
+
+func (suite *MemCacheTestSuite) TestPin() {
+	suite.thing.On("update").Return(anything, nil)
+	suite.validate(suite.mem.GetOrUpdate("cat", "an entry", 50*time.Millisecond, false, suite.update))
+
+	suite.False(suite.mem.Pin(context.Background(), "cat", "an entry"))
+	suite.False(suite.mem.Pin(context.Background(), "cat", "missing entry"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	otherCtx, otherCancel := context.WithCancel(context.Background())
+	suite.True(suite.mem.Pin(ctx, "cat", "an entry"))
+	suite.True(suite.mem.Pin(otherCtx, "cat", "an entry"))
+
+	// The entry doesn't expire while it's pinned
+	time.Sleep(100 * time.Millisecond)
+	suite.validate(suite.mem.GetOrUpdate("cat", "an entry", time.Second, false, suite.update))
+	suite.thing.AssertNumberOfCalls(suite.T(), "update", 1)
+
+	// The entry stays pinned until its last pin is released
+	cancel()
+	time.Sleep(10 * time.Millisecond)
+	_, expiration, ok := suite.mem.instance.GetWithExpiration("cat::an entry")
+	if suite.True(ok) {
+		suite.True(expiration.IsZero())
+	}
+
+	// The entry expires once it's unpinned
+	otherCancel()
+	time.Sleep(100 * time.Millisecond)
+	_, ok = suite.mem.instance.Get("cat::an entry")
+	suite.False(ok)
+}
+
+func (suite *MemCacheTestSuite) TestPinnedEntriesAreNotEvicted() {
+	suite.mem.Limit(2)
+	generate := func() (interface{}, error) { return "value", nil }
+	_, err := suite.mem.GetOrUpdate("cat", "pinned", time.Second, false, generate)
+	suite.NoError(err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	suite.True(suite.mem.Pin(ctx, "cat", "pinned"))
+
+	_, err = suite.mem.GetOrUpdate("cat", "an entry", time.Minute, false, generate)
+	suite.NoError(err)
+	_, err = suite.mem.GetOrUpdate("cat", "another entry", time.Minute, false, generate)
+	suite.NoError(err)
+	_, ok := suite.mem.instance.Get("cat::pinned")
+	suite.True(ok)
+	suite.Equal(2, suite.mem.instance.ItemCount())
+
+	// Every item's pinned, so the limit's exceeded
+	suite.True(suite.mem.Pin(ctx, "cat", "another entry"))
+	_, err = suite.mem.GetOrUpdate("cat", "a third entry", time.Minute, false, generate)
+	suite.NoError(err)
+	suite.Equal(3, suite.mem.instance.ItemCount())
+}
+
 func TestMemCache(t *testing.T) {
 	suite.Run(t, new(MemCacheTestSuite))
 }
@@ -0,0 +1,224 @@
+package extplugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// request is a JSON-RPC 2.0 request or notification from Wash.
+type request struct {
+	ID     *uint64         `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+type callParams struct {
+	Path  string   `json:"path"`
+	State string   `json:"state"`
+	Args  []string `json:"args"`
+	Input []byte   `json:"input"`
+}
+
+// notificationParams are the params of the input and cancel notifications.
+type notificationParams struct {
+	ID   uint64 `json:"id"`
+	Data []byte `json:"data"`
+	EOF  bool   `json:"eof"`
+}
+
+// daemon serves the jsonrpc protocol. Calls are handled concurrently.
+type daemon struct {
+	root      Root
+	writeMux  sync.Mutex
+	encoder   *json.Encoder
+	mux       sync.Mutex
+	config    map[string]interface{}
+	cancels   map[uint64]context.CancelFunc
+	stdins    map[uint64]*io.PipeWriter
+	callsDone sync.WaitGroup
+}
+
+func newDaemon(root Root, stdout io.Writer) *daemon {
+	return &daemon{
+		root:    root,
+		encoder: json.NewEncoder(stdout),
+		cancels: make(map[uint64]context.CancelFunc),
+		stdins:  make(map[uint64]*io.PipeWriter),
+	}
+}
+
+// send writes a message to Wash.
+func (d *daemon) send(msg map[string]interface{}) {
+	d.writeMux.Lock()
+	defer d.writeMux.Unlock()
+	msg["jsonrpc"] = "2.0"
+	// Errors mean that Wash is gone, in which case stdin's closed too.
+	_ = d.encoder.Encode(msg)
+}
+
+// serve handles Wash's messages until stdin's closed, then cancels the calls
+// that are still running.
+func (d *daemon) serve(stdin io.Reader) error {
+	decoder := json.NewDecoder(stdin)
+	var err error
+	for {
+		var req request
+		if err = decoder.Decode(&req); err != nil {
+			break
+		}
+		d.handle(req)
+	}
+
+	d.mux.Lock()
+	for _, cancel := range d.cancels {
+		cancel()
+	}
+	d.mux.Unlock()
+	d.callsDone.Wait()
+	if err == io.EOF {
+		return nil
+	}
+	return fmt.Errorf("could not decode a message: %v", err)
+}
+
+func (d *daemon) handle(req request) {
+	if req.ID == nil {
+		var params notificationParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return
+		}
+		d.mux.Lock()
+		cancel, hasCancel := d.cancels[params.ID]
+		stdin, hasStdin := d.stdins[params.ID]
+		if req.Method == "input" && params.EOF {
+			delete(d.stdins, params.ID)
+		}
+		d.mux.Unlock()
+		switch {
+		case req.Method == "cancel" && hasCancel:
+			cancel()
+		case req.Method == "input" && hasStdin:
+			if params.EOF {
+				stdin.Close()
+			} else {
+				// Exec reads its input as it arrives, so this doesn't block
+				// for long. The write fails once the call's finished.
+				_, _ = stdin.Write(params.Data)
+			}
+		}
+		return
+	}
+
+	id := *req.ID
+	var params callParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		d.reply(id, nil, fmt.Errorf("could not decode the params: %v", err))
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	var stdin io.Reader = bytes.NewReader(params.Input)
+	d.mux.Lock()
+	d.cancels[id] = cancel
+	if req.Method == "exec" {
+		r, w := io.Pipe()
+		d.stdins[id], stdin = w, r
+	}
+	d.mux.Unlock()
+
+	d.callsDone.Add(1)
+	go func() {
+		defer d.callsDone.Done()
+		defer func() {
+			d.mux.Lock()
+			delete(d.cancels, id)
+			if w, ok := d.stdins[id]; ok {
+				w.Close()
+				delete(d.stdins, id)
+			}
+			d.mux.Unlock()
+			cancel()
+		}()
+		d.call(ctx, id, req.Method, params, stdin)
+	}()
+}
+
+func (d *daemon) reply(id uint64, result interface{}, err error) {
+	if err != nil {
+		d.send(map[string]interface{}{"id": id, "error": map[string]interface{}{"code": 1, "message": err.Error()}})
+		return
+	}
+	d.send(map[string]interface{}{"id": id, "result": result})
+}
+
+// outputWriter sends the data that's written to it in output notifications.
+type outputWriter struct {
+	d      *daemon
+	id     uint64
+	stream string
+}
+
+func (w *outputWriter) Write(p []byte) (int, error) {
+	w.d.send(map[string]interface{}{
+		"method": "output",
+		"params": map[string]interface{}{"id": w.id, "stream": w.stream, "data": p},
+	})
+	return len(p), nil
+}
+
+func (d *daemon) call(ctx context.Context, id uint64, method string, params callParams, stdin io.Reader) {
+	if method == "init" {
+		var config string
+		if len(params.Args) > 0 {
+			config = params.Args[0]
+		}
+		var stdout bytes.Buffer
+		if err := initRoot(d.root, config, &stdout); err != nil {
+			d.reply(id, nil, err)
+			return
+		}
+		d.mux.Lock()
+		_ = json.Unmarshal([]byte(config), &d.config)
+		d.mux.Unlock()
+		d.reply(id, json.RawMessage(stdout.Bytes()), nil)
+		return
+	}
+
+	d.mux.Lock()
+	config := d.config
+	d.mux.Unlock()
+	if method == "stream" || method == "exec" {
+		stdout := &outputWriter{d: d, id: id, stream: "stdout"}
+		stderr := &outputWriter{d: d, id: id, stream: "stderr"}
+		exitCode, err := invoke(ctx, d.root, config, method, params.Path, params.State, params.Args, stdin, stdout, stderr)
+		switch {
+		case err != nil:
+			d.reply(id, nil, err)
+		case ctx.Err() != nil:
+			d.reply(id, nil, fmt.Errorf("the call was cancelled"))
+		case method == "exec":
+			d.reply(id, map[string]interface{}{"exitCode": exitCode}, nil)
+		default:
+			d.reply(id, nil, nil)
+		}
+		return
+	}
+
+	var stdout, stderr bytes.Buffer
+	if _, err := invoke(ctx, d.root, config, method, params.Path, params.State, params.Args, stdin, &stdout, &stderr); err != nil {
+		d.reply(id, nil, err)
+		return
+	}
+	switch {
+	case method == "read":
+		// Content can be binary, so it's base64-encoded.
+		d.reply(id, stdout.Bytes(), nil)
+	case stdout.Len() == 0:
+		d.reply(id, nil, nil)
+	default:
+		d.reply(id, json.RawMessage(stdout.Bytes()), nil)
+	}
+}
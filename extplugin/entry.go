@@ -0,0 +1,179 @@
+/*
+Package extplugin implements Wash's external plugin protocol so that external
+plugins can be written in Go. Its interfaces mirror the plugin package's, so
+an external plugin looks like a core plugin: each resource embeds EntryBase,
+and implements the interfaces of the methods that it supports. For example
+
+	type container struct {
+		extplugin.EntryBase
+	}
+
+	func (c *container) Schema() *extplugin.EntrySchema {
+		return extplugin.NewEntrySchema(c, "container")
+	}
+
+	func (c *container) Read(ctx context.Context) ([]byte, error) {
+		...
+	}
+
+The plugin's main function then calls Run with the plugin's root. The script
+supports both of the protocols that Wash uses to call external plugins: the
+default one, which forks the script for each method invocation, and the
+jsonrpc protocol, which launches the script once as a daemon.
+
+Wash identifies entries by their path, so the script finds the invoked entry
+by listing its ancestors. In the fork-per-call protocol, the root's Init is
+also called with the plugin's config on each invocation, since each
+invocation's a separate process.
+*/
+package extplugin
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// Entry is the interface that all of the plugin's entries must implement. To
+// do so, they should include the EntryBase type, and initialize it via
+// NewEntry.
+//
+// Schema returns the entry's schema. If the root's Schema returns nil, then
+// the plugin doesn't have a schema and none of its entries need one.
+type Entry interface {
+	Schema() *EntrySchema
+	eb() *EntryBase
+}
+
+// Parent is an entry with children.
+type Parent interface {
+	Entry
+	ChildSchemas() []*EntrySchema
+	List(context.Context) ([]Entry, error)
+}
+
+// Root is the plugin's root.
+type Root interface {
+	Parent
+	Init(map[string]interface{}) error
+}
+
+// Readable is an entry with content.
+type Readable interface {
+	Entry
+	Read(context.Context) ([]byte, error)
+}
+
+// BlockReadable is an entry whose content is read in blocks, like a large
+// file. An entry shouldn't implement both Readable and BlockReadable.
+type BlockReadable interface {
+	Entry
+	BlockRead(ctx context.Context, size int64, offset int64) ([]byte, error)
+}
+
+// Writable is an entry that can be written.
+type Writable interface {
+	Entry
+	Write(context.Context, []byte) error
+}
+
+// Streamable is an entry whose content can be streamed, like a log.
+type Streamable interface {
+	Entry
+	Stream(context.Context) (io.ReadCloser, error)
+}
+
+// ExecOptions are Exec's options. Stdin is nil if the command has no input.
+type ExecOptions struct {
+	Stdin   io.Reader
+	Stdout  io.Writer
+	Stderr  io.Writer
+	Tty     bool
+	Elevate bool
+}
+
+// Execable is an entry that can run commands. Exec returns the command's
+// exit code once it finishes.
+type Execable interface {
+	Entry
+	Exec(ctx context.Context, cmd string, args []string, opts ExecOptions) (int, error)
+}
+
+// HasMetadata is an entry whose metadata is fetched separately from its
+// partial metadata. Other entries' metadata is their partial metadata.
+type HasMetadata interface {
+	Entry
+	Metadata(context.Context) (plugin.JSONObject, error)
+}
+
+// Deletable is an entry that can be deleted. Delete returns whether the entry
+// was deleted or is being deleted asynchronously.
+type Deletable interface {
+	Entry
+	Delete(context.Context) (bool, error)
+}
+
+// Signalable is an entry that can be sent signals.
+type Signalable interface {
+	Entry
+	Signal(context.Context, string) error
+}
+
+// EntryBase implements Entry, making it easy to create new entries.
+type EntryBase struct {
+	name            string
+	attributes      plugin.EntryAttributes
+	partialMetadata plugin.JSONObject
+	slashReplacer   rune
+	ttls            map[string]time.Duration
+}
+
+// NewEntry creates a new entry named name.
+func NewEntry(name string) EntryBase {
+	return EntryBase{name: name}
+}
+
+func (e *EntryBase) eb() *EntryBase {
+	return e
+}
+
+// Name returns the entry's name.
+func (e *EntryBase) Name() string {
+	return e.name
+}
+
+// Attributes returns the entry's attributes.
+func (e *EntryBase) Attributes() *plugin.EntryAttributes {
+	return &e.attributes
+}
+
+// SetAttributes sets the entry's attributes.
+func (e *EntryBase) SetAttributes(attr plugin.EntryAttributes) *EntryBase {
+	e.attributes = attr
+	return e
+}
+
+// SetPartialMetadata sets the entry's partial metadata.
+func (e *EntryBase) SetPartialMetadata(obj plugin.JSONObject) *EntryBase {
+	e.partialMetadata = obj
+	return e
+}
+
+// SetSlashReplacer replaces the '/'es in the entry's cname with char instead of
+// percent-encoding them. See plugin.CName.
+func (e *EntryBase) SetSlashReplacer(char rune) *EntryBase {
+	e.slashReplacer = char
+	return e
+}
+
+// SetTTLOf sets how long Wash caches the result of the list, read or metadata
+// method. Wash rounds it to seconds.
+func (e *EntryBase) SetTTLOf(method string, ttl time.Duration) *EntryBase {
+	if e.ttls == nil {
+		e.ttls = make(map[string]time.Duration)
+	}
+	e.ttls[method] = ttl
+	return e
+}
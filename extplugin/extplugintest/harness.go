@@ -0,0 +1,278 @@
+/*
+Package extplugintest checks an external plugin's conformance to Wash's
+external plugin protocol. It loads the plugin's script the way that Wash does,
+then replays a golden transcript of schema, list, read, metadata and exec
+calls, and compares each call's result with the transcript's. A transcript's
+a JSON file like
+
+	{
+		"config": {"region": "us-west-1"},
+		"steps": [
+			{"method": "schema", "path": ""},
+			{"method": "list", "path": "instances"},
+			{"method": "exec", "path": "instances/foo", "args": ["uname"]}
+		]
+	}
+
+where each step's path is relative to the plugin's root. Running the tests
+with WASH_UPDATE_TRANSCRIPTS=1 set records each step's output in the
+transcript, so that subsequent runs compare against it.
+
+Plugins that are written with the extplugin package can be tested without
+building their script: the test binary serves the plugin when Main's called
+from TestMain, and Script returns a script that launches it.
+*/
+package extplugintest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/puppetlabs/wash/datastore"
+	"github.com/puppetlabs/wash/extplugin"
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/puppetlabs/wash/plugin/external"
+)
+
+// UpdateEnv is the environment variable that makes Run record the plugin's
+// output in the transcript instead of comparing them.
+const UpdateEnv = "WASH_UPDATE_TRANSCRIPTS"
+
+// scriptEnv is set when the test binary's launched by a Script.
+const scriptEnv = "WASH_EXTPLUGINTEST_SCRIPT"
+
+// Transcript is a golden transcript. Config is the plugin's config.
+type Transcript struct {
+	Config map[string]interface{} `json:"config,omitempty"`
+	Steps  []Step                 `json:"steps"`
+}
+
+// Step is a call in the transcript. Method is one of schema, list, read,
+// metadata or exec, and Path is the entry's path relative to the plugin's
+// root. Args are exec's command and its arguments, and Stdin is its input.
+// Output is the call's result.
+type Step struct {
+	Method string          `json:"method"`
+	Path   string          `json:"path"`
+	Args   []string        `json:"args,omitempty"`
+	Stdin  string          `json:"stdin,omitempty"`
+	Output json.RawMessage `json:"output,omitempty"`
+}
+
+// listedEntry is a child in a list step's output.
+type listedEntry struct {
+	Name    string   `json:"name"`
+	Actions []string `json:"actions"`
+}
+
+// execResult is an exec step's output.
+type execResult struct {
+	Stdout   string `json:"stdout"`
+	Stderr   string `json:"stderr"`
+	ExitCode int    `json:"exit_code"`
+}
+
+// Main serves root as an external plugin if the test binary was launched by
+// a Script. Otherwise it runs the tests. Packages that test extplugin roots
+// should call it from TestMain.
+func Main(m *testing.M, root extplugin.Root) {
+	if os.Getenv(scriptEnv) == "1" {
+		extplugin.Run(root)
+	}
+	os.Exit(m.Run())
+}
+
+// Script creates a plugin script named name that launches the test binary as
+// the plugin that's passed to Main. It returns the script's path and a
+// function that removes the script.
+func Script(t *testing.T, name string) (string, func()) {
+	bin, err := filepath.Abs(os.Args[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := ioutil.TempDir("", "extplugintest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, name)
+	script := fmt.Sprintf("#!/bin/sh\n%v=1 exec '%v' \"$@\"\n", scriptEnv, bin)
+	if err := ioutil.WriteFile(path, []byte(script), 0755); err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return path, func() { os.RemoveAll(dir) }
+}
+
+// Run loads the plugin that's described by spec and replays the transcript
+// at transcriptPath. Each step's a subtest that fails if its result differs
+// from the transcript's output.
+func Run(t *testing.T, spec external.PluginSpec, transcriptPath string) {
+	data, err := ioutil.ReadFile(transcriptPath)
+	if err != nil {
+		t.Fatalf("could not read the transcript: %v", err)
+	}
+	var transcript Transcript
+	if err := json.Unmarshal(data, &transcript); err != nil {
+		t.Fatalf("could not decode the transcript %v: %v", transcriptPath, err)
+	}
+
+	plugin.SetTestCache(datastore.NewMemCache())
+	defer plugin.UnsetTestCache()
+	root, err := spec.Load()
+	if err != nil {
+		t.Fatalf("could not load the plugin: %v", err)
+	}
+	registry := plugin.NewRegistry()
+	if err := registry.RegisterPlugin(root, transcript.Config); err != nil {
+		t.Fatalf("could not initialize the plugin: %v", err)
+	}
+
+	update := os.Getenv(UpdateEnv) == "1"
+	for i := range transcript.Steps {
+		step := &transcript.Steps[i]
+		t.Run(fmt.Sprintf("%v %v", step.Method, step.Path), func(t *testing.T) {
+			output, err := replay(registry, plugin.Name(root), *step)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if update {
+				step.Output = output
+				return
+			}
+			if step.Output == nil {
+				t.Fatalf("the step has no output. Run the tests with %v=1 to record it", UpdateEnv)
+			}
+			if !equalJSON(step.Output, output) {
+				t.Errorf("unexpected output\nexpected: %s\nactual:   %s", step.Output, output)
+			}
+		})
+	}
+
+	if update {
+		data, err := json.MarshalIndent(transcript, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(transcriptPath, append(data, '\n'), 0644); err != nil {
+			t.Fatalf("could not update the transcript: %v", err)
+		}
+	}
+}
+
+// replay calls the step's method and returns its JSON result.
+func replay(registry *plugin.Registry, pluginName string, step Step) (json.RawMessage, error) {
+	ctx := context.Background()
+	segments := []string{pluginName}
+	if path := strings.Trim(step.Path, "/"); path != "" {
+		segments = append(segments, strings.Split(path, "/")...)
+	}
+	entry, err := plugin.FindEntry(ctx, registry, segments)
+	if err != nil {
+		return nil, err
+	}
+	unsupported := fmt.Errorf("%v does not support %v", step.Path, step.Method)
+
+	var result interface{}
+	switch step.Method {
+	case "schema":
+		graph, err := plugin.SchemaGraph(entry)
+		if err != nil {
+			return nil, err
+		}
+		if graph == nil {
+			return json.RawMessage("null"), nil
+		}
+		return graph.ToJSON()
+	case "list":
+		parent, ok := entry.(plugin.Parent)
+		if !ok {
+			return nil, unsupported
+		}
+		children, err := plugin.List(ctx, parent)
+		if err != nil {
+			return nil, err
+		}
+		listed := []listedEntry{}
+		children.Range(func(cname string, child plugin.Entry) bool {
+			actions := plugin.SupportedActionsOf(child)
+			sort.Strings(actions)
+			listed = append(listed, listedEntry{Name: cname, Actions: actions})
+			return true
+		})
+		sort.Slice(listed, func(i, j int) bool {
+			return listed[i].Name < listed[j].Name
+		})
+		result = listed
+	case "read":
+		if !plugin.ReadAction().IsSupportedOn(entry) {
+			return nil, unsupported
+		}
+		size, err := plugin.Size(ctx, entry)
+		if err != nil {
+			return nil, err
+		}
+		content, err := plugin.Read(ctx, entry, int64(size), 0)
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		result = string(content)
+	case "metadata":
+		metadata, err := plugin.Metadata(ctx, entry)
+		if err != nil {
+			return nil, err
+		}
+		result = metadata
+	case "exec":
+		execable, ok := entry.(plugin.Execable)
+		if !ok || !plugin.ExecAction().IsSupportedOn(entry) {
+			return nil, unsupported
+		}
+		if len(step.Args) == 0 {
+			return nil, fmt.Errorf("an exec step needs a command")
+		}
+		opts := plugin.ExecOptions{}
+		if step.Stdin != "" {
+			opts.Stdin = strings.NewReader(step.Stdin)
+		}
+		cmd, err := plugin.Exec(ctx, execable, step.Args[0], step.Args[1:], opts)
+		if err != nil {
+			return nil, err
+		}
+		var res execResult
+		for chunk := range cmd.OutputCh() {
+			if chunk.Err != nil {
+				return nil, chunk.Err
+			}
+			if chunk.StreamID == plugin.Stdout {
+				res.Stdout += chunk.Data
+			} else {
+				res.Stderr += chunk.Data
+			}
+		}
+		if res.ExitCode, err = cmd.ExitCode(); err != nil {
+			return nil, err
+		}
+		result = res
+	default:
+		return nil, fmt.Errorf("unknown method %v, expected schema, list, read, metadata or exec", step.Method)
+	}
+	return json.Marshal(result)
+}
+
+func equalJSON(a, b json.RawMessage) bool {
+	var x, y interface{}
+	if json.Unmarshal(a, &x) != nil || json.Unmarshal(b, &y) != nil {
+		return false
+	}
+	expected, _ := json.Marshal(x)
+	actual, _ := json.Marshal(y)
+	return string(expected) == string(actual)
+}
@@ -0,0 +1,106 @@
+package extplugintest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/puppetlabs/wash/extplugin"
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/puppetlabs/wash/plugin/external"
+)
+
+type sampleRoot struct {
+	extplugin.EntryBase
+	greeting string
+}
+
+func (r *sampleRoot) Init(config map[string]interface{}) error {
+	r.EntryBase = extplugin.NewEntry("sample")
+	r.greeting = "hello"
+	if greeting, ok := config["greeting"].(string); ok {
+		r.greeting = greeting
+	}
+	return nil
+}
+
+func (r *sampleRoot) Schema() *extplugin.EntrySchema {
+	return extplugin.NewEntrySchema(r, "sample").SetDescription("A sample plugin.")
+}
+
+func (r *sampleRoot) ChildSchemas() []*extplugin.EntrySchema {
+	return []*extplugin.EntrySchema{(&vm{}).Schema()}
+}
+
+func (r *sampleRoot) List(context.Context) ([]extplugin.Entry, error) {
+	var vms []extplugin.Entry
+	for _, name := range []string{"web/1", "db"} {
+		v := &vm{EntryBase: extplugin.NewEntry(name), greeting: r.greeting}
+		v.SetPartialMetadata(plugin.JSONObject{"name": name})
+		vms = append(vms, v)
+	}
+	return vms, nil
+}
+
+type vm struct {
+	extplugin.EntryBase
+	greeting string
+}
+
+func (v *vm) Schema() *extplugin.EntrySchema {
+	return extplugin.NewEntrySchema(v, "vm").AddSignal("stop", "Stops the VM")
+}
+
+func (v *vm) Read(context.Context) ([]byte, error) {
+	return []byte(fmt.Sprintf("%v from %v\n", v.greeting, v.Name())), nil
+}
+
+func (v *vm) Metadata(context.Context) (plugin.JSONObject, error) {
+	return plugin.JSONObject{"name": v.Name(), "state": "running"}, nil
+}
+
+func (v *vm) Exec(ctx context.Context, cmd string, args []string, opts extplugin.ExecOptions) (int, error) {
+	switch cmd {
+	case "echo":
+		fmt.Fprintln(opts.Stdout, strings.Join(args, " "))
+		return 0, nil
+	case "cat":
+		if opts.Stdin == nil {
+			return 0, nil
+		}
+		_, err := io.Copy(opts.Stdout, opts.Stdin)
+		return 0, err
+	default:
+		fmt.Fprintf(opts.Stderr, "%v: command not found\n", cmd)
+		return 127, nil
+	}
+}
+
+func (v *vm) Signal(context.Context, string) error {
+	return nil
+}
+
+func TestMain(m *testing.M) {
+	Main(m, &sampleRoot{})
+}
+
+func TestRun(t *testing.T) {
+	script, cleanup := Script(t, "sample")
+	defer cleanup()
+	for _, protocol := range []string{"fork", "jsonrpc"} {
+		t.Run(protocol, func(t *testing.T) {
+			Run(t, external.PluginSpec{Script: script, Protocol: protocol}, "testdata/sample.json")
+		})
+	}
+}
+
+func TestEqualJSON(t *testing.T) {
+	if !equalJSON([]byte(`{"a": 1, "b": [2]}`), []byte(`{"b":[2],"a":1}`)) {
+		t.Error("expected objects with reordered keys to be equal")
+	}
+	if equalJSON([]byte(`[1, 2]`), []byte(`[2, 1]`)) {
+		t.Error("expected reordered arrays to differ")
+	}
+}
@@ -0,0 +1,126 @@
+package extplugin
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EntrySchema describes an entry. Create it with NewEntrySchema.
+type EntrySchema struct {
+	entry       Entry
+	typeID      string
+	label       string
+	description string
+	singleton   bool
+	signals     []signalSchema
+}
+
+type signalSchema struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// NewEntrySchema returns a new schema for the entry with the label. Like the
+// plugin package's schemas, the entry's type ID is its Go type, so e is
+// usually a zero value of the entry's type.
+func NewEntrySchema(e Entry, label string) *EntrySchema {
+	return &EntrySchema{entry: e, typeID: typeID(e), label: label}
+}
+
+// SetDescription sets the description that's shown by the docs command.
+func (s *EntrySchema) SetDescription(description string) *EntrySchema {
+	s.description = strings.Trim(description, "\n")
+	return s
+}
+
+// IsSingleton marks the entry as a singleton.
+func (s *EntrySchema) IsSingleton() *EntrySchema {
+	s.singleton = true
+	return s
+}
+
+// AddSignal adds a signal that the entry supports.
+func (s *EntrySchema) AddSignal(name string, description string) *EntrySchema {
+	s.signals = append(s.signals, signalSchema{Name: name, Description: description})
+	return s
+}
+
+func typeID(e Entry) string {
+	return strings.TrimPrefix(fmt.Sprintf("%T", e), "*")
+}
+
+// serializedSchema is an entry schema JSON object.
+type serializedSchema struct {
+	Label       string         `json:"label"`
+	Description string         `json:"description,omitempty"`
+	Singleton   bool           `json:"singleton,omitempty"`
+	Methods     []string       `json:"methods"`
+	Children    []string       `json:"children,omitempty"`
+	Signals     []signalSchema `json:"signals,omitempty"`
+}
+
+// schemaGraph returns the entry schema graph JSON object of the schema and its
+// descendants' schemas.
+func schemaGraph(s *EntrySchema) map[string]serializedSchema {
+	graph := make(map[string]serializedSchema)
+	var visit func(*EntrySchema)
+	visit = func(s *EntrySchema) {
+		if _, ok := graph[s.typeID]; ok {
+			return
+		}
+		node := serializedSchema{
+			Label:       s.label,
+			Description: s.description,
+			Singleton:   s.singleton,
+			Methods:     methodNames(s.entry, true),
+			Signals:     s.signals,
+		}
+		graph[s.typeID] = node
+		parent, ok := s.entry.(Parent)
+		if !ok {
+			return
+		}
+		for _, child := range parent.ChildSchemas() {
+			node.Children = append(node.Children, child.typeID)
+			visit(child)
+		}
+		graph[s.typeID] = node
+	}
+	visit(s)
+	return graph
+}
+
+// methodNames returns the names of the methods that the entry implements.
+func methodNames(e Entry, hasSchema bool) []string {
+	var methods []string
+	if hasSchema {
+		methods = append(methods, "schema")
+	}
+	if _, ok := e.(Parent); ok {
+		methods = append(methods, "list")
+	}
+	if _, ok := e.(Readable); ok {
+		methods = append(methods, "read")
+	} else if _, ok := e.(BlockReadable); ok {
+		methods = append(methods, "read")
+	}
+	if _, ok := e.(Writable); ok {
+		methods = append(methods, "write")
+	}
+	if _, ok := e.(Streamable); ok {
+		methods = append(methods, "stream")
+	}
+	if _, ok := e.(Execable); ok {
+		methods = append(methods, "exec")
+	}
+	if _, ok := e.(HasMetadata); ok {
+		methods = append(methods, "metadata")
+	}
+	if _, ok := e.(Deletable); ok {
+		methods = append(methods, "delete")
+	}
+	if _, ok := e.(Signalable); ok {
+		methods = append(methods, "signal")
+	}
+	return methods
+}
@@ -0,0 +1,390 @@
+package extplugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/puppetlabs/wash/plugin/external"
+)
+
+// Run serves the plugin's methods. The plugin's main function should call it
+// with the plugin's root, and it exits once the invoked method's done. It's
+// cancelled when the script's sent SIGTERM, which Wash does for cancelled
+// requests.
+func Run(root Root) {
+	ctx, cancel := context.WithCancel(context.Background())
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGTERM)
+	go func() {
+		<-sigCh
+		cancel()
+	}()
+	os.Exit(Serve(ctx, root, os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// Serve serves the method that args invokes, using the fork-per-call
+// protocol's calling conventions, and returns the script's exit code. If args
+// invokes the daemon method, then it serves the jsonrpc protocol until stdin
+// is closed. Run calls it with the script's arguments and stdio.
+func Serve(ctx context.Context, root Root, args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, "usage: <plugin_script> <method> <path> <state> <args...>")
+		return 1
+	}
+	method, args := args[0], args[1:]
+	switch method {
+	case external.DaemonMethod:
+		if err := newDaemon(root, stdout).serve(stdin); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		return 0
+	case "init":
+		var config string
+		if len(args) > 0 {
+			config = args[0]
+		}
+		if err := initRoot(root, config, stdout); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		return 0
+	}
+
+	if len(args) < 2 {
+		fmt.Fprintf(stderr, "%v must be called with the entry's path and state\n", method)
+		return 1
+	}
+	path, state, args := args[0], args[1], args[2:]
+	// Each invocation's a new process, so the root's initialized with the
+	// config that's stored in the entry's state.
+	var s entryState
+	_ = json.Unmarshal([]byte(state), &s)
+	if err := root.Init(s.Config); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	exitCode, err := invoke(ctx, root, s.Config, method, path, state, args, stdin, stdout, stderr)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	return exitCode
+}
+
+// entryState is the state that's included in each entry's JSON object. Names
+// are the names of the entry and its ancestors below the root, which locate it
+// even if Wash had to disambiguate its cname.
+type entryState struct {
+	Config map[string]interface{} `json:"config,omitempty"`
+	Names  []string               `json:"names"`
+}
+
+// initRoot initializes the root with the JSON config and writes its entry JSON
+// object. The root's schema graph is prefetched so that Wash doesn't invoke
+// schema on each entry.
+func initRoot(root Root, config string, stdout io.Writer) error {
+	var cfg map[string]interface{}
+	if config != "" {
+		if err := json.Unmarshal([]byte(config), &cfg); err != nil {
+			return fmt.Errorf("could not decode the config %v: %v", config, err)
+		}
+	}
+	if err := root.Init(cfg); err != nil {
+		return err
+	}
+	obj, err := serialize(root, cfg, nil)
+	if err != nil {
+		return err
+	}
+	// The root's name is the script's name, so it's omitted.
+	obj.Name = ""
+	if schema := root.Schema(); schema != nil {
+		obj.Methods[0] = []interface{}{"schema", schemaGraph(schema)}
+	}
+	return json.NewEncoder(stdout).Encode(handshake{
+		serializedEntry: obj,
+		ProtocolVersion: external.ProtocolVersion,
+		Capabilities:    capabilities,
+	})
+}
+
+// capabilities are the methods that Run serves. Wash ignores the methods that
+// aren't capabilities, so an entry's methods are only those that it implements.
+var capabilities = []string{
+	"list",
+	"read",
+	external.BlockReadCapability,
+	"write",
+	"metadata",
+	"schema",
+	"stream",
+	"exec",
+	"delete",
+	"signal",
+}
+
+// handshake is the root's entry JSON object, with the protocol version and
+// capabilities that the plugin was built for.
+type handshake struct {
+	serializedEntry
+	ProtocolVersion int      `json:"protocol_version"`
+	Capabilities    []string `json:"capabilities"`
+}
+
+// serializedEntry is an entry JSON object.
+type serializedEntry struct {
+	TypeID          string                 `json:"type_id,omitempty"`
+	Name            string                 `json:"name,omitempty"`
+	Methods         []interface{}          `json:"methods"`
+	SlashReplacer   string                 `json:"slash_replacer,omitempty"`
+	CacheTTLs       map[string]int64       `json:"cache_ttls,omitempty"`
+	Attributes      plugin.EntryAttributes `json:"attributes"`
+	PartialMetadata plugin.JSONObject      `json:"partial_metadata,omitempty"`
+	State           string                 `json:"state"`
+}
+
+// serialize returns the entry's JSON object. Names are the names of its
+// ancestors below the root.
+func serialize(e Entry, config map[string]interface{}, names []string) (serializedEntry, error) {
+	base := e.eb()
+	obj := serializedEntry{
+		Name:            base.name,
+		Attributes:      base.attributes,
+		PartialMetadata: base.partialMetadata,
+	}
+	hasSchema := e.Schema() != nil
+	if hasSchema {
+		obj.TypeID = typeID(e)
+	}
+	for _, method := range methodNames(e, hasSchema) {
+		if _, ok := e.(BlockReadable); ok && method == "read" {
+			obj.Methods = append(obj.Methods, []interface{}{"read", true})
+			continue
+		}
+		obj.Methods = append(obj.Methods, method)
+	}
+	if base.slashReplacer != 0 {
+		obj.SlashReplacer = string(base.slashReplacer)
+	}
+	for method, ttl := range base.ttls {
+		if obj.CacheTTLs == nil {
+			obj.CacheTTLs = make(map[string]int64)
+		}
+		obj.CacheTTLs[method] = int64(ttl.Seconds())
+	}
+	state, err := json.Marshal(entryState{Config: config, Names: names})
+	if err != nil {
+		return obj, err
+	}
+	obj.State = string(state)
+	return obj, nil
+}
+
+// find returns the entry that's identified by the path and state, and the
+// names of it and its ancestors below the root. The state's names are used if
+// they're present. Otherwise the path's cnames are used.
+func find(ctx context.Context, root Root, path string, state string) (Entry, []string, error) {
+	var s entryState
+	byName := json.Unmarshal([]byte(state), &s) == nil && s.Names != nil
+	segments := s.Names
+	if !byName {
+		// The path's /<plugin>/<cname>/...
+		segments = strings.Split(strings.Trim(path, "/"), "/")[1:]
+	}
+
+	var cur Entry = root
+	for i, segment := range segments {
+		parent, ok := cur.(Parent)
+		if !ok {
+			return nil, nil, fmt.Errorf("%v is not a parent", strings.Join(segments[:i], "/"))
+		}
+		children, err := parent.List(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		cur = nil
+		for _, child := range children {
+			if segment == child.eb().name || (!byName && segment == cname(child)) {
+				cur = child
+				break
+			}
+		}
+		if cur == nil {
+			return nil, nil, fmt.Errorf("%v does not exist", path)
+		}
+		segments[i] = cur.eb().name
+	}
+	return cur, segments, nil
+}
+
+func cname(e Entry) string {
+	return plugin.EscapeName(e.eb().name, e.eb().slashReplacer)
+}
+
+// invoke invokes the method on the entry, writing its result to stdout. It
+// returns the exit code of exec's command, and 0 for the other methods.
+func invoke(
+	ctx context.Context,
+	root Root,
+	config map[string]interface{},
+	method string,
+	path string,
+	state string,
+	args []string,
+	stdin io.Reader,
+	stdout io.Writer,
+	stderr io.Writer,
+) (int, error) {
+	entry, names, err := find(ctx, root, path, state)
+	if err != nil {
+		return 0, err
+	}
+	unsupported := fmt.Errorf("%v does not support %v", path, method)
+	writeJSON := func(v interface{}) (int, error) {
+		return 0, json.NewEncoder(stdout).Encode(v)
+	}
+
+	switch method {
+	case "list":
+		parent, ok := entry.(Parent)
+		if !ok {
+			return 0, unsupported
+		}
+		children, err := parent.List(ctx)
+		if err != nil {
+			return 0, err
+		}
+		objs := make([]serializedEntry, len(children))
+		for i, child := range children {
+			if objs[i], err = serialize(child, config, append(names[:len(names):len(names)], child.eb().name)); err != nil {
+				return 0, err
+			}
+		}
+		return writeJSON(objs)
+	case "read":
+		var content []byte
+		if len(args) == 2 {
+			e, ok := entry.(BlockReadable)
+			if !ok {
+				return 0, unsupported
+			}
+			size, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid size %v: %v", args[0], err)
+			}
+			offset, err := strconv.ParseInt(args[1], 10, 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid offset %v: %v", args[1], err)
+			}
+			content, err = e.BlockRead(ctx, size, offset)
+		} else {
+			e, ok := entry.(Readable)
+			if !ok {
+				return 0, unsupported
+			}
+			content, err = e.Read(ctx)
+		}
+		if err != nil {
+			return 0, err
+		}
+		_, err = stdout.Write(content)
+		return 0, err
+	case "write":
+		e, ok := entry.(Writable)
+		if !ok {
+			return 0, unsupported
+		}
+		data, err := ioutil.ReadAll(stdin)
+		if err != nil {
+			return 0, err
+		}
+		return 0, e.Write(ctx, data)
+	case "metadata":
+		e, ok := entry.(HasMetadata)
+		if !ok {
+			return 0, unsupported
+		}
+		metadata, err := e.Metadata(ctx)
+		if err != nil {
+			return 0, err
+		}
+		return writeJSON(metadata)
+	case "schema":
+		schema := entry.Schema()
+		if schema == nil {
+			return 0, unsupported
+		}
+		return writeJSON(schemaGraph(schema))
+	case "stream":
+		e, ok := entry.(Streamable)
+		if !ok {
+			return 0, unsupported
+		}
+		rdr, err := e.Stream(ctx)
+		if err != nil {
+			return 0, err
+		}
+		defer rdr.Close()
+		if _, err := io.WriteString(stdout, "200\n"); err != nil {
+			return 0, err
+		}
+		go func() {
+			// Stop streaming once the invocation's cancelled.
+			<-ctx.Done()
+			rdr.Close()
+		}()
+		if _, err := io.Copy(stdout, rdr); err != nil && ctx.Err() == nil {
+			return 0, err
+		}
+		return 0, nil
+	case "exec":
+		e, ok := entry.(Execable)
+		if !ok {
+			return 0, unsupported
+		}
+		if len(args) < 2 {
+			return 0, fmt.Errorf("exec must be called with the options and the command")
+		}
+		var opts struct {
+			Tty     bool `json:"tty"`
+			Elevate bool `json:"elevate"`
+			Stdin   bool `json:"stdin"`
+		}
+		if err := json.Unmarshal([]byte(args[0]), &opts); err != nil {
+			return 0, fmt.Errorf("could not decode the exec options %v: %v", args[0], err)
+		}
+		execOpts := ExecOptions{Stdout: stdout, Stderr: stderr, Tty: opts.Tty, Elevate: opts.Elevate}
+		if opts.Stdin {
+			execOpts.Stdin = stdin
+		}
+		return e.Exec(ctx, args[1], args[2:], execOpts)
+	case "delete":
+		e, ok := entry.(Deletable)
+		if !ok {
+			return 0, unsupported
+		}
+		deleted, err := e.Delete(ctx)
+		if err != nil {
+			return 0, err
+		}
+		return writeJSON(deleted)
+	case "signal":
+		e, ok := entry.(Signalable)
+		if !ok || len(args) == 0 {
+			return 0, unsupported
+		}
+		return 0, e.Signal(ctx, args[0])
+	default:
+		return 0, fmt.Errorf("unknown method %v", method)
+	}
+}
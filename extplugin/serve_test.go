@@ -0,0 +1,95 @@
+package extplugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testRoot struct {
+	EntryBase
+	config map[string]interface{}
+}
+
+func (r *testRoot) Init(config map[string]interface{}) error {
+	r.EntryBase = NewEntry("test")
+	r.config = config
+	return nil
+}
+
+func (r *testRoot) Schema() *EntrySchema {
+	return nil
+}
+
+func (r *testRoot) ChildSchemas() []*EntrySchema {
+	return nil
+}
+
+func (r *testRoot) List(context.Context) ([]Entry, error) {
+	f := &testFile{EntryBase: NewEntry("a/b")}
+	f.SetTTLOf("read", 0)
+	return []Entry{f}, nil
+}
+
+type testFile struct {
+	EntryBase
+}
+
+func (f *testFile) Schema() *EntrySchema {
+	return nil
+}
+
+func (f *testFile) Read(context.Context) ([]byte, error) {
+	return []byte("content of " + f.Name()), nil
+}
+
+func serve(t *testing.T, root Root, args ...string) string {
+	var stdout, stderr bytes.Buffer
+	exitCode := Serve(context.Background(), root, args, strings.NewReader(""), &stdout, &stderr)
+	require.Equal(t, 0, exitCode, stderr.String())
+	return stdout.String()
+}
+
+func TestServeInit(t *testing.T) {
+	root := &testRoot{}
+	var obj map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(serve(t, root, "init", `{"key":"value"}`)), &obj))
+	assert.Equal(t, map[string]interface{}{"key": "value"}, root.config)
+	assert.Equal(t, []interface{}{"list"}, obj["methods"])
+	assert.NotContains(t, obj, "name")
+	assert.Equal(t, `{"config":{"key":"value"},"names":null}`, obj["state"])
+}
+
+func TestServeList(t *testing.T) {
+	var objs []map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(serve(t, &testRoot{}, "list", "/test", "{}")), &objs))
+	require.Len(t, objs, 1)
+	assert.Equal(t, "a/b", objs[0]["name"])
+	assert.Equal(t, []interface{}{"read"}, objs[0]["methods"])
+	assert.Equal(t, map[string]interface{}{"read": 0.0}, objs[0]["cache_ttls"])
+	assert.Equal(t, `{"names":["a/b"]}`, objs[0]["state"])
+}
+
+func TestServeRead(t *testing.T) {
+	// The entry's found by its name in the state, or by its cname in the
+	// path if there's no state.
+	assert.Equal(t, "content of a/b", serve(t, &testRoot{}, "read", "/test/a%2Fb", `{"names":["a/b"]}`))
+	assert.Equal(t, "content of a/b", serve(t, &testRoot{}, "read", "/test/a%2Fb", ""))
+}
+
+func TestServeErrors(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	exitCode := Serve(context.Background(), &testRoot{}, []string{"list", "/test/a%2Fb", ""}, strings.NewReader(""), &stdout, &stderr)
+	assert.Equal(t, 1, exitCode)
+	assert.Equal(t, "/test/a%2Fb does not support list\n", stderr.String())
+
+	stderr.Reset()
+	exitCode = Serve(context.Background(), &testRoot{}, []string{"read", "/test/c", ""}, strings.NewReader(""), &stdout, &stderr)
+	assert.Equal(t, 1, exitCode)
+	assert.Equal(t, "/test/c does not exist\n", stderr.String())
+}
@@ -1,11 +1,11 @@
-// Package fuse adapts wash plugin types to a FUSE filesystem.
+//go:build !windows
+// +build !windows
+
 package fuse
 
 import (
 	"context"
 	"os"
-	"os/user"
-	"strconv"
 	"strings"
 	"time"
 
@@ -17,8 +17,6 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
-var startTime = time.Now()
-
 // Root represents the root of the FUSE filesystem
 type Root struct {
 	registry *plugin.Registry
@@ -33,27 +31,6 @@ func (r *Root) Root() (fs.Node, error) {
 	return newDir(nil, r.registry), nil
 }
 
-func getIDs() (uint32, uint32) {
-	me, err := user.Current()
-	if err != nil {
-		log.Infof("Unable to fetch user: %v", err)
-		return 0, 0
-	}
-	uid, err := strconv.ParseUint(me.Uid, 10, 32)
-	if err != nil {
-		log.Infof("Unable to parse uid: %v", err)
-		return 0, 0
-	}
-	gid, err := strconv.ParseUint(me.Gid, 10, 32)
-	if err != nil {
-		log.Infof("Unable to parse gid: %v", err)
-		return 0, 0
-	}
-	return uint32(uid), uint32(gid)
-}
-
-var uid, gid = getIDs()
-
 type fuseNode struct {
 	ftype  string
 	parent *dir
@@ -73,9 +50,9 @@ func (f *fuseNode) String() string {
 }
 
 // Applies attributes where non-default, and sets defaults otherwise.
-func applyAttr(a *fuse.Attr, attr plugin.EntryAttributes, defaultMode os.FileMode) {
-	// Setting a.Valid to 1 second avoids frequent Attr calls.
-	a.Valid = 1 * time.Second
+func applyAttr(a *fuse.Attr, entry plugin.Entry, defaultMode os.FileMode) {
+	attr := plugin.Attributes(entry)
+	a.Valid = cacheOptionsFor(entry).AttrTimeout
 
 	// TODO: tie this to actual hard links in plugins
 	a.Nlink = 1
@@ -112,8 +89,16 @@ func applyAttr(a *fuse.Attr, attr plugin.EntryAttributes, defaultMode os.FileMod
 		a.Crtime = attr.Crtime()
 	}
 	a.BlockSize = 4096
+	// Entries are owned by the user running Wash unless the plugin knows their
+	// real owner, like a file on a remote filesystem.
 	a.Uid = uid
+	if attr.HasUID() {
+		a.Uid = attr.UID()
+	}
 	a.Gid = gid
+	if attr.HasGID() {
+		a.Gid = attr.GID()
+	}
 }
 
 // Re-discovers the source ancestor of the current node to get fresh data. It returns that ancestor
@@ -177,6 +162,9 @@ func ServeFuseFS(
 			},
 		}
 		server := fs.New(fuseConn, serverConfig)
+		watchCtx, stopWatches := context.WithCancel(context.Background())
+		defer stopWatches()
+		watches.start(watchCtx, server)
 		root := newRoot(filesys)
 		if err := server.Serve(&root); err != nil {
 			log.Warnf("FUSE: fs.Serve errored with: %v", err)
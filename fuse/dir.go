@@ -1,9 +1,13 @@
+//go:build !windows
+// +build !windows
+
 package fuse
 
 import (
 	"context"
 	"os"
 	"syscall"
+	"time"
 
 	"bazil.org/fuse"
 	"bazil.org/fuse/fs"
@@ -21,6 +25,11 @@ type dir struct {
 var _ fs.Node = (*dir)(nil)
 var _ = fs.NodeRequestLookuper(&dir{})
 var _ = fs.HandleReadDirAller(&dir{})
+var _ = fs.NodeCreater(&dir{})
+var _ = fs.NodeMkdirer(&dir{})
+var _ = fs.NodeRemover(&dir{})
+var _ = fs.NodeRenamer(&dir{})
+var _ = fs.NodeForgetter(&dir{})
 
 func newDir(p *dir, e plugin.Parent) *dir {
 	return &dir{newFuseNode("d", p, e)}
@@ -43,7 +52,9 @@ func (d *dir) children(ctx context.Context) (*plugin.EntryMap, error) {
 }
 
 // Lookup searches a directory for children.
-func (d *dir) Lookup(ctx context.Context, req *fuse.LookupRequest, resp *fuse.LookupResponse) (fs.Node, error) {
+func (d *dir) Lookup(ctx context.Context, req *fuse.LookupRequest, resp *fuse.LookupResponse) (node fs.Node, err error) {
+	defer recordOp(ctx, d, "Lookup", time.Now(), &err)
+	defer recoverPanic(ctx, d, "Lookup", &err)
 	// Find is only occasionally useful and happens a lot. Log it to debug like other activity, but
 	// leave it out of activity because it introduces history entries for miscellaneous shell commands.
 	log.Debugf("FUSE: Find %v in %v", req.Name, d)
@@ -60,6 +71,12 @@ func (d *dir) Lookup(ctx context.Context, req *fuse.LookupRequest, resp *fuse.Lo
 		log.Debugf("FUSE: %v not found in %v", req.Name, d)
 		return nil, syscall.ENOENT
 	}
+	resp.EntryValid = cacheOptionsFor(entry).EntryTimeout
+
+	if link, ok := entry.(*plugin.Symlink); ok {
+		log.Debugf("FUSE: Found symlink %v/%v", d, cname)
+		return newSymlink(d, link), nil
+	}
 
 	if plugin.ListAction().IsSupportedOn(entry) {
 		childdir := newDir(d, entry.(plugin.Parent))
@@ -72,7 +89,9 @@ func (d *dir) Lookup(ctx context.Context, req *fuse.LookupRequest, resp *fuse.Lo
 }
 
 // ReadDirAll lists all children of the directory.
-func (d *dir) ReadDirAll(ctx context.Context) ([]fuse.Dirent, error) {
+func (d *dir) ReadDirAll(ctx context.Context) (dirents []fuse.Dirent, err error) {
+	defer recordOp(ctx, d, "List", time.Now(), &err)
+	defer recoverPanic(ctx, d, "List", &err)
 	activity.Record(ctx, "FUSE: List %v", d)
 
 	entries, err := d.children(ctx)
@@ -80,12 +99,15 @@ func (d *dir) ReadDirAll(ctx context.Context) ([]fuse.Dirent, error) {
 		activity.Warnf(ctx, "FUSE: List %v errored: %v", d, err)
 		return nil, err
 	}
+	watches.watch(d, d.entry)
 
 	res := make([]fuse.Dirent, 0, entries.Len())
 	entries.Range(func(cname string, entry plugin.Entry) bool {
 		var de fuse.Dirent
 		de.Name = cname
-		if plugin.ListAction().IsSupportedOn(entry) {
+		if _, ok := entry.(*plugin.Symlink); ok {
+			de.Type = fuse.DT_Link
+		} else if plugin.ListAction().IsSupportedOn(entry) {
 			de.Type = fuse.DT_Dir
 		} else {
 			de.Type = fuse.DT_File
@@ -97,7 +119,166 @@ func (d *dir) ReadDirAll(ctx context.Context) ([]fuse.Dirent, error) {
 	return res, nil
 }
 
-func (d *dir) Attr(ctx context.Context, a *fuse.Attr) error {
+// Create creates a new file in the directory. The file's entry only exists in
+// the plugin's API once the file's flushed.
+func (d *dir) Create(ctx context.Context, req *fuse.CreateRequest, resp *fuse.CreateResponse) (node fs.Node, handle fs.Handle, err error) {
+	defer recordOp(ctx, d, "Create", time.Now(), &err)
+	defer recoverPanic(ctx, d, "Create", &err)
+	activity.Record(ctx, "FUSE: Create %v in %v", req.Name, d)
+
+	entry, err := d.refind(ctx)
+	if err != nil {
+		activity.Warnf(ctx, "FUSE: Create %v in %v errored: %v", req.Name, d, err)
+		return nil, nil, err
+	}
+	parent, ok := entry.(plugin.Creatable)
+	if !ok {
+		// The directory's mode doesn't include write permissions.
+		activity.Warnf(ctx, "FUSE: Create denied in read-only %v", d)
+		return nil, nil, syscall.EACCES
+	}
+
+	child, err := plugin.Create(ctx, parent, req.Name, req.Mode.Perm())
+	if err != nil {
+		activity.Warnf(ctx, "FUSE: Create %v in %v errored: %v", req.Name, d, err)
+		return nil, nil, err
+	}
+	f := newFile(d, child)
+	f.created = true
+	if attr := plugin.Attributes(child); attr.HasSize() {
+		f.readSize = attr.Size()
+	}
+	return f, f, nil
+}
+
+// Mkdir creates a new directory in the directory.
+func (d *dir) Mkdir(ctx context.Context, req *fuse.MkdirRequest) (node fs.Node, err error) {
+	defer recordOp(ctx, d, "Mkdir", time.Now(), &err)
+	defer recoverPanic(ctx, d, "Mkdir", &err)
+	activity.Record(ctx, "FUSE: Mkdir %v in %v", req.Name, d)
+
+	entry, err := d.refind(ctx)
+	if err != nil {
+		activity.Warnf(ctx, "FUSE: Mkdir %v in %v errored: %v", req.Name, d, err)
+		return nil, err
+	}
+	parent, ok := entry.(plugin.DirCreatable)
+	if !ok {
+		activity.Warnf(ctx, "FUSE: Mkdir denied in read-only %v", d)
+		return nil, syscall.EACCES
+	}
+
+	child, err := plugin.CreateDir(ctx, parent, req.Name)
+	if err != nil {
+		activity.Warnf(ctx, "FUSE: Mkdir %v in %v errored: %v", req.Name, d, err)
+		return nil, err
+	}
+	return newDir(d, child), nil
+}
+
+// Remove deletes a child of the directory, i.e. it implements unlink and rmdir.
+// Only Deletable entries can be removed. Directories must be empty so that a
+// recursive rm can't delete things like VMs, which are directories that can't
+// be emptied. Use 'wash delete' for those.
+func (d *dir) Remove(ctx context.Context, req *fuse.RemoveRequest) (err error) {
+	defer recordOp(ctx, d, "Remove", time.Now(), &err)
+	defer recoverPanic(ctx, d, "Remove", &err)
+	activity.Record(ctx, "FUSE: Remove %v in %v", req.Name, d)
+
+	entries, err := d.children(ctx)
+	if err != nil {
+		activity.Warnf(ctx, "FUSE: Remove %v in %v errored: %v", req.Name, d, err)
+		return err
+	}
+	entry, ok := entries.Load(req.Name)
+	if !ok {
+		return syscall.ENOENT
+	}
+
+	isDir := plugin.ListAction().IsSupportedOn(entry)
+	if req.Dir && !isDir {
+		return syscall.ENOTDIR
+	} else if !req.Dir && isDir {
+		return syscall.EISDIR
+	}
+	if !plugin.DeleteAction().IsSupportedOn(entry) {
+		activity.Warnf(ctx, "FUSE: Remove unsupported for %v in %v", req.Name, d)
+		return syscall.ENOTSUP
+	}
+	if isDir {
+		children, err := plugin.ListWithAnalytics(ctx, entry.(plugin.Parent))
+		if err != nil {
+			activity.Warnf(ctx, "FUSE: Remove %v in %v errored: %v", req.Name, d, err)
+			return err
+		}
+		if children.Len() > 0 {
+			return syscall.ENOTEMPTY
+		}
+	}
+
+	deleted, err := plugin.DeleteWithAnalytics(ctx, entry.(plugin.Deletable))
+	if err != nil {
+		activity.Warnf(ctx, "FUSE: Remove %v in %v errored: %v", req.Name, d, err)
+		return err
+	}
+	activity.Record(ctx, "FUSE: Removed %v in %v: %v", req.Name, d, deleted)
+	return nil
+}
+
+// Rename renames or moves a child of the directory, i.e. it implements mv. Only
+// Renamable entries can be renamed. Moving an entry to another plugin returns
+// EXDEV so that mv falls back to copying and deleting it.
+func (d *dir) Rename(ctx context.Context, req *fuse.RenameRequest, newDir fs.Node) (err error) {
+	defer recordOp(ctx, d, "Rename", time.Now(), &err)
+	defer recoverPanic(ctx, d, "Rename", &err)
+	activity.Record(ctx, "FUSE: Rename %v in %v to %v in %v", req.OldName, d, req.NewName, newDir)
+
+	dst, ok := newDir.(*dir)
+	if !ok {
+		return syscall.EXDEV
+	}
+	entries, err := d.children(ctx)
+	if err != nil {
+		activity.Warnf(ctx, "FUSE: Rename %v in %v errored: %v", req.OldName, d, err)
+		return err
+	}
+	entry, ok := entries.Load(req.OldName)
+	if !ok {
+		return syscall.ENOENT
+	}
+	newParent, err := dst.refind(ctx)
+	if err != nil {
+		activity.Warnf(ctx, "FUSE: Rename %v in %v errored: %v", req.OldName, d, err)
+		return err
+	}
+	if pluginOf(entry) != pluginOf(newParent) {
+		return syscall.EXDEV
+	}
+	if !plugin.RenameAction().IsSupportedOn(entry) {
+		activity.Warnf(ctx, "FUSE: Rename unsupported for %v in %v", req.OldName, d)
+		return syscall.ENOTSUP
+	}
+
+	err = plugin.RenameWithAnalytics(ctx, entry.(plugin.Renamable), newParent.(plugin.Parent), req.NewName)
+	if err != nil {
+		activity.Warnf(ctx, "FUSE: Rename %v in %v errored: %v", req.OldName, d, err)
+		if plugin.IsInvalidInputErr(err) {
+			return syscall.EINVAL
+		}
+		return err
+	}
+	activity.Record(ctx, "FUSE: Renamed %v in %v to %v in %v", req.OldName, d, req.NewName, dst)
+	return nil
+}
+
+// Forget stops watching the directory.
+func (d *dir) Forget() {
+	watches.forget(d)
+}
+
+func (d *dir) Attr(ctx context.Context, a *fuse.Attr) (err error) {
+	defer recordOp(ctx, d, "Attr", time.Now(), &err)
+	defer recoverPanic(ctx, d, "Attr", &err)
 	// FUSE caches nodes for a long time, meaning there's a chance that
 	// f's attributes are outdated. 'refind' requests the entry from its
 	// parent to ensure it has updated attributes.
@@ -111,7 +292,13 @@ func (d *dir) Attr(ctx context.Context, a *fuse.Attr) error {
 	// is not strictly necessary for the other FUSE operations, we choose to
 	// leave it alone.
 
-	applyAttr(a, plugin.Attributes(entry), os.ModeDir|0550)
+	mode := os.ModeDir | 0550
+	if _, ok := entry.(plugin.Creatable); ok {
+		mode |= 0220
+	} else if _, ok := entry.(plugin.DirCreatable); ok {
+		mode |= 0220
+	}
+	applyAttr(a, entry, mode)
 	// Attr is not a particularly interesting call and happens a lot. Log it to debug like other
 	// activity, but leave it out of activity because it introduces history entries for lots of
 	// miscellaneous shell activity.
@@ -1,3 +1,6 @@
+//go:build !windows
+// +build !windows
+
 package fuse
 
 import (
@@ -6,6 +9,7 @@ import (
 	"os"
 	"sync"
 	"syscall"
+	"time"
 
 	"bazil.org/fuse"
 	"bazil.org/fuse/fs"
@@ -52,6 +56,9 @@ type file struct {
 	data []byte
 	// Size of readable content, necessary for *non-file-like* entries
 	readSize uint64
+	// created is true if the file was created via FUSE and hasn't been flushed yet,
+	// in which case its entry doesn't exist in the plugin's API
+	created bool
 }
 
 func newFile(p *dir, e plugin.Entry) *file {
@@ -72,11 +79,13 @@ func (f *file) useLocalContent() bool {
 var _ = fs.Node(&file{})
 var _ = fs.Handle(&file{})
 
-func (f *file) Attr(ctx context.Context, a *fuse.Attr) error {
+func (f *file) Attr(ctx context.Context, a *fuse.Attr) (err error) {
+	defer recordOp(ctx, f, "Attr", time.Now(), &err)
+	defer recoverPanic(ctx, f, "Attr", &err)
 	f.mux.Lock()
 	defer f.mux.Unlock()
 
-	if !f.useLocalContent() {
+	if !f.useLocalContent() && !f.created {
 		// Fetch updated attributes only if we're not currently writing to it.
 		entry, err := f.refind(ctx)
 		if err != nil {
@@ -92,8 +101,7 @@ func (f *file) Attr(ctx context.Context, a *fuse.Attr) error {
 }
 
 func (f *file) fillAttr(a *fuse.Attr) {
-	attr := plugin.Attributes(f.entry)
-	applyAttr(a, attr, defaultMode(f.entry))
+	applyAttr(a, f.entry, defaultMode(f.entry))
 
 	if f.useLocalContent() || !f.isFileLikeEntry() {
 		// Use whatever size we know locally. Retrieving content can be expensive so we settle for
@@ -123,12 +131,14 @@ var _ = fs.NodeOpener(&file{})
 //
 // When writing and flushing a file, we may call Read on the entry (if it supports Read) even if
 // opened WriteOnly. That only happens when performing a partial write of a *file-like* entry.
-func (f *file) Open(ctx context.Context, req *fuse.OpenRequest, resp *fuse.OpenResponse) (fs.Handle, error) {
+func (f *file) Open(ctx context.Context, req *fuse.OpenRequest, resp *fuse.OpenResponse) (handle fs.Handle, err error) {
+	defer recordOp(ctx, f, "Open", time.Now(), &err)
+	defer recoverPanic(ctx, f, "Open", &err)
 	f.mux.Lock()
 	defer f.mux.Unlock()
 	activity.Record(ctx, "FUSE: Open %v: %+v", f, *req)
 
-	if !f.useLocalContent() {
+	if !f.useLocalContent() && !f.created {
 		// Check for an updated entry in case it has static content, like for preloaded external plugin entries.
 		entry, err := f.refind(ctx)
 		if err != nil {
@@ -138,18 +148,19 @@ func (f *file) Open(ctx context.Context, req *fuse.OpenRequest, resp *fuse.OpenR
 		f.entry = entry
 	}
 
+	// Entries that don't support an access mode don't have its permission bits, so
+	// opening them with it is denied like it would be for a regular file.
 	readable := plugin.ReadAction().IsSupportedOn(f.entry)
 	writable := plugin.WriteAction().IsSupportedOn(f.entry)
+	wantsRead := req.Flags.IsReadOnly() || req.Flags.IsReadWrite()
+	wantsWrite := req.Flags.IsWriteOnly() || req.Flags.IsReadWrite()
 	switch {
-	case req.Flags.IsReadOnly() && !readable:
-		activity.Warnf(ctx, "FUSE: Open read-only unsupported on %v", f)
-		return nil, syscall.ENOTSUP
-	case req.Flags.IsWriteOnly() && !writable:
-		activity.Warnf(ctx, "FUSE: Open write-only unsupported on %v", f)
-		return nil, syscall.ENOTSUP
-	case req.Flags.IsReadWrite() && (!readable || !writable):
-		activity.Warnf(ctx, "FUSE: Open read-write unsupported on %v", f)
-		return nil, syscall.ENOTSUP
+	case wantsWrite && !writable:
+		activity.Warnf(ctx, "FUSE: Open for writing denied on read-only %v", f)
+		return nil, syscall.EACCES
+	case wantsRead && !readable:
+		activity.Warnf(ctx, "FUSE: Open for reading denied on write-only %v", f)
+		return nil, syscall.EACCES
 	}
 
 	if !f.isFileLikeEntry() && req.Flags.IsReadWrite() {
@@ -158,7 +169,7 @@ func (f *file) Open(ctx context.Context, req *fuse.OpenRequest, resp *fuse.OpenR
 		return nil, syscall.ENOTSUP
 	}
 
-	if !f.isFileLikeEntry() {
+	if !f.isFileLikeEntry() || cacheOptionsFor(f.entry).DirectIO {
 		// Open the file in direct IO mode to avoid the kernel page cache. This also enables FUSE to
 		// still read the entry's content so that built-in tools like cat and grep still work.
 		resp.Flags |= fuse.OpenDirectIO
@@ -193,7 +204,9 @@ func (f *file) releaseWriter(ctx context.Context, handle fuse.HandleID) {
 
 var _ = fs.HandleReleaser(&file{})
 
-func (f *file) Release(ctx context.Context, req *fuse.ReleaseRequest) error {
+func (f *file) Release(ctx context.Context, req *fuse.ReleaseRequest) (err error) {
+	defer recordOp(ctx, f, "Release", time.Now(), &err)
+	defer recoverPanic(ctx, f, "Release", &err)
 	if req.ReleaseFlags&fuse.ReleaseFlush != 0 {
 		activity.Record(ctx, "FUSE: Invoking Flush for Release on %v", f)
 		err := f.Flush(ctx, &fuse.FlushRequest{
@@ -216,7 +229,9 @@ func (f *file) Release(ctx context.Context, req *fuse.ReleaseRequest) error {
 
 var _ = fs.HandleReader(&file{})
 
-func (f *file) Read(ctx context.Context, req *fuse.ReadRequest, resp *fuse.ReadResponse) error {
+func (f *file) Read(ctx context.Context, req *fuse.ReadRequest, resp *fuse.ReadResponse) (err error) {
+	defer recordOp(ctx, f, "Read", time.Now(), &err)
+	defer recoverPanic(ctx, f, "Read", &err)
 	f.mux.Lock()
 	defer f.mux.Unlock()
 
@@ -238,7 +253,9 @@ func (f *file) Read(ctx context.Context, req *fuse.ReadRequest, resp *fuse.ReadR
 
 var _ = fs.HandleWriter(&file{})
 
-func (f *file) Write(ctx context.Context, req *fuse.WriteRequest, resp *fuse.WriteResponse) error {
+func (f *file) Write(ctx context.Context, req *fuse.WriteRequest, resp *fuse.WriteResponse) (err error) {
+	defer recordOp(ctx, f, "Write", time.Now(), &err)
+	defer recoverPanic(ctx, f, "Write", &err)
 	f.mux.Lock()
 	defer f.mux.Unlock()
 
@@ -289,12 +306,20 @@ var _ = fs.HandleFlusher(&file{})
 
 // Note that this implementation of Flush only calls plugin.Write if there were previous calls to
 // Write or Setattr. It doesn't check whether the data that's there matches what we're writing.
-func (f *file) Flush(ctx context.Context, req *fuse.FlushRequest) error {
+func (f *file) Flush(ctx context.Context, req *fuse.FlushRequest) (err error) {
+	defer recordOp(ctx, f, "Flush", time.Now(), &err)
+	defer recoverPanic(ctx, f, "Flush", &err)
 	f.mux.Lock()
 	defer f.mux.Unlock()
 	activity.Record(ctx, "FUSE: Flush %v: %+v", f, *req)
+	return f.writeBack(ctx, req.Handle)
+}
 
-	if _, ok := f.writers[req.Handle]; !ok {
+// writeBack writes the data that's buffered for the handle to the entry. f.mux
+// must be locked.
+func (f *file) writeBack(ctx context.Context, handle fuse.HandleID) error {
+	// Created files are written even if they weren't written to so that they exist.
+	if _, ok := f.writers[handle]; !ok && !f.created {
 		return nil
 	}
 
@@ -327,23 +352,35 @@ func (f *file) Flush(ctx context.Context, req *fuse.FlushRequest) error {
 		activity.Warnf(ctx, "FUSE: Error writing %v: %v", f, err)
 		return err
 	}
+	if f.created {
+		// The entry now exists, so make sure that its parent lists it.
+		f.created = false
+		deleted := plugin.ClearCacheFor(plugin.ID(f.entry), true)
+		activity.Record(ctx, "Clear cache for %v: %+v", f.entry, deleted)
+	}
 
 	// Non-file-like entries start from scratch on each Write operation, and have their cache
 	// invalidated whenever we write to them because we can't accurately model their readable data.
 	if !f.isFileLikeEntry() {
-		f.releaseWriter(ctx, req.Handle)
+		f.releaseWriter(ctx, handle)
 	}
 	return nil
 }
 
 var _ = fs.NodeSetattrer(&file{})
 
-func (f *file) Setattr(ctx context.Context, req *fuse.SetattrRequest, resp *fuse.SetattrResponse) error {
+func (f *file) Setattr(ctx context.Context, req *fuse.SetattrRequest, resp *fuse.SetattrResponse) (err error) {
+	defer recordOp(ctx, f, "Setattr", time.Now(), &err)
+	defer recoverPanic(ctx, f, "Setattr", &err)
 	f.mux.Lock()
 	defer f.mux.Unlock()
 	activity.Record(ctx, "FUSE: Setattr[%v] %v: %+v", req.Handle, f, *req)
 
 	if req.Valid.Size() {
+		if !plugin.WriteAction().IsSupportedOn(f.entry) {
+			activity.Warnf(ctx, "FUSE: Truncate denied on read-only %v", f)
+			return syscall.EACCES
+		}
 		if !req.Valid.Handle() {
 			// No guarantee we'll ever write the change. If this is ever necessary, we could update it
 			// to immediately do a plugin.Write.
@@ -375,11 +412,14 @@ func (f *file) Setattr(ctx context.Context, req *fuse.SetattrRequest, resp *fuse
 // Needs to be defined or vim gets an EIO error on Fsync.
 var _ = fs.NodeFsyncer(&file{})
 
-func (f *file) Fsync(ctx context.Context, req *fuse.FsyncRequest) error {
-	// As noted in the docs for fs.NodeFsyncer, this should be implemented on a Handle. Write Fsync
-	// should be unnecessary because Flush handles complete serialization out. On a handle opened
-	// for reading, we could potentially invalidate the Wash cache and re-request data from the
-	// plugin, but in most cases that doesn't seem to be necessary.
+// Fsync writes the handle's buffered data so that it's saved before the file's closed. On a
+// handle opened for reading, we could potentially invalidate the Wash cache and re-request data
+// from the plugin, but in most cases that doesn't seem to be necessary.
+func (f *file) Fsync(ctx context.Context, req *fuse.FsyncRequest) (err error) {
+	defer recordOp(ctx, f, "Fsync", time.Now(), &err)
+	defer recoverPanic(ctx, f, "Fsync", &err)
+	f.mux.Lock()
+	defer f.mux.Unlock()
 	activity.Record(ctx, "FUSE: Fsync %v: %+v", f, *req)
-	return syscall.ENOSYS
+	return f.writeBack(ctx, req.Handle)
 }
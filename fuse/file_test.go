@@ -1,12 +1,19 @@
+//go:build !windows
+// +build !windows
+
 package fuse
 
 import (
 	"context"
+	"syscall"
 	"testing"
+	"time"
 
 	"bazil.org/fuse"
 	"bazil.org/fuse/fs"
+	"github.com/puppetlabs/wash/activity"
 	"github.com/puppetlabs/wash/datastore"
+	"github.com/puppetlabs/wash/metrics"
 	"github.com/puppetlabs/wash/plugin"
 	plugintest "github.com/puppetlabs/wash/plugin/test"
 	"github.com/stretchr/testify/mock"
@@ -94,6 +101,49 @@ func (suite *fileTestSuite) TestOpen_NonFileLikeEntry_OpenFlags() {
 	mock.AssertExpectationsForObjects(suite.T(), mr, mw, mrw)
 }
 
+func (suite *fileTestSuite) TestOpen_ReadOnlyEntry_Denied() {
+	m := plugintest.NewMockRead()
+	m.Attributes().SetSize(1)
+
+	f := newFile(nil, m)
+	var resp fuse.OpenResponse
+	_, err := f.Open(suite.ctx, &fuse.OpenRequest{Flags: fuse.OpenWriteOnly}, &resp)
+	suite.Equal(syscall.EACCES, err)
+	_, err = f.Open(suite.ctx, &fuse.OpenRequest{Flags: fuse.OpenReadWrite}, &resp)
+	suite.Equal(syscall.EACCES, err)
+}
+
+func (suite *fileTestSuite) TestCacheOptions() {
+	SetOptions(Options{
+		CacheOptions: CacheOptions{AttrTimeout: 2 * time.Second},
+		Plugins:      map[string]CacheOptions{"slow": {AttrTimeout: 5 * time.Minute, DirectIO: true}},
+	})
+	defer SetOptions(Options{CacheOptions: DefaultCacheOptions})
+
+	fast := plugintest.NewMockRead()
+	fast.Attributes().SetSize(1)
+	fast.SetTestID("/fast/foo")
+	slow := plugintest.NewMockRead()
+	slow.Attributes().SetSize(1)
+	slow.SetTestID("/slow/foo")
+
+	var attr fuse.Attr
+	suite.NoError(newFile(nil, fast).Attr(suite.ctx, &attr))
+	suite.Equal(2*time.Second, attr.Valid)
+	suite.NoError(newFile(nil, slow).Attr(suite.ctx, &attr))
+	suite.Equal(5*time.Minute, attr.Valid)
+
+	// DirectIO applies to file-like entries of the plugins that set it.
+	var resp fuse.OpenResponse
+	_, err := newFile(nil, fast).Open(suite.ctx, &fuse.OpenRequest{Flags: fuse.OpenReadOnly}, &resp)
+	suite.NoError(err)
+	suite.Zero(resp.Flags & fuse.OpenDirectIO)
+	resp = fuse.OpenResponse{}
+	_, err = newFile(nil, slow).Open(suite.ctx, &fuse.OpenRequest{Flags: fuse.OpenReadOnly}, &resp)
+	suite.NoError(err)
+	suite.Equal(fuse.OpenDirectIO, resp.Flags&fuse.OpenDirectIO)
+}
+
 func (suite *fileTestSuite) TestOpen_NonFileLikeEntry_Direct() {
 	m := plugintest.NewMockReadWrite()
 	m.On("Read", suite.ctx).Return([]byte("hello"), nil).Once()
@@ -205,6 +255,28 @@ func (suite *fileTestSuite) TestOpenAndFlush() {
 	}
 }
 
+func (suite *fileTestSuite) TestCreatedAndFlush_WritesEmptyContent() {
+	m := plugintest.NewMockReadWrite()
+	m.Attributes().SetSize(0)
+	m.On("Write", suite.ctx, []byte(nil)).Return(nil).Once()
+
+	f := newFile(nil, m)
+	f.created = true
+	var attr fuse.Attr
+	suite.NoError(f.Attr(suite.ctx, &attr))
+	suite.Equal(uint64(0), attr.Size)
+
+	err := f.Flush(suite.ctx, &fuse.FlushRequest{Handle: 1})
+	suite.NoError(err)
+	suite.False(f.created)
+	m.AssertExpectations(suite.T())
+
+	// Subsequent flushes without writes are no-ops
+	err = f.Flush(suite.ctx, &fuse.FlushRequest{Handle: 1})
+	suite.NoError(err)
+	m.AssertExpectations(suite.T())
+}
+
 func (suite *fileTestSuite) TestOpenAndFlushRelease() {
 	m := plugintest.NewMockReadWrite()
 	m.Attributes().SetSize(5)
@@ -258,6 +330,27 @@ func (suite *fileTestSuite) TestAttrWithWriters() {
 	suite.Equal(uint64(5), attr.Size)
 }
 
+func (suite *fileTestSuite) TestAttr_OpLogging() {
+	metrics.Reset()
+	defer metrics.Reset()
+	m := plugintest.NewMockRead()
+	f := newFile(nil, m)
+	var attr fuse.Attr
+
+	suite.NoError(f.Attr(suite.ctx, &attr))
+	suite.Empty(metrics.Histograms())
+
+	SetOpLogging(true)
+	defer SetOpLogging(false)
+	suite.NoError(f.Attr(suite.ctx, &attr))
+	histograms := metrics.Histograms()
+	if suite.Len(histograms, 1) {
+		suite.Equal("FUSE Attr", histograms[0].Name)
+		suite.Equal(uint64(1), histograms[0].Count)
+		suite.Equal(uint64(0), histograms[0].Errors)
+	}
+}
+
 func (suite *fileTestSuite) TestSetAttr_NoHandle() {
 	m := plugintest.NewMockReadWrite()
 	m.Attributes().SetSize(0)
@@ -269,6 +362,83 @@ func (suite *fileTestSuite) TestSetAttr_NoHandle() {
 	suite.Error(err)
 }
 
+func (suite *fileTestSuite) TestSetAttr_ReadOnlyEntry() {
+	m := plugintest.NewMockRead()
+	m.Attributes().SetSize(0)
+
+	f := newFile(nil, m)
+	req := fuse.SetattrRequest{Valid: fuse.SetattrSize | fuse.SetattrHandle, Size: 1, Handle: 1}
+	var resp fuse.SetattrResponse
+	err := f.Setattr(suite.ctx, &req, &resp)
+	suite.Equal(syscall.EACCES, err)
+}
+
+func (suite *fileTestSuite) TestGetxattr() {
+	m := plugintest.NewMockRead()
+	m.Attributes().
+		SetCustom("generation", int64(1589411937812000)).
+		SetCustom("storage_class", "STANDARD")
+
+	f := newFile(nil, m)
+	var resp fuse.GetxattrResponse
+	err := f.Getxattr(suite.ctx, &fuse.GetxattrRequest{Name: "user.wash.generation"}, &resp)
+	suite.NoError(err)
+	suite.Equal([]byte("1589411937812000"), resp.Xattr)
+
+	err = f.Getxattr(suite.ctx, &fuse.GetxattrRequest{Name: "user.wash.storage_class"}, &resp)
+	suite.NoError(err)
+	suite.Equal([]byte("STANDARD"), resp.Xattr)
+
+	err = f.Getxattr(suite.ctx, &fuse.GetxattrRequest{Name: "user.wash.foo"}, &resp)
+	suite.Equal(fuse.ErrNoXattr, err)
+	err = f.Getxattr(suite.ctx, &fuse.GetxattrRequest{Name: "security.selinux"}, &resp)
+	suite.Equal(fuse.ErrNoXattr, err)
+}
+
+func (suite *fileTestSuite) TestListxattr() {
+	m := plugintest.NewMockRead()
+	m.Attributes().
+		SetCustom("storage_class", "STANDARD").
+		SetCustom("generation", 1)
+
+	f := newFile(nil, m)
+	var resp fuse.ListxattrResponse
+	err := f.Listxattr(suite.ctx, &fuse.ListxattrRequest{}, &resp)
+	suite.NoError(err)
+	// Attributes are included in the partial metadata when it isn't set.
+	suite.Equal([]byte("user.wash.generation\x00user.wash.meta.custom.generation\x00user.wash.meta.custom.storage_class\x00user.wash.storage_class\x00"), resp.Xattr)
+}
+
+func (suite *fileTestSuite) TestMetadataXattrs() {
+	m := plugintest.NewMockRead()
+	m.SetPartialMetadata(map[string]interface{}{
+		"State": map[string]interface{}{"Name": "running", "Code": 16},
+		"Tags":  []interface{}{map[string]interface{}{"Key": "owner", "Value": "me"}},
+		"Empty": []interface{}{},
+	})
+
+	f := newFile(nil, m)
+	var listResp fuse.ListxattrResponse
+	suite.NoError(f.Listxattr(suite.ctx, &fuse.ListxattrRequest{}, &listResp))
+	suite.Equal([]byte("user.wash.meta.Empty\x00user.wash.meta.State.Code\x00user.wash.meta.State.Name\x00user.wash.meta.Tags.0.Key\x00user.wash.meta.Tags.0.Value\x00"), listResp.Xattr)
+
+	for name, expected := range map[string]string{
+		"user.wash.meta.State.Name": "running",
+		"user.wash.meta.State.Code": "16",
+		"user.wash.meta.Tags.0.Key": "owner",
+		"user.wash.meta.Empty":      "[]",
+	} {
+		var resp fuse.GetxattrResponse
+		suite.NoError(f.Getxattr(suite.ctx, &fuse.GetxattrRequest{Name: name}, &resp), name)
+		suite.Equal(expected, string(resp.Xattr), name)
+	}
+
+	// Values that aren't in the partial metadata are looked up in the full
+	// metadata, which is the partial metadata for this entry.
+	var resp fuse.GetxattrResponse
+	suite.Equal(fuse.ErrNoXattr, f.Getxattr(suite.ctx, &fuse.GetxattrRequest{Name: "user.wash.meta.State.Reason"}, &resp))
+}
+
 func (suite *fileTestSuite) assertFileHandle(handle fs.Handle) bool {
 	return suite.Implements((*fs.HandleReader)(nil), handle) &&
 		suite.Implements((*fs.HandleWriter)(nil), handle) &&
@@ -359,6 +529,31 @@ func (suite *fileTestSuite) TestWrite_NonFileLikeEntry() {
 	m.AssertExpectations(suite.T())
 }
 
+func (suite *fileTestSuite) TestFsync_WritesBufferedData() {
+	m := plugintest.NewMockWrite()
+	m.Attributes().SetSize(5)
+	// Called on Fsync, then on Flush since the handle's still writing.
+	m.On("Write", suite.ctx, []byte("hello")).Return(nil).Twice()
+
+	f := newFile(nil, m)
+	var resp fuse.OpenResponse
+	handle, err := f.Open(suite.ctx, &fuse.OpenRequest{Flags: fuse.OpenWriteOnly}, &resp)
+	if !suite.NoError(err) || !suite.assertFileHandle(handle) {
+		suite.FailNow("Unusable handle")
+	}
+
+	writeReq := fuse.WriteRequest{Offset: 0, Data: []byte("hello"), Handle: 1}
+	var writeResp fuse.WriteResponse
+	suite.NoError(handle.(fs.HandleWriter).Write(suite.ctx, &writeReq, &writeResp))
+
+	suite.NoError(f.Fsync(suite.ctx, &fuse.FsyncRequest{Handle: 1}))
+	m.AssertNumberOfCalls(suite.T(), "Write", 1)
+
+	relReq := fuse.ReleaseRequest{ReleaseFlags: fuse.ReleaseFlush, Handle: 1}
+	suite.NoError(handle.(fs.HandleReleaser).Release(suite.ctx, &relReq))
+	m.AssertExpectations(suite.T())
+}
+
 func (suite *fileTestSuite) TestWrite_FileLikeEntry() {
 	m := plugintest.NewMockWrite()
 	m.Attributes().SetSize(5)
@@ -648,7 +843,9 @@ func (suite *fileTestSuite) TestWriteRead_NonFileLikeEntry() {
 }
 
 func TestFile(t *testing.T) {
-	ctx, cancel := context.WithCancel(context.Background())
+	// The plugin's set so that the method wrappers pass the context through
+	// unchanged, which lets the mocks match it.
+	ctx, cancel := context.WithCancel(activity.WithPlugin(context.Background(), "mock"))
 	suite.Run(t, &fileTestSuite{ctx: ctx})
 	cancel()
 }
@@ -0,0 +1,76 @@
+// Package fuse adapts wash plugin types to a FUSE filesystem. It uses the
+// kernel's FUSE module on Linux, macOS and FreeBSD, and WinFsp on Windows.
+package fuse
+
+import (
+	"context"
+	"fmt"
+	"os/user"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/metrics"
+	log "github.com/sirupsen/logrus"
+)
+
+var startTime = time.Now()
+
+func getIDs() (uint32, uint32) {
+	me, err := user.Current()
+	if err != nil {
+		log.Infof("Unable to fetch user: %v", err)
+		return 0, 0
+	}
+	uid, err := strconv.ParseUint(me.Uid, 10, 32)
+	if err != nil {
+		log.Infof("Unable to parse uid: %v", err)
+		return 0, 0
+	}
+	gid, err := strconv.ParseUint(me.Gid, 10, 32)
+	if err != nil {
+		log.Infof("Unable to parse gid: %v", err)
+		return 0, 0
+	}
+	return uint32(uid), uint32(gid)
+}
+
+var uid, gid = getIDs()
+
+// recoverPanic recovers from a panic in a FUSE handler so that the panic fails
+// only the current operation (with EIO) instead of crashing the daemon. It must
+// be deferred.
+func recoverPanic(ctx context.Context, node fmt.Stringer, op string, err *error) {
+	if r := recover(); r != nil {
+		_ = activity.RecordPanic(ctx, fmt.Sprintf("FUSE: %v on %v", op, node), r)
+		*err = syscall.EIO
+	}
+}
+
+// opLogging is set by SetOpLogging.
+var opLogging bool
+
+// SetOpLogging turns FUSE op logging on or off. When it's on, each FUSE op is
+// recorded in the journal with its latency, and its latency is added to the op's
+// histogram in the metrics package. Comparing the histograms with the plugins'
+// method stats shows whether a slow mount is spending its time in kernel round
+// trips or in plugin calls. It's off by default because it records every op.
+func SetOpLogging(enabled bool) {
+	opLogging = enabled
+}
+
+// recordOp records the op's latency if op logging is on. It must be deferred
+// before recoverPanic so that it sees the error that a panic's turned into.
+func recordOp(ctx context.Context, node fmt.Stringer, op string, start time.Time, err *error) {
+	if !opLogging {
+		return
+	}
+	latency := time.Since(start)
+	if *err != nil {
+		activity.Record(ctx, "FUSE: %v on %v took %v and errored: %v", op, node, latency, *err)
+	} else {
+		activity.Record(ctx, "FUSE: %v on %v took %v", op, node, latency)
+	}
+	metrics.Observe("FUSE "+op, latency, *err)
+}
@@ -0,0 +1,60 @@
+package fuse
+
+import (
+	"strings"
+	"time"
+
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// CacheOptions configures how the kernel caches a plugin's entries. Slow
+// backends benefit from long timeouts because fewer requests reach the plugin,
+// while fast backends can use short timeouts to keep the mount fresh.
+type CacheOptions struct {
+	// AttrTimeout is how long the kernel caches an entry's attributes.
+	AttrTimeout time.Duration
+	// EntryTimeout is how long the kernel caches a name's lookup in its
+	// directory.
+	EntryTimeout time.Duration
+	// DirectIO opens files in direct IO mode, which bypasses the kernel's page
+	// cache so that each read reaches the plugin. Non-file-like entries are
+	// always opened in direct IO mode.
+	DirectIO bool
+}
+
+// DefaultCacheOptions are the cache options that are used when none are set.
+var DefaultCacheOptions = CacheOptions{
+	// A 1 second attribute timeout avoids frequent Attr calls.
+	AttrTimeout:  1 * time.Second,
+	EntryTimeout: 1 * time.Minute,
+}
+
+// Options configures the FUSE server's caching.
+type Options struct {
+	// CacheOptions applies to plugins that aren't in Plugins.
+	CacheOptions
+	// Plugins overrides the cache options for the named plugins.
+	Plugins map[string]CacheOptions
+}
+
+// options is set by SetOptions.
+var options = Options{CacheOptions: DefaultCacheOptions}
+
+// SetOptions sets the FUSE server's options. It must be called before the
+// server starts.
+func SetOptions(opts Options) {
+	options = opts
+}
+
+// cacheOptionsFor returns the cache options for e's plugin.
+func cacheOptionsFor(e plugin.Entry) CacheOptions {
+	if opts, ok := options.Plugins[pluginOf(e)]; ok {
+		return opts
+	}
+	return options.CacheOptions
+}
+
+// pluginOf returns the name of the entry's plugin.
+func pluginOf(e plugin.Entry) string {
+	return strings.SplitN(strings.Trim(plugin.ID(e), "/"), "/", 2)[0]
+}
@@ -0,0 +1,51 @@
+//go:build !windows
+// +build !windows
+
+package fuse
+
+import (
+	"context"
+	"time"
+
+	"bazil.org/fuse"
+	"bazil.org/fuse/fs"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+	log "github.com/sirupsen/logrus"
+)
+
+// ==== FUSE symlink Interface ====
+
+type symlink struct {
+	fuseNode
+}
+
+var _ = fs.Node(&symlink{})
+var _ = fs.NodeReadlinker(&symlink{})
+
+func newSymlink(p *dir, e *plugin.Symlink) *symlink {
+	return &symlink{newFuseNode("l", p, e)}
+}
+
+func (l *symlink) Attr(ctx context.Context, a *fuse.Attr) (err error) {
+	defer recordOp(ctx, l, "Attr", time.Now(), &err)
+	defer recoverPanic(ctx, l, "Attr", &err)
+	entry, err := l.refind(ctx)
+	if err != nil {
+		activity.Warnf(ctx, "FUSE: Attr errored %v, %v", l, err)
+		return err
+	}
+	applyAttr(a, entry, 0777)
+	log.Debugf("FUSE: Attr %v: %+v", l, *a)
+	return nil
+}
+
+// Readlink returns the symlink's target. Targets are relative to the symlink's
+// parent, so the kernel resolves them within the mount.
+func (l *symlink) Readlink(ctx context.Context, req *fuse.ReadlinkRequest) (target string, err error) {
+	defer recordOp(ctx, l, "Readlink", time.Now(), &err)
+	defer recoverPanic(ctx, l, "Readlink", &err)
+	target = l.entry.(*plugin.Symlink).Target()
+	log.Debugf("FUSE: Readlink %v: %v", l, target)
+	return target, nil
+}
@@ -0,0 +1,117 @@
+//go:build !windows
+// +build !windows
+
+package fuse
+
+import (
+	"context"
+	"sync"
+
+	"bazil.org/fuse"
+	"bazil.org/fuse/fs"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+	log "github.com/sirupsen/logrus"
+)
+
+// FUSE can't send inotify events for changes that it didn't make, so watched
+// directories invalidate the kernel's cache of their listing and of their
+// changed children instead. Subsequent reads then see the changes without
+// waiting for the cache to expire.
+
+// watcher tracks the watched directories. There's one watch per entry.
+type watcher struct {
+	mux     sync.Mutex
+	server  *fs.Server
+	ctx     context.Context
+	watches map[string]*dirWatch
+}
+
+type dirWatch struct {
+	// node is the latest node for the directory. Lookup creates a new node each
+	// time, and the kernel only caches the nodes that it's looked up.
+	node   *dir
+	cancel context.CancelFunc
+}
+
+var watches = &watcher{}
+
+// start enables watches for the given server. The watches are stopped once
+// ctx is cancelled.
+func (w *watcher) start(ctx context.Context, server *fs.Server) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	w.server = server
+	w.ctx = ctx
+	w.watches = make(map[string]*dirWatch)
+}
+
+// watch starts watching d if its entry is watchable.
+func (w *watcher) watch(d *dir, entry plugin.Entry) {
+	parent, ok := entry.(plugin.Watchable)
+	if !ok || !plugin.WatchAction().IsSupportedOn(entry) {
+		return
+	}
+
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	if w.server == nil {
+		return
+	}
+	id := plugin.ID(entry)
+	if dw, ok := w.watches[id]; ok {
+		dw.node = d
+		return
+	}
+
+	ctx, cancel := context.WithCancel(w.ctx)
+	events, err := plugin.Watch(ctx, parent)
+	if err != nil {
+		cancel()
+		log.Debugf("FUSE: Could not watch %v: %v", d, err)
+		return
+	}
+	w.watches[id] = &dirWatch{node: d, cancel: cancel}
+	log.Debugf("FUSE: Watching %v", d)
+
+	go func() {
+		for event := range events {
+			w.invalidate(ctx, id, event)
+		}
+		w.mux.Lock()
+		defer w.mux.Unlock()
+		// The watch may have been replaced after it was forgotten.
+		if dw, ok := w.watches[id]; ok && dw.node == d {
+			delete(w.watches, id)
+		}
+	}()
+}
+
+func (w *watcher) invalidate(ctx context.Context, id string, event plugin.EntryEvent) {
+	w.mux.Lock()
+	dw, ok := w.watches[id]
+	w.mux.Unlock()
+	if !ok {
+		return
+	}
+
+	activity.Record(ctx, "FUSE: %v %v in %v", event.Type, event.Name, dw.node)
+	if err := w.server.InvalidateEntry(dw.node, event.Name); err != nil && err != fuse.ErrNotCached {
+		log.Debugf("FUSE: Could not invalidate %v in %v: %v", event.Name, dw.node, err)
+	}
+	if err := w.server.InvalidateNodeData(dw.node); err != nil && err != fuse.ErrNotCached {
+		log.Debugf("FUSE: Could not invalidate %v: %v", dw.node, err)
+	}
+}
+
+// forget stops watching d once the kernel's forgotten it.
+func (w *watcher) forget(d *dir) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	id := plugin.ID(d.entry)
+	if dw, ok := w.watches[id]; ok && dw.node == d {
+		dw.cancel()
+		delete(w.watches, id)
+		log.Debugf("FUSE: Stopped watching %v", d)
+	}
+}
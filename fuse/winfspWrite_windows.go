@@ -0,0 +1,354 @@
+package fuse
+
+import (
+	"context"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/billziss-gh/cgofuse/fuse"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// noHandle is the fh of callbacks that aren't made on an open file.
+const noHandle = ^uint64(0)
+
+// winfspHandle buffers the writes to a file that's open for writing. Like the
+// other platforms' files, the buffered content is only written to the entry
+// when the handle's flushed or closed.
+//
+// The buffer holds the whole file. It starts empty if the file was created or
+// truncated. Otherwise it's loaded from the entry on the first write, so that
+// partial writes to readable entries keep the rest of their content.
+type winfspHandle struct {
+	mux   sync.Mutex
+	entry plugin.Entry
+	data  []byte
+	// loaded is true if data holds the file's content.
+	loaded bool
+	// dirty is true if data has changes that haven't been written yet.
+	dirty bool
+	// created is true if the file was created through the mount and hasn't been
+	// written yet, in which case its entry doesn't exist in the plugin's API.
+	created bool
+	// path is set for created files so that they can be found before they exist.
+	path string
+}
+
+// open returns a new handle for the entry.
+func (f *winfspFS) open(h *winfspHandle) uint64 {
+	f.handlesMux.Lock()
+	defer f.handlesMux.Unlock()
+	f.nextHandle++
+	f.handles[f.nextHandle] = h
+	return f.nextHandle
+}
+
+// handle returns the handle with the fh, or nil if the fh isn't a write handle.
+func (f *winfspFS) handle(fh uint64) *winfspHandle {
+	f.handlesMux.Lock()
+	defer f.handlesMux.Unlock()
+	return f.handles[fh]
+}
+
+// createdAt returns the open handle of the file that was created at the path,
+// or nil if there isn't one.
+func (f *winfspFS) createdAt(path string) *winfspHandle {
+	f.handlesMux.Lock()
+	defer f.handlesMux.Unlock()
+	for _, h := range f.handles {
+		if h.created && h.path == path {
+			return h
+		}
+	}
+	return nil
+}
+
+// load reads the entry's content into the handle's buffer if it's not there
+// yet. Write-only entries can't be read, so they start from an empty buffer
+// like they do on the other platforms. h.mux must be locked.
+func (h *winfspHandle) load(ctx context.Context) error {
+	if h.loaded {
+		return nil
+	}
+	if plugin.ReadAction().IsSupportedOn(h.entry) {
+		size, err := plugin.Size(ctx, h.entry)
+		if err != nil {
+			return err
+		}
+		data, err := plugin.Read(ctx, h.entry, int64(size), 0)
+		if err != nil && err != io.EOF {
+			return err
+		}
+		h.data = data
+	}
+	h.loaded = true
+	return nil
+}
+
+// resize changes the size of the handle's buffer. h.mux must be locked.
+func (h *winfspHandle) resize(ctx context.Context, size int64) error {
+	if err := h.load(ctx); err != nil {
+		return err
+	}
+	if cur := int64(len(h.data)); size > cur {
+		h.data = append(h.data, make([]byte, size-cur)...)
+	} else {
+		h.data = h.data[:size]
+	}
+	h.dirty = true
+	return nil
+}
+
+// writeBack writes the handle's buffer to its entry if it's changed. Created
+// files are written even if they weren't written to so that they exist. h.mux
+// must be locked.
+func (h *winfspHandle) writeBack(ctx context.Context) error {
+	if !h.dirty && !h.created {
+		return nil
+	}
+	if err := plugin.WriteWithAnalytics(ctx, h.entry.(plugin.Writable), h.data); err != nil {
+		return err
+	}
+	h.dirty, h.created = false, false
+	// Clear the entry's and its parent's caches so that they show the new
+	// content and list a created file.
+	deleted := plugin.ClearCacheFor(plugin.ID(h.entry), true)
+	activity.Record(ctx, "Clear cache for %v: %+v", h.entry, deleted)
+	return nil
+}
+
+// splitPath returns the path's parent and its name.
+func splitPath(p string) (string, string) {
+	p = "/" + strings.Trim(p, "/")
+	return path.Dir(p), path.Base(p)
+}
+
+func (f *winfspFS) Create(path string, flags int, mode uint32) (int, uint64) {
+	ctx := f.context()
+	parentPath, name := splitPath(path)
+	activity.Record(ctx, "FUSE: Create %v in %v", name, parentPath)
+	entry, errc := f.find(ctx, parentPath)
+	if errc != 0 {
+		return errc, noHandle
+	}
+	parent, ok := entry.(plugin.Creatable)
+	if !ok {
+		activity.Warnf(ctx, "FUSE: Create denied in read-only %v", parentPath)
+		return -fuse.EACCES, noHandle
+	}
+	child, err := plugin.Create(ctx, parent, name, os.FileMode(mode).Perm())
+	if err != nil {
+		activity.Warnf(ctx, "FUSE: Create %v in %v errored: %v", name, parentPath, err)
+		return -fuse.EIO, noHandle
+	}
+	return 0, f.open(&winfspHandle{entry: child, loaded: true, created: true, path: "/" + strings.Trim(path, "/")})
+}
+
+func (f *winfspFS) Write(path string, buff []byte, ofst int64, fh uint64) int {
+	ctx := f.context()
+	h := f.handle(fh)
+	if h == nil {
+		return -fuse.EBADF
+	}
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	if err := h.load(ctx); err != nil {
+		activity.Warnf(ctx, "FUSE: Write %v errored: %v", path, err)
+		return -fuse.EIO
+	}
+	if end := ofst + int64(len(buff)); end > int64(len(h.data)) {
+		h.data = append(h.data, make([]byte, end-int64(len(h.data)))...)
+	}
+	n := copy(h.data[ofst:], buff)
+	h.dirty = true
+	activity.Record(ctx, "FUSE: Write %v/%v bytes starting at %v from %v", n, len(buff), ofst, path)
+	return n
+}
+
+// Truncate resizes the file. Files that aren't open are resized and written
+// immediately.
+func (f *winfspFS) Truncate(path string, size int64, fh uint64) int {
+	ctx := f.context()
+	activity.Record(ctx, "FUSE: Truncate %v to %v", path, size)
+	h := f.handle(fh)
+	if h != nil {
+		h.mux.Lock()
+		defer h.mux.Unlock()
+		if err := h.resize(ctx, size); err != nil {
+			activity.Warnf(ctx, "FUSE: Truncate %v errored: %v", path, err)
+			return -fuse.EIO
+		}
+		return 0
+	}
+
+	entry, errc := f.find(ctx, path)
+	if errc != 0 {
+		return errc
+	}
+	if !plugin.WriteAction().IsSupportedOn(entry) {
+		activity.Warnf(ctx, "FUSE: Truncate denied on read-only %v", path)
+		return -fuse.EACCES
+	}
+	h = &winfspHandle{entry: entry}
+	if err := h.resize(ctx, size); err != nil {
+		activity.Warnf(ctx, "FUSE: Truncate %v errored: %v", path, err)
+		return -fuse.EIO
+	}
+	if err := h.writeBack(ctx); err != nil {
+		activity.Warnf(ctx, "FUSE: Error writing %v: %v", path, err)
+		return -fuse.EIO
+	}
+	return 0
+}
+
+func (f *winfspFS) Flush(path string, fh uint64) int {
+	return f.flush(path, fh)
+}
+
+func (f *winfspFS) Fsync(path string, datasync bool, fh uint64) int {
+	return f.flush(path, fh)
+}
+
+// flush writes the handle's buffered writes to its entry.
+func (f *winfspFS) flush(path string, fh uint64) int {
+	h := f.handle(fh)
+	if h == nil {
+		return 0
+	}
+	ctx := f.context()
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	activity.Record(ctx, "FUSE: Flush %v", path)
+	if err := h.writeBack(ctx); err != nil {
+		activity.Warnf(ctx, "FUSE: Error writing %v: %v", path, err)
+		return -fuse.EIO
+	}
+	return 0
+}
+
+// Release writes the handle's remaining writes and then closes it.
+func (f *winfspFS) Release(path string, fh uint64) int {
+	errc := f.flush(path, fh)
+	f.handlesMux.Lock()
+	delete(f.handles, fh)
+	f.handlesMux.Unlock()
+	return errc
+}
+
+func (f *winfspFS) Mkdir(path string, mode uint32) int {
+	ctx := f.context()
+	parentPath, name := splitPath(path)
+	activity.Record(ctx, "FUSE: Mkdir %v in %v", name, parentPath)
+	entry, errc := f.find(ctx, parentPath)
+	if errc != 0 {
+		return errc
+	}
+	parent, ok := entry.(plugin.DirCreatable)
+	if !ok {
+		activity.Warnf(ctx, "FUSE: Mkdir denied in read-only %v", parentPath)
+		return -fuse.EACCES
+	}
+	if _, err := plugin.CreateDir(ctx, parent, name); err != nil {
+		activity.Warnf(ctx, "FUSE: Mkdir %v in %v errored: %v", name, parentPath, err)
+		return -fuse.EIO
+	}
+	return 0
+}
+
+func (f *winfspFS) Unlink(path string) int {
+	return f.remove(path, false)
+}
+
+func (f *winfspFS) Rmdir(path string) int {
+	return f.remove(path, true)
+}
+
+// remove deletes the entry at the path. Like on the other platforms, only
+// Deletable entries can be removed, and directories must be empty so that a
+// recursive delete can't delete things like VMs.
+func (f *winfspFS) remove(path string, dir bool) int {
+	ctx := f.context()
+	activity.Record(ctx, "FUSE: Remove %v", path)
+	entry, errc := f.find(ctx, path)
+	if errc != 0 {
+		return errc
+	}
+	if dir && !isDir(entry) {
+		return -fuse.ENOTDIR
+	} else if !dir && isDir(entry) {
+		return -fuse.EISDIR
+	}
+	if !plugin.DeleteAction().IsSupportedOn(entry) {
+		activity.Warnf(ctx, "FUSE: Remove unsupported for %v", path)
+		return -fuse.ENOTSUP
+	}
+	if dir {
+		children, err := plugin.ListWithAnalytics(ctx, entry.(plugin.Parent))
+		if err != nil {
+			activity.Warnf(ctx, "FUSE: Remove %v errored: %v", path, err)
+			return -fuse.EIO
+		}
+		if children.Len() > 0 {
+			return -fuse.ENOTEMPTY
+		}
+	}
+	deleted, err := plugin.DeleteWithAnalytics(ctx, entry.(plugin.Deletable))
+	if err != nil {
+		activity.Warnf(ctx, "FUSE: Remove %v errored: %v", path, err)
+		return -fuse.EIO
+	}
+	activity.Record(ctx, "FUSE: Removed %v: %v", path, deleted)
+	return 0
+}
+
+// Rename renames or moves the entry. Like on the other platforms, moving an
+// entry to another plugin returns EXDEV so that it's copied and deleted
+// instead.
+func (f *winfspFS) Rename(oldpath string, newpath string) int {
+	ctx := f.context()
+	activity.Record(ctx, "FUSE: Rename %v to %v", oldpath, newpath)
+	entry, errc := f.find(ctx, oldpath)
+	if errc != 0 {
+		return errc
+	}
+	newParentPath, newName := splitPath(newpath)
+	newParent, errc := f.find(ctx, newParentPath)
+	if errc != 0 {
+		return errc
+	}
+	if pluginOf(entry) != pluginOf(newParent) {
+		return -fuse.EXDEV
+	}
+	if !plugin.RenameAction().IsSupportedOn(entry) {
+		activity.Warnf(ctx, "FUSE: Rename unsupported for %v", oldpath)
+		return -fuse.ENOTSUP
+	}
+	parent, ok := newParent.(plugin.Parent)
+	if !ok {
+		return -fuse.ENOTDIR
+	}
+	if err := plugin.RenameWithAnalytics(ctx, entry.(plugin.Renamable), parent, newName); err != nil {
+		activity.Warnf(ctx, "FUSE: Rename %v errored: %v", oldpath, err)
+		if plugin.IsInvalidInputErr(err) {
+			return -fuse.EINVAL
+		}
+		return -fuse.EIO
+	}
+	return 0
+}
+
+// Chmod and Utimens succeed without changing anything, like the other
+// platforms' Setattr, since entries' modes and times can't be changed. This
+// lets tools that copy files set them.
+
+func (f *winfspFS) Chmod(path string, mode uint32) int {
+	return 0
+}
+
+func (f *winfspFS) Utimens(path string, tmsp []fuse.Timespec) int {
+	return 0
+}
@@ -0,0 +1,351 @@
+package fuse
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/billziss-gh/cgofuse/fuse"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/analytics"
+	"github.com/puppetlabs/wash/plugin"
+	log "github.com/sirupsen/logrus"
+)
+
+// winfspFS serves the registry through WinFsp's FUSE compatibility layer.
+// WinFsp's callbacks are path-based, so each callback finds its entry from the
+// registry. The ancestors' listings are cached, so this is usually cheap.
+//
+// Files that are open for writing have a handle that buffers their writes (see
+// winfspHandle). Callbacks that aren't implemented return ENOSYS from
+// fuse.FileSystemBase.
+type winfspFS struct {
+	fuse.FileSystemBase
+	registry        *plugin.Registry
+	analyticsClient analytics.Client
+	readyCh         chan struct{}
+
+	handlesMux sync.Mutex
+	handles    map[uint64]*winfspHandle
+	nextHandle uint64
+}
+
+// context returns the context for the current callback. Like the other
+// platforms, the activity's recorded in the calling process's journal.
+func (f *winfspFS) context() context.Context {
+	_, _, pid := fuse.Getcontext()
+	ctx := context.WithValue(context.Background(), activity.JournalKey, activity.JournalForPID(pid))
+	return context.WithValue(ctx, analytics.ClientKey, f.analyticsClient)
+}
+
+// find returns the entry at the path.
+func (f *winfspFS) find(ctx context.Context, path string) (plugin.Entry, int) {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return f.registry, 0
+	}
+	entry, err := plugin.FindEntry(ctx, f.registry, strings.Split(path, "/"))
+	if err != nil {
+		log.Debugf("FUSE: Find %v errored: %v", path, err)
+		return nil, -fuse.ENOENT
+	}
+	return entry, 0
+}
+
+func isSymlink(entry plugin.Entry) bool {
+	_, ok := entry.(*plugin.Symlink)
+	return ok
+}
+
+func isDir(entry plugin.Entry) bool {
+	return !isSymlink(entry) && plugin.ListAction().IsSupportedOn(entry)
+}
+
+// fillStat fills the entry's attributes. Readable entries without a size have
+// their content read to get it, since Windows only reads up to a file's size.
+func fillStat(ctx context.Context, entry plugin.Entry, stat *fuse.Stat_t) {
+	attr := plugin.Attributes(entry)
+	var mode os.FileMode
+	if isSymlink(entry) {
+		stat.Mode, mode = fuse.S_IFLNK, 0777
+	} else if isDir(entry) {
+		stat.Mode, mode = fuse.S_IFDIR, 0550
+	} else {
+		stat.Mode = fuse.S_IFREG
+		if plugin.ReadAction().IsSupportedOn(entry) || plugin.StreamAction().IsSupportedOn(entry) {
+			mode = 0440
+		}
+		size, err := plugin.Size(ctx, entry)
+		if err != nil {
+			activity.Warnf(ctx, "FUSE: Getting the size of %v errored: %v", plugin.ID(entry), err)
+		}
+		stat.Size = int64(size)
+	}
+	if attr.HasMode() {
+		mode = attr.Mode()
+	}
+	stat.Mode |= uint32(mode.Perm())
+	stat.Nlink = 1
+
+	stat.Uid, stat.Gid = uid, gid
+	if attr.HasUID() {
+		stat.Uid = attr.UID()
+	}
+	if attr.HasGID() {
+		stat.Gid = attr.GID()
+	}
+
+	stat.Atim, stat.Mtim, stat.Ctim, stat.Birthtim = fuse.NewTimespec(startTime), fuse.NewTimespec(startTime), fuse.NewTimespec(startTime), fuse.NewTimespec(startTime)
+	if attr.HasAtime() {
+		stat.Atim = fuse.NewTimespec(attr.Atime())
+	}
+	if attr.HasMtime() {
+		stat.Mtim = fuse.NewTimespec(attr.Mtime())
+	}
+	if attr.HasCtime() {
+		stat.Ctim = fuse.NewTimespec(attr.Ctime())
+	}
+	if attr.HasCrtime() {
+		stat.Birthtim = fuse.NewTimespec(attr.Crtime())
+	}
+}
+
+// Init signals that the filesystem's mounted.
+func (f *winfspFS) Init() {
+	close(f.readyCh)
+}
+
+// Getattr fills the file's attributes. Files that are open for writing have
+// the size of their buffer, since a created file's entry doesn't exist yet and
+// the others' content hasn't been written.
+func (f *winfspFS) Getattr(path string, stat *fuse.Stat_t, fh uint64) int {
+	ctx := f.context()
+	h := f.handle(fh)
+	if h == nil {
+		h = f.createdAt("/" + strings.Trim(path, "/"))
+	}
+	if h != nil {
+		h.mux.Lock()
+		defer h.mux.Unlock()
+		if h.loaded {
+			fillStat(ctx, h.entry, stat)
+			stat.Size = int64(len(h.data))
+			return 0
+		}
+	}
+	entry, errc := f.find(ctx, path)
+	if errc != 0 {
+		return errc
+	}
+	fillStat(ctx, entry, stat)
+	log.Debugf("FUSE: Attr[winfsp] %v %+v", path, *stat)
+	return 0
+}
+
+// Open opens the file. Files that are opened for writing get a handle that
+// buffers their writes. Entries that don't support an access mode don't have
+// its permission bits, so opening them with it is denied.
+func (f *winfspFS) Open(path string, flags int) (int, uint64) {
+	ctx := f.context()
+	entry, errc := f.find(ctx, path)
+	if errc != 0 {
+		return errc, noHandle
+	}
+	activity.Record(ctx, "FUSE: Open %v with flags %#x", path, flags)
+	wantsRead := flags&fuse.O_ACCMODE != fuse.O_WRONLY
+	wantsWrite := flags&fuse.O_ACCMODE != fuse.O_RDONLY || flags&fuse.O_TRUNC != 0
+	switch {
+	case wantsWrite && !plugin.WriteAction().IsSupportedOn(entry):
+		activity.Warnf(ctx, "FUSE: Open for writing denied on read-only %v", path)
+		return -fuse.EACCES, noHandle
+	case wantsRead && !plugin.ReadAction().IsSupportedOn(entry):
+		activity.Warnf(ctx, "FUSE: Open for reading denied on write-only %v", path)
+		return -fuse.EACCES, noHandle
+	}
+	if !wantsWrite {
+		return 0, 0
+	}
+	h := &winfspHandle{entry: entry}
+	if flags&fuse.O_TRUNC != 0 {
+		h.loaded, h.dirty = true, true
+	}
+	return 0, f.open(h)
+}
+
+// Read reads from the handle's buffer if it's been written to, and from the
+// entry otherwise.
+func (f *winfspFS) Read(path string, buff []byte, ofst int64, fh uint64) int {
+	ctx := f.context()
+	if h := f.handle(fh); h != nil {
+		h.mux.Lock()
+		defer h.mux.Unlock()
+		if h.loaded {
+			if ofst >= int64(len(h.data)) {
+				return 0
+			}
+			return copy(buff, h.data[ofst:])
+		}
+	}
+	entry, errc := f.find(ctx, path)
+	if errc != 0 {
+		return errc
+	}
+	data, err := plugin.ReadWithAnalytics(ctx, entry, int64(len(buff)), ofst)
+	if err != nil && err != io.EOF {
+		activity.Warnf(ctx, "FUSE: Read %v errored: %v", path, err)
+		return -fuse.EIO
+	}
+	activity.Record(ctx, "FUSE: Read %v/%v bytes starting at %v from %v", len(data), len(buff), ofst, path)
+	return copy(buff, data)
+}
+
+func (f *winfspFS) Opendir(path string) (int, uint64) {
+	ctx := f.context()
+	entry, errc := f.find(ctx, path)
+	if errc != 0 {
+		return errc, ^uint64(0)
+	}
+	if !isDir(entry) {
+		return -fuse.ENOTDIR, ^uint64(0)
+	}
+	return 0, 0
+}
+
+func (f *winfspFS) Readdir(path string, fill func(name string, stat *fuse.Stat_t, ofst int64) bool, ofst int64, fh uint64) int {
+	ctx := f.context()
+	entry, errc := f.find(ctx, path)
+	if errc != 0 {
+		return errc
+	}
+	parent, ok := entry.(plugin.Parent)
+	if !ok || !isDir(entry) {
+		return -fuse.ENOTDIR
+	}
+	activity.Record(ctx, "FUSE: List %v", path)
+	entries, err := plugin.ListWithAnalytics(ctx, parent)
+	if err != nil {
+		activity.Warnf(ctx, "FUSE: List %v errored: %v", path, err)
+		return -fuse.EIO
+	}
+	cnames := make([]string, 0, entries.Len())
+	entries.Range(func(cname string, _ plugin.Entry) bool {
+		cnames = append(cnames, cname)
+		return true
+	})
+	sort.Strings(cnames)
+
+	fill(".", nil, 0)
+	fill("..", nil, 0)
+	for _, cname := range cnames {
+		if !fill(cname, nil, 0) {
+			break
+		}
+	}
+	return 0
+}
+
+func (f *winfspFS) Readlink(path string) (int, string) {
+	ctx := f.context()
+	entry, errc := f.find(ctx, path)
+	if errc != 0 {
+		return errc, ""
+	}
+	link, ok := entry.(*plugin.Symlink)
+	if !ok {
+		return -fuse.EINVAL, ""
+	}
+	return 0, link.Target()
+}
+
+func (f *winfspFS) Statfs(path string, stat *fuse.Statfs_t) int {
+	stat.Bsize = 4096
+	stat.Frsize = 4096
+	stat.Namemax = 255
+	return 0
+}
+
+// mount mounts the filesystem and serves it until it's unmounted. WinFsp's
+// FUSE layer panics when WinFsp isn't installed, so the panic's returned as an
+// error.
+func mount(host *fuse.FileSystemHost, mountpoint string) (ok bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v; WinFsp can be installed from https://winfsp.dev", r)
+		}
+	}()
+	// uid=-1,gid=-1 maps the files' owner to the user running Wash.
+	return host.Mount(mountpoint, []string{"-o", "uid=-1,gid=-1"}), nil
+}
+
+// ServeFuseFS starts serving the filesystem at the mountpoint through
+// WinFsp. The mountpoint's either a drive letter like W: or a directory that
+// doesn't exist. It returns the same values as the other platforms'
+// ServeFuseFS.
+func ServeFuseFS(
+	filesys *plugin.Registry,
+	mountpoint string,
+	analyticsClient analytics.Client,
+) (chan<- context.Context, <-chan struct{}, error) {
+	log.Infof("FUSE: Mounting at %v with WinFsp", mountpoint)
+	// WinFsp creates directory mountpoints itself, so an empty directory
+	// that's already there is removed.
+	if err := os.Remove(mountpoint); err != nil && !os.IsNotExist(err) {
+		log.Debugf("FUSE: Removing %v errored: %v", mountpoint, err)
+	}
+	winfsp := &winfspFS{
+		registry:        filesys,
+		analyticsClient: analyticsClient,
+		readyCh:         make(chan struct{}),
+		handles:         make(map[uint64]*winfspHandle),
+	}
+	host := fuse.NewFileSystemHost(winfsp)
+
+	mountErrCh := make(chan error, 1)
+	serverExitedCh := make(chan struct{})
+	go func() {
+		defer close(serverExitedCh)
+		ok, err := mount(host, mountpoint)
+		if err == nil && !ok {
+			err = fmt.Errorf("WinFsp failed to mount %v", mountpoint)
+		}
+		mountErrCh <- err
+		log.Infof("FUSE: Serve complete")
+	}()
+
+	select {
+	case <-winfsp.readyCh:
+	case err := <-mountErrCh:
+		if err == nil {
+			err = fmt.Errorf("WinFsp unmounted %v before it was ready", mountpoint)
+		}
+		return nil, nil, err
+	}
+
+	// Clean-up
+	stopCh := make(chan context.Context)
+	stoppedCh := make(chan struct{})
+	go func() {
+		select {
+		case <-stopCh:
+			log.Infof("FUSE: Shutting down the server")
+			log.Infof("FUSE: Unmounting %v", mountpoint)
+			if !host.Unmount() {
+				log.Warnf("FUSE: Unmounting %v failed", mountpoint)
+			}
+		case <-serverExitedCh:
+			// Server exited on its own, fallthrough.
+		}
+		<-serverExitedCh
+		if err := <-mountErrCh; err != nil {
+			log.Warnf("FUSE: Mount errored with: %v", err)
+		}
+		log.Infof("FUSE: Server shutdown complete")
+		close(stoppedCh)
+	}()
+
+	return stopCh, stoppedCh, nil
+}
@@ -0,0 +1,176 @@
+//go:build !windows
+// +build !windows
+
+package fuse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"bazil.org/fuse"
+	"bazil.org/fuse/fs"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// ==== FUSE extended attributes ====
+
+// xattrPrefix prefixes the names of the extended attributes that expose an
+// entry's custom attributes, e.g. user.wash.generation. The user namespace is
+// the only one that unprivileged users can read on Linux.
+const xattrPrefix = "user.wash."
+
+// xattrMetaPrefix prefixes the names of the extended attributes that expose an
+// entry's metadata. The rest of the name is the value's path in the metadata,
+// with its keys and array indexes separated by dots, e.g.
+// user.wash.meta.State.Name or user.wash.meta.Tags.0.Key.
+const xattrMetaPrefix = xattrPrefix + "meta."
+
+// xattrs returns the entry's custom attributes and partial metadata as extended
+// attributes. The partial metadata's used so that file browsers, which list
+// every file's extended attributes, don't trigger a metadata request per file.
+func xattrs(entry plugin.Entry) map[string][]byte {
+	attr := plugin.Attributes(entry)
+	custom := attr.CustomAttributes()
+	xattrs := make(map[string][]byte, len(custom))
+	for name, value := range custom {
+		xattrs[xattrPrefix+name] = xattrValue(value)
+	}
+	addMetaXattrs(xattrs, "", plugin.PartialMetadata(entry))
+	return xattrs
+}
+
+// addMetaXattrs adds an extended attribute for each scalar in value, which is
+// at path in the entry's metadata. Empty objects and arrays are added as "{}"
+// and "[]" so that they're still visible.
+func addMetaXattrs(xattrs map[string][]byte, path string, value interface{}) {
+	join := func(key string) string {
+		if path == "" {
+			return key
+		}
+		return path + "." + key
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 && path != "" {
+			xattrs[xattrMetaPrefix+path] = []byte("{}")
+		}
+		for key, child := range v {
+			addMetaXattrs(xattrs, join(key), child)
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			xattrs[xattrMetaPrefix+path] = []byte("[]")
+		}
+		for i, child := range v {
+			addMetaXattrs(xattrs, join(strconv.Itoa(i)), child)
+		}
+	default:
+		xattrs[xattrMetaPrefix+path] = xattrValue(v)
+	}
+}
+
+// xattrValue formats value as an extended attribute's value. Strings aren't
+// quoted so that they're displayed as-is by tools like getfattr.
+func xattrValue(value interface{}) []byte {
+	switch v := value.(type) {
+	case string:
+		return []byte(v)
+	case bool:
+		return []byte(strconv.FormatBool(v))
+	case float64:
+		return []byte(strconv.FormatFloat(v, 'f', -1, 64))
+	case time.Time:
+		return []byte(v.Format(time.RFC3339))
+	case nil:
+		return []byte("null")
+	default:
+		bytes, err := json.Marshal(v)
+		if err != nil {
+			return []byte(fmt.Sprintf("%v", v))
+		}
+		return bytes
+	}
+}
+
+func getxattr(ctx context.Context, node fuseNode, req *fuse.GetxattrRequest, resp *fuse.GetxattrResponse) error {
+	if !strings.HasPrefix(req.Name, xattrPrefix) {
+		// Avoid logging lookups of the security.* and system.* attributes that the
+		// kernel and tools like ls make for every file.
+		return fuse.ErrNoXattr
+	}
+	value, ok := xattrs(node.entry)[req.Name]
+	if !ok && strings.HasPrefix(req.Name, xattrMetaPrefix) {
+		// The value may only be in the entry's full metadata. Only a requested
+		// value is looked up there since it can be expensive to fetch.
+		meta, err := plugin.Metadata(ctx, node.entry)
+		if err != nil {
+			activity.Warnf(ctx, "FUSE: Getxattr %v on %v errored: %v", req.Name, &node, err)
+			return fuse.ErrNoXattr
+		}
+		metaXattrs := make(map[string][]byte)
+		addMetaXattrs(metaXattrs, "", meta)
+		value, ok = metaXattrs[req.Name]
+	}
+	if !ok {
+		return fuse.ErrNoXattr
+	}
+	resp.Xattr = value
+	activity.Record(ctx, "FUSE: Getxattr %v on %v: %q", req.Name, &node, value)
+	return nil
+}
+
+func listxattr(ctx context.Context, node fuseNode, resp *fuse.ListxattrResponse) {
+	names := make([]string, 0)
+	for name := range xattrs(node.entry) {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	resp.Append(names...)
+	activity.Record(ctx, "FUSE: Listxattr %v: %v", &node, names)
+}
+
+var _ = fs.NodeGetxattrer(&file{})
+var _ = fs.NodeListxattrer(&file{})
+
+// Getxattr returns the value of one of the file's custom attributes or metadata values.
+func (f *file) Getxattr(ctx context.Context, req *fuse.GetxattrRequest, resp *fuse.GetxattrResponse) (err error) {
+	defer recordOp(ctx, f, "Getxattr", time.Now(), &err)
+	defer recoverPanic(ctx, f, "Getxattr", &err)
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	return getxattr(ctx, f.fuseNode, req, resp)
+}
+
+// Listxattr lists the file's custom attributes and partial metadata values.
+func (f *file) Listxattr(ctx context.Context, req *fuse.ListxattrRequest, resp *fuse.ListxattrResponse) (err error) {
+	defer recordOp(ctx, f, "Listxattr", time.Now(), &err)
+	defer recoverPanic(ctx, f, "Listxattr", &err)
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	listxattr(ctx, f.fuseNode, resp)
+	return nil
+}
+
+var _ = fs.NodeGetxattrer(&dir{})
+var _ = fs.NodeListxattrer(&dir{})
+
+// Getxattr returns the value of one of the directory's custom attributes or metadata values.
+func (d *dir) Getxattr(ctx context.Context, req *fuse.GetxattrRequest, resp *fuse.GetxattrResponse) (err error) {
+	defer recordOp(ctx, d, "Getxattr", time.Now(), &err)
+	defer recoverPanic(ctx, d, "Getxattr", &err)
+	return getxattr(ctx, d.fuseNode, req, resp)
+}
+
+// Listxattr lists the directory's custom attributes and partial metadata values.
+func (d *dir) Listxattr(ctx context.Context, req *fuse.ListxattrRequest, resp *fuse.ListxattrResponse) (err error) {
+	defer recordOp(ctx, d, "Listxattr", time.Now(), &err)
+	defer recoverPanic(ctx, d, "Listxattr", &err)
+	listxattr(ctx, d.fuseNode, resp)
+	return nil
+}
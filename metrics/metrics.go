@@ -0,0 +1,232 @@
+// Package metrics records the performance of the plugins' methods. It counts
+// each plugin's method calls, their errors and their latencies, and keeps track
+// of the operations that are in progress. It also aggregates latency histograms
+// of other operations, like FUSE requests. The API's /stats endpoint reports
+// them.
+package metrics
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// StartTime is the time at which the daemon started.
+var StartTime = time.Now()
+
+// MethodStats summarizes the calls to one of a plugin's methods. Latencies are
+// in nanoseconds.
+type MethodStats struct {
+	Method         string        `json:"method"`
+	Count          uint64        `json:"count"`
+	Errors         uint64        `json:"errors"`
+	AverageLatency time.Duration `json:"average_latency"`
+	MaxLatency     time.Duration `json:"max_latency"`
+	totalLatency   time.Duration
+}
+
+// PluginStats summarizes the calls to a plugin's methods.
+type PluginStats struct {
+	Plugin  string        `json:"plugin"`
+	Methods []MethodStats `json:"methods"`
+}
+
+// Operation describes an operation that's in progress, like a List call or an
+// open stream.
+type Operation struct {
+	Plugin  string    `json:"plugin"`
+	Method  string    `json:"method"`
+	Entry   string    `json:"entry"`
+	Started time.Time `json:"started"`
+}
+
+// LatencyBuckets are the upper bounds of the latency histograms' buckets.
+var LatencyBuckets = []time.Duration{
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	5 * time.Second,
+}
+
+// Histogram summarizes the latencies of an operation. Buckets[i] counts the
+// latencies that are at most LatencyBuckets[i] but more than the previous
+// bound, and Slower counts the latencies that exceed the last bound. Latencies
+// are in nanoseconds.
+type Histogram struct {
+	Name           string        `json:"name"`
+	Count          uint64        `json:"count"`
+	Errors         uint64        `json:"errors"`
+	AverageLatency time.Duration `json:"average_latency"`
+	MaxLatency     time.Duration `json:"max_latency"`
+	Buckets        []uint64      `json:"buckets"`
+	Slower         uint64        `json:"slower"`
+	totalLatency   time.Duration
+}
+
+var registry = struct {
+	mux        sync.Mutex
+	methods    map[string]map[string]*MethodStats
+	active     map[*Operation]struct{}
+	histograms map[string]*Histogram
+}{
+	methods:    make(map[string]map[string]*MethodStats),
+	active:     make(map[*Operation]struct{}),
+	histograms: make(map[string]*Histogram),
+}
+
+// Start records the start of a call to the plugin's method on the given entry.
+// The call is active until the returned function's called with the call's
+// error, which records its latency.
+func Start(plugin string, method string, entry string) func(err error) {
+	op := begin(plugin, method, entry)
+	var once sync.Once
+	return func(err error) {
+		once.Do(func() {
+			latency := time.Since(op.Started)
+
+			registry.mux.Lock()
+			defer registry.mux.Unlock()
+			delete(registry.active, op)
+			methods, ok := registry.methods[plugin]
+			if !ok {
+				methods = make(map[string]*MethodStats)
+				registry.methods[plugin] = methods
+			}
+			stats, ok := methods[method]
+			if !ok {
+				stats = &MethodStats{Method: method}
+				methods[method] = stats
+			}
+			stats.Count++
+			if err != nil {
+				stats.Errors++
+			}
+			stats.totalLatency += latency
+			if latency > stats.MaxLatency {
+				stats.MaxLatency = latency
+			}
+		})
+	}
+}
+
+// TrackUntilDone records an operation that's active until ctx is done, like
+// an open stream. It doesn't affect the method's stats. It's a no-op if ctx
+// can't be cancelled.
+func TrackUntilDone(ctx context.Context, plugin string, method string, entry string) {
+	if ctx.Done() == nil {
+		return
+	}
+	op := begin(plugin, method, entry)
+	go func() {
+		<-ctx.Done()
+		registry.mux.Lock()
+		delete(registry.active, op)
+		registry.mux.Unlock()
+	}()
+}
+
+func begin(plugin string, method string, entry string) *Operation {
+	op := &Operation{Plugin: plugin, Method: method, Entry: entry, Started: time.Now()}
+	registry.mux.Lock()
+	registry.active[op] = struct{}{}
+	registry.mux.Unlock()
+	return op
+}
+
+// Observe adds the latency of a call to the named operation to the operation's
+// histogram.
+func Observe(name string, latency time.Duration, err error) {
+	registry.mux.Lock()
+	defer registry.mux.Unlock()
+	h, ok := registry.histograms[name]
+	if !ok {
+		h = &Histogram{Name: name, Buckets: make([]uint64, len(LatencyBuckets))}
+		registry.histograms[name] = h
+	}
+	h.Count++
+	if err != nil {
+		h.Errors++
+	}
+	h.totalLatency += latency
+	if latency > h.MaxLatency {
+		h.MaxLatency = latency
+	}
+	for i, bound := range LatencyBuckets {
+		if latency <= bound {
+			h.Buckets[i]++
+			return
+		}
+	}
+	h.Slower++
+}
+
+// Histograms returns the observed operations' histograms, sorted by name.
+func Histograms() []Histogram {
+	registry.mux.Lock()
+	defer registry.mux.Unlock()
+
+	histograms := make([]Histogram, 0, len(registry.histograms))
+	for _, h := range registry.histograms {
+		histogram := *h
+		histogram.Buckets = append([]uint64{}, h.Buckets...)
+		histogram.AverageLatency = h.totalLatency / time.Duration(h.Count)
+		histograms = append(histograms, histogram)
+	}
+	sort.Slice(histograms, func(i, j int) bool {
+		return histograms[i].Name < histograms[j].Name
+	})
+	return histograms
+}
+
+// Plugins returns the stats of each plugin's methods, sorted by plugin and
+// method.
+func Plugins() []PluginStats {
+	registry.mux.Lock()
+	defer registry.mux.Unlock()
+
+	plugins := make([]PluginStats, 0, len(registry.methods))
+	for plugin, methods := range registry.methods {
+		pluginStats := PluginStats{Plugin: plugin, Methods: make([]MethodStats, 0, len(methods))}
+		for _, stats := range methods {
+			methodStats := *stats
+			methodStats.AverageLatency = stats.totalLatency / time.Duration(stats.Count)
+			pluginStats.Methods = append(pluginStats.Methods, methodStats)
+		}
+		sort.Slice(pluginStats.Methods, func(i, j int) bool {
+			return pluginStats.Methods[i].Method < pluginStats.Methods[j].Method
+		})
+		plugins = append(plugins, pluginStats)
+	}
+	sort.Slice(plugins, func(i, j int) bool {
+		return plugins[i].Plugin < plugins[j].Plugin
+	})
+	return plugins
+}
+
+// Active returns the operations that are in progress, oldest first.
+func Active() []Operation {
+	registry.mux.Lock()
+	defer registry.mux.Unlock()
+
+	ops := make([]Operation, 0, len(registry.active))
+	for op := range registry.active {
+		ops = append(ops, *op)
+	}
+	sort.Slice(ops, func(i, j int) bool {
+		return ops[i].Started.Before(ops[j].Started)
+	})
+	return ops
+}
+
+// Reset clears the recorded stats. Active operations are kept.
+func Reset() {
+	registry.mux.Lock()
+	defer registry.mux.Unlock()
+	registry.methods = make(map[string]map[string]*MethodStats)
+	registry.histograms = make(map[string]*Histogram)
+}
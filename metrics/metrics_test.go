@@ -0,0 +1,76 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStart(t *testing.T) {
+	Reset()
+	defer Reset()
+
+	done := Start("docker", "List", "/docker/containers")
+	if assert.Len(t, Active(), 1) {
+		assert.Equal(t, "/docker/containers", Active()[0].Entry)
+	}
+	done(nil)
+	// Calling done again doesn't double-count the call
+	done(nil)
+	assert.Empty(t, Active())
+
+	Start("docker", "List", "/docker/volumes")(fmt.Errorf("failed"))
+	Start("docker", "Exec", "/docker/containers/foo")(nil)
+	Start("aws", "Read", "/aws/foo")(nil)
+
+	plugins := Plugins()
+	if assert.Len(t, plugins, 2) {
+		assert.Equal(t, "aws", plugins[0].Plugin)
+		assert.Equal(t, "docker", plugins[1].Plugin)
+		methods := plugins[1].Methods
+		if assert.Len(t, methods, 2) {
+			assert.Equal(t, "Exec", methods[0].Method)
+			assert.Equal(t, "List", methods[1].Method)
+			assert.Equal(t, uint64(2), methods[1].Count)
+			assert.Equal(t, uint64(1), methods[1].Errors)
+			assert.True(t, methods[1].MaxLatency >= methods[1].AverageLatency)
+		}
+	}
+}
+
+func TestTrackUntilDone(t *testing.T) {
+	TrackUntilDone(context.Background(), "docker", "Stream", "/docker/containers/foo/log")
+	assert.Empty(t, Active())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	TrackUntilDone(ctx, "docker", "Stream", "/docker/containers/foo/log")
+	assert.Len(t, Active(), 1)
+	cancel()
+	assert.Eventually(t, func() bool { return len(Active()) == 0 }, time.Second, 10*time.Millisecond)
+}
+
+func TestObserve(t *testing.T) {
+	Reset()
+	defer Reset()
+
+	Observe("FUSE Read", 500*time.Microsecond, nil)
+	Observe("FUSE Read", 20*time.Millisecond, fmt.Errorf("failed"))
+	Observe("FUSE Read", time.Minute, nil)
+	Observe("FUSE Lookup", time.Millisecond, nil)
+
+	histograms := Histograms()
+	if assert.Len(t, histograms, 2) {
+		assert.Equal(t, "FUSE Lookup", histograms[0].Name)
+		assert.Equal(t, uint64(1), histograms[0].Buckets[0])
+
+		read := histograms[1]
+		assert.Equal(t, uint64(3), read.Count)
+		assert.Equal(t, uint64(1), read.Errors)
+		assert.Equal(t, time.Minute, read.MaxLatency)
+		assert.Equal(t, []uint64{1, 0, 0, 1, 0, 0, 0, 0}, read.Buckets)
+		assert.Equal(t, uint64(1), read.Slower)
+	}
+}
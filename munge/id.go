@@ -0,0 +1,38 @@
+package munge
+
+import (
+	"fmt"
+	"math"
+)
+
+func intToID(id int64) (uint32, error) {
+	if id < 0 {
+		return 0, fmt.Errorf("%v is a negative ID", id)
+	}
+	if id > math.MaxUint32 {
+		return 0, fmt.Errorf("%v is too large to be an ID", id)
+	}
+	return uint32(id), nil
+}
+
+// ToID converts v to a uint32 that's meant to represent a user
+// or group ID
+func ToID(v interface{}) (uint32, error) {
+	switch id := v.(type) {
+	case uint32:
+		return id, nil
+	case int:
+		return intToID(int64(id))
+	case int32:
+		return intToID(int64(id))
+	case int64:
+		return intToID(id)
+	case float64:
+		if id != float64(int64(id)) {
+			return 0, fmt.Errorf("%v is a decimal ID", id)
+		}
+		return intToID(int64(id))
+	default:
+		return 0, fmt.Errorf("%v is not a valid ID type. Valid ID types are uint32, int, int32, int64, float64", v)
+	}
+}
@@ -0,0 +1,32 @@
+package munge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type IDTestSuite struct {
+	MungeTestSuite
+}
+
+func (suite *IDTestSuite) TestToID() {
+	suite.mungeFunc = func(v interface{}) (interface{}, error) {
+		return ToID(v)
+	}
+	suite.runTestCases(
+		nTC(uint32(10), uint32(10)),
+		nETC(int(-1), "-1.*negative.*ID"),
+		nTC(int(10), uint32(10)),
+		nTC(int32(10), uint32(10)),
+		nTC(int64(10), uint32(10)),
+		nETC(int64(1<<32), "4294967296.*too large.*ID"),
+		nETC(float64(10.5), "10.5.*decimal.*ID"),
+		nTC(float64(10.0), uint32(10)),
+		nETC("foo", "foo.*valid.*ID.*uint32.*int.*int32.*int64.*float64"),
+	)
+}
+
+func TestToID(t *testing.T) {
+	suite.Run(t, new(IDTestSuite))
+}
@@ -0,0 +1,121 @@
+package netfs
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+var startTime = time.Now()
+
+// FileType is a node's file type.
+type FileType int
+
+// The file types
+const (
+	Regular FileType = iota
+	Directory
+	Symlink
+)
+
+// Attributes are a node's attributes. Times that the plugin doesn't know are
+// the time wash started.
+type Attributes struct {
+	Type FileType
+	// Mode is the node's permission bits, including the setuid, setgid and
+	// sticky bits. It doesn't include the file type.
+	Mode  uint32
+	UID   uint32
+	GID   uint32
+	Size  uint64
+	Atime time.Time
+	Mtime time.Time
+	Ctime time.Time
+	Btime time.Time
+}
+
+// NeedsSize returns true if the node's size is only known once its content's
+// read.
+func (n *Node) NeedsSize() bool {
+	attr := plugin.Attributes(n.Entry)
+	return !n.IsDir() && plugin.ReadAction().IsSupportedOn(n.Entry) && !attr.HasSize()
+}
+
+// Attributes returns the node's attributes. Clients only read up to a file's
+// size, so readable entries without a size have their content read to get it.
+// That can be expensive, so callers that can omit the attributes set sized to
+// false and Attributes returns false for those entries.
+func (n *Node) Attributes(ctx context.Context, sized bool) (Attributes, bool) {
+	attr := plugin.Attributes(n.Entry)
+	a := Attributes{
+		UID:   UID,
+		GID:   GID,
+		Atime: startTime,
+		Mtime: startTime,
+		Ctime: startTime,
+		Btime: startTime,
+	}
+
+	var mode os.FileMode
+	if n.IsSymlink() {
+		a.Type, mode = Symlink, 0777
+	} else if n.IsDir() {
+		a.Type, mode = Directory, 0550
+	} else {
+		a.Type = Regular
+		if plugin.ReadAction().IsSupportedOn(n.Entry) || plugin.StreamAction().IsSupportedOn(n.Entry) {
+			mode = 0440
+		}
+	}
+	if attr.HasMode() {
+		mode = attr.Mode()
+	}
+	a.Mode = uint32(mode.Perm())
+	if mode&os.ModeSetuid != 0 {
+		a.Mode |= 04000
+	}
+	if mode&os.ModeSetgid != 0 {
+		a.Mode |= 02000
+	}
+	if mode&os.ModeSticky != 0 {
+		a.Mode |= 01000
+	}
+
+	if attr.HasSize() {
+		a.Size = attr.Size()
+	} else if n.NeedsSize() {
+		if !sized {
+			return a, false
+		}
+		size, err := plugin.Size(ctx, n.Entry)
+		if err != nil {
+			activity.Warnf(ctx, "Getting the size of %v errored: %v", n, err)
+		}
+		a.Size = size
+	}
+
+	if attr.HasAtime() {
+		a.Atime = attr.Atime()
+	}
+	if attr.HasMtime() {
+		a.Mtime = attr.Mtime()
+	}
+	if attr.HasCtime() {
+		a.Ctime = attr.Ctime()
+	}
+	if attr.HasCrtime() {
+		a.Btime = attr.Crtime()
+	}
+	// Entries are owned by the user running Wash unless the plugin knows their
+	// real owner.
+	if attr.HasUID() {
+		a.UID = attr.UID()
+	}
+	if attr.HasGID() {
+		a.GID = attr.GID()
+	}
+	return a, true
+}
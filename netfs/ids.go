@@ -0,0 +1,57 @@
+package netfs
+
+import (
+	"net"
+	"os"
+	"os/user"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func getIDs() (uint32, uint32) {
+	me, err := user.Current()
+	if err != nil {
+		log.Infof("Unable to fetch user: %v", err)
+		return 0, 0
+	}
+	uid, err := strconv.ParseUint(me.Uid, 10, 32)
+	if err != nil {
+		log.Infof("Unable to parse uid: %v", err)
+		return 0, 0
+	}
+	gid, err := strconv.ParseUint(me.Gid, 10, 32)
+	if err != nil {
+		log.Infof("Unable to parse gid: %v", err)
+		return 0, 0
+	}
+	return uint32(uid), uint32(gid)
+}
+
+// UID and GID are the IDs of the user running wash. Entries are owned by them
+// unless the plugin knows their real owner.
+var UID, GID = getIDs()
+
+// AllowedUIDs returns the UIDs whose requests are answered. That's wash's UID
+// and, if wash is run with sudo, the UID of the user that ran it. Root makes
+// the mount's requests in that case.
+func AllowedUIDs() map[uint32]bool {
+	allowed := map[uint32]bool{UID: true}
+	if UID == 0 {
+		if sudoUID, err := strconv.ParseUint(os.Getenv("SUDO_UID"), 10, 32); err == nil {
+			allowed[uint32(sudoUID)] = true
+		}
+	}
+	return allowed
+}
+
+// maxPrivilegedPort is the highest port that only root can bind to.
+const maxPrivilegedPort = 1023
+
+// FromPrivilegedPort returns true if the connection's from a privileged port,
+// i.e. it was made by root on behalf of the kernel's client instead of by a
+// user's process.
+func FromPrivilegedPort(conn net.Conn) bool {
+	addr, ok := conn.RemoteAddr().(*net.TCPAddr)
+	return ok && addr.Port <= maxPrivilegedPort
+}
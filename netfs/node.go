@@ -0,0 +1,130 @@
+// Package netfs contains what the NFS and 9P servers share: the nodes that
+// their clients refer to, the nodes' listings and attributes, and the checks
+// that limit the servers to wash's user. The servers only differ in how they
+// encode them.
+package netfs
+
+import (
+	"context"
+	"hash/fnv"
+	"sort"
+
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// Node is an entry that a client refers to. Like the FUSE filesystem's nodes,
+// it remembers its parent so that the entry can be re-discovered to get fresh
+// data.
+type Node struct {
+	Entry  plugin.Entry
+	Parent *Node
+}
+
+func (n *Node) String() string {
+	return plugin.ID(n.Entry)
+}
+
+// FileID returns the node's file ID, which is a hash of its entry's ID so
+// that it stays the same across server restarts.
+func (n *Node) FileID() uint64 {
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(plugin.ID(n.Entry)))
+	return hash.Sum64()
+}
+
+// IsSymlink returns true if the node's a symlink.
+func (n *Node) IsSymlink() bool {
+	_, ok := n.Entry.(*plugin.Symlink)
+	return ok
+}
+
+// IsDir returns true if the node's a directory.
+func (n *Node) IsDir() bool {
+	return !n.IsSymlink() && plugin.ListAction().IsSupportedOn(n.Entry)
+}
+
+// Refind returns the node with its entry re-discovered through its closest
+// ancestor that isn't prefetched. The ancestors' listings are cached, so this
+// is usually cheap.
+func (n *Node) Refind(ctx context.Context) (*Node, error) {
+	cur, segments := n.Parent, []string{plugin.CName(n.Entry)}
+	for cur != nil {
+		if !plugin.IsPrefetched(cur.Entry) {
+			entry, err := plugin.FindEntry(ctx, cur.Entry, segments)
+			if err != nil {
+				return nil, err
+			}
+			return &Node{Entry: entry, Parent: n.Parent}, nil
+		}
+		segments = append([]string{plugin.CName(cur.Entry)}, segments...)
+		cur = cur.Parent
+	}
+	return n, nil
+}
+
+// Children returns the directory's children sorted by their cname.
+func (n *Node) Children(ctx context.Context) ([]*Node, error) {
+	entries, err := plugin.ListWithAnalytics(ctx, n.Entry.(plugin.Parent))
+	if err != nil {
+		return nil, err
+	}
+	children := make([]*Node, 0, entries.Len())
+	entries.Range(func(cname string, entry plugin.Entry) bool {
+		children = append(children, &Node{Entry: entry, Parent: n})
+		return true
+	})
+	sort.Slice(children, func(i, j int) bool {
+		return plugin.CName(children[i].Entry) < plugin.CName(children[j].Entry)
+	})
+	return children, nil
+}
+
+// Child returns the directory's child with the cname. It also handles "." and
+// "..". It returns false if there isn't one.
+func (n *Node) Child(ctx context.Context, cname string) (*Node, bool, error) {
+	switch cname {
+	case ".":
+		return n, true, nil
+	case "..":
+		return n.parentOrSelf(), true, nil
+	}
+	children, err := n.Children(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	for _, child := range children {
+		if plugin.CName(child.Entry) == cname {
+			return child, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+// parentOrSelf returns the node's parent. The root is its own parent.
+func (n *Node) parentOrSelf() *Node {
+	if n.Parent == nil {
+		return n
+	}
+	return n.Parent
+}
+
+// DirEntry is an entry in a directory listing.
+type DirEntry struct {
+	Name string
+	Node *Node
+}
+
+// Listing returns the directory's listing. It starts with "." and "..", so
+// the children's cookies (or offsets) are their indexes plus one.
+func (n *Node) Listing(ctx context.Context) ([]DirEntry, error) {
+	children, err := n.Children(ctx)
+	if err != nil {
+		return nil, err
+	}
+	listing := make([]DirEntry, 0, len(children)+2)
+	listing = append(listing, DirEntry{".", n}, DirEntry{"..", n.parentOrSelf()})
+	for _, child := range children {
+		listing = append(listing, DirEntry{plugin.CName(child.Entry), child})
+	}
+	return listing, nil
+}
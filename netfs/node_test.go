@@ -0,0 +1,147 @@
+package netfs
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/puppetlabs/wash/datastore"
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/stretchr/testify/suite"
+)
+
+type testRoot struct {
+	plugin.EntryBase
+	children []plugin.Entry
+}
+
+func (r *testRoot) Init(map[string]interface{}) error {
+	return nil
+}
+
+func (r *testRoot) Schema() *plugin.EntrySchema {
+	return nil
+}
+
+func (r *testRoot) ChildSchemas() []*plugin.EntrySchema {
+	return nil
+}
+
+func (r *testRoot) List(context.Context) ([]plugin.Entry, error) {
+	return r.children, nil
+}
+
+type testFile struct {
+	plugin.EntryBase
+	content string
+}
+
+func (f *testFile) Schema() *plugin.EntrySchema {
+	return nil
+}
+
+func (f *testFile) Read(context.Context) ([]byte, error) {
+	return []byte(f.content), nil
+}
+
+type nodeTestSuite struct {
+	suite.Suite
+	ctx  context.Context
+	root *Node
+}
+
+func (suite *nodeTestSuite) SetupTest() {
+	suite.ctx = plugin.SetTestCache(datastore.NewMemCache())
+	root := &testRoot{EntryBase: plugin.NewEntry("mine")}
+	root.SetTestID("/mine")
+
+	file := &testFile{EntryBase: plugin.NewEntry("file"), content: "hello"}
+	mtime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	file.Attributes().SetMtime(mtime)
+	setuid := &testFile{EntryBase: plugin.NewEntry("setuid")}
+	setuid.Attributes().SetMode(os.ModeSetuid | os.ModeSticky | 0755).SetSize(3).SetUID(UID + 1)
+	root.children = []plugin.Entry{file, plugin.NewSymlink("file", "link"), setuid}
+
+	registry := plugin.NewRegistry()
+	suite.NoError(registry.RegisterPlugin(root, map[string]interface{}{}))
+	suite.root = &Node{Entry: registry}
+}
+
+func (suite *nodeTestSuite) TearDownTest() {
+	plugin.UnsetTestCache()
+}
+
+func (suite *nodeTestSuite) find(segments ...string) *Node {
+	n := suite.root
+	for _, segment := range segments {
+		child, ok, err := n.Child(suite.ctx, segment)
+		suite.Require().NoError(err)
+		suite.Require().True(ok, "%v not found in %v", segment, n)
+		n = child
+	}
+	return n
+}
+
+func (suite *nodeTestSuite) TestListing() {
+	mine := suite.find("mine")
+	listing, err := mine.Listing(suite.ctx)
+	suite.Require().NoError(err)
+	var names []string
+	for _, e := range listing {
+		names = append(names, e.Name)
+	}
+	suite.Equal([]string{".", "..", "file", "link", "setuid"}, names)
+	suite.Equal(mine, listing[0].Node)
+	suite.Equal(suite.root, listing[1].Node)
+
+	// The root is its own parent.
+	suite.Equal(suite.root, suite.find(".."))
+	_, ok, err := mine.Child(suite.ctx, "missing")
+	suite.NoError(err)
+	suite.False(ok)
+}
+
+func (suite *nodeTestSuite) TestAttributes() {
+	a, ok := suite.find("mine").Attributes(suite.ctx, false)
+	suite.True(ok)
+	suite.Equal(Directory, a.Type)
+	suite.Equal(uint32(0550), a.Mode)
+	suite.Equal(UID, a.UID)
+	suite.Equal(startTime, a.Mtime)
+
+	// Entries without a size need their content read to get it.
+	file := suite.find("mine", "file")
+	_, ok = file.Attributes(suite.ctx, false)
+	suite.False(ok)
+	a, ok = file.Attributes(suite.ctx, true)
+	suite.True(ok)
+	suite.Equal(Regular, a.Type)
+	suite.Equal(uint32(0440), a.Mode)
+	suite.Equal(uint64(5), a.Size)
+	suite.Equal(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), a.Mtime)
+	suite.Equal(startTime, a.Atime)
+
+	a, _ = suite.find("mine", "link").Attributes(suite.ctx, false)
+	suite.Equal(Symlink, a.Type)
+	suite.Equal(uint32(0777), a.Mode)
+
+	a, ok = suite.find("mine", "setuid").Attributes(suite.ctx, false)
+	suite.True(ok)
+	suite.Equal(uint32(05755), a.Mode)
+	suite.Equal(uint64(3), a.Size)
+	suite.Equal(UID+1, a.UID)
+	suite.Equal(GID, a.GID)
+}
+
+func (suite *nodeTestSuite) TestRefind() {
+	file := suite.find("mine", "file")
+	refound, err := file.Refind(suite.ctx)
+	suite.Require().NoError(err)
+	suite.Equal(plugin.ID(file.Entry), plugin.ID(refound.Entry))
+	suite.Equal(file.Parent, refound.Parent)
+}
+
+func TestNode(t *testing.T) {
+	suite.Run(t, new(nodeTestSuite))
+}
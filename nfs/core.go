@@ -0,0 +1,198 @@
+// Package nfs serves wash's filesystem over NFS v3. It's an alternative to the
+// FUSE filesystem for systems where FUSE isn't available.
+//
+// The server only listens on the loopback interface, and it only answers calls
+// from privileged ports with the credentials of the user that started wash, so
+// the mount's private to that user like a FUSE mount is. It only implements
+// the parts of the protocol that are needed for a read-only mount: the MOUNT
+// program that gives the client the root's file handle and the NFS program's
+// procedures that read the filesystem. The client is told where to find both
+// programs when it's mounted, so a portmapper isn't needed. Locking isn't
+// supported either, so the mount's made with local locks.
+package nfs
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/analytics"
+	"github.com/puppetlabs/wash/netfs"
+	"github.com/puppetlabs/wash/plugin"
+	log "github.com/sirupsen/logrus"
+)
+
+// server serves the registry's entries.
+type server struct {
+	root        *netfs.Node
+	handles     *handleTable
+	programs    map[uint32]*program
+	allowedUIDs map[uint32]bool
+}
+
+func newServer(registry *plugin.Registry) *server {
+	s := &server{
+		root:        &netfs.Node{Entry: registry},
+		handles:     newHandleTable(),
+		allowedUIDs: netfs.AllowedUIDs(),
+	}
+	s.handles.pin(s.root)
+	s.programs = map[uint32]*program{
+		mountProgram: {name: "MOUNT", version: mountVersion, procedures: s.mountProcedures()},
+		nfsProgram:   {name: "NFS", version: nfsVersion, procedures: s.nfsProcedures()},
+	}
+	return s
+}
+
+// serveConn answers the calls on the connection until it's closed. Clients
+// send several calls at once, so each call's answered in its own goroutine.
+func (s *server) serveConn(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+	var writeMux sync.Mutex
+	r := bufio.NewReader(conn)
+	for {
+		msg, err := readRecord(r)
+		if err != nil {
+			if err != io.EOF {
+				log.Debugf("NFS: Closing the connection from %v: %v", conn.RemoteAddr(), err)
+			}
+			return
+		}
+		go func() {
+			reply := handleCall(ctx, s.programs, s.allowedUIDs, msg)
+			if reply == nil {
+				return
+			}
+			writeMux.Lock()
+			defer writeMux.Unlock()
+			if err := writeRecord(conn, reply); err != nil {
+				log.Debugf("NFS: Replying to %v errored: %v", conn.RemoteAddr(), err)
+			}
+		}()
+	}
+}
+
+// ServeNFS starts an NFS server that serves the registry's entries and mounts
+// it at the mountpoint. Like fuse.ServeFuseFS, it returns three values:
+//
+//  1. A channel to initiate the shutdown (stopCh).
+//
+//  2. A read-only channel that signals whether the server was shutdown
+//
+//  3. An error object
+func ServeNFS(
+	registry *plugin.Registry,
+	mountpoint string,
+	analyticsClient analytics.Client,
+) (chan<- context.Context, <-chan struct{}, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, nil, err
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	log.Infof("NFS: Listening on %v", listener.Addr())
+
+	// NFS calls don't include the calling process's ID, so all of the
+	// server's activity is recorded in the same journal.
+	ctx := context.WithValue(context.Background(), activity.JournalKey, activity.NewJournal("nfs", "NFS server"))
+	ctx = context.WithValue(ctx, analytics.ClientKey, analyticsClient)
+
+	s := newServer(registry)
+	var connsMux sync.Mutex
+	conns := make(map[net.Conn]struct{})
+	serverExitedCh := make(chan struct{})
+	go func() {
+		defer close(serverExitedCh)
+		var wg sync.WaitGroup
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				break
+			}
+			if !netfs.FromPrivilegedPort(conn) {
+				log.Infof("NFS: Refused a connection from unprivileged port %v", conn.RemoteAddr())
+				conn.Close()
+				continue
+			}
+			connsMux.Lock()
+			conns[conn] = struct{}{}
+			connsMux.Unlock()
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				s.serveConn(ctx, conn)
+				connsMux.Lock()
+				delete(conns, conn)
+				connsMux.Unlock()
+			}()
+		}
+		wg.Wait()
+		log.Infof("NFS: Serve complete")
+	}()
+
+	log.Infof("NFS: Mounting at %v", mountpoint)
+	mountCmd, err := mountCommand(port, mountpoint)
+	if err != nil {
+		listener.Close()
+		<-serverExitedCh
+		return nil, nil, err
+	}
+	if out, err := mountCmd.CombinedOutput(); err != nil {
+		listener.Close()
+		<-serverExitedCh
+		return nil, nil, mountFailedErr(err, out)
+	}
+
+	// Clean-up
+	stopCh := make(chan context.Context)
+	stoppedCh := make(chan struct{})
+	go func() {
+		<-stopCh
+		log.Infof("NFS: Shutting down the server")
+
+		log.Infof("NFS: Unmounting %v", mountpoint)
+		err := unmount(mountpoint)
+		if err != nil {
+			log.Warnf("NFS: Shutdown failed: %v", err)
+			log.Warnf("NFS: Manual cleanup required: umount %v", mountpoint)
+
+			// Retry in a loop until no longer blocked by an open handle.
+			for ; err != nil && strings.Contains(strings.ToLower(err.Error()), "busy"); err = unmount(mountpoint) {
+				log.Debugf("NFS: Unmount failed: %v", err)
+				time.Sleep(3 * time.Second)
+			}
+			log.Debugf("NFS: Unmount: %v", err)
+		}
+		log.Infof("NFS: Unmount complete")
+
+		listener.Close()
+		connsMux.Lock()
+		for conn := range conns {
+			conn.Close()
+		}
+		connsMux.Unlock()
+		<-serverExitedCh
+		log.Infof("NFS: Server shutdown complete")
+		close(stoppedCh)
+	}()
+
+	return stopCh, stoppedCh, nil
+}
+
+func unmount(mountpoint string) error {
+	if out, err := exec.Command("umount", mountpoint).CombinedOutput(); err != nil {
+		return fmt.Errorf("%v: %v", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func mountFailedErr(err error, out []byte) error {
+	return fmt.Errorf("Received %v mounting the NFS server: %v", err, strings.TrimSpace(string(out)))
+}
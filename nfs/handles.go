@@ -0,0 +1,97 @@
+package nfs
+
+import (
+	"container/list"
+	"encoding/binary"
+	"sync"
+
+	"github.com/puppetlabs/wash/netfs"
+)
+
+// handle returns the node's file handle. It's the node's file ID so that the
+// handle stays the same across server restarts.
+func handle(n *netfs.Node) []byte {
+	fh := make([]byte, 8)
+	binary.BigEndian.PutUint64(fh, n.FileID())
+	return fh
+}
+
+// maxHandles bounds the number of nodes that the handle table remembers so
+// that a long-running server's memory doesn't grow with every entry that's
+// ever been looked up.
+const maxHandles = 1 << 16
+
+// handleTable maps file handles to the nodes that they were issued for. Once
+// it has maxHandles nodes, adding a node evicts the least recently used one,
+// and its handle becomes stale. Clients look the entry up again when that
+// happens. Pinned nodes, like the root, are never evicted.
+type handleTable struct {
+	mux    sync.Mutex
+	max    int
+	pinned map[uint64]*netfs.Node
+	nodes  map[uint64]*list.Element
+	lru    *list.List
+}
+
+func newHandleTable() *handleTable {
+	return &handleTable{
+		max:    maxHandles,
+		pinned: make(map[uint64]*netfs.Node),
+		nodes:  make(map[uint64]*list.Element),
+		lru:    list.New(),
+	}
+}
+
+// pin adds the node to the table so that it's never evicted, and returns its
+// handle.
+func (t *handleTable) pin(n *netfs.Node) []byte {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	t.pinned[n.FileID()] = n
+	return handle(n)
+}
+
+// add adds the node to the table and returns its handle. If the table already
+// has a node for the entry, the node's replaced so that its entry is fresh.
+func (t *handleTable) add(n *netfs.Node) []byte {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	id := n.FileID()
+	if _, ok := t.pinned[id]; ok {
+		t.pinned[id] = n
+		return handle(n)
+	}
+	if elem, ok := t.nodes[id]; ok {
+		elem.Value = n
+		t.lru.MoveToFront(elem)
+		return handle(n)
+	}
+	t.nodes[id] = t.lru.PushFront(n)
+	for t.lru.Len() > t.max {
+		oldest := t.lru.Back()
+		t.lru.Remove(oldest)
+		delete(t.nodes, oldest.Value.(*netfs.Node).FileID())
+	}
+	return handle(n)
+}
+
+// get returns the node for the handle. It returns false if the handle's
+// malformed or wasn't issued by the table, e.g. because the server restarted
+// since it was issued or because its node was evicted.
+func (t *handleTable) get(fh []byte) (*netfs.Node, bool) {
+	if len(fh) != 8 {
+		return nil, false
+	}
+	id := binary.BigEndian.Uint64(fh)
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	if n, ok := t.pinned[id]; ok {
+		return n, true
+	}
+	elem, ok := t.nodes[id]
+	if !ok {
+		return nil, false
+	}
+	t.lru.MoveToFront(elem)
+	return elem.Value.(*netfs.Node), true
+}
@@ -0,0 +1,77 @@
+package nfs
+
+import (
+	"context"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// MOUNT v3 (RFC 1813 appendix I) constants
+const (
+	mountProgram = 100005
+	mountVersion = 3
+
+	mountOK       = 0
+	mountErrNoEnt = 2
+
+	// exportPath is the only exported path. It's the root of Wash's
+	// filesystem.
+	exportPath = "/"
+)
+
+// mountProcedures returns the MOUNT v3 program's procedures. The client uses
+// the program to get the root's file handle.
+func (s *server) mountProcedures() map[uint32]procedure {
+	return map[uint32]procedure{
+		0: null,
+		1: s.mnt,
+		2: dump,
+		3: umnt,
+		4: null,
+		5: export,
+	}
+}
+
+func (s *server) mnt(ctx context.Context, args *xdrReader, reply *xdrWriter) error {
+	path := args.string()
+	if args.err != nil {
+		return args.err
+	}
+	if path != exportPath {
+		log.Infof("NFS: Refused to mount %v, only %v is exported", path, exportPath)
+		reply.uint32(mountErrNoEnt)
+		return nil
+	}
+	log.Infof("NFS: Client mounted %v", path)
+	reply.uint32(mountOK)
+	reply.opaque(s.handles.pin(s.root))
+	// The auth flavors
+	reply.uint32(1)
+	reply.uint32(authUnix)
+	return nil
+}
+
+// dump lists the mounts. Mounts aren't tracked, so it's always empty.
+func dump(ctx context.Context, args *xdrReader, reply *xdrWriter) error {
+	reply.bool(false)
+	return nil
+}
+
+func umnt(ctx context.Context, args *xdrReader, reply *xdrWriter) error {
+	path := args.string()
+	if args.err != nil {
+		return args.err
+	}
+	log.Infof("NFS: Client unmounted %v", path)
+	return nil
+}
+
+func export(ctx context.Context, args *xdrReader, reply *xdrWriter) error {
+	reply.bool(true)
+	reply.string(exportPath)
+	// Any host can mount the export, though the server only listens on the
+	// loopback interface.
+	reply.bool(false)
+	reply.bool(false)
+	return nil
+}
@@ -0,0 +1,13 @@
+package nfs
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// mountCommand returns the command that mounts the server. macOS lets a user
+// mount an NFS filesystem on a directory that they own.
+func mountCommand(port int, mountpoint string) (*exec.Cmd, error) {
+	opts := fmt.Sprintf("vers=3,tcp,port=%v,mountport=%v,nolocks,locallocks,resvport", port, port)
+	return exec.Command("/sbin/mount_nfs", "-o", opts, "127.0.0.1:"+exportPath, mountpoint), nil
+}
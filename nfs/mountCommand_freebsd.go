@@ -0,0 +1,12 @@
+package nfs
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// mountCommand returns the command that mounts the server.
+func mountCommand(port int, mountpoint string) (*exec.Cmd, error) {
+	opts := fmt.Sprintf("nfsv3,tcp,port=%v,mountport=%v,nolockd,resvport", port, port)
+	return exec.Command("mount_nfs", "-o", opts, "127.0.0.1:"+exportPath, mountpoint), nil
+}
@@ -0,0 +1,13 @@
+package nfs
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// mountCommand returns the command that mounts the server. Mounting an NFS
+// filesystem on Linux needs root, so wash's usually run with sudo.
+func mountCommand(port int, mountpoint string) (*exec.Cmd, error) {
+	opts := fmt.Sprintf("vers=3,proto=tcp,port=%v,mountproto=tcp,mountport=%v,nolock,resvport", port, port)
+	return exec.Command("mount", "-t", "nfs", "-o", opts, "127.0.0.1:"+exportPath, mountpoint), nil
+}
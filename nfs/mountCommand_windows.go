@@ -0,0 +1,12 @@
+package nfs
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// mountCommand returns an error because Windows' NFS client can only mount
+// servers that listen on port 2049 and are registered with a portmapper.
+func mountCommand(port int, mountpoint string) (*exec.Cmd, error) {
+	return nil, fmt.Errorf("the NFS mount isn't supported on Windows; use the default FUSE mount, which uses WinFsp")
+}
@@ -0,0 +1,520 @@
+package nfs
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/netfs"
+	"github.com/puppetlabs/wash/plugin"
+	log "github.com/sirupsen/logrus"
+)
+
+// NFS v3 (RFC 1813) constants
+const (
+	nfsProgram = 100003
+	nfsVersion = 3
+
+	nfsOK           = 0
+	nfsErrNoEnt     = 2
+	nfsErrIO        = 5
+	nfsErrAccess    = 13
+	nfsErrNotDir    = 20
+	nfsErrIsDir     = 21
+	nfsErrInval     = 22
+	nfsErrROFS      = 30
+	nfsErrStale     = 70
+	nfsErrBadHandle = 10001
+	nfsErrBadCookie = 10003
+
+	typeReg = 1
+	typeDir = 2
+	typeLnk = 5
+
+	accessRead    = 0x01
+	accessLookup  = 0x02
+	accessExecute = 0x20
+
+	// fsInfoProperties is FSF3_SYMLINK | FSF3_HOMOGENEOUS.
+	fsInfoProperties = 0x02 | 0x08
+
+	// maxTransferSize is the largest read or directory listing that the
+	// server returns.
+	maxTransferSize = 1 << 20
+	maxNameLength   = 255
+)
+
+// fsID identifies the filesystem in the file attributes.
+const fsID = 0x77617368
+
+// nfsProcedures returns the NFS v3 program's procedures. The filesystem's
+// read-only, so the procedures that change it fail with NFS3ERR_ROFS.
+func (s *server) nfsProcedures() map[uint32]procedure {
+	return map[uint32]procedure{
+		0:  null,
+		1:  s.getattr,
+		2:  readOnly(2),
+		3:  s.lookup,
+		4:  s.access,
+		5:  s.readlink,
+		6:  s.read,
+		7:  readOnly(2),
+		8:  readOnly(2),
+		9:  readOnly(2),
+		10: readOnly(2),
+		11: readOnly(2),
+		12: readOnly(2),
+		13: readOnly(2),
+		14: readOnly(4),
+		15: readOnly(3),
+		16: s.readdir,
+		17: s.readdirplus,
+		18: s.fsstat,
+		19: s.fsinfo,
+		20: s.pathconf,
+		21: readOnly(2),
+	}
+}
+
+func null(ctx context.Context, args *xdrReader, reply *xdrWriter) error {
+	return nil
+}
+
+// readOnly returns a procedure that fails with NFS3ERR_ROFS. The failure
+// results of the procedures that change the filesystem are the status followed
+// by wcc_data or post_op_attr, whose attributes are all omitted. attrs is the
+// number of omitted attributes, which is 2 for a wcc_data and 1 for a
+// post_op_attr.
+func readOnly(attrs int) procedure {
+	return func(ctx context.Context, args *xdrReader, reply *xdrWriter) error {
+		reply.uint32(nfsErrROFS)
+		for i := 0; i < attrs; i++ {
+			reply.bool(false)
+		}
+		return nil
+	}
+}
+
+// fattr is an NFS v3 fattr3.
+type fattr struct {
+	ftype  uint32
+	mode   uint32
+	uid    uint32
+	gid    uint32
+	size   uint64
+	fileID uint64
+	atime  time.Time
+	mtime  time.Time
+	ctime  time.Time
+}
+
+func (a fattr) encode(w *xdrWriter) {
+	w.uint32(a.ftype)
+	w.uint32(a.mode)
+	// nlink
+	w.uint32(1)
+	w.uint32(a.uid)
+	w.uint32(a.gid)
+	w.uint64(a.size)
+	// used
+	w.uint64(a.size)
+	// rdev
+	w.uint32(0)
+	w.uint32(0)
+	w.uint64(fsID)
+	w.uint64(a.fileID)
+	for _, t := range []time.Time{a.atime, a.mtime, a.ctime} {
+		w.uint32(uint32(t.Unix()))
+		w.uint32(uint32(t.Nanosecond()))
+	}
+}
+
+// fileTypes maps the nodes' file types to NFS' types.
+var fileTypes = map[netfs.FileType]uint32{
+	netfs.Regular:   typeReg,
+	netfs.Directory: typeDir,
+	netfs.Symlink:   typeLnk,
+}
+
+// attributes returns the node's fattr. It returns false if the attributes
+// would need the entry's content to be read and sized is false. See
+// netfs.Node#Attributes.
+func (s *server) attributes(ctx context.Context, n *netfs.Node, sized bool) (fattr, bool) {
+	a, ok := n.Attributes(ctx, sized)
+	if !ok {
+		return fattr{}, false
+	}
+	return fattr{
+		ftype:  fileTypes[a.Type],
+		mode:   a.Mode,
+		uid:    a.UID,
+		gid:    a.GID,
+		size:   a.Size,
+		fileID: n.FileID(),
+		atime:  a.Atime,
+		mtime:  a.Mtime,
+		ctime:  a.Ctime,
+	}, true
+}
+
+// postOpAttr writes the node's post_op_attr. The attributes are omitted if
+// they'd need the entry's content to be read.
+func (s *server) postOpAttr(ctx context.Context, reply *xdrWriter, n *netfs.Node) {
+	if n == nil {
+		reply.bool(false)
+		return
+	}
+	a, ok := s.attributes(ctx, n, false)
+	reply.bool(ok)
+	if ok {
+		a.encode(reply)
+	}
+}
+
+// resolve returns the node for the handle with a freshly discovered entry. If
+// it fails, it returns the NFS status that the call fails with.
+func (s *server) resolve(ctx context.Context, fh []byte) (*netfs.Node, uint32) {
+	n, ok := s.handles.get(fh)
+	if !ok {
+		if len(fh) != 8 {
+			return nil, nfsErrBadHandle
+		}
+		return nil, nfsErrStale
+	}
+	refound, err := n.Refind(ctx)
+	if err != nil {
+		log.Debugf("NFS: %v is stale: %v", n, err)
+		return nil, nfsErrStale
+	}
+	return refound, nfsOK
+}
+
+func (s *server) getattr(ctx context.Context, args *xdrReader, reply *xdrWriter) error {
+	fh := args.opaque()
+	if args.err != nil {
+		return args.err
+	}
+	n, status := s.resolve(ctx, fh)
+	if status != nfsOK {
+		reply.uint32(status)
+		return nil
+	}
+	a, _ := s.attributes(ctx, n, true)
+	reply.uint32(nfsOK)
+	a.encode(reply)
+	log.Debugf("NFS: Getattr %v: %+v", n, a)
+	return nil
+}
+
+func (s *server) lookup(ctx context.Context, args *xdrReader, reply *xdrWriter) error {
+	fh, name := args.opaque(), args.string()
+	if args.err != nil {
+		return args.err
+	}
+	dir, status := s.resolve(ctx, fh)
+	if status == nfsOK {
+		// Lookups happen a lot, so they're only logged at the debug level like
+		// the FUSE filesystem's.
+		log.Debugf("NFS: Lookup %v in %v", name, dir)
+	}
+	if status == nfsOK && !dir.IsDir() {
+		status = nfsErrNotDir
+	}
+	if status != nfsOK {
+		reply.uint32(status)
+		s.postOpAttr(ctx, reply, dir)
+		return nil
+	}
+
+	child, ok, err := dir.Child(ctx, name)
+	if err != nil {
+		activity.Warnf(ctx, "NFS: List %v errored: %v", dir, err)
+		reply.uint32(nfsErrIO)
+		s.postOpAttr(ctx, reply, dir)
+		return nil
+	}
+	if !ok {
+		reply.uint32(nfsErrNoEnt)
+		s.postOpAttr(ctx, reply, dir)
+		return nil
+	}
+
+	reply.uint32(nfsOK)
+	reply.opaque(s.handles.add(child))
+	a, _ := s.attributes(ctx, child, true)
+	reply.bool(true)
+	a.encode(reply)
+	s.postOpAttr(ctx, reply, dir)
+	return nil
+}
+
+func (s *server) access(ctx context.Context, args *xdrReader, reply *xdrWriter) error {
+	fh, requested := args.opaque(), args.uint32()
+	if args.err != nil {
+		return args.err
+	}
+	n, status := s.resolve(ctx, fh)
+	if status != nfsOK {
+		reply.uint32(status)
+		reply.bool(false)
+		return nil
+	}
+
+	// The filesystem's read-only, so modifying access is never granted.
+	var allowed uint32
+	if n.IsDir() {
+		allowed = accessRead | accessLookup | accessExecute
+	} else if plugin.ReadAction().IsSupportedOn(n.Entry) {
+		allowed = accessRead
+	}
+	reply.uint32(nfsOK)
+	s.postOpAttr(ctx, reply, n)
+	reply.uint32(requested & allowed)
+	return nil
+}
+
+func (s *server) readlink(ctx context.Context, args *xdrReader, reply *xdrWriter) error {
+	fh := args.opaque()
+	if args.err != nil {
+		return args.err
+	}
+	n, status := s.resolve(ctx, fh)
+	if status != nfsOK {
+		reply.uint32(status)
+		reply.bool(false)
+		return nil
+	}
+	link, ok := n.Entry.(*plugin.Symlink)
+	if !ok {
+		reply.uint32(nfsErrInval)
+		s.postOpAttr(ctx, reply, n)
+		return nil
+	}
+	reply.uint32(nfsOK)
+	s.postOpAttr(ctx, reply, n)
+	// Targets are relative to the symlink's parent, so the client resolves
+	// them within the mount.
+	reply.string(link.Target())
+	return nil
+}
+
+func (s *server) read(ctx context.Context, args *xdrReader, reply *xdrWriter) error {
+	fh, offset, count := args.opaque(), args.uint64(), args.uint32()
+	if args.err != nil {
+		return args.err
+	}
+	n, status := s.resolve(ctx, fh)
+	if status == nfsOK {
+		if n.IsDir() {
+			status = nfsErrIsDir
+		} else if !plugin.ReadAction().IsSupportedOn(n.Entry) {
+			status = nfsErrAccess
+		}
+	}
+	if status != nfsOK {
+		reply.uint32(status)
+		s.postOpAttr(ctx, reply, n)
+		return nil
+	}
+	if count > maxTransferSize {
+		count = maxTransferSize
+	}
+
+	data, err := plugin.ReadWithAnalytics(ctx, n.Entry, int64(count), int64(offset))
+	eof := err == io.EOF
+	if err != nil && !eof {
+		activity.Warnf(ctx, "NFS: Read %v errored: %v", n, err)
+		reply.uint32(nfsErrIO)
+		s.postOpAttr(ctx, reply, n)
+		return nil
+	}
+	if !eof {
+		// Read only returns io.EOF for entries with a size, and entries
+		// without one have their content cached, so Size is cheap.
+		size, err := plugin.Size(ctx, n.Entry)
+		eof = err == nil && offset+uint64(len(data)) >= size
+	}
+	activity.Record(ctx, "NFS: Read %v/%v bytes starting at %v from %v", len(data), count, offset, n)
+
+	reply.uint32(nfsOK)
+	s.postOpAttr(ctx, reply, n)
+	reply.uint32(uint32(len(data)))
+	reply.bool(eof)
+	reply.opaque(data)
+	return nil
+}
+
+// listing returns the directory's listing. Its cookies are the entries'
+// indexes plus one.
+func (s *server) listing(ctx context.Context, dir *netfs.Node) ([]netfs.DirEntry, uint32) {
+	if !dir.IsDir() {
+		return nil, nfsErrNotDir
+	}
+	listing, err := dir.Listing(ctx)
+	if err != nil {
+		activity.Warnf(ctx, "NFS: List %v errored: %v", dir, err)
+		return nil, nfsErrIO
+	}
+	activity.Record(ctx, "NFS: Listed %v entries in %v", len(listing)-2, dir)
+	return listing, nfsOK
+}
+
+// readdirEntries writes the listing's entries after the cookie until they'd
+// exceed maxBytes. Each entry's written by writeEntry. It returns false if the
+// cookie's invalid.
+func readdirEntries(reply *xdrWriter, listing []netfs.DirEntry, cookie uint64, maxBytes uint32, writeEntry func(*xdrWriter, netfs.DirEntry, uint64)) bool {
+	if cookie > uint64(len(listing)) {
+		return false
+	}
+	if maxBytes > maxTransferSize {
+		maxBytes = maxTransferSize
+	}
+	entries := &xdrWriter{}
+	eof := true
+	for i := cookie; i < uint64(len(listing)); i++ {
+		entry := &xdrWriter{}
+		entry.bool(true)
+		writeEntry(entry, listing[i], i+1)
+		// Leave room for the reply's header and trailer.
+		if uint32(entries.Len()+entry.Len()+128) > maxBytes && entries.Len() > 0 {
+			eof = false
+			break
+		}
+		entries.Write(entry.Bytes())
+	}
+	reply.Write(entries.Bytes())
+	reply.bool(false)
+	reply.bool(eof)
+	return true
+}
+
+// cookieVerifier is the readdir cookie verifier. Cookies are indexes into a
+// sorted listing, so they stay valid as long as the listing doesn't change.
+var cookieVerifier = make([]byte, 8)
+
+func (s *server) readdir(ctx context.Context, args *xdrReader, reply *xdrWriter) error {
+	fh, cookie := args.opaque(), args.uint64()
+	args.fixedOpaque(8)
+	count := args.uint32()
+	if args.err != nil {
+		return args.err
+	}
+	s.readdirReply(ctx, reply, fh, cookie, count, func(w *xdrWriter, e netfs.DirEntry, cookie uint64) {
+		w.uint64(e.Node.FileID())
+		w.string(e.Name)
+		w.uint64(cookie)
+	})
+	return nil
+}
+
+func (s *server) readdirplus(ctx context.Context, args *xdrReader, reply *xdrWriter) error {
+	fh, cookie := args.opaque(), args.uint64()
+	args.fixedOpaque(8)
+	// dircount
+	args.uint32()
+	maxcount := args.uint32()
+	if args.err != nil {
+		return args.err
+	}
+	s.readdirReply(ctx, reply, fh, cookie, maxcount, func(w *xdrWriter, e netfs.DirEntry, cookie uint64) {
+		w.uint64(e.Node.FileID())
+		w.string(e.Name)
+		w.uint64(cookie)
+		s.postOpAttr(ctx, w, e.Node)
+		w.bool(true)
+		w.opaque(s.handles.add(e.Node))
+	})
+	return nil
+}
+
+func (s *server) readdirReply(ctx context.Context, reply *xdrWriter, fh []byte, cookie uint64, maxBytes uint32, writeEntry func(*xdrWriter, netfs.DirEntry, uint64)) {
+	dir, status := s.resolve(ctx, fh)
+	var listing []netfs.DirEntry
+	if status == nfsOK {
+		listing, status = s.listing(ctx, dir)
+	}
+	if status != nfsOK {
+		reply.uint32(status)
+		s.postOpAttr(ctx, reply, dir)
+		return
+	}
+
+	result := &xdrWriter{}
+	if !readdirEntries(result, listing, cookie, maxBytes, writeEntry) {
+		reply.uint32(nfsErrBadCookie)
+		s.postOpAttr(ctx, reply, dir)
+		return
+	}
+	reply.uint32(nfsOK)
+	s.postOpAttr(ctx, reply, dir)
+	reply.fixedOpaque(cookieVerifier)
+	reply.Write(result.Bytes())
+}
+
+func (s *server) fsstat(ctx context.Context, args *xdrReader, reply *xdrWriter) error {
+	fh := args.opaque()
+	if args.err != nil {
+		return args.err
+	}
+	n, status := s.resolve(ctx, fh)
+	reply.uint32(status)
+	s.postOpAttr(ctx, reply, n)
+	if status != nfsOK {
+		return nil
+	}
+	// The filesystem has no capacity, so it reports that it's empty and full.
+	// tbytes, fbytes, abytes, tfiles, ffiles and afiles
+	for i := 0; i < 6; i++ {
+		reply.uint64(0)
+	}
+	// invarsec
+	reply.uint32(0)
+	return nil
+}
+
+func (s *server) fsinfo(ctx context.Context, args *xdrReader, reply *xdrWriter) error {
+	fh := args.opaque()
+	if args.err != nil {
+		return args.err
+	}
+	n, status := s.resolve(ctx, fh)
+	reply.uint32(status)
+	s.postOpAttr(ctx, reply, n)
+	if status != nfsOK {
+		return nil
+	}
+	// rtmax, rtpref, rtmult, wtmax, wtpref, wtmult and dtpref
+	for _, v := range []uint32{maxTransferSize, maxTransferSize, 4096, maxTransferSize, maxTransferSize, 4096, maxTransferSize} {
+		reply.uint32(v)
+	}
+	// maxfilesize
+	reply.uint64(1<<63 - 1)
+	// time_delta is a nanosecond.
+	reply.uint32(0)
+	reply.uint32(1)
+	reply.uint32(fsInfoProperties)
+	return nil
+}
+
+func (s *server) pathconf(ctx context.Context, args *xdrReader, reply *xdrWriter) error {
+	fh := args.opaque()
+	if args.err != nil {
+		return args.err
+	}
+	n, status := s.resolve(ctx, fh)
+	reply.uint32(status)
+	s.postOpAttr(ctx, reply, n)
+	if status != nfsOK {
+		return nil
+	}
+	// linkmax and name_max
+	reply.uint32(1)
+	reply.uint32(maxNameLength)
+	// no_trunc, chown_restricted, case_insensitive and case_preserving
+	reply.bool(true)
+	reply.bool(true)
+	reply.bool(false)
+	reply.bool(true)
+	return nil
+}
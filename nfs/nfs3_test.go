@@ -0,0 +1,371 @@
+package nfs
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/puppetlabs/wash/datastore"
+	"github.com/puppetlabs/wash/netfs"
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/stretchr/testify/suite"
+)
+
+type testRoot struct {
+	plugin.EntryBase
+}
+
+func (r *testRoot) Init(map[string]interface{}) error {
+	return nil
+}
+
+func (r *testRoot) Schema() *plugin.EntrySchema {
+	return nil
+}
+
+func (r *testRoot) ChildSchemas() []*plugin.EntrySchema {
+	return nil
+}
+
+func (r *testRoot) List(context.Context) ([]plugin.Entry, error) {
+	return []plugin.Entry{
+		&testFile{EntryBase: plugin.NewEntry("file"), content: "hello"},
+		plugin.NewSymlink("file", "link"),
+	}, nil
+}
+
+type testFile struct {
+	plugin.EntryBase
+	content string
+}
+
+func (f *testFile) Schema() *plugin.EntrySchema {
+	return nil
+}
+
+func (f *testFile) Read(context.Context) ([]byte, error) {
+	return []byte(f.content), nil
+}
+
+type nfsTestSuite struct {
+	suite.Suite
+	server *server
+	xid    uint32
+}
+
+func (suite *nfsTestSuite) SetupTest() {
+	plugin.SetTestCache(datastore.NewMemCache())
+	root := &testRoot{EntryBase: plugin.NewEntry("mine")}
+	root.SetTestID("/mine")
+	registry := plugin.NewRegistry()
+	suite.NoError(registry.RegisterPlugin(root, map[string]interface{}{}))
+	suite.server = newServer(registry)
+}
+
+func (suite *nfsTestSuite) TearDownTest() {
+	plugin.UnsetTestCache()
+}
+
+// unixCred returns an AUTH_UNIX credential's body for the UID.
+func unixCred(uid uint32) []byte {
+	cred := &xdrWriter{}
+	cred.uint32(0)
+	cred.string("localhost")
+	cred.uint32(uid)
+	cred.uint32(netfs.GID)
+	cred.uint32(1)
+	cred.uint32(netfs.GID)
+	return cred.Bytes()
+}
+
+// rawCall makes an RPC call with the credential and returns the reply after
+// its reply status.
+func (suite *nfsTestSuite) rawCall(prog, vers, proc, credFlavor uint32, credBody []byte, args func(*xdrWriter)) (uint32, *xdrReader) {
+	suite.xid++
+	msg := &xdrWriter{}
+	msg.uint32(suite.xid)
+	msg.uint32(msgCall)
+	msg.uint32(rpcVersion)
+	msg.uint32(prog)
+	msg.uint32(vers)
+	msg.uint32(proc)
+	msg.uint32(credFlavor)
+	msg.opaque(credBody)
+	msg.uint32(authNone)
+	msg.opaque(nil)
+	if args != nil {
+		args(msg)
+	}
+
+	reply := handleCall(context.Background(), suite.server.programs, suite.server.allowedUIDs, msg.Bytes())
+	suite.Require().NotNil(reply)
+	r := newXDRReader(reply)
+	suite.Equal(suite.xid, r.uint32())
+	suite.Equal(uint32(msgReply), r.uint32())
+	return r.uint32(), r
+}
+
+// call makes an RPC call as wash's user and returns the accept status and the
+// results.
+func (suite *nfsTestSuite) call(prog, vers, proc uint32, args func(*xdrWriter)) (uint32, *xdrReader) {
+	replyStatus, r := suite.rawCall(prog, vers, proc, authUnix, unixCred(netfs.UID), args)
+	suite.Require().Equal(uint32(replyAccepted), replyStatus)
+	// The verifier
+	r.uint32()
+	r.opaque()
+	return r.uint32(), r
+}
+
+// nfs calls an NFS procedure that succeeds at the RPC level.
+func (suite *nfsTestSuite) nfs(proc uint32, args func(*xdrWriter)) *xdrReader {
+	status, r := suite.call(nfsProgram, nfsVersion, proc, args)
+	suite.Require().Equal(uint32(acceptSuccess), status)
+	return r
+}
+
+func (suite *nfsTestSuite) mountRoot() []byte {
+	status, r := suite.call(mountProgram, mountVersion, 1, func(w *xdrWriter) {
+		w.string("/")
+	})
+	suite.Require().Equal(uint32(acceptSuccess), status)
+	suite.Equal(uint32(mountOK), r.uint32())
+	fh := r.opaque()
+	suite.Equal([]uint32{1, authUnix}, []uint32{r.uint32(), r.uint32()})
+	suite.NoError(r.err)
+	return fh
+}
+
+// lookup looks up the path's segments and returns the last one's handle.
+func (suite *nfsTestSuite) lookup(segments ...string) []byte {
+	fh := suite.mountRoot()
+	for _, segment := range segments {
+		r := suite.nfs(3, func(w *xdrWriter) {
+			w.opaque(fh)
+			w.string(segment)
+		})
+		suite.Require().Equal(uint32(nfsOK), r.uint32(), segment)
+		fh = r.opaque()
+	}
+	return fh
+}
+
+// fattrSize is the size of an encoded fattr3.
+const fattrSize = 84
+
+func (suite *nfsTestSuite) TestMount_OnlyExportsRoot() {
+	status, r := suite.call(mountProgram, mountVersion, 1, func(w *xdrWriter) {
+		w.string("/mine")
+	})
+	suite.Equal(uint32(acceptSuccess), status)
+	suite.Equal(uint32(mountErrNoEnt), r.uint32())
+}
+
+func (suite *nfsTestSuite) TestReaddir() {
+	fh := suite.lookup("mine")
+	r := suite.nfs(16, func(w *xdrWriter) {
+		w.opaque(fh)
+		w.uint64(0)
+		w.fixedOpaque(make([]byte, 8))
+		w.uint32(4096)
+	})
+	suite.Equal(uint32(nfsOK), r.uint32())
+	if r.bool() {
+		r.next(fattrSize)
+	}
+	r.fixedOpaque(8)
+
+	var names []string
+	var cookies []uint64
+	for r.bool() {
+		r.uint64()
+		names = append(names, r.string())
+		cookies = append(cookies, r.uint64())
+	}
+	suite.True(r.bool())
+	suite.NoError(r.err)
+	suite.Equal([]string{".", "..", "file", "link"}, names)
+	suite.Equal([]uint64{1, 2, 3, 4}, cookies)
+}
+
+func (suite *nfsTestSuite) TestLookup_NotFound() {
+	fh := suite.lookup("mine")
+	r := suite.nfs(3, func(w *xdrWriter) {
+		w.opaque(fh)
+		w.string("missing")
+	})
+	suite.Equal(uint32(nfsErrNoEnt), r.uint32())
+}
+
+func (suite *nfsTestSuite) TestGetattr() {
+	for _, c := range []struct {
+		segments []string
+		ftype    uint32
+		mode     uint32
+		size     uint64
+	}{
+		{nil, typeDir, 0550, 0},
+		{[]string{"mine"}, typeDir, 0550, 0},
+		// The file's size is computed from its content.
+		{[]string{"mine", "file"}, typeReg, 0440, 5},
+		{[]string{"mine", "link"}, typeLnk, 0777, 0},
+	} {
+		fh := suite.lookup(c.segments...)
+		r := suite.nfs(1, func(w *xdrWriter) {
+			w.opaque(fh)
+		})
+		suite.Equal(uint32(nfsOK), r.uint32())
+		suite.Equal(c.ftype, r.uint32(), c.segments)
+		suite.Equal(c.mode, r.uint32(), c.segments)
+		// nlink, uid and gid
+		r.next(12)
+		suite.Equal(c.size, r.uint64(), c.segments)
+		suite.NoError(r.err)
+	}
+}
+
+func (suite *nfsTestSuite) TestGetattr_StaleHandle() {
+	r := suite.nfs(1, func(w *xdrWriter) {
+		w.opaque(make([]byte, 8))
+	})
+	suite.Equal(uint32(nfsErrStale), r.uint32())
+
+	r = suite.nfs(1, func(w *xdrWriter) {
+		w.opaque([]byte{1})
+	})
+	suite.Equal(uint32(nfsErrBadHandle), r.uint32())
+}
+
+func (suite *nfsTestSuite) TestRead() {
+	fh := suite.lookup("mine", "file")
+	read := func(offset uint64, count uint32) (string, bool) {
+		r := suite.nfs(6, func(w *xdrWriter) {
+			w.opaque(fh)
+			w.uint64(offset)
+			w.uint32(count)
+		})
+		suite.Require().Equal(uint32(nfsOK), r.uint32())
+		if r.bool() {
+			r.next(fattrSize)
+		}
+		n := r.uint32()
+		eof := r.bool()
+		data := r.opaque()
+		suite.NoError(r.err)
+		suite.Equal(int(n), len(data))
+		return string(data), eof
+	}
+
+	data, eof := read(0, 2)
+	suite.Equal("he", data)
+	suite.False(eof)
+	data, eof = read(2, 10)
+	suite.Equal("llo", data)
+	suite.True(eof)
+}
+
+func (suite *nfsTestSuite) TestReadlink() {
+	fh := suite.lookup("mine", "link")
+	r := suite.nfs(5, func(w *xdrWriter) {
+		w.opaque(fh)
+	})
+	suite.Equal(uint32(nfsOK), r.uint32())
+	if r.bool() {
+		r.next(fattrSize)
+	}
+	suite.Equal("file", r.string())
+	suite.NoError(r.err)
+}
+
+func (suite *nfsTestSuite) TestWritesFailWithROFS() {
+	fh := suite.lookup("mine", "file")
+	r := suite.nfs(7, func(w *xdrWriter) {
+		w.opaque(fh)
+		w.uint64(0)
+		w.uint32(1)
+		// stable_how
+		w.uint32(0)
+		w.opaque([]byte{'x'})
+	})
+	suite.Equal(uint32(nfsErrROFS), r.uint32())
+	suite.False(r.bool())
+	suite.False(r.bool())
+	suite.NoError(r.err)
+	suite.Empty(r.buf)
+}
+
+func (suite *nfsTestSuite) TestCallErrors() {
+	status, _ := suite.call(1234, 1, 0, nil)
+	suite.Equal(uint32(acceptProgUnavail), status)
+
+	status, r := suite.call(nfsProgram, 4, 0, nil)
+	suite.Equal(uint32(acceptProgMismatch), status)
+	suite.Equal([]uint32{nfsVersion, nfsVersion}, []uint32{r.uint32(), r.uint32()})
+
+	status, _ = suite.call(nfsProgram, nfsVersion, 22, nil)
+	suite.Equal(uint32(acceptProcUnavail), status)
+
+	status, _ = suite.call(nfsProgram, nfsVersion, 1, nil)
+	suite.Equal(uint32(acceptGarbageArgs), status)
+}
+
+func (suite *nfsTestSuite) TestCallAuth() {
+	// Calls without AUTH_UNIX credentials are refused
+	replyStatus, r := suite.rawCall(mountProgram, mountVersion, 0, authNone, nil, nil)
+	suite.Equal(uint32(replyDenied), replyStatus)
+	suite.Equal([]uint32{rejectAuthError, authErrTooWeak}, []uint32{r.uint32(), r.uint32()})
+
+	// So are calls from other users
+	replyStatus, r = suite.rawCall(mountProgram, mountVersion, 0, authUnix, unixCred(netfs.UID+1), nil)
+	suite.Equal(uint32(replyDenied), replyStatus)
+	suite.Equal([]uint32{rejectAuthError, authErrBadCred}, []uint32{r.uint32(), r.uint32()})
+
+	// And calls with malformed credentials
+	replyStatus, r = suite.rawCall(mountProgram, mountVersion, 0, authUnix, make([]byte, 8), nil)
+	suite.Equal(uint32(replyDenied), replyStatus)
+	suite.Equal([]uint32{rejectAuthError, authErrBadCred}, []uint32{r.uint32(), r.uint32()})
+}
+
+func (suite *nfsTestSuite) TestHandles_EvictsLeastRecentlyUsed() {
+	suite.server.handles.max = 2
+	fh := suite.lookup("mine", "file")
+	suite.lookup("mine", "link")
+	suite.lookup("mine")
+	// The file's handle was evicted, so it's stale
+	r := suite.nfs(1, func(w *xdrWriter) {
+		w.opaque(fh)
+	})
+	suite.Equal(uint32(nfsErrStale), r.uint32())
+
+	// The root's pinned, so it's never evicted
+	root := suite.mountRoot()
+	r = suite.nfs(1, func(w *xdrWriter) {
+		w.opaque(root)
+	})
+	suite.Equal(uint32(nfsOK), r.uint32())
+}
+
+func TestNFS(t *testing.T) {
+	suite.Run(t, new(nfsTestSuite))
+}
+
+func TestRecordMarking(t *testing.T) {
+	var buf bytes.Buffer
+	// A record that's split into two fragments
+	buf.Write([]byte{0, 0, 0, 2, 'a', 'b'})
+	buf.Write([]byte{0x80, 0, 0, 1, 'c'})
+	if err := writeRecord(&buf, []byte("de")); err != nil {
+		t.Fatal(err)
+	}
+
+	r := bufio.NewReader(&buf)
+	for _, expected := range []string{"abc", "de"} {
+		record, err := readRecord(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(record) != expected {
+			t.Errorf("expected %q, got %q", expected, record)
+		}
+	}
+}
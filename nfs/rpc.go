@@ -0,0 +1,210 @@
+package nfs
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ONC RPC (RFC 5531) constants
+const (
+	rpcVersion = 2
+
+	msgCall  = 0
+	msgReply = 1
+
+	replyAccepted = 0
+	replyDenied   = 1
+
+	acceptSuccess      = 0
+	acceptProgUnavail  = 1
+	acceptProgMismatch = 2
+	acceptProcUnavail  = 3
+	acceptGarbageArgs  = 4
+	acceptSystemErr    = 5
+
+	rejectRPCMismatch = 0
+	rejectAuthError   = 1
+
+	authNone = 0
+	authUnix = 1
+
+	authErrBadCred = 1
+	authErrTooWeak = 5
+
+	// maxUnixGIDs is the maximum number of supplementary groups in an
+	// AUTH_UNIX credential.
+	maxUnixGIDs = 16
+
+	// lastFragment is set in a record marker's header when the fragment is the
+	// record's last.
+	lastFragment = 1 << 31
+	// maxRecordSize bounds the size of a call so that a bad client can't make
+	// the server allocate an arbitrary amount of memory. Calls are small since
+	// the server doesn't support writes.
+	maxRecordSize = 1 << 20
+)
+
+// procedure handles a call. It decodes its arguments from args and encodes its
+// results to reply. It returns errGarbageArgs if the arguments can't be decoded.
+type procedure func(ctx context.Context, args *xdrReader, reply *xdrWriter) error
+
+// program is an RPC program's version, like NFS v3.
+type program struct {
+	name       string
+	version    uint32
+	procedures map[uint32]procedure
+}
+
+// readRecord reads a record-marked RPC message (RFC 5531 section 11).
+func readRecord(r *bufio.Reader) ([]byte, error) {
+	var record []byte
+	for {
+		var header [4]byte
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			return nil, err
+		}
+		marker := binary.BigEndian.Uint32(header[:])
+		size := int(marker &^ lastFragment)
+		if len(record)+size > maxRecordSize {
+			return nil, fmt.Errorf("the record is larger than %v bytes", maxRecordSize)
+		}
+		fragment := make([]byte, size)
+		if _, err := io.ReadFull(r, fragment); err != nil {
+			return nil, err
+		}
+		record = append(record, fragment...)
+		if marker&lastFragment != 0 {
+			return record, nil
+		}
+	}
+}
+
+// writeRecord writes msg as a single-fragment record.
+func writeRecord(w io.Writer, msg []byte) error {
+	var header [4]byte
+	binary.BigEndian.PutUint32(header[:], uint32(len(msg))|lastFragment)
+	if _, err := w.Write(append(header[:], msg...)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// unixUID returns the UID in an AUTH_UNIX credential's body (RFC 5531
+// appendix A). It returns false if the body's malformed.
+func unixUID(body []byte) (uint32, bool) {
+	cred := newXDRReader(body)
+	// The stamp and the machine name
+	cred.uint32()
+	cred.string()
+	uid := cred.uint32()
+	// The GID and the supplementary GIDs
+	cred.uint32()
+	n := cred.uint32()
+	if n > maxUnixGIDs {
+		return 0, false
+	}
+	for i := uint32(0); i < n; i++ {
+		cred.uint32()
+	}
+	return uid, cred.err == nil
+}
+
+// handleCall decodes an RPC call and dispatches it to its procedure. It returns
+// the encoded reply, or nil if the message isn't a call that can be answered.
+//
+// The server's reachable by every local user, so calls are only answered if
+// they have AUTH_UNIX credentials for one of the allowed UIDs. Like a FUSE
+// mount, that keeps the mount private to the user that started wash.
+func handleCall(ctx context.Context, programs map[uint32]*program, allowedUIDs map[uint32]bool, msg []byte) []byte {
+	args := newXDRReader(msg)
+	xid := args.uint32()
+	msgType := args.uint32()
+	if args.err != nil || msgType != msgCall {
+		return nil
+	}
+	rpcvers := args.uint32()
+	prog, vers, proc := args.uint32(), args.uint32(), args.uint32()
+	credFlavor, credBody := args.uint32(), args.opaque()
+	// The verifier
+	args.uint32()
+	args.opaque()
+	if args.err != nil {
+		return nil
+	}
+
+	reply := &xdrWriter{}
+	reply.uint32(xid)
+	reply.uint32(msgReply)
+	if rpcvers != rpcVersion {
+		reply.uint32(replyDenied)
+		reply.uint32(rejectRPCMismatch)
+		reply.uint32(rpcVersion)
+		reply.uint32(rpcVersion)
+		return reply.Bytes()
+	}
+	if credFlavor != authUnix {
+		reply.uint32(replyDenied)
+		reply.uint32(rejectAuthError)
+		reply.uint32(authErrTooWeak)
+		return reply.Bytes()
+	}
+	if uid, ok := unixUID(credBody); !ok || !allowedUIDs[uid] {
+		if ok {
+			log.Debugf("NFS: Refused a call from UID %v", uid)
+		}
+		reply.uint32(replyDenied)
+		reply.uint32(rejectAuthError)
+		reply.uint32(authErrBadCred)
+		return reply.Bytes()
+	}
+	reply.uint32(replyAccepted)
+	reply.uint32(authNone)
+	reply.opaque(nil)
+
+	p, ok := programs[prog]
+	if !ok {
+		reply.uint32(acceptProgUnavail)
+		return reply.Bytes()
+	}
+	if vers != p.version {
+		reply.uint32(acceptProgMismatch)
+		reply.uint32(p.version)
+		reply.uint32(p.version)
+		return reply.Bytes()
+	}
+	handler, ok := p.procedures[proc]
+	if !ok {
+		reply.uint32(acceptProcUnavail)
+		return reply.Bytes()
+	}
+
+	results := &xdrWriter{}
+	if err := callProcedure(ctx, p, proc, handler, args, results); err != nil {
+		if err == errGarbageArgs {
+			reply.uint32(acceptGarbageArgs)
+		} else {
+			reply.uint32(acceptSystemErr)
+		}
+		return reply.Bytes()
+	}
+	reply.uint32(acceptSuccess)
+	reply.Write(results.Bytes())
+	return reply.Bytes()
+}
+
+// callProcedure calls the handler, turning a panic into an error so that it
+// only fails the call instead of crashing the daemon.
+func callProcedure(ctx context.Context, p *program, proc uint32, handler procedure, args *xdrReader, results *xdrWriter) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Warnf("NFS: %v procedure %v panicked: %v", p.name, proc, r)
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	return handler(ctx, args, results)
+}
@@ -0,0 +1,118 @@
+package nfs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+)
+
+// errGarbageArgs is returned when a call's arguments can't be decoded. The
+// call's answered with GARBAGE_ARGS.
+var errGarbageArgs = errors.New("could not decode the call's arguments")
+
+// xdrReader decodes XDR (RFC 4506) values. The first decoding error is kept in
+// err, and later reads return zero values, so callers only check err once
+// they've read all of their arguments.
+type xdrReader struct {
+	buf []byte
+	err error
+}
+
+func newXDRReader(buf []byte) *xdrReader {
+	return &xdrReader{buf: buf}
+}
+
+func (r *xdrReader) next(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || n > len(r.buf) {
+		r.err = errGarbageArgs
+		return nil
+	}
+	b := r.buf[:n]
+	r.buf = r.buf[n:]
+	return b
+}
+
+func (r *xdrReader) uint32() uint32 {
+	b := r.next(4)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint32(b)
+}
+
+func (r *xdrReader) uint64() uint64 {
+	b := r.next(8)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint64(b)
+}
+
+func (r *xdrReader) bool() bool {
+	return r.uint32() != 0
+}
+
+// fixedOpaque reads n bytes and their padding.
+func (r *xdrReader) fixedOpaque(n int) []byte {
+	b := r.next(n)
+	r.next(padding(n))
+	return b
+}
+
+// opaque reads variable-length opaque data.
+func (r *xdrReader) opaque() []byte {
+	return r.fixedOpaque(int(r.uint32()))
+}
+
+func (r *xdrReader) string() string {
+	return string(r.opaque())
+}
+
+// xdrWriter encodes XDR values.
+type xdrWriter struct {
+	bytes.Buffer
+}
+
+func (w *xdrWriter) uint32(v uint32) {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], v)
+	w.Write(b[:])
+}
+
+func (w *xdrWriter) uint64(v uint64) {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], v)
+	w.Write(b[:])
+}
+
+func (w *xdrWriter) bool(v bool) {
+	if v {
+		w.uint32(1)
+	} else {
+		w.uint32(0)
+	}
+}
+
+// fixedOpaque writes b and its padding.
+func (w *xdrWriter) fixedOpaque(b []byte) {
+	w.Write(b)
+	w.Write(make([]byte, padding(len(b))))
+}
+
+// opaque writes variable-length opaque data.
+func (w *xdrWriter) opaque(b []byte) {
+	w.uint32(uint32(len(b)))
+	w.fixedOpaque(b)
+}
+
+func (w *xdrWriter) string(s string) {
+	w.opaque([]byte(s))
+}
+
+// padding returns the number of bytes that pad n bytes to a multiple of four.
+func padding(n int) int {
+	return (4 - n%4) % 4
+}
@@ -0,0 +1,74 @@
+package ninep
+
+import (
+	"net"
+	"strings"
+
+	"github.com/puppetlabs/wash/api/auth"
+	"github.com/puppetlabs/wash/netfs"
+	"github.com/puppetlabs/wash/plugin"
+	log "github.com/sirupsen/logrus"
+)
+
+// noUID is the n_uname of attaches that aren't made on behalf of a user, like
+// the one that v9fs makes when it mounts the export.
+const noUID = ^uint32(0)
+
+// isLocal returns true if the connection was made by the kernel's client on
+// wash's host. Only wash's user can connect to its Unix socket, and only root
+// can connect from a privileged port.
+func isLocal(rw net.Conn) bool {
+	switch addr := rw.RemoteAddr().(type) {
+	case *net.UnixAddr:
+		return true
+	case *net.TCPAddr:
+		return addr.IP.IsLoopback() && netfs.FromPrivilegedPort(rw)
+	default:
+		return false
+	}
+}
+
+// allowsUID returns true if attaches from the local kernel client are answered
+// for the user. Like the NFS server's, they're answered for wash's user and,
+// if wash was run with sudo, the user that ran it. Root's and the mount's own
+// attaches are also answered because root makes the mount.
+func allowsUID(uid uint32) bool {
+	return uid == 0 || uid == noUID || netfs.AllowedUIDs()[uid]
+}
+
+// authenticate returns the token whose secret is the attach's aname.
+func (c *conn) authenticate(secret string) (*auth.Token, error) {
+	if c.tokens == nil {
+		return nil, errno(errAccess)
+	}
+	token, ok, err := c.tokens.Authenticate(secret)
+	if err != nil {
+		log.Warnf("9P: Could not read the tokens: %v", err)
+		return nil, errno(errIO)
+	}
+	if !ok {
+		return nil, errno(errAccess)
+	}
+	return &token, nil
+}
+
+// authorize checks that the fid's token hasn't been revoked since its attach.
+// Fids that weren't attached with a token are always authorized.
+func (c *conn) authorize(f *fid) error {
+	if f.token == nil {
+		return nil
+	}
+	_, err := c.authenticate(f.secret)
+	return err
+}
+
+// visible returns true if the fid can see the node. Fids that were attached
+// with a token can only see the plugins that the token can read.
+func (f *fid) visible(n *netfs.Node) bool {
+	if f.token == nil {
+		return true
+	}
+	segments := strings.SplitN(strings.TrimPrefix(plugin.ID(n.Entry), "/"), "/", 2)
+	// The registry's visible so that the token's plugins can be listed.
+	return segments[0] == "" || f.token.Allows(segments[0], false)
+}
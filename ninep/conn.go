@@ -0,0 +1,295 @@
+package ninep
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/api/auth"
+	"github.com/puppetlabs/wash/netfs"
+	log "github.com/sirupsen/logrus"
+)
+
+// errno is an error that's returned to the client in Rlerror.
+type errno uint32
+
+func (e errno) Error() string {
+	return fmt.Sprintf("errno %d", uint32(e))
+}
+
+// fid is a client's reference to a node.
+type fid struct {
+	node   *netfs.Node
+	opened bool
+	// listing is the directory's listing, which is kept from the Treaddir
+	// with offset 0 so that the offsets of the following Treaddirs refer to
+	// the same listing.
+	listing []netfs.DirEntry
+	// secret and token are the token that the fid was attached with. They're
+	// empty if the attach didn't present one.
+	secret string
+	token  *auth.Token
+}
+
+// to returns a new fid that refers to the node with the fid's token.
+func (f *fid) to(n *netfs.Node) *fid {
+	return &fid{node: n, secret: f.secret, token: f.token}
+}
+
+// request is an in-flight request. It's cancelled by Tflush.
+type request struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// conn is a client's connection.
+type conn struct {
+	root   *netfs.Node
+	rw     net.Conn
+	tokens *auth.Store
+	// local is true if the connection was made by the kernel's client on
+	// wash's host. Other connections must attach with a token.
+	local bool
+
+	mux     sync.Mutex
+	msize   uint32
+	fids    map[uint32]*fid
+	pending map[uint16]*request
+
+	writeMux sync.Mutex
+}
+
+func newConn(root *netfs.Node, rw net.Conn, tokens *auth.Store) *conn {
+	return &conn{
+		root:    root,
+		rw:      rw,
+		tokens:  tokens,
+		local:   isLocal(rw),
+		msize:   maxMessageSize,
+		fids:    make(map[uint32]*fid),
+		pending: make(map[uint16]*request),
+	}
+}
+
+// serve answers the connection's requests until it's closed. Clients send
+// several requests at once, so each request's answered in its own goroutine.
+// Tversion's answered before the next request's read since it resets the
+// connection.
+func (c *conn) serve(ctx context.Context) {
+	defer c.rw.Close()
+	r := bufio.NewReader(c.rw)
+	for {
+		msg, err := c.readMessage(r)
+		if err != nil {
+			if err != io.EOF {
+				log.Debugf("9P: Closing the connection from %v: %v", c.rw.RemoteAddr(), err)
+			}
+			return
+		}
+		typ, tag := msg[4], binary.LittleEndian.Uint16(msg[5:7])
+		body := &decoder{buf: msg[headerSize:]}
+		if typ == tversion {
+			c.reply(typ, tag, c.version(body))
+			continue
+		}
+
+		reqCtx, cancel := context.WithCancel(ctx)
+		req := &request{cancel: cancel, done: make(chan struct{})}
+		c.mux.Lock()
+		c.pending[tag] = req
+		c.mux.Unlock()
+		go func() {
+			defer close(req.done)
+			defer cancel()
+			reply := c.handle(reqCtx, typ, body)
+			c.mux.Lock()
+			delete(c.pending, tag)
+			c.mux.Unlock()
+			c.reply(typ, tag, reply)
+		}()
+	}
+}
+
+func (c *conn) readMessage(r *bufio.Reader) ([]byte, error) {
+	var size [4]byte
+	if _, err := io.ReadFull(r, size[:]); err != nil {
+		return nil, err
+	}
+	n := binary.LittleEndian.Uint32(size[:])
+	c.mux.Lock()
+	msize := c.msize
+	c.mux.Unlock()
+	if n < headerSize || n > msize {
+		return nil, fmt.Errorf("the message's size %v isn't between %v and %v", n, headerSize, msize)
+	}
+	msg := make([]byte, n)
+	copy(msg, size[:])
+	if _, err := io.ReadFull(r, msg[4:]); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// reply sends the reply to a request. If the request failed, reply is the
+// error and Rlerror is sent.
+func (c *conn) reply(typ uint8, tag uint16, reply interface{}) {
+	e := &encoder{buf: make([]byte, headerSize)}
+	switch r := reply.(type) {
+	case *encoder:
+		e.buf = append(e.buf, r.buf...)
+		typ++
+	case errno:
+		e.uint32(uint32(r))
+		typ = rlerror
+	default:
+		log.Debugf("9P: Request %v errored: %v", typ, r)
+		e.uint32(errIO)
+		typ = rlerror
+	}
+	binary.LittleEndian.PutUint32(e.buf, uint32(len(e.buf)))
+	e.buf[4] = typ
+	binary.LittleEndian.PutUint16(e.buf[5:], tag)
+
+	c.writeMux.Lock()
+	defer c.writeMux.Unlock()
+	if _, err := c.rw.Write(e.buf); err != nil {
+		log.Debugf("9P: Replying to %v errored: %v", c.rw.RemoteAddr(), err)
+	}
+}
+
+// handle answers a request. It returns the reply's body, or the error that's
+// sent in Rlerror.
+func (c *conn) handle(ctx context.Context, typ uint8, d *decoder) (reply interface{}) {
+	defer func() {
+		if r := recover(); r != nil {
+			_ = activity.RecordPanic(ctx, fmt.Sprintf("9P: request %v", typ), r)
+			reply = errno(errIO)
+		}
+	}()
+
+	e := &encoder{}
+	var err error
+	switch typ {
+	case tattach:
+		err = c.attach(ctx, d, e)
+	case twalk:
+		err = c.walk(ctx, d, e)
+	case tlopen:
+		err = c.lopen(ctx, d, e)
+	case tread:
+		err = c.read(ctx, d, e)
+	case treaddir:
+		err = c.readdir(ctx, d, e)
+	case tgetattr:
+		err = c.getattr(ctx, d, e)
+	case treadlink:
+		err = c.readlink(ctx, d, e)
+	case tstatfs:
+		err = c.statfs(ctx, d, e)
+	case tclunk:
+		err = c.clunk(d)
+	case tremove:
+		// The fid's clunked even though the remove fails.
+		err = c.clunk(d)
+		if err == nil {
+			err = errno(errROFS)
+		}
+	case tflush:
+		err = c.flush(d)
+	case tfsync:
+		// There's nothing to sync.
+	case tlock:
+		err = lock(d, e)
+	case tgetlock:
+		err = getlock(d, e)
+	case tlcreate, tsymlink, tmknod, trename, tsetattr, txattrcreate, tlink, tmkdir, trenameat, tunlinkat, twrite:
+		err = errno(errROFS)
+	case tauth, txattrwalk:
+		err = errno(errOpNotSupp)
+	default:
+		err = errno(errNoSys)
+	}
+	if d.err != nil {
+		return errno(errInval)
+	}
+	if err != nil {
+		return err
+	}
+	return e
+}
+
+// version negotiates the protocol version and message size, and resets the
+// connection's fids.
+func (c *conn) version(d *decoder) interface{} {
+	msize, version := d.uint32(), d.string()
+	if d.err != nil {
+		return errno(errInval)
+	}
+	if msize > maxMessageSize {
+		msize = maxMessageSize
+	}
+	if version != protocolVersion {
+		version = "unknown"
+	}
+
+	c.mux.Lock()
+	c.msize = msize
+	c.fids = make(map[uint32]*fid)
+	c.mux.Unlock()
+
+	e := &encoder{}
+	e.uint32(msize)
+	e.string(version)
+	return e
+}
+
+// flush waits for the request to finish. Requests can't be interrupted, so
+// they're only cancelled.
+func (c *conn) flush(d *decoder) error {
+	oldtag := d.uint16()
+	c.mux.Lock()
+	req, ok := c.pending[oldtag]
+	c.mux.Unlock()
+	if ok {
+		req.cancel()
+		<-req.done
+	}
+	return nil
+}
+
+// getFID returns the fid. It returns errAccess if the fid's token was
+// revoked.
+func (c *conn) getFID(id uint32) (*fid, error) {
+	c.mux.Lock()
+	f, ok := c.fids[id]
+	c.mux.Unlock()
+	if !ok {
+		return nil, errno(errBadF)
+	}
+	if err := c.authorize(f); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (c *conn) setFID(id uint32, f *fid) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.fids[id] = f
+}
+
+func (c *conn) clunk(d *decoder) error {
+	id := d.uint32()
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if _, ok := c.fids[id]; !ok {
+		return errno(errBadF)
+	}
+	delete(c.fids, id)
+	return nil
+}
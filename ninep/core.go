@@ -0,0 +1,161 @@
+// Package ninep exports wash's filesystem over 9P2000.L, the protocol that's
+// used by Linux's v9fs client. It lets the filesystem be mounted in VMs and
+// containers that can't run FUSE, e.g. with
+//
+//	mount -t 9p -o trans=tcp,port=5640,version=9p2000.L,aname=<token> <host> /mnt/wash
+//
+// The export's read-only. Clients attach with a token's secret as the aname,
+// and only see the plugins that the token can read. Attaches without a token
+// are only answered for wash's user when they're made by the kernel's client
+// on wash's host, like the NFS server's calls.
+package ninep
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/analytics"
+	"github.com/puppetlabs/wash/api/auth"
+	"github.com/puppetlabs/wash/netfs"
+	"github.com/puppetlabs/wash/plugin"
+	log "github.com/sirupsen/logrus"
+)
+
+// listen listens on the address. Addresses that start with unix: are Unix
+// socket paths, and the others are TCP addresses. TCP addresses without a host
+// listen on the loopback interface.
+func listen(address string) (net.Listener, error) {
+	if strings.HasPrefix(address, "unix:") {
+		return listenUnix(strings.TrimPrefix(address, "unix:"))
+	}
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	return net.Listen("tcp", net.JoinHostPort(host, port))
+}
+
+// listenUnix listens on a Unix socket that only wash's user can connect to.
+// The socket's created in a private directory and moved to the path once its
+// permissions are set, so that no one else can connect to it in between.
+func listenUnix(path string) (net.Listener, error) {
+	dir, err := ioutil.TempDir(filepath.Dir(path), ".wash-9p")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	tmp := filepath.Join(dir, "socket")
+	listener, err := net.Listen("unix", tmp)
+	if err != nil {
+		return nil, err
+	}
+	listener.(*net.UnixListener).SetUnlinkOnClose(false)
+	if err := os.Chmod(tmp, 0600); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return unixListener{Listener: listener, path: path}, nil
+}
+
+// unixListener removes its socket when it's closed. The net package can't
+// remove it because it was moved.
+type unixListener struct {
+	net.Listener
+	path string
+}
+
+func (l unixListener) Close() error {
+	err := l.Listener.Close()
+	if rmErr := os.Remove(l.path); err == nil && !os.IsNotExist(rmErr) {
+		err = rmErr
+	}
+	return err
+}
+
+// Serve exports the registry's entries over 9P2000.L at the address. Clients
+// attach with the tokens' secrets; tokens can be nil if they can't. Like
+// fuse.ServeFuseFS, it returns three values:
+//
+//  1. A channel to initiate the shutdown (stopCh).
+//
+//  2. A read-only channel that signals whether the server was shutdown
+//
+//  3. An error object
+func Serve(
+	registry *plugin.Registry,
+	address string,
+	tokens *auth.Store,
+	analyticsClient analytics.Client,
+) (chan<- context.Context, <-chan struct{}, error) {
+	listener, err := listen(address)
+	if err != nil {
+		return nil, nil, err
+	}
+	log.Infof("9P: Listening on %v", listener.Addr())
+
+	// 9P requests don't include the calling process's ID, so all of the
+	// server's activity is recorded in the same journal.
+	ctx := context.WithValue(context.Background(), activity.JournalKey, activity.NewJournal("9p", "9P server"))
+	ctx = context.WithValue(ctx, analytics.ClientKey, analyticsClient)
+
+	root := &netfs.Node{Entry: registry}
+	var connsMux sync.Mutex
+	conns := make(map[net.Conn]struct{})
+	serverExitedCh := make(chan struct{})
+	go func() {
+		defer close(serverExitedCh)
+		var wg sync.WaitGroup
+		for {
+			rw, err := listener.Accept()
+			if err != nil {
+				break
+			}
+			connsMux.Lock()
+			conns[rw] = struct{}{}
+			connsMux.Unlock()
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				newConn(root, rw, tokens).serve(ctx)
+				connsMux.Lock()
+				delete(conns, rw)
+				connsMux.Unlock()
+			}()
+		}
+		wg.Wait()
+		log.Infof("9P: Serve complete")
+	}()
+
+	// Clean-up
+	stopCh := make(chan context.Context)
+	stoppedCh := make(chan struct{})
+	go func() {
+		<-stopCh
+		log.Infof("9P: Shutting down the server")
+		listener.Close()
+		connsMux.Lock()
+		for rw := range conns {
+			rw.Close()
+		}
+		connsMux.Unlock()
+		<-serverExitedCh
+		log.Infof("9P: Server shutdown complete")
+		close(stoppedCh)
+	}()
+
+	return stopCh, stoppedCh, nil
+}
@@ -0,0 +1,47 @@
+package ninep
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListen_DefaultsToLoopback(t *testing.T) {
+	l, err := listen(":0")
+	require.NoError(t, err)
+	defer l.Close()
+	assert.True(t, l.Addr().(*net.TCPAddr).IP.IsLoopback())
+}
+
+func TestListen_UnixSocketIsPrivate(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Unix sockets don't have permissions on Windows")
+	}
+	dir, err := ioutil.TempDir("", "ninep")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "wash.sock")
+
+	l, err := listen("unix:" + path)
+	require.NoError(t, err)
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	conn, err := net.Dial("unix", path)
+	require.NoError(t, err)
+	conn.Close()
+
+	// Only the socket's left in the directory, and it's removed on close.
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+	require.NoError(t, l.Close())
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,34 @@
+package ninep
+
+import (
+	"github.com/puppetlabs/wash/netfs"
+)
+
+// nodeQID returns the node's qid. Its path is the node's file ID so that it
+// stays the same across server restarts.
+func nodeQID(n *netfs.Node) qid {
+	q := qid{typ: qidFile, path: n.FileID()}
+	if n.IsSymlink() {
+		q.typ = qidSymlink
+	} else if n.IsDir() {
+		q.typ = qidDir
+	}
+	return q
+}
+
+// dirType returns the node's type in a directory listing.
+func dirType(n *netfs.Node) uint8 {
+	if n.IsSymlink() {
+		return dtLnk
+	} else if n.IsDir() {
+		return dtDir
+	}
+	return dtReg
+}
+
+// fileModes maps the nodes' file types to the mode's file type bits.
+var fileModes = map[netfs.FileType]uint32{
+	netfs.Regular:   modeReg,
+	netfs.Directory: modeDir,
+	netfs.Symlink:   modeSymlink,
+}
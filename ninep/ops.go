@@ -0,0 +1,330 @@
+package ninep
+
+import (
+	"context"
+	"io"
+
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/netfs"
+	"github.com/puppetlabs/wash/plugin"
+	log "github.com/sirupsen/logrus"
+)
+
+// refind re-discovers the fid's node so that its attributes are fresh.
+func refind(ctx context.Context, f *fid) (*netfs.Node, error) {
+	n, err := f.node.Refind(ctx)
+	if err != nil {
+		log.Debugf("9P: %v no longer exists: %v", f.node, err)
+		return nil, errno(errNoEnt)
+	}
+	return n, nil
+}
+
+func (c *conn) attach(ctx context.Context, d *decoder, e *encoder) error {
+	id := d.uint32()
+	// afid
+	d.uint32()
+	uname, aname, uid := d.string(), d.string(), d.uint32()
+	if d.err != nil {
+		return nil
+	}
+	// The aname's a token's secret. Attaches without one are only answered
+	// like the NFS server's calls, i.e. for wash's user on wash's host.
+	f := &fid{node: c.root}
+	if aname != "" && aname != "/" {
+		token, err := c.authenticate(aname)
+		if err != nil {
+			log.Infof("9P: Refused %v's attach from %v: the token is invalid or was revoked", uname, c.rw.RemoteAddr())
+			return err
+		}
+		f.secret, f.token = aname, token
+	} else if !c.local {
+		log.Infof("9P: Refused %v's attach from %v: remote clients must attach with a token", uname, c.rw.RemoteAddr())
+		return errno(errAccess)
+	} else if !allowsUID(uid) {
+		log.Infof("9P: Refused %v's attach from %v: uid %v isn't wash's user", uname, c.rw.RemoteAddr(), uid)
+		return errno(errAccess)
+	}
+	log.Infof("9P: %v attached from %v", uname, c.rw.RemoteAddr())
+	c.setFID(id, f)
+	e.qid(nodeQID(c.root))
+	return nil
+}
+
+func (c *conn) walk(ctx context.Context, d *decoder, e *encoder) error {
+	id, newID := d.uint32(), d.uint32()
+	names := make([]string, d.uint16())
+	for i := range names {
+		names[i] = d.string()
+	}
+	if d.err != nil {
+		return nil
+	}
+	f, err := c.getFID(id)
+	if err != nil {
+		return err
+	}
+
+	cur := f.node
+	var qids []qid
+	for i, name := range names {
+		if i == 0 {
+			if cur, err = refind(ctx, f); err != nil {
+				return err
+			}
+		}
+		if !cur.IsDir() {
+			if i == 0 {
+				return errno(errNotDir)
+			}
+			break
+		}
+		child, ok, err := cur.Child(ctx, name)
+		if err != nil {
+			activity.Warnf(ctx, "9P: List %v errored: %v", cur, err)
+			if i == 0 {
+				return errno(errIO)
+			}
+			break
+		}
+		if !ok || !f.visible(child) {
+			if i == 0 {
+				return errno(errNoEnt)
+			}
+			break
+		}
+		cur = child
+		qids = append(qids, nodeQID(cur))
+	}
+	// Lookups happen a lot, so they're only logged at the debug level like
+	// the FUSE filesystem's.
+	log.Debugf("9P: Walked %v from %v", names[:len(qids)], f.node)
+
+	// The new fid's only set if the whole walk succeeded.
+	if len(qids) == len(names) {
+		c.setFID(newID, f.to(cur))
+	}
+	e.uint16(uint16(len(qids)))
+	for _, q := range qids {
+		e.qid(q)
+	}
+	return nil
+}
+
+func (c *conn) lopen(ctx context.Context, d *decoder, e *encoder) error {
+	id, flags := d.uint32(), d.uint32()
+	if d.err != nil {
+		return nil
+	}
+	f, err := c.getFID(id)
+	if err != nil {
+		return err
+	}
+	// The filesystem's read-only.
+	if flags&oAccMode != 0 || flags&oTrunc != 0 {
+		return errno(errROFS)
+	}
+	n, err := refind(ctx, f)
+	if err != nil {
+		return err
+	}
+	if !n.IsDir() && !n.IsSymlink() && !plugin.ReadAction().IsSupportedOn(n.Entry) {
+		return errno(errAccess)
+	}
+
+	opened := f.to(n)
+	opened.opened = true
+	c.setFID(id, opened)
+	e.qid(nodeQID(n))
+	// An iounit of 0 tells the client to use the message size.
+	e.uint32(0)
+	return nil
+}
+
+func (c *conn) read(ctx context.Context, d *decoder, e *encoder) error {
+	id, offset, count := d.uint32(), d.uint64(), d.uint32()
+	if d.err != nil {
+		return nil
+	}
+	f, err := c.getFID(id)
+	if err != nil {
+		return err
+	}
+	if !f.opened {
+		return errno(errBadF)
+	}
+	if f.node.IsDir() {
+		return errno(errIsDir)
+	}
+	c.mux.Lock()
+	maxCount := c.msize - headerSize - 4
+	c.mux.Unlock()
+	if count > maxCount {
+		count = maxCount
+	}
+
+	data, err := plugin.ReadWithAnalytics(ctx, f.node.Entry, int64(count), int64(offset))
+	if err != nil && err != io.EOF {
+		activity.Warnf(ctx, "9P: Read %v errored: %v", f.node, err)
+		return errno(errIO)
+	}
+	activity.Record(ctx, "9P: Read %v/%v bytes starting at %v from %v", len(data), count, offset, f.node)
+	e.uint32(uint32(len(data)))
+	e.buf = append(e.buf, data...)
+	return nil
+}
+
+func (c *conn) readdir(ctx context.Context, d *decoder, e *encoder) error {
+	id, offset, count := d.uint32(), d.uint64(), d.uint32()
+	if d.err != nil {
+		return nil
+	}
+	f, err := c.getFID(id)
+	if err != nil {
+		return err
+	}
+	if !f.opened || !f.node.IsDir() {
+		return errno(errNotDir)
+	}
+
+	c.mux.Lock()
+	listing := f.listing
+	c.mux.Unlock()
+	if offset == 0 || listing == nil {
+		activity.Record(ctx, "9P: List %v", f.node)
+		listing, err = f.node.Listing(ctx)
+		if err != nil {
+			activity.Warnf(ctx, "9P: List %v errored: %v", f.node, err)
+			return errno(errIO)
+		}
+		visible := listing[:0]
+		for _, entry := range listing {
+			if entry.Name == "." || entry.Name == ".." || f.visible(entry.Node) {
+				visible = append(visible, entry)
+			}
+		}
+		listing = visible
+		c.mux.Lock()
+		f.listing = listing
+		c.mux.Unlock()
+	}
+	if offset > uint64(len(listing)) {
+		return errno(errInval)
+	}
+
+	entries := &encoder{}
+	for i := offset; i < uint64(len(listing)); i++ {
+		entry := &encoder{}
+		entry.qid(nodeQID(listing[i].Node))
+		entry.uint64(i + 1)
+		entry.uint8(dirType(listing[i].Node))
+		entry.string(listing[i].Name)
+		if len(entries.buf)+len(entry.buf) > int(count) {
+			break
+		}
+		entries.buf = append(entries.buf, entry.buf...)
+	}
+	e.uint32(uint32(len(entries.buf)))
+	e.buf = append(e.buf, entries.buf...)
+	return nil
+}
+
+func (c *conn) getattr(ctx context.Context, d *decoder, e *encoder) error {
+	id := d.uint32()
+	// request_mask. The basic attributes are always returned.
+	d.uint64()
+	if d.err != nil {
+		return nil
+	}
+	f, err := c.getFID(id)
+	if err != nil {
+		return err
+	}
+	n, err := refind(ctx, f)
+	if err != nil {
+		return err
+	}
+	a, _ := n.Attributes(ctx, true)
+	log.Debugf("9P: Getattr %v: %+v", n, a)
+
+	e.uint64(getattrBasic)
+	e.qid(nodeQID(n))
+	e.uint32(fileModes[a.Type] | a.Mode)
+	e.uint32(a.UID)
+	e.uint32(a.GID)
+	// nlink and rdev
+	e.uint64(1)
+	e.uint64(0)
+	e.uint64(a.Size)
+	// blksize and blocks
+	e.uint64(4096)
+	e.uint64((a.Size + 511) / 512)
+	for _, t := range []int64{a.Atime.Unix(), int64(a.Atime.Nanosecond()), a.Mtime.Unix(), int64(a.Mtime.Nanosecond()), a.Ctime.Unix(), int64(a.Ctime.Nanosecond()), a.Btime.Unix(), int64(a.Btime.Nanosecond())} {
+		e.uint64(uint64(t))
+	}
+	// gen and data_version
+	e.uint64(0)
+	e.uint64(0)
+	return nil
+}
+
+func (c *conn) readlink(ctx context.Context, d *decoder, e *encoder) error {
+	id := d.uint32()
+	if d.err != nil {
+		return nil
+	}
+	f, err := c.getFID(id)
+	if err != nil {
+		return err
+	}
+	link, ok := f.node.Entry.(*plugin.Symlink)
+	if !ok {
+		return errno(errInval)
+	}
+	// Targets are relative to the symlink's parent, so the client resolves
+	// them within the mount.
+	e.string(link.Target())
+	return nil
+}
+
+func (c *conn) statfs(ctx context.Context, d *decoder, e *encoder) error {
+	id := d.uint32()
+	if d.err != nil {
+		return nil
+	}
+	if _, err := c.getFID(id); err != nil {
+		return err
+	}
+	e.uint32(v9fsMagic)
+	// bsize
+	e.uint32(4096)
+	// blocks, bfree, bavail, files, ffree and fsid are all 0 since entries
+	// don't take up any space.
+	for i := 0; i < 6; i++ {
+		e.uint64(0)
+	}
+	e.uint32(maxNameLength)
+	return nil
+}
+
+// lock always succeeds since the filesystem's read-only.
+func lock(d *decoder, e *encoder) error {
+	e.uint8(0)
+	return nil
+}
+
+// getlock reports that the range isn't locked.
+func getlock(d *decoder, e *encoder) error {
+	// fid
+	d.uint32()
+	// type
+	d.uint8()
+	start, length, procID, clientID := d.uint64(), d.uint64(), d.uint32(), d.string()
+	// F_UNLCK
+	e.uint8(2)
+	e.uint64(start)
+	e.uint64(length)
+	e.uint32(procID)
+	e.string(clientID)
+	return nil
+}
@@ -0,0 +1,376 @@
+package ninep
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/puppetlabs/wash/api/auth"
+	"github.com/puppetlabs/wash/datastore"
+	"github.com/puppetlabs/wash/netfs"
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/stretchr/testify/suite"
+)
+
+type testRoot struct {
+	plugin.EntryBase
+}
+
+func (r *testRoot) Init(map[string]interface{}) error {
+	return nil
+}
+
+func (r *testRoot) Schema() *plugin.EntrySchema {
+	return nil
+}
+
+func (r *testRoot) ChildSchemas() []*plugin.EntrySchema {
+	return nil
+}
+
+func (r *testRoot) List(context.Context) ([]plugin.Entry, error) {
+	return []plugin.Entry{
+		&testFile{EntryBase: plugin.NewEntry("file"), content: "hello"},
+		plugin.NewSymlink("file", "link"),
+	}, nil
+}
+
+type testFile struct {
+	plugin.EntryBase
+	content string
+}
+
+func (f *testFile) Schema() *plugin.EntrySchema {
+	return nil
+}
+
+func (f *testFile) Read(context.Context) ([]byte, error) {
+	return []byte(f.content), nil
+}
+
+type opsTestSuite struct {
+	suite.Suite
+	root      *netfs.Node
+	tokensDir string
+	tokens    *auth.Store
+	client    net.Conn
+	tag       uint16
+}
+
+func (suite *opsTestSuite) SetupTest() {
+	plugin.SetTestCache(datastore.NewMemCache())
+	registry := plugin.NewRegistry()
+	for _, name := range []string{"mine", "other"} {
+		root := &testRoot{EntryBase: plugin.NewEntry(name)}
+		root.SetTestID("/" + name)
+		suite.NoError(registry.RegisterPlugin(root, map[string]interface{}{}))
+	}
+	suite.root = &netfs.Node{Entry: registry}
+
+	var err error
+	suite.tokensDir, err = ioutil.TempDir("", "ninep")
+	suite.Require().NoError(err)
+	suite.tokens = auth.NewStore(filepath.Join(suite.tokensDir, "tokens.json"))
+
+	suite.connect(true)
+	suite.request(tattach, suite.attachBody(0, "", 0))
+}
+
+func (suite *opsTestSuite) TearDownTest() {
+	suite.client.Close()
+	os.RemoveAll(suite.tokensDir)
+	plugin.UnsetTestCache()
+}
+
+// connect replaces the client's connection with a new one, which is treated
+// like the local kernel client's if local is true.
+func (suite *opsTestSuite) connect(local bool) {
+	if suite.client != nil {
+		suite.client.Close()
+	}
+	client, server := net.Pipe()
+	suite.client = client
+	c := newConn(suite.root, server, suite.tokens)
+	c.local = local
+	go c.serve(context.Background())
+
+	r := suite.request(tversion, func(e *encoder) {
+		e.uint32(8192)
+		e.string(protocolVersion)
+	})
+	suite.Equal(uint32(8192), r.uint32())
+	suite.Equal(protocolVersion, r.string())
+}
+
+func (suite *opsTestSuite) attachBody(fid uint32, aname string, uid uint32) func(*encoder) {
+	return func(e *encoder) {
+		e.uint32(fid)
+		e.uint32(^uint32(0))
+		e.string("user")
+		e.string(aname)
+		e.uint32(uid)
+	}
+}
+
+// call sends the request and returns the reply's type and body.
+func (suite *opsTestSuite) call(typ uint8, body func(*encoder)) (uint8, *decoder) {
+	suite.tag++
+	e := &encoder{buf: make([]byte, headerSize)}
+	if body != nil {
+		body(e)
+	}
+	binary.LittleEndian.PutUint32(e.buf, uint32(len(e.buf)))
+	e.buf[4] = typ
+	binary.LittleEndian.PutUint16(e.buf[5:], suite.tag)
+	_, err := suite.client.Write(e.buf)
+	suite.Require().NoError(err)
+
+	header := make([]byte, headerSize)
+	_, err = io.ReadFull(suite.client, header)
+	suite.Require().NoError(err)
+	reply := make([]byte, binary.LittleEndian.Uint32(header)-headerSize)
+	_, err = io.ReadFull(suite.client, reply)
+	suite.Require().NoError(err)
+	suite.Equal(suite.tag, binary.LittleEndian.Uint16(header[5:]))
+	return header[4], &decoder{buf: reply}
+}
+
+// request sends a request that must succeed.
+func (suite *opsTestSuite) request(typ uint8, body func(*encoder)) *decoder {
+	replyType, d := suite.call(typ, body)
+	suite.Require().Equal(typ+1, replyType, "request %v failed with errno %v", typ, d.buf)
+	return d
+}
+
+// requestErr sends a request that must fail and returns its errno.
+func (suite *opsTestSuite) requestErr(typ uint8, body func(*encoder)) uint32 {
+	replyType, d := suite.call(typ, body)
+	suite.Require().Equal(uint8(rlerror), replyType)
+	return d.uint32()
+}
+
+// walk walks fid 0 to newfid.
+func (suite *opsTestSuite) walk(newfid uint32, names ...string) *decoder {
+	return suite.request(twalk, func(e *encoder) {
+		e.uint32(0)
+		e.uint32(newfid)
+		e.uint16(uint16(len(names)))
+		for _, name := range names {
+			e.string(name)
+		}
+	})
+}
+
+func (suite *opsTestSuite) open(fid uint32, flags uint32) {
+	suite.request(tlopen, func(e *encoder) {
+		e.uint32(fid)
+		e.uint32(flags)
+	})
+}
+
+func (suite *opsTestSuite) TestWalk() {
+	d := suite.walk(1, "mine", "file")
+	suite.Equal(uint16(2), d.uint16())
+	suite.Equal(uint8(qidDir), d.uint8())
+	d.next(qidSize - 1)
+	suite.Equal(uint8(qidFile), d.uint8())
+	suite.NoError(d.err)
+
+	// A partial walk returns the qids that were walked, and doesn't set the
+	// new fid.
+	d = suite.walk(2, "mine", "missing")
+	suite.Equal(uint16(1), d.uint16())
+	suite.Equal(uint32(errBadF), suite.requestErr(tgetattr, func(e *encoder) {
+		e.uint32(2)
+		e.uint64(getattrBasic)
+	}))
+
+	suite.Equal(uint32(errNoEnt), suite.requestErr(twalk, func(e *encoder) {
+		e.uint32(0)
+		e.uint32(2)
+		e.uint16(1)
+		e.string("missing")
+	}))
+}
+
+func (suite *opsTestSuite) TestGetattr() {
+	for _, c := range []struct {
+		names []string
+		mode  uint32
+		size  uint64
+	}{
+		{[]string{"mine"}, modeDir | 0550, 0},
+		// The file's size is computed from its content.
+		{[]string{"mine", "file"}, modeReg | 0440, 5},
+		{[]string{"mine", "link"}, modeSymlink | 0777, 0},
+	} {
+		suite.walk(1, c.names...)
+		d := suite.request(tgetattr, func(e *encoder) {
+			e.uint32(1)
+			e.uint64(getattrBasic)
+		})
+		suite.Equal(uint64(getattrBasic), d.uint64())
+		d.next(qidSize)
+		suite.Equal(c.mode, d.uint32(), c.names)
+		// uid, gid, nlink and rdev
+		d.next(24)
+		suite.Equal(c.size, d.uint64(), c.names)
+		suite.NoError(d.err)
+	}
+}
+
+func (suite *opsTestSuite) TestRead() {
+	suite.walk(1, "mine", "file")
+	suite.open(1, 0)
+	read := func(offset uint64, count uint32) string {
+		d := suite.request(tread, func(e *encoder) {
+			e.uint32(1)
+			e.uint64(offset)
+			e.uint32(count)
+		})
+		data := d.next(int(d.uint32()))
+		suite.NoError(d.err)
+		return string(data)
+	}
+	suite.Equal("he", read(0, 2))
+	suite.Equal("llo", read(2, 10))
+	suite.Equal("", read(5, 10))
+}
+
+func (suite *opsTestSuite) TestReaddir() {
+	suite.walk(1, "mine")
+	suite.open(1, 0)
+	readdir := func(offset uint64, count uint32) ([]string, []uint64) {
+		d := suite.request(treaddir, func(e *encoder) {
+			e.uint32(1)
+			e.uint64(offset)
+			e.uint32(count)
+		})
+		entries := &decoder{buf: d.next(int(d.uint32()))}
+		suite.NoError(d.err)
+		var names []string
+		var offsets []uint64
+		for len(entries.buf) > 0 {
+			entries.next(qidSize)
+			offsets = append(offsets, entries.uint64())
+			entries.uint8()
+			names = append(names, entries.string())
+		}
+		suite.NoError(entries.err)
+		return names, offsets
+	}
+
+	names, offsets := readdir(0, 8192)
+	suite.Equal([]string{".", "..", "file", "link"}, names)
+	suite.Equal([]uint64{1, 2, 3, 4}, offsets)
+
+	// Each entry's 24 bytes plus its name, so only "file" fits.
+	names, offsets = readdir(2, 30)
+	suite.Equal([]string{"file"}, names)
+	suite.Equal([]uint64{3}, offsets)
+	names, _ = readdir(4, 8192)
+	suite.Empty(names)
+}
+
+func (suite *opsTestSuite) TestReadlink() {
+	suite.walk(1, "mine", "link")
+	d := suite.request(treadlink, func(e *encoder) {
+		e.uint32(1)
+	})
+	suite.Equal("file", d.string())
+}
+
+func (suite *opsTestSuite) TestReadOnly() {
+	suite.walk(1, "mine", "file")
+	suite.Equal(uint32(errROFS), suite.requestErr(tlopen, func(e *encoder) {
+		e.uint32(1)
+		// O_WRONLY
+		e.uint32(1)
+	}))
+	suite.Equal(uint32(errROFS), suite.requestErr(tmkdir, func(e *encoder) {
+		e.uint32(0)
+		e.string("dir")
+		e.uint32(0755)
+		e.uint32(0)
+	}))
+
+	// Tremove clunks the fid even though it fails.
+	suite.Equal(uint32(errROFS), suite.requestErr(tremove, func(e *encoder) {
+		e.uint32(1)
+	}))
+	suite.Equal(uint32(errBadF), suite.requestErr(tclunk, func(e *encoder) {
+		e.uint32(1)
+	}))
+}
+
+func (suite *opsTestSuite) TestAttach() {
+	suite.request(tattach, suite.attachBody(1, "/", netfs.UID))
+	suite.request(tattach, suite.attachBody(1, "", noUID))
+	if netfs.UID != 12345 {
+		suite.Equal(uint32(errAccess), suite.requestErr(tattach, suite.attachBody(1, "", 12345)))
+	}
+	suite.Equal(uint32(errAccess), suite.requestErr(tattach, suite.attachBody(1, "invalid", 0)))
+
+	// Remote clients must attach with a token.
+	suite.connect(false)
+	suite.Equal(uint32(errAccess), suite.requestErr(tattach, suite.attachBody(0, "", 0)))
+	secret, err := suite.tokens.Create("vm", []string{"mine"}, nil)
+	suite.Require().NoError(err)
+	suite.request(tattach, suite.attachBody(0, secret, noUID))
+}
+
+func (suite *opsTestSuite) TestAttach_TokenOnlySeesItsPlugins() {
+	secret, err := suite.tokens.Create("vm", []string{"mine"}, nil)
+	suite.Require().NoError(err)
+	suite.connect(false)
+	suite.request(tattach, suite.attachBody(0, secret, noUID))
+
+	suite.walk(1, "mine", "file")
+	suite.Equal(uint32(errNoEnt), suite.requestErr(twalk, func(e *encoder) {
+		e.uint32(0)
+		e.uint32(2)
+		e.uint16(1)
+		e.string("other")
+	}))
+
+	suite.walk(2)
+	suite.open(2, 0)
+	d := suite.request(treaddir, func(e *encoder) {
+		e.uint32(2)
+		e.uint64(0)
+		e.uint32(8192)
+	})
+	entries := &decoder{buf: d.next(int(d.uint32()))}
+	var names []string
+	for len(entries.buf) > 0 {
+		entries.next(qidSize + 8 + 1)
+		names = append(names, entries.string())
+	}
+	suite.NoError(entries.err)
+	suite.Equal([]string{".", "..", "mine"}, names)
+
+	// Revoking the token stops its fids from being used.
+	suite.Require().NoError(suite.tokens.Revoke("vm"))
+	suite.Equal(uint32(errAccess), suite.requestErr(tgetattr, func(e *encoder) {
+		e.uint32(1)
+		e.uint64(getattrBasic)
+	}))
+}
+
+func (suite *opsTestSuite) TestUnknownVersion() {
+	d := suite.request(tversion, func(e *encoder) {
+		e.uint32(8192)
+		e.string("9P2000")
+	})
+	d.uint32()
+	suite.Equal("unknown", d.string())
+}
+
+func TestOps(t *testing.T) {
+	suite.Run(t, new(opsTestSuite))
+}
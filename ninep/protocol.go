@@ -0,0 +1,199 @@
+package ninep
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// 9P2000.L message types. Each reply's type is its request's type plus one.
+const (
+	rlerror      = 7
+	tstatfs      = 8
+	tlopen       = 12
+	tlcreate     = 14
+	tsymlink     = 16
+	tmknod       = 18
+	trename      = 20
+	treadlink    = 22
+	tgetattr     = 24
+	tsetattr     = 26
+	txattrwalk   = 30
+	txattrcreate = 32
+	treaddir     = 40
+	tfsync       = 50
+	tlock        = 52
+	tgetlock     = 54
+	tlink        = 70
+	tmkdir       = 72
+	trenameat    = 74
+	tunlinkat    = 76
+	tversion     = 100
+	tauth        = 102
+	tattach      = 104
+	tflush       = 108
+	twalk        = 110
+	tread        = 116
+	twrite       = 118
+	tclunk       = 120
+	tremove      = 122
+)
+
+// The errors that are returned in Rlerror. 9P2000.L uses Linux's errno values
+// regardless of the server's OS, so they're defined here instead of using the
+// syscall package's.
+const (
+	errNoEnt     = 2
+	errIO        = 5
+	errBadF      = 9
+	errAccess    = 13
+	errNotDir    = 20
+	errIsDir     = 21
+	errInval     = 22
+	errROFS      = 30
+	errNoSys     = 38
+	errOpNotSupp = 95
+)
+
+const (
+	protocolVersion = "9P2000.L"
+
+	// headerSize is the size of a message's size, type and tag.
+	headerSize = 7
+	// maxMessageSize bounds the message size that's negotiated with Tversion.
+	maxMessageSize = 1 << 20
+
+	qidDir     = 0x80
+	qidSymlink = 0x02
+	qidFile    = 0x00
+
+	// The file types in Rreaddir's entries
+	dtDir = 4
+	dtReg = 8
+	dtLnk = 10
+
+	// The file types in Rgetattr's mode
+	modeDir     = 0040000
+	modeReg     = 0100000
+	modeSymlink = 0120000
+
+	// getattrBasic is P9_GETATTR_BASIC, the attributes that Rgetattr returns.
+	getattrBasic = 0x7ff
+
+	// oAccMode and oTrunc are Linux's open flags that Tlopen uses.
+	oAccMode = 03
+	oTrunc   = 01000
+
+	// v9fsMagic is the filesystem type in Rstatfs.
+	v9fsMagic = 0x01021997
+
+	maxNameLength = 255
+)
+
+// errShortMessage is returned when a message's fields can't be decoded.
+var errShortMessage = errors.New("the message is too short")
+
+// decoder decodes a message's fields. The first decoding error is kept in err,
+// and later reads return zero values, so callers only check err once they've
+// read all of the fields.
+type decoder struct {
+	buf []byte
+	err error
+}
+
+func (d *decoder) next(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n > len(d.buf) {
+		d.err = errShortMessage
+		return nil
+	}
+	b := d.buf[:n]
+	d.buf = d.buf[n:]
+	return b
+}
+
+func (d *decoder) uint8() uint8 {
+	b := d.next(1)
+	if b == nil {
+		return 0
+	}
+	return b[0]
+}
+
+func (d *decoder) uint16() uint16 {
+	b := d.next(2)
+	if b == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint16(b)
+}
+
+func (d *decoder) uint32() uint32 {
+	b := d.next(4)
+	if b == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint32(b)
+}
+
+func (d *decoder) uint64() uint64 {
+	b := d.next(8)
+	if b == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint64(b)
+}
+
+func (d *decoder) string() string {
+	return string(d.next(int(d.uint16())))
+}
+
+// encoder encodes a message's fields.
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) uint8(v uint8) {
+	e.buf = append(e.buf, v)
+}
+
+func (e *encoder) uint16(v uint16) {
+	var b [2]byte
+	binary.LittleEndian.PutUint16(b[:], v)
+	e.buf = append(e.buf, b[:]...)
+}
+
+func (e *encoder) uint32(v uint32) {
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], v)
+	e.buf = append(e.buf, b[:]...)
+}
+
+func (e *encoder) uint64(v uint64) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], v)
+	e.buf = append(e.buf, b[:]...)
+}
+
+func (e *encoder) string(s string) {
+	e.uint16(uint16(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+// qid identifies a file on the server.
+type qid struct {
+	typ  uint8
+	path uint64
+}
+
+// qidSize is the size of an encoded qid.
+const qidSize = 13
+
+func (e *encoder) qid(q qid) {
+	e.uint8(q.typ)
+	// The version is always 0 since the server doesn't know when an entry
+	// changes.
+	e.uint32(0)
+	e.uint64(q.path)
+}
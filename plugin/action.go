@@ -89,6 +89,13 @@ var deleteAction = newAction("delete", "Deletable", func(e Entry) MethodSignatur
 	return UnsupportedSignature
 })
 
+var renameAction = newAction("rename", "Renamable", func(e Entry) MethodSignature {
+	if _, ok := e.(Renamable); ok {
+		return DefaultSignature
+	}
+	return UnsupportedSignature
+})
+
 var signalAction = newAction("signal", "Signalable", func(e Entry) MethodSignature {
 	if _, ok := e.(Signalable); ok {
 		return DefaultSignature
@@ -96,6 +103,20 @@ var signalAction = newAction("signal", "Signalable", func(e Entry) MethodSignatu
 	return UnsupportedSignature
 })
 
+var searchAction = newAction("search", "Searchable", func(e Entry) MethodSignature {
+	if _, ok := e.(Searchable); ok {
+		return DefaultSignature
+	}
+	return UnsupportedSignature
+})
+
+var watchAction = newAction("watch", "Watchable", func(e Entry) MethodSignature {
+	if _, ok := e.(Watchable); ok {
+		return DefaultSignature
+	}
+	return UnsupportedSignature
+})
+
 // ListAction represents the list action
 func ListAction() Action {
 	return listAction
@@ -126,11 +147,26 @@ func DeleteAction() Action {
 	return deleteAction
 }
 
+// RenameAction represents the rename action
+func RenameAction() Action {
+	return renameAction
+}
+
 // SignalAction represents the signal action
 func SignalAction() Action {
 	return signalAction
 }
 
+// SearchAction represents the search action
+func SearchAction() Action {
+	return searchAction
+}
+
+// WatchAction represents the watch action
+func WatchAction() Action {
+	return watchAction
+}
+
 // Actions returns all of the available Wash actions as a map
 // of <action_name> => <action_object>.
 func Actions() map[string]Action {
@@ -56,6 +56,13 @@ func DeleteWithAnalytics(ctx context.Context, d Deletable) (bool, error) {
 	return Delete(ctx, d)
 }
 
+// RenameWithAnalytics is a wrapper to plugin.Rename. Use it when you need to report a
+// 'Rename' invocation to analytics. Otherwise, use plugin.Rename.
+func RenameWithAnalytics(ctx context.Context, r Renamable, newParent Parent, newName string) error {
+	submitMethodInvocation(ctx, r, "Rename")
+	return Rename(ctx, r, newParent, newName)
+}
+
 func submitMethodInvocation(ctx context.Context, e Entry, method string) {
 	isCorePluginEntry := e.Schema() != nil
 	if !isCorePluginEntry {
@@ -0,0 +1,286 @@
+package aws
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	awsSDK "github.com/aws/aws-sdk-go/aws"
+	apigatewayClient "github.com/aws/aws-sdk-go/service/apigateway"
+	apigatewayv2Client "github.com/aws/aws-sdk-go/service/apigatewayv2"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// apigatewayAPI represents an API Gateway API. HTTP and WebSocket APIs are
+// managed by API Gateway v2 and REST APIs by v1, so exactly one of client and
+// clientV2 is set.
+type apigatewayAPI struct {
+	plugin.EntryBase
+	id       string
+	client   *apigatewayClient.APIGateway
+	clientV2 *apigatewayv2Client.ApiGatewayV2
+}
+
+func newAPIGatewayV2API(name string, api *apigatewayv2Client.Api, client *apigatewayv2Client.ApiGatewayV2) *apigatewayAPI {
+	apigatewayAPI := &apigatewayAPI{
+		EntryBase: plugin.NewEntry(name),
+	}
+	apigatewayAPI.id = awsSDK.StringValue(api.ApiId)
+	apigatewayAPI.clientV2 = client
+	apigatewayAPI.
+		SetPartialMetadata(api).
+		Attributes().
+		SetCrtime(awsSDK.TimeValue(api.CreatedDate)).
+		SetCustom("protocol", awsSDK.StringValue(api.ProtocolType))
+	return apigatewayAPI
+}
+
+func newAPIGatewayRestAPI(name string, api *apigatewayClient.RestApi, client *apigatewayClient.APIGateway) *apigatewayAPI {
+	apigatewayAPI := &apigatewayAPI{
+		EntryBase: plugin.NewEntry(name),
+	}
+	apigatewayAPI.id = awsSDK.StringValue(api.Id)
+	apigatewayAPI.client = client
+	apigatewayAPI.
+		SetPartialMetadata(api).
+		Attributes().
+		SetCrtime(awsSDK.TimeValue(api.CreatedDate)).
+		SetCustom("protocol", "REST")
+	return apigatewayAPI
+}
+
+func (a *apigatewayAPI) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(a, "api").
+		SetDescription(apigatewayAPIDescription).
+		AddCustomAttribute("protocol", plugin.StringAttribute, "The API's protocol, i.e. HTTP, WEBSOCKET or REST")
+}
+
+func (a *apigatewayAPI) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&apigatewayStagesDir{}).Schema(),
+		(&apigatewayRoutesDir{}).Schema(),
+	}
+}
+
+func (a *apigatewayAPI) List(ctx context.Context) ([]plugin.Entry, error) {
+	return []plugin.Entry{
+		newAPIGatewayStagesDir(a),
+		newAPIGatewayRoutesDir(a),
+	}, nil
+}
+
+func (a *apigatewayAPI) Metadata(ctx context.Context) (plugin.JSONObject, error) {
+	if a.client != nil {
+		api, err := a.client.GetRestApiWithContext(ctx, &apigatewayClient.GetRestApiInput{
+			RestApiId: awsSDK.String(a.id),
+		})
+		if err != nil {
+			return nil, err
+		}
+		return plugin.ToJSONObject(api), nil
+	}
+	api, err := a.clientV2.GetApiWithContext(ctx, &apigatewayv2Client.GetApiInput{
+		ApiId: awsSDK.String(a.id),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return plugin.ToJSONObject(api), nil
+}
+
+// apigatewayStagesDir represents an API's stages directory
+type apigatewayStagesDir struct {
+	plugin.EntryBase
+	api *apigatewayAPI
+}
+
+func newAPIGatewayStagesDir(api *apigatewayAPI) *apigatewayStagesDir {
+	stagesDir := &apigatewayStagesDir{
+		EntryBase: plugin.NewEntry("stages"),
+	}
+	stagesDir.api = api
+	return stagesDir
+}
+
+func (d *apigatewayStagesDir) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(d, "stages").IsSingleton()
+}
+
+func (d *apigatewayStagesDir) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&apigatewayStage{}).Schema(),
+	}
+}
+
+func (d *apigatewayStagesDir) List(ctx context.Context) ([]plugin.Entry, error) {
+	var stages []plugin.Entry
+	if d.api.client != nil {
+		resp, err := d.api.client.GetStagesWithContext(ctx, &apigatewayClient.GetStagesInput{
+			RestApiId: awsSDK.String(d.api.id),
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, stage := range resp.Item {
+			stages = append(stages, newAPIGatewayStage(awsSDK.StringValue(stage.StageName), stage, stage.CreatedDate, stage.LastUpdatedDate))
+		}
+	} else {
+		request := &apigatewayv2Client.GetStagesInput{ApiId: awsSDK.String(d.api.id)}
+		for {
+			resp, err := d.api.clientV2.GetStagesWithContext(ctx, request)
+			if err != nil {
+				return nil, err
+			}
+			for _, stage := range resp.Items {
+				stages = append(stages, newAPIGatewayStage(awsSDK.StringValue(stage.StageName), stage, stage.CreatedDate, stage.LastUpdatedDate))
+			}
+			if resp.NextToken == nil {
+				break
+			}
+			request.NextToken = resp.NextToken
+		}
+	}
+
+	activity.Record(ctx, "Listing %v stages of the %v API", len(stages), d.api.id)
+	return stages, nil
+}
+
+// apigatewayStage represents an API's stage. Its partial metadata is the stage's
+// description from API Gateway v1 or v2.
+type apigatewayStage struct {
+	plugin.EntryBase
+}
+
+func newAPIGatewayStage(name string, stage interface{}, created *time.Time, updated *time.Time) *apigatewayStage {
+	apigatewayStage := &apigatewayStage{
+		EntryBase: plugin.NewEntry(name),
+	}
+	apigatewayStage.
+		SetPartialMetadata(stage).
+		Attributes().
+		SetCrtime(awsSDK.TimeValue(created)).
+		SetMtime(awsSDK.TimeValue(updated))
+	return apigatewayStage
+}
+
+func (s *apigatewayStage) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(s, "stage").
+		SetDescription(apigatewayStageDescription)
+}
+
+// apigatewayRoutesDir represents an API's routes directory
+type apigatewayRoutesDir struct {
+	plugin.EntryBase
+	api *apigatewayAPI
+}
+
+func newAPIGatewayRoutesDir(api *apigatewayAPI) *apigatewayRoutesDir {
+	routesDir := &apigatewayRoutesDir{
+		EntryBase: plugin.NewEntry("routes"),
+	}
+	routesDir.api = api
+	return routesDir
+}
+
+func (d *apigatewayRoutesDir) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(d, "routes").IsSingleton()
+}
+
+func (d *apigatewayRoutesDir) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&apigatewayRoute{}).Schema(),
+	}
+}
+
+// apigatewayRestRoute is a REST API's method on one of its resources.
+type apigatewayRestRoute struct {
+	Path   string
+	Method *apigatewayClient.Method
+}
+
+// List lists the API's routes. REST APIs don't have routes, so their routes are
+// their resources' methods.
+func (d *apigatewayRoutesDir) List(ctx context.Context) ([]plugin.Entry, error) {
+	var routes []plugin.Entry
+	if d.api.client != nil {
+		request := &apigatewayClient.GetResourcesInput{
+			RestApiId: awsSDK.String(d.api.id),
+			Embed:     awsSDK.StringSlice([]string{"methods"}),
+		}
+		err := d.api.client.GetResourcesPagesWithContext(ctx, request, func(resp *apigatewayClient.GetResourcesOutput, _ bool) bool {
+			for _, resource := range resp.Items {
+				path := awsSDK.StringValue(resource.Path)
+				for httpMethod, method := range resource.ResourceMethods {
+					route := apigatewayRestRoute{Path: path, Method: method}
+					routes = append(routes, newAPIGatewayRoute(httpMethod+" "+path, route))
+				}
+			}
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		request := &apigatewayv2Client.GetRoutesInput{ApiId: awsSDK.String(d.api.id)}
+		for {
+			resp, err := d.api.clientV2.GetRoutesWithContext(ctx, request)
+			if err != nil {
+				return nil, err
+			}
+			for _, route := range resp.Items {
+				routes = append(routes, newAPIGatewayRoute(awsSDK.StringValue(route.RouteKey), route))
+			}
+			if resp.NextToken == nil {
+				break
+			}
+			request.NextToken = resp.NextToken
+		}
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		return plugin.Name(routes[i]) < plugin.Name(routes[j])
+	})
+
+	activity.Record(ctx, "Listing %v routes of the %v API", len(routes), d.api.id)
+	return routes, nil
+}
+
+// apigatewayRoute represents an API's route. It's named by its route key, like
+// "GET /pets/{id}".
+type apigatewayRoute struct {
+	plugin.EntryBase
+}
+
+func newAPIGatewayRoute(routeKey string, route interface{}) *apigatewayRoute {
+	apigatewayRoute := &apigatewayRoute{
+		EntryBase: plugin.NewEntry(routeKey),
+	}
+	apigatewayRoute.
+		SetSlashReplacer('#').
+		SetPartialMetadata(route)
+	return apigatewayRoute
+}
+
+func (r *apigatewayRoute) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(r, "route").
+		SetDescription(apigatewayRouteDescription)
+}
+
+const apigatewayAPIDescription = `
+This is an API Gateway API. HTTP and WebSocket APIs are from API Gateway v2, and
+REST APIs are from API Gateway v1. It contains the API's stages and routes.
+`
+
+const apigatewayStageDescription = `
+This is an API Gateway stage. Its metadata includes its deployment, variables
+and throttling settings.
+`
+
+const apigatewayRouteDescription = `
+This is an API Gateway route, named by its route key. The routes of REST APIs
+are their resources' methods, e.g. "GET /pets/{id}". The '/' in a route's name
+is replaced by '#' in its path.
+`
@@ -0,0 +1,95 @@
+package aws
+
+import (
+	"context"
+
+	awsSDK "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	apigatewayClient "github.com/aws/aws-sdk-go/service/apigateway"
+	apigatewayv2Client "github.com/aws/aws-sdk-go/service/apigatewayv2"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// apigatewayDir represents the resources/apigateway directory. It lists the
+// profile's HTTP and WebSocket APIs (from API Gateway v2) and its REST APIs
+// (from API Gateway v1).
+type apigatewayDir struct {
+	plugin.EntryBase
+	client   *apigatewayClient.APIGateway
+	clientV2 *apigatewayv2Client.ApiGatewayV2
+}
+
+func newAPIGatewayDir(ctx context.Context, session *session.Session) *apigatewayDir {
+	apigatewayDir := &apigatewayDir{
+		EntryBase: plugin.NewEntry("apigateway"),
+	}
+	apigatewayDir.client = apigatewayClient.New(session)
+	apigatewayDir.clientV2 = apigatewayv2Client.New(session)
+	if _, err := plugin.List(ctx, apigatewayDir); err != nil {
+		apigatewayDir.MarkInaccessible(ctx, err)
+	}
+	return apigatewayDir
+}
+
+func (d *apigatewayDir) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(d, "apigateway").IsSingleton()
+}
+
+func (d *apigatewayDir) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&apigatewayAPI{}).Schema(),
+	}
+}
+
+// List lists the APIs. API names aren't unique, so APIs that share a name are
+// suffixed with their ID.
+func (d *apigatewayDir) List(ctx context.Context) ([]plugin.Entry, error) {
+	var apisV2 []*apigatewayv2Client.Api
+	request := &apigatewayv2Client.GetApisInput{}
+	for {
+		resp, err := d.clientV2.GetApisWithContext(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+		apisV2 = append(apisV2, resp.Items...)
+		if resp.NextToken == nil {
+			break
+		}
+		request.NextToken = resp.NextToken
+	}
+
+	var restAPIs []*apigatewayClient.RestApi
+	err := d.client.GetRestApisPagesWithContext(ctx, &apigatewayClient.GetRestApisInput{}, func(resp *apigatewayClient.GetRestApisOutput, _ bool) bool {
+		restAPIs = append(restAPIs, resp.Items...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int)
+	for _, api := range apisV2 {
+		counts[awsSDK.StringValue(api.Name)]++
+	}
+	for _, api := range restAPIs {
+		counts[awsSDK.StringValue(api.Name)]++
+	}
+	name := func(name *string, id *string) string {
+		if counts[awsSDK.StringValue(name)] > 1 {
+			return awsSDK.StringValue(name) + "-" + awsSDK.StringValue(id)
+		}
+		return awsSDK.StringValue(name)
+	}
+
+	entries := make([]plugin.Entry, 0, len(apisV2)+len(restAPIs))
+	for _, api := range apisV2 {
+		entries = append(entries, newAPIGatewayV2API(name(api.Name, api.ApiId), api, d.clientV2))
+	}
+	for _, api := range restAPIs {
+		entries = append(entries, newAPIGatewayRestAPI(name(api.Name, api.Id), api, d.client))
+	}
+
+	activity.Record(ctx, "Listing %v API Gateway APIs", len(entries))
+	return entries, nil
+}
@@ -0,0 +1,58 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	cloudfrontClient "github.com/aws/aws-sdk-go/service/cloudfront"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// cloudfrontDir represents the resources/cloudfront directory. It lists the
+// profile's distributions.
+type cloudfrontDir struct {
+	plugin.EntryBase
+	client *cloudfrontClient.CloudFront
+}
+
+func newCloudFrontDir(ctx context.Context, session *session.Session) *cloudfrontDir {
+	cloudfrontDir := &cloudfrontDir{
+		EntryBase: plugin.NewEntry("cloudfront"),
+	}
+	cloudfrontDir.client = cloudfrontClient.New(session)
+	if _, err := plugin.List(ctx, cloudfrontDir); err != nil {
+		cloudfrontDir.MarkInaccessible(ctx, err)
+	}
+	return cloudfrontDir
+}
+
+func (c *cloudfrontDir) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(c, "cloudfront").IsSingleton()
+}
+
+func (c *cloudfrontDir) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&cloudfrontDistribution{}).Schema(),
+	}
+}
+
+// List lists the distributions.
+func (c *cloudfrontDir) List(ctx context.Context) ([]plugin.Entry, error) {
+	var distributions []plugin.Entry
+	err := c.client.ListDistributionsPagesWithContext(ctx, &cloudfrontClient.ListDistributionsInput{}, func(resp *cloudfrontClient.ListDistributionsOutput, _ bool) bool {
+		if resp.DistributionList == nil {
+			return true
+		}
+		for _, distribution := range resp.DistributionList.Items {
+			distributions = append(distributions, newCloudFrontDistribution(distribution, c.client))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	activity.Record(ctx, "Listing %v CloudFront distributions", len(distributions))
+	return distributions, nil
+}
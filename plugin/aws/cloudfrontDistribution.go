@@ -0,0 +1,120 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	awsSDK "github.com/aws/aws-sdk-go/aws"
+	cloudfrontClient "github.com/aws/aws-sdk-go/service/cloudfront"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// cloudfrontDistribution represents a CloudFront distribution. It's named by
+// its ID since distributions don't have names.
+type cloudfrontDistribution struct {
+	plugin.EntryBase
+	id     string
+	client *cloudfrontClient.CloudFront
+}
+
+func newCloudFrontDistribution(distribution *cloudfrontClient.DistributionSummary, client *cloudfrontClient.CloudFront) *cloudfrontDistribution {
+	cloudfrontDistribution := &cloudfrontDistribution{
+		EntryBase: plugin.NewEntry(awsSDK.StringValue(distribution.Id)),
+	}
+	cloudfrontDistribution.id = awsSDK.StringValue(distribution.Id)
+	cloudfrontDistribution.client = client
+	cloudfrontDistribution.
+		DisableCachingFor(plugin.MetadataOp).
+		SetPartialMetadata(distribution).
+		Attributes().
+		SetMtime(awsSDK.TimeValue(distribution.LastModifiedTime)).
+		SetCustom("status", awsSDK.StringValue(distribution.Status)).
+		SetCustom("domain_name", awsSDK.StringValue(distribution.DomainName)).
+		SetCustom("enabled", awsSDK.BoolValue(distribution.Enabled))
+	return cloudfrontDistribution
+}
+
+func (d *cloudfrontDistribution) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(d, "distribution").
+		SetDescription(cloudfrontDistributionDescription).
+		SetPartialMetadataSchema(cloudfrontClient.DistributionSummary{}).
+		SetMetadataSchema(cloudfrontClient.Distribution{}).
+		AddSignal("invalidate", "Invalidates all of the distribution's cached objects").
+		AddCustomAttribute("status", plugin.StringAttribute, "The distribution's deployment status, i.e. InProgress or Deployed").
+		AddCustomAttribute("domain_name", plugin.StringAttribute, "The distribution's domain name").
+		AddCustomAttribute("enabled", plugin.BooleanAttribute, "Whether the distribution is enabled")
+}
+
+func (d *cloudfrontDistribution) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&cloudfrontInvalidation{}).Schema(),
+	}
+}
+
+// List lists the distribution's invalidations.
+func (d *cloudfrontDistribution) List(ctx context.Context) ([]plugin.Entry, error) {
+	var invalidations []plugin.Entry
+	request := &cloudfrontClient.ListInvalidationsInput{DistributionId: awsSDK.String(d.id)}
+	err := d.client.ListInvalidationsPagesWithContext(ctx, request, func(resp *cloudfrontClient.ListInvalidationsOutput, _ bool) bool {
+		if resp.InvalidationList == nil {
+			return true
+		}
+		for _, invalidation := range resp.InvalidationList.Items {
+			invalidations = append(invalidations, newCloudFrontInvalidation(d.id, invalidation, d.client))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	activity.Record(ctx, "Listing %v invalidations of the %v distribution", len(invalidations), d.id)
+	return invalidations, nil
+}
+
+// Metadata returns the distribution's config and its deployment status.
+func (d *cloudfrontDistribution) Metadata(ctx context.Context) (plugin.JSONObject, error) {
+	resp, err := d.client.GetDistributionWithContext(ctx, &cloudfrontClient.GetDistributionInput{
+		Id: awsSDK.String(d.id),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return plugin.ToJSONObject(resp.Distribution), nil
+}
+
+// Signal supports the "invalidate" signal, which creates an invalidation of
+// every path in the distribution. The invalidation appears in the
+// distribution's listing.
+func (d *cloudfrontDistribution) Signal(ctx context.Context, signal string) error {
+	if signal != "invalidate" {
+		return fmt.Errorf("unknown signal %v", signal)
+	}
+	resp, err := d.client.CreateInvalidationWithContext(ctx, &cloudfrontClient.CreateInvalidationInput{
+		DistributionId: awsSDK.String(d.id),
+		InvalidationBatch: &cloudfrontClient.InvalidationBatch{
+			CallerReference: awsSDK.String(strconv.FormatInt(time.Now().UnixNano(), 10)),
+			Paths: &cloudfrontClient.Paths{
+				Items:    awsSDK.StringSlice([]string{"/*"}),
+				Quantity: awsSDK.Int64(1),
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	activity.Record(ctx, "Created invalidation %v of the %v distribution", awsSDK.StringValue(resp.Invalidation.Id), d.id)
+	return nil
+}
+
+const cloudfrontDistributionDescription = `
+This is a CloudFront distribution. Its metadata includes its origins, cache
+behaviors and deployment status. It contains the distribution's invalidations.
+
+Signal it with "invalidate" to invalidate all of its cached objects, e.g.
+  signal invalidate aws/default/resources/cloudfront/E2QWRUHAPOMQZL
+`
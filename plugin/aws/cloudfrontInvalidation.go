@@ -0,0 +1,59 @@
+package aws
+
+import (
+	"context"
+
+	awsSDK "github.com/aws/aws-sdk-go/aws"
+	cloudfrontClient "github.com/aws/aws-sdk-go/service/cloudfront"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// cloudfrontInvalidation represents an invalidation of a CloudFront
+// distribution's cached objects
+type cloudfrontInvalidation struct {
+	plugin.EntryBase
+	id             string
+	distributionID string
+	client         *cloudfrontClient.CloudFront
+}
+
+func newCloudFrontInvalidation(distributionID string, invalidation *cloudfrontClient.InvalidationSummary, client *cloudfrontClient.CloudFront) *cloudfrontInvalidation {
+	cloudfrontInvalidation := &cloudfrontInvalidation{
+		EntryBase: plugin.NewEntry(awsSDK.StringValue(invalidation.Id)),
+	}
+	cloudfrontInvalidation.id = awsSDK.StringValue(invalidation.Id)
+	cloudfrontInvalidation.distributionID = distributionID
+	cloudfrontInvalidation.client = client
+	cloudfrontInvalidation.
+		SetPartialMetadata(invalidation).
+		Attributes().
+		SetCrtime(awsSDK.TimeValue(invalidation.CreateTime)).
+		SetCustom("status", awsSDK.StringValue(invalidation.Status))
+	return cloudfrontInvalidation
+}
+
+func (i *cloudfrontInvalidation) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(i, "invalidation").
+		SetDescription(cloudfrontInvalidationDescription).
+		SetPartialMetadataSchema(cloudfrontClient.InvalidationSummary{}).
+		SetMetadataSchema(cloudfrontClient.Invalidation{}).
+		AddCustomAttribute("status", plugin.StringAttribute, "The invalidation's status, i.e. InProgress or Completed")
+}
+
+// Metadata returns the invalidation's paths and status.
+func (i *cloudfrontInvalidation) Metadata(ctx context.Context) (plugin.JSONObject, error) {
+	resp, err := i.client.GetInvalidationWithContext(ctx, &cloudfrontClient.GetInvalidationInput{
+		DistributionId: awsSDK.String(i.distributionID),
+		Id:             awsSDK.String(i.id),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return plugin.ToJSONObject(resp.Invalidation), nil
+}
+
+const cloudfrontInvalidationDescription = `
+This is an invalidation of a CloudFront distribution's cached objects. Its
+metadata includes the invalidated paths.
+`
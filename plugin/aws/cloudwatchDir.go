@@ -0,0 +1,38 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	logsClient "github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// cloudwatchDir represents the resources/cloudwatch directory
+type cloudwatchDir struct {
+	plugin.EntryBase
+	logsClient *logsClient.CloudWatchLogs
+}
+
+func newCloudwatchDir(session *session.Session) *cloudwatchDir {
+	cloudwatchDir := &cloudwatchDir{
+		EntryBase: plugin.NewEntry("cloudwatch"),
+	}
+	cloudwatchDir.DisableDefaultCaching()
+	cloudwatchDir.logsClient = logsClient.New(session)
+	return cloudwatchDir
+}
+
+func (c *cloudwatchDir) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(c, "cloudwatch").IsSingleton()
+}
+
+func (c *cloudwatchDir) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&cloudwatchLogGroupsDir{}).Schema(),
+	}
+}
+
+func (c *cloudwatchDir) List(ctx context.Context) ([]plugin.Entry, error) {
+	return []plugin.Entry{newCloudwatchLogGroupsDir(ctx, c.logsClient)}, nil
+}
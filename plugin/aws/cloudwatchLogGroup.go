@@ -0,0 +1,94 @@
+package aws
+
+import (
+	"context"
+	"io"
+	"time"
+
+	awsSDK "github.com/aws/aws-sdk-go/aws"
+	logsClient "github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// cloudwatchMaxLogStreams is the maximum number of log streams that are listed
+// in a log group. Log groups can have many thousands of streams, most of which
+// are stale, so only the streams with the most recent events are listed.
+const cloudwatchMaxLogStreams = 100
+
+// cloudwatchLogGroup represents a CloudWatch log group
+type cloudwatchLogGroup struct {
+	plugin.EntryBase
+	name   string
+	client *logsClient.CloudWatchLogs
+}
+
+func newCloudwatchLogGroup(group *logsClient.LogGroup, client *logsClient.CloudWatchLogs) *cloudwatchLogGroup {
+	name := awsSDK.StringValue(group.LogGroupName)
+	logGroup := &cloudwatchLogGroup{
+		EntryBase: plugin.NewEntry(name),
+	}
+	logGroup.name = name
+	logGroup.client = client
+
+	crtime := fromMillis(awsSDK.Int64Value(group.CreationTime))
+	logGroup.
+		SetTTLOf(plugin.ListOp, 30*time.Second).
+		SetPartialMetadata(group).
+		Attributes().
+		SetCrtime(crtime).
+		SetMtime(crtime).
+		SetCtime(crtime).
+		SetAtime(crtime)
+	return logGroup
+}
+
+func (g *cloudwatchLogGroup) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(g, "group").
+		SetDescription(cloudwatchLogGroupDescription).
+		SetPartialMetadataSchema(logsClient.LogGroup{})
+}
+
+func (g *cloudwatchLogGroup) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&cloudwatchLogStream{}).Schema(),
+	}
+}
+
+// List lists the log streams with the most recent events.
+func (g *cloudwatchLogGroup) List(ctx context.Context) ([]plugin.Entry, error) {
+	request := &logsClient.DescribeLogStreamsInput{
+		LogGroupName: awsSDK.String(g.name),
+		OrderBy:      awsSDK.String(logsClient.OrderByLastEventTime),
+		Descending:   awsSDK.Bool(true),
+	}
+	var streams []plugin.Entry
+	err := g.client.DescribeLogStreamsPagesWithContext(ctx, request, func(resp *logsClient.DescribeLogStreamsOutput, _ bool) bool {
+		for _, stream := range resp.LogStreams {
+			if len(streams) >= cloudwatchMaxLogStreams {
+				return false
+			}
+			streams = append(streams, newCloudwatchLogStream(stream, g.name, g.client))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	activity.Record(ctx, "Listing %v log streams in the %v log group", len(streams), g.name)
+	return streams, nil
+}
+
+// Stream follows the events of all of the group's log streams.
+func (g *cloudwatchLogGroup) Stream(ctx context.Context) (io.ReadCloser, error) {
+	activity.Record(ctx, "Following the %v log group", g.name)
+	return newCloudwatchLogsStreamer(ctx, g.client, g.name, nil), nil
+}
+
+const cloudwatchLogGroupDescription = `
+This is a CloudWatch log group. It lists the 100 log streams with the most recent
+events. Streaming it (e.g. with 'wash tail -f') follows the events of all of its
+log streams.
+`
@@ -0,0 +1,53 @@
+package aws
+
+import (
+	"context"
+
+	logsClient "github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// cloudwatchLogGroupsDir represents the cloudwatch/logs directory
+type cloudwatchLogGroupsDir struct {
+	plugin.EntryBase
+	client *logsClient.CloudWatchLogs
+}
+
+func newCloudwatchLogGroupsDir(ctx context.Context, client *logsClient.CloudWatchLogs) *cloudwatchLogGroupsDir {
+	groupsDir := &cloudwatchLogGroupsDir{
+		EntryBase: plugin.NewEntry("logs"),
+	}
+	groupsDir.client = client
+	if _, err := plugin.List(ctx, groupsDir); err != nil {
+		groupsDir.MarkInaccessible(ctx, err)
+	}
+	return groupsDir
+}
+
+func (d *cloudwatchLogGroupsDir) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(d, "logs").IsSingleton()
+}
+
+func (d *cloudwatchLogGroupsDir) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&cloudwatchLogGroup{}).Schema(),
+	}
+}
+
+// List lists the log groups.
+func (d *cloudwatchLogGroupsDir) List(ctx context.Context) ([]plugin.Entry, error) {
+	var groups []plugin.Entry
+	err := d.client.DescribeLogGroupsPagesWithContext(ctx, &logsClient.DescribeLogGroupsInput{}, func(resp *logsClient.DescribeLogGroupsOutput, _ bool) bool {
+		for _, group := range resp.LogGroups {
+			groups = append(groups, newCloudwatchLogGroup(group, d.client))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	activity.Record(ctx, "Listing %v CloudWatch log groups", len(groups))
+	return groups, nil
+}
@@ -0,0 +1,86 @@
+package aws
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	awsSDK "github.com/aws/aws-sdk-go/aws"
+	logsClient "github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// cloudwatchRecentEventsLimit is the maximum number of events that are read from
+// a log stream.
+const cloudwatchRecentEventsLimit = 1000
+
+// cloudwatchLogStream represents a CloudWatch log stream
+type cloudwatchLogStream struct {
+	plugin.EntryBase
+	name   string
+	group  string
+	client *logsClient.CloudWatchLogs
+}
+
+func newCloudwatchLogStream(stream *logsClient.LogStream, group string, client *logsClient.CloudWatchLogs) *cloudwatchLogStream {
+	name := awsSDK.StringValue(stream.LogStreamName)
+	logStream := &cloudwatchLogStream{
+		EntryBase: plugin.NewEntry(name),
+	}
+	logStream.name = name
+	logStream.group = group
+	logStream.client = client
+
+	crtime := fromMillis(awsSDK.Int64Value(stream.CreationTime))
+	mtime := crtime
+	if stream.LastEventTimestamp != nil {
+		mtime = fromMillis(awsSDK.Int64Value(stream.LastEventTimestamp))
+	}
+	logStream.
+		SetPartialMetadata(stream).
+		Attributes().
+		SetCrtime(crtime).
+		SetMtime(mtime).
+		SetCtime(mtime).
+		SetAtime(mtime)
+	return logStream
+}
+
+func (s *cloudwatchLogStream) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(s, "stream").
+		SetDescription(cloudwatchLogStreamDescription).
+		SetPartialMetadataSchema(logsClient.LogStream{})
+}
+
+// Read returns the stream's most recent events.
+func (s *cloudwatchLogStream) Read(ctx context.Context) ([]byte, error) {
+	resp, err := s.client.GetLogEventsWithContext(ctx, &logsClient.GetLogEventsInput{
+		LogGroupName:  awsSDK.String(s.group),
+		LogStreamName: awsSDK.String(s.name),
+		Limit:         awsSDK.Int64(cloudwatchRecentEventsLimit),
+		StartFromHead: awsSDK.Bool(false),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	activity.Record(ctx, "Read %v events from the %v log stream", len(resp.Events), s.name)
+	var b strings.Builder
+	for _, event := range resp.Events {
+		b.WriteString(formatLogEvent(awsSDK.Int64Value(event.Timestamp), awsSDK.StringValue(event.Message)))
+	}
+	return []byte(b.String()), nil
+}
+
+// Stream follows the stream's new events.
+func (s *cloudwatchLogStream) Stream(ctx context.Context) (io.ReadCloser, error) {
+	activity.Record(ctx, "Following the %v log stream in the %v log group", s.name, s.group)
+	return newCloudwatchLogsStreamer(ctx, s.client, s.group, []string{s.name}), nil
+}
+
+const cloudwatchLogStreamDescription = `
+This is a CloudWatch log stream. Reading it returns its 1000 most recent events.
+Streaming it (e.g. with 'tail -f') follows its new events.
+`
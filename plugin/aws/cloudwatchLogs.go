@@ -0,0 +1,152 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	awsSDK "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	logsClient "github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// cloudwatchLogsPollInterval is how often a cloudwatchLogsStreamer polls for new
+// events.
+var cloudwatchLogsPollInterval = 2 * time.Second
+
+// cloudwatchLogsStreamer follows a CloudWatch log group's events by polling
+// FilterLogEvents. It's used to stream the logs of resources that write to
+// CloudWatch, like Lambda functions.
+type cloudwatchLogsStreamer struct {
+	ctx    context.Context
+	client *logsClient.CloudWatchLogs
+	group  string
+	// streams restricts the followed events to the named log streams. All of the
+	// group's streams are followed if it's empty.
+	streams []string
+	// startTime is the timestamp (in milliseconds) of the newest streamed event.
+	// seen contains the IDs of the streamed events with that timestamp so that
+	// they're not streamed again by the next poll.
+	startTime int64
+	seen      map[string]struct{}
+	current   []byte
+}
+
+// newCloudwatchLogsStreamer returns a streamer that follows the group's new
+// events, or its events since the stream's since time if it's set.
+func newCloudwatchLogsStreamer(
+	ctx context.Context,
+	client *logsClient.CloudWatchLogs,
+	group string,
+	streams []string,
+) *cloudwatchLogsStreamer {
+	since, ok := plugin.StreamSince(ctx)
+	if !ok {
+		since = time.Now()
+	}
+	return &cloudwatchLogsStreamer{
+		ctx:       ctx,
+		client:    client,
+		group:     group,
+		streams:   streams,
+		startTime: toMillis(since),
+		seen:      make(map[string]struct{}),
+	}
+}
+
+func (s *cloudwatchLogsStreamer) Read(p []byte) (int, error) {
+	for len(s.current) == 0 {
+		if s.closed() {
+			return 0, io.EOF
+		}
+		if err := s.fetchEvents(); err != nil {
+			return 0, err
+		}
+		if len(s.current) > 0 {
+			break
+		}
+		select {
+		case <-s.ctx.Done():
+			return 0, io.EOF
+		case <-time.After(cloudwatchLogsPollInterval):
+		}
+	}
+	n := copy(p, s.current)
+	s.current = s.current[n:]
+	return n, nil
+}
+
+func (s *cloudwatchLogsStreamer) Close() error {
+	// s is closed when the context is cancelled, so this can noop
+	return nil
+}
+
+func (s *cloudwatchLogsStreamer) closed() bool {
+	select {
+	case <-s.ctx.Done():
+		return true
+	default:
+		return false
+	}
+}
+
+// fetchEvents fetches the events since the newest streamed event. A log group
+// that doesn't exist yet (e.g. because a Lambda function hasn't been invoked) has
+// no events.
+func (s *cloudwatchLogsStreamer) fetchEvents() error {
+	request := &logsClient.FilterLogEventsInput{
+		LogGroupName: awsSDK.String(s.group),
+		StartTime:    awsSDK.Int64(s.startTime),
+		Interleaved:  awsSDK.Bool(true),
+	}
+	if len(s.streams) > 0 {
+		request.LogStreamNames = awsSDK.StringSlice(s.streams)
+	}
+
+	var b strings.Builder
+	err := s.client.FilterLogEventsPagesWithContext(s.ctx, request, func(resp *logsClient.FilterLogEventsOutput, _ bool) bool {
+		for _, event := range resp.Events {
+			id := awsSDK.StringValue(event.EventId)
+			timestamp := awsSDK.Int64Value(event.Timestamp)
+			if _, ok := s.seen[id]; ok && timestamp == s.startTime {
+				continue
+			}
+			if timestamp > s.startTime {
+				s.startTime = timestamp
+				s.seen = make(map[string]struct{})
+			}
+			s.seen[id] = struct{}{}
+			b.WriteString(formatLogEvent(timestamp, awsSDK.StringValue(event.Message)))
+		}
+		return true
+	})
+	if err != nil {
+		if awserr, ok := err.(awserr.Error); ok && awserr.Code() == logsClient.ErrCodeResourceNotFoundException {
+			return nil
+		}
+		return err
+	}
+	if b.Len() > 0 {
+		activity.Debugf(s.ctx, "Fetched new events from the %v log group", s.group)
+		s.current = []byte(b.String())
+	}
+	return nil
+}
+
+// formatLogEvent formats a log event as "<time> <message>" on its own line.
+func formatLogEvent(timestamp int64, message string) string {
+	t := fromMillis(timestamp).UTC()
+	return fmt.Sprintf("%v %v\n", t.Format(time.RFC3339Nano), strings.TrimRight(message, "\n"))
+}
+
+func toMillis(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+func fromMillis(millis int64) time.Time {
+	return time.Unix(0, millis*int64(time.Millisecond))
+}
@@ -0,0 +1,56 @@
+package aws
+
+import (
+	"context"
+
+	awsSDK "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	dynamodbClient "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// dynamodbDir represents the resources/dynamodb directory. It lists the tables
+// in the profile's region.
+type dynamodbDir struct {
+	plugin.EntryBase
+	client *dynamodbClient.DynamoDB
+}
+
+func newDynamoDBDir(ctx context.Context, session *session.Session) *dynamodbDir {
+	dynamodbDir := &dynamodbDir{
+		EntryBase: plugin.NewEntry("dynamodb"),
+	}
+	dynamodbDir.client = dynamodbClient.New(session)
+	if _, err := plugin.List(ctx, dynamodbDir); err != nil {
+		dynamodbDir.MarkInaccessible(ctx, err)
+	}
+	return dynamodbDir
+}
+
+func (d *dynamodbDir) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(d, "dynamodb").IsSingleton()
+}
+
+func (d *dynamodbDir) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&dynamodbTable{}).Schema(),
+	}
+}
+
+// List lists the tables.
+func (d *dynamodbDir) List(ctx context.Context) ([]plugin.Entry, error) {
+	var tables []plugin.Entry
+	err := d.client.ListTablesPagesWithContext(ctx, &dynamodbClient.ListTablesInput{}, func(resp *dynamodbClient.ListTablesOutput, _ bool) bool {
+		for _, name := range resp.TableNames {
+			tables = append(tables, newDynamoDBTable(awsSDK.StringValue(name), d.client))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	activity.Record(ctx, "Listing %v DynamoDB tables", len(tables))
+	return tables, nil
+}
@@ -0,0 +1,240 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	awsSDK "github.com/aws/aws-sdk-go/aws"
+	dynamodbClient "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// The items command returns at most dynamodbDefaultItemsLimit items unless
+// --limit is passed. The limit can't exceed dynamodbMaxItemsLimit.
+const (
+	dynamodbDefaultItemsLimit = 100
+	dynamodbMaxItemsLimit     = 1000
+)
+
+// dynamodbTable represents a DynamoDB table
+type dynamodbTable struct {
+	plugin.EntryBase
+	name   string
+	client *dynamodbClient.DynamoDB
+}
+
+func newDynamoDBTable(name string, client *dynamodbClient.DynamoDB) *dynamodbTable {
+	table := &dynamodbTable{
+		EntryBase: plugin.NewEntry(name),
+	}
+	table.name = name
+	table.client = client
+	return table
+}
+
+func (t *dynamodbTable) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(t, "table").
+		SetDescription(dynamodbTableDescription).
+		SetMetadataSchema(dynamodbClient.TableDescription{})
+}
+
+func (t *dynamodbTable) describe(ctx context.Context) (*dynamodbClient.TableDescription, error) {
+	resp, err := t.client.DescribeTableWithContext(ctx, &dynamodbClient.DescribeTableInput{
+		TableName: awsSDK.String(t.name),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Table, nil
+}
+
+// Metadata returns the table's description, which includes its key schema,
+// status, capacity and approximate size.
+func (t *dynamodbTable) Metadata(ctx context.Context) (plugin.JSONObject, error) {
+	table, err := t.describe(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return plugin.ToJSONObject(table), nil
+}
+
+// Read returns the table's description.
+func (t *dynamodbTable) Read(ctx context.Context) ([]byte, error) {
+	table, err := t.describe(ctx)
+	if err != nil {
+		return nil, err
+	}
+	content, err := json.MarshalIndent(table, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(content, '\n'), nil
+}
+
+// Exec supports the items command, which returns the table's items as a JSON
+// array. See the table's description for its usage.
+func (t *dynamodbTable) Exec(ctx context.Context, cmd string, args []string, opts plugin.ExecOptions) (plugin.ExecCommand, error) {
+	if cmd != "items" {
+		return nil, fmt.Errorf("unsupported command %v, only items is supported", cmd)
+	}
+	limit, conditions, err := parseDynamoDBItemsArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	table, err := t.describe(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	execCmd := plugin.NewExecCommand(ctx)
+	go func() {
+		items, err := t.items(ctx, table, limit, conditions)
+		var output []byte
+		if err == nil {
+			output, err = json.MarshalIndent(items, "", "  ")
+		}
+		if err != nil {
+			execCmd.CloseStreamsWithError(err)
+			execCmd.SetExitCodeErr(err)
+			return
+		}
+		_, err = execCmd.Stdout().Write(append(output, '\n'))
+		execCmd.CloseStreamsWithError(err)
+		execCmd.SetExitCode(0)
+	}()
+	return execCmd, nil
+}
+
+// items returns at most limit items that match the conditions. It queries the
+// table if the conditions only constrain its key attributes (including its
+// partition key). Otherwise, it scans the table and filters the scanned items.
+func (t *dynamodbTable) items(
+	ctx context.Context,
+	table *dynamodbClient.TableDescription,
+	limit int,
+	conditions map[string]string,
+) ([]map[string]interface{}, error) {
+	keyTypes := make(map[string]string)
+	for _, key := range table.KeySchema {
+		keyTypes[awsSDK.StringValue(key.AttributeName)] = awsSDK.StringValue(key.KeyType)
+	}
+	attributeTypes := make(map[string]string)
+	for _, def := range table.AttributeDefinitions {
+		attributeTypes[awsSDK.StringValue(def.AttributeName)] = awsSDK.StringValue(def.AttributeType)
+	}
+
+	var expr []string
+	names := make(map[string]*string)
+	values := make(map[string]*dynamodbClient.AttributeValue)
+	hasPartitionKey, onlyKeys := false, true
+	for name, value := range conditions {
+		i := len(expr)
+		expr = append(expr, fmt.Sprintf("#k%v = :v%v", i, i))
+		names[fmt.Sprintf("#k%v", i)] = awsSDK.String(name)
+		attrValue := &dynamodbClient.AttributeValue{S: awsSDK.String(value)}
+		if attributeTypes[name] == dynamodbClient.ScalarAttributeTypeN {
+			attrValue = &dynamodbClient.AttributeValue{N: awsSDK.String(value)}
+		}
+		values[fmt.Sprintf(":v%v", i)] = attrValue
+
+		switch keyTypes[name] {
+		case dynamodbClient.KeyTypeHash:
+			hasPartitionKey = true
+		case "":
+			onlyKeys = false
+		}
+	}
+
+	var rawItems []map[string]*dynamodbClient.AttributeValue
+	collect := func(page []map[string]*dynamodbClient.AttributeValue) bool {
+		rawItems = append(rawItems, page...)
+		return len(rawItems) < limit
+	}
+	var err error
+	if hasPartitionKey && onlyKeys {
+		activity.Record(ctx, "Querying the %v DynamoDB table for %v", t.name, conditions)
+		err = t.client.QueryPagesWithContext(ctx, &dynamodbClient.QueryInput{
+			TableName:                 awsSDK.String(t.name),
+			KeyConditionExpression:    awsSDK.String(strings.Join(expr, " AND ")),
+			ExpressionAttributeNames:  names,
+			ExpressionAttributeValues: values,
+			Limit:                     awsSDK.Int64(int64(limit)),
+		}, func(resp *dynamodbClient.QueryOutput, _ bool) bool {
+			return collect(resp.Items)
+		})
+	} else {
+		activity.Record(ctx, "Scanning the %v DynamoDB table for %v", t.name, conditions)
+		request := &dynamodbClient.ScanInput{
+			TableName: awsSDK.String(t.name),
+			Limit:     awsSDK.Int64(int64(limit)),
+		}
+		if len(expr) > 0 {
+			request.FilterExpression = awsSDK.String(strings.Join(expr, " AND "))
+			request.ExpressionAttributeNames = names
+			request.ExpressionAttributeValues = values
+		}
+		err = t.client.ScanPagesWithContext(ctx, request, func(resp *dynamodbClient.ScanOutput, _ bool) bool {
+			return collect(resp.Items)
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(rawItems) > limit {
+		rawItems = rawItems[:limit]
+	}
+
+	items := []map[string]interface{}{}
+	if err := dynamodbattribute.UnmarshalListOfMaps(rawItems, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// parseDynamoDBItemsArgs parses the items command's arguments, which are an
+// optional '--limit <n>' followed by '<attribute>=<value>' conditions.
+func parseDynamoDBItemsArgs(args []string) (int, map[string]string, error) {
+	limit := dynamodbDefaultItemsLimit
+	conditions := make(map[string]string)
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--limit" {
+			if i+1 >= len(args) {
+				return 0, nil, fmt.Errorf("--limit requires a value")
+			}
+			i++
+			n, err := strconv.Atoi(args[i])
+			if err != nil || n <= 0 || n > dynamodbMaxItemsLimit {
+				return 0, nil, fmt.Errorf("--limit must be between 1 and %v, not %v", dynamodbMaxItemsLimit, args[i])
+			}
+			limit = n
+			continue
+		}
+		segments := strings.SplitN(args[i], "=", 2)
+		if len(segments) != 2 || segments[0] == "" {
+			return 0, nil, fmt.Errorf("invalid condition %v, conditions must be of the form <attribute>=<value>", args[i])
+		}
+		conditions[segments[0]] = segments[1]
+	}
+	return limit, conditions, nil
+}
+
+const dynamodbTableDescription = `
+This is a DynamoDB table. Reading it returns its description, which includes its
+key schema, status, provisioned capacity, item count and size. The description's
+also available as its metadata.
+
+Exec supports the items command, which prints the table's items as a JSON array,
+e.g.
+  wash exec dynamodb/my-table items --limit 10 id=42
+
+It returns at most 100 items unless --limit is passed (the maximum is 1000). The
+optional '<attribute>=<value>' conditions restrict the returned items. The table
+is queried if the conditions include its partition key and only constrain its
+key attributes. Otherwise, it's scanned, which can be slow for large tables.
+`
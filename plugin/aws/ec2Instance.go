@@ -68,7 +68,9 @@ func newEC2Instance(ctx context.Context, inst *ec2Client.Instance, session *sess
 		SetTTLOf(plugin.ListOp, 30*time.Second).
 		DisableCachingFor(plugin.MetadataOp).
 		SetAttributes(attributes).
-		SetPartialMetadata(metadata)
+		SetPartialMetadata(metadata).
+		Attributes().
+		SetCustom("region", awsSDK.StringValue(session.Config.Region))
 
 	return ec2Instance
 }
@@ -173,7 +175,9 @@ func (inst *ec2Instance) Schema() *plugin.EntrySchema {
 		AddSignal("stop", "Stops the EC2 instance").
 		AddSignal("hibernate", "Hibernates the EC2 instance").
 		AddSignal("restart", "Reboots the EC2 instance").
-		AddSignal("terminate", "Terminates the EC2 instance")
+		AddSignal("terminate", "Terminates the EC2 instance").
+		AddCustomAttribute("region", plugin.StringAttribute, "The region that the EC2 instance is in").
+		SetConsoleURL("https://console.aws.amazon.com/ec2/home?region={{.Attributes.region}}#InstanceDetails:instanceId={{.Metadata.InstanceId}}")
 }
 
 func (inst *ec2Instance) ChildSchemas() []*plugin.EntrySchema {
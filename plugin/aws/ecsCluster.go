@@ -0,0 +1,58 @@
+package aws
+
+import (
+	"context"
+
+	awsSDK "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	ecsClient "github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// ecsCluster represents an ECS cluster
+type ecsCluster struct {
+	plugin.EntryBase
+	name    string
+	session *session.Session
+	client  *ecsClient.ECS
+}
+
+func newECSCluster(cluster *ecsClient.Cluster, session *session.Session, client *ecsClient.ECS) *ecsCluster {
+	name := awsSDK.StringValue(cluster.ClusterName)
+	ecsCluster := &ecsCluster{
+		EntryBase: plugin.NewEntry(name),
+	}
+	ecsCluster.name = name
+	ecsCluster.session = session
+	ecsCluster.client = client
+	ecsCluster.
+		DisableDefaultCaching().
+		SetPartialMetadata(cluster)
+	return ecsCluster
+}
+
+func (c *ecsCluster) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(c, "cluster").
+		SetDescription(ecsClusterDescription).
+		SetPartialMetadataSchema(ecsClient.Cluster{})
+}
+
+func (c *ecsCluster) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&ecsServicesDir{}).Schema(),
+		(&ecsTasksDir{}).Schema(),
+	}
+}
+
+func (c *ecsCluster) List(ctx context.Context) ([]plugin.Entry, error) {
+	return []plugin.Entry{
+		newECSServicesDir(c),
+		newECSTasksDir(c),
+	}, nil
+}
+
+const ecsClusterDescription = `
+This is an ECS cluster. It contains the cluster's services and its running
+tasks. Exec'ing on a task runs the command in one of its containers via ECS Exec.
+`
@@ -0,0 +1,76 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	ecsClient "github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// ecsDescribeClustersBatchSize is the maximum number of clusters that
+// DescribeClusters accepts.
+const ecsDescribeClustersBatchSize = 100
+
+// ecsDir represents the resources/ecs directory. It lists the clusters in the
+// profile's region.
+type ecsDir struct {
+	plugin.EntryBase
+	session *session.Session
+	client  *ecsClient.ECS
+}
+
+func newECSDir(ctx context.Context, session *session.Session) *ecsDir {
+	ecsDir := &ecsDir{
+		EntryBase: plugin.NewEntry("ecs"),
+	}
+	ecsDir.session = session
+	ecsDir.client = ecsClient.New(session)
+	if _, err := plugin.List(ctx, ecsDir); err != nil {
+		ecsDir.MarkInaccessible(ctx, err)
+	}
+	return ecsDir
+}
+
+func (e *ecsDir) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(e, "ecs").IsSingleton()
+}
+
+func (e *ecsDir) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&ecsCluster{}).Schema(),
+	}
+}
+
+// List lists the clusters.
+func (e *ecsDir) List(ctx context.Context) ([]plugin.Entry, error) {
+	var arns []*string
+	err := e.client.ListClustersPagesWithContext(ctx, &ecsClient.ListClustersInput{}, func(resp *ecsClient.ListClustersOutput, _ bool) bool {
+		arns = append(arns, resp.ClusterArns...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var clusters []plugin.Entry
+	for start := 0; start < len(arns); start += ecsDescribeClustersBatchSize {
+		end := start + ecsDescribeClustersBatchSize
+		if end > len(arns) {
+			end = len(arns)
+		}
+		resp, err := e.client.DescribeClustersWithContext(ctx, &ecsClient.DescribeClustersInput{
+			Clusters: arns[start:end],
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, cluster := range resp.Clusters {
+			clusters = append(clusters, newECSCluster(cluster, e.session, e.client))
+		}
+	}
+
+	activity.Record(ctx, "Listing %v ECS clusters", len(clusters))
+	return clusters, nil
+}
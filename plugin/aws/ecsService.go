@@ -0,0 +1,62 @@
+package aws
+
+import (
+	"context"
+	"time"
+
+	awsSDK "github.com/aws/aws-sdk-go/aws"
+	ecsClient "github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// ecsService represents an ECS service
+type ecsService struct {
+	plugin.EntryBase
+	name    string
+	cluster *ecsCluster
+}
+
+func newECSService(service *ecsClient.Service, cluster *ecsCluster) *ecsService {
+	name := awsSDK.StringValue(service.ServiceName)
+	ecsService := &ecsService{
+		EntryBase: plugin.NewEntry(name),
+	}
+	ecsService.name = name
+	ecsService.cluster = cluster
+
+	crtime := awsSDK.TimeValue(service.CreatedAt)
+	ecsService.
+		SetTTLOf(plugin.ListOp, 30*time.Second).
+		SetPartialMetadata(service).
+		Attributes().
+		SetCrtime(crtime).
+		SetMtime(crtime).
+		SetCtime(crtime).
+		SetAtime(crtime)
+	return ecsService
+}
+
+func (s *ecsService) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(s, "service").
+		SetDescription(ecsServiceDescription).
+		SetPartialMetadataSchema(ecsClient.Service{})
+}
+
+func (s *ecsService) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&ecsTask{}).Schema(),
+	}
+}
+
+// List lists the service's running tasks.
+func (s *ecsService) List(ctx context.Context) ([]plugin.Entry, error) {
+	return listECSTasks(ctx, s.cluster, &ecsClient.ListTasksInput{
+		ServiceName: awsSDK.String(s.name),
+	})
+}
+
+const ecsServiceDescription = `
+This is an ECS service. Its metadata includes its deployments, desired and
+running task counts and recent events. It contains the service's running tasks.
+`
@@ -0,0 +1,72 @@
+package aws
+
+import (
+	"context"
+
+	awsSDK "github.com/aws/aws-sdk-go/aws"
+	ecsClient "github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// ecsDescribeServicesBatchSize is the maximum number of services that
+// DescribeServices accepts.
+const ecsDescribeServicesBatchSize = 10
+
+// ecsServicesDir represents the <cluster>/services directory
+type ecsServicesDir struct {
+	plugin.EntryBase
+	cluster *ecsCluster
+}
+
+func newECSServicesDir(cluster *ecsCluster) *ecsServicesDir {
+	servicesDir := &ecsServicesDir{
+		EntryBase: plugin.NewEntry("services"),
+	}
+	servicesDir.cluster = cluster
+	return servicesDir
+}
+
+func (d *ecsServicesDir) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(d, "services").IsSingleton()
+}
+
+func (d *ecsServicesDir) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&ecsService{}).Schema(),
+	}
+}
+
+// List lists the cluster's services.
+func (d *ecsServicesDir) List(ctx context.Context) ([]plugin.Entry, error) {
+	cluster := awsSDK.String(d.cluster.name)
+	var arns []*string
+	err := d.cluster.client.ListServicesPagesWithContext(ctx, &ecsClient.ListServicesInput{Cluster: cluster}, func(resp *ecsClient.ListServicesOutput, _ bool) bool {
+		arns = append(arns, resp.ServiceArns...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var services []plugin.Entry
+	for start := 0; start < len(arns); start += ecsDescribeServicesBatchSize {
+		end := start + ecsDescribeServicesBatchSize
+		if end > len(arns) {
+			end = len(arns)
+		}
+		resp, err := d.cluster.client.DescribeServicesWithContext(ctx, &ecsClient.DescribeServicesInput{
+			Cluster:  cluster,
+			Services: arns[start:end],
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, service := range resp.Services {
+			services = append(services, newECSService(service, d.cluster))
+		}
+	}
+
+	activity.Record(ctx, "Listing %v services in the %v ECS cluster", len(services), d.cluster.name)
+	return services, nil
+}
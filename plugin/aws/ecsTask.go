@@ -0,0 +1,206 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	awsSDK "github.com/aws/aws-sdk-go/aws"
+	ecsClient "github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/kballard/go-shellquote"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// ecsDescribeTasksBatchSize is the maximum number of tasks that DescribeTasks
+// accepts.
+const ecsDescribeTasksBatchSize = 100
+
+// sessionManagerPlugin is the AWS Session Manager plugin's executable. ECS Exec
+// sessions are started with it, the same way that the AWS CLI starts them.
+var sessionManagerPlugin = "session-manager-plugin"
+
+// ecsTask represents an ECS task
+type ecsTask struct {
+	plugin.EntryBase
+	id         string
+	arn        string
+	containers []*ecsClient.Container
+	cluster    *ecsCluster
+}
+
+func newECSTask(task *ecsClient.Task, cluster *ecsCluster) *ecsTask {
+	arn := awsSDK.StringValue(task.TaskArn)
+	id := arn[strings.LastIndex(arn, "/")+1:]
+	ecsTask := &ecsTask{
+		EntryBase: plugin.NewEntry(id),
+	}
+	ecsTask.id = id
+	ecsTask.arn = arn
+	ecsTask.containers = task.Containers
+	ecsTask.cluster = cluster
+
+	crtime := awsSDK.TimeValue(task.CreatedAt)
+	mtime := crtime
+	if task.StartedAt != nil {
+		mtime = awsSDK.TimeValue(task.StartedAt)
+	}
+	ecsTask.
+		SetTTLOf(plugin.ListOp, 30*time.Second).
+		DisableCachingFor(plugin.MetadataOp).
+		SetPartialMetadata(task).
+		Attributes().
+		SetCrtime(crtime).
+		SetMtime(mtime).
+		SetCtime(mtime).
+		SetAtime(mtime)
+	return ecsTask
+}
+
+// listECSTasks lists the cluster's running tasks that match the request.
+func listECSTasks(ctx context.Context, cluster *ecsCluster, request *ecsClient.ListTasksInput) ([]plugin.Entry, error) {
+	request.Cluster = awsSDK.String(cluster.name)
+	var arns []*string
+	err := cluster.client.ListTasksPagesWithContext(ctx, request, func(resp *ecsClient.ListTasksOutput, _ bool) bool {
+		arns = append(arns, resp.TaskArns...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []plugin.Entry
+	for start := 0; start < len(arns); start += ecsDescribeTasksBatchSize {
+		end := start + ecsDescribeTasksBatchSize
+		if end > len(arns) {
+			end = len(arns)
+		}
+		resp, err := cluster.client.DescribeTasksWithContext(ctx, &ecsClient.DescribeTasksInput{
+			Cluster: request.Cluster,
+			Tasks:   arns[start:end],
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, task := range resp.Tasks {
+			tasks = append(tasks, newECSTask(task, cluster))
+		}
+	}
+
+	activity.Record(ctx, "Listing %v tasks in the %v ECS cluster", len(tasks), cluster.name)
+	return tasks, nil
+}
+
+func (t *ecsTask) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(t, "task").
+		SetDescription(ecsTaskDescription).
+		SetPartialMetadataSchema(ecsClient.Task{}).
+		SetMetadataSchema(ecsClient.Task{})
+}
+
+// Metadata returns the task's latest description, including its tags.
+func (t *ecsTask) Metadata(ctx context.Context) (plugin.JSONObject, error) {
+	resp, err := t.cluster.client.DescribeTasksWithContext(ctx, &ecsClient.DescribeTasksInput{
+		Cluster: awsSDK.String(t.cluster.name),
+		Tasks:   []*string{awsSDK.String(t.arn)},
+		Include: []*string{awsSDK.String(ecsClient.TaskFieldTags)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Tasks) == 0 {
+		return nil, fmt.Errorf("the %v task no longer exists", t.id)
+	}
+	return plugin.ToJSONObject(resp.Tasks[0]), nil
+}
+
+// Exec runs the command in the task's first container that has ECS Exec
+// enabled. The session is started with the Session Manager plugin.
+func (t *ecsTask) Exec(ctx context.Context, cmd string, args []string, opts plugin.ExecOptions) (plugin.ExecCommand, error) {
+	container := t.execContainer()
+	if container == nil {
+		return nil, fmt.Errorf("the %v task has no containers that ECS Exec can run commands in", t.id)
+	}
+
+	activity.Record(ctx, "Starting an ECS Exec session in the %v container of the %v task", awsSDK.StringValue(container.Name), t.id)
+	resp, err := t.cluster.client.ExecuteCommandWithContext(ctx, &ecsClient.ExecuteCommandInput{
+		Cluster:     awsSDK.String(t.cluster.name),
+		Task:        awsSDK.String(t.arn),
+		Container:   container.Name,
+		Command:     awsSDK.String(shellquote.Join(append([]string{cmd}, args...)...)),
+		Interactive: awsSDK.Bool(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+	session, err := json.Marshal(resp.Session)
+	if err != nil {
+		return nil, err
+	}
+	target, err := json.Marshal(map[string]string{
+		"Target": fmt.Sprintf("ecs:%v_%v_%v", t.cluster.name, t.id, awsSDK.StringValue(container.RuntimeId)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pluginCmd := exec.CommandContext(
+		ctx,
+		sessionManagerPlugin,
+		string(session),
+		awsSDK.StringValue(t.cluster.session.Config.Region),
+		"StartSession",
+		"",
+		string(target),
+		t.cluster.client.Endpoint,
+	)
+	execCmd := plugin.NewExecCommand(ctx)
+	pluginCmd.Stdin = opts.Stdin
+	pluginCmd.Stdout = execCmd.Stdout()
+	pluginCmd.Stderr = execCmd.Stderr()
+	if err := pluginCmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start %v, is the Session Manager plugin installed? %w", sessionManagerPlugin, err)
+	}
+	go func() {
+		err := pluginCmd.Wait()
+		execCmd.CloseStreamsWithError(nil)
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			execCmd.SetExitCode(exitErr.ExitCode())
+		} else if err != nil {
+			execCmd.SetExitCodeErr(err)
+		} else {
+			execCmd.SetExitCode(0)
+		}
+	}()
+	return execCmd, nil
+}
+
+// execContainer returns the first container whose ECS Exec agent is running.
+func (t *ecsTask) execContainer() *ecsClient.Container {
+	for _, container := range t.containers {
+		for _, agent := range container.ManagedAgents {
+			if awsSDK.StringValue(agent.Name) == ecsClient.ManagedAgentNameExecuteCommandAgent &&
+				awsSDK.StringValue(agent.LastStatus) == "RUNNING" {
+				return container
+			}
+		}
+	}
+	return nil
+}
+
+const ecsTaskDescription = `
+This is an ECS task. Its metadata is the task's description, which includes its
+containers, network attachments and tags.
+
+Exec runs the command in the task's first container that has ECS Exec enabled,
+e.g.
+  wash exec aws/my-profile/resources/ecs/my-cluster/tasks/<task-id> ls /
+
+This requires the Session Manager plugin for the AWS CLI to be installed. The
+service must also have ECS Exec enabled ('--enable-execute-command'). Note that
+the exit code is the Session Manager plugin's, not the command's.
+`
@@ -0,0 +1,37 @@
+package aws
+
+import (
+	"context"
+
+	ecsClient "github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// ecsTasksDir represents the <cluster>/tasks directory
+type ecsTasksDir struct {
+	plugin.EntryBase
+	cluster *ecsCluster
+}
+
+func newECSTasksDir(cluster *ecsCluster) *ecsTasksDir {
+	tasksDir := &ecsTasksDir{
+		EntryBase: plugin.NewEntry("tasks"),
+	}
+	tasksDir.cluster = cluster
+	return tasksDir
+}
+
+func (d *ecsTasksDir) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(d, "tasks").IsSingleton()
+}
+
+func (d *ecsTasksDir) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&ecsTask{}).Schema(),
+	}
+}
+
+// List lists the cluster's running tasks.
+func (d *ecsTasksDir) List(ctx context.Context) ([]plugin.Entry, error) {
+	return listECSTasks(ctx, d.cluster, &ecsClient.ListTasksInput{})
+}
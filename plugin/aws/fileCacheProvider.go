@@ -14,6 +14,11 @@ import (
 	"github.com/puppetlabs/wash/activity"
 )
 
+// credentialsExpiryWindow is how long before their expiration cached credentials
+// are refreshed. This keeps requests that are signed just before the credentials
+// expire from failing.
+const credentialsExpiryWindow = 5 * time.Minute
+
 // FileCacheProvider is a credentials.Provider implementation that wraps an underlying Provider
 // (contained in credentials.Credentials) and provides caching support for credentials for the
 // specified profile.
@@ -65,7 +70,10 @@ func (f *FileCacheProvider) Retrieve() (credentials.Value, error) {
 		return f.cachedCredential.Credential, nil
 	}
 
-	// fetch the credentials from the underlying Provider
+	// fetch fresh credentials from the underlying Provider. They're expired first
+	// because the underlying Provider may still consider them valid if they're
+	// within the expiry window.
+	f.credentials.Expire()
 	credential, err := f.credentials.Get()
 	if err != nil {
 		return credential, err
@@ -89,13 +97,17 @@ func (f *FileCacheProvider) Retrieve() (credentials.Value, error) {
 	if err = writeCache(filename, f.cachedCredential); err != nil {
 		activity.Record(context.Background(), "Unable to update credential cache %s: %v", filename, err)
 	}
-	return credential, err
+	return credential, nil
 }
 
-// IsExpired implements the Provider interface, deferring to the cached credential first,
-// but fall back to the underlying Provider if it is expired.
+// IsExpired implements the Provider interface. It returns true if the cached credential
+// is expired or about to expire, in which case Retrieve refreshes it. Credentials that
+// don't expire aren't cached, so it defers to the underlying Provider for those.
 func (f *FileCacheProvider) IsExpired() bool {
-	return f.cachedCredential.IsExpired() && f.credentials.IsExpired()
+	if f.cachedCredential.Expiration.IsZero() {
+		return f.credentials.IsExpired()
+	}
+	return f.cachedCredential.IsExpired()
 }
 
 // ExpiresAt implements the Expirer interface, and gives access to the expiration time of the credential
@@ -126,9 +138,10 @@ type cachedCredential struct {
 	Expiration time.Time
 }
 
-// IsExpired determines if the cached credential has expired
+// IsExpired determines if the cached credential has expired or will expire within
+// the expiry window
 func (c *cachedCredential) IsExpired() bool {
-	return c.Expiration.Before(time.Now())
+	return c.Expiration.Add(-credentialsExpiryWindow).Before(time.Now())
 }
 
 // readCache reads the contents of the credential cache and returns the
@@ -0,0 +1,58 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	logsClient "github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	lambdaClient "github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// lambdaDir represents the resources/lambda directory. It lists the functions
+// in the profile's region.
+type lambdaDir struct {
+	plugin.EntryBase
+	client     *lambdaClient.Lambda
+	logsClient *logsClient.CloudWatchLogs
+}
+
+func newLambdaDir(ctx context.Context, session *session.Session) *lambdaDir {
+	lambdaDir := &lambdaDir{
+		EntryBase: plugin.NewEntry("lambda"),
+	}
+	lambdaDir.client = lambdaClient.New(session)
+	lambdaDir.logsClient = logsClient.New(session)
+	if _, err := plugin.List(ctx, lambdaDir); err != nil {
+		lambdaDir.MarkInaccessible(ctx, err)
+	}
+	return lambdaDir
+}
+
+func (l *lambdaDir) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(l, "lambda").IsSingleton()
+}
+
+func (l *lambdaDir) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&lambdaFunction{}).Schema(),
+	}
+}
+
+// List lists the functions.
+func (l *lambdaDir) List(ctx context.Context) ([]plugin.Entry, error) {
+	var functions []plugin.Entry
+	err := l.client.ListFunctionsPagesWithContext(ctx, &lambdaClient.ListFunctionsInput{}, func(resp *lambdaClient.ListFunctionsOutput, _ bool) bool {
+		for _, config := range resp.Functions {
+			functions = append(functions, newLambdaFunction(config, l.client, l.logsClient))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	activity.Record(ctx, "Listing %v Lambda functions", len(functions))
+	return functions, nil
+}
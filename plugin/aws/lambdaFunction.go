@@ -0,0 +1,126 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	awsSDK "github.com/aws/aws-sdk-go/aws"
+	logsClient "github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	lambdaClient "github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// lambdaLastModifiedLayout is the layout of a function's LastModified timestamp.
+const lambdaLastModifiedLayout = "2006-01-02T15:04:05.000-0700"
+
+// lambdaFunction represents a Lambda function
+type lambdaFunction struct {
+	plugin.EntryBase
+	name       string
+	client     *lambdaClient.Lambda
+	logsClient *logsClient.CloudWatchLogs
+}
+
+func newLambdaFunction(config *lambdaClient.FunctionConfiguration, client *lambdaClient.Lambda, logsClient *logsClient.CloudWatchLogs) *lambdaFunction {
+	name := awsSDK.StringValue(config.FunctionName)
+	fn := &lambdaFunction{
+		EntryBase: plugin.NewEntry(name),
+	}
+	fn.name = name
+	fn.client = client
+	fn.logsClient = logsClient
+
+	fn.SetPartialMetadata(config)
+	if mtime, err := time.Parse(lambdaLastModifiedLayout, awsSDK.StringValue(config.LastModified)); err == nil {
+		fn.
+			Attributes().
+			SetCrtime(mtime).
+			SetMtime(mtime).
+			SetCtime(mtime).
+			SetAtime(mtime)
+	}
+	return fn
+}
+
+func (fn *lambdaFunction) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(fn, "function").
+		SetDescription(lambdaFunctionDescription).
+		SetPartialMetadataSchema(lambdaClient.FunctionConfiguration{})
+}
+
+// Read returns the function's configuration and the location of its code.
+func (fn *lambdaFunction) Read(ctx context.Context) ([]byte, error) {
+	resp, err := fn.client.GetFunctionWithContext(ctx, &lambdaClient.GetFunctionInput{
+		FunctionName: awsSDK.String(fn.name),
+	})
+	if err != nil {
+		return nil, err
+	}
+	content, err := json.MarshalIndent(resp, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(content, '\n'), nil
+}
+
+// Stream follows the function's CloudWatch logs.
+func (fn *lambdaFunction) Stream(ctx context.Context) (io.ReadCloser, error) {
+	group := "/aws/lambda/" + fn.name
+	activity.Record(ctx, "Following the %v log group", group)
+	return newCloudwatchLogsStreamer(ctx, fn.logsClient, group, nil), nil
+}
+
+// Exec invokes the function. The command and its arguments are joined to form
+// the invocation's payload. A payload of "-" reads the payload from stdin.
+func (fn *lambdaFunction) Exec(ctx context.Context, cmd string, args []string, opts plugin.ExecOptions) (plugin.ExecCommand, error) {
+	payload := []byte(strings.Join(append([]string{cmd}, args...), " "))
+	if string(payload) == "-" && opts.Stdin != nil {
+		var err error
+		if payload, err = ioutil.ReadAll(opts.Stdin); err != nil {
+			return nil, err
+		}
+	}
+
+	execCmd := plugin.NewExecCommand(ctx)
+	go func() {
+		activity.Record(ctx, "Invoking Lambda function %v", fn.name)
+		resp, err := fn.client.InvokeWithContext(ctx, &lambdaClient.InvokeInput{
+			FunctionName: awsSDK.String(fn.name),
+			Payload:      payload,
+		})
+		if err != nil {
+			execCmd.CloseStreamsWithError(err)
+			execCmd.SetExitCodeErr(err)
+			return
+		}
+
+		// The response payload describes the error if the function failed.
+		output, exitCode := execCmd.Stdout(), 0
+		if functionErr := awsSDK.StringValue(resp.FunctionError); functionErr != "" {
+			activity.Record(ctx, "Lambda function %v failed: %v", fn.name, functionErr)
+			output, exitCode = execCmd.Stderr(), 1
+		}
+		_, err = output.Write(append(resp.Payload, '\n'))
+		execCmd.CloseStreamsWithError(err)
+		execCmd.SetExitCode(exitCode)
+	}()
+	return execCmd, nil
+}
+
+const lambdaFunctionDescription = `
+This is a Lambda function. Reading it returns its configuration and the location
+of its code. Streaming it (e.g. with 'tail -f') follows its CloudWatch logs.
+
+Exec invokes the function synchronously and prints its response. The command and
+its arguments are joined to form the invocation's JSON payload, e.g.
+  wash exec lambda/my-function '{"key": "value"}'
+
+Use '-' as the payload to read it from stdin. If the function fails, then its
+error is printed to stderr and the exit code is 1.
+`
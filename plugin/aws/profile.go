@@ -5,7 +5,10 @@ import (
 	"fmt"
 	"time"
 
+	awsSDK "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/ssocreds"
 	"github.com/aws/aws-sdk-go/aws/session"
 	stsClient "github.com/aws/aws-sdk-go/service/sts"
 	"github.com/puppetlabs/wash/activity"
@@ -15,11 +18,13 @@ import (
 // profile represents an AWS profile
 type profile struct {
 	plugin.EntryBase
-	session      *session.Session
-	resourcesDir []plugin.Entry
+	session  *session.Session
+	children []plugin.Entry
 }
 
-func newProfile(ctx context.Context, name string) (*profile, error) {
+// newProfile creates a session for the named profile. mfa is true if assuming
+// the profile's role requires an MFA token code.
+func newProfile(ctx context.Context, name string, mfa bool) (*profile, error) {
 	profile := &profile{
 		EntryBase: plugin.NewEntry(name),
 	}
@@ -27,20 +32,18 @@ func newProfile(ctx context.Context, name string) (*profile, error) {
 
 	activity.Record(ctx, "Creating a new AWS session for the %v profile", name)
 
-	// profile-specific stdin prompt
-	tokenProvider := func() (string, error) {
-		return plugin.Prompt(fmt.Sprintf("Enter MFA token code for profile %v", name))
-	}
+	mfaEntry := newProfileMFA(name)
 
 	// Create the session. SharedConfigEnable tells AWS to load the profile
 	// config from the ~/.aws/credentials and ~/.aws/config files
 	sess, err := session.NewSessionWithOptions(session.Options{
 		Profile:                 name,
-		AssumeRoleTokenProvider: tokenProvider,
+		AssumeRoleTokenProvider: mfaEntry.tokenCode,
 		// TODO: make this configurable. Different IAM configs may allow different durations.
 		// Use the minimum IAM limit of 1 hour.
 		AssumeRoleDuration: 1 * time.Hour,
 		SharedConfigState:  session.SharedConfigEnable,
+		Config:             awsSDK.Config{HTTPClient: plugin.HTTPClient()},
 	})
 	if err != nil {
 		return nil, err
@@ -52,13 +55,23 @@ func newProfile(ctx context.Context, name string) (*profile, error) {
 		activity.Record(ctx, "Unable to use cached credentials for %v profile: %v", name, err)
 	}
 
-	// Force retrieving credentials now to expose errors early.
-	if _, err := sess.Config.Credentials.Get(); err != nil {
+	// Force retrieving credentials now to expose errors early. We skip this if
+	// we'd have to wait for an MFA token code to be written to the mfa entry
+	// since that entry isn't listed until newProfile returns.
+	if mfa && !plugin.IsInteractive() {
+		activity.Record(ctx, "Deferring retrieving credentials for the %v profile until they're needed", name)
+	} else if _, err := sess.Config.Credentials.Get(); err != nil {
+		if awserr, ok := err.(awserr.Error); ok && awserr.Code() == ssocreds.ErrCodeSSOProviderInvalidToken {
+			return nil, fmt.Errorf("Unable to get credentials for %v: %v. Run 'aws sso login --profile %v' to start a new SSO session", name, err, name)
+		}
 		return nil, fmt.Errorf("Unable to get credentials for %v: %v", name, err)
 	}
 
 	profile.session = sess
-	profile.resourcesDir = []plugin.Entry{newResourcesDir(sess)}
+	profile.children = []plugin.Entry{newResourcesDir(sess)}
+	if mfa {
+		profile.children = append(profile.children, mfaEntry)
+	}
 
 	return profile, nil
 }
@@ -72,12 +85,14 @@ func (p *profile) Schema() *plugin.EntrySchema {
 func (p *profile) ChildSchemas() []*plugin.EntrySchema {
 	return []*plugin.EntrySchema{
 		(&resourcesDir{}).Schema(),
+		(&profileMFA{}).Schema(),
 	}
 }
 
-// List lists the resources directory
+// List lists the resources directory and, if the profile requires MFA, the mfa
+// entry
 func (p *profile) List(ctx context.Context) ([]plugin.Entry, error) {
-	return p.resourcesDir, nil
+	return p.children, nil
 }
 
 type profileMetadata struct {
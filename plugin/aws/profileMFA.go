@@ -0,0 +1,87 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// mfaTokenCodeTimeout is how long a non-interactive Wash waits for an MFA token
+// code to be written to a profile's mfa entry.
+var mfaTokenCodeTimeout = 5 * time.Minute
+
+// profileMFA supplies the MFA token codes that are needed to assume a profile's
+// role. If Wash is running interactively, then the codes are prompted for on
+// stdin. Otherwise, they're written to this entry.
+type profileMFA struct {
+	plugin.EntryBase
+	profile string
+	codes   chan string
+}
+
+func newProfileMFA(profile string) *profileMFA {
+	mfa := &profileMFA{
+		EntryBase: plugin.NewEntry("mfa"),
+	}
+	mfa.profile = profile
+	mfa.codes = make(chan string)
+	mfa.DisableDefaultCaching()
+	return mfa
+}
+
+func (mfa *profileMFA) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(mfa, "mfa").
+		SetDescription(profileMFADescription).
+		IsSingleton()
+}
+
+// Write supplies the MFA token code to the pending assume-role call.
+func (mfa *profileMFA) Write(ctx context.Context, p []byte) error {
+	code := strings.TrimSpace(string(p))
+	if len(code) == 0 {
+		return fmt.Errorf("the MFA token code cannot be empty")
+	}
+	select {
+	case mfa.codes <- code:
+		activity.Record(ctx, "Received an MFA token code for the %v profile", mfa.profile)
+		return nil
+	default:
+		return fmt.Errorf("the %v profile isn't waiting for an MFA token code", mfa.profile)
+	}
+}
+
+// tokenCode returns an MFA token code for the profile. It's the session's
+// AssumeRoleTokenProvider.
+func (mfa *profileMFA) tokenCode() (string, error) {
+	if plugin.IsInteractive() {
+		return plugin.Prompt(fmt.Sprintf("Enter MFA token code for profile %v", mfa.profile))
+	}
+
+	activity.Warnf(
+		context.Background(),
+		"The %v profile needs an MFA token code. Write it to its mfa entry within %v, e.g. echo <code> > aws/%v/mfa",
+		mfa.profile,
+		mfaTokenCodeTimeout,
+		mfa.profile,
+	)
+	select {
+	case code := <-mfa.codes:
+		return code, nil
+	case <-time.After(mfaTokenCodeTimeout):
+		return "", fmt.Errorf("timed out waiting for an MFA token code for the %v profile", mfa.profile)
+	}
+}
+
+const profileMFADescription = `
+This entry supplies MFA token codes to the profile when Wash isn't running
+interactively. When the profile's credentials need to be refreshed, Wash logs a
+warning and waits for the token code to be written here, e.g.
+  echo 123456 > aws/my-profile/mfa
+
+Writes fail if the profile isn't waiting for a token code.
+`
@@ -0,0 +1,57 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	awsSDK "github.com/aws/aws-sdk-go/aws"
+	rdsClient "github.com/aws/aws-sdk-go/service/rds"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// rdsCluster represents an RDS DB cluster
+type rdsCluster struct {
+	plugin.EntryBase
+	cluster *rdsClient.DBCluster
+}
+
+func newRDSCluster(cluster *rdsClient.DBCluster) *rdsCluster {
+	rdsCluster := &rdsCluster{
+		EntryBase: plugin.NewEntry(awsSDK.StringValue(cluster.DBClusterIdentifier)),
+	}
+	rdsCluster.cluster = cluster
+
+	crtime := awsSDK.TimeValue(cluster.ClusterCreateTime)
+	rdsCluster.
+		SetTTLOf(plugin.ListOp, 30*time.Second).
+		SetPartialMetadata(cluster).
+		Attributes().
+		SetCrtime(crtime).
+		SetMtime(crtime).
+		SetCtime(crtime).
+		SetAtime(crtime)
+	return rdsCluster
+}
+
+func (c *rdsCluster) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(c, "cluster").
+		SetDescription(rdsClusterDescription).
+		SetPartialMetadataSchema(rdsClient.DBCluster{})
+}
+
+// Read returns the cluster's description.
+func (c *rdsCluster) Read(ctx context.Context) ([]byte, error) {
+	content, err := json.MarshalIndent(c.cluster, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(content, '\n'), nil
+}
+
+const rdsClusterDescription = `
+This is an RDS DB cluster (e.g. an Aurora cluster). Reading it returns its
+description, which includes its writer and reader endpoints, status, engine and
+members. The description's also available as its metadata.
+`
@@ -0,0 +1,53 @@
+package aws
+
+import (
+	"context"
+
+	rdsClient "github.com/aws/aws-sdk-go/service/rds"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// rdsClustersDir represents the rds/clusters directory
+type rdsClustersDir struct {
+	plugin.EntryBase
+	client *rdsClient.RDS
+}
+
+func newRDSClustersDir(ctx context.Context, client *rdsClient.RDS) *rdsClustersDir {
+	clustersDir := &rdsClustersDir{
+		EntryBase: plugin.NewEntry("clusters"),
+	}
+	clustersDir.client = client
+	if _, err := plugin.List(ctx, clustersDir); err != nil {
+		clustersDir.MarkInaccessible(ctx, err)
+	}
+	return clustersDir
+}
+
+func (d *rdsClustersDir) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(d, "clusters").IsSingleton()
+}
+
+func (d *rdsClustersDir) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&rdsCluster{}).Schema(),
+	}
+}
+
+// List lists the DB clusters.
+func (d *rdsClustersDir) List(ctx context.Context) ([]plugin.Entry, error) {
+	var clusters []plugin.Entry
+	err := d.client.DescribeDBClustersPagesWithContext(ctx, &rdsClient.DescribeDBClustersInput{}, func(resp *rdsClient.DescribeDBClustersOutput, _ bool) bool {
+		for _, cluster := range resp.DBClusters {
+			clusters = append(clusters, newRDSCluster(cluster))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	activity.Record(ctx, "Listing %v RDS clusters", len(clusters))
+	return clusters, nil
+}
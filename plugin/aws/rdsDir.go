@@ -0,0 +1,42 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	rdsClient "github.com/aws/aws-sdk-go/service/rds"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// rdsDir represents the resources/rds directory
+type rdsDir struct {
+	plugin.EntryBase
+	client *rdsClient.RDS
+}
+
+func newRDSDir(session *session.Session) *rdsDir {
+	rdsDir := &rdsDir{
+		EntryBase: plugin.NewEntry("rds"),
+	}
+	rdsDir.DisableDefaultCaching()
+	rdsDir.client = rdsClient.New(session)
+	return rdsDir
+}
+
+func (r *rdsDir) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(r, "rds").IsSingleton()
+}
+
+func (r *rdsDir) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&rdsInstancesDir{}).Schema(),
+		(&rdsClustersDir{}).Schema(),
+	}
+}
+
+func (r *rdsDir) List(ctx context.Context) ([]plugin.Entry, error) {
+	return []plugin.Entry{
+		newRDSInstancesDir(ctx, r.client),
+		newRDSClustersDir(ctx, r.client),
+	}, nil
+}
@@ -0,0 +1,57 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	awsSDK "github.com/aws/aws-sdk-go/aws"
+	rdsClient "github.com/aws/aws-sdk-go/service/rds"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// rdsInstance represents an RDS DB instance
+type rdsInstance struct {
+	plugin.EntryBase
+	instance *rdsClient.DBInstance
+}
+
+func newRDSInstance(instance *rdsClient.DBInstance) *rdsInstance {
+	rdsInstance := &rdsInstance{
+		EntryBase: plugin.NewEntry(awsSDK.StringValue(instance.DBInstanceIdentifier)),
+	}
+	rdsInstance.instance = instance
+
+	crtime := awsSDK.TimeValue(instance.InstanceCreateTime)
+	rdsInstance.
+		SetTTLOf(plugin.ListOp, 30*time.Second).
+		SetPartialMetadata(instance).
+		Attributes().
+		SetCrtime(crtime).
+		SetMtime(crtime).
+		SetCtime(crtime).
+		SetAtime(crtime)
+	return rdsInstance
+}
+
+func (i *rdsInstance) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(i, "instance").
+		SetDescription(rdsInstanceDescription).
+		SetPartialMetadataSchema(rdsClient.DBInstance{})
+}
+
+// Read returns the instance's description.
+func (i *rdsInstance) Read(ctx context.Context) ([]byte, error) {
+	content, err := json.MarshalIndent(i.instance, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(content, '\n'), nil
+}
+
+const rdsInstanceDescription = `
+This is an RDS DB instance. Reading it returns its description, which includes
+its endpoint, status, engine, instance class and storage. The description's
+also available as its metadata.
+`
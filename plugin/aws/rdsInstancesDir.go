@@ -0,0 +1,53 @@
+package aws
+
+import (
+	"context"
+
+	rdsClient "github.com/aws/aws-sdk-go/service/rds"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// rdsInstancesDir represents the rds/instances directory
+type rdsInstancesDir struct {
+	plugin.EntryBase
+	client *rdsClient.RDS
+}
+
+func newRDSInstancesDir(ctx context.Context, client *rdsClient.RDS) *rdsInstancesDir {
+	instancesDir := &rdsInstancesDir{
+		EntryBase: plugin.NewEntry("instances"),
+	}
+	instancesDir.client = client
+	if _, err := plugin.List(ctx, instancesDir); err != nil {
+		instancesDir.MarkInaccessible(ctx, err)
+	}
+	return instancesDir
+}
+
+func (d *rdsInstancesDir) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(d, "instances").IsSingleton()
+}
+
+func (d *rdsInstancesDir) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&rdsInstance{}).Schema(),
+	}
+}
+
+// List lists the DB instances.
+func (d *rdsInstancesDir) List(ctx context.Context) ([]plugin.Entry, error) {
+	var instances []plugin.Entry
+	err := d.client.DescribeDBInstancesPagesWithContext(ctx, &rdsClient.DescribeDBInstancesInput{}, func(resp *rdsClient.DescribeDBInstancesOutput, _ bool) bool {
+		for _, instance := range resp.DBInstances {
+			instances = append(instances, newRDSInstance(instance))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	activity.Record(ctx, "Listing %v RDS instances", len(instances))
+	return instances, nil
+}
@@ -30,6 +30,16 @@ func (r *resourcesDir) ChildSchemas() []*plugin.EntrySchema {
 	return []*plugin.EntrySchema{
 		(&s3Dir{}).Schema(),
 		(&ec2Dir{}).Schema(),
+		(&lambdaDir{}).Schema(),
+		(&cloudwatchDir{}).Schema(),
+		(&ecsDir{}).Schema(),
+		(&route53Dir{}).Schema(),
+		(&rdsDir{}).Schema(),
+		(&dynamodbDir{}).Schema(),
+		(&sqsDir{}).Schema(),
+		(&snsDir{}).Schema(),
+		(&apigatewayDir{}).Schema(),
+		(&cloudfrontDir{}).Schema(),
 	}
 }
 
@@ -38,5 +48,15 @@ func (r *resourcesDir) List(ctx context.Context) ([]plugin.Entry, error) {
 	return []plugin.Entry{
 		newS3Dir(ctx, r.session),
 		newEC2Dir(r.session),
+		newLambdaDir(ctx, r.session),
+		newCloudwatchDir(r.session),
+		newECSDir(ctx, r.session),
+		newRoute53Dir(ctx, r.session),
+		newRDSDir(r.session),
+		newDynamoDBDir(ctx, r.session),
+		newSQSDir(ctx, r.session),
+		newSNSDir(ctx, r.session),
+		newAPIGatewayDir(ctx, r.session),
+		newCloudFrontDir(ctx, r.session),
 	}, nil
 }
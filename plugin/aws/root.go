@@ -12,6 +12,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/dustin/go-humanize"
 	"github.com/puppetlabs/wash/activity"
 	"github.com/puppetlabs/wash/plugin"
 	"gopkg.in/go-ini/ini.v1"
@@ -81,11 +83,49 @@ func (r *Root) Init(cfg map[string]interface{}) error {
 		}
 	}
 
+	if thresholdI, ok := cfg["s3_multipart_threshold"]; ok {
+		threshold, err := parseS3MultipartThreshold(thresholdI)
+		if err != nil {
+			return err
+		}
+		s3MultipartThreshold = threshold
+	}
+
+	if deleteI, ok := cfg["sqs_delete_streamed_messages"]; ok {
+		deleteMessages, ok := deleteI.(bool)
+		if !ok {
+			return fmt.Errorf("aws.sqs_delete_streamed_messages config must be a boolean, not %v", deleteI)
+		}
+		sqsDeleteStreamedMessages = deleteMessages
+	}
+
 	// Force authorizing profiles on startup
 	_, err := r.List(context.Background())
 	return err
 }
 
+// parseS3MultipartThreshold parses the aws.s3_multipart_threshold config, which
+// is either a number of bytes or a human-readable size like "64MB".
+func parseS3MultipartThreshold(thresholdI interface{}) (int64, error) {
+	var threshold int64
+	switch t := thresholdI.(type) {
+	case int:
+		threshold = int64(t)
+	case string:
+		bytes, err := humanize.ParseBytes(t)
+		if err != nil {
+			return 0, fmt.Errorf("aws.s3_multipart_threshold config is not a valid size: %v", err)
+		}
+		threshold = int64(bytes)
+	default:
+		return 0, fmt.Errorf("aws.s3_multipart_threshold config must be a size, not %v", thresholdI)
+	}
+	if threshold < s3manager.MinUploadPartSize {
+		return 0, fmt.Errorf("aws.s3_multipart_threshold config must be at least %v bytes, not %v", s3manager.MinUploadPartSize, threshold)
+	}
+	return threshold, nil
+}
+
 // ChildSchemas returns the root's child schema
 func (r *Root) ChildSchemas() []*plugin.EntrySchema {
 	return []*plugin.EntrySchema{
@@ -138,6 +178,7 @@ func (r *Root) List(ctx context.Context) ([]plugin.Entry, error) {
 	activity.Record(ctx, "Loading profiles from %v", loadedFiles)
 
 	names := make(map[string]struct{})
+	configSections := make(map[string]*ini.Section)
 
 	if awsCredentialsExists {
 		cred, err := ini.Load(awsCredentials)
@@ -154,10 +195,17 @@ func (r *Root) List(ctx context.Context) ([]plugin.Entry, error) {
 			return nil, fmt.Errorf("failed to read %v: %v", awsConfig, err)
 		}
 		for _, section := range config.Sections() {
+			// sso-session and services sections configure the profiles that reference
+			// them, so they aren't profiles themselves.
+			if strings.HasPrefix(section.Name(), "sso-session ") || strings.HasPrefix(section.Name(), "services ") {
+				continue
+			}
 			// https://docs.aws.amazon.com/cli/latest/userguide/cli-configure-profiles.html
 			// Named profiles in config begin with 'profile '. Trim that so config and credentials
 			// entries match up.
-			names[strings.TrimPrefix(section.Name(), "profile ")] = struct{}{}
+			name := strings.TrimPrefix(section.Name(), "profile ")
+			names[name] = struct{}{}
+			configSections[name] = section
 		}
 	}
 
@@ -171,7 +219,7 @@ func (r *Root) List(ctx context.Context) ([]plugin.Entry, error) {
 			continue
 		}
 
-		profile, err := newProfile(ctx, name)
+		profile, err := newProfile(ctx, name, requiresMFA(configSections, name))
 		if err != nil {
 			activity.Warnf(ctx, err.Error())
 			continue
@@ -183,6 +231,31 @@ func (r *Root) List(ctx context.Context) ([]plugin.Entry, error) {
 	return profiles, nil
 }
 
+// requiresMFA returns true if assuming the profile's role requires an MFA token
+// code. This is the case if the profile or any of the profiles in its role chain
+// (its source_profile, that profile's source_profile, etc.) set mfa_serial.
+func requiresMFA(configSections map[string]*ini.Section, name string) bool {
+	visited := make(map[string]struct{})
+	for {
+		section, ok := configSections[name]
+		if !ok {
+			return false
+		}
+		if _, ok := visited[name]; ok {
+			// The role chain has a cycle, which the SDK will report
+			return false
+		}
+		visited[name] = struct{}{}
+		if section.HasKey("mfa_serial") {
+			return true
+		}
+		if !section.HasKey("source_profile") {
+			return false
+		}
+		name = section.Key("source_profile").String()
+	}
+}
+
 const rootDescription = `
 This is the AWS plugin root. The AWS plugin reads the AWS_SHARED_CREDENTIALS_FILE
 environment variable or $HOME/.aws/credentials and AWS_CONFIG_FILE environment
@@ -197,12 +270,28 @@ aws:
 
 to Wash’s config file.
 
-The AWS plugin currently supports EC2 and S3. IAM roles are supported when configured
-as described here. Note that currently region will also need to be specified with the
-profile.
+Writes to S3 objects that are at least 16MB are uploaded via a multipart upload
+in 16MB parts. You can change the threshold (minimum 5MB) by adding
+
+aws:
+  s3_multipart_threshold: 64MB
+
+to Wash’s config file.
 
-If using MFA, Wash will prompt for it on standard input. Credentials are valid for 1 hour.
-They are cached under wash/aws-credentials in your user cache directory so they can be
-re-used across server restarts. Wash may have to re-prompt for a new MFA token in response
-to navigating the Wash environment to authorize a new session.
+The AWS plugin currently supports EC2, S3, Lambda, CloudWatch Logs, ECS,
+Route53, RDS, DynamoDB, SQS and SNS. IAM roles (including role chains via
+source_profile) and SSO profiles (including ones that reference an sso-session
+section) are supported when configured as described here. Note that currently
+region will also need to be specified with the profile. SSO profiles need a
+valid SSO login, so run 'aws sso login' first.
+
+If using MFA, Wash will prompt for it on standard input. If Wash isn't running
+interactively, then it logs a warning instead and waits for the token code to be
+written to the profile's mfa entry, e.g.
+  echo 123456 > aws/my-profile/mfa
+
+Credentials are valid for 1 hour. They are cached under wash/aws-credentials in
+your user cache directory so they can be re-used across server restarts, and are
+refreshed shortly before they expire. Wash may have to re-prompt for a new MFA
+token in response to navigating the Wash environment to authorize a new session.
 `
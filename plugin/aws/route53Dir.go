@@ -0,0 +1,55 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	route53Client "github.com/aws/aws-sdk-go/service/route53"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// route53Dir represents the resources/route53 directory. It lists the
+// profile's hosted zones.
+type route53Dir struct {
+	plugin.EntryBase
+	client *route53Client.Route53
+}
+
+func newRoute53Dir(ctx context.Context, session *session.Session) *route53Dir {
+	route53Dir := &route53Dir{
+		EntryBase: plugin.NewEntry("route53"),
+	}
+	route53Dir.client = route53Client.New(session)
+	if _, err := plugin.List(ctx, route53Dir); err != nil {
+		route53Dir.MarkInaccessible(ctx, err)
+	}
+	return route53Dir
+}
+
+func (r *route53Dir) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(r, "route53").IsSingleton()
+}
+
+func (r *route53Dir) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&route53Zone{}).Schema(),
+	}
+}
+
+// List lists the hosted zones.
+func (r *route53Dir) List(ctx context.Context) ([]plugin.Entry, error) {
+	var zones []plugin.Entry
+	err := r.client.ListHostedZonesPagesWithContext(ctx, &route53Client.ListHostedZonesInput{}, func(resp *route53Client.ListHostedZonesOutput, _ bool) bool {
+		for _, zone := range resp.HostedZones {
+			zones = append(zones, newRoute53Zone(zone, r.client))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	activity.Record(ctx, "Listing %v Route53 hosted zones", len(zones))
+	return zones, nil
+}
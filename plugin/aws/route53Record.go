@@ -0,0 +1,170 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	awsSDK "github.com/aws/aws-sdk-go/aws"
+	route53Client "github.com/aws/aws-sdk-go/service/route53"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// route53Changes tracks the most recent change to each record set that was
+// submitted by Wash, keyed by the record set's route53Record#key. Records are
+// recreated whenever their zone is listed, so the changes are tracked here
+// instead of on the record.
+var route53Changes = struct {
+	sync.Mutex
+	m map[string]*route53Client.ChangeInfo
+}{m: make(map[string]*route53Client.ChangeInfo)}
+
+// route53Record represents a Route53 record set
+type route53Record struct {
+	plugin.EntryBase
+	zoneID    string
+	recordSet *route53Client.ResourceRecordSet
+	client    *route53Client.Route53
+}
+
+// route53RecordMetadata is a record set's metadata. LastChange is the most
+// recent change that Wash submitted for the record set.
+type route53RecordMetadata struct {
+	*route53Client.ResourceRecordSet
+	LastChange *route53Client.ChangeInfo `json:",omitempty"`
+}
+
+func newRoute53Record(recordSet *route53Client.ResourceRecordSet, zoneID string, client *route53Client.Route53) *route53Record {
+	// Record sets with a routing policy (e.g. weighted records) share their name
+	// and type, so they're distinguished by their set identifier.
+	name := route53DisplayName(awsSDK.StringValue(recordSet.Name)) + "_" + awsSDK.StringValue(recordSet.Type)
+	if recordSet.SetIdentifier != nil {
+		name += "_" + awsSDK.StringValue(recordSet.SetIdentifier)
+	}
+	record := &route53Record{
+		EntryBase: plugin.NewEntry(name),
+	}
+	record.zoneID = zoneID
+	record.recordSet = recordSet
+	record.client = client
+	record.
+		DisableCachingFor(plugin.MetadataOp).
+		SetPartialMetadata(recordSet)
+	return record
+}
+
+func (r *route53Record) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(r, "record").
+		SetDescription(route53RecordDescription).
+		SetPartialMetadataSchema(route53Client.ResourceRecordSet{}).
+		SetMetadataSchema(route53RecordMetadata{})
+}
+
+func (r *route53Record) key() string {
+	return strings.Join([]string{
+		r.zoneID,
+		awsSDK.StringValue(r.recordSet.Name),
+		awsSDK.StringValue(r.recordSet.Type),
+		awsSDK.StringValue(r.recordSet.SetIdentifier),
+	}, "/")
+}
+
+// Metadata returns the record set along with the latest status of the most
+// recent change that Wash submitted for it.
+func (r *route53Record) Metadata(ctx context.Context) (plugin.JSONObject, error) {
+	route53Changes.Lock()
+	change := route53Changes.m[r.key()]
+	route53Changes.Unlock()
+
+	if change != nil && awsSDK.StringValue(change.Status) != route53Client.ChangeStatusInsync {
+		resp, err := r.client.GetChangeWithContext(ctx, &route53Client.GetChangeInput{Id: change.Id})
+		if err != nil {
+			return nil, err
+		}
+		change = resp.ChangeInfo
+		route53Changes.Lock()
+		route53Changes.m[r.key()] = change
+		route53Changes.Unlock()
+	}
+	return plugin.ToJSONObject(route53RecordMetadata{
+		ResourceRecordSet: r.recordSet,
+		LastChange:        change,
+	}), nil
+}
+
+// Read returns the record set as JSON. Writing the (edited) JSON back updates
+// the record set.
+func (r *route53Record) Read(ctx context.Context) ([]byte, error) {
+	content, err := json.MarshalIndent(r.recordSet, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(content, '\n'), nil
+}
+
+// Write upserts the record set described by the given JSON. The record set's
+// name, type and set identifier can't be changed because they identify it. They
+// default to the record's if they're omitted.
+func (r *route53Record) Write(ctx context.Context, p []byte) error {
+	var recordSet route53Client.ResourceRecordSet
+	if err := json.Unmarshal(p, &recordSet); err != nil {
+		return fmt.Errorf("the record set must be JSON: %w", err)
+	}
+	if recordSet.Name == nil {
+		recordSet.Name = r.recordSet.Name
+	}
+	if recordSet.Type == nil {
+		recordSet.Type = r.recordSet.Type
+	}
+	if recordSet.SetIdentifier == nil {
+		recordSet.SetIdentifier = r.recordSet.SetIdentifier
+	}
+	if !strings.EqualFold(route53DisplayName(awsSDK.StringValue(recordSet.Name)), route53DisplayName(awsSDK.StringValue(r.recordSet.Name))) ||
+		awsSDK.StringValue(recordSet.Type) != awsSDK.StringValue(r.recordSet.Type) ||
+		awsSDK.StringValue(recordSet.SetIdentifier) != awsSDK.StringValue(r.recordSet.SetIdentifier) {
+		return fmt.Errorf("the record set's name, type and set identifier can't be changed")
+	}
+
+	resp, err := r.client.ChangeResourceRecordSetsWithContext(ctx, &route53Client.ChangeResourceRecordSetsInput{
+		HostedZoneId: awsSDK.String(r.zoneID),
+		ChangeBatch: &route53Client.ChangeBatch{
+			Comment: awsSDK.String("Updated by Wash"),
+			Changes: []*route53Client.Change{
+				{
+					Action:            awsSDK.String(route53Client.ChangeActionUpsert),
+					ResourceRecordSet: &recordSet,
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	activity.Record(ctx, "Upserted the %v record set in the %v hosted zone: %v", r.Name(), r.zoneID, resp.ChangeInfo)
+	route53Changes.Lock()
+	route53Changes.m[r.key()] = resp.ChangeInfo
+	route53Changes.Unlock()
+	r.recordSet = &recordSet
+	r.SetPartialMetadata(&recordSet)
+	return nil
+}
+
+const route53RecordDescription = `
+This is a Route53 record set. Reading it returns the record set as JSON, e.g.
+  {
+    "Name": "www.example.com.",
+    "ResourceRecords": [{"Value": "192.0.2.1"}],
+    "TTL": 300,
+    "Type": "A"
+  }
+
+Writing edited JSON back to the record set updates it via an UPSERT change. Its
+name, type and set identifier can't be changed. The record set's metadata
+includes the status of the last change that Wash submitted for it in
+LastChange; the change is done when its status is INSYNC.
+`
@@ -0,0 +1,74 @@
+package aws
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	awsSDK "github.com/aws/aws-sdk-go/aws"
+	route53Client "github.com/aws/aws-sdk-go/service/route53"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// route53Zone represents a Route53 hosted zone
+type route53Zone struct {
+	plugin.EntryBase
+	id     string
+	client *route53Client.Route53
+}
+
+func newRoute53Zone(zone *route53Client.HostedZone, client *route53Client.Route53) *route53Zone {
+	route53Zone := &route53Zone{
+		EntryBase: plugin.NewEntry(route53DisplayName(awsSDK.StringValue(zone.Name))),
+	}
+	route53Zone.id = awsSDK.StringValue(zone.Id)
+	route53Zone.client = client
+	route53Zone.
+		SetTTLOf(plugin.ListOp, 30*time.Second).
+		SetPartialMetadata(zone)
+	return route53Zone
+}
+
+func (z *route53Zone) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(z, "zone").
+		SetDescription(route53ZoneDescription).
+		SetPartialMetadataSchema(route53Client.HostedZone{})
+}
+
+func (z *route53Zone) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&route53Record{}).Schema(),
+	}
+}
+
+// List lists the zone's record sets.
+func (z *route53Zone) List(ctx context.Context) ([]plugin.Entry, error) {
+	request := &route53Client.ListResourceRecordSetsInput{
+		HostedZoneId: awsSDK.String(z.id),
+	}
+	var records []plugin.Entry
+	err := z.client.ListResourceRecordSetsPagesWithContext(ctx, request, func(resp *route53Client.ListResourceRecordSetsOutput, _ bool) bool {
+		for _, recordSet := range resp.ResourceRecordSets {
+			records = append(records, newRoute53Record(recordSet, z.id, z.client))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	activity.Record(ctx, "Listing %v record sets in the %v hosted zone", len(records), z.id)
+	return records, nil
+}
+
+// route53DisplayName returns a DNS name without its trailing dot and with an
+// escaped wildcard (\052) replaced by '*'.
+func route53DisplayName(name string) string {
+	return strings.Replace(strings.TrimSuffix(name, "."), `\052`, "*", -1)
+}
+
+const route53ZoneDescription = `
+This is a Route53 hosted zone. It contains the zone's record sets.
+`
@@ -3,11 +3,13 @@ package aws
 import (
 	"context"
 	"fmt"
+	"os"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/archive"
 	"github.com/puppetlabs/wash/plugin"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -144,7 +146,9 @@ func listObjects(ctx context.Context, client *s3Client.S3, bucket string, prefix
 		entries = append(entries, newS3Object(o, name, bucket, key, client))
 	}
 
-	return entries, nil
+	// Let users browse the content of compressed objects (e.g. rotated logs)
+	// via a sibling archive directory.
+	return archive.AddDirs(entries), nil
 }
 
 // deleteObjects is a helper that deletes all objects that start with a specific prefix.
@@ -187,6 +191,7 @@ func (b *s3Bucket) Schema() *plugin.EntrySchema {
 	return plugin.
 		NewEntrySchema(b, "bucket").
 		SetMetadataSchema(bucketMetadata{}).
+		SetListCost(plugin.ExpensiveList).
 		SetDescription(s3BucketDescription)
 }
 
@@ -201,6 +206,15 @@ func (b *s3Bucket) List(ctx context.Context) ([]plugin.Entry, error) {
 	return listObjects(ctx, b.client, b.Name(), "")
 }
 
+// Create returns a new object with the given name at the root of the bucket. The
+// object's uploaded when it's written to.
+func (b *s3Bucket) Create(ctx context.Context, name string, _ os.FileMode) (plugin.Writable, error) {
+	if _, err := b.getRegion(ctx); err != nil {
+		return nil, err
+	}
+	return newEmptyS3Object(name, b.Name(), name, b.client), nil
+}
+
 func (b *s3Bucket) Delete(ctx context.Context) (bool, error) {
 	// According to https://docs.aws.amazon.com/AmazonS3/latest/dev/delete-or-empty-bucket.html,
 	// we must delete the bucket's objects and object versions (for versioned buckets) before
@@ -321,4 +335,13 @@ For example, the objects 'foo/bar' and 'foo/baz' are represented as files with
 path 'foo/bar' and path 'foo/baz', where 'foo' is represented as a 'directory'.
 Thus, if you ls this bucket, then everything you'll see is either an S3 object
 prefix ('directory') or an S3 object ('file').
+
+Compressed objects (those ending in .gz, .tgz, .tar or .zip) also get a
+sibling '<object>.contents' directory containing their decompressed content,
+so you can e.g. grep rotated logs without downloading them first.
+
+Objects can be modified and new objects can be created, e.g. via
+'cp local.txt <bucket>/foo/bar.txt' in the Wash shell. The content's uploaded
+when the file's closed. Large content is uploaded via a multipart upload, see
+the aws.s3_multipart_threshold config in the plugin root's docs.
 `
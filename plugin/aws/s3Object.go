@@ -3,8 +3,11 @@ package aws
 import (
 	"bytes"
 	"context"
+	"fmt"
+	"io"
 	"io/ioutil"
-	"strconv"
+	"net/url"
+	"time"
 
 	"github.com/puppetlabs/wash/activity"
 	"github.com/puppetlabs/wash/plugin"
@@ -12,14 +15,29 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	awsSDK "github.com/aws/aws-sdk-go/aws"
 	s3Client "github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 )
 
+// s3ReadAheadSize is the minimum number of bytes that an S3 object read fetches.
+// FUSE reads a file in small blocks, so fetching each block with its own
+// GetObject request would make sequential reads of large objects very slow.
+// Instead, the object prefetches a chunk and serves subsequent reads from it.
+// See plugin.RangePrefetcher.
+var s3ReadAheadSize int64 = 8 * 1024 * 1024
+
+// s3MultipartThreshold is the size at which writes switch from a single PutObject
+// request to a multipart upload. It's also the size of each uploaded part. It's
+// configurable via the aws.s3_multipart_threshold config.
+var s3MultipartThreshold int64 = 16 * 1024 * 1024
+
 // s3Object represents an S3 object.
 type s3Object struct {
 	plugin.EntryBase
-	bucket string
-	key    string
-	client *s3Client.S3
+	bucket     string
+	key        string
+	client     *s3Client.S3
+	size       int64
+	prefetcher plugin.RangePrefetcher
 }
 
 func newS3Object(o *s3Client.Object, name string, bucket string, key string, client *s3Client.S3) *s3Object {
@@ -29,6 +47,7 @@ func newS3Object(o *s3Client.Object, name string, bucket string, key string, cli
 	s3Obj.bucket = bucket
 	s3Obj.key = key
 	s3Obj.client = client
+	s3Obj.size = awsSDK.Int64Value(o.Size)
 
 	// S3 objects do not have a "creation time"; they're treated as atomic
 	// blobs that get replaced whenever the user uploads new data. Thus, we
@@ -38,6 +57,7 @@ func newS3Object(o *s3Client.Object, name string, bucket string, key string, cli
 	// TODO: Export a mungeSize helper to abstract away the common
 	// logic of validating a negative size
 	mtime := awsSDK.TimeValue(o.LastModified)
+	s3Obj.SetValidator(plugin.Validator{ETag: awsSDK.StringValue(o.ETag), LastModified: mtime})
 	s3Obj.
 		SetPartialMetadata(o).
 		Attributes().
@@ -50,6 +70,16 @@ func newS3Object(o *s3Client.Object, name string, bucket string, key string, cli
 	return s3Obj
 }
 
+// newEmptyS3Object returns an s3Object representing a new, empty object. The
+// object's only uploaded once it's written to.
+func newEmptyS3Object(name string, bucket string, key string, client *s3Client.S3) *s3Object {
+	return newS3Object(&s3Client.Object{
+		Key:          awsSDK.String(key),
+		LastModified: awsSDK.Time(time.Now()),
+		Size:         awsSDK.Int64(0),
+	}, name, bucket, key, client)
+}
+
 func (o *s3Object) Schema() *plugin.EntrySchema {
 	return plugin.
 		NewEntrySchema(o, "object").
@@ -72,11 +102,21 @@ func (o *s3Object) Metadata(ctx context.Context) (plugin.JSONObject, error) {
 	return plugin.ToJSONObject(metadata), nil
 }
 
+// Read reads the requested range of the object's content. Reads are served
+// from the prefetched chunk when possible so that large objects can be read
+// sequentially without buffering their entire content.
 func (o *s3Object) Read(ctx context.Context, size int64, offset int64) ([]byte, error) {
+	return o.prefetcher.Read(ctx, size, offset, o.size, s3ReadAheadSize, o.readRange)
+}
+
+// readRange fetches the given range of the object's content with a single
+// ranged GetObject request.
+func (o *s3Object) readRange(ctx context.Context, size int64, offset int64) ([]byte, error) {
 	request := &s3Client.GetObjectInput{
 		Bucket: awsSDK.String(o.bucket),
 		Key:    awsSDK.String(o.key),
-		Range:  awsSDK.String("bytes=" + strconv.FormatInt(offset, 10) + "-" + strconv.FormatInt(offset+size, 10)),
+		// The range's end is inclusive.
+		Range: awsSDK.String(fmt.Sprintf("bytes=%v-%v", offset, offset+size-1)),
 	}
 
 	resp, err := o.client.GetObjectWithContext(ctx, request)
@@ -89,23 +129,33 @@ func (o *s3Object) Read(ctx context.Context, size int64, offset int64) ([]byte,
 		}
 	}()
 
-	activity.Record(ctx, "S3 object read response: %+v", *resp)
+	activity.Debugf(ctx, "S3 object read response: %+v", *resp)
 	return ioutil.ReadAll(resp.Body)
 }
 
 func (o *s3Object) Write(ctx context.Context, p []byte) error {
-	request := &s3Client.PutObjectInput{
+	return o.WriteStream(ctx, bytes.NewReader(p), int64(len(p)))
+}
+
+// WriteStream uploads the object's new content. Content that's smaller than
+// s3MultipartThreshold is uploaded with a single PutObject request. Larger
+// content is uploaded in s3MultipartThreshold-sized parts via a multipart
+// upload, so only a few parts are buffered at a time.
+func (o *s3Object) WriteStream(ctx context.Context, r io.Reader, size int64) error {
+	uploader := s3manager.NewUploaderWithClient(o.client, func(u *s3manager.Uploader) {
+		u.PartSize = s3MultipartThreshold
+	})
+	resp, err := uploader.UploadWithContext(ctx, &s3manager.UploadInput{
 		Bucket: awsSDK.String(o.bucket),
 		Key:    awsSDK.String(o.key),
-		Body:   bytes.NewReader(p),
-	}
-
-	resp, err := o.client.PutObjectWithContext(ctx, request)
+		Body:   r,
+	})
 	if err != nil {
 		return err
 	}
 
-	activity.Record(ctx, "S3 object write response: %+v", *resp)
+	activity.Record(ctx, "S3 object upload response: %+v", *resp)
+	o.prefetcher.Reset()
 	return nil
 }
 
@@ -117,7 +167,48 @@ func (o *s3Object) Delete(ctx context.Context) (bool, error) {
 	return true, err
 }
 
+// Rename copies the object to its new key, then deletes it. S3 doesn't support
+// renaming objects in place. newParent can be any bucket or prefix that's
+// accessible by the object's profile.
+func (o *s3Object) Rename(ctx context.Context, newParent plugin.Parent, newName string) error {
+	var bucket, key string
+	var client *s3Client.S3
+	switch p := newParent.(type) {
+	case *s3Bucket:
+		if _, err := p.getRegion(ctx); err != nil {
+			return err
+		}
+		bucket, key, client = p.Name(), newName, p.client
+	case *s3ObjectPrefix:
+		bucket, key, client = p.bucket, p.prefix+newName, p.client
+	default:
+		return fmt.Errorf("%v can only be moved to an S3 bucket or prefix", plugin.ID(o))
+	}
+
+	if bucket == o.bucket && key == o.key {
+		return nil
+	}
+
+	// CopySource is the URL-encoded "bucket/key" of the source object.
+	source := (&url.URL{Path: o.bucket + "/" + o.key}).EscapedPath()
+	_, err := client.CopyObjectWithContext(ctx, &s3Client.CopyObjectInput{
+		Bucket:     awsSDK.String(bucket),
+		Key:        awsSDK.String(key),
+		CopySource: awsSDK.String(source),
+	})
+	if err != nil {
+		return err
+	}
+	activity.Record(ctx, "Copied S3 object s3://%v/%v to s3://%v/%v", o.bucket, o.key, bucket, key)
+	_, err = o.Delete(ctx)
+	return err
+}
+
 const s3ObjectDescription = `
 This is an S3 object. See the bucket's docs for more details on
 why we have this kind of entry.
+
+Renaming or moving an object (e.g. via mv) copies it to its new key, then
+deletes it, since S3 doesn't support renaming objects in place. Objects larger
+than 5GB can't be copied this way.
 `
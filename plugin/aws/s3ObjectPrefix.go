@@ -2,7 +2,9 @@ package aws
 
 import (
 	"context"
+	"os"
 
+	"github.com/puppetlabs/wash/archive"
 	"github.com/puppetlabs/wash/plugin"
 
 	s3Client "github.com/aws/aws-sdk-go/service/s3"
@@ -37,6 +39,7 @@ func (d *s3ObjectPrefix) ChildSchemas() []*plugin.EntrySchema {
 	return []*plugin.EntrySchema{
 		(&s3ObjectPrefix{}).Schema(),
 		(&s3Object{}).Schema(),
+		(&archive.Dir{}).Schema(),
 	}
 }
 
@@ -46,6 +49,12 @@ func (d *s3ObjectPrefix) List(ctx context.Context) ([]plugin.Entry, error) {
 	return listObjects(ctx, d.client, d.bucket, d.prefix)
 }
 
+// Create returns a new object with the given name under the prefix. The object's
+// uploaded when it's written to.
+func (d *s3ObjectPrefix) Create(ctx context.Context, name string, _ os.FileMode) (plugin.Writable, error) {
+	return newEmptyS3Object(name, d.bucket, d.prefix+name, d.client), nil
+}
+
 func (d *s3ObjectPrefix) Delete(ctx context.Context) (bool, error) {
 	err := deleteObjects(ctx, d.client, d.bucket, d.prefix)
 	return true, err
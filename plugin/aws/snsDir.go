@@ -0,0 +1,55 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	snsClient "github.com/aws/aws-sdk-go/service/sns"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// snsDir represents the resources/sns directory. It lists the topics in the
+// profile's region.
+type snsDir struct {
+	plugin.EntryBase
+	client *snsClient.SNS
+}
+
+func newSNSDir(ctx context.Context, session *session.Session) *snsDir {
+	snsDir := &snsDir{
+		EntryBase: plugin.NewEntry("sns"),
+	}
+	snsDir.client = snsClient.New(session)
+	if _, err := plugin.List(ctx, snsDir); err != nil {
+		snsDir.MarkInaccessible(ctx, err)
+	}
+	return snsDir
+}
+
+func (s *snsDir) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(s, "sns").IsSingleton()
+}
+
+func (s *snsDir) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&snsTopic{}).Schema(),
+	}
+}
+
+// List lists the topics.
+func (s *snsDir) List(ctx context.Context) ([]plugin.Entry, error) {
+	var topics []plugin.Entry
+	err := s.client.ListTopicsPagesWithContext(ctx, &snsClient.ListTopicsInput{}, func(resp *snsClient.ListTopicsOutput, _ bool) bool {
+		for _, topic := range resp.Topics {
+			topics = append(topics, newSNSTopic(*topic.TopicArn, s.client))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	activity.Record(ctx, "Listing %v SNS topics", len(topics))
+	return topics, nil
+}
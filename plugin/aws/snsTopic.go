@@ -0,0 +1,69 @@
+package aws
+
+import (
+	"context"
+	"strings"
+
+	awsSDK "github.com/aws/aws-sdk-go/aws"
+	snsClient "github.com/aws/aws-sdk-go/service/sns"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// snsTopic represents an SNS topic
+type snsTopic struct {
+	plugin.EntryBase
+	arn    string
+	client *snsClient.SNS
+}
+
+type snsTopicMetadata struct {
+	Attributes    map[string]string
+	Subscriptions []*snsClient.Subscription
+}
+
+func newSNSTopic(arn string, client *snsClient.SNS) *snsTopic {
+	topic := &snsTopic{
+		EntryBase: plugin.NewEntry(arn[strings.LastIndex(arn, ":")+1:]),
+	}
+	topic.arn = arn
+	topic.client = client
+	topic.
+		DisableCachingFor(plugin.MetadataOp).
+		SetPartialMetadata(map[string]string{"TopicArn": arn})
+	return topic
+}
+
+func (t *snsTopic) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(t, "topic").
+		SetDescription(snsTopicDescription).
+		SetMetadataSchema(snsTopicMetadata{})
+}
+
+// Metadata returns the topic's attributes and subscriptions.
+func (t *snsTopic) Metadata(ctx context.Context) (plugin.JSONObject, error) {
+	resp, err := t.client.GetTopicAttributesWithContext(ctx, &snsClient.GetTopicAttributesInput{
+		TopicArn: awsSDK.String(t.arn),
+	})
+	if err != nil {
+		return nil, err
+	}
+	meta := snsTopicMetadata{
+		Attributes:    awsSDK.StringValueMap(resp.Attributes),
+		Subscriptions: []*snsClient.Subscription{},
+	}
+
+	request := &snsClient.ListSubscriptionsByTopicInput{TopicArn: awsSDK.String(t.arn)}
+	err = t.client.ListSubscriptionsByTopicPagesWithContext(ctx, request, func(resp *snsClient.ListSubscriptionsByTopicOutput, _ bool) bool {
+		meta.Subscriptions = append(meta.Subscriptions, resp.Subscriptions...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return plugin.ToJSONObject(meta), nil
+}
+
+const snsTopicDescription = `
+This is an SNS topic. Its metadata includes its attributes and subscriptions.
+`
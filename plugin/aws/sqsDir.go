@@ -0,0 +1,55 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	sqsClient "github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// sqsDir represents the resources/sqs directory. It lists the queues in the
+// profile's region.
+type sqsDir struct {
+	plugin.EntryBase
+	client *sqsClient.SQS
+}
+
+func newSQSDir(ctx context.Context, session *session.Session) *sqsDir {
+	sqsDir := &sqsDir{
+		EntryBase: plugin.NewEntry("sqs"),
+	}
+	sqsDir.client = sqsClient.New(session)
+	if _, err := plugin.List(ctx, sqsDir); err != nil {
+		sqsDir.MarkInaccessible(ctx, err)
+	}
+	return sqsDir
+}
+
+func (s *sqsDir) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(s, "sqs").IsSingleton()
+}
+
+func (s *sqsDir) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&sqsQueue{}).Schema(),
+	}
+}
+
+// List lists the queues.
+func (s *sqsDir) List(ctx context.Context) ([]plugin.Entry, error) {
+	var queues []plugin.Entry
+	err := s.client.ListQueuesPagesWithContext(ctx, &sqsClient.ListQueuesInput{}, func(resp *sqsClient.ListQueuesOutput, _ bool) bool {
+		for _, url := range resp.QueueUrls {
+			queues = append(queues, newSQSQueue(*url, s.client))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	activity.Record(ctx, "Listing %v SQS queues", len(queues))
+	return queues, nil
+}
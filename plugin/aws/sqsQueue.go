@@ -0,0 +1,274 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+
+	awsSDK "github.com/aws/aws-sdk-go/aws"
+	sqsClient "github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// sqsDeleteStreamedMessages is true if streamed messages are deleted from their
+// queue, i.e. if streaming consumes the queue's messages instead of peeking at
+// them. It's set by the aws.sqs_delete_streamed_messages config.
+var sqsDeleteStreamedMessages = false
+
+// sqsPollInterval is how long a sqsQueueStreamer waits before polling again
+// when it only received messages that it already streamed. Without it, peeking
+// at a non-empty queue would poll continuously because the messages are always
+// visible.
+var sqsPollInterval = 2 * time.Second
+
+// sqsQueue represents an SQS queue
+type sqsQueue struct {
+	plugin.EntryBase
+	url    string
+	client *sqsClient.SQS
+}
+
+func newSQSQueue(url string, client *sqsClient.SQS) *sqsQueue {
+	queue := &sqsQueue{
+		EntryBase: plugin.NewEntry(url[strings.LastIndex(url, "/")+1:]),
+	}
+	queue.url = url
+	queue.client = client
+	queue.
+		DisableCachingFor(plugin.MetadataOp).
+		SetPartialMetadata(map[string]string{"QueueUrl": url})
+	return queue
+}
+
+func (q *sqsQueue) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(q, "queue").
+		SetDescription(sqsQueueDescription)
+}
+
+func (q *sqsQueue) attributes(ctx context.Context) (map[string]string, error) {
+	resp, err := q.client.GetQueueAttributesWithContext(ctx, &sqsClient.GetQueueAttributesInput{
+		QueueUrl:       awsSDK.String(q.url),
+		AttributeNames: []*string{awsSDK.String(sqsClient.QueueAttributeNameAll)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	attributes := awsSDK.StringValueMap(resp.Attributes)
+	attributes["QueueUrl"] = q.url
+	return attributes, nil
+}
+
+// Metadata returns the queue's attributes.
+func (q *sqsQueue) Metadata(ctx context.Context) (plugin.JSONObject, error) {
+	attributes, err := q.attributes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return plugin.ToJSONObject(attributes), nil
+}
+
+// Read returns the queue's attributes, which include its approximate number of
+// messages.
+func (q *sqsQueue) Read(ctx context.Context) ([]byte, error) {
+	attributes, err := q.attributes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	content, err := json.MarshalIndent(attributes, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(content, '\n'), nil
+}
+
+// Stream long-polls the queue for messages.
+func (q *sqsQueue) Stream(ctx context.Context) (io.ReadCloser, error) {
+	activity.Record(ctx, "Streaming the messages of the %v queue", q.url)
+	return &sqsQueueStreamer{
+		ctx:    ctx,
+		queue:  q,
+		delete: sqsDeleteStreamedMessages,
+		seen:   make(map[string]struct{}),
+	}, nil
+}
+
+// Exec supports the send and purge commands. See the queue's description for
+// their usage.
+func (q *sqsQueue) Exec(ctx context.Context, cmd string, args []string, opts plugin.ExecOptions) (plugin.ExecCommand, error) {
+	var run func() (string, error)
+	switch cmd {
+	case "send":
+		body := strings.Join(args, " ")
+		if body == "-" && opts.Stdin != nil {
+			content, err := ioutil.ReadAll(opts.Stdin)
+			if err != nil {
+				return nil, err
+			}
+			body = string(content)
+		}
+		if body == "" {
+			return nil, fmt.Errorf("send requires a message body")
+		}
+		run = func() (string, error) {
+			resp, err := q.client.SendMessageWithContext(ctx, &sqsClient.SendMessageInput{
+				QueueUrl:    awsSDK.String(q.url),
+				MessageBody: awsSDK.String(body),
+			})
+			if err != nil {
+				return "", err
+			}
+			activity.Record(ctx, "Sent message %v to the %v queue", awsSDK.StringValue(resp.MessageId), q.url)
+			return awsSDK.StringValue(resp.MessageId), nil
+		}
+	case "purge":
+		if len(args) > 0 {
+			return nil, fmt.Errorf("purge does not take any arguments")
+		}
+		run = func() (string, error) {
+			_, err := q.client.PurgeQueueWithContext(ctx, &sqsClient.PurgeQueueInput{
+				QueueUrl: awsSDK.String(q.url),
+			})
+			if err != nil {
+				return "", err
+			}
+			activity.Record(ctx, "Purged the %v queue", q.url)
+			return fmt.Sprintf("Purged %v", q.Name()), nil
+		}
+	default:
+		return nil, fmt.Errorf("unsupported command %v, only send and purge are supported", cmd)
+	}
+
+	execCmd := plugin.NewExecCommand(ctx)
+	go func() {
+		output, err := run()
+		if err != nil {
+			execCmd.CloseStreamsWithError(err)
+			execCmd.SetExitCodeErr(err)
+			return
+		}
+		_, err = execCmd.Stdout().Write([]byte(output + "\n"))
+		execCmd.CloseStreamsWithError(err)
+		execCmd.SetExitCode(0)
+	}()
+	return execCmd, nil
+}
+
+// sqsQueueStreamer streams a queue's messages by long-polling ReceiveMessage.
+// Unless delete is true, the messages are left in the queue. Their visibility
+// timeout is 0 so that the queue's consumers aren't affected, which means that
+// they may be received again; seen contains the IDs of the streamed messages so
+// that they're only streamed once.
+type sqsQueueStreamer struct {
+	ctx     context.Context
+	queue   *sqsQueue
+	delete  bool
+	seen    map[string]struct{}
+	current []byte
+}
+
+func (s *sqsQueueStreamer) Read(p []byte) (int, error) {
+	for len(s.current) == 0 {
+		select {
+		case <-s.ctx.Done():
+			return 0, io.EOF
+		default:
+		}
+		received, err := s.receiveMessages()
+		if err != nil {
+			if s.ctx.Err() != nil {
+				return 0, io.EOF
+			}
+			return 0, err
+		}
+		if len(s.current) == 0 && received > 0 {
+			select {
+			case <-s.ctx.Done():
+				return 0, io.EOF
+			case <-time.After(sqsPollInterval):
+			}
+		}
+	}
+	n := copy(p, s.current)
+	s.current = s.current[n:]
+	return n, nil
+}
+
+func (s *sqsQueueStreamer) Close() error {
+	// s is closed when the context is cancelled, so this can noop
+	return nil
+}
+
+// receiveMessages receives the next batch of messages and returns the number of
+// received messages, including the ones that were already streamed.
+func (s *sqsQueueStreamer) receiveMessages() (int, error) {
+	request := &sqsClient.ReceiveMessageInput{
+		QueueUrl:            awsSDK.String(s.queue.url),
+		MaxNumberOfMessages: awsSDK.Int64(10),
+		WaitTimeSeconds:     awsSDK.Int64(20),
+		AttributeNames:      []*string{awsSDK.String(sqsClient.MessageSystemAttributeNameSentTimestamp)},
+	}
+	if !s.delete {
+		request.VisibilityTimeout = awsSDK.Int64(0)
+	}
+	resp, err := s.queue.client.ReceiveMessageWithContext(s.ctx, request)
+	if err != nil {
+		return 0, err
+	}
+
+	var b strings.Builder
+	var entries []*sqsClient.DeleteMessageBatchRequestEntry
+	for _, msg := range resp.Messages {
+		id := awsSDK.StringValue(msg.MessageId)
+		if s.delete {
+			entries = append(entries, &sqsClient.DeleteMessageBatchRequestEntry{
+				Id:            msg.MessageId,
+				ReceiptHandle: msg.ReceiptHandle,
+			})
+		}
+		if _, ok := s.seen[id]; ok {
+			continue
+		}
+		s.seen[id] = struct{}{}
+		sentTimestamp, _ := strconv.ParseInt(awsSDK.StringValue(msg.Attributes[sqsClient.MessageSystemAttributeNameSentTimestamp]), 10, 64)
+		b.WriteString(formatLogEvent(sentTimestamp, awsSDK.StringValue(msg.Body)))
+	}
+	if len(entries) > 0 {
+		_, err := s.queue.client.DeleteMessageBatchWithContext(s.ctx, &sqsClient.DeleteMessageBatchInput{
+			QueueUrl: awsSDK.String(s.queue.url),
+			Entries:  entries,
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+	s.current = []byte(b.String())
+	return len(resp.Messages), nil
+}
+
+const sqsQueueDescription = `
+This is an SQS queue. Reading it returns its attributes, which include its
+approximate number of messages. The attributes are also available as its
+metadata.
+
+Streaming it (e.g. with 'tail -f') long-polls the queue for messages and prints
+each message's sent time and body. The messages aren't deleted, so they're
+still delivered to the queue's consumers. Add
+
+aws:
+  sqs_delete_streamed_messages: true
+
+to Wash’s config file to delete the streamed messages instead.
+
+Exec supports the send and purge commands, e.g.
+  wash exec sqs/my-queue send 'hello world'
+  wash exec sqs/my-queue purge
+
+Use '-' as the message body to read it from stdin.
+`
@@ -0,0 +1,101 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2020-02-01/containerservice"
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/puppetlabs/wash/plugin/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+type aksCluster struct {
+	plugin.EntryBase
+	client            containerservice.ManagedClustersClient
+	group             string
+	provisioningState string
+	mux               sync.Mutex
+	// cluster is nil until the cluster's listed
+	cluster *kubernetes.Cluster
+}
+
+func newAKSCluster(mc containerservice.ManagedCluster, client containerservice.ManagedClustersClient, group string) *aksCluster {
+	c := &aksCluster{
+		EntryBase: plugin.NewEntry(stringValue(mc.Name)),
+		client:    client,
+		group:     group,
+	}
+	if mc.ManagedClusterProperties != nil {
+		c.provisioningState = stringValue(mc.ProvisioningState)
+	}
+	c.SetPartialMetadata(mc)
+	return c
+}
+
+// List lists the cluster's Kubernetes namespaces. The cluster's kubeconfig is
+// fetched from AKS, so it doesn't need a local kubeconfig context.
+func (c *aksCluster) List(ctx context.Context) ([]plugin.Entry, error) {
+	cluster, err := c.kubernetesCluster(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return cluster.List(ctx)
+}
+
+func (c *aksCluster) kubernetesCluster(ctx context.Context) (*kubernetes.Cluster, error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if c.cluster != nil {
+		return c.cluster, nil
+	}
+
+	if c.provisioningState != "Succeeded" {
+		return nil, fmt.Errorf("the %v cluster is %v, its Kubernetes API is only reachable once it's Succeeded", c.Name(), c.provisioningState)
+	}
+	creds, err := c.client.ListClusterUserCredentials(ctx, c.group, c.Name())
+	if err != nil {
+		return nil, fmt.Errorf("could not get the %v cluster's credentials: %w", c.Name(), err)
+	}
+	if creds.Kubeconfigs == nil || len(*creds.Kubeconfigs) == 0 || (*creds.Kubeconfigs)[0].Value == nil {
+		return nil, fmt.Errorf("AKS didn't return a kubeconfig for the %v cluster", c.Name())
+	}
+	config, err := clientcmd.RESTConfigFromKubeConfig(*(*creds.Kubeconfigs)[0].Value)
+	if err != nil {
+		return nil, fmt.Errorf("could not load the %v cluster's kubeconfig: %w", c.Name(), err)
+	}
+	cluster, err := kubernetes.NewCluster(config, "default")
+	if err != nil {
+		return nil, err
+	}
+	c.cluster = cluster
+	return cluster, nil
+}
+
+func (c *aksCluster) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(c, "cluster").
+		SetPartialMetadataSchema(containerservice.ManagedCluster{}).
+		SetDescription(aksClusterDescription)
+}
+
+func (c *aksCluster) ChildSchemas() []*plugin.EntrySchema {
+	return kubernetes.ClusterChildSchemas()
+}
+
+// WrappedTypes implements plugin.HasWrappedTypes so that the Kubernetes entries
+// get the same metadata schemas as they do in the Kubernetes plugin.
+func (c *aksCluster) WrappedTypes() plugin.SchemaMap {
+	return kubernetes.WrappedTypes()
+}
+
+const aksClusterDescription = `
+This is an AKS cluster. Its entries are the cluster's Kubernetes namespaces, which
+have the same pods and persistent volume claims as the Kubernetes plugin's
+contexts, e.g.
+  ls azure/my-subscription/my-group/aks/my-cluster/default/pods
+
+The cluster's user credentials are fetched from AKS, so the cluster doesn't need
+a kubeconfig context. Your account needs permission to list the cluster's user
+credentials, and RBAC bindings that grant access to the cluster's resources.
+`
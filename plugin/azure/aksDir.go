@@ -0,0 +1,56 @@
+package azure
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2020-02-01/containerservice"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+type aksDir struct {
+	plugin.EntryBase
+	client containerservice.ManagedClustersClient
+	group  string
+}
+
+func newAKSDir(s session) *aksDir {
+	client := containerservice.NewManagedClustersClient(s.subscriptionID)
+	client.Authorizer = s.authorizer
+	return &aksDir{
+		EntryBase: plugin.NewEntry("aks"),
+		client:    client,
+		group:     s.resourceGroup,
+	}
+}
+
+func (d *aksDir) List(ctx context.Context) ([]plugin.Entry, error) {
+	iter, err := d.client.ListByResourceGroupComplete(ctx, d.group)
+	if err != nil {
+		return nil, err
+	}
+	var clusters []plugin.Entry
+	for ; iter.NotDone(); err = iter.NextWithContext(ctx) {
+		if err != nil {
+			return nil, err
+		}
+		clusters = append(clusters, newAKSCluster(iter.Value(), d.client, d.group))
+	}
+	return clusters, err
+}
+
+func (d *aksDir) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(d, "aks").
+		IsSingleton().
+		SetDescription(aksDirDescription)
+}
+
+func (d *aksDir) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&aksCluster{}).Schema(),
+	}
+}
+
+const aksDirDescription = `
+This directory represents Azure Kubernetes Service (AKS). Its entries consist of
+the resource group's AKS clusters.
+`
@@ -0,0 +1,74 @@
+package azure
+
+import (
+	"context"
+	"io/ioutil"
+
+	"github.com/Azure/azure-storage-blob-go/azblob"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+type blob struct {
+	plugin.EntryBase
+	blobURL azblob.BlobURL
+	size    int64
+}
+
+func newBlob(item azblob.BlobItem, name string, containerURL azblob.ContainerURL) *blob {
+	b := &blob{
+		EntryBase: plugin.NewEntry(name),
+		blobURL:   containerURL.NewBlobURL(item.Name),
+	}
+	if item.Properties.ContentLength != nil {
+		b.size = *item.Properties.ContentLength
+	}
+	mtime := item.Properties.LastModified
+	crtime := mtime
+	if item.Properties.CreationTime != nil {
+		crtime = *item.Properties.CreationTime
+	}
+	b.
+		SetPartialMetadata(item).
+		Attributes().
+		SetCrtime(crtime).
+		SetMtime(mtime).
+		SetCtime(mtime).
+		SetAtime(mtime).
+		SetSize(uint64(b.size))
+	return b
+}
+
+// Read reads the requested range of the blob's content, so that large blobs
+// don't need to be downloaded to be read.
+func (b *blob) Read(ctx context.Context, size int64, offset int64) ([]byte, error) {
+	if size == 0 || offset >= b.size {
+		return []byte{}, nil
+	}
+	resp, err := b.blobURL.Download(ctx, offset, size, azblob.BlobAccessConditions{}, false)
+	if err != nil {
+		return nil, err
+	}
+	body := resp.Body(azblob.RetryReaderOptions{MaxRetryRequests: 3})
+	defer body.Close()
+	return ioutil.ReadAll(body)
+}
+
+func (b *blob) Metadata(ctx context.Context) (plugin.JSONObject, error) {
+	resp, err := b.blobURL.GetProperties(ctx, azblob.BlobAccessConditions{})
+	if err != nil {
+		return nil, err
+	}
+	return plugin.JSONObject{
+		"ContentType":  resp.ContentType(),
+		"LastModified": resp.LastModified(),
+		"ETag":         string(resp.ETag()),
+		"BlobType":     string(resp.BlobType()),
+		"AccessTier":   resp.AccessTier(),
+		"Metadata":     resp.NewMetadata(),
+	}, nil
+}
+
+func (b *blob) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(b, "blob").
+		SetPartialMetadataSchema(azblob.BlobItem{})
+}
@@ -0,0 +1,83 @@
+package azure
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Azure/azure-storage-blob-go/azblob"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// listBlobs lists the blobs and blob prefixes that start with the given prefix,
+// using "/" as the delimiter. It's analogous to the AWS plugin's listObjects, so
+// see that for more details.
+func listBlobs(ctx context.Context, containerURL azblob.ContainerURL, prefix string) ([]plugin.Entry, error) {
+	var entries []plugin.Entry
+	for marker := (azblob.Marker{}); marker.NotDone(); {
+		resp, err := containerURL.ListBlobsHierarchySegment(ctx, marker, "/", azblob.ListBlobsSegmentOptions{Prefix: prefix})
+		if err != nil {
+			return nil, err
+		}
+		activity.Record(
+			ctx,
+			"(Container %v, Prefix %v): Retrieved %v prefixes and %v blobs",
+			resp.ContainerName,
+			prefix,
+			len(resp.Segment.BlobPrefixes),
+			len(resp.Segment.BlobItems),
+		)
+		for _, p := range resp.Segment.BlobPrefixes {
+			name := strings.TrimPrefix(p.Name, prefix)
+			if name != "/" {
+				name = strings.TrimSuffix(name, "/")
+			}
+			entries = append(entries, newBlobPrefix(name, p.Name, containerURL))
+		}
+		for _, item := range resp.Segment.BlobItems {
+			name := strings.TrimPrefix(item.Name, prefix)
+			if name == "" {
+				continue
+			}
+			entries = append(entries, newBlob(item, name, containerURL))
+		}
+		marker = resp.NextMarker
+	}
+	return entries, nil
+}
+
+type blobContainer struct {
+	plugin.EntryBase
+	containerURL azblob.ContainerURL
+}
+
+func newBlobContainer(item azblob.ContainerItem, containerURL azblob.ContainerURL) *blobContainer {
+	c := &blobContainer{
+		EntryBase:    plugin.NewEntry(item.Name),
+		containerURL: containerURL,
+	}
+	c.
+		SetPartialMetadata(item).
+		Attributes().
+		SetMtime(item.Properties.LastModified)
+	return c
+}
+
+func (c *blobContainer) List(ctx context.Context) ([]plugin.Entry, error) {
+	return listBlobs(ctx, c.containerURL, "")
+}
+
+func (c *blobContainer) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(c, "container").
+		SetPartialMetadataSchema(azblob.ContainerItem{}).
+		SetDescription(blobContainerDescription)
+}
+
+func (c *blobContainer) ChildSchemas() []*plugin.EntrySchema {
+	return (&blobPrefix{}).ChildSchemas()
+}
+
+const blobContainerDescription = `
+This is a blob container. Blob names are split on "/" into directories, like
+the AWS plugin's S3 buckets.
+`
@@ -0,0 +1,39 @@
+package azure
+
+import (
+	"context"
+
+	"github.com/Azure/azure-storage-blob-go/azblob"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// blobPrefix represents a common prefix of a container's blobs, i.e. a
+// directory.
+type blobPrefix struct {
+	plugin.EntryBase
+	prefix       string
+	containerURL azblob.ContainerURL
+}
+
+func newBlobPrefix(name string, prefix string, containerURL azblob.ContainerURL) *blobPrefix {
+	return &blobPrefix{
+		EntryBase:    plugin.NewEntry(name),
+		prefix:       prefix,
+		containerURL: containerURL,
+	}
+}
+
+func (p *blobPrefix) List(ctx context.Context) ([]plugin.Entry, error) {
+	return listBlobs(ctx, p.containerURL, p.prefix)
+}
+
+func (p *blobPrefix) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(p, "prefix")
+}
+
+func (p *blobPrefix) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&blobPrefix{}).Schema(),
+		(&blob{}).Schema(),
+	}
+}
@@ -0,0 +1,58 @@
+package azure
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-05-01/resources"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// session contains what's needed to create a subscription's API clients.
+type session struct {
+	authorizer     autorest.Authorizer
+	subscriptionID string
+	resourceGroup  string
+}
+
+type resourceGroup struct {
+	plugin.EntryBase
+	resources []plugin.Entry
+}
+
+func newResourceGroup(group resources.Group, s session) *resourceGroup {
+	rg := &resourceGroup{
+		EntryBase: plugin.NewEntry(stringValue(group.Name)),
+	}
+	s.resourceGroup = rg.Name()
+	rg.resources = []plugin.Entry{
+		newVMsDir(s),
+		newStorageAccountsDir(s),
+		newAKSDir(s),
+	}
+	rg.SetPartialMetadata(group)
+	return rg
+}
+
+func (rg *resourceGroup) List(ctx context.Context) ([]plugin.Entry, error) {
+	return rg.resources, nil
+}
+
+func (rg *resourceGroup) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(rg, "resource_group").
+		SetPartialMetadataSchema(resources.Group{}).
+		SetDescription(resourceGroupDescription)
+}
+
+func (rg *resourceGroup) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&vmsDir{}).Schema(),
+		(&storageAccountsDir{}).Schema(),
+		(&aksDir{}).Schema(),
+	}
+}
+
+const resourceGroupDescription = `
+This is an Azure resource group. Its entries consist of the group's virtual
+machines, storage accounts and AKS clusters.
+`
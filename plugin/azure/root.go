@@ -0,0 +1,148 @@
+// Package azure presents a filesystem hierarchy for Microsoft Azure resources.
+package azure
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-06-01/subscriptions"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure/auth"
+	"github.com/Azure/go-autorest/autorest/date"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// Root of the Azure plugin
+type Root struct {
+	plugin.EntryBase
+	authorizer    autorest.Authorizer
+	subscriptions map[string]struct{}
+}
+
+// newAuthorizer authorizes requests with the service principal that's described
+// by the AZURE_* environment variables, if they're set. Otherwise it uses the
+// Azure CLI's credentials.
+func newAuthorizer() (autorest.Authorizer, error) {
+	if os.Getenv("AZURE_CLIENT_ID") != "" {
+		return auth.NewAuthorizerFromEnvironment()
+	}
+	return auth.NewAuthorizerFromCLI()
+}
+
+// Init for root
+func (r *Root) Init(cfg map[string]interface{}) error {
+	r.EntryBase = plugin.NewEntry("azure")
+	r.SetTTLOf(plugin.ListOp, 1*time.Minute)
+
+	enabled, err := parseSubscriptions(cfg)
+	if err != nil {
+		return err
+	}
+	r.subscriptions = enabled
+
+	authorizer, err := newAuthorizer()
+	if err != nil {
+		return fmt.Errorf("could not load Azure credentials: %v", err)
+	}
+	r.authorizer = authorizer
+	return nil
+}
+
+// parseSubscriptions returns the subscriptions that are enabled by the config.
+// It returns nil if the config doesn't restrict them.
+func parseSubscriptions(cfg map[string]interface{}) (map[string]struct{}, error) {
+	subsI, ok := cfg["subscriptions"]
+	if !ok {
+		return nil, nil
+	}
+	subs, ok := subsI.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("azure.subscriptions config must be an array of strings, not %v", subsI)
+	}
+	enabled := make(map[string]struct{})
+	for _, elem := range subs {
+		sub, ok := elem.(string)
+		if !ok {
+			return nil, fmt.Errorf("azure.subscriptions config must be an array of strings, not %v", subs)
+		}
+		enabled[sub] = struct{}{}
+	}
+	return enabled, nil
+}
+
+// ChildSchemas returns the root's child schema
+func (r *Root) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&subscription{}).Schema(),
+	}
+}
+
+// Schema returns the root's schema
+func (r *Root) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(r, "azure").
+		SetDescription(rootDescription).
+		IsSingleton()
+}
+
+// WrappedTypes implements plugin.Root#WrappedTypes
+func (r *Root) WrappedTypes() plugin.SchemaMap {
+	return plugin.SchemaMap{
+		date.Time{}: plugin.TimeSchema(),
+	}
+}
+
+// List the available Azure subscriptions
+func (r *Root) List(ctx context.Context) ([]plugin.Entry, error) {
+	client := subscriptions.NewClient()
+	client.Authorizer = r.authorizer
+
+	activity.Record(ctx, "Loading subscriptions from %v", client.BaseURI)
+	iter, err := client.ListComplete(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var subs []plugin.Entry
+	for ; iter.NotDone(); err = iter.NextWithContext(ctx) {
+		if err != nil {
+			return nil, err
+		}
+		sub := iter.Value()
+		if _, ok := r.subscriptions[stringValue(sub.DisplayName)]; len(r.subscriptions) > 0 && !ok {
+			if _, ok := r.subscriptions[stringValue(sub.SubscriptionID)]; !ok {
+				// If a list of enabled subscriptions is provided and both name and
+				// subscription ID are not in it, omit this subscription.
+				continue
+			}
+		}
+		subs = append(subs, newSubscription(sub, r.authorizer))
+	}
+	return subs, err
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+const rootDescription = `
+This is the Azure plugin root. It uses the service principal that's described by
+the AZURE_TENANT_ID, AZURE_CLIENT_ID and AZURE_CLIENT_SECRET (or
+AZURE_CERTIFICATE_PATH) environment variables if they're set. Otherwise it uses
+the Azure CLI's credentials, so the simplest way to set it up is with
+
+  az login
+
+The Azure plugin will list all subscriptions you have access to. The
+subscriptions it lists can be limited by adding
+
+azure:
+  subscriptions: [subscription-1, subscription-2]
+
+to Wash’s config file. Subscriptions can be referenced either by name or
+subscription ID.
+`
@@ -0,0 +1,42 @@
+package azure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSubscriptions(t *testing.T) {
+	subs, err := parseSubscriptions(map[string]interface{}{})
+	if assert.NoError(t, err) {
+		assert.Nil(t, subs)
+	}
+
+	subs, err = parseSubscriptions(map[string]interface{}{"subscriptions": []interface{}{"dev", "0000-1111"}})
+	if assert.NoError(t, err) {
+		assert.Equal(t, map[string]struct{}{"dev": {}, "0000-1111": {}}, subs)
+	}
+
+	// An empty list enables every subscription.
+	subs, err = parseSubscriptions(map[string]interface{}{"subscriptions": []interface{}{}})
+	if assert.NoError(t, err) {
+		assert.Empty(t, subs)
+	}
+
+	_, err = parseSubscriptions(map[string]interface{}{"subscriptions": "dev"})
+	assert.EqualError(t, err, "azure.subscriptions config must be an array of strings, not dev")
+
+	_, err = parseSubscriptions(map[string]interface{}{"subscriptions": []interface{}{"dev", 1}})
+	assert.EqualError(t, err, "azure.subscriptions config must be an array of strings, not [dev 1]")
+}
+
+func TestInit_RejectsInvalidConfigBeforeLoadingCredentials(t *testing.T) {
+	err := (&Root{}).Init(map[string]interface{}{"subscriptions": 1})
+	assert.EqualError(t, err, "azure.subscriptions config must be an array of strings, not 1")
+}
+
+func TestStringValue(t *testing.T) {
+	assert.Equal(t, "", stringValue(nil))
+	s := "dev"
+	assert.Equal(t, "dev", stringValue(&s))
+}
@@ -0,0 +1,112 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2019-06-01/storage"
+	"github.com/Azure/azure-storage-blob-go/azblob"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+type storageAccount struct {
+	plugin.EntryBase
+	client       storage.AccountsClient
+	group        string
+	blobEndpoint string
+	mux          sync.Mutex
+	// serviceURL is nil until the account's containers are listed
+	serviceURL *azblob.ServiceURL
+}
+
+func newStorageAccount(account storage.Account, client storage.AccountsClient, group string) *storageAccount {
+	a := &storageAccount{
+		EntryBase: plugin.NewEntry(stringValue(account.Name)),
+		client:    client,
+		group:     group,
+	}
+	a.SetPartialMetadata(account)
+	if props := account.AccountProperties; props != nil {
+		if props.PrimaryEndpoints != nil {
+			a.blobEndpoint = stringValue(props.PrimaryEndpoints.Blob)
+		}
+		if props.CreationTime != nil {
+			a.Attributes().SetCrtime(props.CreationTime.Time)
+		}
+	}
+	return a
+}
+
+// List lists the account's blob containers
+func (a *storageAccount) List(ctx context.Context) ([]plugin.Entry, error) {
+	serviceURL, err := a.blobServiceURL(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var containers []plugin.Entry
+	for marker := (azblob.Marker{}); marker.NotDone(); {
+		resp, err := serviceURL.ListContainersSegment(ctx, marker, azblob.ListContainersSegmentOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range resp.ContainerItems {
+			containers = append(containers, newBlobContainer(item, serviceURL.NewContainerURL(item.Name)))
+		}
+		marker = resp.NextMarker
+	}
+	return containers, nil
+}
+
+// blobServiceURL authenticates against the account's blob endpoint with one of
+// the account's access keys.
+func (a *storageAccount) blobServiceURL(ctx context.Context) (*azblob.ServiceURL, error) {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	if a.serviceURL != nil {
+		return a.serviceURL, nil
+	}
+
+	if a.blobEndpoint == "" {
+		return nil, fmt.Errorf("the %v storage account doesn't have a blob endpoint", a.Name())
+	}
+	endpoint, err := url.Parse(a.blobEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the %v storage account's blob endpoint: %w", a.Name(), err)
+	}
+	keys, err := a.client.ListKeys(ctx, a.group, a.Name(), "")
+	if err != nil {
+		return nil, fmt.Errorf("could not get the %v storage account's access keys: %w", a.Name(), err)
+	}
+	if keys.Keys == nil || len(*keys.Keys) == 0 {
+		return nil, fmt.Errorf("the %v storage account doesn't have any access keys", a.Name())
+	}
+	activity.Record(ctx, "Using access key %v of storage account %v", stringValue((*keys.Keys)[0].KeyName), a.Name())
+	credential, err := azblob.NewSharedKeyCredential(a.Name(), stringValue((*keys.Keys)[0].Value))
+	if err != nil {
+		return nil, err
+	}
+	serviceURL := azblob.NewServiceURL(*endpoint, azblob.NewPipeline(credential, azblob.PipelineOptions{}))
+	a.serviceURL = &serviceURL
+	return a.serviceURL, nil
+}
+
+func (a *storageAccount) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(a, "storage_account").
+		SetPartialMetadataSchema(storage.Account{}).
+		SetDescription(storageAccountDescription)
+}
+
+func (a *storageAccount) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&blobContainer{}).Schema(),
+	}
+}
+
+const storageAccountDescription = `
+This is an Azure storage account. Its entries consist of the account's blob
+containers. The containers are accessed with the account's first access key, so
+your account needs permission to list the storage account's keys.
+`
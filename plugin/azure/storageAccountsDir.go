@@ -0,0 +1,50 @@
+package azure
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2019-06-01/storage"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+type storageAccountsDir struct {
+	plugin.EntryBase
+	client storage.AccountsClient
+	group  string
+}
+
+func newStorageAccountsDir(s session) *storageAccountsDir {
+	client := storage.NewAccountsClient(s.subscriptionID)
+	client.Authorizer = s.authorizer
+	return &storageAccountsDir{
+		EntryBase: plugin.NewEntry("storage_accounts"),
+		client:    client,
+		group:     s.resourceGroup,
+	}
+}
+
+func (d *storageAccountsDir) List(ctx context.Context) ([]plugin.Entry, error) {
+	resp, err := d.client.ListByResourceGroup(ctx, d.group)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Value == nil {
+		return []plugin.Entry{}, nil
+	}
+	accounts := make([]plugin.Entry, len(*resp.Value))
+	for i, account := range *resp.Value {
+		accounts[i] = newStorageAccount(account, d.client, d.group)
+	}
+	return accounts, nil
+}
+
+func (d *storageAccountsDir) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(d, "storage_accounts").
+		IsSingleton()
+}
+
+func (d *storageAccountsDir) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&storageAccount{}).Schema(),
+	}
+}
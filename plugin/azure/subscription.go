@@ -0,0 +1,69 @@
+package azure
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-05-01/resources"
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-06-01/subscriptions"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+type subscription struct {
+	plugin.EntryBase
+	authorizer autorest.Authorizer
+	id         string
+}
+
+func newSubscription(sub subscriptions.Subscription, authorizer autorest.Authorizer) *subscription {
+	name := stringValue(sub.DisplayName)
+	if name == "" {
+		name = stringValue(sub.SubscriptionID)
+	}
+	s := &subscription{
+		EntryBase:  plugin.NewEntry(name),
+		authorizer: authorizer,
+		id:         stringValue(sub.SubscriptionID),
+	}
+	s.SetPartialMetadata(sub)
+	return s
+}
+
+// List the subscription's resource groups
+func (s *subscription) List(ctx context.Context) ([]plugin.Entry, error) {
+	client := resources.NewGroupsClient(s.id)
+	client.Authorizer = s.authorizer
+	iter, err := client.ListComplete(ctx, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	var groups []plugin.Entry
+	for ; iter.NotDone(); err = iter.NextWithContext(ctx) {
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, newResourceGroup(iter.Value(), s.session()))
+	}
+	return groups, err
+}
+
+func (s *subscription) session() session {
+	return session{authorizer: s.authorizer, subscriptionID: s.id}
+}
+
+func (s *subscription) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(s, "subscription").
+		SetPartialMetadataSchema(subscriptions.Subscription{}).
+		SetDescription(subscriptionDescription)
+}
+
+func (s *subscription) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&resourceGroup{}).Schema(),
+	}
+}
+
+const subscriptionDescription = `
+This is an Azure subscription. Its entries consist of the subscription's
+resource groups.
+`
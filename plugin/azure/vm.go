@@ -0,0 +1,169 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-07-01/compute"
+	"github.com/kballard/go-shellquote"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+type vm struct {
+	plugin.EntryBase
+	client  compute.VirtualMachinesClient
+	group   string
+	windows bool
+}
+
+func newVM(machine compute.VirtualMachine, client compute.VirtualMachinesClient, group string) *vm {
+	v := &vm{
+		EntryBase: plugin.NewEntry(stringValue(machine.Name)),
+		client:    client,
+		group:     group,
+	}
+	var size, osType, provisioningState string
+	if props := machine.VirtualMachineProperties; props != nil {
+		if props.HardwareProfile != nil {
+			size = string(props.HardwareProfile.VMSize)
+		}
+		if props.StorageProfile != nil && props.StorageProfile.OsDisk != nil {
+			osType = string(props.StorageProfile.OsDisk.OsType)
+		}
+		provisioningState = stringValue(props.ProvisioningState)
+	}
+	v.windows = osType == string(compute.Windows)
+	v.
+		SetPartialMetadata(machine).
+		Attributes().
+		SetCustom("location", stringValue(machine.Location)).
+		SetCustom("size", size).
+		SetCustom("os_type", osType).
+		SetCustom("provisioning_state", provisioningState)
+	return v
+}
+
+// Exec runs the command with Run Command, which runs it as a script through the
+// VM agent. The VM doesn't need to be reachable over SSH or WinRM.
+func (v *vm) Exec(ctx context.Context, cmd string, args []string, opts plugin.ExecOptions) (plugin.ExecCommand, error) {
+	if opts.Stdin != nil {
+		return nil, fmt.Errorf("Run Command doesn't support stdin")
+	}
+	commandID := "RunShellScript"
+	script := shellquote.Join(append([]string{cmd}, args...)...)
+	if v.windows {
+		commandID = "RunPowerShellScript"
+		script = strings.Join(append([]string{cmd}, args...), " ")
+	}
+	input := compute.RunCommandInput{
+		CommandID: &commandID,
+		Script:    &[]string{script},
+	}
+	future, err := v.client.RunCommand(ctx, v.group, v.Name(), input)
+	if err != nil {
+		return nil, err
+	}
+
+	execCmd := plugin.NewExecCommand(ctx)
+	go func() {
+		result, err := v.runCommandResult(ctx, future)
+		if err != nil {
+			execCmd.CloseStreamsWithError(err)
+			execCmd.SetExitCodeErr(err)
+			return
+		}
+		stdout, stderr := parseRunCommandResult(result)
+		_, err = execCmd.Stdout().Write([]byte(stdout))
+		if err == nil {
+			_, err = execCmd.Stderr().Write([]byte(stderr))
+		}
+		execCmd.CloseStreamsWithError(err)
+		// Run Command doesn't report the script's exit code.
+		execCmd.SetExitCode(0)
+	}()
+	return execCmd, nil
+}
+
+// Signal starts, stops, restarts or deallocates the VM. It returns once Azure's
+// accepted the request, without waiting for the VM's power state to change.
+func (v *vm) Signal(ctx context.Context, signal string) error {
+	var err error
+	switch signal {
+	case "start":
+		_, err = v.client.Start(ctx, v.group, v.Name())
+	case "stop":
+		_, err = v.client.PowerOff(ctx, v.group, v.Name(), nil)
+	case "restart":
+		_, err = v.client.Restart(ctx, v.group, v.Name())
+	case "deallocate":
+		_, err = v.client.Deallocate(ctx, v.group, v.Name())
+	default:
+		err = fmt.Errorf("unsupported signal %v", signal)
+	}
+	return err
+}
+
+func (v *vm) runCommandResult(ctx context.Context, future compute.VirtualMachinesRunCommandFuture) (compute.RunCommandResult, error) {
+	activity.Record(ctx, "Waiting for the command to finish on %v", v.Name())
+	if err := future.WaitForCompletionRef(ctx, v.client.Client); err != nil {
+		return compute.RunCommandResult{}, err
+	}
+	return future.Result(v.client)
+}
+
+// parseRunCommandResult returns the command's stdout and stderr. Windows VMs
+// report them as separate statuses, while Linux VMs report a single status whose
+// message contains [stdout] and [stderr] sections.
+func parseRunCommandResult(result compute.RunCommandResult) (stdout string, stderr string) {
+	if result.Value == nil {
+		return "", ""
+	}
+	for _, status := range *result.Value {
+		code, message := stringValue(status.Code), stringValue(status.Message)
+		switch {
+		case strings.Contains(code, "StdOut"):
+			stdout += message
+		case strings.Contains(code, "StdErr"):
+			stderr += message
+		default:
+			const stdoutMarker, stderrMarker = "[stdout]\n", "[stderr]\n"
+			stdoutIx, stderrIx := strings.Index(message, stdoutMarker), strings.Index(message, stderrMarker)
+			if stdoutIx < 0 || stderrIx < stdoutIx {
+				stdout += message
+				continue
+			}
+			stdout += message[stdoutIx+len(stdoutMarker) : stderrIx]
+			stderr += message[stderrIx+len(stderrMarker):]
+		}
+	}
+	return stdout, stderr
+}
+
+func (v *vm) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(v, "vm").
+		SetPartialMetadataSchema(compute.VirtualMachine{}).
+		SetDescription(vmDescription).
+		AddCustomAttribute("location", plugin.StringAttribute, "The region that the VM's deployed to").
+		AddCustomAttribute("size", plugin.StringAttribute, "The VM's size, e.g. Standard_B1s").
+		AddCustomAttribute("os_type", plugin.StringAttribute, "The VM's operating system, i.e. Linux or Windows").
+		AddCustomAttribute("provisioning_state", plugin.StringAttribute, "The VM's provisioning state, e.g. Succeeded").
+		AddSignal("start", "Starts the VM").
+		AddSignal("stop", "Powers off the VM. It's still billed for its compute resources").
+		AddSignal("restart", "Restarts the VM").
+		AddSignal("deallocate", "Stops the VM and releases its compute resources so that it's no longer billed for them")
+}
+
+const vmDescription = `
+This is an Azure virtual machine. Exec runs commands with Run Command, so the VM
+doesn't need to be reachable over SSH or WinRM, e.g.
+  wash exec azure/my-subscription/my-group/vms/my-vm uname -a
+
+Commands run as root on Linux VMs and in PowerShell on Windows VMs. Run Command
+runs one command at a time, doesn't support stdin and doesn't report the
+command's exit code, so it's best suited to quick, non-interactive commands.
+
+Signal the VM to start, stop, restart or deallocate it, e.g.
+  wash signal deallocate azure/my-subscription/my-group/vms/my-vm
+`
@@ -0,0 +1,59 @@
+package azure
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-07-01/compute"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRunCommandResult(t *testing.T) {
+	status := func(code string, message string) compute.InstanceViewStatus {
+		return compute.InstanceViewStatus{Code: &code, Message: &message}
+	}
+	for _, c := range []struct {
+		name     string
+		statuses *[]compute.InstanceViewStatus
+		stdout   string
+		stderr   string
+	}{
+		{name: "no statuses"},
+		{
+			name: "Windows statuses",
+			statuses: &[]compute.InstanceViewStatus{
+				status("ComponentStatus/StdOut/succeeded", "out\n"),
+				status("ComponentStatus/StdErr/succeeded", "err\n"),
+			},
+			stdout: "out\n",
+			stderr: "err\n",
+		},
+		{
+			name: "Linux status",
+			statuses: &[]compute.InstanceViewStatus{
+				status("ProvisioningState/succeeded", "Enable succeeded: \n[stdout]\nout\n\n[stderr]\nerr\n"),
+			},
+			stdout: "out\n\n",
+			stderr: "err\n",
+		},
+		{
+			name: "Linux status without sections",
+			statuses: &[]compute.InstanceViewStatus{
+				status("ProvisioningState/failed", "Enable failed"),
+			},
+			stdout: "Enable failed",
+		},
+		{
+			name: "Linux status with its sections out of order",
+			statuses: &[]compute.InstanceViewStatus{
+				status("ProvisioningState/succeeded", "[stderr]\nerr\n[stdout]\nout\n"),
+			},
+			stdout: "[stderr]\nerr\n[stdout]\nout\n",
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			stdout, stderr := parseRunCommandResult(compute.RunCommandResult{Value: c.statuses})
+			assert.Equal(t, c.stdout, stdout)
+			assert.Equal(t, c.stderr, stderr)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package azure
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-07-01/compute"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+type vmsDir struct {
+	plugin.EntryBase
+	client compute.VirtualMachinesClient
+	group  string
+}
+
+func newVMsDir(s session) *vmsDir {
+	client := compute.NewVirtualMachinesClient(s.subscriptionID)
+	client.Authorizer = s.authorizer
+	return &vmsDir{
+		EntryBase: plugin.NewEntry("vms"),
+		client:    client,
+		group:     s.resourceGroup,
+	}
+}
+
+func (d *vmsDir) List(ctx context.Context) ([]plugin.Entry, error) {
+	iter, err := d.client.ListComplete(ctx, d.group)
+	if err != nil {
+		return nil, err
+	}
+	var vms []plugin.Entry
+	for ; iter.NotDone(); err = iter.NextWithContext(ctx) {
+		if err != nil {
+			return nil, err
+		}
+		vms = append(vms, newVM(iter.Value(), d.client, d.group))
+	}
+	return vms, err
+}
+
+func (d *vmsDir) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(d, "vms").
+		IsSingleton()
+}
+
+func (d *vmsDir) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&vm{}).Schema(),
+	}
+}
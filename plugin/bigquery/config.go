@@ -0,0 +1,123 @@
+package bigquery
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	bq "google.golang.org/api/bigquery/v2"
+)
+
+// namedQuery is a query from the bigquery.queries config.
+type namedQuery struct {
+	Name    string `json:"name"`
+	SQL     string `json:"sql"`
+	Project string `json:"project,omitempty"`
+	// Params are the query's named parameters, which are referenced as @name
+	// in its SQL.
+	Params []*bq.QueryParameter `json:"params,omitempty"`
+}
+
+// parseQueries parses the bigquery.queries config, which maps each query's
+// name to its definition, e.g.
+//
+//	signups:
+//	  sql: SELECT day, COUNT(*) AS n FROM `p.d.signups` WHERE day >= @since GROUP BY day
+//	  project: p
+//	  params:
+//	    since: {type: DATE, value: 2020-01-01}
+//
+// The queries are sorted by name.
+func parseQueries(queriesI interface{}) ([]namedQuery, error) {
+	queriesMap, ok := queriesI.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("bigquery.queries config must be a map of query names to queries, not %v", queriesI)
+	}
+
+	queries := make([]namedQuery, 0, len(queriesMap))
+	for name, queryI := range queriesMap {
+		if strings.Contains(name, "/") {
+			return nil, fmt.Errorf("bigquery.queries.%v: query names can't contain a '/'", name)
+		}
+		query, err := parseQuery(name, queryI)
+		if err != nil {
+			return nil, fmt.Errorf("bigquery.queries.%v: %v", name, err)
+		}
+		queries = append(queries, query)
+	}
+	sort.Slice(queries, func(i, j int) bool {
+		return queries[i].Name < queries[j].Name
+	})
+	return queries, nil
+}
+
+func parseQuery(name string, queryI interface{}) (namedQuery, error) {
+	queryMap, ok := queryI.(map[string]interface{})
+	if !ok {
+		return namedQuery{}, fmt.Errorf("query must be a map with sql, project and params keys, not %v", queryI)
+	}
+
+	query := namedQuery{Name: name}
+	if query.SQL, ok = queryMap["sql"].(string); !ok || query.SQL == "" {
+		return namedQuery{}, fmt.Errorf("sql must be a non-empty string, not %v", queryMap["sql"])
+	}
+	if projectI, ok := queryMap["project"]; ok {
+		if query.Project, ok = projectI.(string); !ok {
+			return namedQuery{}, fmt.Errorf("project must be a string, not %v", projectI)
+		}
+	}
+	if paramsI, ok := queryMap["params"]; ok {
+		paramsMap, ok := paramsI.(map[string]interface{})
+		if !ok {
+			return namedQuery{}, fmt.Errorf("params must be a map of parameter names to values, not %v", paramsI)
+		}
+		for paramName, valueI := range paramsMap {
+			param, err := parseParam(paramName, valueI)
+			if err != nil {
+				return namedQuery{}, fmt.Errorf("params.%v: %v", paramName, err)
+			}
+			query.Params = append(query.Params, param)
+		}
+		sort.Slice(query.Params, func(i, j int) bool {
+			return query.Params[i].Name < query.Params[j].Name
+		})
+	}
+	return query, nil
+}
+
+// parseParam parses a query parameter. Its type is inferred from scalar values,
+// so types like DATE that don't have a YAML equivalent are set with the
+// {type, value} form.
+func parseParam(name string, valueI interface{}) (*bq.QueryParameter, error) {
+	var typ, value string
+	switch v := valueI.(type) {
+	case string:
+		typ, value = "STRING", v
+	case bool:
+		typ, value = "BOOL", strconv.FormatBool(v)
+	case int:
+		typ, value = "INT64", strconv.Itoa(v)
+	case int64:
+		typ, value = "INT64", strconv.FormatInt(v, 10)
+	case float64:
+		typ, value = "FLOAT64", strconv.FormatFloat(v, 'f', -1, 64)
+	case map[string]interface{}:
+		var ok bool
+		if typ, ok = v["type"].(string); !ok || typ == "" {
+			return nil, fmt.Errorf("type must be a non-empty string, not %v", v["type"])
+		}
+		typ = strings.ToUpper(typ)
+		if v["value"] == nil {
+			return nil, fmt.Errorf("value must be set")
+		}
+		value = fmt.Sprint(v["value"])
+	default:
+		return nil, fmt.Errorf("value must be a string, boolean, number or {type, value} map, not %v", valueI)
+	}
+	return &bq.QueryParameter{
+		Name:           name,
+		ParameterType:  &bq.QueryParameterType{Type: typ},
+		ParameterValue: &bq.QueryParameterValue{Value: value},
+	}, nil
+}
@@ -0,0 +1,65 @@
+package bigquery
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bq "google.golang.org/api/bigquery/v2"
+)
+
+func param(name, typ, value string) *bq.QueryParameter {
+	return &bq.QueryParameter{
+		Name:           name,
+		ParameterType:  &bq.QueryParameterType{Type: typ},
+		ParameterValue: &bq.QueryParameterValue{Value: value},
+	}
+}
+
+func TestParseQueries(t *testing.T) {
+	queries, err := parseQueries(map[string]interface{}{
+		"signups": map[string]interface{}{
+			"sql": "SELECT * FROM t WHERE day >= @since AND n > @min",
+			"params": map[string]interface{}{
+				"since":  map[string]interface{}{"type": "date", "value": "2020-01-01"},
+				"min":    10,
+				"ratio":  0.5,
+				"active": true,
+				"name":   "x",
+			},
+		},
+		"all": map[string]interface{}{"sql": "SELECT 1", "project": "p"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []namedQuery{
+		{Name: "all", SQL: "SELECT 1", Project: "p"},
+		{
+			Name: "signups",
+			SQL:  "SELECT * FROM t WHERE day >= @since AND n > @min",
+			Params: []*bq.QueryParameter{
+				param("active", "BOOL", "true"),
+				param("min", "INT64", "10"),
+				param("name", "STRING", "x"),
+				param("ratio", "FLOAT64", "0.5"),
+				param("since", "DATE", "2020-01-01"),
+			},
+		},
+	}, queries)
+}
+
+func TestParseQueriesErrors(t *testing.T) {
+	_, err := parseQueries([]interface{}{"q"})
+	assert.EqualError(t, err, "bigquery.queries config must be a map of query names to queries, not [q]")
+
+	_, err = parseQueries(map[string]interface{}{"a/b": map[string]interface{}{"sql": "SELECT 1"}})
+	assert.EqualError(t, err, "bigquery.queries.a/b: query names can't contain a '/'")
+
+	_, err = parseQueries(map[string]interface{}{"q": map[string]interface{}{}})
+	assert.EqualError(t, err, "bigquery.queries.q: sql must be a non-empty string, not <nil>")
+
+	_, err = parseQueries(map[string]interface{}{"q": map[string]interface{}{
+		"sql":    "SELECT @p",
+		"params": map[string]interface{}{"p": map[string]interface{}{"type": "DATE"}},
+	}})
+	assert.EqualError(t, err, "bigquery.queries.q: params.p: value must be set")
+}
@@ -0,0 +1,70 @@
+package bigquery
+
+import (
+	"context"
+	"time"
+
+	"github.com/puppetlabs/wash/plugin"
+	bq "google.golang.org/api/bigquery/v2"
+)
+
+type dataset struct {
+	plugin.EntryBase
+	service *bq.Service
+	ref     *bq.DatasetReference
+}
+
+func newDataset(service *bq.Service, ds *bq.DatasetListDatasets) *dataset {
+	d := &dataset{
+		EntryBase: plugin.NewEntry(ds.DatasetReference.DatasetId),
+	}
+	d.service = service
+	d.ref = ds.DatasetReference
+	d.
+		SetPartialMetadata(ds).
+		Attributes().
+		SetCustom("location", ds.Location)
+	return d
+}
+
+func (d *dataset) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(d, "dataset").
+		SetPartialMetadataSchema(bq.DatasetListDatasets{}).
+		SetMetadataSchema(bq.Dataset{}).
+		AddCustomAttribute("location", plugin.StringAttribute, "The dataset's location, e.g. US or europe-west2")
+}
+
+func (d *dataset) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&table{}).Schema(),
+	}
+}
+
+func (d *dataset) List(ctx context.Context) ([]plugin.Entry, error) {
+	var entries []plugin.Entry
+	err := d.service.Tables.List(d.ref.ProjectId, d.ref.DatasetId).Pages(ctx, func(page *bq.TableList) error {
+		for _, t := range page.Tables {
+			entries = append(entries, newTable(d.service, t))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (d *dataset) Metadata(ctx context.Context) (plugin.JSONObject, error) {
+	ds, err := d.service.Datasets.Get(d.ref.ProjectId, d.ref.DatasetId).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	return plugin.ToJSONObject(ds), nil
+}
+
+// fromMillis converts BigQuery's timestamps, which are milliseconds since the
+// epoch.
+func fromMillis(ms int64) time.Time {
+	return time.Unix(0, ms*int64(time.Millisecond))
+}
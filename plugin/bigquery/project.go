@@ -0,0 +1,97 @@
+package bigquery
+
+import (
+	"context"
+
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+	bq "google.golang.org/api/bigquery/v2"
+)
+
+type projectsDir struct {
+	plugin.EntryBase
+	root *Root
+}
+
+func newProjectsDir(r *Root) *projectsDir {
+	dir := &projectsDir{
+		EntryBase: plugin.NewEntry("projects"),
+	}
+	dir.root = r
+	return dir
+}
+
+func (d *projectsDir) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(d, "projects").
+		IsSingleton()
+}
+
+func (d *projectsDir) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&project{}).Schema(),
+	}
+}
+
+// List lists the projects that can be accessed in BigQuery. If the
+// bigquery.projects config is set, then only those projects are listed.
+func (d *projectsDir) List(ctx context.Context) ([]plugin.Entry, error) {
+	var entries []plugin.Entry
+	err := d.root.service.Projects.List().Pages(ctx, func(page *bq.ProjectList) error {
+		for _, proj := range page.Projects {
+			id := proj.ProjectReference.ProjectId
+			if _, ok := d.root.projects[id]; len(d.root.projects) > 0 && !ok {
+				continue
+			}
+			entries = append(entries, newProject(d.root.service, proj))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	activity.Record(ctx, "Listed %v BigQuery projects", len(entries))
+	return entries, nil
+}
+
+type project struct {
+	plugin.EntryBase
+	service *bq.Service
+	id      string
+}
+
+func newProject(service *bq.Service, proj *bq.ProjectListProjects) *project {
+	p := &project{
+		EntryBase: plugin.NewEntry(proj.ProjectReference.ProjectId),
+	}
+	p.service = service
+	p.id = proj.ProjectReference.ProjectId
+	p.SetPartialMetadata(proj)
+	return p
+}
+
+func (p *project) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(p, "project").
+		SetPartialMetadataSchema(bq.ProjectListProjects{})
+}
+
+func (p *project) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&dataset{}).Schema(),
+	}
+}
+
+func (p *project) List(ctx context.Context) ([]plugin.Entry, error) {
+	var entries []plugin.Entry
+	err := p.service.Datasets.List(p.id).Pages(ctx, func(page *bq.DatasetList) error {
+		for _, ds := range page.Datasets {
+			entries = append(entries, newDataset(p.service, ds))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,164 @@
+package bigquery
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+	bq "google.golang.org/api/bigquery/v2"
+)
+
+// queryTimeoutMs is how long each request waits for a query to finish before
+// it's polled again.
+const queryTimeoutMs = 10000
+
+// queriesDir lists the named queries' results.
+type queriesDir struct {
+	plugin.EntryBase
+	root *Root
+}
+
+func newQueriesDir(r *Root) *queriesDir {
+	dir := &queriesDir{
+		EntryBase: plugin.NewEntry("queries"),
+	}
+	dir.root = r
+	return dir
+}
+
+func (d *queriesDir) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(d, "queries").
+		SetDescription(queriesDirDescription).
+		IsSingleton()
+}
+
+func (d *queriesDir) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&queryResult{}).Schema(),
+	}
+}
+
+// List lists a CSV and a JSON result for each query. The queries aren't run
+// until the results are read.
+func (d *queriesDir) List(ctx context.Context) ([]plugin.Entry, error) {
+	entries := make([]plugin.Entry, 0, 2*len(d.root.queries))
+	for _, query := range d.root.queries {
+		entries = append(entries, newQueryResult(d.root, query, "csv"), newQueryResult(d.root, query, "json"))
+	}
+	return entries, nil
+}
+
+// queryResult is a named query's result in one format.
+type queryResult struct {
+	plugin.EntryBase
+	root   *Root
+	query  namedQuery
+	format string
+}
+
+func newQueryResult(r *Root, query namedQuery, format string) *queryResult {
+	result := &queryResult{
+		EntryBase: plugin.NewEntry(query.Name + "." + format),
+	}
+	result.root = r
+	result.query = query
+	result.format = format
+	result.SetPartialMetadata(query)
+	return result
+}
+
+func (q *queryResult) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(q, "result").
+		SetDescription(queryResultDescription).
+		SetPartialMetadataSchema(namedQuery{})
+}
+
+// Read runs the query and formats its rows.
+func (q *queryResult) Read(ctx context.Context) ([]byte, error) {
+	project := q.query.Project
+	if project == "" {
+		project = q.root.project
+	}
+	if project == "" {
+		return nil, fmt.Errorf("query %v has no project to run in; set its project or the bigquery.project config", q.query.Name)
+	}
+
+	fields, rows, err := runQuery(ctx, q.root.service, project, q.query)
+	if err != nil {
+		return nil, err
+	}
+	if q.format == "csv" {
+		return formatCSV(fields, rows)
+	}
+	return formatJSON(rows)
+}
+
+// runQuery runs the query as a standard SQL job in project and returns all of
+// its rows. It polls the job until it's done, then pages through its results.
+func runQuery(ctx context.Context, service *bq.Service, project string, query namedQuery) ([]*bq.TableFieldSchema, []row, error) {
+	useLegacySQL := false
+	req := &bq.QueryRequest{
+		Query:           query.SQL,
+		UseLegacySql:    &useLegacySQL,
+		QueryParameters: query.Params,
+		TimeoutMs:       queryTimeoutMs,
+	}
+	if len(query.Params) > 0 {
+		req.ParameterMode = "NAMED"
+	}
+	resp, err := service.Jobs.Query(project, req).Context(ctx).Do()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(resp.Errors) > 0 {
+		return nil, nil, fmt.Errorf("query %v failed: %v", query.Name, resp.Errors[0].Message)
+	}
+	job := resp.JobReference
+	activity.Record(ctx, "Started query %v as job %v", query.Name, job.JobId)
+
+	result := &bq.GetQueryResultsResponse{
+		JobComplete: resp.JobComplete,
+		PageToken:   resp.PageToken,
+		Rows:        resp.Rows,
+		Schema:      resp.Schema,
+	}
+	var rows []row
+	for {
+		if result.JobComplete {
+			for _, r := range result.Rows {
+				rows = append(rows, convertRow(result.Schema.Fields, r.F))
+			}
+			if result.PageToken == "" {
+				return result.Schema.Fields, rows, nil
+			}
+		}
+		call := service.Jobs.GetQueryResults(job.ProjectId, job.JobId).
+			Location(job.Location).
+			TimeoutMs(queryTimeoutMs).
+			Context(ctx)
+		if result.JobComplete {
+			call = call.PageToken(result.PageToken)
+		}
+		if result, err = call.Do(); err != nil {
+			return nil, nil, err
+		}
+		if len(result.Errors) > 0 {
+			return nil, nil, fmt.Errorf("query %v failed: %v", query.Name, result.Errors[0].Message)
+		}
+	}
+}
+
+const queriesDirDescription = `
+This is the results of the named queries in the bigquery.queries config. Each
+query has a CSV and a JSON result.
+`
+
+const queryResultDescription = `
+This is a named query's result as CSV or JSON. Reading it runs the query, so its
+content is the query's current rows. The JSON result is an array of objects,
+whose keys are in column order. In the CSV result, nulls are empty and records
+and repeated columns are JSON. Its metadata is the query's definition.
+`
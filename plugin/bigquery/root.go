@@ -0,0 +1,121 @@
+// Package bigquery presents a filesystem hierarchy for Google BigQuery's
+// projects, datasets and tables, and for the results of named queries.
+//
+// It uses Google's application default credentials, like the GCP plugin.
+package bigquery
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/puppetlabs/wash/plugin"
+	"golang.org/x/oauth2/google"
+	bq "google.golang.org/api/bigquery/v2"
+	"google.golang.org/api/option"
+)
+
+// Root of the BigQuery plugin
+type Root struct {
+	plugin.EntryBase
+	service  *bq.Service
+	projects map[string]struct{}
+	// project is the default project that queries run in.
+	project string
+	queries []namedQuery
+}
+
+// Init for root
+func (r *Root) Init(cfg map[string]interface{}) error {
+	if projsI, ok := cfg["projects"]; ok {
+		projs, ok := projsI.([]interface{})
+		if !ok {
+			return fmt.Errorf("bigquery.projects config must be an array of strings, not %v", projsI)
+		}
+		r.projects = make(map[string]struct{})
+		for _, elem := range projs {
+			proj, ok := elem.(string)
+			if !ok {
+				return fmt.Errorf("bigquery.projects config must be an array of strings, not %v", projs)
+			}
+			r.projects[proj] = struct{}{}
+		}
+	}
+	if projectI, ok := cfg["project"]; ok {
+		if r.project, ok = projectI.(string); !ok {
+			return fmt.Errorf("bigquery.project config must be a string, not %v", projectI)
+		}
+	}
+	if queriesI, ok := cfg["queries"]; ok {
+		queries, err := parseQueries(queriesI)
+		if err != nil {
+			return err
+		}
+		r.queries = queries
+	}
+
+	ctx := context.Background()
+	creds, err := google.FindDefaultCredentials(ctx, bq.BigqueryScope)
+	if err != nil {
+		return err
+	}
+	if r.project == "" {
+		r.project = creds.ProjectID
+	}
+	service, err := bq.NewService(ctx, option.WithCredentials(creds))
+	if err != nil {
+		return err
+	}
+	r.service = service
+
+	r.EntryBase = plugin.NewEntry("bigquery")
+	return nil
+}
+
+// Schema returns the root's schema
+func (r *Root) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(r, "bigquery").
+		SetDescription(rootDescription).
+		IsSingleton()
+}
+
+// ChildSchemas returns the root's child schema
+func (r *Root) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&projectsDir{}).Schema(),
+		(&queriesDir{}).Schema(),
+	}
+}
+
+// List lists the projects and the named queries.
+func (r *Root) List(ctx context.Context) ([]plugin.Entry, error) {
+	return []plugin.Entry{newProjectsDir(r), newQueriesDir(r)}, nil
+}
+
+const rootDescription = `
+This is the BigQuery plugin root. Its projects directory lists the projects that
+you can access in BigQuery, along with their datasets and tables. A table's
+metadata includes its columns (in schema.fields). Its queries directory has the
+results of the named queries in the plugin's config.
+
+Like the GCP plugin, it uses the credentials in GOOGLE_APPLICATION_CREDENTIALS,
+or the ones stored by 'gcloud auth application-default login'.
+
+The listed projects can be limited by the bigquery.projects config. Queries are
+configured by name, and their parameters are referenced as @name in their SQL.
+A parameter's type is inferred from its value, or set with the {type, value}
+form, e.g.
+
+bigquery:
+  projects: [analytics]
+  project: analytics
+  queries:
+    signups:
+      sql: SELECT day, COUNT(*) AS n FROM analytics.events.signups WHERE day >= @since GROUP BY day
+      params:
+        since: {type: DATE, value: 2020-01-01}
+
+Queries run in their project, which defaults to the bigquery.project config or
+the credentials' project. Query and parameter names are lowercased when the
+config's loaded.
+`
@@ -0,0 +1,188 @@
+package bigquery
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	bq "google.golang.org/api/bigquery/v2"
+)
+
+// The REST API returns every scalar as a string, records as {"f": [cells]} and
+// repeated fields as [{"v": value}]. convertRow converts a row's cells to Go
+// values using the result's schema: integers, floats and booleans are parsed,
+// timestamps (which are float seconds since the epoch) become times, records
+// become rows and repeated fields become slices. Other types stay strings.
+func convertRow(fields []*bq.TableFieldSchema, cells []*bq.TableCell) row {
+	r := make(row, len(fields))
+	for i, field := range fields {
+		var v interface{}
+		if i < len(cells) && cells[i] != nil {
+			v = cells[i].V
+		}
+		r[i] = column{name: field.Name, value: convertValue(field, v)}
+	}
+	return r
+}
+
+func convertValue(field *bq.TableFieldSchema, v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	if field.Mode == "REPEATED" {
+		elems, ok := v.([]interface{})
+		if !ok {
+			return v
+		}
+		elemField := *field
+		elemField.Mode = ""
+		values := make([]interface{}, len(elems))
+		for i, elem := range elems {
+			if cell, ok := elem.(map[string]interface{}); ok {
+				elem = cell["v"]
+			}
+			values[i] = convertValue(&elemField, elem)
+		}
+		return values
+	}
+
+	switch field.Type {
+	case "RECORD", "STRUCT":
+		record, ok := v.(map[string]interface{})
+		if !ok {
+			return v
+		}
+		cellsI, _ := record["f"].([]interface{})
+		cells := make([]*bq.TableCell, len(cellsI))
+		for i, cellI := range cellsI {
+			cells[i] = &bq.TableCell{}
+			if cell, ok := cellI.(map[string]interface{}); ok {
+				cells[i].V = cell["v"]
+			}
+		}
+		return convertRow(field.Fields, cells)
+	}
+
+	s, ok := v.(string)
+	if !ok {
+		return v
+	}
+	switch field.Type {
+	case "INTEGER", "INT64":
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return i
+		}
+	case "FLOAT", "FLOAT64":
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	case "BOOLEAN", "BOOL":
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b
+		}
+	case "TIMESTAMP":
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			secs := int64(f)
+			return time.Unix(secs, int64((f-float64(secs))*1e9)).UTC().Round(time.Microsecond)
+		}
+	}
+	return s
+}
+
+// column is a row's named value.
+type column struct {
+	name  string
+	value interface{}
+}
+
+// row is a result's row. Its columns are in the schema's order, which JSON
+// objects keep.
+type row []column
+
+// MarshalJSON encodes the row as an object whose keys are in column order.
+func (r row) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, col := range r {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(col.name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(col.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// formatJSON formats the rows as a JSON array of objects.
+func formatJSON(rows []row) ([]byte, error) {
+	if rows == nil {
+		rows = []row{}
+	}
+	content, err := json.MarshalIndent(rows, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(content, '\n'), nil
+}
+
+// formatCSV formats the rows as CSV with a header of the schema's column names.
+// Nulls are empty, and records and repeated fields are JSON.
+func formatCSV(fields []*bq.TableFieldSchema, rows []row) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	header := make([]string, len(fields))
+	for i, field := range fields {
+		header[i] = field.Name
+	}
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+
+	record := make([]string, len(fields))
+	for _, r := range rows {
+		for i, col := range r {
+			s, err := csvValue(col.value)
+			if err != nil {
+				return nil, err
+			}
+			record[i] = s
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+func csvValue(v interface{}) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case time.Time:
+		return v.Format(time.RFC3339Nano), nil
+	default:
+		b, err := json.Marshal(v)
+		return string(b), err
+	}
+}
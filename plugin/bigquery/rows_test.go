@@ -0,0 +1,100 @@
+package bigquery
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bq "google.golang.org/api/bigquery/v2"
+)
+
+var testFields = []*bq.TableFieldSchema{
+	{Name: "name", Type: "STRING"},
+	{Name: "n", Type: "INTEGER"},
+	{Name: "ratio", Type: "FLOAT"},
+	{Name: "active", Type: "BOOLEAN"},
+	{Name: "at", Type: "TIMESTAMP"},
+	{Name: "tags", Type: "STRING", Mode: "REPEATED"},
+	{Name: "owner", Type: "RECORD", Fields: []*bq.TableFieldSchema{
+		{Name: "id", Type: "INTEGER"},
+		{Name: "email", Type: "STRING"},
+	}},
+}
+
+func cells(values ...interface{}) []*bq.TableCell {
+	result := make([]*bq.TableCell, len(values))
+	for i, v := range values {
+		result[i] = &bq.TableCell{V: v}
+	}
+	return result
+}
+
+func testRows() []row {
+	return []row{
+		convertRow(testFields, cells(
+			"a,b", "42", "0.25", "true", "1.5E9",
+			[]interface{}{map[string]interface{}{"v": "x"}, map[string]interface{}{"v": "y"}},
+			map[string]interface{}{"f": []interface{}{map[string]interface{}{"v": "7"}, map[string]interface{}{"v": "o@example.com"}}},
+		)),
+		convertRow(testFields, cells(nil, nil, nil, nil, nil, []interface{}{}, nil)),
+	}
+}
+
+func TestConvertRow(t *testing.T) {
+	rows := testRows()
+	assert.Equal(t, row{
+		{name: "name", value: "a,b"},
+		{name: "n", value: int64(42)},
+		{name: "ratio", value: 0.25},
+		{name: "active", value: true},
+		{name: "at", value: time.Unix(1500000000, 0).UTC()},
+		{name: "tags", value: []interface{}{"x", "y"}},
+		{name: "owner", value: row{{name: "id", value: int64(7)}, {name: "email", value: "o@example.com"}}},
+	}, rows[0])
+}
+
+func TestFormatJSON(t *testing.T) {
+	content, err := formatJSON(testRows())
+	require.NoError(t, err)
+	assert.Equal(t, `[
+  {
+    "name": "a,b",
+    "n": 42,
+    "ratio": 0.25,
+    "active": true,
+    "at": "2017-07-14T02:40:00Z",
+    "tags": [
+      "x",
+      "y"
+    ],
+    "owner": {
+      "id": 7,
+      "email": "o@example.com"
+    }
+  },
+  {
+    "name": null,
+    "n": null,
+    "ratio": null,
+    "active": null,
+    "at": null,
+    "tags": [],
+    "owner": null
+  }
+]
+`, string(content))
+
+	content, err = formatJSON(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "[]\n", string(content))
+}
+
+func TestFormatCSV(t *testing.T) {
+	content, err := formatCSV(testFields, testRows())
+	require.NoError(t, err)
+	assert.Equal(t, `name,n,ratio,active,at,tags,owner
+"a,b",42,0.25,true,2017-07-14T02:40:00Z,"[""x"",""y""]","{""id"":7,""email"":""o@example.com""}"
+,,,,,[],
+`, string(content))
+}
@@ -0,0 +1,54 @@
+package bigquery
+
+import (
+	"context"
+
+	"github.com/puppetlabs/wash/plugin"
+	bq "google.golang.org/api/bigquery/v2"
+)
+
+// table is a table, view or materialized view. Its rows aren't readable, since
+// tables can be huge; named queries should be used to get them.
+type table struct {
+	plugin.EntryBase
+	service *bq.Service
+	ref     *bq.TableReference
+}
+
+func newTable(service *bq.Service, t *bq.TableListTables) *table {
+	tbl := &table{
+		EntryBase: plugin.NewEntry(t.TableReference.TableId),
+	}
+	tbl.service = service
+	tbl.ref = t.TableReference
+	tbl.
+		SetPartialMetadata(t).
+		Attributes().
+		SetCrtime(fromMillis(t.CreationTime)).
+		SetCustom("type", t.Type)
+	return tbl
+}
+
+func (t *table) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(t, "table").
+		SetDescription(tableDescription).
+		SetPartialMetadataSchema(bq.TableListTables{}).
+		SetMetadataSchema(bq.Table{}).
+		AddCustomAttribute("type", plugin.StringAttribute, "The table's type, i.e. TABLE, VIEW, MATERIALIZED_VIEW or EXTERNAL")
+}
+
+// Metadata gets the table's details, which include its columns, row count and
+// size.
+func (t *table) Metadata(ctx context.Context) (plugin.JSONObject, error) {
+	tbl, err := t.service.Tables.Get(t.ref.ProjectId, t.ref.DatasetId, t.ref.TableId).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	return plugin.ToJSONObject(tbl), nil
+}
+
+const tableDescription = `
+This is a BigQuery table. Its metadata includes its columns (in schema.fields),
+row count and size. Its rows can be queried with a named query.
+`
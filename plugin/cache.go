@@ -7,17 +7,51 @@ import (
 	"strings"
 	"time"
 
+	"github.com/puppetlabs/wash/activity"
 	"github.com/puppetlabs/wash/datastore"
 )
 
 // KeyType is used to create a unique key type for looking up context values.
 type keyType int
 
-// id is used to identify the parent's ID in a context.
-const parentID keyType = iota
+const (
+	// id is used to identify the parent's ID in a context.
+	parentID keyType = iota
+	// streamSince is used to identify the time that a stream starts from in a
+	// context. See WithStreamSince.
+	streamSince
+	// cacheNamespace is used to identify the cache namespace in a context.
+	// See WithCacheNamespace.
+	cacheNamespace
+)
 
 var cache datastore.Cache
 
+// WithCacheNamespace returns a context whose operations are cached separately
+// from the operations of other namespaces, including the default namespace of
+// contexts without one. The API uses it to keep remote clients' cached results
+// apart. The namespace can only contain letters, digits, '.', '_' and '-'.
+//
+// ClearCacheFor clears every namespace's results, since they're stale for all
+// of them.
+func WithCacheNamespace(ctx context.Context, namespace string) context.Context {
+	if !cacheNamespaceRegex.MatchString(namespace) {
+		panic(fmt.Sprintf("plugin.WithCacheNamespace: invalid namespace %q", namespace))
+	}
+	return context.WithValue(ctx, cacheNamespace, namespace)
+}
+
+var cacheNamespaceRegex = regexp.MustCompile("^[a-zA-Z0-9._-]+$")
+
+// cacheCategory returns the cache category of the operation in the context's
+// namespace.
+func cacheCategory(ctx context.Context, opName string) string {
+	if ns, ok := ctx.Value(cacheNamespace).(string); ok {
+		return ns + "@" + opName
+	}
+	return opName
+}
+
 // InitCache initializes the cache
 func InitCache() {
 	if notRunningTests() {
@@ -56,13 +90,95 @@ func UnsetTestCache() {
 	cache = nil
 }
 
+// CacheStats returns the cache's usage. It returns false if the cache doesn't
+// track its usage.
+func CacheStats() (datastore.Stats, bool) {
+	if mem, ok := cache.(*datastore.MemCache); ok {
+		return mem.Stats(), true
+	}
+	return datastore.Stats{}, false
+}
+
+// WashMetadataKey is the metadata key that holds Wash's own information about
+// an entry, like the state of its cached actions.
+const WashMetadataKey = "wash"
+
+// MetadataWithCacheInfo returns the entry's metadata with a "wash.cache"
+// section that describes its cached actions. Each action's source is "cache"
+// if its result is served from the cache, or "live" if it's fetched from the
+// plugin. Cached results also include their age, when they expire, and how
+// long their last refresh took. The metadata action's source describes the
+// returned metadata.
+//
+// The section's only included if the cache tracks its items.
+func MetadataWithCacheInfo(ctx context.Context, e Entry) (JSONObject, error) {
+	mem, ok := cache.(*datastore.MemCache)
+	if !ok {
+		return Metadata(ctx, e)
+	}
+	metadataOpName := defaultOpCodeToNameMap[MetadataOp]
+	_, metadataWasCached := mem.Info(cacheCategory(ctx, metadataOpName), e.eb().id)
+	meta, err := Metadata(ctx, e)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheInfo := make(map[string]interface{})
+	for op, opName := range defaultOpCodeToNameMap {
+		switch defaultOpCode(op) {
+		case ListOp:
+			if !ListAction().IsSupportedOn(e) {
+				continue
+			}
+		case ReadOp:
+			if !ReadAction().IsSupportedOn(e) {
+				continue
+			}
+		}
+		opInfo := map[string]interface{}{"source": "live"}
+		if info, ok := mem.Info(cacheCategory(ctx, opName), e.eb().id); ok {
+			opInfo["source"] = "cache"
+			opInfo["age"] = time.Since(info.Refreshed).Round(time.Millisecond).String()
+			opInfo["last_refresh_duration"] = info.RefreshDuration.Round(time.Millisecond).String()
+			if !info.Expires.IsZero() {
+				opInfo["expires_in"] = time.Until(info.Expires).Round(time.Millisecond).String()
+			}
+		}
+		cacheInfo[strings.ToLower(opName)] = opInfo
+	}
+	if metadataInfo, ok := cacheInfo[strings.ToLower(metadataOpName)]; ok && !metadataWasCached {
+		metadataInfo.(map[string]interface{})["source"] = "live"
+	}
+
+	washInfo := map[string]interface{}{"cache": cacheInfo}
+	if existing, ok := meta[WashMetadataKey].(map[string]interface{}); ok {
+		// Keep the information that came with the metadata, like a remote
+		// daemon's.
+		for k, v := range existing {
+			if k != "cache" {
+				washInfo[k] = v
+			}
+		}
+	}
+	withInfo := make(JSONObject, len(meta)+1)
+	for k, v := range meta {
+		withInfo[k] = v
+	}
+	withInfo[WashMetadataKey] = washInfo
+	return withInfo, nil
+}
+
 var opNameRegex = regexp.MustCompile("^[a-zA-Z]+$")
 
-const opQualifier = "^[a-zA-Z]+::"
+// namespaceQualifier matches the optional namespace of an op's key. See
+// cacheCategory.
+const namespaceQualifier = "^([a-zA-Z0-9._-]+@)?"
+
+const opQualifier = namespaceQualifier + "[a-zA-Z]+::"
 
-// opKeyRegex returns a regex that matches <op>::<path>
+// opKeyRegex returns a regex that matches <op>::<path> in every namespace
 func opKeyRegex(op string, path string) *regexp.Regexp {
-	opRegex := "^" + regexp.QuoteMeta(op+"::")
+	opRegex := namespaceQualifier + regexp.QuoteMeta(op+"::")
 
 	var expr string
 	if path == "/" {
@@ -74,6 +190,12 @@ func opKeyRegex(op string, path string) *regexp.Regexp {
 	return regexp.MustCompile(expr)
 }
 
+// allOpKeysRegex returns a regex that matches <op>::<path> for every op in
+// every namespace
+func allOpKeysRegex(path string) *regexp.Regexp {
+	return regexp.MustCompile(opQualifier + "/" + regexp.QuoteMeta(strings.Trim(path, "/")) + "$")
+}
+
 // This returns a regex that matches <op>::<path> and <op>::<child_path>
 // where <child_path> is a descendant of <path>.
 func allOpKeysIncludingChildrenRegex(path string) *regexp.Regexp {
@@ -108,6 +230,13 @@ func ClearCacheFor(path string, clearAncestorList bool) []string {
 	return deleted
 }
 
+// ClearOpsFor removes the results of the entry's own ops from the cache. Unlike
+// ClearCacheFor, it doesn't remove the results of its children's ops or its
+// ancestor's listing. Returns an array of deleted keys.
+func ClearOpsFor(path string) []string {
+	return cache.Delete(allOpKeysRegex(path))
+}
+
 // Get the path for the ancestor that prefetched an entry at path. If none are found in the cache,
 // returns an empty string. This may be overly aggressive in some cases where it finds an ancestor
 // but it's not the immediate source ancestor; this seems like an acceptable compromise to make
@@ -179,70 +308,36 @@ func CachedOp(ctx context.Context, opName string, entry Entry, ttl time.Duration
 	return cachedOp(ctx, opName, entry, ttl, op)
 }
 
-// DuplicateCNameErr represents a duplicate cname error, which
-// occurs when at least two children have the same cname.
-type DuplicateCNameErr struct {
-	ParentID                 string
-	FirstChildName           string
-	FirstChildSlashReplacer  rune
-	SecondChildName          string
-	SecondChildSlashReplacer rune
-	CName                    string
-}
-
-func (c DuplicateCNameErr) Error() string {
-	pluginName := strings.SplitN(
-		strings.TrimLeft(c.ParentID, "/"),
-		"/",
-		2,
-	)[0]
-
-	return fmt.Sprintf(
-		"error listing %v: children %v and %v have the same cname of %v. This means that either the %v plugin's API returns duplicate names, or that you need to use a different slash replacer (see EntryBase#SetSlashReplacer)",
-		c.ParentID,
-		c.FirstChildName,
-		c.SecondChildName,
-		c.CName,
-		pluginName,
-	)
-}
-
 // CachedList caches a Parent's List method. It also sets the
 // children's IDs to <parent_id> + "/" + <child_cname>.
 //
 // CachedList returns a map of <entry_cname> => <entry_object> to optimize
 // querying a specific entry.
 func cachedList(ctx context.Context, p Parent) (*EntryMap, error) {
-	cachedEntries, err := cachedDefaultOp(ctx, ListOp, p, func() (interface{}, error) {
+	cachedEntries, err := cachedDefaultOp(ctx, ListOp, p, func() (result interface{}, err error) {
 		// Including the entry's ID allows plugin authors to use any Cached* methods defined on the
 		// children after their creation. This is necessary when the child's Cached* methods are used
 		// to calculate its attributes. Note that the child's ID is set in cachedOp.
+		done := recordCall(p, "List")
+		defer func() { done(err) }()
 		entries, err := p.List(context.WithValue(ctx, parentID, p.eb().id))
 		if err != nil {
 			return nil, err
 		}
 
-		searchedEntries := newEntryMap()
+		var accessibleEntries []Entry
 		for _, entry := range entries {
-			cname := CName(entry)
-
-			if duplicateEntry, ok := searchedEntries.mp[cname]; ok {
-				return nil, DuplicateCNameErr{
-					ParentID:                 p.eb().id,
-					FirstChildName:           duplicateEntry.eb().name,
-					FirstChildSlashReplacer:  duplicateEntry.eb().slashReplacer,
-					SecondChildName:          entry.eb().name,
-					SecondChildSlashReplacer: entry.eb().slashReplacer,
-					CName:                    cname,
-				}
-			}
-
 			if entry.eb().isInaccessible {
 				// Skip entries that are expected to be inaccessible.
 				continue
 			}
+			accessibleEntries = append(accessibleEntries, entry)
+		}
+		assignCNames(ctx, p, accessibleEntries)
 
-			searchedEntries.mp[cname] = entry
+		searchedEntries := newEntryMap()
+		for _, entry := range accessibleEntries {
+			searchedEntries.mp[CName(entry)] = entry
 
 			// Ensure ID is set on all entries so that we can use it for caching later in places
 			// where the context doesn't include the parent's ID.
@@ -250,6 +345,7 @@ func cachedList(ctx context.Context, p Parent) (*EntryMap, error) {
 
 			passAlongWrappedTypes(p, entry)
 		}
+		searchedEntries.lookup = virtualChildLookup(p)
 
 		return searchedEntries, nil
 	})
@@ -264,41 +360,12 @@ func cachedList(ctx context.Context, p Parent) (*EntryMap, error) {
 // cachedRead caches an entry's Read method
 func cachedRead(ctx context.Context, e Entry) (entryContent, error) {
 	cachedContent, err := cachedDefaultOp(ctx, ReadOp, e, func() (interface{}, error) {
-		switch signature := ReadAction().signature(e); signature {
-		case DefaultSignature:
-			// Both external and core plugin entries that have the default Read signature
-			// implement the Readable interface, so we can go ahead and cast directly.
-			r := e.(Readable)
-			rawContent, err := r.Read(ctx)
-			if err != nil {
-				return nil, err
-			}
-			return newEntryContent(rawContent), nil
-		case BlockReadableSignature:
-			var readFunc blockReadFunc
-			switch t := e.(type) {
-			case externalPlugin:
-				readFunc = func(ctx context.Context, size int64, offset int64) ([]byte, error) {
-					return t.BlockRead(ctx, size, offset)
-				}
-			case BlockReadable:
-				readFunc = func(ctx context.Context, size int64, offset int64) ([]byte, error) {
-					return t.Read(ctx, size, offset)
-				}
-			default:
-				// We should never hit this code-path
-				panic("attempting to retrieve the content of a non-readable entry")
-			}
-			content := newBlockReadableEntryContent(readFunc)
-			if attr := e.eb().attributes; attr.HasSize() {
-				content.sz = attr.Size()
-			}
-			return content, nil
-		default:
-			// We should never hit this code-path
-			msg := fmt.Sprintf("unknown signature '%v' for read", signature)
-			panic(msg)
+		if e.eb().id != "" && usesValidators(e) {
+			return validatedRead(ctx, e, func() (entryContent, error) {
+				return readContent(ctx, e, true)
+			})
 		}
+		return readContent(ctx, e, false)
 	})
 
 	if err != nil {
@@ -308,9 +375,62 @@ func cachedRead(ctx context.Context, e Entry) (entryContent, error) {
 	return cachedContent.(entryContent), nil
 }
 
+// readContent reads the entry's content. If memoizeBlocks is true, then the
+// blocks read from a BlockReadable entry are memoized by the returned content.
+func readContent(ctx context.Context, e Entry, memoizeBlocks bool) (content entryContent, err error) {
+	switch signature := ReadAction().signature(e); signature {
+	case DefaultSignature:
+		// Both external and core plugin entries that have the default Read signature
+		// implement the Readable interface, so we can go ahead and cast directly.
+		r := e.(Readable)
+		done := recordCall(e, "Read")
+		defer func() { done(err) }()
+		rawContent, err := r.Read(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return newEntryContent(rawContent), nil
+	case BlockReadableSignature:
+		var readFunc blockReadFunc
+		switch t := e.(type) {
+		case externalPlugin:
+			readFunc = func(ctx context.Context, size int64, offset int64) (data []byte, err error) {
+				done := recordCall(e, "Read")
+				defer func() { done(err) }()
+				defer recoverPanic(ctx, e, "Read", &err)
+				return t.BlockRead(ctx, size, offset)
+			}
+		case BlockReadable:
+			readFunc = func(ctx context.Context, size int64, offset int64) (data []byte, err error) {
+				done := recordCall(e, "Read")
+				defer func() { done(err) }()
+				defer recoverPanic(ctx, e, "Read", &err)
+				return t.Read(ctx, size, offset)
+			}
+		default:
+			// We should never hit this code-path
+			panic("attempting to retrieve the content of a non-readable entry")
+		}
+		content := newBlockReadableEntryContent(readFunc)
+		if attr := e.eb().attributes; attr.HasSize() {
+			content.sz = attr.Size()
+		}
+		if memoizeBlocks {
+			content.memoizeBlocks()
+		}
+		return content, nil
+	default:
+		// We should never hit this code-path
+		msg := fmt.Sprintf("unknown signature '%v' for read", signature)
+		panic(msg)
+	}
+}
+
 // cachedMetadata caches an entry's Metadata method
 func cachedMetadata(ctx context.Context, e Entry) (JSONObject, error) {
-	cachedMetadata, err := cachedDefaultOp(ctx, MetadataOp, e, func() (interface{}, error) {
+	cachedMetadata, err := cachedDefaultOp(ctx, MetadataOp, e, func() (meta interface{}, err error) {
+		done := recordCall(e, "Metadata")
+		defer func() { done(err) }()
 		return e.Metadata(ctx)
 	})
 
@@ -339,10 +459,17 @@ func cachedOp(ctx context.Context, opName string, entry Entry, ttl time.Duration
 		}
 	}
 
-	if ttl < 0 {
+	// A panicking plugin only fails the current operation. Note that the resulting
+	// error's cached like any other error.
+	callOp := func() (result interface{}, err error) {
+		defer recoverPanic(ctx, entry, opName, &err)
 		return op()
 	}
 
+	if ttl < 0 {
+		return callOp()
+	}
+
 	if entry.eb().id == "" {
 		// Try to set the ID based on parent ID
 		if obj := ctx.Value(parentID); obj != nil {
@@ -352,7 +479,13 @@ func cachedOp(ctx context.Context, opName string, entry Entry, ttl time.Duration
 		}
 	}
 
-	return cache.GetOrUpdate(opName, entry.eb().id, ttl, false, op)
+	return cache.GetOrUpdate(cacheCategory(ctx, opName), entry.eb().id, ttl, false, func() (interface{}, error) {
+		activity.Debugf(ctx, "Cache miss for %v on %v, calling the plugin", opName, entry.eb().id)
+		start := time.Now()
+		result, err := callOp()
+		activity.Debugf(ctx, "%v on %v took %v", opName, entry.eb().id, time.Since(start))
+		return result, err
+	})
 }
 
 func setChildID(parentID string, child Entry) {
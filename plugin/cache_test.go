@@ -8,7 +8,10 @@ import (
 	"time"
 
 	"github.com/emirpasic/gods/maps/linkedhashmap"
+	"github.com/puppetlabs/wash/datastore"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -102,6 +105,12 @@ func (suite *CacheTestSuite) TestAllOpKeysIncludingChildrenRegex() {
 	suite.Regexp(rx, "Test::/foo*[]")
 	suite.Regexp(rx, "Test::/foo*[]/bar(")
 	suite.Regexp(rx, "Test::/foo*[]/bar(/baz)")
+
+	// Test that it matches every namespace
+	rx = allOpKeysIncludingChildrenRegex("/a")
+	suite.Regexp(rx, "alice@Test::/a")
+	suite.Regexp(rx, "token.ci-1@Test::/a/b")
+	suite.NotRegexp(rx, "alice@Test::/ab")
 }
 
 func (suite *CacheTestSuite) TestOpKeyRegex() {
@@ -135,6 +144,12 @@ func (suite *CacheTestSuite) TestOpKeyRegex() {
 	suite.NotRegexp(rx, "TestOther::/foo*[]")
 	suite.NotRegexp(rx, "List::/foo*[]")
 	suite.NotRegexp(rx, "Test::/foo*[]/a")
+
+	// Test that it matches every namespace
+	rx = opKeyRegex("Test", "/a")
+	suite.Regexp(rx, "alice@Test::/a")
+	suite.NotRegexp(rx, "alice@OtherTest::/a")
+	suite.NotRegexp(rx, "alice@Test::/a/b")
 }
 
 func (suite *CacheTestSuite) TestClearCache() {
@@ -146,6 +161,17 @@ func (suite *CacheTestSuite) TestClearCache() {
 	suite.Equal([]string{path}, deleted)
 }
 
+func (suite *CacheTestSuite) TestClearOpsFor() {
+	rx := allOpKeysRegex("/a/b")
+	suite.Regexp(rx, "List::/a/b")
+	suite.Regexp(rx, "alice@Metadata::/a/b")
+	suite.NotRegexp(rx, "List::/a/b/c")
+	suite.NotRegexp(rx, "List::/a")
+
+	suite.cache.On("Delete", rx).Return([]string{"List::/a/b"})
+	suite.Equal([]string{"List::/a/b"}, ClearOpsFor("/a/b"))
+}
+
 // Creates an EntryMap with a mock entry for "child"
 func mockEntryMap(child string, prefetched bool) *EntryMap {
 	entry := newCacheTestsMockEntry(child)
@@ -277,19 +303,21 @@ func (suite *CacheTestSuite) TestCachedOp() {
 	suite.cache.AssertCalled(suite.T(), "GetOrUpdate", opName, entry.eb().id, opTTL, false, mock.MatchedBy(generateValueMatcher))
 }
 
-func (suite *CacheTestSuite) TestDuplicateCNameErr() {
-	err := DuplicateCNameErr{
-		ParentID:                 "/my_plugin/foo",
-		FirstChildName:           "foo/bar/",
-		FirstChildSlashReplacer:  '#',
-		SecondChildName:          "foo#bar/",
-		SecondChildSlashReplacer: '#',
-		CName:                    "foo#bar#",
+func (suite *CacheTestSuite) TestCachedOp_WithCacheNamespace() {
+	entry := newCacheTestsMockEntry("mock")
+	entry.SetTestID("id")
+	opTTL := 5 * time.Second
+	op := func() (interface{}, error) { return "result", nil }
+	generateValueMatcher := suite.makeGenerateValueMatcher("result")
+	suite.cache.On("GetOrUpdate", "alice@Op", entry.eb().id, opTTL, false, mock.MatchedBy(generateValueMatcher)).Return("result", nil).Once()
+	ctx := WithCacheNamespace(context.Background(), "alice")
+	v, err := CachedOp(ctx, "Op", entry, opTTL, op)
+	if suite.NoError(err) {
+		suite.Equal("result", v)
 	}
+	suite.cache.AssertExpectations(suite.T())
 
-	suite.Regexp("listing /my_plugin/foo", err.Error())
-	suite.Regexp("foo/bar/.*foo#bar/.*foo#bar#", err.Error())
-	suite.Regexp("my_plugin plugin", err.Error())
+	suite.Panics(func() { WithCacheNamespace(context.Background(), "a@b") })
 }
 
 func (suite *CacheTestSuite) testCachedDefaultOp(
@@ -360,31 +388,30 @@ func (suite *CacheTestSuite) TestCachedListDefaultOp() {
 	})
 }
 
-func (suite *CacheTestSuite) TestCachedListCNameErrors() {
+func (suite *CacheTestSuite) TestCachedListDisambiguatesCNames() {
 	ctx := context.Background()
 	entry := newCacheTestsMockEntry("foo")
 	entry.DisableDefaultCaching()
 	entry.SetTestID("/my_plugin/foo")
 
-	// Test that CachedList returns an error if two children have the same
-	// cname
-	child1 := newCacheTestsMockEntry("foo/bar/")
-	child2 := newCacheTestsMockEntry("foo#bar/")
-	child3 := newCacheTestsMockEntry("baz")
-	mockChildren := []Entry{child1, child2, child3}
+	// Test that CachedList disambiguates children with the same cname
+	child1 := newCacheTestsMockEntry("foo")
+	child1.SetPartialMetadata(map[string]interface{}{"id": "b"})
+	child2 := newCacheTestsMockEntry("foo")
+	child2.SetPartialMetadata(map[string]interface{}{"id": "a"})
+	child3 := newCacheTestsMockEntry("foo~1")
+	child4 := newCacheTestsMockEntry("baz")
+	mockChildren := []Entry{child1, child2, child3, child4}
 	entry.On("List", mock.Anything).Return(mockChildren, nil).Once()
-	_, err := cachedList(ctx, entry)
-	if suite.Error(err) {
-		expectedErr := DuplicateCNameErr{
-			ParentID:                 "/my_plugin/foo",
-			FirstChildName:           "foo/bar/",
-			FirstChildSlashReplacer:  '#',
-			SecondChildName:          "foo#bar/",
-			SecondChildSlashReplacer: '#',
-			CName:                    "foo#bar#",
-		}
-
-		suite.Equal(expectedErr, err)
+	children, err := cachedList(ctx, entry)
+	if suite.NoError(err) {
+		suite.Equal(map[string]Entry{
+			"foo":   child2,
+			"foo~1": child3,
+			"foo~2": child1,
+			"baz":   child4,
+		}, children.mp)
+		suite.Equal("/my_plugin/foo/foo~2", child1.eb().id)
 	}
 }
 
@@ -404,7 +431,7 @@ func (suite *CacheTestSuite) TestCachedListSetEntryID() {
 	children, err := cachedList(ctx, entry)
 	if suite.NoError(err) {
 		if suite.Equal(toMap(mockChildren), children.mp) {
-			suite.Equal("/foo#child1", children.mp["foo#child1"].eb().id)
+			suite.Equal("/foo%2Fchild1", children.mp["foo%2Fchild1"].eb().id)
 			suite.Equal("/child2", children.mp["child2"].eb().id)
 		}
 	}
@@ -417,7 +444,7 @@ func (suite *CacheTestSuite) TestCachedListSetEntryID() {
 	children, err = cachedList(ctx, entry)
 	if suite.NoError(err) {
 		if suite.Equal(toMap(mockChildren), children.mp) {
-			suite.Equal("/parent/foo#child1", children.mp["foo#child1"].eb().id)
+			suite.Equal("/parent/foo%2Fchild1", children.mp["foo%2Fchild1"].eb().id)
 			suite.Equal("/parent/child2", children.mp["child2"].eb().id)
 		}
 	}
@@ -582,3 +609,33 @@ func (suite *CacheTestSuite) TestSplitID() {
 func TestCache(t *testing.T) {
 	suite.Run(t, new(CacheTestSuite))
 }
+
+func TestMetadataWithCacheInfo(t *testing.T) {
+	SetTestCache(datastore.NewMemCache())
+	defer UnsetTestCache()
+
+	parent := &mockParent{NewEntry("root"), []Entry{newMockEntry("foo")}}
+	parent.SetTestID("/root")
+	parent.SetPartialMetadata(JSONObject{"key": "value"})
+
+	meta, err := MetadataWithCacheInfo(context.Background(), parent)
+	require.NoError(t, err)
+	assert.Equal(t, "value", meta["key"])
+	cacheInfo := meta[WashMetadataKey].(map[string]interface{})["cache"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"source": "live"}, cacheInfo["list"])
+	assert.NotContains(t, cacheInfo, "read")
+	assert.Equal(t, "live", cacheInfo["metadata"].(map[string]interface{})["source"])
+
+	_, err = List(context.Background(), parent)
+	require.NoError(t, err)
+	meta, err = MetadataWithCacheInfo(context.Background(), parent)
+	require.NoError(t, err)
+	cacheInfo = meta[WashMetadataKey].(map[string]interface{})["cache"].(map[string]interface{})
+	for _, action := range []string{"list", "metadata"} {
+		info := cacheInfo[action].(map[string]interface{})
+		assert.Equal(t, "cache", info["source"], action)
+		assert.Contains(t, info, "age")
+		assert.Contains(t, info, "expires_in")
+		assert.Contains(t, info, "last_refresh_duration")
+	}
+}
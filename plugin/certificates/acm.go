@@ -0,0 +1,183 @@
+package certificates
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	awsSDK "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/acm"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// acmRegions lists the regions whose AWS Certificate Manager certificates are
+// included.
+type acmRegions struct {
+	plugin.EntryBase
+	session *session.Session
+	regions []string
+}
+
+func newACMRegions(sess *session.Session, regions []string) *acmRegions {
+	a := &acmRegions{
+		EntryBase: plugin.NewEntry("acm"),
+	}
+	a.session = sess
+	a.regions = regions
+	return a
+}
+
+func (a *acmRegions) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(a, "acm").
+		SetDescription(acmRegionsDescription).
+		IsSingleton()
+}
+
+func (a *acmRegions) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&acmRegion{}).Schema(),
+	}
+}
+
+func (a *acmRegions) List(ctx context.Context) ([]plugin.Entry, error) {
+	regions := a.regions
+	if len(regions) == 0 {
+		region := awsSDK.StringValue(a.session.Config.Region)
+		if region == "" {
+			return nil, fmt.Errorf("no AWS region is configured; set the certificates.acm_regions config or the AWS_REGION environment variable")
+		}
+		regions = []string{region}
+	}
+	entries := make([]plugin.Entry, len(regions))
+	for i, region := range regions {
+		entries[i] = newACMRegion(a.session, region)
+	}
+	return entries, nil
+}
+
+// acmRegion lists the ACM certificates in a region.
+type acmRegion struct {
+	plugin.EntryBase
+	client *acm.ACM
+}
+
+func newACMRegion(sess *session.Session, region string) *acmRegion {
+	r := &acmRegion{
+		EntryBase: plugin.NewEntry(region),
+	}
+	r.client = acm.New(sess, awsSDK.NewConfig().WithRegion(region))
+	return r
+}
+
+func (r *acmRegion) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(r, "region").
+		SetDescription(acmRegionDescription)
+}
+
+func (r *acmRegion) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&acmCertificate{}).Schema(),
+	}
+}
+
+func (r *acmRegion) List(ctx context.Context) ([]plugin.Entry, error) {
+	// ListCertificates only includes RSA 2048 certificates unless other key
+	// types are requested.
+	input := &acm.ListCertificatesInput{
+		Includes: &acm.Filters{KeyTypes: awsSDK.StringSlice(acm.KeyAlgorithm_Values())},
+	}
+	var entries []plugin.Entry
+	err := r.client.ListCertificatesPagesWithContext(ctx, input, func(page *acm.ListCertificatesOutput, lastPage bool) bool {
+		for _, summary := range page.CertificateSummaryList {
+			entries = append(entries, newACMCertificate(r.client, summary))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// acmCertificate is an ACM certificate. It's named after its domain name and
+// ID since a domain can have several certificates.
+type acmCertificate struct {
+	plugin.EntryBase
+	client *acm.ACM
+	arn    string
+}
+
+func newACMCertificate(client *acm.ACM, summary *acm.CertificateSummary) *acmCertificate {
+	certArn := awsSDK.StringValue(summary.CertificateArn)
+	id := certArn
+	if parsed, err := arn.Parse(certArn); err == nil {
+		id = strings.TrimPrefix(parsed.Resource, "certificate/")
+	}
+	name := awsSDK.StringValue(summary.DomainName) + "_" + id
+
+	c := &acmCertificate{
+		EntryBase: plugin.NewEntry(name),
+	}
+	c.client = client
+	c.arn = certArn
+	certificateInfo{
+		source:   "acm",
+		notAfter: awsSDK.TimeValue(summary.NotAfter),
+		subject:  awsSDK.StringValue(summary.DomainName),
+		dnsNames: awsSDK.StringValueSlice(summary.SubjectAlternativeNameSummaries),
+	}.setAttributes(c.Attributes())
+	if summary.CreatedAt != nil {
+		c.Attributes().SetCrtime(*summary.CreatedAt)
+	}
+	c.SetPartialMetadata(summary)
+	return c
+}
+
+func (c *acmCertificate) Schema() *plugin.EntrySchema {
+	return certificateSchema(c).
+		SetPartialMetadataSchema(acm.CertificateSummary{}).
+		SetMetadataSchema(acm.CertificateDetail{})
+}
+
+// Read returns the certificate and its chain. ACM only returns issued
+// certificates.
+func (c *acmCertificate) Read(ctx context.Context) ([]byte, error) {
+	resp, err := c.client.GetCertificateWithContext(ctx, &acm.GetCertificateInput{
+		CertificateArn: awsSDK.String(c.arn),
+	})
+	if err != nil {
+		return nil, err
+	}
+	content := awsSDK.StringValue(resp.Certificate)
+	if chain := awsSDK.StringValue(resp.CertificateChain); chain != "" {
+		content = strings.TrimRight(content, "\n") + "\n" + chain
+	}
+	return []byte(content), nil
+}
+
+// Metadata returns the certificate's details, which include its issuer and
+// renewal status.
+func (c *acmCertificate) Metadata(ctx context.Context) (plugin.JSONObject, error) {
+	resp, err := c.client.DescribeCertificateWithContext(ctx, &acm.DescribeCertificateInput{
+		CertificateArn: awsSDK.String(c.arn),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return plugin.ToJSONObject(resp.Certificate), nil
+}
+
+const acmRegionsDescription = `
+This lists the regions whose AWS Certificate Manager certificates are included.
+They're set by the certificates.acm_regions config, and default to the AWS
+profile's region.
+`
+
+const acmRegionDescription = `
+This is an AWS region. It lists the region's AWS Certificate Manager
+certificates, which are named <domain>_<certificate ID>.
+`
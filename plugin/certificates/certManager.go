@@ -0,0 +1,143 @@
+package certificates
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/puppetlabs/wash/plugin"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	k8s "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+var certManagerCertificates = schema.GroupVersionResource{
+	Group:    "cert-manager.io",
+	Version:  "v1",
+	Resource: "certificates",
+}
+
+// certManager lists the cert-manager Certificate resources in every namespace
+// of a Kubernetes cluster.
+type certManager struct {
+	plugin.EntryBase
+	kubeContext string
+}
+
+func newCertManager(kubeContext string) *certManager {
+	c := &certManager{
+		EntryBase: plugin.NewEntry("cert-manager"),
+	}
+	c.kubeContext = kubeContext
+	return c
+}
+
+func (c *certManager) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(c, "cert-manager").
+		SetDescription(certManagerDescription).
+		IsSingleton()
+}
+
+func (c *certManager) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&certManagerCertificate{}).Schema(),
+	}
+}
+
+// List lists the cluster's Certificates. They're named <namespace>/<name>.
+func (c *certManager) List(ctx context.Context) ([]plugin.Entry, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: c.kubeContext}
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	clientset, err := k8s.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := dynamicClient.Resource(certManagerCertificates).Namespace(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("could not list cert-manager certificates (is cert-manager installed?): %v", err)
+	}
+	sort.Slice(list.Items, func(i, j int) bool {
+		a, b := list.Items[i], list.Items[j]
+		if a.GetNamespace() != b.GetNamespace() {
+			return a.GetNamespace() < b.GetNamespace()
+		}
+		return a.GetName() < b.GetName()
+	})
+	entries := make([]plugin.Entry, len(list.Items))
+	for i, item := range list.Items {
+		entries[i] = newCertManagerCertificate(clientset, item)
+	}
+	return entries, nil
+}
+
+// certManagerCertificate is a cert-manager Certificate. Its content is the
+// certificate in the Certificate's secret.
+type certManagerCertificate struct {
+	plugin.EntryBase
+	clientset  *k8s.Clientset
+	namespace  string
+	secretName string
+}
+
+func newCertManagerCertificate(clientset *k8s.Clientset, obj unstructured.Unstructured) *certManagerCertificate {
+	c := &certManagerCertificate{
+		EntryBase: plugin.NewEntry(obj.GetNamespace() + "/" + obj.GetName()),
+	}
+	c.clientset = clientset
+	c.namespace = obj.GetNamespace()
+	c.secretName, _, _ = unstructured.NestedString(obj.Object, "spec", "secretName")
+
+	info := certificateInfo{source: "cert-manager"}
+	if notAfter, _, _ := unstructured.NestedString(obj.Object, "status", "notAfter"); notAfter != "" {
+		if t, err := time.Parse(time.RFC3339, notAfter); err == nil {
+			info.notAfter = t
+		}
+	}
+	info.subject, _, _ = unstructured.NestedString(obj.Object, "spec", "commonName")
+	info.issuer, _, _ = unstructured.NestedString(obj.Object, "spec", "issuerRef", "name")
+	info.dnsNames, _, _ = unstructured.NestedStringSlice(obj.Object, "spec", "dnsNames")
+	if info.subject == "" && len(info.dnsNames) > 0 {
+		info.subject = info.dnsNames[0]
+	}
+	info.setAttributes(c.Attributes())
+	c.Attributes().SetCrtime(obj.GetCreationTimestamp().Time)
+	c.SetPartialMetadata(obj.Object)
+	return c
+}
+
+func (c *certManagerCertificate) Schema() *plugin.EntrySchema {
+	return certificateSchema(c)
+}
+
+// Read returns the tls.crt of the Certificate's secret, which only exists
+// once the certificate's been issued.
+func (c *certManagerCertificate) Read(ctx context.Context) ([]byte, error) {
+	if c.secretName == "" {
+		return nil, fmt.Errorf("the certificate doesn't have a secret")
+	}
+	secret, err := c.clientset.CoreV1().Secrets(c.namespace).Get(ctx, c.secretName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return secret.Data["tls.crt"], nil
+}
+
+const certManagerDescription = `
+This lists the cert-manager Certificate resources in every namespace of the
+current Kubernetes context's cluster. Set the certificates.kube_context config
+to use a different context. The certificates are named <namespace>/<name>.
+`
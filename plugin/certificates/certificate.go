@@ -0,0 +1,69 @@
+package certificates
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// certificateInfo is what's known about a certificate when it's listed. Each
+// source fills in what it can.
+type certificateInfo struct {
+	source   string
+	notAfter time.Time
+	subject  string
+	issuer   string
+	dnsNames []string
+}
+
+// setAttributes sets the certificate's custom attributes. Each source's
+// certificate has the same custom attributes so that they can be queried
+// together.
+func (info certificateInfo) setAttributes(attr *plugin.EntryAttributes) {
+	attr.SetCustom("source", info.source)
+	if !info.notAfter.IsZero() {
+		attr.
+			SetCustom("expires", info.notAfter).
+			SetCustom("days_to_expiry", daysUntil(info.notAfter, time.Now()))
+	}
+	if info.subject != "" {
+		attr.SetCustom("subject", info.subject)
+	}
+	if info.issuer != "" {
+		attr.SetCustom("issuer", info.issuer)
+	}
+	if len(info.dnsNames) > 0 {
+		attr.SetCustom("dns_names", strings.Join(info.dnsNames, ","))
+	}
+}
+
+// certificateSchema returns the schema that's shared by each source's
+// certificates.
+func certificateSchema(e plugin.Entry) *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(e, "certificate").
+		SetDescription(certificateDescription).
+		AddCustomAttribute("source", plugin.StringAttribute, "Where the certificate's from, one of local, acm or cert-manager").
+		AddCustomAttribute("expires", plugin.TimeAttribute, "When the certificate expires").
+		AddCustomAttribute("days_to_expiry", plugin.NumberAttribute, "The number of whole days until the certificate expires when it was listed. It's negative for expired certificates").
+		AddCustomAttribute("subject", plugin.StringAttribute, "The certificate's subject or primary domain name").
+		AddCustomAttribute("issuer", plugin.StringAttribute, "The certificate's issuer").
+		AddCustomAttribute("dns_names", plugin.StringAttribute, "The certificate's DNS names, separated by commas")
+}
+
+// daysUntil returns the number of whole days from now until t. It's rounded
+// down so that a certificate that expires in 12 hours has 0 days left.
+func daysUntil(t time.Time, now time.Time) int64 {
+	return int64(math.Floor(t.Sub(now).Hours() / 24))
+}
+
+const certificateDescription = `
+This is a certificate. Its content is the certificate's PEM-encoded chain, and
+its metadata is what its source knows about it. Its expiry is in the expires and
+days_to_expiry custom attributes, so the certificates that expire within 30 days
+are found across every source with the RQL query
+
+["attr", "days_to_expiry", ["number", ["<", 30]]]
+`
@@ -0,0 +1,218 @@
+package certificates
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// pemExtensions are the extensions of the files in a local directory that are
+// checked for certificates.
+var pemExtensions = map[string]bool{
+	".pem": true,
+	".crt": true,
+	".cer": true,
+}
+
+// localDirs lists the configured local directories.
+type localDirs struct {
+	plugin.EntryBase
+	dirs []string
+}
+
+func newLocalDirs(dirs []string) *localDirs {
+	l := &localDirs{
+		EntryBase: plugin.NewEntry("local"),
+	}
+	l.dirs = dirs
+	return l
+}
+
+func (l *localDirs) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(l, "local").
+		SetDescription(localDirsDescription).
+		IsSingleton()
+}
+
+func (l *localDirs) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&localDir{}).Schema(),
+	}
+}
+
+func (l *localDirs) List(ctx context.Context) ([]plugin.Entry, error) {
+	entries := make([]plugin.Entry, len(l.dirs))
+	for i, dir := range l.dirs {
+		entries[i] = newLocalDir(dir)
+	}
+	return entries, nil
+}
+
+// localDir is a local directory of PEM files. It's named after its path.
+type localDir struct {
+	plugin.EntryBase
+	path string
+}
+
+func newLocalDir(path string) *localDir {
+	d := &localDir{
+		EntryBase: plugin.NewEntry(path),
+	}
+	d.path = path
+	return d
+}
+
+func (d *localDir) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(d, "dir").
+		SetDescription(localDirDescription)
+}
+
+func (d *localDir) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&localCertificate{}).Schema(),
+	}
+}
+
+// List lists the PEM files in the directory that contain a certificate. Files
+// that can't be read or parsed are skipped.
+func (d *localDir) List(ctx context.Context) ([]plugin.Entry, error) {
+	files, err := ioutil.ReadDir(d.path)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Name() < files[j].Name()
+	})
+
+	entries := make([]plugin.Entry, 0, len(files))
+	for _, file := range files {
+		if file.IsDir() || !pemExtensions[strings.ToLower(filepath.Ext(file.Name()))] {
+			continue
+		}
+		path := filepath.Join(d.path, file.Name())
+		cert, err := newLocalCertificate(path, file)
+		if err != nil {
+			activity.Record(ctx, "certificates: skipping %v: %v", path, err)
+			continue
+		}
+		entries = append(entries, cert)
+	}
+	return entries, nil
+}
+
+// localCertificateMetadata is a local certificate's partial metadata.
+type localCertificateMetadata struct {
+	Path         string    `json:"path"`
+	Subject      string    `json:"subject"`
+	Issuer       string    `json:"issuer"`
+	DNSNames     []string  `json:"dns_names,omitempty"`
+	SerialNumber string    `json:"serial_number"`
+	NotBefore    time.Time `json:"not_before"`
+	NotAfter     time.Time `json:"not_after"`
+	// ChainLength is the number of certificates in the file.
+	ChainLength int `json:"chain_length"`
+}
+
+// localCertificate is a PEM file. Its content is the file's content.
+type localCertificate struct {
+	plugin.EntryBase
+	path string
+}
+
+func newLocalCertificate(path string, file os.FileInfo) (*localCertificate, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	chain, err := parsePEMCertificates(content)
+	if err != nil {
+		return nil, err
+	}
+	leaf := chain[0]
+
+	cert := &localCertificate{
+		EntryBase: plugin.NewEntry(file.Name()),
+	}
+	cert.path = path
+	certificateInfo{
+		source:   "local",
+		notAfter: leaf.NotAfter,
+		subject:  leaf.Subject.String(),
+		issuer:   leaf.Issuer.String(),
+		dnsNames: leaf.DNSNames,
+	}.setAttributes(cert.Attributes())
+	cert.Attributes().
+		SetCrtime(leaf.NotBefore).
+		SetMtime(file.ModTime()).
+		SetSize(uint64(file.Size())).
+		SetMode(file.Mode())
+	cert.SetPartialMetadata(localCertificateMetadata{
+		Path:         path,
+		Subject:      leaf.Subject.String(),
+		Issuer:       leaf.Issuer.String(),
+		DNSNames:     leaf.DNSNames,
+		SerialNumber: leaf.SerialNumber.String(),
+		NotBefore:    leaf.NotBefore,
+		NotAfter:     leaf.NotAfter,
+		ChainLength:  len(chain),
+	})
+	return cert, nil
+}
+
+func (c *localCertificate) Schema() *plugin.EntrySchema {
+	return certificateSchema(c).
+		SetPartialMetadataSchema(localCertificateMetadata{})
+}
+
+func (c *localCertificate) Read(ctx context.Context) ([]byte, error) {
+	return ioutil.ReadFile(c.path)
+}
+
+// parsePEMCertificates parses the certificates in content. The first one's
+// the leaf. It returns an error if there aren't any, e.g. because content is a
+// private key.
+func parsePEMCertificates(content []byte) ([]*x509.Certificate, error) {
+	var chain []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, content = pem.Decode(content)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, cert)
+	}
+	if len(chain) == 0 {
+		return nil, fmt.Errorf("no PEM-encoded certificates found")
+	}
+	return chain, nil
+}
+
+const localDirsDescription = `
+This lists the local directories of PEM files that are set by the
+certificates.dirs config.
+`
+
+const localDirDescription = `
+This is a local directory of PEM files. It lists the .pem, .crt and .cer files
+that contain a certificate. A file's first certificate is its leaf, whose expiry
+and names are the file's attributes.
+`
@@ -0,0 +1,86 @@
+package certificates
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func selfSignedPEM(t *testing.T, name string, notAfter time.Time) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name, "www." + name},
+		NotBefore:    notAfter.Add(-90 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestParsePEMCertificates(t *testing.T) {
+	leaf := selfSignedPEM(t, "example.com", time.Now().Add(24*time.Hour))
+	ca := selfSignedPEM(t, "ca.example.com", time.Now().Add(365*24*time.Hour))
+	key := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: []byte("key")})
+
+	chain, err := parsePEMCertificates(append(append(key, leaf...), ca...))
+	require.NoError(t, err)
+	require.Len(t, chain, 2)
+	assert.Equal(t, "example.com", chain[0].Subject.CommonName)
+	assert.Equal(t, "ca.example.com", chain[1].Subject.CommonName)
+
+	_, err = parsePEMCertificates(key)
+	assert.EqualError(t, err, "no PEM-encoded certificates found")
+}
+
+func TestLocalDirList(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wash-certificates")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	notAfter := time.Now().Add(10*24*time.Hour + time.Hour).Truncate(time.Second)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "web.pem"), selfSignedPEM(t, "example.com", notAfter), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "web.key"), []byte("not a certificate"), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "broken.crt"), []byte("not a certificate"), 0644))
+
+	entries, err := newLocalDir(dir).List(context.Background())
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	cert := entries[0].(*localCertificate)
+	assert.Equal(t, "web.pem", plugin.Name(cert))
+
+	custom := cert.Attributes().CustomAttributes()
+	assert.Equal(t, "local", custom["source"])
+	assert.True(t, notAfter.Equal(custom["expires"].(time.Time)))
+	assert.Equal(t, float64(10), custom["days_to_expiry"])
+	assert.Equal(t, "CN=example.com", custom["subject"])
+	assert.Equal(t, "example.com,www.example.com", custom["dns_names"])
+
+	content, err := cert.Read(context.Background())
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "BEGIN CERTIFICATE")
+}
+
+func TestDaysUntil(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, int64(30), daysUntil(now.Add(30*24*time.Hour), now))
+	assert.Equal(t, int64(0), daysUntil(now.Add(12*time.Hour), now))
+	assert.Equal(t, int64(-1), daysUntil(now.Add(-12*time.Hour), now))
+}
@@ -0,0 +1,143 @@
+// Package certificates presents a filesystem hierarchy for certificates from
+// cert-manager, AWS Certificate Manager and local directories of PEM files.
+//
+// Each certificate has the same custom attributes, like its expiry, regardless
+// of its source. That lets a single RQL query find the certificates that are
+// about to expire everywhere.
+package certificates
+
+import (
+	"context"
+	"fmt"
+
+	awsSDK "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// Root of the certificates plugin
+type Root struct {
+	plugin.EntryBase
+	dirs        []string
+	acmRegions  []string
+	kubeContext string
+	session     *session.Session
+}
+
+// Init for root
+func (r *Root) Init(cfg map[string]interface{}) error {
+	var err error
+	if r.dirs, err = stringSliceConfig(cfg, "dirs"); err != nil {
+		return err
+	}
+	if r.acmRegions, err = stringSliceConfig(cfg, "acm_regions"); err != nil {
+		return err
+	}
+	if kubeContextI, ok := cfg["kube_context"]; ok {
+		kubeContext, ok := kubeContextI.(string)
+		if !ok {
+			return fmt.Errorf("certificates.kube_context config must be a string, not %v", kubeContextI)
+		}
+		r.kubeContext = kubeContext
+	}
+	profile := ""
+	if profileI, ok := cfg["aws_profile"]; ok {
+		if profile, ok = profileI.(string); !ok {
+			return fmt.Errorf("certificates.aws_profile config must be a string, not %v", profileI)
+		}
+	}
+
+	// Creating the session doesn't contact AWS, so it doesn't fail if the user
+	// doesn't use it.
+	r.session, err = session.NewSessionWithOptions(session.Options{
+		Profile:           profile,
+		SharedConfigState: session.SharedConfigEnable,
+		Config:            awsSDK.Config{HTTPClient: plugin.HTTPClient()},
+	})
+	if err != nil {
+		return err
+	}
+
+	r.EntryBase = plugin.NewEntry("certificates")
+	return nil
+}
+
+func stringSliceConfig(cfg map[string]interface{}, key string) ([]string, error) {
+	valueI, ok := cfg[key]
+	if !ok {
+		return nil, nil
+	}
+	arr, ok := valueI.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("certificates.%v config must be an array of strings, not %v", key, valueI)
+	}
+	values := make([]string, len(arr))
+	for i, elem := range arr {
+		value, ok := elem.(string)
+		if !ok {
+			return nil, fmt.Errorf("certificates.%v config must be an array of strings, not %v", key, valueI)
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// Schema returns the root's schema
+func (r *Root) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(r, "certificates").
+		SetDescription(rootDescription).
+		IsSingleton()
+}
+
+// ChildSchemas returns the root's child schema
+func (r *Root) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&localDirs{}).Schema(),
+		(&acmRegions{}).Schema(),
+		(&certManager{}).Schema(),
+	}
+}
+
+// List lists the sources. The local directories are only listed if they're
+// configured.
+func (r *Root) List(ctx context.Context) ([]plugin.Entry, error) {
+	var entries []plugin.Entry
+	if len(r.dirs) > 0 {
+		entries = append(entries, newLocalDirs(r.dirs))
+	}
+	entries = append(entries, newACMRegions(r.session, r.acmRegions), newCertManager(r.kubeContext))
+	return entries, nil
+}
+
+const rootDescription = `
+This is the certificates plugin root. It gathers certificates from several
+sources into one tree:
+
+* local lists the PEM files in the directories set by the certificates.dirs
+  config
+* acm lists the AWS Certificate Manager certificates in the regions set by the
+  certificates.acm_regions config, which default to the AWS profile's region
+* cert-manager lists the cert-manager Certificates in the current Kubernetes
+  context's cluster
+
+Every certificate has the same custom attributes, so the certificates that
+expire within 30 days are found across every source with the RQL query
+
+["attr", "days_to_expiry", ["number", ["<", 30]]]
+
+The plugin's config looks like
+
+certificates:
+  dirs:
+  - /etc/ssl/private
+  acm_regions:
+  - us-east-1
+  - eu-west-1
+  aws_profile: prod
+  kube_context: prod-cluster
+
+The AWS profile defaults to the AWS_PROFILE environment variable or the default
+profile, and the Kubernetes context defaults to the kubeconfig's current
+context.
+`
@@ -0,0 +1,151 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/puppetlabs/wash/activity"
+)
+
+// OriginalNameKey is the metadata key that holds an entry's original name when
+// its cname differs from its name, i.e. when the name was escaped or its cname was
+// disambiguated from a sibling's. See plugin.CName for more details.
+const OriginalNameKey = "wash_original_name"
+
+// caseInsensitiveCNames is true if cnames that only differ by case collide. This
+// is the case on macOS, whose filesystems and tools are usually case-insensitive.
+var caseInsensitiveCNames = runtime.GOOS == "darwin"
+
+// EscapeName returns the cname of an entry with the name and slash replacer.
+// The characters that are illegal in a filename ('/' and NUL) are
+// percent-encoded, as is '%' so that the escape can be reversed. If the slash
+// replacer isn't 0, then '/' is replaced by it instead and it's percent-encoded
+// wherever else it appears. The "." and ".." names are also percent-encoded
+// because they refer to the current and parent directories.
+func EscapeName(name string, slashReplacer rune) string {
+	if name == "." || name == ".." {
+		return strings.Repeat("%2E", len(name))
+	}
+	var replacer string
+	if slashReplacer != 0 {
+		replacer = string(slashReplacer)
+	}
+	var b strings.Builder
+	for i := 0; i < len(name); {
+		switch c := name[i]; {
+		case c == '/' && replacer != "":
+			b.WriteString(replacer)
+			i++
+		case c == '/' || c == 0 || c == '%':
+			fmt.Fprintf(&b, "%%%02X", c)
+			i++
+		case replacer != "" && strings.HasPrefix(name[i:], replacer):
+			for j := 0; j < len(replacer); j++ {
+				fmt.Fprintf(&b, "%%%02X", replacer[j])
+			}
+			i += len(replacer)
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return b.String()
+}
+
+func cnameKey(cname string) string {
+	if caseInsensitiveCNames {
+		return strings.ToLower(cname)
+	}
+	return cname
+}
+
+// assignCNames ensures that the siblings' cnames are unique so that none of them
+// shadow the others. When several siblings have the same cname, the sibling whose
+// name didn't need escaping keeps it (ties are broken by name). The others are
+// disambiguated by appending "~<n>" to their cnames. Siblings with the same name
+// are ordered by their partial metadata so that their suffixes don't depend on
+// the order that the plugin listed them in.
+func assignCNames(ctx context.Context, parent Parent, siblings []Entry) {
+	// Siblings may be returned by several List calls, so start from their escaped
+	// names.
+	for _, sibling := range siblings {
+		sibling.eb().cname = ""
+	}
+
+	groups := make(map[string][]Entry)
+	var keys []string
+	for _, sibling := range siblings {
+		key := cnameKey(CName(sibling))
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], sibling)
+	}
+
+	sort.Strings(keys)
+	for _, key := range keys {
+		group := groups[key]
+		if len(group) < 2 {
+			continue
+		}
+		sort.SliceStable(group, func(i, j int) bool {
+			iEscaped := Name(group[i]) != CName(group[i])
+			jEscaped := Name(group[j]) != CName(group[j])
+			if iEscaped != jEscaped {
+				return !iEscaped
+			}
+			if Name(group[i]) != Name(group[j]) {
+				return Name(group[i]) < Name(group[j])
+			}
+			return sortKey(group[i]) < sortKey(group[j])
+		})
+		for _, sibling := range group[1:] {
+			base := CName(sibling)
+			for n := 1; ; n++ {
+				cname := fmt.Sprintf("%v~%v", base, n)
+				if _, ok := groups[cnameKey(cname)]; !ok {
+					groups[cnameKey(cname)] = []Entry{sibling}
+					sibling.eb().cname = cname
+					break
+				}
+			}
+			activity.Warnf(
+				ctx,
+				"%v and %v in %v have the same cname %v, so the latter's cname is now %v",
+				Name(group[0]),
+				Name(sibling),
+				parent.eb().id,
+				CName(group[0]),
+				CName(sibling),
+			)
+		}
+	}
+}
+
+// sortKey orders siblings with the same name. It's their partial metadata's JSON,
+// whose keys are sorted.
+func sortKey(e Entry) string {
+	b, err := json.Marshal(e.eb().partialMetadata())
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// withOriginalName adds e's original name to its metadata if e's cname differs
+// from its name.
+func withOriginalName(e Entry, meta JSONObject) JSONObject {
+	if Name(e) == CName(e) {
+		return meta
+	}
+	withName := make(JSONObject, len(meta)+1)
+	for k, v := range meta {
+		withName[k] = v
+	}
+	withName[OriginalNameKey] = Name(e)
+	return withName
+}
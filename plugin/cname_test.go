@@ -0,0 +1,125 @@
+package plugin
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEscapeName(t *testing.T) {
+	for name, expected := range map[string]string{
+		"foo":         "foo",
+		"foo/bar/":    "foo%2Fbar%2F",
+		"foo\x00bar":  "foo%00bar",
+		"foo%2Fbar":   "foo%252Fbar",
+		".":           "%2E",
+		"..":          "%2E%2E",
+		"...":         "...",
+		"./foo":       ".%2Ffoo",
+		"foo#bar.txt": "foo#bar.txt",
+	} {
+		assert.Equal(t, expected, EscapeName(name, 0), "escaping %q", name)
+	}
+
+	// The slash replacer is escaped wherever it isn't replacing a '/'.
+	for name, expected := range map[string]string{
+		"foo/bar":  "foo:bar",
+		"foo:bar":  "foo%3Abar",
+		"a/b∕c":    "a∕b%E2%88%95c",
+		"%/\x00":   "%25∕%00",
+		"foo∕/bar": "foo%E2%88%95∕bar",
+	} {
+		replacer := ':'
+		if strings.ContainsRune(expected, '∕') {
+			replacer = '∕'
+		}
+		assert.Equal(t, expected, EscapeName(name, replacer), "escaping %q", name)
+	}
+}
+
+func TestEscapeName_IsReversible(t *testing.T) {
+	names := []string{"foo/bar", "foo#bar", "foo%2Fbar", "foo\x00bar", "foo:bar", ".", "%2E", "..", "foo%bar"}
+	for _, replacer := range []rune{0, '#', ':'} {
+		cnames := make(map[string]string)
+		for _, name := range names {
+			cname := EscapeName(name, replacer)
+			if other, ok := cnames[cname]; ok {
+				t.Errorf("%q and %q have the same cname %q with replacer %q", name, other, cname, replacer)
+			}
+			cnames[cname] = name
+		}
+	}
+}
+
+func TestAssignCNames(t *testing.T) {
+	parent := newMockParent("parent")
+	foo, fooSlash, bar := newMockEntry("foo:bar"), newMockEntry("foo/bar"), newMockEntry("bar")
+	fooSlash.SetSlashReplacer(':')
+	assignCNames(context.Background(), parent, []Entry{fooSlash, foo, bar})
+	assert.Equal(t, "foo:bar", CName(foo))
+	assert.Equal(t, "foo:bar~1", CName(fooSlash))
+	assert.Equal(t, "bar", CName(bar))
+
+	// Disambiguated cnames are recomputed on the next List.
+	assignCNames(context.Background(), parent, []Entry{fooSlash, bar})
+	assert.Equal(t, "foo:bar", CName(fooSlash))
+
+	// A suffix that's taken by another sibling is skipped.
+	foo1 := newMockEntry("foo:bar~1")
+	assignCNames(context.Background(), parent, []Entry{fooSlash, foo, foo1})
+	assert.Equal(t, "foo:bar", CName(foo))
+	assert.Equal(t, "foo:bar~1", CName(foo1))
+	assert.Equal(t, "foo:bar~2", CName(fooSlash))
+}
+
+func TestAssignCNames_IsStable(t *testing.T) {
+	parent := newMockParent("parent")
+	first, second := newMockEntry("foo"), newMockEntry("foo")
+	first.SetPartialMetadata(map[string]interface{}{"id": "a"})
+	second.SetPartialMetadata(map[string]interface{}{"id": "b"})
+
+	// Siblings with the same name get the same suffixes whatever order
+	// they're listed in.
+	assignCNames(context.Background(), parent, []Entry{first, second})
+	assert.Equal(t, "foo", CName(first))
+	assert.Equal(t, "foo~1", CName(second))
+	assignCNames(context.Background(), parent, []Entry{second, first})
+	assert.Equal(t, "foo", CName(first))
+	assert.Equal(t, "foo~1", CName(second))
+}
+
+func TestAssignCNames_CaseInsensitive(t *testing.T) {
+	defer func(caseInsensitive bool) { caseInsensitiveCNames = caseInsensitive }(caseInsensitiveCNames)
+	parent := newMockParent("parent")
+	upper, lower := newMockEntry("README"), newMockEntry("readme")
+
+	caseInsensitiveCNames = false
+	assignCNames(context.Background(), parent, []Entry{upper, lower})
+	assert.Equal(t, "README", CName(upper))
+	assert.Equal(t, "readme", CName(lower))
+
+	caseInsensitiveCNames = true
+	assignCNames(context.Background(), parent, []Entry{lower, upper})
+	assert.Equal(t, "README", CName(upper))
+	assert.Equal(t, "readme~1", CName(lower))
+}
+
+func TestPartialMetadata_IncludesOriginalName(t *testing.T) {
+	e := newMockEntry("foo")
+	e.SetPartialMetadata(map[string]interface{}{"key": "value"})
+	assert.Equal(t, JSONObject{"key": "value"}, PartialMetadata(e))
+
+	e = newMockEntry("foo/bar")
+	e.SetPartialMetadata(map[string]interface{}{"key": "value"})
+	assert.Equal(t, JSONObject{"key": "value", OriginalNameKey: "foo/bar"}, PartialMetadata(e))
+	// The entry's own metadata is unchanged.
+	assert.Equal(t, JSONObject{"key": "value"}, e.partialMetadata())
+}
+
+func newMockParent(name string) *mockParent {
+	parent := &mockParent{EntryBase: NewEntry(name)}
+	parent.SetTestID("/" + name)
+	return parent
+}
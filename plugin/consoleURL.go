@@ -0,0 +1,23 @@
+package plugin
+
+import (
+	"text/template"
+)
+
+// ConsoleURLData is the data that an entry's console URL template is executed
+// with. See EntrySchema#SetConsoleURL.
+type ConsoleURLData struct {
+	Name  string
+	CName string
+	Path  string
+	// Attributes are the entry's custom attributes.
+	Attributes map[string]interface{}
+	// Metadata is the entry's full metadata.
+	Metadata JSONObject
+}
+
+// ParseConsoleURL parses an entry's console URL template. Referencing a
+// missing attribute or metadata key is an error when the template's executed.
+func ParseConsoleURL(text string) (*template.Template, error) {
+	return template.New("console_url").Option("missingkey=error").Parse(text)
+}
@@ -0,0 +1,226 @@
+package consul
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// kvStore is the root of the KV store. Unlike its subdirectories, it can't be
+// deleted.
+type kvStore struct {
+	plugin.EntryBase
+	client *api.Client
+}
+
+func newKVStore(client *api.Client) *kvStore {
+	store := &kvStore{
+		EntryBase: plugin.NewEntry("kv"),
+	}
+	store.client = client
+	return store
+}
+
+func (s *kvStore) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(s, "kv").
+		SetDescription(kvStoreDescription).
+		IsSingleton()
+}
+
+func (s *kvStore) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&kvDir{}).Schema(),
+		(&kvPair{}).Schema(),
+	}
+}
+
+func (s *kvStore) List(ctx context.Context) ([]plugin.Entry, error) {
+	return listKV(ctx, s.client, "")
+}
+
+func (s *kvStore) Create(ctx context.Context, name string, _ os.FileMode) (plugin.Writable, error) {
+	return newKVPair(s.client, name, name), nil
+}
+
+func (s *kvStore) CreateDir(ctx context.Context, name string) (plugin.Parent, error) {
+	return createKVDir(ctx, s.client, name, name+"/")
+}
+
+// kvDir is a prefix of keys that ends in a slash.
+type kvDir struct {
+	plugin.EntryBase
+	client *api.Client
+	prefix string
+}
+
+func newKVDir(client *api.Client, name string, prefix string) *kvDir {
+	dir := &kvDir{
+		EntryBase: plugin.NewEntry(name),
+	}
+	dir.client = client
+	dir.prefix = prefix
+	return dir
+}
+
+func (d *kvDir) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(d, "dir").
+		SetDescription(kvDirDescription)
+}
+
+func (d *kvDir) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&kvDir{}).Schema(),
+		(&kvPair{}).Schema(),
+	}
+}
+
+func (d *kvDir) List(ctx context.Context) ([]plugin.Entry, error) {
+	return listKV(ctx, d.client, d.prefix)
+}
+
+func (d *kvDir) Create(ctx context.Context, name string, _ os.FileMode) (plugin.Writable, error) {
+	return newKVPair(d.client, name, d.prefix+name), nil
+}
+
+func (d *kvDir) CreateDir(ctx context.Context, name string) (plugin.Parent, error) {
+	return createKVDir(ctx, d.client, name, d.prefix+name+"/")
+}
+
+// createKVDir creates an empty directory by putting its prefix as a key, like
+// 'consul kv put foo/'.
+func createKVDir(ctx context.Context, client *api.Client, name string, prefix string) (*kvDir, error) {
+	if _, err := client.KV().Put(&api.KVPair{Key: prefix}, (&api.WriteOptions{}).WithContext(ctx)); err != nil {
+		return nil, err
+	}
+	return newKVDir(client, name, prefix), nil
+}
+
+// Delete deletes the keys with the directory's prefix.
+func (d *kvDir) Delete(ctx context.Context) (bool, error) {
+	_, err := d.client.KV().DeleteTree(d.prefix, (&api.WriteOptions{}).WithContext(ctx))
+	return err == nil, err
+}
+
+// listKV lists the keys and directories directly under prefix. Consul returns
+// the nested prefixes (ending in the separator) along with the keys.
+func listKV(ctx context.Context, client *api.Client, prefix string) ([]plugin.Entry, error) {
+	keys, _, err := client.KV().Keys(prefix, "/", (&api.QueryOptions{}).WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]plugin.Entry, 0, len(keys))
+	for _, key := range keys {
+		// A directory created with e.g. 'consul kv put foo/' is returned as a key
+		// when listing its own prefix.
+		if key == prefix {
+			continue
+		}
+		name := strings.TrimPrefix(key, prefix)
+		if strings.HasSuffix(name, "/") {
+			entries = append(entries, newKVDir(client, strings.TrimSuffix(name, "/"), key))
+		} else {
+			entries = append(entries, newKVPair(client, name, key))
+		}
+	}
+	return entries, nil
+}
+
+// kvPair is a key. Its content is the key's value.
+type kvPair struct {
+	plugin.EntryBase
+	client *api.Client
+	key    string
+}
+
+// kvMetadata is a key's KVPair without its value.
+type kvMetadata struct {
+	Key         string
+	CreateIndex uint64
+	ModifyIndex uint64
+	LockIndex   uint64
+	Flags       uint64
+	Session     string `json:",omitempty"`
+}
+
+func newKVPair(client *api.Client, name string, key string) *kvPair {
+	pair := &kvPair{
+		EntryBase: plugin.NewEntry(name),
+	}
+	pair.client = client
+	pair.key = key
+	return pair
+}
+
+func (p *kvPair) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(p, "key").
+		SetDescription(kvPairDescription).
+		SetMetadataSchema(kvMetadata{})
+}
+
+func (p *kvPair) get(ctx context.Context) (*api.KVPair, error) {
+	pair, _, err := p.client.KV().Get(p.key, (&api.QueryOptions{}).WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if pair == nil {
+		return nil, fmt.Errorf("key %v not found", p.key)
+	}
+	return pair, nil
+}
+
+func (p *kvPair) Read(ctx context.Context) ([]byte, error) {
+	pair, err := p.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return pair.Value, nil
+}
+
+func (p *kvPair) Write(ctx context.Context, b []byte) error {
+	_, err := p.client.KV().Put(&api.KVPair{Key: p.key, Value: b}, (&api.WriteOptions{}).WithContext(ctx))
+	return err
+}
+
+func (p *kvPair) Delete(ctx context.Context) (bool, error) {
+	_, err := p.client.KV().Delete(p.key, (&api.WriteOptions{}).WithContext(ctx))
+	return err == nil, err
+}
+
+func (p *kvPair) Metadata(ctx context.Context) (plugin.JSONObject, error) {
+	pair, err := p.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return plugin.ToJSONObject(kvMetadata{
+		Key:         pair.Key,
+		CreateIndex: pair.CreateIndex,
+		ModifyIndex: pair.ModifyIndex,
+		LockIndex:   pair.LockIndex,
+		Flags:       pair.Flags,
+		Session:     pair.Session,
+	}), nil
+}
+
+const kvStoreDescription = `
+This is Consul's KV store. Key prefixes that end in a slash are directories, and
+keys are files whose content is the key's value. Writing to a file puts its
+key's value, and new keys and directories can be created in any directory.
+`
+
+const kvDirDescription = `
+This is a prefix of keys in Consul's KV store. Deleting it deletes all of the
+keys with the prefix.
+`
+
+const kvPairDescription = `
+This is a key in Consul's KV store. Its content is the key's value. Its metadata
+includes the key's flags, indexes and the session that holds its lock (if any).
+`
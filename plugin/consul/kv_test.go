@@ -0,0 +1,89 @@
+package consul
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T, responses map[string]string) (*api.Client, *httptest.Server) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := responses[r.URL.RequestURI()]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+
+	config := api.DefaultConfig()
+	config.Address = server.URL
+	config.Token = ""
+	client, err := api.NewClient(config)
+	require.NoError(t, err)
+	return client, server
+}
+
+func TestListKV(t *testing.T) {
+	client, server := newTestClient(t, map[string]string{
+		"/v1/kv/?keys=&separator=%2F":     `["app/", "db"]`,
+		"/v1/kv/app/?keys=&separator=%2F": `["app/", "app/config/", "app/port"]`,
+		"/v1/kv/app/port":                 `[{"Key": "app/port", "Value": "ODA4MA==", "CreateIndex": 5, "ModifyIndex": 7}]`,
+	})
+	defer server.Close()
+	ctx := context.Background()
+
+	entries, err := newKVStore(client).List(ctx)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	app := entries[0].(*kvDir)
+	assert.Equal(t, "app", plugin.Name(app))
+	assert.Equal(t, "app/", app.prefix)
+	assert.Equal(t, "db", entries[1].(*kvPair).key)
+
+	// The directory's own key is skipped.
+	entries, err = app.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "app/config/", entries[0].(*kvDir).prefix)
+	port := entries[1].(*kvPair)
+	assert.Equal(t, "port", plugin.Name(port))
+
+	content, err := port.Read(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "8080", string(content))
+
+	meta, err := port.Metadata(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, plugin.JSONObject{
+		"Key":         "app/port",
+		"CreateIndex": 5.0,
+		"ModifyIndex": 7.0,
+		"LockIndex":   0.0,
+		"Flags":       0.0,
+	}, meta)
+
+	_, err = newKVPair(client, "missing", "app/missing").Read(ctx)
+	assert.EqualError(t, err, "key app/missing not found")
+}
+
+func TestCreateDir(t *testing.T) {
+	client, server := newTestClient(t, map[string]string{
+		"/v1/kv/app/logs/": `true`,
+	})
+	defer server.Close()
+
+	dir, err := newKVDir(client, "app", "app/").CreateDir(context.Background(), "logs")
+	require.NoError(t, err)
+	assert.Equal(t, "logs", plugin.Name(dir))
+	assert.Equal(t, "app/logs/", dir.(*kvDir).prefix)
+
+	_, err = newKVStore(client).CreateDir(context.Background(), "other")
+	assert.Error(t, err)
+}
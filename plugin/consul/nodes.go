@@ -0,0 +1,129 @@
+package consul
+
+import (
+	"context"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+type nodesDir struct {
+	plugin.EntryBase
+	client *api.Client
+}
+
+func newNodesDir(client *api.Client) *nodesDir {
+	dir := &nodesDir{
+		EntryBase: plugin.NewEntry("nodes"),
+	}
+	dir.client = client
+	return dir
+}
+
+func (d *nodesDir) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(d, "nodes").
+		SetDescription(nodesDirDescription).
+		IsSingleton()
+}
+
+func (d *nodesDir) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&node{}).Schema(),
+	}
+}
+
+func (d *nodesDir) List(ctx context.Context) ([]plugin.Entry, error) {
+	nodes, _, err := d.client.Catalog().Nodes((&api.QueryOptions{}).WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]plugin.Entry, len(nodes))
+	for i, n := range nodes {
+		entries[i] = newNode(d.client, n)
+	}
+	return entries, nil
+}
+
+type node struct {
+	plugin.EntryBase
+	client *api.Client
+}
+
+// nodeMetadata adds the node's services and health checks to its catalog info.
+type nodeMetadata struct {
+	*api.Node
+	Services map[string]*api.AgentService `json:"services"`
+	Checks   api.HealthChecks             `json:"checks"`
+}
+
+func newNode(client *api.Client, n *api.Node) *node {
+	nd := &node{
+		EntryBase: plugin.NewEntry(n.Node),
+	}
+	nd.client = client
+	nd.
+		SetPartialMetadata(n).
+		Attributes().
+		SetCustom("address", n.Address)
+	return nd
+}
+
+func (n *node) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(n, "node").
+		SetDescription(nodeDescription).
+		SetPartialMetadataSchema(api.Node{}).
+		SetMetadataSchema(nodeMetadata{}).
+		AddCustomAttribute("address", plugin.StringAttribute, "The node's address")
+}
+
+func (n *node) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&plugin.MetadataJSONFile{}).Schema(),
+	}
+}
+
+func (n *node) List(ctx context.Context) ([]plugin.Entry, error) {
+	meta, err := plugin.NewMetadataJSONFile(ctx, n)
+	if err != nil {
+		return nil, err
+	}
+	return []plugin.Entry{meta}, nil
+}
+
+func (n *node) Metadata(ctx context.Context) (plugin.JSONObject, error) {
+	catalogNode, _, err := n.client.Catalog().Node(n.Name(), (&api.QueryOptions{}).WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	checks, _, err := n.client.Health().Node(n.Name(), (&api.QueryOptions{}).WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	meta := nodeMetadata{Checks: checks}
+	if catalogNode != nil {
+		meta.Node = catalogNode.Node
+		meta.Services = catalogNode.Services
+	}
+	return plugin.ToJSONObject(meta), nil
+}
+
+func (n *node) Exec(ctx context.Context, cmd string, args []string, opts plugin.ExecOptions) (plugin.ExecCommand, error) {
+	return remoteExec(ctx, n.client, n.Name(), cmd, args, opts)
+}
+
+const nodesDirDescription = `
+This is the list of nodes in Consul's catalog.
+`
+
+const nodeDescription = `
+This is a node in Consul's catalog. Its metadata includes the services that are
+registered on it and its health checks.
+
+Its Exec action uses Consul's remote execution (like 'consul exec'), so it only
+works on nodes whose agents set disable_remote_exec to false. Commands are run
+by the agent's shell, and don't support stdin or a TTY.
+`
@@ -0,0 +1,179 @@
+package consul
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/kballard/go-shellquote"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// These are the names that Consul agents use for remote execution. An agent
+// runs a job when it receives the _rexec event, and reports its progress under
+// the job's prefix in the KV store.
+const (
+	rexecEventName = "_rexec"
+	rexecPrefix    = "_rexec"
+)
+
+const (
+	// rexecSessionTTL is the TTL of the session that holds a job's keys. The
+	// session's renewed until the job's finished.
+	rexecSessionTTL = "15s"
+	// rexecAckTimeout is how long to wait for the node to acknowledge a job.
+	// Agents that disable remote execution ignore jobs.
+	rexecAckTimeout = 15 * time.Second
+	// rexecPollWait bounds the blocking queries that watch a job's progress.
+	rexecPollWait = 5 * time.Second
+	// rexecWait is the job's wait, which matches 'consul exec -wait'.
+	rexecWait = 2 * time.Second
+)
+
+type rexecEvent struct {
+	Prefix  string
+	Session string
+}
+
+type rexecSpec struct {
+	Command string
+	Wait    time.Duration
+}
+
+// remoteExec runs the command on the node with Consul's remote execution. The
+// job's spec is stored in the KV store under a session, then the node is told
+// to run it with a user event. The node's acknowledgement, output and exit code
+// are written under the same prefix.
+func remoteExec(ctx context.Context, client *api.Client, nodeName string, cmd string, args []string, opts plugin.ExecOptions) (plugin.ExecCommand, error) {
+	if opts.Stdin != nil || opts.Tty {
+		return nil, fmt.Errorf("Consul's remote execution doesn't support stdin or a TTY")
+	}
+
+	session, _, err := client.Session().Create(&api.SessionEntry{
+		Name:     "Remote exec via wash",
+		Behavior: api.SessionBehaviorDelete,
+		TTL:      rexecSessionTTL,
+	}, (&api.WriteOptions{}).WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	doneCh := make(chan struct{})
+	go func() {
+		// RenewPeriodic destroys the session when doneCh is closed.
+		if err := client.Session().RenewPeriodic(rexecSessionTTL, session, nil, doneCh); err != nil {
+			activity.Record(ctx, "Could not renew remote exec session %v: %v", session, err)
+		}
+	}()
+	jobPrefix := rexecPrefix + "/" + session + "/"
+	cleanup := func() {
+		// ctx may be cancelled, so don't use it to clean up.
+		if _, err := client.KV().DeleteTree(jobPrefix, nil); err != nil {
+			activity.Record(ctx, "Could not delete remote exec job %v: %v", jobPrefix, err)
+		}
+		close(doneCh)
+	}
+
+	if err := startRexecJob(ctx, client, nodeName, session, jobPrefix, append([]string{cmd}, args...)); err != nil {
+		cleanup()
+		return nil, err
+	}
+
+	execCmd := plugin.NewExecCommand(ctx)
+	go func() {
+		defer cleanup()
+		exitCode, err := watchRexecJob(ctx, client, jobPrefix+nodeName+"/", execCmd)
+		execCmd.CloseStreamsWithError(err)
+		if err != nil {
+			execCmd.SetExitCodeErr(err)
+		} else {
+			execCmd.SetExitCode(exitCode)
+		}
+	}()
+	return execCmd, nil
+}
+
+// startRexecJob stores the job's spec and fires the event that tells the node
+// to run it.
+func startRexecJob(ctx context.Context, client *api.Client, nodeName string, session string, jobPrefix string, command []string) error {
+	spec, err := json.Marshal(rexecSpec{Command: shellquote.Join(command...), Wait: rexecWait})
+	if err != nil {
+		return err
+	}
+	acquired, _, err := client.KV().Acquire(&api.KVPair{
+		Key:     jobPrefix + "job",
+		Value:   spec,
+		Session: session,
+	}, (&api.WriteOptions{}).WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	if !acquired {
+		return fmt.Errorf("could not acquire the remote exec job's key %vjob", jobPrefix)
+	}
+
+	payload, err := json.Marshal(rexecEvent{Prefix: rexecPrefix, Session: session})
+	if err != nil {
+		return err
+	}
+	activity.Record(ctx, "Starting remote exec job %v on %v: %v", session, nodeName, command)
+	_, _, err = client.Event().Fire(&api.UserEvent{
+		Name:       rexecEventName,
+		Payload:    payload,
+		NodeFilter: "^" + regexp.QuoteMeta(nodeName) + "$",
+	}, (&api.WriteOptions{}).WithContext(ctx))
+	return err
+}
+
+// watchRexecJob writes the node's output to execCmd's stdout until the node
+// reports the job's exit code. Agents combine stdout and stderr.
+func watchRexecJob(ctx context.Context, client *api.Client, nodePrefix string, execCmd *plugin.ExecCommandImpl) (int, error) {
+	ackDeadline := time.Now().Add(rexecAckTimeout)
+	acked := false
+	written := make(map[string]struct{})
+	var index uint64
+	for {
+		opts := (&api.QueryOptions{WaitIndex: index, WaitTime: rexecPollWait}).WithContext(ctx)
+		pairs, meta, err := client.KV().List(nodePrefix, opts)
+		if err != nil {
+			return 0, err
+		}
+		index = meta.LastIndex
+
+		// Consul returns the pairs sorted by key, and the output keys are
+		// zero-padded sequence numbers, so output is written in order.
+		var exit *api.KVPair
+		for _, pair := range pairs {
+			key := strings.TrimPrefix(pair.Key, nodePrefix)
+			switch {
+			case key == "ack":
+				acked = true
+			case key == "exit":
+				exit = pair
+			case strings.HasPrefix(key, "out/"):
+				if _, ok := written[key]; ok {
+					continue
+				}
+				written[key] = struct{}{}
+				if _, err := execCmd.Stdout().Write(pair.Value); err != nil {
+					return 0, err
+				}
+			}
+		}
+		if exit != nil {
+			exitCode, err := strconv.Atoi(strings.TrimSpace(string(exit.Value)))
+			if err != nil {
+				return 0, fmt.Errorf("invalid exit code %q: %v", exit.Value, err)
+			}
+			return exitCode, nil
+		}
+		if !acked && time.Now().After(ackDeadline) {
+			return 0, fmt.Errorf("the node didn't acknowledge the job; is remote exec enabled on its agent?")
+		}
+	}
+}
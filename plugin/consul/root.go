@@ -0,0 +1,97 @@
+// Package consul presents a filesystem hierarchy for HashiCorp Consul's KV
+// store, service catalog and nodes.
+//
+// It uses the CONSUL environment variables (like CONSUL_HTTP_ADDR and
+// CONSUL_HTTP_TOKEN) to access Consul.
+package consul
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// Root of the Consul plugin
+type Root struct {
+	plugin.EntryBase
+	client *api.Client
+}
+
+// Init for root
+func (r *Root) Init(cfg map[string]interface{}) error {
+	config := api.DefaultConfig()
+	if addressI, ok := cfg["address"]; ok {
+		address, ok := addressI.(string)
+		if !ok {
+			return fmt.Errorf("consul.address config must be a string, not %v", addressI)
+		}
+		config.Address = address
+	}
+
+	// The default config uses its own transport, so send requests through the
+	// shared one instead. The client only applies the CONSUL_* TLS settings if
+	// the transport doesn't have a TLS config, so merge them with the shared
+	// one's.
+	transport := plugin.HTTPTransport()
+	if shared := transport.TLSClientConfig; shared != nil {
+		tlsConfig, err := api.SetupTLSConfig(&config.TLSConfig)
+		if err != nil {
+			return err
+		}
+		if tlsConfig.RootCAs == nil {
+			tlsConfig.RootCAs = shared.RootCAs
+		}
+		tlsConfig.MinVersion = shared.MinVersion
+		transport.TLSClientConfig = tlsConfig
+	}
+	config.Transport = transport
+
+	client, err := api.NewClient(config)
+	if err != nil {
+		return err
+	}
+	r.client = client
+
+	r.EntryBase = plugin.NewEntry("consul")
+	return nil
+}
+
+// Schema returns the root's schema
+func (r *Root) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(r, "consul").
+		SetDescription(rootDescription).
+		IsSingleton()
+}
+
+// ChildSchemas returns the root's child schema
+func (r *Root) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&kvStore{}).Schema(),
+		(&servicesDir{}).Schema(),
+		(&nodesDir{}).Schema(),
+	}
+}
+
+// List lists the KV store, the service catalog and the nodes.
+func (r *Root) List(ctx context.Context) ([]plugin.Entry, error) {
+	return []plugin.Entry{
+		newKVStore(r.client),
+		newServicesDir(r.client),
+		newNodesDir(r.client),
+	}, nil
+}
+
+const rootDescription = `
+This is the Consul plugin root. It contains the KV store (kv), the service
+catalog (services) and the cluster's nodes (nodes).
+
+Consul is found via the CONSUL environment variables like CONSUL_HTTP_ADDR and
+CONSUL_HTTP_TOKEN. The address can also be set by the consul.address config,
+e.g.
+
+consul:
+  address: consul.example.com:8500
+`
@@ -0,0 +1,29 @@
+package consul
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInit_UsesSharedHTTPClient(t *testing.T) {
+	var proxiedHost string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxiedHost = r.URL.Host
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer proxy.Close()
+	require.NoError(t, plugin.SetHTTPOptions(plugin.HTTPOptions{Proxy: proxy.URL}))
+	defer func() { _ = plugin.SetHTTPOptions(plugin.HTTPOptions{}) }()
+
+	r := &Root{}
+	require.NoError(t, r.Init(map[string]interface{}{"address": "http://consul.invalid:8500"}))
+
+	_, _, err := r.client.KV().Keys("", "/", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "consul.invalid:8500", proxiedHost)
+}
@@ -0,0 +1,145 @@
+package consul
+
+import (
+	"context"
+	"sort"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+type servicesDir struct {
+	plugin.EntryBase
+	client *api.Client
+}
+
+func newServicesDir(client *api.Client) *servicesDir {
+	dir := &servicesDir{
+		EntryBase: plugin.NewEntry("services"),
+	}
+	dir.client = client
+	return dir
+}
+
+func (d *servicesDir) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(d, "services").
+		SetDescription(servicesDirDescription).
+		IsSingleton()
+}
+
+func (d *servicesDir) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&service{}).Schema(),
+	}
+}
+
+func (d *servicesDir) List(ctx context.Context) ([]plugin.Entry, error) {
+	services, _, err := d.client.Catalog().Services((&api.QueryOptions{}).WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(services))
+	for name := range services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	entries := make([]plugin.Entry, len(names))
+	for i, name := range names {
+		entries[i] = newService(d.client, name, services[name])
+	}
+	return entries, nil
+}
+
+type service struct {
+	plugin.EntryBase
+	client *api.Client
+}
+
+type serviceInfo struct {
+	Name string   `json:"name"`
+	Tags []string `json:"tags"`
+}
+
+// serviceMetadata adds the service's instances, with their health checks, to
+// its catalog info.
+type serviceMetadata struct {
+	serviceInfo
+	Status    string              `json:"status"`
+	Instances []*api.ServiceEntry `json:"instances"`
+}
+
+func newService(client *api.Client, name string, tags []string) *service {
+	s := &service{
+		EntryBase: plugin.NewEntry(name),
+	}
+	s.client = client
+	if tags == nil {
+		tags = []string{}
+	}
+	s.SetPartialMetadata(serviceInfo{Name: name, Tags: tags})
+	return s
+}
+
+func (s *service) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(s, "service").
+		SetDescription(serviceDescription).
+		SetPartialMetadataSchema(serviceInfo{}).
+		SetMetadataSchema(serviceMetadata{})
+}
+
+func (s *service) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&plugin.MetadataJSONFile{}).Schema(),
+	}
+}
+
+func (s *service) List(ctx context.Context) ([]plugin.Entry, error) {
+	meta, err := plugin.NewMetadataJSONFile(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+	return []plugin.Entry{meta}, nil
+}
+
+// Metadata gets the service's instances from the health endpoint, so that each
+// instance includes its node and service checks.
+func (s *service) Metadata(ctx context.Context) (plugin.JSONObject, error) {
+	entries, _, err := s.client.Health().Service(s.Name(), "", false, (&api.QueryOptions{}).WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	var checks api.HealthChecks
+	tags := make(map[string]struct{})
+	for _, entry := range entries {
+		checks = append(checks, entry.Checks...)
+		if entry.Service != nil {
+			for _, tag := range entry.Service.Tags {
+				tags[tag] = struct{}{}
+			}
+		}
+	}
+	meta := serviceMetadata{
+		serviceInfo: serviceInfo{Name: s.Name(), Tags: make([]string, 0, len(tags))},
+		Status:      checks.AggregatedStatus(),
+		Instances:   entries,
+	}
+	for tag := range tags {
+		meta.Tags = append(meta.Tags, tag)
+	}
+	sort.Strings(meta.Tags)
+	return plugin.ToJSONObject(meta), nil
+}
+
+const servicesDirDescription = `
+This is Consul's service catalog.
+`
+
+const serviceDescription = `
+This is a service in Consul's catalog. Its metadata includes its instances and
+their health checks, along with the service's aggregated status (the worst
+status of all its checks).
+`
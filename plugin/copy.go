@@ -0,0 +1,78 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// CopyChunkSize is the maximum amount of data that Copy reads from the source
+// entry at a time.
+var CopyChunkSize int64 = 4 * 1024 * 1024
+
+// Copy copies the source entry's content to the destination entry and returns
+// the number of copied bytes. If the source is BlockReadable and the destination
+// is StreamWritable, then the content is piped to the destination in chunks of
+// at most CopyChunkSize bytes so that memory usage is constant regardless of the
+// content's size. Otherwise, the entire content is buffered before it's written.
+func Copy(ctx context.Context, src Entry, dst Writable) (copied int64, err error) {
+	if !ReadAction().IsSupportedOn(src) {
+		return 0, fmt.Errorf("%v is not readable", src.eb().id)
+	}
+	ctx = withPluginContext(ctx, src)
+	defer recoverPanic(ctx, dst, "Copy", &err)
+
+	// Copy bypasses the cache because the content's read exactly once.
+	content, err := readContent(ctx, src, false)
+	if err != nil {
+		return 0, err
+	}
+	size := int64(-1)
+	if ReadAction().signature(src) == DefaultSignature || src.eb().attributes.HasSize() {
+		size = int64(content.size())
+	}
+	r := &contentReader{ctx: ctx, content: content, sz: size}
+
+	err = WriteStream(ctx, dst, r, size)
+	return r.offset, err
+}
+
+// contentReader is an io.Reader over an entry's content. It reads the content
+// in chunks of at most CopyChunkSize bytes, buffering a single chunk at a time.
+type contentReader struct {
+	ctx     context.Context
+	content entryContent
+	// sz is -1 if the content's size is unknown
+	sz     int64
+	offset int64
+	chunk  []byte
+	eof    bool
+}
+
+func (r *contentReader) Read(p []byte) (int, error) {
+	if len(r.chunk) == 0 {
+		if r.eof || (r.sz >= 0 && r.offset >= r.sz) {
+			return 0, io.EOF
+		}
+		chunkSize := CopyChunkSize
+		if r.sz >= 0 && r.sz-r.offset < chunkSize {
+			chunkSize = r.sz - r.offset
+		}
+		data, err := r.content.read(r.ctx, chunkSize, r.offset)
+		if err != nil && err != io.EOF {
+			return 0, err
+		}
+		if err == io.EOF || int64(len(data)) < chunkSize {
+			r.eof = true
+		}
+		if len(data) == 0 {
+			return 0, io.EOF
+		}
+		r.chunk = data
+	}
+
+	n := copy(p, r.chunk)
+	r.chunk = r.chunk[n:]
+	r.offset += int64(n)
+	return n, nil
+}
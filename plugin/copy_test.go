@@ -0,0 +1,119 @@
+package plugin
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type copyTestsBlockReadableEntry struct {
+	EntryBase
+	content []byte
+	reads   []int64
+}
+
+func (e *copyTestsBlockReadableEntry) Schema() *EntrySchema {
+	return nil
+}
+
+func (e *copyTestsBlockReadableEntry) Read(ctx context.Context, size int64, offset int64) ([]byte, error) {
+	e.reads = append(e.reads, size)
+	return e.content[offset : offset+size], nil
+}
+
+type copyTestsWritableEntry struct {
+	EntryBase
+	content []byte
+}
+
+func (e *copyTestsWritableEntry) Schema() *EntrySchema {
+	return nil
+}
+
+func (e *copyTestsWritableEntry) Write(ctx context.Context, b []byte) error {
+	e.content = b
+	return nil
+}
+
+type copyTestsStreamWritableEntry struct {
+	copyTestsWritableEntry
+	size   int64
+	writes []int
+}
+
+func (e *copyTestsStreamWritableEntry) WriteStream(ctx context.Context, r io.Reader, size int64) error {
+	e.size = size
+	buf := make([]byte, 2)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			e.writes = append(e.writes, n)
+			e.content = append(e.content, buf[:n]...)
+		}
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+// setCopyChunkSize sets CopyChunkSize and returns a function that restores it.
+func setCopyChunkSize(size int64) func() {
+	old := CopyChunkSize
+	CopyChunkSize = size
+	return func() { CopyChunkSize = old }
+}
+
+func TestCopy_StreamsBlocksToStreamWritable(t *testing.T) {
+	defer setCopyChunkSize(4)()
+	src := &copyTestsBlockReadableEntry{EntryBase: NewEntry("src"), content: []byte("hello world")}
+	src.Attributes().SetSize(uint64(len(src.content)))
+	dst := &copyTestsStreamWritableEntry{copyTestsWritableEntry: copyTestsWritableEntry{EntryBase: NewEntry("dst")}}
+
+	copied, err := Copy(context.Background(), src, dst)
+	require.NoError(t, err)
+	assert.Equal(t, int64(11), copied)
+	assert.Equal(t, "hello world", string(dst.content))
+	assert.Equal(t, int64(11), dst.size)
+	assert.Equal(t, []int64{4, 4, 3}, src.reads)
+	// Each write is bounded by the reader's buffer, not the content's size.
+	for _, n := range dst.writes {
+		assert.LessOrEqual(t, n, 2)
+	}
+}
+
+func TestCopy_BuffersForWritable(t *testing.T) {
+	defer setCopyChunkSize(4)()
+	src := &copyTestsBlockReadableEntry{EntryBase: NewEntry("src"), content: []byte("hello world")}
+	src.Attributes().SetSize(uint64(len(src.content)))
+	dst := &copyTestsWritableEntry{EntryBase: NewEntry("dst")}
+
+	copied, err := Copy(context.Background(), src, dst)
+	require.NoError(t, err)
+	assert.Equal(t, int64(11), copied)
+	assert.Equal(t, "hello world", string(dst.content))
+}
+
+func TestCopy_ReadableSource(t *testing.T) {
+	src := &validatorTestsReadableEntry{EntryBase: NewEntry("src"), content: "hello world"}
+	dst := &copyTestsStreamWritableEntry{copyTestsWritableEntry: copyTestsWritableEntry{EntryBase: NewEntry("dst")}}
+
+	copied, err := Copy(context.Background(), src, dst)
+	require.NoError(t, err)
+	assert.Equal(t, int64(11), copied)
+	assert.Equal(t, "hello world", string(dst.content))
+	assert.Equal(t, int64(11), dst.size)
+	assert.Equal(t, 1, src.reads)
+}
+
+func TestCopy_NonReadableSource(t *testing.T) {
+	src := &mockNonReadable{EntryBase: NewEntry("src")}
+	dst := &copyTestsWritableEntry{EntryBase: NewEntry("dst")}
+
+	_, err := Copy(context.Background(), src, dst)
+	assert.Error(t, err)
+}
@@ -0,0 +1,114 @@
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// CustomAttributeType represents a custom attribute's type
+type CustomAttributeType string
+
+// These are the supported custom attribute types
+const (
+	StringAttribute  CustomAttributeType = "string"
+	NumberAttribute  CustomAttributeType = "number"
+	BooleanAttribute CustomAttributeType = "boolean"
+	TimeAttribute    CustomAttributeType = "time"
+)
+
+func (t CustomAttributeType) validate() error {
+	switch t {
+	case StringAttribute, NumberAttribute, BooleanAttribute, TimeAttribute:
+		return nil
+	default:
+		return fmt.Errorf(
+			"unknown custom attribute type %v, must be one of %v, %v, %v or %v",
+			t,
+			StringAttribute,
+			NumberAttribute,
+			BooleanAttribute,
+			TimeAttribute,
+		)
+	}
+}
+
+// JSONSchema returns the JSON schema of the type's values
+func (t CustomAttributeType) JSONSchema() *JSONSchema {
+	switch t {
+	case NumberAttribute:
+		return NumberSchema()
+	case BooleanAttribute:
+		return BooleanSchema()
+	case TimeAttribute:
+		return TimeSchema()
+	default:
+		return StringSchema()
+	}
+}
+
+// CustomAttributeSchema represents a given custom attribute's schema
+type CustomAttributeSchema struct {
+	customAttributeSchema
+}
+
+// Name returns the custom attribute's name
+func (s *CustomAttributeSchema) Name() string {
+	return s.customAttributeSchema.Name
+}
+
+// SetName sets the custom attribute's name. This should only be
+// called by the tests.
+func (s *CustomAttributeSchema) SetName(name string) *CustomAttributeSchema {
+	s.customAttributeSchema.Name = name
+	return s
+}
+
+// Type returns the custom attribute's type
+func (s *CustomAttributeSchema) Type() CustomAttributeType {
+	return s.customAttributeSchema.Type
+}
+
+// SetType sets the custom attribute's type. This should only be
+// called by the tests.
+func (s *CustomAttributeSchema) SetType(t CustomAttributeType) *CustomAttributeSchema {
+	s.customAttributeSchema.Type = t
+	return s
+}
+
+// Description returns the custom attribute's description
+func (s *CustomAttributeSchema) Description() string {
+	return s.customAttributeSchema.Description
+}
+
+// SetDescription sets the custom attribute's description. This should
+// only be called by the tests.
+func (s *CustomAttributeSchema) SetDescription(description string) *CustomAttributeSchema {
+	s.customAttributeSchema.Description = description
+	return s
+}
+
+// MarshalJSON marshals the custom attribute schema to JSON. It takes
+// a value receiver so that the entry schema's still marshalled
+// when it's referenced as an interface{} object. See
+// https://stackoverflow.com/a/21394657 for more details.
+func (s CustomAttributeSchema) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.customAttributeSchema)
+}
+
+// UnmarshalJSON unmarshals the custom attribute schema JSON.
+func (s *CustomAttributeSchema) UnmarshalJSON(bytes []byte) error {
+	err := json.Unmarshal(bytes, &s.customAttributeSchema)
+	if err != nil {
+		return err
+	}
+	return s.Type().validate()
+}
+
+// This is to implement JSON Marshal/Unmarshal. The main reason
+// for implementing the Marshaler/Unmarshaler interfaces is to
+// validate the marshalled Type.
+type customAttributeSchema struct {
+	Name        string              `json:"name"`
+	Type        CustomAttributeType `json:"type"`
+	Description string              `json:"description"`
+}
@@ -0,0 +1,66 @@
+package docker
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/puppetlabs/wash/plugin"
+)
+
+const buildRunning = "Running"
+
+// buildInfo is the part of a build's 'docker buildx history ls' output that's
+// used for its attributes. The rest is included in its metadata.
+type buildInfo struct {
+	Ref            string     `json:"ref"`
+	Name           string     `json:"name"`
+	Status         string     `json:"status"`
+	CreatedAt      *time.Time `json:"created_at"`
+	TotalSteps     int        `json:"total_steps"`
+	CompletedSteps int        `json:"completed_steps"`
+}
+
+// build is an in-flight build on a buildx builder. It's named by its ref.
+type build struct {
+	plugin.EntryBase
+	builder string
+}
+
+func newBuild(builder string, info buildInfo, raw map[string]interface{}) *build {
+	build := &build{
+		EntryBase: plugin.NewEntry(info.Ref),
+	}
+	build.builder = builder
+	attr := build.
+		SetPartialMetadata(raw).
+		Attributes().
+		SetCustom("name", info.Name).
+		SetCustom("total_steps", info.TotalSteps).
+		SetCustom("completed_steps", info.CompletedSteps)
+	if info.CreatedAt != nil {
+		attr.SetCrtime(*info.CreatedAt)
+	}
+	return build
+}
+
+func (b *build) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(b, "build").
+		SetDescription(buildDescription).
+		AddCustomAttribute("name", plugin.StringAttribute, "The build's name, which is usually its Dockerfile's directory").
+		AddCustomAttribute("total_steps", plugin.NumberAttribute, "The number of steps in the build").
+		AddCustomAttribute("completed_steps", plugin.NumberAttribute, "The number of steps that have completed")
+}
+
+// Stream streams the build's progress as plain text, starting from the
+// beginning of the build. The stream ends when the build does.
+func (b *build) Stream(ctx context.Context) (io.ReadCloser, error) {
+	return streamBuildx(ctx, "history", "logs", "--builder", b.builder, "--progress", "plain", b.Name())
+}
+
+const buildDescription = `
+This is an in-flight build on a buildx builder, named by its ref. Stream it to
+follow its progress, e.g.
+  tail -f docker/builders/default/qpo5wd8yiqwhr2ne0lf6hdhyn
+`
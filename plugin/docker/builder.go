@@ -0,0 +1,90 @@
+package docker
+
+import (
+	"context"
+
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// builderInfo is the part of a builder's 'docker buildx ls' output that's used
+// for its attributes. The rest is included in its metadata.
+type builderInfo struct {
+	Name   string
+	Driver string
+	Nodes  []struct {
+		Name   string
+		Status string
+	}
+}
+
+// builder is a buildx builder. It lists its in-flight builds.
+type builder struct {
+	plugin.EntryBase
+}
+
+func newBuilder(info builderInfo, raw map[string]interface{}) *builder {
+	builder := &builder{
+		EntryBase: plugin.NewEntry(info.Name),
+	}
+	// Builds finish quickly, so they're always re-listed.
+	builder.DisableCachingFor(plugin.ListOp)
+	status := ""
+	if len(info.Nodes) > 0 {
+		status = info.Nodes[0].Status
+	}
+	builder.
+		SetPartialMetadata(raw).
+		Attributes().
+		SetCustom("driver", info.Driver).
+		SetCustom("status", status)
+	return builder
+}
+
+func (b *builder) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(b, "builder").
+		SetDescription(builderDescription).
+		AddCustomAttribute("driver", plugin.StringAttribute, "The builder's driver, e.g. docker or docker-container").
+		AddCustomAttribute("status", plugin.StringAttribute, "The status of the builder's first node, e.g. running or inactive")
+}
+
+func (b *builder) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&build{}).Schema(),
+	}
+}
+
+// List lists the builder's in-flight builds from its build history, which
+// includes builds that were started by other clients.
+func (b *builder) List(ctx context.Context) ([]plugin.Entry, error) {
+	out, err := runBuildx(ctx, "history", "ls", "--builder", b.Name(), "--format", "json")
+	if err != nil {
+		return nil, err
+	}
+
+	var builds []plugin.Entry
+	err = decodeJSONLines(out, func(line []byte) error {
+		var info buildInfo
+		var raw map[string]interface{}
+		if err := unmarshalJSONLine(line, &info, &raw); err != nil {
+			return err
+		}
+		if info.Status == buildRunning {
+			builds = append(builds, newBuild(b.Name(), info, raw))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	activity.Record(ctx, "Listing %v in-flight builds on builder %v", len(builds), b.Name())
+	return builds, nil
+}
+
+const builderDescription = `
+This is a buildx builder. It contains the builder's in-flight builds, including
+those that were started elsewhere, such as by CI or another terminal. Listing
+them requires buildx v0.13 or later.
+`
@@ -0,0 +1,62 @@
+package docker
+
+import (
+	"context"
+
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// buildersDir lists the buildx builders.
+type buildersDir struct {
+	plugin.EntryBase
+}
+
+func newBuildersDir() *buildersDir {
+	buildersDir := &buildersDir{
+		EntryBase: plugin.NewEntry("builders"),
+	}
+	return buildersDir
+}
+
+func (bs *buildersDir) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(bs, "builders").
+		SetDescription(buildersDirDescription).
+		IsSingleton()
+}
+
+func (bs *buildersDir) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&builder{}).Schema(),
+	}
+}
+
+func (bs *buildersDir) List(ctx context.Context) ([]plugin.Entry, error) {
+	out, err := runBuildx(ctx, "ls", "--format", "json")
+	if err != nil {
+		return nil, err
+	}
+
+	var builders []plugin.Entry
+	err = decodeJSONLines(out, func(line []byte) error {
+		var info builderInfo
+		var raw map[string]interface{}
+		if err := unmarshalJSONLine(line, &info, &raw); err != nil {
+			return err
+		}
+		builders = append(builders, newBuilder(info, raw))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	activity.Record(ctx, "Listing %v buildx builders", len(builders))
+	return builders, nil
+}
+
+const buildersDirDescription = `
+This is the buildx builders directory. It contains the builders listed by
+'docker buildx ls', which requires buildx v0.13 or later.
+`
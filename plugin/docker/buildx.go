@@ -0,0 +1,90 @@
+package docker
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	"github.com/puppetlabs/wash/activity"
+)
+
+// Buildx builders and their builds are managed by the docker buildx CLI plugin,
+// so buildx is run to get them. Builders have their own endpoints, which
+// needn't be any of the plugin's daemons.
+
+// runBuildx runs `docker buildx <args>` and returns its stdout.
+func runBuildx(ctx context.Context, args ...string) ([]byte, error) {
+	activity.Record(ctx, "Running docker buildx %v", strings.Join(args, " "))
+	cmd := exec.CommandContext(ctx, "docker", append([]string{"buildx"}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if _, ok := err.(*exec.ExitError); ok {
+			return nil, fmt.Errorf("docker buildx %v failed: %v", args[0], strings.TrimSpace(stderr.String()))
+		}
+		return nil, fmt.Errorf("failed to run docker buildx, is it installed? %v", err)
+	}
+	return out, nil
+}
+
+// streamBuildx runs `docker buildx <args>` and streams its stdout and stderr.
+// The command's killed when the stream's closed.
+func streamBuildx(ctx context.Context, args ...string) (io.ReadCloser, error) {
+	activity.Record(ctx, "Streaming docker buildx %v", strings.Join(args, " "))
+	ctx, cancel := context.WithCancel(ctx)
+	cmd := exec.CommandContext(ctx, "docker", append([]string{"buildx"}, args...)...)
+	r, w := io.Pipe()
+	cmd.Stdout = w
+	cmd.Stderr = w
+	if err := cmd.Start(); err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to run docker buildx, is it installed? %v", err)
+	}
+	go func() {
+		err := cmd.Wait()
+		activity.Record(ctx, "docker buildx %v exited: %v", args[0], err)
+		w.CloseWithError(err)
+	}()
+	return &buildxStream{PipeReader: r, cancel: cancel}, nil
+}
+
+// buildxStream kills its command when it's closed.
+type buildxStream struct {
+	*io.PipeReader
+	cancel context.CancelFunc
+}
+
+func (s *buildxStream) Close() error {
+	s.cancel()
+	return s.PipeReader.Close()
+}
+
+// decodeJSONLines decodes output that has a JSON object per line, which is how
+// buildx formats lists with --format json.
+func decodeJSONLines(out []byte, decode func(line []byte) error) error {
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if err := decode(line); err != nil {
+			return fmt.Errorf("could not parse %s: %v", line, err)
+		}
+	}
+	return scanner.Err()
+}
+
+func unmarshalJSONLine(line []byte, v interface{}, raw *map[string]interface{}) error {
+	if err := json.Unmarshal(line, v); err != nil {
+		return err
+	}
+	return json.Unmarshal(line, raw)
+}
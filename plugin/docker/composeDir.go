@@ -0,0 +1,93 @@
+package docker
+
+import (
+	"context"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// Labels that Docker Compose attaches to the resources it creates.
+const (
+	composeProjectLabel         = "com.docker.compose.project"
+	composeServiceLabel         = "com.docker.compose.service"
+	composeContainerNumberLabel = "com.docker.compose.container-number"
+)
+
+// composeProjectFilters returns filters that match the resources of the given
+// project. If project is empty, then they match the resources of all projects.
+func composeProjectFilters(project string) filters.Args {
+	label := composeProjectLabel
+	if project != "" {
+		label += "=" + project
+	}
+	return filters.NewArgs(filters.Arg("label", label))
+}
+
+type composeDir struct {
+	plugin.EntryBase
+	client *client.Client
+}
+
+func newComposeDir(client *client.Client) *composeDir {
+	composeDir := &composeDir{
+		EntryBase: plugin.NewEntry("compose"),
+	}
+	composeDir.client = client
+	return composeDir
+}
+
+func (cd *composeDir) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(cd, "compose").
+		SetDescription(composeDirDescription).
+		IsSingleton()
+}
+
+func (cd *composeDir) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&composeProject{}).Schema(),
+	}
+}
+
+// List lists the Compose projects that own at least one container or volume.
+func (cd *composeDir) List(ctx context.Context) ([]plugin.Entry, error) {
+	projectFilters := composeProjectFilters("")
+	containers, err := cd.client.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: projectFilters})
+	if err != nil {
+		return nil, err
+	}
+	volumes, err := cd.client.VolumeList(ctx, projectFilters)
+	if err != nil {
+		return nil, err
+	}
+
+	projects := make(map[string]*composeProject)
+	getProject := func(name string) *composeProject {
+		if _, ok := projects[name]; !ok {
+			projects[name] = newComposeProject(name, cd.client)
+		}
+		return projects[name]
+	}
+	for _, inst := range containers {
+		getProject(inst.Labels[composeProjectLabel]).addContainer(inst)
+	}
+	for _, vol := range volumes.Volumes {
+		getProject(vol.Labels[composeProjectLabel]).addVolume(vol)
+	}
+
+	activity.Record(ctx, "Listing %v Compose projects in %v", len(projects), cd)
+	entries := make([]plugin.Entry, 0, len(projects))
+	for _, project := range projects {
+		entries = append(entries, project.finalize())
+	}
+	return entries, nil
+}
+
+const composeDirDescription = `
+This directory groups Docker resources by their Docker Compose project, as
+identified by the com.docker.compose.project label.
+`
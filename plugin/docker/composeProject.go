@@ -0,0 +1,171 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// composeProjectMetadata is the partial metadata of a Compose project.
+type composeProjectMetadata struct {
+	Name       string   `json:"name"`
+	Services   []string `json:"services"`
+	Containers []string `json:"containers"`
+	Volumes    []string `json:"volumes"`
+	Networks   []string `json:"networks"`
+}
+
+type composeProject struct {
+	plugin.EntryBase
+	client   *client.Client
+	meta     composeProjectMetadata
+	services map[string]struct{}
+	networks map[string]struct{}
+	crtime   time.Time
+}
+
+func newComposeProject(name string, client *client.Client) *composeProject {
+	project := &composeProject{
+		EntryBase: plugin.NewEntry(name),
+	}
+	project.client = client
+	project.meta.Name = name
+	project.services = make(map[string]struct{})
+	project.networks = make(map[string]struct{})
+	return project
+}
+
+func (p *composeProject) addContainer(inst types.Container) {
+	p.meta.Containers = append(p.meta.Containers, newContainer(inst, p.client).Name())
+	if service, ok := inst.Labels[composeServiceLabel]; ok {
+		p.services[service] = struct{}{}
+	}
+	if inst.NetworkSettings != nil {
+		for network := range inst.NetworkSettings.Networks {
+			p.networks[network] = struct{}{}
+		}
+	}
+	if created := time.Unix(inst.Created, 0); p.crtime.IsZero() || created.Before(p.crtime) {
+		p.crtime = created
+	}
+}
+
+func (p *composeProject) addVolume(vol *types.Volume) {
+	p.meta.Volumes = append(p.meta.Volumes, vol.Name)
+}
+
+// finalize sets the project's partial metadata and attributes once all of its
+// resources have been added.
+func (p *composeProject) finalize() *composeProject {
+	p.meta.Services = sortedKeys(p.services)
+	p.meta.Networks = sortedKeys(p.networks)
+	sort.Strings(p.meta.Containers)
+	sort.Strings(p.meta.Volumes)
+	p.SetPartialMetadata(p.meta)
+	if !p.crtime.IsZero() {
+		p.Attributes().SetCrtime(p.crtime)
+	}
+	return p
+}
+
+func sortedKeys(mp map[string]struct{}) []string {
+	keys := make([]string, 0, len(mp))
+	for k := range mp {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (p *composeProject) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(p, "project").
+		SetDescription(composeProjectDescription).
+		SetPartialMetadataSchema(composeProjectMetadata{})
+}
+
+func (p *composeProject) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&containersDir{}).Schema(),
+		(&volumesDir{}).Schema(),
+		(&networksDir{}).Schema(),
+		(&composeProjectLogFile{}).Schema(),
+	}
+}
+
+func (p *composeProject) List(ctx context.Context) ([]plugin.Entry, error) {
+	containers := newContainersDir(p.client)
+	containers.filters = composeProjectFilters(p.Name())
+	volumes := newVolumesDir(p.client)
+	volumes.filters = composeProjectFilters(p.Name())
+	networks := newNetworksDir(p.client)
+	networks.filters = composeProjectFilters(p.Name())
+	return []plugin.Entry{containers, volumes, networks, newComposeProjectLogFile(p)}, nil
+}
+
+// containers returns the project's containers, ordered by service and container
+// number.
+func (p *composeProject) containers(ctx context.Context) ([]*container, error) {
+	opts := types.ContainerListOptions{All: true, Filters: composeProjectFilters(p.Name())}
+	insts, err := p.client.ContainerList(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(insts, func(i, j int) bool {
+		si, sj := insts[i].Labels[composeServiceLabel], insts[j].Labels[composeServiceLabel]
+		if si != sj {
+			return si < sj
+		}
+		ni, _ := strconv.Atoi(insts[i].Labels[composeContainerNumberLabel])
+		nj, _ := strconv.Atoi(insts[j].Labels[composeContainerNumberLabel])
+		return ni < nj
+	})
+
+	containers := make([]*container, len(insts))
+	for i, inst := range insts {
+		containers[i] = newContainer(inst, p.client)
+	}
+	return containers, nil
+}
+
+// primaryContainer returns the container that project-level execs run in. This
+// is the first running container when ordered by service and container number.
+func (p *composeProject) primaryContainer(ctx context.Context) (*container, error) {
+	containers, err := p.containers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range containers {
+		if c.state == "running" {
+			return c, nil
+		}
+	}
+	return nil, fmt.Errorf("project %v does not have any running containers", p.Name())
+}
+
+func (p *composeProject) Exec(ctx context.Context, cmd string, args []string, opts plugin.ExecOptions) (plugin.ExecCommand, error) {
+	c, err := p.primaryContainer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	activity.Record(ctx, "Running project %v's exec in its primary container %v", p.Name(), c.Name())
+	return c.Exec(ctx, cmd, args, opts)
+}
+
+const composeProjectDescription = `
+This is a Docker Compose project. It contains the project's containers,
+volumes and networks, along with a 'logs' file that aggregates the logs of all
+the project's containers. Each line of the aggregated log is prefixed with the
+name of the container that wrote it.
+
+Exec'ing on a project runs the command in the project's primary container,
+which is the first running container when ordered by service name and
+container number.
+`
@@ -0,0 +1,138 @@
+package docker
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// composeProjectLogFile aggregates the logs of a Compose project's containers,
+// like 'docker-compose logs'.
+type composeProjectLogFile struct {
+	plugin.EntryBase
+	project *composeProject
+}
+
+func newComposeProjectLogFile(project *composeProject) *composeProjectLogFile {
+	log := &composeProjectLogFile{
+		EntryBase: plugin.NewEntry("logs"),
+	}
+	log.project = project
+	return log
+}
+
+func (l *composeProjectLogFile) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(l, "logs").
+		SetDescription(composeProjectLogFileDescription).
+		IsSingleton()
+}
+
+// Read returns each container's log in turn, with each line prefixed by the
+// container's name.
+func (l *composeProjectLogFile) Read(ctx context.Context) ([]byte, error) {
+	containers, err := l.project.containers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	prefixes := logPrefixes(containers)
+	var buf bytes.Buffer
+	for i, c := range containers {
+		data, err := newContainerLogFile(c).Read(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %v's log: %w", c.Name(), err)
+		}
+		writePrefixedLines(&buf, prefixes[i], bytes.NewReader(data))
+	}
+	return buf.Bytes(), nil
+}
+
+// Stream interleaves the streamed logs of all the project's containers. Like
+// each container's log, it honors the stream's since time.
+func (l *composeProjectLogFile) Stream(ctx context.Context) (io.ReadCloser, error) {
+	containers, err := l.project.containers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	streams := make([]io.ReadCloser, 0, len(containers))
+	closeStreams := func() {
+		for _, stream := range streams {
+			activity.Record(ctx, "Closed log stream: %v", stream.Close())
+		}
+	}
+	for _, c := range containers {
+		stream, err := newContainerLogFile(c).Stream(ctx)
+		if err != nil {
+			closeStreams()
+			return nil, fmt.Errorf("failed to stream %v's log: %w", c.Name(), err)
+		}
+		streams = append(streams, stream)
+	}
+
+	prefixes := logPrefixes(containers)
+	r, w := io.Pipe()
+	var wg sync.WaitGroup
+	var mux sync.Mutex
+	for i, stream := range streams {
+		wg.Add(1)
+		go func(prefix string, stream io.Reader) {
+			defer wg.Done()
+			scanner := bufio.NewScanner(stream)
+			for scanner.Scan() {
+				mux.Lock()
+				_, err := fmt.Fprintf(w, "%v | %v\n", prefix, scanner.Text())
+				mux.Unlock()
+				if err != nil {
+					return
+				}
+			}
+		}(prefixes[i], stream)
+	}
+	go func() {
+		wg.Wait()
+		activity.Record(ctx, "Closing write pipe: %v", w.Close())
+	}()
+	return plugin.CleanupReader{ReadCloser: r, Cleanup: closeStreams}, nil
+}
+
+// logPrefixes returns the containers' names padded to the same width so that
+// the aggregated log's lines are aligned.
+func logPrefixes(containers []*container) []string {
+	width := 0
+	for _, c := range containers {
+		if len(c.Name()) > width {
+			width = len(c.Name())
+		}
+	}
+	prefixes := make([]string, len(containers))
+	for i, c := range containers {
+		prefixes[i] = fmt.Sprintf("%-*v", width, c.Name())
+	}
+	return prefixes
+}
+
+func writePrefixedLines(w io.Writer, prefix string, r io.Reader) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fmt.Fprintf(w, "%v | %v\n", prefix, scanner.Text())
+	}
+}
+
+const composeProjectLogFileDescription = `
+This is the aggregated log of a Docker Compose project's containers. Each line
+is prefixed with the name of the container that wrote it, like
+'docker-compose logs'.
+
+Streaming it follows the logs of all the project's containers, e.g.
+  tail -f docker/compose/my-project/logs
+Use 'wash tail -f --since <duration>' to also output the lines that were logged
+since then.
+`
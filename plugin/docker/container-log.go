@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"io"
+	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/client"
@@ -65,8 +66,15 @@ func (clf *containerLogFile) Read(ctx context.Context) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// Stream follows the container's log, starting with its last 10 lines. If the
+// stream's since time is set, then it starts with the lines logged since then
+// instead.
 func (clf *containerLogFile) Stream(ctx context.Context) (io.ReadCloser, error) {
 	opts := types.ContainerLogsOptions{ShowStdout: true, ShowStderr: true, Follow: true, Tail: "10"}
+	if since, ok := plugin.StreamSince(ctx); ok {
+		opts.Since = since.Format(time.RFC3339Nano)
+		opts.Tail = ""
+	}
 	rdr, err := clf.client.ContainerLogs(ctx, clf.containerName, opts)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,142 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/dustin/go-humanize"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+type containerStatsFile struct {
+	plugin.EntryBase
+	containerID string
+	client      *client.Client
+}
+
+func newContainerStatsFile(container *container) *containerStatsFile {
+	csf := &containerStatsFile{
+		EntryBase: plugin.NewEntry("stats"),
+	}
+	csf.containerID = container.id
+	csf.client = container.client
+	// Stats are always changing, so there's no point caching them.
+	csf.DisableCachingFor(plugin.ReadOp)
+	return csf
+}
+
+func (csf *containerStatsFile) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(csf, "stats").
+		SetDescription(containerStatsFileDescription).
+		IsSingleton()
+}
+
+// Read returns a snapshot of the container's stats.
+func (csf *containerStatsFile) Read(ctx context.Context) ([]byte, error) {
+	resp, err := csf.client.ContainerStats(ctx, csf.containerID, false)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var stats types.StatsJSON
+	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		return nil, fmt.Errorf("could not decode %v's stats: %v", csf.containerID, err)
+	}
+	var buf bytes.Buffer
+	writeStatsLine(&buf, &stats)
+	return buf.Bytes(), nil
+}
+
+// Stream follows the container's stats. Docker reports them about once a second.
+func (csf *containerStatsFile) Stream(ctx context.Context) (io.ReadCloser, error) {
+	resp, err := csf.client.ContainerStats(ctx, csf.containerID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	r, w := io.Pipe()
+	go func() {
+		decoder := json.NewDecoder(resp.Body)
+		for {
+			var stats types.StatsJSON
+			if err := decoder.Decode(&stats); err != nil {
+				if err != io.EOF {
+					activity.Record(ctx, "Errored decoding %v's stats: %v", csf.containerID, err)
+				}
+				break
+			}
+			if _, err := writeStatsLine(w, &stats); err != nil {
+				break
+			}
+		}
+		activity.Record(ctx, "Closing write pipe: %v", w.Close())
+	}()
+	return plugin.CleanupReader{ReadCloser: r, Cleanup: func() {
+		activity.Record(ctx, "Closed stats stream: %v", resp.Body.Close())
+	}}, nil
+}
+
+// writeStatsLine writes a human-readable, single-line summary of the stats.
+func writeStatsLine(w io.Writer, stats *types.StatsJSON) (int, error) {
+	var rx, tx uint64
+	for _, network := range stats.Networks {
+		rx += network.RxBytes
+		tx += network.TxBytes
+	}
+	mem := memoryUsage(stats)
+	var memPercent float64
+	if stats.MemoryStats.Limit > 0 {
+		memPercent = float64(mem) / float64(stats.MemoryStats.Limit) * 100
+	}
+	return fmt.Fprintf(
+		w,
+		"%v cpu %.2f%% mem %v / %v (%.2f%%) net rx %v tx %v\n",
+		stats.Read.UTC().Format("2006-01-02T15:04:05Z"),
+		cpuPercent(stats),
+		humanize.IBytes(mem),
+		humanize.IBytes(stats.MemoryStats.Limit),
+		memPercent,
+		humanize.Bytes(rx),
+		humanize.Bytes(tx),
+	)
+}
+
+// cpuPercent calculates the container's CPU usage the same way as 'docker stats'.
+func cpuPercent(stats *types.StatsJSON) float64 {
+	cpuDelta := float64(stats.CPUStats.CPUUsage.TotalUsage) - float64(stats.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(stats.CPUStats.SystemUsage) - float64(stats.PreCPUStats.SystemUsage)
+	onlineCPUs := float64(stats.CPUStats.OnlineCPUs)
+	if onlineCPUs == 0 {
+		onlineCPUs = float64(len(stats.CPUStats.CPUUsage.PercpuUsage))
+	}
+	if cpuDelta <= 0 || systemDelta <= 0 {
+		return 0
+	}
+	return cpuDelta / systemDelta * onlineCPUs * 100
+}
+
+// memoryUsage excludes the page cache, which 'docker stats' also does.
+func memoryUsage(stats *types.StatsJSON) uint64 {
+	usage := stats.MemoryStats.Usage
+	if cache, ok := stats.MemoryStats.Stats["cache"]; ok && cache < usage {
+		usage -= cache
+	}
+	return usage
+}
+
+const containerStatsFileDescription = `
+This file contains the container's resource usage, i.e. its CPU, memory and
+network usage. Reading it returns a snapshot of the current usage. Streaming
+it (e.g. with 'tail -f') returns a new line each time Docker reports the usage,
+which is about once a second. Each line looks like
+
+2020-04-01T10:00:00Z cpu 1.53% mem 10MiB / 1.9GiB (0.51%) net rx 1.2kB tx 648B
+`
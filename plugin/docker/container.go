@@ -18,6 +18,7 @@ import (
 type container struct {
 	plugin.EntryBase
 	id     string
+	state  string
 	client *client.Client
 }
 
@@ -35,6 +36,7 @@ func newContainer(inst types.Container, client *client.Client) *container {
 		EntryBase: plugin.NewEntry(name),
 	}
 	cont.id = inst.ID
+	cont.state = inst.State
 	cont.client = client
 
 	startTime := time.Unix(inst.Created, 0)
@@ -69,12 +71,16 @@ func (c *container) Schema() *plugin.EntrySchema {
 		AddSignal("pause", "Suspends all processes in the container. Equivalent to 'docker pause <container>'").
 		AddSignal("resume", "Un-suspends all processes in the container. Equivalent to 'docker unpause <container>'").
 		AddSignal("restart", "Restarts the container. Equivalent to 'docker restart <container>'").
-		AddSignalGroup("linux", `\Asig.+`, "Consists of all the supported Linux signals like SIGHUP, SIGKILL. Equivalent to\n'docker kill <container> --signal <signal>'")
+		AddSignalGroup("linux", `\Asig.+`, "Consists of all the supported Linux signals like SIGHUP, SIGKILL. Equivalent to\n'docker kill <container> --signal <signal>'").
+		// Docker Desktop's deep links can't select a container, so this opens
+		// its dashboard.
+		SetConsoleURL("docker-desktop://dashboard/open")
 }
 
 func (c *container) ChildSchemas() []*plugin.EntrySchema {
 	return []*plugin.EntrySchema{
 		(&containerLogFile{}).Schema(),
+		(&containerStatsFile{}).Schema(),
 		(&plugin.MetadataJSONFile{}).Schema(),
 		(&vol.FS{}).Schema(),
 	}
@@ -91,7 +97,7 @@ func (c *container) List(ctx context.Context) ([]plugin.Entry, error) {
 
 	// Include a view of the remote filesystem using volume.FS. Use a small maxdepth because
 	// VMs can have lots of files and Exec is fast.
-	return []plugin.Entry{clf, cm, vol.NewFS(ctx, "fs", c, 3)}, nil
+	return []plugin.Entry{clf, newContainerStatsFile(c), cm, vol.NewFS(ctx, "fs", c, 3)}, nil
 }
 
 func (c *container) Delete(ctx context.Context) (bool, error) {
@@ -134,6 +140,14 @@ func (c *container) Exec(ctx context.Context, cmd string, args []string, opts pl
 	}
 
 	execCmd := plugin.NewExecCommand(ctx)
+	if opts.Tty {
+		execCmd.SetResizeFunc(opts.Resize, func(size plugin.WindowSize) error {
+			return c.client.ContainerExecResize(ctx, created.ID, types.ResizeOptions{
+				Height: uint(size.Rows),
+				Width:  uint(size.Cols),
+			})
+		})
+	}
 	execCmd.SetStopFunc(func() {
 		// Close the response on cancellation. Copying will block until there's more to read from the
 		// exec output. For an action with no more output it may never return.
@@ -168,6 +182,12 @@ func (c *container) Exec(ctx context.Context, cmd string, args []string, opts pl
 	return execCmd, nil
 }
 
+// WriteFile satisfies the volume.FileWriter interface used by the container's "fs"
+// directory.
+func (c *container) WriteFile(ctx context.Context, path string, b []byte, attr plugin.EntryAttributes) error {
+	return copyFileToContainer(ctx, c.client, c.id, path, b, attr)
+}
+
 func (c *container) Signal(ctx context.Context, signal string) error {
 	var err error
 	switch signal {
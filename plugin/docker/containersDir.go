@@ -2,8 +2,12 @@ package docker
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/client"
 	"github.com/puppetlabs/wash/activity"
 	"github.com/puppetlabs/wash/plugin"
@@ -11,7 +15,8 @@ import (
 
 type containersDir struct {
 	plugin.EntryBase
-	client *client.Client
+	client  *client.Client
+	filters filters.Args
 }
 
 func newContainersDir(client *client.Client) *containersDir {
@@ -34,7 +39,7 @@ func (cs *containersDir) ChildSchemas() []*plugin.EntrySchema {
 
 // List
 func (cs *containersDir) List(ctx context.Context) ([]plugin.Entry, error) {
-	containers, err := cs.client.ContainerList(ctx, types.ContainerListOptions{All: true})
+	containers, err := cs.client.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: cs.filters})
 	if err != nil {
 		return nil, err
 	}
@@ -46,3 +51,91 @@ func (cs *containersDir) List(ctx context.Context) ([]plugin.Entry, error) {
 	}
 	return keys, nil
 }
+
+// containerEventTypes maps the Docker container actions that Watch reports to
+// their entry event types. Other actions don't change a container's entry.
+var containerEventTypes = map[string]plugin.EntryEventType{
+	"create":  plugin.EntryCreated,
+	"destroy": plugin.EntryDeleted,
+	"start":   plugin.EntryModified,
+	"stop":    plugin.EntryModified,
+	"die":     plugin.EntryModified,
+	"pause":   plugin.EntryModified,
+	"unpause": plugin.EntryModified,
+	"rename":  plugin.EntryCreated,
+}
+
+// Watch uses Docker's events API to watch the containers.
+func (cs *containersDir) Watch(ctx context.Context) (<-chan plugin.EntryEvent, error) {
+	eventFilters := cs.filters.Clone()
+	eventFilters.Add("type", events.ContainerEventType)
+	msgs, errs := cs.client.Events(ctx, types.EventsOptions{Filters: eventFilters})
+
+	entryEvents := make(chan plugin.EntryEvent)
+	send := func(event plugin.EntryEvent) bool {
+		select {
+		case entryEvents <- event:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+	go func() {
+		defer close(entryEvents)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case err := <-errs:
+				// The events stream ends after an error.
+				if ctx.Err() == nil {
+					activity.Warnf(ctx, "Stopped watching %v: %v", cs, err)
+				}
+				return
+			case msg := <-msgs:
+				eventType, ok := containerEventTypes[msg.Action]
+				if !ok {
+					continue
+				}
+				t := time.Unix(0, msg.TimeNano)
+				if msg.Action == "rename" {
+					oldName := strings.TrimPrefix(msg.Actor.Attributes["oldName"], "/")
+					if !send(plugin.EntryEvent{Type: plugin.EntryDeleted, Name: oldName, Time: t}) {
+						return
+					}
+				}
+				if eventType == plugin.EntryDeleted {
+					if !send(plugin.EntryEvent{Type: eventType, Name: msg.Actor.Attributes["name"], Time: t}) {
+						return
+					}
+					continue
+				}
+				cont, err := cs.getContainer(ctx, msg.Actor.ID)
+				if err != nil {
+					activity.Warnf(ctx, "Could not get container %v: %v", msg.Actor.ID, err)
+					continue
+				} else if cont == nil {
+					// The container was destroyed before it could be listed.
+					continue
+				}
+				if !send(plugin.EntryEvent{Type: eventType, Entry: cont, Time: t}) {
+					return
+				}
+			}
+		}
+	}()
+	return entryEvents, nil
+}
+
+// getContainer returns the container with the given ID, or nil if it
+// doesn't exist.
+func (cs *containersDir) getContainer(ctx context.Context, id string) (*container, error) {
+	containers, err := cs.client.ContainerList(ctx, types.ContainerListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("id", id)),
+	})
+	if err != nil || len(containers) == 0 {
+		return nil, err
+	}
+	return newContainer(containers[0], cs.client), nil
+}
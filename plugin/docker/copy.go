@@ -0,0 +1,49 @@
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"path"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// copyFileToContainer writes b to the file at the given path in the container. It
+// mirrors 'docker cp', which uploads a tar archive of the file to its parent directory.
+// Note that this works on stopped containers too. Docker extracts the archive with
+// its entries' ownership, so the file's owner is included to avoid resetting it to
+// root.
+func copyFileToContainer(ctx context.Context, c *client.Client, cid string, filePath string, b []byte, attr plugin.EntryAttributes) error {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	hdr := &tar.Header{
+		Name:    path.Base(filePath),
+		Mode:    int64(attr.Mode().Perm()),
+		Size:    int64(len(b)),
+		ModTime: time.Now(),
+	}
+	if attr.HasUID() {
+		hdr.Uid = int(attr.UID())
+	}
+	if attr.HasGID() {
+		hdr.Gid = int(attr.GID())
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	if _, err := tw.Write(b); err != nil {
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+
+	dir := path.Dir(filePath)
+	activity.Record(ctx, "Copying %v bytes to %v on container %v", len(b), filePath, cid)
+	return c.CopyToContainer(ctx, cid, dir, &buf, types.CopyToContainerOptions{})
+}
@@ -0,0 +1,63 @@
+package docker
+
+import (
+	"context"
+
+	"github.com/docker/docker/client"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// newResources returns the resource directories of the daemon that the client
+// connects to.
+func newResources(client *client.Client) []plugin.Entry {
+	return []plugin.Entry{
+		newContainersDir(client),
+		newVolumesDir(client),
+		newNetworksDir(client),
+		newComposeDir(client),
+	}
+}
+
+func resourceSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&containersDir{}).Schema(),
+		(&volumesDir{}).Schema(),
+		(&networksDir{}).Schema(),
+		(&composeDir{}).Schema(),
+	}
+}
+
+// host represents a Docker daemon. It's only used when the plugin's configured
+// with more than one daemon.
+type host struct {
+	plugin.EntryBase
+	resources []plugin.Entry
+}
+
+func newHost(name string, client *client.Client) *host {
+	host := &host{
+		EntryBase: plugin.NewEntry(name),
+	}
+	host.DisableDefaultCaching()
+	host.resources = newResources(client)
+	return host
+}
+
+func (h *host) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(h, "host").
+		SetDescription(hostDescription)
+}
+
+func (h *host) ChildSchemas() []*plugin.EntrySchema {
+	return resourceSchemas()
+}
+
+func (h *host) List(ctx context.Context) ([]plugin.Entry, error) {
+	return h.resources, nil
+}
+
+const hostDescription = `
+This is a Docker daemon. It lets you interact with the daemon's containers,
+volumes and networks, which are also grouped by their Docker Compose project.
+`
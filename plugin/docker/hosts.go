@@ -0,0 +1,275 @@
+package docker
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/docker/docker/client"
+)
+
+// defaultHostName is the name of the daemon that's configured via the Docker socket
+// or the DOCKER environment variables. It matches the name of Docker's built-in
+// context.
+const defaultHostName = "default"
+
+// hostConfig describes how to connect to a Docker daemon.
+type hostConfig struct {
+	name string
+	// host is the daemon's endpoint, e.g. tcp://host:2376 or ssh://user@host. It is
+	// empty for the default daemon.
+	host string
+	// certPath is a directory containing the ca.pem, cert.pem and key.pem files used
+	// to connect to the daemon over TLS. It's optional.
+	certPath string
+}
+
+// parseHostsConfig parses the docker.hosts config, which is an array of objects
+// with "name", "host" and (optionally) "cert_path" keys.
+func parseHostsConfig(hostsI interface{}) ([]hostConfig, error) {
+	hosts, ok := hostsI.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("docker.hosts config must be an array of objects, not %v", hostsI)
+	}
+	configs := make([]hostConfig, len(hosts))
+	for i, elem := range hosts {
+		obj, ok := elem.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("docker.hosts config must be an array of objects, not %v", hosts)
+		}
+		for _, key := range []string{"name", "host", "cert_path"} {
+			value, ok := obj[key]
+			if !ok {
+				continue
+			}
+			str, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("docker.hosts[%v].%v must be a string, not %v", i, key, value)
+			}
+			switch key {
+			case "name":
+				configs[i].name = str
+			case "host":
+				configs[i].host = str
+			case "cert_path":
+				configs[i].certPath = str
+			}
+		}
+		if configs[i].name == "" || configs[i].host == "" {
+			return nil, fmt.Errorf("docker.hosts[%v] must specify a name and a host", i)
+		}
+	}
+	return configs, nil
+}
+
+// parseContextsConfig parses the docker.contexts config, which is an array of
+// context names.
+func parseContextsConfig(contextsI interface{}) (map[string]struct{}, error) {
+	contexts, ok := contextsI.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("docker.contexts config must be an array of strings, not %v", contextsI)
+	}
+	names := make(map[string]struct{})
+	for _, elem := range contexts {
+		name, ok := elem.(string)
+		if !ok {
+			return nil, fmt.Errorf("docker.contexts config must be an array of strings, not %v", contexts)
+		}
+		names[name] = struct{}{}
+	}
+	return names, nil
+}
+
+// dockerConfigDir returns the Docker CLI's config directory.
+func dockerConfigDir() (string, error) {
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		return dir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".docker"), nil
+}
+
+type contextMeta struct {
+	Name      string
+	Endpoints map[string]struct {
+		Host string
+	}
+}
+
+// loadContexts loads the Docker CLI contexts stored in configDir. If names is
+// non-nil, then only the named contexts are loaded. Contexts are stored in
+// contexts/meta/<sha256 of name>/meta.json, with their TLS material in
+// contexts/tls/<sha256 of name>/docker.
+func loadContexts(configDir string, names map[string]struct{}) ([]hostConfig, error) {
+	metaDir := filepath.Join(configDir, "contexts", "meta")
+	dirs, err := ioutil.ReadDir(metaDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var configs []hostConfig
+	for _, dir := range dirs {
+		raw, err := ioutil.ReadFile(filepath.Join(metaDir, dir.Name(), "meta.json"))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		var meta contextMeta
+		if err := json.Unmarshal(raw, &meta); err != nil {
+			return nil, fmt.Errorf("could not parse docker context %v: %v", dir.Name(), err)
+		}
+		if names != nil {
+			if _, ok := names[meta.Name]; !ok {
+				continue
+			}
+		}
+		endpoint, ok := meta.Endpoints["docker"]
+		if !ok || endpoint.Host == "" || meta.Name == defaultHostName {
+			continue
+		}
+
+		config := hostConfig{name: meta.Name, host: endpoint.Host}
+		digest := sha256.Sum256([]byte(meta.Name))
+		certPath := filepath.Join(configDir, "contexts", "tls", hex.EncodeToString(digest[:]), "docker")
+		if _, err := os.Stat(certPath); err == nil {
+			config.certPath = certPath
+		}
+		configs = append(configs, config)
+	}
+	sort.Slice(configs, func(i, j int) bool {
+		return configs[i].name < configs[j].name
+	})
+	return configs, nil
+}
+
+// newClient returns a client for the configured daemon.
+func (hc hostConfig) newClient() (*client.Client, error) {
+	if hc.host == "" {
+		return client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	}
+
+	opts := []client.Opt{client.WithAPIVersionNegotiation()}
+	hostURL, err := url.Parse(hc.host)
+	if err != nil {
+		return nil, fmt.Errorf("invalid host %v: %v", hc.host, err)
+	}
+	if hostURL.Scheme == "ssh" {
+		// The dialer ignores the host, but the client needs a valid one to build
+		// its request URLs.
+		opts = append(opts, client.WithHost("http://docker"), client.WithDialContext(sshDialer(hostURL)))
+	} else {
+		opts = append(opts, client.WithHost(hc.host))
+	}
+	if hc.certPath != "" && hostURL.Scheme != "ssh" {
+		opts = append(opts, client.WithTLSClientConfig(
+			filepath.Join(hc.certPath, "ca.pem"),
+			filepath.Join(hc.certPath, "cert.pem"),
+			filepath.Join(hc.certPath, "key.pem"),
+		))
+	}
+	return client.NewClientWithOpts(opts...)
+}
+
+// sshDialer returns a dialer that connects to the daemon on the remote host by
+// running `docker system dial-stdio` over ssh. This is how the Docker CLI
+// supports ssh:// hosts.
+func sshDialer(hostURL *url.URL) func(context.Context, string, string) (net.Conn, error) {
+	var args []string
+	if hostURL.User != nil {
+		args = append(args, "-l", hostURL.User.Username())
+	}
+	if port := hostURL.Port(); port != "" {
+		args = append(args, "-p", port)
+	}
+	args = append(args, "--", hostURL.Hostname(), "docker", "system", "dial-stdio")
+
+	return func(context.Context, string, string) (net.Conn, error) {
+		// Don't use the dial's context because the command needs to outlive the dial.
+		cmd := exec.Command("ssh", args...)
+		stdin, err := cmd.StdinPipe()
+		if err != nil {
+			return nil, err
+		}
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			return nil, err
+		}
+		if err := cmd.Start(); err != nil {
+			return nil, fmt.Errorf("failed to run ssh: %v", err)
+		}
+		return &commandConn{cmd: cmd, stdin: stdin, stdout: stdout}, nil
+	}
+}
+
+// commandConn is a net.Conn over a command's stdin and stdout.
+type commandConn struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout io.ReadCloser
+}
+
+func (c *commandConn) Read(p []byte) (int, error) {
+	return c.stdout.Read(p)
+}
+
+func (c *commandConn) Write(p []byte) (int, error) {
+	return c.stdin.Write(p)
+}
+
+func (c *commandConn) Close() error {
+	err := c.stdin.Close()
+	// The process may have already exited, and its exit status is irrelevant
+	// since the connection's closed, so ignore the errors.
+	_ = c.cmd.Process.Kill()
+	_ = c.cmd.Wait()
+	return err
+}
+
+func (c *commandConn) LocalAddr() net.Addr {
+	return commandAddr{}
+}
+
+func (c *commandConn) RemoteAddr() net.Addr {
+	return commandAddr{}
+}
+
+func (c *commandConn) SetDeadline(time.Time) error {
+	return nil
+}
+
+func (c *commandConn) SetReadDeadline(time.Time) error {
+	return nil
+}
+
+func (c *commandConn) SetWriteDeadline(time.Time) error {
+	return nil
+}
+
+type commandAddr struct{}
+
+func (commandAddr) Network() string {
+	return "command"
+}
+
+func (commandAddr) String() string {
+	return "command"
+}
@@ -0,0 +1,141 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+type network struct {
+	plugin.EntryBase
+	id     string
+	client *client.Client
+}
+
+func newNetwork(inst types.NetworkResource, client *client.Client) *network {
+	net := &network{
+		EntryBase: plugin.NewEntry(inst.Name),
+	}
+	net.id = inst.ID
+	net.client = client
+	net.
+		SetPartialMetadata(inst).
+		Attributes().
+		SetCrtime(inst.Created).
+		SetMtime(inst.Created).
+		SetCtime(inst.Created).
+		SetAtime(inst.Created)
+
+	return net
+}
+
+// Metadata includes the network's attached containers and their endpoints.
+func (n *network) Metadata(ctx context.Context) (plugin.JSONObject, error) {
+	_, raw, err := n.client.NetworkInspectWithRaw(ctx, n.id, types.NetworkInspectOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return plugin.ToJSONObject(raw), nil
+}
+
+func (n *network) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(n, "network").
+		SetDescription(networkDescription).
+		SetPartialMetadataSchema(types.NetworkResource{}).
+		SetMetadataSchema(types.NetworkResource{})
+}
+
+func (n *network) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&container{}).Schema(),
+	}
+}
+
+// List lists the containers that are attached to the network.
+func (n *network) List(ctx context.Context) ([]plugin.Entry, error) {
+	opts := types.ContainerListOptions{Filters: filters.NewArgs(filters.Arg("network", n.id))}
+	containers, err := n.client.ContainerList(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	activity.Record(ctx, "Listing %v containers attached to %v", len(containers), n)
+	keys := make([]plugin.Entry, len(containers))
+	for i, inst := range containers {
+		keys[i] = newContainer(inst, n.client)
+	}
+	return keys, nil
+}
+
+func (n *network) Delete(ctx context.Context) (bool, error) {
+	err := n.client.NetworkRemove(ctx, n.id)
+	return true, err
+}
+
+// Stream streams the network's connect and disconnect events. Each event is
+// a line of the form "<timestamp> <connect|disconnect> <container>".
+func (n *network) Stream(ctx context.Context) (io.ReadCloser, error) {
+	// Events stops when its context is cancelled, so use a separate context that
+	// is cancelled when the stream is closed.
+	ctx, cancel := context.WithCancel(ctx)
+	opts := types.EventsOptions{
+		Filters: filters.NewArgs(
+			filters.Arg("type", events.NetworkEventType),
+			filters.Arg("network", n.id),
+			filters.Arg("event", "connect"),
+			filters.Arg("event", "disconnect"),
+		),
+	}
+	msgs, errs := n.client.Events(ctx, opts)
+
+	r, w := io.Pipe()
+	go func() {
+		for {
+			select {
+			case msg := <-msgs:
+				timestamp := time.Unix(0, msg.TimeNano).Format(time.RFC3339)
+				container := n.containerName(ctx, msg.Actor.Attributes["container"])
+				if _, err := fmt.Fprintf(w, "%v %v %v\n", timestamp, msg.Action, container); err != nil {
+					return
+				}
+			case err := <-errs:
+				if err == context.Canceled {
+					err = nil
+				}
+				activity.Record(ctx, "Closing write pipe for %v's events: %v", n, w.CloseWithError(err))
+				return
+			}
+		}
+	}()
+	return plugin.CleanupReader{ReadCloser: r, Cleanup: cancel}, nil
+}
+
+// containerName returns the name of the container with the given ID. It falls
+// back to the ID if the container no longer exists.
+func (n *network) containerName(ctx context.Context, id string) string {
+	inst, err := n.client.ContainerInspect(ctx, id)
+	if err != nil {
+		return id
+	}
+	return strings.TrimPrefix(inst.Name, "/")
+}
+
+const networkDescription = `
+This is a Docker network. Its children are the containers that are attached
+to it, and its metadata includes the network's IPAM configuration and the
+endpoint (IP and MAC addresses) of each attached container.
+
+Streaming a network (e.g. with 'wash tail -f') returns its connect and disconnect events,
+one per line, as they happen.
+`
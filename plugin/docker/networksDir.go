@@ -0,0 +1,50 @@
+package docker
+
+import (
+	"context"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+type networksDir struct {
+	plugin.EntryBase
+	client  *client.Client
+	filters filters.Args
+}
+
+func newNetworksDir(client *client.Client) *networksDir {
+	networksDir := &networksDir{
+		EntryBase: plugin.NewEntry("networks"),
+	}
+	networksDir.client = client
+	return networksDir
+}
+
+func (ns *networksDir) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(ns, "networks").IsSingleton()
+}
+
+func (ns *networksDir) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&network{}).Schema(),
+	}
+}
+
+// List
+func (ns *networksDir) List(ctx context.Context) ([]plugin.Entry, error) {
+	networks, err := ns.client.NetworkList(ctx, types.NetworkListOptions{Filters: ns.filters})
+	if err != nil {
+		return nil, err
+	}
+
+	activity.Record(ctx, "Listing %v networks in %v", len(networks), ns)
+	keys := make([]plugin.Entry, len(networks))
+	for i, inst := range networks {
+		keys[i] = newNetwork(inst, ns.client)
+	}
+	return keys, nil
+}
@@ -1,13 +1,14 @@
 // Package docker presents a filesystem hierarchy for Docker resources.
 //
 // It uses local socket access or the DOCKER environment variables to
-// access the Docker daemon.
+// access the Docker daemon. Remote daemons can be added via Docker contexts
+// or the docker.hosts config.
 package docker
 
 import (
 	"context"
+	"fmt"
 
-	"github.com/docker/docker/client"
 	"github.com/puppetlabs/wash/plugin"
 )
 
@@ -20,19 +21,63 @@ type Root struct {
 }
 
 // Init for root
-func (r *Root) Init(map[string]interface{}) error {
-	dockerCli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+func (r *Root) Init(cfg map[string]interface{}) error {
+	configs := []hostConfig{{name: defaultHostName}}
+	if hostsI, ok := cfg["hosts"]; ok {
+		hosts, err := parseHostsConfig(hostsI)
+		if err != nil {
+			return err
+		}
+		configs = append(configs, hosts...)
+	}
+
+	var contextNames map[string]struct{}
+	if contextsI, ok := cfg["contexts"]; ok {
+		var err error
+		if contextNames, err = parseContextsConfig(contextsI); err != nil {
+			return err
+		}
+	}
+	configDir, err := dockerConfigDir()
+	if err != nil {
+		return err
+	}
+	contexts, err := loadContexts(configDir, contextNames)
 	if err != nil {
 		return err
 	}
+	configs = append(configs, contexts...)
 
 	r.EntryBase = plugin.NewEntry("docker")
 	r.DisableDefaultCaching()
-	r.resources = []plugin.Entry{
-		newContainersDir(dockerCli),
-		newVolumesDir(dockerCli),
+
+	// Preserve the single-daemon layout when only the default daemon's available.
+	if len(configs) == 1 {
+		dockerCli, err := configs[0].newClient()
+		if err != nil {
+			return err
+		}
+		r.resources = append(newResources(dockerCli), newBuildersDir())
+		return nil
 	}
 
+	seen := make(map[string]struct{})
+	for _, config := range configs {
+		if _, ok := seen[config.name]; ok {
+			return fmt.Errorf("docker: duplicate host name %v", config.name)
+		}
+		seen[config.name] = struct{}{}
+
+		dockerCli, err := config.newClient()
+		if err != nil {
+			return fmt.Errorf("docker: could not create a client for %v: %v", config.name, err)
+		}
+		r.resources = append(r.resources, newHost(config.name, dockerCli))
+	}
+	if _, ok := seen["builders"]; ok {
+		return fmt.Errorf("docker: the host name builders is reserved for the buildx builders")
+	}
+	r.resources = append(r.resources, newBuildersDir())
 	return nil
 }
 
@@ -46,19 +91,38 @@ func (r *Root) Schema() *plugin.EntrySchema {
 
 // ChildSchemas returns the root's child schema
 func (r *Root) ChildSchemas() []*plugin.EntrySchema {
-	return []*plugin.EntrySchema{
-		(&containersDir{}).Schema(),
-		(&volumesDir{}).Schema(),
-	}
+	return append(resourceSchemas(), (&host{}).Schema(), (&buildersDir{}).Schema())
 }
 
-// List lists the types of resources the Docker plugin exposes.
+// List lists the types of resources the Docker plugin exposes, or the configured
+// Docker daemons if there's more than one, followed by the buildx builders.
 func (r *Root) List(ctx context.Context) ([]plugin.Entry, error) {
 	return r.resources, nil
 }
 
 const rootDescription = `
 This is the Docker plugin root. It lets you interact with Docker resources
-like containers and volumes. These resources are found from the Docker socket
+like containers, volumes and networks, which are also grouped by their Docker
+Compose project. These resources are found from the Docker socket
 or via the DOCKER environment variables.
+
+Remote daemons can be added via Docker contexts (see 'docker context') or the
+docker.hosts config, e.g.
+
+docker:
+  hosts:
+    - name: build
+      host: ssh://user@build.example.com
+    - name: ci
+      host: tcp://ci.example.com:2376
+      cert_path: /etc/docker/certs/ci
+
+All Docker contexts are included by default. Use the docker.contexts config to
+only include the listed contexts, e.g. 'contexts: [staging]'. If there's more
+than one daemon, then each daemon appears as a separate host directory with the
+'default' host being the daemon found from the Docker socket or the DOCKER
+environment variables.
+
+The builders directory contains the buildx builders and their in-flight builds,
+whose progress can be streamed. It uses the docker buildx CLI plugin.
 `
@@ -13,7 +13,7 @@ import (
 	"github.com/docker/docker/api/types"
 	docontainer "github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/mount"
-	"github.com/docker/docker/api/types/network"
+	donetwork "github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/client"
 	"github.com/puppetlabs/wash/activity"
 	"github.com/puppetlabs/wash/plugin"
@@ -71,16 +71,22 @@ func (v *volume) Delete(ctx context.Context) (bool, error) {
 
 // Create a container that mounts a volume to a default mountpoint and runs a command.
 func (v *volume) createContainer(ctx context.Context, cmd []string) (string, error) {
+	return v.createContainerWithMount(ctx, cmd, true)
+}
+
+// Create a container that mounts a volume to a default mountpoint and runs a command.
+// The volume is mounted read-only if readOnly is true.
+func (v *volume) createContainerWithMount(ctx context.Context, cmd []string, readOnly bool) (string, error) {
 	// Use tty to avoid messing with the extra log formatting.
 	cfg := docontainer.Config{Image: "busybox", Cmd: cmd, Tty: true}
 	mounts := []mount.Mount{{
 		Type:     mount.TypeVolume,
 		Source:   v.Name(),
 		Target:   mountpoint,
-		ReadOnly: true,
+		ReadOnly: readOnly,
 	}}
 	hostcfg := docontainer.HostConfig{Mounts: mounts}
-	netcfg := network.NetworkingConfig{}
+	netcfg := donetwork.NetworkingConfig{}
 	created, err := v.client.ContainerCreate(ctx, &cfg, &hostcfg, &netcfg, "")
 	if err != nil {
 		// Pull busybox if create failed because it wasn't found.
@@ -262,11 +268,32 @@ func (v *volume) VolumeDelete(ctx context.Context, path string) (bool, error) {
 	return true, nil
 }
 
+func (v *volume) VolumeRename(ctx context.Context, path string, newPath string) error {
+	_, err := v.runInTemporaryContainer(ctx, []string{"mv", "-f", mountpoint + path, mountpoint + newPath})
+	return err
+}
+
+func (v *volume) VolumeWrite(ctx context.Context, path string, b []byte, attr plugin.EntryAttributes) error {
+	// Create a container that mounts the volume read-write. We don't need to start it
+	// because files can be copied to stopped containers.
+	cid, err := v.createContainerWithMount(ctx, []string{"true"}, false)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err := v.client.ContainerRemove(context.Background(), cid, types.ContainerRemoveOptions{})
+		activity.Record(ctx, "Deleted temporary container %v: %v", cid, err)
+	}()
+
+	return copyFileToContainer(ctx, v.client, cid, mountpoint+path, b, attr)
+}
+
 const volumeDescription = `
 This is a Docker volume. We create a temporary Docker container whenever
 Wash invokes a currently uncached List/Read/Stream action on it or one of
 its children. For List, we run 'find -exec stat' on the container and parse
 its output. For Read, we run 'sleep 60' then proceed to download the file
 content from the container. For Stream, we run 'tail -f' and pass over its
-output.
+output. For Write, we create (but don't start) a container that mounts the
+volume read-write, then upload the file's new content to it.
 `
@@ -11,7 +11,8 @@ import (
 
 type volumesDir struct {
 	plugin.EntryBase
-	client *client.Client
+	client  *client.Client
+	filters filters.Args
 }
 
 func newVolumesDir(client *client.Client) *volumesDir {
@@ -34,7 +35,7 @@ func (vs *volumesDir) ChildSchemas() []*plugin.EntrySchema {
 
 // List
 func (vs *volumesDir) List(ctx context.Context) ([]plugin.Entry, error) {
-	volumes, err := vs.client.VolumeList(ctx, filters.Args{})
+	volumes, err := vs.client.VolumeList(ctx, vs.filters)
 	if err != nil {
 		return nil, err
 	}
@@ -91,6 +91,11 @@ type EntryAttributes struct {
 	hasMode bool
 	size    uint64
 	hasSize bool
+	uid     uint32
+	hasUID  bool
+	gid     uint32
+	hasGID  bool
+	custom  map[string]interface{}
 }
 
 // We can't just export EntryAttributes' fields because there's no way
@@ -226,6 +231,92 @@ func (a *EntryAttributes) SetSize(size uint64) *EntryAttributes {
 	return a
 }
 
+// HasUID returns true if the entry has an owner's user ID
+func (a *EntryAttributes) HasUID() bool {
+	return a.hasUID
+}
+
+// UID returns the user ID of the entry's owner
+func (a *EntryAttributes) UID() uint32 {
+	return a.uid
+}
+
+// SetUID sets the user ID of the entry's owner. It should only be set
+// when the plugin knows the entry's real owner, like a file's owner on
+// a remote filesystem.
+func (a *EntryAttributes) SetUID(uid uint32) *EntryAttributes {
+	a.uid = uid
+	a.hasUID = true
+	return a
+}
+
+// HasGID returns true if the entry has an owner's group ID
+func (a *EntryAttributes) HasGID() bool {
+	return a.hasGID
+}
+
+// GID returns the group ID of the entry's owner
+func (a *EntryAttributes) GID() uint32 {
+	return a.gid
+}
+
+// SetGID sets the group ID of the entry's owner. It should only be set
+// when the plugin knows the entry's real group.
+func (a *EntryAttributes) SetGID(gid uint32) *EntryAttributes {
+	a.gid = gid
+	a.hasGID = true
+	return a
+}
+
+// HasCustom returns true if the entry has the named custom attribute
+func (a *EntryAttributes) HasCustom(name string) bool {
+	_, ok := a.custom[name]
+	return ok
+}
+
+// Custom returns the value of the named custom attribute. Numeric values
+// are returned as float64s.
+func (a *EntryAttributes) Custom(name string) interface{} {
+	return a.custom[name]
+}
+
+// CustomAttributes returns the entry's custom attributes
+func (a *EntryAttributes) CustomAttributes() map[string]interface{} {
+	return a.custom
+}
+
+// SetCustom sets the named custom attribute. Custom attributes should be
+// declared in the entry's schema via EntrySchema#AddCustomAttribute. The
+// value must be a string, a number, a boolean or a time.Time. SetCustom
+// panics if it isn't.
+func (a *EntryAttributes) SetCustom(name string, value interface{}) *EntryAttributes {
+	switch v := value.(type) {
+	case string, bool, float64, time.Time:
+	case int:
+		value = float64(v)
+	case int32:
+		value = float64(v)
+	case int64:
+		value = float64(v)
+	case uint:
+		value = float64(v)
+	case uint32:
+		value = float64(v)
+	case uint64:
+		value = float64(v)
+	case float32:
+		value = float64(v)
+	default:
+		msg := fmt.Sprintf("a.SetCustom: the %v custom attribute has a value of unsupported type %T", name, value)
+		panic(msg)
+	}
+	if a.custom == nil {
+		a.custom = make(map[string]interface{})
+	}
+	a.custom[name] = value
+	return a
+}
+
 // ToMap converts the entry's attributes to a map, which makes it easier to write
 // generic code on them.
 func (a *EntryAttributes) ToMap() map[string]interface{} {
@@ -253,6 +344,15 @@ func (a *EntryAttributes) ToMap() map[string]interface{} {
 	if a.HasSize() {
 		mp["size"] = a.Size()
 	}
+	if a.HasUID() {
+		mp["uid"] = a.UID()
+	}
+	if a.HasGID() {
+		mp["gid"] = a.GID()
+	}
+	if len(a.custom) > 0 {
+		mp["custom"] = a.custom
+	}
 	return mp
 }
 
@@ -342,6 +442,34 @@ func (a *EntryAttributes) UnmarshalJSON(data []byte) error {
 		}
 		a.SetSize(sz)
 	}
+	if uid, ok := mp["uid"]; ok {
+		id, err := munge.ToID(uid)
+		if err != nil {
+			return attrMungeError("uid", err)
+		}
+		a.SetUID(id)
+	}
+	if gid, ok := mp["gid"]; ok {
+		id, err := munge.ToID(gid)
+		if err != nil {
+			return attrMungeError("gid", err)
+		}
+		a.SetGID(id)
+	}
+	if obj, ok := mp["custom"]; ok {
+		custom, ok := obj.(map[string]interface{})
+		if !ok {
+			return attrMungeError("custom", fmt.Errorf("custom must be an object"))
+		}
+		for name, value := range custom {
+			switch value.(type) {
+			case string, bool, float64:
+				a.SetCustom(name, value)
+			default:
+				return attrMungeError("custom", fmt.Errorf("%v must be a string, number or boolean", name))
+			}
+		}
+	}
 	return nil
 }
 
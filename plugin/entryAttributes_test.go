@@ -137,6 +137,46 @@ func (suite *EntryAttributesTestSuite) TestEntryAttributes() {
 	suite.Equal(true, attr.HasSize())
 	suite.Equal(expectedMp, attr.ToMap())
 	doUnmarshalJSONTests()
+
+	// Tests for UID
+	suite.Equal(false, attr.HasUID())
+	suite.Equal(expectedMp, attr.ToMap())
+	uid := uint32(1000)
+	attr.SetUID(uid)
+	expectedMp["uid"] = uid
+	suite.Equal(uid, attr.UID())
+	suite.Equal(true, attr.HasUID())
+	suite.Equal(expectedMp, attr.ToMap())
+	doUnmarshalJSONTests()
+
+	// Tests for GID
+	suite.Equal(false, attr.HasGID())
+	suite.Equal(expectedMp, attr.ToMap())
+	gid := uint32(0)
+	attr.SetGID(gid)
+	expectedMp["gid"] = gid
+	suite.Equal(gid, attr.GID())
+	suite.Equal(true, attr.HasGID())
+	suite.Equal(expectedMp, attr.ToMap())
+	doUnmarshalJSONTests()
+
+	// Tests for custom attributes
+	suite.Equal(false, attr.HasCustom("restarts"))
+	suite.Equal(expectedMp, attr.ToMap())
+	attr.SetCustom("restarts", int32(3))
+	attr.SetCustom("region", "us-west-1")
+	expectedMp["custom"] = map[string]interface{}{"restarts": float64(3), "region": "us-west-1"}
+	suite.Equal(float64(3), attr.Custom("restarts"))
+	suite.Equal(true, attr.HasCustom("restarts"))
+	suite.Equal(expectedMp, attr.ToMap())
+	doUnmarshalJSONTests()
+	suite.Panics(func() { attr.SetCustom("tags", []string{"foo"}) })
+}
+
+func (suite *EntryAttributesTestSuite) TestUnmarshalJSON_CustomAttributeErrors() {
+	attr := EntryAttributes{}
+	suite.Regexp("custom must be an object", json.Unmarshal([]byte(`{"custom":"foo"}`), &attr))
+	suite.Regexp("foo must be a string, number or boolean", json.Unmarshal([]byte(`{"custom":{"foo":[]}}`), &attr))
 }
 
 func TestEntryAttributes(t *testing.T) {
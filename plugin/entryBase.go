@@ -3,6 +3,7 @@ package plugin
 import (
 	"context"
 	"flag"
+	"fmt"
 	"time"
 
 	"github.com/puppetlabs/wash/activity"
@@ -41,11 +42,13 @@ type EntryBase struct {
 	attributes               EntryAttributes
 	specifiedPartialMetadata JSONObject
 	slashReplacer            rune
+	cname                    string
 	id                       string
 	ttl                      [3]time.Duration
 	wrappedTypes             SchemaMap
 	isPrefetched             bool
 	isInaccessible           bool
+	validator                Validator
 }
 
 // NewEntry creates a new entry
@@ -55,8 +58,7 @@ func NewEntry(name string) EntryBase {
 	}
 
 	e := EntryBase{
-		name:          name,
-		slashReplacer: '#',
+		name: name,
 	}
 	for op := range e.ttl {
 		e.SetTTLOf(defaultOpCode(op), 15*time.Second)
@@ -139,6 +141,13 @@ func (e *EntryBase) SetPartialMetadata(obj interface{}) *EntryBase {
 	return e
 }
 
+// SetValidator sets the validator of the entry's content, as reported by the
+// plugin API's List endpoint. See plugin.Validator for more details.
+func (e *EntryBase) SetValidator(v Validator) *EntryBase {
+	e.validator = v
+	return e
+}
+
 // MarkInaccessible sets the inaccessible attribute and logs a message about why the entry is
 // inaccessible.
 func (e *EntryBase) MarkInaccessible(ctx context.Context, err error) {
@@ -162,13 +171,14 @@ func (e *EntryBase) Prefetched() *EntryBase {
 }
 
 /*
-SetSlashReplacer overrides the default '/' replacer '#' to char.
-The '/' replacer is used when determining the entry's cname. See
-plugin.CName for more details.
+SetSlashReplacer replaces the '/'es in the entry's cname with char instead
+of percent-encoding them. See plugin.CName for more details. char can't be
+'/', '%', '.' or NUL because the cname couldn't be reversed.
 */
 func (e *EntryBase) SetSlashReplacer(char rune) *EntryBase {
-	if char == '/' {
-		panic("e.SetSlashReplacer called with '/'")
+	switch char {
+	case '/', '%', '.', 0:
+		panic(fmt.Sprintf("e.SetSlashReplacer called with %q", char))
 	}
 
 	e.slashReplacer = char
@@ -66,10 +66,12 @@ func (suite *EntryBaseTestSuite) TestMetadata() {
 func (suite *EntryBaseTestSuite) TestSetSlashReplacer() {
 	e := NewEntry("foo/bar")
 
-	suite.Panics(
-		func() { e.SetSlashReplacer('/') },
-		"e.SetSlashReplacer called with '/'",
-	)
+	for _, char := range []rune{'/', '%', '.', 0} {
+		suite.Panics(
+			func() { e.SetSlashReplacer(char) },
+			"e.SetSlashReplacer called with %q", char,
+		)
+	}
 
 	e.SetSlashReplacer(':')
 	suite.Equal(e.slashReplacer, ':')
@@ -3,6 +3,7 @@ package plugin
 import (
 	"context"
 	"io"
+	"sync"
 )
 
 // entryContent is the cached result of a Read invocation
@@ -45,11 +46,26 @@ func (c *entryContentImpl) size() uint64 {
 
 type blockReadFunc = func(context.Context, int64, int64) ([]byte, error)
 
+// MaxMemoizedBlockBytes is the maximum number of bytes that Wash memoizes for
+// a single BlockReadable entry with a validator. See plugin.Validator.
+var MaxMemoizedBlockBytes int64 = 32 * 1024 * 1024
+
+type blockKey struct {
+	size   int64
+	offset int64
+}
+
 // blockReadableEntryContent is the implementation of entryContent that's
-// meant for BlockReadable entries. For now, it doesn't cache any content.
+// meant for BlockReadable entries. It doesn't cache any content unless the
+// entry has a validator, in which case it memoizes the read blocks (up to
+// MaxMemoizedBlockBytes) so that they can be re-used for as long as the
+// validator's unchanged.
 type blockReadableEntryContent struct {
-	readFunc blockReadFunc
-	sz       uint64
+	readFunc      blockReadFunc
+	sz            uint64
+	mux           sync.Mutex
+	blocks        map[blockKey][]byte
+	memoizedBytes int64
 }
 
 func newBlockReadableEntryContent(readFunc blockReadFunc) *blockReadableEntryContent {
@@ -63,7 +79,33 @@ func newBlockReadableEntryContent(readFunc blockReadFunc) *blockReadableEntryCon
 // wasn't set, then it is the responsibility of the plugin's API to raise the error
 // for us.
 func (c *blockReadableEntryContent) read(ctx context.Context, size int64, offset int64) ([]byte, error) {
-	return c.readFunc(ctx, size, offset)
+	if c.blocks == nil {
+		return c.readFunc(ctx, size, offset)
+	}
+
+	key := blockKey{size: size, offset: offset}
+	c.mux.Lock()
+	data, ok := c.blocks[key]
+	c.mux.Unlock()
+	if ok {
+		return data, nil
+	}
+
+	data, err := c.readFunc(ctx, size, offset)
+	if err != nil {
+		return data, err
+	}
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if c.memoizedBytes+int64(len(data)) <= MaxMemoizedBlockBytes {
+		c.blocks[key] = data
+		c.memoizedBytes += int64(len(data))
+	}
+	return data, nil
+}
+
+func (c *blockReadableEntryContent) memoizeBlocks() {
+	c.blocks = make(map[blockKey][]byte)
 }
 
 func (c *blockReadableEntryContent) size() uint64 {
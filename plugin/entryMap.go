@@ -7,6 +7,9 @@ import "sync"
 type EntryMap struct {
 	mp  map[string]Entry
 	mux sync.RWMutex
+	// lookup looks up the virtual children that aren't in mp, e.g. a Searchable
+	// entry's search directory. It's nil if there aren't any.
+	lookup func(cname string) (Entry, bool)
 }
 
 func newEntryMap() *EntryMap {
@@ -15,12 +18,16 @@ func newEntryMap() *EntryMap {
 	}
 }
 
-// Load retrieves an entry
+// Load retrieves an entry. Virtual children like a Searchable entry's search
+// directory can be loaded even though Range doesn't iterate over them.
 func (m *EntryMap) Load(cname string) (Entry, bool) {
 	m.mux.RLock()
-	defer m.mux.RUnlock()
-
 	entry, ok := m.mp[cname]
+	m.mux.RUnlock()
+
+	if !ok && m.lookup != nil {
+		return m.lookup(cname)
+	}
 	return entry, ok
 }
 
@@ -72,7 +72,7 @@ func schema(e Entry) (*EntrySchema, error) {
 		// We start by putting in a stub value for s so that we preserve the insertion
 		// order. We'll then update this value once the "Children" array's been calculated.
 		schema.graph.Put(typeID, EntrySchema{})
-		for _, root := range t.pluginRoots {
+		for _, root := range t.roots() {
 			childSchema, err := Schema(root)
 			if err != nil {
 				return nil, fmt.Errorf("failed to retrieve the %v plugin's schema: %v", root.eb().name, err)
@@ -105,14 +105,17 @@ func schema(e Entry) (*EntrySchema, error) {
 }
 
 type entrySchema struct {
-	Label                 string         `json:"label"`
-	Description           string         `json:"description,omitempty"`
-	Singleton             bool           `json:"singleton"`
-	Signals               []SignalSchema `json:"signals,omitempty"`
-	Actions               []string       `json:"actions"`
-	PartialMetadataSchema *JSONSchema    `json:"partial_metadata_schema"`
-	MetadataSchema        *JSONSchema    `json:"metadata_schema"`
-	Children              []string       `json:"children"`
+	Label                 string                  `json:"label"`
+	Description           string                  `json:"description,omitempty"`
+	Singleton             bool                    `json:"singleton"`
+	Signals               []SignalSchema          `json:"signals,omitempty"`
+	CustomAttributes      []CustomAttributeSchema `json:"custom_attributes,omitempty"`
+	ConsoleURL            string                  `json:"console_url,omitempty"`
+	ListCost              ListCost                `json:"list_cost,omitempty"`
+	Actions               []string                `json:"actions"`
+	PartialMetadataSchema *JSONSchema             `json:"partial_metadata_schema"`
+	MetadataSchema        *JSONSchema             `json:"metadata_schema"`
+	Children              []string                `json:"children"`
 }
 
 // EntrySchema represents an entry's schema. Use plugin.NewEntrySchema
@@ -240,6 +243,64 @@ func (s *EntrySchema) addSignalSchema(name string, regex string, description str
 	return s
 }
 
+// AddCustomAttribute adds the given custom attribute to s' custom attributes.
+// Custom attributes are cheap, typed properties of the entry that are set via
+// EntryAttributes#SetCustom. Declaring them here makes them discoverable, and
+// lets RQL queries reference them via the "attr" primary.
+func (s *EntrySchema) AddCustomAttribute(name string, t CustomAttributeType, description string) *EntrySchema {
+	if len(name) <= 0 {
+		panic("s.AddCustomAttribute: received empty name")
+	}
+	if len(description) <= 0 {
+		panic("s.AddCustomAttribute: received empty description")
+	}
+	if err := t.validate(); err != nil {
+		msg := fmt.Sprintf("s.AddCustomAttribute: %v", err)
+		panic(msg)
+	}
+	for _, attr := range s.CustomAttributes {
+		if attr.Name() == name {
+			msg := fmt.Sprintf("s.AddCustomAttribute: the %v custom attribute was already added", name)
+			panic(msg)
+		}
+	}
+	s.CustomAttributes = append(s.CustomAttributes, CustomAttributeSchema{
+		customAttributeSchema: customAttributeSchema{
+			Name:        name,
+			Type:        t,
+			Description: description,
+		},
+	})
+	return s
+}
+
+// SetConsoleURL sets the template of the URL of the entry's page in its
+// backend's web console. It's used by `wash open`. The template's a Go
+// text/template that's executed with a ConsoleURLData object, e.g.
+// "https://example.com/things/{{.Metadata.id}}?region={{.Attributes.region}}".
+// SetConsoleURL will panic if the template can't be parsed.
+func (s *EntrySchema) SetConsoleURL(template string) *EntrySchema {
+	if _, err := ParseConsoleURL(template); err != nil {
+		msg := fmt.Sprintf("s.SetConsoleURL: received an invalid template: %v", err)
+		panic(msg)
+	}
+	s.entrySchema.ConsoleURL = template
+	return s
+}
+
+// SetListCost sets how expensive it is to list the entry's children. Set it on
+// entries whose listing is slow or billed so that traversals like `wash find`
+// walk them last. SetListCost will panic if cost isn't CheapList or
+// ExpensiveList.
+func (s *EntrySchema) SetListCost(cost ListCost) *EntrySchema {
+	if err := cost.validate(); err != nil {
+		msg := fmt.Sprintf("s.SetListCost: %v", err)
+		panic(msg)
+	}
+	s.entrySchema.ListCost = cost
+	return s
+}
+
 // SetPartialMetadataSchema sets the partial metadata's schema. obj is an empty
 // struct that will be marshalled into a JSON schema. SetPartialMetadataSchema
 // will panic if obj is not a struct.
@@ -0,0 +1,132 @@
+package etcd
+
+import (
+	"context"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/puppetlabs/wash/plugin"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// dir is a prefix of keys that ends in a slash.
+type dir struct {
+	plugin.EntryBase
+	client *clientv3.Client
+	prefix string
+}
+
+func newDir(client *clientv3.Client, name string, prefix string) *dir {
+	d := &dir{
+		EntryBase: plugin.NewEntry(name),
+	}
+	d.client = client
+	d.prefix = prefix
+	return d
+}
+
+func (d *dir) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(d, "dir").
+		SetDescription(dirDescription)
+}
+
+func (d *dir) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&dir{}).Schema(),
+		(&key{}).Schema(),
+	}
+}
+
+func (d *dir) List(ctx context.Context) ([]plugin.Entry, error) {
+	return list(ctx, d.client, d.prefix)
+}
+
+func (d *dir) Create(ctx context.Context, name string, _ os.FileMode) (plugin.Writable, error) {
+	return newKey(d.client, name, d.prefix+name, nil), nil
+}
+
+// Delete deletes the keys with the directory's prefix.
+func (d *dir) Delete(ctx context.Context) (bool, error) {
+	_, err := d.client.Delete(ctx, d.prefix, clientv3.WithPrefix())
+	return err == nil, err
+}
+
+// Stream streams the changes to the keys with the directory's prefix.
+func (d *dir) Stream(ctx context.Context) (io.ReadCloser, error) {
+	return watch(ctx, d.client, d.prefix, clientv3.WithPrefix())
+}
+
+// list lists the directories and keys directly under prefix. etcd doesn't have
+// directories, so this gets every key with the prefix. It only gets their
+// metadata, since their values could be large.
+func list(ctx context.Context, client *clientv3.Client, prefix string) ([]plugin.Entry, error) {
+	resp, err := client.Get(ctx, prefix, clientv3.WithPrefix(), clientv3.WithKeysOnly())
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := children(prefix, resp.Kvs)
+	entries := make([]plugin.Entry, len(nodes))
+	for i, n := range nodes {
+		if n.kv == nil {
+			entries[i] = newDir(client, n.name, n.prefix)
+		} else {
+			entries[i] = newKey(client, n.name, string(n.kv.Key), n.kv)
+		}
+	}
+	return entries, nil
+}
+
+// child is a directory (with a prefix) or a key (with a kv) under a prefix.
+type child struct {
+	name   string
+	prefix string
+	kv     *mvccpb.KeyValue
+}
+
+// children splits the keys with prefix into the directories and keys directly
+// under it, sorted by name. Repeated slashes (including a key's leading slash,
+// like Kubernetes' /registry keys) are treated as one separator. When a key has
+// the same name as a directory, like foo and foo/bar, only the directory is
+// listed; the key can't be a file in the hierarchy. A key that's the same as the
+// prefix also can't be listed.
+func children(prefix string, kvs []*mvccpb.KeyValue) []child {
+	var nodes []child
+	seen := make(map[string]int)
+	for _, kv := range kvs {
+		rest := strings.TrimPrefix(string(kv.Key), prefix)
+		trimmed := strings.TrimLeft(rest, "/")
+		if trimmed == "" {
+			continue
+		}
+		lead := rest[:len(rest)-len(trimmed)]
+
+		var node child
+		if i := strings.Index(trimmed, "/"); i >= 0 {
+			node = child{name: trimmed[:i], prefix: prefix + lead + trimmed[:i] + "/"}
+		} else {
+			node = child{name: trimmed, kv: kv}
+		}
+		if ix, ok := seen[node.name]; ok {
+			if node.kv == nil && nodes[ix].kv != nil {
+				nodes[ix] = node
+			}
+			continue
+		}
+		seen[node.name] = len(nodes)
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].name < nodes[j].name
+	})
+	return nodes
+}
+
+const dirDescription = `
+This is a prefix of keys that ends in a slash. Streaming it shows the changes to
+the keys under it, and deleting it deletes all of them.
+`
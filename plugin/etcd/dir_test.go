@@ -0,0 +1,42 @@
+package etcd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+func kvs(keys ...string) []*mvccpb.KeyValue {
+	result := make([]*mvccpb.KeyValue, len(keys))
+	for i, key := range keys {
+		result[i] = &mvccpb.KeyValue{Key: []byte(key)}
+	}
+	return result
+}
+
+func TestChildren(t *testing.T) {
+	nodes := children("", kvs("/registry/pods/a", "/registry/services/b", "config", "config/port", "name"))
+	assert.Equal(t, []child{
+		{name: "config", prefix: "config/"},
+		{name: "name", kv: &mvccpb.KeyValue{Key: []byte("name")}},
+		{name: "registry", prefix: "/registry/"},
+	}, nodes)
+
+	nodes = children("/registry/", kvs("/registry/", "/registry/pods/a", "/registry/pods/b", "/registry/version"))
+	assert.Equal(t, []child{
+		{name: "pods", prefix: "/registry/pods/"},
+		{name: "version", kv: &mvccpb.KeyValue{Key: []byte("/registry/version")}},
+	}, nodes)
+
+	assert.Empty(t, children("missing/", nil))
+}
+
+func TestFormatEvent(t *testing.T) {
+	put := &clientv3.Event{Type: clientv3.EventTypePut, Kv: &mvccpb.KeyValue{Key: []byte("/config/port"), Value: []byte("8080\n")}}
+	assert.Equal(t, "PUT /config/port \"8080\\n\"\n", formatEvent(put))
+
+	del := &clientv3.Event{Type: clientv3.EventTypeDelete, Kv: &mvccpb.KeyValue{Key: []byte("/config/port")}}
+	assert.Equal(t, "DELETE /config/port\n", formatEvent(del))
+}
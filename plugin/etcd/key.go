@@ -0,0 +1,105 @@
+package etcd
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/puppetlabs/wash/plugin"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// key is a key. Its content is the key's value.
+type key struct {
+	plugin.EntryBase
+	client *clientv3.Client
+	key    string
+}
+
+// keyMetadata is a key's revision info, which is everything but its value.
+type keyMetadata struct {
+	Key            string `json:"key"`
+	CreateRevision int64  `json:"create_revision"`
+	ModRevision    int64  `json:"mod_revision"`
+	Version        int64  `json:"version"`
+	Lease          int64  `json:"lease,omitempty"`
+}
+
+func toKeyMetadata(kv *mvccpb.KeyValue) keyMetadata {
+	return keyMetadata{
+		Key:            string(kv.Key),
+		CreateRevision: kv.CreateRevision,
+		ModRevision:    kv.ModRevision,
+		Version:        kv.Version,
+		Lease:          kv.Lease,
+	}
+}
+
+// newKey creates a key. kv is nil for keys that haven't been put yet.
+func newKey(client *clientv3.Client, name string, k string, kv *mvccpb.KeyValue) *key {
+	ky := &key{
+		EntryBase: plugin.NewEntry(name),
+	}
+	ky.client = client
+	ky.key = k
+	if kv != nil {
+		ky.SetPartialMetadata(toKeyMetadata(kv))
+	}
+	return ky
+}
+
+func (k *key) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(k, "key").
+		SetDescription(keyDescription).
+		SetPartialMetadataSchema(keyMetadata{})
+}
+
+func (k *key) get(ctx context.Context, opts ...clientv3.OpOption) (*mvccpb.KeyValue, error) {
+	resp, err := k.client.Get(ctx, k.key, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, fmt.Errorf("key %v not found", k.key)
+	}
+	return resp.Kvs[0], nil
+}
+
+func (k *key) Read(ctx context.Context) ([]byte, error) {
+	kv, err := k.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return kv.Value, nil
+}
+
+func (k *key) Write(ctx context.Context, b []byte) error {
+	_, err := k.client.Put(ctx, k.key, string(b))
+	return err
+}
+
+func (k *key) Delete(ctx context.Context) (bool, error) {
+	_, err := k.client.Delete(ctx, k.key)
+	return err == nil, err
+}
+
+func (k *key) Metadata(ctx context.Context) (plugin.JSONObject, error) {
+	kv, err := k.get(ctx, clientv3.WithKeysOnly())
+	if err != nil {
+		return nil, err
+	}
+	return plugin.ToJSONObject(toKeyMetadata(kv)), nil
+}
+
+// Stream streams the key's changes.
+func (k *key) Stream(ctx context.Context) (io.ReadCloser, error) {
+	return watch(ctx, k.client, k.key)
+}
+
+const keyDescription = `
+This is a key. Its content is the key's value, and writing to it puts the key.
+Its metadata is the key's revisions and lease. Streaming it shows the key's
+changes as they happen.
+`
@@ -0,0 +1,109 @@
+// Package etcd presents a filesystem hierarchy for an etcd cluster's key space.
+// Keys are split on '/' into directories and files.
+//
+// It uses the ETCDCTL_ENDPOINTS environment variable to find the cluster, and
+// falls back to localhost:2379 like etcdctl.
+package etcd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/puppetlabs/wash/plugin"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// Root of the etcd plugin
+type Root struct {
+	plugin.EntryBase
+	client *clientv3.Client
+}
+
+// Init for root
+func (r *Root) Init(cfg map[string]interface{}) error {
+	endpoints := []string{"localhost:2379"}
+	if env := os.Getenv("ETCDCTL_ENDPOINTS"); env != "" {
+		endpoints = strings.Split(env, ",")
+	}
+	if endpointsI, ok := cfg["endpoints"]; ok {
+		endpointsArr, ok := endpointsI.([]interface{})
+		if !ok {
+			return fmt.Errorf("etcd.endpoints config must be an array of strings, not %v", endpointsI)
+		}
+		endpoints = make([]string, len(endpointsArr))
+		for i, elem := range endpointsArr {
+			endpoint, ok := elem.(string)
+			if !ok {
+				return fmt.Errorf("etcd.endpoints config must be an array of strings, not %v", endpointsI)
+			}
+			endpoints[i] = endpoint
+		}
+	}
+
+	client, err := clientv3.New(clientv3.Config{
+		Endpoints:   endpoints,
+		DialTimeout: 5 * time.Second,
+		Username:    os.Getenv("ETCDCTL_USER"),
+		Password:    os.Getenv("ETCDCTL_PASSWORD"),
+	})
+	if err != nil {
+		return err
+	}
+	r.client = client
+
+	r.EntryBase = plugin.NewEntry("etcd")
+	return nil
+}
+
+// Schema returns the root's schema
+func (r *Root) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(r, "etcd").
+		SetDescription(rootDescription).
+		IsSingleton()
+}
+
+// ChildSchemas returns the root's child schema
+func (r *Root) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&dir{}).Schema(),
+		(&key{}).Schema(),
+	}
+}
+
+// List lists the top-level directories and keys.
+func (r *Root) List(ctx context.Context) ([]plugin.Entry, error) {
+	return list(ctx, r.client, "")
+}
+
+// Create creates a top-level key.
+func (r *Root) Create(ctx context.Context, name string, _ os.FileMode) (plugin.Writable, error) {
+	return newKey(r.client, name, name, nil), nil
+}
+
+// Stream streams the changes to every key.
+func (r *Root) Stream(ctx context.Context) (io.ReadCloser, error) {
+	return watch(ctx, r.client, "", clientv3.WithPrefix())
+}
+
+const rootDescription = `
+This is the etcd plugin root. It presents the cluster's key space as a
+hierarchy by splitting keys on '/'. Keys are files whose content is the key's
+value, and writing a file puts its key. Streaming a directory (e.g. with
+'tail -f') shows the changes to the keys under it as they happen.
+
+The cluster is found via the ETCDCTL_ENDPOINTS environment variable, or the
+etcd.endpoints config, e.g.
+
+etcd:
+  endpoints:
+  - etcd1.example.com:2379
+  - etcd2.example.com:2379
+
+It defaults to localhost:2379. The ETCDCTL_USER and ETCDCTL_PASSWORD
+environment variables are used for authentication.
+`
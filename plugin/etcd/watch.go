@@ -0,0 +1,63 @@
+package etcd
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/puppetlabs/wash/activity"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// watch streams the changes to key, or to the keys with the prefix key if opts
+// include clientv3.WithPrefix. Each change is written as a line. The stream
+// ends when ctx is done or the reader's closed.
+func watch(ctx context.Context, client *clientv3.Client, key string, opts ...clientv3.OpOption) (io.ReadCloser, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	// Requiring a leader makes the watch fail if the cluster loses quorum,
+	// rather than silently missing changes.
+	watchCh := client.Watch(clientv3.WithRequireLeader(ctx), key, opts...)
+
+	activity.Record(ctx, "Watching %v", key)
+	r, w := io.Pipe()
+	go func() {
+		defer cancel()
+		for resp := range watchCh {
+			if err := resp.Err(); err != nil {
+				activity.Record(ctx, "Watch on %v failed: %v", key, err)
+				w.CloseWithError(err)
+				return
+			}
+			for _, event := range resp.Events {
+				if _, err := io.WriteString(w, formatEvent(event)); err != nil {
+					// The reader's closed.
+					return
+				}
+			}
+		}
+		w.Close()
+	}()
+	return &watchReader{PipeReader: r, cancel: cancel}, nil
+}
+
+// formatEvent formats a change as its type, its key and its quoted value, e.g.
+//
+//	PUT /config/port "8080"
+//	DELETE /config/port
+func formatEvent(event *clientv3.Event) string {
+	if event.Type == clientv3.EventTypeDelete {
+		return fmt.Sprintf("%v %s\n", event.Type, event.Kv.Key)
+	}
+	return fmt.Sprintf("%v %s %q\n", event.Type, event.Kv.Key, event.Kv.Value)
+}
+
+// watchReader cancels its watch when it's closed.
+type watchReader struct {
+	*io.PipeReader
+	cancel context.CancelFunc
+}
+
+func (r *watchReader) Close() error {
+	r.cancel()
+	return r.PipeReader.Close()
+}
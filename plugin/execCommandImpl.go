@@ -3,6 +3,8 @@ package plugin
 import (
 	"context"
 	"fmt"
+
+	"github.com/puppetlabs/wash/activity"
 )
 
 /*
@@ -25,6 +27,8 @@ related cleanup for you. plugin.NewExecCommand ensures that the OutputCh
 is closed upon context-cancellation so that clients streaming your command's
 output are not blocked. SetStopFunc ensures that the exec'ing command is
 stopped upon context-cancellation, which prevents orphaned processes.
+SetResizeFunc resizes the command's TTY as the caller's terminal is resized
+during an interactive session.
 
 See Container#Exec in the Docker plugin and ExecSSH in the transport
 package for examples of how ExecCommandImpl is used.
@@ -91,6 +95,31 @@ func (cmd *ExecCommandImpl) SetStopFunc(stopFunc func()) {
 	}
 }
 
+// SetResizeFunc sets the function that resizes the command's TTY. resizeFunc
+// is called with each size that's received on resizeCh until cmd.ctx is
+// cancelled. resizeCh should be the ExecOptions#Resize channel, and it can be
+// nil.
+func (cmd *ExecCommandImpl) SetResizeFunc(resizeCh <-chan WindowSize, resizeFunc func(WindowSize) error) {
+	if resizeCh == nil || resizeFunc == nil {
+		return
+	}
+	go func() {
+		for {
+			select {
+			case <-cmd.ctx.Done():
+				return
+			case size, ok := <-resizeCh:
+				if !ok {
+					return
+				}
+				if err := resizeFunc(size); err != nil {
+					activity.Record(cmd.ctx, "Failed to resize the TTY to %vx%v: %v", size.Cols, size.Rows, err)
+				}
+			}
+		}
+	}()
+}
+
 // Stdout returns the command's stdout stream. Attach this to your
 // plugin API's stdout stream.
 func (cmd *ExecCommandImpl) Stdout() *OutputStream {
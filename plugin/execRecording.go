@@ -0,0 +1,59 @@
+package plugin
+
+import (
+	"context"
+	"strings"
+
+	"github.com/puppetlabs/wash/activity"
+)
+
+// recordedExecCommand is an ExecCommand whose output is recorded by a session
+// recording as it's consumed.
+type recordedExecCommand struct {
+	ExecCommand
+	outputCh chan ExecOutputChunk
+}
+
+// recordExec starts recording cmd's output if session recording is enabled and
+// the command's an interactive session, i.e. one with a TTY. Otherwise, it
+// returns cmd unchanged. Recording failures don't fail the Exec, they're only
+// logged.
+func recordExec(ctx context.Context, e Entry, cmd ExecCommand, command string, args []string, opts ExecOptions) ExecCommand {
+	if !opts.Tty || !activity.RecordingEnabled() {
+		return cmd
+	}
+
+	cmdline := strings.Join(append([]string{command}, args...), " ")
+	recording, err := activity.NewRecording(ctx, e.eb().id, cmdline)
+	if err != nil {
+		activity.Warnf(ctx, "Unable to record the session on %v: %v", e.eb().id, err)
+		return cmd
+	}
+
+	recorded := &recordedExecCommand{
+		ExecCommand: cmd,
+		outputCh:    make(chan ExecOutputChunk),
+	}
+	go func() {
+		defer close(recorded.outputCh)
+		defer func() {
+			activity.Record(ctx, "Closed recording %v: %v", recording.Path(), recording.Close())
+		}()
+		for chunk := range cmd.OutputCh() {
+			if chunk.Err == nil {
+				if err := recording.Output(chunk.Timestamp, chunk.Data); err != nil {
+					activity.Warnf(ctx, "Unable to record output to %v: %v", recording.Path(), err)
+				}
+			}
+			select {
+			case recorded.outputCh <- chunk:
+			case <-ctx.Done():
+			}
+		}
+	}()
+	return recorded
+}
+
+func (c *recordedExecCommand) OutputCh() <-chan ExecOutputChunk {
+	return c.outputCh
+}
@@ -0,0 +1,61 @@
+package plugin
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/puppetlabs/wash/activity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordExec_OnlyRecordsTtySessionsWhenEnabled(t *testing.T) {
+	ctx := context.Background()
+	e := newMockEntry("foo")
+	cmd := NewExecCommand(ctx)
+
+	assert.Equal(t, cmd, recordExec(ctx, e, cmd, "sh", nil, ExecOptions{Tty: true}))
+
+	activity.SetRecordingOptions(activity.RecordingOptions{Enabled: true})
+	defer activity.SetRecordingOptions(activity.RecordingOptions{})
+	assert.Equal(t, cmd, recordExec(ctx, e, cmd, "sh", nil, ExecOptions{}))
+}
+
+func TestRecordExec_RecordsOutput(t *testing.T) {
+	dir, err := ioutil.TempDir("", "recording_tests")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	oldDir := activity.Dir()
+	activity.SetDir(dir)
+	defer activity.SetDir(oldDir)
+	activity.SetRecordingOptions(activity.RecordingOptions{Enabled: true})
+	defer activity.SetRecordingOptions(activity.RecordingOptions{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cmd := NewExecCommand(ctx)
+	recorded := recordExec(ctx, newMockEntry("foo"), cmd, "sh", []string{"-l"}, ExecOptions{Tty: true})
+	require.NotEqual(t, cmd, recorded)
+
+	go func() {
+		_, _ = cmd.Stdout().Write([]byte("hello"))
+		cmd.CloseStreamsWithError(nil)
+	}()
+	var output string
+	for chunk := range recorded.OutputCh() {
+		output += chunk.Data
+	}
+	assert.Equal(t, "hello", output)
+
+	recordings, err := filepath.Glob(filepath.Join(activity.RecordingsDir(), "*.cast"))
+	require.NoError(t, err)
+	if assert.Len(t, recordings, 1) {
+		bits, err := ioutil.ReadFile(recordings[0])
+		require.NoError(t, err)
+		assert.Contains(t, string(bits), `"command":"sh -l"`)
+		assert.Regexp(t, `\[[0-9.e-]+,"o","hello"\]`, string(bits))
+	}
+}
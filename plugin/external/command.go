@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"os"
 	"os/exec"
 	"sync"
 	"syscall"
@@ -31,7 +32,7 @@ type Command interface {
 type command struct {
 	*exec.Cmd
 	ctx         context.Context
-	pgid        int
+	group       processGroup
 	terminateCh chan struct{}
 	waitResult  error
 	waitDoneCh  chan struct{}
@@ -43,7 +44,8 @@ type command struct {
 // own process group. When the context is cancelled, a SIGTERM signal will
 // be sent to the command's process group. If after five seconds the command's
 // process has not been terminated, then a SIGKILL signal is sent to the
-// command's process group.
+// command's process group. On Windows, the process group is a Job Object, and
+// both signals terminate the job's processes.
 func NewCommand(ctx context.Context, cmd string, args ...string) Command {
 	if ctx == nil {
 		panic("plugin.newCommand called with a nil context")
@@ -51,13 +53,11 @@ func NewCommand(ctx context.Context, cmd string, args ...string) Command {
 	cmdObj := &command{
 		Cmd:         exec.Command(cmd, args...),
 		ctx:         ctx,
-		pgid:        -1,
 		terminateCh: make(chan struct{}),
 		waitDoneCh:  make(chan struct{}),
 	}
-	cmdObj.SysProcAttr = &syscall.SysProcAttr{
-		Setpgid: true,
-	}
+	cmdObj.Cmd.Env = append(os.Environ(), fmt.Sprintf("%v=%v", ProtocolVersionEnv, ProtocolVersion))
+	cmdObj.group.setup(cmdObj.Cmd)
 	return cmdObj
 }
 
@@ -67,13 +67,14 @@ func (cmd *command) Start() error {
 	if err != nil {
 		return err
 	}
-	// Get the command's PGID for logging. If this fails, we'll try
-	// again in cmd.signal() when it is needed.
-	pgid, err := syscall.Getpgid(cmd.Process.Pid)
-	if err != nil {
-		activity.Record(cmd.ctx, "%v: could not get pgid: %v", cmd, err)
-	} else {
-		cmd.pgid = pgid
+	if err := cmd.group.add(cmd.Process); err != nil {
+		activity.Record(cmd.ctx, "%v: %v", cmd, err)
+	}
+	if err := cmd.group.resume(cmd.Process); err != nil {
+		// The process would never run, so kill it and release its resources.
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return err
 	}
 	// Setup the context-cancellation cleanup
 	go func() {
@@ -119,9 +120,7 @@ func (cmd *command) String() string {
 	if cmd.Process != nil {
 		str += fmt.Sprintf("(PID %v) ", cmd.Process.Pid)
 	}
-	if cmd.pgid >= 0 {
-		str += fmt.Sprintf("(PGID %v) ", cmd.pgid)
-	}
+	str += cmd.group.String()
 	str += shellquote.Join(cmd.Args...)
 	return str
 }
@@ -154,6 +153,7 @@ func (cmd *command) Wait() error {
 	// our own version.
 	cmd.waitOnce.Do(func() {
 		cmd.waitResult = cmd.Cmd.Wait()
+		cmd.group.close()
 		close(cmd.waitDoneCh)
 	})
 	return cmd.waitResult
@@ -163,19 +163,7 @@ func (cmd *command) signal(sig syscall.Signal) error {
 	if cmd.Process == nil {
 		panic("cmd.signal called with cmd.Process == nil")
 	}
-	if cmd.pgid < 0 {
-		// We failed to get the pgid in cmd.Start(), so try again
-		pgid, err := syscall.Getpgid(cmd.Process.Pid)
-		if err != nil {
-			return fmt.Errorf("could not get pgid: %v", err)
-		}
-		cmd.pgid = pgid
-	}
-	err := syscall.Kill(-cmd.pgid, sig)
-	if err != nil {
-		return err
-	}
-	return nil
+	return cmd.group.signal(cmd.Process, sig)
 }
 
 func (cmd *command) SetStdout(stdout io.Writer) {
@@ -0,0 +1,321 @@
+package external
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/kballard/go-shellquote"
+	"github.com/puppetlabs/wash/activity"
+)
+
+// daemonInvocation is a call to a plugin daemon. It implements invocation so
+// that pluginEntry handles it like a forked invocation: non-streaming calls'
+// results are written to stdout, while stream and exec calls' output is
+// forwarded from the daemon's output notifications.
+type daemonInvocation struct {
+	script *daemonPluginScript
+	ctx    context.Context
+	method string
+	params callParams
+	id     uint64
+
+	stdout, stderr   bytes.Buffer
+	stdoutW, stderrW io.Writer
+	pipes            []*io.PipeWriter
+	stdin            io.Reader
+
+	// Output's queued so that a slow reader doesn't block the daemon's other
+	// calls.
+	outputMux  sync.Mutex
+	outputCond *sync.Cond
+	chunks     []outputChunk
+	finished   bool
+	drainedCh  chan struct{}
+
+	finishOnce    sync.Once
+	finishedCh    chan struct{}
+	exitCode      int
+	err           error
+	terminateOnce sync.Once
+	terminateCh   chan struct{}
+}
+
+type outputChunk struct {
+	w    io.Writer
+	data []byte
+}
+
+func newDaemonInvocation(ctx context.Context, script *daemonPluginScript, method string, params callParams) *daemonInvocation {
+	inv := &daemonInvocation{
+		script:      script,
+		ctx:         ctx,
+		method:      method,
+		params:      params,
+		exitCode:    -1,
+		drainedCh:   make(chan struct{}),
+		finishedCh:  make(chan struct{}),
+		terminateCh: make(chan struct{}),
+	}
+	inv.stdoutW, inv.stderrW = &inv.stdout, &inv.stderr
+	inv.outputCond = sync.NewCond(&inv.outputMux)
+	return inv
+}
+
+// streaming returns whether the call's output is sent in output notifications.
+func (inv *daemonInvocation) streaming() bool {
+	return inv.method == "stream" || inv.method == "exec"
+}
+
+func (inv *daemonInvocation) String() string {
+	args := []string{inv.script.Path(), inv.method}
+	if inv.method != "init" {
+		args = append(args, inv.params.Path, inv.params.State)
+	}
+	return fmt.Sprintf("(Call %v) %v", inv.id, shellquote.Join(append(args, inv.params.Args...)...))
+}
+
+// Start sends the call to the daemon.
+func (inv *daemonInvocation) Start() error {
+	if inv.stdin != nil && !inv.streaming() {
+		input, err := ioutil.ReadAll(inv.stdin)
+		if err != nil {
+			return err
+		}
+		inv.params.Input = input
+	}
+	if err := inv.script.call(inv); err != nil {
+		return err
+	}
+	go inv.drain()
+	if inv.stdin != nil && inv.streaming() {
+		go inv.forwardInput()
+	}
+
+	// Setup the context-cancellation cleanup. Like the fork-per-call
+	// protocol, the daemon has five seconds to stop the call.
+	go func() {
+		var desc string
+		select {
+		case <-inv.finishedCh:
+			return
+		case <-inv.terminateCh:
+			desc = "Command terminated"
+		case <-inv.ctx.Done():
+			desc = "Context cancelled"
+		}
+		activity.Record(inv.ctx, "%v: %s. Sending the cancel notification", inv, desc)
+		if err := inv.script.notify("cancel", cancelParams{ID: inv.id}); err != nil {
+			activity.Record(inv.ctx, "%v: Failed to send the cancel notification: %v", inv, err)
+		}
+		select {
+		case <-inv.finishedCh:
+		case <-time.After(5 * time.Second):
+			activity.Record(inv.ctx, "%v: Did not stop after five seconds. Abandoning the call", inv)
+			inv.script.forget(inv.id)
+			inv.fail(errors.New("the call did not stop after five seconds"))
+		}
+	}()
+	return nil
+}
+
+// forwardInput sends stdin to the daemon in input notifications.
+func (inv *daemonInvocation) forwardInput() {
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := inv.stdin.Read(buf)
+		if n > 0 {
+			data := append([]byte(nil), buf[:n]...)
+			if err := inv.script.notify("input", inputParams{ID: inv.id, Data: data}); err != nil {
+				activity.Record(inv.ctx, "%v: Failed to send input: %v", inv, err)
+				return
+			}
+		}
+		if err != nil {
+			if err != io.EOF {
+				activity.Record(inv.ctx, "%v: Failed to read input: %v", inv, err)
+			}
+			break
+		}
+	}
+	if err := inv.script.notify("input", inputParams{ID: inv.id, EOF: true}); err != nil {
+		activity.Record(inv.ctx, "%v: Failed to close input: %v", inv, err)
+	}
+}
+
+// output queues output from the daemon.
+func (inv *daemonInvocation) output(stream string, data []byte) {
+	w := inv.stdoutW
+	if stream == "stderr" {
+		w = inv.stderrW
+	}
+	inv.outputMux.Lock()
+	defer inv.outputMux.Unlock()
+	if inv.finished {
+		return
+	}
+	inv.chunks = append(inv.chunks, outputChunk{w, data})
+	inv.outputCond.Signal()
+}
+
+// drain writes the queued output until the call's finished.
+func (inv *daemonInvocation) drain() {
+	defer close(inv.drainedCh)
+	for {
+		inv.outputMux.Lock()
+		for len(inv.chunks) == 0 && !inv.finished {
+			inv.outputCond.Wait()
+		}
+		if len(inv.chunks) == 0 {
+			inv.outputMux.Unlock()
+			break
+		}
+		chunk := inv.chunks[0]
+		inv.chunks = inv.chunks[1:]
+		inv.outputMux.Unlock()
+		// Write errors mean that the reader's gone, so the output's dropped.
+		_, _ = chunk.w.Write(chunk.data)
+	}
+	for _, pipe := range inv.pipes {
+		_ = pipe.Close()
+	}
+}
+
+// finish handles the daemon's response.
+func (inv *daemonInvocation) finish(result json.RawMessage, rpcErr *rpcError) {
+	if rpcErr != nil {
+		inv.fail(errors.New(rpcErr.Message))
+		return
+	}
+	var err error
+	exitCode := 0
+	var stdout []byte
+	switch {
+	case inv.method == "exec":
+		var execResult struct {
+			ExitCode int `json:"exitCode"`
+		}
+		if err = json.Unmarshal(result, &execResult); err != nil {
+			exitCode, err = -1, fmt.Errorf("could not decode exec's result: %v", err)
+		} else if exitCode = execResult.ExitCode; exitCode != 0 {
+			err = fmt.Errorf("exit status %v", exitCode)
+		}
+	case inv.method == "stream":
+	case inv.method == "read":
+		// Content can be binary, so it's base64-encoded.
+		if err = json.Unmarshal(result, &stdout); err != nil {
+			exitCode, err = -1, fmt.Errorf("could not decode read's base64-encoded result: %v", err)
+		}
+	case len(result) > 0 && string(result) != "null":
+		stdout = result
+	}
+	inv.complete(exitCode, err, stdout)
+}
+
+// fail finishes the call with an error.
+func (inv *daemonInvocation) fail(err error) {
+	inv.complete(-1, err, nil)
+}
+
+func (inv *daemonInvocation) complete(exitCode int, err error, stdout []byte) {
+	inv.finishOnce.Do(func() {
+		inv.outputMux.Lock()
+		if len(stdout) > 0 {
+			inv.chunks = append(inv.chunks, outputChunk{inv.stdoutW, stdout})
+		}
+		inv.finished = true
+		inv.outputCond.Signal()
+		inv.outputMux.Unlock()
+
+		inv.exitCode, inv.err = exitCode, err
+		close(inv.finishedCh)
+	})
+}
+
+// Run is a wrapper to Start and Wait.
+func (inv *daemonInvocation) Run() error {
+	if err := inv.Start(); err != nil {
+		return err
+	}
+	return inv.Wait()
+}
+
+// RunAndWait runs the call and returns an error if it failed. It records
+// the call like invocationImpl#RunAndWait.
+func (inv *daemonInvocation) RunAndWait(ctx context.Context) error {
+	activity.Record(ctx, "Invoking %v", inv)
+	err := inv.Run()
+	if inv.exitCode < 0 {
+		return newInvokeError(err.Error(), inv)
+	}
+	activity.Record(ctx, "stdout: %v", inv.stdout.String())
+	if inv.stderr.Len() != 0 {
+		activity.Record(ctx, "stderr: %v", inv.stderr.String())
+	}
+	if inv.exitCode != 0 {
+		return newInvokeError(fmt.Sprintf("script returned a non-zero exit code of %v", inv.exitCode), inv)
+	}
+	return nil
+}
+
+// Terminate cancels the call. Its output pipes are closed since the caller's
+// done reading them.
+func (inv *daemonInvocation) Terminate() {
+	inv.terminateOnce.Do(func() {
+		for _, pipe := range inv.pipes {
+			_ = pipe.Close()
+		}
+		close(inv.terminateCh)
+	})
+}
+
+// Wait waits for the call to finish and for its output to be written.
+func (inv *daemonInvocation) Wait() error {
+	<-inv.finishedCh
+	<-inv.drainedCh
+	return inv.err
+}
+
+func (inv *daemonInvocation) SetStdout(stdout io.Writer) {
+	inv.stdoutW = stdout
+}
+
+func (inv *daemonInvocation) SetStderr(stderr io.Writer) {
+	inv.stderrW = stderr
+}
+
+func (inv *daemonInvocation) SetStdin(stdin io.Reader) {
+	inv.stdin = stdin
+}
+
+func (inv *daemonInvocation) StdoutPipe() (io.ReadCloser, error) {
+	r, w := io.Pipe()
+	inv.stdoutW = w
+	inv.pipes = append(inv.pipes, w)
+	return r, nil
+}
+
+func (inv *daemonInvocation) StderrPipe() (io.ReadCloser, error) {
+	r, w := io.Pipe()
+	inv.stderrW = w
+	inv.pipes = append(inv.pipes, w)
+	return r, nil
+}
+
+func (inv *daemonInvocation) ExitCode() int {
+	return inv.exitCode
+}
+
+func (inv *daemonInvocation) Stdout() *bytes.Buffer {
+	return &inv.stdout
+}
+
+func (inv *daemonInvocation) Stderr() *bytes.Buffer {
+	return &inv.stderr
+}
@@ -0,0 +1,385 @@
+package external
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/puppetlabs/wash/plugin"
+	log "github.com/sirupsen/logrus"
+)
+
+// rpcRequest is a JSON-RPC 2.0 request that's sent to a plugin daemon.
+// Notifications don't have an ID.
+type rpcRequest struct {
+	JSONRPC string      `json:"jsonrpc"`
+	ID      *uint64     `json:"id,omitempty"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+// rpcMessage is a message from a plugin daemon. It's either a response to a
+// call or a notification.
+type rpcMessage struct {
+	ID     *uint64         `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// callParams are a method call's params. They mirror the arguments that the
+// script's invoked with in the fork-per-call protocol. Input is write's data.
+type callParams struct {
+	Path  string   `json:"path,omitempty"`
+	State string   `json:"state,omitempty"`
+	Args  []string `json:"args"`
+	Input []byte   `json:"input,omitempty"`
+}
+
+// outputParams are the params of the output notifications that the daemon
+// sends for stream and exec calls. Stream is either stdout or stderr.
+type outputParams struct {
+	ID     uint64 `json:"id"`
+	Stream string `json:"stream"`
+	Data   []byte `json:"data"`
+}
+
+// inputParams are the params of the input notifications that Wash sends with
+// exec's stdin.
+type inputParams struct {
+	ID   uint64 `json:"id"`
+	Data []byte `json:"data,omitempty"`
+	EOF  bool   `json:"eof,omitempty"`
+}
+
+// cancelParams are the params of the cancel notification that Wash sends when
+// a call's cancelled.
+type cancelParams struct {
+	ID uint64 `json:"id"`
+}
+
+// daemonPluginScript is a plugin script that's launched once, with
+// `<plugin_script> daemon`, and is called over JSON-RPC 2.0 on its stdin and
+// stdout. This avoids forking a process for each method invocation. If the
+// daemon exits, then it's relaunched on the next call, and the init call's
+// replayed before any other call.
+type daemonPluginScript struct {
+	path     string
+	mux      sync.Mutex
+	cmd      Command
+	stdin    *outbox
+	nextID   uint64
+	calls    map[uint64]*daemonInvocation
+	initArgs []string
+	// stoppedCh is closed once the running daemon's stopped.
+	stoppedCh chan struct{}
+}
+
+// outbox queues the messages that are written to the daemon's stdin. Writes
+// can block while the daemon's busy, so they're done by a separate goroutine
+// to avoid blocking the calls' responses.
+type outbox struct {
+	mux    sync.Mutex
+	cond   *sync.Cond
+	msgs   [][]byte
+	closed bool
+}
+
+func newOutbox() *outbox {
+	o := &outbox{}
+	o.cond = sync.NewCond(&o.mux)
+	return o
+}
+
+// push queues the message.
+func (o *outbox) push(msg interface{}) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	o.mux.Lock()
+	defer o.mux.Unlock()
+	if o.closed {
+		return fmt.Errorf("the plugin daemon isn't running")
+	}
+	o.msgs = append(o.msgs, append(data, '\n'))
+	o.cond.Signal()
+	return nil
+}
+
+func (o *outbox) close() {
+	o.mux.Lock()
+	defer o.mux.Unlock()
+	o.closed = true
+	o.cond.Signal()
+}
+
+// run writes the queued messages to w until the outbox is closed, then closes
+// w.
+func (o *outbox) run(w io.WriteCloser) {
+	defer w.Close()
+	for {
+		o.mux.Lock()
+		for len(o.msgs) == 0 && !o.closed {
+			o.cond.Wait()
+		}
+		if o.closed {
+			o.mux.Unlock()
+			return
+		}
+		msg := o.msgs[0]
+		o.msgs = o.msgs[1:]
+		o.mux.Unlock()
+		if _, err := w.Write(msg); err != nil {
+			log.Debugf("Writing to the plugin daemon errored: %v", err)
+		}
+	}
+}
+
+func newDaemonPluginScript(path string) *daemonPluginScript {
+	return &daemonPluginScript{path: path, calls: make(map[uint64]*daemonInvocation)}
+}
+
+func (s *daemonPluginScript) Path() string {
+	return s.path
+}
+
+// InvokeAndWait calls method on entry and waits for its result. The result is
+// written to the invocation's stdout like the fork-per-call protocol's output.
+func (s *daemonPluginScript) InvokeAndWait(
+	ctx context.Context,
+	method string,
+	entry *pluginEntry,
+	args ...string,
+) (invocation, error) {
+	inv := s.NewInvocation(ctx, method, entry, args...)
+	err := inv.RunAndWait(ctx)
+	return inv, err
+}
+
+func (s *daemonPluginScript) NewInvocation(
+	ctx context.Context,
+	method string,
+	entry *pluginEntry,
+	args ...string,
+) invocation {
+	params := callParams{Args: args}
+	if method != "init" {
+		if entry == nil {
+			msg := fmt.Sprintf("s.NewInvocation called with method '%v' and entry == nil", method)
+			panic(msg)
+		}
+		params.Path, params.State = plugin.ID(entry), entry.state
+	}
+	if params.Args == nil {
+		params.Args = []string{}
+	}
+	return newDaemonInvocation(ctx, s, method, params)
+}
+
+// start launches the daemon. It must be called with s.mux held.
+func (s *daemonPluginScript) start() error {
+	// The daemon outlives the calls, so it isn't tied to their contexts.
+	cmd := NewCommand(context.Background(), s.path, DaemonMethod)
+	stdinR, stdinW := io.Pipe()
+	cmd.SetStdin(stdinR)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("could not launch the plugin daemon: %v", err)
+	}
+	log.Infof("Launched the plugin daemon %v", cmd)
+	s.cmd, s.stdin, s.stoppedCh = cmd, newOutbox(), make(chan struct{})
+	go s.stdin.run(stdinW)
+
+	stderrDoneCh := make(chan struct{})
+	go func() {
+		defer close(stderrDoneCh)
+		scanner := bufio.NewScanner(stderr)
+		for scanner.Scan() {
+			log.Debugf("%v: %v", s.path, scanner.Text())
+		}
+	}()
+	go s.serve(cmd, stdout, s.stdin, stderrDoneCh, s.stoppedCh)
+
+	// Replay init so that the relaunched daemon's initialized before it sees
+	// any other call. Its result was already handled when the plugin loaded.
+	if s.initArgs != nil {
+		inv := newDaemonInvocation(context.Background(), s, "init", callParams{Args: s.initArgs})
+		if err := s.send(inv); err != nil {
+			return err
+		}
+		go inv.drain()
+		go func() {
+			if err := inv.Wait(); err != nil {
+				log.Warnf("Re-initializing the plugin daemon %v errored: %v", s.path, err)
+			}
+		}()
+	}
+	return nil
+}
+
+// daemonStopTimeout is how long stop waits for the daemon to exit after its
+// stdin's closed before it's terminated.
+var daemonStopTimeout = 5 * time.Second
+
+// stop stops the daemon by closing its stdin, and waits for it to exit. Its
+// init call isn't replayed, so the next call should be init.
+func (s *daemonPluginScript) stop() {
+	s.mux.Lock()
+	cmd, stdin, stoppedCh := s.cmd, s.stdin, s.stoppedCh
+	s.initArgs = nil
+	s.mux.Unlock()
+	if cmd == nil {
+		return
+	}
+	stdin.close()
+	select {
+	case <-stoppedCh:
+	case <-time.After(daemonStopTimeout):
+		log.Warnf("The plugin daemon %v didn't exit after its stdin was closed, so it's being terminated", cmd)
+		cmd.Terminate()
+		<-stoppedCh
+	}
+}
+
+// call sends the invocation's request, launching the daemon if it isn't
+// running.
+func (s *daemonPluginScript) call(inv *daemonInvocation) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if s.cmd == nil {
+		if err := s.start(); err != nil {
+			return err
+		}
+	}
+	if inv.method == "init" {
+		s.initArgs = inv.params.Args
+	}
+	return s.send(inv)
+}
+
+// send registers the invocation and queues its request. It must be called
+// with s.mux held so that requests are sent in the order that they're
+// registered.
+func (s *daemonPluginScript) send(inv *daemonInvocation) error {
+	s.nextID++
+	inv.id = s.nextID
+	s.calls[inv.id] = inv
+	err := s.stdin.push(rpcRequest{JSONRPC: "2.0", ID: &inv.id, Method: inv.method, Params: inv.params})
+	if err != nil {
+		delete(s.calls, inv.id)
+		return fmt.Errorf("could not send the %v call to the plugin daemon: %v", inv.method, err)
+	}
+	return nil
+}
+
+// notify sends a notification to the daemon.
+func (s *daemonPluginScript) notify(method string, params interface{}) error {
+	s.mux.Lock()
+	stdin := s.stdin
+	s.mux.Unlock()
+	if stdin == nil {
+		return fmt.Errorf("the plugin daemon isn't running")
+	}
+	return stdin.push(rpcRequest{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+// forget removes the call so that its response is ignored.
+func (s *daemonPluginScript) forget(id uint64) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	delete(s.calls, id)
+}
+
+func (s *daemonPluginScript) lookup(id uint64, remove bool) *daemonInvocation {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	inv := s.calls[id]
+	if remove {
+		delete(s.calls, id)
+	}
+	return inv
+}
+
+// serve reads the daemon's messages until it closes its stdout, then fails
+// the calls that are still pending.
+func (s *daemonPluginScript) serve(cmd Command, stdout io.Reader, stdin *outbox, stderrDoneCh <-chan struct{}, stoppedCh chan<- struct{}) {
+	defer close(stoppedCh)
+	decoder := json.NewDecoder(stdout)
+	var reason string
+	for {
+		var msg rpcMessage
+		if err := decoder.Decode(&msg); err != nil {
+			if err == io.EOF {
+				reason = "it closed its stdout"
+			} else {
+				// The daemon's still running, so it's stopped.
+				reason = fmt.Sprintf("it sent an invalid message: %v", err)
+				cmd.Terminate()
+			}
+			break
+		}
+		s.handle(msg)
+	}
+
+	stdin.close()
+	<-stderrDoneCh
+	if err := cmd.Wait(); err != nil {
+		reason += fmt.Sprintf(" (%v)", err)
+	}
+	log.Warnf("The plugin daemon %v stopped: %v", cmd, reason)
+
+	s.mux.Lock()
+	calls := s.calls
+	s.cmd, s.stdin, s.calls = nil, nil, make(map[uint64]*daemonInvocation)
+	s.mux.Unlock()
+	for _, inv := range calls {
+		inv.fail(fmt.Errorf("the plugin daemon stopped: %v", reason))
+	}
+}
+
+func (s *daemonPluginScript) handle(msg rpcMessage) {
+	if msg.Method == "" {
+		if msg.ID == nil {
+			log.Debugf("%v: ignoring a response without an ID", s.path)
+			return
+		}
+		if inv := s.lookup(*msg.ID, true); inv != nil {
+			inv.finish(msg.Result, msg.Error)
+		} else {
+			log.Debugf("%v: ignoring the response to unknown call %v", s.path, *msg.ID)
+		}
+		return
+	}
+
+	switch msg.Method {
+	case "output":
+		var params outputParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			log.Warnf("%v: could not decode an output notification: %v", s.path, err)
+			return
+		}
+		if inv := s.lookup(params.ID, false); inv != nil {
+			inv.output(params.Stream, params.Data)
+		}
+	default:
+		log.Debugf("%v: ignoring the unknown %v message", s.path, msg.Method)
+	}
+}
@@ -0,0 +1,186 @@
+package external
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/stretchr/testify/suite"
+)
+
+// The test binary runs as the plugin daemon when it's launched with this
+// environment variable set.
+const testDaemonEnv = "WASH_TEST_PLUGIN_DAEMON"
+
+func init() {
+	if os.Getenv(testDaemonEnv) == "1" {
+		runTestDaemon()
+		os.Exit(0)
+	}
+}
+
+// runTestDaemon serves the methods that the tests call.
+func runTestDaemon() {
+	var mux sync.Mutex
+	encoder := json.NewEncoder(os.Stdout)
+	send := func(msg map[string]interface{}) {
+		mux.Lock()
+		defer mux.Unlock()
+		msg["jsonrpc"] = "2.0"
+		_ = encoder.Encode(msg)
+	}
+	initialized := false
+	decoder := json.NewDecoder(os.Stdin)
+	for {
+		var msg struct {
+			ID     *uint64         `json:"id"`
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		if err := decoder.Decode(&msg); err != nil {
+			return
+		}
+		var params struct {
+			ID    uint64   `json:"id"`
+			Path  string   `json:"path"`
+			Args  []string `json:"args"`
+			Data  []byte   `json:"data"`
+			EOF   bool     `json:"eof"`
+			Input []byte   `json:"input"`
+		}
+		_ = json.Unmarshal(msg.Params, &params)
+		switch msg.Method {
+		case "init":
+			initialized = true
+			send(map[string]interface{}{"id": msg.ID, "result": map[string]interface{}{}})
+		case "initialized":
+			send(map[string]interface{}{"id": msg.ID, "result": initialized})
+		case "read":
+			send(map[string]interface{}{"id": msg.ID, "result": []byte("content of " + params.Path)})
+		case "write":
+			send(map[string]interface{}{"id": msg.ID, "result": nil})
+		case "metadata":
+			send(map[string]interface{}{"id": msg.ID, "error": map[string]interface{}{"code": 1, "message": "metadata failed"}})
+		case "exec":
+			// The exec call's output echoes its input, and it finishes once
+			// its input's closed.
+			send(map[string]interface{}{"method": "output", "params": map[string]interface{}{"id": msg.ID, "stream": "stderr", "data": []byte("starting")}})
+		case "input":
+			if params.EOF {
+				send(map[string]interface{}{"id": params.ID, "result": map[string]interface{}{"exitCode": 3}})
+			} else {
+				send(map[string]interface{}{"method": "output", "params": map[string]interface{}{"id": params.ID, "stream": "stdout", "data": params.Data}})
+			}
+		case "stream":
+			send(map[string]interface{}{"method": "output", "params": map[string]interface{}{"id": msg.ID, "stream": "stdout", "data": []byte("200\nstreamed")}})
+		case "cancel":
+			send(map[string]interface{}{"id": params.ID, "error": map[string]interface{}{"code": 2, "message": "cancelled"}})
+		case "crash":
+			os.Exit(1)
+		}
+	}
+}
+
+type PluginDaemonTestSuite struct {
+	suite.Suite
+	script *daemonPluginScript
+	entry  *pluginEntry
+}
+
+func (suite *PluginDaemonTestSuite) SetupSuite() {
+	suite.NoError(os.Setenv(testDaemonEnv, "1"))
+}
+
+func (suite *PluginDaemonTestSuite) TearDownSuite() {
+	suite.NoError(os.Unsetenv(testDaemonEnv))
+}
+
+func (suite *PluginDaemonTestSuite) SetupTest() {
+	suite.script = newDaemonPluginScript(os.Args[0])
+	suite.entry = &pluginEntry{EntryBase: plugin.NewEntry("foo"), script: suite.script}
+	suite.entry.SetTestID("/foo")
+	_, err := suite.script.InvokeAndWait(context.Background(), "init", nil, "{}")
+	suite.Require().NoError(err)
+}
+
+func (suite *PluginDaemonTestSuite) TearDownTest() {
+	// Closing the daemon's stdin stops it.
+	suite.script.mux.Lock()
+	defer suite.script.mux.Unlock()
+	if suite.script.stdin != nil {
+		suite.script.stdin.close()
+	}
+}
+
+func (suite *PluginDaemonTestSuite) TestInvokeAndWait() {
+	inv, err := suite.script.InvokeAndWait(context.Background(), "read", suite.entry)
+	if suite.NoError(err) {
+		suite.Equal("content of /foo", inv.Stdout().String())
+	}
+
+	err = suite.entry.Write(context.Background(), []byte("data"))
+	suite.NoError(err)
+
+	_, err = suite.script.InvokeAndWait(context.Background(), "metadata", suite.entry)
+	suite.Regexp("metadata failed", err)
+}
+
+func (suite *PluginDaemonTestSuite) TestExec() {
+	inv := suite.script.NewInvocation(context.Background(), "exec", suite.entry, "{}", "cat")
+	var stdout, stderr bytes.Buffer
+	inv.SetStdout(&stdout)
+	inv.SetStderr(&stderr)
+	inv.SetStdin(strings.NewReader("input"))
+	suite.Require().NoError(inv.Start())
+	suite.Error(inv.Wait())
+	suite.Equal(3, inv.ExitCode())
+	suite.Equal("input", stdout.String())
+	suite.Equal("starting", stderr.String())
+}
+
+func (suite *PluginDaemonTestSuite) TestStream() {
+	rdr, err := suite.entry.Stream(context.Background())
+	if suite.NoError(err) {
+		buf := make([]byte, len("streamed"))
+		_, err = rdr.Read(buf)
+		suite.NoError(err)
+		suite.Equal("streamed", string(buf))
+
+		// The daemon fails the call when it's cancelled.
+		suite.Regexp("cancelled", rdr.Close())
+	}
+}
+
+func (suite *PluginDaemonTestSuite) TestRestart() {
+	_, err := suite.script.InvokeAndWait(context.Background(), "crash", suite.entry)
+	suite.Regexp("the plugin daemon stopped", err)
+
+	// The next call relaunches the daemon, and init's replayed first.
+	inv, err := suite.script.InvokeAndWait(context.Background(), "initialized", suite.entry)
+	if suite.NoError(err) {
+		suite.Equal("true", inv.Stdout().String())
+	}
+}
+
+func (suite *PluginDaemonTestSuite) TestStop() {
+	suite.script.stop()
+	suite.script.mux.Lock()
+	suite.Nil(suite.script.cmd)
+	suite.script.mux.Unlock()
+
+	// The next call relaunches the daemon, but init isn't replayed since the
+	// stopped plugin's re-initialized by the registry.
+	inv, err := suite.script.InvokeAndWait(context.Background(), "initialized", suite.entry)
+	if suite.NoError(err) {
+		suite.Equal("false", inv.Stdout().String())
+	}
+}
+
+func TestPluginDaemon(t *testing.T) {
+	suite.Run(t, new(PluginDaemonTestSuite))
+}
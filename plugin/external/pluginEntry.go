@@ -40,6 +40,9 @@ type decodedExternalPluginEntry struct {
 	Attributes         plugin.EntryAttributes `json:"attributes"`
 	PartialMetadata    plugin.JSONObject      `json:"partial_metadata"`
 	State              string                 `json:"state"`
+	// capabilities are the plugin's capabilities, which are passed down
+	// from the entry's parent.
+	capabilities capabilitySet
 }
 
 type methodTuple struct {
@@ -69,13 +72,22 @@ type execImpl struct {
 	Options   transport.Identity `json:"options"`
 }
 
-func (e decodedExternalPluginEntry) getMungedMethods() (map[string]methodInfo, error) {
+func (e decodedExternalPluginEntry) getMungedMethods(ctx context.Context) (map[string]methodInfo, error) {
 	methods := make(map[string]methodInfo)
+	// Methods that Wash doesn't support, or that the plugin didn't report as
+	// capabilities, are ignored so that the entry degrades gracefully.
+	addMethod := func(name string, info methodInfo) {
+		if !e.capabilities.allows(name, info.signature) {
+			activity.Record(ctx, "Ignoring %v's %v method: Wash doesn't support it, or it isn't one of the plugin's capabilities", e.Name, name)
+			return
+		}
+		methods[name] = info
+	}
 	for _, raw := range e.Methods {
 		// Try to unmarshal to a string. If that doesn't work, unmarshal to a method-specific tuple.
 		var name string
 		if err := json.Unmarshal(raw, &name); err == nil {
-			methods[name] = methodInfo{signature: plugin.DefaultSignature}
+			addMethod(name, methodInfo{signature: plugin.DefaultSignature})
 			continue
 		}
 
@@ -132,7 +144,7 @@ func (e decodedExternalPluginEntry) getMungedMethods() (map[string]methodInfo, e
 			info.tupleValue = impl
 		}
 
-		methods[tuple.Method] = info
+		addMethod(tuple.Method, info)
 	}
 	return methods, nil
 }
@@ -145,7 +157,7 @@ func (e decodedExternalPluginEntry) toExternalPluginEntry(ctx context.Context, s
 		return nil, fmt.Errorf("the entry's methods must be provided")
 	}
 
-	methods, err := e.getMungedMethods()
+	methods, err := e.getMungedMethods(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -186,11 +198,12 @@ func (e decodedExternalPluginEntry) toExternalPluginEntry(ctx context.Context, s
 	}
 
 	entry := &pluginEntry{
-		EntryBase:   plugin.NewEntry(e.Name),
-		methods:     methods,
-		state:       e.State,
-		schemaKnown: schemaKnown,
-		rawTypeID:   e.TypeID,
+		EntryBase:    plugin.NewEntry(e.Name),
+		methods:      methods,
+		state:        e.State,
+		schemaKnown:  schemaKnown,
+		rawTypeID:    e.TypeID,
+		capabilities: e.capabilities,
 	}
 	entry.SetAttributes(e.Attributes)
 	entry.SetPartialMetadata(e.PartialMetadata)
@@ -236,6 +249,9 @@ type pluginEntry struct {
 	// schemaGraphs is a map of <type_id> => <schema_graph>. It is created
 	// by the root and passed along to child entries in list.
 	schemaGraphs map[string]*linkedhashmap.Map
+	// capabilities are reported by the root's init. They're also passed
+	// along to child entries in list.
+	capabilities capabilitySet
 }
 
 func (e *pluginEntry) setCacheTTLs(ttls decodedCacheTTLs) {
@@ -346,11 +362,14 @@ func (e *pluginEntry) SchemaGraph() (*linkedhashmap.Map, error) {
 			strings.Join(schemaMethods, ", "),
 			strings.Join(instanceMethods, ", "),
 		)
-		if len(schemaMethods) != len(instanceMethods) {
+		// Only the actions are compared, since methods like metadata aren't
+		// part of the schema.
+		schemaActions, instanceActions := methodsToActions(schemaMethods), methodsToActions(instanceMethods)
+		if len(schemaActions) != len(instanceActions) {
 			return nil, mismatchErr
 		}
-		for i := range instanceMethods {
-			if instanceMethods[i] != schemaMethods[i] {
+		for i := range instanceActions {
+			if instanceActions[i] != schemaActions[i] {
 				return nil, mismatchErr
 			}
 		}
@@ -404,7 +423,33 @@ func (e *pluginEntry) List(ctx context.Context) ([]plugin.Entry, error) {
 			return nil, newStdoutDecodeErr(ctx, "the entries", err, inv, listFormat)
 		}
 	}
+	return e.toEntries(ctx, decodedEntries)
+}
+
+const searchFormat = "{\"entries\":[{\"name\":\"entry1\",\"methods\":[\"read\"]}],\"next_page_token\":\"page2\"}"
+
+func (e *pluginEntry) Search(ctx context.Context, query string, pageToken string) ([]plugin.Entry, string, error) {
+	inv, err := e.script.InvokeAndWait(ctx, "search", e, query, pageToken)
+	if err != nil {
+		return nil, "", err
+	}
+	var page struct {
+		Entries       []decodedExternalPluginEntry `json:"entries"`
+		NextPageToken string                       `json:"next_page_token"`
+	}
+	if err := json.Unmarshal(inv.Stdout().Bytes(), &page); err != nil {
+		return nil, "", newStdoutDecodeErr(ctx, "the search results", err, inv, searchFormat)
+	}
+	entries, err := e.toEntries(ctx, page.Entries)
+	if err != nil {
+		return nil, "", err
+	}
+	return entries, page.NextPageToken, nil
+}
 
+// toEntries converts the decoded entries returned by list or search into
+// entries.
+func (e *pluginEntry) toEntries(ctx context.Context, decodedEntries []decodedExternalPluginEntry) ([]plugin.Entry, error) {
 	entries := make([]plugin.Entry, len(decodedEntries))
 	for i, decodedExternalPluginEntry := range decodedEntries {
 		if coreEnt, ok := coreEntries[decodedExternalPluginEntry.TypeID]; ok {
@@ -416,6 +461,7 @@ func (e *pluginEntry) List(ctx context.Context) ([]plugin.Entry, error) {
 			continue
 		}
 
+		decodedExternalPluginEntry.capabilities = e.capabilities
 		entry, err := decodedExternalPluginEntry.toExternalPluginEntry(ctx, e.schemaKnown, false)
 		if err != nil {
 			return nil, err
@@ -501,6 +547,11 @@ func (e *pluginEntry) Delete(ctx context.Context) (deleted bool, err error) {
 	return
 }
 
+func (e *pluginEntry) Rename(ctx context.Context, newParent plugin.Parent, newName string) error {
+	_, err := e.script.InvokeAndWait(ctx, "rename", e, plugin.ID(newParent), newName)
+	return err
+}
+
 func (e *pluginEntry) Stream(ctx context.Context) (io.ReadCloser, error) {
 	inv := e.script.NewInvocation(ctx, "stream", e)
 	stdoutR, err := inv.StdoutPipe()
@@ -784,6 +835,11 @@ func unmarshalSchemaGraph(pluginName, rawTypeID string, stdout []byte) (*linkedh
 		if isSignalable && len(node.Signals) <= 0 {
 			return fmt.Errorf("signalable entries must include their supported signals")
 		}
+		if node.ConsoleURL != "" {
+			if _, err := plugin.ParseConsoleURL(node.ConsoleURL); err != nil {
+				return fmt.Errorf("invalid value for the console URL: %v", err)
+			}
+		}
 		if node.PartialMetadataSchema != nil && node.PartialMetadataSchema.Type.Type != "object" {
 			return fmt.Errorf("invalid value for the partial metadata schema: expected a JSON object schema but got %v", node.PartialMetadataSchema.Type.Type)
 		}
@@ -793,8 +849,9 @@ func unmarshalSchemaGraph(pluginName, rawTypeID string, stdout []byte) (*linkedh
 
 		// All required fields are present, so put node.entrySchema in the graph.
 		// We don't put node itself in because doing so would marshal its "Methods"
-		// field.
-		node.Actions = node.Methods
+		// field. Methods that aren't actions, like schema and metadata, are
+		// omitted so that stree and docs show accurate action lists.
+		node.Actions = methodsToActions(node.Methods)
 		graph.Put(typeID, node.EntrySchema)
 		return nil
 	}
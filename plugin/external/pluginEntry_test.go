@@ -1061,6 +1061,36 @@ func (suite *ExternalPluginEntryTestSuite) TestSignal() {
 	}
 }
 
+func (suite *ExternalPluginEntryTestSuite) TestRename() {
+	mockScript := &mockPluginScript{path: "plugin_script"}
+	entry := &pluginEntry{
+		EntryBase: plugin.NewEntry("foo"),
+		methods:   map[string]methodInfo{"rename": methodInfo{}},
+		script:    mockScript,
+	}
+	entry.SetTestID("/foo/bar")
+	newParent := &pluginEntry{EntryBase: plugin.NewEntry("baz")}
+	newParent.SetTestID("/foo/baz")
+
+	ctx := context.Background()
+	mockInvokeAndWait := func(err error) {
+		mockScript.OnInvokeAndWait(ctx, "rename", entry, "/foo/baz", "qux").Return(mockInvocation([]byte{}), err).Once()
+	}
+
+	// Test that if InvokeAndWait errors, then Rename returns its error
+	mockErr := fmt.Errorf("execution error")
+	mockInvokeAndWait(mockErr)
+	err := entry.Rename(ctx, newParent, "qux")
+	suite.EqualError(err, mockErr.Error())
+
+	// Test that Rename properly renames the entry
+	mockInvokeAndWait(nil)
+	err = entry.Rename(ctx, newParent, "qux")
+	if suite.NoError(err) {
+		mockScript.AssertExpectations(suite.T())
+	}
+}
+
 func (suite *ExternalPluginEntryTestSuite) TestDelete() {
 	mockScript := &mockPluginScript{path: "plugin_script"}
 	entry := &pluginEntry{
@@ -1096,6 +1126,45 @@ func (suite *ExternalPluginEntryTestSuite) TestDelete() {
 	}
 }
 
+func (suite *ExternalPluginEntryTestSuite) TestSearch() {
+	mockScript := &mockPluginScript{path: "plugin_script"}
+	entry := &pluginEntry{
+		EntryBase: plugin.NewEntry("foo"),
+		methods:   map[string]methodInfo{"list": methodInfo{}, "search": methodInfo{}},
+		script:    mockScript,
+	}
+	entry.SetTestID("/foo")
+
+	ctx := context.Background()
+	mockInvokeAndWait := func(pageToken string, stdout []byte, err error) {
+		mockScript.OnInvokeAndWait(ctx, "search", entry, "name=bar", pageToken).Return(mockInvocation(stdout), err).Once()
+	}
+
+	// Test that if InvokeAndWait errors, then Search returns its error
+	mockErr := fmt.Errorf("execution error")
+	mockInvokeAndWait("", []byte{}, mockErr)
+	_, _, err := entry.Search(ctx, "name=bar", "")
+	suite.EqualError(err, mockErr.Error())
+
+	// Test that Search returns an error if stdout does not have the right
+	// output format
+	mockInvokeAndWait("", []byte("bad format"), nil)
+	_, _, err = entry.Search(ctx, "name=bar", "")
+	suite.Regexp(regexp.MustCompile("stdout"), err)
+
+	// Test that Search decodes the page's entries and the next page's token
+	stdout := `{"entries": [{"name": "bar", "methods": ["read"]}], "next_page_token": "2"}`
+	mockInvokeAndWait("1", []byte(stdout), nil)
+	entries, nextPageToken, err := entry.Search(ctx, "name=bar", "1")
+	if suite.NoError(err) {
+		suite.Equal("2", nextPageToken)
+		if suite.Len(entries, 1) {
+			suite.Equal("bar", plugin.Name(entries[0]))
+			suite.True(plugin.ReadAction().IsSupportedOn(entries[0]))
+		}
+	}
+}
+
 // TODO: Add tests for stdoutStreamer, Stream and Exec
 // once the API for Stream and Exec's at a more stable
 // state.
@@ -1424,9 +1493,11 @@ func (suite *ExternalPluginEntryTestSuite) TestUnmarshalSchemaGraph_CoreEntries(
 			assertFunc(schema.(plugin.EntrySchema))
 		}
 
-		// Ensure that only four nodes exist in schema graph -- "foo", volume::fs,
-		// volume::dir, and volume::file
-		suite.Equal(int(4), graph.Size())
+		// Ensure that only eleven nodes exist in schema graph -- "foo", volume::fs,
+		// volume::dir, volume::writableDir, volume::file, volume::writableFile,
+		// volume::blockFile, volume::writableBlockFile, and archive::Dir with its
+		// dir and file
+		suite.Equal(int(11), graph.Size())
 
 		// Now ensure that the right nodes are set in the graph
 		volumeFSTemplate := (&volumeFS{}).template()
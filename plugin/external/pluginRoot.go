@@ -39,6 +39,19 @@ func (r *pluginRoot) Init(cfg map[string]interface{}) error {
 		}
 	}
 	var decodedRoot decodedExternalPluginEntry
+	var handshake decodedHandshake
+	if err := json.Unmarshal(inv.Stdout().Bytes(), &handshake); err != nil {
+		return newStdoutDecodeErr(
+			context.Background(),
+			"the plugin root",
+			err,
+			inv,
+			"{}",
+		)
+	}
+	if decodedRoot.capabilities, err = newCapabilitySet(context.Background(), r.script.Path(), handshake); err != nil {
+		return err
+	}
 	if err := json.Unmarshal(inv.Stdout().Bytes(), &decodedRoot); err != nil {
 		return newStdoutDecodeErr(
 			context.Background(),
@@ -78,6 +91,16 @@ it's safe to omit name from the response to 'init'`, r.script.Path()))
 	return nil
 }
 
+// Close stops the plugin's daemon if it uses the jsonrpc protocol. The
+// registry closes the root when the plugin's disabled or reloaded, so that a
+// reloaded daemon runs the script's latest version.
+func (r *pluginRoot) Close() error {
+	if script, ok := r.script.(*daemonPluginScript); ok {
+		script.stop()
+	}
+	return nil
+}
+
 func (r *pluginRoot) WrappedTypes() plugin.SchemaMap {
 	// This only makes sense for core plugins because it is a Go-specific
 	// limitation.
@@ -1,6 +1,7 @@
 package external
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"regexp"
@@ -82,6 +83,50 @@ func (suite *ExternalPluginRootTestSuite) TestInitWithConfig() {
 	suite.NoError(root.Init(map[string]interface{}{"key": []string{"value"}}))
 }
 
+func (suite *ExternalPluginRootTestSuite) TestInitWithHandshake_ReturnsErrorIfProtocolIsTooNew() {
+	mockScript := &mockPluginScript{path: "plugin_script"}
+	root := &pluginRoot{pluginEntry{
+		EntryBase: plugin.NewEntry("foo"),
+		script:    mockScript,
+	}}
+
+	stdout := fmt.Sprintf(`{"protocol_version":%v}`, ProtocolVersion+1)
+	mockScript.OnInvokeAndWait(mock.Anything, "init", nil, "{}").Return(mockInvocation([]byte(stdout)), nil).Once()
+	suite.Regexp("requires version 4 .* only supports up to version 3", root.Init(nil))
+}
+
+func (suite *ExternalPluginRootTestSuite) TestInitWithHandshake_IgnoresMethodsThatArentCapabilities() {
+	mockScript := &mockPluginScript{path: "plugin_script"}
+	root := &pluginRoot{pluginEntry{
+		EntryBase: plugin.NewEntry("foo"),
+		script:    mockScript,
+	}}
+
+	// The unknown watch capability is dropped, and so is the root's write
+	// method since write isn't one of the capabilities.
+	stdout := `{
+		"protocol_version": 2,
+		"capabilities": ["list", "read", "watch"],
+		"methods": ["list", "write", "watch"]
+	}`
+	mockScript.OnInvokeAndWait(mock.Anything, "init", nil, "{}").Return(mockInvocation([]byte(stdout)), nil).Once()
+	if suite.NoError(root.Init(nil)) {
+		suite.Equal(capabilitySet{"list": true, "read": true}, root.capabilities)
+		suite.Equal([]string{"list"}, plugin.SupportedActionsOf(root))
+	}
+
+	// The capabilities are passed down to the root's children. Block-reads
+	// need the block_read capability.
+	entries, err := root.toEntries(context.Background(), []decodedExternalPluginEntry{
+		{Name: "bar", Methods: rawMethods(`["read", true]`, `"list"`)},
+		{Name: "baz", Methods: rawMethods(`"read"`, `"stream"`)},
+	})
+	if suite.NoError(err) {
+		suite.Equal([]string{"list"}, plugin.SupportedActionsOf(entries[0]))
+		suite.Equal([]string{"read"}, plugin.SupportedActionsOf(entries[1]))
+	}
+}
+
 func (suite *ExternalPluginRootTestSuite) TestInitWithSchema_SetsSchemaKnownVariable() {
 	mockScript := &mockPluginScript{path: "plugin_script"}
 	root := &pluginRoot{pluginEntry{
@@ -9,9 +9,13 @@ import (
 	"github.com/puppetlabs/wash/plugin"
 )
 
-// PluginSpec represents an external plugin's specification.
+// PluginSpec represents an external plugin's specification. Protocol is how
+// the script's invoked. It's either fork (the default), which forks the script
+// for each method invocation, or jsonrpc, which launches the script once and
+// calls it over JSON-RPC.
 type PluginSpec struct {
-	Script string
+	Script   string
+	Protocol string
 }
 
 // Name returns the plugin name, which is the basename of the script with extension removed.
@@ -31,9 +35,19 @@ func (s PluginSpec) Load() (plugin.Root, error) {
 		return nil, fmt.Errorf("script %v is not executable", s.Script)
 	}
 
+	var script pluginScript
+	switch s.Protocol {
+	case "", "fork":
+		script = externalPluginScriptImpl{path: s.Script}
+	case "jsonrpc":
+		script = newDaemonPluginScript(s.Script)
+	default:
+		return nil, fmt.Errorf("unknown protocol %v, expected fork or jsonrpc", s.Protocol)
+	}
+
 	root := &pluginRoot{pluginEntry{
 		EntryBase: plugin.NewEntry(s.Name()),
-		script:    externalPluginScriptImpl{path: s.Script},
+		script:    script,
 	}}
 	return root, nil
 }
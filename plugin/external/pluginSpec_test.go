@@ -31,3 +31,9 @@ func TestRegisterExternalPluginNotFile(t *testing.T) {
 	_, err := spec.Load()
 	assert.EqualError(t, err, "script testdata/notfile is not a file")
 }
+
+func TestLoadExternalPluginUnknownProtocol(t *testing.T) {
+	spec := PluginSpec{Script: "testdata/external.sh", Protocol: "grpc"}
+	_, err := spec.Load()
+	assert.EqualError(t, err, "unknown protocol grpc, expected fork or jsonrpc")
+}
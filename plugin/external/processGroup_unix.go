@@ -0,0 +1,62 @@
+//go:build !windows
+// +build !windows
+
+package external
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// processGroup is the command's process group. Signals are sent to the whole
+// group so that the command's children are stopped with it.
+type processGroup struct {
+	// pgid is set when the command starts. It's 0 until then.
+	pgid int
+}
+
+// setup makes the command start in its own process group.
+func (g *processGroup) setup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Setpgid: true,
+	}
+}
+
+// add gets the started process's PGID for logging. If this fails, we'll try
+// again in signal when it is needed.
+func (g *processGroup) add(process *os.Process) error {
+	pgid, err := syscall.Getpgid(process.Pid)
+	if err != nil {
+		return fmt.Errorf("could not get pgid: %v", err)
+	}
+	g.pgid = pgid
+	return nil
+}
+
+// resume is a no-op since the process isn't started suspended.
+func (g *processGroup) resume(process *os.Process) error {
+	return nil
+}
+
+func (g *processGroup) signal(process *os.Process, sig syscall.Signal) error {
+	if g.pgid == 0 {
+		// We failed to get the pgid in add, so try again
+		if err := g.add(process); err != nil {
+			return err
+		}
+	}
+	return syscall.Kill(-g.pgid, sig)
+}
+
+// close is a no-op since a process group doesn't hold any resources.
+func (g *processGroup) close() {
+}
+
+func (g *processGroup) String() string {
+	if g.pgid == 0 {
+		return ""
+	}
+	return fmt.Sprintf("(PGID %v) ", g.pgid)
+}
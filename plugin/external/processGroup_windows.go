@@ -0,0 +1,147 @@
+package external
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// processGroup is the command's Job Object, which is Windows' equivalent of a
+// process group. Terminating the job stops the command's children with it.
+//
+// The process starts suspended and is only resumed once it's in the job, so
+// every process that it creates is in the job too.
+type processGroup struct {
+	// job is set when the command starts. It's 0 until then.
+	job windows.Handle
+}
+
+// setup makes the command start suspended and in its own console process group
+// so that it doesn't get the console's Ctrl-C events.
+func (g *processGroup) setup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		CreationFlags: windows.CREATE_NEW_PROCESS_GROUP | windows.CREATE_SUSPENDED,
+	}
+}
+
+// add creates the job and adds the started process to it. The job's processes
+// are also terminated when the job's closed, i.e. once the command exits or if
+// the daemon exits first.
+func (g *processGroup) add(process *os.Process) error {
+	job, err := newJob()
+	if err != nil {
+		return err
+	}
+	handle, err := windows.OpenProcess(windows.PROCESS_SET_QUOTA|windows.PROCESS_TERMINATE, false, uint32(process.Pid))
+	if err != nil {
+		_ = windows.CloseHandle(job)
+		return fmt.Errorf("could not open the process: %v", err)
+	}
+	defer windows.CloseHandle(handle)
+	if err := windows.AssignProcessToJobObject(job, handle); err != nil {
+		_ = windows.CloseHandle(job)
+		return fmt.Errorf("could not add the process to a job object: %v", err)
+	}
+	g.job = job
+	return nil
+}
+
+// resume resumes the process once it's been added to the job. It's resumed
+// even if it couldn't be added so that the command still runs.
+func (g *processGroup) resume(process *os.Process) error {
+	if err := resumeProcess(uint32(process.Pid)); err != nil {
+		return fmt.Errorf("could not resume the process: %v", err)
+	}
+	return nil
+}
+
+// newJob creates a job whose processes are terminated when it's closed.
+func newJob() (windows.Handle, error) {
+	job, err := windows.CreateJobObject(nil, nil)
+	if err != nil {
+		return 0, fmt.Errorf("could not create a job object: %v", err)
+	}
+	info := windows.JOBOBJECT_EXTENDED_LIMIT_INFORMATION{
+		BasicLimitInformation: windows.JOBOBJECT_BASIC_LIMIT_INFORMATION{
+			LimitFlags: windows.JOB_OBJECT_LIMIT_KILL_ON_JOB_CLOSE,
+		},
+	}
+	_, err = windows.SetInformationJobObject(
+		job,
+		windows.JobObjectExtendedLimitInformation,
+		uintptr(unsafe.Pointer(&info)),
+		uint32(unsafe.Sizeof(info)),
+	)
+	if err != nil {
+		_ = windows.CloseHandle(job)
+		return 0, fmt.Errorf("could not configure the job object: %v", err)
+	}
+	return job, nil
+}
+
+// resumeProcess resumes a process that was started suspended. os/exec doesn't
+// expose the handle of the process' main thread, so its threads are found via
+// a snapshot. A suspended process only has its main thread.
+func resumeProcess(pid uint32) error {
+	snapshot, err := windows.CreateToolhelp32Snapshot(windows.TH32CS_SNAPTHREAD, 0)
+	if err != nil {
+		return fmt.Errorf("could not snapshot the threads: %v", err)
+	}
+	defer windows.CloseHandle(snapshot)
+
+	var entry windows.ThreadEntry32
+	entry.Size = uint32(unsafe.Sizeof(entry))
+	resumed := false
+	for err = windows.Thread32First(snapshot, &entry); err == nil; err = windows.Thread32Next(snapshot, &entry) {
+		if entry.OwnerProcessID != pid {
+			continue
+		}
+		thread, err := windows.OpenThread(windows.THREAD_SUSPEND_RESUME, false, entry.ThreadID)
+		if err != nil {
+			return fmt.Errorf("could not open thread %v: %v", entry.ThreadID, err)
+		}
+		_, err = windows.ResumeThread(thread)
+		_ = windows.CloseHandle(thread)
+		if err != nil {
+			return fmt.Errorf("could not resume thread %v: %v", entry.ThreadID, err)
+		}
+		resumed = true
+	}
+	if err != windows.ERROR_NO_MORE_FILES {
+		return fmt.Errorf("could not list the threads: %v", err)
+	}
+	if !resumed {
+		return fmt.Errorf("the process has no threads")
+	}
+	return nil
+}
+
+// signal terminates the job's processes. Windows can't ask arbitrary
+// processes to stop gracefully, so SIGTERM and SIGKILL both terminate them. If
+// the job couldn't be created, only the process is killed.
+func (g *processGroup) signal(process *os.Process, sig syscall.Signal) error {
+	if g.job == 0 {
+		return process.Kill()
+	}
+	return windows.TerminateJobObject(g.job, 1)
+}
+
+// close closes the job's handle, which terminates any of its processes that
+// are still running.
+func (g *processGroup) close() {
+	if g.job != 0 {
+		_ = windows.CloseHandle(g.job)
+		g.job = 0
+	}
+}
+
+func (g *processGroup) String() string {
+	if g.job == 0 {
+		return ""
+	}
+	return fmt.Sprintf("(Job %v) ", g.job)
+}
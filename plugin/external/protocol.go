@@ -0,0 +1,149 @@
+package external
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// ProtocolVersion is the version of the external plugin protocol that Wash
+// implements. Version 1 is the protocol before plugins reported their
+// version. Version 2 adds the handshake, where init's result reports the
+// plugin's protocol version and capabilities. Version 3 adds the daemon mode,
+// where the script's launched once with the daemon method and called over
+// JSON-RPC.
+const ProtocolVersion = 3
+
+// ProtocolVersionEnv is the environment variable that tells plugin scripts
+// which protocol version Wash implements, so that they can avoid features
+// that it doesn't support.
+const ProtocolVersionEnv = "WASH_PLUGIN_PROTOCOL_VERSION"
+
+// BlockReadCapability is the capability of reading content in blocks, i.e.
+// of implementing the ["read", true] method tuple.
+const BlockReadCapability = "block_read"
+
+// DaemonMethod is the method that plugin scripts are launched with when their
+// protocol is jsonrpc. The script then serves JSON-RPC on its stdin and stdout
+// until its stdin's closed.
+const DaemonMethod = "daemon"
+
+// daemonNotifications are the JSON-RPC notifications of the daemon mode. The
+// daemon sends output notifications, and Wash sends the others.
+var daemonNotifications = map[string]bool{
+	"output": true,
+	"input":  true,
+	"cancel": true,
+}
+
+// DaemonNotifications returns the daemon mode's notifications, sorted by name.
+func DaemonNotifications() []string {
+	notifications := make([]string, 0, len(daemonNotifications))
+	for notification := range daemonNotifications {
+		notifications = append(notifications, notification)
+	}
+	sort.Strings(notifications)
+	return notifications
+}
+
+// supportedMethods are the methods that Wash calls on external plugin
+// entries. Entries' other methods are ignored, since Wash can't invoke them.
+var supportedMethods = map[string]bool{
+	"list":     true,
+	"read":     true,
+	"write":    true,
+	"metadata": true,
+	"stream":   true,
+	"exec":     true,
+	"schema":   true,
+	"delete":   true,
+	"signal":   true,
+	"search":   true,
+	"rename":   true,
+}
+
+// SupportedMethods returns the methods that Wash calls on external plugin
+// entries, sorted by name.
+func SupportedMethods() []string {
+	methods := make([]string, 0, len(supportedMethods))
+	for method := range supportedMethods {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+// decodedHandshake is the part of init's result that negotiates the
+// protocol. A zero version is version 1.
+type decodedHandshake struct {
+	ProtocolVersion int      `json:"protocol_version"`
+	Capabilities    []string `json:"capabilities"`
+}
+
+// capabilitySet is the set of methods that a plugin's entries can
+// implement. A nil set allows every supported method, which is the case for
+// plugins that don't report their capabilities.
+type capabilitySet map[string]bool
+
+// newCapabilitySet validates the handshake and returns the plugin's
+// capabilities. Capabilities that Wash doesn't know about are dropped with a
+// warning, so that newer plugins degrade gracefully.
+func newCapabilitySet(ctx context.Context, script string, h decodedHandshake) (capabilitySet, error) {
+	if h.ProtocolVersion > ProtocolVersion {
+		return nil, fmt.Errorf(
+			"the plugin requires version %v of the external plugin protocol, but Wash only supports up to version %v. Upgrade Wash to use it",
+			h.ProtocolVersion,
+			ProtocolVersion,
+		)
+	}
+	if h.Capabilities == nil {
+		return nil, nil
+	}
+	caps := make(capabilitySet)
+	var unknown []string
+	for _, capability := range h.Capabilities {
+		if supportedMethods[capability] || capability == BlockReadCapability {
+			caps[capability] = true
+		} else {
+			unknown = append(unknown, capability)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		activity.Warnf(ctx, "%v: ignoring the capabilities that Wash doesn't support: %v", script, unknown)
+	}
+	return caps, nil
+}
+
+// allows returns true if the plugin can implement the method with the given
+// signature.
+func (caps capabilitySet) allows(method string, signature plugin.MethodSignature) bool {
+	if !supportedMethods[method] {
+		return false
+	}
+	if caps == nil || method == "schema" {
+		// The schema method's validated separately since its presence is
+		// an invariant of the plugin's schema.
+		return true
+	}
+	if signature == plugin.BlockReadableSignature && !caps[BlockReadCapability] {
+		return false
+	}
+	return caps[method]
+}
+
+// methodsToActions returns the actions that correspond to the methods. Some
+// methods, like schema and metadata, aren't actions.
+func methodsToActions(methods []string) []string {
+	actions := plugin.Actions()
+	result := []string{}
+	for _, method := range methods {
+		if _, ok := actions[method]; ok && supportedMethods[method] {
+			result = append(result, method)
+		}
+	}
+	return result
+}
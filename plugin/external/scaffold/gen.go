@@ -0,0 +1,34 @@
+//go:build ignore
+// +build ignore
+
+// gen compiles protocol.json into protocolJSON.go.
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+func main() {
+	schema, err := ioutil.ReadFile("protocol.json")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if strings.Contains(string(schema), "`") {
+		fmt.Fprintln(os.Stderr, "protocol.json can't contain backticks")
+		os.Exit(1)
+	}
+	src := fmt.Sprintf(`// Code generated by gen.go from protocol.json. DO NOT EDIT.
+
+package scaffold
+
+const protocolJSON = %v
+`, "`"+string(schema)+"`")
+	if err := ioutil.WriteFile("protocolJSON.go", []byte(src), 0644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,232 @@
+/*
+Package scaffold generates skeleton external plugins in other languages. The
+external plugin protocol is defined by protocol.json, which is the
+machine-readable version of the protocol's docs. The skeletons' method stubs
+and dispatchers are generated from it, so they stay in sync with the methods
+that Wash invokes.
+
+protocol.json is compiled into protocolJSON.go. Regenerate it with go generate
+after editing protocol.json.
+*/
+package scaffold
+
+//go:generate go run gen.go
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/puppetlabs/wash/plugin/external"
+)
+
+// Protocol is the external plugin protocol's schema.
+type Protocol struct {
+	Version      int          `json:"version"`
+	VersionEnv   string       `json:"version_env"`
+	Methods      []Method     `json:"methods"`
+	Capabilities []Capability `json:"capabilities"`
+	Daemon       Daemon       `json:"daemon"`
+}
+
+// Method describes how one of the protocol's methods is invoked. Methods that
+// are invoked on an entry are passed the entry's path and state before their
+// arguments.
+//
+// Output is one of entry, entries, content, json, stream, exit_code or none.
+type Method struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	OnEntry     bool   `json:"on_entry"`
+	Args        []Arg  `json:"args"`
+	Stdin       bool   `json:"stdin"`
+	Output      string `json:"output"`
+}
+
+// Arg is one of a method's arguments. Type is one of string, int or json.
+// Only the trailing arguments can be optional or variadic.
+type Arg struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Type        string `json:"type"`
+	Optional    bool   `json:"optional"`
+	Variadic    bool   `json:"variadic"`
+}
+
+// Daemon describes the daemon mode, where the script's launched once with
+// Method and its methods are called over JSON-RPC 2.0. Results maps each of
+// the methods' outputs to a description of its call's JSON-RPC result.
+type Daemon struct {
+	Description   string            `json:"description"`
+	Method        string            `json:"method"`
+	Results       map[string]string `json:"results"`
+	Notifications []Notification    `json:"notifications"`
+}
+
+// Notification is one of the daemon mode's JSON-RPC notifications. Sender is
+// either wash or plugin. Its params' types are string, int or bool.
+type Notification struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Sender      string `json:"sender"`
+	Params      []Arg  `json:"params"`
+}
+
+// Capability is a capability that isn't a method.
+type Capability struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+var outputs = map[string]bool{
+	"entry":     true,
+	"entries":   true,
+	"content":   true,
+	"json":      true,
+	"stream":    true,
+	"exit_code": true,
+	"none":      true,
+}
+
+var argTypes = map[string]bool{
+	"string": true,
+	"int":    true,
+	"json":   true,
+}
+
+var paramTypes = map[string]bool{
+	"string": true,
+	"int":    true,
+	"bool":   true,
+}
+
+var senders = map[string]bool{
+	"wash":   true,
+	"plugin": true,
+}
+
+// LoadProtocol returns the protocol's schema.
+func LoadProtocol() (Protocol, error) {
+	var p Protocol
+	if err := json.Unmarshal([]byte(protocolJSON), &p); err != nil {
+		return p, fmt.Errorf("could not decode the protocol's schema: %v", err)
+	}
+	return p, nil
+}
+
+// Validate returns an error if the schema's out of sync with the protocol
+// that Wash implements, or if it's malformed.
+func (p Protocol) Validate() error {
+	if p.Version != external.ProtocolVersion {
+		return fmt.Errorf("the schema's version is %v, but Wash implements version %v", p.Version, external.ProtocolVersion)
+	}
+	if p.VersionEnv != external.ProtocolVersionEnv {
+		return fmt.Errorf("the schema's version_env is %v, but Wash sets %v", p.VersionEnv, external.ProtocolVersionEnv)
+	}
+
+	methods := make(map[string]bool)
+	for _, m := range p.Methods {
+		if methods[m.Name] {
+			return fmt.Errorf("the %v method is defined twice", m.Name)
+		}
+		methods[m.Name] = true
+		if m.Name == "init" && m.OnEntry {
+			return fmt.Errorf("init isn't invoked on an entry")
+		}
+		if !outputs[m.Output] {
+			return fmt.Errorf("%v has an unknown output %v", m.Name, m.Output)
+		}
+		for i, arg := range m.Args {
+			if !argTypes[arg.Type] {
+				return fmt.Errorf("%v's %v argument has an unknown type %v", m.Name, arg.Name, arg.Type)
+			}
+			last := i == len(m.Args)-1
+			if arg.Variadic && !last {
+				return fmt.Errorf("%v's %v argument is variadic, but it isn't the last argument", m.Name, arg.Name)
+			}
+			if i > 0 && m.Args[i-1].Optional && !arg.Optional {
+				return fmt.Errorf("%v's %v argument must be optional, since it follows an optional argument", m.Name, arg.Name)
+			}
+		}
+	}
+	if !methods["init"] {
+		return fmt.Errorf("the init method is missing")
+	}
+	delete(methods, "init")
+	for _, method := range external.SupportedMethods() {
+		if !methods[method] {
+			return fmt.Errorf("the %v method is missing", method)
+		}
+		delete(methods, method)
+	}
+	if len(methods) > 0 {
+		var unknown []string
+		for method := range methods {
+			unknown = append(unknown, method)
+		}
+		sort.Strings(unknown)
+		return fmt.Errorf("the schema has methods that Wash doesn't invoke: %v", unknown)
+	}
+
+	capabilities := make(map[string]bool)
+	for _, c := range p.Capabilities {
+		capabilities[c.Name] = true
+	}
+	if !capabilities[external.BlockReadCapability] {
+		return fmt.Errorf("the %v capability is missing", external.BlockReadCapability)
+	}
+	return p.Daemon.validate()
+}
+
+func (d Daemon) validate() error {
+	if d.Method != external.DaemonMethod {
+		return fmt.Errorf("the daemon's method is %v, but Wash launches daemons with %v", d.Method, external.DaemonMethod)
+	}
+	for output := range outputs {
+		if d.Results[output] == "" {
+			return fmt.Errorf("the daemon's result for %v outputs is missing", output)
+		}
+	}
+
+	notifications := make(map[string]bool)
+	for _, n := range d.Notifications {
+		if notifications[n.Name] {
+			return fmt.Errorf("the %v notification is defined twice", n.Name)
+		}
+		notifications[n.Name] = true
+		if !senders[n.Sender] {
+			return fmt.Errorf("the %v notification has an unknown sender %v", n.Name, n.Sender)
+		}
+		for _, param := range n.Params {
+			if !paramTypes[param.Type] {
+				return fmt.Errorf("the %v notification's %v param has an unknown type %v", n.Name, param.Name, param.Type)
+			}
+		}
+	}
+	for _, notification := range external.DaemonNotifications() {
+		if !notifications[notification] {
+			return fmt.Errorf("the %v notification is missing", notification)
+		}
+		delete(notifications, notification)
+	}
+	if len(notifications) > 0 {
+		var unknown []string
+		for notification := range notifications {
+			unknown = append(unknown, notification)
+		}
+		sort.Strings(unknown)
+		return fmt.Errorf("the schema has notifications that Wash doesn't use: %v", unknown)
+	}
+	return nil
+}
+
+// EntryMethods returns the methods that are invoked on entries.
+func (p Protocol) EntryMethods() []Method {
+	var methods []Method
+	for _, m := range p.Methods {
+		if m.OnEntry {
+			methods = append(methods, m)
+		}
+	}
+	return methods
+}
@@ -0,0 +1,105 @@
+package scaffold
+
+const pythonTemplate = `#!/usr/bin/env python3
+"""{{.Name}} is a Wash external plugin.
+
+It was generated by wash plugin scaffold. Each do_<method> function implements
+one of the external plugin protocol's methods, and main dispatches Wash's
+invocations to them. See https://puppetlabs.github.io/wash/docs/external-plugins
+for the methods' calling conventions. Add the methods that the plugin
+implements to CAPABILITIES.
+
+The plugin root lists a single hello entry, which can be read.
+"""
+
+import json
+import sys
+
+# The version of the external plugin protocol that the plugin was written for.
+PROTOCOL_VERSION = {{.Protocol.Version}}
+
+# The methods that the plugin implements. Wash ignores the methods that an
+# entry reports if they aren't capabilities.
+CAPABILITIES = ["list", "read"]
+{{range .Protocol.Methods}}
+
+def do_{{.Name}}({{params .}}):
+    """{{.Description}}"""
+{{- if eq .Name "init"}}
+    return {
+        "protocol_version": PROTOCOL_VERSION,
+        "capabilities": CAPABILITIES,
+        "methods": ["list"],
+    }
+{{- else if eq .Name "list"}}
+    return [
+        {"name": "hello", "methods": ["read"]},
+    ]
+{{- else if eq .Name "read"}}
+    return "Hello, world!\n"
+{{- else}}
+    raise NotImplementedError("{{.Name}} is not implemented")
+{{- end}}
+{{end}}
+
+# The methods' calling conventions, which are generated from Wash's protocol
+# schema: the method's function, whether it's invoked on an entry, its
+# arguments' types, whether it reads stdin, and its output.
+METHODS = {
+{{- range .Protocol.Methods}}
+    "{{.Name}}": (do_{{.Name}}, {{bool .OnEntry}}, {{argTypes .}}, {{bool .Stdin}}, "{{.Output}}"),
+{{- end}}
+}
+
+
+def decode(arg, arg_type):
+    if arg_type == "int":
+        return int(arg)
+    if arg_type == "json":
+        return json.loads(arg) if arg else None
+    return arg
+
+
+def main(argv):
+    if len(argv) < 2 or argv[1] not in METHODS:
+        sys.stderr.write("usage: %s <method> <path> <state> <args...>\n" % argv[0])
+        return 1
+    method, args = argv[1], argv[2:]
+    fn, on_entry, arg_types, reads_stdin, output = METHODS[method]
+    params = []
+    if on_entry:
+        if len(args) < 2:
+            sys.stderr.write("%s must be called with the entry's path and state\n" % method)
+            return 1
+        params, args = args[:2], args[2:]
+    if reads_stdin:
+        params.append(sys.stdin.buffer)
+
+    try:
+        for i, arg in enumerate(args):
+            # Variadic arguments have the last argument's type.
+            params.append(decode(arg, arg_types[min(i, len(arg_types) - 1)]))
+        result = fn(*params)
+        if output in ("entry", "entries", "json"):
+            json.dump(result, sys.stdout)
+        elif output == "content":
+            if isinstance(result, str):
+                result = result.encode()
+            sys.stdout.buffer.write(result)
+        elif output == "stream":
+            sys.stdout.write("200\n")
+            sys.stdout.flush()
+            for chunk in result:
+                sys.stdout.write(chunk)
+                sys.stdout.flush()
+        elif output == "exit_code":
+            return result
+    except Exception as e:
+        sys.stderr.write("%s\n" % e)
+        return 1
+    return 0
+
+
+if __name__ == "__main__":
+    sys.exit(main(sys.argv))
+`
@@ -0,0 +1,105 @@
+package scaffold
+
+const rubyTemplate = `#!/usr/bin/env ruby
+# frozen_string_literal: true
+
+# {{.Name}} is a Wash external plugin.
+#
+# It was generated by wash plugin scaffold. Each do_<method> method implements
+# one of the external plugin protocol's methods, and main dispatches Wash's
+# invocations to them. See https://puppetlabs.github.io/wash/docs/external-plugins
+# for the methods' calling conventions. Add the methods that the plugin
+# implements to CAPABILITIES.
+#
+# The plugin root lists a single hello entry, which can be read.
+
+require 'json'
+
+# The version of the external plugin protocol that the plugin was written for.
+PROTOCOL_VERSION = {{.Protocol.Version}}
+
+# The methods that the plugin implements. Wash ignores the methods that an
+# entry reports if they aren't capabilities.
+CAPABILITIES = %w[list read].freeze
+{{range .Protocol.Methods}}
+# {{.Description}}
+def do_{{.Name}}({{params .}})
+{{- if eq .Name "init"}}
+  {
+    protocol_version: PROTOCOL_VERSION,
+    capabilities: CAPABILITIES,
+    methods: ['list']
+  }
+{{- else if eq .Name "list"}}
+  [
+    { name: 'hello', methods: ['read'] }
+  ]
+{{- else if eq .Name "read"}}
+  "Hello, world!\n"
+{{- else}}
+  raise NotImplementedError, '{{.Name}} is not implemented'
+{{- end}}
+end
+{{end}}
+# The methods' calling conventions, which are generated from Wash's protocol
+# schema: the method's function, whether it's invoked on an entry, its
+# arguments' types, whether it reads stdin, and its output.
+METHODS = {
+{{- range .Protocol.Methods}}
+  '{{.Name}}' => [method(:do_{{.Name}}), {{bool .OnEntry}}, {{argTypes .}}, {{bool .Stdin}}, '{{.Output}}'],
+{{- end}}
+}.freeze
+
+def decode(arg, type)
+  case type
+  when 'int' then Integer(arg)
+  when 'json' then arg.empty? ? nil : JSON.parse(arg)
+  else arg
+  end
+end
+
+def main(argv)
+  method, *args = argv
+  unless METHODS.key?(method)
+    warn "usage: #{$PROGRAM_NAME} <method> <path> <state> <args...>"
+    return 1
+  end
+  fn, on_entry, arg_types, reads_stdin, output = METHODS[method]
+  params = []
+  if on_entry
+    if args.length < 2
+      warn "#{method} must be called with the entry's path and state"
+      return 1
+    end
+    params = args.shift(2)
+  end
+  params << $stdin if reads_stdin
+
+  args.each_with_index do |arg, i|
+    # Variadic arguments have the last argument's type.
+    params << decode(arg, arg_types[[i, arg_types.length - 1].min])
+  end
+  result = fn.call(*params)
+  case output
+  when 'entry', 'entries', 'json'
+    puts JSON.generate(result)
+  when 'content'
+    $stdout.write(result)
+  when 'stream'
+    $stdout.write("200\n")
+    $stdout.flush
+    result.each do |chunk|
+      $stdout.write(chunk)
+      $stdout.flush
+    end
+  when 'exit_code'
+    return result
+  end
+  0
+rescue StandardError, NotImplementedError => e
+  warn e.message
+  1
+end
+
+exit(main(ARGV)) if __FILE__ == $PROGRAM_NAME
+`
@@ -0,0 +1,108 @@
+package scaffold
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// language is a language that skeletons can be generated in.
+type language struct {
+	extension string
+	template  *template.Template
+}
+
+var languages = map[string]language{
+	"python": {
+		extension: ".py",
+		template:  newTemplate("python", pythonTemplate, "=None", "True", "False"),
+	},
+	"ruby": {
+		extension: ".rb",
+		template:  newTemplate("ruby", rubyTemplate, " = nil", "true", "false"),
+	},
+}
+
+// newTemplate parses the language's template. Its params function formats a
+// method's parameters, so the language's syntax for default values and
+// booleans is passed in.
+func newTemplate(name string, text string, optionalSuffix string, trueLiteral string, falseLiteral string) *template.Template {
+	funcs := template.FuncMap{
+		"params": func(m Method) string {
+			var params []string
+			if m.OnEntry {
+				params = append(params, "path", "state")
+			}
+			if m.Stdin {
+				params = append(params, "stdin")
+			}
+			for _, arg := range m.Args {
+				switch {
+				case arg.Variadic:
+					params = append(params, "*"+arg.Name)
+				case arg.Optional:
+					params = append(params, arg.Name+optionalSuffix)
+				default:
+					params = append(params, arg.Name)
+				}
+			}
+			return strings.Join(params, ", ")
+		},
+		"bool": func(b bool) string {
+			if b {
+				return trueLiteral
+			}
+			return falseLiteral
+		},
+		// argTypes is a list literal that's valid in both Python and Ruby.
+		"argTypes": func(m Method) string {
+			var types []string
+			for _, arg := range m.Args {
+				types = append(types, strconv.Quote(arg.Type))
+			}
+			return "[" + strings.Join(types, ", ") + "]"
+		},
+	}
+	return template.Must(template.New(name).Funcs(funcs).Parse(text))
+}
+
+// Languages returns the languages that skeletons can be generated in.
+func Languages() []string {
+	var names []string
+	for name := range languages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Skeleton returns the file name and content of a skeleton plugin named name
+// that's written in the language. The skeleton's plugin root lists a single
+// readable entry. Its other methods are stubs, and its dispatcher implements
+// the protocol's calling conventions.
+func Skeleton(lang string, name string) (string, []byte, error) {
+	l, ok := languages[lang]
+	if !ok {
+		return "", nil, fmt.Errorf("unknown language %v. Supported languages are %v", lang, strings.Join(Languages(), ", "))
+	}
+	protocol, err := LoadProtocol()
+	if err != nil {
+		return "", nil, err
+	}
+	if err := protocol.Validate(); err != nil {
+		return "", nil, fmt.Errorf("the protocol's schema is invalid: %v", err)
+	}
+
+	var content bytes.Buffer
+	data := struct {
+		Name     string
+		Protocol Protocol
+	}{name, protocol}
+	if err := l.template.Execute(&content, data); err != nil {
+		return "", nil, err
+	}
+	return name + l.extension, content.Bytes(), nil
+}
@@ -0,0 +1,121 @@
+package scaffold
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProtocolJSONIsUpToDate(t *testing.T) {
+	schema, err := ioutil.ReadFile("protocol.json")
+	require.NoError(t, err)
+	assert.Equal(t, string(schema), protocolJSON, "protocolJSON.go is out of date. Run go generate")
+}
+
+func TestProtocolIsValid(t *testing.T) {
+	p, err := LoadProtocol()
+	require.NoError(t, err)
+	assert.NoError(t, p.Validate())
+}
+
+func TestValidate_ReturnsErrorIfMalformed(t *testing.T) {
+	valid, err := LoadProtocol()
+	require.NoError(t, err)
+
+	p := valid
+	p.Version++
+	assert.Regexp(t, "schema's version is 4", p.Validate())
+
+	p = valid
+	p.Methods = append(p.Methods[1:len(p.Methods):len(p.Methods)], Method{Name: "watch", OnEntry: true, Output: "none"})
+	assert.EqualError(t, p.Validate(), "the init method is missing")
+
+	p = valid
+	p.Methods = append(p.Methods[:len(p.Methods):len(p.Methods)], Method{Name: "watch", OnEntry: true, Output: "none"})
+	assert.EqualError(t, p.Validate(), "the schema has methods that Wash doesn't invoke: [watch]")
+
+	p = valid
+	p.Methods = append(p.Methods[:len(p.Methods):len(p.Methods)], Method{
+		Name:    "watch",
+		OnEntry: true,
+		Output:  "none",
+		Args:    []Arg{{Name: "a", Type: "string", Optional: true}, {Name: "b", Type: "string"}},
+	})
+	assert.EqualError(t, p.Validate(), "watch's b argument must be optional, since it follows an optional argument")
+
+	p = valid
+	p.Daemon.Method = "serve"
+	assert.EqualError(t, p.Validate(), "the daemon's method is serve, but Wash launches daemons with daemon")
+
+	p = valid
+	p.Daemon.Notifications = p.Daemon.Notifications[1:]
+	assert.EqualError(t, p.Validate(), "the output notification is missing")
+
+	p = valid
+	p.Daemon.Notifications = append(p.Daemon.Notifications[:len(p.Daemon.Notifications):len(p.Daemon.Notifications)], Notification{Name: "progress", Sender: "plugin"})
+	assert.EqualError(t, p.Validate(), "the schema has notifications that Wash doesn't use: [progress]")
+}
+
+func TestSkeleton_ReturnsErrorForUnknownLanguage(t *testing.T) {
+	_, _, err := Skeleton("cobol", "myplugin")
+	assert.EqualError(t, err, "unknown language cobol. Supported languages are python, ruby")
+}
+
+func TestSkeleton_Python(t *testing.T) {
+	script := writeSkeleton(t, "python", "myplugin.py")
+	defer os.RemoveAll(filepath.Dir(script))
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 isn't installed")
+	}
+	testSkeleton(t, "python3", script)
+}
+
+func TestSkeleton_Ruby(t *testing.T) {
+	script := writeSkeleton(t, "ruby", "myplugin.rb")
+	defer os.RemoveAll(filepath.Dir(script))
+	if _, err := exec.LookPath("ruby"); err != nil {
+		t.Skip("ruby isn't installed")
+	}
+	testSkeleton(t, "ruby", script)
+}
+
+func writeSkeleton(t *testing.T, language string, expectedName string) string {
+	name, content, err := Skeleton(language, "myplugin")
+	require.NoError(t, err)
+	assert.Equal(t, expectedName, name)
+
+	dir, err := ioutil.TempDir("", "scaffold")
+	require.NoError(t, err)
+	script := filepath.Join(dir, name)
+	require.NoError(t, ioutil.WriteFile(script, content, 0755))
+	return script
+}
+
+func testSkeleton(t *testing.T, interpreter string, script string) {
+	run := func(args ...string) (string, error) {
+		out, err := exec.Command(interpreter, append([]string{script}, args...)...).Output()
+		return string(out), err
+	}
+
+	out, err := run("init", "{}")
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{"protocol_version":3,"capabilities":["list","read"],"methods":["list"]}`, out)
+	}
+	out, err = run("list", "/myplugin", "")
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `[{"name":"hello","methods":["read"]}]`, out)
+	}
+	out, err = run("read", "/myplugin/hello", "")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "Hello, world!\n", out)
+	}
+	_, err = run("signal", "/myplugin/hello", "", "stop")
+	if assert.Error(t, err) {
+		assert.Regexp(t, "signal is not implemented", string(err.(*exec.ExitError).Stderr))
+	}
+}
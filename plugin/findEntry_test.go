@@ -69,8 +69,8 @@ func TestFindEntry(t *testing.T) {
 	parent.DisableDefaultCaching()
 	for _, c := range []testcase{
 		{[]string{"not found"}, "", fmt.Errorf("The not found entry does not exist")},
-		{[]string{"foo#bar"}, "foo#bar", nil},
-		{[]string{"foo#bar", "bar"}, "", fmt.Errorf("The entry foo#bar is not a parent")},
+		{[]string{"foo%2Fbar"}, "foo%2Fbar", nil},
+		{[]string{"foo%2Fbar", "bar"}, "", fmt.Errorf("The entry %v is not a parent", "foo%2Fbar")},
 	} {
 		runTestCase(parent, c)
 	}
@@ -87,19 +87,13 @@ func TestFindEntry(t *testing.T) {
 		runTestCase(parent, c)
 	}
 
-	// Finally, test the duplicate cname error response
-	duplicateFoo := newMockEntry("foo#bar")
+	// Finally, test that entries with the same cname don't shadow each other
+	duplicateFoo := newMockEntry("foo/bar")
 	parent.entries = append(parent.entries, duplicateFoo)
-	expectedErr := DuplicateCNameErr{
-		ParentID:                 ID(parent),
-		FirstChildName:           foo.Name(),
-		FirstChildSlashReplacer:  '#',
-		SecondChildName:          duplicateFoo.Name(),
-		SecondChildSlashReplacer: '#',
-		CName:                    "foo#bar",
+	for _, c := range []testcase{
+		{[]string{"foo%2Fbar"}, "foo%2Fbar", nil},
+		{[]string{"foo%2Fbar~1"}, "foo%2Fbar~1", nil},
+	} {
+		runTestCase(parent, c)
 	}
-	runTestCase(
-		parent,
-		testcase{[]string{"foo#bar"}, "", expectedErr},
-	)
 }
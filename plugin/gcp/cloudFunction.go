@@ -2,10 +2,13 @@ package gcp
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"strings"
 	"time"
 
+	"github.com/puppetlabs/wash/activity"
 	"github.com/puppetlabs/wash/plugin"
 	"google.golang.org/api/cloudfunctions/v1"
 )
@@ -13,7 +16,9 @@ import (
 type cloudFunction struct {
 	plugin.EntryBase
 	service cloudFunctionsProjectService
-	region  string
+	// path is the function's full resource name
+	path   string
+	region string
 }
 
 func newCloudFunction(function *cloudfunctions.CloudFunction, service cloudFunctionsProjectService) *cloudFunction {
@@ -24,16 +29,30 @@ func newCloudFunction(function *cloudfunctions.CloudFunction, service cloudFunct
 	cf := &cloudFunction{
 		EntryBase: plugin.NewEntry(functionName),
 		service:   service,
+		path:      functionPath,
 		region:    region,
 	}
 	mtime, err := time.Parse(time.RFC3339, function.UpdateTime)
 	if err != nil {
 		panic(fmt.Sprintf("Timestamp for %v was not expected format RFC3339: %v", cf, function.UpdateTime))
 	}
+	trigger := ""
+	if function.HttpsTrigger != nil {
+		trigger = function.HttpsTrigger.Url
+	} else if function.EventTrigger != nil {
+		trigger = function.EventTrigger.EventType
+	}
 	cf.
 		SetPartialMetadata(function).
 		Attributes().
-		SetMtime(mtime)
+		SetMtime(mtime).
+		SetCustom("region", region).
+		SetCustom("runtime", function.Runtime).
+		SetCustom("status", function.Status).
+		SetCustom("version", function.VersionId).
+		SetCustom("entry_point", function.EntryPoint).
+		SetCustom("memory_mb", function.AvailableMemoryMb).
+		SetCustom("trigger", trigger)
 	return cf
 }
 
@@ -45,14 +64,53 @@ func (cf *cloudFunction) List(ctx context.Context) ([]plugin.Entry, error) {
 	return []plugin.Entry{cfl}, nil
 }
 
+// Exec supports the invoke command. See the function's description for its
+// usage.
+func (cf *cloudFunction) Exec(ctx context.Context, cmd string, args []string, opts plugin.ExecOptions) (plugin.ExecCommand, error) {
+	payload, err := invocationPayload(cmd, args, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	execCmd := plugin.NewExecCommand(ctx)
+	go func() {
+		req := &cloudfunctions.CallFunctionRequest{Data: payload}
+		resp, err := cf.service.Projects.Locations.Functions.Call(cf.path, req).Context(ctx).Do()
+		if err != nil {
+			execCmd.CloseStreamsWithError(err)
+			execCmd.SetExitCodeErr(err)
+			return
+		}
+		activity.Record(ctx, "Invoked %v with execution ID %v", cf.path, resp.ExecutionId)
+		if resp.Error != "" {
+			_, err = execCmd.Stderr().Write([]byte(resp.Error + "\n"))
+			execCmd.CloseStreamsWithError(err)
+			execCmd.SetExitCode(1)
+			return
+		}
+		_, err = execCmd.Stdout().Write([]byte(resp.Result + "\n"))
+		execCmd.CloseStreamsWithError(err)
+		execCmd.SetExitCode(0)
+	}()
+	return execCmd, nil
+}
+
 func (cf *cloudFunction) Delete(ctx context.Context) (bool, error) {
-	_, err := cf.service.Projects.Locations.Functions.Delete(cf.Name()).Context(ctx).Do()
+	_, err := cf.service.Projects.Locations.Functions.Delete(cf.path).Context(ctx).Do()
 	return false, err
 }
 
 func (cf *cloudFunction) Schema() *plugin.EntrySchema {
 	return plugin.NewEntrySchema(cf, "cloud_function").
-		SetPartialMetadataSchema(cloudfunctions.CloudFunction{})
+		SetPartialMetadataSchema(cloudfunctions.CloudFunction{}).
+		SetDescription(cloudFunctionDescription).
+		AddCustomAttribute("region", plugin.StringAttribute, "The region that the function's deployed to").
+		AddCustomAttribute("runtime", plugin.StringAttribute, "The function's runtime, e.g. go113 or nodejs10").
+		AddCustomAttribute("status", plugin.StringAttribute, "The function's deployment status, e.g. ACTIVE or DEPLOY_IN_PROGRESS").
+		AddCustomAttribute("version", plugin.NumberAttribute, "The version of the function's deployment. It's incremented on each deployment").
+		AddCustomAttribute("entry_point", plugin.StringAttribute, "The name of the function that's executed").
+		AddCustomAttribute("memory_mb", plugin.NumberAttribute, "The memory available to the function in MB").
+		AddCustomAttribute("trigger", plugin.StringAttribute, "The function's URL if it's triggered by HTTP requests, otherwise the type of event that triggers it")
 }
 
 func (cf *cloudFunction) ChildSchemas() []*plugin.EntrySchema {
@@ -60,3 +118,39 @@ func (cf *cloudFunction) ChildSchemas() []*plugin.EntrySchema {
 		(&cloudFunctionLog{}).Schema(),
 	}
 }
+
+// invocationPayload returns the JSON payload of an invoke command. The payload
+// defaults to an empty object, and '-' reads it from stdin.
+func invocationPayload(cmd string, args []string, opts plugin.ExecOptions) (string, error) {
+	if cmd != "invoke" {
+		return "", fmt.Errorf("unsupported command %v, only invoke is supported", cmd)
+	}
+	payload := strings.Join(args, " ")
+	if payload == "-" && opts.Stdin != nil {
+		content, err := ioutil.ReadAll(opts.Stdin)
+		if err != nil {
+			return "", err
+		}
+		payload = string(content)
+	}
+	if strings.TrimSpace(payload) == "" {
+		payload = "{}"
+	}
+	if !json.Valid([]byte(payload)) {
+		return "", fmt.Errorf("invoke requires a JSON payload, not %v", payload)
+	}
+	return payload, nil
+}
+
+const cloudFunctionDescription = `
+A Cloud Function. Its log entry contains the function's logs, and its
+attributes include the function's deployment details like its runtime, status
+and version.
+
+Exec supports the invoke command, which calls the function with the given JSON
+payload and prints its result. It's meant for quick smoke tests, e.g.
+  wash exec cloud_functions/my-function invoke '{"name": "wash"}'
+
+Use '-' as the payload to read it from stdin. The payload defaults to '{}'.
+The invocations are rate limited, so use the function's trigger to load test it.
+`
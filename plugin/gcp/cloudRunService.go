@@ -3,8 +3,12 @@ package gcp
 import (
 	"context"
 	"fmt"
+	"io"
+	"net/http"
+	"strings"
 	"time"
 
+	"github.com/puppetlabs/wash/activity"
 	"github.com/puppetlabs/wash/plugin"
 	"google.golang.org/api/run/v1"
 )
@@ -13,6 +17,7 @@ type cloudRunService struct {
 	plugin.EntryBase
 	apiService cloudRunProjectAPIService
 	region     string
+	url        string
 }
 
 func newCloudRunService(service *run.Service, apiService cloudRunProjectAPIService) *cloudRunService {
@@ -25,10 +30,24 @@ func newCloudRunService(service *run.Service, apiService cloudRunProjectAPIServi
 	if err != nil {
 		panic(fmt.Sprintf("Timestamp for %v was not expected format RFC3339: %v", crs, service.Metadata.CreationTimestamp))
 	}
+	latestRevision, ready := "", "Unknown"
+	if service.Status != nil {
+		crs.url = service.Status.Url
+		latestRevision = service.Status.LatestReadyRevisionName
+		for _, condition := range service.Status.Conditions {
+			if condition.Type == "Ready" {
+				ready = condition.Status
+			}
+		}
+	}
 	crs.
 		SetPartialMetadata(service).
 		Attributes().
-		SetCrtime(crtime)
+		SetCrtime(crtime).
+		SetCustom("region", crs.region).
+		SetCustom("url", crs.url).
+		SetCustom("latest_revision", latestRevision).
+		SetCustom("ready", ready)
 	return crs
 }
 
@@ -40,6 +59,51 @@ func (crs *cloudRunService) List(ctx context.Context) ([]plugin.Entry, error) {
 	return []plugin.Entry{crsl}, nil
 }
 
+// Exec supports the invoke command. See the service's description for its
+// usage.
+func (crs *cloudRunService) Exec(ctx context.Context, cmd string, args []string, opts plugin.ExecOptions) (plugin.ExecCommand, error) {
+	payload, err := invocationPayload(cmd, args, opts)
+	if err != nil {
+		return nil, err
+	}
+	if crs.url == "" {
+		return nil, fmt.Errorf("%v doesn't have a URL yet, so it can't be invoked", crs.Name())
+	}
+	req, err := http.NewRequest(http.MethodPost, crs.url, strings.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req = req.WithContext(ctx)
+
+	execCmd := plugin.NewExecCommand(ctx)
+	go func() {
+		// Cloud Run only accepts ID tokens, not the plugin's OAuth access tokens,
+		// so the request's unauthenticated.
+		resp, err := plugin.HTTPClient().Do(req)
+		if err != nil {
+			execCmd.CloseStreamsWithError(err)
+			execCmd.SetExitCodeErr(err)
+			return
+		}
+		defer resp.Body.Close()
+		activity.Record(ctx, "Invoked %v: %v", crs.url, resp.Status)
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			_, err = execCmd.Stderr().Write([]byte(resp.Status + "\n"))
+			if err == nil {
+				_, err = io.Copy(execCmd.Stderr(), resp.Body)
+			}
+			execCmd.CloseStreamsWithError(err)
+			execCmd.SetExitCode(1)
+			return
+		}
+		_, err = io.Copy(execCmd.Stdout(), resp.Body)
+		execCmd.CloseStreamsWithError(err)
+		execCmd.SetExitCode(0)
+	}()
+	return execCmd, nil
+}
+
 func (crs *cloudRunService) Delete(ctx context.Context) (bool, error) {
 	fullResourceName := fmt.Sprintf(
 		"projects/%s/locations/%s/services/%s",
@@ -52,7 +116,12 @@ func (crs *cloudRunService) Delete(ctx context.Context) (bool, error) {
 }
 func (crs *cloudRunService) Schema() *plugin.EntrySchema {
 	return plugin.NewEntrySchema(crs, "service").
-		SetPartialMetadataSchema(run.Service{})
+		SetPartialMetadataSchema(run.Service{}).
+		SetDescription(cloudRunServiceDescription).
+		AddCustomAttribute("region", plugin.StringAttribute, "The region that the service's deployed to").
+		AddCustomAttribute("url", plugin.StringAttribute, "The URL that the service's served on").
+		AddCustomAttribute("latest_revision", plugin.StringAttribute, "The name of the service's latest revision that's ready to serve traffic").
+		AddCustomAttribute("ready", plugin.StringAttribute, "Whether the service's ready to serve traffic. It's True, False or Unknown")
 }
 
 func (crs *cloudRunService) ChildSchemas() []*plugin.EntrySchema {
@@ -60,3 +129,17 @@ func (crs *cloudRunService) ChildSchemas() []*plugin.EntrySchema {
 		(&cloudRunServiceLog{}).Schema(),
 	}
 }
+
+const cloudRunServiceDescription = `
+A Cloud Run service. Its log entry contains the service's logs, and its
+attributes include the service's deployment details like its URL and latest
+revision.
+
+Exec supports the invoke command, which POSTs the given JSON payload to the
+service's URL and prints the response. It's meant for quick smoke tests, e.g.
+  wash exec cloud_run/my-service invoke '{"name": "wash"}'
+
+Use '-' as the payload to read it from stdin. The payload defaults to '{}'.
+The request's unauthenticated, so only services that allow unauthenticated
+invocations can be invoked.
+`
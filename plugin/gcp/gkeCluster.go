@@ -0,0 +1,117 @@
+package gcp
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/puppetlabs/wash/plugin/kubernetes"
+	"golang.org/x/oauth2"
+	container "google.golang.org/api/container/v1"
+	"k8s.io/client-go/rest"
+)
+
+type gkeCluster struct {
+	plugin.EntryBase
+	service  gkeProjectService
+	endpoint string
+	caCert   string
+	status   string
+	mux      sync.Mutex
+	// cluster is nil until the cluster's listed
+	cluster *kubernetes.Cluster
+}
+
+func newGKECluster(cluster *container.Cluster, service gkeProjectService) *gkeCluster {
+	c := &gkeCluster{
+		EntryBase: plugin.NewEntry(cluster.Name),
+		service:   service,
+		endpoint:  cluster.Endpoint,
+		status:    cluster.Status,
+	}
+	if cluster.MasterAuth != nil {
+		c.caCert = cluster.MasterAuth.ClusterCaCertificate
+	}
+	c.
+		SetPartialMetadata(cluster).
+		Attributes().
+		SetCustom("project", service.projectID).
+		SetCustom("location", cluster.Location)
+	if crtime, err := time.Parse(time.RFC3339, cluster.CreateTime); err == nil {
+		c.Attributes().SetCrtime(crtime)
+	}
+	return c
+}
+
+// List lists the cluster's Kubernetes namespaces. The cluster's credentials are
+// generated from the GCP credentials, so it doesn't need a kubeconfig context.
+func (c *gkeCluster) List(ctx context.Context) ([]plugin.Entry, error) {
+	cluster, err := c.kubernetesCluster()
+	if err != nil {
+		return nil, err
+	}
+	return cluster.List(ctx)
+}
+
+func (c *gkeCluster) kubernetesCluster() (*kubernetes.Cluster, error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if c.cluster != nil {
+		return c.cluster, nil
+	}
+
+	if c.status != "RUNNING" {
+		return nil, fmt.Errorf("the %v cluster is %v, its Kubernetes API is only reachable while it's RUNNING", c.Name(), c.status)
+	}
+	ca, err := base64.StdEncoding.DecodeString(c.caCert)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode the %v cluster's CA certificate: %w", c.Name(), err)
+	}
+	config := &rest.Config{
+		Host:            "https://" + c.endpoint,
+		TLSClientConfig: rest.TLSClientConfig{CAData: ca},
+		WrapTransport: func(rt http.RoundTripper) http.RoundTripper {
+			return &oauth2.Transport{Source: c.service.tokenSource, Base: rt}
+		},
+	}
+	cluster, err := kubernetes.NewCluster(config, "default")
+	if err != nil {
+		return nil, err
+	}
+	c.cluster = cluster
+	return cluster, nil
+}
+
+func (c *gkeCluster) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(c, "cluster").
+		SetPartialMetadataSchema(container.Cluster{}).
+		SetDescription(gkeClusterDescription).
+		AddCustomAttribute("project", plugin.StringAttribute, "The ID of the project that the cluster's in").
+		AddCustomAttribute("location", plugin.StringAttribute, "The zone or region that the cluster's in").
+		SetConsoleURL("https://console.cloud.google.com/kubernetes/clusters/details/{{.Attributes.location}}/{{.Name}}/details?project={{.Attributes.project}}")
+}
+
+func (c *gkeCluster) ChildSchemas() []*plugin.EntrySchema {
+	return kubernetes.ClusterChildSchemas()
+}
+
+// WrappedTypes implements plugin.HasWrappedTypes so that the Kubernetes entries
+// get the same metadata schemas as they do in the Kubernetes plugin.
+func (c *gkeCluster) WrappedTypes() plugin.SchemaMap {
+	return kubernetes.WrappedTypes()
+}
+
+const gkeClusterDescription = `
+This is a GKE cluster. Its entries are the cluster's Kubernetes namespaces, which
+have the same pods and persistent volume claims as the Kubernetes plugin's
+contexts, e.g.
+  ls gcp/my-project/gke/my-cluster/default/pods
+
+Credentials are generated from your GCP credentials, so the cluster doesn't need
+a kubeconfig context. Your account needs a Kubernetes Engine role (or RBAC
+bindings) that grants access to the cluster's resources.
+`
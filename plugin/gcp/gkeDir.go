@@ -0,0 +1,75 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/puppetlabs/wash/plugin"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	container "google.golang.org/api/container/v1"
+	"google.golang.org/api/option"
+)
+
+type gkeProjectService struct {
+	*container.Service
+	projectID string
+	// Used to authenticate against the clusters' Kubernetes APIs
+	tokenSource oauth2.TokenSource
+}
+
+type gkeDir struct {
+	plugin.EntryBase
+	service gkeProjectService
+}
+
+func newGKEDir(ctx context.Context, client *http.Client, projID string) (*gkeDir, error) {
+	svc, err := container.NewService(context.Background(), option.WithHTTPClient(client))
+	if err != nil {
+		return nil, err
+	}
+	tokenSource, err := google.DefaultTokenSource(context.Background(), container.CloudPlatformScope)
+	if err != nil {
+		return nil, err
+	}
+	gke := &gkeDir{
+		EntryBase: plugin.NewEntry("gke"),
+		service:   gkeProjectService{Service: svc, projectID: projID, tokenSource: tokenSource},
+	}
+	if _, err := plugin.List(ctx, gke); err != nil {
+		gke.MarkInaccessible(ctx, err)
+	}
+	return gke, nil
+}
+
+// List all clusters in all of the project's locations
+func (g *gkeDir) List(ctx context.Context) ([]plugin.Entry, error) {
+	parent := fmt.Sprintf("projects/%s/locations/-", g.service.projectID)
+	resp, err := g.service.Projects.Locations.Clusters.List(parent).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	clusters := make([]plugin.Entry, len(resp.Clusters))
+	for i, cluster := range resp.Clusters {
+		clusters[i] = newGKECluster(cluster, g.service)
+	}
+	return clusters, nil
+}
+
+func (g *gkeDir) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(g, "gke").
+		IsSingleton().
+		SetDescription(gkeDirDescription)
+}
+
+func (g *gkeDir) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&gkeCluster{}).Schema(),
+	}
+}
+
+const gkeDirDescription = `
+This directory represents Google Kubernetes Engine (GKE). Its entries consist of
+the project's GKE clusters in all locations.
+`
@@ -53,7 +53,8 @@ func (p *project) List(ctx context.Context) ([]plugin.Entry, error) {
 	go func() { save(newPubsubDir(ctx, p.id)) }()
 	go func() { save(newCloudFunctionsDir(ctx, p.client, p.id)) }()
 	go func() { save(newCloudRunDir(ctx, p.client, p.id)) }()
-	wg.Add(6)
+	go func() { save(newGKEDir(ctx, p.client, p.id)) }()
+	wg.Add(7)
 	wg.Wait()
 
 	if len(errs) > 0 {
@@ -86,6 +87,7 @@ func (p *project) ChildSchemas() []*plugin.EntrySchema {
 		(&pubsubDir{}).Schema(),
 		(&cloudFunctionsDir{}).Schema(),
 		(&cloudRunDir{}).Schema(),
+		(&gkeDir{}).Schema(),
 	}
 }
 
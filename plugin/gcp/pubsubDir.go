@@ -58,11 +58,12 @@ func (p *pubsubDir) ChildSchemas() []*plugin.EntrySchema {
 }
 
 const pubsubDirDescription = `
-This directory represents Cloud Pub/Sub. Its entries consist of Pub/Sub topics.
+This directory represents Cloud Pub/Sub. Its entries consist of Pub/Sub topics,
+whose entries consist of their subscriptions.
 
-You can publish a message to a topic by appending text to the topic file. For example
-		wash gcp/project/pubsub > tail -f topic &
-		wash gcp/project/pubsub > echo hello >> topic
-		===> my-topic <===
+You can publish a message to a topic and tail its subscriptions. For example
+		wash gcp/project/pubsub > tail -f my-topic/my-sub &
+		wash gcp/project/pubsub > wash exec my-topic publish hello
+		===> my-sub <===
 		Nov 21 00:25:14.633 | hello
 `
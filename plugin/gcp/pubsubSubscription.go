@@ -0,0 +1,186 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// pubsubAckStreamedMessages is true if streamed messages are acknowledged, i.e.
+// if streaming a subscription consumes its messages instead of peeking at them.
+// It's set by the gcp.pubsub_ack_streamed_messages config.
+var pubsubAckStreamedMessages = false
+
+// pubsubRedeliveryDelay is how long a pubsubStreamer holds on to a message that
+// it already streamed before nacking it. Nacked messages are redelivered right
+// away, so without it, peeking at a subscription would continuously receive the
+// same messages.
+var pubsubRedeliveryDelay = 2 * time.Second
+
+type pubsubSubscription struct {
+	plugin.EntryBase
+	client *pubsub.Client
+	sub    *pubsub.Subscription
+}
+
+type pubsubSubscriptionMetadata struct {
+	Topic               string
+	PushEndpoint        string
+	AckDeadline         time.Duration
+	RetainAckedMessages bool
+	RetentionDuration   time.Duration
+	Labels              map[string]string
+	DeadLetterTopic     string
+}
+
+func newPubsubSubscription(client *pubsub.Client, sub *pubsub.Subscription) *pubsubSubscription {
+	return &pubsubSubscription{
+		EntryBase: plugin.NewEntry(sub.ID()),
+		client:    client,
+		sub:       sub,
+	}
+}
+
+func (s *pubsubSubscription) Metadata(ctx context.Context) (plugin.JSONObject, error) {
+	cfg, err := s.sub.Config(ctx)
+	if err != nil {
+		return nil, err
+	}
+	meta := pubsubSubscriptionMetadata{
+		PushEndpoint:        cfg.PushConfig.Endpoint,
+		AckDeadline:         cfg.AckDeadline,
+		RetainAckedMessages: cfg.RetainAckedMessages,
+		RetentionDuration:   cfg.RetentionDuration,
+		Labels:              cfg.Labels,
+	}
+	if cfg.Topic != nil {
+		meta.Topic = cfg.Topic.ID()
+	}
+	if cfg.DeadLetterPolicy != nil {
+		meta.DeadLetterTopic = cfg.DeadLetterPolicy.DeadLetterTopic
+	}
+	return plugin.ToJSONObject(meta), nil
+}
+
+// Stream pulls the subscription's messages. See the subscription's description
+// for how they're acknowledged.
+func (s *pubsubSubscription) Stream(ctx context.Context) (io.ReadCloser, error) {
+	activity.Record(ctx, "Streaming the messages of the %v subscription", s.sub.ID())
+	// Use a separate handle so that the receive settings don't affect other
+	// streams of the subscription.
+	return newPubsubStreamer(ctx, s.client.Subscription(s.sub.ID()), pubsubAckStreamedMessages, nil), nil
+}
+
+func (s *pubsubSubscription) Delete(ctx context.Context) (bool, error) {
+	return true, s.sub.Delete(ctx)
+}
+
+func (s *pubsubSubscription) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(s, "subscription").
+		SetMetadataSchema(pubsubSubscriptionMetadata{}).
+		SetDescription(pubsubSubscriptionDescription)
+}
+
+// pubsubStreamer is a ReadCloser that receives a subscription's messages and
+// buffers them until they're read. Unless ack is true, the messages are nacked
+// so that they're redelivered to the subscription's other consumers; seen
+// contains the IDs of the streamed messages so that they're only streamed once.
+type pubsubStreamer struct {
+	ctx     context.Context
+	queue   <-chan []byte
+	err     <-chan error
+	current []byte
+	onClose func() error
+}
+
+func newPubsubStreamer(ctx context.Context, sub *pubsub.Subscription, ack bool, onClose func() error) *pubsubStreamer {
+	// Use a buffer so we can Ack messages quickly.
+	queue := make(chan []byte, 5)
+	errCh := make(chan error, 1)
+	streamer := &pubsubStreamer{ctx: ctx, queue: queue, err: errCh, onClose: onClose}
+
+	var mux sync.Mutex
+	seen := make(map[string]struct{})
+	bufferMessage := func(_ context.Context, msg *pubsub.Message) {
+		if ack {
+			msg.Ack()
+		} else {
+			mux.Lock()
+			_, ok := seen[msg.ID]
+			seen[msg.ID] = struct{}{}
+			mux.Unlock()
+			if ok {
+				select {
+				case <-ctx.Done():
+				case <-time.After(pubsubRedeliveryDelay):
+				}
+				msg.Nack()
+				return
+			}
+			defer msg.Nack()
+		}
+		activity.Record(ctx, "Received message %v", msg.ID)
+		select {
+		case <-ctx.Done():
+		case queue <- []byte(formatPubsubMessage(msg)):
+		}
+	}
+	go func() {
+		errCh <- sub.Receive(ctx, bufferMessage)
+		close(queue)
+	}()
+	return streamer
+}
+
+func (s *pubsubStreamer) Read(p []byte) (int, error) {
+	for len(s.current) == 0 {
+		select {
+		case <-s.ctx.Done():
+			return 0, io.EOF
+		case msg, ok := <-s.queue:
+			if !ok {
+				if err := <-s.err; err != nil && s.ctx.Err() == nil {
+					return 0, err
+				}
+				return 0, io.EOF
+			}
+			s.current = msg
+		}
+	}
+	n := copy(p, s.current)
+	s.current = s.current[n:]
+	return n, nil
+}
+
+func (s *pubsubStreamer) Close() error {
+	if s.onClose == nil {
+		// s is closed when the context is cancelled, so this can noop
+		return nil
+	}
+	return s.onClose()
+}
+
+// formatPubsubMessage formats the message as a line containing its publish
+// time and data.
+func formatPubsubMessage(msg *pubsub.Message) string {
+	return fmt.Sprintf("%v | %v\n", msg.PublishTime.Format(time.StampMilli), strings.TrimSuffix(string(msg.Data), "\n"))
+}
+
+const pubsubSubscriptionDescription = `
+A Cloud Pub/Sub subscription. Streaming it (e.g. with 'tail -f') pulls the
+subscription's messages and prints each message's publish time and data.
+The messages aren't acknowledged, so they're still delivered to the
+subscription's consumers. Add
+
+gcp:
+  pubsub_ack_streamed_messages: true
+
+to Wash’s config file to acknowledge the streamed messages instead.
+`
@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"runtime"
+	"strings"
 	"time"
 
 	"cloud.google.com/go/pubsub"
@@ -39,8 +41,7 @@ func newPubsubTopic(client *pubsub.Client, topic *pubsub.Topic) *pubsubTopic {
 	top.SetPartialMetadata(pubsubTopicPartialMeta{PublishSettings: topic.PublishSettings})
 
 	// This may be somewhat hacky, but it ensures the goroutines for publishing get cleaned up eventually.
-	// Writeable can optionally also implement io.Closer to do cleanup when we're done writing,
-	// but we may re-use this client so we shouldn't stop it in Close.
+	// We may re-use this topic for later publishes, so we shouldn't stop it after each one.
 	runtime.SetFinalizer(top, func(t *pubsubTopic) { t.topic.Stop() })
 	return top
 }
@@ -75,15 +76,26 @@ func (t *pubsubTopic) Delete(ctx context.Context) (bool, error) {
 	return true, t.topic.Delete(ctx)
 }
 
-// A ReadCloser that subscribes to a topic and buffers all messages that appear there.
-type pubsubTopicWatcher struct {
-	ctx   context.Context
-	sub   *pubsub.Subscription
-	queue <-chan *pubsub.Message
-	err   <-chan error
+// List lists the topic's subscriptions.
+func (t *pubsubTopic) List(ctx context.Context) ([]plugin.Entry, error) {
+	subs := make([]plugin.Entry, 0)
+	it := t.topic.Subscriptions(ctx)
+	for {
+		s, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, newPubsubSubscription(t.client, s))
+	}
+	return subs, nil
 }
 
-func (t *pubsubTopic) newPubsubTopicWatcher(ctx context.Context) (*pubsubTopicWatcher, error) {
+// Stream creates a temporary subscription to the topic and streams its
+// messages. The subscription's deleted when the stream's closed.
+func (t *pubsubTopic) Stream(ctx context.Context) (io.ReadCloser, error) {
 	sub, err := t.client.CreateSubscription(ctx, "wash-"+uuid.New().String(), pubsub.SubscriptionConfig{
 		Topic:            t.topic,
 		AckDeadline:      10 * time.Second,
@@ -92,54 +104,44 @@ func (t *pubsubTopic) newPubsubTopicWatcher(ctx context.Context) (*pubsubTopicWa
 	if err != nil {
 		return nil, err
 	}
-
-	// Use a buffer so we can Ack messages quickly.
-	queue := make(chan *pubsub.Message, 5)
-	errCh := make(chan error)
-	watcher := &pubsubTopicWatcher{ctx: ctx, sub: sub, queue: queue, err: errCh}
-
-	bufferMessages := func(_ context.Context, msg *pubsub.Message) {
-		msg.Ack()
-		queue <- msg
-	}
-	go func() {
-		errCh <- sub.Receive(ctx, bufferMessages)
-		close(errCh)
-		close(queue)
-	}()
-	return watcher, nil
+	return newPubsubStreamer(ctx, sub, true, func() error {
+		return sub.Delete(context.Background())
+	}), nil
 }
 
-func (w *pubsubTopicWatcher) Read(p []byte) (int, error) {
-	// Wait for an outstanding message, context completion, or error.
-	select {
-	case <-w.ctx.Done():
-		return 0, io.EOF
-	case msg := <-w.queue:
-		activity.Record(w.ctx, "Reading next message: %v", msg)
-
-		// TODO: don't truncate messages longer than the read buffer.
-		s := fmt.Sprintf("%v | %v", msg.PublishTime.Format(time.StampMilli), string(msg.Data))
-		return copy(p, []byte(s)), nil
-	case err := <-w.err:
-		return 0, err
+// Exec supports the publish command. See the topic's description for its
+// usage.
+func (t *pubsubTopic) Exec(ctx context.Context, cmd string, args []string, opts plugin.ExecOptions) (plugin.ExecCommand, error) {
+	if cmd != "publish" {
+		return nil, fmt.Errorf("unsupported command %v, only publish is supported", cmd)
+	}
+	data := strings.Join(args, " ")
+	if data == "-" && opts.Stdin != nil {
+		content, err := ioutil.ReadAll(opts.Stdin)
+		if err != nil {
+			return nil, err
+		}
+		data = string(content)
+	}
+	if data == "" {
+		return nil, fmt.Errorf("publish requires the message's data")
 	}
-}
-
-func (w *pubsubTopicWatcher) Close() error {
-	return w.sub.Delete(context.Background())
-}
-
-func (t *pubsubTopic) Stream(ctx context.Context) (io.ReadCloser, error) {
-	// Create subscription and wrap it in a ReadCloser to handle cleanup.
-	return t.newPubsubTopicWatcher(ctx)
-}
 
-func (t *pubsubTopic) Write(ctx context.Context, b []byte) error {
-	result := t.topic.Publish(ctx, &pubsub.Message{Data: b})
-	sid, err := result.Get(ctx)
-	activity.Record(ctx, "Message %v published with server ID %v: %v", string(b), sid, err)
-	return err
+	execCmd := plugin.NewExecCommand(ctx)
+	go func() {
+		result := t.topic.Publish(ctx, &pubsub.Message{Data: []byte(data)})
+		sid, err := result.Get(ctx)
+		activity.Record(ctx, "Message %v published with server ID %v: %v", data, sid, err)
+		if err != nil {
+			execCmd.CloseStreamsWithError(err)
+			execCmd.SetExitCodeErr(err)
+			return
+		}
+		_, err = execCmd.Stdout().Write([]byte(sid + "\n"))
+		execCmd.CloseStreamsWithError(err)
+		execCmd.SetExitCode(0)
+	}()
+	return execCmd, nil
 }
 
 func (t *pubsubTopic) Schema() *plugin.EntrySchema {
@@ -149,6 +151,21 @@ func (t *pubsubTopic) Schema() *plugin.EntrySchema {
 		SetDescription(pubsubTopicDescription)
 }
 
+func (t *pubsubTopic) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&pubsubSubscription{}).Schema(),
+	}
+}
+
 const pubsubTopicDescription = `
-A Cloud Pub/Sub topic. You can pipe text to it to publish messages.
+A Cloud Pub/Sub topic. Its entries consist of the topic's subscriptions.
+
+Streaming it (e.g. with 'tail -f') creates a temporary subscription and prints
+the messages that are published while it's streamed. Use a subscription to
+stream the messages that it's already holding.
+
+Exec supports the publish command, e.g.
+  wash exec pubsub/my-topic publish 'hello world'
+
+Use '-' as the message's data to read it from stdin.
 `
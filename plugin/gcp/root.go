@@ -49,6 +49,14 @@ func (r *Root) Init(cfg map[string]interface{}) error {
 		}
 	}
 
+	if ackI, ok := cfg["pubsub_ack_streamed_messages"]; ok {
+		ack, ok := ackI.(bool)
+		if !ok {
+			return fmt.Errorf("gcp.pubsub_ack_streamed_messages config must be a boolean, not %v", ackI)
+		}
+		pubsubAckStreamedMessages = ack
+	}
+
 	return err
 }
 
@@ -113,4 +121,11 @@ gcp:
   projects: [project-1, project-2]
 
 to Wash’s config file. Project can be referenced either by name or project ID.
+
+Streaming a Pub/Sub subscription doesn't acknowledge its messages. Add
+
+gcp:
+  pubsub_ack_streamed_messages: true
+
+to Wash’s config file to acknowledge them instead.
 `
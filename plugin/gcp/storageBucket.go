@@ -3,6 +3,7 @@ package gcp
 import (
 	"context"
 	"fmt"
+	"os"
 	"strings"
 	"time"
 
@@ -73,6 +74,12 @@ func (s *storageBucket) List(ctx context.Context) ([]plugin.Entry, error) {
 	return listBucket(ctx, bucket, "")
 }
 
+// Create returns a new object with the given name at the root of the bucket. The
+// object's uploaded when it's written to.
+func (s *storageBucket) Create(ctx context.Context, name string, _ os.FileMode) (plugin.Writable, error) {
+	return newEmptyStorageObject(name, s.Bucket(s.Name()), name), nil
+}
+
 func (s *storageBucket) Delete(ctx context.Context) (bool, error) {
 	// GCP only deletes empty buckets, so we'll need to delete all of its
 	// objects before deleting the bucket.
@@ -88,6 +95,7 @@ func (s *storageBucket) Schema() *plugin.EntrySchema {
 	return plugin.NewEntrySchema(s, "bucket").
 		SetPartialMetadataSchema(storage.BucketAttrs{}).
 		SetMetadataSchema(fullMeta{}).
+		SetListCost(plugin.ExpensiveList).
 		SetDescription(storageBucketDescription)
 }
 
@@ -164,4 +172,9 @@ For example, the objects 'foo/bar' and 'foo/baz' are represented as files with
 path 'foo/bar' and path 'foo/baz', where 'foo' is represented as a 'directory'.
 Thus, if you ls this bucket, then everything you'll see is either a Storage
 object prefix ('directory') or a Storage object ('file').
+
+Objects can be modified and new objects can be created, e.g. via
+'cp local.txt <bucket>/foo/bar.txt' in the Wash shell. The content's uploaded
+when the file's closed. Content that's larger than 16MB is uploaded in chunks
+via a resumable upload.
 `
@@ -1,39 +1,85 @@
 package gcp
 
 import (
+	"bytes"
 	"context"
+	"io"
 	"io/ioutil"
+	"time"
 
 	"cloud.google.com/go/storage"
 	"github.com/puppetlabs/wash/activity"
 	"github.com/puppetlabs/wash/plugin"
 )
 
+// gcsReadAheadSize is the minimum number of bytes that a Storage object read
+// fetches. FUSE reads a file in small blocks, so fetching each block with its
+// own ranged request would make sequential reads of large objects very slow.
+// Instead, the object prefetches a chunk and serves subsequent reads from it.
+// See plugin.RangePrefetcher.
+var gcsReadAheadSize int64 = 8 * 1024 * 1024
+
+// gcsResumableUploadThreshold is the size at which writes switch from a single
+// upload request to a resumable upload. It's also the size of each uploaded
+// chunk, so only one chunk is buffered at a time.
+var gcsResumableUploadThreshold int64 = 16 * 1024 * 1024
+
 type storageObject struct {
 	plugin.EntryBase
 	*storage.ObjectHandle
+	size       int64
+	prefetcher plugin.RangePrefetcher
 }
 
 func newStorageObject(name string, object *storage.ObjectHandle, attrs *storage.ObjectAttrs) *storageObject {
-	obj := &storageObject{EntryBase: plugin.NewEntry(name), ObjectHandle: object}
+	obj := &storageObject{EntryBase: plugin.NewEntry(name), ObjectHandle: object, size: attrs.Size}
+	obj.SetValidator(plugin.Validator{ETag: attrs.Etag, LastModified: attrs.Updated})
 	obj.SetPartialMetadata(attrs).
 		Attributes().
 		SetCrtime(attrs.Created).
 		SetCtime(attrs.Updated).
 		SetMtime(attrs.Updated).
-		SetSize(uint64(attrs.Size))
+		SetSize(uint64(attrs.Size)).
+		SetCustom("generation", attrs.Generation).
+		SetCustom("metageneration", attrs.Metageneration).
+		SetCustom("content_type", attrs.ContentType).
+		SetCustom("storage_class", attrs.StorageClass)
 	return obj
 }
 
+// newEmptyStorageObject returns a storageObject representing a new, empty
+// object. The object's only uploaded once it's written to.
+func newEmptyStorageObject(name string, bucket *storage.BucketHandle, key string) *storageObject {
+	now := time.Now()
+	return newStorageObject(name, bucket.Object(key), &storage.ObjectAttrs{
+		Name:    key,
+		Created: now,
+		Updated: now,
+	})
+}
+
 func (s *storageObject) Schema() *plugin.EntrySchema {
 	return plugin.
 		NewEntrySchema(s, "object").
 		SetDescription(storageObjectDescription).
-		SetPartialMetadataSchema(storage.ObjectAttrs{})
+		SetPartialMetadataSchema(storage.ObjectAttrs{}).
+		AddCustomAttribute("generation", plugin.NumberAttribute, "The generation of the object's content. It changes whenever the object's overwritten").
+		AddCustomAttribute("metageneration", plugin.NumberAttribute, "The version of the object's metadata within its current generation").
+		AddCustomAttribute("content_type", plugin.StringAttribute, "The object's MIME type").
+		AddCustomAttribute("storage_class", plugin.StringAttribute, "The object's storage class, e.g. STANDARD or NEARLINE")
 }
 
+// Read reads the requested range of the object's content. Reads are served
+// from the prefetched chunk when possible so that large objects can be read
+// sequentially without buffering their entire content.
 func (s *storageObject) Read(ctx context.Context, size int64, offset int64) ([]byte, error) {
-	rdr, err := s.NewRangeReader(context.Background(), offset, int64(size))
+	return s.prefetcher.Read(ctx, size, offset, s.size, gcsReadAheadSize, s.readRange)
+}
+
+// readRange fetches the given range of the object's content with a single
+// ranged request.
+func (s *storageObject) readRange(ctx context.Context, size int64, offset int64) ([]byte, error) {
+	rdr, err := s.NewRangeReader(ctx, offset, size)
 	if err != nil {
 		return nil, err
 	}
@@ -46,14 +92,33 @@ func (s *storageObject) Read(ctx context.Context, size int64, offset int64) ([]b
 }
 
 func (s *storageObject) Write(ctx context.Context, p []byte) error {
+	return s.WriteStream(ctx, bytes.NewReader(p), int64(len(p)))
+}
+
+// WriteStream uploads the object's new content. Content that's smaller than
+// gcsResumableUploadThreshold is uploaded with a single request. Larger content
+// is uploaded in gcsResumableUploadThreshold-sized chunks via a resumable
+// upload, so only one chunk is buffered at a time and a failed chunk is retried
+// without restarting the upload.
+func (s *storageObject) WriteStream(ctx context.Context, r io.Reader, size int64) error {
 	wr := s.ObjectHandle.NewWriter(ctx)
-	if _, err := wr.Write(p); err != nil {
+	if size < gcsResumableUploadThreshold {
+		wr.ChunkSize = 0
+	} else {
+		wr.ChunkSize = int(gcsResumableUploadThreshold)
+	}
+	if _, err := io.Copy(wr, r); err != nil {
 		wr.Close()
 		return err
 	}
-
 	// When Close fails we can assume the object update failed.
-	return wr.Close()
+	if err := wr.Close(); err != nil {
+		return err
+	}
+
+	activity.Record(ctx, "GCP storage object upload finished, new generation is %v", wr.Attrs().Generation)
+	s.prefetcher.Reset()
+	return nil
 }
 
 func (s *storageObject) Delete(ctx context.Context) (bool, error) {
@@ -64,4 +129,12 @@ func (s *storageObject) Delete(ctx context.Context) (bool, error) {
 const storageObjectDescription = `
 This is a Storage object. See the bucket's docs for more details
 on why we have this kind of entry.
+
+Reads fetch the object's content in ranged chunks, so large objects
+can be read without downloading them in full. Large writes are
+uploaded in chunks via a resumable upload. The object's generation,
+metageneration, content type and storage class are available as
+custom attributes, which are also exposed as user.wash.* extended
+attributes of the mounted file, e.g.
+  getfattr -n user.wash.generation <object>
 `
@@ -2,6 +2,7 @@ package gcp
 
 import (
 	"context"
+	"os"
 
 	"cloud.google.com/go/storage"
 	"github.com/puppetlabs/wash/plugin"
@@ -38,6 +39,12 @@ func (s *storageObjectPrefix) List(ctx context.Context) ([]plugin.Entry, error)
 	return listBucket(ctx, s.bucket, s.prefix)
 }
 
+// Create returns a new object with the given name under this prefix. The
+// object's uploaded when it's written to.
+func (s *storageObjectPrefix) Create(ctx context.Context, name string, _ os.FileMode) (plugin.Writable, error) {
+	return newEmptyStorageObject(name, s.bucket, s.prefix+name), nil
+}
+
 func (s *storageObjectPrefix) Delete(ctx context.Context) (bool, error) {
 	err := deleteObjects(ctx, s.bucket, s.prefix)
 	return true, err
@@ -0,0 +1,105 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	gh "github.com/google/go-github/v32/github"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// artifactsDir represents a run's artifacts directory
+type artifactsDir struct {
+	plugin.EntryBase
+	run *run
+}
+
+func newArtifactsDir(r *run) *artifactsDir {
+	d := &artifactsDir{
+		EntryBase: plugin.NewEntry("artifacts"),
+	}
+	d.run = r
+	return d
+}
+
+func (d *artifactsDir) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(d, "artifacts").IsSingleton()
+}
+
+func (d *artifactsDir) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&artifact{}).Schema(),
+	}
+}
+
+func (d *artifactsDir) List(ctx context.Context) ([]plugin.Entry, error) {
+	repo := d.run.repo
+	var artifacts []plugin.Entry
+	opts := &gh.ListOptions{PerPage: 100}
+	for {
+		page, resp, err := repo.client.Actions.ListWorkflowRunArtifacts(ctx, repo.owner, repo.Name(), d.run.id, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range page.Artifacts {
+			artifacts = append(artifacts, newArtifact(d.run, a))
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	activity.Record(ctx, "Listing %v artifacts of run %v", len(artifacts), d.run.id)
+	return artifacts, nil
+}
+
+// artifact is a run's artifact. It's read as a zip file.
+type artifact struct {
+	plugin.EntryBase
+	run     *run
+	id      int64
+	expired bool
+}
+
+func newArtifact(r *run, a *gh.Artifact) *artifact {
+	art := &artifact{
+		EntryBase: plugin.NewEntry(a.GetName() + ".zip"),
+	}
+	art.run = r
+	art.id = a.GetID()
+	art.expired = a.GetExpired()
+	art.
+		SetPartialMetadata(a).
+		Attributes().
+		SetCrtime(a.GetCreatedAt().Time).
+		SetSize(uint64(a.GetSizeInBytes())).
+		SetCustom("expired", a.GetExpired())
+	return art
+}
+
+func (a *artifact) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(a, "artifact").
+		SetDescription(artifactDescription).
+		SetPartialMetadataSchema(gh.Artifact{}).
+		AddCustomAttribute("expired", plugin.BooleanAttribute, "Whether the artifact has expired and can't be downloaded")
+}
+
+func (a *artifact) Read(ctx context.Context) ([]byte, error) {
+	if a.expired {
+		return nil, fmt.Errorf("artifact %v has expired", a.Name())
+	}
+	repo := a.run.repo
+	u, _, err := repo.client.Actions.DownloadArtifact(ctx, repo.owner, repo.Name(), a.id, true)
+	if err != nil {
+		return nil, err
+	}
+	return download(ctx, u)
+}
+
+const artifactDescription = `
+This is an artifact of a GitHub Actions run. Reading it downloads the artifact's
+zip archive, e.g.
+  cp github/puppetlabs/wash/runs/123456789/artifacts/dist.zip .
+`
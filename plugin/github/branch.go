@@ -0,0 +1,94 @@
+package github
+
+import (
+	"context"
+
+	gh "github.com/google/go-github/v32/github"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// branchesDir represents a repository's branches directory
+type branchesDir struct {
+	plugin.EntryBase
+	repo *repo
+}
+
+func newBranchesDir(r *repo) *branchesDir {
+	d := &branchesDir{
+		EntryBase: plugin.NewEntry("branches"),
+	}
+	d.repo = r
+	return d
+}
+
+func (d *branchesDir) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(d, "branches").IsSingleton()
+}
+
+func (d *branchesDir) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&branch{}).Schema(),
+	}
+}
+
+func (d *branchesDir) List(ctx context.Context) ([]plugin.Entry, error) {
+	var branches []plugin.Entry
+	opts := &gh.BranchListOptions{ListOptions: gh.ListOptions{PerPage: 100}}
+	for {
+		page, resp, err := d.repo.client.Repositories.ListBranches(ctx, d.repo.owner, d.repo.Name(), opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range page {
+			branches = append(branches, newBranch(d.repo, b))
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	activity.Record(ctx, "Listing %v branches of %v/%v", len(branches), d.repo.owner, d.repo.Name())
+	return branches, nil
+}
+
+// branch is a branch's tree of files. Branch names that contain a '/' have it
+// replaced by a '#' in their path.
+type branch struct {
+	plugin.EntryBase
+	repo *repo
+}
+
+func newBranch(r *repo, b *gh.Branch) *branch {
+	br := &branch{
+		EntryBase: plugin.NewEntry(b.GetName()),
+	}
+	br.repo = r
+	br.
+		SetSlashReplacer('#').
+		SetPartialMetadata(b).
+		Attributes().
+		SetCustom("protected", b.GetProtected())
+	return br
+}
+
+func (b *branch) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(b, "branch").
+		SetDescription(branchDescription).
+		SetPartialMetadataSchema(gh.Branch{}).
+		AddCustomAttribute("protected", plugin.BooleanAttribute, "Whether the branch is protected")
+}
+
+func (b *branch) ChildSchemas() []*plugin.EntrySchema {
+	return contentSchemas()
+}
+
+func (b *branch) List(ctx context.Context) ([]plugin.Entry, error) {
+	return listContents(ctx, b.repo, b.Name(), "")
+}
+
+const branchDescription = `
+This is a branch of a GitHub repository. It contains the files at the branch's
+head, which are fetched with the contents API.
+`
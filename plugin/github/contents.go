@@ -0,0 +1,124 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+
+	gh "github.com/google/go-github/v32/github"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+func contentSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&contentDir{}).Schema(),
+		(&contentFile{}).Schema(),
+	}
+}
+
+// listContents lists the files and directories in the directory at path, as of
+// ref. Submodules are skipped, since their contents are in other repositories.
+func listContents(ctx context.Context, r *repo, ref string, path string) ([]plugin.Entry, error) {
+	opts := &gh.RepositoryContentGetOptions{Ref: ref}
+	_, contents, _, err := r.client.Repositories.GetContents(ctx, r.owner, r.Name(), path, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]plugin.Entry, 0, len(contents))
+	for _, content := range contents {
+		switch content.GetType() {
+		case "dir":
+			entries = append(entries, newContentDir(r, ref, content))
+		case "file", "symlink":
+			entries = append(entries, newContentFile(r, ref, content))
+		default:
+			activity.Record(ctx, "Skipping %v %v", content.GetType(), content.GetPath())
+		}
+	}
+	return entries, nil
+}
+
+type contentDir struct {
+	plugin.EntryBase
+	repo *repo
+	ref  string
+	path string
+}
+
+func newContentDir(r *repo, ref string, content *gh.RepositoryContent) *contentDir {
+	d := &contentDir{
+		EntryBase: plugin.NewEntry(content.GetName()),
+	}
+	d.repo = r
+	d.ref = ref
+	d.path = content.GetPath()
+	d.SetPartialMetadata(content)
+	return d
+}
+
+func (d *contentDir) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(d, "dir").
+		SetPartialMetadataSchema(gh.RepositoryContent{})
+}
+
+func (d *contentDir) ChildSchemas() []*plugin.EntrySchema {
+	return contentSchemas()
+}
+
+func (d *contentDir) List(ctx context.Context) ([]plugin.Entry, error) {
+	return listContents(ctx, d.repo, d.ref, d.path)
+}
+
+type contentFile struct {
+	plugin.EntryBase
+	repo *repo
+	ref  string
+	path string
+}
+
+func newContentFile(r *repo, ref string, content *gh.RepositoryContent) *contentFile {
+	f := &contentFile{
+		EntryBase: plugin.NewEntry(content.GetName()),
+	}
+	f.repo = r
+	f.ref = ref
+	f.path = content.GetPath()
+	f.
+		SetPartialMetadata(content).
+		Attributes().
+		SetSize(uint64(content.GetSize()))
+	return f
+}
+
+func (f *contentFile) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(f, "file").
+		SetPartialMetadataSchema(gh.RepositoryContent{})
+}
+
+// Read gets the file's raw content. Unlike the contents API's base64-encoded
+// content, raw content isn't limited to 1 MB.
+func (f *contentFile) Read(ctx context.Context) ([]byte, error) {
+	u := fmt.Sprintf(
+		"repos/%v/%v/contents/%v?ref=%v",
+		f.repo.owner,
+		f.repo.Name(),
+		(&url.URL{Path: f.path}).EscapedPath(),
+		url.QueryEscape(f.ref),
+	)
+	req, err := f.repo.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github.v3.raw")
+
+	var buf bytes.Buffer
+	if _, err := f.repo.client.Do(ctx, req, &buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,37 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	gh "github.com/google/go-github/v32/github"
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContentFileRead(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v3/repos/octo/hello/contents/docs/read me.md", r.URL.Path)
+		assert.Equal(t, "feature/x", r.URL.Query().Get("ref"))
+		assert.Equal(t, "application/vnd.github.v3.raw", r.Header.Get("Accept"))
+		_, _ = w.Write([]byte("# Hello\n"))
+	}))
+	defer server.Close()
+
+	client, err := gh.NewEnterpriseClient(server.URL, server.URL, nil)
+	require.NoError(t, err)
+	r := newRepo(client, "octo", &gh.Repository{Name: gh.String("hello")})
+	f := newContentFile(r, "feature/x", &gh.RepositoryContent{
+		Name: gh.String("read me.md"),
+		Path: gh.String("docs/read me.md"),
+		Size: gh.Int(8),
+	})
+
+	content, err := f.Read(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "# Hello\n", string(content))
+	assert.Equal(t, "read me.md", plugin.Name(f))
+}
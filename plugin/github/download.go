@@ -0,0 +1,29 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// download gets the content at u. Logs and artifacts are downloaded from
+// short-lived URLs that GitHub redirects to, which don't need authentication.
+func download(ctx context.Context, u *url.URL) ([]byte, error) {
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := plugin.HTTPClient().Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("downloading %v failed: %v", u.Host+u.Path, resp.Status)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
@@ -0,0 +1,178 @@
+package github
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	gh "github.com/google/go-github/v32/github"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// jobsDir represents a run's jobs directory
+type jobsDir struct {
+	plugin.EntryBase
+	run *run
+}
+
+func newJobsDir(r *run) *jobsDir {
+	d := &jobsDir{
+		EntryBase: plugin.NewEntry("jobs"),
+	}
+	d.run = r
+	return d
+}
+
+func (d *jobsDir) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(d, "jobs").IsSingleton()
+}
+
+func (d *jobsDir) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&jobLog{}).Schema(),
+	}
+}
+
+// List lists the run's jobs. Jobs that share a name, which can happen with
+// matrix builds, are suffixed with their ID.
+func (d *jobsDir) List(ctx context.Context) ([]plugin.Entry, error) {
+	repo := d.run.repo
+	var jobs []*gh.WorkflowJob
+	opts := &gh.ListWorkflowJobsOptions{ListOptions: gh.ListOptions{PerPage: 100}}
+	for {
+		page, resp, err := repo.client.Actions.ListWorkflowJobs(ctx, repo.owner, repo.Name(), d.run.id, opts)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, page.Jobs...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	counts := make(map[string]int)
+	for _, job := range jobs {
+		counts[job.GetName()]++
+	}
+	entries := make([]plugin.Entry, len(jobs))
+	for i, job := range jobs {
+		name := job.GetName()
+		if counts[name] > 1 {
+			name += "-" + strconv.FormatInt(job.GetID(), 10)
+		}
+		entries[i] = newJobLog(d.run, name+".log", job)
+	}
+	activity.Record(ctx, "Listing %v jobs of run %v", len(entries), d.run.id)
+	return entries, nil
+}
+
+// jobLog is a job's log.
+type jobLog struct {
+	plugin.EntryBase
+	run *run
+	id  int64
+}
+
+// logPollInterval is how often a running job's log is fetched while it's
+// streamed.
+var logPollInterval = 5 * time.Second
+
+func newJobLog(r *run, name string, job *gh.WorkflowJob) *jobLog {
+	l := &jobLog{
+		EntryBase: plugin.NewEntry(name),
+	}
+	l.run = r
+	l.id = job.GetID()
+	attr := l.
+		SetPartialMetadata(job).
+		Attributes().
+		SetCrtime(job.GetStartedAt().Time).
+		SetCustom("status", job.GetStatus()).
+		SetCustom("conclusion", job.GetConclusion())
+	if job.CompletedAt != nil {
+		attr.SetMtime(job.GetCompletedAt().Time)
+	} else {
+		// The log of a running job changes, so it's re-read every time.
+		l.DisableCachingFor(plugin.ReadOp)
+	}
+	return l
+}
+
+func (l *jobLog) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(l, "job").
+		SetDescription(jobLogDescription).
+		SetPartialMetadataSchema(gh.WorkflowJob{}).
+		AddCustomAttribute("status", plugin.StringAttribute, "The job's status, e.g. queued, in_progress or completed").
+		AddCustomAttribute("conclusion", plugin.StringAttribute, "The completed job's conclusion, e.g. success or failure")
+}
+
+// Read returns the job's log. A job that hasn't started yet has an empty log.
+func (l *jobLog) Read(ctx context.Context) ([]byte, error) {
+	repo := l.run.repo
+	u, resp, err := repo.client.Actions.GetWorkflowJobLogs(ctx, repo.owner, repo.Name(), l.id, true)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return []byte{}, nil
+		}
+		return nil, err
+	}
+	return download(ctx, u)
+}
+
+// Stream follows the job's log until the job completes.
+func (l *jobLog) Stream(ctx context.Context) (io.ReadCloser, error) {
+	repo := l.run.repo
+	ctx, cancel := context.WithCancel(ctx)
+	r, w := io.Pipe()
+	go func() {
+		defer cancel()
+		err := follow(ctx, w, logPollInterval, func() ([]byte, bool, error) {
+			job, _, err := repo.client.Actions.GetWorkflowJobByID(ctx, repo.owner, repo.Name(), l.id)
+			if err != nil {
+				return nil, false, err
+			}
+			log, err := l.Read(ctx)
+			return log, job.GetStatus() == "completed", err
+		})
+		w.CloseWithError(err)
+	}()
+	return plugin.CleanupReader{ReadCloser: r, Cleanup: cancel}, nil
+}
+
+// follow polls fetch every interval and writes the part of the log that it
+// hasn't written yet. It stops once fetch says that the log is done, or ctx is
+// done.
+func follow(ctx context.Context, w io.Writer, interval time.Duration, fetch func() ([]byte, bool, error)) error {
+	written := 0
+	for {
+		log, done, err := fetch()
+		if err != nil {
+			return err
+		}
+		if len(log) > written {
+			if _, err := w.Write(log[written:]); err != nil {
+				return err
+			}
+			written = len(log)
+		}
+		if done {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+const jobLogDescription = `
+This is the log of a GitHub Actions job. Streaming it follows the log until the
+job completes.
+`
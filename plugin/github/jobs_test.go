@@ -0,0 +1,41 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFollow(t *testing.T) {
+	logs := []string{"", "step 1\n", "step 1\n", "step 1\nstep 2\n", "step 1\nstep 2\ndone\n"}
+	calls := 0
+	var out strings.Builder
+	err := follow(context.Background(), &out, time.Millisecond, func() ([]byte, bool, error) {
+		log := logs[calls]
+		calls++
+		return []byte(log), calls == len(logs), nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, len(logs), calls)
+	assert.Equal(t, "step 1\nstep 2\ndone\n", out.String())
+}
+
+func TestFollowErrors(t *testing.T) {
+	var out strings.Builder
+	err := follow(context.Background(), &out, time.Millisecond, func() ([]byte, bool, error) {
+		return nil, false, errors.New("not found")
+	})
+	assert.EqualError(t, err, "not found")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err = follow(ctx, &out, time.Hour, func() ([]byte, bool, error) {
+		cancel()
+		return []byte("running\n"), false, nil
+	})
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, "running\n", out.String())
+}
@@ -0,0 +1,102 @@
+package github
+
+import (
+	"context"
+
+	gh "github.com/google/go-github/v32/github"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+type ownerKind int
+
+const (
+	// selfOwner is the authenticated user, whose private repositories can be
+	// listed.
+	selfOwner ownerKind = iota
+	userOwner
+	orgOwner
+)
+
+// owner is a user or an organization. It lists the repositories that it owns.
+type owner struct {
+	plugin.EntryBase
+	client *gh.Client
+	kind   ownerKind
+}
+
+func newOwner(client *gh.Client, user *gh.User, kind ownerKind) *owner {
+	o := &owner{
+		EntryBase: plugin.NewEntry(user.GetLogin()),
+	}
+	o.client = client
+	o.kind = kind
+	o.
+		SetPartialMetadata(user).
+		Attributes().
+		SetCrtime(user.GetCreatedAt().Time)
+	return o
+}
+
+func newOrgOwner(client *gh.Client, org *gh.Organization) *owner {
+	o := &owner{
+		EntryBase: plugin.NewEntry(org.GetLogin()),
+	}
+	o.client = client
+	o.kind = orgOwner
+	o.SetPartialMetadata(org)
+	return o
+}
+
+func (o *owner) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(o, "owner").
+		SetDescription(ownerDescription)
+}
+
+func (o *owner) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&repo{}).Schema(),
+	}
+}
+
+func (o *owner) List(ctx context.Context) ([]plugin.Entry, error) {
+	var repos []*gh.Repository
+	listOpts := gh.ListOptions{PerPage: 100}
+	for {
+		var page []*gh.Repository
+		var resp *gh.Response
+		var err error
+		switch o.kind {
+		case selfOwner:
+			opts := &gh.RepositoryListOptions{Affiliation: "owner", ListOptions: listOpts}
+			page, resp, err = o.client.Repositories.List(ctx, "", opts)
+		case orgOwner:
+			opts := &gh.RepositoryListByOrgOptions{ListOptions: listOpts}
+			page, resp, err = o.client.Repositories.ListByOrg(ctx, o.Name(), opts)
+		default:
+			opts := &gh.RepositoryListOptions{ListOptions: listOpts}
+			page, resp, err = o.client.Repositories.List(ctx, o.Name(), opts)
+		}
+		if err != nil {
+			return nil, err
+		}
+		repos = append(repos, page...)
+		if resp.NextPage == 0 {
+			break
+		}
+		listOpts.Page = resp.NextPage
+	}
+
+	entries := make([]plugin.Entry, len(repos))
+	for i, r := range repos {
+		entries[i] = newRepo(o.client, o.Name(), r)
+	}
+	activity.Record(ctx, "Listing %v repositories of %v", len(entries), o.Name())
+	return entries, nil
+}
+
+const ownerDescription = `
+This is a GitHub user or organization. It contains the repositories that it
+owns.
+`
@@ -0,0 +1,67 @@
+package github
+
+import (
+	"context"
+
+	gh "github.com/google/go-github/v32/github"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+type repo struct {
+	plugin.EntryBase
+	client *gh.Client
+	owner  string
+}
+
+func newRepo(client *gh.Client, owner string, r *gh.Repository) *repo {
+	rp := &repo{
+		EntryBase: plugin.NewEntry(r.GetName()),
+	}
+	rp.client = client
+	rp.owner = owner
+	rp.
+		SetPartialMetadata(r).
+		Attributes().
+		SetCrtime(r.GetCreatedAt().Time).
+		SetMtime(r.GetPushedAt().Time).
+		SetCustom("private", r.GetPrivate())
+	return rp
+}
+
+func (r *repo) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(r, "repo").
+		SetDescription(repoDescription).
+		SetPartialMetadataSchema(gh.Repository{}).
+		SetMetadataSchema(gh.Repository{}).
+		AddCustomAttribute("private", plugin.BooleanAttribute, "Whether the repository is private")
+}
+
+func (r *repo) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&branchesDir{}).Schema(),
+		(&runsDir{}).Schema(),
+	}
+}
+
+func (r *repo) List(ctx context.Context) ([]plugin.Entry, error) {
+	return []plugin.Entry{
+		newBranchesDir(r),
+		newRunsDir(r),
+	}, nil
+}
+
+// Metadata returns the repository's full details, which include its license,
+// parent and permissions.
+func (r *repo) Metadata(ctx context.Context) (plugin.JSONObject, error) {
+	rp, _, err := r.client.Repositories.Get(ctx, r.owner, r.Name())
+	if err != nil {
+		return nil, err
+	}
+	return plugin.ToJSONObject(rp), nil
+}
+
+const repoDescription = `
+This is a GitHub repository. It contains the repository's branches, whose files
+are browsed with the contents API, and its recent Actions workflow runs.
+`
@@ -0,0 +1,152 @@
+// Package github presents a filesystem hierarchy for GitHub repositories: their
+// branches' files and their Actions workflow runs, including job logs and run
+// artifacts.
+//
+// It uses the GITHUB_TOKEN environment variable to access GitHub.
+package github
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	gh "github.com/google/go-github/v32/github"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+	"golang.org/x/oauth2"
+)
+
+// Root of the GitHub plugin
+type Root struct {
+	plugin.EntryBase
+	client        *gh.Client
+	authenticated bool
+	owners        []string
+}
+
+// Init for root
+func (r *Root) Init(cfg map[string]interface{}) error {
+	token := os.Getenv("GITHUB_TOKEN")
+	if tokenI, ok := cfg["token"]; ok {
+		t, ok := tokenI.(string)
+		if !ok {
+			return fmt.Errorf("github.token config must be a string, not %v", tokenI)
+		}
+		token = t
+	}
+	if ownersI, ok := cfg["owners"]; ok {
+		owners, ok := ownersI.([]interface{})
+		if !ok {
+			return fmt.Errorf("github.owners config must be an array of strings, not %v", ownersI)
+		}
+		for _, ownerI := range owners {
+			owner, ok := ownerI.(string)
+			if !ok || owner == "" {
+				return fmt.Errorf("github.owners config must be an array of strings, not %v", ownersI)
+			}
+			r.owners = append(r.owners, owner)
+		}
+	}
+
+	httpClient := plugin.HTTPClient()
+	if token != "" {
+		// The oauth2 client wraps the shared client's transport.
+		ctx := context.WithValue(context.Background(), oauth2.HTTPClient, httpClient)
+		httpClient = oauth2.NewClient(ctx, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}))
+		r.authenticated = true
+	}
+	if baseURLI, ok := cfg["base_url"]; ok {
+		baseURL, ok := baseURLI.(string)
+		if !ok {
+			return fmt.Errorf("github.base_url config must be a string, not %v", baseURLI)
+		}
+		client, err := gh.NewEnterpriseClient(baseURL, baseURL, httpClient)
+		if err != nil {
+			return fmt.Errorf("github.base_url config is invalid: %v", err)
+		}
+		r.client = client
+	} else {
+		r.client = gh.NewClient(httpClient)
+	}
+
+	r.EntryBase = plugin.NewEntry("github")
+	return nil
+}
+
+// Schema returns the root's schema
+func (r *Root) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(r, "github").
+		SetDescription(rootDescription).
+		IsSingleton()
+}
+
+// ChildSchemas returns the root's child schema
+func (r *Root) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&owner{}).Schema(),
+	}
+}
+
+// List lists the authenticated user and their organizations, followed by the
+// users and organizations in the github.owners config.
+func (r *Root) List(ctx context.Context) ([]plugin.Entry, error) {
+	var owners []plugin.Entry
+	seen := make(map[string]struct{})
+	if r.authenticated {
+		user, _, err := r.client.Users.Get(ctx, "")
+		if err != nil {
+			return nil, err
+		}
+		owners = append(owners, newOwner(r.client, user, selfOwner))
+		seen[user.GetLogin()] = struct{}{}
+
+		opts := &gh.ListOptions{PerPage: 100}
+		for {
+			orgs, resp, err := r.client.Organizations.List(ctx, "", opts)
+			if err != nil {
+				return nil, err
+			}
+			for _, org := range orgs {
+				owners = append(owners, newOrgOwner(r.client, org))
+				seen[org.GetLogin()] = struct{}{}
+			}
+			if resp.NextPage == 0 {
+				break
+			}
+			opts.Page = resp.NextPage
+		}
+	}
+
+	for _, name := range r.owners {
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		user, _, err := r.client.Users.Get(ctx, name)
+		if err != nil {
+			activity.Warnf(ctx, "github: could not find owner %v: %v", name, err)
+			continue
+		}
+		kind := userOwner
+		if user.GetType() == "Organization" {
+			kind = orgOwner
+		}
+		owners = append(owners, newOwner(r.client, user, kind))
+		seen[name] = struct{}{}
+	}
+	return owners, nil
+}
+
+const rootDescription = `
+This is the GitHub plugin root. It contains the authenticated user and their
+organizations, and the users and organizations in the github.owners config.
+
+GitHub is accessed with the token in the GITHUB_TOKEN environment variable, or
+the github.token config. Without a token, only the public repositories of the
+configured owners are listed. GitHub Enterprise is used by setting the
+github.base_url config, e.g.
+
+github:
+  owners: [puppetlabs]
+  base_url: https://github.example.com/api/v3/
+`
@@ -0,0 +1,118 @@
+package github
+
+import (
+	"context"
+	"strconv"
+
+	gh "github.com/google/go-github/v32/github"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// runsDir represents a repository's runs directory. It lists the repository's
+// most recent Actions workflow runs.
+type runsDir struct {
+	plugin.EntryBase
+	repo *repo
+}
+
+// maxRuns is the number of runs that are listed. Busy repositories have
+// thousands of runs, which would take many requests to list.
+const maxRuns = 100
+
+func newRunsDir(r *repo) *runsDir {
+	d := &runsDir{
+		EntryBase: plugin.NewEntry("runs"),
+	}
+	d.repo = r
+	return d
+}
+
+func (d *runsDir) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(d, "runs").IsSingleton()
+}
+
+func (d *runsDir) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&run{}).Schema(),
+	}
+}
+
+func (d *runsDir) List(ctx context.Context) ([]plugin.Entry, error) {
+	opts := &gh.ListWorkflowRunsOptions{ListOptions: gh.ListOptions{PerPage: maxRuns}}
+	runs, _, err := d.repo.client.Actions.ListRepositoryWorkflowRuns(ctx, d.repo.owner, d.repo.Name(), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]plugin.Entry, len(runs.WorkflowRuns))
+	for i, r := range runs.WorkflowRuns {
+		entries[i] = newRun(d.repo, r)
+	}
+	activity.Record(ctx, "Listing %v of the %v runs of %v/%v", len(entries), runs.GetTotalCount(), d.repo.owner, d.repo.Name())
+	return entries, nil
+}
+
+// run is an Actions workflow run. It's named by its ID.
+type run struct {
+	plugin.EntryBase
+	repo *repo
+	id   int64
+}
+
+func newRun(r *repo, wr *gh.WorkflowRun) *run {
+	rn := &run{
+		EntryBase: plugin.NewEntry(strconv.FormatInt(wr.GetID(), 10)),
+	}
+	rn.repo = r
+	rn.id = wr.GetID()
+	rn.
+		SetPartialMetadata(wr).
+		Attributes().
+		SetCrtime(wr.GetCreatedAt().Time).
+		SetMtime(wr.GetUpdatedAt().Time).
+		SetCustom("status", wr.GetStatus()).
+		SetCustom("conclusion", wr.GetConclusion()).
+		SetCustom("branch", wr.GetHeadBranch())
+	return rn
+}
+
+func (r *run) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(r, "run").
+		SetDescription(runDescription).
+		SetPartialMetadataSchema(gh.WorkflowRun{}).
+		SetMetadataSchema(gh.WorkflowRun{}).
+		AddCustomAttribute("status", plugin.StringAttribute, "The run's status, e.g. queued, in_progress or completed").
+		AddCustomAttribute("conclusion", plugin.StringAttribute, "The completed run's conclusion, e.g. success or failure").
+		AddCustomAttribute("branch", plugin.StringAttribute, "The branch that the run's for")
+}
+
+func (r *run) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&jobsDir{}).Schema(),
+		(&artifactsDir{}).Schema(),
+	}
+}
+
+func (r *run) List(ctx context.Context) ([]plugin.Entry, error) {
+	return []plugin.Entry{
+		newJobsDir(r),
+		newArtifactsDir(r),
+	}, nil
+}
+
+// Metadata gets the run's current status.
+func (r *run) Metadata(ctx context.Context) (plugin.JSONObject, error) {
+	wr, _, err := r.repo.client.Actions.GetWorkflowRunByID(ctx, r.repo.owner, r.repo.Name(), r.id)
+	if err != nil {
+		return nil, err
+	}
+	return plugin.ToJSONObject(wr), nil
+}
+
+const runDescription = `
+This is a GitHub Actions workflow run, named by its ID. It contains the logs of
+the run's jobs, which can be streamed while the jobs run, and the run's
+artifacts, which are downloaded as zip files.
+`
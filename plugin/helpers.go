@@ -1,8 +1,13 @@
 package plugin
 
 import (
+	"context"
+	"fmt"
+	"strings"
 	"time"
 
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/metrics"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -12,3 +17,72 @@ func TrackTime(start time.Time, name string) {
 	elapsed := time.Since(start)
 	log.Infof("%s took %s", name, elapsed)
 }
+
+// recordCall records a call to the entry's method for the daemon's stats. The
+// returned function must be called with the call's error once it returns. See
+// metrics.Start.
+func recordCall(e Entry, method string) func(error) {
+	plugin := pluginName(e)
+	if plugin == "" {
+		// e is an apifs entry or the registry, neither of which belong to a plugin
+		return func(error) {}
+	}
+	return metrics.Start(plugin, method, e.eb().id)
+}
+
+// trackUntilDone records the entry's method as an active operation until ctx
+// is done. It's used for long-running operations like streams. See
+// metrics.TrackUntilDone.
+func trackUntilDone(ctx context.Context, e Entry, method string) {
+	if plugin := pluginName(e); plugin != "" {
+		metrics.TrackUntilDone(ctx, plugin, method, e.eb().id)
+	}
+}
+
+// withPluginContext returns a context that records activity on behalf of the
+// entry's plugin so that the plugin's journal level applies. See
+// activity.SetPluginLevel.
+func withPluginContext(ctx context.Context, e Entry) context.Context {
+	if _, ok := ctx.Value(activity.PluginKey).(string); ok {
+		return ctx
+	}
+	id := e.eb().id
+	if id == "" {
+		// The entry's ID is set from its parent's ID
+		if obj := ctx.Value(parentID); obj != nil {
+			id = obj.(string)
+		}
+	}
+	segments := strings.SplitN(strings.TrimLeft(id, "/"), "/", 2)
+	if segments[0] == "" {
+		return ctx
+	}
+	return activity.WithPlugin(ctx, segments[0])
+}
+
+// recoverPanic recovers from a panic in the plugin's implementation of op on e,
+// setting err so that the panic only fails the current operation. It must be
+// deferred.
+func recoverPanic(ctx context.Context, e Entry, op string, err *error) {
+	if r := recover(); r != nil {
+		*err = activity.RecordPanic(ctx, fmt.Sprintf("%v on %v", op, e.eb().id), r)
+	}
+}
+
+// ToStringMap converts a YAML object from a plugin's config to a map. Objects
+// nested in arrays aren't converted by the config loader, so their keys may not
+// be strings. It returns false if obj isn't an object.
+func ToStringMap(obj interface{}) (map[string]interface{}, bool) {
+	switch m := obj.(type) {
+	case map[string]interface{}:
+		return m, true
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(m))
+		for k, v := range m {
+			converted[fmt.Sprint(k)] = v
+		}
+		return converted, true
+	default:
+		return nil, false
+	}
+}
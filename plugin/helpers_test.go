@@ -0,0 +1,22 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToStringMap(t *testing.T) {
+	m, ok := ToStringMap(map[string]interface{}{"name": "foo"})
+	assert.True(t, ok)
+	assert.Equal(t, map[string]interface{}{"name": "foo"}, m)
+
+	m, ok = ToStringMap(map[interface{}]interface{}{"name": "foo", 1: true})
+	assert.True(t, ok)
+	assert.Equal(t, map[string]interface{}{"name": "foo", "1": true}, m)
+
+	_, ok = ToStringMap([]interface{}{"foo"})
+	assert.False(t, ok)
+	_, ok = ToStringMap(nil)
+	assert.False(t, ok)
+}
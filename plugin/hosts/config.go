@@ -0,0 +1,79 @@
+package hosts
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/kevinburke/ssh_config"
+	"github.com/puppetlabs/wash/transport"
+)
+
+// sshConfigHosts returns the hosts that are named by the config's Host
+// directives. Patterns with wildcards and negated patterns don't name a host,
+// so they're skipped.
+func sshConfigHosts(r io.Reader) ([]string, error) {
+	cfg, err := ssh_config.Decode(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	seen := make(map[string]struct{})
+	for _, host := range cfg.Hosts {
+		for _, pattern := range host.Patterns {
+			name := pattern.String()
+			if strings.ContainsAny(name, "*?") {
+				continue
+			}
+			// The pattern's string doesn't include the '!' of a negated pattern,
+			// but a negated pattern doesn't match its own name.
+			if !host.Matches(name) {
+				continue
+			}
+			if _, ok := seen[name]; ok {
+				continue
+			}
+			seen[name] = struct{}{}
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// parseInventory parses an inventory, which lists one host per line as
+// [user@]host[:port]. Blank lines and lines that start with '#' are ignored.
+func parseInventory(r io.Reader) ([]transport.Identity, error) {
+	var ids []transport.Identity
+	scanner := bufio.NewScanner(r)
+	for lineno := 1; scanner.Scan(); lineno++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		invalid := fmt.Errorf("line %v: %v must be [user@]host[:port]", lineno, line)
+		var id transport.Identity
+		if i := strings.LastIndex(line, "@"); i >= 0 {
+			if i == 0 {
+				return nil, invalid
+			}
+			id.User, line = line[:i], line[i+1:]
+		}
+		if i := strings.LastIndex(line, ":"); i >= 0 {
+			port, err := strconv.ParseUint(line[i+1:], 10, 16)
+			if err != nil {
+				return nil, fmt.Errorf("line %v: invalid port %v", lineno, line[i+1:])
+			}
+			id.Port, line = uint(port), line[:i]
+		}
+		if line == "" {
+			return nil, invalid
+		}
+		id.Host = line
+		ids = append(ids, id)
+	}
+	return ids, scanner.Err()
+}
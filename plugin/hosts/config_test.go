@@ -0,0 +1,50 @@
+package hosts
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/puppetlabs/wash/transport"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSSHConfigHosts(t *testing.T) {
+	names, err := sshConfigHosts(strings.NewReader(`
+Host web01 web02
+  User deploy
+
+Host *.example.com !bastion.example.com
+  StrictHostKeyChecking no
+
+Host db? web01
+  Port 2222
+
+Host !db1 db2
+`))
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"web01", "web02", "db2"}, names)
+	}
+}
+
+func TestParseInventory(t *testing.T) {
+	ids, err := parseInventory(strings.NewReader(`
+# Web servers
+web01
+deploy@web02:2222
+
+admin@db01
+`))
+	if assert.NoError(t, err) {
+		assert.Equal(t, []transport.Identity{
+			{Host: "web01"},
+			{Host: "web02", User: "deploy", Port: 2222},
+			{Host: "db01", User: "admin"},
+		}, ids)
+	}
+
+	_, err = parseInventory(strings.NewReader("web01\n@web02\n"))
+	assert.EqualError(t, err, "line 2: @web02 must be [user@]host[:port]")
+
+	_, err = parseInventory(strings.NewReader("web01:ssh\n"))
+	assert.EqualError(t, err, "line 1: invalid port ssh")
+}
@@ -0,0 +1,183 @@
+package hosts
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kevinburke/ssh_config"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/puppetlabs/wash/transport"
+)
+
+type host struct {
+	plugin.EntryBase
+	id transport.Identity
+}
+
+// hostMetadata is a host's connection info. It's found from the inventory and
+// ~/.ssh/config, so it doesn't require connecting to the host.
+type hostMetadata struct {
+	HostName string `json:"hostname"`
+	User     string `json:"user,omitempty"`
+	Port     string `json:"port,omitempty"`
+}
+
+// fullHostMetadata adds the host's kernel (from uname) and OS (from
+// /etc/os-release) to its connection info.
+type fullHostMetadata struct {
+	hostMetadata
+	Kernel kernel            `json:"kernel"`
+	OS     map[string]string `json:"os,omitempty"`
+}
+
+type kernel struct {
+	Name    string `json:"name"`
+	Release string `json:"release"`
+	Machine string `json:"machine"`
+}
+
+func newHost(id transport.Identity) *host {
+	h := &host{
+		EntryBase: plugin.NewEntry(id.Host),
+	}
+	h.id = id
+	h.SetPartialMetadata(h.connectionInfo())
+	return h
+}
+
+func (h *host) connectionInfo() hostMetadata {
+	meta := hostMetadata{
+		HostName: ssh_config.Get(h.id.Host, "HostName"),
+		User:     h.id.User,
+		Port:     ssh_config.Get(h.id.Host, "Port"),
+	}
+	if meta.HostName == "" {
+		meta.HostName = h.id.Host
+	}
+	if meta.User == "" {
+		meta.User = ssh_config.Get(h.id.Host, "User")
+	}
+	if h.id.Port != 0 {
+		meta.Port = strconv.Itoa(int(h.id.Port))
+	}
+	return meta
+}
+
+func (h *host) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(h, "host").
+		SetDescription(hostDescription).
+		SetPartialMetadataSchema(hostMetadata{}).
+		SetMetadataSchema(fullHostMetadata{})
+}
+
+func (h *host) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&plugin.MetadataJSONFile{}).Schema(),
+		(&sftpFS{}).Schema(),
+	}
+}
+
+func (h *host) List(ctx context.Context) ([]plugin.Entry, error) {
+	meta, err := plugin.NewMetadataJSONFile(ctx, h)
+	if err != nil {
+		return nil, err
+	}
+	return []plugin.Entry{meta, newSFTPFS(h)}, nil
+}
+
+// Metadata connects to the host to get its kernel and OS. Hosts that don't have
+// an /etc/os-release file omit their OS.
+func (h *host) Metadata(ctx context.Context) (plugin.JSONObject, error) {
+	meta := fullHostMetadata{hostMetadata: h.connectionInfo()}
+
+	uname, err := h.run(ctx, "uname", "-s", "-r", "-m")
+	if err != nil {
+		return nil, err
+	}
+	if fields := strings.Fields(uname); len(fields) == 3 {
+		meta.Kernel = kernel{Name: fields[0], Release: fields[1], Machine: fields[2]}
+	}
+
+	if osRelease, err := h.run(ctx, "cat", "/etc/os-release"); err != nil {
+		activity.Record(ctx, "Could not read /etc/os-release on %v: %v", h.id.Host, err)
+	} else {
+		meta.OS = parseOSRelease(osRelease)
+	}
+	return plugin.ToJSONObject(meta), nil
+}
+
+func (h *host) Exec(ctx context.Context, cmd string, args []string, opts plugin.ExecOptions) (plugin.ExecCommand, error) {
+	return transport.ExecSSH(ctx, h.id, append([]string{cmd}, args...), opts)
+}
+
+// run runs the command on the host and returns its stdout.
+func (h *host) run(ctx context.Context, cmd string, args ...string) (string, error) {
+	execCmd, err := plugin.Exec(ctx, h, cmd, args, plugin.ExecOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	var stdout, stderr strings.Builder
+	var errs []error
+	for chunk := range execCmd.OutputCh() {
+		if chunk.Err != nil {
+			errs = append(errs, chunk.Err)
+		} else if chunk.StreamID == plugin.Stdout {
+			stdout.WriteString(chunk.Data)
+		} else {
+			stderr.WriteString(chunk.Data)
+		}
+	}
+	if len(errs) > 0 {
+		return "", fmt.Errorf("exec errored: %v", errs)
+	}
+
+	exitCode, err := execCmd.ExitCode()
+	if err != nil {
+		return "", err
+	} else if exitCode != 0 {
+		return "", fmt.Errorf("%v exited with %v: %v", cmd, exitCode, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// parseOSRelease parses the KEY=value lines of an os-release file. See
+// os-release(5).
+func parseOSRelease(content string) map[string]string {
+	fields := make(map[string]string)
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		segments := strings.SplitN(line, "=", 2)
+		if len(segments) != 2 {
+			continue
+		}
+		value := segments[1]
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		} else {
+			value = strings.Trim(value, "'")
+		}
+		fields[segments[0]] = value
+	}
+	return fields
+}
+
+const hostDescription = `
+This is a host from ~/.ssh/config or the inventory. Its Exec action uses SSH
+with the host's settings in ~/.ssh/config (like its HostName, User, Port and
+IdentityFile). If present, a local SSH agent will be used for authentication.
+The user and port in the inventory override the ones in ~/.ssh/config.
+
+The host's metadata includes its kernel (from uname) and its OS (from
+/etc/os-release). Its fs directory is browsed via SFTP, so it only shows the
+files that the login user can access.
+`
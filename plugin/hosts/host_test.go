@@ -0,0 +1,22 @@
+package hosts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseOSRelease(t *testing.T) {
+	fields := parseOSRelease(`NAME="Ubuntu"
+VERSION_ID='20.04'
+ID=ubuntu
+# comment
+PRETTY_NAME="Ubuntu 20.04.1 LTS"
+`)
+	assert.Equal(t, map[string]string{
+		"NAME":        "Ubuntu",
+		"VERSION_ID":  "20.04",
+		"ID":          "ubuntu",
+		"PRETTY_NAME": "Ubuntu 20.04.1 LTS",
+	}, fields)
+}
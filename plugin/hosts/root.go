@@ -0,0 +1,129 @@
+// Package hosts presents a filesystem hierarchy for the hosts that you reach
+// via SSH.
+//
+// The hosts are found from the Host directives in ~/.ssh/config and from an
+// optional inventory file. Each host supports Exec over SSH, and includes a view
+// of its filesystem that's browsed via SFTP.
+package hosts
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/puppetlabs/wash/transport"
+)
+
+// Root of the hosts plugin
+type Root struct {
+	plugin.EntryBase
+	sshConfig string
+	inventory string
+}
+
+// Init for root
+func (r *Root) Init(cfg map[string]interface{}) error {
+	if inventoryI, ok := cfg["inventory"]; ok {
+		inventory, ok := inventoryI.(string)
+		if !ok {
+			return fmt.Errorf("hosts.inventory config must be a string, not %v", inventoryI)
+		}
+		r.inventory = inventory
+	}
+
+	homedir, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+	r.sshConfig = filepath.Join(homedir, ".ssh", "config")
+
+	r.EntryBase = plugin.NewEntry("hosts")
+	return nil
+}
+
+// Schema returns the root's schema
+func (r *Root) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(r, "hosts").
+		SetDescription(rootDescription).
+		IsSingleton()
+}
+
+// ChildSchemas returns the root's child schema
+func (r *Root) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&host{}).Schema(),
+	}
+}
+
+// List lists the hosts in ~/.ssh/config and the inventory. A host that's in both
+// uses the inventory's user and port.
+func (r *Root) List(ctx context.Context) ([]plugin.Entry, error) {
+	identities := make(map[string]transport.Identity)
+
+	f, err := os.Open(r.sshConfig)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	} else if err == nil {
+		defer f.Close()
+		names, err := sshConfigHosts(f)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse %v: %v", r.sshConfig, err)
+		}
+		for _, name := range names {
+			identities[name] = transport.Identity{Host: name}
+		}
+	} else {
+		activity.Record(ctx, "%v doesn't exist", r.sshConfig)
+	}
+
+	if r.inventory != "" {
+		f, err := os.Open(r.inventory)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		ids, err := parseInventory(f)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse %v: %v", r.inventory, err)
+		}
+		for _, id := range ids {
+			identities[id.Host] = id
+		}
+	}
+
+	names := make([]string, 0, len(identities))
+	for name := range identities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	entries := make([]plugin.Entry, len(names))
+	for i, name := range names {
+		entries[i] = newHost(identities[name])
+	}
+	return entries, nil
+}
+
+const rootDescription = `
+This is the hosts plugin root. It lists the hosts that you reach via SSH, so
+that you can work with hosts that aren't managed by a cloud API. The hosts are
+the Host directives in ~/.ssh/config that don't contain wildcards, e.g.
+
+Host web01
+  HostName web01.example.com
+  User deploy
+
+More hosts can be listed in an inventory file that's set by the hosts.inventory
+config, e.g.
+
+hosts:
+  inventory: /etc/wash/inventory
+
+The inventory lists one host per line as [user@]host[:port]. Blank lines and
+lines that start with '#' are ignored. The inventory's user and port override
+the ones in ~/.ssh/config.
+`
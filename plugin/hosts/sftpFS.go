@@ -0,0 +1,296 @@
+package hosts
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/pkg/sftp"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/puppetlabs/wash/transport"
+	"github.com/puppetlabs/wash/volume"
+)
+
+// sftpFS presents a host's filesystem. It's browsed via SFTP, which is faster
+// than running stat via Exec and doesn't depend on the host's shell.
+type sftpFS struct {
+	plugin.EntryBase
+	host     *host
+	maxdepth int
+}
+
+// streamPollInterval is how often a stream checks its file for new content.
+const streamPollInterval = time.Second
+
+func newSFTPFS(h *host) *sftpFS {
+	fs := &sftpFS{
+		EntryBase: plugin.NewEntry("fs"),
+	}
+	fs.host = h
+	// Use a small maxdepth because hosts can have lots of files.
+	fs.maxdepth = 3
+	fs.SetTTLOf(plugin.ListOp, volume.ListTTL)
+	return fs
+}
+
+func (fs *sftpFS) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(fs, "fs").
+		SetDescription(sftpFSDescription).
+		IsSingleton()
+}
+
+func (fs *sftpFS) ChildSchemas() []*plugin.EntrySchema {
+	return volume.ChildSchemas()
+}
+
+func (fs *sftpFS) List(ctx context.Context) ([]plugin.Entry, error) {
+	return volume.List(ctx, fs)
+}
+
+// VolumeList satisfies the volume.Interface required by List to enumerate files.
+func (fs *sftpFS) VolumeList(ctx context.Context, path string) (volume.DirMap, error) {
+	client, err := transport.SFTP(ctx, fs.host.id)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	activity.Record(ctx, "Listing %v on %v via SFTP", remotePath(path), fs.host.id.Host)
+	dirmap := make(volume.DirMap)
+	if err := listDir(ctx, client, dirmap, path, fs.maxdepth); err != nil {
+		return nil, err
+	}
+	return dirmap, nil
+}
+
+// listDir adds the directory at path and its descendants up to maxdepth to the
+// dirmap. Descendants that can't be read are left unexplored, so that their
+// error's returned when they're listed.
+func listDir(ctx context.Context, client *sftp.Client, dirmap volume.DirMap, path string, maxdepth int) error {
+	infos, err := client.ReadDir(remotePath(path))
+	if err != nil {
+		return err
+	}
+
+	children := make(volume.Children, len(infos))
+	for _, info := range infos {
+		children[info.Name()] = attributes(info)
+		if !info.IsDir() {
+			continue
+		}
+		subpath := path + "/" + info.Name()
+		dirmap[subpath] = nil
+		if maxdepth > 1 {
+			if err := listDir(ctx, client, dirmap, subpath, maxdepth-1); err != nil {
+				activity.Record(ctx, "Could not list %v: %v", subpath, err)
+			}
+		}
+	}
+	dirmap[path] = children
+	return nil
+}
+
+func attributes(info os.FileInfo) plugin.EntryAttributes {
+	var attr plugin.EntryAttributes
+	attr.
+		SetMode(info.Mode()).
+		SetSize(uint64(info.Size())).
+		SetMtime(info.ModTime())
+	if stat, ok := info.Sys().(*sftp.FileStat); ok {
+		attr.
+			SetAtime(time.Unix(int64(stat.Atime), 0)).
+			SetUID(stat.UID).
+			SetGID(stat.GID)
+	}
+	return attr
+}
+
+// VolumeRead satisfies the volume.Interface required by List to read file contents.
+func (fs *sftpFS) VolumeRead(ctx context.Context, path string) ([]byte, error) {
+	client, err := transport.SFTP(ctx, fs.host.id)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	activity.Record(ctx, "Reading %v on %v via SFTP", path, fs.host.id.Host)
+	f, err := client.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ioutil.ReadAll(f)
+}
+
+// VolumeBlockRead satisfies the volume.BlockReader interface so that the host's
+// files are read in blocks instead of downloading their entire content.
+func (fs *sftpFS) VolumeBlockRead(ctx context.Context, path string, size int64, offset int64) ([]byte, error) {
+	client, err := transport.SFTP(ctx, fs.host.id)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	activity.Record(ctx, "Reading %v bytes at offset %v of %v on %v via SFTP", size, offset, path, fs.host.id.Host)
+	f, err := client.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, size)
+	n, err := io.ReadFull(f, buf)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	return buf[:n], nil
+}
+
+// VolumeStream satisfies the volume.Interface required by List to stream file
+// contents. The stream starts at the end of the file and polls it for new
+// content until ctx is done.
+func (fs *sftpFS) VolumeStream(ctx context.Context, path string) (io.ReadCloser, error) {
+	client, err := transport.SFTP(ctx, fs.host.id)
+	if err != nil {
+		return nil, err
+	}
+
+	activity.Record(ctx, "Streaming %v on %v via SFTP", path, fs.host.id.Host)
+	f, err := client.Open(path)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	if _, err := f.Seek(0, io.SeekEnd); err != nil {
+		f.Close()
+		client.Close()
+		return nil, err
+	}
+	return &followReader{ctx: ctx, client: client, file: f}, nil
+}
+
+// followReader reads a remote file like 'tail -f'. It returns io.EOF once its
+// context is done.
+type followReader struct {
+	ctx    context.Context
+	client *sftp.Client
+	file   *sftp.File
+}
+
+func (r *followReader) Read(p []byte) (int, error) {
+	for {
+		n, err := r.file.Read(p)
+		if n > 0 || err != io.EOF {
+			return n, err
+		}
+		select {
+		case <-r.ctx.Done():
+			return 0, io.EOF
+		case <-time.After(streamPollInterval):
+		}
+	}
+}
+
+func (r *followReader) Close() error {
+	r.file.Close()
+	return r.client.Close()
+}
+
+// VolumeDelete satisfies the volume.Interface required by Delete to delete volume nodes.
+func (fs *sftpFS) VolumeDelete(ctx context.Context, path string) (bool, error) {
+	client, err := transport.SFTP(ctx, fs.host.id)
+	if err != nil {
+		return false, err
+	}
+	defer client.Close()
+
+	activity.Record(ctx, "Deleting %v on %v via SFTP", path, fs.host.id.Host)
+	if err := removeAll(client, path); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// removeAll removes path and its descendants. SFTP only removes empty
+// directories.
+func removeAll(client *sftp.Client, path string) error {
+	info, err := client.Lstat(path)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return client.Remove(path)
+	}
+
+	infos, err := client.ReadDir(path)
+	if err != nil {
+		return err
+	}
+	for _, info := range infos {
+		if err := removeAll(client, path+"/"+info.Name()); err != nil {
+			return err
+		}
+	}
+	return client.RemoveDirectory(path)
+}
+
+// VolumeRename satisfies the volume.Interface required by Rename to move volume nodes.
+func (fs *sftpFS) VolumeRename(ctx context.Context, path string, newPath string) error {
+	client, err := transport.SFTP(ctx, fs.host.id)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	activity.Record(ctx, "Moving %v to %v on %v via SFTP", path, newPath, fs.host.id.Host)
+	// SFTP's rename fails if newPath exists, so use OpenSSH's POSIX rename
+	// extension to replace it like mv does.
+	return client.PosixRename(path, newPath)
+}
+
+// VolumeWrite satisfies the volume.Interface required by Write to write file contents.
+func (fs *sftpFS) VolumeWrite(ctx context.Context, path string, b []byte, attr plugin.EntryAttributes) error {
+	client, err := transport.SFTP(ctx, fs.host.id)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	activity.Record(ctx, "Writing %v bytes to %v on %v via SFTP", len(b), path, fs.host.id.Host)
+	_, statErr := client.Lstat(path)
+	f, err := client.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if os.IsNotExist(statErr) {
+		return client.Chmod(path, attr.Mode().Perm())
+	}
+	return nil
+}
+
+// remotePath converts a volume path to the host's path.
+func remotePath(path string) string {
+	if path == volume.RootPath {
+		return "/"
+	}
+	return path
+}
+
+const sftpFSDescription = `
+This is a view of the host's filesystem. It's browsed via SFTP as the login user,
+so unlike the fs directory of other entries, it doesn't use sudo. Streaming a
+file (e.g. with 'tail -f') shows the content that's appended to it.
+`
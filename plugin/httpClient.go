@@ -0,0 +1,116 @@
+package plugin
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+// HTTPOptions configures the HTTP client that's shared by the core plugins, so
+// that environments with a corporate proxy or a private CA are configured once
+// instead of per plugin.
+type HTTPOptions struct {
+	// Proxy is the URL of the proxy that requests are sent through. Empty uses
+	// the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables.
+	Proxy string
+	// CABundle is the path of a PEM file of CA certificates that are trusted
+	// in addition to the system's.
+	CABundle string
+	// MinTLSVersion is the minimum TLS version, one of 1.0, 1.1, 1.2 or 1.3.
+	// Empty uses Go's default.
+	MinTLSVersion string
+}
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// defaultHTTPTransport is http.DefaultTransport before SetHTTPOptions replaces
+// it.
+var defaultHTTPTransport = http.DefaultTransport.(*http.Transport).Clone()
+
+var httpClient struct {
+	mux       sync.RWMutex
+	transport *http.Transport
+	client    *http.Client
+}
+
+func init() {
+	httpClient.transport = defaultHTTPTransport.Clone()
+	httpClient.client = &http.Client{Transport: httpClient.transport}
+}
+
+// SetHTTPOptions configures the shared HTTP client. The configured transport is
+// also installed as http.DefaultTransport so that the SDKs that don't let a
+// plugin supply its client (like GCP's and Azure's) use it too. It should be
+// called before the plugins are initialized since they typically create their
+// API clients in Init.
+func SetHTTPOptions(opts HTTPOptions) error {
+	transport := defaultHTTPTransport.Clone()
+	if opts.Proxy != "" {
+		proxy, err := url.Parse(opts.Proxy)
+		if err != nil || proxy.Host == "" {
+			return fmt.Errorf("http.proxy config %v must be a URL like http://proxy.example.com:3128", opts.Proxy)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	if opts.CABundle != "" || opts.MinTLSVersion != "" {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+	if opts.CABundle != "" {
+		pem, err := ioutil.ReadFile(opts.CABundle)
+		if err != nil {
+			return fmt.Errorf("http.ca_bundle config: %v", err)
+		}
+		// Add the bundle to the system's CAs instead of replacing them so that
+		// public endpoints keep working.
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("http.ca_bundle config: %v does not contain any PEM-encoded certificates", opts.CABundle)
+		}
+		transport.TLSClientConfig.RootCAs = pool
+	}
+	if opts.MinTLSVersion != "" {
+		version, ok := tlsVersions[opts.MinTLSVersion]
+		if !ok {
+			return fmt.Errorf("http.min_tls_version config must be one of 1.0, 1.1, 1.2 or 1.3, not %v", opts.MinTLSVersion)
+		}
+		transport.TLSClientConfig.MinVersion = version
+	}
+
+	httpClient.mux.Lock()
+	defer httpClient.mux.Unlock()
+	httpClient.transport = transport
+	httpClient.client = &http.Client{Transport: transport}
+	http.DefaultTransport = transport.Clone()
+	return nil
+}
+
+// HTTPClient returns the shared HTTP client. Plugins should use it (or
+// HTTPTransport) instead of http.DefaultClient or their own client so that
+// they honor the configured proxy and CAs.
+func HTTPClient() *http.Client {
+	httpClient.mux.RLock()
+	defer httpClient.mux.RUnlock()
+	return httpClient.client
+}
+
+// HTTPTransport returns a copy of the shared HTTP client's transport. Plugins
+// that need to customize it, e.g. to skip verifying a self-signed certificate,
+// can modify the copy's TLSClientConfig.
+func HTTPTransport() *http.Transport {
+	httpClient.mux.RLock()
+	defer httpClient.mux.RUnlock()
+	return httpClient.transport.Clone()
+}
@@ -0,0 +1,91 @@
+package plugin
+
+import (
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type HTTPClientTestSuite struct {
+	suite.Suite
+}
+
+func (suite *HTTPClientTestSuite) TearDownTest() {
+	suite.NoError(SetHTTPOptions(HTTPOptions{}))
+}
+
+func (suite *HTTPClientTestSuite) TestSetHTTPOptions_InvalidOptions() {
+	err := SetHTTPOptions(HTTPOptions{Proxy: "proxy.example.com"})
+	suite.Regexp("http.proxy config .* must be a URL", err)
+
+	err = SetHTTPOptions(HTTPOptions{MinTLSVersion: "1.4"})
+	suite.Regexp("http.min_tls_version config must be one of", err)
+
+	err = SetHTTPOptions(HTTPOptions{CABundle: "/does/not/exist"})
+	suite.Regexp("http.ca_bundle config", err)
+
+	bundle := suite.writeTempFile("not a certificate")
+	defer os.Remove(bundle)
+	err = SetHTTPOptions(HTTPOptions{CABundle: bundle})
+	suite.Regexp("does not contain any PEM-encoded certificates", err)
+}
+
+func (suite *HTTPClientTestSuite) TestSetHTTPOptions_Proxy() {
+	proxied := false
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.Host == "example.invalid"
+	}))
+	defer proxy.Close()
+
+	suite.NoError(SetHTTPOptions(HTTPOptions{Proxy: proxy.URL}))
+	resp, err := HTTPClient().Get("http://example.invalid/")
+	if suite.NoError(err) {
+		resp.Body.Close()
+		suite.True(proxied)
+	}
+}
+
+func (suite *HTTPClientTestSuite) TestSetHTTPOptions_CABundle() {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	// The server's self-signed certificate isn't trusted by default.
+	_, err := HTTPClient().Get(server.URL)
+	suite.Error(err)
+
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	bundle := suite.writeTempFile(string(cert))
+	defer os.Remove(bundle)
+	suite.NoError(SetHTTPOptions(HTTPOptions{CABundle: bundle, MinTLSVersion: "1.2"}))
+	resp, err := HTTPClient().Get(server.URL)
+	if suite.NoError(err) {
+		resp.Body.Close()
+	}
+
+	// Plugins can customize a copy of the transport without affecting the
+	// shared client.
+	transport := HTTPTransport()
+	transport.TLSClientConfig.InsecureSkipVerify = true
+	suite.False(HTTPTransport().TLSClientConfig.InsecureSkipVerify)
+}
+
+func (suite *HTTPClientTestSuite) writeTempFile(content string) string {
+	f, err := ioutil.TempFile("", "wash-http-test")
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	defer f.Close()
+	if _, err := f.WriteString(content); err != nil {
+		suite.FailNow(err.Error())
+	}
+	return f.Name()
+}
+
+func TestHTTPClient(t *testing.T) {
+	suite.Run(t, new(HTTPClientTestSuite))
+}
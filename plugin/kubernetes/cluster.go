@@ -0,0 +1,75 @@
+package kubernetes
+
+import (
+	"context"
+
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+	"k8s.io/apimachinery/pkg/api/resource"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8s "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// Cluster provides access to a Kubernetes cluster's namespaces. Other plugins
+// use it to expose the Kubernetes hierarchy of the clusters that they manage
+// (e.g. the GCP plugin's GKE clusters) without requiring a kubeconfig context.
+type Cluster struct {
+	client    *k8s.Clientset
+	config    *rest.Config
+	defaultns string
+}
+
+// NewCluster returns the cluster that's reachable with the given config.
+// Namespaces can't always be listed, so defaultns is the namespace that's
+// listed when they can't.
+func NewCluster(config *rest.Config, defaultns string) (*Cluster, error) {
+	clientset, err := k8s.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return &Cluster{client: clientset, config: config, defaultns: defaultns}, nil
+}
+
+// List lists the cluster's namespaces.
+func (c *Cluster) List(ctx context.Context) ([]plugin.Entry, error) {
+	return listNamespaces(ctx, c.client, c.config, c.defaultns)
+}
+
+// ClusterChildSchemas returns the schemas of a cluster's children. Entries that
+// list a cluster should return them from their ChildSchemas method.
+func ClusterChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&namespace{}).Schema(),
+	}
+}
+
+// WrappedTypes returns the Kubernetes types that are wrapped by the metadata
+// schemas. Entries that list a cluster should return them from their
+// WrappedTypes method.
+func WrappedTypes() plugin.SchemaMap {
+	return map[interface{}]*plugin.JSONSchema{
+		v1.Time{}:           plugin.TimeSchema(),
+		resource.Quantity{}: plugin.StringSchema(),
+	}
+}
+
+func listNamespaces(ctx context.Context, client *k8s.Clientset, config *rest.Config, defaultns string) ([]plugin.Entry, error) {
+	nsi := client.CoreV1().Namespaces()
+	nsList, err := nsi.List(ctx, v1.ListOptions{})
+	if err != nil {
+		activity.Record(ctx, "Error loading namespaces, using default namespace %v: %v", defaultns, err)
+		ns, err := nsi.Get(ctx, defaultns, v1.GetOptions{})
+		if err != nil {
+			activity.Record(ctx, "Error loading default namespace, metadata will not be available: %v", err)
+		}
+		return []plugin.Entry{newNamespace(defaultns, ns, client, config)}, nil
+	}
+
+	namespaces := make([]plugin.Entry, len(nsList.Items))
+	for i, ns := range nsList.Items {
+		namespaces[i] = newNamespace(ns.Name, &ns, client, config)
+	}
+	activity.Record(ctx, "Listing namespaces: %+v", namespaces)
+	return namespaces, nil
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/puppetlabs/wash/activity"
 	"github.com/puppetlabs/wash/plugin"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8s "k8s.io/client-go/kubernetes"
 )
 
@@ -52,6 +53,9 @@ func (clf *containerLogFile) Read(ctx context.Context) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// Stream follows the container's log, starting with its last 10 lines. If the
+// stream's since time is set, then it starts with the lines logged since then
+// instead.
 func (clf *containerLogFile) Stream(ctx context.Context) (io.ReadCloser, error) {
 	var tailLines int64 = 10
 	logOptions := corev1.PodLogOptions{
@@ -59,6 +63,11 @@ func (clf *containerLogFile) Stream(ctx context.Context) (io.ReadCloser, error)
 		Follow:    true,
 		TailLines: &tailLines,
 	}
+	if since, ok := plugin.StreamSince(ctx); ok {
+		sinceTime := metav1.NewTime(since)
+		logOptions.SinceTime = &sinceTime
+		logOptions.TailLines = nil
+	}
 	req := clf.client.CoreV1().Pods(clf.namespace).GetLogs(clf.podName, &logOptions)
 	return req.Stream(ctx)
 }
@@ -76,12 +76,16 @@ func (c *container) List(ctx context.Context) ([]plugin.Entry, error) {
 
 func (c *container) Exec(ctx context.Context, cmd string, args []string, opts plugin.ExecOptions) (plugin.ExecCommand, error) {
 	execCmd := plugin.NewExecCommand(ctx)
-	executor, err := c.newExecutor(ctx, cmd, args, remotecommand.StreamOptions{
+	streamOpts := remotecommand.StreamOptions{
 		Stdout: execCmd.Stdout(),
 		Stderr: execCmd.Stderr(),
 		Stdin:  opts.Stdin,
 		Tty:    opts.Tty,
-	})
+	}
+	if opts.Tty && opts.Resize != nil {
+		streamOpts.TerminalSizeQueue = terminalSizeQueue{ctx: ctx, ch: opts.Resize}
+	}
+	executor, err := c.newExecutor(ctx, cmd, args, streamOpts)
 	if err != nil {
 		return nil, errors.Wrap(err, "kubernetes.container.Exec request")
 	}
@@ -5,6 +5,7 @@ import (
 	"io"
 
 	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
 	corev1 "k8s.io/api/core/v1"
 	k8s "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -66,6 +67,25 @@ func (c *containerBase) String() string {
 	return s
 }
 
+// terminalSizeQueue feeds ExecOptions#Resize to the executor so that the TTY's
+// resized with the caller's terminal.
+type terminalSizeQueue struct {
+	ctx context.Context
+	ch  <-chan plugin.WindowSize
+}
+
+func (q terminalSizeQueue) Next() *remotecommand.TerminalSize {
+	select {
+	case <-q.ctx.Done():
+		return nil
+	case size, ok := <-q.ch:
+		if !ok {
+			return nil
+		}
+		return &remotecommand.TerminalSize{Width: size.Cols, Height: size.Rows}
+	}
+}
+
 type executor struct {
 	ctx  context.Context
 	exec remotecommand.Executor
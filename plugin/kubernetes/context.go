@@ -3,9 +3,7 @@ package kubernetes
 import (
 	"context"
 
-	"github.com/puppetlabs/wash/activity"
 	"github.com/puppetlabs/wash/plugin"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8s "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 )
@@ -15,15 +13,18 @@ type k8context struct {
 	client    *k8s.Clientset
 	config    *rest.Config
 	defaultns string
+	// namespaces selects the namespaces that are listed.
+	namespaces nameFilter
 }
 
-func newK8Context(name string, client *k8s.Clientset, config *rest.Config, defaultns string) *k8context {
+func newK8Context(name string, client *k8s.Clientset, config *rest.Config, defaultns string, namespaces nameFilter) *k8context {
 	context := &k8context{
 		EntryBase: plugin.NewEntry(name),
 	}
 	context.client = client
 	context.config = config
 	context.defaultns = defaultns
+	context.namespaces = namespaces
 	return context
 }
 
@@ -34,29 +35,21 @@ func (c *k8context) Schema() *plugin.EntrySchema {
 }
 
 func (c *k8context) ChildSchemas() []*plugin.EntrySchema {
-	return []*plugin.EntrySchema{
-		(&namespace{}).Schema(),
-	}
+	return ClusterChildSchemas()
 }
 
 func (c *k8context) List(ctx context.Context) ([]plugin.Entry, error) {
-	nsi := c.client.CoreV1().Namespaces()
-	nsList, err := nsi.List(ctx, metav1.ListOptions{})
+	namespaces, err := listNamespaces(ctx, c.client, c.config, c.defaultns)
 	if err != nil {
-		activity.Record(ctx, "Error loading namespaces, using default namespace %v: %v", c.defaultns, err)
-		ns, err := nsi.Get(ctx, c.defaultns, metav1.GetOptions{})
-		if err != nil {
-			activity.Record(ctx, "Error loading default namespace, metadata will not be available: %v", err)
-		}
-		return []plugin.Entry{newNamespace(c.defaultns, ns, c.client, c.config)}, nil
+		return nil, err
 	}
-
-	namespaces := make([]plugin.Entry, len(nsList.Items))
-	for i, ns := range nsList.Items {
-		namespaces[i] = newNamespace(ns.Name, &ns, c.client, c.config)
+	selected := make([]plugin.Entry, 0, len(namespaces))
+	for _, ns := range namespaces {
+		if c.namespaces.matches(plugin.Name(ns)) {
+			selected = append(selected, ns)
+		}
 	}
-	activity.Record(ctx, "Listing namespaces: %+v", namespaces)
-	return namespaces, nil
+	return selected, nil
 }
 
 const contextDescription = `
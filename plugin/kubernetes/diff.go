@@ -0,0 +1,264 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/puppetlabs/wash/activity"
+	k8err "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+)
+
+// diffFieldManager is the field manager of diff's dry-run applies.
+const diffFieldManager = "wash"
+
+// readManifest reads the manifest at path, or from stdin if path is "-". The
+// path must be absolute since it's opened by the daemon, whose working directory
+// isn't the caller's.
+func readManifest(path string, stdin io.Reader) (io.ReadCloser, error) {
+	if path != "-" {
+		if !filepath.IsAbs(path) {
+			return nil, fmt.Errorf("the manifest's path %v must be absolute", path)
+		}
+		return os.Open(path)
+	}
+	if stdin == nil {
+		return nil, fmt.Errorf("diff - requires the manifest on stdin")
+	}
+	return ioutil.NopCloser(stdin), nil
+}
+
+// decodeManifest decodes the objects in a YAML or JSON manifest. YAML manifests
+// can contain multiple documents, and List objects are flattened into their
+// items.
+func decodeManifest(r io.Reader) ([]*unstructured.Unstructured, error) {
+	var objs []*unstructured.Unstructured
+	decoder := k8syaml.NewYAMLOrJSONDecoder(r, 4096)
+	for {
+		obj := &unstructured.Unstructured{}
+		if err := decoder.Decode(&obj.Object); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to decode the manifest: %v", err)
+		}
+		if len(obj.Object) == 0 {
+			// An empty document, e.g. after a trailing '---'
+			continue
+		}
+		if !obj.IsList() {
+			objs = append(objs, obj)
+			continue
+		}
+		err := obj.EachListItem(func(item runtime.Object) error {
+			objs = append(objs, item.(*unstructured.Unstructured))
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode the manifest's %v: %v", obj.GetKind(), err)
+		}
+	}
+	if len(objs) == 0 {
+		return nil, fmt.Errorf("the manifest does not contain any objects")
+	}
+	return objs, nil
+}
+
+// manifestDiffer diffs a manifest's objects against the live objects in a
+// namespace.
+type manifestDiffer struct {
+	namespace string
+	mapper    meta.RESTMapper
+	client    dynamic.Interface
+}
+
+func newManifestDiffer(config *rest.Config, namespace string) (*manifestDiffer, error) {
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	client, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return &manifestDiffer{
+		namespace: namespace,
+		mapper:    restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient)),
+		client:    client,
+	}, nil
+}
+
+// diff writes a unified diff of each object's live and dry-run applied states to
+// out. It returns true if any of the objects would change.
+func (d *manifestDiffer) diff(ctx context.Context, objs []*unstructured.Unstructured, out io.Writer) (bool, error) {
+	changed := false
+	for _, obj := range objs {
+		live, merged, err := d.dryRun(ctx, obj)
+		if err != nil {
+			return changed, fmt.Errorf("%v %v: %v", obj.GetKind(), obj.GetName(), err)
+		}
+		name := objectName(merged)
+		activity.Record(ctx, "Diffing %v against its dry-run apply", name)
+		if isSecret(merged) {
+			maskSecretData(live, merged)
+		}
+
+		liveYAML, err := toDiffYAML(live)
+		if err != nil {
+			return changed, err
+		}
+		mergedYAML, err := toDiffYAML(merged)
+		if err != nil {
+			return changed, err
+		}
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(liveYAML),
+			B:        difflib.SplitLines(mergedYAML),
+			FromFile: "live/" + name,
+			ToFile:   "merged/" + name,
+			Context:  3,
+		})
+		if err != nil {
+			return changed, err
+		}
+		if diff == "" {
+			continue
+		}
+		changed = true
+		if _, err := io.WriteString(out, diff); err != nil {
+			return changed, err
+		}
+	}
+	return changed, nil
+}
+
+// dryRun returns the object's live state, or nil if it doesn't exist, and the
+// state it would have if it was applied. The latter's computed by the API
+// server with a server-side dry-run apply, so it includes defaulted fields and
+// the changes made by admission controllers.
+func (d *manifestDiffer) dryRun(ctx context.Context, obj *unstructured.Unstructured) (live *unstructured.Unstructured, merged *unstructured.Unstructured, err error) {
+	if obj.GetName() == "" {
+		return nil, nil, fmt.Errorf("objects must have a name to be diffed")
+	}
+	gvk := obj.GroupVersionKind()
+	mapping, err := d.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, nil, err
+	}
+	var client dynamic.ResourceInterface
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		if ns := obj.GetNamespace(); ns != "" && ns != d.namespace {
+			return nil, nil, fmt.Errorf("the object's namespace %v does not match the %v namespace", ns, d.namespace)
+		}
+		obj.SetNamespace(d.namespace)
+		client = d.client.Resource(mapping.Resource).Namespace(d.namespace)
+	} else {
+		client = d.client.Resource(mapping.Resource)
+	}
+
+	live, err = client.Get(ctx, obj.GetName(), v1.GetOptions{})
+	if k8err.IsNotFound(err) {
+		live = nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	body, err := obj.MarshalJSON()
+	if err != nil {
+		return nil, nil, err
+	}
+	force := true
+	merged, err = client.Patch(ctx, obj.GetName(), types.ApplyPatchType, body, v1.PatchOptions{
+		DryRun:       []string{v1.DryRunAll},
+		FieldManager: diffFieldManager,
+		Force:        &force,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return live, merged, nil
+}
+
+// objectName returns a name like kubectl diff's, e.g. apps.v1.Deployment.default.web.
+func objectName(obj *unstructured.Unstructured) string {
+	gvk := obj.GroupVersionKind()
+	parts := []string{gvk.Version, gvk.Kind}
+	if gvk.Group != "" {
+		parts = append([]string{gvk.Group}, parts...)
+	}
+	if ns := obj.GetNamespace(); ns != "" {
+		parts = append(parts, ns)
+	}
+	return strings.Join(append(parts, obj.GetName()), ".")
+}
+
+// toDiffYAML returns the object's YAML without its managed fields, which are
+// noise in a diff. A nil object's YAML is empty.
+func toDiffYAML(obj *unstructured.Unstructured) (string, error) {
+	if obj == nil {
+		return "", nil
+	}
+	obj = obj.DeepCopy()
+	unstructured.RemoveNestedField(obj.Object, "metadata", "managedFields")
+	content, err := yaml.Marshal(obj.Object)
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+func isSecret(obj *unstructured.Unstructured) bool {
+	gvk := obj.GroupVersionKind()
+	return gvk.Group == "" && gvk.Kind == "Secret"
+}
+
+// maskSecretData replaces the values of the secrets' data so that they aren't
+// shown in the diff or recorded in the journal. Changed values are masked
+// differently so that the diff still shows which keys would change. live is
+// nil if the secret doesn't exist yet.
+func maskSecretData(live *unstructured.Unstructured, merged *unstructured.Unstructured) {
+	var liveData map[string]interface{}
+	if live != nil {
+		liveData, _, _ = unstructured.NestedMap(live.Object, "data")
+	}
+	mergedData, _, _ := unstructured.NestedMap(merged.Object, "data")
+	for key, liveValue := range liveData {
+		mergedValue, ok := mergedData[key]
+		if !ok {
+			liveData[key] = "***"
+		} else if reflect.DeepEqual(liveValue, mergedValue) {
+			liveData[key], mergedData[key] = "***", "***"
+		} else {
+			liveData[key], mergedData[key] = "*** (before)", "*** (after)"
+		}
+	}
+	for key := range mergedData {
+		if _, ok := liveData[key]; !ok {
+			mergedData[key] = "***"
+		}
+	}
+	if live != nil && liveData != nil {
+		_ = unstructured.SetNestedMap(live.Object, liveData, "data")
+	}
+	if mergedData != nil {
+		_ = unstructured.SetNestedMap(merged.Object, mergedData, "data")
+	}
+	unstructured.RemoveNestedField(merged.Object, "stringData")
+}
@@ -0,0 +1,75 @@
+package kubernetes
+
+import (
+	"fmt"
+
+	"github.com/gobwas/glob"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// nameFilter selects contexts or namespaces by name. A name's selected if it
+// matches one of the include globs, or there aren't any, and it doesn't match
+// any of the exclude globs.
+type nameFilter struct {
+	include []glob.Glob
+	exclude []glob.Glob
+}
+
+func (f nameFilter) matches(name string) bool {
+	for _, g := range f.exclude {
+		if g.Match(name) {
+			return false
+		}
+	}
+	if len(f.include) == 0 {
+		return true
+	}
+	for _, g := range f.include {
+		if g.Match(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseNameFilter parses the kubernetes.<key> config, which is an object with
+// optional include and exclude arrays of globs, e.g.
+//
+//	contexts:
+//	  include: [prod-*, staging]
+//	  exclude: ["*-legacy"]
+func parseNameFilter(key string, filterI interface{}) (nameFilter, error) {
+	var f nameFilter
+	obj, ok := plugin.ToStringMap(filterI)
+	if !ok {
+		return f, fmt.Errorf("kubernetes.%v config must be an object with include and exclude arrays, not %v", key, filterI)
+	}
+	for k, v := range obj {
+		var globs *[]glob.Glob
+		switch k {
+		case "include":
+			globs = &f.include
+		case "exclude":
+			globs = &f.exclude
+		default:
+			return f, fmt.Errorf("kubernetes.%v config has an unknown key %v, expected include or exclude", key, k)
+		}
+
+		patterns, ok := v.([]interface{})
+		if !ok {
+			return f, fmt.Errorf("kubernetes.%v.%v config must be an array of globs, not %v", key, k, v)
+		}
+		for _, patternI := range patterns {
+			pattern, ok := patternI.(string)
+			if !ok || pattern == "" {
+				return f, fmt.Errorf("kubernetes.%v.%v config must be an array of globs, not %v", key, k, v)
+			}
+			g, err := glob.Compile(pattern)
+			if err != nil {
+				return f, fmt.Errorf("kubernetes.%v.%v config has an invalid glob %v: %v", key, k, pattern, err)
+			}
+			*globs = append(*globs, g)
+		}
+	}
+	return f, nil
+}
@@ -0,0 +1,120 @@
+package kubernetes
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// kubeconfigWatcher watches the kubeconfig files, along with any certificate files
+// that they reference, and invokes onChange when any of them are modified. It
+// watches the files' parent directories instead of the files themselves because
+// tools like `gcloud` and `kubectl` replace the kubeconfig instead of updating it
+// in-place, and because the files may not exist yet.
+type kubeconfigWatcher struct {
+	watcher  *fsnotify.Watcher
+	onChange func()
+	mux      sync.Mutex
+	files    map[string]struct{}
+	dirs     map[string]struct{}
+}
+
+func newKubeconfigWatcher(onChange func()) (*kubeconfigWatcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	w := &kubeconfigWatcher{
+		watcher:  watcher,
+		onChange: onChange,
+		files:    make(map[string]struct{}),
+		dirs:     make(map[string]struct{}),
+	}
+	go w.run()
+	return w, nil
+}
+
+// watch adds the given files to the set of watched files.
+func (w *kubeconfigWatcher) watch(files ...string) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	for _, file := range files {
+		if file == "" {
+			continue
+		}
+		file, err := filepath.Abs(file)
+		if err != nil {
+			continue
+		}
+		w.files[file] = struct{}{}
+
+		dir := filepath.Dir(file)
+		if _, ok := w.dirs[dir]; ok {
+			continue
+		}
+		if err := w.watcher.Add(dir); err != nil {
+			activity.Warnf(context.Background(), "kubernetes: unable to watch %v for kubeconfig changes: %v", dir, err)
+			continue
+		}
+		w.dirs[dir] = struct{}{}
+	}
+}
+
+// watchReferencedFiles watches the certificate and token files that are
+// referenced by the given kubeconfig.
+func (w *kubeconfigWatcher) watchReferencedFiles(config clientcmdapi.Config) {
+	var files []string
+	for _, cluster := range config.Clusters {
+		files = append(files, cluster.CertificateAuthority)
+	}
+	for _, authInfo := range config.AuthInfos {
+		files = append(files, authInfo.ClientCertificate, authInfo.ClientKey, authInfo.TokenFile)
+	}
+	w.watch(files...)
+}
+
+func (w *kubeconfigWatcher) isWatched(file string) bool {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	_, ok := w.files[filepath.Clean(file)]
+	return ok
+}
+
+func (w *kubeconfigWatcher) run() {
+	for {
+		select {
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Op == fsnotify.Chmod || !w.isWatched(event.Name) {
+				continue
+			}
+			activity.Record(context.Background(), "kubernetes: %v changed (%v), refreshing contexts", event.Name, event.Op)
+			w.onChange()
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			activity.Warnf(context.Background(), "kubernetes: error watching kubeconfig files: %v", err)
+		}
+	}
+}
+
+// refresh is the onChange callback of the root's kubeconfig watcher. It drops
+// the loaded contexts and clears everything that was cached with their clients.
+func (r *Root) refresh() {
+	r.mux.Lock()
+	r.contexts = nil
+	r.mux.Unlock()
+	// The root's ID is empty until the registry lists it, in which case there's
+	// nothing cached.
+	if id := r.ID(); id != "" {
+		plugin.ClearCacheFor(id, false)
+	}
+}
@@ -2,7 +2,9 @@ package kubernetes
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/puppetlabs/wash/activity"
 	"github.com/puppetlabs/wash/plugin"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -26,6 +28,8 @@ func newNamespace(name string, meta *corev1.Namespace, c *k8s.Clientset, cfg *re
 	ns.resources = []plugin.Entry{
 		newPodsDir(ns),
 		newPVCSDir(ns),
+		newNetworkPolicies(ns),
+		newRBAC(ns),
 	}
 	// TODO: Figure out other attributes that we could set here, if any.
 	ns.SetPartialMetadata(meta)
@@ -43,6 +47,8 @@ func (n *namespace) ChildSchemas() []*plugin.EntrySchema {
 	return []*plugin.EntrySchema{
 		(&podsDir{}).Schema(),
 		(&pvcsDir{}).Schema(),
+		(&networkPolicies{}).Schema(),
+		(&rbac{}).Schema(),
 	}
 }
 
@@ -55,6 +61,61 @@ func (n *namespace) Delete(ctx context.Context) (bool, error) {
 	return true, err
 }
 
+// Exec supports the diff command, which diffs a local manifest against the
+// namespace's live objects. See the namespace's description for its usage.
+func (n *namespace) Exec(ctx context.Context, cmd string, args []string, opts plugin.ExecOptions) (plugin.ExecCommand, error) {
+	if cmd != "diff" {
+		return nil, fmt.Errorf("unsupported command %v, only diff is supported", cmd)
+	}
+	if len(args) != 1 {
+		return nil, fmt.Errorf("diff requires the manifest's path, or - to read it from stdin")
+	}
+	manifest, err := readManifest(args[0], opts.Stdin)
+	if err != nil {
+		return nil, err
+	}
+	objs, err := decodeManifest(manifest)
+	manifest.Close()
+	if err != nil {
+		return nil, err
+	}
+	differ, err := newManifestDiffer(n.config, n.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	execCmd := plugin.NewExecCommand(ctx)
+	go func() {
+		changed, err := differ.diff(ctx, objs, execCmd.Stdout())
+		activity.Record(ctx, "Diffed %v objects against %v: changed %v, %v", len(objs), n.Name(), changed, err)
+		if err != nil {
+			execCmd.CloseStreamsWithError(err)
+			execCmd.SetExitCodeErr(err)
+			return
+		}
+		execCmd.CloseStreamsWithError(nil)
+		// Like diff and kubectl diff, exit 1 if there are differences.
+		if changed {
+			execCmd.SetExitCode(1)
+		} else {
+			execCmd.SetExitCode(0)
+		}
+	}()
+	return execCmd, nil
+}
+
 const namespaceDescription = `
 This is a Kubernetes namespace.
+
+Exec supports the diff command, which shows what would change if a manifest was
+applied to the namespace (e.g. with kubectl apply) without changing anything,
+e.g.
+  wexec kubernetes/my-cluster/default diff $PWD/deployment.yaml
+
+The manifest's path must be absolute. Use '-' as the path to read the manifest
+from stdin, e.g. the output of 'kustomize build' that's passed as the exec API's
+input. The manifest can be YAML or JSON and contain multiple objects. Each object's live state is diffed against the state
+that a server-side dry-run apply returns, so defaulted fields and changes made by
+admission controllers are included. Secrets' data is masked. Like kubectl diff,
+the exit code is 1 if anything would change.
 `
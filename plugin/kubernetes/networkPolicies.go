@@ -0,0 +1,431 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/puppetlabs/wash/activity"
+	cmdutil "github.com/puppetlabs/wash/cmd/util"
+	"github.com/puppetlabs/wash/plugin"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	k8s "k8s.io/client-go/kubernetes"
+)
+
+// networkPolicies renders the effective network policies of a namespace, i.e.
+// which of its pods can talk to which.
+type networkPolicies struct {
+	plugin.EntryBase
+	client *k8s.Clientset
+	ns     string
+}
+
+func newNetworkPolicies(ns *namespace) *networkPolicies {
+	np := &networkPolicies{
+		EntryBase: plugin.NewEntry("networkpolicies"),
+	}
+	np.client = ns.client
+	np.ns = ns.Name()
+	return np
+}
+
+func (np *networkPolicies) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(np, "networkpolicies").
+		IsSingleton().
+		SetDescription(networkPoliciesDescription)
+}
+
+func (np *networkPolicies) Read(ctx context.Context) ([]byte, error) {
+	policyList, err := np.client.NetworkingV1().NetworkPolicies(np.ns).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	podList, err := np.client.CoreV1().Pods(np.ns).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	// The namespace's labels are only needed to evaluate namespace selectors, so
+	// carry on without them if they can't be fetched.
+	var nsLabels labels.Set
+	if ns, err := np.client.CoreV1().Namespaces().Get(ctx, np.ns, metav1.GetOptions{}); err != nil {
+		activity.Record(ctx, "Unable to get namespace %v, its namespace selectors won't match: %v", np.ns, err)
+	} else {
+		nsLabels = ns.Labels
+	}
+	return []byte(renderNetworkPolicies(np.ns, nsLabels, podList.Items, policyList.Items)), nil
+}
+
+// allPorts represents a rule that applies to all ports.
+var allPorts []string
+
+func renderNetworkPolicies(ns string, nsLabels labels.Set, pods []corev1.Pod, policies []networkingv1.NetworkPolicy) string {
+	var b strings.Builder
+	if len(policies) == 0 {
+		fmt.Fprintf(&b, "No network policies apply to namespace %v, so its pods accept all traffic.\n", ns)
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "Network policies in namespace %v:\n", ns)
+	var rows [][]string
+	var externalRows [][]string
+	for _, policy := range policies {
+		ingress, egress := policyTypes(policy)
+		var types []string
+		if ingress {
+			types = append(types, "Ingress")
+		}
+		if egress {
+			types = append(types, "Egress")
+		}
+		rows = append(rows, []string{policy.Name, formatSelector(&policy.Spec.PodSelector, "all pods"), strings.Join(types, ",")})
+
+		for _, rule := range policy.Spec.Ingress {
+			for _, peer := range rule.From {
+				if peer.PodSelector != nil && peer.NamespaceSelector == nil {
+					continue
+				}
+				externalRows = append(externalRows, []string{policy.Name, "from", formatPeer(peer), formatPorts(rulePorts(rule.Ports))})
+			}
+		}
+		for _, rule := range policy.Spec.Egress {
+			for _, peer := range rule.To {
+				if peer.PodSelector != nil && peer.NamespaceSelector == nil {
+					continue
+				}
+				externalRows = append(externalRows, []string{policy.Name, "to", formatPeer(peer), formatPorts(rulePorts(rule.Ports))})
+			}
+		}
+	}
+	b.WriteString(cmdutil.NewTableWithHeaders([]cmdutil.ColumnHeader{
+		{ShortName: "policy", FullName: "POLICY"},
+		{ShortName: "selects", FullName: "SELECTS"},
+		{ShortName: "types", FullName: "TYPES"},
+	}, rows).Format())
+
+	b.WriteString("\nAllowed connections between the namespace's pods:\n")
+	rows = nil
+	egress := newPodConnections(policies, nsLabels, pods, false)
+	ingress := newPodConnections(policies, nsLabels, pods, true)
+	for _, src := range pods {
+		for _, dst := range pods {
+			if src.Name == dst.Name {
+				continue
+			}
+			egressPorts, ok := egress.allowedPorts(src.Name, dst.Name)
+			if !ok {
+				continue
+			}
+			ingressPorts, ok := ingress.allowedPorts(dst.Name, src.Name)
+			if !ok {
+				continue
+			}
+			ports, ok := intersectPorts(egressPorts, ingressPorts)
+			if !ok {
+				continue
+			}
+			rows = append(rows, []string{src.Name, dst.Name, formatPorts(ports)})
+		}
+	}
+	if len(rows) == 0 {
+		b.WriteString("  none\n")
+	} else {
+		b.WriteString(cmdutil.NewTableWithHeaders([]cmdutil.ColumnHeader{
+			{ShortName: "from", FullName: "FROM"},
+			{ShortName: "to", FullName: "TO"},
+			{ShortName: "ports", FullName: "PORTS"},
+		}, rows).Format())
+	}
+
+	if len(externalRows) > 0 {
+		b.WriteString("\nRules for peers outside the namespace's pods:\n")
+		b.WriteString(cmdutil.NewTableWithHeaders([]cmdutil.ColumnHeader{
+			{ShortName: "policy", FullName: "POLICY"},
+			{ShortName: "direction", FullName: "DIRECTION"},
+			{ShortName: "peer", FullName: "PEER"},
+			{ShortName: "ports", FullName: "PORTS"},
+		}, externalRows).Format())
+	}
+	return b.String()
+}
+
+// policyTypes returns whether the policy applies to ingress and egress traffic.
+// Policies without explicit types always apply to ingress, and apply to egress
+// if they have egress rules.
+func policyTypes(policy networkingv1.NetworkPolicy) (ingress bool, egress bool) {
+	if len(policy.Spec.PolicyTypes) == 0 {
+		return true, len(policy.Spec.Egress) > 0
+	}
+	for _, t := range policy.Spec.PolicyTypes {
+		switch t {
+		case networkingv1.PolicyTypeIngress:
+			ingress = true
+		case networkingv1.PolicyTypeEgress:
+			egress = true
+		}
+	}
+	return
+}
+
+// podConnections is the traffic that the policies of one direction (ingress
+// or egress) allow for each of the namespace's pods. Its maps are keyed by pod
+// name. Pods that aren't isolated, i.e. that aren't selected by a policy of
+// the direction, allow all traffic.
+type podConnections struct {
+	isolated map[string]bool
+	allowed  map[string]map[string]*portSet
+}
+
+// newPodConnections evaluates the policies of a direction. Each policy's
+// selectors are matched against the pods once, rather than once for every
+// pair of pods.
+func newPodConnections(policies []networkingv1.NetworkPolicy, nsLabels labels.Set, pods []corev1.Pod, ingress bool) podConnections {
+	c := podConnections{
+		isolated: make(map[string]bool),
+		allowed:  make(map[string]map[string]*portSet),
+	}
+	for _, policy := range policies {
+		appliesToIngress, appliesToEgress := policyTypes(policy)
+		if (ingress && !appliesToIngress) || (!ingress && !appliesToEgress) {
+			continue
+		}
+		selected := selectPods(&policy.Spec.PodSelector, pods)
+		for _, pod := range selected {
+			c.isolated[pod] = true
+		}
+		for _, r := range policyRules(policy, ingress) {
+			peers := matchPeers(r.peers, nsLabels, pods)
+			ports := rulePorts(r.ports)
+			for _, pod := range selected {
+				for _, peer := range peers {
+					c.allow(pod, peer, ports)
+				}
+			}
+		}
+	}
+	return c
+}
+
+func (c podConnections) allow(pod string, peer string, ports []string) {
+	peers, ok := c.allowed[pod]
+	if !ok {
+		peers = make(map[string]*portSet)
+		c.allowed[pod] = peers
+	}
+	set, ok := peers[peer]
+	if !ok {
+		set = &portSet{ports: make(map[string]struct{})}
+		peers[peer] = set
+	}
+	set.add(ports)
+}
+
+// allowedPorts returns the ports of pod that allow traffic from or to peer,
+// depending on the connections' direction. It returns false if the traffic
+// isn't allowed.
+func (c podConnections) allowedPorts(pod string, peer string) ([]string, bool) {
+	if !c.isolated[pod] {
+		return allPorts, true
+	}
+	set, ok := c.allowed[pod][peer]
+	if !ok {
+		return nil, false
+	}
+	return set.list(), true
+}
+
+// portSet is the union of the ports of the rules that allow a connection.
+type portSet struct {
+	all   bool
+	ports map[string]struct{}
+}
+
+func (s *portSet) add(ports []string) {
+	if ports == nil {
+		s.all = true
+	}
+	for _, port := range ports {
+		s.ports[port] = struct{}{}
+	}
+}
+
+func (s *portSet) list() []string {
+	if s.all {
+		return allPorts
+	}
+	return sortedKeys(s.ports)
+}
+
+// policyRule is an ingress or egress rule of a policy.
+type policyRule struct {
+	peers []networkingv1.NetworkPolicyPeer
+	ports []networkingv1.NetworkPolicyPort
+}
+
+func policyRules(policy networkingv1.NetworkPolicy, ingress bool) []policyRule {
+	var rules []policyRule
+	if ingress {
+		for _, r := range policy.Spec.Ingress {
+			rules = append(rules, policyRule{r.From, r.Ports})
+		}
+	} else {
+		for _, r := range policy.Spec.Egress {
+			rules = append(rules, policyRule{r.To, r.Ports})
+		}
+	}
+	return rules
+}
+
+// selectPods returns the names of the pods that the selector matches.
+func selectPods(selector *metav1.LabelSelector, pods []corev1.Pod) []string {
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil
+	}
+	var selected []string
+	for _, pod := range pods {
+		if s.Matches(labels.Set(pod.Labels)) {
+			selected = append(selected, pod.Name)
+		}
+	}
+	return selected
+}
+
+// matchPeers returns the names of the pods that any of the peers select. The
+// pods are in the policy's namespace, whose labels are nsLabels. An empty list
+// of peers selects everything.
+func matchPeers(peers []networkingv1.NetworkPolicyPeer, nsLabels labels.Set, pods []corev1.Pod) []string {
+	if len(peers) == 0 {
+		return selectPods(&metav1.LabelSelector{}, pods)
+	}
+	matched := make(map[string]struct{})
+	for _, peer := range peers {
+		if peer.IPBlock != nil {
+			// Pod IPs change, so IP blocks aren't matched against pods
+			continue
+		}
+		if peer.NamespaceSelector != nil && !selectorMatches(peer.NamespaceSelector, nsLabels) {
+			continue
+		}
+		podSelector := peer.PodSelector
+		if podSelector == nil {
+			podSelector = &metav1.LabelSelector{}
+		}
+		for _, pod := range selectPods(podSelector, pods) {
+			matched[pod] = struct{}{}
+		}
+	}
+	return sortedKeys(matched)
+}
+
+func selectorMatches(selector *metav1.LabelSelector, set labels.Set) bool {
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return false
+	}
+	return s.Matches(set)
+}
+
+// rulePorts returns the rule's ports formatted as <protocol>/<port>. It returns
+// allPorts if the rule applies to all ports.
+func rulePorts(ports []networkingv1.NetworkPolicyPort) []string {
+	if len(ports) == 0 {
+		return allPorts
+	}
+	formatted := make([]string, len(ports))
+	for i, port := range ports {
+		protocol := corev1.ProtocolTCP
+		if port.Protocol != nil {
+			protocol = *port.Protocol
+		}
+		if port.Port == nil {
+			formatted[i] = string(protocol)
+		} else {
+			formatted[i] = fmt.Sprintf("%v/%v", protocol, port.Port.String())
+		}
+	}
+	return formatted
+}
+
+// intersectPorts returns the ports that are allowed by both a and b. A port
+// without a number, like "TCP", allows all of its protocol's ports. It returns
+// false if there aren't any.
+func intersectPorts(a []string, b []string) ([]string, bool) {
+	if a == nil {
+		return b, true
+	}
+	if b == nil {
+		return a, true
+	}
+	both := make(map[string]struct{})
+	for _, portA := range a {
+		for _, portB := range b {
+			switch {
+			case portA == portB || strings.HasPrefix(portB, portA+"/"):
+				both[portB] = struct{}{}
+			case strings.HasPrefix(portA, portB+"/"):
+				both[portA] = struct{}{}
+			}
+		}
+	}
+	if len(both) == 0 {
+		return nil, false
+	}
+	return sortedKeys(both), true
+}
+
+func formatPorts(ports []string) string {
+	if ports == nil {
+		return "all"
+	}
+	return strings.Join(ports, ",")
+}
+
+func formatPeer(peer networkingv1.NetworkPolicyPeer) string {
+	if peer.IPBlock != nil {
+		if len(peer.IPBlock.Except) == 0 {
+			return peer.IPBlock.CIDR
+		}
+		return fmt.Sprintf("%v except %v", peer.IPBlock.CIDR, strings.Join(peer.IPBlock.Except, ","))
+	}
+	formatted := "pods " + formatSelector(peer.PodSelector, "*")
+	if peer.PodSelector == nil {
+		formatted = "all pods"
+	}
+	return formatted + " in namespaces " + formatSelector(peer.NamespaceSelector, "*")
+}
+
+func formatSelector(selector *metav1.LabelSelector, emptyValue string) string {
+	if selector == nil {
+		return emptyValue
+	}
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return fmt.Sprintf("<invalid selector: %v>", err)
+	}
+	if s.Empty() {
+		return emptyValue
+	}
+	return s.String()
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+const networkPoliciesDescription = `
+This file describes the effective network policies of the namespace. It lists
+the namespace's policies, the connections that they allow between the
+namespace's pods, and their rules for other peers like IP blocks and pods in
+other namespaces. A connection's allowed if the source pod's egress policies
+and the destination pod's ingress policies both allow it. Pods that aren't
+selected by a policy allow all traffic.
+`
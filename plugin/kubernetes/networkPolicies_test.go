@@ -0,0 +1,214 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func testPod(name string, app string) corev1.Pod {
+	return corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"app": app}}}
+}
+
+func appSelector(app string) *metav1.LabelSelector {
+	return &metav1.LabelSelector{MatchLabels: map[string]string{"app": app}}
+}
+
+func testPort(protocol corev1.Protocol, port int) networkingv1.NetworkPolicyPort {
+	p := intstr.FromInt(port)
+	return networkingv1.NetworkPolicyPort{Protocol: &protocol, Port: &p}
+}
+
+// ingressPolicy returns a policy that allows the pods that it selects to
+// receive traffic from the peers on the ports.
+func ingressPolicy(name string, selects string, from []networkingv1.NetworkPolicyPeer, ports ...networkingv1.NetworkPolicyPort) networkingv1.NetworkPolicy {
+	return networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: *appSelector(selects),
+			Ingress:     []networkingv1.NetworkPolicyIngressRule{{From: from, Ports: ports}},
+		},
+	}
+}
+
+var testPods = []corev1.Pod{testPod("web", "web"), testPod("db", "db"), testPod("other", "other")}
+
+func TestAllowedPorts(t *testing.T) {
+	fromWeb := []networkingv1.NetworkPolicyPeer{{PodSelector: appSelector("web")}}
+	denyAll := ingressPolicy("deny", "db", nil)
+	denyAll.Spec.Ingress = nil
+	egressToDB := networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "egress"},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: *appSelector("web"),
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress},
+			Egress: []networkingv1.NetworkPolicyEgressRule{{
+				To:    []networkingv1.NetworkPolicyPeer{{PodSelector: appSelector("db")}},
+				Ports: []networkingv1.NetworkPolicyPort{testPort(corev1.ProtocolTCP, 5432)},
+			}},
+		},
+	}
+
+	for _, c := range []struct {
+		name     string
+		policies []networkingv1.NetworkPolicy
+		nsLabels labels.Set
+		ingress  bool
+		pod      string
+		peer     string
+		ports    []string
+		allowed  bool
+	}{
+		{
+			name:    "pods without policies allow everything",
+			ingress: true, pod: "db", peer: "other", allowed: true,
+		},
+		{
+			name:     "pods that aren't selected allow everything",
+			policies: []networkingv1.NetworkPolicy{ingressPolicy("db", "db", fromWeb, testPort(corev1.ProtocolTCP, 5432))},
+			ingress:  true, pod: "web", peer: "other", allowed: true,
+		},
+		{
+			name:     "a pod selector allows its pods on the rule's ports",
+			policies: []networkingv1.NetworkPolicy{ingressPolicy("db", "db", fromWeb, testPort(corev1.ProtocolTCP, 5432))},
+			ingress:  true, pod: "db", peer: "web", ports: []string{"TCP/5432"}, allowed: true,
+		},
+		{
+			name:     "a pod selector doesn't allow other pods",
+			policies: []networkingv1.NetworkPolicy{ingressPolicy("db", "db", fromWeb, testPort(corev1.ProtocolTCP, 5432))},
+			ingress:  true, pod: "db", peer: "other",
+		},
+		{
+			name:     "a rule without ports allows every port",
+			policies: []networkingv1.NetworkPolicy{ingressPolicy("db", "db", fromWeb)},
+			ingress:  true, pod: "db", peer: "web", allowed: true,
+		},
+		{
+			name:     "a rule without peers allows every pod",
+			policies: []networkingv1.NetworkPolicy{ingressPolicy("db", "db", nil, testPort(corev1.ProtocolUDP, 53))},
+			ingress:  true, pod: "db", peer: "other", ports: []string{"UDP/53"}, allowed: true,
+		},
+		{
+			name:     "a policy without rules denies everything",
+			policies: []networkingv1.NetworkPolicy{denyAll},
+			ingress:  true, pod: "db", peer: "web",
+		},
+		{
+			name: "the ports of several policies are combined",
+			policies: []networkingv1.NetworkPolicy{
+				ingressPolicy("postgres", "db", fromWeb, testPort(corev1.ProtocolTCP, 5432)),
+				ingressPolicy("metrics", "db", fromWeb, testPort(corev1.ProtocolTCP, 9187)),
+			},
+			ingress: true, pod: "db", peer: "web", ports: []string{"TCP/5432", "TCP/9187"}, allowed: true,
+		},
+		{
+			name: "a matching namespace selector allows its pods",
+			policies: []networkingv1.NetworkPolicy{ingressPolicy("db", "db", []networkingv1.NetworkPolicyPeer{{
+				NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+				PodSelector:       appSelector("web"),
+			}})},
+			nsLabels: labels.Set{"team": "a"},
+			ingress:  true, pod: "db", peer: "web", allowed: true,
+		},
+		{
+			name: "a namespace selector that doesn't match the namespace doesn't allow its pods",
+			policies: []networkingv1.NetworkPolicy{ingressPolicy("db", "db", []networkingv1.NetworkPolicyPeer{{
+				NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+			}})},
+			nsLabels: labels.Set{"team": "b"},
+			ingress:  true, pod: "db", peer: "web",
+		},
+		{
+			name: "IP blocks don't match pods",
+			policies: []networkingv1.NetworkPolicy{ingressPolicy("db", "db", []networkingv1.NetworkPolicyPeer{{
+				IPBlock: &networkingv1.IPBlock{CIDR: "0.0.0.0/0"},
+			}})},
+			ingress: true, pod: "db", peer: "web",
+		},
+		{
+			name:     "egress policies allow their pods' destinations",
+			policies: []networkingv1.NetworkPolicy{egressToDB},
+			pod:      "web", peer: "db", ports: []string{"TCP/5432"}, allowed: true,
+		},
+		{
+			name:     "egress policies deny other destinations",
+			policies: []networkingv1.NetworkPolicy{egressToDB},
+			pod:      "web", peer: "other",
+		},
+		{
+			name:     "egress policies don't isolate ingress",
+			policies: []networkingv1.NetworkPolicy{egressToDB},
+			ingress:  true, pod: "web", peer: "other", allowed: true,
+		},
+		{
+			name:     "policies without types only isolate egress if they have egress rules",
+			policies: []networkingv1.NetworkPolicy{ingressPolicy("db", "db", fromWeb)},
+			pod:      "db", peer: "other", allowed: true,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			connections := newPodConnections(c.policies, c.nsLabels, testPods, c.ingress)
+			ports, allowed := connections.allowedPorts(c.pod, c.peer)
+			assert.Equal(t, c.allowed, allowed)
+			assert.Equal(t, c.ports, ports)
+		})
+	}
+}
+
+func TestIntersectPorts(t *testing.T) {
+	for _, c := range []struct {
+		a       []string
+		b       []string
+		ports   []string
+		allowed bool
+	}{
+		{nil, nil, nil, true},
+		{nil, []string{"TCP/80"}, []string{"TCP/80"}, true},
+		{[]string{"TCP/80"}, nil, []string{"TCP/80"}, true},
+		{[]string{"TCP/80", "TCP/443"}, []string{"TCP/443", "UDP/53"}, []string{"TCP/443"}, true},
+		{[]string{"TCP/80"}, []string{"UDP/80"}, nil, false},
+		// A port without a number allows all of its protocol's ports.
+		{[]string{"TCP"}, []string{"TCP/80", "UDP/53"}, []string{"TCP/80"}, true},
+		{[]string{"TCP/80", "TCP/443"}, []string{"TCP"}, []string{"TCP/443", "TCP/80"}, true},
+		{[]string{"TCP"}, []string{"UDP"}, nil, false},
+	} {
+		ports, allowed := intersectPorts(c.a, c.b)
+		assert.Equal(t, c.allowed, allowed, "%v and %v", c.a, c.b)
+		assert.Equal(t, c.ports, ports, "%v and %v", c.a, c.b)
+	}
+}
+
+func TestRenderNetworkPolicies(t *testing.T) {
+	assert.Equal(
+		t,
+		"No network policies apply to namespace default, so its pods accept all traffic.\n",
+		renderNetworkPolicies("default", nil, testPods, nil),
+	)
+
+	fromWebAndBlock := []networkingv1.NetworkPolicyPeer{
+		{PodSelector: appSelector("web")},
+		{IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.0/8", Except: []string{"10.0.0.1/32"}}},
+	}
+	isolateAll := networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "isolate"},
+		Spec: networkingv1.NetworkPolicySpec{
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+		},
+	}
+	rendered := renderNetworkPolicies("default", nil, testPods, []networkingv1.NetworkPolicy{
+		isolateAll,
+		ingressPolicy("db", "db", fromWebAndBlock, testPort(corev1.ProtocolTCP, 5432)),
+	})
+	assert.Regexp(t, `isolate\s+all pods\s+Ingress`, rendered)
+	assert.Regexp(t, `db\s+app=db\s+Ingress`, rendered)
+	// Only web can reach db, and nothing can reach the other pods.
+	assert.Regexp(t, `web\s+db\s+TCP/5432`, rendered)
+	assert.NotRegexp(t, `other\s+db`, rendered)
+	assert.NotRegexp(t, `\s+(web|other)\s+(all|TCP)`, rendered)
+	assert.Regexp(t, `db\s+from\s+10\.0\.0\.0/8 except 10\.0\.0\.1/32\s+TCP/5432`, rendered)
+}
@@ -25,11 +25,18 @@ func newPod(ctx context.Context, client *k8s.Clientset, config *rest.Config, ns
 	pd.config = config
 	pd.ns = ns
 
+	// restarts is the total number of times that the pod's containers restarted
+	restarts := 0
+	for _, status := range p.Status.ContainerStatuses {
+		restarts += int(status.RestartCount)
+	}
+
 	pd.
 		SetPartialMetadata(p).
 		Attributes().
 		SetCrtime(p.CreationTimestamp.Time).
-		SetAtime(p.CreationTimestamp.Time)
+		SetAtime(p.CreationTimestamp.Time).
+		SetCustom("restarts", restarts)
 
 	return pd, nil
 }
@@ -37,12 +44,14 @@ func newPod(ctx context.Context, client *k8s.Clientset, config *rest.Config, ns
 func (p *pod) Schema() *plugin.EntrySchema {
 	return plugin.
 		NewEntrySchema(p, "pod").
-		SetPartialMetadataSchema(corev1.Pod{})
+		SetPartialMetadataSchema(corev1.Pod{}).
+		AddCustomAttribute("restarts", plugin.NumberAttribute, "The total number of times that the pod's containers restarted")
 }
 
 func (p *pod) ChildSchemas() []*plugin.EntrySchema {
 	return []*plugin.EntrySchema{
 		(&container{}).Schema(),
+		(&podPVCsDir{}).Schema(),
 	}
 }
 
@@ -62,6 +71,9 @@ func (p *pod) List(ctx context.Context) ([]plugin.Entry, error) {
 		entries[i] = c
 	}
 
+	if pvcs := newPodPVCsDir(pd); len(pvcs.claims) > 0 {
+		entries = append(entries, pvcs)
+	}
 	return entries, nil
 }
 
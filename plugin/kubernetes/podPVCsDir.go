@@ -0,0 +1,55 @@
+package kubernetes
+
+import (
+	"context"
+
+	"github.com/puppetlabs/wash/plugin"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// podPVCsDir contains symlinks to the persistent volume claims that are mounted
+// by a pod's volumes.
+type podPVCsDir struct {
+	plugin.EntryBase
+	claims []string
+}
+
+func newPodPVCsDir(p *corev1.Pod) *podPVCsDir {
+	pv := &podPVCsDir{
+		EntryBase: plugin.NewEntry("persistentvolumeclaims"),
+	}
+	for _, volume := range p.Spec.Volumes {
+		if claim := volume.PersistentVolumeClaim; claim != nil {
+			pv.claims = append(pv.claims, claim.ClaimName)
+		}
+	}
+	return pv
+}
+
+func (pv *podPVCsDir) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(pv, "persistentvolumeclaims").
+		SetDescription(podPVCsDirDescription).
+		IsSingleton()
+}
+
+func (pv *podPVCsDir) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&plugin.Symlink{}).Schema(),
+	}
+}
+
+func (pv *podPVCsDir) List(ctx context.Context) ([]plugin.Entry, error) {
+	entries := make([]plugin.Entry, len(pv.claims))
+	for i, claim := range pv.claims {
+		// The claims are in the namespace's persistentvolumeclaims directory,
+		// which is a sibling of the pod's pods directory.
+		entries[i] = plugin.NewSymlink("../../../persistentvolumeclaims/"+claim, claim)
+	}
+	return entries, nil
+}
+
+const podPVCsDirDescription = `
+This directory contains symlinks to the persistent volume claims that are
+mounted by the pod, so you can cd into them to browse the volumes' files.
+`
@@ -2,11 +2,19 @@ package kubernetes
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"sync"
 
+	"github.com/puppetlabs/wash/activity"
 	"github.com/puppetlabs/wash/plugin"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
 	k8s "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	k8scache "k8s.io/client-go/tools/cache"
 )
 
 type podsDir struct {
@@ -27,7 +35,9 @@ func newPodsDir(ns *namespace) *podsDir {
 }
 
 func (ps *podsDir) Schema() *plugin.EntrySchema {
-	return plugin.NewEntrySchema(ps, "pods").IsSingleton()
+	return plugin.NewEntrySchema(ps, "pods").
+		IsSingleton().
+		SetDescription(podsDirDescription)
 }
 
 func (ps *podsDir) ChildSchemas() []*plugin.EntrySchema {
@@ -43,6 +53,123 @@ func (ps *podsDir) List(ctx context.Context) ([]plugin.Entry, error) {
 	if err != nil {
 		return nil, err
 	}
+	return ps.toEntries(ctx, podList)
+}
+
+// podsSearchPageSize is the number of pods that are requested per page of
+// search results.
+const podsSearchPageSize = 500
+
+// Search lists the pods that match the given field selector, or the given label
+// selector if the query's prefixed with "label:". The selector's evaluated by the
+// Kubernetes API server.
+func (ps *podsDir) Search(ctx context.Context, query string, pageToken string) ([]plugin.Entry, string, error) {
+	opts := metav1.ListOptions{Limit: podsSearchPageSize, Continue: pageToken}
+	if strings.HasPrefix(query, labelSelectorPrefix) {
+		opts.LabelSelector = strings.TrimPrefix(query, labelSelectorPrefix)
+	} else {
+		opts.FieldSelector = query
+	}
+	podList, err := ps.client.CoreV1().Pods(ps.ns).List(ctx, opts)
+	if err != nil {
+		return nil, "", err
+	}
+	entries, err := ps.toEntries(ctx, podList)
+	if err != nil {
+		return nil, "", err
+	}
+	return entries, podList.Continue, nil
+}
+
+const labelSelectorPrefix = "label:"
+
+// Watch uses an informer to watch the pods.
+func (ps *podsDir) Watch(ctx context.Context) (<-chan plugin.EntryEvent, error) {
+	factory := informers.NewSharedInformerFactoryWithOptions(ps.client, 0, informers.WithNamespace(ps.ns))
+	informer := factory.Core().V1().Pods().Informer()
+
+	// The informer reports the existing pods as added when it starts, so
+	// those pods are skipped once the informer's synced.
+	synced := make(chan struct{})
+	existing := make(map[types.UID]bool)
+	events := make(chan plugin.EntryEvent)
+	var mux sync.Mutex
+	closed := false
+	send := func(eventType plugin.EntryEventType, p *corev1.Pod) {
+		var event plugin.EntryEvent
+		event.Type = eventType
+		if eventType == plugin.EntryDeleted {
+			event.Name = p.Name
+		} else {
+			pd, err := newPod(ctx, ps.client, ps.config, ps.ns, p)
+			if err != nil {
+				activity.Warnf(ctx, "Could not create an entry for pod %v: %v", p.Name, err)
+				return
+			}
+			event.Entry = pd
+		}
+		// Handlers can run after ctx is done, so the lock stops them from
+		// sending events once the channel's closed.
+		mux.Lock()
+		defer mux.Unlock()
+		if closed {
+			return
+		}
+		select {
+		case events <- event:
+		case <-ctx.Done():
+		}
+	}
+	informer.AddEventHandler(k8scache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			select {
+			case <-synced:
+			case <-ctx.Done():
+				return
+			}
+			if p, ok := obj.(*corev1.Pod); ok && !existing[p.UID] {
+				send(plugin.EntryCreated, p)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			if p, ok := newObj.(*corev1.Pod); ok {
+				send(plugin.EntryModified, p)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			// The pod's final state is unknown if the informer missed its
+			// deletion.
+			if tombstone, ok := obj.(k8scache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if p, ok := obj.(*corev1.Pod); ok {
+				send(plugin.EntryDeleted, p)
+			}
+		},
+	})
+
+	factory.Start(ctx.Done())
+	if !k8scache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
+		return nil, fmt.Errorf("could not sync the pods in namespace %v: %v", ps.ns, ctx.Err())
+	}
+	for _, obj := range informer.GetStore().List() {
+		if p, ok := obj.(*corev1.Pod); ok {
+			existing[p.UID] = true
+		}
+	}
+	close(synced)
+
+	go func() {
+		<-ctx.Done()
+		mux.Lock()
+		defer mux.Unlock()
+		closed = true
+		close(events)
+	}()
+	return events, nil
+}
+
+func (ps *podsDir) toEntries(ctx context.Context, podList *corev1.PodList) ([]plugin.Entry, error) {
 	entries := make([]plugin.Entry, len(podList.Items))
 	for i, p := range podList.Items {
 		pd, err := newPod(ctx, ps.client, ps.config, ps.ns, &p)
@@ -54,3 +181,13 @@ func (ps *podsDir) List(ctx context.Context) ([]plugin.Entry, error) {
 	}
 	return entries, nil
 }
+
+const podsDirDescription = `
+This directory contains the namespace's pods. Its .search directory lists the
+pods that match a field selector, or a label selector that's prefixed with
+"label:". For example,
+  ls pods/.search/status.phase=Running
+  ls pods/.search/label:app=web
+The selectors are evaluated by the Kubernetes API server, so only the matching
+pods are fetched. The .search directory isn't listed, but you can cd into it.
+`
@@ -3,8 +3,10 @@ package kubernetes
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"io"
 
+	"github.com/kballard/go-shellquote"
 	"github.com/puppetlabs/wash/activity"
 	"github.com/puppetlabs/wash/plugin"
 	"github.com/puppetlabs/wash/volume"
@@ -51,6 +53,7 @@ func (v *pvc) Schema() *plugin.EntrySchema {
 	return plugin.
 		NewEntrySchema(v, "persistentvolumeclaim").
 		SetDescription(pvcDescription).
+		SetListCost(plugin.ExpensiveList).
 		SetPartialMetadataSchema(corev1.PersistentVolumeClaim{})
 }
 
@@ -62,6 +65,12 @@ func (v *pvc) List(ctx context.Context) ([]plugin.Entry, error) {
 	return volume.List(ctx, v)
 }
 
+// VolumeBrowsesArchives lets users browse the content of the PVC's compressed
+// files, like rotated logs.
+func (v *pvc) VolumeBrowsesArchives() bool {
+	return true
+}
+
 func (v *pvc) Delete(ctx context.Context) (bool, error) {
 	err := v.pvci.Delete(ctx, v.Name(), metav1.DeleteOptions{})
 	return true, err
@@ -203,6 +212,19 @@ func (v *pvc) VolumeRead(ctx context.Context, path string) ([]byte, error) {
 	return output, nil
 }
 
+// VolumeBlockRead reads the requested range with tail and head so that reading
+// part of a large file doesn't copy all of it out of the pod.
+func (v *pvc) VolumeBlockRead(ctx context.Context, path string, size int64, offset int64) ([]byte, error) {
+	output, err := v.exec(ctx, func(base string) []string {
+		script := fmt.Sprintf("tail -c +%v %v | head -c %v", offset+1, shellquote.Join(base+path), size)
+		return []string{"sh", "-c", script}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
 func (v *pvc) VolumeStream(ctx context.Context, path string) (io.ReadCloser, error) {
 	obj, err := v.inContainer(ctx, func(c *containerBase, mountpoint string, cleanup func()) (interface{}, error) {
 		cmd := []string{"tail", "-f", mountpoint + path}
@@ -238,10 +260,19 @@ func (v *pvc) VolumeDelete(ctx context.Context, path string) (bool, error) {
 	return true, nil
 }
 
+func (v *pvc) VolumeRename(ctx context.Context, path string, newPath string) error {
+	_, err := v.exec(ctx, func(base string) []string {
+		return []string{"mv", "-f", base + path, base + newPath}
+	})
+	return err
+}
+
 const pvcDescription = `
 This is a Kubernetes persistent volume claim. We create a temporary Kubernetes
 pod whenever Wash invokes a currently uncached List/Read/Stream action on it or
 one of its children. For List, we run 'find -exec stat' on the pod and parse its
-output. For Read, we run 'cat' and return its output. For Stream, we run 'tail -f'
-and stream its output.
+output. For Read, we run 'tail -c +<offset> | head -c <size>' to read the requested
+range, prefetching a few MB at a time. For Stream, we run 'tail -f' and stream its
+output. Compressed files, like rotated logs, can be browsed via their sibling
+'<name>.contents' directory.
 `
@@ -0,0 +1,163 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/puppetlabs/wash/activity"
+	cmdutil "github.com/puppetlabs/wash/cmd/util"
+	"github.com/puppetlabs/wash/plugin"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8s "k8s.io/client-go/kubernetes"
+)
+
+// rbac renders the effective RBAC rules of a namespace, i.e. which subjects
+// hold which verbs on which resources.
+type rbac struct {
+	plugin.EntryBase
+	client *k8s.Clientset
+	ns     string
+}
+
+func newRBAC(ns *namespace) *rbac {
+	r := &rbac{
+		EntryBase: plugin.NewEntry("rbac"),
+	}
+	r.client = ns.client
+	r.ns = ns.Name()
+	return r
+}
+
+func (r *rbac) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(r, "rbac").
+		IsSingleton().
+		SetDescription(rbacDescription)
+}
+
+func (r *rbac) Read(ctx context.Context) ([]byte, error) {
+	bindingList, err := r.client.RbacV1().RoleBindings(r.ns).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	roleList, err := r.client.RbacV1().Roles(r.ns).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	roleRules := make(map[string][]rbacv1.PolicyRule)
+	for _, role := range roleList.Items {
+		roleRules[roleRefKey("Role", role.Name)] = role.Rules
+	}
+	// Role bindings can also grant a cluster role's rules within the namespace.
+	// Those are fetched individually so that listing the namespace's RBAC rules
+	// doesn't require access to every cluster role.
+	roleErrs := make(map[string]error)
+	for _, binding := range bindingList.Items {
+		key := roleRefKey(binding.RoleRef.Kind, binding.RoleRef.Name)
+		if binding.RoleRef.Kind != "ClusterRole" {
+			continue
+		}
+		if _, ok := roleRules[key]; ok {
+			continue
+		}
+		if _, ok := roleErrs[key]; ok {
+			continue
+		}
+		clusterRole, err := r.client.RbacV1().ClusterRoles().Get(ctx, binding.RoleRef.Name, metav1.GetOptions{})
+		if err != nil {
+			activity.Record(ctx, "Unable to get cluster role %v: %v", binding.RoleRef.Name, err)
+			roleErrs[key] = err
+			continue
+		}
+		roleRules[key] = clusterRole.Rules
+	}
+	return []byte(renderRBAC(r.ns, bindingList.Items, roleRules, roleErrs)), nil
+}
+
+func roleRefKey(kind string, name string) string {
+	return kind + "/" + name
+}
+
+func renderRBAC(ns string, bindings []rbacv1.RoleBinding, roleRules map[string][]rbacv1.PolicyRule, roleErrs map[string]error) string {
+	var b strings.Builder
+	if len(bindings) == 0 {
+		fmt.Fprintf(&b, "No role bindings grant permissions in namespace %v.\n", ns)
+		return b.String()
+	}
+
+	var rows [][]string
+	for _, binding := range bindings {
+		key := roleRefKey(binding.RoleRef.Kind, binding.RoleRef.Name)
+		via := fmt.Sprintf("RoleBinding/%v (%v)", binding.Name, key)
+		for _, subject := range binding.Subjects {
+			subjectName := formatSubject(subject, ns)
+			if err, ok := roleErrs[key]; ok {
+				rows = append(rows, []string{subjectName, "?", fmt.Sprintf("<unknown: %v>", err), via})
+				continue
+			}
+			rules, ok := roleRules[key]
+			if !ok {
+				rows = append(rows, []string{subjectName, "?", "<missing role>", via})
+				continue
+			}
+			for _, rule := range rules {
+				rows = append(rows, []string{subjectName, strings.Join(rule.Verbs, ","), formatRuleResources(rule), via})
+			}
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i][0] < rows[j][0]
+	})
+
+	fmt.Fprintf(&b, "Permissions granted in namespace %v:\n", ns)
+	b.WriteString(cmdutil.NewTableWithHeaders([]cmdutil.ColumnHeader{
+		{ShortName: "subject", FullName: "SUBJECT"},
+		{ShortName: "verbs", FullName: "VERBS"},
+		{ShortName: "resources", FullName: "RESOURCES"},
+		{ShortName: "via", FullName: "VIA"},
+	}, rows).Format())
+	return b.String()
+}
+
+func formatSubject(subject rbacv1.Subject, ns string) string {
+	if subject.Kind == rbacv1.ServiceAccountKind {
+		subjectNS := subject.Namespace
+		if subjectNS == "" {
+			subjectNS = ns
+		}
+		return fmt.Sprintf("%v %v/%v", subject.Kind, subjectNS, subject.Name)
+	}
+	return fmt.Sprintf("%v %v", subject.Kind, subject.Name)
+}
+
+// formatRuleResources formats the rule's resources as <resource>[.<group>], with
+// the resource names that the rule's restricted to in brackets.
+func formatRuleResources(rule rbacv1.PolicyRule) string {
+	if len(rule.NonResourceURLs) > 0 {
+		return strings.Join(rule.NonResourceURLs, ",")
+	}
+	var resources []string
+	for _, group := range rule.APIGroups {
+		for _, resource := range rule.Resources {
+			if group != "" {
+				resource += "." + group
+			}
+			resources = append(resources, resource)
+		}
+	}
+	formatted := strings.Join(resources, ",")
+	if len(rule.ResourceNames) > 0 {
+		formatted += "[" + strings.Join(rule.ResourceNames, ",") + "]"
+	}
+	return formatted
+}
+
+const rbacDescription = `
+This file describes the effective RBAC rules of the namespace. Each line lists
+a subject (a user, group or service account), the verbs that it holds on the
+listed resources, and the role binding that grants them. Cluster roles that are
+bound in the namespace are expanded, but permissions that are granted by
+cluster role bindings aren't included.
+`
@@ -0,0 +1,71 @@
+package kubernetes
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFormatRuleResources(t *testing.T) {
+	for _, c := range []struct {
+		rule      rbacv1.PolicyRule
+		formatted string
+	}{
+		{rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"pods", "services"}}, "pods,services"},
+		{rbacv1.PolicyRule{APIGroups: []string{"apps", "extensions"}, Resources: []string{"deployments"}}, "deployments.apps,deployments.extensions"},
+		{rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"secrets"}, ResourceNames: []string{"a", "b"}}, "secrets[a,b]"},
+		{rbacv1.PolicyRule{NonResourceURLs: []string{"/healthz", "/metrics"}}, "/healthz,/metrics"},
+	} {
+		assert.Equal(t, c.formatted, formatRuleResources(c.rule))
+	}
+}
+
+func TestFormatSubject(t *testing.T) {
+	for _, c := range []struct {
+		subject   rbacv1.Subject
+		formatted string
+	}{
+		{rbacv1.Subject{Kind: rbacv1.UserKind, Name: "alice"}, "User alice"},
+		{rbacv1.Subject{Kind: rbacv1.GroupKind, Name: "devs"}, "Group devs"},
+		// Service accounts default to the binding's namespace.
+		{rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: "ci"}, "ServiceAccount default/ci"},
+		{rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: "ci", Namespace: "tools"}, "ServiceAccount tools/ci"},
+	} {
+		assert.Equal(t, c.formatted, formatSubject(c.subject, "default"))
+	}
+}
+
+func TestRenderRBAC(t *testing.T) {
+	assert.Equal(t, "No role bindings grant permissions in namespace default.\n", renderRBAC("default", nil, nil, nil))
+
+	binding := func(name string, kind string, role string, subjects ...rbacv1.Subject) rbacv1.RoleBinding {
+		return rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			RoleRef:    rbacv1.RoleRef{Kind: kind, Name: role},
+			Subjects:   subjects,
+		}
+	}
+	bob := rbacv1.Subject{Kind: rbacv1.UserKind, Name: "bob"}
+	alice := rbacv1.Subject{Kind: rbacv1.UserKind, Name: "alice"}
+	rendered := renderRBAC(
+		"default",
+		[]rbacv1.RoleBinding{
+			binding("readers", "Role", "reader", bob, alice),
+			binding("admins", "ClusterRole", "admin", alice),
+			binding("missing", "Role", "gone", bob),
+		},
+		map[string][]rbacv1.PolicyRule{
+			"Role/reader": {{Verbs: []string{"get", "list"}, APIGroups: []string{""}, Resources: []string{"pods"}}},
+		},
+		map[string]error{"ClusterRole/admin": fmt.Errorf("forbidden")},
+	)
+	assert.Regexp(t, `User alice\s+get,list\s+pods\s+RoleBinding/readers \(Role/reader\)`, rendered)
+	assert.Regexp(t, `User bob\s+get,list\s+pods\s+RoleBinding/readers \(Role/reader\)`, rendered)
+	assert.Regexp(t, `User alice\s+\?\s+<unknown: forbidden>\s+RoleBinding/admins \(ClusterRole/admin\)`, rendered)
+	assert.Regexp(t, `User bob\s+\?\s+<missing role>\s+RoleBinding/missing \(Role/gone\)`, rendered)
+	// Rows are sorted by subject.
+	assert.Regexp(t, `(?s)User alice.*User alice.*User bob.*User bob`, rendered)
+}
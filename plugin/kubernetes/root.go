@@ -5,11 +5,11 @@ package kubernetes
 
 import (
 	"context"
+	"fmt"
+	"sync"
 
 	"github.com/puppetlabs/wash/activity"
 	"github.com/puppetlabs/wash/plugin"
-	"k8s.io/apimachinery/pkg/api/resource"
-	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8s "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
@@ -21,10 +21,22 @@ import (
 // Root of the Kubernetes plugin
 type Root struct {
 	plugin.EntryBase
+	watcher *kubeconfigWatcher
+	mux     sync.Mutex
+	// contexts is nil if the contexts need to be (re)loaded
+	contexts []plugin.Entry
+	// impersonations maps context names to the user and groups that the context
+	// acts as.
+	impersonations map[string]clientcmdapi.AuthInfo
+	// contextFilter and namespaceFilter select the contexts and namespaces
+	// that are listed.
+	contextFilter   nameFilter
+	namespaceFilter nameFilter
 }
 
-func createContext(raw clientcmdapi.Config, name string, access clientcmd.ConfigAccess) (plugin.Entry, error) {
-	config := clientcmd.NewNonInteractiveClientConfig(raw, name, &clientcmd.ConfigOverrides{}, access)
+func createContext(raw clientcmdapi.Config, name string, access clientcmd.ConfigAccess, impersonation clientcmdapi.AuthInfo, namespaceFilter nameFilter) (plugin.Entry, error) {
+	overrides := &clientcmd.ConfigOverrides{AuthInfo: impersonation}
+	config := clientcmd.NewNonInteractiveClientConfig(raw, name, overrides, access)
 	cfg, err := config.ClientConfig()
 	if err != nil {
 		return nil, err
@@ -37,14 +49,45 @@ func createContext(raw clientcmdapi.Config, name string, access clientcmd.Config
 	if err != nil {
 		return nil, err
 	}
-	return newK8Context(name, clientset, cfg, defaultns), nil
+	return newK8Context(name, clientset, cfg, defaultns, namespaceFilter), nil
 }
 
 // Init for root
-func (r *Root) Init(map[string]interface{}) error {
+func (r *Root) Init(cfg map[string]interface{}) error {
 	r.EntryBase = plugin.NewEntry("kubernetes")
 	r.DisableDefaultCaching()
 
+	if impersonateI, ok := cfg["impersonate"]; ok {
+		impersonations, err := parseImpersonations(impersonateI)
+		if err != nil {
+			return err
+		}
+		r.impersonations = impersonations
+	}
+	if contextsI, ok := cfg["contexts"]; ok {
+		contextFilter, err := parseNameFilter("contexts", contextsI)
+		if err != nil {
+			return err
+		}
+		r.contextFilter = contextFilter
+	}
+	if namespacesI, ok := cfg["namespaces"]; ok {
+		namespaceFilter, err := parseNameFilter("namespaces", namespacesI)
+		if err != nil {
+			return err
+		}
+		r.namespaceFilter = namespaceFilter
+	}
+
+	watcher, err := newKubeconfigWatcher(r.refresh)
+	if err != nil {
+		// Fallback to reloading the kubeconfig on every List.
+		activity.Warnf(context.Background(), "kubernetes: unable to watch kubeconfig files: %v", err)
+	} else {
+		watcher.watch(clientcmd.NewDefaultClientConfigLoadingRules().GetLoadingPrecedence()...)
+		r.watcher = watcher
+	}
+
 	return nil
 }
 
@@ -65,14 +108,29 @@ func (r *Root) ChildSchemas() []*plugin.EntrySchema {
 
 // WrappedTypes implements plugin.Root#WrappedTypes
 func (r *Root) WrappedTypes() plugin.SchemaMap {
-	return map[interface{}]*plugin.JSONSchema{
-		v1.Time{}:           plugin.TimeSchema(),
-		resource.Quantity{}: plugin.StringSchema(),
-	}
+	return WrappedTypes()
 }
 
-// List returns available contexts.
+// List returns available contexts. The contexts are reloaded whenever the
+// kubeconfig files change.
 func (r *Root) List(ctx context.Context) ([]plugin.Entry, error) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	if r.contexts != nil {
+		return r.contexts, nil
+	}
+
+	contexts, err := r.loadContexts()
+	if err != nil {
+		return nil, err
+	}
+	if r.watcher != nil {
+		r.contexts = contexts
+	}
+	return contexts, nil
+}
+
+func (r *Root) loadContexts() ([]plugin.Entry, error) {
 	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
 	config := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{})
 
@@ -80,23 +138,119 @@ func (r *Root) List(ctx context.Context) ([]plugin.Entry, error) {
 	if err != nil {
 		return nil, err
 	}
+	if r.watcher != nil {
+		r.watcher.watchReferencedFiles(raw)
+	}
 
 	contexts := make([]plugin.Entry, 0)
+	selected := 0
 	for name := range raw.Contexts {
-		ctx, err := createContext(raw, name, config.ConfigAccess())
+		// Excluded contexts are skipped before they're loaded, so that dead
+		// clusters don't slow down listing or log errors.
+		if !r.contextFilter.matches(name) {
+			continue
+		}
+		selected++
+		impersonation := r.impersonations[name]
+		if impersonation.Impersonate != "" {
+			activity.Record(
+				context.Background(),
+				"kubernetes: context %v acts as user %v with groups %v",
+				name,
+				impersonation.Impersonate,
+				impersonation.ImpersonateGroups,
+			)
+		}
+		ctx, err := createContext(raw, name, config.ConfigAccess(), impersonation, r.namespaceFilter)
 		if err != nil {
 			activity.Warnf(context.Background(), "loading context %v failed: %+v", name, err)
 			continue
 		}
 		contexts = append(contexts, ctx)
 	}
+	if selected == 0 && len(raw.Contexts) > 0 {
+		activity.Warnf(context.Background(), "kubernetes: the contexts config doesn't select any of the %v contexts", len(raw.Contexts))
+	}
 
 	return contexts, nil
 }
 
+// parseImpersonations parses the kubernetes.impersonate config, which is an
+// array of {context: <name>, as: <user>, as_groups: [<group>, ...]} objects.
+// Contexts are listed in an array instead of being keys of an object because
+// config keys are case-insensitive but context names aren't.
+func parseImpersonations(impersonateI interface{}) (map[string]clientcmdapi.AuthInfo, error) {
+	impersonate, ok := impersonateI.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("kubernetes.impersonate config must be an array, not %v", impersonateI)
+	}
+
+	impersonations := make(map[string]clientcmdapi.AuthInfo)
+	for _, elem := range impersonate {
+		obj, ok := plugin.ToStringMap(elem)
+		if !ok {
+			return nil, fmt.Errorf("kubernetes.impersonate config must be an array of objects, not %v", impersonate)
+		}
+		name, ok := obj["context"].(string)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("kubernetes.impersonate config entry %v must specify a context", obj)
+		}
+		user, ok := obj["as"].(string)
+		if !ok || user == "" {
+			return nil, fmt.Errorf("kubernetes.impersonate config for context %v must specify the user to act as", name)
+		}
+		var groups []string
+		if groupsI, ok := obj["as_groups"]; ok {
+			groupsArr, ok := groupsI.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("kubernetes.impersonate config for context %v: as_groups must be an array of strings, not %v", name, groupsI)
+			}
+			for _, groupI := range groupsArr {
+				group, ok := groupI.(string)
+				if !ok {
+					return nil, fmt.Errorf("kubernetes.impersonate config for context %v: as_groups must be an array of strings, not %v", name, groupsI)
+				}
+				groups = append(groups, group)
+			}
+		}
+		if _, ok := impersonations[name]; ok {
+			return nil, fmt.Errorf("kubernetes.impersonate config specifies context %v more than once", name)
+		}
+		impersonations[name] = clientcmdapi.AuthInfo{Impersonate: user, ImpersonateGroups: groups}
+	}
+	return impersonations, nil
+}
+
 const rootDescription = `
 This is the Kubernetes plugin root. It lets you interact with Kubernetes resources
 like pods and persistent volume claims.
 
-Kubernetes contexts are extracted from ~/.kube/config.
+Kubernetes contexts are extracted from ~/.kube/config (or the files listed
+in the KUBECONFIG environment variable). These files are watched for changes,
+so new contexts and rotated credentials are picked up automatically.
+
+Contexts can impersonate another user and groups (like kubectl's --as and
+--as-group flags), e.g. to test RBAC rules with reduced privileges. Add
+
+kubernetes:
+  impersonate:
+    - context: my-context
+      as: jane
+      as_groups: [developers]
+
+to Wash’s config file. The context's own credentials must be allowed to
+impersonate the given user and groups.
+
+Large kubeconfigs can be trimmed by selecting the contexts and namespaces that
+are listed with include and exclude globs, e.g.
+
+kubernetes:
+  contexts:
+    include: ["prod-*", staging]
+    exclude: ["*-legacy"]
+  namespaces:
+    exclude: ["kube-*"]
+
+A name is listed if it matches an include glob (or there aren't any) and
+doesn't match an exclude glob. Excluded contexts aren't loaded at all.
 `
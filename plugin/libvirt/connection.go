@@ -0,0 +1,113 @@
+package libvirt
+
+import (
+	"context"
+
+	libvirtClient "github.com/digitalocean/go-libvirt"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// connection is a connection to a libvirt daemon. Each operation opens its own
+// connection, since the daemon may be remote and connections aren't cheap to
+// keep open.
+type connection struct {
+	plugin.EntryBase
+	uri libvirtURI
+}
+
+type connectionMetadata struct {
+	Hostname   string `json:"hostname"`
+	LibVersion uint64 `json:"lib_version"`
+	Version    uint64 `json:"version"`
+}
+
+func newConnection(name string, uri libvirtURI) *connection {
+	c := &connection{
+		EntryBase: plugin.NewEntry(name),
+	}
+	c.uri = uri
+	c.SetPartialMetadata(map[string]string{"uri": uri.driverURI, "transport": uri.transport})
+	return c
+}
+
+func (c *connection) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(c, "connection").
+		SetDescription(connectionDescription).
+		SetMetadataSchema(connectionMetadata{})
+}
+
+func (c *connection) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&domain{}).Schema(),
+	}
+}
+
+// List lists all of the daemon's domains, including inactive ones.
+func (c *connection) List(ctx context.Context) ([]plugin.Entry, error) {
+	l, err := connect(ctx, c.uri)
+	if err != nil {
+		return nil, err
+	}
+	defer disconnect(ctx, l)
+
+	doms, _, err := l.ConnectListAllDomains(1, 0)
+	if err != nil {
+		return nil, err
+	}
+	domains := make([]plugin.Entry, 0, len(doms))
+	for _, dom := range doms {
+		info, err := getDomainInfo(l, dom)
+		if err != nil {
+			return nil, err
+		}
+		domains = append(domains, newDomain(c, info))
+	}
+	activity.Record(ctx, "Listing %v domains on %v", len(domains), c.Name())
+	return domains, nil
+}
+
+// Metadata returns the daemon's hostname and its libvirt and hypervisor
+// versions. Versions are encoded as major * 1,000,000 + minor * 1,000 + release.
+func (c *connection) Metadata(ctx context.Context) (plugin.JSONObject, error) {
+	l, err := connect(ctx, c.uri)
+	if err != nil {
+		return nil, err
+	}
+	defer disconnect(ctx, l)
+
+	var meta connectionMetadata
+	if meta.Hostname, err = l.ConnectGetHostname(); err != nil {
+		return nil, err
+	}
+	if meta.LibVersion, err = l.ConnectGetLibVersion(); err != nil {
+		return nil, err
+	}
+	if meta.Version, err = l.ConnectGetVersion(); err != nil {
+		return nil, err
+	}
+	return plugin.ToJSONObject(meta), nil
+}
+
+// withDomain connects to the daemon and looks up the domain by its UUID, since
+// a domain's ID changes whenever it's started.
+func (c *connection) withDomain(ctx context.Context, uuid libvirtClient.UUID, fn func(*libvirtClient.Libvirt, libvirtClient.Domain) error) error {
+	l, err := connect(ctx, c.uri)
+	if err != nil {
+		return err
+	}
+	defer disconnect(ctx, l)
+
+	dom, err := l.DomainLookupByUUID(uuid)
+	if err != nil {
+		return err
+	}
+	return fn(l, dom)
+}
+
+const connectionDescription = `
+This is a connection to a libvirt daemon. It contains all of the daemon's
+domains, including inactive ones. Its metadata includes the daemon's hostname
+and versions.
+`
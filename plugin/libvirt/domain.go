@@ -0,0 +1,220 @@
+package libvirt
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	libvirtClient "github.com/digitalocean/go-libvirt"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// domain is a libvirt domain, like a KVM virtual machine. Exec runs one of its
+// lifecycle actions.
+type domain struct {
+	plugin.EntryBase
+	conn *connection
+	uuid libvirtClient.UUID
+}
+
+// domainInfo is a domain's partial metadata. Memory is in KiB and CPU time is
+// in nanoseconds. The ID is -1 for inactive domains.
+type domainInfo struct {
+	Name      string `json:"name"`
+	UUID      string `json:"uuid"`
+	ID        int32  `json:"id"`
+	State     string `json:"state"`
+	MaxMemory uint64 `json:"max_memory"`
+	Memory    uint64 `json:"memory"`
+	VCPUs     uint16 `json:"vcpus"`
+	CPUTime   uint64 `json:"cpu_time"`
+
+	uuid libvirtClient.UUID
+}
+
+type domainMetadata struct {
+	domainInfo
+	XML   string                 `json:"xml"`
+	Stats map[string]interface{} `json:"stats"`
+}
+
+var domainStates = map[libvirtClient.DomainState]string{
+	libvirtClient.DomainNostate:     "nostate",
+	libvirtClient.DomainRunning:     "running",
+	libvirtClient.DomainBlocked:     "blocked",
+	libvirtClient.DomainPaused:      "paused",
+	libvirtClient.DomainShutdown:    "shutdown",
+	libvirtClient.DomainShutoff:     "shutoff",
+	libvirtClient.DomainCrashed:     "crashed",
+	libvirtClient.DomainPmsuspended: "pmsuspended",
+}
+
+func getDomainInfo(l *libvirtClient.Libvirt, dom libvirtClient.Domain) (domainInfo, error) {
+	state, maxMem, mem, vcpus, cpuTime, err := l.DomainGetInfo(dom)
+	if err != nil {
+		return domainInfo{}, err
+	}
+	info := domainInfo{
+		Name:      dom.Name,
+		UUID:      formatUUID(dom.UUID),
+		ID:        dom.ID,
+		State:     domainStates[libvirtClient.DomainState(state)],
+		MaxMemory: maxMem,
+		Memory:    mem,
+		VCPUs:     vcpus,
+		CPUTime:   cpuTime,
+		uuid:      dom.UUID,
+	}
+	if info.State == "" {
+		info.State = fmt.Sprint(state)
+	}
+	return info, nil
+}
+
+func newDomain(conn *connection, info domainInfo) *domain {
+	d := &domain{
+		EntryBase: plugin.NewEntry(info.Name),
+	}
+	d.conn = conn
+	d.uuid = info.uuid
+	d.
+		SetPartialMetadata(info).
+		Attributes().
+		SetSize(info.Memory*1024).
+		SetCustom("state", info.State)
+	return d
+}
+
+func (d *domain) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(d, "domain").
+		SetDescription(domainDescription).
+		SetPartialMetadataSchema(domainInfo{}).
+		SetMetadataSchema(domainMetadata{}).
+		AddCustomAttribute("state", plugin.StringAttribute, "The domain's state, e.g. running, paused or shutoff")
+}
+
+// Metadata returns the domain's info, its XML description and its stats.
+func (d *domain) Metadata(ctx context.Context) (plugin.JSONObject, error) {
+	var meta domainMetadata
+	err := d.conn.withDomain(ctx, d.uuid, func(l *libvirtClient.Libvirt, dom libvirtClient.Domain) error {
+		var err error
+		if meta.domainInfo, err = getDomainInfo(l, dom); err != nil {
+			return err
+		}
+		if meta.XML, err = l.DomainGetXMLDesc(dom, 0); err != nil {
+			return err
+		}
+		records, err := l.ConnectGetAllDomainStats([]libvirtClient.Domain{dom}, 0, 0)
+		if err != nil {
+			return err
+		}
+		if len(records) > 0 {
+			meta.Stats = flattenStats(records[0].Params)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return plugin.ToJSONObject(meta), nil
+}
+
+// Stream streams the domain's console. The domain must be running. Consoles
+// only allow one client, so it fails if someone's already connected.
+func (d *domain) Stream(ctx context.Context) (io.ReadCloser, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	l, err := connect(ctx, d.conn.uri)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	dom, err := l.DomainLookupByUUID(d.uuid)
+	if err != nil {
+		disconnect(ctx, l)
+		cancel()
+		return nil, err
+	}
+
+	activity.Record(ctx, "Streaming the console of %v", dom.Name)
+	r, w := io.Pipe()
+	go func() {
+		err := l.DomainOpenConsole(dom, nil, w, uint32(libvirtClient.DomainConsoleSafe))
+		activity.Record(ctx, "Console of %v closed: %v", dom.Name, err)
+		w.CloseWithError(err)
+	}()
+	return plugin.CleanupReader{ReadCloser: r, Cleanup: func() {
+		disconnect(ctx, l)
+		cancel()
+	}}, nil
+}
+
+// Exec runs one of the domain's lifecycle actions: start, shutdown or destroy.
+// Shutdown asks the guest to shut down, and destroy forcibly stops it. An
+// action that fails exits with 1.
+func (d *domain) Exec(ctx context.Context, cmd string, args []string, opts plugin.ExecOptions) (plugin.ExecCommand, error) {
+	var action func(*libvirtClient.Libvirt, libvirtClient.Domain) error
+	switch cmd {
+	case "start":
+		action = func(l *libvirtClient.Libvirt, dom libvirtClient.Domain) error {
+			return l.DomainCreate(dom)
+		}
+	case "shutdown":
+		action = func(l *libvirtClient.Libvirt, dom libvirtClient.Domain) error {
+			return l.DomainShutdown(dom)
+		}
+	case "destroy":
+		action = func(l *libvirtClient.Libvirt, dom libvirtClient.Domain) error {
+			return l.DomainDestroy(dom)
+		}
+	default:
+		return nil, fmt.Errorf("unknown action %v, expected start, shutdown or destroy", cmd)
+	}
+	if len(args) > 0 {
+		return nil, fmt.Errorf("%v doesn't take any arguments", cmd)
+	}
+	activity.Record(ctx, "Running %v on %v", cmd, d.Name())
+
+	execCmd := plugin.NewExecCommand(ctx)
+	go func() {
+		err := d.conn.withDomain(ctx, d.uuid, action)
+		if err != nil {
+			fmt.Fprintln(execCmd.Stderr(), err)
+			execCmd.CloseStreamsWithError(nil)
+			execCmd.SetExitCode(1)
+			return
+		}
+		execCmd.CloseStreamsWithError(nil)
+		execCmd.SetExitCode(0)
+	}()
+	return execCmd, nil
+}
+
+// flattenStats converts a domain's stats to a map of stat names, like
+// cpu.time or block.0.rd.bytes, to their values.
+func flattenStats(params []libvirtClient.TypedParam) map[string]interface{} {
+	stats := make(map[string]interface{}, len(params))
+	for _, param := range params {
+		stats[param.Field] = param.Value.I
+	}
+	return stats
+}
+
+// formatUUID formats a UUID in its canonical 8-4-4-4-12 form.
+func formatUUID(uuid libvirtClient.UUID) string {
+	hex := fmt.Sprintf("%x", uuid[:])
+	return strings.Join([]string{hex[0:8], hex[8:12], hex[12:16], hex[16:20], hex[20:32]}, "-")
+}
+
+const domainDescription = `
+This is a libvirt domain, like a KVM virtual machine. Its metadata includes its
+XML description (in xml) and its stats, like cpu.time and block.0.rd.bytes.
+Its size is its current memory, and streaming it streams its console, e.g.
+  tail -f libvirt/local/web1
+
+Exec runs one of the domain's lifecycle actions: start, shutdown (which asks
+the guest to shut down) or destroy (which forcibly stops it), e.g.
+  wexec libvirt/local/web1 shutdown
+`
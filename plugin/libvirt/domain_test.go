@@ -0,0 +1,26 @@
+package libvirt
+
+import (
+	"testing"
+
+	libvirtClient "github.com/digitalocean/go-libvirt"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatUUID(t *testing.T) {
+	uuid := libvirtClient.UUID{0x4d, 0xea, 0x22, 0xb3, 0x1d, 0x52, 0xd8, 0xf3, 0x27, 0x16, 0x78, 0x2e, 0xe7, 0x75, 0x5a, 0x0c}
+	assert.Equal(t, "4dea22b3-1d52-d8f3-2716-782ee7755a0c", formatUUID(uuid))
+}
+
+func TestFlattenStats(t *testing.T) {
+	stats := flattenStats([]libvirtClient.TypedParam{
+		{Field: "state.state", Value: *libvirtClient.NewTypedParamValueInt(1)},
+		{Field: "cpu.time", Value: *libvirtClient.NewTypedParamValueUllong(1500000000)},
+		{Field: "block.0.name", Value: *libvirtClient.NewTypedParamValueString("vda")},
+	})
+	assert.Equal(t, map[string]interface{}{
+		"state.state":  int32(1),
+		"cpu.time":     uint64(1500000000),
+		"block.0.name": "vda",
+	}, stats)
+}
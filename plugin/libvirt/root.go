@@ -0,0 +1,126 @@
+// Package libvirt presents a filesystem hierarchy for libvirt domains, like KVM
+// virtual machines. Domains expose their XML and stats as metadata, stream their
+// console, and can be started, shut down and destroyed with exec.
+//
+// Connections are listed in the plugin's config as libvirt URIs. The local
+// system daemon (qemu:///system) is used if none are.
+package libvirt
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// Root of the libvirt plugin
+type Root struct {
+	plugin.EntryBase
+	connections []plugin.Entry
+}
+
+type connectionConfig struct {
+	name string
+	uri  string
+}
+
+// Init for root
+func (r *Root) Init(cfg map[string]interface{}) error {
+	var configs []connectionConfig
+	if connectionsI, ok := cfg["connections"]; ok {
+		var err error
+		if configs, err = parseConnections(connectionsI); err != nil {
+			return err
+		}
+	} else if runtime.GOOS == "linux" {
+		configs = []connectionConfig{{name: "local", uri: "qemu:///system"}}
+	}
+
+	for _, config := range configs {
+		uri, err := parseURI(config.uri)
+		if err != nil {
+			return fmt.Errorf("libvirt.connections config for %v has an invalid uri: %v", config.name, err)
+		}
+		r.connections = append(r.connections, newConnection(config.name, uri))
+	}
+
+	r.EntryBase = plugin.NewEntry("libvirt")
+	r.DisableDefaultCaching()
+	return nil
+}
+
+// Schema returns the root's schema
+func (r *Root) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(r, "libvirt").
+		SetDescription(rootDescription).
+		IsSingleton()
+}
+
+// ChildSchemas returns the root's child schema
+func (r *Root) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&connection{}).Schema(),
+	}
+}
+
+// List lists the configured connections.
+func (r *Root) List(ctx context.Context) ([]plugin.Entry, error) {
+	return r.connections, nil
+}
+
+// parseConnections parses the libvirt.connections config, which is an array of
+// {name: <name>, uri: <uri>} objects.
+func parseConnections(connectionsI interface{}) ([]connectionConfig, error) {
+	connections, ok := connectionsI.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("libvirt.connections config must be an array, not %v", connectionsI)
+	}
+
+	var configs []connectionConfig
+	seen := make(map[string]struct{})
+	for _, elem := range connections {
+		obj, ok := plugin.ToStringMap(elem)
+		if !ok {
+			return nil, fmt.Errorf("libvirt.connections config must be an array of objects, not %v", connections)
+		}
+		name, ok := obj["name"].(string)
+		if !ok || name == "" || strings.Contains(name, "/") {
+			return nil, fmt.Errorf("libvirt.connections config entry %v must specify a name that doesn't contain a '/'", obj)
+		}
+		uri, ok := obj["uri"].(string)
+		if !ok || uri == "" {
+			return nil, fmt.Errorf("libvirt.connections config for %v must specify the connection's uri", name)
+		}
+		if _, ok := seen[name]; ok {
+			return nil, fmt.Errorf("libvirt.connections config specifies %v more than once", name)
+		}
+		seen[name] = struct{}{}
+		configs = append(configs, connectionConfig{name: name, uri: uri})
+	}
+	return configs, nil
+}
+
+const rootDescription = `
+This is the libvirt plugin root. It contains the connections in the
+libvirt.connections config, e.g.
+
+libvirt:
+  connections:
+    - name: local
+      uri: qemu:///system
+    - name: kvm1
+      uri: qemu+ssh://admin@kvm1.example.com/system
+    - name: kvm2
+      uri: qemu+tcp://kvm2.example.com/system
+
+qemu+ssh connections are tunneled to the remote daemon's socket over SSH, like
+the hosts plugin's hosts. qemu+tcp connections are unencrypted and connect to
+port 16509 by default. The daemon's socket can be set with the socket
+parameter, e.g. qemu:///session?socket=/run/user/1000/libvirt/libvirt-sock.
+
+If no connections are configured, then the local system daemon is listed as
+'local' on Linux.
+`
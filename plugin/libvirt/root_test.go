@@ -0,0 +1,37 @@
+package libvirt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseConnections(t *testing.T) {
+	configs, err := parseConnections([]interface{}{
+		map[string]interface{}{"name": "Local", "uri": "qemu:///system"},
+		map[interface{}]interface{}{"name": "kvm1", "uri": "qemu+ssh://kvm1/system"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []connectionConfig{
+		{name: "Local", uri: "qemu:///system"},
+		{name: "kvm1", uri: "qemu+ssh://kvm1/system"},
+	}, configs)
+}
+
+func TestParseConnectionsErrors(t *testing.T) {
+	_, err := parseConnections("qemu:///system")
+	assert.EqualError(t, err, "libvirt.connections config must be an array, not qemu:///system")
+
+	_, err = parseConnections([]interface{}{map[string]interface{}{"uri": "qemu:///system"}})
+	assert.EqualError(t, err, "libvirt.connections config entry map[uri:qemu:///system] must specify a name that doesn't contain a '/'")
+
+	_, err = parseConnections([]interface{}{map[string]interface{}{"name": "local"}})
+	assert.EqualError(t, err, "libvirt.connections config for local must specify the connection's uri")
+
+	_, err = parseConnections([]interface{}{
+		map[string]interface{}{"name": "kvm", "uri": "qemu+ssh://a/system"},
+		map[string]interface{}{"name": "kvm", "uri": "qemu+ssh://b/system"},
+	})
+	assert.EqualError(t, err, "libvirt.connections config specifies kvm more than once")
+}
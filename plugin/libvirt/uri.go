@@ -0,0 +1,134 @@
+package libvirt
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	libvirtClient "github.com/digitalocean/go-libvirt"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/transport"
+)
+
+const (
+	defaultSocket = "/var/run/libvirt/libvirt-sock"
+	defaultPort   = "16509"
+	dialTimeout   = 15 * time.Second
+)
+
+// libvirtURI is a parsed libvirt URI, like qemu+ssh://user@host/system. It's
+// split into the daemon's address, which depends on the URI's transport, and
+// the driver URI (qemu:///system) that's opened once connected.
+type libvirtURI struct {
+	driverURI string
+	// transport is unix, ssh or tcp.
+	transport string
+	// socket is the daemon's socket for the unix and ssh transports.
+	socket string
+	// id is the remote host for the ssh transport.
+	id transport.Identity
+	// address is the daemon's host:port for the tcp transport.
+	address string
+}
+
+// parseURI parses a libvirt URI. The transport defaults to unix for local URIs
+// and to tls for remote ones, like libvirt's does. tls isn't supported.
+func parseURI(rawURI string) (libvirtURI, error) {
+	var uri libvirtURI
+	u, err := url.Parse(rawURI)
+	if err != nil {
+		return uri, err
+	}
+	if u.Scheme == "" || u.Opaque != "" {
+		return uri, fmt.Errorf("%v must be driver[+transport]://[user@][host][:port]/path", rawURI)
+	}
+
+	driver := u.Scheme
+	if i := strings.Index(driver, "+"); i >= 0 {
+		driver, uri.transport = driver[:i], driver[i+1:]
+	} else if u.Host == "" {
+		uri.transport = "unix"
+	} else {
+		uri.transport = "tls"
+	}
+	uri.driverURI = driver + "://" + u.Path
+
+	query := u.Query()
+	switch uri.transport {
+	case "unix":
+		if u.Host != "" {
+			return uri, fmt.Errorf("%v can't specify a host for the unix transport", rawURI)
+		}
+		uri.socket = query.Get("socket")
+	case "ssh":
+		if u.Hostname() == "" {
+			return uri, fmt.Errorf("%v must specify a host for the ssh transport", rawURI)
+		}
+		uri.socket = query.Get("socket")
+		uri.id.Host = u.Hostname()
+		uri.id.User = u.User.Username()
+		if port := u.Port(); port != "" {
+			p, err := strconv.ParseUint(port, 10, 16)
+			if err != nil {
+				return uri, fmt.Errorf("%v has an invalid port %v", rawURI, port)
+			}
+			uri.id.Port = uint(p)
+		}
+	case "tcp":
+		if u.Hostname() == "" {
+			return uri, fmt.Errorf("%v must specify a host for the tcp transport", rawURI)
+		}
+		port := u.Port()
+		if port == "" {
+			port = defaultPort
+		}
+		uri.address = net.JoinHostPort(u.Hostname(), port)
+	default:
+		return uri, fmt.Errorf("%v uses the %v transport, but only unix, ssh and tcp are supported", rawURI, uri.transport)
+	}
+	if uri.socket == "" {
+		uri.socket = defaultSocket
+	}
+	return uri, nil
+}
+
+// dialer dials the daemon for go-libvirt. Its Dial doesn't take a context, so
+// the dialer keeps the context that its connection's for.
+type dialer struct {
+	ctx context.Context
+	uri libvirtURI
+}
+
+func (d dialer) Dial() (net.Conn, error) {
+	switch d.uri.transport {
+	case "ssh":
+		return transport.DialSSH(d.ctx, d.uri.id, "unix", d.uri.socket)
+	case "tcp":
+		return net.DialTimeout("tcp", d.uri.address, dialTimeout)
+	default:
+		return net.DialTimeout("unix", d.uri.socket, dialTimeout)
+	}
+}
+
+// connect opens a connection to the URI's daemon. The connection should be
+// closed with Disconnect before ctx is done.
+func connect(ctx context.Context, uri libvirtURI) (*libvirtClient.Libvirt, error) {
+	activity.Record(ctx, "Connecting to %v over %v", uri.driverURI, uri.transport)
+	l := libvirtClient.NewWithDialer(dialer{ctx: ctx, uri: uri})
+	if err := l.ConnectToURI(libvirtClient.ConnectURI(uri.driverURI)); err != nil {
+		return nil, fmt.Errorf("failed to connect to libvirt: %v", err)
+	}
+	return l, nil
+}
+
+// disconnect closes the connection, recording any errors since there's nothing
+// else to do about them.
+func disconnect(ctx context.Context, l *libvirtClient.Libvirt) {
+	if err := l.Disconnect(); err != nil {
+		activity.Record(ctx, "Failed to disconnect from libvirt: %v", err)
+	}
+}
@@ -0,0 +1,46 @@
+package libvirt
+
+import (
+	"testing"
+
+	"github.com/puppetlabs/wash/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseURI(t *testing.T) {
+	uri, err := parseURI("qemu:///system")
+	require.NoError(t, err)
+	assert.Equal(t, libvirtURI{driverURI: "qemu:///system", transport: "unix", socket: defaultSocket}, uri)
+
+	uri, err = parseURI("qemu+unix:///session?socket=/run/user/1000/libvirt/libvirt-sock")
+	require.NoError(t, err)
+	assert.Equal(t, libvirtURI{driverURI: "qemu:///session", transport: "unix", socket: "/run/user/1000/libvirt/libvirt-sock"}, uri)
+
+	uri, err = parseURI("qemu+ssh://admin@kvm1.example.com:2222/system")
+	require.NoError(t, err)
+	assert.Equal(t, libvirtURI{
+		driverURI: "qemu:///system",
+		transport: "ssh",
+		socket:    defaultSocket,
+		id:        transport.Identity{Host: "kvm1.example.com", User: "admin", Port: 2222},
+	}, uri)
+
+	uri, err = parseURI("qemu+tcp://kvm2/system")
+	require.NoError(t, err)
+	assert.Equal(t, libvirtURI{driverURI: "qemu:///system", transport: "tcp", socket: defaultSocket, address: "kvm2:16509"}, uri)
+}
+
+func TestParseURIErrors(t *testing.T) {
+	_, err := parseURI("kvm1")
+	assert.EqualError(t, err, "kvm1 must be driver[+transport]://[user@][host][:port]/path")
+
+	_, err = parseURI("qemu://kvm1/system")
+	assert.EqualError(t, err, "qemu://kvm1/system uses the tls transport, but only unix, ssh and tcp are supported")
+
+	_, err = parseURI("qemu+ssh:///system")
+	assert.EqualError(t, err, "qemu+ssh:///system must specify a host for the ssh transport")
+
+	_, err = parseURI("qemu+unix://kvm1/system")
+	assert.EqualError(t, err, "qemu+unix://kvm1/system can't specify a host for the unix transport")
+}
@@ -0,0 +1,49 @@
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ListCost is a hint of how expensive it is to list an entry's children. Wash
+// uses it to order traversals like `wash find` so that expensive subtrees are
+// walked after cheap ones, which returns most results sooner.
+type ListCost string
+
+// These are the supported list costs
+const (
+	// CheapList is for entries whose children are listed with a single fast
+	// request, like a directory in a local filesystem.
+	CheapList ListCost = "cheap"
+	// ExpensiveList is for entries whose listing is slow, paginated,
+	// rate-limited or billed, like an S3 bucket or a Kubernetes PVC.
+	ExpensiveList ListCost = "expensive"
+)
+
+func (c ListCost) validate() error {
+	switch c {
+	case CheapList, ExpensiveList:
+		return nil
+	default:
+		return fmt.Errorf("unknown list cost %v, must be %v or %v", c, CheapList, ExpensiveList)
+	}
+}
+
+// IsExpensive returns true if c is ExpensiveList. Entries without a list
+// cost aren't expensive.
+func (c ListCost) IsExpensive() bool {
+	return c == ExpensiveList
+}
+
+// UnmarshalJSON unmarshals the list cost JSON.
+func (c *ListCost) UnmarshalJSON(bytes []byte) error {
+	var s string
+	if err := json.Unmarshal(bytes, &s); err != nil {
+		return err
+	}
+	if err := ListCost(s).validate(); err != nil {
+		return err
+	}
+	*c = ListCost(s)
+	return nil
+}
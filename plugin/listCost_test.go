@@ -0,0 +1,25 @@
+package plugin
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetListCost(t *testing.T) {
+	s := NewEntrySchema(newMockEntry("foo"), "foo")
+	assert.Equal(t, ExpensiveList, s.SetListCost(ExpensiveList).ListCost)
+	assert.Panics(t, func() { s.SetListCost("slow") })
+}
+
+func TestListCost_UnmarshalJSON(t *testing.T) {
+	var s EntrySchema
+	require.NoError(t, json.Unmarshal([]byte(`{"label":"foo","list_cost":"cheap"}`), &s.entrySchema))
+	assert.Equal(t, CheapList, s.ListCost)
+	assert.False(t, s.ListCost.IsExpensive())
+
+	err := json.Unmarshal([]byte(`{"label":"foo","list_cost":"slow"}`), &s.entrySchema)
+	assert.EqualError(t, err, "unknown list cost slow, must be cheap or expensive")
+}
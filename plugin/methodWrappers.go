@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"os"
 	"strings"
 	"time"
 
@@ -51,18 +53,23 @@ func Name(e Entry) string {
 /*
 CName returns the entry's canonical name, which is what Wash uses to
 construct the entry's path. The entry's cname is plugin.Name(e), but with
-all '/' characters replaced by a '#' character. CNames are necessary
-because it is possible for entry names to have '/'es in them, which is
-illegal in bourne shells and UNIX-y filesystems.
-
-CNames are unique. CName uniqueness is checked in plugin.CachedList.
-
-NOTE: The '#' character was chosen because it is unlikely to appear in
-a meaningful entry's name. If, however, there's a good chance that an
-entry's name can contain the '#' character, and that two entries can
-have the same cname (e.g. 'foo/bar', 'foo#bar'), then you can use
-e.SetSlashReplacer(<char>) to change the default slash replacer from
-a '#' to <char>.
+all '/', NUL and '%' characters percent-encoded, e.g. 'foo/bar' becomes
+'foo%2Fbar'. CNames are necessary because it is possible for entry names to
+have '/'es in them, which is illegal in bourne shells and UNIX-y filesystems.
+Similarly, the "." and ".." names are escaped as "%2E" and "%2E%2E". Since
+'%' is also encoded, an entry's name can always be recovered from its cname.
+
+CNames are unique. If several siblings have the same cname (e.g. two
+siblings named 'foo', or 'README' and 'readme' on macOS, where cnames that
+only differ by case are also disambiguated), then the others are
+disambiguated by appending "~<n>" to their cnames (e.g. 'foo~1'). Siblings
+are disambiguated by plugin.List. In either case, the entry's original name
+is preserved in its metadata under the plugin.OriginalNameKey key.
+
+NOTE: If an entry's name often contains '/'es, like a branch or a route,
+then you can use e.SetSlashReplacer(<char>) to replace them with <char>
+instead, e.g. 'feature/x' becomes 'feature#x' with e.SetSlashReplacer('#').
+<char> is percent-encoded wherever else it appears in the name.
 */
 func CName(e Entry) string {
 	if len(e.eb().name) == 0 {
@@ -71,12 +78,10 @@ func CName(e Entry) string {
 	// We make the CName a separate function instead of embedding it
 	// in the Entry interface because doing so prevents plugin authors
 	// from overriding it.
-	return strings.Replace(
-		e.eb().name,
-		"/",
-		string(e.eb().slashReplacer),
-		-1,
-	)
+	if e.eb().cname != "" {
+		return e.eb().cname
+	}
+	return EscapeName(e.eb().name, e.eb().slashReplacer)
 }
 
 // ID returns the entry's ID, which is just its path rooted at Wash's mountpoint.
@@ -114,7 +119,12 @@ func Schema(e Entry) (*EntrySchema, error) {
 //
 // Note that List's results could be cached.
 func List(ctx context.Context, p Parent) (*EntryMap, error) {
-	return cachedList(ctx, p)
+	ctx = withPluginContext(ctx, p)
+	entries, err := cachedList(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	return withoutTrashedEntries(p.eb().id, entries), nil
 }
 
 // Read reads up to size bits of the entry's content starting at the given offset.
@@ -126,6 +136,7 @@ func List(ctx context.Context, p Parent) (*EntryMap, error) {
 //
 // Note that Read is thread-safe.
 func Read(ctx context.Context, e Entry, size int64, offset int64) (data []byte, err error) {
+	ctx = withPluginContext(ctx, e)
 	if !ReadAction().IsSupportedOn(e) {
 		panic("plugin.Read called on a non-readable entry")
 	}
@@ -145,7 +156,7 @@ func Read(ctx context.Context, e Entry, size int64, offset int64) (data []byte,
 		}
 		minSize := size + offset
 		if contentSize < minSize {
-			size = contentSize
+			size = contentSize - offset
 			err = io.EOF
 		}
 	} else if ReadAction().signature(e) == BlockReadableSignature {
@@ -168,6 +179,7 @@ func Read(ctx context.Context, e Entry, size int64, offset int64) (data []byte,
 
 // Size returns the size of readable data for an entry. It may call Read to do so.
 func Size(ctx context.Context, e Entry) (uint64, error) {
+	ctx = withPluginContext(ctx, e)
 	if attr := e.eb().attributes; attr.HasSize() {
 		return attr.Size(), nil
 	}
@@ -188,31 +200,104 @@ func Size(ctx context.Context, e Entry) (uint64, error) {
 // entry didn't specify any partial metadata, then this returns Attributes(e).ToMap()
 // to enforce the "attributes are a subset of the partial metadata" invariant.
 func PartialMetadata(e Entry) JSONObject {
-	return e.eb().partialMetadata()
+	return withOriginalName(e, e.eb().partialMetadata())
 }
 
 // Metadata returns the entry's metadata. Note that Metadata's results could be cached.
 func Metadata(ctx context.Context, e Entry) (JSONObject, error) {
-	return cachedMetadata(ctx, e)
+	ctx = withPluginContext(ctx, e)
+	meta, err := cachedMetadata(ctx, e)
+	if err != nil {
+		return nil, err
+	}
+	return withOriginalName(e, meta), nil
 }
 
 // Exec execs the command on the given entry.
-func Exec(ctx context.Context, e Execable, cmd string, args []string, opts ExecOptions) (ExecCommand, error) {
-	return e.Exec(ctx, cmd, args, opts)
+func Exec(ctx context.Context, e Execable, cmd string, args []string, opts ExecOptions) (execCmd ExecCommand, err error) {
+	ctx = withPluginContext(ctx, e)
+	done := recordCall(e, "Exec")
+	defer func() { done(err) }()
+	defer recoverPanic(ctx, e, "Exec", &err)
+	execCmd, err = e.Exec(ctx, cmd, args, opts)
+	if err != nil {
+		return nil, err
+	}
+	activity.PinJournal(ctx)
+	trackUntilDone(ctx, e, "Exec")
+	return recordExec(ctx, e, execCmd, cmd, args, opts), nil
 }
 
 // Stream streams the entry's content for updates.
-func Stream(ctx context.Context, s Streamable) (io.ReadCloser, error) {
-	return s.Stream(ctx)
+func Stream(ctx context.Context, s Streamable) (rdr io.ReadCloser, err error) {
+	ctx = withPluginContext(ctx, s)
+	done := recordCall(s, "Stream")
+	defer func() { done(err) }()
+	defer recoverPanic(ctx, s, "Stream", &err)
+	rdr, err = s.Stream(ctx)
+	if err != nil {
+		return nil, err
+	}
+	activity.PinJournal(ctx)
+	trackUntilDone(ctx, s, "Stream")
+	return rdr, nil
+}
+
+// WithStreamSince returns a context that asks Stream to also stream the updates
+// since the given time.
+func WithStreamSince(ctx context.Context, since time.Time) context.Context {
+	return context.WithValue(ctx, streamSince, since)
+}
+
+// StreamSince returns the time set by WithStreamSince. Its second return value
+// is false if no time was set, in which case only new updates should be streamed.
+func StreamSince(ctx context.Context) (time.Time, bool) {
+	since, ok := ctx.Value(streamSince).(time.Time)
+	return since, ok
 }
 
 // Write sends the supplied buffer to the entry.
-func Write(ctx context.Context, a Writable, b []byte) error {
+func Write(ctx context.Context, a Writable, b []byte) (err error) {
+	ctx = withPluginContext(ctx, a)
+	done := recordCall(a, "Write")
+	defer func() { done(err) }()
+	defer recoverPanic(ctx, a, "Write", &err)
 	return a.Write(ctx, b)
 }
 
+// WriteStream writes the content read from r to the entry. StreamWritable
+// entries consume the content as it's read. Otherwise, the entire content is
+// buffered before it's written. size is the content's size, or -1 if it's
+// unknown. The entry's cached data is cleared once the write succeeds so that
+// fresh data's loaded when it's next needed.
+func WriteStream(ctx context.Context, a Writable, r io.Reader, size int64) (err error) {
+	ctx = withPluginContext(ctx, a)
+	done := recordCall(a, "Write")
+	defer func() { done(err) }()
+	defer recoverPanic(ctx, a, "Write", &err)
+	if sw, ok := a.(StreamWritable); ok {
+		err = sw.WriteStream(ctx, r, size)
+	} else {
+		var data []byte
+		if data, err = ioutil.ReadAll(r); err == nil {
+			err = a.Write(ctx, data)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if id := a.eb().id; id != "" {
+		ClearCacheFor(id, true)
+	}
+	return nil
+}
+
 // Signal signals the entry with the specified signal
-func Signal(ctx context.Context, s Signalable, signal string) error {
+func Signal(ctx context.Context, s Signalable, signal string) (err error) {
+	ctx = withPluginContext(ctx, s)
+	done := recordCall(s, "Signal")
+	defer func() { done(err) }()
+	defer recoverPanic(ctx, s, "Signal", &err)
 	// Signals are case-insensitive
 	signal = strings.ToLower(signal)
 
@@ -261,8 +346,55 @@ func Signal(ctx context.Context, s Signalable, signal string) error {
 	return nil
 }
 
-// Delete deletes the given entry.
+// Create creates a new child of the given parent with the given permissions.
+// The child's ID is set so that it can be written to before it's listed.
+func Create(ctx context.Context, p Creatable, name string, mode os.FileMode) (child Writable, err error) {
+	ctx = withPluginContext(ctx, p)
+	done := recordCall(p, "Create")
+	defer func() { done(err) }()
+	defer recoverPanic(ctx, p, "Create", &err)
+	child, err = p.Create(ctx, name, mode)
+	if err != nil {
+		return nil, err
+	}
+	setChildID(p.eb().id, child)
+	return child, nil
+}
+
+// CreateDir creates a new child directory of the given parent. The parent's
+// cached data is cleared so that the new directory is listed.
+func CreateDir(ctx context.Context, p DirCreatable, name string) (child Parent, err error) {
+	ctx = withPluginContext(ctx, p)
+	done := recordCall(p, "CreateDir")
+	defer func() { done(err) }()
+	defer recoverPanic(ctx, p, "CreateDir", &err)
+	child, err = p.CreateDir(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	setChildID(p.eb().id, child)
+	ClearCacheFor(p.eb().id, false)
+	return child, nil
+}
+
+// Delete deletes the given entry. If the trash is enabled for the entry's plugin,
+// then the entry's moved to the trash instead and Delete returns false, since
+// the entry will be deleted when its retention period expires. See TrashOptions.
 func Delete(ctx context.Context, d Deletable) (deleted bool, err error) {
+	ctx = withPluginContext(ctx, d)
+	if trashEnabledFor(d) {
+		moveToTrash(ctx, d)
+		return false, nil
+	}
+	return deleteEntry(ctx, d)
+}
+
+// deleteEntry calls the entry's Delete. It's also used to delete trashed entries.
+func deleteEntry(ctx context.Context, d Deletable) (deleted bool, err error) {
+	ctx = withPluginContext(ctx, d)
+	done := recordCall(d, "Delete")
+	defer func() { done(err) }()
+	defer recoverPanic(ctx, d, "Delete", &err)
 	deleted, err = d.Delete(ctx)
 	if err != nil {
 		return
@@ -282,7 +414,7 @@ func Delete(ctx context.Context, d Deletable) (deleted bool, err error) {
 		// result.
 		parentID, cname := splitID(d.eb().id)
 		listOpName := defaultOpCodeToNameMap[ListOp]
-		entries, _ := cache.Get(listOpName, parentID)
+		entries, _ := cache.Get(cacheCategory(ctx, listOpName), parentID)
 		if entries != nil {
 			entries.(*EntryMap).Delete(cname)
 		}
@@ -290,3 +422,29 @@ func Delete(ctx context.Context, d Deletable) (deleted bool, err error) {
 
 	return
 }
+
+// Rename renames the given entry to newName under newParent. Entries can only be
+// moved within their plugin. Once the entry's renamed, its cached data and both
+// parents' cached lists are cleared.
+func Rename(ctx context.Context, r Renamable, newParent Parent, newName string) (err error) {
+	ctx = withPluginContext(ctx, r)
+	done := recordCall(r, "Rename")
+	defer func() { done(err) }()
+	defer recoverPanic(ctx, r, "Rename", &err)
+
+	if pluginName(r) != pluginName(newParent) {
+		return InvalidInputErr{fmt.Sprintf("%v cannot be moved to %v because it's in a different plugin", ID(r), ID(newParent))}
+	}
+	if strings.HasPrefix(ID(newParent)+"/", ID(r)+"/") {
+		return InvalidInputErr{fmt.Sprintf("%v cannot be moved into itself", ID(r))}
+	}
+	if err = r.Rename(ctx, newParent, newName); err != nil {
+		return
+	}
+
+	// Clearing the ancestor lists ensures that prefetched entries, like the files
+	// in a volume, are re-listed too.
+	ClearCacheFor(r.eb().id, true)
+	ClearCacheFor(newParent.eb().id, true)
+	return
+}
@@ -1,13 +1,16 @@
 package plugin
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
+	"os"
 	"regexp"
 	"testing"
 	"time"
 
+	"github.com/puppetlabs/wash/activity"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
@@ -17,6 +20,12 @@ type MethodWrappersTestSuite struct {
 	cache *cacheTestsMockCache
 }
 
+// newPluginContext returns a context that already identifies a plugin so that the
+// method wrappers pass it through to the entry as-is.
+func newPluginContext() context.Context {
+	return activity.WithPlugin(context.Background(), "foo")
+}
+
 func (suite *MethodWrappersTestSuite) SetupTest() {
 	suite.cache = &cacheTestsMockCache{}
 	SetTestCache(suite.cache)
@@ -34,7 +43,7 @@ func (suite *MethodWrappersTestSuite) TestName() {
 
 func (suite *MethodWrappersTestSuite) TestCName() {
 	e := newMethodWrappersTestsMockEntry("foo/bar/baz")
-	suite.Equal("foo#bar#baz", CName(e))
+	suite.Equal("foo%2Fbar%2Fbaz", CName(e))
 
 	e.SetSlashReplacer(':')
 	suite.Equal("foo:bar:baz", CName(e))
@@ -150,7 +159,7 @@ func (suite *MethodWrappersTestSuite) TestRead_ReturnsCachedReadError() {
 	e.DisableDefaultCaching()
 	e.SetTestID("/foo")
 
-	ctx := context.Background()
+	ctx := newPluginContext()
 	expectedErr := fmt.Errorf("an error")
 	e.On("Read", ctx).Return([]byte{}, expectedErr)
 
@@ -175,7 +184,7 @@ func (suite *MethodWrappersTestSuite) TestRead_ReturnsContentReadError() {
 	e.DisableDefaultCaching()
 	e.SetTestID("/foo")
 
-	ctx := context.Background()
+	ctx := newPluginContext()
 	expectedErr := fmt.Errorf("an error")
 	e.On("Read", ctx, int64(10), int64(0)).Return([]byte{}, expectedErr)
 
@@ -191,7 +200,7 @@ func (suite *MethodWrappersTestSuite) TestRead_PluginAPIReturnsMoreThanTheReques
 	e.DisableDefaultCaching()
 	e.SetTestID("/foo")
 
-	ctx := context.Background()
+	ctx := newPluginContext()
 	e.On("Read", ctx, int64(1), int64(0)).Return([]byte("content"), nil)
 
 	_, err := Read(ctx, e, 1, 0)
@@ -203,7 +212,7 @@ func (suite *MethodWrappersTestSuite) TestRead_ReadsTheEntryContent() {
 	e.DisableDefaultCaching()
 	e.SetTestID("/foo")
 
-	ctx := context.Background()
+	ctx := newPluginContext()
 	e.On("Read", ctx).Return([]byte("some raw content"), nil).Once()
 
 	rawContent, err := Read(ctx, e, 2, 1)
@@ -223,7 +232,7 @@ func (suite *MethodWrappersTestSuite) TestRead_EntryHasSizeAttribute() {
 	e.SetTestID("/foo")
 	e.Attributes().SetSize(uint64(contentSize))
 
-	ctx := context.Background()
+	ctx := newPluginContext()
 
 	// Test that out-of-bounds offset does the right thing.
 	data, err := Read(ctx, e, 0, contentSize)
@@ -245,6 +254,8 @@ func (suite *MethodWrappersTestSuite) TestRead_EntryHasSizeAttribute() {
 		testCase{contentSize - 1, 0, contentSize - 1},
 		// Test with an out-of-bounds size
 		testCase{contentSize + 1, 0, contentSize},
+		// Test with an out-of-bounds size at a non-zero offset
+		testCase{contentSize, 1, contentSize - 1},
 	}
 	for _, testCase := range testCases {
 		e.On("Read", ctx, testCase.expectedSize, testCase.offset).Return([]byte("success"), nil).Once()
@@ -259,7 +270,7 @@ func (suite *MethodWrappersTestSuite) TestRead_EntryHasSizeAttribute() {
 }
 
 func (suite *MethodWrappersTestSuite) TestSize() {
-	ctx := context.Background()
+	ctx := newPluginContext()
 
 	basic := newMockEntry("/mock")
 	size, err := Size(ctx, basic)
@@ -286,7 +297,7 @@ func (suite *MethodWrappersTestSuite) TestSize() {
 }
 
 func (suite *MethodWrappersTestSuite) TestWrite() {
-	ctx := context.Background()
+	ctx := newPluginContext()
 	data := []byte("something")
 
 	writable := newMethodWrappersTestsMockEntry("/mock")
@@ -296,8 +307,51 @@ func (suite *MethodWrappersTestSuite) TestWrite() {
 	writable.AssertExpectations(suite.T())
 }
 
+func (suite *MethodWrappersTestSuite) TestWrite_RecoversPanic() {
+	ctx := newPluginContext()
+	data := []byte("something")
+
+	writable := newMethodWrappersTestsMockEntry("/mock")
+	writable.SetTestID("/mock")
+	writable.On("Write", ctx, data).Run(func(mock.Arguments) { panic("boom") }).Once()
+	err := Write(ctx, writable, data)
+	suite.EqualError(err, "Write on /mock panicked: boom")
+	writable.AssertExpectations(suite.T())
+}
+
+func (suite *MethodWrappersTestSuite) TestWriteStream() {
+	ctx := newPluginContext()
+	data := []byte("something")
+	// Successful writes clear the entry's cached data.
+	suite.cache.On("Get", mock.Anything, mock.Anything).Return(nil, nil)
+	suite.cache.On("Delete", mock.Anything).Return([]string{})
+
+	writable := newMethodWrappersTestsMockEntry("/mock")
+	writable.On("Write", ctx, data).Return(nil).Once()
+	err := WriteStream(ctx, writable, bytes.NewReader(data), int64(len(data)))
+	suite.NoError(err)
+	writable.AssertExpectations(suite.T())
+
+	streamWritable := &copyTestsStreamWritableEntry{copyTestsWritableEntry: copyTestsWritableEntry{EntryBase: NewEntry("dst")}}
+	err = WriteStream(ctx, streamWritable, bytes.NewReader(data), -1)
+	suite.NoError(err)
+	suite.Equal("something", string(streamWritable.content))
+	suite.Equal(int64(-1), streamWritable.size)
+}
+
+func (suite *MethodWrappersTestSuite) TestStreamSince() {
+	ctx := newPluginContext()
+	_, ok := StreamSince(ctx)
+	suite.False(ok)
+
+	since := time.Now().Add(-10 * time.Minute)
+	actual, ok := StreamSince(WithStreamSince(ctx, since))
+	suite.True(ok)
+	suite.Equal(since, actual)
+}
+
 func (suite *MethodWrappersTestSuite) TestSignal_ReturnsSignalError() {
-	ctx := context.Background()
+	ctx := newPluginContext()
 	e := newMethodWrappersTestsMockEntry("foo")
 
 	expectedErr := fmt.Errorf("an error")
@@ -310,7 +364,7 @@ func (suite *MethodWrappersTestSuite) TestSignal_ReturnsSignalError() {
 }
 
 func (suite *MethodWrappersTestSuite) TestSignal_SendsSignalAndUpdatesCache() {
-	ctx := context.Background()
+	ctx := newPluginContext()
 	e := newMethodWrappersTestsMockEntry("bar")
 	e.SetTestID("/foo/bar")
 
@@ -330,7 +384,7 @@ func (suite *MethodWrappersTestSuite) TestSignal_SendsSignalAndUpdatesCache() {
 }
 
 func (suite *MethodWrappersTestSuite) TestSignal_SchemaKnown_ReturnsInvalidInputErrForInvalidSignal() {
-	ctx := context.Background()
+	ctx := newPluginContext()
 	e := newMethodWrappersTestsMockEntry("foo")
 
 	schema := &EntrySchema{
@@ -366,8 +420,47 @@ func (suite *MethodWrappersTestSuite) TestSignal_SchemaKnown_ReturnsInvalidInput
 	suite.Regexp("invalid.*signal.*invalid_signal.*start.*stop.*linux", err)
 }
 
+type methodWrappersTestsMockCreatable struct {
+	*methodWrappersTestsMockEntry
+}
+
+func (m methodWrappersTestsMockCreatable) ChildSchemas() []*EntrySchema {
+	return nil
+}
+
+func (m methodWrappersTestsMockCreatable) Create(ctx context.Context, name string, _ os.FileMode) (Writable, error) {
+	args := m.Called(ctx, name)
+	return args.Get(0).(Writable), args.Error(1)
+}
+
+func (suite *MethodWrappersTestSuite) TestCreate_SetsChildID() {
+	ctx := newPluginContext()
+	parent := methodWrappersTestsMockCreatable{newMethodWrappersTestsMockEntry("foo")}
+	parent.SetTestID("/foo")
+	child := newMethodWrappersTestsMockEntry("bar/baz")
+	parent.On("Create", ctx, "bar/baz").Return(child, nil)
+
+	created, err := Create(ctx, parent, "bar/baz", 0644)
+	if suite.NoError(err) {
+		suite.Equal(child, created)
+		suite.Equal("/foo/bar%2Fbaz", ID(created))
+	}
+}
+
+func (suite *MethodWrappersTestSuite) TestCreate_ReturnsCreateError() {
+	ctx := newPluginContext()
+	parent := methodWrappersTestsMockCreatable{newMethodWrappersTestsMockEntry("foo")}
+	parent.SetTestID("/foo")
+
+	expectedErr := fmt.Errorf("an error")
+	parent.On("Create", ctx, "bar").Return(&methodWrappersTestsMockEntry{}, expectedErr)
+
+	_, err := Create(ctx, parent, "bar", 0644)
+	suite.Equal(expectedErr, err)
+}
+
 func (suite *MethodWrappersTestSuite) TestDelete_ReturnsDeleteError() {
-	ctx := context.Background()
+	ctx := newPluginContext()
 	e := newMethodWrappersTestsMockEntry("foo")
 
 	expectedErr := fmt.Errorf("an error")
@@ -448,3 +541,63 @@ func (suite *MethodWrappersTestSuite) TestDelete_DeletedEntry_NoCachedListResult
 func TestMethodWrappers(t *testing.T) {
 	suite.Run(t, new(MethodWrappersTestSuite))
 }
+
+type methodWrappersTestsMockRenamable struct {
+	*methodWrappersTestsMockEntry
+}
+
+func (m methodWrappersTestsMockRenamable) Rename(ctx context.Context, newParent Parent, newName string) error {
+	args := m.Called(ctx, newParent, newName)
+	return args.Error(0)
+}
+
+func (suite *MethodWrappersTestSuite) TestRename_RenamesAndUpdatesCache() {
+	ctx := newPluginContext()
+	e := methodWrappersTestsMockRenamable{newMethodWrappersTestsMockEntry("bar")}
+	e.SetTestID("/foo/bar")
+	newParent := methodWrappersTestsMockCreatable{newMethodWrappersTestsMockEntry("baz")}
+	newParent.SetTestID("/foo/baz")
+	e.On("Rename", ctx, newParent, "qux").Return(nil)
+
+	suite.cache.On("Get", mock.Anything, mock.Anything).Return(nil, nil)
+	suite.cache.On("Delete", allOpKeysIncludingChildrenRegex("/foo/bar")).Return([]string{}).Once()
+	suite.cache.On("Delete", allOpKeysIncludingChildrenRegex("/foo/baz")).Return([]string{}).Once()
+
+	err := Rename(ctx, e, newParent, "qux")
+	if suite.NoError(err) {
+		e.AssertExpectations(suite.T())
+		suite.cache.AssertExpectations(suite.T())
+	}
+}
+
+func (suite *MethodWrappersTestSuite) TestRename_ReturnsInvalidInputErrForOtherPlugins() {
+	ctx := newPluginContext()
+	e := methodWrappersTestsMockRenamable{newMethodWrappersTestsMockEntry("bar")}
+	e.SetTestID("/foo/bar")
+	newParent := methodWrappersTestsMockCreatable{newMethodWrappersTestsMockEntry("baz")}
+	newParent.SetTestID("/other/baz")
+
+	err := Rename(ctx, e, newParent, "qux")
+	suite.True(IsInvalidInputErr(err))
+	suite.Regexp("different plugin", err)
+
+	newParent.SetTestID("/foo/bar/baz")
+	err = Rename(ctx, e, newParent, "qux")
+	suite.True(IsInvalidInputErr(err))
+	suite.Regexp("into itself", err)
+	e.AssertNotCalled(suite.T(), "Rename", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *MethodWrappersTestSuite) TestRename_ReturnsRenameError() {
+	ctx := newPluginContext()
+	e := methodWrappersTestsMockRenamable{newMethodWrappersTestsMockEntry("bar")}
+	e.SetTestID("/foo/bar")
+	newParent := methodWrappersTestsMockCreatable{newMethodWrappersTestsMockEntry("baz")}
+	newParent.SetTestID("/foo/baz")
+
+	expectedErr := fmt.Errorf("an error")
+	e.On("Rename", ctx, newParent, "qux").Return(expectedErr)
+
+	err := Rename(ctx, e, newParent, "qux")
+	suite.Equal(expectedErr, err)
+}
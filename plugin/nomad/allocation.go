@@ -0,0 +1,60 @@
+package nomad
+
+import (
+	"context"
+	"time"
+
+	nomad "github.com/hashicorp/nomad/api"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// allocation is an allocation of a Nomad job's task group. It lists the
+// allocation's tasks.
+type allocation struct {
+	plugin.EntryBase
+	client *nomad.Client
+	stub   *nomad.AllocationListStub
+}
+
+func newAllocation(client *nomad.Client, stub *nomad.AllocationListStub) *allocation {
+	a := &allocation{
+		EntryBase: plugin.NewEntry(stub.ID),
+	}
+	a.client = client
+	a.stub = stub
+	// The tasks' states are fetched when the job's allocations are listed.
+	a.DisableCachingFor(plugin.ListOp)
+
+	a.
+		SetPartialMetadata(stub).
+		Attributes().
+		SetCrtime(time.Unix(0, stub.CreateTime)).
+		SetMtime(time.Unix(0, stub.ModifyTime))
+	return a
+}
+
+func (a *allocation) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(a, "allocation").
+		SetPartialMetadataSchema(nomad.AllocationListStub{})
+}
+
+func (a *allocation) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&task{}).Schema(),
+	}
+}
+
+func (a *allocation) List(ctx context.Context) ([]plugin.Entry, error) {
+	// Logs and exec only need the allocation's ID and node.
+	alloc := &nomad.Allocation{
+		ID:        a.stub.ID,
+		Namespace: a.stub.Namespace,
+		NodeID:    a.stub.NodeID,
+	}
+	tasks := make([]plugin.Entry, 0, len(a.stub.TaskStates))
+	for name, state := range a.stub.TaskStates {
+		tasks = append(tasks, newTask(a.client, alloc, name, state))
+	}
+	return tasks, nil
+}
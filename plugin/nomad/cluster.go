@@ -0,0 +1,103 @@
+package nomad
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+
+	nomad "github.com/hashicorp/nomad/api"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// cluster is a Nomad cluster. It lists the cluster's jobs.
+type cluster struct {
+	plugin.EntryBase
+	client *nomad.Client
+}
+
+func newCluster(config clusterConfig) (*cluster, error) {
+	nomadConfig := nomad.DefaultConfig()
+	nomadConfig.Address = config.address
+	if config.token != "" {
+		nomadConfig.SecretID = config.token
+	}
+	if config.region != "" {
+		nomadConfig.Region = config.region
+	}
+	if config.namespace != "" {
+		nomadConfig.Namespace = config.namespace
+	}
+	httpClient, err := newHTTPClient(nomadConfig.TLSConfig)
+	if err != nil {
+		return nil, err
+	}
+	nomadConfig.HttpClient = httpClient
+	client, err := nomad.NewClient(nomadConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &cluster{
+		EntryBase: plugin.NewEntry(config.name),
+	}
+	c.client = client
+	c.SetPartialMetadata(map[string]string{
+		"address":   nomadConfig.Address,
+		"region":    nomadConfig.Region,
+		"namespace": nomadConfig.Namespace,
+	})
+	return c, nil
+}
+
+// newHTTPClient returns a client that sends requests through the shared
+// transport with the NOMAD_* TLS settings applied on top of it. The default
+// client uses its own transport, which ignores the http config.
+func newHTTPClient(tlsConfig *nomad.TLSConfig) (*http.Client, error) {
+	transport := plugin.HTTPTransport()
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	client := &http.Client{Transport: transport}
+	// ConfigureTLS replaces the root CAs even if the TLS settings don't specify
+	// any, so keep the shared ones in that case.
+	rootCAs := transport.TLSClientConfig.RootCAs
+	if err := nomad.ConfigureTLS(client, tlsConfig); err != nil {
+		return nil, err
+	}
+	if tlsConfig.CACert == "" && tlsConfig.CAPath == "" && len(tlsConfig.CACertPEM) == 0 {
+		transport.TLSClientConfig.RootCAs = rootCAs
+	}
+	return client, nil
+}
+
+func (c *cluster) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(c, "cluster").
+		SetDescription(clusterDescription)
+}
+
+func (c *cluster) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&job{}).Schema(),
+	}
+}
+
+func (c *cluster) List(ctx context.Context) ([]plugin.Entry, error) {
+	stubs, _, err := c.client.Jobs().List(nil)
+	if err != nil {
+		return nil, err
+	}
+	activity.Record(ctx, "Listing %v jobs in %v", len(stubs), c.Name())
+
+	jobs := make([]plugin.Entry, len(stubs))
+	for i, stub := range stubs {
+		jobs[i] = newJob(c.client, stub)
+	}
+	return jobs, nil
+}
+
+const clusterDescription = `
+This is a Nomad cluster. It contains the cluster's jobs, including the jobs
+that are dead.
+`
@@ -0,0 +1,54 @@
+package nomad
+
+import (
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	nomad "github.com/hashicorp/nomad/api"
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCluster_UsesSharedProxy(t *testing.T) {
+	var proxiedHost string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxiedHost = r.URL.Host
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer proxy.Close()
+	require.NoError(t, plugin.SetHTTPOptions(plugin.HTTPOptions{Proxy: proxy.URL}))
+	defer func() { _ = plugin.SetHTTPOptions(plugin.HTTPOptions{}) }()
+
+	c, err := newCluster(clusterConfig{name: "prod", address: "http://nomad.invalid:4646"})
+	require.NoError(t, err)
+	_, _, err = c.client.Jobs().List(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "nomad.invalid:4646", proxiedHost)
+}
+
+func TestNewHTTPClient_UsesSharedCABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	f, err := ioutil.TempFile("", "wash-nomad-test")
+	require.NoError(t, err)
+	defer os.Remove(f.Name())
+	_, err = f.Write(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	require.NoError(t, plugin.SetHTTPOptions(plugin.HTTPOptions{CABundle: f.Name()}))
+	defer func() { _ = plugin.SetHTTPOptions(plugin.HTTPOptions{}) }()
+
+	// The shared CAs are kept when the NOMAD_* TLS settings don't specify any.
+	client, err := newHTTPClient(&nomad.TLSConfig{})
+	require.NoError(t, err)
+	resp, err := client.Get(server.URL)
+	if assert.NoError(t, err) {
+		resp.Body.Close()
+	}
+}
@@ -0,0 +1,73 @@
+package nomad
+
+import (
+	"context"
+	"time"
+
+	nomad "github.com/hashicorp/nomad/api"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// job is a Nomad job. It lists the job's allocations.
+type job struct {
+	plugin.EntryBase
+	client *nomad.Client
+	id     string
+}
+
+func newJob(client *nomad.Client, stub *nomad.JobListStub) *job {
+	j := &job{
+		EntryBase: plugin.NewEntry(stub.ID),
+	}
+	j.client = client
+	j.id = stub.ID
+
+	submitTime := time.Unix(0, stub.SubmitTime)
+	j.
+		SetPartialMetadata(stub).
+		Attributes().
+		SetCrtime(submitTime).
+		SetMtime(submitTime)
+	return j
+}
+
+func (j *job) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(j, "job").
+		SetDescription(jobDescription).
+		SetPartialMetadataSchema(nomad.JobListStub{}).
+		SetMetadataSchema(nomad.Job{})
+}
+
+func (j *job) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&allocation{}).Schema(),
+	}
+}
+
+func (j *job) List(ctx context.Context) ([]plugin.Entry, error) {
+	stubs, _, err := j.client.Jobs().Allocations(j.id, false, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	allocs := make([]plugin.Entry, len(stubs))
+	for i, stub := range stubs {
+		allocs[i] = newAllocation(j.client, stub)
+	}
+	return allocs, nil
+}
+
+// Metadata returns the job's spec.
+func (j *job) Metadata(ctx context.Context) (plugin.JSONObject, error) {
+	spec, _, err := j.client.Jobs().Info(j.id, nil)
+	if err != nil {
+		return nil, err
+	}
+	return plugin.ToJSONObject(spec), nil
+}
+
+const jobDescription = `
+This is a Nomad job. Its metadata is the job's spec, and it contains the job's
+current allocations.
+`
@@ -0,0 +1,64 @@
+package nomad
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCluster(t *testing.T, handler http.HandlerFunc) (*cluster, func()) {
+	server := httptest.NewServer(handler)
+	c, err := newCluster(clusterConfig{name: "test", address: server.URL})
+	if err != nil {
+		server.Close()
+		require.NoError(t, err)
+	}
+	return c, server.Close
+}
+
+func TestJob(t *testing.T) {
+	c, closeServer := newTestCluster(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/jobs":
+			_, _ = w.Write([]byte(`[{"ID": "web", "Type": "service", "Status": "running", "SubmitTime": 1600000000000000000}]`))
+		case "/v1/job/web/allocations":
+			_, _ = w.Write([]byte(`[{"ID": "1a2b", "NodeID": "n1", "TaskGroup": "web", "TaskStates": {"nginx": {"State": "running"}, "sidecar": {"State": "dead"}}}]`))
+		case "/v1/job/web":
+			_, _ = w.Write([]byte(`{"ID": "web", "Type": "service", "TaskGroups": [{"Name": "web"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer closeServer()
+
+	ctx := context.Background()
+	jobs, err := c.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	j := jobs[0].(*job)
+	assert.Equal(t, "web", j.Name())
+	assert.Equal(t, int64(1600000000), j.Attributes().Crtime().Unix())
+
+	meta, err := j.Metadata(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "service", meta["Type"])
+
+	allocs, err := j.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, allocs, 1)
+	assert.Equal(t, "1a2b", allocs[0].(*allocation).Name())
+
+	tasks, err := allocs[0].(plugin.Parent).List(ctx)
+	require.NoError(t, err)
+	var names []string
+	for _, tsk := range tasks {
+		names = append(names, tsk.(*task).Name())
+		assert.Equal(t, "n1", tsk.(*task).alloc.NodeID)
+	}
+	assert.ElementsMatch(t, []string{"nginx", "sidecar"}, names)
+}
@@ -0,0 +1,150 @@
+// Package nomad presents a filesystem hierarchy for HashiCorp Nomad clusters:
+// their jobs, the jobs' allocations and the allocations' tasks. Jobs expose
+// their spec as metadata, tasks expose their stdout and stderr logs, and exec
+// runs commands in a task via Nomad's exec API.
+//
+// Clusters are listed in the plugin's config. The cluster at the NOMAD_ADDR
+// environment variable is used if none are.
+package nomad
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// Root of the Nomad plugin
+type Root struct {
+	plugin.EntryBase
+	clusters []plugin.Entry
+}
+
+type clusterConfig struct {
+	name      string
+	address   string
+	token     string
+	region    string
+	namespace string
+}
+
+// Init for root
+func (r *Root) Init(cfg map[string]interface{}) error {
+	var configs []clusterConfig
+	if clustersI, ok := cfg["clusters"]; ok {
+		var err error
+		if configs, err = parseClusters(clustersI); err != nil {
+			return err
+		}
+	} else if address := os.Getenv("NOMAD_ADDR"); address != "" {
+		// The client reads the token, region and namespace from the
+		// environment too.
+		configs = []clusterConfig{{name: "default", address: address}}
+	}
+
+	for _, config := range configs {
+		cluster, err := newCluster(config)
+		if err != nil {
+			return fmt.Errorf("nomad: could not create a client for %v: %v", config.name, err)
+		}
+		r.clusters = append(r.clusters, cluster)
+	}
+
+	r.EntryBase = plugin.NewEntry("nomad")
+	r.DisableDefaultCaching()
+	return nil
+}
+
+// Schema returns the root's schema
+func (r *Root) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(r, "nomad").
+		SetDescription(rootDescription).
+		IsSingleton()
+}
+
+// ChildSchemas returns the root's child schema
+func (r *Root) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&cluster{}).Schema(),
+	}
+}
+
+// List lists the configured clusters.
+func (r *Root) List(ctx context.Context) ([]plugin.Entry, error) {
+	return r.clusters, nil
+}
+
+// parseClusters parses the nomad.clusters config, which is an array of
+// {name: <name>, address: <url>, token: <token>, region: <region>, namespace: <namespace>}
+// objects. Only the name and address are required. Clusters are listed in an
+// array instead of being keys of an object because config keys are
+// case-insensitive.
+func parseClusters(clustersI interface{}) ([]clusterConfig, error) {
+	clusters, ok := clustersI.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("nomad.clusters config must be an array, not %v", clustersI)
+	}
+
+	var configs []clusterConfig
+	seen := make(map[string]struct{})
+	for _, elem := range clusters {
+		obj, ok := plugin.ToStringMap(elem)
+		if !ok {
+			return nil, fmt.Errorf("nomad.clusters config must be an array of objects, not %v", clusters)
+		}
+		name, ok := obj["name"].(string)
+		if !ok || name == "" || strings.Contains(name, "/") {
+			return nil, fmt.Errorf("nomad.clusters config entry %v must specify a name that doesn't contain a '/'", obj)
+		}
+		address, ok := obj["address"].(string)
+		if !ok || address == "" {
+			return nil, fmt.Errorf("nomad.clusters config for %v must specify the cluster's address", name)
+		}
+		config := clusterConfig{name: name, address: address}
+		for key, field := range map[string]*string{
+			"token":     &config.token,
+			"region":    &config.region,
+			"namespace": &config.namespace,
+		} {
+			valueI, ok := obj[key]
+			if !ok {
+				continue
+			}
+			value, ok := valueI.(string)
+			if !ok {
+				return nil, fmt.Errorf("nomad.clusters config for %v: %v must be a string, not %v", name, key, valueI)
+			}
+			*field = value
+		}
+		if _, ok := seen[name]; ok {
+			return nil, fmt.Errorf("nomad.clusters config specifies %v more than once", name)
+		}
+		seen[name] = struct{}{}
+		configs = append(configs, config)
+	}
+	return configs, nil
+}
+
+const rootDescription = `
+This is the Nomad plugin root. It contains the clusters in the nomad.clusters
+config, e.g.
+
+nomad:
+  clusters:
+    - name: prod
+      address: https://nomad.example.com:4646
+      token: <ACL token>
+      region: us-west
+      namespace: web
+    - name: dev
+      address: http://127.0.0.1:4646
+
+The token, region and namespace are optional. If they're omitted, then the
+NOMAD_TOKEN, NOMAD_REGION and NOMAD_NAMESPACE environment variables are used.
+
+If no clusters are configured, then the cluster at the NOMAD_ADDR environment
+variable is listed as 'default'.
+`
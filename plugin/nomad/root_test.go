@@ -0,0 +1,40 @@
+package nomad
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseClusters(t *testing.T) {
+	configs, err := parseClusters([]interface{}{
+		map[string]interface{}{"name": "Prod", "address": "https://prod:4646", "token": "secret", "region": "west"},
+		map[interface{}]interface{}{"name": "dev", "address": "http://127.0.0.1:4646", "namespace": "web"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []clusterConfig{
+		{name: "Prod", address: "https://prod:4646", token: "secret", region: "west"},
+		{name: "dev", address: "http://127.0.0.1:4646", namespace: "web"},
+	}, configs)
+}
+
+func TestParseClustersErrors(t *testing.T) {
+	_, err := parseClusters("http://prod:4646")
+	assert.EqualError(t, err, "nomad.clusters config must be an array, not http://prod:4646")
+
+	_, err = parseClusters([]interface{}{map[string]interface{}{"address": "http://prod:4646"}})
+	assert.EqualError(t, err, "nomad.clusters config entry map[address:http://prod:4646] must specify a name that doesn't contain a '/'")
+
+	_, err = parseClusters([]interface{}{map[string]interface{}{"name": "prod"}})
+	assert.EqualError(t, err, "nomad.clusters config for prod must specify the cluster's address")
+
+	_, err = parseClusters([]interface{}{map[string]interface{}{"name": "prod", "address": "http://prod:4646", "token": 5}})
+	assert.EqualError(t, err, "nomad.clusters config for prod: token must be a string, not 5")
+
+	_, err = parseClusters([]interface{}{
+		map[string]interface{}{"name": "prod", "address": "http://a:4646"},
+		map[string]interface{}{"name": "prod", "address": "http://b:4646"},
+	})
+	assert.EqualError(t, err, "nomad.clusters config specifies prod more than once")
+}
@@ -0,0 +1,87 @@
+package nomad
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	nomad "github.com/hashicorp/nomad/api"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// streamedLogBytes is how much of the end of a log is included when it's
+// streamed.
+const streamedLogBytes = 4096
+
+// taskLogFile is a task's stdout or stderr log.
+type taskLogFile struct {
+	plugin.EntryBase
+	client   *nomad.Client
+	alloc    *nomad.Allocation
+	taskName string
+	logType  string
+}
+
+func newTaskLogFile(t *task, logType string) *taskLogFile {
+	tlf := &taskLogFile{
+		EntryBase: plugin.NewEntry(logType),
+	}
+	tlf.client = t.client
+	tlf.alloc = t.alloc
+	tlf.taskName = t.Name()
+	tlf.logType = logType
+	return tlf
+}
+
+func (tlf *taskLogFile) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(tlf, "log")
+}
+
+func (tlf *taskLogFile) Read(ctx context.Context) ([]byte, error) {
+	cancel := make(chan struct{})
+	defer close(cancel)
+	frames, errCh := tlf.client.AllocFS().Logs(tlf.alloc, false, tlf.taskName, tlf.logType, "start", 0, cancel, nil)
+
+	var buf bytes.Buffer
+	if err := copyFrames(&buf, frames, errCh, cancel); err != nil {
+		return nil, err
+	}
+	activity.Record(ctx, "Read %v bytes of %v %v log", buf.Len(), tlf.taskName, tlf.logType)
+	return buf.Bytes(), nil
+}
+
+func (tlf *taskLogFile) Stream(ctx context.Context) (io.ReadCloser, error) {
+	cancel := make(chan struct{})
+	frames, errCh := tlf.client.AllocFS().Logs(tlf.alloc, true, tlf.taskName, tlf.logType, "end", streamedLogBytes, cancel, nil)
+
+	r, w := io.Pipe()
+	go func() {
+		err := copyFrames(w, frames, errCh, cancel)
+		activity.Record(ctx, "Stream of %v %v log closed: %v", tlf.taskName, tlf.logType, err)
+		w.CloseWithError(err)
+	}()
+	return plugin.CleanupReader{ReadCloser: r, Cleanup: func() {
+		close(cancel)
+	}}, nil
+}
+
+// copyFrames writes the log frames' data to w until the frames channel is
+// closed, there's an error or the copy's cancelled.
+func copyFrames(w io.Writer, frames <-chan *nomad.StreamFrame, errCh <-chan error, cancel <-chan struct{}) error {
+	for {
+		select {
+		case frame, ok := <-frames:
+			if !ok {
+				return nil
+			}
+			if _, err := w.Write(frame.Data); err != nil {
+				return err
+			}
+		case err := <-errCh:
+			return err
+		case <-cancel:
+			return nil
+		}
+	}
+}
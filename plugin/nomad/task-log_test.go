@@ -0,0 +1,34 @@
+package nomad
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	nomad "github.com/hashicorp/nomad/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskLogFileRead(t *testing.T) {
+	c, closeServer := newTestCluster(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/client/fs/logs/1a2b" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		assert.Equal(t, "nginx", r.URL.Query().Get("task"))
+		assert.Equal(t, "stderr", r.URL.Query().Get("type"))
+		assert.Equal(t, "false", r.URL.Query().Get("follow"))
+		// The frames' data is base64 encoded.
+		_, _ = w.Write([]byte(`{"Data": "aGVsbG8g"}{}{"Data": "d29ybGQK"}`))
+	})
+	defer closeServer()
+
+	alloc := &nomad.Allocation{ID: "1a2b", NodeID: "n1"}
+	tlf := newTaskLogFile(newTask(c.client, alloc, "nginx", nil), "stderr")
+	assert.Equal(t, "stderr", tlf.Name())
+
+	content, err := tlf.Read(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "hello world\n", string(content))
+}
@@ -0,0 +1,132 @@
+package nomad
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	nomad "github.com/hashicorp/nomad/api"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/puppetlabs/wash/volume"
+)
+
+// task is a task that's running (or ran) in a Nomad allocation.
+type task struct {
+	plugin.EntryBase
+	client *nomad.Client
+	alloc  *nomad.Allocation
+}
+
+func newTask(client *nomad.Client, alloc *nomad.Allocation, name string, state *nomad.TaskState) *task {
+	t := &task{
+		EntryBase: plugin.NewEntry(name),
+	}
+	t.client = client
+	t.alloc = alloc
+
+	t.SetPartialMetadata(state)
+	if state != nil && !state.StartedAt.IsZero() {
+		t.
+			Attributes().
+			SetAtime(state.StartedAt).
+			SetCrtime(state.StartedAt).
+			SetMtime(state.StartedAt)
+	}
+	return t
+}
+
+func (t *task) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(t, "task").
+		SetPartialMetadataSchema(nomad.TaskState{})
+}
+
+func (t *task) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&taskLogFile{}).Schema(),
+		(&plugin.MetadataJSONFile{}).Schema(),
+		(&volume.FS{}).Schema(),
+	}
+}
+
+func (t *task) List(ctx context.Context) ([]plugin.Entry, error) {
+	tm, err := plugin.NewMetadataJSONFile(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+
+	// Include a view of the remote filesystem using volume.FS. Use a small maxdepth because
+	// tasks can have lots of files and Exec is fast.
+	return []plugin.Entry{
+		newTaskLogFile(t, "stdout"),
+		newTaskLogFile(t, "stderr"),
+		tm,
+		volume.NewFS(ctx, "fs", t, 3),
+	}, nil
+}
+
+// Exec runs the command in the task via Nomad's exec API, which requires the
+// task's driver to support exec.
+func (t *task) Exec(ctx context.Context, cmd string, args []string, opts plugin.ExecOptions) (plugin.ExecCommand, error) {
+	execCmd := plugin.NewExecCommand(ctx)
+	command := append([]string{cmd}, args...)
+
+	// The exec is cancelled by the stop function instead of by ctx so that a
+	// Ctrl-C can be sent to a Tty first.
+	execCtx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	stdin := opts.Stdin
+	if stdin == nil {
+		stdin = bytes.NewReader(nil)
+	}
+	var stdinW *io.PipeWriter
+	if opts.Tty {
+		var r *io.PipeReader
+		r, stdinW = io.Pipe()
+		stdin = io.MultiReader(stdin, r)
+	}
+	execCmd.SetStopFunc(func() {
+		select {
+		case <-done:
+			// Passthrough, the command's finished so there's nothing to stop.
+		default:
+			if stdinW != nil {
+				_, err := stdinW.Write([]byte{0x03})
+				activity.Record(ctx, "Sent ETX on context termination: %v", err)
+				stdinW.Close()
+			}
+		}
+		cancel()
+	})
+
+	go func() {
+		defer cancel()
+		exitCode, err := t.client.Allocations().Exec(
+			execCtx,
+			t.alloc,
+			t.Name(),
+			opts.Tty,
+			command,
+			stdin,
+			execCmd.Stdout(),
+			execCmd.Stderr(),
+			nil,
+			nil,
+		)
+		close(done)
+		if stdinW != nil {
+			stdinW.Close()
+		}
+		activity.Record(ctx, "Exec on %v complete: %v", t.Name(), err)
+		if err != nil {
+			// Set the exit code error so that callers don't block
+			// when trying to retrieve the command's exit code
+			execCmd.SetExitCodeErr(err)
+		} else {
+			execCmd.SetExitCode(exitCode)
+		}
+		execCmd.CloseStreamsWithError(err)
+	}()
+	return execCmd, nil
+}
@@ -0,0 +1,38 @@
+package openstack
+
+import (
+	"context"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+type consoleLog struct {
+	plugin.EntryBase
+	id     string
+	client *gophercloud.ServiceClient
+}
+
+func newConsoleLog(id string, client *gophercloud.ServiceClient) *consoleLog {
+	cl := &consoleLog{
+		EntryBase: plugin.NewEntry("console.log"),
+	}
+	cl.id = id
+	cl.client = client
+	return cl
+}
+
+func (cl *consoleLog) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(cl, "console.log").IsSingleton()
+}
+
+func (cl *consoleLog) Read(ctx context.Context) ([]byte, error) {
+	activity.Record(ctx, "Getting the console log of instance %v", cl.id)
+	output, err := servers.ShowConsoleOutput(cl.client, cl.id, servers.ShowConsoleOutputOpts{}).Extract()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(output), nil
+}
@@ -0,0 +1,97 @@
+package openstack
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/objectstorage/v1/containers"
+	"github.com/gophercloud/gophercloud/openstack/objectstorage/v1/objects"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+type container struct {
+	plugin.EntryBase
+	client *gophercloud.ServiceClient
+}
+
+func newContainer(info containers.Container, client *gophercloud.ServiceClient) *container {
+	c := &container{
+		EntryBase: plugin.NewEntry(info.Name),
+	}
+	c.client = client
+	c.
+		SetPartialMetadata(info).
+		Attributes().
+		SetSize(uint64(info.Bytes))
+	return c
+}
+
+func (c *container) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(c, "container").
+		SetDescription(containerDescription).
+		SetPartialMetadataSchema(containers.Container{})
+}
+
+func (c *container) ChildSchemas() []*plugin.EntrySchema {
+	return containerSchemas()
+}
+
+func (c *container) List(ctx context.Context) ([]plugin.Entry, error) {
+	return listContainer(ctx, c.client, c.Name(), "")
+}
+
+// Create returns a new object with the given name at the root of the
+// container. The object's uploaded when it's written to.
+func (c *container) Create(ctx context.Context, name string, _ os.FileMode) (plugin.Writable, error) {
+	return newEmptyObject(name, c.client, c.Name(), name), nil
+}
+
+const delimiter = "/"
+
+// listContainer lists the objects directly under the prefix. Like other object
+// stores, Swift groups the keys that share a prefix up to the delimiter into a
+// subdir.
+func listContainer(ctx context.Context, client *gophercloud.ServiceClient, container string, prefix string) ([]plugin.Entry, error) {
+	activity.Record(ctx, "Listing objects in %v with prefix %q", container, prefix)
+	opts := objects.ListOpts{Full: true, Prefix: prefix, Delimiter: delimiter}
+	pages, err := objects.List(client, container, opts).AllPages()
+	if err != nil {
+		return nil, err
+	}
+	list, err := objects.ExtractInfo(pages)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []plugin.Entry
+	for _, obj := range list {
+		if obj.Subdir != "" {
+			name := strings.TrimPrefix(strings.TrimSuffix(obj.Subdir, delimiter), prefix)
+			entries = append(entries, newObjectPrefix(name, client, container, obj.Subdir))
+		} else if obj.Name != prefix {
+			name := strings.TrimPrefix(obj.Name, prefix)
+			entries = append(entries, newObject(name, client, container, obj))
+		}
+	}
+	return entries, nil
+}
+
+func containerSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{(&objectPrefix{}).Schema(), (&object{}).Schema()}
+}
+
+const containerDescription = `
+This is a Swift container. For convenience, we impose some hierarchical
+structure on its objects by grouping keys with common prefixes into a specific
+directory. For example, the objects 'foo/bar' and 'foo/baz' are represented as
+files with path 'foo/bar' and path 'foo/baz', where 'foo' is represented as a
+'directory'.
+
+New objects can be created by writing to a new file, e.g.
+
+  echo hello > openstack/storage/my-container/greeting.txt
+`
@@ -0,0 +1,83 @@
+package openstack
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/stretchr/testify/assert"
+)
+
+// swiftServer returns a client for a Swift endpoint that lists the given JSON
+// for the container's objects.
+func swiftServer(t *testing.T, container string, listing string) (*gophercloud.ServiceClient, *httptest.Server) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/"+container, r.URL.Path)
+		assert.Equal(t, "/", r.URL.Query().Get("delimiter"))
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("marker") != "" {
+			_, _ = w.Write([]byte("[]"))
+			return
+		}
+		_, _ = w.Write([]byte(listing))
+	}))
+	client := &gophercloud.ServiceClient{
+		ProviderClient: &gophercloud.ProviderClient{HTTPClient: *server.Client()},
+		Endpoint:       server.URL + "/",
+	}
+	return client, server
+}
+
+func TestListContainer(t *testing.T) {
+	client, server := swiftServer(t, "bucket", `[
+		{"subdir": "logs/"},
+		{"name": "readme.md", "bytes": 5, "hash": "abc", "content_type": "text/markdown", "last_modified": "2020-01-02T03:04:05.123456"}
+	]`)
+	defer server.Close()
+
+	entries, err := listContainer(context.Background(), client, "bucket", "")
+	if !assert.NoError(t, err) || !assert.Len(t, entries, 2) {
+		return
+	}
+
+	prefix, ok := entries[0].(*objectPrefix)
+	if assert.True(t, ok) {
+		assert.Equal(t, "logs", prefix.Name())
+		assert.Equal(t, "logs/", prefix.prefix)
+	}
+
+	obj, ok := entries[1].(*object)
+	if assert.True(t, ok) {
+		assert.Equal(t, "readme.md", obj.Name())
+		assert.Equal(t, "readme.md", obj.key)
+		assert.Equal(t, int64(5), obj.size)
+		attr := plugin.Attributes(obj)
+		assert.Equal(t, uint64(5), attr.Size())
+		assert.Equal(t, time.Date(2020, 1, 2, 3, 4, 5, 123456000, time.UTC), attr.Mtime())
+		assert.Equal(t, "text/markdown", attr.Custom("content_type"))
+	}
+}
+
+func TestListContainer_Prefix(t *testing.T) {
+	client, server := swiftServer(t, "bucket", `[
+		{"name": "logs/", "bytes": 0},
+		{"subdir": "logs/2020/"},
+		{"name": "logs/today.log", "bytes": 3}
+	]`)
+	defer server.Close()
+
+	entries, err := listContainer(context.Background(), client, "bucket", "logs/")
+	if !assert.NoError(t, err) || !assert.Len(t, entries, 2) {
+		return
+	}
+	// The prefix's own placeholder object is skipped, and names are relative
+	// to the prefix.
+	assert.Equal(t, "2020", entries[0].(*objectPrefix).Name())
+	assert.Equal(t, "logs/2020/", entries[0].(*objectPrefix).prefix)
+	assert.Equal(t, "today.log", entries[1].(*object).Name())
+	assert.Equal(t, "logs/today.log", entries[1].(*object).key)
+}
@@ -0,0 +1,48 @@
+package openstack
+
+import (
+	"context"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/objectstorage/v1/containers"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+type containersDir struct {
+	plugin.EntryBase
+	client *gophercloud.ServiceClient
+}
+
+func newContainersDir(client *gophercloud.ServiceClient) *containersDir {
+	d := &containersDir{
+		EntryBase: plugin.NewEntry("storage"),
+	}
+	d.client = client
+	return d
+}
+
+func (d *containersDir) List(ctx context.Context) ([]plugin.Entry, error) {
+	pages, err := containers.List(d.client, containers.ListOpts{Full: true}).AllPages()
+	if err != nil {
+		return nil, err
+	}
+	list, err := containers.ExtractInfo(pages)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]plugin.Entry, len(list))
+	for i, info := range list {
+		entries[i] = newContainer(info, d.client)
+	}
+	return entries, nil
+}
+
+func (d *containersDir) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(d, "storage").IsSingleton()
+}
+
+func (d *containersDir) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&container{}).Schema(),
+	}
+}
@@ -0,0 +1,117 @@
+package openstack
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/pauseunpause"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/startstop"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/suspendresume"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+type instance struct {
+	plugin.EntryBase
+	id     string
+	client *gophercloud.ServiceClient
+}
+
+func newInstance(server servers.Server, client *gophercloud.ServiceClient) *instance {
+	inst := &instance{
+		EntryBase: plugin.NewEntry(server.Name),
+	}
+	inst.id = server.ID
+	inst.client = client
+	inst.
+		SetPartialMetadata(server).
+		Attributes().
+		SetCrtime(server.Created).
+		SetCtime(server.Updated).
+		SetMtime(server.Updated).
+		SetCustom("status", server.Status)
+	return inst
+}
+
+func (inst *instance) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(inst, "instance").
+		SetDescription(instanceDescription).
+		SetPartialMetadataSchema(servers.Server{}).
+		AddCustomAttribute("status", plugin.StringAttribute, "The instance's status, e.g. ACTIVE or SHUTOFF").
+		AddSignal("start", "Starts the instance").
+		AddSignal("stop", "Stops the instance").
+		AddSignal("restart", "Gracefully restarts the instance (a soft reboot)").
+		AddSignal("reset", "Resets the instance, similar to doing a hard-reset on your computer").
+		AddSignal("pause", "Pauses the instance. Its state stays in memory").
+		AddSignal("unpause", "Un-pauses a paused instance").
+		AddSignal("suspend", "Suspends the instance. Its state is saved to disk").
+		AddSignal("resume", "Resumes a suspended instance")
+}
+
+func (inst *instance) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&consoleLog{}).Schema(),
+		(&plugin.MetadataJSONFile{}).Schema(),
+	}
+}
+
+func (inst *instance) List(ctx context.Context) ([]plugin.Entry, error) {
+	meta, err := plugin.NewMetadataJSONFile(ctx, inst)
+	if err != nil {
+		return nil, err
+	}
+	return []plugin.Entry{newConsoleLog(inst.id, inst.client), meta}, nil
+}
+
+func (inst *instance) Metadata(ctx context.Context) (plugin.JSONObject, error) {
+	server, err := servers.Get(inst.client, inst.id).Extract()
+	if err != nil {
+		return nil, err
+	}
+	return plugin.ToJSONObject(server), nil
+}
+
+func (inst *instance) Signal(ctx context.Context, signal string) error {
+	activity.Record(ctx, "Sending %v to instance %v", signal, inst.id)
+	var err error
+	switch signal {
+	case "start":
+		err = startstop.Start(inst.client, inst.id).ExtractErr()
+	case "stop":
+		err = startstop.Stop(inst.client, inst.id).ExtractErr()
+	case "restart":
+		err = servers.Reboot(inst.client, inst.id, servers.RebootOpts{Type: servers.SoftReboot}).ExtractErr()
+	case "reset":
+		err = servers.Reboot(inst.client, inst.id, servers.RebootOpts{Type: servers.HardReboot}).ExtractErr()
+	case "pause":
+		err = pauseunpause.Pause(inst.client, inst.id).ExtractErr()
+	case "unpause":
+		err = pauseunpause.Unpause(inst.client, inst.id).ExtractErr()
+	case "suspend":
+		err = suspendresume.Suspend(inst.client, inst.id).ExtractErr()
+	case "resume":
+		err = suspendresume.Resume(inst.client, inst.id).ExtractErr()
+	default:
+		err = fmt.Errorf("unsupported signal %v", signal)
+	}
+	return err
+}
+
+func (inst *instance) Delete(ctx context.Context) (bool, error) {
+	// Nova deletes instances asynchronously.
+	return false, servers.Delete(inst.client, inst.id).ExtractErr()
+}
+
+const instanceDescription = `
+This is a Nova instance. Its metadata is the instance's server details, and its
+console.log file is the instance's console log. It supports the start, stop,
+restart (soft reboot), reset (hard reboot), pause, unpause, suspend and resume
+signals, e.g.
+
+  signal restart openstack/instances/web01
+
+Deleting it deletes the instance.
+`
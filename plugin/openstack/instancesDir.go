@@ -0,0 +1,48 @@
+package openstack
+
+import (
+	"context"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+type instancesDir struct {
+	plugin.EntryBase
+	client *gophercloud.ServiceClient
+}
+
+func newInstancesDir(client *gophercloud.ServiceClient) *instancesDir {
+	d := &instancesDir{
+		EntryBase: plugin.NewEntry("instances"),
+	}
+	d.client = client
+	return d
+}
+
+func (d *instancesDir) List(ctx context.Context) ([]plugin.Entry, error) {
+	pages, err := servers.List(d.client, servers.ListOpts{}).AllPages()
+	if err != nil {
+		return nil, err
+	}
+	list, err := servers.ExtractServers(pages)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]plugin.Entry, len(list))
+	for i, server := range list {
+		entries[i] = newInstance(server, d.client)
+	}
+	return entries, nil
+}
+
+func (d *instancesDir) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(d, "instances").IsSingleton()
+}
+
+func (d *instancesDir) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&instance{}).Schema(),
+	}
+}
@@ -0,0 +1,89 @@
+package openstack
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/objectstorage/v1/objects"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+type object struct {
+	plugin.EntryBase
+	client    *gophercloud.ServiceClient
+	container string
+	key       string
+	size      int64
+}
+
+func newObject(name string, client *gophercloud.ServiceClient, container string, obj objects.Object) *object {
+	o := &object{
+		EntryBase: plugin.NewEntry(name),
+	}
+	o.client = client
+	o.container = container
+	o.key = obj.Name
+	o.size = obj.Bytes
+	o.SetValidator(plugin.Validator{ETag: obj.Hash, LastModified: obj.LastModified})
+	o.
+		SetPartialMetadata(obj).
+		Attributes().
+		SetCtime(obj.LastModified).
+		SetMtime(obj.LastModified).
+		SetSize(uint64(obj.Bytes)).
+		SetCustom("content_type", obj.ContentType)
+	return o
+}
+
+// newEmptyObject returns an object representing a new, empty object. The
+// object's only uploaded once it's written to.
+func newEmptyObject(name string, client *gophercloud.ServiceClient, container string, key string) *object {
+	return newObject(name, client, container, objects.Object{Name: key, LastModified: time.Now()})
+}
+
+func (o *object) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(o, "object").
+		SetDescription(objectDescription).
+		SetPartialMetadataSchema(objects.Object{}).
+		AddCustomAttribute("content_type", plugin.StringAttribute, "The object's MIME type")
+}
+
+// Read reads the requested range of the object's content with a ranged request.
+func (o *object) Read(ctx context.Context, size int64, offset int64) ([]byte, error) {
+	if size == 0 || offset >= o.size {
+		return []byte{}, nil
+	}
+	activity.Record(ctx, "Downloading %v bytes of %v/%v at %v", size, o.container, o.key, offset)
+	opts := objects.DownloadOpts{Range: fmt.Sprintf("bytes=%v-%v", offset, offset+size-1)}
+	result := objects.Download(o.client, o.container, o.key, opts)
+	return result.ExtractContent()
+}
+
+// Write uploads the object's new content.
+func (o *object) Write(ctx context.Context, p []byte) error {
+	activity.Record(ctx, "Uploading %v bytes to %v/%v", len(p), o.container, o.key)
+	opts := objects.CreateOpts{Content: bytes.NewReader(p), ContentLength: int64(len(p))}
+	if _, err := objects.Create(o.client, o.container, o.key, opts).Extract(); err != nil {
+		return err
+	}
+	o.size = int64(len(p))
+	return nil
+}
+
+func (o *object) Delete(ctx context.Context) (bool, error) {
+	_, err := objects.Delete(o.client, o.container, o.key, nil).Extract()
+	return true, err
+}
+
+const objectDescription = `
+This is a Swift object. See the container's docs for more details on why we
+have this kind of entry.
+
+Reads fetch the requested range of the object's content, so large objects can
+be read without downloading them in full. Writes replace the object's content.
+`
@@ -0,0 +1,73 @@
+package openstack
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/objectstorage/v1/objects"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+type objectPrefix struct {
+	plugin.EntryBase
+	client    *gophercloud.ServiceClient
+	container string
+	prefix    string
+}
+
+// Takes the name of the directory, as well as the full prefix path.
+func newObjectPrefix(name string, client *gophercloud.ServiceClient, container string, prefix string) *objectPrefix {
+	p := &objectPrefix{
+		EntryBase: plugin.NewEntry(name),
+	}
+	p.client = client
+	p.container = container
+	p.prefix = prefix
+	return p
+}
+
+func (p *objectPrefix) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(p, "prefix").
+		SetDescription(objectPrefixDescription)
+}
+
+func (p *objectPrefix) ChildSchemas() []*plugin.EntrySchema {
+	return containerSchemas()
+}
+
+// List all objects under this prefix as dirs and files.
+func (p *objectPrefix) List(ctx context.Context) ([]plugin.Entry, error) {
+	return listContainer(ctx, p.client, p.container, p.prefix)
+}
+
+// Create returns a new object with the given name under this prefix. The
+// object's uploaded when it's written to.
+func (p *objectPrefix) Create(ctx context.Context, name string, _ os.FileMode) (plugin.Writable, error) {
+	return newEmptyObject(name, p.client, p.container, p.prefix+name), nil
+}
+
+// Delete deletes the objects under this prefix.
+func (p *objectPrefix) Delete(ctx context.Context) (bool, error) {
+	pages, err := objects.List(p.client, p.container, objects.ListOpts{Prefix: p.prefix}).AllPages()
+	if err != nil {
+		return false, err
+	}
+	names, err := objects.ExtractNames(pages)
+	if err != nil {
+		return false, err
+	}
+	for _, name := range names {
+		if _, err := objects.Delete(p.client, p.container, name, nil).Extract(); err != nil {
+			return false, fmt.Errorf("failed to delete the %v object: %v", name, err)
+		}
+	}
+	return true, nil
+}
+
+const objectPrefixDescription = `
+This represents a common prefix shared by multiple Swift objects. See the
+container's docs for more details on why we have this kind of entry.
+`
@@ -0,0 +1,106 @@
+// Package openstack presents a filesystem hierarchy for OpenStack resources.
+//
+// It authenticates with Keystone via the OS_* environment variables that an
+// OpenStack RC file sets, like OS_AUTH_URL, OS_USERNAME and OS_PASSWORD.
+package openstack
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// Root of the OpenStack plugin
+type Root struct {
+	plugin.EntryBase
+	provider *gophercloud.ProviderClient
+	region   string
+}
+
+// Init for root
+func (r *Root) Init(cfg map[string]interface{}) error {
+	r.region = os.Getenv("OS_REGION_NAME")
+	if regionI, ok := cfg["region"]; ok {
+		region, ok := regionI.(string)
+		if !ok {
+			return fmt.Errorf("openstack.region config must be a string, not %v", regionI)
+		}
+		r.region = region
+	}
+
+	opts, err := openstack.AuthOptionsFromEnv()
+	if err != nil {
+		return fmt.Errorf("could not load OpenStack credentials: %v", err)
+	}
+	// Tokens expire, and the daemon's long-lived.
+	opts.AllowReauth = true
+	provider, err := openstack.AuthenticatedClient(opts)
+	if err != nil {
+		return fmt.Errorf("could not authenticate with Keystone: %v", err)
+	}
+	r.provider = provider
+
+	r.EntryBase = plugin.NewEntry("openstack")
+	return nil
+}
+
+// Schema returns the root's schema
+func (r *Root) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(r, "openstack").
+		SetDescription(rootDescription).
+		IsSingleton()
+}
+
+// ChildSchemas returns the root's child schema
+func (r *Root) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&instancesDir{}).Schema(),
+		(&volumesDir{}).Schema(),
+		(&containersDir{}).Schema(),
+	}
+}
+
+// List lists the services that are in the region's catalog. Clouds don't have
+// to provide every service, so missing services are skipped.
+func (r *Root) List(ctx context.Context) ([]plugin.Entry, error) {
+	endpoint := gophercloud.EndpointOpts{Region: r.region}
+	var entries []plugin.Entry
+	if client, err := openstack.NewComputeV2(r.provider, endpoint); err != nil {
+		activity.Record(ctx, "Skipping instances: %v", err)
+	} else {
+		entries = append(entries, newInstancesDir(client))
+	}
+	if client, err := openstack.NewBlockStorageV3(r.provider, endpoint); err != nil {
+		activity.Record(ctx, "Skipping volumes: %v", err)
+	} else {
+		entries = append(entries, newVolumesDir(client))
+	}
+	if client, err := openstack.NewObjectStorageV1(r.provider, endpoint); err != nil {
+		activity.Record(ctx, "Skipping object storage: %v", err)
+	} else {
+		entries = append(entries, newContainersDir(client))
+	}
+	return entries, nil
+}
+
+const rootDescription = `
+This is the OpenStack plugin root. It lists the project's Nova instances,
+Cinder volumes and Swift containers. It authenticates with Keystone via the
+OS_* environment variables, so the simplest way to set it up is to source your
+project's OpenStack RC file, e.g.
+
+  source ~/my-project-openrc.sh
+
+The region is set by OS_REGION_NAME. It can be overridden by adding
+
+openstack:
+  region: RegionTwo
+
+to Wash's config file.
+`
@@ -0,0 +1,45 @@
+package openstack
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// setenv sets the environment variable and returns a function that restores it.
+func setenv(key string, value string) func() {
+	old, ok := os.LookupEnv(key)
+	if value == "" {
+		os.Unsetenv(key)
+	} else {
+		os.Setenv(key, value)
+	}
+	return func() {
+		if ok {
+			os.Setenv(key, old)
+		} else {
+			os.Unsetenv(key)
+		}
+	}
+}
+
+func TestInit_Region(t *testing.T) {
+	// Without OS_AUTH_URL, Init fails after it's read the region.
+	defer setenv("OS_AUTH_URL", "")()
+	defer setenv("OS_REGION_NAME", "RegionOne")()
+
+	r := &Root{}
+	err := r.Init(map[string]interface{}{})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "could not load OpenStack credentials")
+	}
+	assert.Equal(t, "RegionOne", r.region)
+
+	r = &Root{}
+	assert.Error(t, r.Init(map[string]interface{}{"region": "RegionTwo"}))
+	assert.Equal(t, "RegionTwo", r.region)
+
+	r = &Root{}
+	assert.EqualError(t, r.Init(map[string]interface{}{"region": 2}), "openstack.region config must be a string, not 2")
+}
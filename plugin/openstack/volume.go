@@ -0,0 +1,70 @@
+package openstack
+
+import (
+	"context"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/v3/volumes"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+type volume struct {
+	plugin.EntryBase
+	id     string
+	client *gophercloud.ServiceClient
+}
+
+func newVolume(vol volumes.Volume, client *gophercloud.ServiceClient) *volume {
+	// Volumes don't need a name, so fall back to their ID.
+	name := vol.Name
+	if name == "" {
+		name = vol.ID
+	}
+	v := &volume{
+		EntryBase: plugin.NewEntry(name),
+	}
+	v.id = vol.ID
+	v.client = client
+	v.
+		SetPartialMetadata(vol).
+		Attributes().
+		SetCrtime(vol.CreatedAt).
+		SetCtime(vol.UpdatedAt).
+		SetMtime(vol.UpdatedAt).
+		SetCustom("status", vol.Status).
+		SetCustom("size_gb", vol.Size).
+		SetCustom("volume_type", vol.VolumeType)
+	return v
+}
+
+func (v *volume) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(v, "volume").
+		SetDescription(volumeDescription).
+		SetPartialMetadataSchema(volumes.Volume{}).
+		AddCustomAttribute("status", plugin.StringAttribute, "The volume's status, e.g. available or in-use").
+		AddCustomAttribute("size_gb", plugin.NumberAttribute, "The volume's size in GiB").
+		AddCustomAttribute("volume_type", plugin.StringAttribute, "The volume's type")
+}
+
+func (v *volume) Metadata(ctx context.Context) (plugin.JSONObject, error) {
+	vol, err := volumes.Get(v.client, v.id).Extract()
+	if err != nil {
+		return nil, err
+	}
+	return plugin.ToJSONObject(vol), nil
+}
+
+func (v *volume) Delete(ctx context.Context) (bool, error) {
+	// Cinder deletes volumes asynchronously. It refuses to delete attached volumes.
+	return false, volumes.Delete(v.client, v.id, volumes.DeleteOpts{}).ExtractErr()
+}
+
+const volumeDescription = `
+This is a Cinder volume. Its metadata includes the volume's attachments, and its
+status, size and type are available as custom attributes, e.g.
+
+  find openstack/volumes -attr .status available
+
+Deleting it deletes the volume. Cinder refuses to delete attached volumes.
+`
@@ -0,0 +1,20 @@
+package openstack
+
+import (
+	"testing"
+
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/v3/volumes"
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewVolume(t *testing.T) {
+	v := newVolume(volumes.Volume{ID: "1234", Name: "data", Size: 10, Status: "available"}, nil)
+	assert.Equal(t, "data", v.Name())
+	attr := plugin.Attributes(v)
+	assert.Equal(t, float64(10), attr.Custom("size_gb"))
+	assert.Equal(t, "available", attr.Custom("status"))
+
+	// Volumes don't need a name, so they fall back to their ID.
+	assert.Equal(t, "1234", newVolume(volumes.Volume{ID: "1234"}, nil).Name())
+}
@@ -0,0 +1,48 @@
+package openstack
+
+import (
+	"context"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/v3/volumes"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+type volumesDir struct {
+	plugin.EntryBase
+	client *gophercloud.ServiceClient
+}
+
+func newVolumesDir(client *gophercloud.ServiceClient) *volumesDir {
+	d := &volumesDir{
+		EntryBase: plugin.NewEntry("volumes"),
+	}
+	d.client = client
+	return d
+}
+
+func (d *volumesDir) List(ctx context.Context) ([]plugin.Entry, error) {
+	pages, err := volumes.List(d.client, volumes.ListOpts{}).AllPages()
+	if err != nil {
+		return nil, err
+	}
+	list, err := volumes.ExtractVolumes(pages)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]plugin.Entry, len(list))
+	for i, vol := range list {
+		entries[i] = newVolume(vol, d.client)
+	}
+	return entries, nil
+}
+
+func (d *volumesDir) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(d, "volumes").IsSingleton()
+}
+
+func (d *volumesDir) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&volume{}).Schema(),
+	}
+}
@@ -0,0 +1,82 @@
+package prometheus
+
+import (
+	"context"
+
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// alertsDir lists a server's active alerts.
+type alertsDir struct {
+	plugin.EntryBase
+	server *server
+}
+
+func newAlertsDir(s *server) *alertsDir {
+	d := &alertsDir{
+		EntryBase: plugin.NewEntry("alerts"),
+	}
+	d.server = s
+	return d
+}
+
+func (d *alertsDir) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(d, "alerts").IsSingleton()
+}
+
+func (d *alertsDir) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&alert{}).Schema(),
+	}
+}
+
+func (d *alertsDir) List(ctx context.Context) ([]plugin.Entry, error) {
+	result, err := d.server.api.Alerts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	alerts := make([]plugin.Entry, len(result.Alerts))
+	for i, a := range result.Alerts {
+		alerts[i] = newAlert(a)
+	}
+	activity.Record(ctx, "Listing %v alerts", len(alerts))
+	return alerts, nil
+}
+
+// alert is an active alert, i.e. one that's pending or firing. Alerts of the
+// same rule differ by their labels, so each is named by its alert name and the
+// fingerprint of its labels.
+type alert struct {
+	plugin.EntryBase
+}
+
+func newAlert(a promv1.Alert) *alert {
+	al := &alert{
+		EntryBase: plugin.NewEntry(string(a.Labels[model.AlertNameLabel]) + "-" + a.Labels.Fingerprint().String()),
+	}
+	al.
+		SetPartialMetadata(a).
+		Attributes().
+		SetCrtime(a.ActiveAt).
+		SetCustom("state", string(a.State))
+	return al
+}
+
+func (a *alert) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(a, "alert").
+		SetDescription(alertDescription).
+		SetPartialMetadataSchema(promv1.Alert{}).
+		AddCustomAttribute("state", plugin.StringAttribute, "The alert's state, i.e. pending or firing")
+}
+
+const alertDescription = `
+This is an active Prometheus alert. It's named by its alert name and the
+fingerprint of its labels, since one rule can have many alerts. Its metadata
+includes its labels, annotations and value, and its crtime is when it became
+active.
+`
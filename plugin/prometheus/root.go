@@ -0,0 +1,120 @@
+// Package prometheus presents a filesystem hierarchy for Prometheus servers:
+// their scrape targets and active alerts. Servers support Exec, which runs an
+// instant query.
+//
+// Servers are listed in the plugin's config. The PROMETHEUS_URL environment
+// variable is used if none are.
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// Root of the Prometheus plugin
+type Root struct {
+	plugin.EntryBase
+	servers []plugin.Entry
+}
+
+type serverConfig struct {
+	name    string
+	address string
+}
+
+// Init for root
+func (r *Root) Init(cfg map[string]interface{}) error {
+	var configs []serverConfig
+	if serversI, ok := cfg["servers"]; ok {
+		var err error
+		if configs, err = parseServers(serversI); err != nil {
+			return err
+		}
+	} else if address := os.Getenv("PROMETHEUS_URL"); address != "" {
+		configs = []serverConfig{{name: "default", address: address}}
+	}
+
+	for _, config := range configs {
+		server, err := newServer(config.name, config.address)
+		if err != nil {
+			return fmt.Errorf("prometheus: could not create a client for %v: %v", config.name, err)
+		}
+		r.servers = append(r.servers, server)
+	}
+
+	r.EntryBase = plugin.NewEntry("prometheus")
+	r.DisableDefaultCaching()
+	return nil
+}
+
+// Schema returns the root's schema
+func (r *Root) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(r, "prometheus").
+		SetDescription(rootDescription).
+		IsSingleton()
+}
+
+// ChildSchemas returns the root's child schema
+func (r *Root) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&server{}).Schema(),
+	}
+}
+
+// List lists the configured servers.
+func (r *Root) List(ctx context.Context) ([]plugin.Entry, error) {
+	return r.servers, nil
+}
+
+// parseServers parses the prometheus.servers config, which is an array of
+// {name: <name>, url: <url>} objects. Servers are listed in an array instead of
+// being keys of an object because config keys are case-insensitive.
+func parseServers(serversI interface{}) ([]serverConfig, error) {
+	servers, ok := serversI.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("prometheus.servers config must be an array, not %v", serversI)
+	}
+
+	var configs []serverConfig
+	seen := make(map[string]struct{})
+	for _, elem := range servers {
+		obj, ok := plugin.ToStringMap(elem)
+		if !ok {
+			return nil, fmt.Errorf("prometheus.servers config must be an array of objects, not %v", servers)
+		}
+		name, ok := obj["name"].(string)
+		if !ok || name == "" || strings.Contains(name, "/") {
+			return nil, fmt.Errorf("prometheus.servers config entry %v must specify a name that doesn't contain a '/'", obj)
+		}
+		address, ok := obj["url"].(string)
+		if !ok || address == "" {
+			return nil, fmt.Errorf("prometheus.servers config for %v must specify the server's url", name)
+		}
+		if _, ok := seen[name]; ok {
+			return nil, fmt.Errorf("prometheus.servers config specifies %v more than once", name)
+		}
+		seen[name] = struct{}{}
+		configs = append(configs, serverConfig{name: name, address: address})
+	}
+	return configs, nil
+}
+
+const rootDescription = `
+This is the Prometheus plugin root. It contains the servers in the
+prometheus.servers config, e.g.
+
+prometheus:
+  servers:
+    - name: prod
+      url: http://prometheus.example.com:9090
+    - name: staging
+      url: http://prometheus.staging.example.com:9090
+
+If no servers are configured, then the server at the PROMETHEUS_URL environment
+variable is listed as 'default'.
+`
@@ -0,0 +1,37 @@
+package prometheus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseServers(t *testing.T) {
+	configs, err := parseServers([]interface{}{
+		map[string]interface{}{"name": "Prod", "url": "http://prod:9090"},
+		map[interface{}]interface{}{"name": "staging", "url": "http://staging:9090"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []serverConfig{
+		{name: "Prod", address: "http://prod:9090"},
+		{name: "staging", address: "http://staging:9090"},
+	}, configs)
+}
+
+func TestParseServersErrors(t *testing.T) {
+	_, err := parseServers("http://prod:9090")
+	assert.EqualError(t, err, "prometheus.servers config must be an array, not http://prod:9090")
+
+	_, err = parseServers([]interface{}{map[string]interface{}{"url": "http://prod:9090"}})
+	assert.EqualError(t, err, "prometheus.servers config entry map[url:http://prod:9090] must specify a name that doesn't contain a '/'")
+
+	_, err = parseServers([]interface{}{map[string]interface{}{"name": "prod"}})
+	assert.EqualError(t, err, "prometheus.servers config for prod must specify the server's url")
+
+	_, err = parseServers([]interface{}{
+		map[string]interface{}{"name": "prod", "url": "http://a:9090"},
+		map[string]interface{}{"name": "prod", "url": "http://b:9090"},
+	})
+	assert.EqualError(t, err, "prometheus.servers config specifies prod more than once")
+}
@@ -0,0 +1,107 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	promapi "github.com/prometheus/client_golang/api"
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// server is a Prometheus server. Exec runs an instant query.
+type server struct {
+	plugin.EntryBase
+	api promv1.API
+}
+
+type serverMetadata struct {
+	Build   promv1.BuildinfoResult   `json:"build"`
+	Runtime promv1.RuntimeinfoResult `json:"runtime"`
+}
+
+func newServer(name string, address string) (*server, error) {
+	client, err := promapi.NewClient(promapi.Config{Address: address, RoundTripper: plugin.HTTPTransport()})
+	if err != nil {
+		return nil, err
+	}
+	s := &server{
+		EntryBase: plugin.NewEntry(name),
+	}
+	s.api = promv1.NewAPI(client)
+	s.SetPartialMetadata(map[string]string{"url": address})
+	return s, nil
+}
+
+func (s *server) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(s, "server").
+		SetDescription(serverDescription).
+		SetMetadataSchema(serverMetadata{})
+}
+
+func (s *server) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&targetsDir{}).Schema(),
+		(&alertsDir{}).Schema(),
+	}
+}
+
+func (s *server) List(ctx context.Context) ([]plugin.Entry, error) {
+	return []plugin.Entry{
+		newTargetsDir(s),
+		newAlertsDir(s),
+	}, nil
+}
+
+// Metadata returns the server's build and runtime info.
+func (s *server) Metadata(ctx context.Context) (plugin.JSONObject, error) {
+	var meta serverMetadata
+	var err error
+	if meta.Build, err = s.api.Buildinfo(ctx); err != nil {
+		return nil, err
+	}
+	if meta.Runtime, err = s.api.Runtimeinfo(ctx); err != nil {
+		return nil, err
+	}
+	return plugin.ToJSONObject(meta), nil
+}
+
+// Exec runs the command and its args, joined by spaces, as an instant query.
+// The result's written to stdout, and the query's warnings and errors to
+// stderr. A query that fails exits with 1.
+func (s *server) Exec(ctx context.Context, cmd string, args []string, opts plugin.ExecOptions) (plugin.ExecCommand, error) {
+	query := strings.Join(append([]string{cmd}, args...), " ")
+	activity.Record(ctx, "Querying %v for %v", s.Name(), query)
+
+	execCmd := plugin.NewExecCommand(ctx)
+	go func() {
+		value, warnings, err := s.api.Query(ctx, query, time.Now())
+		for _, warning := range warnings {
+			fmt.Fprintf(execCmd.Stderr(), "warning: %v\n", warning)
+		}
+		if err != nil {
+			fmt.Fprintln(execCmd.Stderr(), err)
+			execCmd.CloseStreamsWithError(nil)
+			execCmd.SetExitCode(1)
+			return
+		}
+		if result := value.String(); result != "" {
+			fmt.Fprintln(execCmd.Stdout(), result)
+		}
+		execCmd.CloseStreamsWithError(nil)
+		execCmd.SetExitCode(0)
+	}()
+	return execCmd, nil
+}
+
+const serverDescription = `
+This is a Prometheus server. It contains the server's scrape targets, grouped by
+job, and its active alerts. Its metadata is the server's build and runtime info.
+
+Exec runs an instant query, e.g.
+  wexec prometheus/prod -- 'rate(http_requests_total[5m])'
+`
@@ -0,0 +1,59 @@
+package prometheus
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func exec(t *testing.T, s *server, cmd string, args ...string) (string, string, int) {
+	execCmd, err := s.Exec(context.Background(), cmd, args, plugin.ExecOptions{})
+	require.NoError(t, err)
+
+	var stdout, stderr strings.Builder
+	for chunk := range execCmd.OutputCh() {
+		require.NoError(t, chunk.Err)
+		if chunk.StreamID == plugin.Stdout {
+			stdout.WriteString(chunk.Data)
+		} else {
+			stderr.WriteString(chunk.Data)
+		}
+	}
+	exitCode, err := execCmd.ExitCode()
+	require.NoError(t, err)
+	return stdout.String(), stderr.String(), exitCode
+}
+
+func TestServerExec(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/query", r.URL.Path)
+		require.NoError(t, r.ParseForm())
+		switch r.Form.Get("query") {
+		case "sum(rate(http_requests_total[5m]))":
+			_, _ = w.Write([]byte(`{"status":"success","warnings":["slow"],"data":{"resultType":"vector","result":[{"metric":{"job":"api"},"value":[1600000000,"4.5"]}]}}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"status":"error","errorType":"bad_data","error":"parse error"}`))
+		}
+	}))
+	defer server.Close()
+
+	s, err := newServer("test", server.URL)
+	require.NoError(t, err)
+
+	stdout, stderr, exitCode := exec(t, s, "sum(rate(http_requests_total[5m]))")
+	assert.Equal(t, "{job=\"api\"} => 4.5 @[1600000000]\n", stdout)
+	assert.Equal(t, "warning: slow\n", stderr)
+	assert.Equal(t, 0, exitCode)
+
+	stdout, stderr, exitCode = exec(t, s, "sum(", "x")
+	assert.Empty(t, stdout)
+	assert.Equal(t, "bad_data: parse error\n", stderr)
+	assert.Equal(t, 1, exitCode)
+}
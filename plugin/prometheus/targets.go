@@ -0,0 +1,126 @@
+package prometheus
+
+import (
+	"context"
+	"net/url"
+
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// targetsDir lists a server's active targets, grouped by job.
+type targetsDir struct {
+	plugin.EntryBase
+	server *server
+}
+
+func newTargetsDir(s *server) *targetsDir {
+	d := &targetsDir{
+		EntryBase: plugin.NewEntry("targets"),
+	}
+	d.server = s
+	return d
+}
+
+func (d *targetsDir) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(d, "targets").IsSingleton()
+}
+
+func (d *targetsDir) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&job{}).Schema(),
+	}
+}
+
+func (d *targetsDir) List(ctx context.Context) ([]plugin.Entry, error) {
+	result, err := d.server.api.Targets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var jobs []plugin.Entry
+	jobsByName := make(map[string]*job)
+	for _, target := range result.Active {
+		j, ok := jobsByName[target.ScrapePool]
+		if !ok {
+			j = newJob(target.ScrapePool)
+			jobsByName[target.ScrapePool] = j
+			jobs = append(jobs, j)
+		}
+		j.targets = append(j.targets, target)
+	}
+	activity.Record(ctx, "Listing %v targets in %v jobs", len(result.Active), len(jobs))
+	return jobs, nil
+}
+
+// job is a scrape job, i.e. a scrape pool. It lists the job's targets.
+type job struct {
+	plugin.EntryBase
+	targets []promv1.ActiveTarget
+}
+
+func newJob(name string) *job {
+	j := &job{
+		EntryBase: plugin.NewEntry(name),
+	}
+	// The targets are fetched when the targets directory is listed.
+	j.DisableCachingFor(plugin.ListOp)
+	return j
+}
+
+func (j *job) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(j, "job")
+}
+
+func (j *job) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&target{}).Schema(),
+	}
+}
+
+func (j *job) List(ctx context.Context) ([]plugin.Entry, error) {
+	entries := make([]plugin.Entry, len(j.targets))
+	for i, t := range j.targets {
+		entries[i] = newTarget(t)
+	}
+	return entries, nil
+}
+
+// target is a scrape target. It's named by its instance label.
+type target struct {
+	plugin.EntryBase
+}
+
+func newTarget(t promv1.ActiveTarget) *target {
+	name := string(t.Labels[model.InstanceLabel])
+	if name == "" {
+		if u, err := url.Parse(t.ScrapeURL); err == nil {
+			name = u.Host
+		}
+	}
+	tgt := &target{
+		EntryBase: plugin.NewEntry(name),
+	}
+	tgt.
+		SetPartialMetadata(t).
+		Attributes().
+		SetMtime(t.LastScrape).
+		SetCustom("health", string(t.Health))
+	return tgt
+}
+
+func (t *target) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(t, "target").
+		SetDescription(targetDescription).
+		SetPartialMetadataSchema(promv1.ActiveTarget{}).
+		AddCustomAttribute("health", plugin.StringAttribute, "The target's health, i.e. up, down or unknown")
+}
+
+const targetDescription = `
+This is a Prometheus scrape target, named by its instance label. Its metadata
+includes its labels, scrape URL and last scrape error, and its mtime is when it
+was last scraped.
+`
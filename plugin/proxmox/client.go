@@ -0,0 +1,152 @@
+package proxmox
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/websocket"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// client is a minimal client for Proxmox VE's JSON API. It authenticates with
+// an API token, whose format is USER@REALM!TOKENID=SECRET.
+type client struct {
+	baseURL *url.URL
+	token   string
+	http    *http.Client
+	dialer  *websocket.Dialer
+}
+
+func newClient(address string, token string, insecure bool) (*client, error) {
+	baseURL, err := url.Parse(strings.TrimSuffix(address, "/"))
+	if err != nil {
+		return nil, err
+	}
+	if baseURL.Scheme != "https" && baseURL.Scheme != "http" {
+		return nil, fmt.Errorf("%v must be an http or https URL", address)
+	}
+	baseURL.Path += "/api2/json"
+
+	// Proxmox VE uses a self-signed certificate by default.
+	transport := plugin.HTTPTransport()
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+	transport.TLSClientConfig.InsecureSkipVerify = insecure
+	return &client{
+		baseURL: baseURL,
+		token:   token,
+		http:    &http.Client{Transport: transport},
+		dialer:  &websocket.Dialer{Proxy: transport.Proxy, TLSClientConfig: transport.TLSClientConfig},
+	}, nil
+}
+
+func (c *client) header() http.Header {
+	return http.Header{"Authorization": []string{"PVEAPIToken=" + c.token}}
+}
+
+// get gets path and decodes the response's data into out.
+func (c *client) get(ctx context.Context, path string, params url.Values, out interface{}) error {
+	return c.do(ctx, http.MethodGet, path, params, out)
+}
+
+// post posts params to path and decodes the response's data into out. out can
+// be nil if the data isn't needed.
+func (c *client) post(ctx context.Context, path string, params url.Values, out interface{}) error {
+	return c.do(ctx, http.MethodPost, path, params, out)
+}
+
+func (c *client) do(ctx context.Context, method string, path string, params url.Values, out interface{}) error {
+	u := *c.baseURL
+	u.Path += path
+	var body io.Reader
+	if method == http.MethodGet {
+		u.RawQuery = params.Encode()
+	} else {
+		body = strings.NewReader(params.Encode())
+	}
+	req, err := http.NewRequest(method, u.String(), body)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header = c.header()
+	if body != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var envelope struct {
+		Data   json.RawMessage   `json:"data"`
+		Errors map[string]string `json:"errors"`
+	}
+	decodeErr := json.NewDecoder(resp.Body).Decode(&envelope)
+	if resp.StatusCode != http.StatusOK {
+		// Proxmox VE puts the error's message in the status line, and parameter
+		// errors in the body.
+		if len(envelope.Errors) > 0 {
+			return fmt.Errorf("%v %v: %v: %v", method, path, resp.Status, envelope.Errors)
+		}
+		return fmt.Errorf("%v %v: %v", method, path, resp.Status)
+	}
+	if decodeErr != nil {
+		return fmt.Errorf("could not decode the response to %v %v: %v", method, path, decodeErr)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(envelope.Data, out)
+}
+
+// websocket opens a websocket to path.
+func (c *client) websocket(ctx context.Context, path string, params url.Values) (*websocket.Conn, error) {
+	u := *c.baseURL
+	u.Path += path
+	u.RawQuery = params.Encode()
+	if u.Scheme == "https" {
+		u.Scheme = "wss"
+	} else {
+		u.Scheme = "ws"
+	}
+	conn, resp, err := c.dialer.DialContext(ctx, u.String(), c.header())
+	if err != nil {
+		if resp != nil {
+			return nil, fmt.Errorf("could not open %v: %v", path, resp.Status)
+		}
+		return nil, err
+	}
+	return conn, nil
+}
+
+// vmid is a guest's ID. The API returns it as a number for VMs and as a string
+// for containers.
+type vmid int
+
+func (id *vmid) UnmarshalJSON(b []byte) error {
+	var n json.Number
+	if err := json.Unmarshal(b, &n); err != nil {
+		return err
+	}
+	i, err := strconv.Atoi(n.String())
+	if err != nil {
+		return fmt.Errorf("invalid VMID %v: %v", n, err)
+	}
+	*id = vmid(i)
+	return nil
+}
+
+func (id vmid) String() string {
+	return strconv.Itoa(int(id))
+}
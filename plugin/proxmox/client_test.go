@@ -0,0 +1,87 @@
+package proxmox
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRoot(t *testing.T, handler http.HandlerFunc) (*Root, *httptest.Server) {
+	server := httptest.NewServer(handler)
+	client, err := newClient(server.URL+"/", "wash@pve!test=secret", false)
+	require.NoError(t, err)
+	return &Root{EntryBase: plugin.NewEntry("proxmox"), client: client, sshUser: "root"}, server
+}
+
+func TestListNodesAndGuests(t *testing.T) {
+	responses := map[string]string{
+		"/api2/json/nodes":           `{"data": [{"node": "pve2", "status": "offline"}, {"node": "pve1", "status": "online", "uptime": 60}]}`,
+		"/api2/json/nodes/pve1/qemu": `{"data": [{"vmid": 101, "name": "db", "status": "stopped"}, {"vmid": 100, "status": "running", "maxdisk": 1024}]}`,
+		"/api2/json/nodes/pve1/lxc":  `{"data": [{"vmid": "200", "name": "proxy", "status": "running"}]}`,
+	}
+	root, server := newTestRoot(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PVEAPIToken=wash@pve!test=secret", r.Header.Get("Authorization"))
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	})
+	defer server.Close()
+	ctx := context.Background()
+
+	entries, err := root.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	pve1 := entries[0].(*node)
+	assert.Equal(t, "pve1", plugin.Name(pve1))
+	assert.Equal(t, "pve2", plugin.Name(entries[1]))
+
+	vms, err := newVMsDir(pve1).List(ctx)
+	require.NoError(t, err)
+	require.Len(t, vms, 2)
+	// Unnamed guests are named by their VMID.
+	vm100 := vms[0].(*vm)
+	assert.Equal(t, "100", plugin.Name(vm100))
+	assert.Equal(t, "/nodes/pve1/qemu/100", vm100.path())
+	attr := plugin.Attributes(vm100)
+	assert.Equal(t, uint64(1024), attr.Size())
+	assert.Equal(t, "db", plugin.Name(vms[1]))
+
+	containers, err := newContainersDir(pve1).List(ctx)
+	require.NoError(t, err)
+	require.Len(t, containers, 1)
+	proxy := containers[0].(*container)
+	assert.Equal(t, "proxy", plugin.Name(proxy))
+	assert.Equal(t, vmid(200), proxy.id)
+}
+
+func TestSignalAndErrors(t *testing.T) {
+	var posted []string
+	root, server := newTestRoot(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			posted = append(posted, r.URL.Path)
+			_, _ = w.Write([]byte(`{"data": "UPID:pve1:0001"}`))
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"data": null, "errors": {"vmid": "invalid format"}}`))
+	})
+	defer server.Close()
+	ctx := context.Background()
+
+	n := newNode(root, nodeInfo{Node: "pve1"})
+	c := &container{newGuest(n, lxc, guestInfo{VMID: 200, Name: "proxy"})}
+	require.NoError(t, c.Signal(ctx, "shutdown"))
+	assert.Equal(t, []string{"/api2/json/nodes/pve1/lxc/200/status/shutdown"}, posted)
+	assert.EqualError(t, c.Signal(ctx, "reset"), "unsupported signal reset")
+
+	_, err := c.Metadata(ctx)
+	assert.EqualError(t, err, "GET /nodes/pve1/lxc/200/status/current: 500 Internal Server Error: map[vmid:invalid format]")
+}
@@ -0,0 +1,154 @@
+package proxmox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// consolePingInterval is how often a console stream pings the terminal proxy.
+// The proxy closes idle connections.
+const consolePingInterval = 30 * time.Second
+
+// console streams a guest's console via the API's terminal proxy, which is what
+// the web UI's xterm.js console uses. The proxy only sends new output, so the
+// console can't be read.
+type console struct {
+	plugin.EntryBase
+	guest *guest
+}
+
+func newConsole(g *guest) *console {
+	c := &console{
+		EntryBase: plugin.NewEntry("console"),
+	}
+	c.guest = g
+	return c
+}
+
+func (c *console) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(c, "console").
+		SetDescription(consoleDescription).
+		IsSingleton()
+}
+
+// Stream starts a terminal proxy for the guest's console and connects to it.
+func (c *console) Stream(ctx context.Context) (io.ReadCloser, error) {
+	client := c.guest.node.root.client
+	params := url.Values{}
+	if c.guest.kind == qemu {
+		params.Set("serial", "serial0")
+	}
+	var proxy struct {
+		Port   json.Number `json:"port"`
+		Ticket string      `json:"ticket"`
+		User   string      `json:"user"`
+	}
+	if err := client.post(ctx, c.guest.path()+"/termproxy", params, &proxy); err != nil {
+		return nil, err
+	}
+
+	activity.Record(ctx, "Connecting to the console of %v %v", c.guest.kind, c.guest.id)
+	conn, err := client.websocket(ctx, c.guest.path()+"/vncwebsocket", url.Values{
+		"port":      []string{proxy.Port.String()},
+		"vncticket": []string{proxy.Ticket},
+	})
+	if err != nil {
+		return nil, err
+	}
+	// The proxy authenticates the connection with the ticket, then acknowledges
+	// it with OK.
+	if err := conn.WriteMessage(websocket.TextMessage, []byte(proxy.User+":"+proxy.Ticket+"\n")); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	_, msg, err := conn.ReadMessage()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if string(msg) != "OK" {
+		conn.Close()
+		return nil, fmt.Errorf("the console's terminal proxy rejected the connection: %s", msg)
+	}
+
+	r := &consoleReader{conn: conn, done: make(chan struct{})}
+	go r.keepalive(ctx)
+	return r, nil
+}
+
+// consoleReader reads the output from a terminal proxy's websocket. It returns
+// io.EOF once the websocket's closed.
+type consoleReader struct {
+	conn      *websocket.Conn
+	buf       []byte
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// keepalive pings the proxy until the reader's closed, and closes the reader
+// when ctx is done.
+func (r *consoleReader) keepalive(ctx context.Context) {
+	ticker := time.NewTicker(consolePingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.done:
+			return
+		case <-ctx.Done():
+			r.Close()
+			return
+		case <-ticker.C:
+			// "2" is the terminal proxy's ping message.
+			if err := r.conn.WriteMessage(websocket.TextMessage, []byte("2")); err != nil {
+				activity.Record(ctx, "Could not ping the console: %v", err)
+			}
+		}
+	}
+}
+
+func (r *consoleReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		_, msg, err := r.conn.ReadMessage()
+		if err != nil {
+			select {
+			case <-r.done:
+				return 0, io.EOF
+			default:
+			}
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				return 0, io.EOF
+			}
+			return 0, err
+		}
+		r.buf = msg
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+func (r *consoleReader) Close() error {
+	var err error
+	r.closeOnce.Do(func() {
+		close(r.done)
+		err = r.conn.Close()
+	})
+	return err
+}
+
+const consoleDescription = `
+This is the guest's console. Streaming it (e.g. with 'tail -f') shows its new
+output. A VM's console is its first serial port (serial0), so the VM needs a
+serial port that its OS uses as a console. The API token needs the VM.Console
+privilege.
+`
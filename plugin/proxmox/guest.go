@@ -0,0 +1,191 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/puppetlabs/wash/transport"
+)
+
+// guest has what's shared by VMs and containers. The API handles both kinds
+// of guest under the same paths, which only differ by their kind.
+type guest struct {
+	plugin.EntryBase
+	node *node
+	kind guestKind
+	id   vmid
+}
+
+// guestInfo is a guest's status from its node's list of guests.
+type guestInfo struct {
+	VMID    vmid    `json:"vmid"`
+	Name    string  `json:"name"`
+	Status  string  `json:"status"`
+	CPUs    float64 `json:"cpus"`
+	Mem     uint64  `json:"mem"`
+	MaxMem  uint64  `json:"maxmem"`
+	MaxDisk uint64  `json:"maxdisk"`
+	Uptime  int64   `json:"uptime"`
+	Lock    string  `json:"lock,omitempty"`
+	Tags    string  `json:"tags,omitempty"`
+}
+
+func newGuest(n *node, kind guestKind, info guestInfo) guest {
+	// Guests can be created without a name.
+	name := info.Name
+	if name == "" {
+		name = info.VMID.String()
+	}
+	g := guest{
+		EntryBase: plugin.NewEntry(name),
+	}
+	g.node = n
+	g.kind = kind
+	g.id = info.VMID
+	attr := g.
+		SetPartialMetadata(info).
+		Attributes()
+	attr.
+		SetSize(info.MaxDisk).
+		SetCustom("vmid", int(info.VMID)).
+		SetCustom("status", info.Status)
+	if info.Uptime > 0 {
+		attr.SetCrtime(time.Now().Add(-time.Duration(info.Uptime) * time.Second))
+	}
+	return g
+}
+
+// path is the guest's API path.
+func (g *guest) path() string {
+	return "/nodes/" + g.node.Name() + "/" + string(g.kind) + "/" + g.id.String()
+}
+
+func (g *guest) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&plugin.MetadataJSONFile{}).Schema(),
+		(&console{}).Schema(),
+	}
+}
+
+// list lists the guest's children. entry is the VM or container that embeds
+// the guest.
+func (g *guest) list(ctx context.Context, entry plugin.Entry) ([]plugin.Entry, error) {
+	meta, err := plugin.NewMetadataJSONFile(ctx, entry)
+	if err != nil {
+		return nil, err
+	}
+	return []plugin.Entry{meta, newConsole(g)}, nil
+}
+
+// Metadata returns the guest's current status, which includes its resource
+// usage.
+func (g *guest) Metadata(ctx context.Context) (plugin.JSONObject, error) {
+	var status plugin.JSONObject
+	if err := g.node.root.client.get(ctx, g.path()+"/status/current", nil, &status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// signal posts one of the guest's status changes. The signals are named after
+// them.
+func (g *guest) signal(ctx context.Context, signal string, supported []string) error {
+	for _, s := range supported {
+		if s == signal {
+			activity.Record(ctx, "Sending %v to %v %v", signal, g.kind, g.id)
+			return g.node.root.client.post(ctx, g.path()+"/status/"+signal, nil, nil)
+		}
+	}
+	return fmt.Errorf("unsupported signal %v", signal)
+}
+
+type vm struct {
+	guest
+}
+
+var vmSignals = []string{"start", "stop", "shutdown", "reboot", "reset", "suspend", "resume"}
+
+func (v *vm) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(v, "vm").
+		SetDescription(vmDescription).
+		SetPartialMetadataSchema(guestInfo{}).
+		AddCustomAttribute("vmid", plugin.NumberAttribute, "The VM's ID").
+		AddCustomAttribute("status", plugin.StringAttribute, "The VM's status, e.g. running or stopped").
+		AddSignal("start", "Starts the VM").
+		AddSignal("stop", "Stops the VM immediately, like pulling its power cord").
+		AddSignal("shutdown", "Shuts down the VM gracefully via ACPI or the guest agent").
+		AddSignal("reboot", "Reboots the VM gracefully").
+		AddSignal("reset", "Resets the VM, similar to doing a hard-reset on your computer").
+		AddSignal("suspend", "Pauses the VM. Its state stays in memory").
+		AddSignal("resume", "Resumes a suspended VM")
+}
+
+func (v *vm) List(ctx context.Context) ([]plugin.Entry, error) {
+	return v.list(ctx, v)
+}
+
+func (v *vm) Signal(ctx context.Context, signal string) error {
+	return v.signal(ctx, signal, vmSignals)
+}
+
+type container struct {
+	guest
+}
+
+var containerSignals = []string{"start", "stop", "shutdown", "reboot"}
+
+func (c *container) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(c, "container").
+		SetDescription(containerDescription).
+		SetPartialMetadataSchema(guestInfo{}).
+		AddCustomAttribute("vmid", plugin.NumberAttribute, "The container's ID").
+		AddCustomAttribute("status", plugin.StringAttribute, "The container's status, e.g. running or stopped").
+		AddSignal("start", "Starts the container").
+		AddSignal("stop", "Stops the container immediately").
+		AddSignal("shutdown", "Shuts down the container gracefully").
+		AddSignal("reboot", "Reboots the container")
+}
+
+func (c *container) List(ctx context.Context) ([]plugin.Entry, error) {
+	return c.list(ctx, c)
+}
+
+func (c *container) Signal(ctx context.Context, signal string) error {
+	return c.signal(ctx, signal, containerSignals)
+}
+
+// Exec runs the command with 'pct exec' on the container's node. The API
+// doesn't support running commands in containers.
+func (c *container) Exec(ctx context.Context, cmd string, args []string, opts plugin.ExecOptions) (plugin.ExecCommand, error) {
+	id := transport.Identity{Host: c.node.Name(), User: c.node.root.sshUser}
+	// pct needs root on the node.
+	opts.Elevate = id.User != "root"
+	command := append([]string{"pct", "exec", c.id.String(), "--", cmd}, args...)
+	return transport.ExecSSH(ctx, id, command, opts)
+}
+
+const vmDescription = `
+This is a QEMU VM. Its metadata is the VM's current status, and its console
+streams the VM's serial console. It supports the start, stop, shutdown, reboot,
+reset, suspend and resume signals, e.g.
+
+  signal shutdown proxmox/pve/vms/web01
+
+Its crtime is when the VM started.
+`
+
+const containerDescription = `
+This is an LXC container. Its metadata is the container's current status, and
+its console streams the container's console. It supports the start, stop,
+shutdown and reboot signals.
+
+Its Exec action runs 'pct exec' on the container's node over SSH, so the node
+must be reachable via its name (or a Host entry in ~/.ssh/config). The SSH user
+is set by the proxmox.ssh_user config. Users other than root need passwordless
+sudo on the node.
+`
@@ -0,0 +1,102 @@
+package proxmox
+
+import (
+	"context"
+	"sort"
+
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// guestKind is the API's name for a type of guest.
+type guestKind string
+
+const (
+	qemu guestKind = "qemu"
+	lxc  guestKind = "lxc"
+)
+
+// guestsDir lists a node's guests of one kind.
+type guestsDir struct {
+	plugin.EntryBase
+	node *node
+	kind guestKind
+}
+
+func (d *guestsDir) List(ctx context.Context) ([]plugin.Entry, error) {
+	var guests []guestInfo
+	if err := d.node.root.client.get(ctx, "/nodes/"+d.node.Name()+"/"+string(d.kind), nil, &guests); err != nil {
+		return nil, err
+	}
+	sort.Slice(guests, func(i, j int) bool {
+		return guests[i].VMID < guests[j].VMID
+	})
+
+	entries := make([]plugin.Entry, len(guests))
+	for i, info := range guests {
+		g := newGuest(d.node, d.kind, info)
+		if d.kind == qemu {
+			entries[i] = &vm{g}
+		} else {
+			entries[i] = &container{g}
+		}
+	}
+	return entries, nil
+}
+
+type vmsDir struct {
+	guestsDir
+}
+
+func newVMsDir(n *node) *vmsDir {
+	dir := &vmsDir{}
+	dir.EntryBase = plugin.NewEntry("vms")
+	dir.node = n
+	dir.kind = qemu
+	return dir
+}
+
+func (d *vmsDir) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(d, "vms").
+		SetDescription(vmsDirDescription).
+		IsSingleton()
+}
+
+func (d *vmsDir) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&vm{}).Schema(),
+	}
+}
+
+type containersDir struct {
+	guestsDir
+}
+
+func newContainersDir(n *node) *containersDir {
+	dir := &containersDir{}
+	dir.EntryBase = plugin.NewEntry("containers")
+	dir.node = n
+	dir.kind = lxc
+	return dir
+}
+
+func (d *containersDir) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(d, "containers").
+		SetDescription(containersDirDescription).
+		IsSingleton()
+}
+
+func (d *containersDir) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&container{}).Schema(),
+	}
+}
+
+const vmsDirDescription = `
+This is a node's QEMU VMs, including templates.
+`
+
+const containersDirDescription = `
+This is a node's LXC containers.
+`
@@ -0,0 +1,85 @@
+package proxmox
+
+import (
+	"context"
+	"time"
+
+	"github.com/puppetlabs/wash/plugin"
+)
+
+type node struct {
+	plugin.EntryBase
+	root *Root
+}
+
+// nodeInfo is a node's status from the nodes list.
+type nodeInfo struct {
+	Node    string  `json:"node"`
+	Status  string  `json:"status"`
+	CPU     float64 `json:"cpu"`
+	MaxCPU  int     `json:"maxcpu"`
+	Mem     uint64  `json:"mem"`
+	MaxMem  uint64  `json:"maxmem"`
+	Disk    uint64  `json:"disk"`
+	MaxDisk uint64  `json:"maxdisk"`
+	Uptime  int64   `json:"uptime"`
+}
+
+func newNode(r *Root, info nodeInfo) *node {
+	n := &node{
+		EntryBase: plugin.NewEntry(info.Node),
+	}
+	n.root = r
+	attr := n.
+		SetPartialMetadata(info).
+		Attributes()
+	attr.SetCustom("status", info.Status)
+	if info.Uptime > 0 {
+		attr.SetCrtime(time.Now().Add(-time.Duration(info.Uptime) * time.Second))
+	}
+	return n
+}
+
+func (n *node) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(n, "node").
+		SetDescription(nodeDescription).
+		SetPartialMetadataSchema(nodeInfo{}).
+		AddCustomAttribute("status", plugin.StringAttribute, "The node's status, i.e. online or offline")
+}
+
+func (n *node) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&plugin.MetadataJSONFile{}).Schema(),
+		(&vmsDir{}).Schema(),
+		(&containersDir{}).Schema(),
+	}
+}
+
+func (n *node) List(ctx context.Context) ([]plugin.Entry, error) {
+	meta, err := plugin.NewMetadataJSONFile(ctx, n)
+	if err != nil {
+		return nil, err
+	}
+	return []plugin.Entry{
+		meta,
+		newVMsDir(n),
+		newContainersDir(n),
+	}, nil
+}
+
+// Metadata returns the node's detailed status, which includes its versions,
+// CPU info and load average.
+func (n *node) Metadata(ctx context.Context) (plugin.JSONObject, error) {
+	var status plugin.JSONObject
+	if err := n.root.client.get(ctx, "/nodes/"+n.Name()+"/status", nil, &status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+const nodeDescription = `
+This is a Proxmox VE node. Its vms and containers directories list its QEMU VMs
+and LXC containers. Its metadata is the node's detailed status. Its crtime is
+when the node booted.
+`
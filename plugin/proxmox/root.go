@@ -0,0 +1,116 @@
+// Package proxmox presents a filesystem hierarchy for Proxmox VE nodes and
+// their QEMU VMs and LXC containers.
+//
+// It uses an API token to access Proxmox VE. The API's URL and token can be set
+// by the PROXMOX_URL and PROXMOX_TOKEN environment variables, or by the plugin's
+// config.
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// Root of the Proxmox plugin
+type Root struct {
+	plugin.EntryBase
+	client  *client
+	sshUser string
+}
+
+// Init for root
+func (r *Root) Init(cfg map[string]interface{}) error {
+	address, token := os.Getenv("PROXMOX_URL"), os.Getenv("PROXMOX_TOKEN")
+	if addressI, ok := cfg["url"]; ok {
+		if address, ok = addressI.(string); !ok {
+			return fmt.Errorf("proxmox.url config must be a string, not %v", addressI)
+		}
+	}
+	if tokenI, ok := cfg["token"]; ok {
+		if token, ok = tokenI.(string); !ok {
+			return fmt.Errorf("proxmox.token config must be a string, not %v", tokenI)
+		}
+	}
+	var insecure bool
+	if insecureI, ok := cfg["insecure"]; ok {
+		if insecure, ok = insecureI.(bool); !ok {
+			return fmt.Errorf("proxmox.insecure config must be a boolean, not %v", insecureI)
+		}
+	}
+	r.sshUser = "root"
+	if sshUserI, ok := cfg["ssh_user"]; ok {
+		if r.sshUser, ok = sshUserI.(string); !ok {
+			return fmt.Errorf("proxmox.ssh_user config must be a string, not %v", sshUserI)
+		}
+	}
+
+	if address == "" {
+		return fmt.Errorf("no Proxmox VE URL found; set PROXMOX_URL or the proxmox.url config")
+	}
+	if token == "" {
+		return fmt.Errorf("no Proxmox VE API token found; set PROXMOX_TOKEN or the proxmox.token config")
+	}
+	client, err := newClient(address, token, insecure)
+	if err != nil {
+		return fmt.Errorf("invalid Proxmox VE URL: %v", err)
+	}
+	r.client = client
+
+	r.EntryBase = plugin.NewEntry("proxmox")
+	return nil
+}
+
+// Schema returns the root's schema
+func (r *Root) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(r, "proxmox").
+		SetDescription(rootDescription).
+		IsSingleton()
+}
+
+// ChildSchemas returns the root's child schema
+func (r *Root) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&node{}).Schema(),
+	}
+}
+
+// List lists the cluster's nodes.
+func (r *Root) List(ctx context.Context) ([]plugin.Entry, error) {
+	var nodes []nodeInfo
+	if err := r.client.get(ctx, "/nodes", nil, &nodes); err != nil {
+		return nil, err
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Node < nodes[j].Node
+	})
+
+	entries := make([]plugin.Entry, len(nodes))
+	for i, info := range nodes {
+		entries[i] = newNode(r, info)
+	}
+	return entries, nil
+}
+
+const rootDescription = `
+This is the Proxmox VE plugin root. It lists the cluster's nodes, and each
+node's QEMU VMs and LXC containers.
+
+The plugin uses an API token, which is found via the PROXMOX_URL and
+PROXMOX_TOKEN environment variables or the plugin's config. The token's format
+is USER@REALM!TOKENID=SECRET. Proxmox VE uses a self-signed certificate by
+default, so set the insecure config to skip verifying it, e.g.
+
+proxmox:
+  url: https://pve.example.com:8006
+  token: wash@pve!wash=aaaaaaaa-bbbb-cccc-dddd-eeeeeeeeeeee
+  insecure: true
+
+The API can't run commands in containers, so a container's Exec action runs
+'pct exec' on its node over SSH. The SSH user is set by the ssh_user config,
+which defaults to root.
+`
@@ -0,0 +1,80 @@
+package plugin
+
+import (
+	"context"
+	"sync"
+)
+
+// RangePrefetcher serves ranged reads of an entry's content from the most
+// recently fetched chunk of that content. FUSE reads a file in small blocks,
+// so fetching each block with its own request would make sequential reads of
+// large content very slow. Instead, a read that misses the chunk fetches at
+// least readAhead bytes so that subsequent reads can be served from it.
+//
+// BlockReadable entries embed a RangePrefetcher and call its Read method from
+// their own Read. Its zero value is ready to use.
+type RangePrefetcher struct {
+	mux sync.Mutex
+	// chunk is the most recently fetched chunk of the content, starting at
+	// chunkOffset.
+	chunk       []byte
+	chunkOffset int64
+}
+
+// RangeFetcher fetches the given range of an entry's content.
+type RangeFetcher = func(ctx context.Context, size int64, offset int64) ([]byte, error)
+
+// Read reads the requested range of content that's contentSize bytes long.
+// It's served from the prefetched chunk when possible. Otherwise, fetch is
+// called to fetch a new chunk of at least readAhead bytes starting at offset.
+func (p *RangePrefetcher) Read(ctx context.Context, size int64, offset int64, contentSize int64, readAhead int64, fetch RangeFetcher) ([]byte, error) {
+	if size == 0 || offset >= contentSize {
+		return []byte{}, nil
+	}
+
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	if data, ok := p.readChunk(size, offset, contentSize); ok {
+		return data, nil
+	}
+
+	chunkSize := size
+	if chunkSize < readAhead {
+		chunkSize = readAhead
+	}
+	chunk, err := fetch(ctx, chunkSize, offset)
+	if err != nil {
+		return nil, err
+	}
+	p.chunk = chunk
+	p.chunkOffset = offset
+
+	if int64(len(chunk)) > size {
+		chunk = chunk[:size]
+	}
+	return chunk, nil
+}
+
+// readChunk returns the requested range from the prefetched chunk. It returns
+// false if the chunk doesn't contain the range. Ranges that extend past the end
+// of the content only need to be contained up to its end.
+func (p *RangePrefetcher) readChunk(size int64, offset int64, contentSize int64) ([]byte, bool) {
+	start := offset - p.chunkOffset
+	end := start + size
+	if p.chunkOffset+end > contentSize {
+		end = contentSize - p.chunkOffset
+	}
+	if start < 0 || end > int64(len(p.chunk)) {
+		return nil, false
+	}
+	return p.chunk[start:end], true
+}
+
+// Reset discards the prefetched chunk. Call it when the content changes, e.g.
+// after it's written.
+func (p *RangePrefetcher) Reset() {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	p.chunk = nil
+	p.chunkOffset = 0
+}
@@ -0,0 +1,70 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRangePrefetcher(t *testing.T) {
+	content := "hello world"
+	contentSize := int64(len(content))
+	var fetches int
+	fetch := func(ctx context.Context, size int64, offset int64) ([]byte, error) {
+		fetches++
+		end := offset + size
+		if end > contentSize {
+			end = contentSize
+		}
+		return []byte(content[offset:end]), nil
+	}
+	read := func(p *RangePrefetcher, size int64, offset int64) string {
+		data, err := p.Read(context.Background(), size, offset, contentSize, 4, fetch)
+		assert.NoError(t, err)
+		return string(data)
+	}
+
+	var p RangePrefetcher
+	assert.Equal(t, "he", read(&p, 2, 0))
+	// The rest of the read-ahead chunk is served without another fetch.
+	assert.Equal(t, "ll", read(&p, 2, 2))
+	assert.Equal(t, 1, fetches)
+
+	// Reads that start before or extend past the chunk fetch a new one.
+	assert.Equal(t, "llo ", read(&p, 4, 2))
+	assert.Equal(t, 2, fetches)
+	assert.Equal(t, "he", read(&p, 2, 0))
+	assert.Equal(t, 3, fetches)
+
+	// Reads past the end of the content only need the chunk to contain the
+	// content up to its end.
+	assert.Equal(t, "world", read(&p, 10, 6))
+	assert.Equal(t, 4, fetches)
+	assert.Equal(t, "ld", read(&p, 10, 9))
+	assert.Equal(t, 4, fetches)
+	assert.Equal(t, "", read(&p, 10, 11))
+	assert.Equal(t, "", read(&p, 0, 0))
+	assert.Equal(t, 4, fetches)
+
+	p.Reset()
+	assert.Equal(t, "ld", read(&p, 10, 9))
+	assert.Equal(t, 5, fetches)
+}
+
+func TestRangePrefetcher_FetchErrorsAreNotCached(t *testing.T) {
+	var p RangePrefetcher
+	fetchErr := errors.New("fetch failed")
+	_, err := p.Read(context.Background(), 2, 0, 10, 4, func(context.Context, int64, int64) ([]byte, error) {
+		return nil, fetchErr
+	})
+	assert.Equal(t, fetchErr, err)
+
+	data, err := p.Read(context.Background(), 2, 0, 10, 4, func(context.Context, int64, int64) ([]byte, error) {
+		return []byte("abcd"), nil
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "ab", string(data))
+	}
+}
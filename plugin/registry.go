@@ -3,8 +3,11 @@ package plugin
 import (
 	"context"
 	"fmt"
+	"io"
 	"regexp"
 	"sync"
+
+	log "github.com/sirupsen/logrus"
 )
 
 // Registry represents the plugin registry. It is also Wash's root.
@@ -13,13 +16,25 @@ type Registry struct {
 	mux         sync.Mutex
 	plugins     map[string]Root
 	pluginRoots []Entry
+	// registrations includes the disabled plugins, so that they can be
+	// enabled again.
+	registrations map[string]registration
+}
+
+// registration is how a plugin was registered. Root is the plugin's root
+// before it was initialized, so it's not a stub root if its Init failed.
+type registration struct {
+	root    Root
+	config  map[string]interface{}
+	enabled bool
 }
 
 // NewRegistry creates a new plugin registry object
 func NewRegistry() *Registry {
 	r := &Registry{
-		EntryBase: NewEntry("/"),
-		plugins:   make(map[string]Root),
+		EntryBase:     NewEntry("/"),
+		plugins:       make(map[string]Root),
+		registrations: make(map[string]registration),
 	}
 	r.eb().id = "/"
 	r.DisableDefaultCaching()
@@ -27,10 +42,29 @@ func NewRegistry() *Registry {
 	return r
 }
 
-// Plugins returns a map of the currently registered plugins. It should not be called
-// while registering plugins.
+// Plugins returns a map of the currently registered plugins. Disabled plugins
+// aren't included.
 func (r *Registry) Plugins() map[string]Root {
-	return r.plugins
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	plugins := make(map[string]Root, len(r.plugins))
+	for name, root := range r.plugins {
+		plugins[name] = root
+	}
+	return plugins
+}
+
+// DisabledPlugins returns the names of the disabled plugins.
+func (r *Registry) DisabledPlugins() []string {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	var names []string
+	for name, reg := range r.registrations {
+		if !reg.enabled {
+			names = append(names, name)
+		}
+	}
+	return names
 }
 
 var pluginNameRegex = regexp.MustCompile("^[0-9a-zA-Z_-]+$")
@@ -38,6 +72,7 @@ var pluginNameRegex = regexp.MustCompile("^[0-9a-zA-Z_-]+$")
 // RegisterPlugin initializes the given plugin and adds it to the registry if
 // initialization was successful.
 func (r *Registry) RegisterPlugin(root Root, config map[string]interface{}) error {
+	originalRoot := root
 	registerPlugin := func(initSucceeded bool) {
 		r.mux.Lock()
 		if initSucceeded {
@@ -59,6 +94,7 @@ func (r *Registry) RegisterPlugin(root Root, config map[string]interface{}) erro
 
 		r.plugins[root.eb().name] = root
 		r.pluginRoots = append(r.pluginRoots, root)
+		r.registrations[root.eb().name] = registration{root: originalRoot, config: config, enabled: true}
 		r.mux.Unlock()
 	}
 
@@ -80,6 +116,99 @@ func (r *Registry) RegisterPlugin(root Root, config map[string]interface{}) erro
 	return nil
 }
 
+// InstallPlugin registers a plugin while Wash is running. Unlike
+// RegisterPlugin, it returns an error if the plugin's name is invalid or is
+// already taken, since the plugin's provided by the user.
+func (r *Registry) InstallPlugin(root Root, config map[string]interface{}) error {
+	name := root.eb().name
+	if !pluginNameRegex.MatchString(name) {
+		return fmt.Errorf("invalid plugin name %v. The plugin name must consist of alphanumeric characters, or a hyphen", name)
+	}
+	if DeleteAction().IsSupportedOn(root) {
+		return fmt.Errorf("the %v plugin's root implements delete", name)
+	}
+	r.mux.Lock()
+	_, ok := r.registrations[name]
+	r.mux.Unlock()
+	if ok {
+		return fmt.Errorf("the %v plugin's already been registered", name)
+	}
+	return r.RegisterPlugin(root, config)
+}
+
+// HasPlugin returns true if the plugin's registered, including if it's
+// disabled.
+func (r *Registry) HasPlugin(name string) bool {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	_, ok := r.registrations[name]
+	return ok
+}
+
+// DisablePlugin removes the plugin from Wash's root and clears its cached
+// data. The plugin's root is closed if it implements io.Closer. It can be
+// enabled again with EnablePlugin.
+func (r *Registry) DisablePlugin(name string) error {
+	r.mux.Lock()
+	reg, ok := r.registrations[name]
+	if !ok || !reg.enabled {
+		r.mux.Unlock()
+		return fmt.Errorf("the %v plugin is not enabled", name)
+	}
+	reg.enabled = false
+	r.registrations[name] = reg
+	delete(r.plugins, name)
+	// Readers can hold the old slice, so it's copied instead of modified in
+	// place.
+	pluginRoots := make([]Entry, 0, len(r.pluginRoots))
+	for _, root := range r.pluginRoots {
+		if root.eb().name != name {
+			pluginRoots = append(pluginRoots, root)
+		}
+	}
+	r.pluginRoots = pluginRoots
+	r.mux.Unlock()
+
+	if cache != nil {
+		ClearCacheFor("/"+name, false)
+	}
+	if closer, ok := reg.root.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			log.Warnf("Closing the %v plugin errored: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// EnablePlugin initializes a disabled plugin with its config and adds it
+// back to Wash's root.
+func (r *Registry) EnablePlugin(name string) error {
+	r.mux.Lock()
+	reg, ok := r.registrations[name]
+	r.mux.Unlock()
+	if !ok || reg.enabled {
+		return fmt.Errorf("the %v plugin is not disabled", name)
+	}
+	return r.RegisterPlugin(reg.root, reg.config)
+}
+
+// ReloadPlugin re-initializes the plugin with its config, clearing its
+// cached data. A disabled plugin's enabled.
+func (r *Registry) ReloadPlugin(name string) error {
+	r.mux.Lock()
+	reg, ok := r.registrations[name]
+	r.mux.Unlock()
+	if !ok {
+		return fmt.Errorf("the %v plugin does not exist", name)
+	}
+	if reg.enabled {
+		if err := r.DisablePlugin(name); err != nil {
+			return err
+		}
+	}
+	return r.EnablePlugin(name)
+}
+
 // ChildSchemas only makes sense for core plugin roots
 func (r *Registry) ChildSchemas() []*EntrySchema {
 	return nil
@@ -92,7 +221,13 @@ func (r *Registry) Schema() *EntrySchema {
 
 // List all of Wash's loaded plugins
 func (r *Registry) List(ctx context.Context) ([]Entry, error) {
-	return r.pluginRoots, nil
+	return r.roots(), nil
+}
+
+func (r *Registry) roots() []Entry {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	return r.pluginRoots
 }
 
 type stubRoot struct {
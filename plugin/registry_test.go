@@ -139,6 +139,72 @@ func (suite *RegistryTestSuite) TestRegisterPluginPluginRootImplementsDelete() {
 	suite.Panics(panicFunc, "r.RegisterPlugin: the mine plugin's root implements delete")
 }
 
+type mockClosableRoot struct {
+	*mockRoot
+	closed int
+}
+
+func (m *mockClosableRoot) Close() error {
+	m.closed++
+	return nil
+}
+
+func (suite *RegistryTestSuite) TestDisableAndEnablePlugin() {
+	reg := NewRegistry()
+	m := &mockClosableRoot{mockRoot: &mockRoot{EntryBase: NewEntry("mine")}}
+	cfg := map[string]interface{}{"key": "value"}
+	m.On("Init", cfg).Return(nil)
+	suite.NoError(reg.RegisterPlugin(m, cfg))
+
+	suite.NoError(reg.DisablePlugin("mine"))
+	suite.Equal(1, m.closed)
+	suite.NotContains(reg.Plugins(), "mine")
+	suite.Equal([]string{"mine"}, reg.DisabledPlugins())
+	suite.True(reg.HasPlugin("mine"))
+	roots, err := reg.List(context.Background())
+	suite.NoError(err)
+	suite.Empty(roots)
+	suite.EqualError(reg.DisablePlugin("mine"), "the mine plugin is not enabled")
+
+	suite.NoError(reg.EnablePlugin("mine"))
+	m.AssertNumberOfCalls(suite.T(), "Init", 2)
+	suite.Contains(reg.Plugins(), "mine")
+	suite.Empty(reg.DisabledPlugins())
+	suite.EqualError(reg.EnablePlugin("mine"), "the mine plugin is not disabled")
+}
+
+func (suite *RegistryTestSuite) TestReloadPlugin() {
+	reg := NewRegistry()
+	m := &mockClosableRoot{mockRoot: &mockRoot{EntryBase: NewEntry("mine")}}
+	m.On("Init", map[string]interface{}(nil)).Return(errors.New("failed")).Once()
+	suite.Error(reg.RegisterPlugin(m, nil))
+	_, ok := reg.Plugins()["mine"].(*stubRoot)
+	suite.True(ok, "expected a stub plugin root to be registered")
+
+	// The original root's re-initialized, not its stub.
+	m.On("Init", map[string]interface{}(nil)).Return(nil)
+	suite.NoError(reg.ReloadPlugin("mine"))
+	suite.Equal(1, m.closed)
+	suite.Equal(m, reg.Plugins()["mine"])
+
+	suite.EqualError(reg.ReloadPlugin("other"), "the other plugin does not exist")
+}
+
+func (suite *RegistryTestSuite) TestInstallPlugin() {
+	reg := NewRegistry()
+	m := &mockRoot{EntryBase: NewEntry("mine")}
+	m.On("Init", map[string]interface{}(nil)).Return(nil)
+	suite.NoError(reg.InstallPlugin(m, nil))
+	suite.Contains(reg.Plugins(), "mine")
+
+	suite.EqualError(reg.InstallPlugin(m, nil), "the mine plugin's already been registered")
+	suite.NoError(reg.DisablePlugin("mine"))
+	suite.EqualError(reg.InstallPlugin(m, nil), "the mine plugin's already been registered")
+
+	bad := &mockRoot{EntryBase: NewEntry("b@dname")}
+	suite.Regexp("invalid plugin name b@dname", reg.InstallPlugin(bad, nil))
+}
+
 func TestRegistry(t *testing.T) {
 	suite.Run(t, new(RegistryTestSuite))
 }
@@ -0,0 +1,218 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Benchkram/errz"
+	"github.com/emirpasic/gods/maps/linkedhashmap"
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/api/client"
+	apitypes "github.com/puppetlabs/wash/api/types"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// entry represents an entry on a remote daemon. Its methods are forwarded to
+// the daemon's API, and it supports the same actions as the remote entry.
+type entry struct {
+	plugin.EntryBase
+	client    client.Client
+	path      string
+	actions   map[string]bool
+	rawTypeID string
+}
+
+func newEntry(conn client.Client, e apitypes.Entry) *entry {
+	remote := &entry{
+		EntryBase: plugin.NewEntry(e.Name),
+		client:    conn,
+		path:      e.Path,
+		actions:   make(map[string]bool),
+		rawTypeID: e.TypeID,
+	}
+	for _, action := range e.Actions {
+		remote.actions[action] = true
+	}
+	remote.SetAttributes(e.Attributes)
+	remote.SetPartialMetadata(e.Metadata)
+	return remote
+}
+
+// listEntries lists the children of the remote entry at path.
+func listEntries(conn client.Client, path string) ([]plugin.Entry, error) {
+	children, err := conn.List(path)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]plugin.Entry, len(children))
+	for i, child := range children {
+		entries[i] = newEntry(conn, child)
+	}
+	return entries, nil
+}
+
+func (e *entry) MethodSignature(name string) plugin.MethodSignature {
+	if !e.actions[name] {
+		return plugin.UnsupportedSignature
+	}
+	switch name {
+	case plugin.ReadAction().Name:
+		// Entries with a known size are read in blocks so that large files
+		// aren't fetched in full.
+		if e.Attributes().HasSize() {
+			return plugin.BlockReadableSignature
+		}
+	case plugin.RenameAction().Name:
+		// The new parent is a local entry, so it can't be sent to the daemon.
+		return plugin.UnsupportedSignature
+	}
+	return plugin.DefaultSignature
+}
+
+func (e *entry) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{}
+}
+
+func (e *entry) Schema() *plugin.EntrySchema {
+	return nil
+}
+
+// SchemaGraph returns nil since the daemon doesn't share its schemas with
+// remote entries.
+func (e *entry) SchemaGraph() (*linkedhashmap.Map, error) {
+	return nil, nil
+}
+
+func (e *entry) RawTypeID() string {
+	return e.rawTypeID
+}
+
+func (e *entry) List(ctx context.Context) ([]plugin.Entry, error) {
+	return listEntries(e.client, e.path)
+}
+
+func (e *entry) Read(ctx context.Context) ([]byte, error) {
+	return e.client.Read(e.path, -1, 0)
+}
+
+func (e *entry) BlockRead(ctx context.Context, size int64, offset int64) ([]byte, error) {
+	return e.client.Read(e.path, size, offset)
+}
+
+func (e *entry) Write(ctx context.Context, p []byte) error {
+	return e.client.Write(e.path, bytes.NewReader(p))
+}
+
+func (e *entry) Metadata(ctx context.Context) (plugin.JSONObject, error) {
+	return e.client.Metadata(e.path)
+}
+
+func (e *entry) Signal(ctx context.Context, signal string) error {
+	return e.client.Signal(e.path, signal)
+}
+
+func (e *entry) Delete(ctx context.Context) (bool, error) {
+	return e.client.Delete(e.path)
+}
+
+func (e *entry) Rename(ctx context.Context, newParent plugin.Parent, newName string) error {
+	return fmt.Errorf("remote entries cannot be renamed")
+}
+
+func (e *entry) Stream(ctx context.Context) (io.ReadCloser, error) {
+	var since time.Duration
+	if t, ok := plugin.StreamSince(ctx); ok {
+		since = time.Since(t)
+	}
+	return e.client.Stream(e.path, since)
+}
+
+func (e *entry) Watch(ctx context.Context) (<-chan plugin.EntryEvent, error) {
+	apiEvents, closer, err := e.client.Watch(e.path)
+	if err != nil {
+		return nil, err
+	}
+	events := make(chan plugin.EntryEvent)
+	go func() {
+		<-ctx.Done()
+		activity.Record(ctx, "Stopped watching %v: %v", e.path, closer.Close())
+	}()
+	go func() {
+		defer close(events)
+		for apiEvent := range apiEvents {
+			event := plugin.EntryEvent{Type: apiEvent.Type, Name: apiEvent.Entry.Name, Time: apiEvent.Time}
+			if apiEvent.Type != plugin.EntryDeleted {
+				event.Entry = newEntry(e.client, apiEvent.Entry)
+			}
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events, nil
+}
+
+func (e *entry) Exec(ctx context.Context, cmd string, args []string, opts plugin.ExecOptions) (plugin.ExecCommand, error) {
+	// A session's used so that stdin can be interactive.
+	session, err := e.client.ExecSession(e.path, cmd, args, apitypes.ExecOptions{Tty: opts.Tty})
+	if err != nil {
+		return nil, err
+	}
+	if opts.Stdin != nil {
+		go func() {
+			_, err := io.Copy(session, opts.Stdin)
+			activity.Record(ctx, "Closed stdin of %v on %v: %v, %v", cmd, e.path, err, session.CloseStdin())
+		}()
+	} else if err := session.CloseStdin(); err != nil {
+		errz.Log(session.Close())
+		return nil, err
+	}
+
+	execCmd := plugin.NewExecCommand(ctx)
+	if opts.Tty {
+		execCmd.SetResizeFunc(opts.Resize, func(size plugin.WindowSize) error {
+			return session.Resize(size.Rows, size.Cols)
+		})
+	}
+	execCmd.SetStopFunc(func() {
+		activity.Record(ctx, "Closing the exec session on %v: %v", e.path, session.Close())
+	})
+	pkts := session.Packets()
+	go func() {
+		var exitCode *int
+		var pktErr error
+		for pkt := range pkts {
+			if pkt.Err != nil {
+				if pktErr == nil {
+					pktErr = fmt.Errorf("%v", pkt.Err.Msg)
+				}
+				continue
+			}
+			switch pkt.TypeField {
+			case apitypes.Stdout:
+				_, _ = fmt.Fprint(execCmd.Stdout(), pkt.Data)
+			case apitypes.Stderr:
+				_, _ = fmt.Fprint(execCmd.Stderr(), pkt.Data)
+			case apitypes.Exitcode:
+				if code, ok := pkt.Data.(float64); ok {
+					c := int(code)
+					exitCode = &c
+				}
+			}
+		}
+		execCmd.CloseStreamsWithError(pktErr)
+		if exitCode != nil {
+			execCmd.SetExitCode(*exitCode)
+		} else if pktErr != nil {
+			execCmd.SetExitCodeErr(pktErr)
+		} else {
+			execCmd.SetExitCodeErr(fmt.Errorf("the remote daemon did not send an exit code"))
+		}
+	}()
+	return execCmd, nil
+}
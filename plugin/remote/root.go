@@ -0,0 +1,178 @@
+// Package remote presents a filesystem hierarchy for other Wash daemons. Each
+// configured daemon is a server entry that contains the daemon's own plugins,
+// so that you can work with another machine's view of the world from your
+// local shell.
+//
+// Remote daemons are reached over TLS, and authenticated with either a client
+// certificate or a bearer token. See the api.listen config.
+package remote
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"sort"
+
+	"github.com/emirpasic/gods/maps/linkedhashmap"
+	"github.com/puppetlabs/wash/api/client"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// Root of the remote plugin
+type Root struct {
+	plugin.EntryBase
+	servers map[string]serverConfig
+}
+
+// serverConfig describes how to reach a remote daemon.
+type serverConfig struct {
+	address  string
+	caBundle string
+	cert     string
+	key      string
+	token    string
+}
+
+// tlsConfig returns the TLS configuration that's used to reach the daemon. It
+// includes the client certificate if there is one.
+func (c serverConfig) tlsConfig() (*tls.Config, error) {
+	return client.NewTLSConfig(c.caBundle, c.cert, c.key)
+}
+
+// Init for root
+func (r *Root) Init(cfg map[string]interface{}) error {
+	r.servers = make(map[string]serverConfig)
+	if serversI, ok := cfg["servers"]; ok {
+		servers, ok := serversI.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("remote.servers config must be a map of server names to their config, not %v", serversI)
+		}
+		for name, serverI := range servers {
+			server, err := parseServerConfig(name, serverI)
+			if err != nil {
+				return err
+			}
+			r.servers[name] = server
+		}
+	}
+
+	r.EntryBase = plugin.NewEntry("remote")
+	return nil
+}
+
+func parseServerConfig(name string, serverI interface{}) (serverConfig, error) {
+	var server serverConfig
+	serverMap, ok := serverI.(map[string]interface{})
+	if !ok {
+		return server, fmt.Errorf("remote.servers.%v config must be a map, not %v", name, serverI)
+	}
+	fields := map[string]*string{
+		"address":   &server.address,
+		"ca_bundle": &server.caBundle,
+		"cert":      &server.cert,
+		"key":       &server.key,
+		"token":     &server.token,
+	}
+	for key, field := range fields {
+		valueI, ok := serverMap[key]
+		if !ok {
+			continue
+		}
+		value, ok := valueI.(string)
+		if !ok {
+			return server, fmt.Errorf("remote.servers.%v.%v config must be a string, not %v", name, key, valueI)
+		}
+		*field = value
+	}
+	if server.address == "" {
+		return server, fmt.Errorf("remote.servers.%v.address must be set", name)
+	}
+	if server.cert == "" && server.key == "" && server.token == "" {
+		return server, fmt.Errorf("remote.servers.%v requires either a cert and key, or a token", name)
+	}
+	for _, key := range []string{"cert", "key"} {
+		if server.cert+server.key != "" && *fields[key] == "" {
+			return server, fmt.Errorf("remote.servers.%v.%v must be set", name, key)
+		}
+	}
+	return server, nil
+}
+
+// Schema returns the root's schema. The schemas of the remote entries are
+// unknown, so the schema's only used to document the plugin.
+func (r *Root) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(r, "remote").
+		SetDescription(rootDescription).
+		IsSingleton()
+}
+
+// ChildSchemas returns the root's child schema
+func (r *Root) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{}
+}
+
+// MethodSignature is part of the interface that lets the root and its
+// descendants have unknown schemas.
+func (r *Root) MethodSignature(name string) plugin.MethodSignature {
+	if name == plugin.ListAction().Name {
+		return plugin.DefaultSignature
+	}
+	return plugin.UnsupportedSignature
+}
+
+// SchemaGraph returns nil since the remote entries' schemas are unknown.
+func (r *Root) SchemaGraph() (*linkedhashmap.Map, error) {
+	return nil, nil
+}
+
+// RawTypeID returns the root's type ID.
+func (r *Root) RawTypeID() string {
+	return "root"
+}
+
+// BlockRead is never called since the root isn't readable.
+func (r *Root) BlockRead(context.Context, int64, int64) ([]byte, error) {
+	return nil, fmt.Errorf("the remote plugin's root is not readable")
+}
+
+// List lists the configured servers.
+func (r *Root) List(ctx context.Context) ([]plugin.Entry, error) {
+	names := make([]string, 0, len(r.servers))
+	for name := range r.servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	entries := make([]plugin.Entry, len(names))
+	for i, name := range names {
+		entries[i] = newServer(name, r.servers[name])
+	}
+	return entries, nil
+}
+
+const rootDescription = `
+This is the remote plugin root. It lists other Wash daemons that you can browse
+as if they were local, e.g. to reach resources that are only accessible from
+another network. Each daemon is configured under the remote.servers config,
+e.g.
+
+remote:
+  servers:
+    prod:
+      address: wash.prod.example.com:6061
+      ca_bundle: /etc/wash/ca.pem
+      cert: /etc/wash/client.pem
+      key: /etc/wash/client-key.pem
+
+The cert and key are the client certificate that's presented to the daemon,
+and ca_bundle verifies the daemon's certificate (the system's CAs are used if
+it's unset). The remote daemon must be started with the api.listen,
+api.tls_cert, api.tls_key and api.tls_client_ca configs.
+
+Instead of a client certificate, set token to a bearer token that was created
+with wash token create on the remote daemon's host. The token limits which of
+the daemon's plugins can be read and written.
+
+The schemas of a daemon's entries are unknown, so commands like find traverse
+all of them.
+`
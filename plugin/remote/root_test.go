@@ -0,0 +1,64 @@
+package remote
+
+import (
+	"testing"
+
+	apitypes "github.com/puppetlabs/wash/api/types"
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInit(t *testing.T) {
+	r := &Root{}
+	err := r.Init(map[string]interface{}{
+		"servers": map[string]interface{}{
+			"prod": map[string]interface{}{
+				"address": "wash.example.com:6061",
+				"cert":    "client.pem",
+				"key":     "client-key.pem",
+			},
+		},
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, serverConfig{address: "wash.example.com:6061", cert: "client.pem", key: "client-key.pem"}, r.servers["prod"])
+	}
+
+	err = r.Init(map[string]interface{}{"servers": "prod"})
+	assert.EqualError(t, err, "remote.servers config must be a map of server names to their config, not prod")
+
+	err = r.Init(map[string]interface{}{
+		"servers": map[string]interface{}{
+			"prod": map[string]interface{}{"address": "wash.example.com:6061", "cert": "client.pem"},
+		},
+	})
+	assert.EqualError(t, err, "remote.servers.prod.key must be set")
+
+	err = r.Init(map[string]interface{}{
+		"servers": map[string]interface{}{
+			"prod": map[string]interface{}{"address": "wash.example.com:6061"},
+		},
+	})
+	assert.EqualError(t, err, "remote.servers.prod requires either a cert and key, or a token")
+
+	err = r.Init(map[string]interface{}{
+		"servers": map[string]interface{}{
+			"prod": map[string]interface{}{"address": "wash.example.com:6061", "token": "wash_secret"},
+		},
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, serverConfig{address: "wash.example.com:6061", token: "wash_secret"}, r.servers["prod"])
+	}
+}
+
+func TestEntryMethodSignature(t *testing.T) {
+	attr := plugin.EntryAttributes{}
+	e := newEntry(nil, apitypes.Entry{Name: "foo", Actions: []string{"list", "read", "rename"}, Attributes: attr})
+	assert.Equal(t, plugin.DefaultSignature, e.MethodSignature("list"))
+	assert.Equal(t, plugin.DefaultSignature, e.MethodSignature("read"))
+	assert.Equal(t, plugin.UnsupportedSignature, e.MethodSignature("rename"))
+	assert.Equal(t, plugin.UnsupportedSignature, e.MethodSignature("exec"))
+
+	attr.SetSize(10)
+	e = newEntry(nil, apitypes.Entry{Name: "foo", Actions: []string{"read"}, Attributes: attr})
+	assert.Equal(t, plugin.BlockReadableSignature, e.MethodSignature("read"))
+}
@@ -0,0 +1,86 @@
+package remote
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/emirpasic/gods/maps/linkedhashmap"
+	"github.com/puppetlabs/wash/api/client"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// server represents a remote Wash daemon. Its children are the daemon's
+// plugins.
+type server struct {
+	plugin.EntryBase
+	config serverConfig
+	// The client and the daemon's mountpoint are fetched on the first List.
+	mux        sync.Mutex
+	client     client.Client
+	mountpoint string
+}
+
+func newServer(name string, config serverConfig) *server {
+	return &server{
+		EntryBase: plugin.NewEntry(name),
+		config:    config,
+	}
+}
+
+func (s *server) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{}
+}
+
+func (s *server) Schema() *plugin.EntrySchema {
+	return nil
+}
+
+func (s *server) MethodSignature(name string) plugin.MethodSignature {
+	if name == plugin.ListAction().Name {
+		return plugin.DefaultSignature
+	}
+	return plugin.UnsupportedSignature
+}
+
+func (s *server) SchemaGraph() (*linkedhashmap.Map, error) {
+	return nil, nil
+}
+
+func (s *server) RawTypeID() string {
+	return "server"
+}
+
+func (s *server) BlockRead(context.Context, int64, int64) ([]byte, error) {
+	return nil, fmt.Errorf("remote server %v is not readable", plugin.Name(s))
+}
+
+// connect returns the daemon's client and mountpoint.
+func (s *server) connect() (client.Client, string, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if s.client != nil {
+		return s.client, s.mountpoint, nil
+	}
+
+	tlsConfig, err := s.config.tlsConfig()
+	if err != nil {
+		return nil, "", err
+	}
+	conn := client.ForTLS(s.config.address, tlsConfig, s.config.token)
+	mountpoint, err := conn.Mountpoint()
+	if err != nil {
+		return nil, "", fmt.Errorf("could not reach %v: %v", s.config.address, err)
+	}
+	s.client = conn
+	s.mountpoint = mountpoint
+	return s.client, s.mountpoint, nil
+}
+
+func (s *server) List(ctx context.Context) ([]plugin.Entry, error) {
+	conn, mountpoint, err := s.connect()
+	if err != nil {
+		return nil, err
+	}
+	return listEntries(conn, mountpoint)
+}
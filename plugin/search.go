@@ -0,0 +1,141 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/puppetlabs/wash/activity"
+)
+
+// SearchDirName is the cname of a Searchable entry's search directory. The
+// search directory isn't listed, so that it doesn't slow down traversals like
+// `find`, but it can be looked up like any other child. Each of its children is
+// named by a search query, and lists that query's results. For example,
+//
+//	ls kubernetes/context/default/pods/.search/status.phase=Running
+//
+// lists the default namespace's running pods.
+const SearchDirName = ".search"
+
+// MaxSearchResults is the maximum number of results that are listed for a
+// search query. Search pages through the results until it's reached so that
+// queries with a lot of results don't exhaust memory.
+var MaxSearchResults = 1000
+
+// Search returns up to MaxSearchResults results of the given query by paging
+// through s#Search.
+func Search(ctx context.Context, s Searchable, query string) (results []Entry, err error) {
+	ctx = withPluginContext(ctx, s)
+	done := recordCall(s, "Search")
+	defer func() { done(err) }()
+	defer recoverPanic(ctx, s, "Search", &err)
+	pageToken := ""
+	for {
+		entries, nextPageToken, err := s.Search(ctx, query, pageToken)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, entries...)
+		if len(results) >= MaxSearchResults {
+			if nextPageToken != "" || len(results) > MaxSearchResults {
+				activity.Warnf(ctx, "Search for %q in %v returned more than %v results, only listing the first %v", query, s.eb().id, MaxSearchResults, MaxSearchResults)
+			}
+			return results[:MaxSearchResults], nil
+		}
+		if nextPageToken == "" {
+			return results, nil
+		}
+		if nextPageToken == pageToken {
+			return nil, fmt.Errorf("search for %q in %v returned the same page token %q twice", query, s.eb().id, pageToken)
+		}
+		pageToken = nextPageToken
+	}
+}
+
+// virtualChildLookup returns a function that looks up p's virtual children,
+// i.e. the children that p#List doesn't return. It returns nil if p doesn't
+// have virtual children.
+func virtualChildLookup(p Parent) func(cname string) (Entry, bool) {
+	var lookup func(cname string) (Entry, bool)
+	switch t := p.(type) {
+	case *searchDir:
+		lookup = func(cname string) (Entry, bool) {
+			return newSearchResults(t.parent, cname), true
+		}
+	default:
+		if !SearchAction().IsSupportedOn(p) {
+			return nil
+		}
+		searchable, ok := p.(Searchable)
+		if !ok {
+			return nil
+		}
+		lookup = func(cname string) (Entry, bool) {
+			if cname != SearchDirName {
+				return nil, false
+			}
+			return newSearchDir(searchable), true
+		}
+	}
+	return func(cname string) (Entry, bool) {
+		child, ok := lookup(cname)
+		if !ok {
+			return nil, false
+		}
+		setChildID(p.eb().id, child)
+		passAlongWrappedTypes(p, child)
+		return child, true
+	}
+}
+
+// searchDir is a Searchable entry's search directory. See SearchDirName for
+// more details.
+type searchDir struct {
+	EntryBase
+	parent Searchable
+}
+
+func newSearchDir(parent Searchable) *searchDir {
+	return &searchDir{EntryBase: NewEntry(SearchDirName), parent: parent}
+}
+
+// List returns nothing because the search directory's children are named by
+// search queries, so they can't be listed.
+func (d *searchDir) List(ctx context.Context) ([]Entry, error) {
+	return []Entry{}, nil
+}
+
+// Schema returns nil because the search results' schemas are only known
+// by the plugin.
+func (d *searchDir) Schema() *EntrySchema {
+	return nil
+}
+
+func (d *searchDir) ChildSchemas() []*EntrySchema {
+	return nil
+}
+
+// searchResults lists the results of a search query.
+type searchResults struct {
+	EntryBase
+	parent Searchable
+	query  string
+}
+
+func newSearchResults(parent Searchable, query string) *searchResults {
+	return &searchResults{EntryBase: NewEntry(query), parent: parent, query: query}
+}
+
+func (r *searchResults) List(ctx context.Context) ([]Entry, error) {
+	return Search(ctx, r.parent, r.query)
+}
+
+// Schema returns nil because the search results' schemas are only known
+// by the plugin.
+func (r *searchResults) Schema() *EntrySchema {
+	return nil
+}
+
+func (r *searchResults) ChildSchemas() []*EntrySchema {
+	return nil
+}
@@ -0,0 +1,90 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/puppetlabs/wash/datastore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockSearchableParent returns pages of two results named <query><n>, up to
+// the given number of pages.
+type mockSearchableParent struct {
+	mockParent
+	pages    int
+	searches []string
+}
+
+func (p *mockSearchableParent) Search(ctx context.Context, query string, pageToken string) ([]Entry, string, error) {
+	p.searches = append(p.searches, query+":"+pageToken)
+	page := 0
+	if pageToken != "" {
+		page, _ = strconv.Atoi(pageToken)
+	}
+	entries := []Entry{
+		newMockEntry(fmt.Sprintf("%v%v", query, 2*page)),
+		newMockEntry(fmt.Sprintf("%v%v", query, 2*page+1)),
+	}
+	if page+1 >= p.pages {
+		return entries, "", nil
+	}
+	return entries, strconv.Itoa(page + 1), nil
+}
+
+func TestSearch_LooksUpSearchDirectory(t *testing.T) {
+	SetTestCache(datastore.NewMemCache())
+	defer UnsetTestCache()
+
+	parent := &mockSearchableParent{mockParent: mockParent{NewEntry("root"), []Entry{newMockEntry("foo")}}, pages: 2}
+	parent.SetTestID("/root")
+	parent.DisableDefaultCaching()
+
+	// The search directory isn't listed
+	entries, err := List(context.Background(), parent)
+	require.NoError(t, err)
+	assert.Equal(t, 1, entries.Len())
+
+	results, err := FindEntry(context.Background(), parent, []string{SearchDirName, "bar"})
+	require.NoError(t, err)
+	assert.Equal(t, "/root/.search/bar", ID(results))
+
+	entries, err = List(context.Background(), results.(Parent))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bar:", "bar:1"}, parent.searches)
+	for _, cname := range []string{"bar0", "bar1", "bar2", "bar3"} {
+		entry, ok := entries.Load(cname)
+		if assert.True(t, ok, cname) {
+			assert.Equal(t, "/root/.search/bar/"+cname, ID(entry))
+		}
+	}
+	_, ok := entries.Load("bar4")
+	assert.False(t, ok)
+}
+
+func TestSearch_NonSearchableParentHasNoSearchDirectory(t *testing.T) {
+	SetTestCache(datastore.NewMemCache())
+	defer UnsetTestCache()
+
+	parent := &mockParent{NewEntry("root"), []Entry{newMockEntry("foo")}}
+	parent.SetTestID("/root")
+	parent.DisableDefaultCaching()
+
+	_, err := FindEntry(context.Background(), parent, []string{SearchDirName})
+	assert.Error(t, err)
+}
+
+func TestSearch_LimitsResults(t *testing.T) {
+	defer func(max int) { MaxSearchResults = max }(MaxSearchResults)
+	MaxSearchResults = 3
+
+	parent := &mockSearchableParent{mockParent: mockParent{NewEntry("root"), nil}, pages: 5}
+	parent.SetTestID("/root")
+	results, err := Search(context.Background(), parent, "bar")
+	require.NoError(t, err)
+	assert.Len(t, results, 3)
+	assert.Equal(t, []string{"bar:", "bar:1"}, parent.searches)
+}
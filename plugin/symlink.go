@@ -0,0 +1,49 @@
+package plugin
+
+import (
+	"os"
+	"path"
+)
+
+// Symlink represents a reference to another entry, like a pod's persistent
+// volume claims. The FUSE filesystem renders it as a symlink and the API includes
+// its target, so that cross-references are navigable.
+type Symlink struct {
+	EntryBase
+	target string
+}
+
+// NewSymlink creates a new Symlink named name that points to target. The target
+// is a path of cnames that's relative to the symlink's parent, like
+// "../../persistentvolumeclaims/data", so it resolves the same way whether it's
+// followed via FUSE or via the API.
+func NewSymlink(target string, name string) *Symlink {
+	s := &Symlink{
+		EntryBase: NewEntry(name),
+		target:    target,
+	}
+	s.Attributes().SetMode(os.ModeSymlink | 0777)
+	return s
+}
+
+// Schema defines the schema of a symlink.
+func (s *Symlink) Schema() *EntrySchema {
+	return NewEntrySchema(s, "symlink").SetDescription(symlinkDescription)
+}
+
+// Target returns the symlink's target.
+func (s *Symlink) Target() string {
+	return s.target
+}
+
+// TargetID returns the ID of the symlink's target, i.e. its target resolved
+// against its parent's ID.
+func (s *Symlink) TargetID() string {
+	parentID, _ := splitID(ID(s))
+	return path.Join(parentID, s.target)
+}
+
+const symlinkDescription = `
+A reference to another entry. It's a symlink in the mounted filesystem, so you
+can cd into it or read it like the entry that it points to.
+`
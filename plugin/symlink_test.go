@@ -0,0 +1,20 @@
+package plugin
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSymlink(t *testing.T) {
+	link := NewSymlink("../../../volumes/data", "data")
+	assert.Equal(t, "data", link.Name())
+	assert.Equal(t, "../../../volumes/data", link.Target())
+	attr := Attributes(link)
+	assert.Equal(t, os.ModeSymlink|0777, attr.Mode())
+	assert.Empty(t, SupportedActionsOf(link))
+
+	link.SetTestID("/docker/containers/web/volumes/data")
+	assert.Equal(t, "/docker/volumes/data", link.TargetID())
+}
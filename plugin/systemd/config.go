@@ -0,0 +1,64 @@
+package systemd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/puppetlabs/wash/transport"
+)
+
+// parseHosts parses the systemd.hosts config, which lists hosts as
+// [user@]host[:port].
+func parseHosts(hostsI interface{}) ([]transport.Identity, error) {
+	hosts, ok := hostsI.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("systemd.hosts config must be a list of [user@]host[:port] strings, not %v", hostsI)
+	}
+
+	var ids []transport.Identity
+	seen := make(map[string]struct{})
+	for _, hostI := range hosts {
+		host, ok := hostI.(string)
+		if !ok {
+			return nil, fmt.Errorf("systemd.hosts config must be a list of [user@]host[:port] strings, not %v", hostsI)
+		}
+		id, err := parseHost(host)
+		if err != nil {
+			return nil, fmt.Errorf("systemd.hosts: %v", err)
+		}
+		if id.Host == "local" {
+			return nil, fmt.Errorf("systemd.hosts: %v conflicts with the local machine", host)
+		}
+		if _, ok := seen[id.Host]; ok {
+			return nil, fmt.Errorf("systemd.hosts: %v is listed more than once", id.Host)
+		}
+		seen[id.Host] = struct{}{}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func parseHost(host string) (transport.Identity, error) {
+	invalid := fmt.Errorf("%v must be [user@]host[:port]", host)
+	var id transport.Identity
+	rest := strings.TrimSpace(host)
+	if i := strings.LastIndex(rest, "@"); i >= 0 {
+		if i == 0 {
+			return id, invalid
+		}
+		id.User, rest = rest[:i], rest[i+1:]
+	}
+	if i := strings.LastIndex(rest, ":"); i >= 0 {
+		port, err := strconv.ParseUint(rest[i+1:], 10, 16)
+		if err != nil {
+			return id, fmt.Errorf("%v has an invalid port %v", host, rest[i+1:])
+		}
+		id.Port, rest = uint(port), rest[:i]
+	}
+	if rest == "" || strings.Contains(rest, "/") {
+		return id, invalid
+	}
+	id.Host = rest
+	return id, nil
+}
@@ -0,0 +1,36 @@
+package systemd
+
+import (
+	"testing"
+
+	"github.com/puppetlabs/wash/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHosts(t *testing.T) {
+	ids, err := parseHosts([]interface{}{"web1", "admin@db1.example.com:2222", "deploy@app"})
+	require.NoError(t, err)
+	assert.Equal(t, []transport.Identity{
+		{Host: "web1"},
+		{Host: "db1.example.com", User: "admin", Port: 2222},
+		{Host: "app", User: "deploy"},
+	}, ids)
+}
+
+func TestParseHostsErrors(t *testing.T) {
+	_, err := parseHosts("web1")
+	assert.EqualError(t, err, "systemd.hosts config must be a list of [user@]host[:port] strings, not web1")
+
+	_, err = parseHosts([]interface{}{"@web1"})
+	assert.EqualError(t, err, "systemd.hosts: @web1 must be [user@]host[:port]")
+
+	_, err = parseHosts([]interface{}{"web1:ssh"})
+	assert.EqualError(t, err, "systemd.hosts: web1:ssh has an invalid port ssh")
+
+	_, err = parseHosts([]interface{}{"local"})
+	assert.EqualError(t, err, "systemd.hosts: local conflicts with the local machine")
+
+	_, err = parseHosts([]interface{}{"web1", "admin@web1"})
+	assert.EqualError(t, err, "systemd.hosts: web1 is listed more than once")
+}
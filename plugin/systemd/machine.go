@@ -0,0 +1,153 @@
+package systemd
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/puppetlabs/wash/transport"
+)
+
+// machine is the local machine or a remote host. Its commands are run locally or
+// over SSH.
+type machine struct {
+	plugin.EntryBase
+	// id is nil for the local machine.
+	id   *transport.Identity
+	sudo bool
+}
+
+func newLocalMachine(sudo bool) *machine {
+	m := &machine{
+		EntryBase: plugin.NewEntry("local"),
+	}
+	m.sudo = sudo
+	return m
+}
+
+func newRemoteMachine(id transport.Identity, sudo bool) *machine {
+	m := &machine{
+		EntryBase: plugin.NewEntry(id.Host),
+	}
+	m.id = &id
+	m.sudo = sudo
+	return m
+}
+
+func (m *machine) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(m, "machine").
+		SetDescription(machineDescription).
+		SetMetadataSchema(map[string]string{})
+}
+
+func (m *machine) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&unit{}).Schema(),
+	}
+}
+
+// List lists all of the machine's loaded units, including inactive ones.
+func (m *machine) List(ctx context.Context) ([]plugin.Entry, error) {
+	out, err := m.run(ctx, false, []string{"systemctl", "list-units", "--all", "--full", "--plain", "--no-legend", "--no-pager"})
+	if err != nil {
+		return nil, err
+	}
+	infos := parseUnits(out)
+	units := make([]plugin.Entry, len(infos))
+	for i, info := range infos {
+		units[i] = newUnit(m, info)
+	}
+	activity.Record(ctx, "Listing %v units on %v", len(units), m)
+	return units, nil
+}
+
+// Metadata returns the properties of the machine's service manager, which
+// include its version and state.
+func (m *machine) Metadata(ctx context.Context) (plugin.JSONObject, error) {
+	out, err := m.run(ctx, false, []string{"systemctl", "show", "--no-pager"})
+	if err != nil {
+		return nil, err
+	}
+	return plugin.ToJSONObject(parseProperties(out)), nil
+}
+
+// exec runs the command on the machine. Elevated commands are run with sudo.
+func (m *machine) exec(ctx context.Context, cmd []string, opts plugin.ExecOptions) (plugin.ExecCommand, error) {
+	if m.id != nil {
+		return transport.ExecSSH(ctx, *m.id, cmd, opts)
+	}
+
+	if opts.Elevate {
+		cmd = append([]string{"sudo"}, cmd...)
+	}
+	localCmd := exec.CommandContext(ctx, cmd[0], cmd[1:]...)
+	execCmd := plugin.NewExecCommand(ctx)
+	localCmd.Stdin = opts.Stdin
+	localCmd.Stdout = execCmd.Stdout()
+	localCmd.Stderr = execCmd.Stderr()
+	if err := localCmd.Start(); err != nil {
+		return nil, err
+	}
+	go func() {
+		err := localCmd.Wait()
+		execCmd.CloseStreamsWithError(nil)
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			execCmd.SetExitCode(exitErr.ExitCode())
+		} else if err != nil {
+			execCmd.SetExitCodeErr(err)
+		} else {
+			execCmd.SetExitCode(0)
+		}
+	}()
+	return execCmd, nil
+}
+
+// run runs the command on the machine and returns its stdout. The command fails
+// if it exits with a code other than 0 or one of okCodes. If elevate is set,
+// the command's run with sudo when the systemd.sudo config is set.
+func (m *machine) run(ctx context.Context, elevate bool, cmd []string, okCodes ...int) (string, error) {
+	activity.Record(ctx, "Running %v on %v", cmd, m)
+	execCmd, err := m.exec(ctx, cmd, plugin.ExecOptions{Elevate: elevate && m.sudo})
+	if err != nil {
+		return "", err
+	}
+
+	var stdout, stderr strings.Builder
+	var errs []error
+	for chunk := range execCmd.OutputCh() {
+		if chunk.Err != nil {
+			errs = append(errs, chunk.Err)
+		} else if chunk.StreamID == plugin.Stdout {
+			stdout.WriteString(chunk.Data)
+		} else {
+			stderr.WriteString(chunk.Data)
+		}
+	}
+	if len(errs) > 0 {
+		return "", fmt.Errorf("exec errored: %v", errs)
+	}
+
+	exitCode, err := execCmd.ExitCode()
+	if err != nil {
+		return "", err
+	}
+	if exitCode == 0 {
+		return stdout.String(), nil
+	}
+	for _, code := range okCodes {
+		if exitCode == code {
+			return stdout.String(), nil
+		}
+	}
+	return "", fmt.Errorf("%v exited with %v: %v", cmd[0], exitCode, strings.TrimSpace(stderr.String()))
+}
+
+const machineDescription = `
+This is a machine that runs systemd. It's the local machine or a remote host
+that's reached over SSH. It contains all of the machine's loaded units, and its
+metadata is its service manager's properties, from systemctl show.
+`
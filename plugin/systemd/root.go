@@ -0,0 +1,85 @@
+// Package systemd presents a filesystem hierarchy for the systemd units of the
+// local machine and of remote hosts.
+//
+// Units are managed with systemctl and journalctl. Remote hosts are reached over
+// SSH, like the hosts plugin's hosts.
+package systemd
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/puppetlabs/wash/transport"
+)
+
+// Root of the systemd plugin
+type Root struct {
+	plugin.EntryBase
+	hosts []transport.Identity
+	sudo  bool
+}
+
+// Init for root
+func (r *Root) Init(cfg map[string]interface{}) error {
+	if hostsI, ok := cfg["hosts"]; ok {
+		hosts, err := parseHosts(hostsI)
+		if err != nil {
+			return err
+		}
+		r.hosts = hosts
+	}
+	if sudoI, ok := cfg["sudo"]; ok {
+		sudo, ok := sudoI.(bool)
+		if !ok {
+			return fmt.Errorf("systemd.sudo config must be a bool, not %v", sudoI)
+		}
+		r.sudo = sudo
+	}
+
+	r.EntryBase = plugin.NewEntry("systemd")
+	return nil
+}
+
+// Schema returns the root's schema
+func (r *Root) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(r, "systemd").
+		SetDescription(rootDescription).
+		IsSingleton()
+}
+
+// ChildSchemas returns the root's child schema
+func (r *Root) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&machine{}).Schema(),
+	}
+}
+
+// List lists the local machine, if it's running Linux, and the configured hosts.
+func (r *Root) List(ctx context.Context) ([]plugin.Entry, error) {
+	var machines []plugin.Entry
+	if runtime.GOOS == "linux" {
+		machines = append(machines, newLocalMachine(r.sudo))
+	}
+	for _, id := range r.hosts {
+		machines = append(machines, newRemoteMachine(id, r.sudo))
+	}
+	return machines, nil
+}
+
+const rootDescription = `
+This is the systemd plugin root. It contains the local machine (local), if it's
+running Linux, and the hosts in the systemd.hosts config. Hosts are listed as
+[user@]host[:port] and are reached over SSH, e.g.
+
+systemd:
+  hosts:
+  - web1
+  - admin@db1.example.com:2222
+  sudo: true
+
+Starting and stopping units usually requires root. Set systemd.sudo to run
+systemctl's start, stop, restart and reload commands with sudo.
+`
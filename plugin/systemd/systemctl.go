@@ -0,0 +1,40 @@
+package systemd
+
+import "strings"
+
+// parseUnits parses the output of
+//
+//	systemctl list-units --all --full --plain --no-legend
+//
+// which lists one unit per line as its name, load state, active state, sub
+// state and description.
+func parseUnits(out string) []unitInfo {
+	var units []unitInfo
+	for _, line := range strings.Split(out, "\n") {
+		// Older versions of systemd mark failed units with a '●', even with
+		// --plain.
+		fields := strings.Fields(strings.TrimPrefix(strings.TrimSpace(line), "●"))
+		if len(fields) < 4 {
+			continue
+		}
+		units = append(units, unitInfo{
+			Name:        fields[0],
+			Load:        fields[1],
+			Active:      fields[2],
+			Sub:         fields[3],
+			Description: strings.Join(fields[4:], " "),
+		})
+	}
+	return units
+}
+
+// parseProperties parses the Key=Value lines output by systemctl show.
+func parseProperties(out string) map[string]string {
+	props := make(map[string]string)
+	for _, line := range strings.Split(out, "\n") {
+		if i := strings.Index(line, "="); i > 0 {
+			props[line[:i]] = line[i+1:]
+		}
+	}
+	return props
+}
@@ -0,0 +1,37 @@
+package systemd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseUnits(t *testing.T) {
+	out := `-.mount                      loaded    active   mounted   Root Mount
+nginx.service                loaded    active   running   A high performance web server
+● postgresql.service         not-found inactive dead      postgresql.service
+systemd-tmpfiles-clean.timer loaded    active   waiting
+`
+	assert.Equal(t, []unitInfo{
+		{Name: "-.mount", Load: "loaded", Active: "active", Sub: "mounted", Description: "Root Mount"},
+		{Name: "nginx.service", Load: "loaded", Active: "active", Sub: "running", Description: "A high performance web server"},
+		{Name: "postgresql.service", Load: "not-found", Active: "inactive", Sub: "dead", Description: "postgresql.service"},
+		{Name: "systemd-tmpfiles-clean.timer", Load: "loaded", Active: "active", Sub: "waiting", Description: ""},
+	}, parseUnits(out))
+
+	assert.Empty(t, parseUnits(""))
+}
+
+func TestParseProperties(t *testing.T) {
+	out := `Type=simple
+ExecStart={ path=/usr/sbin/nginx ; argv[]=/usr/sbin/nginx -g daemon on; ; ignore_errors=no }
+Description=
+ActiveState=active
+`
+	assert.Equal(t, map[string]string{
+		"Type":        "simple",
+		"ExecStart":   "{ path=/usr/sbin/nginx ; argv[]=/usr/sbin/nginx -g daemon on; ; ignore_errors=no }",
+		"Description": "",
+		"ActiveState": "active",
+	}, parseProperties(out))
+}
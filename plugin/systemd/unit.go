@@ -0,0 +1,130 @@
+package systemd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// unitInfo is a unit's status from systemctl list-units.
+type unitInfo struct {
+	Name        string `json:"name"`
+	Load        string `json:"load"`
+	Active      string `json:"active"`
+	Sub         string `json:"sub"`
+	Description string `json:"description"`
+}
+
+type unit struct {
+	plugin.EntryBase
+	machine *machine
+}
+
+func newUnit(m *machine, info unitInfo) *unit {
+	u := &unit{
+		EntryBase: plugin.NewEntry(info.Name),
+	}
+	u.machine = m
+	u.
+		DisableCachingFor(plugin.ReadOp).
+		SetPartialMetadata(info).
+		Attributes().
+		SetCustom("active", info.Active).
+		SetCustom("sub", info.Sub)
+	return u
+}
+
+func (u *unit) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(u, "unit").
+		SetDescription(unitDescription).
+		SetPartialMetadataSchema(unitInfo{}).
+		SetMetadataSchema(map[string]string{}).
+		AddSignal("start", "Starts the unit").
+		AddSignal("stop", "Stops the unit").
+		AddSignal("restart", "Restarts the unit").
+		AddSignal("reload", "Reloads the unit's configuration").
+		AddCustomAttribute("active", plugin.StringAttribute, "The unit's active state, e.g. active, inactive or failed").
+		AddCustomAttribute("sub", plugin.StringAttribute, "The unit's low-level state, e.g. running or exited")
+}
+
+// Read returns the unit's systemctl status output.
+func (u *unit) Read(ctx context.Context) ([]byte, error) {
+	// systemctl status exits with 3 when the unit isn't active.
+	out, err := u.machine.run(ctx, false, []string{"systemctl", "status", "--full", "--no-pager", "--", u.Name()}, 3)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(out), nil
+}
+
+// Stream follows the unit's journal, starting with its last 10 lines.
+func (u *unit) Stream(ctx context.Context) (io.ReadCloser, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	cmd := []string{"journalctl", "--follow", "--no-pager", "--lines=10", "--unit", u.Name()}
+	activity.Record(ctx, "Streaming %v on %v", cmd, u.machine)
+	execCmd, err := u.machine.exec(ctx, cmd, plugin.ExecOptions{})
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	r, w := io.Pipe()
+	go func() {
+		var stderr strings.Builder
+		for chunk := range execCmd.OutputCh() {
+			if chunk.Err != nil {
+				w.CloseWithError(chunk.Err)
+				return
+			}
+			if chunk.StreamID == plugin.Stderr {
+				stderr.WriteString(chunk.Data)
+			} else if _, err := io.WriteString(w, chunk.Data); err != nil {
+				// The reader's closed.
+				return
+			}
+		}
+		if exitCode, err := execCmd.ExitCode(); err != nil {
+			w.CloseWithError(err)
+		} else if exitCode != 0 {
+			w.CloseWithError(fmt.Errorf("journalctl exited with %v: %v", exitCode, strings.TrimSpace(stderr.String())))
+		} else {
+			w.Close()
+		}
+	}()
+	return plugin.CleanupReader{ReadCloser: r, Cleanup: cancel}, nil
+}
+
+// Metadata returns the unit's properties from systemctl show, which are the
+// properties of the unit's D-Bus object.
+func (u *unit) Metadata(ctx context.Context) (plugin.JSONObject, error) {
+	out, err := u.machine.run(ctx, false, []string{"systemctl", "show", "--no-pager", "--", u.Name()})
+	if err != nil {
+		return nil, err
+	}
+	return plugin.ToJSONObject(parseProperties(out)), nil
+}
+
+func (u *unit) Signal(ctx context.Context, signal string) error {
+	switch signal {
+	case "start", "stop", "restart", "reload":
+		_, err := u.machine.run(ctx, true, []string{"systemctl", signal, "--", u.Name()})
+		return err
+	default:
+		return fmt.Errorf("unknown signal %v", signal)
+	}
+}
+
+const unitDescription = `
+This is a systemd unit. Reading it returns its systemctl status output, and
+streaming it follows its journal. Its metadata is its properties, from
+systemctl show.
+
+Signal it with start, stop, restart or reload to run the systemctl command of
+the same name, e.g.
+  signal restart systemd/web1/nginx.service
+`
@@ -0,0 +1,244 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/puppetlabs/wash/activity"
+)
+
+// DefaultTrashRetention is the default amount of time that trashed entries are
+// kept before they're deleted.
+const DefaultTrashRetention = 24 * time.Hour
+
+// TrashOptions configures the trash. When it's enabled, plugin.Delete moves
+// entries to the trash instead of deleting them. A trashed entry is hidden from
+// its parent's listing, and it's deleted once its retention period expires
+// unless it's restored first. This is a safety net for plugins whose deletes
+// can't be undone.
+//
+// The trash is kept in memory, so stopping the daemon restores its entries.
+type TrashOptions struct {
+	Enabled bool
+	// Retention is how long trashed entries are kept before they're deleted.
+	// Zero uses DefaultTrashRetention.
+	Retention time.Duration
+	// Plugins limits the trash to the entries of the named plugins. Empty
+	// includes every plugin.
+	Plugins []string
+}
+
+// TrashedEntry describes an entry that's in the trash.
+type TrashedEntry struct {
+	// ID identifies the entry in the trash. It's unrelated to the entry's ID.
+	ID        string
+	EntryID   string
+	TrashedAt time.Time
+	PurgeAt   time.Time
+}
+
+// TrashedEntryNotFoundErr indicates that the trash doesn't have the requested
+// entry, e.g. because it was restored or already purged.
+type TrashedEntryNotFoundErr struct {
+	id string
+}
+
+func (e TrashedEntryNotFoundErr) Error() string {
+	return fmt.Sprintf("%v is not in the trash", e.id)
+}
+
+// IsTrashedEntryNotFoundErr returns true if err is a TrashedEntryNotFoundErr
+// error object
+func IsTrashedEntryNotFoundErr(err error) bool {
+	_, ok := err.(TrashedEntryNotFoundErr)
+	return ok
+}
+
+type trashedEntry struct {
+	TrashedEntry
+	entry Deletable
+	timer *time.Timer
+}
+
+var trash = struct {
+	mux    sync.Mutex
+	opts   TrashOptions
+	nextID int
+	// entries are keyed by their trash ID.
+	entries map[string]*trashedEntry
+}{
+	nextID:  1,
+	entries: make(map[string]*trashedEntry),
+}
+
+// SetTrashOptions configures the trash. It should be called before any entries
+// are deleted.
+func SetTrashOptions(opts TrashOptions) {
+	if opts.Retention <= 0 {
+		opts.Retention = DefaultTrashRetention
+	}
+	trash.mux.Lock()
+	defer trash.mux.Unlock()
+	trash.opts = opts
+}
+
+// Trash returns the trashed entries, ordered by when they were trashed.
+func Trash() []TrashedEntry {
+	trash.mux.Lock()
+	defer trash.mux.Unlock()
+
+	entries := make([]TrashedEntry, 0, len(trash.entries))
+	for _, t := range trash.entries {
+		entries = append(entries, t.TrashedEntry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		a, _ := strconv.Atoi(entries[i].ID)
+		b, _ := strconv.Atoi(entries[j].ID)
+		return a < b
+	})
+	return entries
+}
+
+// RestoreFromTrash takes the entry out of the trash so that it's listed again
+// and isn't deleted.
+func RestoreFromTrash(id string) error {
+	t, ok := takeFromTrash(id)
+	if !ok {
+		return TrashedEntryNotFoundErr{id}
+	}
+	// Clear the parent's cached list result, which no longer has the entry.
+	parentID, _ := splitID(t.EntryID)
+	cache.Delete(opKeyRegex(defaultOpCodeToNameMap[ListOp], parentID))
+	return nil
+}
+
+// PurgeFromTrash deletes the trashed entry now instead of waiting for its
+// retention period to expire. It returns the result of the entry's Delete.
+func PurgeFromTrash(ctx context.Context, id string) (bool, error) {
+	t, ok := takeFromTrash(id)
+	if !ok {
+		return false, TrashedEntryNotFoundErr{id}
+	}
+	return deleteEntry(ctx, t.entry)
+}
+
+func trashEnabledFor(e Entry) bool {
+	trash.mux.Lock()
+	defer trash.mux.Unlock()
+
+	if !trash.opts.Enabled {
+		return false
+	}
+	if len(trash.opts.Plugins) == 0 {
+		return true
+	}
+	name := pluginName(e)
+	for _, plugin := range trash.opts.Plugins {
+		if plugin == name {
+			return true
+		}
+	}
+	return false
+}
+
+// moveToTrash trashes the entry and schedules its deletion. The entry and its
+// children are removed from the cache like they would be if it were deleted.
+func moveToTrash(ctx context.Context, d Deletable) {
+	entryID := d.eb().id
+	trash.mux.Lock()
+	for _, t := range trash.entries {
+		if t.EntryID == entryID {
+			// The entry's already trashed, which is possible if it was
+			// looked up before it was trashed.
+			trash.mux.Unlock()
+			return
+		}
+	}
+	now := time.Now()
+	t := &trashedEntry{
+		TrashedEntry: TrashedEntry{
+			ID:        strconv.Itoa(trash.nextID),
+			EntryID:   entryID,
+			TrashedAt: now,
+			PurgeAt:   now.Add(trash.opts.Retention),
+		},
+		entry: d,
+	}
+	trash.nextID++
+	trash.entries[t.ID] = t
+	id := t.ID
+	t.timer = time.AfterFunc(trash.opts.Retention, func() {
+		purgeExpiredEntry(id)
+	})
+	trash.mux.Unlock()
+
+	activity.Record(ctx, "Moved %v to the trash as %v. It will be deleted at %v", entryID, t.ID, t.PurgeAt.Format(time.RFC3339))
+	ClearCacheFor(entryID, false)
+	parentID, cname := splitID(entryID)
+	if entries, _ := cache.Get(cacheCategory(ctx, defaultOpCodeToNameMap[ListOp]), parentID); entries != nil {
+		entries.(*EntryMap).Delete(cname)
+	}
+}
+
+// takeFromTrash removes the entry from the trash and cancels its deletion.
+func takeFromTrash(id string) (*trashedEntry, bool) {
+	trash.mux.Lock()
+	defer trash.mux.Unlock()
+
+	t, ok := trash.entries[id]
+	if !ok {
+		return nil, false
+	}
+	t.timer.Stop()
+	delete(trash.entries, id)
+	return t, true
+}
+
+// purgeExpiredEntry deletes a trashed entry whose retention period expired. If
+// the delete fails, then the entry's listed again.
+func purgeExpiredEntry(id string) {
+	journal := activity.NewJournal("trash", "Trash")
+	ctx := context.WithValue(context.Background(), activity.JournalKey, journal)
+	t, ok := takeFromTrash(id)
+	if !ok {
+		return
+	}
+	deleted, err := deleteEntry(ctx, t.entry)
+	if err != nil {
+		activity.Warnf(ctx, "Failed to delete trashed entry %v (%v): %v", t.EntryID, id, err)
+		return
+	}
+	activity.Record(ctx, "Deleted trashed entry %v (%v): %v", t.EntryID, id, deleted)
+}
+
+// withoutTrashedEntries returns the parent's children without its trashed
+// children. The cached list result isn't modified, since the entries are listed
+// again if they're restored.
+func withoutTrashedEntries(parentID string, entries *EntryMap) *EntryMap {
+	trash.mux.Lock()
+	var trashed []string
+	for _, t := range trash.entries {
+		if tParentID, cname := splitID(t.EntryID); tParentID == parentID {
+			trashed = append(trashed, cname)
+		}
+	}
+	trash.mux.Unlock()
+	if len(trashed) == 0 {
+		return entries
+	}
+
+	filtered := newEntryMap()
+	entries.Range(func(cname string, entry Entry) bool {
+		filtered.mp[cname] = entry
+		return true
+	})
+	for _, cname := range trashed {
+		delete(filtered.mp, cname)
+	}
+	filtered.lookup = entries.lookup
+	return filtered
+}
@@ -0,0 +1,158 @@
+package plugin
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type TrashTestSuite struct {
+	suite.Suite
+	cache *cacheTestsMockCache
+}
+
+func (suite *TrashTestSuite) SetupTest() {
+	suite.cache = &cacheTestsMockCache{}
+	suite.cache.On("Get", mock.Anything, mock.Anything).Return(nil, nil)
+	suite.cache.On("Delete", mock.Anything).Return([]string{})
+	SetTestCache(suite.cache)
+}
+
+func (suite *TrashTestSuite) TearDownTest() {
+	UnsetTestCache()
+	suite.cache = nil
+	for id := range trash.entries {
+		takeFromTrash(id)
+	}
+	trash.nextID = 1
+	SetTrashOptions(TrashOptions{})
+}
+
+// newTrashTestsParent returns a parent whose children are /foo/bar and /foo/baz.
+func newTrashTestsParent() (Parent, *methodWrappersTestsMockEntry) {
+	parent := methodWrappersTestsMockCreatable{newMethodWrappersTestsMockEntry("foo")}
+	parent.SetTestID("/foo")
+	bar := newMethodWrappersTestsMockEntry("bar")
+	bar.SetTestID("/foo/bar")
+	baz := newMethodWrappersTestsMockEntry("baz")
+	baz.SetTestID("/foo/baz")
+	parent.On("List", mock.Anything).Return([]Entry{bar, baz}, nil)
+	return parent, bar
+}
+
+func (suite *TrashTestSuite) listedCNames(parent Parent) []string {
+	entries, err := List(context.Background(), parent)
+	suite.Require().NoError(err)
+	var cnames []string
+	for cname := range entries.Map() {
+		cnames = append(cnames, cname)
+	}
+	return cnames
+}
+
+func (suite *TrashTestSuite) TestDelete_TrashDisabled_DeletesEntry() {
+	_, bar := newTrashTestsParent()
+	bar.On("Delete", mock.Anything).Return(true, nil)
+
+	deleted, err := Delete(context.Background(), bar)
+	if suite.NoError(err) {
+		suite.True(deleted)
+		suite.Empty(Trash())
+		bar.AssertExpectations(suite.T())
+	}
+}
+
+func (suite *TrashTestSuite) TestDelete_TrashEnabled_MovesEntryToTrash() {
+	SetTrashOptions(TrashOptions{Enabled: true})
+	parent, bar := newTrashTestsParent()
+
+	start := time.Now()
+	deleted, err := Delete(context.Background(), bar)
+	if suite.NoError(err) {
+		suite.False(deleted)
+		bar.AssertNotCalled(suite.T(), "Delete", mock.Anything)
+	}
+
+	trashed := Trash()
+	if suite.Len(trashed, 1) {
+		suite.Equal("1", trashed[0].ID)
+		suite.Equal("/foo/bar", trashed[0].EntryID)
+		suite.WithinDuration(start.Add(DefaultTrashRetention), trashed[0].PurgeAt, time.Second)
+	}
+	suite.ElementsMatch([]string{"baz"}, suite.listedCNames(parent))
+
+	// Deleting the entry again doesn't trash it twice.
+	_, err = Delete(context.Background(), bar)
+	suite.NoError(err)
+	suite.Len(Trash(), 1)
+}
+
+func (suite *TrashTestSuite) TestRestoreFromTrash() {
+	SetTrashOptions(TrashOptions{Enabled: true})
+	parent, bar := newTrashTestsParent()
+
+	_, err := Delete(context.Background(), bar)
+	suite.Require().NoError(err)
+	suite.NoError(RestoreFromTrash("1"))
+	suite.Empty(Trash())
+	suite.ElementsMatch([]string{"bar", "baz"}, suite.listedCNames(parent))
+
+	err = RestoreFromTrash("1")
+	suite.True(IsTrashedEntryNotFoundErr(err))
+	suite.EqualError(err, "1 is not in the trash")
+}
+
+func (suite *TrashTestSuite) TestPurgeFromTrash() {
+	SetTrashOptions(TrashOptions{Enabled: true})
+	_, bar := newTrashTestsParent()
+	bar.On("Delete", mock.Anything).Return(true, nil)
+
+	_, err := Delete(context.Background(), bar)
+	suite.Require().NoError(err)
+	deleted, err := PurgeFromTrash(context.Background(), "1")
+	if suite.NoError(err) {
+		suite.True(deleted)
+		suite.Empty(Trash())
+		bar.AssertExpectations(suite.T())
+	}
+
+	_, err = PurgeFromTrash(context.Background(), "1")
+	suite.True(IsTrashedEntryNotFoundErr(err))
+}
+
+func (suite *TrashTestSuite) TestTrashedEntryIsDeletedWhenRetentionExpires() {
+	SetTrashOptions(TrashOptions{Enabled: true, Retention: 10 * time.Millisecond})
+	_, bar := newTrashTestsParent()
+	deletedCh := make(chan struct{})
+	bar.On("Delete", mock.Anything).Return(true, nil).Run(func(mock.Arguments) {
+		close(deletedCh)
+	})
+
+	_, err := Delete(context.Background(), bar)
+	suite.Require().NoError(err)
+	select {
+	case <-deletedCh:
+		suite.Empty(Trash())
+	case <-time.After(5 * time.Second):
+		suite.Fail("the trashed entry wasn't deleted")
+	}
+}
+
+func (suite *TrashTestSuite) TestDelete_PluginNotInTrashPlugins_DeletesEntry() {
+	SetTrashOptions(TrashOptions{Enabled: true, Plugins: []string{"aws"}})
+	_, bar := newTrashTestsParent()
+	bar.On("Delete", mock.Anything).Return(true, nil)
+
+	deleted, err := Delete(context.Background(), bar)
+	if suite.NoError(err) {
+		suite.True(deleted)
+		suite.Empty(Trash())
+	}
+}
+
+func TestTrash(t *testing.T) {
+	suite.Run(t, new(TrashTestSuite))
+}
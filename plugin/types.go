@@ -27,6 +27,10 @@ non-file-like entries error if you try to open them with a ReadWrite handle. If
 implements non-file-like write-semantics, remember to document how they work in the plugin schema's
 description.
 
+Entries are read-only by default. Parents that implement Creatable or DirCreatable can create
+new files or directories, e.g. via touch and mkdir, Deletable entries can be removed, e.g.
+via rm and rmdir, and Renamable entries can be renamed or moved within their plugin, e.g. via mv.
+
 All of the above, as well as other types - Execable, Stream - provide additional functionality
 via the HTTP API.
 */
@@ -37,6 +41,7 @@ package plugin
 import (
 	"context"
 	"io"
+	"os"
 	"time"
 
 	"github.com/emirpasic/gods/maps/linkedhashmap"
@@ -122,6 +127,10 @@ type HasWrappedTypes interface {
 type ExecOptions struct {
 	// Stdin can be used to pass a stream of input to write to stdin when executing the command.
 	// It is not included in ExecOption's JSON serialization.
+	//
+	// NOTE TO PLUGIN AUTHORS: Stdin can be interactive, meaning its input arrives while the command
+	// is running (e.g. as a user types it). So copy it to the command as it's read instead of reading
+	// all of it before starting the command.
 	Stdin io.Reader `json:"-"`
 
 	// Tty instructs the executor to allocate a TTY (pseudo-terminal), which lets Wash communicate
@@ -141,6 +150,17 @@ type ExecOptions struct {
 
 	// Elevate execution to run as a privileged user if not already running as a privileged user.
 	Elevate bool `json:"elevate"`
+
+	// Resize receives the caller's terminal size whenever it changes during an interactive session.
+	// Executors that allocate a TTY should resize it via ExecCommandImpl#SetResizeFunc. Other
+	// executors can ignore it. It is not included in ExecOption's JSON serialization.
+	Resize <-chan WindowSize `json:"-"`
+}
+
+// WindowSize is the size of a terminal, in characters.
+type WindowSize struct {
+	Rows uint16 `json:"rows"`
+	Cols uint16 `json:"cols"`
 }
 
 // ExecPacketType identifies the packet type.
@@ -181,7 +201,9 @@ type Execable interface {
 	Exec(ctx context.Context, cmd string, args []string, opts ExecOptions) (ExecCommand, error)
 }
 
-// Streamable is an entry that returns a stream of updates.
+// Streamable is an entry that returns a stream of updates. Entries whose
+// updates are timestamped (like logs) should also stream the updates since
+// StreamSince(ctx) when it's set.
 type Streamable interface {
 	Entry
 	Stream(context.Context) (io.ReadCloser, error)
@@ -209,11 +231,91 @@ type Readable interface {
 // Writable can be implemented with or without Readable/BlockReadable. If an
 // entry is only Writable, then only full writes (starting from offset 0) are
 // allowed, anything else initiated by the filesystem will result in an error.
+//
+// Write replaces the entry's entire content, so each call is a transaction:
+// the entry has either its old or its new content. That's why Writable doesn't
+// have Truncate or Flush methods. The filesystem buffers a handle's writes and
+// truncations, and a flush or fsync commits the buffered content with a single
+// Write. The API's write endpoint also commits its body with a single Write.
 type Writable interface {
 	Entry
 	Write(context.Context, []byte) error
 }
 
+// StreamWritable is a Writable entry that can consume its new data from a
+// stream. plugin.Copy uses it to pipe data between entries without buffering
+// the entire content. size is the content's size, or -1 if it's unknown.
+type StreamWritable interface {
+	Writable
+	WriteStream(ctx context.Context, r io.Reader, size int64) error
+}
+
+// Creatable is a Parent that can create new children, e.g. new files in a
+// directory. The created child only needs to exist once it's first written to,
+// so Create typically returns a new Writable entry without calling the plugin's
+// API. mode is the new file's permissions, e.g. the mode that was passed to
+// open(2). Plugins whose API doesn't have permissions can ignore it. FUSE uses
+// Create to support creating files.
+type Creatable interface {
+	Parent
+	Create(ctx context.Context, name string, mode os.FileMode) (Writable, error)
+}
+
+// DirCreatable is a Parent that can create new child directories, e.g. key
+// prefixes in a KV store. Unlike Create, CreateDir should create the directory
+// in the plugin's API because an empty directory is never written to. FUSE uses
+// CreateDir to support mkdir.
+type DirCreatable interface {
+	Parent
+	CreateDir(ctx context.Context, name string) (Parent, error)
+}
+
+// Searchable is a Parent whose backend has a native query API, like CloudWatch
+// Logs Insights, Elasticsearch or Kubernetes field selectors. Searching lets the
+// backend do the heavy filtering instead of listing every child. A query's
+// results are listed by the entry's search directory, see SearchDirName.
+//
+// Search returns one page of the query's results. pageToken is empty for the
+// first page. nextPageToken is the token of the next page, or empty if this is
+// the last page. The query's syntax is up to the plugin, so remember to document
+// it in the entry schema's description.
+type Searchable interface {
+	Parent
+	Search(ctx context.Context, query string, pageToken string) (entries []Entry, nextPageToken string, err error)
+}
+
+// Watchable is a Parent whose backend can notify Wash when its children change,
+// e.g. via Kubernetes informers or Docker's events API. Watching lets clients
+// react to changes as they happen instead of polling List.
+//
+// Watch should send an EntryEvent for each change until ctx is cancelled, and
+// then close the channel. Use plugin.Watch to watch an entry because it keeps
+// the cache up-to-date with the events.
+type Watchable interface {
+	Parent
+	Watch(ctx context.Context) (<-chan EntryEvent, error)
+}
+
+// EntryEventType identifies the type of an EntryEvent.
+type EntryEventType = string
+
+// Enumerates the entry event types.
+const (
+	EntryCreated  EntryEventType = "created"
+	EntryModified EntryEventType = "modified"
+	EntryDeleted  EntryEventType = "deleted"
+)
+
+// EntryEvent describes a change to one of a Watchable entry's children. Entry
+// is the child's new state. It's required for created and modified events, and
+// can be nil for deleted events if the child's Name is set instead.
+type EntryEvent struct {
+	Type  EntryEventType
+	Entry Entry
+	Name  string
+	Time  time.Time
+}
+
 // Deletable is an entry that can be deleted. Entries that implement Delete
 // should ensure that it and all its children are removed. If the entry has
 // any dependencies that need to be deleted, then Delete should return an
@@ -228,6 +330,16 @@ type Deletable interface {
 	Delete(context.Context) (bool, error)
 }
 
+// Renamable is an entry that can be renamed or moved to another parent in the
+// same plugin, e.g. an S3 object or a file in a container. newParent is the
+// entry's current parent if it's only being renamed. Like POSIX's rename,
+// Rename should replace an existing newName child. The entry's and both
+// parents' cached data is cleared once it's renamed.
+type Renamable interface {
+	Entry
+	Rename(ctx context.Context, newParent Parent, newName string) error
+}
+
 // Signalable is an entry that can be signaled. Signal should return nil if the
 // signal was successfully sent. Otherwise, it should return an error explaining
 // why the signal was not sent.
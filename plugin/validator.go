@@ -0,0 +1,83 @@
+package plugin
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/puppetlabs/wash/activity"
+)
+
+// Validator identifies a specific version of an entry's content, like an HTTP
+// ETag or Last-Modified timestamp. Wash uses validators to avoid re-downloading
+// unchanged content once the entry's cached content expires.
+//
+// Plugins whose List endpoint returns validators (e.g. S3's ETags) should set them
+// via EntryBase#SetValidator. Wash will then re-use the previously read content as
+// long as the re-listed entry's validator matches.
+type Validator struct {
+	ETag         string
+	LastModified time.Time
+}
+
+// IsZero returns true if the validator is empty.
+func (v Validator) IsZero() bool {
+	return v.ETag == "" && v.LastModified.IsZero()
+}
+
+// Matches returns true if v and other identify the same version of the content.
+// ETags take precedence over LastModified timestamps.
+func (v Validator) Matches(other Validator) bool {
+	if v.ETag != "" || other.ETag != "" {
+		return v.ETag == other.ETag
+	}
+	return !v.LastModified.IsZero() && v.LastModified.Equal(other.LastModified)
+}
+
+// ValidatedContentTTL is how long Wash keeps validated content after it was last
+// used. It is meant to be much longer than the Read op's TTL.
+var ValidatedContentTTL = 1 * time.Hour
+
+const validatedContentOpName = "ValidatedContent"
+
+// validatedContent is the most recently read content of an entry along with the
+// validator that identifies it. It outlives the Read op's cache entry.
+type validatedContent struct {
+	mux       sync.Mutex
+	validator Validator
+	content   entryContent
+}
+
+func getValidatedContent(e Entry) *validatedContent {
+	obj, _ := cache.GetOrUpdate(validatedContentOpName, e.eb().id, ValidatedContentTTL, true, func() (interface{}, error) {
+		return &validatedContent{}, nil
+	})
+	return obj.(*validatedContent)
+}
+
+// usesValidators returns true if Wash should keep the entry's content around
+// for revalidation.
+func usesValidators(e Entry) bool {
+	return !e.eb().validator.IsZero()
+}
+
+// validatedRead returns the entry's content, re-using the previously read content
+// if it is still valid. read is invoked to fetch the content when it isn't.
+func validatedRead(ctx context.Context, e Entry, read func() (entryContent, error)) (entryContent, error) {
+	vc := getValidatedContent(e)
+	vc.mux.Lock()
+	defer vc.mux.Unlock()
+
+	validator := e.eb().validator
+	if vc.content != nil && vc.validator.Matches(validator) {
+		activity.Record(ctx, "Content of %v is unchanged (%+v), re-using it", e.eb().id, validator)
+		return vc.content, nil
+	}
+	content, err := read()
+	if err != nil {
+		return nil, err
+	}
+	vc.validator = validator
+	vc.content = content
+	return content, nil
+}
@@ -0,0 +1,75 @@
+package plugin
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/puppetlabs/wash/datastore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestValidatorMatches(t *testing.T) {
+	now := time.Now()
+	assert.True(t, Validator{ETag: "a"}.Matches(Validator{ETag: "a"}))
+	assert.False(t, Validator{ETag: "a"}.Matches(Validator{ETag: "b"}))
+	// ETags take precedence over timestamps
+	assert.False(t, Validator{ETag: "a", LastModified: now}.Matches(Validator{LastModified: now}))
+	assert.True(t, Validator{LastModified: now}.Matches(Validator{LastModified: now}))
+	assert.False(t, Validator{LastModified: now}.Matches(Validator{LastModified: now.Add(time.Second)}))
+	assert.False(t, Validator{}.Matches(Validator{}))
+}
+
+type ValidatorTestSuite struct {
+	suite.Suite
+	ctx context.Context
+}
+
+func (suite *ValidatorTestSuite) SetupTest() {
+	suite.ctx = SetTestCache(datastore.NewMemCache())
+}
+
+func (suite *ValidatorTestSuite) TearDownTest() {
+	UnsetTestCache()
+}
+
+type validatorTestsReadableEntry struct {
+	EntryBase
+	content string
+	reads   int
+}
+
+func (e *validatorTestsReadableEntry) Schema() *EntrySchema {
+	return nil
+}
+
+func (e *validatorTestsReadableEntry) Read(context.Context) ([]byte, error) {
+	e.reads++
+	return []byte(e.content), nil
+}
+
+func (suite *ValidatorTestSuite) TestListValidator_ReusesContentUntilValidatorChanges() {
+	e := &validatorTestsReadableEntry{EntryBase: NewEntry("foo"), content: "hello"}
+	e.SetTestID("/foo")
+	e.DisableCachingFor(ReadOp)
+	e.SetValidator(Validator{ETag: "1"})
+
+	for i := 0; i < 2; i++ {
+		data, err := Read(suite.ctx, e, 5, 0)
+		suite.NoError(err)
+		suite.Equal("hello", string(data))
+	}
+	suite.Equal(1, e.reads)
+
+	e.content = "world"
+	e.SetValidator(Validator{ETag: "2"})
+	data, err := Read(suite.ctx, e, 5, 0)
+	suite.NoError(err)
+	suite.Equal("world", string(data))
+	suite.Equal(2, e.reads)
+}
+
+func TestValidator(t *testing.T) {
+	suite.Run(t, new(ValidatorTestSuite))
+}
@@ -0,0 +1,36 @@
+package vault
+
+import (
+	"context"
+
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// dir is a path in a key/value secrets engine that contains secrets. Its path
+// ends in a slash.
+type dir struct {
+	plugin.EntryBase
+	kv   *kv
+	path string
+}
+
+func newDir(kv *kv, name string, path string) *dir {
+	d := &dir{
+		EntryBase: plugin.NewEntry(name),
+	}
+	d.kv = kv
+	d.path = path
+	return d
+}
+
+func (d *dir) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(d, "dir")
+}
+
+func (d *dir) ChildSchemas() []*plugin.EntrySchema {
+	return kvChildSchemas()
+}
+
+func (d *dir) List(ctx context.Context) ([]plugin.Entry, error) {
+	return d.kv.list(ctx, d.path)
+}
@@ -0,0 +1,121 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// kv is a key/value secrets engine. Version 2 engines keep the versions of each
+// secret, and their API paths are prefixed by 'data' or 'metadata'.
+type kv struct {
+	client  *api.Client
+	mount   string
+	version int
+	reveal  bool
+}
+
+// list lists the secrets and directories at path. Directories' paths end in a
+// slash.
+func (kv *kv) list(ctx context.Context, path string) ([]plugin.Entry, error) {
+	listPath := kv.mount + path
+	if kv.version == 2 {
+		listPath = kv.mount + "metadata/" + path
+	}
+	r := kv.client.NewRequest(http.MethodGet, "/v1/"+listPath)
+	r.Params.Set("list", "true")
+	secret, err := send(ctx, kv.client, r)
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil {
+		// Vault doesn't distinguish between an empty and a missing directory.
+		return []plugin.Entry{}, nil
+	}
+
+	keys, _ := secret.Data["keys"].([]interface{})
+	entries := make([]plugin.Entry, 0, len(keys))
+	for _, keyI := range keys {
+		key, ok := keyI.(string)
+		if !ok {
+			continue
+		}
+		switch {
+		case strings.HasSuffix(key, "/"):
+			entries = append(entries, newDir(kv, strings.TrimSuffix(key, "/"), path+key))
+		case kv.version == 2:
+			entries = append(entries, newSecret(kv, key, path+key))
+		default:
+			entries = append(entries, newSecretVersion(kv, key, path+key, 0, nil))
+		}
+	}
+	return entries, nil
+}
+
+// read reads the secret at path. Version 0 is the latest version. It's ignored
+// by version 1 engines.
+func (kv *kv) read(ctx context.Context, path string, version int) (*api.Secret, error) {
+	readPath := kv.mount + path
+	if kv.version == 2 {
+		readPath = kv.mount + "data/" + path
+	}
+	r := kv.client.NewRequest(http.MethodGet, "/v1/"+readPath)
+	if kv.version == 2 && version != 0 {
+		r.Params = url.Values{"version": []string{strconv.Itoa(version)}}
+	}
+	secret, err := send(ctx, kv.client, r)
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil {
+		return nil, fmt.Errorf("%v%v not found", kv.mount, path)
+	}
+	return secret, nil
+}
+
+// metadata reads the metadata of the secret at path. It's only supported by
+// version 2 engines.
+func (kv *kv) metadata(ctx context.Context, path string) (*api.Secret, error) {
+	secret, err := send(ctx, kv.client, kv.client.NewRequest(http.MethodGet, "/v1/"+kv.mount+"metadata/"+path))
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil {
+		return nil, fmt.Errorf("%v%v not found", kv.mount, path)
+	}
+	return secret, nil
+}
+
+// send sends the request with ctx. The api package's Logical methods don't
+// accept a context, so this mirrors them. Like them, it returns a nil secret if
+// the path doesn't exist.
+func send(ctx context.Context, client *api.Client, r *api.Request) (*api.Secret, error) {
+	resp, err := client.RawRequestWithContext(ctx, r)
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return api.ParseSecret(resp.Body)
+}
+
+// redacted replaces the secret values in a secret's data.
+const redacted = "<redacted>"
+
+func redact(data map[string]interface{}) map[string]interface{} {
+	redactedData := make(map[string]interface{}, len(data))
+	for key := range data {
+		redactedData[key] = redacted
+	}
+	return redactedData
+}
@@ -0,0 +1,91 @@
+package vault
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestKV(t *testing.T, version int, reveal bool, responses map[string]string) (*kv, *httptest.Server) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := responses[r.URL.RequestURI()]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+
+	config := api.DefaultConfig()
+	config.Address = server.URL
+	client, err := api.NewClient(config)
+	require.NoError(t, err)
+	return &kv{client: client, mount: "secret/", version: version, reveal: reveal}, server
+}
+
+func TestListAndReadV1(t *testing.T) {
+	kv, server := newTestKV(t, 1, false, map[string]string{
+		"/v1/secret?list=true": `{"data": {"keys": ["app/", "db"]}}`,
+		"/v1/secret/db":        `{"lease_duration": 3600, "data": {"password": "hunter2"}}`,
+	})
+	defer server.Close()
+	ctx := context.Background()
+
+	entries, err := kv.list(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.IsType(t, &dir{}, entries[0])
+	assert.Equal(t, "app/", entries[0].(*dir).path)
+	db := entries[1].(*secretVersion)
+	assert.Equal(t, "db", plugin.Name(db))
+
+	content, err := db.Read(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "{\n  \"password\": \"<redacted>\"\n}\n", string(content))
+
+	meta, err := db.Metadata(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, plugin.JSONObject{"lease_duration": 3600.0, "renewable": false}, meta)
+
+	entries, err = kv.list(ctx, "missing/")
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestListAndReadV2(t *testing.T) {
+	kv, server := newTestKV(t, 2, true, map[string]string{
+		"/v1/secret/metadata?list=true": `{"data": {"keys": ["db"]}}`,
+		"/v1/secret/metadata/db": `{"data": {"current_version": 2, "versions": {
+			"1": {"created_time": "2020-01-01T00:00:00.000Z", "deletion_time": "2020-01-02T00:00:00.000Z", "destroyed": false},
+			"2": {"created_time": "2020-01-03T00:00:00.000Z", "deletion_time": "", "destroyed": false}
+		}}}`,
+		"/v1/secret/data/db?version=2": `{"data": {"data": {"password": "hunter2"}, "metadata": {"created_time": "2020-01-03T00:00:00.000Z", "version": 2}}}`,
+	})
+	defer server.Close()
+	ctx := context.Background()
+
+	entries, err := kv.list(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	versions, err := entries[0].(*secret).List(ctx)
+	require.NoError(t, err)
+	require.Len(t, versions, 1)
+	version := versions[0].(*secretVersion)
+	assert.Equal(t, "2", plugin.Name(version))
+	assert.Equal(t, 2, version.version)
+
+	content, err := version.Read(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "{\n  \"password\": \"hunter2\"\n}\n", string(content))
+
+	meta, err := version.Metadata(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "2020-01-03T00:00:00.000Z", meta["created_time"])
+	assert.Equal(t, 2.0, meta["version"])
+}
@@ -0,0 +1,58 @@
+package vault
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// mount is a key/value secrets engine that's mounted at a path.
+type mount struct {
+	plugin.EntryBase
+	kv *kv
+}
+
+func newMount(client *api.Client, path string, out *api.MountOutput, reveal bool) *mount {
+	m := &mount{
+		EntryBase: plugin.NewEntry(strings.TrimSuffix(path, "/")),
+	}
+	m.kv = &kv{client: client, mount: path, version: 1, reveal: reveal}
+	if out.Options["version"] == "2" {
+		m.kv.version = 2
+	}
+	m.SetPartialMetadata(out)
+	return m
+}
+
+func (m *mount) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(m, "mount").
+		SetDescription(mountDescription).
+		SetPartialMetadataSchema(api.MountOutput{})
+}
+
+func (m *mount) ChildSchemas() []*plugin.EntrySchema {
+	return kvChildSchemas()
+}
+
+func (m *mount) List(ctx context.Context) ([]plugin.Entry, error) {
+	return m.kv.list(ctx, "")
+}
+
+// kvChildSchemas returns the schemas of the entries in a key/value engine's
+// directories.
+func kvChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&dir{}).Schema(),
+		(&secret{}).Schema(),
+		(&secretVersion{}).Schema(),
+	}
+}
+
+const mountDescription = `
+This is a key/value secrets engine. Its metadata includes the engine's default
+and maximum lease TTLs in its config, and its version in its options. On
+version 2 engines, each secret is a directory of its versions.
+`
@@ -0,0 +1,148 @@
+// Package vault presents a filesystem hierarchy for HashiCorp Vault's key/value
+// secrets.
+//
+// It uses the VAULT environment variables (like VAULT_ADDR and VAULT_TOKEN) to
+// access Vault, and falls back to the token that 'vault login' stores in
+// ~/.vault-token.
+package vault
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// Root of the Vault plugin
+type Root struct {
+	plugin.EntryBase
+	client *api.Client
+	reveal bool
+}
+
+// Init for root
+func (r *Root) Init(cfg map[string]interface{}) error {
+	config := api.DefaultConfig()
+	if config.Error != nil {
+		return config.Error
+	}
+	// The default config uses its own transport, so send requests through the
+	// shared one instead and re-apply the VAULT_* TLS settings on top of it.
+	transport := plugin.HTTPTransport()
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	config.HttpClient.Transport = transport
+	if err := config.ReadEnvironment(); err != nil {
+		return err
+	}
+	if addressI, ok := cfg["address"]; ok {
+		address, ok := addressI.(string)
+		if !ok {
+			return fmt.Errorf("vault.address config must be a string, not %v", addressI)
+		}
+		config.Address = address
+	}
+	if revealI, ok := cfg["reveal"]; ok {
+		reveal, ok := revealI.(bool)
+		if !ok {
+			return fmt.Errorf("vault.reveal config must be a boolean, not %v", revealI)
+		}
+		r.reveal = reveal
+	}
+
+	client, err := api.NewClient(config)
+	if err != nil {
+		return err
+	}
+	if client.Token() == "" {
+		token, err := loginToken()
+		if err != nil {
+			return err
+		}
+		client.SetToken(token)
+	}
+	r.client = client
+
+	r.EntryBase = plugin.NewEntry("vault")
+	return nil
+}
+
+// loginToken returns the token that 'vault login' stored in ~/.vault-token.
+func loginToken() (string, error) {
+	homedir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	token, err := ioutil.ReadFile(filepath.Join(homedir, ".vault-token"))
+	if os.IsNotExist(err) {
+		return "", fmt.Errorf("no Vault token found; set VAULT_TOKEN or run 'vault login'")
+	} else if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(token)), nil
+}
+
+// Schema returns the root's schema
+func (r *Root) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(r, "vault").
+		SetDescription(rootDescription).
+		IsSingleton()
+}
+
+// ChildSchemas returns the root's child schema
+func (r *Root) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&mount{}).Schema(),
+	}
+}
+
+// List lists the key/value secrets engines. Other secrets engines don't store
+// secrets at paths that can be listed, so they're skipped.
+func (r *Root) List(ctx context.Context) ([]plugin.Entry, error) {
+	mounts, err := r.client.Sys().ListMounts()
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(mounts))
+	for path, mount := range mounts {
+		// "generic" is the key/value engine's name before Vault 0.8.
+		if mount.Type == "kv" || mount.Type == "generic" {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	entries := make([]plugin.Entry, len(paths))
+	for i, path := range paths {
+		entries[i] = newMount(r.client, path, mounts[path], r.reveal)
+	}
+	return entries, nil
+}
+
+const rootDescription = `
+This is the Vault plugin root. It lists the key/value secrets engines that the
+token can see. Each engine's secret paths are directories, and its secrets are
+files whose content is the secret's data as JSON. On version 2 engines, each
+secret is a directory of its versions.
+
+Vault is found via the VAULT environment variables like VAULT_ADDR and
+VAULT_TOKEN. If VAULT_TOKEN isn't set, then the token that 'vault login' stored
+in ~/.vault-token is used. The address can also be set by the vault.address
+config.
+
+Secret values are redacted unless the vault.reveal config is set, e.g.
+
+vault:
+  reveal: true
+
+The secrets' metadata includes their lease and TTL info.
+`
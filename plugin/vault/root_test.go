@@ -0,0 +1,31 @@
+package vault
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInit_UsesSharedHTTPClient(t *testing.T) {
+	var proxiedHost string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxiedHost = r.URL.Host
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer proxy.Close()
+	require.NoError(t, plugin.SetHTTPOptions(plugin.HTTPOptions{Proxy: proxy.URL}))
+	defer func() { _ = plugin.SetHTTPOptions(plugin.HTTPOptions{}) }()
+
+	defer os.Setenv("VAULT_TOKEN", os.Getenv("VAULT_TOKEN"))
+	os.Setenv("VAULT_TOKEN", "token")
+	r := &Root{}
+	require.NoError(t, r.Init(map[string]interface{}{"address": "http://vault.invalid:8200"}))
+
+	_, _ = r.client.Sys().ListMounts()
+	assert.Equal(t, "vault.invalid:8200", proxiedHost)
+}
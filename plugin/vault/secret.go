@@ -0,0 +1,97 @@
+package vault
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// secret is a secret in a version 2 key/value secrets engine. It lists the
+// secret's versions.
+type secret struct {
+	plugin.EntryBase
+	kv   *kv
+	path string
+}
+
+// secretMetadata describes a secret's versions. It's the response of the
+// engine's metadata endpoint.
+type secretMetadata struct {
+	CreatedTime    string                 `json:"created_time"`
+	UpdatedTime    string                 `json:"updated_time"`
+	CurrentVersion int                    `json:"current_version"`
+	OldestVersion  int                    `json:"oldest_version"`
+	MaxVersions    int                    `json:"max_versions"`
+	CasRequired    bool                   `json:"cas_required"`
+	Versions       map[string]versionInfo `json:"versions"`
+}
+
+func newSecret(kv *kv, name string, path string) *secret {
+	s := &secret{
+		EntryBase: plugin.NewEntry(name),
+	}
+	s.kv = kv
+	s.path = path
+	return s
+}
+
+func (s *secret) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(s, "secret").
+		SetDescription(secretDescription).
+		SetMetadataSchema(secretMetadata{})
+}
+
+func (s *secret) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&secretVersion{}).Schema(),
+	}
+}
+
+// List lists the secret's versions. Deleted and destroyed versions don't have
+// any data, so they're skipped.
+func (s *secret) List(ctx context.Context) ([]plugin.Entry, error) {
+	meta, err := s.readMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]plugin.Entry, 0, len(meta.Versions))
+	for name, info := range meta.Versions {
+		if info.Destroyed || info.DeletionTime != "" {
+			continue
+		}
+		version, err := strconv.Atoi(name)
+		if err != nil {
+			continue
+		}
+		info.Version = version
+		entries = append(entries, newSecretVersion(s.kv, name, s.path, version, &info))
+	}
+	return entries, nil
+}
+
+func (s *secret) Metadata(ctx context.Context) (plugin.JSONObject, error) {
+	meta, err := s.readMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return plugin.ToJSONObject(meta), nil
+}
+
+func (s *secret) readMetadata(ctx context.Context) (secretMetadata, error) {
+	var meta secretMetadata
+	resp, err := s.kv.metadata(ctx, s.path)
+	if err != nil {
+		return meta, err
+	}
+	err = decode(resp.Data, &meta)
+	return meta, err
+}
+
+const secretDescription = `
+This is a secret in a version 2 key/value secrets engine. It lists the secret's
+versions, except for the versions that were deleted or destroyed. Its metadata
+includes the secret's current version and when each version was created.
+`
@@ -0,0 +1,127 @@
+package vault
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// secretVersion is a version of a secret in a version 2 key/value secrets
+// engine, or a secret in a version 1 engine. Its content is the secret's data
+// as JSON.
+type secretVersion struct {
+	plugin.EntryBase
+	kv      *kv
+	path    string
+	version int
+}
+
+// versionInfo describes a version of a secret in a version 2 engine.
+type versionInfo struct {
+	CreatedTime  string `json:"created_time,omitempty"`
+	DeletionTime string `json:"deletion_time,omitempty"`
+	Destroyed    bool   `json:"destroyed,omitempty"`
+	Version      int    `json:"version,omitempty"`
+}
+
+// versionMetadata adds the lease info of a read to a version's info. Version 1
+// engines only return the lease info.
+type versionMetadata struct {
+	versionInfo
+	LeaseID       string `json:"lease_id,omitempty"`
+	LeaseDuration int    `json:"lease_duration"`
+	Renewable     bool   `json:"renewable"`
+}
+
+// newSecretVersion creates a secretVersion. Version 0 is the latest version,
+// and info is the version's info from its secret's metadata. It's nil for
+// secrets in version 1 engines.
+func newSecretVersion(kv *kv, name string, path string, version int, info *versionInfo) *secretVersion {
+	v := &secretVersion{
+		EntryBase: plugin.NewEntry(name),
+	}
+	v.kv = kv
+	v.path = path
+	v.version = version
+	if info != nil {
+		v.SetPartialMetadata(info)
+		if created, err := time.Parse(time.RFC3339Nano, info.CreatedTime); err == nil {
+			v.Attributes().SetCrtime(created).SetMtime(created)
+		}
+	}
+	return v
+}
+
+func (v *secretVersion) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(v, "version").
+		SetDescription(secretVersionDescription).
+		SetPartialMetadataSchema(versionInfo{}).
+		SetMetadataSchema(versionMetadata{})
+}
+
+// Read returns the secret's data as JSON. Its values are redacted unless the
+// vault.reveal config is set.
+func (v *secretVersion) Read(ctx context.Context) ([]byte, error) {
+	resp, err := v.kv.read(ctx, v.path, v.version)
+	if err != nil {
+		return nil, err
+	}
+
+	data := resp.Data
+	if v.kv.version == 2 {
+		data, _ = resp.Data["data"].(map[string]interface{})
+	}
+	if !v.kv.reveal {
+		data = redact(data)
+	}
+	// Secrets often contain characters like '<' and '&', so don't escape them.
+	var content bytes.Buffer
+	encoder := json.NewEncoder(&content)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(data); err != nil {
+		return nil, err
+	}
+	return content.Bytes(), nil
+}
+
+// Metadata returns the secret's lease info and, on version 2 engines, the
+// version's info.
+func (v *secretVersion) Metadata(ctx context.Context) (plugin.JSONObject, error) {
+	resp, err := v.kv.read(ctx, v.path, v.version)
+	if err != nil {
+		return nil, err
+	}
+
+	var meta versionMetadata
+	if v.kv.version == 2 {
+		if err := decode(resp.Data["metadata"], &meta.versionInfo); err != nil {
+			return nil, err
+		}
+	}
+	meta.LeaseID = resp.LeaseID
+	meta.LeaseDuration = resp.LeaseDuration
+	meta.Renewable = resp.Renewable
+	return plugin.ToJSONObject(meta), nil
+}
+
+// decode decodes a secret's data, which the api package decodes into generic
+// maps, into obj.
+func decode(data interface{}, obj interface{}) error {
+	content, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(content, obj)
+}
+
+const secretVersionDescription = `
+This is a secret's version, or a secret in a version 1 key/value secrets engine.
+Its content is the secret's data as JSON. Its values are redacted unless the
+vault.reveal config is set. Its metadata includes its lease ID, its lease
+duration (the secret's TTL in seconds) and whether the lease is renewable.
+`
@@ -0,0 +1,79 @@
+package vsphere
+
+import (
+	"context"
+
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/vmware/govmomi/view"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// cluster is a vSphere cluster or standalone host. It lists the VMs that run
+// on it.
+type cluster struct {
+	plugin.EntryBase
+	datacenter *datacenter
+	ref        types.ManagedObjectReference
+}
+
+func newCluster(d *datacenter, resource mo.ComputeResource) *cluster {
+	c := &cluster{
+		EntryBase: plugin.NewEntry(resource.Name),
+	}
+	c.datacenter = d
+	c.ref = resource.Reference()
+	if resource.Summary != nil {
+		c.SetPartialMetadata(resource.Summary.GetComputeResourceSummary())
+	}
+	return c
+}
+
+func (c *cluster) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(c, "cluster").
+		SetDescription(clusterDescription).
+		SetPartialMetadataSchema(types.ComputeResourceSummary{})
+}
+
+func (c *cluster) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&vm{}).Schema(),
+	}
+}
+
+func (c *cluster) List(ctx context.Context) ([]plugin.Entry, error) {
+	client, err := c.datacenter.server.vimClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	kinds := []string{"VirtualMachine"}
+	v, err := view.NewManager(client).CreateContainerView(ctx, c.ref, kinds, true)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := v.Destroy(context.Background()); err != nil {
+			activity.Record(ctx, "Failed to destroy the view of %v's VMs: %v", c.Name(), err)
+		}
+	}()
+
+	var vms []mo.VirtualMachine
+	if err := v.Retrieve(ctx, kinds, []string{"summary"}, &vms); err != nil {
+		return nil, err
+	}
+	activity.Record(ctx, "Listing %v VMs in %v", len(vms), c.Name())
+
+	entries := make([]plugin.Entry, len(vms))
+	for i, machine := range vms {
+		entries[i] = newVM(c.datacenter, machine.Reference(), machine.Summary)
+	}
+	return entries, nil
+}
+
+const clusterDescription = `
+This is a vSphere cluster or a standalone host. It contains the VMs that run on
+the cluster's hosts.
+`
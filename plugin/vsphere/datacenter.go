@@ -0,0 +1,80 @@
+package vsphere
+
+import (
+	"context"
+
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/view"
+	"github.com/vmware/govmomi/vim25/mo"
+)
+
+// datacenter is a vSphere datacenter. It lists the datacenter's clusters.
+type datacenter struct {
+	plugin.EntryBase
+	server *server
+	dc     *object.Datacenter
+}
+
+func newDatacenter(s *server, dc *object.Datacenter) *datacenter {
+	d := &datacenter{
+		EntryBase: plugin.NewEntry(dc.Name()),
+	}
+	d.server = s
+	d.dc = dc
+	d.SetPartialMetadata(map[string]string{"path": dc.InventoryPath})
+	return d
+}
+
+func (d *datacenter) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(d, "datacenter").
+		SetDescription(datacenterDescription)
+}
+
+func (d *datacenter) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&cluster{}).Schema(),
+	}
+}
+
+func (d *datacenter) List(ctx context.Context) ([]plugin.Entry, error) {
+	client, err := d.server.vimClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Standalone hosts are ComputeResources, clusters are ClusterComputeResources.
+	// Both are included so that every VM in the datacenter's listed. A container
+	// view's used instead of a finder so that clusters in nested folders are
+	// found.
+	kinds := []string{"ComputeResource", "ClusterComputeResource"}
+	v, err := view.NewManager(client).CreateContainerView(ctx, d.dc.Reference(), kinds, true)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := v.Destroy(context.Background()); err != nil {
+			activity.Record(ctx, "Failed to destroy the view of %v's clusters: %v", d.Name(), err)
+		}
+	}()
+
+	var resources []mo.ComputeResource
+	if err := v.Retrieve(ctx, kinds, []string{"name", "summary"}, &resources); err != nil {
+		return nil, err
+	}
+	activity.Record(ctx, "Listing %v clusters in %v", len(resources), d.Name())
+
+	entries := make([]plugin.Entry, len(resources))
+	for i, resource := range resources {
+		entries[i] = newCluster(d, resource)
+	}
+	return entries, nil
+}
+
+const datacenterDescription = `
+This is a vSphere datacenter. It contains the datacenter's clusters. Standalone
+hosts, i.e. hosts that aren't part of a cluster, are included as single-host
+clusters.
+`
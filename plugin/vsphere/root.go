@@ -0,0 +1,177 @@
+// Package vsphere presents a filesystem hierarchy for VMware vSphere: the
+// datacenters of each configured vCenter or ESXi server, their clusters and the
+// clusters' VMs. VMs expose their config and runtime info as metadata, their
+// vmware.log and support Exec via guest operations, which requires VMware Tools.
+//
+// Servers are listed in the plugin's config. The server at the GOVC_URL
+// environment variable is used if none are.
+package vsphere
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// Root of the vSphere plugin
+type Root struct {
+	plugin.EntryBase
+	servers []plugin.Entry
+}
+
+type serverConfig struct {
+	name     string
+	url      string
+	username string
+	password string
+	insecure bool
+	// guestUsername and guestPassword authenticate guest operations, i.e. Exec.
+	guestUsername string
+	guestPassword string
+}
+
+// Init for root
+func (r *Root) Init(cfg map[string]interface{}) error {
+	var configs []serverConfig
+	if serversI, ok := cfg["servers"]; ok {
+		var err error
+		if configs, err = parseServers(serversI); err != nil {
+			return err
+		}
+	} else if url := os.Getenv("GOVC_URL"); url != "" {
+		// Use the same environment variables as govc.
+		insecure, _ := strconv.ParseBool(os.Getenv("GOVC_INSECURE"))
+		configs = []serverConfig{{
+			name:          "default",
+			url:           url,
+			username:      os.Getenv("GOVC_USERNAME"),
+			password:      os.Getenv("GOVC_PASSWORD"),
+			insecure:      insecure,
+			guestUsername: os.Getenv("GOVC_GUEST_USERNAME"),
+			guestPassword: os.Getenv("GOVC_GUEST_PASSWORD"),
+		}}
+	}
+
+	for _, config := range configs {
+		server, err := newServer(config)
+		if err != nil {
+			return fmt.Errorf("vsphere: invalid url for %v: %v", config.name, err)
+		}
+		r.servers = append(r.servers, server)
+	}
+
+	r.EntryBase = plugin.NewEntry("vsphere")
+	r.DisableDefaultCaching()
+	return nil
+}
+
+// Schema returns the root's schema
+func (r *Root) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(r, "vsphere").
+		SetDescription(rootDescription).
+		IsSingleton()
+}
+
+// ChildSchemas returns the root's child schema
+func (r *Root) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&server{}).Schema(),
+	}
+}
+
+// List lists the configured servers.
+func (r *Root) List(ctx context.Context) ([]plugin.Entry, error) {
+	return r.servers, nil
+}
+
+// parseServers parses the vsphere.servers config, which is an array of
+// {name: <name>, url: <url>, username: <username>, password: <password>, insecure: <bool>,
+// guest_username: <username>, guest_password: <password>} objects. Only the
+// name and url are required. Servers are listed in an array instead of being
+// keys of an object because config keys are case-insensitive.
+func parseServers(serversI interface{}) ([]serverConfig, error) {
+	servers, ok := serversI.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("vsphere.servers config must be an array, not %v", serversI)
+	}
+
+	var configs []serverConfig
+	seen := make(map[string]struct{})
+	for _, elem := range servers {
+		obj, ok := plugin.ToStringMap(elem)
+		if !ok {
+			return nil, fmt.Errorf("vsphere.servers config must be an array of objects, not %v", servers)
+		}
+		name, ok := obj["name"].(string)
+		if !ok || name == "" || strings.Contains(name, "/") {
+			return nil, fmt.Errorf("vsphere.servers config entry %v must specify a name that doesn't contain a '/'", obj)
+		}
+		url, ok := obj["url"].(string)
+		if !ok || url == "" {
+			return nil, fmt.Errorf("vsphere.servers config for %v must specify the server's url", name)
+		}
+		config := serverConfig{name: name, url: url}
+		for key, field := range map[string]*string{
+			"username":       &config.username,
+			"password":       &config.password,
+			"guest_username": &config.guestUsername,
+			"guest_password": &config.guestPassword,
+		} {
+			valueI, ok := obj[key]
+			if !ok {
+				continue
+			}
+			value, ok := valueI.(string)
+			if !ok {
+				return nil, fmt.Errorf("vsphere.servers config for %v: %v must be a string, not %v", name, key, valueI)
+			}
+			*field = value
+		}
+		if insecureI, ok := obj["insecure"]; ok {
+			insecure, ok := insecureI.(bool)
+			if !ok {
+				return nil, fmt.Errorf("vsphere.servers config for %v: insecure must be a boolean, not %v", name, insecureI)
+			}
+			config.insecure = insecure
+		}
+		if _, ok := seen[name]; ok {
+			return nil, fmt.Errorf("vsphere.servers config specifies %v more than once", name)
+		}
+		seen[name] = struct{}{}
+		configs = append(configs, config)
+	}
+	return configs, nil
+}
+
+const rootDescription = `
+This is the vSphere plugin root. It contains the vCenter and ESXi servers in the
+vsphere.servers config, e.g.
+
+vsphere:
+  servers:
+    - name: prod
+      url: https://vcenter.example.com/sdk
+      username: administrator@vsphere.local
+      password: <password>
+      guest_username: root
+      guest_password: <password>
+    - name: lab
+      url: https://esxi.lab.example.com/sdk
+      username: root
+      password: <password>
+      insecure: true
+
+The guest credentials are used to log in to VMs' guest operating systems when
+exec'ing commands in them. Set insecure to skip verifying the server's
+certificate.
+
+If no servers are configured, then the server at the GOVC_URL environment
+variable is listed as 'default'. It's configured with the same environment
+variables as govc, like GOVC_USERNAME, GOVC_PASSWORD and GOVC_INSECURE, with
+GOVC_GUEST_USERNAME and GOVC_GUEST_PASSWORD for the guest credentials.
+`
@@ -0,0 +1,53 @@
+package vsphere
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseServers(t *testing.T) {
+	configs, err := parseServers([]interface{}{
+		map[string]interface{}{"name": "prod", "url": "https://vcenter/sdk", "username": "admin", "password": "secret", "guest_username": "root"},
+		map[interface{}]interface{}{"name": "lab", "url": "esxi", "insecure": true},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []serverConfig{
+		{name: "prod", url: "https://vcenter/sdk", username: "admin", password: "secret", guestUsername: "root"},
+		{name: "lab", url: "esxi", insecure: true},
+	}, configs)
+}
+
+func TestParseServersErrors(t *testing.T) {
+	_, err := parseServers("https://vcenter/sdk")
+	assert.EqualError(t, err, "vsphere.servers config must be an array, not https://vcenter/sdk")
+
+	_, err = parseServers([]interface{}{map[string]interface{}{"url": "https://vcenter/sdk"}})
+	assert.EqualError(t, err, "vsphere.servers config entry map[url:https://vcenter/sdk] must specify a name that doesn't contain a '/'")
+
+	_, err = parseServers([]interface{}{map[string]interface{}{"name": "prod"}})
+	assert.EqualError(t, err, "vsphere.servers config for prod must specify the server's url")
+
+	_, err = parseServers([]interface{}{map[string]interface{}{"name": "prod", "url": "vcenter", "password": 5}})
+	assert.EqualError(t, err, "vsphere.servers config for prod: password must be a string, not 5")
+
+	_, err = parseServers([]interface{}{map[string]interface{}{"name": "prod", "url": "vcenter", "insecure": "yes"}})
+	assert.EqualError(t, err, "vsphere.servers config for prod: insecure must be a boolean, not yes")
+
+	_, err = parseServers([]interface{}{
+		map[string]interface{}{"name": "prod", "url": "vcenter"},
+		map[string]interface{}{"name": "prod", "url": "vcenter2"},
+	})
+	assert.EqualError(t, err, "vsphere.servers config specifies prod more than once")
+}
+
+func TestNewServerRedactsPassword(t *testing.T) {
+	s, err := newServer(serverConfig{name: "prod", url: "vcenter", username: "admin", password: "secret"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://admin@vcenter/sdk", redact(s.url))
+
+	s, err = newServer(serverConfig{name: "lab", url: "https://esxi/sdk"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://esxi/sdk", redact(s.url))
+}
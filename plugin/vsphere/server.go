@@ -0,0 +1,126 @@
+package vsphere
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/session"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// keepAliveInterval is how often an idle session is kept alive so that it
+// doesn't expire.
+const keepAliveInterval = 5 * time.Minute
+
+// server is a vCenter or ESXi server. It lists the server's datacenters.
+type server struct {
+	plugin.EntryBase
+	url    *url.URL
+	config serverConfig
+	mux    sync.Mutex
+	// client is nil until the server's first used
+	client *vim25.Client
+}
+
+func newServer(config serverConfig) (*server, error) {
+	u, err := soap.ParseURL(config.url)
+	if err != nil {
+		return nil, err
+	}
+	if config.username != "" {
+		u.User = url.UserPassword(config.username, config.password)
+	}
+
+	s := &server{
+		EntryBase: plugin.NewEntry(config.name),
+	}
+	s.url = u
+	s.config = config
+	s.SetPartialMetadata(map[string]string{"url": redact(u)})
+	return s, nil
+}
+
+func (s *server) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(s, "server").
+		SetDescription(serverDescription).
+		SetMetadataSchema(types.AboutInfo{})
+}
+
+func (s *server) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&datacenter{}).Schema(),
+	}
+}
+
+func (s *server) List(ctx context.Context) ([]plugin.Entry, error) {
+	client, err := s.vimClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dcs, err := find.NewFinder(client).DatacenterList(ctx, "*")
+	if err != nil {
+		return nil, err
+	}
+	activity.Record(ctx, "Listing %v datacenters in %v", len(dcs), s.Name())
+
+	entries := make([]plugin.Entry, len(dcs))
+	for i, dc := range dcs {
+		entries[i] = newDatacenter(s, dc)
+	}
+	return entries, nil
+}
+
+// Metadata returns the server's product info.
+func (s *server) Metadata(ctx context.Context) (plugin.JSONObject, error) {
+	client, err := s.vimClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return plugin.ToJSONObject(client.ServiceContent.About), nil
+}
+
+// vimClient returns the server's client, logging in if it's the first call.
+// The session's kept alive so that it can be reused.
+func (s *server) vimClient(ctx context.Context) (*vim25.Client, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if s.client != nil {
+		return s.client, nil
+	}
+
+	client, err := vim25.NewClient(ctx, soap.NewClient(s.url, s.config.insecure))
+	if err != nil {
+		return nil, err
+	}
+	client.RoundTripper = session.KeepAlive(client.RoundTripper, keepAliveInterval)
+	if err := session.NewManager(client).Login(ctx, s.url.User); err != nil {
+		return nil, err
+	}
+	activity.Record(ctx, "Logged in to %v", redact(s.url))
+	s.client = client
+	return client, nil
+}
+
+// redact returns the URL without its password.
+func redact(u *url.URL) string {
+	redacted := *u
+	redacted.User = nil
+	if u.User != nil && u.User.Username() != "" {
+		redacted.User = url.User(u.User.Username())
+	}
+	return redacted.String()
+}
+
+const serverDescription = `
+This is a vCenter or ESXi server. Its metadata is the server's product info, and
+it contains the server's datacenters. ESXi servers have a single datacenter
+named ha-datacenter.
+`
@@ -0,0 +1,96 @@
+package vsphere
+
+import (
+	"context"
+	"testing"
+
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+)
+
+func names(entries []plugin.Entry) []string {
+	var names []string
+	for _, entry := range entries {
+		names = append(names, plugin.Name(entry))
+	}
+	return names
+}
+
+func TestServerList(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		s := &server{EntryBase: plugin.NewEntry("sim"), client: c}
+
+		dcs, err := s.List(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"DC0"}, names(dcs))
+
+		clusters, err := dcs[0].(*datacenter).List(ctx)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"DC0_H0", "DC0_C0"}, names(clusters))
+
+		for _, entry := range clusters {
+			if plugin.Name(entry) != "DC0_C0" {
+				continue
+			}
+			vms, err := entry.(*cluster).List(ctx)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, []string{"DC0_C0_RP0_VM0", "DC0_C0_RP0_VM1"}, names(vms))
+		}
+	})
+}
+
+func TestVMMetadataAndLog(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		s := &server{EntryBase: plugin.NewEntry("sim"), client: c}
+		dcs, err := s.List(ctx)
+		require.NoError(t, err)
+		clusters, err := dcs[0].(*datacenter).List(ctx)
+		require.NoError(t, err)
+		var vms []plugin.Entry
+		for _, entry := range clusters {
+			if plugin.Name(entry) == "DC0_H0" {
+				vms, err = entry.(*cluster).List(ctx)
+				require.NoError(t, err)
+			}
+		}
+		require.Len(t, vms, 2)
+		v := vms[0].(*vm)
+
+		meta, err := v.Metadata(ctx)
+		require.NoError(t, err)
+		assert.Contains(t, meta, "config")
+		assert.Contains(t, meta, "runtime")
+		assert.Contains(t, meta, "guest")
+
+		_, err = newVMLog(v).Read(ctx)
+		assert.NoError(t, err)
+
+		_, err = v.Exec(ctx, "uname", nil, plugin.ExecOptions{})
+		assert.EqualError(t, err, "exec'ing in "+v.Name()+" requires the sim server's guest_username and guest_password config")
+	})
+}
+
+func TestVMSignal(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		s := &server{EntryBase: plugin.NewEntry("sim"), client: c}
+		dcs, err := s.List(ctx)
+		require.NoError(t, err)
+		clusters, err := dcs[0].(*datacenter).List(ctx)
+		require.NoError(t, err)
+		var vms []plugin.Entry
+		for _, entry := range clusters {
+			if plugin.Name(entry) == "DC0_H0" {
+				vms, err = entry.(*cluster).List(ctx)
+				require.NoError(t, err)
+			}
+		}
+		require.NotEmpty(t, vms)
+		v := vms[0].(*vm)
+
+		assert.NoError(t, v.Signal(ctx, "stop"))
+		assert.EqualError(t, v.Signal(ctx, "foo"), "unsupported signal foo")
+	})
+}
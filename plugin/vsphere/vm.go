@@ -0,0 +1,210 @@
+package vsphere
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/vmware/govmomi/guest"
+	"github.com/vmware/govmomi/guest/toolbox"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// vm is a vSphere virtual machine.
+type vm struct {
+	plugin.EntryBase
+	datacenter *datacenter
+	ref        types.ManagedObjectReference
+}
+
+// vmMetadata is a VM's metadata.
+type vmMetadata struct {
+	Config  *types.VirtualMachineConfigInfo `json:"config"`
+	Runtime types.VirtualMachineRuntimeInfo `json:"runtime"`
+	Guest   *types.GuestInfo                `json:"guest"`
+}
+
+func newVM(d *datacenter, ref types.ManagedObjectReference, summary types.VirtualMachineSummary) *vm {
+	name := ref.Value
+	if summary.Config.Name != "" {
+		name = summary.Config.Name
+	}
+	v := &vm{
+		EntryBase: plugin.NewEntry(name),
+	}
+	v.datacenter = d
+	v.ref = ref
+
+	v.SetPartialMetadata(summary)
+	if bootTime := summary.Runtime.BootTime; bootTime != nil {
+		v.
+			Attributes().
+			SetAtime(*bootTime).
+			SetCrtime(*bootTime).
+			SetMtime(*bootTime)
+	}
+	return v
+}
+
+func (v *vm) Schema() *plugin.EntrySchema {
+	return plugin.
+		NewEntrySchema(v, "vm").
+		SetDescription(vmDescription).
+		SetPartialMetadataSchema(types.VirtualMachineSummary{}).
+		SetMetadataSchema(vmMetadata{}).
+		AddSignal("start", "Powers on the VM").
+		AddSignal("stop", "Powers off the VM without shutting down its guest OS").
+		AddSignal("reset", "Resets the VM, similar to doing a hard-reset on your computer").
+		AddSignal("suspend", "Suspends the VM").
+		AddSignal("shutdown", "Shuts down the VM's guest OS. Requires VMware Tools").
+		AddSignal("reboot", "Reboots the VM's guest OS. Requires VMware Tools")
+}
+
+func (v *vm) ChildSchemas() []*plugin.EntrySchema {
+	return []*plugin.EntrySchema{
+		(&vmLog{}).Schema(),
+		(&plugin.MetadataJSONFile{}).Schema(),
+	}
+}
+
+func (v *vm) List(ctx context.Context) ([]plugin.Entry, error) {
+	vmm, err := plugin.NewMetadataJSONFile(ctx, v)
+	if err != nil {
+		return nil, err
+	}
+	return []plugin.Entry{newVMLog(v), vmm}, nil
+}
+
+// Metadata returns the VM's config, runtime and guest info.
+func (v *vm) Metadata(ctx context.Context) (plugin.JSONObject, error) {
+	props, err := v.properties(ctx, "config", "runtime", "guest")
+	if err != nil {
+		return nil, err
+	}
+	return plugin.ToJSONObject(vmMetadata{
+		Config:  props.Config,
+		Runtime: props.Runtime,
+		Guest:   props.Guest,
+	}), nil
+}
+
+// Exec runs the command in the VM's guest OS via guest operations, which
+// require VMware Tools to be running in the VM. The command's output is
+// written to temporary files in the guest, so it's only available once the
+// command finishes.
+func (v *vm) Exec(ctx context.Context, cmd string, args []string, opts plugin.ExecOptions) (plugin.ExecCommand, error) {
+	config := v.datacenter.server.config
+	if config.guestUsername == "" {
+		return nil, fmt.Errorf("exec'ing in %v requires the %v server's guest_username and guest_password config", v.Name(), v.datacenter.server.Name())
+	}
+	client, err := v.datacenter.server.vimClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	props, err := v.properties(ctx, "guest")
+	if err != nil {
+		return nil, err
+	}
+	if props.Guest == nil || props.Guest.ToolsRunningStatus != string(types.VirtualMachineToolsRunningStatusGuestToolsRunning) {
+		return nil, fmt.Errorf("exec'ing in %v requires VMware Tools to be running in it", v.Name())
+	}
+
+	ops := guest.NewOperationsManager(client, v.ref)
+	processManager, err := ops.ProcessManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+	fileManager, err := ops.FileManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tools := &toolbox.Client{
+		ProcessManager: processManager,
+		FileManager:    fileManager,
+		Authentication: &types.NamePasswordAuthentication{
+			Username: config.guestUsername,
+			Password: config.guestPassword,
+		},
+		GuestFamily: types.VirtualMachineGuestOsFamily(props.Guest.GuestFamily),
+	}
+
+	execCmd := plugin.NewExecCommand(ctx)
+	go func() {
+		command := &exec.Cmd{
+			Path:   cmd,
+			Args:   args,
+			Stdin:  opts.Stdin,
+			Stdout: execCmd.Stdout(),
+			Stderr: execCmd.Stderr(),
+		}
+		err := tools.Run(ctx, command)
+		activity.Record(ctx, "Exec on %v complete: %v", v.Name(), err)
+		if exitErr, ok := err.(interface{ ExitCode() int }); ok {
+			execCmd.SetExitCode(exitErr.ExitCode())
+			err = nil
+		} else if err != nil {
+			// Set the exit code error so that callers don't block
+			// when trying to retrieve the command's exit code
+			execCmd.SetExitCodeErr(err)
+		} else {
+			execCmd.SetExitCode(0)
+		}
+		execCmd.CloseStreamsWithError(err)
+	}()
+	return execCmd, nil
+}
+
+// Signal changes the VM's power state. It returns once vSphere's accepted the
+// request, without waiting for the power state to change.
+func (v *vm) Signal(ctx context.Context, signal string) error {
+	client, err := v.datacenter.server.vimClient(ctx)
+	if err != nil {
+		return err
+	}
+	obj := object.NewVirtualMachine(client, v.ref)
+	switch signal {
+	case "start":
+		_, err = obj.PowerOn(ctx)
+	case "stop":
+		_, err = obj.PowerOff(ctx)
+	case "reset":
+		_, err = obj.Reset(ctx)
+	case "suspend":
+		_, err = obj.Suspend(ctx)
+	case "shutdown":
+		err = obj.ShutdownGuest(ctx)
+	case "reboot":
+		err = obj.RebootGuest(ctx)
+	default:
+		err = fmt.Errorf("unsupported signal %v", signal)
+	}
+	return err
+}
+
+// properties retrieves the named properties of the VM.
+func (v *vm) properties(ctx context.Context, names ...string) (mo.VirtualMachine, error) {
+	var props mo.VirtualMachine
+	client, err := v.datacenter.server.vimClient(ctx)
+	if err != nil {
+		return props, err
+	}
+	err = object.NewVirtualMachine(client, v.ref).Properties(ctx, v.ref, names, &props)
+	return props, err
+}
+
+const vmDescription = `
+This is a vSphere VM. Its metadata contains the VM's config, runtime and guest
+info. It contains the VM's vmware.log.
+
+Exec'ing commands in the VM uses guest operations, so VMware Tools must be
+running in the VM and the server's guest_username and guest_password must be
+configured. Commands without a '/' in their path are run with '/bin/bash -c' on
+Linux guests. Their output is only available once they finish.
+
+Signal the VM to change its power state, e.g.
+  wash signal shutdown vsphere/my-server/my-datacenter/my-cluster/my-vm
+`
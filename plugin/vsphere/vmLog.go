@@ -0,0 +1,74 @@
+package vsphere
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path"
+
+	"github.com/puppetlabs/wash/activity"
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/soap"
+)
+
+// vmLog is a VM's vmware.log, which is the VM's console log. It's
+// downloaded from the datastore that contains the VM's log directory.
+type vmLog struct {
+	plugin.EntryBase
+	vm *vm
+}
+
+func newVMLog(v *vm) *vmLog {
+	l := &vmLog{
+		EntryBase: plugin.NewEntry("vmware.log"),
+	}
+	l.vm = v
+	l.DisableCachingFor(plugin.ReadOp)
+	return l
+}
+
+func (l *vmLog) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(l, "log").SetDescription(vmLogDescription)
+}
+
+func (l *vmLog) Read(ctx context.Context) ([]byte, error) {
+	props, err := l.vm.properties(ctx, "config.files.logDirectory")
+	if err != nil {
+		return nil, err
+	}
+	if props.Config == nil {
+		return nil, fmt.Errorf("%v does not have a log directory", l.vm.Name())
+	}
+	var dir object.DatastorePath
+	if !dir.FromString(props.Config.Files.LogDirectory) {
+		return nil, fmt.Errorf("%v has an invalid log directory %v", l.vm.Name(), props.Config.Files.LogDirectory)
+	}
+
+	client, err := l.vm.datacenter.server.vimClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ds, err := find.NewFinder(client).SetDatacenter(l.vm.datacenter.dc).Datastore(ctx, dir.Datastore)
+	if err != nil {
+		return nil, err
+	}
+	rdr, _, err := ds.Download(ctx, path.Join(dir.Path, "vmware.log"), &soap.DefaultDownload)
+	if err != nil {
+		return nil, err
+	}
+	defer rdr.Close()
+
+	content, err := ioutil.ReadAll(rdr)
+	if err != nil {
+		return nil, err
+	}
+	activity.Record(ctx, "Read %v bytes of %v's log", len(content), l.vm.Name())
+	return content, nil
+}
+
+const vmLogDescription = `
+This is the VM's vmware.log. It's downloaded from the datastore that contains
+the VM's files.
+`
@@ -0,0 +1,62 @@
+package plugin
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/puppetlabs/wash/activity"
+)
+
+// Watch watches w's children. Each event's entry has its ID set, so that it
+// can be used like a listed entry, and its Name is set from the entry. The
+// cached data of the event's child and w's cached list are cleared before the
+// event's sent, so that subsequent calls observe the change.
+func Watch(ctx context.Context, w Watchable) (events <-chan EntryEvent, err error) {
+	ctx = withPluginContext(ctx, w)
+	done := recordCall(w, "Watch")
+	defer func() { done(err) }()
+	defer recoverPanic(ctx, w, "Watch", &err)
+	rawEvents, err := w.Watch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	parentID := w.eb().id
+	ch := make(chan EntryEvent)
+	go func() {
+		defer close(ch)
+		defer func() {
+			if r := recover(); r != nil {
+				_ = activity.RecordPanic(ctx, "Watch on "+parentID, r)
+			}
+		}()
+		for event := range rawEvents {
+			var childID string
+			if event.Entry != nil {
+				setChildID(parentID, event.Entry)
+				passAlongWrappedTypes(w, event.Entry)
+				event.Name = Name(event.Entry)
+				childID = event.Entry.eb().id
+			} else if event.Name != "" {
+				// Children usually share their parent's slash replacer.
+				childID = strings.TrimRight(parentID, "/") + "/" + EscapeName(event.Name, w.eb().slashReplacer)
+			} else {
+				activity.Warnf(ctx, "Watch on %v sent a %v event without an entry or a name", parentID, event.Type)
+				continue
+			}
+			if event.Time.IsZero() {
+				event.Time = time.Now()
+			}
+
+			ClearCacheFor(childID, false)
+			cache.Delete(opKeyRegex(defaultOpCodeToNameMap[ListOp], parentID))
+			select {
+			case ch <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
@@ -0,0 +1,74 @@
+package plugin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/puppetlabs/wash/datastore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockWatchableParent sends the events it's given, and counts its List calls.
+type mockWatchableParent struct {
+	mockParent
+	events chan EntryEvent
+	lists  int
+}
+
+func (p *mockWatchableParent) List(ctx context.Context) ([]Entry, error) {
+	p.lists++
+	return p.mockParent.List(ctx)
+}
+
+func (p *mockWatchableParent) Watch(ctx context.Context) (<-chan EntryEvent, error) {
+	return p.events, nil
+}
+
+func TestWatch(t *testing.T) {
+	SetTestCache(datastore.NewMemCache())
+	defer UnsetTestCache()
+
+	parent := &mockWatchableParent{mockParent: mockParent{NewEntry("root"), []Entry{newMockEntry("foo")}}, events: make(chan EntryEvent)}
+	parent.SetTestID("/root")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := Watch(ctx, parent)
+	require.NoError(t, err)
+
+	_, err = List(ctx, parent)
+	require.NoError(t, err)
+	_, err = List(ctx, parent)
+	require.NoError(t, err)
+	assert.Equal(t, 1, parent.lists)
+
+	// Created events have their entry's ID and name set.
+	go func() { parent.events <- EntryEvent{Type: EntryCreated, Entry: newMockEntry("bar")} }()
+	event := <-events
+	assert.Equal(t, EntryCreated, event.Type)
+	assert.Equal(t, "bar", event.Name)
+	assert.Equal(t, "/root/bar", ID(event.Entry))
+	assert.False(t, event.Time.IsZero())
+
+	// The event cleared the parent's cached list.
+	_, err = List(ctx, parent)
+	require.NoError(t, err)
+	assert.Equal(t, 2, parent.lists)
+
+	// Deleted events can use the child's name instead of an entry.
+	go func() { parent.events <- EntryEvent{Type: EntryDeleted, Name: "foo"} }()
+	event = <-events
+	assert.Equal(t, EntryDeleted, event.Type)
+	assert.Equal(t, "foo", event.Name)
+	assert.Nil(t, event.Entry)
+
+	close(parent.events)
+	_, ok := <-events
+	assert.False(t, ok)
+}
+
+func TestWatchAction(t *testing.T) {
+	assert.True(t, WatchAction().IsSupportedOn(&mockWatchableParent{}))
+	assert.False(t, WatchAction().IsSupportedOn(&mockParent{}))
+}
@@ -0,0 +1,37 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/puppetlabs/wash/activity"
+)
+
+// DialSSH connects to addr from a target, tunneling the connection over SSH.
+// The network is "tcp" or "unix", so it can connect to a port or to a Unix
+// socket on the target. Unix sockets are forwarded with OpenSSH's
+// direct-streamlocal extension.
+//
+// The target's connection info is found the same way as ExecSSH finds it, and
+// the SSH connection is shared with ExecSSH. The connection stays open until
+// ctx is done, so the returned conn should be closed before then.
+func DialSSH(ctx context.Context, id Identity, network string, addr string) (net.Conn, error) {
+	conf, err := getConnInfo(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get connection info: %s", err)
+	}
+	activity.Record(ctx, "Found connection info %+v", conf)
+
+	connection, err := sshConnect(ctx, conf, id.Retries)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to connect: %s", err)
+	}
+	connectionCache.Pin(ctx, "", connectionID(conf))
+
+	conn, err := connection.Dial(network, addr)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to dial %v on %v: %s", addr, id.Host, err)
+	}
+	return conn, nil
+}
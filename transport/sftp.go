@@ -0,0 +1,36 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/sftp"
+	"github.com/puppetlabs/wash/activity"
+)
+
+// SFTP starts an SFTP session with a target. The target's connection info is
+// found the same way as ExecSSH finds it, and the SSH connection is shared with
+// ExecSSH. The connection stays open until ctx is done, so the returned client
+// should be closed before then.
+//
+// Unlike ExecSSH, SFTP can't elevate, so the session only has the login user's
+// access.
+func SFTP(ctx context.Context, id Identity) (*sftp.Client, error) {
+	conf, err := getConnInfo(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get connection info: %s", err)
+	}
+	activity.Record(ctx, "Found connection info %+v", conf)
+
+	connection, err := sshConnect(ctx, conf, id.Retries)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to connect: %s", err)
+	}
+	connectionCache.Pin(ctx, "", connectionID(conf))
+
+	client, err := sftp.NewClient(connection)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to start SFTP session: %s", err)
+	}
+	return client, nil
+}
@@ -137,10 +137,13 @@ func getConnInfo(ctx context.Context, id Identity) (conf sshConfig, err error) {
 	return
 }
 
+func connectionID(conf sshConfig) string {
+	return conf.user + "@" + conf.host + ":" + conf.port
+}
+
 func sshConnect(ctx context.Context, conf sshConfig, retries uint) (*ssh.Client, error) {
-	connID := conf.user + "@" + conf.host + ":" + conf.port
 	// This is a single-use cache, so pass in an empty category.
-	obj, err := connectionCache.GetOrUpdate("", connID, expires, true, func() (interface{}, error) {
+	obj, err := connectionCache.GetOrUpdate("", connectionID(conf), expires, true, func() (interface{}, error) {
 		agentPath := os.Getenv("SSH_AUTH_SOCK")
 		var agent ssh.AuthMethod
 		if agentPath == "" {
@@ -239,6 +242,9 @@ func ExecSSH(ctx context.Context, id Identity, cmd []string, opts plugin.ExecOpt
 	if err != nil {
 		return nil, fmt.Errorf("Failed to connect: %s", err)
 	}
+	// Keep the connection open until the exec's done. Otherwise it's closed when
+	// it expires, which kills long-running commands.
+	connectionCache.Pin(ctx, "", connectionID(conf))
 
 	// Run command via session
 	session, err := connection.NewSession()
@@ -265,6 +271,11 @@ func ExecSSH(ctx context.Context, id Identity, cmd []string, opts plugin.ExecOpt
 	if err := session.Start(cmdStr); err != nil {
 		return nil, err
 	}
+	if opts.Tty {
+		execCmd.SetResizeFunc(opts.Resize, func(size plugin.WindowSize) error {
+			return session.WindowChange(int(size.Rows), int(size.Cols))
+		})
+	}
 	execCmd.SetStopFunc(func() {
 		// Close the session on context cancellation. Copying will block until there's more to read
 		// from the exec output. For an action with no more output it may never return.
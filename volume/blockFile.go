@@ -0,0 +1,60 @@
+package volume
+
+import (
+	"context"
+
+	"github.com/puppetlabs/wash/plugin"
+)
+
+// blockReadAheadSize is the minimum number of bytes that a block file read
+// fetches. FUSE reads a file in small blocks, and each VolumeBlockRead call can
+// be expensive (e.g. it may create a temporary pod), so a block file prefetches
+// a chunk and serves subsequent reads from it. See plugin.RangePrefetcher.
+var blockReadAheadSize int64 = 4 * 1024 * 1024
+
+// blockFile represents a file in a volume that implements BlockReader. It reads
+// ranges of its content instead of its entire content.
+type blockFile struct {
+	*file
+	prefetcher plugin.RangePrefetcher
+}
+
+// newBlockFile creates a blockFile.
+func newBlockFile(name string, attr plugin.EntryAttributes, impl Interface, path string) *blockFile {
+	return &blockFile{file: newFile(name, attr, impl, path)}
+}
+
+func (v *blockFile) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(v, "file").SetDescription(fileDescription)
+}
+
+// Read reads the requested range of the file's content. Reads are served from
+// the prefetched chunk when possible.
+func (v *blockFile) Read(ctx context.Context, size int64, offset int64) ([]byte, error) {
+	attr := plugin.Attributes(v)
+	fileSize := int64(attr.Size())
+	return v.prefetcher.Read(ctx, size, offset, fileSize, blockReadAheadSize, func(ctx context.Context, size int64, offset int64) ([]byte, error) {
+		return v.impl.(BlockReader).VolumeBlockRead(ctx, v.path, size, offset)
+	})
+}
+
+var _ = plugin.BlockReadable(&blockFile{file: &file{}})
+
+// writableBlockFile represents a file in a volume that implements both
+// BlockReader and Writer.
+type writableBlockFile struct {
+	*blockFile
+}
+
+func (v *writableBlockFile) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(v, "file").SetDescription(fileDescription)
+}
+
+// Write overwrites the content of the file. It discards the prefetched chunk
+// since it's now stale.
+func (v *writableBlockFile) Write(ctx context.Context, b []byte) error {
+	v.prefetcher.Reset()
+	return writeFile(ctx, v.file, b)
+}
+
+var _ = plugin.Writable(&writableBlockFile{})
@@ -0,0 +1,75 @@
+package volume
+
+import (
+	"context"
+	"testing"
+
+	"github.com/puppetlabs/wash/plugin"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockBlockFileEntry struct {
+	mockFileEntry
+	reads int
+}
+
+func (m *mockBlockFileEntry) VolumeBlockRead(ctx context.Context, path string, size int64, offset int64) ([]byte, error) {
+	m.reads++
+	if m.err != nil {
+		return nil, m.err
+	}
+	end := offset + size
+	if end > int64(len(m.content)) {
+		end = int64(len(m.content))
+	}
+	return []byte(m.content[offset:end]), nil
+}
+
+func TestVolumeBlockFile(t *testing.T) {
+	defer func(size int64) { blockReadAheadSize = size }(blockReadAheadSize)
+	blockReadAheadSize = 4
+
+	impl := &mockBlockFileEntry{mockFileEntry: mockFileEntry{EntryBase: plugin.NewEntry("parent"), content: "hello world"}}
+	attr := plugin.EntryAttributes{}
+	attr.SetSize(uint64(len(impl.content)))
+	vf := newBlockFile("mine", attr, impl, "my path")
+	ctx := context.Background()
+
+	content, err := vf.Read(ctx, 2, 0)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "he", string(content))
+	}
+	// The rest of the read-ahead chunk is served without another read.
+	content, err = vf.Read(ctx, 2, 2)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "ll", string(content))
+	}
+	assert.Equal(t, 1, impl.reads)
+
+	content, err = vf.Read(ctx, 10, 6)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "world", string(content))
+	}
+	assert.Equal(t, 2, impl.reads)
+
+	content, err = vf.Read(ctx, 10, 11)
+	if assert.NoError(t, err) {
+		assert.Empty(t, content)
+	}
+	assert.Equal(t, 2, impl.reads)
+}
+
+func TestVolumeDirListsBlockFiles(t *testing.T) {
+	impl := &mockBlockFileEntry{mockFileEntry: mockFileEntry{EntryBase: plugin.NewEntry("parent")}}
+	attr := plugin.EntryAttributes{}
+	attr.SetMode(0644)
+	dirmap := &dirMap{mp: DirMap{RootPath: Children{"a": attr}}}
+
+	entries := newDir("dummy", plugin.EntryAttributes{}, impl, RootPath).generateChildren(dirmap)
+	if assert.Len(t, entries, 1) {
+		_, ok := entries[0].(plugin.BlockReadable)
+		assert.True(t, ok)
+		_, ok = entries[0].(plugin.Readable)
+		assert.False(t, ok)
+	}
+}
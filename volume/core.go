@@ -8,11 +8,13 @@ package volume
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/puppetlabs/wash/archive"
 	"github.com/puppetlabs/wash/plugin"
 )
 
@@ -33,6 +35,48 @@ type Interface interface {
 	VolumeStream(ctx context.Context, path string) (io.ReadCloser, error)
 	// Deletes the volume node at the specified path. Mirrors plugin.Deletable#Delete
 	VolumeDelete(ctx context.Context, path string) (bool, error)
+	// Moves the volume node at the specified path to newPath, replacing any node that's
+	// already there. Mirrors plugin.Renamable#Rename
+	VolumeRename(ctx context.Context, path string, newPath string) error
+}
+
+// BlockReader is an optional interface that volumes implement when they can read
+// a range of a file's content without reading all of it. A volume's files are
+// plugin.BlockReadable if it implements BlockReader, so that reading part of a
+// large file doesn't download the entire file.
+type BlockReader interface {
+	// Accepts a path and returns up to size bytes of its content starting at offset.
+	VolumeBlockRead(ctx context.Context, path string, size int64, offset int64) ([]byte, error)
+}
+
+// Writer is an optional interface that volumes implement when they can write
+// files. A volume's files are only plugin.Writable if it implements Writer, so
+// that read-only volumes don't advertise the write action.
+type Writer interface {
+	// Writes content to the file at the specified path, creating it with attr's mode if
+	// it doesn't exist. Implementations should also preserve attr's ownership when they
+	// can, since some upload mechanisms would otherwise reset it. Mirrors
+	// plugin.Writable#Write
+	VolumeWrite(ctx context.Context, path string, b []byte, attr plugin.EntryAttributes) error
+}
+
+// ArchiveBrowser is an optional interface that volumes implement to let users
+// browse the content of their compressed files, like rotated logs. If a
+// volume's VolumeBrowsesArchives returns true, then its gzip, tar and zip files
+// get a sibling archive.Dir.
+type ArchiveBrowser interface {
+	VolumeBrowsesArchives() bool
+}
+
+// canWrite returns true if the volume's files can be written. An FS can only
+// write files if its executor implements FileWriter.
+func canWrite(impl Interface) bool {
+	if fs, ok := impl.(*FS); ok {
+		_, ok := fs.executor.(FileWriter)
+		return ok
+	}
+	_, ok := impl.(Writer)
+	return ok
 }
 
 // Children represents a directory's children. It is a map of <child_basename> => <child_attributes>.
@@ -54,7 +98,12 @@ type dirMap struct {
 func ChildSchemas() []*plugin.EntrySchema {
 	return []*plugin.EntrySchema{
 		(&dir{}).Schema(),
+		(&writableDir{dir: &dir{}}).Schema(),
 		(&file{}).Schema(),
+		(&writableFile{file: &file{}}).Schema(),
+		(&blockFile{file: &file{}}).Schema(),
+		(&writableBlockFile{blockFile: &blockFile{file: &file{}}}).Schema(),
+		(&archive.Dir{}).Schema(),
 	}
 }
 
@@ -73,6 +122,24 @@ func List(ctx context.Context, impl Interface) ([]plugin.Entry, error) {
 // set the List op's TTL to this value.
 const ListTTL = 30 * time.Second
 
+// renamePath returns the path of newName in newParent, which must be a directory in the
+// same volume as impl.
+func renamePath(impl Interface, newParent plugin.Parent, newName string) (string, error) {
+	switch p := newParent.(type) {
+	case *writableDir:
+		return renamePath(impl, p.dir, newName)
+	case *dir:
+		if plugin.ID(p.impl) == plugin.ID(impl) {
+			return p.path + "/" + newName, nil
+		}
+	case Interface:
+		if plugin.ID(p) == plugin.ID(impl) {
+			return RootPath + "/" + newName, nil
+		}
+	}
+	return "", fmt.Errorf("%v is not a directory in %v", plugin.ID(newParent), plugin.ID(impl))
+}
+
 // delete is a keyword, so we use deleteNode instead. Note that this implementation is
 // symmetric with plugin.Delete except that we are managing a dirmap instead of a cache.
 func deleteNode(ctx context.Context, impl Interface, path string, dirmap *dirMap) (deleted bool, err error) {
@@ -2,7 +2,9 @@ package volume
 
 import (
 	"context"
+	"os"
 
+	"github.com/puppetlabs/wash/archive"
 	"github.com/puppetlabs/wash/plugin"
 )
 
@@ -51,13 +53,14 @@ func (v *dir) generateChildren(dirmap *dirMap) []plugin.Entry {
 				newEntry.Prefetched()
 				newEntry.DisableCachingFor(plugin.ListOp)
 			}
-			entries = append(entries, newEntry)
+			entries = append(entries, newDirEntry(newEntry))
 		} else {
-			newEntry := newFile(name, attr, v.impl, subpath)
-			newEntry.dirmap = dirmap
-			entries = append(entries, newEntry)
+			entries = append(entries, newFileEntry(name, attr, v.impl, subpath, dirmap))
 		}
 	}
+	if browser, ok := v.impl.(ArchiveBrowser); ok && browser.VolumeBrowsesArchives() {
+		entries = archive.AddDirs(entries)
+	}
 	return entries
 }
 
@@ -81,6 +84,44 @@ func (v *dir) Delete(ctx context.Context) (bool, error) {
 	return deleteNode(ctx, v.impl, v.path, v.dirmap)
 }
 
+// Rename moves the directory to newName in newParent, which must be in the same volume
+func (v *dir) Rename(ctx context.Context, newParent plugin.Parent, newName string) error {
+	newPath, err := renamePath(v.impl, newParent, newName)
+	if err != nil {
+		return err
+	}
+	return v.impl.VolumeRename(ctx, v.path, newPath)
+}
+
+// newDirEntry returns the entry for a directory in the volume. Like a file's,
+// its type depends on whether the volume can write files.
+func newDirEntry(vd *dir) plugin.Entry {
+	if canWrite(vd.impl) {
+		return &writableDir{dir: vd}
+	}
+	return vd
+}
+
+// writableDir represents a directory in a volume that implements Writer. New
+// files can be created in it.
+type writableDir struct {
+	*dir
+}
+
+func (v *writableDir) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(v, "dir").SetDescription(dirDescription)
+}
+
+// Create returns the new file. The volume creates it with the mode when it's
+// first written.
+func (v *writableDir) Create(ctx context.Context, name string, mode os.FileMode) (plugin.Writable, error) {
+	attr := plugin.EntryAttributes{}
+	attr.SetMode(mode)
+	return newFileEntry(name, attr, v.impl, v.path+"/"+name, v.dirmap).(plugin.Writable), nil
+}
+
+var _ = plugin.Creatable(&writableDir{})
+
 const dirDescription = `
 This is a directory on a remote volume or a container/VM.
 `
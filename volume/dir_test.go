@@ -37,6 +37,11 @@ func (m *mockDirEntry) VolumeDelete(ctx context.Context, path string) (bool, err
 	return args.Get(0).(bool), args.Error(1)
 }
 
+func (m *mockDirEntry) VolumeRename(ctx context.Context, path string, newPath string) error {
+	args := m.Called(ctx, path, newPath)
+	return args.Error(0)
+}
+
 func (m *mockDirEntry) Schema() *plugin.EntrySchema {
 	return nil
 }
@@ -79,3 +84,95 @@ func TestVolumeDir(t *testing.T) {
 
 	plugin.UnsetTestCache()
 }
+
+func TestVolumeDirCreate(t *testing.T) {
+	dirAttr := plugin.EntryAttributes{}
+	dirAttr.SetMode(0755 | os.ModeDir)
+	dmap := DirMap{RootPath: Children{"dir": dirAttr}, "/dir": Children{}}
+
+	// Only the directories of volumes that can write files are Creatable.
+	readOnly := &mockDirEntry{EntryBase: plugin.NewEntry("mine")}
+	entries := newDir("dummy", plugin.EntryAttributes{}, readOnly, RootPath).generateChildren(&dirMap{mp: dmap})
+	if assert.Len(t, entries, 1) {
+		_, ok := entries[0].(plugin.Creatable)
+		assert.False(t, ok)
+	}
+
+	impl := &mockFileEntry{EntryBase: plugin.NewEntry("mine")}
+	entries = newDir("dummy", plugin.EntryAttributes{}, impl, RootPath).generateChildren(&dirMap{mp: dmap})
+	if assert.Len(t, entries, 1) {
+		parent, ok := entries[0].(plugin.Creatable)
+		if assert.True(t, ok) {
+			file, err := parent.Create(context.Background(), "new", 0600)
+			if assert.NoError(t, err) {
+				assert.Equal(t, "new", plugin.Name(file))
+				assert.NoError(t, file.Write(context.Background(), []byte("hello")))
+				expectedAttr := plugin.EntryAttributes{}
+				expectedAttr.SetMode(0600)
+				assert.Equal(t, expectedAttr, impl.written)
+			}
+		}
+	}
+}
+
+func TestVolumeDirRename(t *testing.T) {
+	entry := &mockDirEntry{EntryBase: plugin.NewEntry("mine")}
+	entry.SetTestID("/mine")
+	ctx := context.Background()
+
+	attr := plugin.EntryAttributes{}
+	attr.SetMode(0755 | os.ModeDir)
+	vd := newDir("path", attr, entry, "/path")
+	dst := newDir("other", attr, entry, "/other")
+	entry.On("VolumeRename", ctx, "/path", "/other/new").Return(nil).Once()
+	if assert.NoError(t, vd.Rename(ctx, dst, "new")) {
+		entry.AssertExpectations(t)
+	}
+
+	// Nodes can't be moved to another volume
+	other := &mockDirEntry{EntryBase: plugin.NewEntry("theirs")}
+	other.SetTestID("/theirs")
+	otherDst := newDir("other", attr, other, "/other")
+	otherDst.SetTestID("/theirs/other")
+	err := vd.Rename(ctx, otherDst, "new")
+	assert.EqualError(t, err, "/theirs/other is not a directory in /mine")
+}
+
+type mockArchiveBrowserEntry struct {
+	mockDirEntry
+}
+
+func (m *mockArchiveBrowserEntry) VolumeBrowsesArchives() bool {
+	return true
+}
+
+func TestVolumeDir_BrowsesArchivesIfTheVolumeOptsIn(t *testing.T) {
+	fileAttr := plugin.EntryAttributes{}
+	fileAttr.SetMode(0644).SetSize(10)
+	dirAttr := plugin.EntryAttributes{}
+	dirAttr.SetMode(0755 | os.ModeDir)
+	dmap := DirMap{"/logs": Children{"app.log": fileAttr, "app.log.1.gz": fileAttr}}
+	names := func(entries []plugin.Entry) []string {
+		var names []string
+		for _, entry := range entries {
+			names = append(names, plugin.Name(entry))
+		}
+		return names
+	}
+
+	entry := &mockDirEntry{EntryBase: plugin.NewEntry("mine")}
+	vd := newDir("logs", dirAttr, entry, "/logs")
+	vd.dirmap = &dirMap{mp: dmap}
+	entries, err := vd.List(context.Background())
+	if assert.NoError(t, err) {
+		assert.ElementsMatch(t, []string{"app.log", "app.log.1.gz"}, names(entries))
+	}
+
+	browser := &mockArchiveBrowserEntry{mockDirEntry: mockDirEntry{EntryBase: plugin.NewEntry("mine")}}
+	vd = newDir("logs", dirAttr, browser, "/logs")
+	vd.dirmap = &dirMap{mp: dmap}
+	entries, err = vd.List(context.Background())
+	if assert.NoError(t, err) {
+		assert.ElementsMatch(t, []string{"app.log", "app.log.1.gz", "app.log.1.gz.contents"}, names(entries))
+	}
+}
@@ -29,6 +29,25 @@ func newFile(name string, attr plugin.EntryAttributes, impl Interface, path stri
 	return vf
 }
 
+// newFileEntry creates the entry for a file in the volume. Its type depends on
+// whether the volume can read ranges of a file and write files so that the
+// entry only supports the actions that the volume does.
+func newFileEntry(name string, attr plugin.EntryAttributes, impl Interface, path string, dirmap *dirMap) plugin.Entry {
+	vf := newFile(name, attr, impl, path)
+	vf.dirmap = dirmap
+	_, isBlockReader := impl.(BlockReader)
+	switch writable := canWrite(impl); {
+	case isBlockReader && writable:
+		return &writableBlockFile{blockFile: &blockFile{file: vf}}
+	case isBlockReader:
+		return &blockFile{file: vf}
+	case writable:
+		return &writableFile{file: vf}
+	default:
+		return vf
+	}
+}
+
 func (v *file) Schema() *plugin.EntrySchema {
 	return plugin.NewEntrySchema(v, "file").SetDescription(fileDescription)
 }
@@ -46,6 +65,42 @@ func (v *file) Delete(ctx context.Context) (bool, error) {
 	return deleteNode(ctx, v.impl, v.path, v.dirmap)
 }
 
+// Rename moves the file to newName in newParent, which must be in the same volume
+func (v *file) Rename(ctx context.Context, newParent plugin.Parent, newName string) error {
+	newPath, err := renamePath(v.impl, newParent, newName)
+	if err != nil {
+		return err
+	}
+	return v.impl.VolumeRename(ctx, v.path, newPath)
+}
+
+// writableFile represents a file in a volume that implements Writer.
+type writableFile struct {
+	*file
+}
+
+func (v *writableFile) Schema() *plugin.EntrySchema {
+	return plugin.NewEntrySchema(v, "file").SetDescription(fileDescription)
+}
+
+// Write overwrites the content of the file.
+func (v *writableFile) Write(ctx context.Context, b []byte) error {
+	return writeFile(ctx, v.file, b)
+}
+
+// writeFile overwrites the file's content. The file's attributes are passed
+// along so that its mode and ownership are preserved, and so that new files
+// get the mode that they were created with.
+func writeFile(ctx context.Context, v *file, b []byte) error {
+	attr := plugin.Attributes(v)
+	if !attr.HasMode() {
+		attr.SetMode(0644)
+	}
+	return v.impl.(Writer).VolumeWrite(ctx, v.path, b, attr)
+}
+
+var _ = plugin.Writable(&writableFile{})
+
 const fileDescription = `
 This is a file on a remote volume or a container/VM.
 `
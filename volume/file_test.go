@@ -17,6 +17,7 @@ type mockFileEntry struct {
 	plugin.EntryBase
 	content string
 	err     error
+	written plugin.EntryAttributes
 }
 
 func (m *mockFileEntry) VolumeList(context.Context, string) (DirMap, error) {
@@ -41,6 +42,15 @@ func (m *mockFileEntry) VolumeDelete(context.Context, string) (bool, error) {
 	return true, nil
 }
 
+func (m *mockFileEntry) VolumeWrite(_ context.Context, _ string, _ []byte, attr plugin.EntryAttributes) error {
+	m.written = attr
+	return nil
+}
+
+func (m *mockFileEntry) VolumeRename(context.Context, string, string) error {
+	return nil
+}
+
 func (m *mockFileEntry) Schema() *plugin.EntrySchema {
 	return nil
 }
@@ -84,3 +94,33 @@ func TestVolumeFileErr(t *testing.T) {
 	assert.Nil(t, rdr)
 	assert.Equal(t, errors.New("fail"), err)
 }
+
+func TestVolumeFileWrite(t *testing.T) {
+	impl := &mockFileEntry{EntryBase: plugin.NewEntry("parent")}
+
+	// New files default to 0644.
+	vf := &writableFile{file: newFile("mine", plugin.EntryAttributes{}, impl, "my path")}
+	assert.NoError(t, vf.Write(context.Background(), []byte("hello")))
+	expectedAttr := plugin.EntryAttributes{}
+	expectedAttr.SetMode(0644)
+	assert.Equal(t, expectedAttr, impl.written)
+
+	// Existing files keep their mode and ownership.
+	attr := plugin.EntryAttributes{}
+	attr.SetMode(0600).SetUID(1000).SetGID(100)
+	vf = &writableFile{file: newFile("mine", attr, impl, "my path")}
+	assert.NoError(t, vf.Write(context.Background(), []byte("hello")))
+	assert.Equal(t, attr, impl.written)
+}
+
+func TestNewFileEntry_OnlyWritableIfTheVolumeWrites(t *testing.T) {
+	writer := &mockFileEntry{EntryBase: plugin.NewEntry("parent")}
+	assert.IsType(t, &writableFile{}, newFileEntry("mine", plugin.EntryAttributes{}, writer, "my path", nil))
+	blockWriter := &mockBlockFileEntry{mockFileEntry: *writer}
+	assert.IsType(t, &writableBlockFile{}, newFileEntry("mine", plugin.EntryAttributes{}, blockWriter, "my path", nil))
+
+	readOnly := &mockDirEntry{EntryBase: plugin.NewEntry("parent")}
+	vf := newFileEntry("mine", plugin.EntryAttributes{}, readOnly, "my path", nil)
+	assert.IsType(t, &file{}, vf)
+	assert.NotContains(t, plugin.SupportedActionsOf(vf), plugin.WriteAction().Name)
+}
@@ -219,6 +219,40 @@ func (d *FS) VolumeDelete(ctx context.Context, path string) (bool, error) {
 	return true, nil
 }
 
+// VolumeRename satisfies the Interface required by Rename to move volume nodes.
+func (d *FS) VolumeRename(ctx context.Context, path string, newPath string) error {
+	activity.Record(ctx, "Moving %v to %v on %v", path, newPath, plugin.ID(d.executor))
+	command := d.selectShellCommand(
+		[]string{"mv", "-f", path, newPath},
+		[]string{"Move-Item -Force -Path '" + path + "' -Destination '" + newPath + "'"},
+	)
+
+	// Skip tty because we don't need it, we ignore the output.
+	_, err := exec(ctx, d.executor, command, false)
+	if err != nil {
+		activity.Record(ctx, "Exec error running 'mv -f %v %v' in VolumeRename: %v", path, newPath, err)
+	}
+	return err
+}
+
+// FileWriter is an optional interface that an FS's executor can implement to support
+// writing files. Executors typically implement it by uploading the content via their
+// API (e.g. 'docker cp') since that avoids having to quote it as part of a command.
+type FileWriter interface {
+	plugin.Execable
+	WriteFile(ctx context.Context, path string, b []byte, attr plugin.EntryAttributes) error
+}
+
+// VolumeWrite satisfies the Interface required by Write to write file contents.
+func (d *FS) VolumeWrite(ctx context.Context, path string, b []byte, attr plugin.EntryAttributes) error {
+	writer, ok := d.executor.(FileWriter)
+	if !ok {
+		return fmt.Errorf("writing files is not supported on %v", plugin.ID(d.executor))
+	}
+	activity.Record(ctx, "Writing %v bytes to %v on %v", len(b), path, plugin.ID(d.executor))
+	return writer.WriteFile(ctx, path, b, attr)
+}
+
 // Selects between a posix and powershell command based on the entry's login shell.
 // Note that powershell commands are often a single string because they represent a PowerShell
 // expression, and it's easier to pass that as a string than try to correctly escape it as
@@ -252,5 +286,7 @@ it. Thus, you're able to do things like 'cat'/'tail' that container/VM's files
 Note that Wash will exec a command on the container/VM whenever it invokes a
 List/Read/Stream action on a directory/file, and the action's result is not
 currently cached. For List, that command is 'find -exec stat'. For Read, that
-command is 'cat'. For Stream, that command is 'tail -f'.
+command is 'cat'. For Stream, that command is 'tail -f'. Writing a file is only
+supported if the container/VM's plugin can upload files via its API (e.g. Docker
+containers support it via 'docker cp').
 `
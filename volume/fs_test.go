@@ -19,25 +19,25 @@ const fixtureDepth = 7
 // Represents the output of StatCmdPOSIX(/var/log)
 const (
 	posixFixture = `
-96 1550611510 1550611448 1550611448 41ed /var/log/path
-96 1550611510 1550611448 1550611448 41ed /var/log/path/has
-96 1550611510 1550611448 1550611448 41ed /var/log/path/has/got
-96 1550611510 1550611458 1550611458 41ed /var/log/path/has/got/some
-0 1550611458 1550611458 1550611458 81a4 /var/log/path/has/got/some/legs
-96 1550611510 1550611453 1550611453 41ed /var/log/path1
-0 1550611453 1550611453 1550611453 81a4 /var/log/path1/a file
-96 1550611510 1550611441 1550611441 41ed /var/log/path2
-64 1550611510 1550611441 1550611441 41ed /var/log/path2/dir
+96 1550611510 1550611448 1550611448 41ed 0 0 /var/log/path
+96 1550611510 1550611448 1550611448 41ed 0 0 /var/log/path/has
+96 1550611510 1550611448 1550611448 41ed 0 0 /var/log/path/has/got
+96 1550611510 1550611458 1550611458 41ed 0 0 /var/log/path/has/got/some
+0 1550611458 1550611458 1550611458 81a4 0 0 /var/log/path/has/got/some/legs
+96 1550611510 1550611453 1550611453 41ed 0 0 /var/log/path1
+0 1550611453 1550611453 1550611453 81a4 0 0 /var/log/path1/a file
+96 1550611510 1550611441 1550611441 41ed 0 0 /var/log/path2
+64 1550611510 1550611441 1550611441 41ed 0 0 /var/log/path2/dir
 `
 	posixFixtureShort = `
-96 1550611510 1550611448 1550611448 41ed /var
-96 1550611510 1550611448 1550611448 41ed /var/log
-96 1550611510 1550611448 1550611448 41ed /var/log/path
+96 1550611510 1550611448 1550611448 41ed 0 0 /var
+96 1550611510 1550611448 1550611448 41ed 0 0 /var/log
+96 1550611510 1550611448 1550611448 41ed 0 0 /var/log/path
 `
 	posixFixtureDeep = `
-96 1550611510 1550611448 1550611448 41ed /var/log/path/has
-96 1550611510 1550611448 1550611448 41ed /var/log/path/has/got
-96 1550611510 1550611458 1550611458 41ed /var/log/path/has/got/some
+96 1550611510 1550611448 1550611448 41ed 0 0 /var/log/path/has
+96 1550611510 1550611448 1550611448 41ed 0 0 /var/log/path/has/got
+96 1550611510 1550611458 1550611458 41ed 0 0 /var/log/path/has/got/some
 `
 )
 
@@ -261,6 +261,26 @@ func (suite *fsTestSuite) TestVolumeDelete() {
 	exec.AssertExpectations(suite.T())
 }
 
+func (suite *fsTestSuite) TestVolumeWrite() {
+	exec := suite.createExec()
+	exec.onExec(suite.statCmd("/", suite.outputDepth), suite.createResult(suite.outputFixture))
+	fs := NewFS(suite.ctx, "fs", exec, suite.outputDepth)
+
+	attr := plugin.EntryAttributes{}
+	attr.SetMode(0644).SetUID(1000).SetGID(1000)
+	err := fs.VolumeWrite(suite.ctx, "/var/log/path1/a file", []byte("hello"), attr)
+	suite.EqualError(err, "writing files is not supported on /instance")
+	// So the FS's files aren't writable
+	suite.False(canWrite(fs))
+
+	writer := &mockFileWriter{mockExecutor: exec}
+	writer.On("WriteFile", mock.Anything, "/var/log/path1/a file", []byte("hello"), attr).Return(nil)
+	fs = NewFS(suite.ctx, "fs", writer, suite.outputDepth)
+	suite.True(canWrite(fs))
+	suite.NoError(fs.VolumeWrite(suite.ctx, "/var/log/path1/a file", []byte("hello"), attr))
+	writer.AssertExpectations(suite.T())
+}
+
 func TestPOSIXFS(t *testing.T) {
 	suite.Run(t, &fsTestSuite{
 		loginShell:    plugin.POSIXShell,
@@ -306,6 +326,14 @@ func (m *mockExecutor) onExec(cmd []string, result plugin.ExecCommand) *mock.Cal
 	return m.On("Exec", mock.Anything, cmd[0], cmd[1:], mock.Anything).Return(result, nil)
 }
 
+type mockFileWriter struct {
+	*mockExecutor
+}
+
+func (m *mockFileWriter) WriteFile(ctx context.Context, path string, b []byte, attr plugin.EntryAttributes) error {
+	return m.Called(ctx, path, b, attr).Error(0)
+}
+
 // Mock ExecCommand that can be used repeatedly when mocking a repeated call.
 type mockExecCmd struct {
 	data string
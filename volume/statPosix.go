@@ -18,17 +18,19 @@ func StatCmdPOSIX(path string, maxdepth int) []string {
 	if path == RootPath {
 		path = "/"
 	}
-	// size, atime, mtime, ctime, mode, name
+	// size, atime, mtime, ctime, mode, uid, gid, name
 	// %s - Total size, in bytes
 	// %X - Time of last access as seconds since Epoch
 	// %Y - Time of last data modification as seconds since Epoch
 	// %Z - Time of last status change as seconds since Epoch
 	// %f - Raw mode in hex
+	// %u - User ID of owner
+	// %g - Group ID of owner
 	// %n - File name
 	// TODO: fix as part of https://github.com/puppetlabs/wash/issues/378. We don't currently handle
 	// showing symbolic links, instead representing them as the resolved target.
 	return []string{"find", "-L", path, "-mindepth", "1", "-maxdepth", strconv.Itoa(maxdepth),
-		"-exec", "stat", "-L", "-c", "%s %X %Y %Z %f %n", "{}", "+"}
+		"-exec", "stat", "-L", "-c", "%s %X %Y %Z %f %u %g %n", "{}", "+"}
 }
 
 // Keep as its own specialized function as it will be faster than munge.ToTime.
@@ -43,9 +45,9 @@ func parseTime(t string) (time.Time, error) {
 // StatParse parses a single line of the output of StatCmdPOSIX into EntryAttributes and a path.
 func parseStatPOSIX(line string) (plugin.EntryAttributes, string, error) {
 	var attr plugin.EntryAttributes
-	segments := strings.SplitN(line, " ", 6)
-	if len(segments) != 6 {
-		return attr, "", fmt.Errorf("Stat did not return 6 components: %v", line)
+	segments := strings.SplitN(line, " ", 8)
+	if len(segments) != 8 {
+		return attr, "", fmt.Errorf("Stat did not return 8 components: %v", line)
 	}
 
 	size, err := strconv.ParseUint(segments[0], 10, 64)
@@ -78,7 +80,19 @@ func parseStatPOSIX(line string) (plugin.EntryAttributes, string, error) {
 	}
 	attr.SetMode(mode)
 
-	return attr, segments[5], nil
+	uid, err := strconv.ParseUint(segments[5], 10, 32)
+	if err != nil {
+		return attr, "", err
+	}
+	attr.SetUID(uint32(uid))
+
+	gid, err := strconv.ParseUint(segments[6], 10, 32)
+	if err != nil {
+		return attr, "", err
+	}
+	attr.SetGID(uint32(gid))
+
+	return attr, segments[7], nil
 }
 
 // ParseStatPOSIX an output stream that is the result of running StatCmdPOSIX. Strips 'base' from the
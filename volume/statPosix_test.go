@@ -14,37 +14,37 @@ import (
 )
 
 // Generated with
-// `docker run --rm -it -v=/test/fixture:/mnt busybox find /mnt/ -mindepth 1 -exec stat -c '%s %X %Y %Z %f %n' {} \;`
+// `docker run --rm -it -v=/test/fixture:/mnt busybox find /mnt/ -mindepth 1 -exec stat -c '%s %X %Y %Z %f %u %g %n' {} \;`
 const mountpoint = "mnt"
 const mountDepth = 5
 const fixture = `
-96 1550611510 1550611448 1550611448 41ed mnt/path
-96 1550611510 1550611448 1550611448 41ed mnt/path/has
-96 1550611510 1550611448 1550611448 41ed mnt/path/has/got
-96 1550611510 1550611458 1550611458 41ed mnt/path/has/got/some
-0 1550611458 1550611458 1550611458 81a4 mnt/path/has/got/some/legs
-96 1550611510 1550611453 1550611453 41ed mnt/path1
-0 1550611453 1550611453 1550611453 81a4 mnt/path1/a file
-96 1550611510 1550611441 1550611441 41ed mnt/path2
-64 1550611510 1550611441 1550611441 41ed mnt/path2/dir
+96 1550611510 1550611448 1550611448 41ed 0 0 mnt/path
+96 1550611510 1550611448 1550611448 41ed 0 0 mnt/path/has
+96 1550611510 1550611448 1550611448 41ed 0 0 mnt/path/has/got
+96 1550611510 1550611458 1550611458 41ed 0 0 mnt/path/has/got/some
+0 1550611458 1550611458 1550611458 81a4 0 0 mnt/path/has/got/some/legs
+96 1550611510 1550611453 1550611453 41ed 0 0 mnt/path1
+0 1550611453 1550611453 1550611453 81a4 0 0 mnt/path1/a file
+96 1550611510 1550611441 1550611441 41ed 0 0 mnt/path2
+64 1550611510 1550611441 1550611441 41ed 0 0 mnt/path2/dir
 `
 
 func TestStatCmdPOSIX(t *testing.T) {
 	cmd := StatCmdPOSIX("", 1)
 	assert.Equal(t, []string{"find", "-L", "/", "-mindepth", "1", "-maxdepth", "1",
-		"-exec", "stat", "-L", "-c", "%s %X %Y %Z %f %n", "{}", "+"}, cmd)
+		"-exec", "stat", "-L", "-c", "%s %X %Y %Z %f %u %g %n", "{}", "+"}, cmd)
 
 	cmd = StatCmdPOSIX("/", 1)
 	assert.Equal(t, []string{"find", "-L", "/", "-mindepth", "1", "-maxdepth", "1",
-		"-exec", "stat", "-L", "-c", "%s %X %Y %Z %f %n", "{}", "+"}, cmd)
+		"-exec", "stat", "-L", "-c", "%s %X %Y %Z %f %u %g %n", "{}", "+"}, cmd)
 
 	cmd = StatCmdPOSIX("/var/log", 5)
 	assert.Equal(t, []string{"find", "-L", "/var/log", "-mindepth", "1", "-maxdepth", "5",
-		"-exec", "stat", "-L", "-c", "%s %X %Y %Z %f %n", "{}", "+"}, cmd)
+		"-exec", "stat", "-L", "-c", "%s %X %Y %Z %f %u %g %n", "{}", "+"}, cmd)
 }
 
 func TestStatParse(t *testing.T) {
-	actualAttr, path, err := parseStatPOSIX("96 1550611510 1550611448 1550611448 41ed mnt/path")
+	actualAttr, path, err := parseStatPOSIX("96 1550611510 1550611448 1550611448 41ed 0 0 mnt/path")
 	assert.Nil(t, err)
 	assert.Equal(t, "mnt/path", path)
 	expectedAttr := plugin.EntryAttributes{}
@@ -53,10 +53,12 @@ func TestStatParse(t *testing.T) {
 		SetMtime(time.Unix(1550611448, 0)).
 		SetCtime(time.Unix(1550611448, 0)).
 		SetMode(0755 | os.ModeDir).
-		SetSize(96)
+		SetSize(96).
+		SetUID(0).
+		SetGID(0)
 	assert.Equal(t, expectedAttr, actualAttr)
 
-	actualAttr, path, err = parseStatPOSIX("0 1550611458 1550611458 1550611458 81a4 mnt/path/has/got/some/legs")
+	actualAttr, path, err = parseStatPOSIX("0 1550611458 1550611458 1550611458 81a4 1000 100 mnt/path/has/got/some/legs")
 	assert.Nil(t, err)
 	assert.Equal(t, "mnt/path/has/got/some/legs", path)
 	expectedAttr = plugin.EntryAttributes{}
@@ -65,26 +67,33 @@ func TestStatParse(t *testing.T) {
 		SetMtime(time.Unix(1550611458, 0)).
 		SetCtime(time.Unix(1550611458, 0)).
 		SetMode(0644).
-		SetSize(0)
+		SetSize(0).
+		SetUID(1000).
+		SetGID(100)
 	assert.Equal(t, expectedAttr, actualAttr)
 
 	_, _, err = parseStatPOSIX("stat: failed")
-	assert.Equal(t, errors.New("Stat did not return 6 components: stat: failed"), err)
+	assert.Equal(t, errors.New("Stat did not return 8 components: stat: failed"), err)
 
-	_, _, err = parseStatPOSIX("-1 1550611510 1550611448 1550611448 41ed mnt/path")
+	_, _, err = parseStatPOSIX("-1 1550611510 1550611448 1550611448 41ed 0 0 mnt/path")
 	if assert.NotNil(t, err) {
 		assert.Equal(t, &strconv.NumError{Func: "ParseUint", Num: "-1", Err: strconv.ErrSyntax}, err)
 	}
 
-	_, _, err = parseStatPOSIX("0 2019-01-01 2019-01-01 2019-01-01 41ed mnt/path")
+	_, _, err = parseStatPOSIX("0 2019-01-01 2019-01-01 2019-01-01 41ed 0 0 mnt/path")
 	if assert.NotNil(t, err) {
 		assert.Equal(t, &strconv.NumError{Func: "ParseInt", Num: "2019-01-01", Err: strconv.ErrSyntax}, err)
 	}
 
-	_, _, err = parseStatPOSIX("96 1550611510 1550611448 1550611448 zebra mnt/path")
+	_, _, err = parseStatPOSIX("96 1550611510 1550611448 1550611448 zebra 0 0 mnt/path")
 	if assert.NotNil(t, err) {
 		assert.Regexp(t, regexp.MustCompile("mode.*zebra"), err.Error())
 	}
+
+	_, _, err = parseStatPOSIX("96 1550611510 1550611448 1550611448 41ed root 0 mnt/path")
+	if assert.NotNil(t, err) {
+		assert.Equal(t, &strconv.NumError{Func: "ParseUint", Num: "root", Err: strconv.ErrSyntax}, err)
+	}
 }
 
 func TestParseStatPOSIX(t *testing.T) {
@@ -109,7 +118,9 @@ func TestParseStatPOSIX(t *testing.T) {
 		SetMtime(time.Unix(1550611453, 0)).
 		SetCtime(time.Unix(1550611453, 0)).
 		SetMode(0644).
-		SetSize(0)
+		SetSize(0).
+		SetUID(0).
+		SetGID(0)
 	assert.Equal(t, expectedAttr, dmap["/path1"]["a file"])
 
 	expectedAttr = plugin.EntryAttributes{}
@@ -118,7 +129,9 @@ func TestParseStatPOSIX(t *testing.T) {
 		SetMtime(time.Unix(1550611441, 0)).
 		SetCtime(time.Unix(1550611441, 0)).
 		SetMode(0755 | os.ModeDir).
-		SetSize(64)
+		SetSize(64).
+		SetUID(0).
+		SetGID(0)
 	assert.Equal(t, expectedAttr, dmap["/path2"]["dir"])
 
 	expectedAttr = plugin.EntryAttributes{}
@@ -127,14 +140,16 @@ func TestParseStatPOSIX(t *testing.T) {
 		SetMtime(time.Unix(1550611448, 0)).
 		SetCtime(time.Unix(1550611448, 0)).
 		SetMode(0755 | os.ModeDir).
-		SetSize(96)
+		SetSize(96).
+		SetUID(0).
+		SetGID(0)
 	assert.Equal(t, expectedAttr, dmap["/path"]["has"])
 }
 
 func TestParseStatPOSIXUnfinished(t *testing.T) {
 	const shortFixture = `
-	96 1550611510 1550611448 1550611448 41ed mnt/path
-	96 1550611510 1550611448 1550611448 41ed mnt/path/has
+	96 1550611510 1550611448 1550611448 41ed 0 0 mnt/path
+	96 1550611510 1550611448 1550611448 41ed 0 0 mnt/path/has
 	`
 	dmap, err := ParseStatPOSIX(strings.NewReader(shortFixture), mountpoint, mountpoint, 2)
 	assert.Nil(t, err)
@@ -151,8 +166,8 @@ func TestParseStatPOSIXUnfinished(t *testing.T) {
 
 func TestParseStatPOSIXDeep(t *testing.T) {
 	const shortFixture = `
-	96 1550611510 1550611448 1550611448 41ed mnt/path
-	96 1550611510 1550611448 1550611448 41ed mnt/path/has
+	96 1550611510 1550611448 1550611448 41ed 0 0 mnt/path
+	96 1550611510 1550611448 1550611448 41ed 0 0 mnt/path/has
 	`
 	dmap, err := ParseStatPOSIX(strings.NewReader(shortFixture), RootPath, mountpoint, 2)
 	assert.Nil(t, err)
@@ -191,7 +206,9 @@ func TestParseStatPOSIXRoot(t *testing.T) {
 		SetMtime(time.Unix(1550611453, 0)).
 		SetCtime(time.Unix(1550611453, 0)).
 		SetMode(0644).
-		SetSize(0)
+		SetSize(0).
+		SetUID(0).
+		SetGID(0)
 	assert.Equal(t, expectedAttr, dmap["mnt/path1"]["a file"])
 
 	expectedAttr = plugin.EntryAttributes{}
@@ -200,7 +217,9 @@ func TestParseStatPOSIXRoot(t *testing.T) {
 		SetMtime(time.Unix(1550611441, 0)).
 		SetCtime(time.Unix(1550611441, 0)).
 		SetMode(0755 | os.ModeDir).
-		SetSize(64)
+		SetSize(64).
+		SetUID(0).
+		SetGID(0)
 	assert.Equal(t, expectedAttr, dmap["mnt/path2"]["dir"])
 
 	expectedAttr = plugin.EntryAttributes{}
@@ -209,7 +228,9 @@ func TestParseStatPOSIXRoot(t *testing.T) {
 		SetMtime(time.Unix(1550611448, 0)).
 		SetCtime(time.Unix(1550611448, 0)).
 		SetMode(0755 | os.ModeDir).
-		SetSize(96)
+		SetSize(96).
+		SetUID(0).
+		SetGID(0)
 	assert.Equal(t, expectedAttr, dmap["mnt/path"]["has"])
 
 	expectedAttr = plugin.EntryAttributes{}